@@ -241,6 +241,141 @@ const docTemplate = `{
                 }
             }
         },
+        "/audit-logs": {
+            "get": {
+                "description": "Get AuditLogs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AuditLog"
+                ],
+                "summary": "Get AuditLogs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.AuditLog"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/audit-logs/export": {
+            "get": {
+                "description": "Export AuditLogs in csv or json format",
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "AuditLog"
+                ],
+                "summary": "Export AuditLogs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "export format, csv or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.AuditLog"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/audit-logs/{id}": {
+            "get": {
+                "description": "Get AuditLog by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AuditLog"
+                ],
+                "summary": "Get AuditLog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.AuditLog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
         "/buckets": {
             "get": {
                 "description": "Get Buckets",
@@ -391,6 +526,38 @@ const docTemplate = `{
                 }
             }
         },
+        "/certificates/ca": {
+            "get": {
+                "description": "Get the PEM encoded CA certificate used to issue peer certificates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-pem-file"
+                ],
+                "tags": [
+                    "Certificate"
+                ],
+                "summary": "Get CA Certificate",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
         "/clusters": {
             "get": {
                 "description": "Get Clusters",
@@ -833,38 +1000,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/healthy": {
-            "get": {
-                "description": "Get app health",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Health"
-                ],
-                "summary": "Get Health",
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request"
-                    },
-                    "404": {
-                        "description": "Not Found"
-                    },
-                    "500": {
-                        "description": "Internal Server Error"
-                    }
-                }
-            }
-        },
-        "/jobs": {
+        "/feature-flags": {
             "get": {
-                "description": "Get Jobs",
+                "description": "Get FeatureFlags",
                 "consumes": [
                     "application/json"
                 ],
@@ -872,9 +1010,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Job"
+                    "FeatureFlag"
                 ],
-                "summary": "Get Jobs",
+                "summary": "Get FeatureFlags",
                 "parameters": [
                     {
                         "type": "integer",
@@ -901,7 +1039,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.FeatureFlag"
                             }
                         }
                     },
@@ -925,17 +1063,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Job"
+                    "FeatureFlag"
                 ],
-                "summary": "Create Job",
+                "summary": "Create FeatureFlag",
                 "parameters": [
                     {
-                        "description": "Job",
-                        "name": "Job",
+                        "description": "FeatureFlag",
+                        "name": "FeatureFlag",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateJobRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateFeatureFlagRequest"
                         }
                     }
                 ],
@@ -943,7 +1081,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.FeatureFlag"
                         }
                     },
                     "400": {
@@ -958,9 +1096,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/jobs/{id}": {
+        "/feature-flags/{id}": {
             "get": {
-                "description": "Get Job by id",
+                "description": "Get FeatureFlag by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -968,9 +1106,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Job"
+                    "FeatureFlag"
                 ],
-                "summary": "Get Job",
+                "summary": "Get FeatureFlag",
                 "parameters": [
                     {
                         "type": "string",
@@ -984,7 +1122,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.FeatureFlag"
                         }
                     },
                     "400": {
@@ -1007,9 +1145,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Job"
+                    "FeatureFlag"
                 ],
-                "summary": "Destroy Job",
+                "summary": "Destroy FeatureFlag",
                 "parameters": [
                     {
                         "type": "string",
@@ -1043,9 +1181,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Job"
+                    "FeatureFlag"
                 ],
-                "summary": "Update Job",
+                "summary": "Update FeatureFlag",
                 "parameters": [
                     {
                         "type": "string",
@@ -1055,12 +1193,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Job",
-                        "name": "Job",
+                        "description": "FeatureFlag",
+                        "name": "FeatureFlag",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateJobRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateFeatureFlagRequest"
                         }
                     }
                 ],
@@ -1068,7 +1206,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.FeatureFlag"
                         }
                     },
                     "400": {
@@ -1083,9 +1221,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/models": {
+        "/healthy": {
             "get": {
-                "description": "Get Models",
+                "description": "Get app health",
                 "consumes": [
                     "application/json"
                 ],
@@ -1093,18 +1231,12 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Model"
+                    "Health"
                 ],
-                "summary": "Get Models",
+                "summary": "Get Health",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Model"
-                            }
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request"
@@ -1118,9 +1250,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/models/{id}": {
+        "/jobs": {
             "get": {
-                "description": "Get Model by id",
+                "description": "Get Jobs",
                 "consumes": [
                     "application/json"
                 ],
@@ -1128,15 +1260,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Model"
+                    "Job"
                 ],
-                "summary": "Get Model",
+                "summary": "Get Jobs",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -1144,7 +1287,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Model"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
+                            }
                         }
                     },
                     "400": {
@@ -1158,8 +1304,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Destroy by id",
+            "post": {
+                "description": "Create by json config, the task is looked up by url and digest across scheduler clusters with the get_task job, and purged from the seed peers, peers and scheduler state with the delete_task job",
                 "consumes": [
                     "application/json"
                 ],
@@ -1167,21 +1313,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Model"
+                    "Job"
                 ],
-                "summary": "Destroy Model",
+                "summary": "Create Job",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                        "description": "Job",
+                        "name": "Job",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateJobRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -1193,9 +1344,11 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "patch": {
-                "description": "Update by json config",
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Get Job by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1203,9 +1356,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Model"
+                    "Job"
                 ],
-                "summary": "Update Model",
+                "summary": "Get Job",
                 "parameters": [
                     {
                         "type": "string",
@@ -1213,22 +1366,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Model",
-                        "name": "Model",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateModelRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Model"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
                         }
                     },
                     "400": {
@@ -1241,11 +1385,9 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/oauth": {
-            "get": {
-                "description": "Get Oauths",
+            },
+            "delete": {
+                "description": "Destroy by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1253,38 +1395,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Oauth"
+                    "Job"
                 ],
-                "summary": "Get Oauths",
+                "summary": "Destroy Job",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "current page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "maximum": 50,
-                        "minimum": 2,
-                        "type": "integer",
-                        "default": 10,
-                        "description": "return max item count, default 10, max 50",
-                        "name": "per_page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
-                            }
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request"
@@ -1297,8 +1422,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create by json config",
+            "patch": {
+                "description": "Update by json config",
                 "consumes": [
                     "application/json"
                 ],
@@ -1306,17 +1431,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Oauth"
+                    "Job"
                 ],
-                "summary": "Create Oauth",
+                "summary": "Update Job",
                 "parameters": [
                     {
-                        "description": "Oauth",
-                        "name": "Oauth",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Job",
+                        "name": "Job",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateOauthRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateJobRequest"
                         }
                     }
                 ],
@@ -1324,7 +1456,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
                         }
                     },
                     "400": {
@@ -1339,9 +1471,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/oauth/{id}": {
+        "/jobs/{id}/progress": {
             "get": {
-                "description": "Get Oauth by id",
+                "description": "Get per layer and per seed peer progress of the preheat job by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1349,9 +1481,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Oauth"
+                    "Job"
                 ],
-                "summary": "Get Oauth",
+                "summary": "Get Preheat Job Progress",
                 "parameters": [
                     {
                         "type": "string",
@@ -1365,7 +1497,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.PreheatJobProgress"
                         }
                     },
                     "400": {
@@ -1378,19 +1510,21 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
-                "description": "Destroy by id",
+            }
+        },
+        "/jobs/{id}/progress/stream": {
+            "get": {
+                "description": "Stream progress of the preheat job by id with server-sent events until the job is finished",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "Oauth"
+                    "Job"
                 ],
-                "summary": "Destroy Oauth",
+                "summary": "Stream Preheat Job Progress",
                 "parameters": [
                     {
                         "type": "string",
@@ -1402,7 +1536,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.PreheatJobProgress"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -1414,9 +1551,11 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "patch": {
-                "description": "Update by json config",
+            }
+        },
+        "/jobs/{id}/task": {
+            "get": {
+                "description": "Get the seed peers and peers which hold the task of the get task job by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1424,9 +1563,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Oauth"
+                    "Job"
                 ],
-                "summary": "Update Oauth",
+                "summary": "Get Task Job Result",
                 "parameters": [
                     {
                         "type": "string",
@@ -1434,22 +1573,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Oauth",
-                        "name": "Oauth",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateOauthRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.GetTaskJobResult"
                         }
                     },
                     "400": {
@@ -1464,9 +1594,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/peers": {
+        "/models": {
             "get": {
-                "description": "Get Peers",
+                "description": "Get Models",
                 "consumes": [
                     "application/json"
                 ],
@@ -1474,36 +1604,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Peer"
-                ],
-                "summary": "Get Peers",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "current page",
-                        "name": "page",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "maximum": 50,
-                        "minimum": 2,
-                        "type": "integer",
-                        "default": 10,
-                        "description": "return max item count, default 10, max 50",
-                        "name": "per_page",
-                        "in": "query",
-                        "required": true
-                    }
+                    "Model"
                 ],
+                "summary": "Get Models",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Peer"
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Model"
                             }
                         }
                     },
@@ -1517,9 +1627,11 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "post": {
-                "description": "Create by json config",
+            }
+        },
+        "/models/{id}": {
+            "get": {
+                "description": "Get Model by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1527,25 +1639,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Peer"
+                    "Model"
                 ],
-                "summary": "Create Peer",
+                "summary": "Get Model",
                 "parameters": [
                     {
-                        "description": "Peer",
-                        "name": "Peer",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreatePeerRequest"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Peer"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Model"
                         }
                     },
                     "400": {
@@ -1558,11 +1668,9 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/peers/{id}": {
-            "get": {
-                "description": "Get Peer by id",
+            },
+            "delete": {
+                "description": "Destroy by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1570,9 +1678,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Peer"
+                    "Model"
                 ],
-                "summary": "Get Peer",
+                "summary": "Destroy Model",
                 "parameters": [
                     {
                         "type": "string",
@@ -1584,10 +1692,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Peer"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request"
@@ -1600,8 +1705,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Destroy by id",
+            "patch": {
+                "description": "Update by json config",
                 "consumes": [
                     "application/json"
                 ],
@@ -1609,9 +1714,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Peer"
+                    "Model"
                 ],
-                "summary": "Destroy Peer",
+                "summary": "Update Model",
                 "parameters": [
                     {
                         "type": "string",
@@ -1619,11 +1724,23 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Model",
+                        "name": "Model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateModelRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Model"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -1637,48 +1754,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/permissions": {
+        "/oauth": {
             "get": {
-                "description": "Get Permissions",
+                "description": "Get Oauths",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Permission"
+                    "Oauth"
                 ],
-                "summary": "Get Permissions",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_permission_rbac.Permission"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request"
-                    },
-                    "500": {
-                        "description": "Internal Server Error"
-                    }
-                }
-            }
-        },
-        "/personal-access-tokens": {
-            "get": {
-                "description": "Get PersonalAccessTokens",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "PersonalAccessToken"
-                ],
-                "summary": "Get PersonalAccessTokens",
+                "summary": "Get Oauths",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1705,7 +1793,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
                             }
                         }
                     },
@@ -1729,17 +1817,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PersonalAccessToken"
+                    "Oauth"
                 ],
-                "summary": "Create PersonalAccessToken",
+                "summary": "Create Oauth",
                 "parameters": [
                     {
-                        "description": "PersonalAccessToken",
-                        "name": "PersonalAccessToken",
+                        "description": "Oauth",
+                        "name": "Oauth",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreatePersonalAccessTokenRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateOauthRequest"
                         }
                     }
                 ],
@@ -1747,7 +1835,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
                         }
                     },
                     "400": {
@@ -1762,9 +1850,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/personal-access-tokens/{id}": {
+        "/oauth/{id}": {
             "get": {
-                "description": "Get PersonalAccessToken by id",
+                "description": "Get Oauth by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -1772,9 +1860,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PersonalAccessToken"
+                    "Oauth"
                 ],
-                "summary": "Get PersonalAccessToken",
+                "summary": "Get Oauth",
                 "parameters": [
                     {
                         "type": "string",
@@ -1788,7 +1876,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
                         }
                     },
                     "400": {
@@ -1811,9 +1899,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PersonalAccessToken"
+                    "Oauth"
                 ],
-                "summary": "Destroy PersonalAccessToken",
+                "summary": "Destroy Oauth",
                 "parameters": [
                     {
                         "type": "string",
@@ -1847,9 +1935,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PersonalAccessToken"
+                    "Oauth"
                 ],
-                "summary": "Update PersonalAccessToken",
+                "summary": "Update Oauth",
                 "parameters": [
                     {
                         "type": "string",
@@ -1859,12 +1947,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "PersonalAccessToken",
-                        "name": "PersonalAccessToken",
+                        "description": "Oauth",
+                        "name": "Oauth",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdatePersonalAccessTokenRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateOauthRequest"
                         }
                     }
                 ],
@@ -1872,7 +1960,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Oauth"
                         }
                     },
                     "400": {
@@ -1887,9 +1975,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/preheats": {
+        "/oidc/refresh_token": {
             "post": {
-                "description": "Create by json config",
+                "description": "Refresh the id token by the refresh token of the OIDC provider",
                 "consumes": [
                     "application/json"
                 ],
@@ -1897,17 +1985,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Preheat"
+                    "OIDC"
                 ],
-                "summary": "Create V1 Preheat",
+                "summary": "OIDC Refresh Token",
                 "parameters": [
                     {
-                        "description": "Preheat",
-                        "name": "Preheat",
+                        "description": "OIDCRefreshToken",
+                        "name": "OIDCRefreshToken",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateV1PreheatRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.OIDCRefreshTokenRequest"
                         }
                     }
                 ],
@@ -1915,14 +2003,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateV1PreheatResponse"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.OIDCToken"
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "401": {
+                        "description": "Unauthorized"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -1930,40 +2018,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/preheats/{id}": {
+        "/oidc/signin": {
             "get": {
-                "description": "Get Preheat by id",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                "description": "Redirect to the OIDC provider to signin",
+                "tags": [
+                    "OIDC"
                 ],
+                "summary": "OIDC Signin",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/oidc/signin/callback": {
+            "get": {
+                "description": "Signin callback of the OIDC provider, the user is signed in manager",
                 "tags": [
-                    "Preheat"
+                    "OIDC"
                 ],
-                "summary": "Get V1 Preheat",
+                "summary": "OIDC Signin Callback",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
+                        "description": "code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "state",
+                        "name": "state",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.GetV1PreheatResponse"
-                        }
+                    "302": {
+                        "description": "Found"
                     },
                     "400": {
                         "description": "Bad Request"
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "401": {
+                        "description": "Unauthorized"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -1971,9 +2077,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/roles": {
+        "/peers": {
             "get": {
-                "description": "Get roles",
+                "description": "Get Peers",
                 "consumes": [
                     "application/json"
                 ],
@@ -1981,23 +2087,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Peer"
+                ],
+                "summary": "Get Peers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Roles",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Peer"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             },
             "post": {
-                "description": "Create Role by json config",
+                "description": "Create by json config",
                 "consumes": [
                     "application/json"
                 ],
@@ -2005,36 +2140,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Peer"
                 ],
-                "summary": "Create Role",
+                "summary": "Create Peer",
                 "parameters": [
                     {
-                        "description": "Role",
-                        "name": "Role",
+                        "description": "Peer",
+                        "name": "Peer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateRoleRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreatePeerRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Peer"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/roles/{role}": {
-            "get": {
-                "description": "Get Role",
+        "/peers/batch": {
+            "post": {
+                "description": "Tag, drain, reannounce or delete peers in batch",
                 "consumes": [
                     "application/json"
                 ],
@@ -2042,32 +2183,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Peer"
                 ],
-                "summary": "Get Role",
+                "summary": "Batch Peers",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "role",
-                        "name": "role",
-                        "in": "path",
-                        "required": true
+                        "description": "Peer",
+                        "name": "Peer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.BatchPeersRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.BatchPeersResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
-                "description": "Destroy role by json config",
+            }
+        },
+        "/peers/{id}": {
+            "get": {
+                "description": "Get Peer by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2075,34 +2226,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Peer"
                 ],
-                "summary": "Destroy Role",
+                "summary": "Get Peer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "role",
-                        "name": "role",
+                        "description": "id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Peer"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/roles/{role}/permissions": {
-            "post": {
-                "description": "Add Permission by json config",
+            },
+            "delete": {
+                "description": "Destroy by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2110,23 +2265,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
+                    "Peer"
                 ],
-                "summary": "Add Permission For Role",
+                "summary": "Destroy Peer",
                 "parameters": [
-                    {
-                        "description": "Permission",
-                        "name": "Permission",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.AddPermissionForRoleRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "role",
-                        "name": "role",
+                        "description": "id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2138,44 +2284,34 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove Role Permission by json config",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
+            }
+        },
+        "/permissions": {
+            "get": {
+                "description": "Get Permissions",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Role"
-                ],
-                "summary": "Update Role",
-                "parameters": [
-                    {
-                        "description": "Permission",
-                        "name": "Permission",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.DeletePermissionForRoleRequest"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "role",
-                        "name": "role",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Permission"
                 ],
+                "summary": "Get Permissions",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_permission_rbac.Permission"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -2186,9 +2322,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/scheduler-clusters": {
+        "/personal-access-tokens": {
             "get": {
-                "description": "Get SchedulerClusters",
+                "description": "Get PersonalAccessTokens",
                 "consumes": [
                     "application/json"
                 ],
@@ -2196,9 +2332,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SchedulerCluster"
+                    "PersonalAccessToken"
                 ],
-                "summary": "Get SchedulerClusters",
+                "summary": "Get PersonalAccessTokens",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2225,7 +2361,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
                             }
                         }
                     },
@@ -2249,17 +2385,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SchedulerCluster"
+                    "PersonalAccessToken"
                 ],
-                "summary": "Create SchedulerCluster",
+                "summary": "Create PersonalAccessToken",
                 "parameters": [
                     {
-                        "description": "SchedulerCluster",
-                        "name": "SchedulerCluster",
+                        "description": "PersonalAccessToken",
+                        "name": "PersonalAccessToken",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSchedulerClusterRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreatePersonalAccessTokenRequest"
                         }
                     }
                 ],
@@ -2267,7 +2403,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
                         }
                     },
                     "400": {
@@ -2282,9 +2418,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/scheduler-clusters/{id}": {
+        "/personal-access-tokens/{id}": {
             "get": {
-                "description": "Get SchedulerCluster by id",
+                "description": "Get PersonalAccessToken by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2292,9 +2428,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SchedulerCluster"
+                    "PersonalAccessToken"
                 ],
-                "summary": "Get SchedulerCluster",
+                "summary": "Get PersonalAccessToken",
                 "parameters": [
                     {
                         "type": "string",
@@ -2308,7 +2444,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
                         }
                     },
                     "400": {
@@ -2331,9 +2467,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SchedulerCluster"
+                    "PersonalAccessToken"
                 ],
-                "summary": "Destroy SchedulerCluster",
+                "summary": "Destroy PersonalAccessToken",
                 "parameters": [
                     {
                         "type": "string",
@@ -2367,9 +2503,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SchedulerCluster"
+                    "PersonalAccessToken"
                 ],
-                "summary": "Update SchedulerCluster",
+                "summary": "Update PersonalAccessToken",
                 "parameters": [
                     {
                         "type": "string",
@@ -2379,12 +2515,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "SchedulerCluster",
-                        "name": "SchedulerCluster",
+                        "description": "PersonalAccessToken",
+                        "name": "PersonalAccessToken",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSchedulerClusterRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdatePersonalAccessTokenRequest"
                         }
                     }
                 ],
@@ -2392,7 +2528,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PersonalAccessToken"
                         }
                     },
                     "400": {
@@ -2407,54 +2543,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/scheduler-clusters/{id}/schedulers/{scheduler_id}": {
-            "put": {
-                "description": "Add Scheduler to schedulerCluster",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "SchedulerCluster"
-                ],
-                "summary": "Add Scheduler to schedulerCluster",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "scheduler id",
-                        "name": "scheduler_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request"
-                    },
-                    "404": {
-                        "description": "Not Found"
-                    },
-                    "500": {
-                        "description": "Internal Server Error"
-                    }
-                }
-            }
-        },
-        "/schedulers": {
+        "/preheat-schedules": {
             "get": {
-                "description": "Get Schedulers",
+                "description": "Get PreheatSchedules",
                 "consumes": [
                     "application/json"
                 ],
@@ -2462,9 +2553,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Scheduler"
+                    "PreheatSchedule"
                 ],
-                "summary": "Get Schedulers",
+                "summary": "Get PreheatSchedules",
                 "parameters": [
                     {
                         "type": "integer",
@@ -2491,7 +2582,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PreheatSchedule"
                             }
                         }
                     },
@@ -2515,17 +2606,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Scheduler"
+                    "PreheatSchedule"
                 ],
-                "summary": "Create Scheduler",
+                "summary": "Create PreheatSchedule",
                 "parameters": [
                     {
-                        "description": "Scheduler",
-                        "name": "Scheduler",
+                        "description": "PreheatSchedule",
+                        "name": "PreheatSchedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSchedulerRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreatePreheatScheduleRequest"
                         }
                     }
                 ],
@@ -2533,7 +2624,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PreheatSchedule"
                         }
                     },
                     "400": {
@@ -2548,9 +2639,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/schedulers/{id}": {
+        "/preheat-schedules/{id}": {
             "get": {
-                "description": "Get Scheduler by id",
+                "description": "Get PreheatSchedule by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2558,9 +2649,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Scheduler"
+                    "PreheatSchedule"
                 ],
-                "summary": "Get Scheduler",
+                "summary": "Get PreheatSchedule",
                 "parameters": [
                     {
                         "type": "string",
@@ -2574,7 +2665,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PreheatSchedule"
                         }
                     },
                     "400": {
@@ -2597,9 +2688,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Scheduler"
+                    "PreheatSchedule"
                 ],
-                "summary": "Destroy Scheduler",
+                "summary": "Destroy PreheatSchedule",
                 "parameters": [
                     {
                         "type": "string",
@@ -2633,9 +2724,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Scheduler"
+                    "PreheatSchedule"
                 ],
-                "summary": "Update Scheduler",
+                "summary": "Update PreheatSchedule",
                 "parameters": [
                     {
                         "type": "string",
@@ -2645,12 +2736,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Scheduler",
-                        "name": "Scheduler",
+                        "description": "PreheatSchedule",
+                        "name": "PreheatSchedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSchedulerRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdatePreheatScheduleRequest"
                         }
                     }
                 ],
@@ -2658,7 +2749,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PreheatSchedule"
                         }
                     },
                     "400": {
@@ -2673,9 +2764,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/seed-peer-clusters": {
+        "/preheat-schedules/{id}/jobs": {
             "get": {
-                "description": "Get SeedPeerClusters",
+                "description": "Get history preheat jobs created by the PreheatSchedule",
                 "consumes": [
                     "application/json"
                 ],
@@ -2683,10 +2774,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeerCluster"
+                    "PreheatSchedule"
                 ],
-                "summary": "Get SeedPeerClusters",
+                "summary": "Get PreheatSchedule Jobs",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "default": 0,
@@ -2712,7 +2810,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Job"
                             }
                         }
                     },
@@ -2726,9 +2824,11 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
+            }
+        },
+        "/preheat-schedules/{id}/pause": {
             "post": {
-                "description": "Create by json config",
+                "description": "Pause by id, no preheat job will be created until it is resumed",
                 "consumes": [
                     "application/json"
                 ],
@@ -2736,25 +2836,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeerCluster"
+                    "PreheatSchedule"
                 ],
-                "summary": "Create SeedPeerCluster",
+                "summary": "Pause PreheatSchedule",
                 "parameters": [
                     {
-                        "description": "DNCluster",
-                        "name": "SeedPeerCluster",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSeedPeerClusterRequest"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PreheatSchedule"
                         }
                     },
                     "400": {
@@ -2769,9 +2867,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/seed-peer-clusters/{id}": {
-            "get": {
-                "description": "Get SeedPeerCluster by id",
+        "/preheat-schedules/{id}/resume": {
+            "post": {
+                "description": "Resume by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2779,9 +2877,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeerCluster"
+                    "PreheatSchedule"
                 ],
-                "summary": "Get SeedPeerCluster",
+                "summary": "Resume PreheatSchedule",
                 "parameters": [
                     {
                         "type": "string",
@@ -2795,7 +2893,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.PreheatSchedule"
                         }
                     },
                     "400": {
@@ -2808,9 +2906,11 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "delete": {
-                "description": "Destroy by id",
+            }
+        },
+        "/preheats": {
+            "post": {
+                "description": "Create by json config",
                 "consumes": [
                     "application/json"
                 ],
@@ -2818,21 +2918,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeerCluster"
+                    "Preheat"
                 ],
-                "summary": "Destroy SeedPeerCluster",
+                "summary": "Create V1 Preheat",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Preheat",
+                        "name": "Preheat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateV1PreheatRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateV1PreheatResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -2844,9 +2949,11 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "patch": {
-                "description": "Update by json config",
+            }
+        },
+        "/preheats/{id}": {
+            "get": {
+                "description": "Get Preheat by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -2854,9 +2961,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeerCluster"
+                    "Preheat"
                 ],
-                "summary": "Update SeedPeerCluster",
+                "summary": "Get V1 Preheat",
                 "parameters": [
                     {
                         "type": "string",
@@ -2864,22 +2971,13 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "SeedPeerCluster",
-                        "name": "SeedPeerCluster",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSeedPeerClusterRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.GetV1PreheatResponse"
                         }
                     },
                     "400": {
@@ -2894,9 +2992,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/seed-peer-clusters/{id}/scheduler-clusters/{scheduler_cluster_id}": {
-            "put": {
-                "description": "Add SchedulerCluster to SeedPeerCluster",
+        "/roles": {
+            "get": {
+                "description": "Get roles",
                 "consumes": [
                     "application/json"
                 ],
@@ -2904,21 +3002,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeerCluster"
+                    "Role"
                 ],
-                "summary": "Add SchedulerCluster to SeedPeerCluster",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                "summary": "Get Roles",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create Role by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Create Role",
+                "parameters": [
+                    {
+                        "description": "Role",
+                        "name": "Role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
                     },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/roles/{role}": {
+            "get": {
+                "description": "Get Role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Get Role",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "scheduler cluster id",
-                        "name": "scheduler_cluster_id",
+                        "description": "role",
+                        "name": "role",
                         "in": "path",
                         "required": true
                     }
@@ -2930,8 +3082,38 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Destroy role by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Destroy Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "role",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
                     },
                     "500": {
                         "description": "Internal Server Error"
@@ -2939,9 +3121,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/seed-peer-clusters/{id}/seed-peers/{seed_peer_id}": {
-            "put": {
-                "description": "Add SeedPeer to SeedPeerCluster",
+        "/roles/{role}/permissions": {
+            "post": {
+                "description": "Add Permission by json config",
                 "consumes": [
                     "application/json"
                 ],
@@ -2949,21 +3131,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeerCluster"
+                    "Role"
                 ],
-                "summary": "Add Instance to SeedPeerCluster",
+                "summary": "Add Permission For Role",
                 "parameters": [
+                    {
+                        "description": "Permission",
+                        "name": "Permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.AddPermissionForRoleRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "id",
-                        "name": "id",
+                        "description": "role",
+                        "name": "role",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove Role Permission by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Update Role",
+                "parameters": [
+                    {
+                        "description": "Permission",
+                        "name": "Permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.DeletePermissionForRoleRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "seed peer id",
-                        "name": "seed_peer_id",
+                        "description": "role",
+                        "name": "role",
                         "in": "path",
                         "required": true
                     }
@@ -2975,18 +3201,15 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
-                    "404": {
-                        "description": "Not Found"
-                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/seed-peers": {
+        "/scheduler-clusters": {
             "get": {
-                "description": "Get SeedPeers",
+                "description": "Get SchedulerClusters",
                 "consumes": [
                     "application/json"
                 ],
@@ -2994,9 +3217,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeer"
+                    "SchedulerCluster"
                 ],
-                "summary": "Get SeedPeers",
+                "summary": "Get SchedulerClusters",
                 "parameters": [
                     {
                         "type": "integer",
@@ -3023,7 +3246,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
                             }
                         }
                     },
@@ -3047,17 +3270,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeer"
+                    "SchedulerCluster"
                 ],
-                "summary": "Create SeedPeer",
+                "summary": "Create SchedulerCluster",
                 "parameters": [
                     {
-                        "description": "SeedPeer",
-                        "name": "SeedPeer",
+                        "description": "SchedulerCluster",
+                        "name": "SchedulerCluster",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSeedPeerRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSchedulerClusterRequest"
                         }
                     }
                 ],
@@ -3065,7 +3288,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
                         }
                     },
                     "400": {
@@ -3080,9 +3303,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/seed-peers/{id}": {
+        "/scheduler-clusters/{id}": {
             "get": {
-                "description": "Get SeedPeer by id",
+                "description": "Get SchedulerCluster by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3090,9 +3313,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeer"
+                    "SchedulerCluster"
                 ],
-                "summary": "Get SeedPeer",
+                "summary": "Get SchedulerCluster",
                 "parameters": [
                     {
                         "type": "string",
@@ -3106,7 +3329,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
                         }
                     },
                     "400": {
@@ -3129,9 +3352,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeer"
+                    "SchedulerCluster"
                 ],
-                "summary": "Destroy SeedPeer",
+                "summary": "Destroy SchedulerCluster",
                 "parameters": [
                     {
                         "type": "string",
@@ -3165,9 +3388,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "SeedPeer"
+                    "SchedulerCluster"
                 ],
-                "summary": "Update SeedPeer",
+                "summary": "Update SchedulerCluster",
                 "parameters": [
                     {
                         "type": "string",
@@ -3177,12 +3400,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "SeedPeer",
-                        "name": "SeedPeer",
+                        "description": "SchedulerCluster",
+                        "name": "SchedulerCluster",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSeedPeerRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSchedulerClusterRequest"
                         }
                     }
                 ],
@@ -3190,7 +3413,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
                         }
                     },
                     "400": {
@@ -3205,9 +3428,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/signin/{name}": {
+        "/scheduler-clusters/{id}/config-versions": {
             "get": {
-                "description": "oauth signin by json config",
+                "description": "Get config versions of the SchedulerCluster",
                 "consumes": [
                     "application/json"
                 ],
@@ -3215,21 +3438,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "SchedulerCluster"
                 ],
-                "summary": "Oauth Signin",
+                "summary": "Get SchedulerCluster Config Versions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "name",
-                        "name": "name",
+                        "description": "id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.ClusterConfigVersion"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -3243,32 +3490,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/signin/{name}/callback": {
+        "/scheduler-clusters/{id}/config-versions/{version}": {
             "get": {
-                "description": "oauth signin callback by json config",
-                "tags": [
-                    "Oauth"
+                "description": "Get config version of the SchedulerCluster by version",
+                "consumes": [
+                    "application/json"
                 ],
-                "summary": "Oauth Signin Callback",
-                "parameters": [
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SchedulerCluster"
+                ],
+                "summary": "Get SchedulerCluster Config Version",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "name",
-                        "name": "name",
+                        "description": "id",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "code",
-                        "name": "code",
-                        "in": "query",
+                        "description": "version",
+                        "name": "version",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.ClusterConfigVersion"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -3282,9 +3538,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/signup": {
-            "post": {
-                "description": "signup by json config",
+        "/scheduler-clusters/{id}/config-versions/{version}/diff": {
+            "get": {
+                "description": "Get changes of the config version compared with the base version, default base is the previous version",
                 "consumes": [
                     "application/json"
                 ],
@@ -3292,39 +3548,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "SchedulerCluster"
                 ],
-                "summary": "SignUp user",
+                "summary": "Get SchedulerCluster Config Version Diff",
                 "parameters": [
                     {
-                        "description": "User",
-                        "name": "User",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.SignUpRequest"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "base version",
+                        "name": "base",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.ClusterConfigVersionDiff"
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/users": {
-            "get": {
-                "description": "Get Users",
+        "/scheduler-clusters/{id}/config-versions/{version}/rollback": {
+            "post": {
+                "description": "Rollback config of the SchedulerCluster to the version",
                 "consumes": [
                     "application/json"
                 ],
@@ -3332,26 +3602,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "SchedulerCluster"
                 ],
-                "summary": "Get Users",
+                "summary": "Rollback SchedulerCluster Config",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "current page",
-                        "name": "page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "maximum": 50,
-                        "minimum": 2,
-                        "type": "integer",
-                        "default": 10,
-                        "description": "return max item count, default 10, max 50",
-                        "name": "per_page",
-                        "in": "query",
+                        "type": "string",
+                        "description": "version",
+                        "name": "version",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -3359,10 +3625,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
-                            }
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
                         }
                     },
                     "400": {
@@ -3377,9 +3640,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
-                "description": "Get User by id",
+        "/scheduler-clusters/{id}/schedulers/{scheduler_id}": {
+            "put": {
+                "description": "Add Scheduler to schedulerCluster",
                 "consumes": [
                     "application/json"
                 ],
@@ -3387,9 +3650,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "SchedulerCluster"
                 ],
-                "summary": "Get User",
+                "summary": "Add Scheduler to schedulerCluster",
                 "parameters": [
                     {
                         "type": "string",
@@ -3397,14 +3660,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "scheduler id",
+                        "name": "scheduler_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request"
@@ -3416,9 +3683,11 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            },
-            "patch": {
-                "description": "Update by json config",
+            }
+        },
+        "/schedulers": {
+            "get": {
+                "description": "Get Schedulers",
                 "consumes": [
                     "application/json"
                 ],
@@ -3426,32 +3695,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Scheduler"
                 ],
-                "summary": "Update User",
+                "summary": "Get Schedulers",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "User",
-                        "name": "User",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateUserRequest"
-                        }
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
+                            }
                         }
                     },
                     "400": {
@@ -3464,11 +3738,9 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/users/{id}/reset_password": {
+            },
             "post": {
-                "description": "reset password by json config",
+                "description": "Create by json config",
                 "consumes": [
                     "application/json"
                 ],
@@ -3476,50 +3748,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Scheduler"
                 ],
-                "summary": "Reset Password For User",
+                "summary": "Create Scheduler",
                 "parameters": [
                     {
-                        "description": "User",
-                        "name": "User",
+                        "description": "Scheduler",
+                        "name": "Scheduler",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.ResetPasswordRequest"
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSchedulerRequest"
                         }
-                    },
-                    {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
         },
-        "/users/{id}/roles": {
+        "/schedulers/{id}": {
             "get": {
-                "description": "get roles by json config",
+                "description": "Get Scheduler by id",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Scheduler"
                 ],
-                "summary": "Get User Roles",
+                "summary": "Get Scheduler",
                 "parameters": [
                     {
                         "type": "string",
@@ -3533,24 +3807,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/users/{id}/roles/{role}": {
-            "put": {
-                "description": "add role to user by uri config",
+            },
+            "delete": {
+                "description": "Destroy by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -3558,9 +3830,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Scheduler"
                 ],
-                "summary": "Add Role For User",
+                "summary": "Destroy Scheduler",
                 "parameters": [
                     {
                         "type": "string",
@@ -3568,13 +3840,6 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "role",
-                        "name": "role",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -3584,13 +3849,16 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             },
-            "delete": {
-                "description": "delete role by uri config",
+            "patch": {
+                "description": "Update by json config",
                 "consumes": [
                     "application/json"
                 ],
@@ -3598,9 +3866,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Scheduler"
                 ],
-                "summary": "Delete Role For User",
+                "summary": "Update Scheduler",
                 "parameters": [
                     {
                         "type": "string",
@@ -3610,29 +3878,1874 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "role",
-                        "name": "role",
-                        "in": "path",
-                        "required": true
+                        "description": "Scheduler",
+                        "name": "Scheduler",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSchedulerRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Scheduler"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "500": {
                         "description": "Internal Server Error"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "d7y_io_dragonfly_v2_manager_models.Application": {
+        },
+        "/seed-peer-clusters": {
+            "get": {
+                "description": "Get SeedPeerClusters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Get SeedPeerClusters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Create SeedPeerCluster",
+                "parameters": [
+                    {
+                        "description": "DNCluster",
+                        "name": "SeedPeerCluster",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSeedPeerClusterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peer-clusters/{id}": {
+            "get": {
+                "description": "Get SeedPeerCluster by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Get SeedPeerCluster",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Destroy by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Destroy SeedPeerCluster",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Update SeedPeerCluster",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SeedPeerCluster",
+                        "name": "SeedPeerCluster",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSeedPeerClusterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peer-clusters/{id}/config-versions": {
+            "get": {
+                "description": "Get config versions of the SeedPeerCluster",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Get SeedPeerCluster Config Versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.ClusterConfigVersion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peer-clusters/{id}/config-versions/{version}": {
+            "get": {
+                "description": "Get config version of the SeedPeerCluster by version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Get SeedPeerCluster Config Version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.ClusterConfigVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peer-clusters/{id}/config-versions/{version}/diff": {
+            "get": {
+                "description": "Get changes of the config version compared with the base version, default base is the previous version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Get SeedPeerCluster Config Version Diff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "base version",
+                        "name": "base",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.ClusterConfigVersionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peer-clusters/{id}/config-versions/{version}/rollback": {
+            "post": {
+                "description": "Rollback config of the SeedPeerCluster to the version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Rollback SeedPeerCluster Config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peer-clusters/{id}/scheduler-clusters/{scheduler_cluster_id}": {
+            "put": {
+                "description": "Add SchedulerCluster to SeedPeerCluster",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Add SchedulerCluster to SeedPeerCluster",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "scheduler cluster id",
+                        "name": "scheduler_cluster_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peer-clusters/{id}/seed-peers/{seed_peer_id}": {
+            "put": {
+                "description": "Add SeedPeer to SeedPeerCluster",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeerCluster"
+                ],
+                "summary": "Add Instance to SeedPeerCluster",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "seed peer id",
+                        "name": "seed_peer_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peers": {
+            "get": {
+                "description": "Get SeedPeers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeer"
+                ],
+                "summary": "Get SeedPeers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeer"
+                ],
+                "summary": "Create SeedPeer",
+                "parameters": [
+                    {
+                        "description": "SeedPeer",
+                        "name": "SeedPeer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateSeedPeerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/seed-peers/{id}": {
+            "get": {
+                "description": "Get SeedPeer by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeer"
+                ],
+                "summary": "Get SeedPeer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Destroy by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeer"
+                ],
+                "summary": "Destroy SeedPeer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SeedPeer"
+                ],
+                "summary": "Update SeedPeer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SeedPeer",
+                        "name": "SeedPeer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateSeedPeerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/tenants": {
+            "get": {
+                "description": "Get Tenants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Get Tenants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Tenant"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Create Tenant",
+                "parameters": [
+                    {
+                        "description": "Tenant",
+                        "name": "Tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/tenants/{id}": {
+            "get": {
+                "description": "Get Tenant by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Get Tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Destroy by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Destroy Tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Update Tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenant",
+                        "name": "Tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/tenants/{id}/usage": {
+            "get": {
+                "description": "Get quota and resource usage of the Tenant by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Get Tenant Usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.TenantUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/user/signin/{name}": {
+            "get": {
+                "description": "oauth signin by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Oauth Signin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/user/signin/{name}/callback": {
+            "get": {
+                "description": "oauth signin callback by json config",
+                "tags": [
+                    "Oauth"
+                ],
+                "summary": "Oauth Signin Callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/user/signup": {
+            "post": {
+                "description": "signup by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "SignUp user",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "User",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.SignUpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Get Users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get Users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get User by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User",
+                        "name": "User",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/users/{id}/reset_password": {
+            "post": {
+                "description": "reset password by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset Password For User",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "User",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.ResetPasswordRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/users/{id}/roles": {
+            "get": {
+                "description": "get roles by json config",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User Roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/users/{id}/roles/{role}": {
+            "put": {
+                "description": "add role to user by uri config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Add Role For User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "role",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete role by uri config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete Role For User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "role",
+                        "name": "role",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Get Webhooks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get Webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "current page",
+                        "name": "page",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 2,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "return max item count, default 10, max 50",
+                        "name": "per_page",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Webhook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "description": "Create by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create Webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "Webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Get Webhook by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "description": "Destroy by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Destroy Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update by json config",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Update Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook",
+                        "name": "Webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/ping": {
+            "post": {
+                "description": "Deliver the ping event to the Webhook synchronously",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Ping Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "d7y_io_dragonfly_v2_manager_models.Application": {
+            "type": "object",
+            "properties": {
+                "allowed_url_regex": {
+                    "type": "string"
+                },
+                "anti_affinity": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "back_to_source_concurrency": {
+                    "type": "integer"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_del": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "rate_limit": {
+                    "type": "integer"
+                },
+                "require_signature": {
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_models.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "after": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "before": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "is_del": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "request": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_models.ClusterConfigVersion": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "client_config": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "cluster_id": {
+                    "type": "integer"
+                },
+                "cluster_type": {
+                    "type": "string"
+                },
+                "config": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_del": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_models.Config": {
             "type": "object",
             "properties": {
                 "bio": {
@@ -3650,24 +5763,21 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "priority": {
-                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
-                },
                 "updated_at": {
                     "type": "string"
                 },
-                "url": {
-                    "type": "string"
-                },
                 "user": {
                     "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
                 },
                 "user_id": {
                     "type": "integer"
+                },
+                "value": {
+                    "type": "string"
                 }
             }
         },
-        "d7y_io_dragonfly_v2_manager_models.Config": {
+        "d7y_io_dragonfly_v2_manager_models.FeatureFlag": {
             "type": "object",
             "properties": {
                 "bio": {
@@ -3685,6 +5795,18 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "percentage": {
+                    "type": "integer"
+                },
+                "scheduler_cluster": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
+                },
+                "scheduler_cluster_id": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -3693,9 +5815,6 @@ const docTemplate = `{
                 },
                 "user_id": {
                     "type": "integer"
-                },
-                "value": {
-                    "type": "string"
                 }
             }
         },
@@ -3721,6 +5840,9 @@ const docTemplate = `{
                 "is_del": {
                     "type": "integer"
                 },
+                "preheat_schedule_id": {
+                    "type": "integer"
+                },
                 "result": {
                     "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
                 },
@@ -3742,6 +5864,9 @@ const docTemplate = `{
                 "task_id": {
                     "type": "string"
                 },
+                "tenant_id": {
+                    "type": "integer"
+                },
                 "type": {
                     "type": "string"
                 },
@@ -3895,6 +6020,12 @@ const docTemplate = `{
                 "state": {
                     "type": "string"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "type": {
                     "type": "string"
                 },
@@ -3947,6 +6078,56 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_models.PreheatSchedule": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_del": {
+                    "type": "integer"
+                },
+                "last_digest": {
+                    "type": "string"
+                },
+                "last_scheduled_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheduler_clusters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SchedulerCluster"
+                    }
+                },
+                "state": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_models.Scheduler": {
             "type": "object",
             "properties": {
@@ -4054,6 +6235,9 @@ const docTemplate = `{
                         "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeerCluster"
                     }
                 },
+                "tenant_id": {
+                    "type": "integer"
+                },
                 "updated_at": {
                     "type": "string"
                 }
@@ -4148,8 +6332,43 @@ const docTemplate = `{
                         "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.SeedPeer"
                     }
                 },
+                "tenant_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_models.Tenant": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_del": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quota": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.JSONMap"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -4197,6 +6416,47 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_models.Webhook": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_del": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_models.User"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_permission_rbac.Permission": {
             "type": "object",
             "required": [
@@ -4235,6 +6495,95 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.BatchPeerResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.BatchPeersRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "ids"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "tag",
+                        "untag",
+                        "drain",
+                        "undrain",
+                        "reannounce",
+                        "delete"
+                    ]
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.BatchPeersResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.BatchPeerResult"
+                    }
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.ClusterConfigChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {}
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.ClusterConfigVersionDiff": {
+            "type": "object",
+            "properties": {
+                "base_version": {
+                    "description": "BaseVersion is the version compared with, zero means empty config.",
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Changes is the changed fields, key is the field path joined by dot.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.ClusterConfigChange"
+                    }
+                },
+                "version": {
+                    "description": "Version is the compared version.",
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.CreateApplicationRequest": {
             "type": "object",
             "required": [
@@ -4244,6 +6593,18 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "allowed_url_regex": {
+                    "type": "string"
+                },
+                "anti_affinity": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "back_to_source_concurrency": {
+                    "type": "integer"
+                },
                 "bio": {
                     "type": "string"
                 },
@@ -4253,6 +6614,15 @@ const docTemplate = `{
                 "priority": {
                     "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.PriorityConfig"
                 },
+                "rate_limit": {
+                    "type": "integer"
+                },
+                "require_signature": {
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "type": "integer"
+                },
                 "url": {
                     "type": "string"
                 },
@@ -4359,11 +6729,37 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "user_id": {
+                "user_id": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.CreateFeatureFlagRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scheduler_cluster_id"
+            ],
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "percentage": {
+                    "type": "integer",
+                    "maximum": 100
+                },
+                "scheduler_cluster_id": {
                     "type": "integer"
                 },
-                "value": {
-                    "type": "string"
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -4396,6 +6792,9 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "tenant_id": {
+                    "type": "integer"
+                },
                 "type": {
                     "type": "string"
                 },
@@ -4538,6 +6937,37 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.CreatePreheatScheduleRequest": {
+            "type": "object",
+            "required": [
+                "args",
+                "cron",
+                "name"
+            ],
+            "properties": {
+                "args": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.PreheatArgs"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheduler_cluster_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.CreateRoleRequest": {
             "type": "object",
             "required": [
@@ -4584,6 +7014,9 @@ const docTemplate = `{
                 },
                 "seed_peer_cluster_id": {
                     "type": "integer"
+                },
+                "tenant_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -4637,6 +7070,9 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "tenant_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -4685,6 +7121,26 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.CreateTenantRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quota": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.TenantQuota"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.CreateV1PreheatRequest": {
             "type": "object",
             "required": [
@@ -4721,6 +7177,37 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "name",
+                "url"
+            ],
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.DeletePermissionForRoleRequest": {
             "type": "object",
             "required": [
@@ -4781,6 +7268,56 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.GetTaskJobResult": {
+            "type": "object",
+            "properties": {
+                "content_length": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.TaskPeer"
+                    }
+                },
+                "placement": {
+                    "description": "Placement is the replica count of the persistent cache task in each failure domain.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "replication": {
+                    "description": "Replication is the replication policy of the persistent cache task.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/d7y_io_dragonfly_v2_pkg_types.Replication"
+                        }
+                    ]
+                },
+                "seed_peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.TaskPeer"
+                    }
+                },
+                "state": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "total_piece_count": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.GetV1PreheatResponse": {
             "type": "object",
             "properties": {
@@ -4798,6 +7335,132 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.OIDCRefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.OIDCToken": {
+            "type": "object",
+            "properties": {
+                "expire": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.PreheatArgs": {
+            "type": "object",
+            "required": [
+                "type",
+                "url"
+            ],
+            "properties": {
+                "filter": {
+                    "type": "string"
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "platforms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "image",
+                        "file"
+                    ]
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.PreheatJobProgress": {
+            "type": "object",
+            "properties": {
+                "failed_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "layers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.PreheatLayerProgress"
+                    }
+                },
+                "pending_count": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "succeeded_count": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.PreheatLayerProgress": {
+            "type": "object",
+            "properties": {
+                "content_length": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_piece_count": {
+                    "type": "integer"
+                },
+                "seed_peer_host_id": {
+                    "type": "string"
+                },
+                "seed_peer_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                },
+                "task_uuid": {
+                    "type": "string"
+                },
+                "total_piece_count": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.PriorityConfig": {
             "type": "object",
             "required": [
@@ -4836,29 +7499,80 @@ const docTemplate = `{
                 }
             }
         },
-        "d7y_io_dragonfly_v2_manager_types.SchedulerClusterClientConfig": {
+        "d7y_io_dragonfly_v2_manager_types.SchedulerClusterClientConfig": {
+            "type": "object",
+            "properties": {
+                "bandwidth_policies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/d7y_io_dragonfly_v2_pkg_types.BandwidthPolicy"
+                    }
+                },
+                "concurrent_piece_count": {
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 1
+                },
+                "load_limit": {
+                    "type": "integer",
+                    "maximum": 2000,
+                    "minimum": 1
+                },
+                "method_request_timeouts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "piece_digest_algorithm": {
+                    "type": "string",
+                    "enum": [
+                        "md5",
+                        "xxh3",
+                        "blake3"
+                    ]
+                },
+                "piece_encryption": {
+                    "type": "string",
+                    "enum": [
+                        "disable",
+                        "prefer",
+                        "require"
+                    ]
+                },
+                "request_timeout": {
+                    "type": "string"
+                },
+                "url_policy": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_pkg_types.URLPolicy"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.SchedulerClusterConfig": {
             "type": "object",
             "properties": {
-                "concurrent_piece_count": {
+                "back_to_source_origin_limit": {
                     "type": "integer",
-                    "maximum": 50,
+                    "maximum": 10000,
                     "minimum": 1
                 },
-                "load_limit": {
+                "back_to_source_origin_qps": {
                     "type": "integer",
-                    "maximum": 2000,
                     "minimum": 1
-                }
-            }
-        },
-        "d7y_io_dragonfly_v2_manager_types.SchedulerClusterConfig": {
-            "type": "object",
-            "properties": {
+                },
+                "back_to_source_task_limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
                 "candidate_parent_limit": {
                     "type": "integer",
                     "maximum": 20,
                     "minimum": 1
                 },
+                "evaluator_weights": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.SchedulerClusterEvaluatorWeights"
+                },
                 "filter_parent_limit": {
                     "type": "integer",
                     "maximum": 1000,
@@ -4866,6 +7580,51 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.SchedulerClusterEvaluatorWeights": {
+            "type": "object",
+            "properties": {
+                "finished_piece": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "free_bandwidth": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "free_upload": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "host_type": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "idc_affinity": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "location_affinity": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "parent_host_upload_success": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "topology_affinity": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.SchedulerClusterScopes": {
             "type": "object",
             "properties": {
@@ -4880,6 +7639,9 @@ const docTemplate = `{
                 },
                 "location": {
                     "type": "string"
+                },
+                "rule": {
+                    "type": "string"
                 }
             }
         },
@@ -4928,6 +7690,81 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.TaskPeer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "finished_piece_count": {
+                    "type": "integer"
+                },
+                "host_id": {
+                    "type": "string"
+                },
+                "host_type": {
+                    "type": "string"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "scheduler_cluster_id": {
+                    "type": "integer"
+                },
+                "scheduler_hostname": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "topology": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_pkg_types.Topology"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.TenantQuota": {
+            "type": "object",
+            "properties": {
+                "concurrent_preheats": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "origin_qps": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "seed_peer_storage_bytes": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_manager_types.TenantUsage": {
+            "type": "object",
+            "properties": {
+                "concurrent_preheats": {
+                    "type": "integer"
+                },
+                "quota": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.TenantQuota"
+                },
+                "seed_peer_storage_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.URLPriorityConfig": {
             "type": "object",
             "required": [
@@ -4951,6 +7788,18 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "allowed_url_regex": {
+                    "type": "string"
+                },
+                "anti_affinity": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "back_to_source_concurrency": {
+                    "type": "integer"
+                },
                 "bio": {
                     "type": "string"
                 },
@@ -4960,6 +7809,15 @@ const docTemplate = `{
                 "priority": {
                     "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.PriorityConfig"
                 },
+                "rate_limit": {
+                    "type": "integer"
+                },
+                "require_signature": {
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "type": "integer"
+                },
                 "url": {
                     "type": "string"
                 },
@@ -5052,6 +7910,28 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.UpdateFeatureFlagRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "integer",
+                    "maximum": 100
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "inactive"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.UpdateJobRequest": {
             "type": "object",
             "properties": {
@@ -5129,6 +8009,29 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.UpdatePreheatScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "args": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.PreheatArgs"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "scheduler_cluster_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.UpdateSchedulerClusterRequest": {
             "type": "object",
             "properties": {
@@ -5152,6 +8055,9 @@ const docTemplate = `{
                 },
                 "seed_peer_cluster_id": {
                     "type": "integer"
+                },
+                "tenant_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -5195,6 +8101,9 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "tenant_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -5232,6 +8141,23 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.UpdateTenantRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quota": {
+                    "$ref": "#/definitions/d7y_io_dragonfly_v2_manager_types.TenantQuota"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_manager_types.UpdateUserRequest": {
             "type": "object",
             "properties": {
@@ -5252,6 +8178,36 @@ const docTemplate = `{
                 }
             }
         },
+        "d7y_io_dragonfly_v2_manager_types.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "inactive"
+                    ]
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "d7y_io_dragonfly_v2_pkg_objectstorage.BucketMetadata": {
             "type": "object",
             "properties": {
@@ -5264,6 +8220,103 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "d7y_io_dragonfly_v2_pkg_types.BandwidthPolicy": {
+            "type": "object",
+            "required": [
+                "traffic_class"
+            ],
+            "properties": {
+                "end": {
+                    "description": "End is the end time of day of the window in format of 15:04, empty means the end of the day,\nthe window crosses midnight when the end is before the start.",
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "RateLimit is the rate limit in bytes per second, zero means no limit.",
+                    "type": "integer"
+                },
+                "start": {
+                    "description": "Start is the start time of day of the window in format of 15:04, empty means 00:00.",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Timezone is the IANA time zone of the window, empty means the local time zone of the peer.",
+                    "type": "string"
+                },
+                "traffic_class": {
+                    "description": "TrafficClass is the traffic limited by the policy.",
+                    "type": "string",
+                    "enum": [
+                        "download",
+                        "upload",
+                        "back_to_source"
+                    ]
+                },
+                "weekdays": {
+                    "description": "Weekdays are the days of the window, e.g. Mon, empty means every day.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_pkg_types.Replication": {
+            "type": "object",
+            "properties": {
+                "replicas": {
+                    "description": "Replicas is the desired count of the replicas.",
+                    "type": "integer"
+                },
+                "spread_by": {
+                    "description": "SpreadBy is the topology label which the replicas are spread across, e.g. zone.",
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_pkg_types.Topology": {
+            "type": "object",
+            "properties": {
+                "hypervisor": {
+                    "description": "Hypervisor is the physical host or hypervisor where the host is running.",
+                    "type": "string"
+                },
+                "rack": {
+                    "description": "Rack is the rack of host.",
+                    "type": "string"
+                },
+                "region": {
+                    "description": "Region is the region of host.",
+                    "type": "string"
+                },
+                "switch": {
+                    "description": "Switch is the top-of-rack switch of host.",
+                    "type": "string"
+                },
+                "zone": {
+                    "description": "Zone is the availability zone of host.",
+                    "type": "string"
+                }
+            }
+        },
+        "d7y_io_dragonfly_v2_pkg_types.URLPolicy": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "description": "Allow are the regexes of the allowed urls, empty means all urls are allowed.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deny": {
+                    "description": "Deny are the regexes of the denied urls, the deny patterns take precedence.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
         }
     }
 }`
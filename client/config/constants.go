@@ -38,12 +38,14 @@ const (
 	DefaultGCInterval      = 1 * time.Minute
 	DefaultDaemonAliveTime = 5 * time.Minute
 	DefaultScheduleTimeout = 5 * time.Minute
+	DefaultRequestTimeout  = 1 * time.Minute
 
 	DefaultSchedulerIP   = "127.0.0.1"
 	DefaultSchedulerPort = 8002
 
 	DefaultPieceDispatcherRandomRatio = 0.1
 	DefaultObjectMaxReplicas          = 3
+	DefaultS3GatewayRegion            = "us-east-1"
 )
 
 // Store strategy.
@@ -58,6 +60,7 @@ const (
 	CmdImport = "import"
 	CmdExport = "export"
 	CmdDelete = "delete"
+	CmdList   = "list"
 )
 
 // Service defalut port of listening.
@@ -66,6 +69,8 @@ const (
 	DefaultPeerStartPort          = 65000
 	DefaultUploadStartPort        = 65002
 	DefaultObjectStorageStartPort = 65004
+	DefaultRegistryStartPort      = 65005
+	DefaultS3GatewayStartPort     = 65010
 	DefaultHealthyStartPort       = 40901
 )
 
@@ -93,3 +98,61 @@ const (
 	// DefaultProbeInterval is the default interval of probing host.
 	DefaultProbeInterval = 20 * time.Minute
 )
+
+const (
+	// DefaultProfilingInterval is the default interval of collecting a cpu profile.
+	DefaultProfilingInterval = 15 * time.Second
+)
+
+const (
+	// DefaultQuotaHighWatermark is the default usage percent of the quota to start evicting tasks.
+	DefaultQuotaHighWatermark = 90
+
+	// DefaultQuotaLowWatermark is the default usage percent of the quota to stop evicting tasks.
+	DefaultQuotaLowWatermark = 80
+)
+
+const (
+	// DefaultTierMemorySize is the default capacity of the memory tier.
+	DefaultTierMemorySize = 256 * unit.MB
+)
+
+const (
+	// DefaultIOUringEntries is the default number of the io_uring submission queue entries.
+	DefaultIOUringEntries = 256
+)
+
+const (
+	// DefaultResumePersistInterval is the default interval to persist the written pieces of the unfinished tasks.
+	DefaultResumePersistInterval = 5 * time.Second
+)
+
+const (
+	// DefaultRegistryHealthCheckInterval is the default interval of checking the health of registry mirror upstreams.
+	DefaultRegistryHealthCheckInterval = 30 * time.Second
+
+	// DefaultRegistryHealthCheckTimeout is the default timeout of checking the health of registry mirror upstreams.
+	DefaultRegistryHealthCheckTimeout = 5 * time.Second
+)
+
+const (
+	// DefaultAdaptiveInterval is the default interval of sampling the host load for the adaptive upload rate limit.
+	DefaultAdaptiveInterval = 10 * time.Second
+
+	// DefaultAdaptiveCPUThreshold is the default threshold of the cpu usage percent.
+	DefaultAdaptiveCPUThreshold = 80
+
+	// DefaultAdaptiveDiskLatencyThreshold is the default threshold of the average latency of the disk io.
+	DefaultAdaptiveDiskLatencyThreshold = 100 * time.Millisecond
+
+	// DefaultAdaptiveNetworkThreshold is the default threshold of the usage percent of the network bandwidth.
+	DefaultAdaptiveNetworkThreshold = 90
+)
+
+const (
+	// DefaultUploadReservationConcurrentLimit is the default number of the upload slots.
+	DefaultUploadReservationConcurrentLimit = 100
+
+	// DefaultUploadReservationTTL is the default time to keep a reservation until the child starts downloading.
+	DefaultUploadReservationTTL = 10 * time.Second
+)
@@ -39,12 +39,14 @@ const (
 	AttributePieceSize         = attribute.Key("d7y.peer.piece.size")
 	AttributePieceWorker       = attribute.Key("d7y.peer.piece.worker")
 	AttributePieceSuccess      = attribute.Key("d7y.peer.piece.success")
+	AttributePieceRetryCount   = attribute.Key("d7y.peer.piece.retry_count")
 	AttributeGetPieceStartNum  = attribute.Key("d7y.peer.piece.start")
 	AttributeGetPieceLimit     = attribute.Key("d7y.peer.piece.limit")
 	AttributeGetPieceCount     = attribute.Key("d7y.peer.piece.count")
 	AttributeGetPieceRetry     = attribute.Key("d7y.peer.piece.retry")
 	AttributeWritePieceSuccess = attribute.Key("d7y.peer.piece.write.success")
 	AttributeSeedTaskSuccess   = attribute.Key("d7y.seed.task.success")
+	AttributeBackSourceReason  = attribute.Key("d7y.peer.back_source.reason")
 
 	SpanFileTask          = "file-task"
 	SpanStreamTask        = "stream-task"
@@ -32,10 +32,14 @@ import (
 	"d7y.io/dragonfly/v2/internal/dferrors"
 	"d7y.io/dragonfly/v2/pkg/os/user"
 	"d7y.io/dragonfly/v2/pkg/strings"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 type DfcacheConfig = CacheOption
 
+// StdinInput is the input which reads the content from stdin, it is imported by the streaming import api.
+const StdinInput = "-"
+
 // CacheOption holds all the runtime config information.
 type CacheOption struct {
 	base.Options `yaml:",inline" mapstructure:",squash"`
@@ -67,6 +71,25 @@ type CacheOption struct {
 
 	// LocalOnly indicates check local cache only
 	LocalOnly bool `yaml:"localOnly,omitempty" mapstructure:"localOnly,omitempty"`
+
+	// Namespace isolates the caches of different users, the same cid in different namespaces are different caches.
+	Namespace string `yaml:"namespace,omitempty" mapstructure:"namespace,omitempty"`
+
+	// Labels are the key/value tags of the cache for import task, the caches are listed and deleted by labels.
+	Labels map[string]string `yaml:"labels,omitempty" mapstructure:"labels,omitempty"`
+
+	// TTL is the time to live of the cache for import task, 0 is never expired.
+	TTL time.Duration `yaml:"ttl,omitempty" mapstructure:"ttl,omitempty"`
+
+	// Replicas is the replica count of the persistent cache for import task, scheduler maintains
+	// the replicas as the peers come and go, 0 is not persistent.
+	Replicas int `yaml:"replicas,omitempty" mapstructure:"replicas,omitempty"`
+
+	// SpreadBy is the topology label which the replicas are spread across, e.g. zone.
+	SpreadBy string `yaml:"spreadBy,omitempty" mapstructure:"spread-by,omitempty"`
+
+	// DaemonSock is daemon download socket path, it is used by the streaming api of stdin and stdout.
+	DaemonSock string `yaml:"daemonSock,omitempty" mapstructure:"daemon-sock,omitempty"`
 }
 
 func NewDfcacheConfig() *CacheOption {
@@ -77,7 +100,34 @@ func validateCacheStat(cfg *CacheOption) error {
 	return nil
 }
 
+// IsBulk returns whether the caches are selected by namespace and labels.
+func (cfg *CacheOption) IsBulk() bool {
+	return cfg.Namespace != "" || len(cfg.Labels) > 0
+}
+
 func validateCacheImport(cfg *CacheOption) error {
+	if cfg.TTL < 0 {
+		return fmt.Errorf("ttl %s is negative: %w", cfg.TTL, dferrors.ErrInvalidArgument)
+	}
+
+	if cfg.Replicas < 0 {
+		return fmt.Errorf("replicas %d is negative: %w", cfg.Replicas, dferrors.ErrInvalidArgument)
+	}
+
+	if cfg.SpreadBy != "" {
+		if cfg.Replicas == 0 {
+			return fmt.Errorf("spread by %s requires replicas: %w", cfg.SpreadBy, dferrors.ErrInvalidArgument)
+		}
+
+		if !types.IsTopologyLabel(cfg.SpreadBy) {
+			return fmt.Errorf("spread by %s is not a topology label: %w", cfg.SpreadBy, dferrors.ErrInvalidArgument)
+		}
+	}
+
+	if cfg.Path == StdinInput {
+		return nil
+	}
+
 	if err := cfg.checkInput(); err != nil {
 		return fmt.Errorf("input path %s: %w", err.Error(), dferrors.ErrInvalidArgument)
 	}
@@ -85,6 +135,10 @@ func validateCacheImport(cfg *CacheOption) error {
 }
 
 func ValidateCacheExport(cfg *CacheOption) error {
+	if cfg.Output == StdoutOutput {
+		return nil
+	}
+
 	if err := cfg.checkOutput(); err != nil {
 		return fmt.Errorf("output %s: %w", err.Error(), dferrors.ErrInvalidArgument)
 	}
@@ -100,6 +154,12 @@ func (cfg *CacheOption) Validate(cmd string) error {
 	if cfg == nil {
 		return fmt.Errorf("runtime config: %w", dferrors.ErrInvalidArgument)
 	}
+
+	// The caches are listed or bulk deleted by namespace and labels without cid
+	if cmd == CmdList || (cmd == CmdDelete && cfg.Cid == "" && cfg.IsBulk()) {
+		return nil
+	}
+
 	if cfg.Cid == "" {
 		return fmt.Errorf("missing Cid: %w", dferrors.ErrInvalidArgument)
 	}
@@ -135,6 +195,10 @@ func convertCacheImport(cfg *CacheOption, args []string) error {
 		return fmt.Errorf("missing input file: %w", dferrors.ErrInvalidArgument)
 	}
 
+	if cfg.Path == StdinInput {
+		return nil
+	}
+
 	if cfg.Path, err = filepath.Abs(cfg.Path); err != nil {
 		return fmt.Errorf("get absulate path for %s: %w", cfg.Path, err)
 	}
@@ -150,6 +214,10 @@ func ConvertCacheExport(cfg *CacheOption, args []string) error {
 		return fmt.Errorf("missing output file: %w", dferrors.ErrInvalidArgument)
 	}
 
+	if cfg.Output == StdoutOutput {
+		return nil
+	}
+
 	if cfg.Output, err = filepath.Abs(cfg.Output); err != nil {
 		return fmt.Errorf("get absulate path for %s: %w", cfg.Output, err)
 	}
@@ -160,6 +228,10 @@ func ConvertCacheDelete(cfg *CacheOption, args []string) error {
 	return nil
 }
 
+func convertCacheList(cfg *CacheOption, args []string) error {
+	return nil
+}
+
 func (cfg *CacheOption) Convert(cmd string, args []string) error {
 	if cfg == nil {
 		return fmt.Errorf("runtime config: %w", dferrors.ErrInvalidArgument)
@@ -174,6 +246,8 @@ func (cfg *CacheOption) Convert(cmd string, args []string) error {
 		return ConvertCacheExport(cfg, args)
 	case CmdDelete:
 		return ConvertCacheDelete(cfg, args)
+	case CmdList:
+		return convertCacheList(cfg, args)
 	default:
 		return fmt.Errorf("unknown cache subcommand %s: %w", cmd, dferrors.ErrInvalidArgument)
 	}
@@ -24,6 +24,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -35,11 +36,21 @@ import (
 	"d7y.io/dragonfly/v2/pkg/net/url"
 	"d7y.io/dragonfly/v2/pkg/os/user"
 	pkgstrings "d7y.io/dragonfly/v2/pkg/strings"
+	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/pkg/unit"
 )
 
 type DfgetConfig = ClientOption
 
+// StdoutOutput is the output which writes the content to stdout, it requires stream.
+const StdoutOutput = "-"
+
+// ProgressJSON is the progress mode which writes the newline-delimited json progress events to stdout.
+const ProgressJSON = "json"
+
+// FdOutputPrefix is the prefix of the output which writes the content to an inherited file descriptor, eg: fd://3.
+const FdOutputPrefix = "fd://"
+
 // ClientOption holds all the runtime config information.
 type ClientOption struct {
 	base.Options `yaml:",inline" mapstructure:",squash"`
@@ -128,10 +139,45 @@ type ClientOption struct {
 
 	RecursiveRejectRegex string `yaml:"rejectRegex,omitempty" mapstructure:"reject-regex,omitempty"`
 
+	// RecursiveInclude indicates the glob patterns of the relative file paths to download,
+	// the file is downloaded when any pattern matches its relative path or name.
+	RecursiveInclude []string `yaml:"include,omitempty" mapstructure:"include,omitempty"`
+
+	// RecursiveExclude indicates the glob patterns of the relative file or directory paths to skip.
+	RecursiveExclude []string `yaml:"exclude,omitempty" mapstructure:"exclude,omitempty"`
+
+	// RecursiveConcurrent indicates the number of files downloading concurrently in recursive downloading.
+	RecursiveConcurrent int `yaml:"recursiveConcurrent,omitempty" mapstructure:"recursive-concurrent,omitempty"`
+
 	KeepOriginalOffset bool `yaml:"keepOriginalOffset,omitempty" mapstructure:"original-offset,omitempty"`
 
 	// Range stands download range for url, like: 0-9, will download 10 bytes from 0 to 9 ([0:9])
 	Range string `yaml:"range,omitempty" mapstructure:"range,omitempty"`
+
+	// Stream writes the verified pieces in order to the output as they arrive.
+	Stream bool `yaml:"stream,omitempty" mapstructure:"stream,omitempty"`
+
+	// OutputPipe indicates the output is a named pipe or a file descriptor, the content is streamed into it directly.
+	OutputPipe bool `yaml:"outputPipe,omitempty" mapstructure:"output-pipe,omitempty"`
+
+	// InputFile is the manifest of urls to download, in format of one url per line or json with per-url options.
+	InputFile string `yaml:"inputFile,omitempty" mapstructure:"input-file,omitempty"`
+
+	// InputConcurrent indicates the number of urls in the manifest downloading concurrently.
+	InputConcurrent int `yaml:"inputConcurrent,omitempty" mapstructure:"input-concurrent,omitempty"`
+
+	// Report is the path to write the json result report of the manifest downloading.
+	Report string `yaml:"report,omitempty" mapstructure:"report,omitempty"`
+
+	// Progress is the progress mode, json writes the newline-delimited json progress events to stdout.
+	Progress string `yaml:"progress,omitempty" mapstructure:"progress,omitempty"`
+
+	// Signature is the url or the inline content of the signature, dfdaemon verifies the artifact
+	// against its trusted keys before the download is reported successful.
+	Signature string `yaml:"signature,omitempty" mapstructure:"signature,omitempty"`
+
+	// SignatureType is the type of the signature, cosign or gpg.
+	SignatureType string `yaml:"signatureType,omitempty" mapstructure:"signature-type,omitempty"`
 }
 
 func NewDfgetConfig() *ClientOption {
@@ -143,7 +189,11 @@ func (cfg *ClientOption) Validate() error {
 		return fmt.Errorf("runtime config: %w", dferrors.ErrInvalidArgument)
 	}
 
-	if !url.IsValid(cfg.URL) {
+	if cfg.InputFile != "" {
+		if err := cfg.checkInputFile(); err != nil {
+			return err
+		}
+	} else if !url.IsValid(cfg.URL) {
 		return fmt.Errorf("url %s: %w", cfg.URL, dferrors.ErrInvalidArgument)
 	}
 
@@ -155,7 +205,41 @@ func (cfg *ClientOption) Validate() error {
 		return err
 	}
 
-	if err := cfg.checkOutput(); err != nil {
+	for _, pattern := range append(cfg.RecursiveInclude, cfg.RecursiveExclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("pattern %s: %w", pattern, err)
+		}
+	}
+
+	if cfg.Progress != "" && cfg.Progress != ProgressJSON {
+		return fmt.Errorf("progress %s: %w", cfg.Progress, dferrors.ErrInvalidArgument)
+	}
+
+	if cfg.Progress == ProgressJSON && cfg.Output == StdoutOutput {
+		return fmt.Errorf("progress json is conflict with output %s: %w", StdoutOutput, dferrors.ErrInvalidArgument)
+	}
+
+	if cfg.Stream && cfg.Recursive {
+		return fmt.Errorf("stream is conflict with recursive: %w", dferrors.ErrInvalidArgument)
+	}
+
+	if err := cfg.checkSignature(); err != nil {
+		return err
+	}
+
+	if cfg.Output == StdoutOutput {
+		if !cfg.Stream {
+			return fmt.Errorf("output %s requires stream: %w", StdoutOutput, dferrors.ErrInvalidArgument)
+		}
+	} else if cfg.OutputPipe {
+		if !cfg.Stream {
+			return fmt.Errorf("output pipe requires stream: %w", dferrors.ErrInvalidArgument)
+		}
+
+		if err := cfg.checkOutputPipe(); err != nil {
+			return fmt.Errorf("output %s: %w", err.Error(), dferrors.ErrInvalidArgument)
+		}
+	} else if err := cfg.checkOutput(); err != nil {
 		return fmt.Errorf("output %s: %w", err.Error(), dferrors.ErrInvalidArgument)
 	}
 
@@ -170,7 +254,34 @@ func (cfg *ClientOption) Validate() error {
 	return nil
 }
 
+// checkSignature checks the signature is verifiable, the signature only verifies a single artifact
+// downloaded into the output file.
+func (cfg *ClientOption) checkSignature() error {
+	if cfg.Signature == "" {
+		return nil
+	}
+
+	if cfg.SignatureType != types.SignatureTypeCosign && cfg.SignatureType != types.SignatureTypeGPG {
+		return fmt.Errorf("signature type %s: %w", cfg.SignatureType, dferrors.ErrInvalidArgument)
+	}
+
+	if cfg.Stream || cfg.Recursive || cfg.InputFile != "" {
+		return fmt.Errorf("signature is conflict with stream, recursive and input file: %w", dferrors.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
 func (cfg *ClientOption) Convert(args []string) error {
+	// The output of the manifest downloading is the directory, default is the working directory.
+	if cfg.InputFile != "" && pkgstrings.IsBlank(cfg.Output) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		cfg.Output = wd
+	}
+
 	if pkgstrings.IsBlank(cfg.Output) {
 		url := strings.TrimRight(cfg.URL, "/")
 		idx := strings.LastIndexByte(url, '/')
@@ -180,7 +291,12 @@ func (cfg *ClientOption) Convert(args []string) error {
 		cfg.Output = url[idx+1:]
 	}
 
-	if !filepath.IsAbs(cfg.Output) {
+	// The pipe is written as a stream.
+	if cfg.OutputPipe {
+		cfg.Stream = true
+	}
+
+	if cfg.Output != StdoutOutput && !strings.HasPrefix(cfg.Output, FdOutputPrefix) && !filepath.IsAbs(cfg.Output) {
 		absPath, err := filepath.Abs(cfg.Output)
 		if err != nil {
 			return fmt.Errorf("get absolute path[%s] error: %v", cfg.Output, err)
@@ -195,7 +311,7 @@ func (cfg *ClientOption) Convert(args []string) error {
 		cfg.Tag = ""
 	}
 
-	if cfg.Console {
+	if cfg.Console || cfg.Progress == ProgressJSON {
 		cfg.ShowProgress = false
 	}
 	return nil
@@ -236,8 +352,8 @@ func (cfg *ClientOption) checkOutput() error {
 	}
 
 	f, err := os.Stat(cfg.Output)
-	// when not recursive download, need a file
-	if !cfg.Recursive && err == nil && f.IsDir() {
+	// when not recursive or manifest download, need a file
+	if !cfg.Recursive && cfg.InputFile == "" && err == nil && f.IsDir() {
 		return fmt.Errorf("path[%s] is directory but requires file path", cfg.Output)
 	}
 
@@ -252,6 +368,46 @@ func (cfg *ClientOption) checkOutput() error {
 	return nil
 }
 
+// checkInputFile checks the manifest downloading options.
+func (cfg *ClientOption) checkInputFile() error {
+	if _, err := os.Stat(cfg.InputFile); err != nil {
+		return fmt.Errorf("input file %s: %w", err.Error(), dferrors.ErrInvalidArgument)
+	}
+
+	if cfg.Recursive {
+		return fmt.Errorf("input file is conflict with recursive: %w", dferrors.ErrInvalidArgument)
+	}
+
+	if cfg.Output == StdoutOutput || cfg.OutputPipe {
+		return fmt.Errorf("input file requires the output directory: %w", dferrors.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+// checkOutputPipe checks the output is a valid file descriptor or an existing named pipe.
+func (cfg *ClientOption) checkOutputPipe() error {
+	if fd, ok := strings.CutPrefix(cfg.Output, FdOutputPrefix); ok {
+		if n, err := strconv.Atoi(fd); err != nil || n < 0 {
+			return fmt.Errorf("file descriptor[%s] is invalid", fd)
+		}
+
+		return nil
+	}
+
+	f, err := os.Stat(cfg.Output)
+	if err != nil {
+		return err
+	}
+
+	// Allow character devices for the file descriptors like /dev/stdout.
+	if f.Mode()&(os.ModeNamedPipe|os.ModeCharDevice) == 0 {
+		return fmt.Errorf("path[%s] is not a named pipe", cfg.Output)
+	}
+
+	return nil
+}
+
 // MkdirAll make directories recursive, and changes uid, gid to the latest directory.
 // For example: the path /data/x exists, uid=1, gid=1
 // when call MkdirAll("/data/x/y/z", 0755, 2, 2)
@@ -22,6 +22,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"d7y.io/dragonfly/v2/client/util"
+	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/pkg/unit"
 )
 
@@ -33,18 +34,21 @@ var dfgetConfig = ClientOption{
 	RateLimit: util.RateLimit{
 		Limit: rate.Limit(DefaultTotalDownloadLimit),
 	},
-	Md5:               "",
-	DigestMethod:      "",
-	DigestValue:       "",
-	Tag:               "",
-	Application:       "",
-	Priority:          0,
-	Cacerts:           nil,
-	Filter:            "",
-	Header:            nil,
-	DisableBackSource: false,
-	Insecure:          false,
-	ShowProgress:      false,
-	Recursive:         false,
-	RecursiveLevel:    5,
+	Md5:                 "",
+	DigestMethod:        "",
+	DigestValue:         "",
+	Tag:                 "",
+	Application:         "",
+	Priority:            0,
+	Cacerts:             nil,
+	Filter:              "",
+	Header:              nil,
+	DisableBackSource:   false,
+	Insecure:            false,
+	ShowProgress:        false,
+	Recursive:           false,
+	RecursiveLevel:      5,
+	RecursiveConcurrent: 4,
+	InputConcurrent:     4,
+	SignatureType:       types.SignatureTypeCosign,
 }
@@ -26,6 +26,7 @@ import (
 
 	"d7y.io/dragonfly/v2/client/util"
 	"d7y.io/dragonfly/v2/cmd/dependency/base"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 func TestDfgetConfig_Validate(t *testing.T) {
@@ -92,6 +93,17 @@ func TestDfgetConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "error parsing regexp: unexpected ): `(a|b))`")
 			},
 		},
+		{
+			name: "include pattern is invaild",
+			cfg: &ClientOption{
+				URL:              "http://path",
+				RecursiveInclude: []string{"[a-"},
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "pattern [a-: syntax error in pattern")
+			},
+		},
 		{
 			name: "output path is not absolute path",
 			cfg: &ClientOption{
@@ -118,6 +130,182 @@ func TestDfgetConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "output header format error: Host: : invalid Header")
 			},
 		},
+		{
+			name: "stream to stdout",
+			cfg: &ClientOption{
+				URL:       "http://path",
+				Output:    StdoutOutput,
+				Stream:    true,
+				RateLimit: util.RateLimit{Limit: 20971520},
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name: "stdout output without stream",
+			cfg: &ClientOption{
+				URL:    "http://path",
+				Output: StdoutOutput,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "output - requires stream: invalid argument")
+			},
+		},
+		{
+			name: "stream to file descriptor",
+			cfg: &ClientOption{
+				URL:        "http://path",
+				Output:     "fd://3",
+				Stream:     true,
+				OutputPipe: true,
+				RateLimit:  util.RateLimit{Limit: 20971520},
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name: "file descriptor is invalid",
+			cfg: &ClientOption{
+				URL:        "http://path",
+				Output:     "fd://foo",
+				Stream:     true,
+				OutputPipe: true,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "output file descriptor[foo] is invalid: invalid argument")
+			},
+		},
+		{
+			name: "output pipe is not a named pipe",
+			cfg: &ClientOption{
+				URL:        "http://path",
+				Output:     "/tmp",
+				Stream:     true,
+				OutputPipe: true,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "output path[/tmp] is not a named pipe: invalid argument")
+			},
+		},
+		{
+			name: "output pipe without stream",
+			cfg: &ClientOption{
+				URL:        "http://path",
+				Output:     "fd://3",
+				OutputPipe: true,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "output pipe requires stream: invalid argument")
+			},
+		},
+		{
+			name: "input file",
+			cfg: &ClientOption{
+				Output:    "/tmp/df",
+				InputFile: "testdata/config/daemon.yaml",
+				RateLimit: util.RateLimit{Limit: 20971520},
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name: "input file does not exist",
+			cfg: &ClientOption{
+				Output:    "/tmp/df",
+				InputFile: "testdata/foo",
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "input file stat testdata/foo: no such file or directory: invalid argument")
+			},
+		},
+		{
+			name: "input file with recursive",
+			cfg: &ClientOption{
+				Output:    "/tmp/df",
+				InputFile: "testdata/config/daemon.yaml",
+				Recursive: true,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "input file is conflict with recursive: invalid argument")
+			},
+		},
+		{
+			name: "progress is invalid",
+			cfg: &ClientOption{
+				URL:      "http://path",
+				Output:   "/tmp/df/test",
+				Progress: "foo",
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "progress foo: invalid argument")
+			},
+		},
+		{
+			name: "progress json with stdout output",
+			cfg: &ClientOption{
+				URL:      "http://path",
+				Output:   StdoutOutput,
+				Stream:   true,
+				Progress: ProgressJSON,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "progress json is conflict with output -: invalid argument")
+			},
+		},
+		{
+			name: "stream with recursive",
+			cfg: &ClientOption{
+				URL:       "http://path",
+				Output:    "/tmp/df/test",
+				Stream:    true,
+				Recursive: true,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "stream is conflict with recursive: invalid argument")
+			},
+		},
+		{
+			name: "signature type is invalid",
+			cfg: &ClientOption{
+				URL:           "http://path",
+				Output:        "/tmp/df/test",
+				Signature:     "foo",
+				SignatureType: "bar",
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "signature type bar: invalid argument")
+			},
+		},
+		{
+			name: "signature with stream",
+			cfg: &ClientOption{
+				URL:           "http://path",
+				Output:        "/tmp/df/test",
+				Stream:        true,
+				Signature:     "foo",
+				SignatureType: types.SignatureTypeGPG,
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "signature is conflict with stream, recursive and input file: invalid argument")
+			},
+		},
 		{
 			name: "rate limit is invalid",
 			cfg: &ClientOption{
@@ -178,6 +366,33 @@ func TestDfgetConfig_Convert(t *testing.T) {
 				assert.Equal(false, cfg.ShowProgress)
 			},
 		},
+		{
+			name: "Output is stdout",
+			cfg: &ClientOption{
+				URL:    "http://path/to/file",
+				Output: StdoutOutput,
+				Stream: true,
+			},
+			expect: func(t *testing.T, cfg *ClientOption, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(StdoutOutput, cfg.Output)
+			},
+		},
+		{
+			name: "Output is file descriptor",
+			cfg: &ClientOption{
+				URL:        "http://path/to/file",
+				Output:     "fd://3",
+				OutputPipe: true,
+			},
+			expect: func(t *testing.T, cfg *ClientOption, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal("fd://3", cfg.Output)
+				assert.True(cfg.Stream)
+			},
+		},
 		{
 			name: "URL is invaild",
 			cfg: &ClientOption{
@@ -27,7 +27,10 @@ import (
 
 	managerv1 "d7y.io/api/v2/pkg/apis/manager/v1"
 
+	"d7y.io/dragonfly/v2/pkg/featureflag"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 type SourceType string
@@ -61,9 +64,30 @@ type Dynconfig interface {
 	// Get the dynamic object storage config.
 	GetObjectStorage() (*managerv1.ObjectStorage, error)
 
+	// Get the dynamic feature flags rolled out to the peer.
+	GetFeatureFlags() (featureflag.Flags, error)
+
+	// Get the dynamic download policies of the applications.
+	GetApplicationPolicies() (map[string]rpc.ApplicationPolicy, error)
+
+	// Get the dynamic digest algorithm of the pieces downloaded from source.
+	GetPieceDigestAlgorithm() (string, error)
+
+	// Get the dynamic encryption mode of the pieces transferred between peers.
+	GetPieceEncryption() (string, error)
+
+	// Get the dynamic bandwidth policies of the traffic classes.
+	GetBandwidthPolicies() ([]types.BandwidthPolicy, error)
+
+	// Get the dynamic policy of the origin urls.
+	GetURLPolicy() (types.URLPolicy, error)
+
 	// Get the dynamic config.
 	Get() (*DynconfigData, error)
 
+	// GetRequestTimeout returns the default timeout of unary request without deadline.
+	GetRequestTimeout(method string) (time.Duration, bool)
+
 	// Refresh refreshes dynconfig in cache.
 	Refresh() error
 
@@ -32,7 +32,10 @@ import (
 	managerv1 "d7y.io/api/v2/pkg/apis/manager/v1"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	healthclient "d7y.io/dragonfly/v2/pkg/rpc/health/client"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 var (
@@ -115,11 +118,49 @@ func (d *dynconfigLocal) GetObjectStorage() (*managerv1.ObjectStorage, error) {
 	return nil, ErrUnimplemented
 }
 
+// Get the dynamic feature flags from local.
+func (d *dynconfigLocal) GetFeatureFlags() (featureflag.Flags, error) {
+	return nil, ErrUnimplemented
+}
+
+// Get the dynamic download policies of the applications from local.
+func (d *dynconfigLocal) GetApplicationPolicies() (map[string]rpc.ApplicationPolicy, error) {
+	return nil, ErrUnimplemented
+}
+
+// Get the dynamic digest algorithm of the pieces from local.
+func (d *dynconfigLocal) GetPieceDigestAlgorithm() (string, error) {
+	return "", ErrUnimplemented
+}
+
+// Get the dynamic encryption mode of the pieces from local.
+func (d *dynconfigLocal) GetPieceEncryption() (string, error) {
+	return "", ErrUnimplemented
+}
+
+// Get the dynamic bandwidth policies from local.
+func (d *dynconfigLocal) GetBandwidthPolicies() ([]types.BandwidthPolicy, error) {
+	return nil, ErrUnimplemented
+}
+
+// Get the dynamic policy of the origin urls from local.
+func (d *dynconfigLocal) GetURLPolicy() (types.URLPolicy, error) {
+	return types.URLPolicy{}, ErrUnimplemented
+}
+
 // Get the dynamic config from local.
 func (d *dynconfigLocal) Get() (*DynconfigData, error) {
 	return nil, ErrUnimplemented
 }
 
+// GetRequestTimeout returns the default timeout of unary request without deadline.
+func (d *dynconfigLocal) GetRequestTimeout(method string) (time.Duration, bool) {
+	return rpc.MethodTimeouts{
+		Default: d.config.Scheduler.RequestTimeout,
+		Methods: d.config.Scheduler.MethodRequestTimeouts,
+	}.GetRequestTimeout(method)
+}
+
 // Refresh refreshes dynconfig in cache.
 func (d *dynconfigLocal) Refresh() error {
 	return nil
@@ -18,6 +18,7 @@ package config
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -36,9 +37,13 @@ import (
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	internaldynconfig "d7y.io/dragonfly/v2/internal/dynconfig"
 	"d7y.io/dragonfly/v2/manager/searcher"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
 	"d7y.io/dragonfly/v2/pkg/net/ip"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	healthclient "d7y.io/dragonfly/v2/pkg/rpc/health/client"
 	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
+	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/version"
 )
 
@@ -189,6 +194,220 @@ func (d *dynconfigManager) GetObjectStorage() (*managerv1.ObjectStorage, error)
 	return data.ObjectStorage, nil
 }
 
+// Get the dynamic feature flags rolled out to the peer, the feature flags
+// are from the client config of the scheduler cluster.
+func (d *dynconfigManager) GetFeatureFlags() (featureflag.Flags, error) {
+	data, err := d.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scheduler := range data.Schedulers {
+		if scheduler.SchedulerCluster != nil {
+			return featureflag.Parse(scheduler.SchedulerCluster.ClientConfig)
+		}
+	}
+
+	return featureflag.Flags{}, nil
+}
+
+// Get the dynamic download policies of the applications, the policies
+// are from the client config of the scheduler cluster.
+func (d *dynconfigManager) GetApplicationPolicies() (map[string]rpc.ApplicationPolicy, error) {
+	data, err := d.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scheduler := range data.Schedulers {
+		if scheduler.SchedulerCluster == nil || len(scheduler.SchedulerCluster.ClientConfig) == 0 {
+			continue
+		}
+
+		var clientConfig struct {
+			ApplicationPolicies map[string]rpc.ApplicationPolicy `json:"application_policies"`
+		}
+		if err := json.Unmarshal(scheduler.SchedulerCluster.ClientConfig, &clientConfig); err != nil {
+			return nil, err
+		}
+
+		return clientConfig.ApplicationPolicies, nil
+	}
+
+	return nil, nil
+}
+
+// Get the dynamic digest algorithm of the pieces downloaded from source, the algorithm
+// is from the client config of the scheduler cluster, md5 is used when it is not configured.
+func (d *dynconfigManager) GetPieceDigestAlgorithm() (string, error) {
+	data, err := d.Get()
+	if err != nil {
+		return "", err
+	}
+
+	for _, scheduler := range data.Schedulers {
+		if scheduler.SchedulerCluster == nil || len(scheduler.SchedulerCluster.ClientConfig) == 0 {
+			continue
+		}
+
+		var clientConfig struct {
+			PieceDigestAlgorithm string `json:"piece_digest_algorithm"`
+		}
+		if err := json.Unmarshal(scheduler.SchedulerCluster.ClientConfig, &clientConfig); err != nil {
+			return "", err
+		}
+
+		switch clientConfig.PieceDigestAlgorithm {
+		case "":
+			return digest.AlgorithmMD5, nil
+		case digest.AlgorithmMD5, digest.AlgorithmXXH3, digest.AlgorithmBLAKE3:
+			return clientConfig.PieceDigestAlgorithm, nil
+		default:
+			return "", fmt.Errorf("unsupported piece digest algorithm: %s", clientConfig.PieceDigestAlgorithm)
+		}
+	}
+
+	return digest.AlgorithmMD5, nil
+}
+
+// Get the dynamic encryption mode of the pieces transferred between peers, the mode is
+// from the client config of the scheduler cluster, the encryption is disabled when it is not configured.
+func (d *dynconfigManager) GetPieceEncryption() (string, error) {
+	data, err := d.Get()
+	if err != nil {
+		return "", err
+	}
+
+	for _, scheduler := range data.Schedulers {
+		if scheduler.SchedulerCluster == nil || len(scheduler.SchedulerCluster.ClientConfig) == 0 {
+			continue
+		}
+
+		var clientConfig struct {
+			PieceEncryption string `json:"piece_encryption"`
+		}
+		if err := json.Unmarshal(scheduler.SchedulerCluster.ClientConfig, &clientConfig); err != nil {
+			return "", err
+		}
+
+		switch clientConfig.PieceEncryption {
+		case "":
+			return types.PieceEncryptionDisable, nil
+		case types.PieceEncryptionDisable, types.PieceEncryptionPrefer, types.PieceEncryptionRequire:
+			return clientConfig.PieceEncryption, nil
+		default:
+			return "", fmt.Errorf("unsupported piece encryption: %s", clientConfig.PieceEncryption)
+		}
+	}
+
+	return types.PieceEncryptionDisable, nil
+}
+
+// Get the dynamic bandwidth policies of the traffic classes, the policies
+// are from the client config of the scheduler cluster.
+func (d *dynconfigManager) GetBandwidthPolicies() ([]types.BandwidthPolicy, error) {
+	data, err := d.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scheduler := range data.Schedulers {
+		if scheduler.SchedulerCluster == nil || len(scheduler.SchedulerCluster.ClientConfig) == 0 {
+			continue
+		}
+
+		var clientConfig struct {
+			BandwidthPolicies []types.BandwidthPolicy `json:"bandwidth_policies"`
+		}
+		if err := json.Unmarshal(scheduler.SchedulerCluster.ClientConfig, &clientConfig); err != nil {
+			return nil, err
+		}
+
+		return clientConfig.BandwidthPolicies, nil
+	}
+
+	return nil, nil
+}
+
+// Get the dynamic policy of the origin urls, the policy is from the client config
+// of the scheduler cluster, all urls are allowed when it is not configured.
+func (d *dynconfigManager) GetURLPolicy() (types.URLPolicy, error) {
+	data, err := d.Get()
+	if err != nil {
+		return types.URLPolicy{}, err
+	}
+
+	for _, scheduler := range data.Schedulers {
+		if scheduler.SchedulerCluster == nil || len(scheduler.SchedulerCluster.ClientConfig) == 0 {
+			continue
+		}
+
+		var clientConfig struct {
+			URLPolicy types.URLPolicy `json:"url_policy"`
+		}
+		if err := json.Unmarshal(scheduler.SchedulerCluster.ClientConfig, &clientConfig); err != nil {
+			return types.URLPolicy{}, err
+		}
+
+		return clientConfig.URLPolicy, nil
+	}
+
+	return types.URLPolicy{}, nil
+}
+
+// GetRequestTimeout returns the default timeout of unary request without deadline, the timeouts
+// are from the client config of the scheduler cluster and fall back to the static config.
+func (d *dynconfigManager) GetRequestTimeout(method string) (time.Duration, bool) {
+	timeouts := rpc.MethodTimeouts{
+		Default: d.config.Scheduler.RequestTimeout,
+		Methods: d.config.Scheduler.MethodRequestTimeouts,
+	}
+
+	data, err := d.Get()
+	if err != nil {
+		return timeouts.GetRequestTimeout(method)
+	}
+
+	for _, scheduler := range data.Schedulers {
+		if scheduler.SchedulerCluster == nil || len(scheduler.SchedulerCluster.ClientConfig) == 0 {
+			continue
+		}
+
+		var clientConfig struct {
+			RequestTimeout        string            `json:"request_timeout"`
+			MethodRequestTimeouts map[string]string `json:"method_request_timeouts"`
+		}
+		if err := json.Unmarshal(scheduler.SchedulerCluster.ClientConfig, &clientConfig); err != nil {
+			logger.Errorf("unmarshal client config failed: %s", err.Error())
+			break
+		}
+
+		if clientConfig.RequestTimeout != "" {
+			timeout, err := time.ParseDuration(clientConfig.RequestTimeout)
+			if err != nil {
+				logger.Errorf("parse request timeout failed: %s", err.Error())
+				break
+			}
+
+			timeouts.Default = timeout
+		}
+
+		if timeout, ok := clientConfig.MethodRequestTimeouts[method]; ok {
+			timeout, err := time.ParseDuration(timeout)
+			if err != nil {
+				logger.Errorf("parse request timeout of %s failed: %s", method, err.Error())
+				break
+			}
+
+			return timeout, timeout > 0
+		}
+
+		break
+	}
+
+	return timeouts.GetRequestTimeout(method)
+}
+
 // Refresh refreshes dynconfig in cache.
 func (d *dynconfigManager) Refresh() error {
 	if err := d.Dynconfig.Refresh(); err != nil {
@@ -35,7 +35,10 @@ import (
 
 	managerv1 "d7y.io/api/v2/pkg/apis/manager/v1"
 
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	"d7y.io/dragonfly/v2/pkg/rpc/manager/client/mocks"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 func TestDynconfigManager_GetResolveSchedulerAddrs(t *testing.T) {
@@ -1346,3 +1349,966 @@ func TestDynconfigManager_GetObjectStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestDynconfigManager_GetFeatureFlags(t *testing.T) {
+	mockCacheDir := t.TempDir()
+	mockCachePath := filepath.Join(mockCacheDir, cacheFileName)
+	tests := []struct {
+		name           string
+		config         *DaemonOption
+		data           *DynconfigData
+		cleanFileCache func(t *testing.T)
+		mock           func(m *mocks.MockV1MockRecorder, data *DynconfigData)
+		expect         func(t *testing.T, dynconfig Dynconfig, data *DynconfigData)
+	}{
+		{
+			name: "get feature flags",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"load_limit":10,"feature_flags":["foo"]}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				flags, err := dynconfig.GetFeatureFlags()
+				assert.NoError(err)
+				assert.True(flags.Enabled("foo"))
+				assert.False(flags.Enabled("bar"))
+			},
+		},
+		{
+			name: "get feature flags without scheduler cluster",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				flags, err := dynconfig.GetFeatureFlags()
+				assert.NoError(err)
+				assert.Empty(flags)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockManagerClient := mocks.NewMockV1(ctl)
+			tc.mock(mockManagerClient.EXPECT(), tc.data)
+			dynconfig, err := NewDynconfig(
+				ManagerSourceType, tc.config,
+				WithCacheDir(mockCacheDir),
+				WithManagerClient(mockManagerClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, dynconfig, tc.data)
+			tc.cleanFileCache(t)
+		})
+	}
+}
+
+func TestDynconfigManager_GetApplicationPolicies(t *testing.T) {
+	mockCacheDir := t.TempDir()
+	mockCachePath := filepath.Join(mockCacheDir, cacheFileName)
+	tests := []struct {
+		name           string
+		config         *DaemonOption
+		data           *DynconfigData
+		cleanFileCache func(t *testing.T)
+		mock           func(m *mocks.MockV1MockRecorder, data *DynconfigData)
+		expect         func(t *testing.T, dynconfig Dynconfig, data *DynconfigData)
+	}{
+		{
+			name: "get application policies",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"load_limit":10,"application_policies":{"foo":{"rate_limit":1024,"allowed_url_regex":"^https://example.com/"}}}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				policies, err := dynconfig.GetApplicationPolicies()
+				assert.NoError(err)
+				assert.Equal(map[string]rpc.ApplicationPolicy{
+					"foo": {RateLimit: 1024, AllowedURLRegex: "^https://example.com/"},
+				}, policies)
+			},
+		},
+		{
+			name: "get application policies without scheduler cluster",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				policies, err := dynconfig.GetApplicationPolicies()
+				assert.NoError(err)
+				assert.Empty(policies)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockManagerClient := mocks.NewMockV1(ctl)
+			tc.mock(mockManagerClient.EXPECT(), tc.data)
+			dynconfig, err := NewDynconfig(
+				ManagerSourceType, tc.config,
+				WithCacheDir(mockCacheDir),
+				WithManagerClient(mockManagerClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, dynconfig, tc.data)
+			tc.cleanFileCache(t)
+		})
+	}
+}
+
+func TestDynconfigManager_GetPieceDigestAlgorithm(t *testing.T) {
+	mockCacheDir := t.TempDir()
+	mockCachePath := filepath.Join(mockCacheDir, cacheFileName)
+	tests := []struct {
+		name           string
+		config         *DaemonOption
+		data           *DynconfigData
+		cleanFileCache func(t *testing.T)
+		mock           func(m *mocks.MockV1MockRecorder, data *DynconfigData)
+		expect         func(t *testing.T, dynconfig Dynconfig, data *DynconfigData)
+	}{
+		{
+			name: "get piece digest algorithm",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"load_limit":10,"piece_digest_algorithm":"blake3"}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				algorithm, err := dynconfig.GetPieceDigestAlgorithm()
+				assert.NoError(err)
+				assert.Equal(digest.AlgorithmBLAKE3, algorithm)
+			},
+		},
+		{
+			name: "get unsupported piece digest algorithm",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"piece_digest_algorithm":"foo"}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				_, err := dynconfig.GetPieceDigestAlgorithm()
+				assert.EqualError(err, "unsupported piece digest algorithm: foo")
+			},
+		},
+		{
+			name: "get piece digest algorithm without scheduler cluster",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				algorithm, err := dynconfig.GetPieceDigestAlgorithm()
+				assert.NoError(err)
+				assert.Equal(digest.AlgorithmMD5, algorithm)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockManagerClient := mocks.NewMockV1(ctl)
+			tc.mock(mockManagerClient.EXPECT(), tc.data)
+			dynconfig, err := NewDynconfig(
+				ManagerSourceType, tc.config,
+				WithCacheDir(mockCacheDir),
+				WithManagerClient(mockManagerClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, dynconfig, tc.data)
+			tc.cleanFileCache(t)
+		})
+	}
+}
+
+func TestDynconfigManager_GetPieceEncryption(t *testing.T) {
+	mockCacheDir := t.TempDir()
+	mockCachePath := filepath.Join(mockCacheDir, cacheFileName)
+	tests := []struct {
+		name           string
+		config         *DaemonOption
+		data           *DynconfigData
+		cleanFileCache func(t *testing.T)
+		mock           func(m *mocks.MockV1MockRecorder, data *DynconfigData)
+		expect         func(t *testing.T, dynconfig Dynconfig, data *DynconfigData)
+	}{
+		{
+			name: "get piece encryption",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"load_limit":10,"piece_encryption":"require"}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				encryption, err := dynconfig.GetPieceEncryption()
+				assert.NoError(err)
+				assert.Equal(types.PieceEncryptionRequire, encryption)
+			},
+		},
+		{
+			name: "get unsupported piece encryption",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"piece_encryption":"foo"}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				_, err := dynconfig.GetPieceEncryption()
+				assert.EqualError(err, "unsupported piece encryption: foo")
+			},
+		},
+		{
+			name: "get piece encryption without scheduler cluster",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				encryption, err := dynconfig.GetPieceEncryption()
+				assert.NoError(err)
+				assert.Equal(types.PieceEncryptionDisable, encryption)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockManagerClient := mocks.NewMockV1(ctl)
+			tc.mock(mockManagerClient.EXPECT(), tc.data)
+			dynconfig, err := NewDynconfig(
+				ManagerSourceType, tc.config,
+				WithCacheDir(mockCacheDir),
+				WithManagerClient(mockManagerClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, dynconfig, tc.data)
+			tc.cleanFileCache(t)
+		})
+	}
+}
+
+func TestDynconfigManager_GetBandwidthPolicies(t *testing.T) {
+	mockCacheDir := t.TempDir()
+	mockCachePath := filepath.Join(mockCacheDir, cacheFileName)
+	tests := []struct {
+		name           string
+		config         *DaemonOption
+		data           *DynconfigData
+		cleanFileCache func(t *testing.T)
+		mock           func(m *mocks.MockV1MockRecorder, data *DynconfigData)
+		expect         func(t *testing.T, dynconfig Dynconfig, data *DynconfigData)
+	}{
+		{
+			name: "get bandwidth policies",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"bandwidth_policies":[{"traffic_class":"upload","rate_limit":1024,"start":"09:00","end":"18:00","weekdays":["Mon"]}]}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				policies, err := dynconfig.GetBandwidthPolicies()
+				assert.NoError(err)
+				assert.Equal([]types.BandwidthPolicy{
+					{
+						TrafficClass: types.TrafficClassUpload,
+						RateLimit:    1024,
+						Start:        "09:00",
+						End:          "18:00",
+						Weekdays:     []string{"Mon"},
+					},
+				}, policies)
+			},
+		},
+		{
+			name: "get bandwidth policies without scheduler cluster",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				policies, err := dynconfig.GetBandwidthPolicies()
+				assert.NoError(err)
+				assert.Empty(policies)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockManagerClient := mocks.NewMockV1(ctl)
+			tc.mock(mockManagerClient.EXPECT(), tc.data)
+			dynconfig, err := NewDynconfig(
+				ManagerSourceType, tc.config,
+				WithCacheDir(mockCacheDir),
+				WithManagerClient(mockManagerClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, dynconfig, tc.data)
+			tc.cleanFileCache(t)
+		})
+	}
+}
+
+func TestDynconfigManager_GetURLPolicy(t *testing.T) {
+	mockCacheDir := t.TempDir()
+	mockCachePath := filepath.Join(mockCacheDir, cacheFileName)
+	tests := []struct {
+		name           string
+		config         *DaemonOption
+		data           *DynconfigData
+		cleanFileCache func(t *testing.T)
+		mock           func(m *mocks.MockV1MockRecorder, data *DynconfigData)
+		expect         func(t *testing.T, dynconfig Dynconfig, data *DynconfigData)
+	}{
+		{
+			name: "get url policy",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"url_policy":{"allow":["^https://example.com/"],"deny":["\\.exe$"]}}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				policy, err := dynconfig.GetURLPolicy()
+				assert.NoError(err)
+				assert.Equal(types.URLPolicy{
+					Allow: []string{"^https://example.com/"},
+					Deny:  []string{"\\.exe$"},
+				}, policy)
+			},
+		},
+		{
+			name: "get url policy without scheduler cluster",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				policy, err := dynconfig.GetURLPolicy()
+				assert.NoError(err)
+				assert.Equal(types.URLPolicy{}, policy)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockManagerClient := mocks.NewMockV1(ctl)
+			tc.mock(mockManagerClient.EXPECT(), tc.data)
+			dynconfig, err := NewDynconfig(
+				ManagerSourceType, tc.config,
+				WithCacheDir(mockCacheDir),
+				WithManagerClient(mockManagerClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, dynconfig, tc.data)
+			tc.cleanFileCache(t)
+		})
+	}
+}
+
+func TestDynconfigManager_GetRequestTimeout(t *testing.T) {
+	mockCacheDir := t.TempDir()
+	mockCachePath := filepath.Join(mockCacheDir, cacheFileName)
+	tests := []struct {
+		name           string
+		config         *DaemonOption
+		data           *DynconfigData
+		cleanFileCache func(t *testing.T)
+		mock           func(m *mocks.MockV1MockRecorder, data *DynconfigData)
+		expect         func(t *testing.T, dynconfig Dynconfig, data *DynconfigData)
+	}{
+		{
+			name: "get request timeout",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+					RequestTimeout: time.Minute,
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"request_timeout":"10s","method_request_timeouts":{"/foo":"1s"}}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				timeout, ok := dynconfig.GetRequestTimeout("/foo")
+				assert.True(ok)
+				assert.Equal(time.Second, timeout)
+
+				timeout, ok = dynconfig.GetRequestTimeout("/bar")
+				assert.True(ok)
+				assert.Equal(10*time.Second, timeout)
+			},
+		},
+		{
+			name: "get request timeout after refreshing",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+					RequestTimeout: time.Minute,
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"request_timeout":"10s"}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				gomock.InOrder(
+					m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+						Schedulers: data.Schedulers,
+					}, nil).Times(1),
+					m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+						Schedulers: []*managerv1.Scheduler{
+							{
+								Hostname: "foo",
+								SchedulerCluster: &managerv1.SchedulerCluster{
+									ClientConfig: []byte(`{"request_timeout":"20s"}`),
+								},
+							},
+						},
+					}, nil).Times(1),
+				)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				timeout, ok := dynconfig.GetRequestTimeout("/foo")
+				assert.True(ok)
+				assert.Equal(10*time.Second, timeout)
+
+				assert.NoError(dynconfig.Refresh())
+				timeout, ok = dynconfig.GetRequestTimeout("/foo")
+				assert.True(ok)
+				assert.Equal(20*time.Second, timeout)
+			},
+		},
+		{
+			name: "get request timeout without scheduler cluster",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+					RequestTimeout: time.Minute,
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				timeout, ok := dynconfig.GetRequestTimeout("/foo")
+				assert.True(ok)
+				assert.Equal(time.Minute, timeout)
+			},
+		},
+		{
+			name: "get request timeout with invalid client config",
+			config: &DaemonOption{
+				Scheduler: SchedulerOption{
+					Manager: ManagerOption{
+						RefreshInterval: 10 * time.Second,
+					},
+					RequestTimeout: time.Minute,
+				},
+				Host: HostOption{
+					Hostname: "foo",
+				},
+			},
+			data: &DynconfigData{
+				Schedulers: []*managerv1.Scheduler{
+					{
+						Hostname: "foo",
+						SchedulerCluster: &managerv1.SchedulerCluster{
+							ClientConfig: []byte(`{"request_timeout":"foo"}`),
+						},
+					},
+				},
+			},
+			cleanFileCache: func(t *testing.T) {
+				if err := os.Remove(mockCachePath); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mock: func(m *mocks.MockV1MockRecorder, data *DynconfigData) {
+				m.ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv1.ListSchedulersResponse{
+					Schedulers: data.Schedulers,
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, dynconfig Dynconfig, data *DynconfigData) {
+				assert := assert.New(t)
+				timeout, ok := dynconfig.GetRequestTimeout("/foo")
+				assert.True(ok)
+				assert.Equal(time.Minute, timeout)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockManagerClient := mocks.NewMockV1(ctl)
+			tc.mock(mockManagerClient.EXPECT(), tc.data)
+			dynconfig, err := NewDynconfig(
+				ManagerSourceType, tc.config,
+				WithCacheDir(mockCacheDir),
+				WithManagerClient(mockManagerClient),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, dynconfig, tc.data)
+			tc.cleanFileCache(t)
+		})
+	}
+}
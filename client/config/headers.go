@@ -29,6 +29,8 @@ const (
 	HeaderDragonflyPriority = "X-Dragonfly-Priority"
 	// HeaderDragonflyRegistry is used for dynamic registry mirrors.
 	HeaderDragonflyRegistry = "X-Dragonfly-Registry"
+	// HeaderDragonflyRegistryUpstream is the upstream of registry mirror which served the request.
+	HeaderDragonflyRegistryUpstream = "X-Dragonfly-Registry-Upstream"
 	// HeaderDragonflyObjectMetaDigest is used for digest of object storage.
 	HeaderDragonflyObjectMetaDigest = "X-Dragonfly-Object-Meta-Digest"
 	// HeaderDragonflyObjectMetaLastModifiedTime is used for last modified time of object storage.
@@ -37,4 +39,8 @@ const (
 	HeaderDragonflyObjectMetaStorageClass = "X-Dragonfly-Object-Meta-Storage-Class"
 	// HeaderDragonflyObjectOperation is used for object storage operation.
 	HeaderDragonflyObjectOperation = "X-Dragonfly-Object-Operation"
+	// HeaderDragonflySignatureType is the signature type of the proxied artifact, cosign or gpg.
+	HeaderDragonflySignatureType = "X-Dragonfly-Signature-Type"
+	// HeaderDragonflySignature is the url of the signature or the inline signature of the proxied artifact.
+	HeaderDragonflySignature = "X-Dragonfly-Signature"
 )
@@ -6,9 +6,13 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	manager "d7y.io/api/v2/pkg/apis/manager/v1"
 	config "d7y.io/dragonfly/v2/client/config"
+	featureflag "d7y.io/dragonfly/v2/pkg/featureflag"
+	rpc "d7y.io/dragonfly/v2/pkg/rpc"
+	types "d7y.io/dragonfly/v2/pkg/types"
 	gomock "github.com/golang/mock/gomock"
 	resolver "google.golang.org/grpc/resolver"
 )
@@ -63,6 +67,51 @@ func (mr *MockDynconfigMockRecorder) Get() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDynconfig)(nil).Get))
 }
 
+// GetApplicationPolicies mocks base method.
+func (m *MockDynconfig) GetApplicationPolicies() (map[string]rpc.ApplicationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicationPolicies")
+	ret0, _ := ret[0].(map[string]rpc.ApplicationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicationPolicies indicates an expected call of GetApplicationPolicies.
+func (mr *MockDynconfigMockRecorder) GetApplicationPolicies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationPolicies", reflect.TypeOf((*MockDynconfig)(nil).GetApplicationPolicies))
+}
+
+// GetBandwidthPolicies mocks base method.
+func (m *MockDynconfig) GetBandwidthPolicies() ([]types.BandwidthPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBandwidthPolicies")
+	ret0, _ := ret[0].([]types.BandwidthPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBandwidthPolicies indicates an expected call of GetBandwidthPolicies.
+func (mr *MockDynconfigMockRecorder) GetBandwidthPolicies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBandwidthPolicies", reflect.TypeOf((*MockDynconfig)(nil).GetBandwidthPolicies))
+}
+
+// GetFeatureFlags mocks base method.
+func (m *MockDynconfig) GetFeatureFlags() (featureflag.Flags, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureFlags")
+	ret0, _ := ret[0].(featureflag.Flags)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlags indicates an expected call of GetFeatureFlags.
+func (mr *MockDynconfigMockRecorder) GetFeatureFlags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlags", reflect.TypeOf((*MockDynconfig)(nil).GetFeatureFlags))
+}
+
 // GetObjectStorage mocks base method.
 func (m *MockDynconfig) GetObjectStorage() (*manager.ObjectStorage, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +127,51 @@ func (mr *MockDynconfigMockRecorder) GetObjectStorage() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectStorage", reflect.TypeOf((*MockDynconfig)(nil).GetObjectStorage))
 }
 
+// GetPieceDigestAlgorithm mocks base method.
+func (m *MockDynconfig) GetPieceDigestAlgorithm() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPieceDigestAlgorithm")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPieceDigestAlgorithm indicates an expected call of GetPieceDigestAlgorithm.
+func (mr *MockDynconfigMockRecorder) GetPieceDigestAlgorithm() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPieceDigestAlgorithm", reflect.TypeOf((*MockDynconfig)(nil).GetPieceDigestAlgorithm))
+}
+
+// GetPieceEncryption mocks base method.
+func (m *MockDynconfig) GetPieceEncryption() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPieceEncryption")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPieceEncryption indicates an expected call of GetPieceEncryption.
+func (mr *MockDynconfigMockRecorder) GetPieceEncryption() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPieceEncryption", reflect.TypeOf((*MockDynconfig)(nil).GetPieceEncryption))
+}
+
+// GetRequestTimeout mocks base method.
+func (m *MockDynconfig) GetRequestTimeout(method string) (time.Duration, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequestTimeout", method)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetRequestTimeout indicates an expected call of GetRequestTimeout.
+func (mr *MockDynconfigMockRecorder) GetRequestTimeout(method interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequestTimeout", reflect.TypeOf((*MockDynconfig)(nil).GetRequestTimeout), method)
+}
+
 // GetResolveSchedulerAddrs mocks base method.
 func (m *MockDynconfig) GetResolveSchedulerAddrs() ([]resolver.Address, error) {
 	m.ctrl.T.Helper()
@@ -122,6 +216,21 @@ func (mr *MockDynconfigMockRecorder) GetSchedulers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedulers", reflect.TypeOf((*MockDynconfig)(nil).GetSchedulers))
 }
 
+// GetURLPolicy mocks base method.
+func (m *MockDynconfig) GetURLPolicy() (types.URLPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetURLPolicy")
+	ret0, _ := ret[0].(types.URLPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetURLPolicy indicates an expected call of GetURLPolicy.
+func (mr *MockDynconfigMockRecorder) GetURLPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURLPolicy", reflect.TypeOf((*MockDynconfig)(nil).GetURLPolicy))
+}
+
 // Notify mocks base method.
 func (m *MockDynconfig) Notify() error {
 	m.ctrl.T.Helper()
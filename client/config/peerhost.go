@@ -28,8 +28,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sync"
 	"time"
 
+	"golang.org/x/time/rate"
 	"gopkg.in/yaml.v3"
 
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
@@ -69,12 +71,14 @@ type DaemonOption struct {
 	Proxy           *ProxyOption          `mapstructure:"proxy" yaml:"proxy"`
 	Upload          UploadOption          `mapstructure:"upload" yaml:"upload"`
 	ObjectStorage   ObjectStorageOption   `mapstructure:"objectStorage" yaml:"objectStorage"`
+	Registry        RegistryOption        `mapstructure:"registry" yaml:"registry"`
 	Storage         StorageOption         `mapstructure:"storage" yaml:"storage"`
 	Health          *HealthOption         `mapstructure:"health" yaml:"health"`
 	Reload          ReloadOption          `mapstructure:"reload" yaml:"reload"`
 	Network         *NetworkOption        `mapstructure:"network" yaml:"network"`
 	Announcer       AnnouncerOption       `mapstructure:"announcer" yaml:"announcer"`
 	NetworkTopology NetworkTopologyOption `mapstructure:"networkTopology" yaml:"networkTopology"`
+	Profiling       ProfilingOption       `mapstructure:"profiling" yaml:"profiling"`
 }
 
 func NewDaemonConfig() *DaemonOption {
@@ -138,6 +142,22 @@ func (p *DaemonOption) Convert() error {
 		}
 	}
 
+	if p.ObjectStorage.S3.ListenOption.TCPListen != nil && p.ObjectStorage.S3.ListenOption.TCPListen.Listen == "" {
+		if p.Network.EnableIPv6 {
+			p.ObjectStorage.S3.ListenOption.TCPListen.Listen = net.IPv6zero.String()
+		} else {
+			p.ObjectStorage.S3.ListenOption.TCPListen.Listen = net.IPv4zero.String()
+		}
+	}
+
+	if p.Registry.ListenOption.TCPListen != nil && p.Registry.ListenOption.TCPListen.Listen == "" {
+		if p.Network.EnableIPv6 {
+			p.Registry.ListenOption.TCPListen.Listen = net.IPv6zero.String()
+		} else {
+			p.Registry.ListenOption.TCPListen.Listen = net.IPv4zero.String()
+		}
+	}
+
 	if p.Proxy.ListenOption.TCPListen != nil && p.Proxy.ListenOption.TCPListen.Listen == "" {
 		if p.Network.EnableIPv6 {
 			p.Proxy.ListenOption.TCPListen.Listen = net.IPv6zero.String()
@@ -189,6 +209,20 @@ func (p *DaemonOption) Validate() error {
 		if p.ObjectStorage.MaxReplicas <= 0 {
 			return errors.New("max replicas must be greater than 0")
 		}
+
+		if p.ObjectStorage.S3.Enable && (p.ObjectStorage.S3.AccessKey == "" || p.ObjectStorage.S3.SecretKey == "") {
+			return errors.New("objectStorage s3 requires parameter accessKey and secretKey")
+		}
+	}
+
+	if p.Registry.Enable {
+		if !p.Scheduler.Manager.SeedPeer.Enable {
+			return errors.New("registry requires seed peer")
+		}
+
+		if p.Registry.Remote == nil || p.Registry.Remote.URL == nil {
+			return errors.New("registry requires parameter url")
+		}
 	}
 
 	if p.Reload.Interval.Duration > 0 && p.Reload.Interval.Duration < time.Second {
@@ -215,6 +249,14 @@ func (p *DaemonOption) Validate() error {
 		if p.Security.CertSpec.ValidityPeriod <= 0 {
 			return errors.New("certSpec requires parameter validityPeriod")
 		}
+	} else if p.Security.CertFile != "" {
+		if p.Security.KeyFile == "" {
+			return errors.New("security requires parameter keyFile")
+		}
+
+		if p.Security.CAFile == "" {
+			return errors.New("security requires parameter caFile")
+		}
 	}
 
 	if p.NetworkTopology.Enable {
@@ -223,6 +265,176 @@ func (p *DaemonOption) Validate() error {
 		}
 	}
 
+	if p.Profiling.Enable {
+		if p.Profiling.Addr == "" {
+			return errors.New("profiling requires parameter addr")
+		}
+
+		if p.Profiling.Interval <= 0 {
+			return errors.New("profiling requires parameter interval")
+		}
+	}
+
+	if p.Upload.Adaptive.Enable {
+		if p.Upload.Adaptive.Interval <= 0 {
+			return errors.New("adaptive requires parameter interval")
+		}
+
+		if p.Upload.Adaptive.CPUThreshold <= 0 || p.Upload.Adaptive.CPUThreshold > 100 {
+			return errors.New("adaptive cpuThreshold must be in (0, 100]")
+		}
+
+		if p.Upload.Adaptive.NetworkThreshold <= 0 || p.Upload.Adaptive.NetworkThreshold > 100 {
+			return errors.New("adaptive networkThreshold must be in (0, 100]")
+		}
+	}
+
+	if p.Upload.Reservation.Enable {
+		if p.Upload.Reservation.ConcurrentLimit <= 0 {
+			return errors.New("reservation requires parameter concurrentLimit")
+		}
+
+		if p.Upload.Reservation.TTL <= 0 {
+			return errors.New("reservation requires parameter ttl")
+		}
+
+		if p.Upload.Reservation.HMACSecret == "" {
+			return errors.New("reservation requires parameter hmacSecret")
+		}
+	}
+
+	if p.Download.Integrity.Enable {
+		if p.Download.Integrity.PrivateKey == "" && len(p.Download.Integrity.PublicKeys) == 0 {
+			return errors.New("integrity requires parameter privateKey or publicKeys")
+		}
+
+		if !p.Download.CalculateDigest {
+			return errors.New("integrity requires calculateDigest")
+		}
+
+		// The pieces are digested by md5 without the algorithm configured by the scheduler cluster,
+		// which can not resist the forged pieces.
+		if !p.Scheduler.Manager.Enable {
+			return errors.New("integrity requires scheduler manager to configure piece digest algorithm sha256, sha512 or blake3")
+		}
+	}
+
+	if p.Storage.Quota.Enable {
+		if p.Storage.Quota.HighWatermark <= 0 || p.Storage.Quota.HighWatermark > 100 {
+			return errors.New("quota requires parameter highWatermark")
+		}
+
+		if p.Storage.Quota.LowWatermark <= 0 || p.Storage.Quota.LowWatermark >= p.Storage.Quota.HighWatermark {
+			return errors.New("quota requires parameter lowWatermark")
+		}
+	}
+
+	if p.Storage.Tier.Enable {
+		if p.Storage.Tier.MemorySize <= 0 && p.Storage.Tier.SSDPath == "" {
+			return errors.New("tier requires parameter memorySize or ssdPath")
+		}
+
+		if p.Storage.Tier.SSDPath != "" && p.Storage.Tier.SSDSize <= 0 {
+			return errors.New("tier requires parameter ssdSize")
+		}
+	}
+
+	if p.Storage.IOUring.Enable && p.Storage.IOUring.Entries == 0 {
+		return errors.New("ioUring requires parameter entries")
+	}
+
+	if p.Storage.Resume.Enable && p.Storage.Resume.PersistInterval.Duration <= 0 {
+		return errors.New("resume requires parameter persistInterval")
+	}
+
+	if p.Storage.Encryption.Enable {
+		if p.Storage.Encryption.KeyFile == "" && p.Storage.Encryption.KeyCommand == "" {
+			return errors.New("encryption requires parameter keyFile or keyCommand")
+		}
+
+		if p.Storage.StoreStrategy == AdvanceLocalTaskStoreStrategy {
+			return errors.New("encryption is not compatible with advance store strategy")
+		}
+
+		if p.Storage.Dedup.Enable {
+			return errors.New("encryption is not compatible with dedup")
+		}
+
+		if p.Storage.Tier.Enable && p.Storage.Tier.SSDPath != "" {
+			return errors.New("encryption is not compatible with tier ssdPath")
+		}
+	}
+
+	if p.Storage.Preallocate.Enable && !p.Scheduler.Manager.SeedPeer.Enable {
+		return errors.New("preallocate requires seed peer")
+	}
+
+	if p.Storage.Overflow.Enable {
+		if !p.Scheduler.Manager.SeedPeer.Enable {
+			return errors.New("overflow requires seed peer")
+		}
+
+		if p.Storage.Overflow.Name == "" {
+			return errors.New("overflow requires parameter name")
+		}
+
+		if p.Storage.Overflow.Bucket == "" {
+			return errors.New("overflow requires parameter bucket")
+		}
+	}
+
+	if p.Proxy != nil && p.Proxy.RangeCoalescing.Enable && p.Proxy.RangeCoalescing.BlockSize <= 0 {
+		return errors.New("rangeCoalescing requires parameter blockSize")
+	}
+
+	if p.Proxy != nil && p.Proxy.SequentialPrefetch.Enable && p.Proxy.SequentialPrefetch.Window <= 0 {
+		return errors.New("sequentialPrefetch requires parameter window")
+	}
+
+	if p.Proxy != nil && p.Proxy.Transparent != nil {
+		if p.Proxy.Transparent.PortRange.Start == 0 {
+			return errors.New("transparent requires parameter port")
+		}
+
+		switch p.Proxy.Transparent.Mode {
+		case "", TransparentModeRedirect, TransparentModeTProxy:
+		default:
+			return fmt.Errorf("transparent mode %s is invalid", p.Proxy.Transparent.Mode)
+		}
+	}
+
+	if p.Proxy != nil && p.Proxy.RegistryMirror != nil {
+		for _, fallback := range p.Proxy.RegistryMirror.Fallbacks {
+			if fallback.Remote == nil || fallback.Remote.URL == nil {
+				return errors.New("registryMirror fallbacks requires parameter url")
+			}
+		}
+	}
+
+	if p.Proxy != nil {
+		for _, rule := range p.Proxy.ProxyRules {
+			switch rule.Policy {
+			case "", ProxyPolicyP2P, ProxyPolicyDirect, ProxyPolicyCacheOnly, ProxyPolicyBypass:
+			default:
+				return fmt.Errorf("proxy rule %s policy %s is invalid", rule.Regx, rule.Policy)
+			}
+		}
+	}
+
+	if p.Proxy != nil && p.Proxy.Socks5 != nil && p.Proxy.Socks5.PortRange.Start == 0 {
+		return errors.New("socks5 requires parameter port")
+	}
+
+	if p.Proxy != nil && p.Proxy.HijackHTTPS != nil {
+		if p.Proxy.HijackHTTPS.LeafCertTTL < 0 {
+			return errors.New("hijackHTTPS requires parameter leafCertTTL greater than or equal to 0")
+		}
+
+		if p.Proxy.HijackHTTPS.CertCacheSize < 0 {
+			return errors.New("hijackHTTPS requires parameter certCacheSize greater than or equal to 0")
+		}
+	}
+
 	return nil
 }
 
@@ -230,6 +442,13 @@ type GlobalSecurityOption struct {
 	// AutoIssueCert indicates to issue client certificates for all grpc call
 	// if AutoIssueCert is false, any other option in Security will be ignored
 	AutoIssueCert bool `mapstructure:"autoIssueCert" yaml:"autoIssueCert"`
+	// CertFile is the certificate file for all grpc tls handshake when AutoIssueCert is false,
+	// the certificate files are reloaded when they are rotated.
+	CertFile string `mapstructure:"certFile" yaml:"certFile"`
+	// KeyFile is the private key file of CertFile.
+	KeyFile string `mapstructure:"keyFile" yaml:"keyFile"`
+	// CAFile is the CA bundle file verifying the peers of CertFile.
+	CAFile string `mapstructure:"caFile" yaml:"caFile"`
 	// CACert is the root CA certificate for all grpc tls handshake, it can be path or PEM format string
 	CACert types.PEMContent `mapstructure:"caCert" yaml:"caCert"`
 	// TLSVerify indicates to verify client certificates.
@@ -261,6 +480,13 @@ type SchedulerOption struct {
 	ScheduleTimeout util.Duration `mapstructure:"scheduleTimeout" yaml:"scheduleTimeout"`
 	// DisableAutoBackSource indicates not back source normally, only scheduler says back source.
 	DisableAutoBackSource bool `mapstructure:"disableAutoBackSource" yaml:"disableAutoBackSource"`
+	// RequestTimeout is the default timeout of unary requests without deadline.
+	RequestTimeout time.Duration `mapstructure:"requestTimeout" yaml:"requestTimeout"`
+	// MethodRequestTimeouts is the default timeouts of unary requests without deadline keyed by full method,
+	// it overrides RequestTimeout.
+	MethodRequestTimeouts map[string]time.Duration `mapstructure:"methodRequestTimeouts" yaml:"methodRequestTimeouts"`
+	// Auth is the credentials of scheduler.
+	Auth AuthOption `mapstructure:"auth" yaml:"auth"`
 }
 
 type ManagerOption struct {
@@ -272,6 +498,15 @@ type ManagerOption struct {
 	RefreshInterval time.Duration `mapstructure:"refreshInterval" yaml:"refreshInterval"`
 	// SeedPeer configuration.
 	SeedPeer SeedPeerOption `mapstructure:"seedPeer" yaml:"seedPeer"`
+	// Auth is the credentials of manager.
+	Auth AuthOption `mapstructure:"auth" yaml:"auth"`
+}
+
+type AuthOption struct {
+	// Token is the bearer token, empty disables token authentication.
+	Token string `mapstructure:"token" yaml:"token"`
+	// HMACSecret is the secret of hmac signature, empty disables hmac authentication.
+	HMACSecret string `mapstructure:"hmacSecret" yaml:"hmacSecret"`
 }
 
 type SeedPeerOption struct {
@@ -299,6 +534,8 @@ type HostOption struct {
 	Hostname string `mapstructure:"hostname" yaml:"hostname"`
 	// The ip report to scheduler, normal same with listen ip
 	AdvertiseIP net.IP `mapstructure:"advertiseIP" yaml:"advertiseIP"`
+	// Topology is the labels of failure domains, scheduler prefers parents in the same failure domain
+	Topology types.Topology `mapstructure:"topology" yaml:"topology"`
 }
 
 type DownloadOption struct {
@@ -317,6 +554,20 @@ type DownloadOption struct {
 	Concurrent           *ConcurrentOption `mapstructure:"concurrent" yaml:"concurrent"`
 	SyncPieceViaHTTPS    bool              `mapstructure:"syncPieceViaHTTPS" yaml:"syncPieceViaHTTPS"`
 	SplitRunningTasks    bool              `mapstructure:"splitRunningTasks" yaml:"splitRunningTasks"`
+	// PieceSelectionStrategy is the strategy of selecting pieces from the parents,
+	// supports random, sequential, rarest-first and random-window.
+	PieceSelectionStrategy string `mapstructure:"pieceSelectionStrategy" yaml:"pieceSelectionStrategy"`
+	// PeerExchange gossips piece availability with the sibling peers returned by scheduler,
+	// it can also be rolled out by the feature flag peer_exchange.
+	PeerExchange bool `mapstructure:"peerExchange" yaml:"peerExchange"`
+	// QUIC downloads pieces over quic when the parent announces it.
+	QUIC QUICOption `mapstructure:"quic" yaml:"quic"`
+	// Stream serves the streaming download api over the download unix socket.
+	Stream StreamOption `mapstructure:"stream" yaml:"stream"`
+	// Integrity verifies the pieces from the parents against the merkle tree signed by the seed peer.
+	Integrity IntegrityOption `mapstructure:"integrity" yaml:"integrity"`
+	// Signature verifies the signatures of the downloaded artifacts against the trusted keys.
+	Signature SignatureOption `mapstructure:"signature" yaml:"signature"`
 	// resource clients option
 	ResourceClients ResourceClientsOption `mapstructure:"resourceClients" yaml:"resourceClients"`
 
@@ -372,6 +623,63 @@ type ProxyOption struct {
 	DumpHTTPContent    bool              `mapstructure:"dumpHTTPContent" yaml:"dumpHTTPContent"`
 	// ExtraRegistryMirrors add more mirror for different ports
 	ExtraRegistryMirrors []*RegistryMirror `mapstructure:"extraRegistryMirrors" yaml:"extraRegistryMirrors"`
+	// RangeCoalescing coalesces the small range requests of the same url into aligned blocks
+	RangeCoalescing RangeCoalescingOption `mapstructure:"rangeCoalescing" yaml:"rangeCoalescing"`
+	// SequentialPrefetch prefetches the following ranges ahead of the sequential range reads of the same url
+	SequentialPrefetch SequentialPrefetchOption `mapstructure:"sequentialPrefetch" yaml:"sequentialPrefetch"`
+	// Transparent serves the connections redirected by iptables, the workloads do not need to configure HTTP_PROXY
+	Transparent *TransparentOption `mapstructure:"transparent" yaml:"transparent"`
+	// Socks5 serves the socks5 clients alongside the http proxy, the basic auth is used as username and password
+	Socks5 *TCPListenOption `mapstructure:"socks5" yaml:"socks5"`
+}
+
+const (
+	// TransparentModeRedirect recovers the original destination of the connections redirected by iptables REDIRECT target.
+	TransparentModeRedirect = "redirect"
+
+	// TransparentModeTProxy recovers the original destination of the connections redirected by iptables TPROXY target.
+	TransparentModeTProxy = "tproxy"
+)
+
+// TransparentOption is the option of transparent proxy, the traffic of the daemon itself must be excluded
+// from the iptables rules, like "-m owner ! --uid-owner <uid>", otherwise the connections are redirected in loop.
+type TransparentOption struct {
+	TCPListenOption `mapstructure:",squash" yaml:",inline"`
+	// Mode is the iptables target redirecting the connections, redirect or tproxy, default is redirect.
+	Mode string `mapstructure:"mode" yaml:"mode"`
+}
+
+type RangeCoalescingOption struct {
+	// Enable coalesces the small range requests of the same url into aligned blocks, every block is
+	// downloaded as a task once, and the following ranges in the block are served from local cache.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// BlockSize is the size of the aligned blocks, the range requests not smaller than it are not coalesced.
+	BlockSize unit.Bytes `mapstructure:"blockSize" yaml:"blockSize"`
+	// PrefetchBlocks is the count of the following blocks prefetched in background.
+	PrefetchBlocks int `mapstructure:"prefetchBlocks" yaml:"prefetchBlocks"`
+	// Estargz is the option of eStargz layers.
+	Estargz EstargzOption `mapstructure:"estargz" yaml:"estargz"`
+}
+
+type SequentialPrefetchOption struct {
+	// Enable detects the sequential range reads of the same url, and prefetches the following ranges ahead of
+	// the reader in background, the following ranges are predicted with the length of the last read.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Window is the size of the data prefetched ahead of the reader.
+	Window unit.Bytes `mapstructure:"window" yaml:"window"`
+	// Threshold is the count of the sequential reads to start prefetching, default is 2.
+	Threshold int `mapstructure:"threshold" yaml:"threshold"`
+	// MinContentLength is the minimum content length of the url to prefetch, the small objects are not prefetched.
+	MinContentLength unit.Bytes `mapstructure:"minContentLength" yaml:"minContentLength"`
+}
+
+type EstargzOption struct {
+	// Enable parses the TOC of eStargz layers, and aligns the range requests to the chunks in TOC
+	// instead of the blocks, every chunk is downloaded as a task once.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Prefetch downloads the prioritized files before the prefetch landmark in background once the TOC is parsed,
+	// the ranges in the prioritized files are served by one task.
+	Prefetch bool `mapstructure:"prefetch" yaml:"prefetch"`
 }
 
 func (p *ProxyOption) UnmarshalJSON(b []byte) error {
@@ -449,17 +757,20 @@ func (p *ProxyOption) UnmarshalYAML(node *yaml.Node) error {
 func (p *ProxyOption) unmarshal(unmarshal func(in []byte, out any) (err error), b []byte) error {
 	pt := struct {
 		ListenOption         `mapstructure:",squash" yaml:",inline"`
-		BasicAuth            *BasicAuth        `mapstructure:"basicAuth" yaml:"basicAuth"`
-		DefaultFilter        string            `mapstructure:"defaultFilter" yaml:"defaultFilter"`
-		DefaultTag           string            `mapstructure:"defaultTag" yaml:"defaultTag"`
-		DefaultApplication   string            `mapstructure:"defaultApplication" yaml:"defaultApplication"`
-		MaxConcurrency       int64             `mapstructure:"maxConcurrency" yaml:"maxConcurrency"`
-		RegistryMirror       *RegistryMirror   `mapstructure:"registryMirror" yaml:"registryMirror"`
-		WhiteList            []*WhiteList      `mapstructure:"whiteList" yaml:"whiteList"`
-		Proxies              []*ProxyRule      `mapstructure:"proxies" yaml:"proxies"`
-		HijackHTTPS          *HijackConfig     `mapstructure:"hijackHTTPS" yaml:"hijackHTTPS"`
-		DumpHTTPContent      bool              `mapstructure:"dumpHTTPContent" yaml:"dumpHTTPContent"`
-		ExtraRegistryMirrors []*RegistryMirror `mapstructure:"extraRegistryMirrors" yaml:"extraRegistryMirrors"`
+		BasicAuth            *BasicAuth            `mapstructure:"basicAuth" yaml:"basicAuth"`
+		DefaultFilter        string                `mapstructure:"defaultFilter" yaml:"defaultFilter"`
+		DefaultTag           string                `mapstructure:"defaultTag" yaml:"defaultTag"`
+		DefaultApplication   string                `mapstructure:"defaultApplication" yaml:"defaultApplication"`
+		MaxConcurrency       int64                 `mapstructure:"maxConcurrency" yaml:"maxConcurrency"`
+		RegistryMirror       *RegistryMirror       `mapstructure:"registryMirror" yaml:"registryMirror"`
+		WhiteList            []*WhiteList          `mapstructure:"whiteList" yaml:"whiteList"`
+		Proxies              []*ProxyRule          `mapstructure:"proxies" yaml:"proxies"`
+		HijackHTTPS          *HijackConfig         `mapstructure:"hijackHTTPS" yaml:"hijackHTTPS"`
+		DumpHTTPContent      bool                  `mapstructure:"dumpHTTPContent" yaml:"dumpHTTPContent"`
+		ExtraRegistryMirrors []*RegistryMirror     `mapstructure:"extraRegistryMirrors" yaml:"extraRegistryMirrors"`
+		RangeCoalescing      RangeCoalescingOption `mapstructure:"rangeCoalescing" yaml:"rangeCoalescing"`
+		Transparent          *TransparentOption    `mapstructure:"transparent" yaml:"transparent"`
+		Socks5               *TCPListenOption      `mapstructure:"socks5" yaml:"socks5"`
 	}{}
 
 	if err := unmarshal(b, &pt); err != nil {
@@ -477,6 +788,9 @@ func (p *ProxyOption) unmarshal(unmarshal func(in []byte, out any) (err error),
 	p.DefaultApplication = pt.DefaultApplication
 	p.BasicAuth = pt.BasicAuth
 	p.DumpHTTPContent = pt.DumpHTTPContent
+	p.RangeCoalescing = pt.RangeCoalescing
+	p.Transparent = pt.Transparent
+	p.Socks5 = pt.Socks5
 
 	return nil
 }
@@ -484,6 +798,76 @@ func (p *ProxyOption) unmarshal(unmarshal func(in []byte, out any) (err error),
 type UploadOption struct {
 	ListenOption `yaml:",inline" mapstructure:",squash"`
 	RateLimit    util.RateLimit `mapstructure:"rateLimit" yaml:"rateLimit"`
+	// QUIC serves pieces over quic on the udp port same as the upload port.
+	QUIC QUICOption `mapstructure:"quic" yaml:"quic"`
+	// Adaptive backs off the upload rate limit when the host is overloaded.
+	Adaptive AdaptiveRateLimitOption `mapstructure:"adaptive" yaml:"adaptive"`
+	// Reservation serves the upload slot reservations of scheduler.
+	Reservation UploadReservationOption `mapstructure:"reservation" yaml:"reservation"`
+}
+
+type UploadReservationOption struct {
+	// Enable serves the upload slot reservations of scheduler, the reservations are denied when
+	// the slots are occupied by the in-flight uploads and the reservations, or the upload bandwidth is saturated.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// ConcurrentLimit is the number of the upload slots.
+	ConcurrentLimit int `mapstructure:"concurrentLimit" yaml:"concurrentLimit"`
+	// TTL is the time to keep a reservation until the child starts downloading.
+	TTL time.Duration `mapstructure:"ttl" yaml:"ttl"`
+	// HMACSecret is the secret shared with scheduler to authenticate the reservations.
+	HMACSecret string `mapstructure:"hmacSecret" yaml:"hmacSecret"`
+}
+
+type AdaptiveRateLimitOption struct {
+	// Enable backs off the upload rate limit when the host load crosses the thresholds,
+	// and recovers it gradually after the host load drops.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Interval is the interval of sampling the host load.
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+	// CPUThreshold is the threshold of the cpu usage percent.
+	CPUThreshold float64 `mapstructure:"cpuThreshold" yaml:"cpuThreshold"`
+	// DiskLatencyThreshold is the threshold of the average latency of the disk io,
+	// the disk latency is not checked if it is zero.
+	DiskLatencyThreshold time.Duration `mapstructure:"diskLatencyThreshold" yaml:"diskLatencyThreshold"`
+	// NetworkThreshold is the threshold of the usage percent of the network bandwidth.
+	NetworkThreshold float64 `mapstructure:"networkThreshold" yaml:"networkThreshold"`
+	// NetworkBandwidth is the bandwidth of the network interfaces,
+	// the network saturation is not checked if it is not specified.
+	NetworkBandwidth util.RateLimit `mapstructure:"networkBandwidth" yaml:"networkBandwidth"`
+}
+
+type QUICOption struct {
+	// Enable transfers pieces over quic, it improves the throughput on lossy links,
+	// peers negotiate quic by the Alt-Svc header and fall back to tcp on failures.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+}
+
+type StreamOption struct {
+	// Enable serves the streaming download api, the verified pieces are written to the response in order
+	// as they arrive, so the caller consumes the data before the task completes.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+}
+
+type IntegrityOption struct {
+	// Enable verifies every piece downloaded from the parents against the signed merkle tree of the task,
+	// the pieces are retried with other parents until the merkle tree is signed by the seed peer.
+	// The scheduler cluster is required to digest the pieces by sha256, sha512 or blake3.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// PrivateKey is the path of the pkcs8 pem encoded ed25519 key signing the merkle trees,
+	// it is used by the seed peers downloading the tasks from the origin.
+	PrivateKey string `mapstructure:"privateKey" yaml:"privateKey"`
+	// PublicKeys are the paths of the pkix pem encoded ed25519 keys trusted to sign the merkle trees.
+	PublicKeys []string `mapstructure:"publicKeys" yaml:"publicKeys"`
+}
+
+type SignatureOption struct {
+	// CosignPublicKeys are the paths of the pem encoded cosign public keys trusted to sign the artifacts.
+	CosignPublicKeys []string `mapstructure:"cosignPublicKeys" yaml:"cosignPublicKeys"`
+	// GPGPublicKeys are the paths of the armored or binary gpg public keys trusted to sign the artifacts.
+	GPGPublicKeys []string `mapstructure:"gpgPublicKeys" yaml:"gpgPublicKeys"`
+	// AllowedURLs are the base urls which the signatures are allowed to be fetched from,
+	// e.g. https://example.com/signatures, only the inline signatures are accepted when it is empty.
+	AllowedURLs []string `mapstructure:"allowedURLs" yaml:"allowedURLs"`
 }
 
 type ObjectStorageOption struct {
@@ -495,10 +879,52 @@ type ObjectStorageOption struct {
 	Filter string `mapstructure:"filter" yaml:"filter"`
 	// MaxReplicas is the maximum number of replicas of an object cache in seed peers.
 	MaxReplicas int `mapstructure:"maxReplicas" yaml:"maxReplicas"`
+	// S3 is the S3-compatible gateway of object storage.
+	S3 S3GatewayOption `mapstructure:"s3" yaml:"s3"`
 	// ListenOption is object storage service listener.
 	ListenOption `yaml:",inline" mapstructure:",squash"`
 }
 
+type S3GatewayOption struct {
+	// Enable S3-compatible gateway, it serves GetObject, HeadObject, PutObject and ListObjects
+	// with path-style requests signed by signature version 4.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Region is the region in the credential scope of signature.
+	Region string `mapstructure:"region" yaml:"region"`
+	// AccessKey is the access key to verify the signature.
+	AccessKey string `mapstructure:"accessKey" yaml:"accessKey"`
+	// SecretKey is the secret key to verify the signature.
+	SecretKey string `mapstructure:"secretKey" yaml:"secretKey"`
+	// ListenOption is S3-compatible gateway listener.
+	ListenOption `yaml:",inline" mapstructure:",squash"`
+}
+
+// RegistryOption is the option of the registry service on seed peers, it implements
+// the pull side of OCI distribution spec, the manifests and blobs are served by P2P.
+type RegistryOption struct {
+	// Enable registry service.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Remote is the url of upstream registry.
+	Remote *URL `mapstructure:"url" yaml:"url"`
+	// Insecure indicates whether to ignore certificates errors of upstream registry.
+	Insecure bool `mapstructure:"insecure" yaml:"insecure"`
+	// Certs are the optional certificates if upstream registry uses self-signed certificates.
+	Certs *CertPool `mapstructure:"certs" yaml:"certs"`
+	// ListenOption is registry service listener.
+	ListenOption `yaml:",inline" mapstructure:",squash"`
+}
+
+// TLSConfig returns the tls.Config used to communicate with upstream registry.
+func (r *RegistryOption) TLSConfig() *tls.Config {
+	cfg := &tls.Config{
+		InsecureSkipVerify: r.Insecure,
+	}
+	if r.Certs != nil {
+		cfg.RootCAs = r.Certs.CertPool
+	}
+	return cfg
+}
+
 type ListenOption struct {
 	Security   SecurityOption    `mapstructure:"security" yaml:"security"`
 	TCPListen  *TCPListenOption  `mapstructure:"tcpListen,omitempty" yaml:"tcpListen,omitempty"`
@@ -630,6 +1056,110 @@ type StorageOption struct {
 	// Multiplex indicates reusing underlying storage for same task id
 	Multiplex     bool          `mapstructure:"multiplex" yaml:"multiplex"`
 	StoreStrategy StoreStrategy `mapstructure:"strategy" yaml:"strategy"`
+	// Quota replaces the ttl based gc with the watermark based gc, the least recently used tasks
+	// are evicted when the usage exceeds the high watermark until the usage is below the low watermark
+	Quota QuotaOption `mapstructure:"quota" yaml:"quota"`
+	// Tier caches the hot pieces in the memory and ssd tiers in front of the data path,
+	// pieces are promoted on access and the cold pieces are demoted until they fall back to the data path
+	Tier TierOption `mapstructure:"tier" yaml:"tier"`
+	// IOUring batches the piece reads and writes with io_uring on linux,
+	// the standard file io is used when io_uring is not supported by the kernel
+	IOUring IOUringOption `mapstructure:"ioUring" yaml:"ioUring"`
+	// Dedup indexes the pieces by digest, identical pieces of different tasks share the disk extents
+	// on the file systems supporting deduplication, like btrfs and xfs
+	Dedup DedupOption `mapstructure:"dedup" yaml:"dedup"`
+	// Resume persists the written pieces of the unfinished tasks, the tasks are resumed from
+	// the written pieces instead of downloading again after dfdaemon restarts
+	Resume ResumeOption `mapstructure:"resume" yaml:"resume"`
+	// Encryption encrypts the piece data in the data path with the key of the node,
+	// the data is decrypted transparently when it is served or stored to the output
+	Encryption StorageEncryptionOption `mapstructure:"encryption" yaml:"encryption"`
+	// Preallocate preallocates the task files of the seed peer with fallocate when the content length is known,
+	// the new tasks are rejected when the projected disk usage exceeds the capacity
+	Preallocate PreallocateOption `mapstructure:"preallocate" yaml:"preallocate"`
+	// Overflow writes the completed tasks of the seed peer through to the object storage bucket,
+	// the tasks evicted from the data path are restored from the bucket instead of the origin
+	Overflow OverflowOption `mapstructure:"overflow" yaml:"overflow"`
+}
+
+type QuotaOption struct {
+	// Enable indicates evicting tasks by the watermarks instead of the task expire time
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Size is the disk quota of the cached tasks, the capacity of the disk of data path is used when it is 0
+	Size unit.Bytes `mapstructure:"size" yaml:"size"`
+	// HighWatermark is the usage percent of the quota to start evicting tasks
+	HighWatermark float64 `mapstructure:"highWatermark" yaml:"highWatermark"`
+	// LowWatermark is the usage percent of the quota to stop evicting tasks
+	LowWatermark float64 `mapstructure:"lowWatermark" yaml:"lowWatermark"`
+}
+
+type TierOption struct {
+	// Enable indicates caching the hot pieces in the memory and ssd tiers
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// MemorySize is the capacity of the memory tier, the memory tier is disabled when it is 0
+	MemorySize unit.Bytes `mapstructure:"memorySize" yaml:"memorySize"`
+	// SSDPath is the directory of the ssd tier, the ssd tier is disabled when it is empty
+	SSDPath string `mapstructure:"ssdPath" yaml:"ssdPath"`
+	// SSDSize is the capacity of the ssd tier
+	SSDSize unit.Bytes `mapstructure:"ssdSize" yaml:"ssdSize"`
+}
+
+type IOUringOption struct {
+	// Enable indicates reading and writing pieces with io_uring
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Entries is the number of the submission queue entries, which is the max batch size of the requests
+	Entries uint32 `mapstructure:"entries" yaml:"entries"`
+}
+
+type DedupOption struct {
+	// Enable indicates deduplicating the identical pieces across tasks
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+}
+
+type ResumeOption struct {
+	// Enable indicates resuming the unfinished tasks after dfdaemon restarts
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// PersistInterval is the interval to persist the written pieces,
+	// the pieces written in the last interval before dfdaemon exits are downloaded again
+	PersistInterval util.Duration `mapstructure:"persistInterval" yaml:"persistInterval"`
+}
+
+type StorageEncryptionOption struct {
+	// Enable indicates encrypting the piece data at rest
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// KeyFile is the file of the hex encoded 32 bytes key of the node
+	KeyFile string `mapstructure:"keyFile" yaml:"keyFile"`
+	// KeyCommand prints the hex encoded 32 bytes key of the node, e.g. decrypting the data key with kms,
+	// it is used when keyFile is empty
+	KeyCommand string `mapstructure:"keyCommand" yaml:"keyCommand"`
+}
+
+type PreallocateOption struct {
+	// Enable indicates preallocating the task files and reserving the disk space of the tasks
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Capacity is the disk capacity of the tasks, the capacity of the disk of data path is used when it is 0
+	Capacity unit.Bytes `mapstructure:"capacity" yaml:"capacity"`
+}
+
+type OverflowOption struct {
+	// Enable indicates writing the completed tasks through to the bucket
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+	// Name is the name of the object storage service, s3, oss or obs
+	Name string `mapstructure:"name" yaml:"name"`
+	// Region is the region of the object storage service
+	Region string `mapstructure:"region" yaml:"region"`
+	// Endpoint is the endpoint of the object storage service
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint"`
+	// AccessKey is the access key id of the object storage service
+	AccessKey string `mapstructure:"accessKey" yaml:"accessKey"`
+	// SecretKey is the access key secret of the object storage service
+	SecretKey string `mapstructure:"secretKey" yaml:"secretKey"`
+	// S3ForcePathStyle indicates using the path style urls of s3
+	S3ForcePathStyle bool `mapstructure:"s3ForcePathStyle" yaml:"s3ForcePathStyle"`
+	// Bucket is the bucket storing the tasks
+	Bucket string `mapstructure:"bucket" yaml:"bucket"`
+	// Prefix is the prefix of the object keys of the tasks
+	Prefix string `mapstructure:"prefix" yaml:"prefix"`
 }
 
 type StoreStrategy string
@@ -713,6 +1243,47 @@ type RegistryMirror struct {
 
 	// Whether to use proxies to decide when to use dragonfly
 	UseProxies bool `yaml:"useProxies" mapstructure:"useProxies"`
+
+	// Fallbacks are the ordered upstream registries after the remote, like internal mirror -> regional
+	// mirror -> docker hub, the requests fail over to the next upstream when the upstream is unhealthy,
+	// returns 5xx or does not have the content
+	Fallbacks []*RegistryUpstream `yaml:"fallbacks" mapstructure:"fallbacks"`
+
+	// HealthCheck is the option of checking the health of the remote and fallbacks
+	HealthCheck RegistryHealthCheckOption `yaml:"healthCheck" mapstructure:"healthCheck"`
+}
+
+// RegistryUpstream is a fallback upstream registry of registry mirror.
+type RegistryUpstream struct {
+	// Remote url for the upstream registry
+	Remote *URL `yaml:"url" mapstructure:"url"`
+
+	// Optional certificates if the upstream uses self-signed certificates
+	Certs *CertPool `yaml:"certs" mapstructure:"certs"`
+
+	// Whether to ignore certificates errors for the upstream
+	Insecure bool `yaml:"insecure" mapstructure:"insecure"`
+}
+
+// TLSConfig returns the tls.Config used to communicate with the upstream.
+func (r *RegistryUpstream) TLSConfig() *tls.Config {
+	cfg := &tls.Config{
+		InsecureSkipVerify: r.Insecure,
+	}
+	if r.Certs != nil {
+		cfg.RootCAs = r.Certs.CertPool
+	}
+	return cfg
+}
+
+// RegistryHealthCheckOption is the option of checking the health of registry mirror upstreams,
+// the upstream is healthy when GET /v2/ does not respond 5xx.
+type RegistryHealthCheckOption struct {
+	// Interval is the interval of health checking, default is 30s
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+
+	// Timeout is the timeout of health checking, default is 5s
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
 }
 
 // TLSConfig returns the tls.Config used to communicate with the mirror.
@@ -830,14 +1401,39 @@ func certPoolFromFiles(files ...string) (*x509.CertPool, error) {
 	return roots, nil
 }
 
+const (
+	// ProxyPolicyP2P downloads the requests with dragonfly.
+	ProxyPolicyP2P = "p2p"
+
+	// ProxyPolicyDirect sends the requests to the origin directly.
+	ProxyPolicyDirect = "direct"
+
+	// ProxyPolicyCacheOnly serves the requests from the local cache only, and fails otherwise.
+	ProxyPolicyCacheOnly = "cacheOnly"
+
+	// ProxyPolicyBypass sends the requests to the origin directly without applying useHTTPS and redirect.
+	ProxyPolicyBypass = "bypass"
+)
+
 // ProxyRule describes a regular expression matching rule for how to proxy a request.
 type ProxyRule struct {
 	Regx     *Regexp `yaml:"regx" mapstructure:"regx"`
 	UseHTTPS bool    `yaml:"useHTTPS" mapstructure:"useHTTPS"`
-	Direct   bool    `yaml:"direct" mapstructure:"direct"`
+	// Direct is deprecated, use Policy direct instead
+	Direct bool `yaml:"direct" mapstructure:"direct"`
 
 	// Redirect is the host to redirect to, if not empty
 	Redirect string `yaml:"redirect" mapstructure:"redirect"`
+
+	// Policy is how to proxy the matched requests, p2p, direct, cacheOnly or bypass,
+	// default is p2p, or direct when Direct is true
+	Policy string `yaml:"policy" mapstructure:"policy"`
+
+	// RateLimit limits the total rate of the response bodies of the matched requests, zero is no limit
+	RateLimit util.RateLimit `yaml:"rateLimit" mapstructure:"rateLimit"`
+
+	limiterOnce sync.Once
+	limiter     *rate.Limiter
 }
 
 func NewProxyRule(regx string, useHTTPS bool, direct bool, redirect string) (*ProxyRule, error) {
@@ -859,6 +1455,32 @@ func (r *ProxyRule) Match(url string) bool {
 	return r.Regx != nil && r.Regx.MatchString(url)
 }
 
+// GetPolicy returns the policy of the rule.
+func (r *ProxyRule) GetPolicy() string {
+	if r.Policy != "" {
+		return r.Policy
+	}
+
+	if r.Direct {
+		return ProxyPolicyDirect
+	}
+
+	return ProxyPolicyP2P
+}
+
+// Limiter returns the limiter shared by the matched requests, it returns nil when there is no rate limit.
+func (r *ProxyRule) Limiter() *rate.Limiter {
+	if r.RateLimit.Limit <= 0 || r.RateLimit.Limit == rate.Inf {
+		return nil
+	}
+
+	r.limiterOnce.Do(func() {
+		// the burst is the bytes of one second, at least one byte
+		r.limiter = rate.NewLimiter(r.RateLimit.Limit, max(int(r.RateLimit.Limit), 1))
+	})
+	return r.limiter
+}
+
 // Regexp is a simple wrapper around regexp. Regexp to make it unmarshallable from a string.
 type Regexp struct {
 	*regexp.Regexp
@@ -911,6 +1533,10 @@ type HijackConfig struct {
 	Key   string             `yaml:"key" mapstructure:"key"`
 	Hosts []*HijackHost      `yaml:"hosts" mapstructure:"hosts"`
 	SNI   []*TCPListenOption `yaml:"sni" mapstructure:"sni"`
+	// LeafCertTTL is the validity period of the leaf certificates generated by the CA.
+	LeafCertTTL time.Duration `yaml:"leafCertTTL" mapstructure:"leafCertTTL"`
+	// CertCacheSize is the max number of the generated leaf certificates in cache.
+	CertCacheSize int `yaml:"certCacheSize" mapstructure:"certCacheSize"`
 }
 
 // HijackHost is a hijack rule for the hosts that matches Regx.
@@ -958,3 +1584,14 @@ type ProbeOption struct {
 	// Interval is the interval of probing hosts.
 	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
 }
+
+type ProfilingOption struct {
+	// Enable pushing cpu profiles continuously.
+	Enable bool `mapstructure:"enable" yaml:"enable"`
+
+	// Addr is the address of the pyroscope compatible server, like: http://pyroscope:4040.
+	Addr string `mapstructure:"addr" yaml:"addr"`
+
+	// Interval is the duration of collecting a cpu profile before pushing.
+	Interval time.Duration `mapstructure:"interval" yaml:"interval"`
+}
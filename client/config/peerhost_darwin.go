@@ -19,6 +19,7 @@
 package config
 
 import (
+	"net/url"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -48,6 +49,7 @@ var peerHostConfig = func() *DaemonOption {
 				},
 			},
 			ScheduleTimeout: util.Duration{Duration: DefaultScheduleTimeout},
+			RequestTimeout:  DefaultRequestTimeout,
 		},
 		Host: HostOption{
 			Hostname: fqdn.FQDNHostname,
@@ -88,11 +90,26 @@ var peerHostConfig = func() *DaemonOption {
 				},
 			},
 			SplitRunningTasks: false,
+			Stream: StreamOption{
+				Enable: true,
+			},
 		},
 		Upload: UploadOption{
 			RateLimit: util.RateLimit{
 				Limit: rate.Limit(DefaultUploadLimit),
 			},
+			Adaptive: AdaptiveRateLimitOption{
+				Enable:               false,
+				Interval:             DefaultAdaptiveInterval,
+				CPUThreshold:         DefaultAdaptiveCPUThreshold,
+				DiskLatencyThreshold: DefaultAdaptiveDiskLatencyThreshold,
+				NetworkThreshold:     DefaultAdaptiveNetworkThreshold,
+			},
+			Reservation: UploadReservationOption{
+				Enable:          false,
+				ConcurrentLimit: DefaultUploadReservationConcurrentLimit,
+				TTL:             DefaultUploadReservationTTL,
+			},
 			ListenOption: ListenOption{
 				Security: SecurityOption{
 					Insecure:  true,
@@ -110,6 +127,22 @@ var peerHostConfig = func() *DaemonOption {
 			Enable:      false,
 			Filter:      "Expires&Signature&ns",
 			MaxReplicas: DefaultObjectMaxReplicas,
+			S3: S3GatewayOption{
+				Enable: false,
+				Region: DefaultS3GatewayRegion,
+				ListenOption: ListenOption{
+					Security: SecurityOption{
+						Insecure:  true,
+						TLSVerify: true,
+					},
+					TCPListen: &TCPListenOption{
+						PortRange: TCPListenPortRange{
+							Start: DefaultS3GatewayStartPort,
+							End:   DefaultEndPort,
+						},
+					},
+				},
+			},
 			ListenOption: ListenOption{
 				Security: SecurityOption{
 					Insecure:  true,
@@ -123,6 +156,27 @@ var peerHostConfig = func() *DaemonOption {
 				},
 			},
 		},
+		Registry: RegistryOption{
+			Enable: false,
+			Remote: &URL{
+				URL: &url.URL{
+					Host:   "index.docker.io",
+					Scheme: "https",
+				},
+			},
+			ListenOption: ListenOption{
+				Security: SecurityOption{
+					Insecure:  true,
+					TLSVerify: true,
+				},
+				TCPListen: &TCPListenOption{
+					PortRange: TCPListenPortRange{
+						Start: DefaultRegistryStartPort,
+						End:   DefaultEndPort,
+					},
+				},
+			},
+		},
 		Proxy: &ProxyOption{
 			ListenOption: ListenOption{
 				Security: SecurityOption{
@@ -141,6 +195,25 @@ var peerHostConfig = func() *DaemonOption {
 			StoreStrategy:          SimpleLocalTaskStoreStrategy,
 			Multiplex:              false,
 			DiskGCThresholdPercent: 95,
+			Quota: QuotaOption{
+				Enable:        false,
+				HighWatermark: DefaultQuotaHighWatermark,
+				LowWatermark:  DefaultQuotaLowWatermark,
+			},
+			Tier: TierOption{
+				Enable:     false,
+				MemorySize: DefaultTierMemorySize,
+			},
+			IOUring: IOUringOption{
+				Enable:  false,
+				Entries: DefaultIOUringEntries,
+			},
+			Resume: ResumeOption{
+				Enable: false,
+				PersistInterval: util.Duration{
+					Duration: DefaultResumePersistInterval,
+				},
+			},
 		},
 		Health: &HealthOption{
 			ListenOption: ListenOption{
@@ -185,5 +258,9 @@ var peerHostConfig = func() *DaemonOption {
 				Interval: DefaultProbeInterval,
 			},
 		},
+		Profiling: ProfilingOption{
+			Enable:   false,
+			Interval: DefaultProfilingInterval,
+		},
 	}
 }
@@ -360,6 +360,22 @@ func TestPeerHostOption_Load(t *testing.T) {
 			RateLimit: util.RateLimit{
 				Limit: 1024 * 1024 * 1024,
 			},
+			Adaptive: AdaptiveRateLimitOption{
+				Enable:               true,
+				Interval:             10 * time.Second,
+				CPUThreshold:         80,
+				DiskLatencyThreshold: 100 * time.Millisecond,
+				NetworkThreshold:     90,
+				NetworkBandwidth: util.RateLimit{
+					Limit: 10 * 1024 * 1024 * 1024,
+				},
+			},
+			Reservation: UploadReservationOption{
+				Enable:          true,
+				ConcurrentLimit: 100,
+				TTL:             10 * time.Second,
+				HMACSecret:      "foo",
+			},
 			ListenOption: ListenOption{
 				Security: SecurityOption{
 					Insecure:  true,
@@ -397,6 +413,44 @@ func TestPeerHostOption_Load(t *testing.T) {
 					},
 				},
 			},
+			S3: S3GatewayOption{
+				Enable:    true,
+				Region:    "us-east-1",
+				AccessKey: "foo",
+				SecretKey: "bar",
+				ListenOption: ListenOption{
+					TCPListen: &TCPListenOption{
+						Listen: "0.0.0.0",
+						PortRange: TCPListenPortRange{
+							Start: 65010,
+							End:   0,
+						},
+					},
+				},
+			},
+		},
+		Registry: RegistryOption{
+			Enable: true,
+			Remote: &URL{
+				&url.URL{
+					Host:   "index.docker.io",
+					Scheme: "https",
+				},
+			},
+			Insecure: true,
+			ListenOption: ListenOption{
+				Security: SecurityOption{
+					Insecure:  true,
+					TLSVerify: true,
+				},
+				TCPListen: &TCPListenOption{
+					Listen: "0.0.0.0",
+					PortRange: TCPListenPortRange{
+						Start: 65005,
+						End:   0,
+					},
+				},
+			},
 		},
 		Storage: StorageOption{
 			DataPath: "/tmp/storage/data",
@@ -407,6 +461,50 @@ func TestPeerHostOption_Load(t *testing.T) {
 			DiskGCThreshold:        60 * unit.MB,
 			DiskGCThresholdPercent: 0.6,
 			Multiplex:              true,
+			Quota: QuotaOption{
+				Enable:        true,
+				Size:          10 * unit.GB,
+				HighWatermark: 90,
+				LowWatermark:  70,
+			},
+			Tier: TierOption{
+				Enable:     true,
+				MemorySize: 512 * unit.MB,
+				SSDPath:    "/tmp/storage/ssd",
+				SSDSize:    100 * unit.GB,
+			},
+			IOUring: IOUringOption{
+				Enable:  true,
+				Entries: 128,
+			},
+			Dedup: DedupOption{
+				Enable: true,
+			},
+			Resume: ResumeOption{
+				Enable: true,
+				PersistInterval: util.Duration{
+					Duration: 10 * time.Second,
+				},
+			},
+			Encryption: StorageEncryptionOption{
+				Enable:  false,
+				KeyFile: "/etc/dragonfly/storage.key",
+			},
+			Preallocate: PreallocateOption{
+				Enable:   false,
+				Capacity: 100 * unit.GB,
+			},
+			Overflow: OverflowOption{
+				Enable:           false,
+				Name:             "s3",
+				Region:           "us-east-1",
+				Endpoint:         "s3.amazonaws.com",
+				AccessKey:        "foo",
+				SecretKey:        "bar",
+				S3ForcePathStyle: true,
+				Bucket:           "dragonfly",
+				Prefix:           "tasks",
+			},
 		},
 		Health: &HealthOption{
 			Path: "/health",
@@ -447,6 +545,21 @@ func TestPeerHostOption_Load(t *testing.T) {
 				UseProxies:    true,
 				Insecure:      true,
 				Direct:        false,
+				Fallbacks: []*RegistryUpstream{
+					{
+						Remote: &URL{
+							&url.URL{
+								Host:   "mirror.example.com",
+								Scheme: "https",
+							},
+						},
+						Insecure: true,
+					},
+				},
+				HealthCheck: RegistryHealthCheckOption{
+					Interval: time.Minute,
+					Timeout:  3 * time.Second,
+				},
 			},
 			WhiteList: []*WhiteList{
 				{
@@ -464,6 +577,10 @@ func TestPeerHostOption_Load(t *testing.T) {
 					UseHTTPS: false,
 					Direct:   false,
 					Redirect: "d7y.io",
+					Policy:   ProxyPolicyCacheOnly,
+					RateLimit: util.RateLimit{
+						Limit: 10485760,
+					},
 				},
 			},
 			HijackHTTPS: &HijackConfig{
@@ -475,7 +592,24 @@ func TestPeerHostOption_Load(t *testing.T) {
 						Insecure: true,
 					},
 				},
-				SNI: nil,
+				SNI:           nil,
+				LeafCertTTL:   12 * time.Hour,
+				CertCacheSize: 200,
+			},
+			Transparent: &TransparentOption{
+				TCPListenOption: TCPListenOption{
+					Listen: "0.0.0.0",
+					PortRange: TCPListenPortRange{
+						Start: 65003,
+					},
+				},
+				Mode: TransparentModeTProxy,
+			},
+			Socks5: &TCPListenOption{
+				Listen: "0.0.0.0",
+				PortRange: TCPListenPortRange{
+					Start: 65004,
+				},
 			},
 			DumpHTTPContent: true,
 			ExtraRegistryMirrors: []*RegistryMirror{
@@ -521,6 +655,11 @@ func TestPeerHostOption_Load(t *testing.T) {
 				Interval: 20 * time.Minute,
 			},
 		},
+		Profiling: ProfilingOption{
+			Enable:   true,
+			Addr:     "http://127.0.0.1:4040",
+			Interval: 10 * time.Second,
+		},
 	}
 
 	peerHostOptionYAML := &DaemonOption{}
@@ -650,6 +789,26 @@ func TestPeerHostOption_Validate(t *testing.T) {
 				assert.EqualError(err, "max replicas must be greater than 0")
 			},
 		},
+		{
+			name:   "objectStorage s3 requires parameter accessKey and secretKey",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.ObjectStorage.Enable = true
+				cfg.ObjectStorage.MaxReplicas = 3
+				cfg.ObjectStorage.S3.Enable = true
+				cfg.ObjectStorage.S3.AccessKey = "foo"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "objectStorage s3 requires parameter accessKey and secretKey")
+			},
+		},
 		{
 			name:   "reload interval too short, must great than 1 second",
 			config: NewDaemonConfig(),
@@ -760,6 +919,42 @@ func TestPeerHostOption_Validate(t *testing.T) {
 				assert.EqualError(err, "certSpec requires parameter validityPeriod")
 			},
 		},
+		{
+			name:   "security requires parameter keyFile",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Security.CertFile = "foo"
+				cfg.Security.CAFile = "bar"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security requires parameter keyFile")
+			},
+		},
+		{
+			name:   "security requires parameter caFile",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Security.CertFile = "foo"
+				cfg.Security.KeyFile = "bar"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security requires parameter caFile")
+			},
+		},
 		{
 			name:   "probe requires parameter interval",
 			config: NewDaemonConfig(),
@@ -778,6 +973,605 @@ func TestPeerHostOption_Validate(t *testing.T) {
 				assert.EqualError(err, "probe requires parameter interval")
 			},
 		},
+		{
+			name:   "adaptive requires parameter interval",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Upload.Adaptive.Enable = true
+				cfg.Upload.Adaptive.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "adaptive requires parameter interval")
+			},
+		},
+		{
+			name:   "adaptive cpuThreshold is invalid",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Upload.Adaptive.Enable = true
+				cfg.Upload.Adaptive.CPUThreshold = 101
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "adaptive cpuThreshold must be in (0, 100]")
+			},
+		},
+		{
+			name:   "adaptive networkThreshold is invalid",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Upload.Adaptive.Enable = true
+				cfg.Upload.Adaptive.NetworkThreshold = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "adaptive networkThreshold must be in (0, 100]")
+			},
+		},
+		{
+			name:   "reservation requires parameter concurrentLimit",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Upload.Reservation.Enable = true
+				cfg.Upload.Reservation.ConcurrentLimit = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "reservation requires parameter concurrentLimit")
+			},
+		},
+		{
+			name:   "reservation requires parameter ttl",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Upload.Reservation.Enable = true
+				cfg.Upload.Reservation.TTL = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "reservation requires parameter ttl")
+			},
+		},
+		{
+			name:   "reservation requires parameter hmacSecret",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Upload.Reservation.Enable = true
+				cfg.Upload.Reservation.HMACSecret = ""
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "reservation requires parameter hmacSecret")
+			},
+		},
+		{
+			name:   "integrity requires parameter privateKey or publicKeys",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Download.Integrity.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "integrity requires parameter privateKey or publicKeys")
+			},
+		},
+		{
+			name:   "integrity requires calculateDigest",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Download.Integrity.Enable = true
+				cfg.Download.Integrity.PublicKeys = []string{"/etc/dragonfly/integrity.pub"}
+				cfg.Download.CalculateDigest = false
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "integrity requires calculateDigest")
+			},
+		},
+		{
+			name:   "integrity requires scheduler manager",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Download.Integrity.Enable = true
+				cfg.Download.Integrity.PublicKeys = []string{"/etc/dragonfly/integrity.pub"}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "integrity requires scheduler manager to configure piece digest algorithm sha256, sha512 or blake3")
+			},
+		},
+		{
+			name:   "profiling requires parameter addr",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Profiling.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "profiling requires parameter addr")
+			},
+		},
+		{
+			name:   "profiling requires parameter interval",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Profiling.Enable = true
+				cfg.Profiling.Addr = "http://127.0.0.1:4040"
+				cfg.Profiling.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "profiling requires parameter interval")
+			},
+		},
+		{
+			name:   "quota requires parameter highWatermark",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Quota.Enable = true
+				cfg.Storage.Quota.HighWatermark = 101
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "quota requires parameter highWatermark")
+			},
+		},
+		{
+			name:   "quota requires parameter lowWatermark",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Quota.Enable = true
+				cfg.Storage.Quota.HighWatermark = 80
+				cfg.Storage.Quota.LowWatermark = 90
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "quota requires parameter lowWatermark")
+			},
+		},
+		{
+			name:   "tier requires parameter memorySize or ssdPath",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Tier.Enable = true
+				cfg.Storage.Tier.MemorySize = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "tier requires parameter memorySize or ssdPath")
+			},
+		},
+		{
+			name:   "tier requires parameter ssdSize",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Tier.Enable = true
+				cfg.Storage.Tier.SSDPath = "/tmp/storage/ssd"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "tier requires parameter ssdSize")
+			},
+		},
+		{
+			name:   "ioUring requires parameter entries",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.IOUring.Enable = true
+				cfg.Storage.IOUring.Entries = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "ioUring requires parameter entries")
+			},
+		},
+		{
+			name:   "resume requires parameter persistInterval",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Resume.Enable = true
+				cfg.Storage.Resume.PersistInterval = util.Duration{}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "resume requires parameter persistInterval")
+			},
+		},
+		{
+			name:   "encryption requires parameter keyFile or keyCommand",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Encryption.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "encryption requires parameter keyFile or keyCommand")
+			},
+		},
+		{
+			name:   "encryption is not compatible with advance store strategy",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Encryption.Enable = true
+				cfg.Storage.Encryption.KeyFile = "/etc/dragonfly/storage.key"
+				cfg.Storage.StoreStrategy = AdvanceLocalTaskStoreStrategy
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "encryption is not compatible with advance store strategy")
+			},
+		},
+		{
+			name:   "encryption is not compatible with dedup",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Encryption.Enable = true
+				cfg.Storage.Encryption.KeyFile = "/etc/dragonfly/storage.key"
+				cfg.Storage.Dedup.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "encryption is not compatible with dedup")
+			},
+		},
+		{
+			name:   "encryption is not compatible with tier ssdPath",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Encryption.Enable = true
+				cfg.Storage.Encryption.KeyFile = "/etc/dragonfly/storage.key"
+				cfg.Storage.Tier.Enable = true
+				cfg.Storage.Tier.SSDPath = "/tmp/storage/ssd"
+				cfg.Storage.Tier.SSDSize = 100 * unit.GB
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "encryption is not compatible with tier ssdPath")
+			},
+		},
+		{
+			name:   "preallocate requires seed peer",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Preallocate.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "preallocate requires seed peer")
+			},
+		},
+		{
+			name:   "overflow requires seed peer",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Storage.Overflow.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "overflow requires seed peer")
+			},
+		},
+		{
+			name:   "overflow requires parameter bucket",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Scheduler.Manager.SeedPeer.Enable = true
+				cfg.Storage.Overflow.Enable = true
+				cfg.Storage.Overflow.Name = "s3"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "overflow requires parameter bucket")
+			},
+		},
+		{
+			name:   "transparent requires parameter port",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Proxy = &ProxyOption{Transparent: &TransparentOption{}}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "transparent requires parameter port")
+			},
+		},
+		{
+			name:   "transparent mode is invalid",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Proxy = &ProxyOption{Transparent: &TransparentOption{
+					TCPListenOption: TCPListenOption{PortRange: TCPListenPortRange{Start: 65003}},
+					Mode:            "foo",
+				}}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "transparent mode foo is invalid")
+			},
+		},
+		{
+			name:   "proxy rule policy is invalid",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				rule, _ := NewProxyRule("blobs/sha256.*", false, false, "")
+				rule.Policy = "foo"
+				cfg.Proxy = &ProxyOption{ProxyRules: []*ProxyRule{rule}}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "proxy rule blobs/sha256.* policy foo is invalid")
+			},
+		},
+		{
+			name:   "registryMirror fallbacks requires parameter url",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Proxy = &ProxyOption{RegistryMirror: &RegistryMirror{
+					Fallbacks: []*RegistryUpstream{{}},
+				}}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "registryMirror fallbacks requires parameter url")
+			},
+		},
+		{
+			name:   "registry requires seed peer",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Registry.Enable = true
+				cfg.Scheduler.Manager.SeedPeer.Enable = false
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "registry requires seed peer")
+			},
+		},
+		{
+			name:   "registry requires parameter url",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Registry.Enable = true
+				cfg.Registry.Remote = nil
+				cfg.Scheduler.Manager.SeedPeer.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "registry requires parameter url")
+			},
+		},
+		{
+			name:   "socks5 requires parameter port",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Proxy = &ProxyOption{Socks5: &TCPListenOption{}}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "socks5 requires parameter port")
+			},
+		},
+		{
+			name:   "hijackHTTPS requires parameter leafCertTTL greater than or equal to 0",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Proxy = &ProxyOption{HijackHTTPS: &HijackConfig{LeafCertTTL: -time.Hour}}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "hijackHTTPS requires parameter leafCertTTL greater than or equal to 0")
+			},
+		},
+		{
+			name:   "hijackHTTPS requires parameter certCacheSize greater than or equal to 0",
+			config: NewDaemonConfig(),
+			mock: func(cfg *DaemonConfig) {
+				cfg.Scheduler.NetAddrs = []dfnet.NetAddr{
+					{
+						Type: dfnet.TCP,
+						Addr: "127.0.0.1:8002",
+					},
+				}
+				cfg.Proxy = &ProxyOption{HijackHTTPS: &HijackConfig{CertCacheSize: -1}}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "hijackHTTPS requires parameter certCacheSize greater than or equal to 0")
+			},
+		},
 	}
 
 	for _, tc := range tests {
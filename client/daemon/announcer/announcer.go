@@ -29,12 +29,15 @@ import (
 	"github.com/shirou/gopsutil/v3/mem"
 	gopsutilnet "github.com/shirou/gopsutil/v3/net"
 	"github.com/shirou/gopsutil/v3/process"
+	"golang.org/x/time/rate"
 
 	managerv1 "d7y.io/api/v2/pkg/apis/manager/v1"
 	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/reputation"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
 	"d7y.io/dragonfly/v2/pkg/types"
@@ -60,6 +63,8 @@ type announcer struct {
 	daemonObjectStoragePort int32
 	schedulerClient         schedulerclient.V1
 	managerClient           managerclient.V1
+	uploadLimiter           *rate.Limiter
+	parentTracker           reputation.Tracker
 	done                    chan struct{}
 }
 
@@ -80,6 +85,20 @@ func WithObjectStoragePort(port int32) Option {
 	}
 }
 
+// WithUploadLimiter sets the upload limiter, its effective limit is reported to scheduler.
+func WithUploadLimiter(limiter *rate.Limiter) Option {
+	return func(a *announcer) {
+		a.uploadLimiter = limiter
+	}
+}
+
+// WithParentTracker sets the parent tracker, the parent reports are reported to scheduler.
+func WithParentTracker(tracker reputation.Tracker) Option {
+	return func(a *announcer) {
+		a.parentTracker = tracker
+	}
+}
+
 // New returns a new Announcer interface.
 func New(cfg *config.DaemonOption, dynconfig config.Dynconfig, hostID string, daemonPort int32, daemonDownloadPort int32, schedulerClient schedulerclient.V1, options ...Option) Announcer {
 	a := &announcer{
@@ -129,7 +148,7 @@ func (a *announcer) announceToScheduler() error {
 		return err
 	}
 
-	if err := a.schedulerClient.AnnounceHost(context.Background(), req); err != nil {
+	if err := a.schedulerClient.AnnounceHost(a.newAnnounceHostContext(), req); err != nil {
 		logger.Errorf("announce for the first time failed: %s", err.Error())
 	}
 
@@ -144,7 +163,7 @@ func (a *announcer) announceToScheduler() error {
 				break
 			}
 
-			if err := a.schedulerClient.AnnounceHost(context.Background(), req); err != nil {
+			if err := a.schedulerClient.AnnounceHost(a.newAnnounceHostContext(), req); err != nil {
 				logger.Error(err)
 				break
 			}
@@ -154,6 +173,21 @@ func (a *announcer) announceToScheduler() error {
 	}
 }
 
+// newAnnounceHostContext returns the context of announcing host, it carries the topology,
+// the effective upload rate limit of host and the reports of the parents since last announcement.
+func (a *announcer) newAnnounceHostContext() context.Context {
+	ctx := rpc.ContextWithTopology(context.Background(), a.config.Host.Topology)
+	if a.uploadLimiter != nil && a.uploadLimiter.Limit() != rate.Inf {
+		ctx = rpc.ContextWithUploadRateLimit(ctx, uint64(a.uploadLimiter.Limit()))
+	}
+
+	if a.parentTracker != nil {
+		ctx = rpc.ContextWithParentReports(ctx, a.parentTracker.Reports())
+	}
+
+	return ctx
+}
+
 // newAnnounceHostRequest returns announce host request.
 func (a *announcer) newAnnounceHostRequest() (*schedulerv1.AnnounceHostRequest, error) {
 	hostType := types.HostTypeNormalName
@@ -17,13 +17,17 @@
 package announcer
 
 import (
+	"context"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
 
 	"d7y.io/dragonfly/v2/client/config"
 	configmocks "d7y.io/dragonfly/v2/client/config/mocks"
+	"d7y.io/dragonfly/v2/client/daemon/reputation"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	managerclientmocks "d7y.io/dragonfly/v2/pkg/rpc/manager/client/mocks"
 	schedulerclientmocks "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client/mocks"
 )
@@ -84,3 +88,40 @@ func TestAnnouncer_New(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnouncer_newAnnounceHostContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(tracker reputation.Tracker)
+		expect func(t *testing.T, reports []rpc.ParentReport)
+	}{
+		{
+			name: "carry parent reports",
+			record: func(tracker reputation.Tracker) {
+				tracker.Success("foo")
+				tracker.Failure("foo", true)
+			},
+			expect: func(t *testing.T, reports []rpc.ParentReport) {
+				assert.Equal(t, []rpc.ParentReport{{PeerID: "foo", Pieces: 2, Failures: 1, Corruptions: 1}}, reports)
+			},
+		},
+		{
+			name:   "without parent reports",
+			record: func(tracker reputation.Tracker) {},
+			expect: func(t *testing.T, reports []rpc.ParentReport) {
+				assert.Empty(t, reports)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := reputation.NewTracker()
+			tc.record(tracker)
+
+			a := &announcer{config: &config.DaemonOption{}, parentTracker: tracker}
+			md, _ := metadata.FromOutgoingContext(a.newAnnounceHostContext())
+			tc.expect(t, rpc.ParentReportsFromIncomingContext(metadata.NewIncomingContext(context.Background(), md)))
+		})
+	}
+}
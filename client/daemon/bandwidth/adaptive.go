@@ -0,0 +1,242 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bandwidth
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	gopsutilnet "github.com/shirou/gopsutil/v3/net"
+	"golang.org/x/time/rate"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// adaptiveBackoffRatio is the ratio of the current limit to keep when the host is overloaded.
+	adaptiveBackoffRatio = 0.5
+
+	// adaptiveRecoverStep is the ratio of the base limit to recover when the host is not overloaded.
+	adaptiveRecoverStep = 0.1
+
+	// adaptiveMinRatio is the minimum ratio of the base limit.
+	adaptiveMinRatio = 0.1
+)
+
+// Load is the host load sampled in an interval.
+type Load struct {
+	// CPUPercent is the cpu usage percent.
+	CPUPercent float64
+
+	// DiskLatency is the average latency of the disk io.
+	DiskLatency time.Duration
+
+	// NetworkPercent is the usage percent of the network bandwidth.
+	NetworkPercent float64
+}
+
+// Adaptive backs off the limit of the upload limiter when the host load crosses the thresholds,
+// and recovers it gradually after the host load drops.
+type Adaptive interface {
+	// Serve starts sampling the host load.
+	Serve()
+
+	// Stop stops sampling the host load.
+	Stop()
+}
+
+// adaptive provides the adaptive upload rate limit.
+type adaptive struct {
+	config  config.AdaptiveRateLimitOption
+	limiter *rate.Limiter
+
+	// base is the limit set by others, e.g. the config or the bandwidth policies.
+	base rate.Limit
+
+	// applied is the limit set by adaptive last time.
+	applied rate.Limit
+
+	// ratio is the ratio of the base limit to apply.
+	ratio float64
+
+	sample func() (Load, error)
+	done   chan struct{}
+}
+
+// NewAdaptive returns a new Adaptive interface.
+func NewAdaptive(cfg config.AdaptiveRateLimitOption, limiter *rate.Limiter) Adaptive {
+	sampler := &loadSampler{bandwidth: float64(cfg.NetworkBandwidth.Limit)}
+	return &adaptive{
+		config:  cfg,
+		limiter: limiter,
+		base:    limiter.Limit(),
+		applied: limiter.Limit(),
+		ratio:   1,
+		sample:  sampler.sample,
+		done:    make(chan struct{}),
+	}
+}
+
+// Serve starts sampling the host load.
+func (a *adaptive) Serve() {
+	// The first sample initializes the counters of disk and network.
+	if _, err := a.sample(); err != nil {
+		logger.Warnf("sample host load error: %s", err)
+	}
+
+	tick := time.NewTicker(a.config.Interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			load, err := a.sample()
+			if err != nil {
+				logger.Warnf("sample host load error: %s", err)
+				continue
+			}
+
+			a.adjust(load)
+		case <-a.done:
+			return
+		}
+	}
+}
+
+// Stop stops sampling the host load.
+func (a *adaptive) Stop() {
+	close(a.done)
+}
+
+// adjust scales the limit of the limiter by the host load, the base limit
+// follows the changes made by others, e.g. the bandwidth policies.
+func (a *adaptive) adjust(load Load) {
+	if current := a.limiter.Limit(); current != a.applied {
+		a.base = current
+	}
+
+	if reason, ok := a.overloaded(load); ok {
+		a.ratio = math.Max(a.ratio*adaptiveBackoffRatio, adaptiveMinRatio)
+		logger.Infof("host is overloaded by %s, back off upload rate limit to %.0f%%", reason, a.ratio*100)
+	} else {
+		a.ratio = math.Min(a.ratio+adaptiveRecoverStep, 1)
+	}
+
+	// The unlimited rate is not scaled.
+	limit := a.base
+	if limit != rate.Inf {
+		limit = a.base * rate.Limit(a.ratio)
+	}
+
+	a.applied = limit
+	if a.limiter.Limit() != limit {
+		a.limiter.SetLimit(limit)
+	}
+}
+
+// overloaded returns the reason if the host load crosses the thresholds.
+func (a *adaptive) overloaded(load Load) (string, bool) {
+	if load.CPUPercent >= a.config.CPUThreshold {
+		return fmt.Sprintf("cpu usage %.2f%%", load.CPUPercent), true
+	}
+
+	if a.config.DiskLatencyThreshold > 0 && load.DiskLatency >= a.config.DiskLatencyThreshold {
+		return fmt.Sprintf("disk latency %s", load.DiskLatency), true
+	}
+
+	if load.NetworkPercent >= a.config.NetworkThreshold {
+		return fmt.Sprintf("network usage %.2f%%", load.NetworkPercent), true
+	}
+
+	return "", false
+}
+
+// loadSampler samples the host load by the differences of the counters between samples.
+type loadSampler struct {
+	// bandwidth is the bandwidth of the network interfaces in bytes per second.
+	bandwidth float64
+
+	sampledAt   time.Time
+	diskTime    uint64
+	diskCount   uint64
+	networkSent uint64
+	networkRecv uint64
+}
+
+// sample returns the host load since last sample.
+func (s *loadSampler) sample() (Load, error) {
+	now := time.Now()
+	cpuPercent, err := cpu.Percent(0, false)
+	if err != nil {
+		return Load{}, err
+	}
+
+	diskCounters, err := disk.IOCounters()
+	if err != nil {
+		return Load{}, err
+	}
+
+	var diskTime, diskCount uint64
+	for _, counter := range diskCounters {
+		diskTime += counter.ReadTime + counter.WriteTime
+		diskCount += counter.ReadCount + counter.WriteCount
+	}
+
+	networkCounters, err := gopsutilnet.IOCounters(true)
+	if err != nil {
+		return Load{}, err
+	}
+
+	var networkSent, networkRecv uint64
+	for _, counter := range networkCounters {
+		// Skip the loopback interfaces.
+		if strings.HasPrefix(counter.Name, "lo") {
+			continue
+		}
+
+		networkSent += counter.BytesSent
+		networkRecv += counter.BytesRecv
+	}
+
+	var load Load
+	if len(cpuPercent) > 0 {
+		load.CPUPercent = cpuPercent[0]
+	}
+
+	// The counters may be reset, e.g. the devices are removed.
+	if !s.sampledAt.IsZero() {
+		if diskCount > s.diskCount && diskTime >= s.diskTime {
+			load.DiskLatency = time.Duration(float64(diskTime-s.diskTime) / float64(diskCount-s.diskCount) * float64(time.Millisecond))
+		}
+
+		elapsed := now.Sub(s.sampledAt).Seconds()
+		if s.bandwidth > 0 && elapsed > 0 && networkSent >= s.networkSent && networkRecv >= s.networkRecv {
+			traffic := max(networkSent-s.networkSent, networkRecv-s.networkRecv)
+			load.NetworkPercent = float64(traffic) / elapsed / s.bandwidth * 100
+		}
+	}
+
+	s.sampledAt = now
+	s.diskTime, s.diskCount = diskTime, diskCount
+	s.networkSent, s.networkRecv = networkSent, networkRecv
+	return load, nil
+}
@@ -0,0 +1,137 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bandwidth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"d7y.io/dragonfly/v2/client/config"
+)
+
+func TestAdaptive_adjust(t *testing.T) {
+	cfg := config.AdaptiveRateLimitOption{
+		Enable:               true,
+		Interval:             time.Second,
+		CPUThreshold:         80,
+		DiskLatencyThreshold: 100 * time.Millisecond,
+		NetworkThreshold:     90,
+	}
+
+	tests := []struct {
+		name   string
+		run    func(a *adaptive, limiter *rate.Limiter)
+		expect func(t *testing.T, limiter *rate.Limiter)
+	}{
+		{
+			name: "host is not overloaded",
+			run: func(a *adaptive, limiter *rate.Limiter) {
+				a.adjust(Load{CPUPercent: 10, DiskLatency: time.Millisecond, NetworkPercent: 10})
+			},
+			expect: func(t *testing.T, limiter *rate.Limiter) {
+				assert.Equal(t, rate.Limit(1000), limiter.Limit())
+			},
+		},
+		{
+			name: "back off by cpu usage",
+			run: func(a *adaptive, limiter *rate.Limiter) {
+				a.adjust(Load{CPUPercent: 90})
+			},
+			expect: func(t *testing.T, limiter *rate.Limiter) {
+				assert.Equal(t, rate.Limit(500), limiter.Limit())
+			},
+		},
+		{
+			name: "back off by disk latency",
+			run: func(a *adaptive, limiter *rate.Limiter) {
+				a.adjust(Load{DiskLatency: time.Second})
+				a.adjust(Load{DiskLatency: time.Second})
+			},
+			expect: func(t *testing.T, limiter *rate.Limiter) {
+				assert.Equal(t, rate.Limit(250), limiter.Limit())
+			},
+		},
+		{
+			name: "back off by network usage to minimum ratio",
+			run: func(a *adaptive, limiter *rate.Limiter) {
+				for i := 0; i < 10; i++ {
+					a.adjust(Load{NetworkPercent: 95})
+				}
+			},
+			expect: func(t *testing.T, limiter *rate.Limiter) {
+				assert.InDelta(t, 100, float64(limiter.Limit()), 0.01)
+			},
+		},
+		{
+			name: "recover gradually after host load drops",
+			run: func(a *adaptive, limiter *rate.Limiter) {
+				a.adjust(Load{CPUPercent: 90})
+				a.adjust(Load{CPUPercent: 10})
+			},
+			expect: func(t *testing.T, limiter *rate.Limiter) {
+				assert.InDelta(t, 600, float64(limiter.Limit()), 0.01)
+			},
+		},
+		{
+			name: "follow the limit changed by others",
+			run: func(a *adaptive, limiter *rate.Limiter) {
+				a.adjust(Load{CPUPercent: 90})
+				limiter.SetLimit(2000)
+				a.adjust(Load{CPUPercent: 90})
+			},
+			expect: func(t *testing.T, limiter *rate.Limiter) {
+				assert.Equal(t, rate.Limit(500), limiter.Limit())
+			},
+		},
+		{
+			name: "unlimited rate is not scaled",
+			run: func(a *adaptive, limiter *rate.Limiter) {
+				limiter.SetLimit(rate.Inf)
+				a.adjust(Load{CPUPercent: 90})
+			},
+			expect: func(t *testing.T, limiter *rate.Limiter) {
+				assert.Equal(t, rate.Inf, limiter.Limit())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limiter := rate.NewLimiter(1000, 1000)
+			a := NewAdaptive(cfg, limiter).(*adaptive)
+			tc.run(a, limiter)
+			tc.expect(t, limiter)
+		})
+	}
+}
+
+func TestAdaptive_sample(t *testing.T) {
+	s := &loadSampler{bandwidth: 1024 * 1024 * 1024}
+	_, err := s.sample()
+	if err != nil {
+		t.Skipf("host load is not available: %s", err)
+	}
+
+	load, err := s.sample()
+	assert := assert.New(t)
+	assert.NoError(err)
+	assert.GreaterOrEqual(load.CPUPercent, float64(0))
+	assert.GreaterOrEqual(load.NetworkPercent, float64(0))
+}
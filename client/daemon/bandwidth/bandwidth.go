@@ -0,0 +1,162 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bandwidth
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/internal/util"
+)
+
+const (
+	// DefaultInterval is the default interval of applying the bandwidth policies.
+	DefaultInterval = time.Minute
+)
+
+// PolicyEngine applies the rate limits of the bandwidth policies from dynconfig
+// to the limiters of the traffic classes by the time window.
+type PolicyEngine interface {
+	// Serve starts applying the bandwidth policies.
+	Serve()
+
+	// Stop stops applying the bandwidth policies.
+	Stop()
+}
+
+// limit is the rate limit and the burst of the limiter.
+type limit struct {
+	limit rate.Limit
+	burst int
+}
+
+// policyEngine provides the bandwidth policy engine.
+type policyEngine struct {
+	dynconfig config.Dynconfig
+	limiters  map[string]*rate.Limiter
+	defaults  map[string]limit
+	applied   map[string]limit
+	interval  time.Duration
+	done      chan struct{}
+}
+
+// Option is a functional option for configuring the policy engine.
+type Option func(p *policyEngine)
+
+// WithInterval sets the interval of applying the bandwidth policies.
+func WithInterval(interval time.Duration) Option {
+	return func(p *policyEngine) {
+		p.interval = interval
+	}
+}
+
+// New returns a new PolicyEngine interface, the limiters are indexed by the traffic class,
+// and their current limits are restored when no policy of the traffic class is active.
+func New(dynconfig config.Dynconfig, limiters map[string]*rate.Limiter, options ...Option) PolicyEngine {
+	p := &policyEngine{
+		dynconfig: dynconfig,
+		limiters:  limiters,
+		defaults:  make(map[string]limit, len(limiters)),
+		applied:   make(map[string]limit, len(limiters)),
+		interval:  DefaultInterval,
+		done:      make(chan struct{}),
+	}
+
+	for class, limiter := range limiters {
+		p.defaults[class] = limit{limit: limiter.Limit(), burst: limiter.Burst()}
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	return p
+}
+
+// Serve starts applying the bandwidth policies.
+func (p *policyEngine) Serve() {
+	p.apply(time.Now())
+
+	tick := time.NewTicker(p.interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			p.apply(time.Now())
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Stop stops applying the bandwidth policies.
+func (p *policyEngine) Stop() {
+	close(p.done)
+}
+
+// apply sets the limiters to the first active policy of the traffic class at the time,
+// the current limits are kept when the policies are not available.
+func (p *policyEngine) apply(t time.Time) {
+	policies, err := p.dynconfig.GetBandwidthPolicies()
+	if err != nil {
+		logger.Warnf("get bandwidth policies error: %s", err)
+		return
+	}
+
+	for class, limiter := range p.limiters {
+		l := p.defaults[class]
+		for _, policy := range policies {
+			if policy.TrafficClass != class {
+				continue
+			}
+
+			active, err := policy.Active(t)
+			if err != nil {
+				logger.Warnf("bandwidth policy of %s is invalid: %s", class, err)
+				continue
+			}
+
+			if !active {
+				continue
+			}
+
+			if policy.RateLimit == 0 {
+				l = limit{limit: rate.Inf, burst: l.burst}
+				break
+			}
+
+			// The burst size must be bigger than piece size.
+			l = limit{limit: rate.Limit(policy.RateLimit), burst: max(int(policy.RateLimit), util.DefaultPieceSizeLimit)}
+			break
+		}
+
+		// Compare with the applied limit instead of the limiter, because the limit
+		// of the limiter may be scaled by the host load.
+		if applied, ok := p.applied[class]; ok && applied == l {
+			continue
+		}
+
+		logger.Infof("set %s rate limit to %f with burst %d", class, l.limit, l.burst)
+		limiter.SetLimit(l.limit)
+		limiter.SetBurst(l.burst)
+		p.applied[class] = l
+	}
+}
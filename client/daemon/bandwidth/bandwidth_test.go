@@ -0,0 +1,156 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bandwidth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"d7y.io/dragonfly/v2/client/config/mocks"
+	"d7y.io/dragonfly/v2/internal/util"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestPolicyEngine_apply(t *testing.T) {
+	// Monday.
+	mockTime := time.Date(2023, 10, 16, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		mock   func(m *mocks.MockDynconfigMockRecorder)
+		expect func(t *testing.T, upload, backToSource *rate.Limiter)
+	}{
+		{
+			name: "policy is active in business hours",
+			now:  mockTime,
+			mock: func(m *mocks.MockDynconfigMockRecorder) {
+				m.GetBandwidthPolicies().Return([]types.BandwidthPolicy{
+					{TrafficClass: types.TrafficClassUpload, RateLimit: 1024, Start: "09:00", End: "18:00", Weekdays: []string{"Mon", "Tue"}, Timezone: "UTC"},
+					{TrafficClass: types.TrafficClassUpload, RateLimit: 2048, Timezone: "UTC"},
+				}, nil)
+			},
+			expect: func(t *testing.T, upload, backToSource *rate.Limiter) {
+				assert := assert.New(t)
+				assert.Equal(rate.Limit(1024), upload.Limit())
+				assert.Equal(util.DefaultPieceSizeLimit, upload.Burst())
+				assert.Equal(rate.Inf, backToSource.Limit())
+			},
+		},
+		{
+			name: "policy is not active out of business hours",
+			now:  mockTime.Add(10 * time.Hour),
+			mock: func(m *mocks.MockDynconfigMockRecorder) {
+				m.GetBandwidthPolicies().Return([]types.BandwidthPolicy{
+					{TrafficClass: types.TrafficClassUpload, RateLimit: 1024, Start: "09:00", End: "18:00", Timezone: "UTC"},
+				}, nil)
+			},
+			expect: func(t *testing.T, upload, backToSource *rate.Limiter) {
+				assert := assert.New(t)
+				assert.Equal(rate.Limit(100*1024*1024), upload.Limit())
+				assert.Equal(100*1024*1024, upload.Burst())
+			},
+		},
+		{
+			name: "policy is not active in other weekdays",
+			now:  mockTime,
+			mock: func(m *mocks.MockDynconfigMockRecorder) {
+				m.GetBandwidthPolicies().Return([]types.BandwidthPolicy{
+					{TrafficClass: types.TrafficClassUpload, RateLimit: 1024, Weekdays: []string{"Sat", "Sun"}, Timezone: "UTC"},
+				}, nil)
+			},
+			expect: func(t *testing.T, upload, backToSource *rate.Limiter) {
+				assert.Equal(t, rate.Limit(100*1024*1024), upload.Limit())
+			},
+		},
+		{
+			name: "policy crosses midnight",
+			now:  mockTime.Add(14 * time.Hour),
+			mock: func(m *mocks.MockDynconfigMockRecorder) {
+				m.GetBandwidthPolicies().Return([]types.BandwidthPolicy{
+					{TrafficClass: types.TrafficClassBackToSource, RateLimit: 1024, Start: "22:00", End: "06:00", Timezone: "UTC"},
+				}, nil)
+			},
+			expect: func(t *testing.T, upload, backToSource *rate.Limiter) {
+				assert := assert.New(t)
+				assert.Equal(rate.Limit(100*1024*1024), upload.Limit())
+				assert.Equal(rate.Limit(1024), backToSource.Limit())
+			},
+		},
+		{
+			name: "policy without rate limit",
+			now:  mockTime,
+			mock: func(m *mocks.MockDynconfigMockRecorder) {
+				m.GetBandwidthPolicies().Return([]types.BandwidthPolicy{
+					{TrafficClass: types.TrafficClassUpload},
+				}, nil)
+			},
+			expect: func(t *testing.T, upload, backToSource *rate.Limiter) {
+				assert.Equal(t, rate.Inf, upload.Limit())
+			},
+		},
+		{
+			name: "policy with invalid timezone is skipped",
+			now:  mockTime,
+			mock: func(m *mocks.MockDynconfigMockRecorder) {
+				m.GetBandwidthPolicies().Return([]types.BandwidthPolicy{
+					{TrafficClass: types.TrafficClassUpload, RateLimit: 1024, Timezone: "foo"},
+				}, nil)
+			},
+			expect: func(t *testing.T, upload, backToSource *rate.Limiter) {
+				assert.Equal(t, rate.Limit(100*1024*1024), upload.Limit())
+			},
+		},
+		{
+			name: "get bandwidth policies failed",
+			now:  mockTime,
+			mock: func(m *mocks.MockDynconfigMockRecorder) {
+				m.GetBandwidthPolicies().Return(nil, errors.New("foo"))
+			},
+			expect: func(t *testing.T, upload, backToSource *rate.Limiter) {
+				assert.Equal(t, rate.Limit(1), upload.Limit())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			dynconfig := mocks.NewMockDynconfig(ctl)
+			tc.mock(dynconfig.EXPECT())
+
+			upload := rate.NewLimiter(100*1024*1024, 100*1024*1024)
+			backToSource := rate.NewLimiter(rate.Inf, util.DefaultPieceSizeLimit)
+			p := New(dynconfig, map[string]*rate.Limiter{
+				types.TrafficClassUpload:       upload,
+				types.TrafficClassBackToSource: backToSource,
+			}).(*policyEngine)
+
+			// The limits changed by the previous policies are kept when the policies are not available.
+			upload.SetLimit(1)
+			p.apply(tc.now)
+			tc.expect(t, upload, backToSource)
+		})
+	}
+}
@@ -28,11 +28,13 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/johanbrandhorst/certify"
+	"github.com/soheilhy/cmux"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
@@ -45,25 +47,35 @@ import (
 
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/announcer"
+	"d7y.io/dragonfly/v2/client/daemon/bandwidth"
 	"d7y.io/dragonfly/v2/client/daemon/gc"
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
 	"d7y.io/dragonfly/v2/client/daemon/metrics"
 	"d7y.io/dragonfly/v2/client/daemon/networktopology"
 	"d7y.io/dragonfly/v2/client/daemon/objectstorage"
+	"d7y.io/dragonfly/v2/client/daemon/overflow"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
 	"d7y.io/dragonfly/v2/client/daemon/proxy"
+	"d7y.io/dragonfly/v2/client/daemon/registry"
+	"d7y.io/dragonfly/v2/client/daemon/reputation"
 	"d7y.io/dragonfly/v2/client/daemon/rpcserver"
+	"d7y.io/dragonfly/v2/client/daemon/signature"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/stream"
 	"d7y.io/dragonfly/v2/client/daemon/upload"
 	"d7y.io/dragonfly/v2/client/util"
 	"d7y.io/dragonfly/v2/cmd/dependency"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	internaldynconfig "d7y.io/dragonfly/v2/internal/dynconfig"
+	internalutil "d7y.io/dragonfly/v2/internal/util"
 	"d7y.io/dragonfly/v2/pkg/cache"
 	"d7y.io/dragonfly/v2/pkg/dfnet"
 	"d7y.io/dragonfly/v2/pkg/dfpath"
+	"d7y.io/dragonfly/v2/pkg/digest"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/issuer"
 	"d7y.io/dragonfly/v2/pkg/net/ip"
+	"d7y.io/dragonfly/v2/pkg/profiler"
 	"d7y.io/dragonfly/v2/pkg/rpc"
 	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
@@ -71,6 +83,7 @@ import (
 	"d7y.io/dragonfly/v2/pkg/source"
 	_ "d7y.io/dragonfly/v2/pkg/source/loader" // register all source clients
 	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/version"
 )
 
 type Daemon interface {
@@ -94,6 +107,8 @@ type clientDaemon struct {
 	RPCManager     rpcserver.Server
 	UploadManager  upload.Manager
 	ObjectStorage  objectstorage.ObjectStorage
+	Registry       registry.Registry
+	Stream         stream.Stream
 	ProxyManager   proxy.Manager
 	StorageManager storage.Manager
 	GCManager      gc.Manager
@@ -107,8 +122,14 @@ type clientDaemon struct {
 	securityClient  securityclient.V1
 	schedulerClient schedulerclient.V1
 	certifyClient   *certify.Certify
+	certReloader    *rpc.CertReloader
 	announcer       announcer.Announcer
 	networkTopology networktopology.NetworkTopology
+
+	uploadLimiter         *rate.Limiter
+	bandwidthPolicyEngine bandwidth.PolicyEngine
+	uploadAdaptive        bandwidth.Adaptive
+	parentTracker         reputation.Tracker
 }
 
 func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
@@ -142,12 +163,26 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		managerClient  managerclient.V1
 		securityClient securityclient.V1
 		certifyClient  *certify.Certify
+		certReloader   *rpc.CertReloader
 	)
 
+	// Initialize cert reloader, the rotated certificates take effect without restarting.
+	if !opt.Security.AutoIssueCert && opt.Security.CertFile != "" {
+		certReloader, err = rpc.NewCertReloader(opt.Security.CertFile, opt.Security.KeyFile, opt.Security.CAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if opt.Scheduler.Manager.Enable {
 		var grpcCredentials credentials.TransportCredentials
 
-		if opt.Security.CACert == "" {
+		if certReloader != nil {
+			grpcCredentials, err = rpc.NewClientCredentialsByCertReloader(opt.Security.TLSPolicy, certReloader)
+			if err != nil {
+				return nil, err
+			}
+		} else if opt.Security.CACert == "" {
 			grpcCredentials = insecure.NewCredentials()
 		} else {
 			grpcCredentials, err = loadManagerGPRCTLSCredentials(opt.Security)
@@ -156,15 +191,21 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 			}
 		}
 
-		managerClient, err = managerclient.GetV1ByNetAddrs(
-			context.Background(), opt.Scheduler.Manager.NetAddrs, grpc.WithTransportCredentials(grpcCredentials))
+		// Inject credentials into the calls of manager.
+		managerDialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(grpcCredentials)},
+			rpc.AuthDialOptions(rpc.NewAuthenticator(
+				rpc.WithAuthToken(opt.Scheduler.Manager.Auth.Token),
+				rpc.WithAuthHMACSecret(opt.Scheduler.Manager.Auth.HMACSecret),
+			))...)
+
+		managerClient, err = managerclient.GetV1ByNetAddrs(context.Background(), opt.Scheduler.Manager.NetAddrs, managerDialOptions...)
 		if err != nil {
 			return nil, err
 		}
 
 		if opt.Security.AutoIssueCert {
 			// Initialize security client.
-			securityClient, err = securityclient.GetV1ByAddr(context.Background(), opt.Scheduler.Manager.NetAddrs, grpc.WithTransportCredentials(grpcCredentials))
+			securityClient, err = securityclient.GetV1ByAddr(context.Background(), opt.Scheduler.Manager.NetAddrs, managerDialOptions...)
 			if err != nil {
 				return nil, err
 			}
@@ -195,13 +236,18 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 	}
 
 	var grpcCredentials credentials.TransportCredentials
-	if certifyClient == nil {
-		grpcCredentials = insecure.NewCredentials()
-	} else {
+	if certifyClient != nil {
 		grpcCredentials, err = loadGlobalGPRCTLSCredentials(certifyClient, opt.Security)
 		if err != nil {
 			return nil, err
 		}
+	} else if certReloader != nil {
+		grpcCredentials, err = rpc.NewClientCredentialsByCertReloader(opt.Security.TLSPolicy, certReloader)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		grpcCredentials = insecure.NewCredentials()
 	}
 
 	// New dynconfig manager client.
@@ -223,7 +269,14 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		}
 	}
 
-	schedulerClient, err := schedulerclient.GetV1(context.Background(), dynconfig, grpc.WithTransportCredentials(grpcCredentials))
+	// Inject credentials into the calls of scheduler.
+	schedulerDialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(grpcCredentials)},
+		rpc.AuthDialOptions(rpc.NewAuthenticator(
+			rpc.WithAuthToken(opt.Scheduler.Auth.Token),
+			rpc.WithAuthHMACSecret(opt.Scheduler.Auth.HMACSecret),
+		))...)
+
+	schedulerClient, err := schedulerclient.GetV1(context.Background(), dynconfig, schedulerDialOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schedulers: %w", err)
 	}
@@ -248,22 +301,117 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		return nil, err
 	}
 
+	var (
+		downloadLimiter = rate.NewLimiter(opt.Download.TotalRateLimit.Limit, int(opt.Download.TotalRateLimit.Limit))
+		uploadLimiter   = rate.NewLimiter(opt.Upload.RateLimit.Limit, int(opt.Upload.RateLimit.Limit))
+	)
+
 	pmOpts := []peer.PieceManagerOption{
-		peer.WithLimiter(rate.NewLimiter(opt.Download.TotalRateLimit.Limit, int(opt.Download.TotalRateLimit.Limit))),
+		peer.WithLimiter(downloadLimiter),
 		peer.WithCalculateDigest(opt.Download.CalculateDigest),
 		peer.WithTransportOption(opt.Download.Transport),
 		peer.WithConcurrentOption(opt.Download.Concurrent),
+		peer.WithQUIC(opt.Download.QUIC.Enable),
 	}
 
 	if opt.Download.SyncPieceViaHTTPS && opt.Scheduler.Manager.Enable {
 		pmOpts = append(pmOpts, peer.WithSyncPieceViaHTTPS(string(opt.Security.CACert)))
 	}
 
+	// Encrypt the pieces transferred between peers by the mode of the scheduler cluster,
+	// the certificates of the peers are issued by the manager ca.
+	var (
+		pieceEncryptionCertPool  *x509.CertPool
+		pieceEncryptionTLSConfig *tls.Config
+	)
+	if certifyClient != nil {
+		pieceEncryptionCertPool = x509.NewCertPool()
+		if !pieceEncryptionCertPool.AppendCertsFromPEM([]byte(opt.Security.CACert)) {
+			return nil, errors.New("failed to add global CA's certificate")
+		}
+
+		pieceEncryptionTLSConfig = &tls.Config{
+			RootCAs:              pieceEncryptionCertPool,
+			GetClientCertificate: certifyClient.GetClientCertificate,
+		}
+		pmOpts = append(pmOpts, peer.WithEncryption(pieceEncryptionTLSConfig, func() string {
+			return getPieceEncryption(dynconfig)
+		}))
+	} else if getPieceEncryption(dynconfig) == types.PieceEncryptionRequire {
+		// The daemon without the certificate issued by the manager ca can only serve the pieces
+		// over plain http, which is refused by the scheduler cluster requiring the encryption.
+		return nil, errors.New("piece encryption is required by scheduler cluster, but the certificate is not issued, enable security.autoIssueCert")
+	}
+
+	// The limits of the traffic classes are changed by the bandwidth policies from manager.
+	var bandwidthPolicyEngine bandwidth.PolicyEngine
+	if opt.Scheduler.Manager.Enable {
+		backToSourceLimiter := rate.NewLimiter(rate.Inf, internalutil.DefaultPieceSizeLimit)
+		pmOpts = append(pmOpts, peer.WithBackToSourceLimiter(backToSourceLimiter))
+		bandwidthPolicyEngine = bandwidth.New(dynconfig, map[string]*rate.Limiter{
+			types.TrafficClassDownload:     downloadLimiter,
+			types.TrafficClassUpload:       uploadLimiter,
+			types.TrafficClassBackToSource: backToSourceLimiter,
+		})
+	}
+
+	// The upload limit is backed off when the host is overloaded.
+	var uploadAdaptive bandwidth.Adaptive
+	if opt.Upload.Adaptive.Enable {
+		uploadAdaptive = bandwidth.NewAdaptive(opt.Upload.Adaptive, uploadLimiter)
+	}
+
+	if opt.Scheduler.Manager.Enable {
+		pmOpts = append(pmOpts, peer.WithPieceDigestAlgorithm(func() string {
+			algorithm, err := dynconfig.GetPieceDigestAlgorithm()
+			if err != nil {
+				logger.Warnf("get piece digest algorithm error: %s", err)
+				return digest.AlgorithmMD5
+			}
+
+			return algorithm
+		}))
+	}
+
 	pieceManager, err := peer.NewPieceManager(opt.Download.PieceDownloadTimeout, pmOpts...)
 	if err != nil {
 		return nil, err
 	}
 
+	// Verify the pieces against the merkle tree signed by the seed peer.
+	var pieceIntegrity integrity.Integrity
+	if opt.Download.Integrity.Enable {
+		// The merkle trees are fetched over tls the same as the pieces.
+		var integrityOpts []integrity.Option
+		if pieceEncryptionTLSConfig != nil {
+			integrityOpts = append(integrityOpts, integrity.WithTLSTransport(pieceEncryptionTLSConfig, func() string {
+				return getPieceEncryption(dynconfig)
+			}))
+		}
+
+		pieceIntegrity, err = integrity.New(opt.Download.Integrity, integrityOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		// The leaves of the merkle trees are the piece digests, so they must be collision resistant.
+		if algorithm, err := dynconfig.GetPieceDigestAlgorithm(); err == nil && !integrity.IsStrongPieceDigestAlgorithm(algorithm) {
+			return nil, fmt.Errorf("integrity requires piece digest algorithm sha256, sha512 or blake3, but scheduler cluster uses %s", algorithm)
+		}
+	}
+
+	// Track the piece results of the parents for their reputation in scheduler.
+	parentTracker := reputation.NewTracker()
+
+	// Write the completed seed tasks through to the overflow bucket.
+	var taskOverflow overflow.Overflow
+	if opt.Storage.Overflow.Enable {
+		taskOverflow, err = overflow.New(opt.Storage.Overflow)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	peerTaskManagerOption := &peer.TaskManagerOption{
 		TaskOption: peer.TaskOption{
 			PeerHost:        host,
@@ -271,18 +419,24 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 			PieceManager:    pieceManager,
 			StorageManager:  storageManager,
 			WatchdogTimeout: opt.Download.WatchdogTimeout,
+			PeerExchange:    opt.Download.PeerExchange,
 			CalculateDigest: opt.Download.CalculateDigest,
 			GRPCCredentials: grpcCredentials,
 			GRPCDialTimeout: opt.Download.GRPCDialTimeout,
+			Integrity:       pieceIntegrity,
+			ParentTracker:   parentTracker,
+			Overflow:        taskOverflow,
 		},
-		SchedulerClient:   schedulerClient,
-		PerPeerRateLimit:  opt.Download.PerPeerRateLimit.Limit,
-		TotalRateLimit:    opt.Download.TotalRateLimit.Limit,
-		TrafficShaperType: opt.Download.TrafficShaperType,
-		Multiplex:         opt.Storage.Multiplex,
-		Prefetch:          opt.Download.Prefetch,
-		GetPiecesMaxRetry: opt.Download.GetPiecesMaxRetry,
-		SplitRunningTasks: opt.Download.SplitRunningTasks,
+		SchedulerClient:        schedulerClient,
+		PerPeerRateLimit:       opt.Download.PerPeerRateLimit.Limit,
+		TotalRateLimit:         opt.Download.TotalRateLimit.Limit,
+		TrafficShaperType:      opt.Download.TrafficShaperType,
+		PieceSelectionStrategy: opt.Download.PieceSelectionStrategy,
+		Multiplex:              opt.Storage.Multiplex,
+		Prefetch:               opt.Download.Prefetch,
+		GetPiecesMaxRetry:      opt.Download.GetPiecesMaxRetry,
+		SplitRunningTasks:      opt.Download.SplitRunningTasks,
+		Dynconfig:              dynconfig,
 	}
 	peerTaskManager, err := peer.NewPeerTaskManager(peerTaskManagerOption)
 	if err != nil {
@@ -305,29 +459,48 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 			return nil, err
 		}
 		peerServerOption = append(peerServerOption, grpc.Creds(tlsCredentials))
+	} else if certReloader != nil {
+		tlsCredentials, err := rpc.NewServerCredentialsByCertReloader(opt.Security.TLSPolicy, opt.Security.TLSVerify, certReloader)
+		if err != nil {
+			return nil, err
+		}
+		peerServerOption = append(peerServerOption, grpc.Creds(tlsCredentials))
+	}
+
+	// Verify the signatures of the downloaded artifacts against the trusted keys.
+	signatureVerifier, err := signature.New(opt.Download.Signature)
+	if err != nil {
+		return nil, err
 	}
 
 	rpcManager, err := rpcserver.New(host, peerTaskManager, storageManager,
-		opt.Download.RecursiveConcurrent.GoroutineCount, opt.Download.CacheRecursiveMetadata, downloadServerOption, peerServerOption)
+		opt.Download.RecursiveConcurrent.GoroutineCount, opt.Download.CacheRecursiveMetadata, downloadServerOption, peerServerOption,
+		rpcserver.WithSignatureVerifier(signatureVerifier), rpcserver.WithDynconfig(dynconfig))
 	if err != nil {
 		return nil, err
 	}
 	// register notify for health check
 	dynconfig.Register(rpcManager)
 
-	proxyManager, err := proxy.NewProxyManager(host, peerTaskManager, opt.Proxy)
+	proxyManager, err := proxy.NewProxyManager(host, peerTaskManager, signatureVerifier, dynconfig, opt.Proxy)
 	if err != nil {
 		return nil, err
 	}
 
 	uploadOpts := []upload.Option{
-		upload.WithLimiter(rate.NewLimiter(opt.Upload.RateLimit.Limit, int(opt.Upload.RateLimit.Limit))),
+		upload.WithLimiter(uploadLimiter),
 	}
 
 	if opt.Security.AutoIssueCert && opt.Scheduler.Manager.Enable {
 		uploadOpts = append(uploadOpts, upload.WithCertify(certifyClient))
 	}
 
+	// The plain http uploads are refused when the encryption is required, even though the daemon
+	// without the certificate can not serve the pieces over tls after the mode is changed.
+	uploadOpts = append(uploadOpts, upload.WithEncryption(pieceEncryptionCertPool, func() bool {
+		return getPieceEncryption(dynconfig) == types.PieceEncryptionRequire
+	}))
+
 	uploadManager, err := upload.NewUploadManager(opt, storageManager, d.LogDir(), uploadOpts...)
 	if err != nil {
 		return nil, err
@@ -341,6 +514,19 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		}
 	}
 
+	var registryServer registry.Registry
+	if opt.Registry.Enable {
+		registryServer, err = registry.New(opt, peerTaskManager, d.LogDir())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var streamServer stream.Stream
+	if opt.Download.Stream.Enable {
+		streamServer = stream.New(opt, peerTaskManager, storageManager)
+	}
+
 	return &clientDaemon{
 		once:            &sync.Once{},
 		done:            make(chan bool),
@@ -352,6 +538,8 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		ProxyManager:    proxyManager,
 		UploadManager:   uploadManager,
 		ObjectStorage:   objectStorage,
+		Registry:        registryServer,
+		Stream:          streamServer,
 		StorageManager:  storageManager,
 		GCManager:       gc.NewManager(opt.GCInterval.Duration),
 		dynconfig:       dynconfig,
@@ -360,6 +548,12 @@ func New(opt *config.DaemonOption, d dfpath.Dfpath) (Daemon, error) {
 		securityClient:  securityClient,
 		schedulerClient: schedulerClient,
 		certifyClient:   certifyClient,
+		certReloader:    certReloader,
+
+		uploadLimiter:         uploadLimiter,
+		bandwidthPolicyEngine: bandwidthPolicyEngine,
+		uploadAdaptive:        uploadAdaptive,
+		parentTracker:         parentTracker,
 	}, nil
 }
 
@@ -454,6 +648,18 @@ func loadGPRCTLSCredentialsWithOptions(baseConfig *tls.Config, security config.G
 	}
 }
 
+// getPieceEncryption returns the encryption mode of the pieces transferred between peers,
+// the encryption is disabled when the mode is unavailable.
+func getPieceEncryption(dynconfig config.Dynconfig) string {
+	encryption, err := dynconfig.GetPieceEncryption()
+	if err != nil {
+		logger.Warnf("get piece encryption error: %s", err)
+		return types.PieceEncryptionDisable
+	}
+
+	return encryption
+}
+
 func (*clientDaemon) prepareTCPListener(opt config.ListenOption, withTLS bool) (net.Listener, int, error) {
 	if len(opt.TCPListen.Namespace) > 0 {
 		runtime.LockOSThread()
@@ -539,6 +745,17 @@ func (cd *clientDaemon) Serve() error {
 		return err
 	}
 
+	// share the download unix socket between the download grpc service and the streaming download api
+	var (
+		downloadMux    cmux.CMux
+		streamListener net.Listener
+	)
+	if cd.Option.Download.Stream.Enable {
+		downloadMux = cmux.New(downloadListener)
+		streamListener = downloadMux.Match(cmux.HTTP1Fast())
+		downloadListener = downloadMux.Match(cmux.Any())
+	}
+
 	// prepare peer service listen
 	if cd.Option.Download.PeerGRPC.TCPListen == nil {
 		return errors.New("peer grpc tcp listen option is empty")
@@ -561,6 +778,16 @@ func (cd *clientDaemon) Serve() error {
 	}
 	cd.schedPeerHost.DownPort = int32(uploadPort)
 
+	// prepare upload service listen over quic, the udp port is same as the upload port
+	var uploadPacketConn net.PacketConn
+	if cd.Option.Upload.QUIC.Enable {
+		uploadPacketConn, err = net.ListenPacket("udp", uploadListener.Addr().String())
+		if err != nil {
+			logger.Errorf("failed to listen for quic upload service: %v", err)
+			return err
+		}
+	}
+
 	// prepare object storage service listen
 	var (
 		objectStorageListener net.Listener
@@ -577,6 +804,32 @@ func (cd *clientDaemon) Serve() error {
 		}
 	}
 
+	// prepare object storage s3 gateway listen
+	var objectStorageS3Listener net.Listener
+	if cd.Option.ObjectStorage.Enable && cd.Option.ObjectStorage.S3.Enable {
+		if cd.Option.ObjectStorage.S3.TCPListen == nil {
+			return errors.New("object storage s3 gateway tcp listen option is empty")
+		}
+		objectStorageS3Listener, _, err = cd.prepareTCPListener(cd.Option.ObjectStorage.S3.ListenOption, true)
+		if err != nil {
+			logger.Errorf("failed to listen for object storage s3 gateway: %v", err)
+			return err
+		}
+	}
+
+	// prepare registry service listen
+	var registryListener net.Listener
+	if cd.Option.Registry.Enable {
+		if cd.Option.Registry.TCPListen == nil {
+			return errors.New("registry tcp listen option is empty")
+		}
+		registryListener, _, err = cd.prepareTCPListener(cd.Option.Registry.ListenOption, true)
+		if err != nil {
+			logger.Errorf("failed to listen for registry service: %v", err)
+			return err
+		}
+	}
+
 	g := errgroup.Group{}
 	// serve download grpc service
 	g.Go(func() error {
@@ -589,6 +842,27 @@ func (cd *clientDaemon) Serve() error {
 		return nil
 	})
 
+	// serve streaming download api
+	if cd.Option.Download.Stream.Enable {
+		g.Go(func() error {
+			defer streamListener.Close()
+			logger.Infof("serve streaming download api at unix://%s", cd.Option.Download.DownloadGRPC.UnixListen.Socket)
+			if err := cd.Stream.Serve(streamListener); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("failed to serve for streaming download api: %v", err)
+				return err
+			}
+			return nil
+		})
+
+		g.Go(func() error {
+			if err := downloadMux.Serve(); err != nil && !errors.Is(err, net.ErrClosed) {
+				logger.Errorf("failed to serve for download unix socket: %v", err)
+				return err
+			}
+			return nil
+		})
+	}
+
 	// serve peer grpc service
 	g.Go(func() error {
 		defer peerListener.Close()
@@ -643,6 +917,44 @@ func (cd *clientDaemon) Serve() error {
 				})
 			}
 		}
+		// serve transparent proxy service
+		if transparent := cd.Option.Proxy.Transparent; transparent != nil {
+			listener, err := proxy.ListenTransparent(transparent)
+			if err != nil {
+				logger.Errorf("failed to listen for transparent proxy service: %v", err)
+				return err
+			}
+			logger.Infof("serve transparent proxy with %q mode at tcp://%s", transparent.Mode, listener.Addr())
+
+			g.Go(func() error {
+				err := cd.ProxyManager.ServeTransparent(listener, transparent.Mode)
+				if err != nil && !errors.Is(err, net.ErrClosed) {
+					logger.Errorf("failed to serve transparent proxy service: %v", err)
+					return err
+				}
+				return nil
+			})
+		}
+		// serve socks5 proxy service
+		if socks5 := cd.Option.Proxy.Socks5; socks5 != nil {
+			listener, port, err := cd.prepareTCPListener(config.ListenOption{
+				TCPListen: socks5,
+			}, false)
+			if err != nil {
+				logger.Errorf("failed to listen for socks5 proxy service: %v", err)
+				return err
+			}
+			logger.Infof("serve socks5 proxy at tcp://%s:%d", socks5.Listen, port)
+
+			g.Go(func() error {
+				err := cd.ProxyManager.ServeSocks5(listener)
+				if err != nil && !errors.Is(err, net.ErrClosed) {
+					logger.Errorf("failed to serve socks5 proxy service: %v", err)
+					return err
+				}
+				return nil
+			})
+		}
 		watchers = append(watchers, func(daemon *config.DaemonOption) {
 			cd.ProxyManager.Watch(daemon.Proxy)
 		})
@@ -661,6 +973,19 @@ func (cd *clientDaemon) Serve() error {
 		return nil
 	})
 
+	// serve upload service over quic
+	if uploadPacketConn != nil {
+		g.Go(func() error {
+			defer uploadPacketConn.Close()
+			logger.Infof("serve quic upload service at %s://%s", uploadPacketConn.LocalAddr().Network(), uploadPacketConn.LocalAddr().String())
+			if err := cd.UploadManager.ServeQUIC(uploadPacketConn); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("failed to serve for quic upload service: %v", err)
+				return err
+			}
+			return nil
+		})
+	}
+
 	// serve object storage service
 	if cd.Option.ObjectStorage.Enable {
 		g.Go(func() error {
@@ -676,8 +1001,41 @@ func (cd *clientDaemon) Serve() error {
 		})
 	}
 
+	// serve object storage s3 gateway
+	if cd.Option.ObjectStorage.Enable && cd.Option.ObjectStorage.S3.Enable {
+		g.Go(func() error {
+			defer objectStorageS3Listener.Close()
+			logger.Infof("serve object storage s3 gateway at %s://%s", objectStorageS3Listener.Addr().Network(), objectStorageS3Listener.Addr().String())
+			if err := cd.ObjectStorage.ServeS3(objectStorageS3Listener); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("failed to serve for object storage s3 gateway: %v", err)
+				return err
+			} else if err == http.ErrServerClosed {
+				logger.Infof("object storage s3 gateway closed")
+			}
+			return nil
+		})
+	}
+
+	// serve registry service
+	if cd.Option.Registry.Enable {
+		g.Go(func() error {
+			defer registryListener.Close()
+			logger.Infof("serve registry service at %s://%s", registryListener.Addr().Network(), registryListener.Addr().String())
+			if err := cd.Registry.Serve(registryListener); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("failed to serve for registry service: %v", err)
+				return err
+			} else if err == http.ErrServerClosed {
+				logger.Infof("registry service closed")
+			}
+			return nil
+		})
+	}
+
 	// serve announcer
-	var announcerOptions []announcer.Option
+	announcerOptions := []announcer.Option{
+		announcer.WithUploadLimiter(cd.uploadLimiter),
+		announcer.WithParentTracker(cd.parentTracker),
+	}
 	if cd.managerClient != nil {
 		announcerOptions = append(announcerOptions, announcer.WithManagerClient(cd.managerClient))
 	}
@@ -708,6 +1066,24 @@ func (cd *clientDaemon) Serve() error {
 		go cd.networkTopology.Serve()
 	}
 
+	// serve cert reloader
+	if cd.certReloader != nil {
+		logger.Infof("serve cert reloader")
+		go cd.certReloader.Serve()
+	}
+
+	// serve bandwidth policy engine
+	if cd.bandwidthPolicyEngine != nil {
+		logger.Infof("serve bandwidth policy engine")
+		go cd.bandwidthPolicyEngine.Serve()
+	}
+
+	// serve adaptive upload rate limit
+	if cd.uploadAdaptive != nil {
+		logger.Infof("serve adaptive upload rate limit")
+		go cd.uploadAdaptive.Serve()
+	}
+
 	if cd.Option.AliveTime.Duration > 0 {
 		g.Go(func() error {
 			for {
@@ -754,7 +1130,8 @@ func (cd *clientDaemon) Serve() error {
 	}
 
 	if cd.Option.Metrics != "" {
-		metricsServer := metrics.New(cd.Option.Metrics)
+		// Pin or unpin the tasks in storage at runtime.
+		metricsServer := metrics.New(cd.Option.Metrics, metrics.WithHandler("/admin/storage/pins", storage.PinHandler(cd.StorageManager)))
 		go func() {
 			logger.Infof("started metrics server at %s", metricsServer.Addr)
 			if err := metricsServer.ListenAndServe(); err != nil {
@@ -766,6 +1143,25 @@ func (cd *clientDaemon) Serve() error {
 		}()
 	}
 
+	if cd.Option.Profiling.Enable {
+		labels := map[string]string{
+			"component": types.DaemonName,
+			"version":   version.GitVersion,
+			"hostname":  cd.Option.Host.Hostname,
+		}
+		if cd.Option.Scheduler.Manager.SeedPeer.Enable {
+			labels["cluster"] = strconv.FormatUint(uint64(cd.Option.Scheduler.Manager.SeedPeer.ClusterID), 10)
+		}
+
+		p := profiler.New(cd.Option.Profiling.Addr, fmt.Sprintf("dragonfly-%s", types.DaemonName),
+			profiler.WithInterval(cd.Option.Profiling.Interval), profiler.WithLabels(labels))
+		go p.Serve()
+		go func() {
+			<-cd.done
+			p.Stop()
+		}()
+	}
+
 	if cd.Option.Health != nil {
 		if cd.Option.Health.ListenOption.TCPListen == nil {
 			logger.Fatalf("health listen not found")
@@ -844,6 +1240,18 @@ func (cd *clientDaemon) Stop() {
 			}
 		}
 
+		if cd.Option.Registry.Enable {
+			if err := cd.Registry.Stop(); err != nil {
+				logger.Errorf("registry stop failed %s", err)
+			}
+		}
+
+		if cd.Option.Download.Stream.Enable {
+			if err := cd.Stream.Stop(); err != nil {
+				logger.Errorf("stream server stop failed %s", err)
+			}
+		}
+
 		if cd.ProxyManager.IsEnabled() {
 			if err := cd.ProxyManager.Stop(); err != nil {
 				logger.Errorf("proxy manager stop failed %s", err)
@@ -861,6 +1269,18 @@ func (cd *clientDaemon) Stop() {
 
 		cd.networkTopology.Stop()
 
+		if cd.bandwidthPolicyEngine != nil {
+			cd.bandwidthPolicyEngine.Stop()
+		}
+
+		if cd.uploadAdaptive != nil {
+			cd.uploadAdaptive.Stop()
+		}
+
+		if cd.certReloader != nil {
+			cd.certReloader.Stop()
+		}
+
 		if err := cd.dynconfig.Stop(); err != nil {
 			logger.Errorf("dynconfig client closed failed %s", err)
 		} else {
@@ -0,0 +1,363 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/integrity_mock.go -source integrity.go -package mocks
+
+package integrity
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// TaskMetaMerkleTree is the key of the signed merkle tree in the task meta of the storage.
+	TaskMetaMerkleTree = "merkleTree"
+
+	// fetchTimeout is the timeout of fetching the merkle tree from the parent.
+	fetchTimeout = 10 * time.Second
+)
+
+var (
+	// ErrSignDisabled is returned when the private key is not configured.
+	ErrSignDisabled = errors.New("merkle tree signing is disabled")
+
+	// ErrMerkleTreeNotFound is returned when the parent has not got the merkle tree of the task yet,
+	// e.g. the seed peer is still downloading the task from the origin.
+	ErrMerkleTreeNotFound = errors.New("merkle tree not found")
+
+	// ErrInvalidSignature is returned when the merkle tree is not signed by the trusted keys.
+	ErrInvalidSignature = errors.New("invalid merkle tree signature")
+
+	// ErrWeakPieceDigest is returned when the piece digests are not collision resistant.
+	ErrWeakPieceDigest = errors.New("piece digest algorithm must be sha256, sha512 or blake3")
+)
+
+// IsStrongPieceDigestAlgorithm returns whether the piece digest algorithm is collision resistant,
+// the leaves of the merkle tree are the piece digests, so the weak digests can be forged.
+func IsStrongPieceDigestAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case digest.AlgorithmSHA256, digest.AlgorithmSHA512, digest.AlgorithmBLAKE3:
+		return true
+	default:
+		return false
+	}
+}
+
+// Integrity signs and verifies the merkle trees over the piece digests of the tasks.
+type Integrity interface {
+	// CanSign returns whether the private key is configured.
+	CanSign() bool
+
+	// Sign builds the merkle tree over the piece digests and signs it.
+	Sign(taskID string, pieceDigests []string) (*types.SignedMerkleTree, error)
+
+	// Verify verifies the signature of the merkle tree and returns the rebuilt tree.
+	Verify(taskID string, tree *types.SignedMerkleTree) (*digest.MerkleTree, error)
+
+	// Fetch fetches the signed merkle tree of the task from the upload server of the parent.
+	Fetch(ctx context.Context, addr, taskID, peerID string) (*types.SignedMerkleTree, error)
+}
+
+// integrity provides the merkle tree signing and verifying.
+type integrity struct {
+	privateKey ed25519.PrivateKey
+	publicKeys []ed25519.PublicKey
+	client     *http.Client
+
+	// encryption returns the encryption mode of the pieces, it is nil when the encryption is disabled.
+	encryption func() string
+}
+
+// Option is a functional option for configuring the integrity.
+type Option func(i *integrity)
+
+// WithTLSTransport fetches the merkle trees over tls the same as the pieces, when the
+// encryption is preferred or required.
+func WithTLSTransport(tlsConfig *tls.Config, encryption func() string) Option {
+	return func(i *integrity) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		i.client.Transport = transport
+		i.encryption = encryption
+	}
+}
+
+// New returns a new Integrity instance.
+func New(cfg config.IntegrityOption, options ...Option) (Integrity, error) {
+	i := &integrity{
+		client: &http.Client{Timeout: fetchTimeout},
+	}
+
+	for _, opt := range options {
+		opt(i)
+	}
+
+	if cfg.PrivateKey != "" {
+		privateKey, err := loadPrivateKey(cfg.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		i.privateKey = privateKey
+		i.publicKeys = append(i.publicKeys, privateKey.Public().(ed25519.PublicKey))
+	}
+
+	for _, path := range cfg.PublicKeys {
+		publicKey, err := loadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		i.publicKeys = append(i.publicKeys, publicKey)
+	}
+
+	return i, nil
+}
+
+// CanSign returns whether the private key is configured.
+func (i *integrity) CanSign() bool {
+	return i.privateKey != nil
+}
+
+// Sign builds the merkle tree over the piece digests and signs it.
+func (i *integrity) Sign(taskID string, pieceDigests []string) (*types.SignedMerkleTree, error) {
+	if i.privateKey == nil {
+		return nil, ErrSignDisabled
+	}
+
+	if err := checkPieceDigests(pieceDigests); err != nil {
+		return nil, err
+	}
+
+	tree, err := digest.NewMerkleTree(pieceDigests)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := ed25519.Sign(i.privateKey, signedMessage(taskID, len(pieceDigests), tree.Root()))
+	return &types.SignedMerkleTree{
+		TaskID:    taskID,
+		Leaves:    tree.Leaves(),
+		Root:      tree.Root(),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+	}, nil
+}
+
+// Verify verifies the signature of the merkle tree and returns the rebuilt tree.
+func (i *integrity) Verify(taskID string, signed *types.SignedMerkleTree) (*digest.MerkleTree, error) {
+	if signed.TaskID != taskID {
+		return nil, fmt.Errorf("merkle tree of task %s, expected task %s", signed.TaskID, taskID)
+	}
+
+	if err := checkPieceDigests(signed.Leaves); err != nil {
+		return nil, err
+	}
+
+	tree, err := digest.NewMerkleTree(signed.Leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	if tree.Root() != signed.Root {
+		return nil, fmt.Errorf("merkle tree root mismatch, expected %s, actual %s", signed.Root, tree.Root())
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	message := signedMessage(taskID, len(signed.Leaves), signed.Root)
+	for _, publicKey := range i.publicKeys {
+		if ed25519.Verify(publicKey, message, signature) {
+			return tree, nil
+		}
+	}
+
+	return nil, ErrInvalidSignature
+}
+
+// Fetch fetches the signed merkle tree of the task from the upload server of the parent.
+func (i *integrity) Fetch(ctx context.Context, addr, taskID, peerID string) (*types.SignedMerkleTree, error) {
+	var encryption string
+	if i.encryption != nil {
+		encryption = i.encryption()
+	}
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	switch encryption {
+	case types.PieceEncryptionRequire:
+		resp, err = i.get(ctx, "https", addr, taskID, peerID)
+	case types.PieceEncryptionPrefer:
+		// Fall back to plain http for the parents not serving tls yet.
+		if resp, err = i.get(ctx, "https", addr, taskID, peerID); err != nil && nethttp.IsTLSHandshakeError(err) {
+			resp, err = i.get(ctx, "http", addr, taskID, peerID)
+		}
+	default:
+		resp, err = i.get(ctx, "http", addr, taskID, peerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrMerkleTreeNotFound
+	default:
+		return nil, fmt.Errorf("fetch merkle tree from %s failed, status code: %d", addr, resp.StatusCode)
+	}
+
+	var tree types.SignedMerkleTree
+	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	return &tree, nil
+}
+
+// get requests the signed merkle tree of the task from the upload server of the parent.
+func (i *integrity) get(ctx context.Context, scheme, addr, taskID, peerID string) (*http.Response, error) {
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     addr,
+		Path:     fmt.Sprintf("%s/%s", types.UploadMerkleTreePath, taskID),
+		RawQuery: url.Values{"peerId": []string{peerID}}.Encode(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.client.Do(req)
+}
+
+// Encode encodes the signed merkle tree for the task meta of the storage.
+func Encode(tree *types.SignedMerkleTree) (string, error) {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Decode decodes the signed merkle tree from the task meta of the storage.
+func Decode(data string) (*types.SignedMerkleTree, error) {
+	var tree types.SignedMerkleTree
+	if err := json.Unmarshal([]byte(data), &tree); err != nil {
+		return nil, err
+	}
+
+	return &tree, nil
+}
+
+// checkPieceDigests checks the piece digests are digested by the collision resistant algorithms.
+func checkPieceDigests(pieceDigests []string) error {
+	for _, pieceDigest := range pieceDigests {
+		d, err := digest.ParsePieceDigest(pieceDigest)
+		if err != nil {
+			return err
+		}
+
+		if !IsStrongPieceDigestAlgorithm(d.Algorithm) {
+			return fmt.Errorf("piece digest %s: %w", pieceDigest, ErrWeakPieceDigest)
+		}
+	}
+
+	return nil
+}
+
+// signedMessage binds the root to the task and the leaf count.
+func signedMessage(taskID string, count int, root string) []byte {
+	return []byte(fmt.Sprintf("%s:%d:%s", taskID, count, root))
+}
+
+// loadPrivateKey loads the pkcs8 pem encoded ed25519 private key.
+func loadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an ed25519 key", path)
+	}
+
+	return privateKey, nil
+}
+
+// loadPublicKey loads the pkix pem encoded ed25519 public key.
+func loadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an ed25519 key", path)
+	}
+
+	return publicKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no pem block found in %s", path)
+	}
+
+	return block, nil
+}
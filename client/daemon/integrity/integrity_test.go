@@ -0,0 +1,306 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package integrity
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func writeKeys(t *testing.T) (string, string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "integrity.key")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	publicPath := filepath.Join(dir, "integrity.pub")
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return privatePath, publicPath
+}
+
+func mockPieceDigest(s string) string {
+	return digest.AlgorithmBLAKE3 + ":" + strings.Repeat(s, 64)
+}
+
+func TestIntegrity_SignAndVerify(t *testing.T) {
+	privatePath, publicPath := writeKeys(t)
+	_, otherPublicPath := writeKeys(t)
+	pieceDigests := []string{mockPieceDigest("1"), mockPieceDigest("2"), mockPieceDigest("3")}
+
+	signer, err := New(config.IntegrityOption{Enable: true, PrivateKey: privatePath})
+	assert.Nil(t, err)
+	assert.True(t, signer.CanSign())
+
+	signed, err := signer.Sign("foo", pieceDigests)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name   string
+		cfg    config.IntegrityOption
+		taskID string
+		mock   func(tree *types.SignedMerkleTree)
+		expect func(t *testing.T, err error)
+	}{
+		{
+			name:   "trusted public key",
+			cfg:    config.IntegrityOption{Enable: true, PublicKeys: []string{publicPath}},
+			taskID: "foo",
+			mock:   func(tree *types.SignedMerkleTree) {},
+			expect: func(t *testing.T, err error) {
+				assert.Nil(t, err)
+			},
+		},
+		{
+			name:   "untrusted public key",
+			cfg:    config.IntegrityOption{Enable: true, PublicKeys: []string{otherPublicPath}},
+			taskID: "foo",
+			mock:   func(tree *types.SignedMerkleTree) {},
+			expect: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name:   "tree of other task",
+			cfg:    config.IntegrityOption{Enable: true, PublicKeys: []string{publicPath}},
+			taskID: "bar",
+			mock:   func(tree *types.SignedMerkleTree) {},
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "merkle tree of task foo, expected task bar")
+			},
+		},
+		{
+			name:   "forged leaves",
+			cfg:    config.IntegrityOption{Enable: true, PublicKeys: []string{publicPath}},
+			taskID: "foo",
+			mock: func(tree *types.SignedMerkleTree) {
+				tree.Leaves = []string{mockPieceDigest("1"), mockPieceDigest("2"), mockPieceDigest("4")}
+			},
+			expect: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "merkle tree root mismatch")
+			},
+		},
+		{
+			name:   "weak piece digests",
+			cfg:    config.IntegrityOption{Enable: true, PublicKeys: []string{publicPath}},
+			taskID: "foo",
+			mock: func(tree *types.SignedMerkleTree) {
+				tree.Leaves = []string{digest.AlgorithmMD5 + ":" + strings.Repeat("1", 32)}
+			},
+			expect: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrWeakPieceDigest)
+			},
+		},
+		{
+			name:   "forged root",
+			cfg:    config.IntegrityOption{Enable: true, PublicKeys: []string{publicPath}},
+			taskID: "foo",
+			mock: func(tree *types.SignedMerkleTree) {
+				tree.Leaves = []string{mockPieceDigest("1"), mockPieceDigest("2"), mockPieceDigest("4")}
+				tree.Root = "1e6e1c2e8ab8c1e3c0c1e4b2a1e3c0c1e4b2a1e3c0c1e4b2a1e3c0c1e4b2a1e3"
+			},
+			expect: func(t *testing.T, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier, err := New(tc.cfg)
+			assert.Nil(t, err)
+			assert.False(t, verifier.CanSign())
+
+			tree := *signed
+			tree.Leaves = append([]string(nil), signed.Leaves...)
+			tc.mock(&tree)
+
+			_, err = verifier.Verify(tc.taskID, &tree)
+			tc.expect(t, err)
+		})
+	}
+}
+
+func TestIntegrity_SignDisabled(t *testing.T) {
+	_, publicPath := writeKeys(t)
+	i, err := New(config.IntegrityOption{Enable: true, PublicKeys: []string{publicPath}})
+	assert.Nil(t, err)
+
+	_, err = i.Sign("foo", []string{"md5:1"})
+	assert.ErrorIs(t, err, ErrSignDisabled)
+
+	_, err = New(config.IntegrityOption{Enable: true, PublicKeys: []string{"/not/exist"}})
+	assert.Error(t, err)
+}
+
+func TestIntegrity_SignWeakPieceDigests(t *testing.T) {
+	privatePath, _ := writeKeys(t)
+	i, err := New(config.IntegrityOption{Enable: true, PrivateKey: privatePath})
+	assert.Nil(t, err)
+
+	_, err = i.Sign("foo", []string{digest.AlgorithmXXH3 + ":" + strings.Repeat("1", 16)})
+	assert.ErrorIs(t, err, ErrWeakPieceDigest)
+
+	_, err = i.Sign("foo", []string{digest.AlgorithmMD5 + ":" + strings.Repeat("1", 32)})
+	assert.ErrorIs(t, err, ErrWeakPieceDigest)
+}
+
+func TestIntegrity_Fetch(t *testing.T) {
+	privatePath, _ := writeKeys(t)
+	i, err := New(config.IntegrityOption{Enable: true, PrivateKey: privatePath})
+	assert.Nil(t, err)
+
+	signed, err := i.Sign("foo", []string{mockPieceDigest("1"), mockPieceDigest("2")})
+	assert.Nil(t, err)
+
+	encoded, err := Encode(signed)
+	assert.Nil(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != types.UploadMerkleTreePath+"/foo" || r.URL.Query().Get("peerId") != "peer" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(encoded))
+	}))
+	defer server.Close()
+
+	addr := server.Listener.Addr().String()
+	tree, err := i.Fetch(context.Background(), addr, "foo", "peer")
+	assert.Nil(t, err)
+	assert.Equal(t, signed, tree)
+
+	_, err = i.Verify("foo", tree)
+	assert.Nil(t, err)
+
+	_, err = i.Fetch(context.Background(), addr, "bar", "peer")
+	assert.ErrorIs(t, err, ErrMerkleTreeNotFound)
+
+	decoded, err := Decode(encoded)
+	assert.Nil(t, err)
+	assert.Equal(t, signed, decoded)
+}
+
+func TestIntegrity_FetchWithTLSTransport(t *testing.T) {
+	privatePath, _ := writeKeys(t)
+	signer, err := New(config.IntegrityOption{Enable: true, PrivateKey: privatePath})
+	assert.Nil(t, err)
+
+	signed, err := signer.Sign("foo", []string{mockPieceDigest("1")})
+	assert.Nil(t, err)
+
+	encoded, err := Encode(signed)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name       string
+		encryption string
+		tls        bool
+		expect     func(t *testing.T, tree *types.SignedMerkleTree, err error)
+	}{
+		{
+			name:       "fetch merkle tree over tls",
+			encryption: types.PieceEncryptionRequire,
+			tls:        true,
+			expect: func(t *testing.T, tree *types.SignedMerkleTree, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, signed, tree)
+			},
+		},
+		{
+			name:       "prefer encryption falls back to plain http",
+			encryption: types.PieceEncryptionPrefer,
+			tls:        false,
+			expect: func(t *testing.T, tree *types.SignedMerkleTree, err error) {
+				assert.Nil(t, err)
+				assert.Equal(t, signed, tree)
+			},
+		},
+		{
+			name:       "require encryption does not fall back to plain http",
+			encryption: types.PieceEncryptionRequire,
+			tls:        false,
+			expect: func(t *testing.T, tree *types.SignedMerkleTree, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(encoded))
+			})
+
+			var server *httptest.Server
+			if tc.tls {
+				server = httptest.NewTLSServer(handler)
+			} else {
+				server = httptest.NewServer(handler)
+			}
+			defer server.Close()
+
+			certPool := x509.NewCertPool()
+			if tc.tls {
+				certPool.AddCert(server.Certificate())
+			}
+
+			i, err := New(config.IntegrityOption{Enable: true, PrivateKey: privatePath}, WithTLSTransport(&tls.Config{RootCAs: certPool}, func() string {
+				return tc.encryption
+			}))
+			assert.Nil(t, err)
+
+			tree, err := i.Fetch(context.Background(), server.Listener.Addr().String(), "foo", "peer")
+			tc.expect(t, tree, err)
+		})
+	}
+}
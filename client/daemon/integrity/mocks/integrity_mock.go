@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: integrity.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	digest "d7y.io/dragonfly/v2/pkg/digest"
+	types "d7y.io/dragonfly/v2/pkg/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIntegrity is a mock of Integrity interface.
+type MockIntegrity struct {
+	ctrl     *gomock.Controller
+	recorder *MockIntegrityMockRecorder
+}
+
+// MockIntegrityMockRecorder is the mock recorder for MockIntegrity.
+type MockIntegrityMockRecorder struct {
+	mock *MockIntegrity
+}
+
+// NewMockIntegrity creates a new mock instance.
+func NewMockIntegrity(ctrl *gomock.Controller) *MockIntegrity {
+	mock := &MockIntegrity{ctrl: ctrl}
+	mock.recorder = &MockIntegrityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIntegrity) EXPECT() *MockIntegrityMockRecorder {
+	return m.recorder
+}
+
+// CanSign mocks base method.
+func (m *MockIntegrity) CanSign() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanSign")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CanSign indicates an expected call of CanSign.
+func (mr *MockIntegrityMockRecorder) CanSign() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSign", reflect.TypeOf((*MockIntegrity)(nil).CanSign))
+}
+
+// Fetch mocks base method.
+func (m *MockIntegrity) Fetch(ctx context.Context, addr, taskID, peerID string) (*types.SignedMerkleTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, addr, taskID, peerID)
+	ret0, _ := ret[0].(*types.SignedMerkleTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockIntegrityMockRecorder) Fetch(ctx, addr, taskID, peerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockIntegrity)(nil).Fetch), ctx, addr, taskID, peerID)
+}
+
+// Sign mocks base method.
+func (m *MockIntegrity) Sign(taskID string, pieceDigests []string) (*types.SignedMerkleTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", taskID, pieceDigests)
+	ret0, _ := ret[0].(*types.SignedMerkleTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockIntegrityMockRecorder) Sign(taskID, pieceDigests interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockIntegrity)(nil).Sign), taskID, pieceDigests)
+}
+
+// Verify mocks base method.
+func (m *MockIntegrity) Verify(taskID string, tree *types.SignedMerkleTree) (*digest.MerkleTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", taskID, tree)
+	ret0, _ := ret[0].(*digest.MerkleTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockIntegrityMockRecorder) Verify(taskID, tree interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockIntegrity)(nil).Verify), taskID, tree)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/version"
 )
@@ -44,6 +45,81 @@ const (
 	SeedPeerDownloadTypeBackToSource = "back_to_source"
 )
 
+const (
+	// Peer task traffic type is downloaded from normal peers.
+	PeerTaskTrafficTypeP2P = "p2p"
+
+	// Peer task traffic type is downloaded from seed peers.
+	PeerTaskTrafficTypeSeedPeer = "seed_peer"
+
+	// Peer task traffic type is downloaded from source.
+	PeerTaskTrafficTypeBackToSource = "back_to_source"
+)
+
+const (
+	// Back-to-source reason is the task is downloaded by seed peer.
+	BackToSourceReasonSeedPeer = "seed_peer"
+
+	// Back-to-source reason is registering to scheduler failed.
+	BackToSourceReasonRegisterFailed = "register_failed"
+
+	// Back-to-source reason is the scheduler requires back-to-source.
+	BackToSourceReasonScheduler = "scheduler"
+
+	// Back-to-source reason is waiting for the first peer packet from scheduler timeout.
+	BackToSourceReasonScheduleTimeout = "schedule_timeout"
+)
+
+const (
+	// Storage eviction reason is the task is expired.
+	StorageEvictReasonExpired = "expired"
+
+	// Storage eviction reason is the usage of the quota exceeds the high watermark.
+	StorageEvictReasonQuota = "quota"
+
+	// Storage eviction reason is the disk usage exceeds the gc threshold.
+	StorageEvictReasonThreshold = "threshold"
+)
+
+const (
+	// Storage tier is the memory cache.
+	StorageTierMemory = "memory"
+
+	// Storage tier is the ssd cache.
+	StorageTierSSD = "ssd"
+
+	// Storage tier is the data path on hdd.
+	StorageTierHDD = "hdd"
+)
+
+// taskSizeLevels are the upper bounds of the task size levels.
+var taskSizeLevels = []struct {
+	size  int64
+	level string
+}{
+	{1 << 20, "lt_1mib"},
+	{10 << 20, "lt_10mib"},
+	{100 << 20, "lt_100mib"},
+	{1 << 30, "lt_1gib"},
+	{10 << 30, "lt_10gib"},
+}
+
+// TaskSizeLevel returns the level of the task size for the metric labels,
+// the level is unknown if the content length is unknown.
+func TaskSizeLevel(contentLength int64) string {
+	if contentLength < 0 {
+		return "unknown"
+	}
+
+	for _, taskSizeLevel := range taskSizeLevels {
+		if contentLength < taskSizeLevel.size {
+			return taskSizeLevel.level
+		}
+	}
+
+	return "ge_10gib"
+}
+
 // Variables declared for metrics.
 var (
 	ProxyRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
@@ -81,6 +157,48 @@ var (
 		Help:      "Counter of the total byte of all proxy request.",
 	}, []string{"method"})
 
+	ProxyRangeCoalescedCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "proxy_range_coalesced_total",
+		Help:      "Counter of the total range requests coalesced into blocks.",
+	})
+
+	ProxyRangePrefetchCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "proxy_range_prefetch_total",
+		Help:      "Counter of the total blocks, prioritized files and sequential ranges prefetched for range requests.",
+	})
+
+	ProxyEstargzLayerCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "proxy_estargz_layer_total",
+		Help:      "Counter of the total layers whose chunk map is learned, labeled with estargz and other.",
+	}, []string{"result"})
+
+	ProxyLeafCertCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "proxy_leaf_cert_total",
+		Help:      "Counter of the total leaf certificates used to hijack https, labeled with hit, generated and failed.",
+	}, []string{"result"})
+
+	ProxyRegistryUpstreamRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "proxy_registry_upstream_request_total",
+		Help:      "Counter of the total requests to registry mirror upstreams, labeled with served, failover and failed.",
+	}, []string{"upstream", "result"})
+
+	ProxyRegistryUpstreamHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "proxy_registry_upstream_healthy",
+		Help:      "Gauge of the health of registry mirror upstreams, 1 is healthy and 0 is unhealthy.",
+	}, []string{"upstream"})
+
 	PeerTaskCount = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: types.MetricsNamespace,
 		Subsystem: types.DfdaemonMetricsName,
@@ -109,6 +227,13 @@ var (
 		Help:      "Counter of the total failed piece tasks.",
 	})
 
+	PieceTaskCorruptedCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "piece_task_corrupted_total",
+		Help:      "Counter of the failed piece tasks whose data or digest is corrupted by the parents.",
+	})
+
 	FileTaskCount = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: types.MetricsNamespace,
 		Subsystem: types.DfdaemonMetricsName,
@@ -158,6 +283,20 @@ var (
 		Help:      "Counter of the total cache hit peer tasks.",
 	})
 
+	URLPolicyDeniedCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "url_policy_denied_total",
+		Help:      "Counter of the total downloads denied by the url policy.",
+	})
+
+	PeerTaskResumeCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "peer_task_resume_total",
+		Help:      "Counter of the total peer tasks resumed from the written pieces after restart.",
+	})
+
 	PrefetchTaskCount = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: types.MetricsNamespace,
 		Subsystem: types.DfdaemonMetricsName,
@@ -165,6 +304,125 @@ var (
 		Help:      "Counter of the total prefetched tasks.",
 	})
 
+	PeerTaskTraffic = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "peer_task_traffic",
+		Help:      "Counter of the number of peer task traffic.",
+	}, []string{"type", "application", "task_size_level"})
+
+	PeerTaskPieceRetryCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "peer_task_piece_retry_total",
+		Help:      "Counter of the number of the retried pieces of peer task.",
+	}, []string{"application", "task_size_level"})
+
+	PeerTaskBackToSourceCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "peer_task_back_to_source_total",
+		Help:      "Counter of the number of the back-to-source peer tasks.",
+	}, []string{"reason", "application"})
+
+	StorageEvictedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_evicted_bytes_total",
+		Help:      "Counter of the bytes of the evicted tasks in storage.",
+	}, []string{"reason"})
+
+	StorageEvictedTaskCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_evicted_task_total",
+		Help:      "Counter of the number of the evicted tasks in storage.",
+	}, []string{"reason"})
+
+	StorageQuotaUsedBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_quota_used_bytes",
+		Help:      "Gauge of the used bytes of the storage quota.",
+	})
+
+	StoragePinnedTaskGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_pinned_task_total",
+		Help:      "Gauge of the number of the pinned tasks in storage.",
+	})
+
+	StorageTierHitCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_tier_hit_total",
+		Help:      "Counter of the number of the pieces read from the storage tier.",
+	}, []string{"tier"})
+
+	StorageTierUsedBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_tier_used_bytes",
+		Help:      "Gauge of the used bytes of the storage tier.",
+	}, []string{"tier"})
+
+	StorageTierPromotedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_tier_promoted_total",
+		Help:      "Counter of the number of the pieces promoted to the storage tier.",
+	}, []string{"tier"})
+
+	StorageTierDemotedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_tier_demoted_total",
+		Help:      "Counter of the number of the pieces demoted from the storage tier.",
+	}, []string{"tier"})
+
+	StorageDedupBytesCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_dedup_bytes_total",
+		Help:      "Counter of the bytes of the pieces deduplicated in storage.",
+	})
+
+	StorageDedupPieceGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_dedup_piece_total",
+		Help:      "Gauge of the number of the unique pieces in the dedup index.",
+	})
+
+	StorageReservedBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_reserved_bytes",
+		Help:      "Gauge of the reserved bytes of the unallocated tasks in storage.",
+	})
+
+	StorageReservationRejectedCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_reservation_rejected_total",
+		Help:      "Counter of the number of the tasks rejected by the storage reservation.",
+	})
+
+	StorageOverflowWrittenCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_overflow_written_total",
+		Help:      "Counter of the number of the tasks written through to the overflow bucket.",
+	})
+
+	StorageOverflowRestoredCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.DfdaemonMetricsName,
+		Name:      "storage_overflow_restored_total",
+		Help:      "Counter of the number of the tasks restored from the overflow bucket.",
+	})
+
 	VersionGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: types.MetricsNamespace,
 		Subsystem: types.DfdaemonMetricsName,
@@ -173,9 +431,29 @@ var (
 	}, []string{"major", "minor", "git_version", "git_commit", "platform", "build_time", "go_version", "go_tags", "go_gcflags"})
 )
 
-func New(addr string) *http.Server {
+// Option is a functional option for configuring the metrics server.
+type Option func(mux *http.ServeMux)
+
+// WithHandler registers the additional handler for the given pattern in the metrics server.
+func WithHandler(pattern string, handler http.Handler) Option {
+	return func(mux *http.ServeMux) {
+		mux.Handle(pattern, handler)
+	}
+}
+
+func New(addr string, options ...Option) *http.Server {
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	))
+
+	// Adjust the log level of every component at runtime.
+	mux.Handle("/admin/log/levels", logger.LevelHandler())
+
+	for _, opt := range options {
+		opt(mux)
+	}
 
 	VersionGauge.WithLabelValues(version.Major, version.Minor, version.GitVersion, version.GitCommit, version.Platform, version.BuildTime, version.GoVersion, version.Gotags, version.Gogcflags).Set(1)
 	return &http.Server{
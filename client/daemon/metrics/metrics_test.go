@@ -32,3 +32,45 @@ func TestNew(t *testing.T) {
 		t.Error("Expected server handler to not be nil")
 	}
 }
+
+func TestTaskSizeLevel(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentLength int64
+		expect        string
+	}{
+		{
+			name:          "unknown content length",
+			contentLength: -1,
+			expect:        "unknown",
+		},
+		{
+			name:          "empty content",
+			contentLength: 0,
+			expect:        "lt_1mib",
+		},
+		{
+			name:          "content length is 1MiB",
+			contentLength: 1 << 20,
+			expect:        "lt_10mib",
+		},
+		{
+			name:          "content length is less than 1GiB",
+			contentLength: 1<<30 - 1,
+			expect:        "lt_1gib",
+		},
+		{
+			name:          "content length is 10GiB",
+			contentLength: 10 << 30,
+			expect:        "ge_10gib",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if level := TaskSizeLevel(tc.contentLength); level != tc.expect {
+				t.Errorf("expected task size level to be %s, but got %s", tc.expect, level)
+			}
+		})
+	}
+}
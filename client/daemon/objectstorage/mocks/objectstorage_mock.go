@@ -48,6 +48,20 @@ func (mr *MockObjectStorageMockRecorder) Serve(lis interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockObjectStorage)(nil).Serve), lis)
 }
 
+// ServeS3 mocks base method.
+func (m *MockObjectStorage) ServeS3(lis net.Listener) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServeS3", lis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ServeS3 indicates an expected call of ServeS3.
+func (mr *MockObjectStorageMockRecorder) ServeS3(lis interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServeS3", reflect.TypeOf((*MockObjectStorage)(nil).ServeS3), lis)
+}
+
 // Stop mocks base method.
 func (m *MockObjectStorage) Stop() error {
 	m.ctrl.T.Helper()
@@ -82,11 +82,25 @@ const (
 	defaultSignExpireTime = 5 * time.Minute
 )
 
+var (
+	// errObjectNotFound is the error of object is not found.
+	errObjectNotFound = errors.New("object not found")
+
+	// errInvalidRange is the error of invalid range header.
+	errInvalidRange = errors.New("invalid range")
+
+	// errUnknownMode is the error of unknown mode of putting object.
+	errUnknownMode = errors.New("unknow mode")
+)
+
 // ObjectStorage is the interface used for object storage server.
 type ObjectStorage interface {
 	// Started object storage server.
 	Serve(lis net.Listener) error
 
+	// Started S3-compatible gateway server.
+	ServeS3(lis net.Listener) error
+
 	// Stop object storage server.
 	Stop() error
 }
@@ -100,6 +114,8 @@ type objectStorage struct {
 	peerTaskManager     peer.TaskManager
 	storageManager      storage.Manager
 	peerIDGenerator     peer.IDGenerator
+	s3Server            *http.Server
+	s3Verifier          *s3Verifier
 }
 
 // New returns a new ObjectStorage instence.
@@ -131,6 +147,18 @@ func New(cfg *config.DaemonOption, dynconfig config.Dynconfig, peerTaskManager p
 		Handler: router,
 	}
 
+	if cfg.ObjectStorage.S3.Enable {
+		o.s3Verifier = &s3Verifier{
+			region:    cfg.ObjectStorage.S3.Region,
+			accessKey: cfg.ObjectStorage.S3.AccessKey,
+			secretKey: cfg.ObjectStorage.S3.SecretKey,
+		}
+
+		o.s3Server = &http.Server{
+			Handler: o.initS3Router(cfg),
+		}
+	}
+
 	return o, nil
 }
 
@@ -141,6 +169,12 @@ func (o *objectStorage) Serve(lis net.Listener) error {
 
 // Stop object storage server.
 func (o *objectStorage) Stop() error {
+	if o.s3Server != nil {
+		if err := o.s3Server.Shutdown(context.Background()); err != nil {
+			return err
+		}
+	}
+
 	return o.Server.Shutdown(context.Background())
 }
 
@@ -259,10 +293,35 @@ func (o *objectStorage) getObject(ctx *gin.Context) {
 		bucketName = params.ID
 		objectKey  = strings.TrimPrefix(params.ObjectKey, string(os.PathSeparator))
 		filter     = query.Filter
-		rg         *nethttp.Range
-		err        error
 	)
 
+	reader, attr, _, err := o.streamObject(ctx, bucketName, objectKey, filter, ctx.GetHeader(headers.Range))
+	if err != nil {
+		switch {
+		case errors.Is(err, errObjectNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"errors": http.StatusText(http.StatusNotFound)})
+		case errors.Is(err, errInvalidRange):
+			ctx.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"errors": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		}
+		return
+	}
+	defer reader.Close()
+
+	var contentLength int64 = -1
+	if l, ok := attr[headers.ContentLength]; ok {
+		if i, err := strconv.ParseInt(l, 10, 64); err == nil {
+			contentLength = i
+		}
+	}
+
+	logger.Infof("object %s content length is %d and content type is %s", objectKey, contentLength, attr[headers.ContentType])
+	ctx.DataFromReader(http.StatusOK, contentLength, attr[headers.ContentType], reader, nil)
+}
+
+// streamObject downloads object data with P2P, the range header is optional.
+func (o *objectStorage) streamObject(ctx context.Context, bucketName, objectKey, filter, rangeHeader string) (io.ReadCloser, map[string]string, *objectstorage.ObjectMetadata, error) {
 	// Initialize filter field.
 	urlMeta := &commonv1.UrlMeta{Filter: o.config.ObjectStorage.Filter}
 	if filter != "" {
@@ -271,24 +330,21 @@ func (o *objectStorage) getObject(ctx *gin.Context) {
 
 	meta, isExist, err := o.objectStorageClient.GetObjectMetadata(ctx, bucketName, objectKey)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
-		return
+		return nil, nil, nil, err
 	}
 
 	if !isExist {
-		ctx.JSON(http.StatusNotFound, gin.H{"errors": http.StatusText(http.StatusNotFound)})
-		return
+		return nil, nil, nil, errObjectNotFound
 	}
 
 	urlMeta.Digest = meta.Digest
 
 	// Parse http range header.
-	rangeHeader := ctx.GetHeader(headers.Range)
+	var rg *nethttp.Range
 	if len(rangeHeader) > 0 {
 		rangeValue, err := nethttp.ParseOneRange(rangeHeader, math.MaxInt64)
 		if err != nil {
-			ctx.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"errors": err.Error()})
-			return
+			return nil, nil, nil, fmt.Errorf("%w: %s", errInvalidRange, err)
 		}
 		rg = &rangeValue
 
@@ -302,8 +358,7 @@ func (o *objectStorage) getObject(ctx *gin.Context) {
 
 	signURL, err := o.objectStorageClient.GetSignURL(ctx, bucketName, objectKey, objectstorage.MethodGet, defaultSignExpireTime)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
-		return
+		return nil, nil, nil, err
 	}
 
 	taskID := idgen.TaskIDV1(signURL, urlMeta)
@@ -317,20 +372,10 @@ func (o *objectStorage) getObject(ctx *gin.Context) {
 		PeerID:  o.peerIDGenerator.PeerID(),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
-		return
-	}
-	defer reader.Close()
-
-	var contentLength int64 = -1
-	if l, ok := attr[headers.ContentLength]; ok {
-		if i, err := strconv.ParseInt(l, 10, 64); err == nil {
-			contentLength = i
-		}
+		return nil, nil, nil, err
 	}
 
-	log.Infof("object content length is %d and content type is %s", contentLength, attr[headers.ContentType])
-	ctx.DataFromReader(http.StatusOK, contentLength, attr[headers.ContentType], reader, nil)
+	return reader, attr, meta, nil
 }
 
 // destroyObject uses to delete object data.
@@ -385,12 +430,26 @@ func (o *objectStorage) putObject(ctx *gin.Context) {
 		fileHeader  = form.File
 	)
 
-	signURL, err := o.objectStorageClient.GetSignURL(ctx, bucketName, objectKey, objectstorage.MethodGet, defaultSignExpireTime)
-	if err != nil {
+	if err := o.importObject(ctx, bucketName, objectKey, filter, mode, maxReplicas, fileHeader); err != nil {
+		if errors.Is(err, errUnknownMode) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
 		return
 	}
 
+	ctx.Status(http.StatusOK)
+}
+
+// importObject imports object to local storage, and then imports it to seed peers and backend by the mode.
+func (o *objectStorage) importObject(ctx context.Context, bucketName, objectKey, filter string, mode uint, maxReplicas int, fileHeader *multipart.FileHeader) error {
+	signURL, err := o.objectStorageClient.GetSignURL(ctx, bucketName, objectKey, objectstorage.MethodGet, defaultSignExpireTime)
+	if err != nil {
+		return err
+	}
+
 	// Initialize url meta.
 	urlMeta := &commonv1.UrlMeta{Filter: o.config.ObjectStorage.Filter}
 	dgst := o.md5FromFileHeader(fileHeader)
@@ -415,8 +474,7 @@ func (o *objectStorage) putObject(ctx *gin.Context) {
 	log.Infof("import object %s to local storage", objectKey)
 	if err := o.importObjectToLocalStorage(ctx, taskID, peerID, fileHeader); err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
-		return
+		return err
 	}
 
 	// Announce peer information to scheduler.
@@ -426,15 +484,13 @@ func (o *objectStorage) putObject(ctx *gin.Context) {
 		PeerID: peerID,
 	}, signURL, commonv1.TaskType_DfStore, urlMeta); err != nil {
 		log.Error(err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
-		return
+		return err
 	}
 
 	// Handle task for backend.
 	switch mode {
 	case Ephemeral:
-		ctx.Status(http.StatusOK)
-		return
+		return nil
 	case WriteBack:
 		// Import object to seed peer.
 		go func() {
@@ -447,12 +503,10 @@ func (o *objectStorage) putObject(ctx *gin.Context) {
 		log.Infof("import object %s to bucket %s", objectKey, bucketName)
 		if err := o.importObjectToBackend(ctx, bucketName, objectKey, dgst, fileHeader); err != nil {
 			log.Error(err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
-			return
+			return err
 		}
 
-		ctx.Status(http.StatusOK)
-		return
+		return nil
 	case AsyncWriteBack:
 		// Import object to seed peer.
 		go func() {
@@ -470,12 +524,10 @@ func (o *objectStorage) putObject(ctx *gin.Context) {
 			}
 		}()
 
-		ctx.Status(http.StatusOK)
-		return
+		return nil
 	}
 
-	ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": fmt.Sprintf("unknow mode %d", mode)})
-	return
+	return fmt.Errorf("%w %d", errUnknownMode, mode)
 }
 
 // createBucket uses to create bucket.
@@ -0,0 +1,502 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-http-utils/headers"
+	ginprometheus "github.com/mcuadros/go-gin-prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/objectstorage"
+)
+
+const (
+	S3PrometheusSubsystemName = "dragonfly_dfdaemon_object_storage_s3"
+	S3OtelServiceName         = "dragonfly-dfdaemon-object-storage-s3"
+)
+
+const (
+	// s3XMLNamespace is the xml namespace of S3 API.
+	s3XMLNamespace = "http://s3.amazonaws.com/doc/2006-03-01/"
+
+	// s3DefaultMaxKeys is the default and max number of keys returned in listing objects.
+	s3DefaultMaxKeys = 1000
+
+	// s3MaxMemory is the max memory to store the object of putting request,
+	// the remaining is stored in temporary files.
+	s3MaxMemory = 32 * 1024 * 1024
+
+	// s3SignatureContextKey is the key of the verified signature in gin context.
+	s3SignatureContextKey = "s3Signature"
+
+	// s3TimeFormat is the time format of S3 API.
+	s3TimeFormat = "2006-01-02T15:04:05.000Z"
+
+	headerAmzCopySource   = "X-Amz-Copy-Source"
+	headerAmzStorageClass = "X-Amz-Storage-Class"
+	headerContentMD5      = "Content-MD5"
+)
+
+// s3Error is the error of S3 API.
+type s3Error struct {
+	Code       string
+	Message    string
+	StatusCode int
+}
+
+// Error implements error interface.
+func (e *s3Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// newS3Error returns the error with the code of err and the message.
+func newS3Error(err *s3Error, message string) *s3Error {
+	return &s3Error{Code: err.Code, Message: message, StatusCode: err.StatusCode}
+}
+
+var (
+	errS3AccessDenied                      = &s3Error{"AccessDenied", "Access Denied", http.StatusForbidden}
+	errS3AuthorizationHeaderMalformed      = &s3Error{"AuthorizationHeaderMalformed", "The authorization header is malformed", http.StatusBadRequest}
+	errS3AuthorizationQueryParametersError = &s3Error{"AuthorizationQueryParametersError", "The authorization query parameters are invalid", http.StatusBadRequest}
+	errS3BadDigest                         = &s3Error{"BadDigest", "The Content-MD5 you specified did not match what we received", http.StatusBadRequest}
+	errS3InternalError                     = &s3Error{"InternalError", "We encountered an internal error, please try again", http.StatusInternalServerError}
+	errS3InvalidAccessKeyID                = &s3Error{"InvalidAccessKeyId", "The access key Id you provided does not exist in our records", http.StatusForbidden}
+	errS3InvalidArgument                   = &s3Error{"InvalidArgument", "Invalid Argument", http.StatusBadRequest}
+	errS3InvalidRange                      = &s3Error{"InvalidRange", "The requested range is not satisfiable", http.StatusRequestedRangeNotSatisfiable}
+	errS3InvalidRequest                    = &s3Error{"InvalidRequest", "Invalid Request", http.StatusBadRequest}
+	errS3NoSuchBucket                      = &s3Error{"NoSuchBucket", "The specified bucket does not exist", http.StatusNotFound}
+	errS3NoSuchKey                         = &s3Error{"NoSuchKey", "The specified key does not exist", http.StatusNotFound}
+	errS3NotImplemented                    = &s3Error{"NotImplemented", "A header or operation you provided implies functionality that is not implemented", http.StatusNotImplemented}
+	errS3RequestTimeTooSkewed              = &s3Error{"RequestTimeTooSkewed", "The difference between the request time and the server's time is too large", http.StatusForbidden}
+	errS3SignatureDoesNotMatch             = &s3Error{"SignatureDoesNotMatch", "The request signature we calculated does not match the signature you provided", http.StatusForbidden}
+	errS3XAmzContentSHA256Mismatch         = &s3Error{"XAmzContentSHA256Mismatch", "The provided x-amz-content-sha256 header does not match what was computed", http.StatusBadRequest}
+)
+
+// s3ErrorResponse is the error response of S3 API.
+type s3ErrorResponse struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string   `xml:"Code"`
+	Message  string   `xml:"Message"`
+	Resource string   `xml:"Resource"`
+}
+
+// s3ListBucketResult is the response of listing objects, both version 1 and version 2.
+type s3ListBucketResult struct {
+	XMLName               xml.Name         `xml:"ListBucketResult"`
+	Xmlns                 string           `xml:"xmlns,attr"`
+	Name                  string           `xml:"Name"`
+	Prefix                string           `xml:"Prefix"`
+	Delimiter             string           `xml:"Delimiter,omitempty"`
+	Marker                string           `xml:"Marker,omitempty"`
+	NextMarker            string           `xml:"NextMarker,omitempty"`
+	ContinuationToken     string           `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string           `xml:"NextContinuationToken,omitempty"`
+	StartAfter            string           `xml:"StartAfter,omitempty"`
+	KeyCount              int              `xml:"KeyCount,omitempty"`
+	MaxKeys               int64            `xml:"MaxKeys"`
+	IsTruncated           bool             `xml:"IsTruncated"`
+	Contents              []s3Object       `xml:"Contents"`
+	CommonPrefixes        []s3CommonPrefix `xml:"CommonPrefixes"`
+}
+
+type s3Object struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+}
+
+type s3CommonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// ServeS3 started S3-compatible gateway server.
+func (o *objectStorage) ServeS3(lis net.Listener) error {
+	if o.s3Server == nil {
+		return errors.New("s3 gateway is disabled")
+	}
+
+	return o.s3Server.Serve(lis)
+}
+
+// Initialize router of S3-compatible gateway, the requests are path-style.
+func (o *objectStorage) initS3Router(cfg *config.DaemonOption) *gin.Engine {
+	r := gin.New()
+
+	// Middleware
+	r.Use(gin.Logger())
+	r.Use(gin.Recovery())
+
+	// Prometheus metrics, the metrics path is not registered to avoid conflicting with the buckets.
+	p := ginprometheus.NewPrometheus(S3PrometheusSubsystemName)
+	p.ReqCntURLLabelMappingFn = func(c *gin.Context) string {
+		return c.FullPath()
+	}
+	r.Use(p.HandlerFunc())
+
+	// Opentelemetry
+	if cfg.Options.Telemetry.Jaeger != "" {
+		r.Use(otelgin.Middleware(S3OtelServiceName))
+	}
+
+	// Signature version 4
+	r.Use(o.s3Authenticate)
+
+	r.GET("/:bucket", o.s3ListObjects)
+	r.HEAD("/:bucket", o.s3HeadBucket)
+	r.GET("/:bucket/*key", o.s3GetObject)
+	r.HEAD("/:bucket/*key", o.s3HeadObject)
+	r.PUT("/:bucket/*key", o.s3PutObject)
+	r.NoRoute(func(ctx *gin.Context) {
+		writeS3Error(ctx, errS3NotImplemented)
+	})
+
+	return r
+}
+
+// s3Authenticate verifies the signature version 4 of requests.
+func (o *objectStorage) s3Authenticate(ctx *gin.Context) {
+	sig, err := o.s3Verifier.verify(ctx.Request, time.Now())
+	if err != nil {
+		logger.Warnf("verify signature of %s %s failed: %s", ctx.Request.Method, ctx.Request.URL.Path, err)
+		writeS3Error(ctx, err)
+		ctx.Abort()
+		return
+	}
+
+	ctx.Set(s3SignatureContextKey, sig)
+	ctx.Next()
+}
+
+// s3HeadBucket checks whether the bucket exists.
+func (o *objectStorage) s3HeadBucket(ctx *gin.Context) {
+	var params S3ObjectParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		writeS3Error(ctx, newS3Error(errS3InvalidArgument, err.Error()))
+		return
+	}
+
+	isExist, err := o.objectStorageClient.IsBucketExist(ctx, params.Bucket)
+	if err != nil {
+		writeS3Error(ctx, err)
+		return
+	}
+
+	if !isExist {
+		writeS3Error(ctx, errS3NoSuchBucket)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// s3ListObjects lists the objects in bucket, both version 1 and version 2.
+func (o *objectStorage) s3ListObjects(ctx *gin.Context) {
+	var params S3ObjectParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		writeS3Error(ctx, newS3Error(errS3InvalidArgument, err.Error()))
+		return
+	}
+
+	var query S3ListObjectsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		writeS3Error(ctx, newS3Error(errS3InvalidArgument, err.Error()))
+		return
+	}
+
+	maxKeys := query.MaxKeys
+	if maxKeys <= 0 || maxKeys > s3DefaultMaxKeys {
+		maxKeys = s3DefaultMaxKeys
+	}
+
+	marker := query.Marker
+	if query.ListType == 2 {
+		marker = query.ContinuationToken
+		if marker == "" {
+			marker = query.StartAfter
+		}
+	}
+
+	logger.Infof("list objects in bucket %s with prefix %s", params.Bucket, query.Prefix)
+	metadatas, err := o.objectStorageClient.GetObjectMetadatas(ctx, params.Bucket, query.Prefix, marker, query.Delimiter, maxKeys)
+	if err != nil {
+		writeS3Error(ctx, err)
+		return
+	}
+
+	result := s3ListBucketResult{
+		Xmlns:     s3XMLNamespace,
+		Name:      params.Bucket,
+		Prefix:    query.Prefix,
+		Delimiter: query.Delimiter,
+		MaxKeys:   maxKeys,
+	}
+
+	var last string
+	for _, metadata := range metadatas.Metadatas {
+		result.Contents = append(result.Contents, s3Object{
+			Key:          metadata.Key,
+			LastModified: metadata.LastModifiedTime.UTC().Format(s3TimeFormat),
+			ETag:         s3ETag(metadata.ETag),
+			Size:         metadata.ContentLength,
+			StorageClass: metadata.StorageClass,
+		})
+		last = max(last, metadata.Key)
+	}
+
+	for _, prefix := range metadatas.CommonPrefixes {
+		result.CommonPrefixes = append(result.CommonPrefixes, s3CommonPrefix{Prefix: prefix})
+		last = max(last, prefix)
+	}
+
+	// The backend does not return whether the listing is truncated, the listing is regarded as truncated
+	// when the number of keys reaches max keys.
+	count := len(result.Contents) + len(result.CommonPrefixes)
+	result.IsTruncated = count > 0 && int64(count) >= maxKeys
+	if query.ListType == 2 {
+		result.KeyCount = count
+		result.ContinuationToken = query.ContinuationToken
+		result.StartAfter = query.StartAfter
+		if result.IsTruncated {
+			result.NextContinuationToken = last
+		}
+	} else {
+		result.Marker = query.Marker
+		if result.IsTruncated {
+			result.NextMarker = last
+		}
+	}
+
+	ctx.XML(http.StatusOK, result)
+}
+
+// s3HeadObject returns the metadata of object.
+func (o *objectStorage) s3HeadObject(ctx *gin.Context) {
+	var params S3ObjectParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		writeS3Error(ctx, newS3Error(errS3InvalidArgument, err.Error()))
+		return
+	}
+
+	objectKey := strings.TrimPrefix(params.Key, "/")
+	if objectKey == "" {
+		o.s3HeadBucket(ctx)
+		return
+	}
+
+	meta, isExist, err := o.objectStorageClient.GetObjectMetadata(ctx, params.Bucket, objectKey)
+	if err != nil {
+		writeS3Error(ctx, err)
+		return
+	}
+
+	if !isExist {
+		writeS3Error(ctx, errS3NoSuchKey)
+		return
+	}
+
+	setS3ObjectHeaders(ctx, meta)
+	ctx.Header(headers.ContentLength, fmt.Sprint(meta.ContentLength))
+	ctx.Header(headers.ContentType, meta.ContentType)
+	ctx.Status(http.StatusOK)
+}
+
+// s3GetObject downloads object data with P2P.
+func (o *objectStorage) s3GetObject(ctx *gin.Context) {
+	var params S3ObjectParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		writeS3Error(ctx, newS3Error(errS3InvalidArgument, err.Error()))
+		return
+	}
+
+	objectKey := strings.TrimPrefix(params.Key, "/")
+	if objectKey == "" {
+		o.s3ListObjects(ctx)
+		return
+	}
+
+	rangeHeader := ctx.GetHeader(headers.Range)
+	reader, _, meta, err := o.streamObject(ctx, params.Bucket, objectKey, "", rangeHeader)
+	if err != nil {
+		switch {
+		case errors.Is(err, errObjectNotFound):
+			writeS3Error(ctx, errS3NoSuchKey)
+		case errors.Is(err, errInvalidRange):
+			writeS3Error(ctx, newS3Error(errS3InvalidRange, err.Error()))
+		default:
+			writeS3Error(ctx, err)
+		}
+		return
+	}
+	defer reader.Close()
+
+	status, contentLength := http.StatusOK, meta.ContentLength
+	if rangeHeader != "" {
+		rg, err := nethttp.ParseOneRange(rangeHeader, meta.ContentLength)
+		if err != nil {
+			writeS3Error(ctx, newS3Error(errS3InvalidRange, err.Error()))
+			return
+		}
+
+		status, contentLength = http.StatusPartialContent, rg.Length
+		ctx.Header(headers.ContentRange, fmt.Sprintf("bytes %d-%d/%d", rg.Start, rg.Start+rg.Length-1, meta.ContentLength))
+	}
+
+	setS3ObjectHeaders(ctx, meta)
+	ctx.DataFromReader(status, contentLength, meta.ContentType, reader, nil)
+}
+
+// s3PutObject uploads object data, the object is imported to local storage and backend synchronously,
+// and imported to seed peers asynchronously.
+func (o *objectStorage) s3PutObject(ctx *gin.Context) {
+	var params S3ObjectParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		writeS3Error(ctx, newS3Error(errS3InvalidArgument, err.Error()))
+		return
+	}
+
+	objectKey := strings.TrimPrefix(params.Key, "/")
+	if objectKey == "" || ctx.GetHeader(headerAmzCopySource) != "" {
+		writeS3Error(ctx, errS3NotImplemented)
+		return
+	}
+
+	sha256Hash, md5Hash := sha256.New(), md5.New()
+	form, fileHeader, err := newFileHeader(io.TeeReader(ctx.Request.Body, io.MultiWriter(sha256Hash, md5Hash)), path.Base(objectKey))
+	if err != nil {
+		writeS3Error(ctx, newS3Error(errS3InvalidRequest, err.Error()))
+		return
+	}
+	// The temporary files of form are removed after the request is finished, same as the multipart form.
+	ctx.Request.MultipartForm = form
+
+	sig := ctx.MustGet(s3SignatureContextKey).(*s3Signature)
+	if sig.payloadHash != s3UnsignedPayload && hex.EncodeToString(sha256Hash.Sum(nil)) != sig.payloadHash {
+		writeS3Error(ctx, errS3XAmzContentSHA256Mismatch)
+		return
+	}
+
+	contentMD5 := md5Hash.Sum(nil)
+	if expected := ctx.GetHeader(headerContentMD5); expected != "" && base64.StdEncoding.EncodeToString(contentMD5) != expected {
+		writeS3Error(ctx, errS3BadDigest)
+		return
+	}
+
+	if err := o.importObject(ctx, params.Bucket, objectKey, "", WriteBack, 0, fileHeader); err != nil {
+		writeS3Error(ctx, err)
+		return
+	}
+
+	ctx.Header(headers.ETag, s3ETag(hex.EncodeToString(contentMD5)))
+	ctx.Status(http.StatusOK)
+}
+
+// newFileHeader reads the body as the file of multipart form, the file is stored in memory or temporary file.
+func newFileHeader(body io.Reader, filename string) (*multipart.Form, *multipart.FileHeader, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		part, err := writer.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, body)
+		}
+
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	form, err := multipart.NewReader(pr, writer.Boundary()).ReadForm(s3MaxMemory)
+	if err != nil {
+		pr.CloseWithError(err)
+		return nil, nil, err
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		return nil, nil, errors.New("empty object")
+	}
+
+	return form, files[0], nil
+}
+
+// setS3ObjectHeaders sets the headers of object metadata.
+func setS3ObjectHeaders(ctx *gin.Context, meta *objectstorage.ObjectMetadata) {
+	ctx.Header(headers.AcceptRanges, "bytes")
+	ctx.Header(headers.ETag, s3ETag(meta.ETag))
+	ctx.Header(headers.LastModified, meta.LastModifiedTime.UTC().Format(http.TimeFormat))
+	if meta.ContentDisposition != "" {
+		ctx.Header(headers.ContentDisposition, meta.ContentDisposition)
+	}
+
+	if meta.ContentEncoding != "" {
+		ctx.Header(headers.ContentEncoding, meta.ContentEncoding)
+	}
+
+	if meta.ContentLanguage != "" {
+		ctx.Header(headers.ContentLanguage, meta.ContentLanguage)
+	}
+
+	if meta.StorageClass != "" {
+		ctx.Header(headerAmzStorageClass, meta.StorageClass)
+	}
+}
+
+// s3ETag returns the quoted etag.
+func s3ETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, "\"") {
+		return etag
+	}
+
+	return fmt.Sprintf("%q", etag)
+}
+
+// writeS3Error writes the error response of S3 API.
+func writeS3Error(ctx *gin.Context, err error) {
+	var s3Err *s3Error
+	if !errors.As(err, &s3Err) {
+		logger.Errorf("s3 request %s %s failed: %s", ctx.Request.Method, ctx.Request.URL.Path, err)
+		s3Err = newS3Error(errS3InternalError, err.Error())
+	}
+
+	ctx.XML(s3Err.StatusCode, s3ErrorResponse{
+		Code:     s3Err.Code,
+		Message:  s3Err.Message,
+		Resource: ctx.Request.URL.Path,
+	})
+}
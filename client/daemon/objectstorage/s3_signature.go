@@ -0,0 +1,315 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// s3SignatureAlgorithm is the algorithm of signature version 4.
+	s3SignatureAlgorithm = "AWS4-HMAC-SHA256"
+
+	// s3Service is the service in the credential scope.
+	s3Service = "s3"
+
+	// s3Terminator is the terminator of the credential scope.
+	s3Terminator = "aws4_request"
+
+	// s3UnsignedPayload indicates the payload is not signed.
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// s3StreamingPayloadPrefix is the prefix of the chunked payload signed per chunk.
+	s3StreamingPayloadPrefix = "STREAMING-"
+
+	// s3DateFormat is the format of x-amz-date.
+	s3DateFormat = "20060102T150405Z"
+
+	// s3MaxClockSkew is the max clock skew between the request and the server.
+	s3MaxClockSkew = 15 * time.Minute
+
+	// s3MaxPresignExpires is the max expires of presigned url.
+	s3MaxPresignExpires = 7 * 24 * time.Hour
+)
+
+const (
+	headerAmzDate          = "X-Amz-Date"
+	headerAmzContentSHA256 = "X-Amz-Content-Sha256"
+
+	queryAmzAlgorithm     = "X-Amz-Algorithm"
+	queryAmzCredential    = "X-Amz-Credential"
+	queryAmzDate          = "X-Amz-Date"
+	queryAmzExpires       = "X-Amz-Expires"
+	queryAmzSignedHeaders = "X-Amz-SignedHeaders"
+	queryAmzSignature     = "X-Amz-Signature"
+)
+
+// s3Signature is the signature version 4 of request, signed in authorization header or presigned url.
+type s3Signature struct {
+	accessKey     string
+	date          string
+	region        string
+	service       string
+	terminator    string
+	signedHeaders []string
+	signature     string
+	amzDate       time.Time
+	expires       time.Duration
+	presigned     bool
+
+	// payloadHash is the hex encoded sha256 of payload, or UNSIGNED-PAYLOAD.
+	payloadHash string
+}
+
+// s3Verifier verifies the signature version 4 of requests with the credentials.
+type s3Verifier struct {
+	region    string
+	accessKey string
+	secretKey string
+}
+
+// verify verifies the signature of request, it returns the signature when the request is authenticated.
+func (v *s3Verifier) verify(r *http.Request, now time.Time) (*s3Signature, error) {
+	sig, err := parseS3Signature(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(sig.accessKey), []byte(v.accessKey)) != 1 {
+		return nil, errS3InvalidAccessKeyID
+	}
+
+	if sig.service != s3Service || sig.terminator != s3Terminator || (v.region != "" && sig.region != v.region) ||
+		sig.date != sig.amzDate.Format("20060102") {
+		return nil, newS3Error(errS3AuthorizationHeaderMalformed, fmt.Sprintf("invalid credential scope %s/%s/%s/%s", sig.date, sig.region, sig.service, sig.terminator))
+	}
+
+	if sig.presigned {
+		if now.Before(sig.amzDate.Add(-s3MaxClockSkew)) || now.After(sig.amzDate.Add(sig.expires)) {
+			return nil, newS3Error(errS3AccessDenied, "request has expired")
+		}
+	} else if now.Sub(sig.amzDate) > s3MaxClockSkew || sig.amzDate.Sub(now) > s3MaxClockSkew {
+		return nil, errS3RequestTimeTooSkewed
+	}
+
+	if strings.HasPrefix(sig.payloadHash, s3StreamingPayloadPrefix) {
+		return nil, newS3Error(errS3NotImplemented, "chunked payload is not supported")
+	}
+
+	expected := hex.EncodeToString(s3HMAC(s3SigningKey(v.secretKey, sig.date, sig.region, sig.service), []byte(s3StringToSign(r, sig))))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(sig.signature)) != 1 {
+		return nil, errS3SignatureDoesNotMatch
+	}
+
+	return sig, nil
+}
+
+// parseS3Signature parses the signature from authorization header or presigned url.
+func parseS3Signature(r *http.Request) (*s3Signature, error) {
+	query := r.URL.Query()
+	if query.Get(queryAmzAlgorithm) != "" {
+		return parseS3PresignedSignature(query)
+	}
+
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		return nil, newS3Error(errS3AccessDenied, "request is not signed")
+	}
+
+	algorithm, fields, _ := strings.Cut(auth, " ")
+	if algorithm != s3SignatureAlgorithm {
+		return nil, newS3Error(errS3AuthorizationHeaderMalformed, fmt.Sprintf("unsupported algorithm %s", algorithm))
+	}
+
+	sig := &s3Signature{}
+	for _, field := range strings.Split(fields, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(field), "=")
+		switch key {
+		case "Credential":
+			if err := sig.parseCredential(value); err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sig.signedHeaders = strings.Split(value, ";")
+		case "Signature":
+			sig.signature = value
+		}
+	}
+
+	if sig.accessKey == "" || len(sig.signedHeaders) == 0 || sig.signature == "" {
+		return nil, newS3Error(errS3AuthorizationHeaderMalformed, "authorization header requires credential, signed headers and signature")
+	}
+
+	amzDate, err := time.Parse(s3DateFormat, r.Header.Get(headerAmzDate))
+	if err != nil {
+		return nil, newS3Error(errS3AccessDenied, "request requires valid x-amz-date header")
+	}
+	sig.amzDate = amzDate
+
+	sig.payloadHash = r.Header.Get(headerAmzContentSHA256)
+	if sig.payloadHash == "" {
+		return nil, newS3Error(errS3InvalidRequest, "request requires x-amz-content-sha256 header")
+	}
+
+	return sig, nil
+}
+
+// parseS3PresignedSignature parses the signature from the query of presigned url.
+func parseS3PresignedSignature(query url.Values) (*s3Signature, error) {
+	if query.Get(queryAmzAlgorithm) != s3SignatureAlgorithm {
+		return nil, newS3Error(errS3AuthorizationQueryParametersError, fmt.Sprintf("unsupported algorithm %s", query.Get(queryAmzAlgorithm)))
+	}
+
+	sig := &s3Signature{
+		signedHeaders: strings.Split(query.Get(queryAmzSignedHeaders), ";"),
+		signature:     query.Get(queryAmzSignature),
+		presigned:     true,
+		payloadHash:   s3UnsignedPayload,
+	}
+	if err := sig.parseCredential(query.Get(queryAmzCredential)); err != nil {
+		return nil, err
+	}
+
+	amzDate, err := time.Parse(s3DateFormat, query.Get(queryAmzDate))
+	if err != nil {
+		return nil, newS3Error(errS3AuthorizationQueryParametersError, "presigned url requires valid X-Amz-Date")
+	}
+	sig.amzDate = amzDate
+
+	expires, err := strconv.ParseInt(query.Get(queryAmzExpires), 10, 64)
+	if err != nil || expires <= 0 || time.Duration(expires)*time.Second > s3MaxPresignExpires {
+		return nil, newS3Error(errS3AuthorizationQueryParametersError, "presigned url requires valid X-Amz-Expires")
+	}
+	sig.expires = time.Duration(expires) * time.Second
+
+	if sig.signature == "" || query.Get(queryAmzSignedHeaders) == "" {
+		return nil, newS3Error(errS3AuthorizationQueryParametersError, "presigned url requires signed headers and signature")
+	}
+
+	return sig, nil
+}
+
+// parseCredential parses the credential like <access key>/<date>/<region>/<service>/aws4_request.
+func (s *s3Signature) parseCredential(credential string) error {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 {
+		return newS3Error(errS3AuthorizationHeaderMalformed, fmt.Sprintf("invalid credential %s", credential))
+	}
+
+	s.accessKey, s.date, s.region, s.service, s.terminator = parts[0], parts[1], parts[2], parts[3], parts[4]
+	return nil
+}
+
+// s3StringToSign returns the string to sign of request.
+func s3StringToSign(r *http.Request, sig *s3Signature) string {
+	hash := sha256.Sum256([]byte(s3CanonicalRequest(r, sig)))
+	return strings.Join([]string{
+		s3SignatureAlgorithm,
+		sig.amzDate.Format(s3DateFormat),
+		strings.Join([]string{sig.date, sig.region, sig.service, sig.terminator}, "/"),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+}
+
+// s3CanonicalRequest returns the canonical request of request.
+func s3CanonicalRequest(r *http.Request, sig *s3Signature) string {
+	// Canonical query string, the signature is excluded in presigned url.
+	var query []string
+	for key, values := range r.URL.Query() {
+		if sig.presigned && key == queryAmzSignature {
+			continue
+		}
+
+		for _, value := range values {
+			query = append(query, s3URIEncode(key, true)+"="+s3URIEncode(value, true))
+		}
+	}
+	sort.Strings(query)
+
+	// Canonical headers.
+	var headers strings.Builder
+	for _, name := range sig.signedHeaders {
+		var value string
+		switch name {
+		case "host":
+			value = r.Host
+		case "content-length":
+			value = r.Header.Get(name)
+			if value == "" && r.ContentLength > 0 {
+				value = strconv.FormatInt(r.ContentLength, 10)
+			}
+		default:
+			values := append([]string(nil), r.Header.Values(name)...)
+			for i := range values {
+				values[i] = strings.Join(strings.Fields(values[i]), " ")
+			}
+			value = strings.Join(values, ",")
+		}
+
+		headers.WriteString(name + ":" + value + "\n")
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		s3URIEncode(r.URL.Path, false),
+		strings.Join(query, "&"),
+		headers.String(),
+		strings.Join(sig.signedHeaders, ";"),
+		sig.payloadHash,
+	}, "\n")
+}
+
+// s3URIEncode encodes the string as signature version 4, the slash is encoded when encodeSlash is true.
+func s3URIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+// s3SigningKey derives the signing key from the secret key and credential scope.
+func s3SigningKey(secretKey, date, region, service string) []byte {
+	key := s3HMAC([]byte("AWS4"+secretKey), []byte(date))
+	key = s3HMAC(key, []byte(region))
+	key = s3HMAC(key, []byte(service))
+	return s3HMAC(key, []byte(s3Terminator))
+}
+
+func s3HMAC(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -73,3 +73,34 @@ type CopyObjectRequest struct {
 	// SourceBucket is the source object key.
 	SourceObjectKey string `form:"source_object_key" binding:"required"`
 }
+
+type S3ObjectParams struct {
+	// Bucket is the name of the bucket.
+	Bucket string `uri:"bucket" binding:"required"`
+
+	// Key is the object key.
+	Key string `uri:"key" binding:"omitempty"`
+}
+
+type S3ListObjectsQuery struct {
+	// ListType is 2 for listing objects version 2.
+	ListType int `form:"list-type" binding:"omitempty,oneof=1 2"`
+
+	// Prefix limits the response to keys that begin with the specified prefix.
+	Prefix string `form:"prefix" binding:"omitempty"`
+
+	// Delimiter is a character used to group keys.
+	Delimiter string `form:"delimiter" binding:"omitempty"`
+
+	// MaxKeys sets the maximum number of keys returned in the response.
+	MaxKeys int64 `form:"max-keys" binding:"omitempty,gte=0"`
+
+	// Marker indicates the starting object key for listing.
+	Marker string `form:"marker" binding:"omitempty"`
+
+	// ContinuationToken indicates the starting object key for listing version 2.
+	ContinuationToken string `form:"continuation-token" binding:"omitempty"`
+
+	// StartAfter indicates the starting object key for listing version 2 without continuation token.
+	StartAfter string `form:"start-after" binding:"omitempty"`
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: overflow.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOverflow is a mock of Overflow interface.
+type MockOverflow struct {
+	ctrl     *gomock.Controller
+	recorder *MockOverflowMockRecorder
+}
+
+// MockOverflowMockRecorder is the mock recorder for MockOverflow.
+type MockOverflowMockRecorder struct {
+	mock *MockOverflow
+}
+
+// NewMockOverflow creates a new mock instance.
+func NewMockOverflow(ctrl *gomock.Controller) *MockOverflow {
+	mock := &MockOverflow{ctrl: ctrl}
+	mock.recorder = &MockOverflowMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOverflow) EXPECT() *MockOverflowMockRecorder {
+	return m.recorder
+}
+
+// Exists mocks base method.
+func (m *MockOverflow) Exists(ctx context.Context, taskID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, taskID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockOverflowMockRecorder) Exists(ctx, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockOverflow)(nil).Exists), ctx, taskID)
+}
+
+// Put mocks base method.
+func (m *MockOverflow) Put(ctx context.Context, taskID, digest string, reader io.Reader) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, taskID, digest, reader)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockOverflowMockRecorder) Put(ctx, taskID, digest, reader interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockOverflow)(nil).Put), ctx, taskID, digest, reader)
+}
+
+// SignURL mocks base method.
+func (m *MockOverflow) SignURL(ctx context.Context, taskID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignURL", ctx, taskID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignURL indicates an expected call of SignURL.
+func (mr *MockOverflowMockRecorder) SignURL(ctx, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignURL", reflect.TypeOf((*MockOverflow)(nil).SignURL), ctx, taskID)
+}
@@ -0,0 +1,92 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/overflow_mock.go -source overflow.go -package mocks
+
+package overflow
+
+import (
+	"context"
+	"io"
+	"path"
+	"time"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/objectstorage"
+)
+
+// signExpire is the expire time of the signed url to restore the task from the bucket.
+const signExpire = time.Hour
+
+// Overflow writes the completed tasks through to the object storage bucket, which is the second-tier cache
+// of the cluster, the tasks evicted from the local storage are restored from the bucket.
+type Overflow interface {
+	// Exists returns whether the task is written through to the bucket.
+	Exists(ctx context.Context, taskID string) (bool, error)
+
+	// Put writes the task data through to the bucket.
+	Put(ctx context.Context, taskID, digest string, reader io.Reader) error
+
+	// SignURL returns the signed url to download the task data from the bucket.
+	SignURL(ctx context.Context, taskID string) (string, error)
+}
+
+// overflow writes the tasks through to the bucket with the object key of the task id.
+type overflow struct {
+	client objectstorage.ObjectStorage
+	bucket string
+	prefix string
+}
+
+// New returns a new Overflow instance.
+func New(cfg config.OverflowOption) (Overflow, error) {
+	client, err := objectstorage.New(cfg.Name, cfg.Region, cfg.Endpoint, cfg.AccessKey, cfg.SecretKey,
+		objectstorage.WithS3ForcePathStyle(cfg.S3ForcePathStyle))
+	if err != nil {
+		return nil, err
+	}
+
+	return newOverflow(client, cfg.Bucket, cfg.Prefix), nil
+}
+
+// newOverflow returns a new overflow with the object storage client.
+func newOverflow(client objectstorage.ObjectStorage, bucket, prefix string) *overflow {
+	return &overflow{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Exists returns whether the task is written through to the bucket.
+func (o *overflow) Exists(ctx context.Context, taskID string) (bool, error) {
+	return o.client.IsObjectExist(ctx, o.bucket, o.objectKey(taskID))
+}
+
+// Put writes the task data through to the bucket.
+func (o *overflow) Put(ctx context.Context, taskID, digest string, reader io.Reader) error {
+	return o.client.PutObject(ctx, o.bucket, o.objectKey(taskID), digest, reader)
+}
+
+// SignURL returns the signed url to download the task data from the bucket.
+func (o *overflow) SignURL(ctx context.Context, taskID string) (string, error) {
+	return o.client.GetSignURL(ctx, o.bucket, o.objectKey(taskID), objectstorage.MethodGet, signExpire)
+}
+
+// objectKey returns the object key of the task.
+func (o *overflow) objectKey(taskID string) string {
+	return path.Join(o.prefix, taskID)
+}
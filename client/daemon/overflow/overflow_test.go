@@ -0,0 +1,98 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package overflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/objectstorage"
+	"d7y.io/dragonfly/v2/pkg/objectstorage/mocks"
+)
+
+func TestOverflow(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		mock   func(m *mocks.MockObjectStorageMockRecorder)
+		expect func(t *testing.T, o *overflow)
+	}{
+		{
+			name:   "task exists in bucket",
+			prefix: "tasks",
+			mock: func(m *mocks.MockObjectStorageMockRecorder) {
+				m.IsObjectExist(gomock.Any(), "dragonfly", "tasks/foo").Return(true, nil).Times(1)
+			},
+			expect: func(t *testing.T, o *overflow) {
+				assert := assert.New(t)
+				exists, err := o.Exists(context.Background(), "foo")
+				assert.NoError(err)
+				assert.True(exists)
+			},
+		},
+		{
+			name: "check task exists failed",
+			mock: func(m *mocks.MockObjectStorageMockRecorder) {
+				m.IsObjectExist(gomock.Any(), "dragonfly", "foo").Return(false, errors.New("bar")).Times(1)
+			},
+			expect: func(t *testing.T, o *overflow) {
+				assert := assert.New(t)
+				_, err := o.Exists(context.Background(), "foo")
+				assert.EqualError(err, "bar")
+			},
+		},
+		{
+			name:   "put task",
+			prefix: "tasks",
+			mock: func(m *mocks.MockObjectStorageMockRecorder) {
+				m.PutObject(gomock.Any(), "dragonfly", "tasks/foo", "sha256:baz", gomock.Any()).Return(nil).Times(1)
+			},
+			expect: func(t *testing.T, o *overflow) {
+				assert.NoError(t, o.Put(context.Background(), "foo", "sha256:baz", strings.NewReader("bar")))
+			},
+		},
+		{
+			name:   "sign url of task",
+			prefix: "tasks",
+			mock: func(m *mocks.MockObjectStorageMockRecorder) {
+				m.GetSignURL(gomock.Any(), "dragonfly", "tasks/foo", objectstorage.MethodGet, signExpire).Return("https://example.com/tasks/foo", nil).Times(1)
+			},
+			expect: func(t *testing.T, o *overflow) {
+				assert := assert.New(t)
+				url, err := o.SignURL(context.Background(), "foo")
+				assert.NoError(err)
+				assert.Equal("https://example.com/tasks/foo", url)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mocks.NewMockObjectStorage(ctrl)
+			tc.mock(client.EXPECT())
+			tc.expect(t, newOverflow(client, "dragonfly", tc.prefix))
+		})
+	}
+}
@@ -39,13 +39,18 @@ import (
 	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
 	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	"d7y.io/dragonfly/v2/client/daemon/overflow"
+	"d7y.io/dragonfly/v2/client/daemon/reputation"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	"d7y.io/dragonfly/v2/internal/dferrors"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	"d7y.io/dragonfly/v2/pkg/rpc/common"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
 	"d7y.io/dragonfly/v2/pkg/source"
@@ -81,6 +86,10 @@ type peerTaskConductor struct {
 	// request is the original PeerTaskRequest
 	request *schedulerv1.PeerTaskRequest
 
+	// priority is the priority class of the caller, it is propagated to
+	// scheduler for queuing the task, e.g. preheat and urgent downloads
+	priority string
+
 	// needBackSource indicates downloading resource from instead of other peers
 	needBackSource *atomic.Bool
 	seed           bool
@@ -101,8 +110,18 @@ type peerTaskConductor struct {
 	usedTraffic     *atomic.Uint64
 	header          atomic.Value
 
+	// p2pTraffic and seedPeerTraffic are the traffic downloaded from normal peers and seed peers,
+	// pieceRetryCount is the count of the failed pieces to be retried.
+	p2pTraffic      *atomic.Uint64
+	seedPeerTraffic *atomic.Uint64
+	pieceRetryCount *atomic.Uint64
+
 	broker *pieceBroker
 
+	// merkleTree is the verified merkle tree of the task pieces, it is nil until fetched from the parents
+	merkleTreeLock sync.Mutex
+	merkleTree     *digest.MerkleTree
+
 	sizeScope   commonv1.SizeScope
 	singlePiece *schedulerv1.SinglePiece
 	tinyData    *TinyData
@@ -134,6 +153,8 @@ type peerTaskConductor struct {
 	readyPiecesLock sync.RWMutex
 	// runningPieces stands all downloading pieces
 	runningPieces *Bitmap
+	// pieceRetries stands the failed download count of every piece, guarded by runningPiecesLock
+	pieceRetries map[int32]int
 	// lock used by piece download worker
 	runningPiecesLock sync.Mutex
 	// requestedPieces stands all pieces requested from peers
@@ -149,6 +170,9 @@ type peerTaskConductor struct {
 
 	startTime time.Time
 
+	// resumedPieces are the pieces written before dfdaemon restarts
+	resumedPieces []storage.PieceMetadata
+
 	// subtask only
 	parent *peerTaskConductor
 	rg     *nethttp.Range
@@ -169,6 +193,14 @@ type TaskOption struct {
 	GRPCDialTimeout time.Duration
 	// WatchdogTimeout > 0 indicates to start watch dog for every single peer task
 	WatchdogTimeout time.Duration
+	// PeerExchange indicates to gossip piece availability with the sibling peers returned by scheduler
+	PeerExchange bool
+	// Integrity verifies the pieces against the signed merkle tree of the task, nil means disabled
+	Integrity integrity.Integrity
+	// ParentTracker tracks the piece results of the parents for their reputation, nil means disabled
+	ParentTracker reputation.Tracker
+	// Overflow writes the completed seed tasks through to the object storage bucket, nil means disabled
+	Overflow overflow.Overflow
 }
 
 func (ptm *peerTaskManager) newPeerTaskConductor(
@@ -178,6 +210,8 @@ func (ptm *peerTaskManager) newPeerTaskConductor(
 	parent *peerTaskConductor,
 	rg *nethttp.Range,
 	seed bool) *peerTaskConductor {
+	priority, _ := rpc.PriorityFromIncomingContext(ctx)
+
 	// use a new context with span info
 	ctx = trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
 	ctx, span := tracer.Start(ctx, config.SpanPeerTask, trace.WithSpanKind(trace.SpanKindClient))
@@ -224,6 +258,7 @@ func (ptm *peerTaskManager) newPeerTaskConductor(
 		span:                span,
 		readyPieces:         NewBitmap(),
 		runningPieces:       NewBitmap(),
+		pieceRetries:        map[int32]int{},
 		requestedPieces:     NewBitmap(),
 		failedReason:        failedReasonNotSet,
 		failedCode:          commonv1.Code_UnknownError,
@@ -234,10 +269,14 @@ func (ptm *peerTaskManager) newPeerTaskConductor(
 		limiter:             rate.NewLimiter(limit, int(limit)),
 		completedLength:     atomic.NewInt64(0),
 		usedTraffic:         atomic.NewUint64(0),
+		p2pTraffic:          atomic.NewUint64(0),
+		seedPeerTraffic:     atomic.NewUint64(0),
+		pieceRetryCount:     atomic.NewUint64(0),
 		SugaredLoggerOnWith: log,
 		seed:                seed,
 		parent:              parent,
 		rg:                  rg,
+		priority:            priority,
 	}
 
 	ptc.pieceDownloadCtx, ptc.pieceDownloadCancel = context.WithCancel(ptc.ctx)
@@ -253,6 +292,9 @@ func (pt *peerTaskConductor) register() error {
 	regCtx, cancel := context.WithTimeout(pt.ctx, pt.SchedulerOption.ScheduleTimeout.Duration)
 	defer cancel()
 	regCtx, regSpan := tracer.Start(regCtx, config.SpanRegisterTask)
+	if pt.priority != "" {
+		regCtx = rpc.ContextWithPriority(regCtx, pt.priority)
+	}
 
 	var (
 		needBackSource bool
@@ -282,6 +324,7 @@ func (pt *peerTaskConductor) register() error {
 			return err
 		}
 		needBackSource = true
+		pt.recordBackSourceDecision(metrics.BackToSourceReasonRegisterFailed)
 		// can not detect source or scheduler error, create a new dummy scheduler client
 		pt.schedulerClient = &dummySchedulerClient{}
 		result = &schedulerv1.RegisterResult{TaskId: pt.taskID}
@@ -366,6 +409,7 @@ func (pt *peerTaskConductor) start() error {
 		pt.schedulerClient = &dummySchedulerClient{}
 		pt.sizeScope = commonv1.SizeScope_NORMAL
 		pt.needBackSource = atomic.NewBool(true)
+		pt.recordBackSourceDecision(metrics.BackToSourceReasonSeedPeer)
 	} else {
 		// register to scheduler
 		if err := pt.register(); err != nil {
@@ -373,12 +417,58 @@ func (pt *peerTaskConductor) start() error {
 		}
 	}
 
+	pt.reportResumedPieces()
 	pt.trafficShaper.AddTask(pt.peerTaskManager.getRunningTaskKey(pt.taskID, pt.peerID), pt)
 	go pt.broker.Start()
 	go pt.pullPieces()
 	return nil
 }
 
+// resume marks the pieces written before dfdaemon restarts as ready, they are skipped in downloading,
+// the pieces are verified against the data file by the storage manager before resuming.
+func (pt *peerTaskConductor) resume(task *storage.ReusePeerTask) {
+	pt.SetContentLength(task.ContentLength)
+	pt.SetTotalPieces(task.TotalPieces)
+	pt.SetPieceMd5Sign(task.PieceMd5Sign)
+	if task.Header != nil {
+		pt.header.Store(task.Header)
+	}
+
+	pt.readyPiecesLock.Lock()
+	for _, piece := range task.Pieces {
+		pt.readyPieces.Set(piece.Num)
+		pt.completedLength.Add(piece.Range.Length)
+	}
+	pt.readyPiecesLock.Unlock()
+
+	pt.resumedPieces = task.Pieces
+	pt.Infof("resume task with %d written pieces, completed length: %d", len(task.Pieces), pt.completedLength.Load())
+}
+
+// reportResumedPieces reports the resumed pieces to scheduler, so scheduler knows the existing piece set of the peer.
+func (pt *peerTaskConductor) reportResumedPieces() {
+	for _, piece := range pt.resumedPieces {
+		if err := pt.sendPieceResult(&schedulerv1.PieceResult{
+			TaskId: pt.GetTaskID(),
+			SrcPid: pt.GetPeerID(),
+			PieceInfo: &commonv1.PieceInfo{
+				PieceNum:    piece.Num,
+				RangeStart:  uint64(piece.Range.Start),
+				RangeSize:   uint32(piece.Range.Length),
+				PieceMd5:    piece.Md5,
+				PieceOffset: piece.Offset,
+				PieceStyle:  piece.Style,
+			},
+			Success:       true,
+			Code:          commonv1.Code_Success,
+			FinishedCount: int32(len(pt.resumedPieces)),
+		}); err != nil {
+			pt.Warnf("report resumed piece %d error: %s", piece.Num, err)
+			return
+		}
+	}
+}
+
 func (pt *peerTaskConductor) GetPeerID() string {
 	return pt.peerID
 }
@@ -482,16 +572,44 @@ func (pt *peerTaskConductor) cancelNotRegisterred(code commonv1.Code, reason str
 }
 
 // only use when receive back source code from scheduler
-func (pt *peerTaskConductor) markBackSource() {
+func (pt *peerTaskConductor) markBackSource(reason string) {
 	pt.needBackSource.Store(true)
+	pt.recordBackSourceDecision(reason)
 }
 
 // only use when legacy get piece from peers schedule timeout
-func (pt *peerTaskConductor) forceBackSource() {
+func (pt *peerTaskConductor) forceBackSource(reason string) {
 	pt.needBackSource.Store(true)
+	pt.recordBackSourceDecision(reason)
 	pt.backSource()
 }
 
+// recordBackSourceDecision records the back-to-source reason in metrics and the peer task span.
+func (pt *peerTaskConductor) recordBackSourceDecision(reason string) {
+	metrics.PeerTaskBackToSourceCount.WithLabelValues(reason, pt.request.UrlMeta.GetApplication()).Inc()
+	pt.span.AddEvent("back source decision", trace.WithAttributes(config.AttributeBackSourceReason.String(reason)))
+}
+
+// addPieceTraffic adds the traffic of the piece downloaded from the parent.
+func (pt *peerTaskConductor) addPieceTraffic(parentID string, size uint32) {
+	if idgen.IsSeedPeerIDV1(parentID) {
+		pt.seedPeerTraffic.Add(uint64(size))
+		return
+	}
+
+	pt.p2pTraffic.Add(uint64(size))
+}
+
+// collectTrafficMetrics collects the metrics of the traffic breakdown of the peer task.
+func (pt *peerTaskConductor) collectTrafficMetrics() {
+	application := pt.request.UrlMeta.GetApplication()
+	taskSizeLevel := metrics.TaskSizeLevel(pt.GetContentLength())
+	metrics.PeerTaskTraffic.WithLabelValues(metrics.PeerTaskTrafficTypeP2P, application, taskSizeLevel).Add(float64(pt.p2pTraffic.Load()))
+	metrics.PeerTaskTraffic.WithLabelValues(metrics.PeerTaskTrafficTypeSeedPeer, application, taskSizeLevel).Add(float64(pt.seedPeerTraffic.Load()))
+	metrics.PeerTaskTraffic.WithLabelValues(metrics.PeerTaskTrafficTypeBackToSource, application, taskSizeLevel).Add(float64(pt.GetTraffic()))
+	metrics.PeerTaskPieceRetryCount.WithLabelValues(application, taskSizeLevel).Add(float64(pt.pieceRetryCount.Load()))
+}
+
 func (pt *peerTaskConductor) backSource() {
 	// cancel all piece download
 	pt.pieceDownloadCancel()
@@ -502,7 +620,12 @@ func (pt *peerTaskConductor) backSource() {
 
 	ctx, span := tracer.Start(pt.ctx, config.SpanBackSource)
 	pt.SetContentLength(-1)
-	err := pt.PieceManager.DownloadSource(ctx, pt, pt.request, pt.rg)
+	request, rg := pt.request, pt.rg
+	if restored, ok := pt.overflowRequest(ctx); ok {
+		request, rg = restored, nil
+	}
+
+	err := pt.PieceManager.DownloadSource(ctx, pt, request, rg)
 	if err != nil {
 		pt.Errorf("download from source error: %s", err)
 		span.SetAttributes(config.AttributePeerTaskSuccess.Bool(false))
@@ -523,6 +646,12 @@ func (pt *peerTaskConductor) backSource() {
 }
 
 func (pt *peerTaskConductor) pullPieces() {
+	// all pieces were written before dfdaemon restarts
+	if len(pt.resumedPieces) > 0 && pt.isCompleted() {
+		pt.Done()
+		return
+	}
+
 	if pt.needBackSource.Load() {
 		pt.backSource()
 		return
@@ -544,7 +673,7 @@ func (pt *peerTaskConductor) pullPieces() {
 func (pt *peerTaskConductor) pullPiecesWithP2P() {
 	var (
 		// keep same size with pt.failedPieceCh for avoiding deadlock
-		pieceRequestQueue = NewPieceDispatcher(config.DefaultPieceDispatcherRandomRatio, pt.Log())
+		pieceRequestQueue = NewPieceDispatcher(config.DefaultPieceDispatcherRandomRatio, pt.peerTaskManager.PieceSelectionStrategy, pt.Log())
 	)
 	ctx, cancel := context.WithCancel(pt.ctx)
 
@@ -721,7 +850,7 @@ loop:
 				if !firstPacketReceived {
 					close(firstPacketDone)
 				}
-				pt.forceBackSource()
+				pt.forceBackSource(metrics.BackToSourceReasonScheduler)
 				pt.Infof("receive back source code")
 				return
 			}
@@ -784,10 +913,31 @@ func (pt *peerTaskConductor) updateSynchronizers(lastNum int32, p *schedulerv1.P
 	var peers = []*schedulerv1.PeerPacket_DestPeer{p.MainPeer}
 	peers = append(peers, p.CandidatePeers...)
 
-	pt.pieceTaskSyncManager.syncPeers(peers, desiredPiece)
+	// gossip piece availability with the sibling peers downloading the same task
+	var siblings []*schedulerv1.PeerPacket_DestPeer
+	if pt.PeerExchange || pt.peerTaskManager.featureFlagEnabled(featureflag.PeerExchange) {
+		siblings = pt.listSiblingPeers()
+	}
+
+	pt.pieceTaskSyncManager.syncPeers(peers, siblings, desiredPiece)
 	return desiredPiece
 }
 
+// listSiblingPeers lists the sibling peers downloading the same task from scheduler,
+// the scheduler not supporting peer exchange returns no siblings.
+func (pt *peerTaskConductor) listSiblingPeers() []*schedulerv1.PeerPacket_DestPeer {
+	siblings, err := pt.schedulerClient.ListSiblingPeers(pt.ctx, &schedulerv1.PeerTarget{
+		TaskId: pt.taskID,
+		PeerId: pt.peerID,
+	})
+	if err != nil {
+		pt.Debugf("list sibling peers failed: %s", err.Error())
+		return nil
+	}
+
+	return siblings
+}
+
 func (pt *peerTaskConductor) confirmReceivePeerPacketError(err error) (cont bool) {
 	select {
 	case <-pt.successCh:
@@ -806,7 +956,7 @@ func (pt *peerTaskConductor) confirmReceivePeerPacketError(err error) (cont bool
 	if ok {
 		switch de.Code {
 		case commonv1.Code_SchedNeedBackSource:
-			pt.forceBackSource()
+			pt.forceBackSource(metrics.BackToSourceReasonScheduler)
 			pt.Infof("receive back source code")
 			return false
 		case commonv1.Code_SchedReregister:
@@ -872,6 +1022,10 @@ func (pt *peerTaskConductor) pullSinglePiece() {
 
 	ctx, span := tracer.Start(pt.ctx, fmt.Sprintf(config.SpanDownloadPiece, pt.singlePiece.PieceInfo.PieceNum))
 	span.SetAttributes(config.AttributePiece.Int(int(pt.singlePiece.PieceInfo.PieceNum)))
+	span.SetAttributes(config.AttributePieceSize.Int(int(pt.singlePiece.PieceInfo.RangeSize)))
+	span.SetAttributes(config.AttributeTargetPeerID.String(pt.singlePiece.DstPid))
+	span.SetAttributes(config.AttributeTargetPeerAddr.String(pt.singlePiece.DstAddr))
+	span.SetAttributes(config.AttributePieceRetryCount.Int(0))
 
 	pt.SetContentLength(int64(pt.singlePiece.PieceInfo.RangeSize))
 	pt.SetTotalPieces(1)
@@ -887,8 +1041,9 @@ func (pt *peerTaskConductor) pullSinglePiece() {
 		DstAddr: pt.singlePiece.DstAddr,
 	}
 
-	if result, err := pt.PieceManager.DownloadPiece(ctx, request); err == nil {
+	if result, err := pt.downloadVerifiedPiece(ctx, request); err == nil {
 		pt.reportSuccessResult(request, result)
+		pt.addPieceTraffic(request.DstPid, request.piece.RangeSize)
 		pt.PublishPieceInfo(request.piece.PieceNum, request.piece.RangeSize)
 
 		span.SetAttributes(config.AttributePieceSuccess.Bool(true))
@@ -983,7 +1138,7 @@ func (pt *peerTaskConductor) waitFirstPeerPacket(done chan bool) {
 		}
 		pt.Warnf("start download from source due to %s", reasonScheduleTimeout)
 		pt.span.AddEvent("back source due to schedule timeout")
-		pt.forceBackSource()
+		pt.forceBackSource(metrics.BackToSourceReasonScheduleTimeout)
 		return
 	}
 }
@@ -1034,6 +1189,7 @@ func (pt *peerTaskConductor) downloadPiece(workerID int32, request *DownloadPiec
 		return nil
 	}
 	pt.runningPieces.Set(request.piece.PieceNum)
+	retryCount := pt.pieceRetries[request.piece.PieceNum]
 	pt.runningPiecesLock.Unlock()
 
 	defer func() {
@@ -1045,6 +1201,10 @@ func (pt *peerTaskConductor) downloadPiece(workerID int32, request *DownloadPiec
 	ctx, span := tracer.Start(pt.pieceDownloadCtx, fmt.Sprintf(config.SpanDownloadPiece, request.piece.PieceNum))
 	span.SetAttributes(config.AttributePiece.Int(int(request.piece.PieceNum)))
 	span.SetAttributes(config.AttributePieceWorker.Int(int(workerID)))
+	span.SetAttributes(config.AttributePieceSize.Int(int(request.piece.RangeSize)))
+	span.SetAttributes(config.AttributeTargetPeerID.String(request.DstPid))
+	span.SetAttributes(config.AttributeTargetPeerAddr.String(request.DstAddr))
+	span.SetAttributes(config.AttributePieceRetryCount.Int(retryCount))
 
 	// wait limit
 	if pt.limiter != nil && !pt.waitLimit(ctx, request) {
@@ -1058,9 +1218,15 @@ func (pt *peerTaskConductor) downloadPiece(workerID int32, request *DownloadPiec
 		workerID, request.DstPid, request.piece.PieceNum, request.piece.RangeStart, request.piece.RangeSize)
 	// download piece
 	// result is always not nil, PieceManager will report begin and end time
-	result, err := pt.PieceManager.DownloadPiece(ctx, request)
+	result, err := pt.downloadVerifiedPiece(ctx, request)
 	if err != nil {
+		pt.pieceRetryCount.Inc()
+		pt.runningPiecesLock.Lock()
+		pt.pieceRetries[request.piece.PieceNum]++
+		pt.runningPiecesLock.Unlock()
+
 		pt.ReportPieceResult(request, result, err)
+		span.RecordError(err)
 		span.SetAttributes(config.AttributePieceSuccess.Bool(false))
 		span.End()
 		if pt.needBackSource.Load() {
@@ -1080,6 +1246,7 @@ func (pt *peerTaskConductor) downloadPiece(workerID int32, request *DownloadPiec
 	}
 	// broadcast success piece
 	pt.reportSuccessResult(request, result)
+	pt.addPieceTraffic(request.DstPid, request.piece.RangeSize)
 	pt.PublishPieceInfo(request.piece.PieceNum, request.piece.RangeSize)
 
 	span.SetAttributes(config.AttributePieceSuccess.Bool(true))
@@ -1328,11 +1495,14 @@ func (pt *peerTaskConductor) done() {
 		code    = commonv1.Code_Success
 	)
 	pt.Log().Infof("peer task done, cost: %dms", cost)
+	pt.collectTrafficMetrics()
 	// TODO merge error handle
 	// update storage metadata
 	if err := pt.UpdateStorage(); err == nil {
 		// validate digest
 		if err = pt.Validate(); err == nil {
+			pt.signMerkleTree()
+			go pt.writeThrough()
 			close(pt.successCh)
 			pt.span.SetAttributes(config.AttributePeerTaskSuccess.Bool(true))
 		} else {
@@ -1414,6 +1584,7 @@ func (pt *peerTaskConductor) fail() {
 	} else {
 		metrics.PeerTaskFailedCount.WithLabelValues(metrics.FailTypeP2P).Add(1)
 	}
+	pt.collectTrafficMetrics()
 	defer func() {
 		close(pt.failCh)
 		pt.broker.Stop()
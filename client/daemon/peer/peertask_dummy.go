@@ -48,6 +48,10 @@ func (d *dummySchedulerClient) LeaveTask(ctx context.Context, target *schedulerv
 	return nil
 }
 
+func (d *dummySchedulerClient) ListSiblingPeers(ctx context.Context, target *schedulerv1.PeerTarget, option ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error) {
+	return nil, nil
+}
+
 func (d *dummySchedulerClient) AnnounceHost(context.Context, *schedulerv1.AnnounceHostRequest, ...grpc.CallOption) error {
 	return nil
 }
@@ -60,6 +64,10 @@ func (d *dummySchedulerClient) StatTask(ctx context.Context, request *schedulerv
 	panic("should not call this function")
 }
 
+func (d *dummySchedulerClient) FindTaskPeers(ctx context.Context, request *schedulerv1.StatTaskRequest, option ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error) {
+	return nil, nil
+}
+
 func (d *dummySchedulerClient) AnnounceTask(ctx context.Context, request *schedulerv1.AnnounceTaskRequest, option ...grpc.CallOption) error {
 	panic("should not call this function")
 }
@@ -0,0 +1,210 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+// errPieceNotInMerkleTree is returned when the piece digest announced by the parent is forged.
+var errPieceNotInMerkleTree = errors.New("not in the signed merkle tree")
+
+// downloadVerifiedPiece verifies the piece digest announced by the parent against the signed merkle tree
+// of the task before downloading it, the downloaded data is verified against the digest by the piece manager,
+// so a parent serving corrupted pieces with forged digests is detected. The piece result is tracked
+// for the reputation of the parent.
+func (pt *peerTaskConductor) downloadVerifiedPiece(ctx context.Context, request *DownloadPieceRequest) (result *DownloadPieceResult, err error) {
+	defer func() {
+		pt.trackParent(ctx, request.DstPid, err)
+	}()
+
+	if pt.Integrity == nil {
+		return pt.PieceManager.DownloadPiece(ctx, request)
+	}
+
+	if err = pt.verifyPieceDigest(ctx, request); err != nil {
+		now := time.Now().UnixNano()
+		pt.Warnf("verify piece %d from parent %s failed: %s", request.piece.PieceNum, request.DstPid, err)
+		return &DownloadPieceResult{
+			BeginTime:  now,
+			FinishTime: now,
+			DstPeerID:  request.DstPid,
+			Fail:       true,
+			pieceInfo:  request.piece,
+		}, err
+	}
+
+	return pt.PieceManager.DownloadPiece(ctx, request)
+}
+
+// verifyPieceDigest verifies the piece digest against the merkle tree of the task.
+func (pt *peerTaskConductor) verifyPieceDigest(ctx context.Context, request *DownloadPieceRequest) error {
+	tree, err := pt.loadMerkleTree(ctx, request.DstAddr, request.DstPid)
+	if err != nil {
+		return err
+	}
+
+	// The total pieces may be unknown when the tree is loaded, check the leaves again once it is known.
+	if total := pt.GetTotalPieces(); total > 0 && int(total) != len(tree.Leaves()) {
+		pt.discardMerkleTree(ctx, tree)
+		return fmt.Errorf("merkle tree has %d leaves, expected %d pieces", len(tree.Leaves()), total)
+	}
+
+	if !tree.Verify(int(request.piece.PieceNum), request.piece.PieceMd5) {
+		return fmt.Errorf("piece %d digest %q: %w", request.piece.PieceNum, request.piece.PieceMd5, errPieceNotInMerkleTree)
+	}
+
+	return nil
+}
+
+// loadMerkleTree fetches the signed merkle tree of the task from the parent once, the verified tree is
+// saved in the task meta, so the children of the peer verify the pieces against it too.
+func (pt *peerTaskConductor) loadMerkleTree(ctx context.Context, addr, peerID string) (*digest.MerkleTree, error) {
+	pt.merkleTreeLock.Lock()
+	defer pt.merkleTreeLock.Unlock()
+	if pt.merkleTree != nil {
+		return pt.merkleTree, nil
+	}
+
+	signed, err := pt.Integrity.Fetch(ctx, addr, pt.taskID, peerID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := pt.Integrity.Verify(pt.taskID, signed)
+	if err != nil {
+		return nil, err
+	}
+
+	if total := pt.GetTotalPieces(); total > 0 && int(total) != len(tree.Leaves()) {
+		return nil, fmt.Errorf("merkle tree has %d leaves, expected %d pieces", len(tree.Leaves()), total)
+	}
+
+	if err := pt.saveMerkleTree(ctx, signed); err != nil {
+		pt.Warnf("save merkle tree error: %s", err)
+	}
+
+	pt.Infof("merkle tree verified, root: %s, leaves: %d, parent: %s", tree.Root(), len(tree.Leaves()), peerID)
+	pt.merkleTree = tree
+	return tree, nil
+}
+
+// discardMerkleTree discards the merkle tree mismatching the task, the tree is fetched from the parents
+// again, and it is removed from the task meta, so the children of the peer do not verify against it.
+func (pt *peerTaskConductor) discardMerkleTree(ctx context.Context, tree *digest.MerkleTree) {
+	pt.merkleTreeLock.Lock()
+	defer pt.merkleTreeLock.Unlock()
+	if pt.merkleTree != tree {
+		return
+	}
+
+	pt.merkleTree = nil
+	if err := pt.GetStorage().UpdateTask(ctx, &storage.UpdateTaskRequest{
+		PeerTaskMetadata: storage.PeerTaskMetadata{
+			PeerID: pt.peerID,
+			TaskID: pt.taskID,
+		},
+		TaskMeta: map[string]string{integrity.TaskMetaMerkleTree: ""},
+	}); err != nil {
+		pt.Warnf("discard merkle tree error: %s", err)
+	}
+
+	pt.Warnf("merkle tree discarded, root: %s, leaves: %d", tree.Root(), len(tree.Leaves()))
+}
+
+// signMerkleTree signs the merkle tree over the piece digests of the task downloaded from the origin.
+func (pt *peerTaskConductor) signMerkleTree() {
+	if pt.Integrity == nil || !pt.Integrity.CanSign() {
+		return
+	}
+
+	pt.merkleTreeLock.Lock()
+	defer pt.merkleTreeLock.Unlock()
+	// The pieces are downloaded from the parents, keep the tree signed by the origin.
+	if pt.merkleTree != nil {
+		return
+	}
+
+	total := pt.GetTotalPieces()
+	if total <= 0 {
+		return
+	}
+
+	packet, err := pt.GetStorage().GetPieces(pt.ctx, &commonv1.PieceTaskRequest{
+		TaskId:   pt.taskID,
+		DstPid:   pt.peerID,
+		StartNum: 0,
+		Limit:    uint32(total),
+	})
+	if err != nil {
+		pt.Errorf("get pieces for merkle tree error: %s", err)
+		return
+	}
+
+	if len(packet.PieceInfos) != int(total) {
+		pt.Errorf("get %d pieces for merkle tree, expected %d", len(packet.PieceInfos), total)
+		return
+	}
+
+	pieceDigests := make([]string, 0, total)
+	for _, piece := range packet.PieceInfos {
+		if piece.PieceMd5 == "" {
+			pt.Errorf("piece %d digest is empty, skip signing merkle tree", piece.PieceNum)
+			return
+		}
+
+		pieceDigests = append(pieceDigests, piece.PieceMd5)
+	}
+
+	signed, err := pt.Integrity.Sign(pt.taskID, pieceDigests)
+	if err != nil {
+		pt.Errorf("sign merkle tree error: %s", err)
+		return
+	}
+
+	if err := pt.saveMerkleTree(pt.ctx, signed); err != nil {
+		pt.Errorf("save merkle tree error: %s", err)
+		return
+	}
+
+	pt.Infof("merkle tree signed, root: %s, leaves: %d", signed.Root, len(signed.Leaves))
+}
+
+func (pt *peerTaskConductor) saveMerkleTree(ctx context.Context, signed *types.SignedMerkleTree) error {
+	encoded, err := integrity.Encode(signed)
+	if err != nil {
+		return err
+	}
+
+	return pt.GetStorage().UpdateTask(ctx, &storage.UpdateTaskRequest{
+		PeerTaskMetadata: storage.PeerTaskMetadata{
+			PeerID: pt.peerID,
+			TaskID: pt.taskID,
+		},
+		TaskMeta: map[string]string{integrity.TaskMetaMerkleTree: encoded},
+	})
+}
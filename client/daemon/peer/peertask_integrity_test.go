@@ -0,0 +1,191 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
+	integritymocks "d7y.io/dragonfly/v2/client/daemon/integrity/mocks"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/storage/mocks"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestPeerTaskConductor_downloadVerifiedPiece(t *testing.T) {
+	leaves := []string{"md5:0", "md5:1", "md5:2"}
+	signed := &types.SignedMerkleTree{TaskID: "task", Leaves: leaves}
+
+	tests := []struct {
+		name   string
+		piece  *commonv1.PieceInfo
+		mock   func(i *integritymocks.MockIntegrityMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder, pm *MockPieceManagerMockRecorder)
+		expect func(t *testing.T, result *DownloadPieceResult, err error)
+	}{
+		{
+			name:  "piece digest in merkle tree",
+			piece: &commonv1.PieceInfo{PieceNum: 1, PieceMd5: "md5:1"},
+			mock: func(i *integritymocks.MockIntegrityMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder, pm *MockPieceManagerMockRecorder) {
+				i.Fetch(gomock.Any(), "127.0.0.1:65002", "task", "parent").Return(signed, nil).Times(1)
+				i.Verify("task", signed).Return(digest.NewMerkleTree(leaves)).Times(1)
+				s.UpdateTask(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				pm.DownloadPiece(gomock.Any(), gomock.Any()).Return(&DownloadPieceResult{Size: 1}, nil).Times(1)
+			},
+			expect: func(t *testing.T, result *DownloadPieceResult, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+				assert.Equal(int64(1), result.Size)
+			},
+		},
+		{
+			name:  "forged piece digest",
+			piece: &commonv1.PieceInfo{PieceNum: 1, PieceMd5: "md5:forged"},
+			mock: func(i *integritymocks.MockIntegrityMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder, pm *MockPieceManagerMockRecorder) {
+				i.Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(signed, nil).Times(1)
+				i.Verify("task", signed).Return(digest.NewMerkleTree(leaves)).Times(1)
+				s.UpdateTask(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			expect: func(t *testing.T, result *DownloadPieceResult, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, `piece 1 digest "md5:forged": not in the signed merkle tree`)
+				assert.True(result.Fail)
+				assert.Equal("parent", result.DstPeerID)
+			},
+		},
+		{
+			name:  "merkle tree not signed",
+			piece: &commonv1.PieceInfo{PieceNum: 1, PieceMd5: "md5:1"},
+			mock: func(i *integritymocks.MockIntegrityMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder, pm *MockPieceManagerMockRecorder) {
+				i.Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, integrity.ErrMerkleTreeNotFound).Times(1)
+			},
+			expect: func(t *testing.T, result *DownloadPieceResult, err error) {
+				assert := testifyassert.New(t)
+				assert.ErrorIs(err, integrity.ErrMerkleTreeNotFound)
+				assert.True(result.Fail)
+			},
+		},
+		{
+			name:  "untrusted merkle tree",
+			piece: &commonv1.PieceInfo{PieceNum: 1, PieceMd5: "md5:1"},
+			mock: func(i *integritymocks.MockIntegrityMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder, pm *MockPieceManagerMockRecorder) {
+				i.Fetch(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(signed, nil).Times(1)
+				i.Verify("task", signed).Return(nil, integrity.ErrInvalidSignature).Times(1)
+			},
+			expect: func(t *testing.T, result *DownloadPieceResult, err error) {
+				assert := testifyassert.New(t)
+				assert.ErrorIs(err, integrity.ErrInvalidSignature)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			pieceIntegrity := integritymocks.NewMockIntegrity(ctrl)
+			storageDriver := mocks.NewMockTaskStorageDriver(ctrl)
+			pieceManager := NewMockPieceManager(ctrl)
+			tc.mock(pieceIntegrity.EXPECT(), storageDriver.EXPECT(), pieceManager.EXPECT())
+
+			pt := &peerTaskConductor{
+				TaskOption: TaskOption{
+					PieceManager: pieceManager,
+					Integrity:    pieceIntegrity,
+				},
+				SugaredLoggerOnWith: logger.With("peer", "peer", "task", "task", "component", "PeerTask"),
+				peerID:              "peer",
+				taskID:              "task",
+				totalPiece:          atomic.NewInt32(3),
+				storage:             storageDriver,
+			}
+
+			result, err := pt.downloadVerifiedPiece(context.Background(), &DownloadPieceRequest{
+				piece:   tc.piece,
+				TaskID:  "task",
+				PeerID:  "peer",
+				DstPid:  "parent",
+				DstAddr: "127.0.0.1:65002",
+			})
+			tc.expect(t, result, err)
+		})
+	}
+}
+
+func TestPeerTaskConductor_discardMerkleTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	leaves := []string{"md5:0", "md5:1", "md5:2"}
+	signed := &types.SignedMerkleTree{TaskID: "task", Leaves: leaves}
+
+	pieceIntegrity := integritymocks.NewMockIntegrity(ctrl)
+	storageDriver := mocks.NewMockTaskStorageDriver(ctrl)
+	pieceManager := NewMockPieceManager(ctrl)
+	gomock.InOrder(
+		pieceIntegrity.EXPECT().Fetch(gomock.Any(), gomock.Any(), "task", "parent").Return(signed, nil).Times(1),
+		pieceIntegrity.EXPECT().Verify("task", signed).Return(digest.NewMerkleTree(leaves)).Times(1),
+		storageDriver.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil).Times(1),
+		pieceManager.EXPECT().DownloadPiece(gomock.Any(), gomock.Any()).Return(&DownloadPieceResult{Size: 1}, nil).Times(1),
+		storageDriver.EXPECT().UpdateTask(gomock.Any(), &storage.UpdateTaskRequest{
+			PeerTaskMetadata: storage.PeerTaskMetadata{PeerID: "peer", TaskID: "task"},
+			TaskMeta:         map[string]string{integrity.TaskMetaMerkleTree: ""},
+		}).Return(nil).Times(1),
+	)
+
+	pt := &peerTaskConductor{
+		TaskOption: TaskOption{
+			PieceManager: pieceManager,
+			Integrity:    pieceIntegrity,
+		},
+		SugaredLoggerOnWith: logger.With("peer", "peer", "task", "task", "component", "PeerTask"),
+		peerID:              "peer",
+		taskID:              "task",
+		totalPiece:          atomic.NewInt32(-1),
+		storage:             storageDriver,
+	}
+
+	request := &DownloadPieceRequest{
+		piece:   &commonv1.PieceInfo{PieceNum: 1, PieceMd5: "md5:1"},
+		TaskID:  "task",
+		PeerID:  "peer",
+		DstPid:  "parent",
+		DstAddr: "127.0.0.1:65002",
+	}
+
+	// The total pieces is unknown when the merkle tree is loaded.
+	assert := testifyassert.New(t)
+	_, err := pt.downloadVerifiedPiece(context.Background(), request)
+	assert.Nil(err)
+	assert.NotNil(pt.merkleTree)
+
+	// The merkle tree mismatches the total pieces known later.
+	pt.totalPiece.Store(4)
+	result, err := pt.downloadVerifiedPiece(context.Background(), request)
+	assert.EqualError(err, "merkle tree has 3 leaves, expected 4 pieces")
+	assert.True(result.Fail)
+	assert.Nil(pt.merkleTree)
+}
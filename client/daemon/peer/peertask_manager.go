@@ -34,12 +34,14 @@ import (
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
 	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
 
+	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/metrics"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/internal/util"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
 )
 
@@ -61,6 +63,9 @@ type TaskManager interface {
 
 	IsPeerTaskRunning(taskID string, peerID string) (Task, bool)
 
+	// GetTaskProgress returns the progress of the running peer task with the traffic breakdown
+	GetTaskProgress(taskID string, peerID string) (*TaskProgress, bool)
+
 	// StatTask checks whether the given task exists in P2P network
 	StatTask(ctx context.Context, taskID string) (*schedulerv1.Task, error)
 
@@ -114,6 +119,9 @@ type TinyData struct {
 
 var tracer trace.Tracer
 
+// ErrTaskNotCached is returned when the cache only task is not completed in local storage.
+var ErrTaskNotCached = errors.New("task is not cached")
+
 func init() {
 	tracer = otel.Tracer("dfget-daemon")
 }
@@ -131,12 +139,16 @@ type TaskManagerOption struct {
 	PerPeerRateLimit  rate.Limit
 	TotalRateLimit    rate.Limit
 	TrafficShaperType string
+	// PieceSelectionStrategy is the strategy of selecting pieces from the parents
+	PieceSelectionStrategy string
 	// Multiplex indicates to reuse the data of completed peer tasks
 	Multiplex bool
 	// Prefetch indicates to prefetch the whole files of ranged requests
 	Prefetch          bool
 	GetPiecesMaxRetry int
 	SplitRunningTasks bool
+	// Dynconfig provides the download policies of the applications
+	Dynconfig config.Dynconfig
 }
 
 func NewPeerTaskManager(opt *TaskManagerOption) (TaskManager, error) {
@@ -150,6 +162,90 @@ func NewPeerTaskManager(opt *TaskManagerOption) (TaskManager, error) {
 	return ptm, nil
 }
 
+// applicationPolicy returns the download policy of the application from manager.
+func (ptm *peerTaskManager) applicationPolicy(application string) (rpc.ApplicationPolicy, bool) {
+	if ptm.Dynconfig == nil || application == "" {
+		return rpc.ApplicationPolicy{}, false
+	}
+
+	policies, err := ptm.Dynconfig.GetApplicationPolicies()
+	if err != nil {
+		return rpc.ApplicationPolicy{}, false
+	}
+
+	policy, ok := policies[application]
+	return policy, ok
+}
+
+// featureFlagEnabled returns whether the feature flag is rolled out to the peer by manager.
+func (ptm *peerTaskManager) featureFlagEnabled(name string) bool {
+	if ptm == nil || ptm.Dynconfig == nil {
+		return false
+	}
+
+	flags, err := ptm.Dynconfig.GetFeatureFlags()
+	if err != nil {
+		return false
+	}
+
+	return flags.Enabled(name)
+}
+
+// checkApplicationURL returns an error if the url is not allowed by the application policy.
+func (ptm *peerTaskManager) checkApplicationURL(urlMeta *commonv1.UrlMeta, url string) error {
+	policy, ok := ptm.applicationPolicy(urlMeta.GetApplication())
+	if !ok {
+		return nil
+	}
+
+	allowed, err := policy.AllowURL(url)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return fmt.Errorf("url %s is not allowed by application %s", url, urlMeta.GetApplication())
+	}
+
+	return nil
+}
+
+// checkURLPolicy returns an error if the url is denied by the url policy of the cluster,
+// and the denial is recorded in the audit log.
+func (ptm *peerTaskManager) checkURLPolicy(urlMeta *commonv1.UrlMeta, url, peerID string) error {
+	if ptm.Dynconfig == nil {
+		return nil
+	}
+
+	policy, err := ptm.Dynconfig.GetURLPolicy()
+	if err != nil {
+		return nil
+	}
+
+	if err := policy.Check(url); err != nil {
+		metrics.URLPolicyDeniedCount.Add(1)
+		logger.AuditLogger.Infow("download is denied by url policy", "url", url, "application", urlMeta.GetApplication(),
+			"peer", peerID, "host", ptm.PeerHost.GetId(), "ip", ptm.PeerHost.GetIp(), "reason", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// applicationRateLimit returns the limit lowered by the rate limit of application policy.
+func (ptm *peerTaskManager) applicationRateLimit(urlMeta *commonv1.UrlMeta, limit rate.Limit) rate.Limit {
+	policy, ok := ptm.applicationPolicy(urlMeta.GetApplication())
+	if !ok || policy.RateLimit == 0 {
+		return limit
+	}
+
+	if policyLimit := rate.Limit(policy.RateLimit); policyLimit < limit {
+		return policyLimit
+	}
+
+	return limit
+}
+
 func (ptm *peerTaskManager) findPeerTaskConductor(key string) (*peerTaskConductor, bool) {
 	pt, ok := ptm.runningPeerTasks.Load(key)
 	if !ok {
@@ -207,6 +303,7 @@ func (ptm *peerTaskManager) getOrCreatePeerTaskConductor(
 		logger.Debugf("peer task found: %s/%s", ptc.taskID, ptc.peerID)
 		return ptc, false, nil
 	}
+	resumable := ptm.findResumablePeerTask(taskID, request, parent)
 	ptc := ptm.newPeerTaskConductor(ctx, request, limit, parent, rg, seed)
 
 	ptm.conductorLock.Lock()
@@ -216,7 +313,7 @@ func (ptm *peerTaskManager) getOrCreatePeerTaskConductor(
 		logger.Debugf("peer task found: %s/%s", p.taskID, p.peerID)
 		if seed && !p.seed && !p.needBackSource.Load() {
 			p.Warnf("new seed request received, switch to back source, may be produced by multiple schedulers")
-			p.markBackSource()
+			p.markBackSource(metrics.BackToSourceReasonSeedPeer)
 		}
 		metrics.PeerTaskCacheHitCount.Add(1)
 		return p, false, nil
@@ -232,6 +329,10 @@ func (ptm *peerTaskManager) getOrCreatePeerTaskConductor(
 		ptc.cancelNotRegisterred(commonv1.Code_ClientError, err.Error())
 		return nil, false, err
 	}
+
+	if resumable != nil {
+		ptc.resume(resumable)
+	}
 	return ptc, true, nil
 }
 
@@ -244,6 +345,7 @@ func (ptm *peerTaskManager) createSplitedPeerTaskConductor(
 	rg *nethttp.Range,
 	desiredLocation string,
 	seed bool) (*peerTaskConductor, bool, error) {
+	resumable := ptm.findResumablePeerTask(taskID, request, parent)
 	ptc := ptm.newPeerTaskConductor(ctx, request, limit, parent, rg, seed)
 
 	ptm.runningPeerTasks.Store(taskID+"/"+ptc.peerID, ptc)
@@ -256,9 +358,32 @@ func (ptm *peerTaskManager) createSplitedPeerTaskConductor(
 		ptc.cancelNotRegisterred(commonv1.Code_ClientError, err.Error())
 		return nil, false, err
 	}
+
+	if resumable != nil {
+		ptc.resume(resumable)
+	}
 	return ptc, true, nil
 }
 
+// findResumablePeerTask finds the unfinished task written before dfdaemon restarts,
+// and reuses its peer id to resume the task from the written pieces.
+func (ptm *peerTaskManager) findResumablePeerTask(taskID string, request *schedulerv1.PeerTaskRequest, parent *peerTaskConductor) *storage.ReusePeerTask {
+	// subtask is stored in the parent task
+	if parent != nil {
+		return nil
+	}
+
+	task := ptm.StorageManager.FindResumableTask(taskID)
+	if task == nil {
+		return nil
+	}
+
+	logger.Infof("resume peer task %s/%s with %d written pieces", taskID, task.PeerID, len(task.Pieces))
+	metrics.PeerTaskResumeCount.Add(1)
+	request.PeerId = task.PeerID
+	return task
+}
+
 func (ptm *peerTaskManager) enabledPrefetch(rg *nethttp.Range) bool {
 	return ptm.Prefetch && rg != nil
 }
@@ -307,6 +432,12 @@ func (ptm *peerTaskManager) StartFileTask(ctx context.Context, req *FileTaskRequ
 	if req.KeepOriginalOffset && !ptm.Prefetch {
 		return nil, fmt.Errorf("please enable prefetch when use original offset feature")
 	}
+	if err := ptm.checkApplicationURL(req.UrlMeta, req.Url); err != nil {
+		return nil, err
+	}
+	if err := ptm.checkURLPolicy(req.UrlMeta, req.Url, req.PeerId); err != nil {
+		return nil, err
+	}
 	if ptm.Multiplex {
 		progress, ok := ptm.tryReuseFilePeerTask(ctx, req)
 		if ok {
@@ -322,6 +453,7 @@ func (ptm *peerTaskManager) StartFileTask(ctx context.Context, req *FileTaskRequ
 	if req.Limit > 0 {
 		limit = rate.Limit(req.Limit)
 	}
+	limit = ptm.applicationRateLimit(req.UrlMeta, limit)
 	ctx, pt, err := ptm.newFileTask(ctx, req, limit)
 	if err != nil {
 		return nil, err
@@ -333,6 +465,14 @@ func (ptm *peerTaskManager) StartFileTask(ctx context.Context, req *FileTaskRequ
 }
 
 func (ptm *peerTaskManager) StartStreamTask(ctx context.Context, req *StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+	if err := ptm.checkApplicationURL(req.URLMeta, req.URL); err != nil {
+		return nil, nil, err
+	}
+
+	if err := ptm.checkURLPolicy(req.URLMeta, req.URL, req.PeerID); err != nil {
+		return nil, nil, err
+	}
+
 	peerTaskRequest := &schedulerv1.PeerTaskRequest{
 		Url:         req.URL,
 		UrlMeta:     req.URLMeta,
@@ -341,6 +481,16 @@ func (ptm *peerTaskManager) StartStreamTask(ctx context.Context, req *StreamTask
 		IsMigrating: false,
 	}
 
+	if req.CacheOnly {
+		r, attr, ok := ptm.tryReuseStreamPeerTask(ctx, req)
+		if !ok {
+			return nil, nil, ErrTaskNotCached
+		}
+
+		metrics.PeerTaskCacheHitCount.Add(1)
+		return r, attr, nil
+	}
+
 	if ptm.Multiplex {
 		// try breakpoint resume for task has range header
 		if req.Range != nil && !ptm.SplitRunningTasks {
@@ -375,6 +525,14 @@ func (ptm *peerTaskManager) StartStreamTask(ctx context.Context, req *StreamTask
 }
 
 func (ptm *peerTaskManager) StartSeedTask(ctx context.Context, req *SeedTaskRequest) (response *SeedTaskResponse, reuse bool, err error) {
+	if err := ptm.checkApplicationURL(req.UrlMeta, req.Url); err != nil {
+		return nil, false, err
+	}
+
+	if err := ptm.checkURLPolicy(req.UrlMeta, req.Url, req.PeerId); err != nil {
+		return nil, false, err
+	}
+
 	response, ok := ptm.tryReuseSeedPeerTask(ctx, req)
 	if ok {
 		metrics.PeerTaskCacheHitCount.Add(1)
@@ -388,6 +546,7 @@ func (ptm *peerTaskManager) StartSeedTask(ctx context.Context, req *SeedTaskRequ
 	if req.Limit > 0 {
 		limit = rate.Limit(req.Limit)
 	}
+	limit = ptm.applicationRateLimit(req.UrlMeta, limit)
 
 	response, err = ptm.newSeedTask(ctx, req, limit)
 	if err != nil {
@@ -453,6 +612,30 @@ func (ptm *peerTaskManager) IsPeerTaskRunning(taskID, peerID string) (Task, bool
 	return nil, ok
 }
 
+// TaskProgress is the progress of the running peer task with the traffic breakdown.
+type TaskProgress struct {
+	ContentLength       int64
+	CompletedLength     int64
+	P2PTraffic          uint64
+	SeedPeerTraffic     uint64
+	BackToSourceTraffic uint64
+}
+
+func (ptm *peerTaskManager) GetTaskProgress(taskID, peerID string) (*TaskProgress, bool) {
+	ptc, ok := ptm.findPeerTaskConductor(ptm.getRunningTaskKey(taskID, peerID))
+	if !ok {
+		return nil, false
+	}
+
+	return &TaskProgress{
+		ContentLength:       ptc.GetContentLength(),
+		CompletedLength:     ptc.completedLength.Load(),
+		P2PTraffic:          ptc.p2pTraffic.Load(),
+		SeedPeerTraffic:     ptc.seedPeerTraffic.Load(),
+		BackToSourceTraffic: ptc.GetTraffic(),
+	}, true
+}
+
 func (ptm *peerTaskManager) StatTask(ctx context.Context, taskID string) (*schedulerv1.Task, error) {
 	req := &schedulerv1.StatTaskRequest{
 		TaskId: taskID,
@@ -68,6 +68,21 @@ func (mr *MockTaskManagerMockRecorder) GetPieceManager() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPieceManager", reflect.TypeOf((*MockTaskManager)(nil).GetPieceManager))
 }
 
+// GetTaskProgress mocks base method.
+func (m *MockTaskManager) GetTaskProgress(taskID, peerID string) (*TaskProgress, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskProgress", taskID, peerID)
+	ret0, _ := ret[0].(*TaskProgress)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetTaskProgress indicates an expected call of GetTaskProgress.
+func (mr *MockTaskManagerMockRecorder) GetTaskProgress(taskID, peerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskProgress", reflect.TypeOf((*MockTaskManager)(nil).GetTaskProgress), taskID, peerID)
+}
+
 // IsPeerTaskRunning mocks base method.
 func (m *MockTaskManager) IsPeerTaskRunning(taskID, peerID string) (Task, bool) {
 	m.ctrl.T.Helper()
@@ -51,13 +51,16 @@ import (
 	schedulerv1mocks "d7y.io/api/v2/pkg/apis/scheduler/v1/mocks"
 
 	"d7y.io/dragonfly/v2/client/config"
+	configmocks "d7y.io/dragonfly/v2/client/config/mocks"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
+	storagemocks "d7y.io/dragonfly/v2/client/daemon/storage/mocks"
 	"d7y.io/dragonfly/v2/client/daemon/test"
 	"d7y.io/dragonfly/v2/client/util"
 	"d7y.io/dragonfly/v2/internal/dferrors"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/dfnet"
 	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
 	"d7y.io/dragonfly/v2/pkg/rpc"
@@ -67,6 +70,7 @@ import (
 	"d7y.io/dragonfly/v2/pkg/source"
 	"d7y.io/dragonfly/v2/pkg/source/clients/httpprotocol"
 	sourcemocks "d7y.io/dragonfly/v2/pkg/source/mocks"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 func TestMain(m *testing.M) {
@@ -925,6 +929,29 @@ func (ts *testSpec) runConductorTest(assert *testifyassert.Assertions, require *
 	assert.Nil(err, "read all should be ok")
 	assert.Equal(ts.taskData, data, "stream output and desired output must match")
 
+	// test cache only stream task
+	cachedRC, _, err := ptm.StartStreamTask(context.Background(),
+		&StreamTaskRequest{
+			URL:       ts.url,
+			URLMeta:   urlMeta,
+			PeerID:    ts.peerID,
+			CacheOnly: true,
+		})
+	assert.Nil(err, "cache only stream task should be ok")
+	data, err = io.ReadAll(cachedRC)
+	assert.Nil(err, "read all should be ok")
+	assert.Nil(cachedRC.Close())
+	assert.Equal(ts.taskData, data, "cache only output and desired output must match")
+
+	_, _, err = ptm.StartStreamTask(context.Background(),
+		&StreamTaskRequest{
+			URL:       ts.url + "?not-cached",
+			URLMeta:   urlMeta,
+			PeerID:    ts.peerID,
+			CacheOnly: true,
+		})
+	assert.ErrorIs(err, ErrTaskNotCached, "not cached task should fail")
+
 	// test reuse file task
 	progress, ok := ptm.tryReuseFilePeerTask(
 		context.Background(),
@@ -950,3 +977,299 @@ func (ts *testSpec) runConductorTest(assert *testifyassert.Assertions, require *
 	assert.Nil(err, "load output file should be ok")
 	assert.Equal(ts.taskData, outputBytes, "file output and desired output must match")
 }
+
+func TestPeerTaskManager_ApplicationPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		urlMeta *commonv1.UrlMeta
+		url     string
+		limit   rate.Limit
+		mock    func(md *configmocks.MockDynconfigMockRecorder)
+		expect  func(t *testing.T, limit rate.Limit, err error)
+	}{
+		{
+			name:    "application is empty",
+			urlMeta: &commonv1.UrlMeta{},
+			url:     "https://example.com/foo",
+			limit:   rate.Inf,
+			mock:    func(md *configmocks.MockDynconfigMockRecorder) {},
+			expect: func(t *testing.T, limit rate.Limit, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(rate.Inf, limit)
+			},
+		},
+		{
+			name:    "get application policies failed",
+			urlMeta: &commonv1.UrlMeta{Application: "foo"},
+			url:     "https://example.com/foo",
+			limit:   rate.Inf,
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, fmt.Errorf("foo")).Times(2)
+			},
+			expect: func(t *testing.T, limit rate.Limit, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(rate.Inf, limit)
+			},
+		},
+		{
+			name:    "url is allowed and limit is lowered",
+			urlMeta: &commonv1.UrlMeta{Application: "foo"},
+			url:     "https://example.com/foo",
+			limit:   rate.Limit(2048),
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{
+					"foo": {RateLimit: 1024, AllowedURLRegex: "^https://example.com/"},
+				}, nil).Times(2)
+			},
+			expect: func(t *testing.T, limit rate.Limit, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(rate.Limit(1024), limit)
+			},
+		},
+		{
+			name:    "limit is lower than rate limit of application",
+			urlMeta: &commonv1.UrlMeta{Application: "foo"},
+			url:     "https://example.com/foo",
+			limit:   rate.Limit(512),
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{
+					"foo": {RateLimit: 1024},
+				}, nil).Times(2)
+			},
+			expect: func(t *testing.T, limit rate.Limit, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(rate.Limit(512), limit)
+			},
+		},
+		{
+			name:    "url is not allowed",
+			urlMeta: &commonv1.UrlMeta{Application: "foo"},
+			url:     "https://example.org/foo",
+			limit:   rate.Inf,
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{
+					"foo": {AllowedURLRegex: "^https://example.com/"},
+				}, nil).Times(2)
+			},
+			expect: func(t *testing.T, limit rate.Limit, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "url https://example.org/foo is not allowed by application foo")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfig(ctl)
+			tc.mock(dynconfig.EXPECT())
+
+			ptm := &peerTaskManager{TaskManagerOption: TaskManagerOption{Dynconfig: dynconfig}}
+			err := ptm.checkApplicationURL(tc.urlMeta, tc.url)
+			tc.expect(t, ptm.applicationRateLimit(tc.urlMeta, tc.limit), err)
+		})
+	}
+}
+
+func TestPeerTaskManager_URLPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		mock   func(md *configmocks.MockDynconfigMockRecorder)
+		expect func(t *testing.T, err error)
+	}{
+		{
+			name: "get url policy failed",
+			url:  "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetURLPolicy().Return(types.URLPolicy{}, fmt.Errorf("foo")).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name: "url policy is empty",
+			url:  "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetURLPolicy().Return(types.URLPolicy{}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name: "url matches allow pattern",
+			url:  "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetURLPolicy().Return(types.URLPolicy{Allow: []string{"^https://example.com/"}}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name: "url matches deny pattern",
+			url:  "https://example.com/foo.exe",
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetURLPolicy().Return(types.URLPolicy{
+					Allow: []string{"^https://example.com/"},
+					Deny:  []string{"\\.exe$"},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.ErrorIs(err, types.ErrURLDenied)
+				assert.EqualError(err, "url is denied by policy: https://example.com/foo.exe matches deny pattern \\.exe$")
+			},
+		},
+		{
+			name: "url matches no allow pattern",
+			url:  "https://example.org/foo",
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetURLPolicy().Return(types.URLPolicy{Allow: []string{"^https://example.com/"}}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.ErrorIs(err, types.ErrURLDenied)
+				assert.EqualError(err, "url is denied by policy: https://example.org/foo matches no allow pattern")
+			},
+		},
+		{
+			name: "deny pattern is invalid",
+			url:  "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigMockRecorder) {
+				md.GetURLPolicy().Return(types.URLPolicy{Deny: []string{"("}}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.ErrorIs(err, types.ErrURLDenied)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfig(ctl)
+			tc.mock(dynconfig.EXPECT())
+
+			ptm := &peerTaskManager{TaskManagerOption: TaskManagerOption{
+				TaskOption: TaskOption{PeerHost: &schedulerv1.PeerHost{Id: "foo", Ip: "127.0.0.1"}},
+				Dynconfig:  dynconfig,
+			}}
+			tc.expect(t, ptm.checkURLPolicy(&commonv1.UrlMeta{}, tc.url, "bar"))
+		})
+	}
+}
+
+func TestPeerTaskManager_FeatureFlagEnabled(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	dynconfig := configmocks.NewMockDynconfig(ctl)
+	gomock.InOrder(
+		dynconfig.EXPECT().GetFeatureFlags().Return(nil, fmt.Errorf("foo")).Times(1),
+		dynconfig.EXPECT().GetFeatureFlags().Return(featureflag.Flags{}, nil).Times(1),
+		dynconfig.EXPECT().GetFeatureFlags().Return(featureflag.Flags{featureflag.PeerExchange}, nil).Times(1),
+		dynconfig.EXPECT().GetFeatureFlags().Return(featureflag.Flags{}, nil).Times(1),
+	)
+
+	var nilPtm *peerTaskManager
+	assert.False(nilPtm.featureFlagEnabled(featureflag.PeerExchange))
+
+	ptm := &peerTaskManager{TaskManagerOption: TaskManagerOption{Dynconfig: dynconfig}}
+	assert.False(ptm.featureFlagEnabled(featureflag.PeerExchange))
+	assert.False(ptm.featureFlagEnabled(featureflag.PeerExchange))
+
+	// The feature flag takes effect once it is rolled out, and stops when it is rolled back.
+	assert.True(ptm.featureFlagEnabled(featureflag.PeerExchange))
+	assert.False(ptm.featureFlagEnabled(featureflag.PeerExchange))
+}
+
+func TestPeerTaskManager_ResumePeerTask(t *testing.T) {
+	assert := testifyassert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		url     = "http://example.com/resume"
+		urlMeta = &commonv1.UrlMeta{}
+		taskID  = idgen.TaskIDV1(url, urlMeta)
+		pieces  = []storage.PieceMetadata{
+			{Num: 0, Md5: "foo", Range: nethttp.Range{Start: 0, Length: 10}},
+			{Num: 1, Md5: "bar", Range: nethttp.Range{Start: 10, Length: 10}},
+		}
+	)
+
+	sm := storagemocks.NewMockManager(ctrl)
+	sm.EXPECT().FindResumableTask(gomock.Eq(taskID)).Return(&storage.ReusePeerTask{
+		PeerTaskMetadata: storage.PeerTaskMetadata{
+			PeerID: "resumed-peer",
+			TaskID: taskID,
+		},
+		ContentLength: 30,
+		TotalPieces:   3,
+		PieceMd5Sign:  "sign",
+		Pieces:        pieces,
+	}).Times(1)
+
+	ptm := &peerTaskManager{
+		TaskManagerOption: TaskManagerOption{
+			TaskOption: TaskOption{
+				PeerHost:       &schedulerv1.PeerHost{},
+				StorageManager: sm,
+			},
+		},
+	}
+
+	// subtask is never resumed
+	assert.Nil(ptm.findResumablePeerTask(taskID, &schedulerv1.PeerTaskRequest{}, &peerTaskConductor{}))
+
+	request := &schedulerv1.PeerTaskRequest{
+		Url:     url,
+		UrlMeta: urlMeta,
+		PeerId:  "new-peer",
+	}
+	resumable := ptm.findResumablePeerTask(taskID, request, nil)
+	assert.NotNil(resumable)
+	assert.Equal("resumed-peer", request.PeerId)
+
+	ptc := ptm.newPeerTaskConductor(context.Background(), request, rate.Inf, nil, nil, false)
+	ptc.resume(resumable)
+	assert.Equal("resumed-peer", ptc.GetPeerID())
+	assert.Equal(int64(30), ptc.GetContentLength())
+	assert.Equal(int32(3), ptc.GetTotalPieces())
+	assert.Equal("sign", ptc.GetPieceMd5Sign())
+	assert.Equal(int64(20), ptc.completedLength.Load())
+	assert.True(ptc.readyPieces.IsSet(0))
+	assert.True(ptc.readyPieces.IsSet(1))
+	assert.False(ptc.readyPieces.IsSet(2))
+
+	var results []*schedulerv1.PieceResult
+	pps := schedulerv1mocks.NewMockScheduler_ReportPieceResultClient(ctrl)
+	pps.EXPECT().Send(gomock.Any()).DoAndReturn(func(pr *schedulerv1.PieceResult) error {
+		results = append(results, pr)
+		return nil
+	}).Times(len(pieces))
+	ptc.peerPacketStream = pps
+	ptc.reportResumedPieces()
+
+	for i, pr := range results {
+		assert.True(pr.Success)
+		assert.Equal("resumed-peer", pr.SrcPid)
+		assert.Empty(pr.DstPid)
+		assert.Equal(pieces[i].Num, pr.PieceInfo.PieceNum)
+		assert.Equal(pieces[i].Md5, pr.PieceInfo.PieceMd5)
+		assert.Equal(uint32(pieces[i].Range.Length), pr.PieceInfo.RangeSize)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+)
+
+// overflowRequest returns the back-to-source request of the task data in the overflow bucket, so the task
+// evicted from the local storage is restored from the bucket instead of the origin. The range and the
+// headers of the origin are not carried, the object in the bucket is the content of the task.
+func (pt *peerTaskConductor) overflowRequest(ctx context.Context) (*schedulerv1.PeerTaskRequest, bool) {
+	if pt.Overflow == nil {
+		return nil, false
+	}
+
+	exists, err := pt.Overflow.Exists(ctx, pt.taskID)
+	if err != nil {
+		pt.Warnf("check task in overflow bucket error: %s", err)
+		return nil, false
+	}
+
+	if !exists {
+		return nil, false
+	}
+
+	url, err := pt.Overflow.SignURL(ctx, pt.taskID)
+	if err != nil {
+		pt.Warnf("sign url of task in overflow bucket error: %s", err)
+		return nil, false
+	}
+
+	pt.Infof("restore task from overflow bucket")
+	metrics.StorageOverflowRestoredCount.Inc()
+	return &schedulerv1.PeerTaskRequest{
+		Url: url,
+		UrlMeta: &commonv1.UrlMeta{
+			Digest:      pt.request.UrlMeta.GetDigest(),
+			Tag:         pt.request.UrlMeta.GetTag(),
+			Application: pt.request.UrlMeta.GetApplication(),
+			Priority:    pt.request.UrlMeta.GetPriority(),
+		},
+		PeerId:   pt.request.PeerId,
+		PeerHost: pt.request.PeerHost,
+		TaskId:   pt.taskID,
+	}, true
+}
+
+// writeThrough writes the completed task of the seed peer through to the overflow bucket,
+// the task already in the bucket is skipped.
+func (pt *peerTaskConductor) writeThrough() {
+	if pt.Overflow == nil || !pt.seed {
+		return
+	}
+
+	ctx := context.Background()
+	exists, err := pt.Overflow.Exists(ctx, pt.taskID)
+	if err != nil {
+		pt.Warnf("check task in overflow bucket error: %s", err)
+		return
+	}
+
+	if exists {
+		return
+	}
+
+	rc, err := pt.GetStorage().ReadAllPieces(ctx, &storage.ReadAllPiecesRequest{
+		PeerTaskMetadata: storage.PeerTaskMetadata{
+			TaskID: pt.taskID,
+			PeerID: pt.peerID,
+		},
+	})
+	if err != nil {
+		pt.Errorf("read pieces for overflow bucket error: %s", err)
+		return
+	}
+	defer rc.Close()
+
+	if err := pt.Overflow.Put(ctx, pt.taskID, pt.request.UrlMeta.GetDigest(), rc); err != nil {
+		pt.Errorf("write task through to overflow bucket error: %s", err)
+		return
+	}
+
+	metrics.StorageOverflowWrittenCount.Inc()
+	pt.Infof("task written through to overflow bucket")
+}
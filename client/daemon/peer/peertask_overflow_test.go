@@ -0,0 +1,183 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	testifyassert "github.com/stretchr/testify/assert"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+
+	overflowmocks "d7y.io/dragonfly/v2/client/daemon/overflow/mocks"
+	"d7y.io/dragonfly/v2/client/daemon/storage/mocks"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+func TestPeerTaskConductor_overflowRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(o *overflowmocks.MockOverflowMockRecorder)
+		expect func(t *testing.T, request *schedulerv1.PeerTaskRequest, ok bool)
+	}{
+		{
+			name: "restore task from overflow bucket",
+			mock: func(o *overflowmocks.MockOverflowMockRecorder) {
+				o.Exists(gomock.Any(), "task").Return(true, nil).Times(1)
+				o.SignURL(gomock.Any(), "task").Return("https://example.com/task", nil).Times(1)
+			},
+			expect: func(t *testing.T, request *schedulerv1.PeerTaskRequest, ok bool) {
+				assert := testifyassert.New(t)
+				assert.True(ok)
+				assert.Equal("https://example.com/task", request.Url)
+				assert.Equal("task", request.TaskId)
+				assert.Equal("sha256:foo", request.UrlMeta.Digest)
+				assert.Empty(request.UrlMeta.Range)
+				assert.Empty(request.UrlMeta.Header)
+			},
+		},
+		{
+			name: "task not in overflow bucket",
+			mock: func(o *overflowmocks.MockOverflowMockRecorder) {
+				o.Exists(gomock.Any(), "task").Return(false, nil).Times(1)
+			},
+			expect: func(t *testing.T, request *schedulerv1.PeerTaskRequest, ok bool) {
+				testifyassert.False(t, ok)
+			},
+		},
+		{
+			name: "check task in overflow bucket failed",
+			mock: func(o *overflowmocks.MockOverflowMockRecorder) {
+				o.Exists(gomock.Any(), "task").Return(false, errors.New("foo")).Times(1)
+			},
+			expect: func(t *testing.T, request *schedulerv1.PeerTaskRequest, ok bool) {
+				testifyassert.False(t, ok)
+			},
+		},
+		{
+			name: "sign url failed",
+			mock: func(o *overflowmocks.MockOverflowMockRecorder) {
+				o.Exists(gomock.Any(), "task").Return(true, nil).Times(1)
+				o.SignURL(gomock.Any(), "task").Return("", errors.New("foo")).Times(1)
+			},
+			expect: func(t *testing.T, request *schedulerv1.PeerTaskRequest, ok bool) {
+				testifyassert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			taskOverflow := overflowmocks.NewMockOverflow(ctrl)
+			tc.mock(taskOverflow.EXPECT())
+
+			pt := &peerTaskConductor{
+				TaskOption: TaskOption{
+					Overflow: taskOverflow,
+				},
+				SugaredLoggerOnWith: logger.With("peer", "peer", "task", "task", "component", "PeerTask"),
+				peerID:              "peer",
+				taskID:              "task",
+				request: &schedulerv1.PeerTaskRequest{
+					Url: "http://example.com/origin",
+					UrlMeta: &commonv1.UrlMeta{
+						Digest: "sha256:foo",
+						Range:  "0-9",
+						Header: map[string]string{"Authorization": "bar"},
+					},
+					PeerId: "peer",
+				},
+			}
+
+			request, ok := pt.overflowRequest(context.Background())
+			tc.expect(t, request, ok)
+		})
+	}
+}
+
+func TestPeerTaskConductor_writeThrough(t *testing.T) {
+	tests := []struct {
+		name string
+		seed bool
+		mock func(o *overflowmocks.MockOverflowMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder)
+	}{
+		{
+			name: "write task through to overflow bucket",
+			seed: true,
+			mock: func(o *overflowmocks.MockOverflowMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder) {
+				o.Exists(gomock.Any(), "task").Return(false, nil).Times(1)
+				s.ReadAllPieces(gomock.Any(), gomock.Any()).Return(io.NopCloser(strings.NewReader("foo")), nil).Times(1)
+				o.Put(gomock.Any(), "task", "sha256:foo", gomock.Any()).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "task already in overflow bucket",
+			seed: true,
+			mock: func(o *overflowmocks.MockOverflowMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder) {
+				o.Exists(gomock.Any(), "task").Return(true, nil).Times(1)
+			},
+		},
+		{
+			name: "read pieces failed",
+			seed: true,
+			mock: func(o *overflowmocks.MockOverflowMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder) {
+				o.Exists(gomock.Any(), "task").Return(false, nil).Times(1)
+				s.ReadAllPieces(gomock.Any(), gomock.Any()).Return(nil, errors.New("foo")).Times(1)
+			},
+		},
+		{
+			name: "task of normal peer",
+			mock: func(o *overflowmocks.MockOverflowMockRecorder, s *mocks.MockTaskStorageDriverMockRecorder) {},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			taskOverflow := overflowmocks.NewMockOverflow(ctrl)
+			storageDriver := mocks.NewMockTaskStorageDriver(ctrl)
+			tc.mock(taskOverflow.EXPECT(), storageDriver.EXPECT())
+
+			pt := &peerTaskConductor{
+				TaskOption: TaskOption{
+					Overflow: taskOverflow,
+				},
+				SugaredLoggerOnWith: logger.With("peer", "peer", "task", "task", "component", "PeerTask"),
+				peerID:              "peer",
+				taskID:              "task",
+				seed:                tc.seed,
+				storage:             storageDriver,
+				request: &schedulerv1.PeerTaskRequest{
+					UrlMeta: &commonv1.UrlMeta{Digest: "sha256:foo"},
+				},
+			}
+
+			pt.writeThrough()
+		})
+	}
+}
@@ -38,6 +38,7 @@ import (
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/dfnet"
 	"d7y.io/dragonfly/v2/pkg/net/ip"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
 )
 
@@ -64,6 +65,9 @@ type pieceTaskSynchronizer struct {
 	grpcInitError     atomic.Value
 	peerTaskConductor *peerTaskConductor
 	pieceRequestQueue PieceDispatcher
+	// sibling indicates the dstPeer is a sibling peer for peer exchange, not a parent scheduled by scheduler,
+	// the errors of sibling peers are not reported to scheduler to avoid rescheduling.
+	sibling *atomic.Bool
 }
 
 type synchronizerWatchdog struct {
@@ -78,7 +82,7 @@ type pieceTaskSynchronizerError struct {
 }
 
 // FIXME for compatibility, sync will be called after the dfdaemonclient.GetPieceTasks deprecated and the pieceTaskPoller removed
-func (s *pieceTaskSyncManager) syncPeers(destPeers []*schedulerv1.PeerPacket_DestPeer, desiredPiece int32) {
+func (s *pieceTaskSyncManager) syncPeers(destPeers []*schedulerv1.PeerPacket_DestPeer, siblingPeers []*schedulerv1.PeerPacket_DestPeer, desiredPiece int32) {
 	s.Lock()
 	defer func() {
 		if s.peerTaskConductor.WatchdogTimeout > 0 {
@@ -87,10 +91,20 @@ func (s *pieceTaskSyncManager) syncPeers(destPeers []*schedulerv1.PeerPacket_Des
 		s.Unlock()
 	}()
 
-	peersToKeep, peersToAdd, peersToClose := s.diffPeers(destPeers)
+	// sibling peers gossip piece availability with the parents
+	siblings := map[string]bool{}
+	peers := append([]*schedulerv1.PeerPacket_DestPeer{}, destPeers...)
+	for _, sibling := range siblingPeers {
+		if !containsPeer(peers, sibling.PeerId) {
+			siblings[sibling.PeerId] = true
+			peers = append(peers, sibling)
+		}
+	}
+
+	peersToKeep, peersToAdd, peersToClose := s.diffPeers(peers)
 
 	for _, peer := range peersToAdd {
-		s.newPieceTaskSynchronizer(s.ctx, peer, desiredPiece)
+		s.newPieceTaskSynchronizer(s.ctx, peer, desiredPiece, siblings[peer.PeerId])
 	}
 
 	for _, peer := range peersToKeep {
@@ -98,13 +112,14 @@ func (s *pieceTaskSyncManager) syncPeers(destPeers []*schedulerv1.PeerPacket_Des
 		// worker is working, keep it going on
 		if worker.error.Load() == nil {
 			s.peerTaskConductor.Infof("reuse working PieceTaskSynchronizer %s", peer.PeerId)
+			worker.sibling.Store(siblings[peer.PeerId])
 		} else {
 			s.peerTaskConductor.Infof("close stale PieceTaskSynchronizer %s and re-initialize it", peer.PeerId)
 			// clean error worker
 			worker.close()
 			delete(s.workers, peer.PeerId)
 			// reconnect and retry
-			s.newPieceTaskSynchronizer(s.ctx, peer, desiredPiece)
+			s.newPieceTaskSynchronizer(s.ctx, peer, desiredPiece, siblings[peer.PeerId])
 		}
 	}
 
@@ -141,10 +156,21 @@ func (s *pieceTaskSyncManager) diffPeers(peers []*schedulerv1.PeerPacket_DestPee
 	return
 }
 
+// containsPeer returns whether the peer id is in the peers
+func containsPeer(peers []*schedulerv1.PeerPacket_DestPeer, peerID string) bool {
+	for _, p := range peers {
+		if p.PeerId == peerID {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *pieceTaskSyncManager) newPieceTaskSynchronizer(
 	ctx context.Context,
 	dstPeer *schedulerv1.PeerPacket_DestPeer,
-	desiredPiece int32) {
+	desiredPiece int32,
+	sibling bool) {
 	_, span := tracer.Start(s.ctx, config.SpanSyncPieceTasks)
 	span.SetAttributes(config.AttributeTargetPeerID.String(dstPeer.PeerId))
 	request := &commonv1.PieceTaskRequest{
@@ -162,6 +188,7 @@ func (s *pieceTaskSyncManager) newPieceTaskSynchronizer(
 		peerTaskConductor:   s.peerTaskConductor,
 		pieceRequestQueue:   s.pieceRequestQueue,
 		dstPeer:             dstPeer,
+		sibling:             atomic.NewBool(sibling),
 		error:               atomic.Value{},
 		grpcInitialized:     atomic.NewBool(false),
 		grpcInitError:       atomic.Value{},
@@ -240,6 +267,11 @@ func (s *pieceTaskSynchronizer) start(request *commonv1.PieceTaskRequest, dstPee
 		if startError != nil {
 			s.grpcInitError.Store(&pieceTaskSynchronizerError{startError})
 			s.peerTaskConductor.Errorf("connect peer %s error: %s", dstPeer.PeerId, startError)
+			if s.sibling.Load() {
+				// sibling peer is not scheduled by scheduler, skip reporting
+				s.peerTaskConductor.Infof("skip reporting invalid sibling peer %s", dstPeer.PeerId)
+				return
+			}
 			if errors.Is(startError, context.DeadlineExceeded) {
 				// connect timeout error, report to scheduler to get more available peers
 				s.peerTaskConductor.pieceTaskSyncManager.reportInvalidPeer(dstPeer, commonv1.Code_ClientConnectionError)
@@ -272,7 +304,14 @@ func (s *pieceTaskSynchronizer) start(request *commonv1.PieceTaskRequest, dstPee
 		return
 	}
 
-	stream, err := grpcClient.SyncPieceTasks(s.ctx, request)
+	// The range task may be scheduled to the peers of the full task,
+	// the peers serve the range from the pieces of the full task.
+	ctx := s.ctx
+	if s.peerTaskConductor.request.UrlMeta.GetRange() != "" {
+		ctx = rpc.ContextWithRange(ctx, s.peerTaskConductor.request.Url, s.peerTaskConductor.request.UrlMeta)
+	}
+
+	stream, err := grpcClient.SyncPieceTasks(ctx, request)
 	// Refer: https://github.com/grpc/grpc-go/blob/v1.44.0/stream.go#L104
 	// When receive io.EOF, the real error should be discovered using RecvMsg, here is client.Recv()
 	if err == io.EOF && stream != nil {
@@ -430,6 +469,10 @@ func (s *pieceTaskSynchronizer) acquire(request *commonv1.PieceTaskRequest) erro
 
 func (s *pieceTaskSynchronizer) reportError(err error) {
 	s.span.RecordError(err)
+	if s.sibling.Load() {
+		s.Debugf("skip reporting sync piece error of sibling peer to scheduler")
+		return
+	}
 	sendError := s.peerTaskConductor.sendPieceResult(compositePieceResult(s.peerTaskConductor, s.dstPeer, commonv1.Code_ClientPieceRequestFail))
 	if sendError != nil {
 		s.Errorf("sync piece info failed and send piece result with error: %s", sendError)
@@ -17,12 +17,15 @@
 package peer
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	testifyassert "github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 
 	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
@@ -292,3 +295,61 @@ func Test_diffPeers(t *testing.T) {
 		})
 	}
 }
+
+func Test_reportErrorOfSibling(t *testing.T) {
+	assert := testifyassert.New(t)
+
+	var testCases = []struct {
+		name    string
+		sibling bool
+	}{
+		{
+			name:    "report error of parent",
+			sibling: false,
+		},
+		{
+			name:    "skip reporting error of sibling",
+			sibling: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			peer := &schedulerv1.PeerPacket_DestPeer{PeerId: "peer-0"}
+			pps := mocks.NewMockScheduler_ReportPieceResultClient(ctrl)
+			if !tt.sibling {
+				pps.EXPECT().Send(gomock.Any()).DoAndReturn(func(pr *schedulerv1.PieceResult) error {
+					assert.Equal(peer.PeerId, pr.DstPid)
+					return nil
+				}).Times(1)
+			}
+
+			ptc := &peerTaskConductor{
+				SugaredLoggerOnWith: logger.With(
+					"peer", "test",
+					"task", "test",
+					"component", "PeerTask"),
+				readyPieces:      NewBitmap(),
+				peerPacketStream: pps,
+			}
+			synchronizer := &pieceTaskSynchronizer{
+				SugaredLoggerOnWith: ptc.SugaredLoggerOnWith,
+				span:                trace.SpanFromContext(context.Background()),
+				dstPeer:             peer,
+				peerTaskConductor:   ptc,
+				sibling:             atomic.NewBool(tt.sibling),
+			}
+			synchronizer.reportError(errors.New("foo"))
+		})
+	}
+}
+
+func Test_containsPeer(t *testing.T) {
+	assert := testifyassert.New(t)
+	peers := []*schedulerv1.PeerPacket_DestPeer{{PeerId: "peer-0"}, {PeerId: "peer-1"}}
+	assert.True(containsPeer(peers, "peer-1"))
+	assert.False(containsPeer(peers, "peer-2"))
+	assert.False(containsPeer(nil, "peer-0"))
+}
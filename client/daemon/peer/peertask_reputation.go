@@ -0,0 +1,57 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"errors"
+
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	"d7y.io/dragonfly/v2/pkg/digest"
+)
+
+// trackParent records the piece result of the parent, the failures not caused by the parent are ignored,
+// e.g. the task is canceled or the merkle tree is not signed by the seed peer yet.
+func (pt *peerTaskConductor) trackParent(ctx context.Context, peerID string, err error) {
+	if err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, integrity.ErrMerkleTreeNotFound)) {
+		return
+	}
+
+	corrupted := isCorruptionError(err)
+	if corrupted {
+		metrics.PieceTaskCorruptedCount.Add(1)
+	}
+
+	if pt.ParentTracker == nil {
+		return
+	}
+
+	if err != nil {
+		pt.ParentTracker.Failure(peerID, corrupted)
+		return
+	}
+
+	pt.ParentTracker.Success(peerID)
+}
+
+// isCorruptionError returns whether the piece data or digest served by the parent is corrupted.
+func isCorruptionError(err error) bool {
+	return errors.Is(err, digest.ErrDigestNotMatch) ||
+		errors.Is(err, errPieceNotInMerkleTree) ||
+		errors.Is(err, integrity.ErrInvalidSignature)
+}
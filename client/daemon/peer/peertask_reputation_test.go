@@ -0,0 +1,88 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
+	"d7y.io/dragonfly/v2/client/daemon/reputation"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+)
+
+func TestPeerTaskConductor_trackParent(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		expect []rpc.ParentReport
+	}{
+		{
+			name:   "piece downloaded",
+			ctx:    context.Background(),
+			expect: []rpc.ParentReport{{PeerID: "parent", Pieces: 1}},
+		},
+		{
+			name:   "piece failed",
+			ctx:    context.Background(),
+			err:    errors.New("foo"),
+			expect: []rpc.ParentReport{{PeerID: "parent", Pieces: 1, Failures: 1}},
+		},
+		{
+			name:   "piece data corrupted",
+			ctx:    context.Background(),
+			err:    fmt.Errorf("write piece: %w", digest.ErrDigestNotMatch),
+			expect: []rpc.ParentReport{{PeerID: "parent", Pieces: 1, Failures: 1, Corruptions: 1}},
+		},
+		{
+			name:   "piece digest forged",
+			ctx:    context.Background(),
+			err:    fmt.Errorf("piece 1: %w", errPieceNotInMerkleTree),
+			expect: []rpc.ParentReport{{PeerID: "parent", Pieces: 1, Failures: 1, Corruptions: 1}},
+		},
+		{
+			name:   "merkle tree not signed yet",
+			ctx:    context.Background(),
+			err:    integrity.ErrMerkleTreeNotFound,
+			expect: []rpc.ParentReport{},
+		},
+		{
+			name:   "task canceled",
+			ctx:    canceled,
+			err:    errors.New("foo"),
+			expect: []rpc.ParentReport{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := reputation.NewTracker()
+			pt := &peerTaskConductor{TaskOption: TaskOption{ParentTracker: tracker}}
+			pt.trackParent(tc.ctx, "parent", tc.err)
+			testifyassert.Equal(t, tc.expect, tracker.Reports())
+		})
+	}
+}
@@ -46,6 +46,8 @@ type StreamTaskRequest struct {
 	Range *http.Range
 	// peer's id and must be global uniqueness
 	PeerID string
+	// CacheOnly serves the task from local completed tasks only, ErrTaskNotCached is returned otherwise
+	CacheOnly bool
 }
 
 // StreamTask represents a peer task with stream io for reading directly without once more disk io
@@ -80,6 +82,7 @@ func (ptm *peerTaskManager) newStreamTask(
 	if ptm.PerPeerRateLimit > 0 {
 		limit = ptm.PerPeerRateLimit
 	}
+	limit = ptm.applicationRateLimit(request.UrlMeta, limit)
 
 	// prefetch parent request
 	var parent *peerTaskConductor
@@ -50,6 +50,10 @@ type pieceDispatcher struct {
 	score map[string]int64
 	// downloaded hold the already successfully downloaded piece num
 	downloaded map[int32]struct{}
+	// availability hold the count of peers holding each piece num
+	availability map[int32]int
+	// selector selects the piece request of a peer
+	selector PieceSelector
 	// sum is the valid num of piece requests. When sum == 0, the consumer will wait until there is a request is putted
 	sum         *atomic.Int64
 	closed      bool
@@ -67,19 +71,22 @@ var (
 	minScore = (60 * time.Second).Nanoseconds()
 )
 
-func NewPieceDispatcher(randomRatio float64, log *logger.SugaredLoggerOnWith) PieceDispatcher {
+func NewPieceDispatcher(randomRatio float64, pieceSelectionStrategy string, log *logger.SugaredLoggerOnWith) PieceDispatcher {
 	lock := &sync.Mutex{}
+	r := rand.New(rand.NewSource(time.Now().Unix()))
 	pd := &pieceDispatcher{
 		peerRequests: map[string][]*DownloadPieceRequest{},
 		score:        map[string]int64{},
 		downloaded:   map[int32]struct{}{},
+		availability: map[int32]int{},
+		selector:     NewPieceSelector(pieceSelectionStrategy, r),
 		sum:          atomic.NewInt64(0),
 		closed:       false,
 		cond:         sync.NewCond(lock),
 		lock:         lock,
 		log:          log.With("component", "pieceDispatcher"),
 		randomRatio:  randomRatio,
-		rand:         rand.New(rand.NewSource(time.Now().Unix())),
+		rand:         r,
 	}
 	log.Debugf("piece dispatcher created")
 	return pd
@@ -96,6 +103,7 @@ func (p *pieceDispatcher) Put(req *DownloadPieceRequest) {
 	if _, ok := p.score[req.DstPid]; !ok {
 		p.score[req.DstPid] = maxScore
 	}
+	p.availability[req.piece.PieceNum]++
 	p.sum.Add(1)
 	p.cond.Broadcast()
 }
@@ -128,8 +136,8 @@ func (p *pieceDispatcher) getDesiredReq() (*DownloadPieceRequest, error) {
 	// iterate all peers, until get a valid piece requests
 	for _, peer := range distPeerIDs {
 		for len(p.peerRequests[peer]) > 0 {
-			// choose a random piece request of a peer
-			n := p.rand.Intn(len(p.peerRequests[peer]))
+			// choose a piece request of a peer with the piece selection strategy
+			n := p.selector.Select(p.peerRequests[peer], p.availability)
 			req := p.peerRequests[peer][n]
 			p.peerRequests[peer] = append(p.peerRequests[peer][0:n], p.peerRequests[peer][n+1:]...)
 			p.sum.Sub(1)
@@ -154,7 +154,7 @@ func TestPieceDispatcherCount(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pieceDispatcher := NewPieceDispatcher(tt.args.randomRatio, logger.With())
+			pieceDispatcher := NewPieceDispatcher(tt.args.randomRatio, PieceSelectionStrategyRandom, logger.With())
 			pieceTestManager := newPieceTestManager(pieceDispatcher, tt.args.peers, tt.args.pieceNum)
 			pieceTestManager.Run()
 			for p, c := range tt.want {
@@ -27,8 +27,11 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/quic-go/quic-go/http3"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc/status"
@@ -38,7 +41,9 @@ import (
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/digest"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
 	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 type DownloadPieceRequest struct {
@@ -73,6 +78,15 @@ type PieceDownloaderOption func(*pieceDownloader) error
 type pieceDownloader struct {
 	scheme     string
 	httpClient *http.Client
+
+	// quicClient is the http client over quic, it is nil when quic is disabled.
+	quicClient *http.Client
+
+	// quicAddrs is the quic addresses of parents negotiated by Alt-Svc header.
+	quicAddrs sync.Map
+
+	// encryption returns the encryption mode of the pieces, it is nil when the encryption is disabled.
+	encryption func() string
 }
 
 type pieceDownloadError struct {
@@ -142,7 +156,37 @@ var defaultTransport http.RoundTripper = &http.Transport{
 	ExpectContinueTimeout: 2 * time.Second,
 }
 
-func NewPieceDownloader(timeout time.Duration, caCertPool *x509.CertPool) PieceDownloader {
+// WithQUICTransport downloads pieces over quic when the parent announces it.
+func WithQUICTransport(timeout time.Duration, caCertPool *x509.CertPool) PieceDownloaderOption {
+	return func(pd *pieceDownloader) error {
+		// Same as pieces over plain http, the integrity of pieces is guaranteed by digest
+		// when the certificates of parents are not issued.
+		tlsConfig := &tls.Config{InsecureSkipVerify: true}
+		if caCertPool != nil {
+			tlsConfig = &tls.Config{RootCAs: caCertPool}
+		}
+
+		pd.quicClient = &http.Client{
+			Transport: &http3.RoundTripper{TLSClientConfig: tlsConfig},
+			Timeout:   timeout,
+		}
+		return nil
+	}
+}
+
+// WithTLSTransport downloads pieces over tls with the certificates issued by the manager ca
+// when the encryption is preferred or required, so the pieces can cross untrusted network segments.
+func WithTLSTransport(tlsConfig *tls.Config, encryption func() string) PieceDownloaderOption {
+	return func(pd *pieceDownloader) error {
+		transport := defaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		pd.httpClient.Transport = transport
+		pd.encryption = encryption
+		return nil
+	}
+}
+
+func NewPieceDownloader(timeout time.Duration, caCertPool *x509.CertPool, opts ...PieceDownloaderOption) PieceDownloader {
 	pd := &pieceDownloader{
 		scheme: "http",
 		httpClient: &http.Client{
@@ -159,18 +203,67 @@ func NewPieceDownloader(timeout time.Duration, caCertPool *x509.CertPool) PieceD
 		}
 	}
 
+	for _, opt := range opts {
+		if err := opt(pd); err != nil {
+			logger.Errorf("apply piece downloader option failed: %s", err)
+		}
+	}
+
 	return pd
 }
 
 func (p *pieceDownloader) DownloadPiece(ctx context.Context, req *DownloadPieceRequest) (io.Reader, io.Closer, error) {
-	httpRequest, err := p.buildDownloadPieceHTTPRequest(ctx, req)
+	if quicAddr, ok := p.quicAddrs.Load(req.DstAddr); ok {
+		reader, closer, err := p.downloadPiece(ctx, req, p.quicClient, "https", quicAddr.(string))
+		if err == nil || !isConnectionError(err) {
+			return reader, closer, err
+		}
+
+		// Fall back to tcp, quic is negotiated again by the following responses.
+		logger.Warnf("task id: %s, piece num: %d, dst: %s, download piece over quic failed, fall back to tcp: %s",
+			req.TaskID, req.piece.PieceNum, quicAddr, err)
+		p.quicAddrs.Delete(req.DstAddr)
+	}
+
+	var encryption string
+	if p.encryption != nil {
+		encryption = p.encryption()
+	}
+
+	switch encryption {
+	case types.PieceEncryptionRequire:
+		return p.downloadPiece(ctx, req, p.httpClient, "https", req.DstAddr)
+	case types.PieceEncryptionPrefer:
+		reader, closer, err := p.downloadPiece(ctx, req, p.httpClient, "https", req.DstAddr)
+		if err == nil || !isTLSHandshakeError(err) {
+			return reader, closer, err
+		}
+
+		// Fall back to plain http for the parents not serving tls yet.
+		logger.Warnf("task id: %s, piece num: %d, dst: %s, tls handshake failed, fall back to plain http: %s",
+			req.TaskID, req.piece.PieceNum, req.DstAddr, err)
+	}
+
+	return p.downloadPiece(ctx, req, p.httpClient, p.scheme, req.DstAddr)
+}
+
+// isTLSHandshakeError returns whether the piece download error is caused by the tls handshake with the parent.
+func isTLSHandshakeError(err error) bool {
+	if e, ok := err.(*pieceDownloadError); ok {
+		return e.connectionError && nethttp.IsTLSHandshakeError(e.err)
+	}
+	return false
+}
+
+func (p *pieceDownloader) downloadPiece(ctx context.Context, req *DownloadPieceRequest, client *http.Client, scheme, addr string) (io.Reader, io.Closer, error) {
+	httpRequest, err := p.buildDownloadPieceHTTPRequest(ctx, req, scheme, addr)
 	if err != nil {
 		return nil, nil, err
 	}
-	resp, err := p.httpClient.Do(httpRequest)
+	resp, err := client.Do(httpRequest)
 	if err != nil {
 		logger.Errorf("task id: %s, piece num: %d, dst: %s, download piece failed: %s",
-			req.TaskID, req.piece.PieceNum, req.DstAddr, err)
+			req.TaskID, req.piece.PieceNum, addr, err)
 		return nil, nil, &pieceDownloadError{
 			target:          httpRequest.URL.String(),
 			err:             err,
@@ -188,10 +281,23 @@ func (p *pieceDownloader) DownloadPiece(ctx context.Context, req *DownloadPieceR
 			statusCode:      resp.StatusCode,
 		}
 	}
+	if p.quicClient != nil && resp.ProtoMajor < 3 {
+		if quicAddr, ok := parseQUICAddr(addr, resp.Header.Values("Alt-Svc")); ok {
+			p.quicAddrs.Store(req.DstAddr, quicAddr)
+		}
+	}
+
 	reader, closer := resp.Body.(io.Reader), resp.Body.(io.Closer)
 	if req.CalcDigest {
 		req.log.Debugf("calculate digest for piece %d, digest: %s", req.piece.PieceNum, req.piece.PieceMd5)
-		reader, err = digest.NewReader(digest.AlgorithmMD5, io.LimitReader(resp.Body, int64(req.piece.RangeSize)), digest.WithEncoded(req.piece.PieceMd5), digest.WithLogger(req.log))
+		d, err := digest.ParsePieceDigest(req.piece.PieceMd5)
+		if err != nil {
+			_ = closer.Close()
+			req.log.Errorf("parse piece digest error: %s", err.Error())
+			return nil, nil, err
+		}
+
+		reader, err = digest.NewReader(d.Algorithm, io.LimitReader(resp.Body, int64(req.piece.RangeSize)), digest.WithEncoded(d.Encoded), digest.WithLogger(req.log))
 		if err != nil {
 			_ = closer.Close()
 			req.log.Errorf("init digest reader error: %s", err.Error())
@@ -201,16 +307,16 @@ func (p *pieceDownloader) DownloadPiece(ctx context.Context, req *DownloadPieceR
 	return reader, closer, nil
 }
 
-func (p *pieceDownloader) buildDownloadPieceHTTPRequest(ctx context.Context, d *DownloadPieceRequest) (*http.Request, error) {
+func (p *pieceDownloader) buildDownloadPieceHTTPRequest(ctx context.Context, d *DownloadPieceRequest, scheme, addr string) (*http.Request, error) {
 	if len(d.TaskID) <= 3 {
 		return nil, fmt.Errorf("invalid task id")
 	}
 	// FIXME switch to https when tls enabled
 	targetURL := url.URL{
-		Scheme:   p.scheme,
-		Host:     d.DstAddr,
+		Scheme:   scheme,
+		Host:     addr,
 		Path:     fmt.Sprintf("download/%s/%s", d.TaskID[:3], d.TaskID),
-		RawQuery: fmt.Sprintf("peerId=%s", d.DstPid),
+		RawQuery: fmt.Sprintf("peerId=%s&srcPeerId=%s", d.DstPid, d.PeerID),
 	}
 
 	logger.Debugf("built request url: %s", targetURL.String())
@@ -224,3 +330,34 @@ func (p *pieceDownloader) buildDownloadPieceHTTPRequest(ctx context.Context, d *
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 	return req, nil
 }
+
+// parseQUICAddr parses the quic address announced by Alt-Svc header, e.g. h3=":65002"; ma=2592000.
+func parseQUICAddr(addr string, altSvcs []string) (string, bool) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", false
+	}
+
+	for _, altSvc := range altSvcs {
+		for _, value := range strings.Split(altSvc, ",") {
+			protocol, authority, ok := strings.Cut(strings.TrimSpace(value), "=")
+			if !ok || protocol != "h3" {
+				continue
+			}
+
+			authority, _, _ = strings.Cut(authority, ";")
+			altHost, altPort, err := net.SplitHostPort(strings.Trim(authority, `"`))
+			if err != nil || altPort == "" {
+				continue
+			}
+
+			if altHost == "" {
+				altHost = host
+			}
+
+			return net.JoinHostPort(altHost, altPort), true
+		}
+	}
+
+	return "", false
+}
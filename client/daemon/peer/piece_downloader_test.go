@@ -19,6 +19,8 @@ package peer
 import (
 	"context"
 	"crypto/md5"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -43,6 +45,7 @@ import (
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
 	"d7y.io/dragonfly/v2/pkg/source"
 	"d7y.io/dragonfly/v2/pkg/source/clients/httpprotocol"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 func TestPieceDownloader_isConnectionError(t *testing.T) {
@@ -305,3 +308,157 @@ func TestPieceDownloader_DownloadPiece(t *testing.T) {
 		server.Close()
 	}
 }
+
+func TestPieceDownloader_WithTLSTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		encryption string
+		tls        bool
+		expect     func(t *testing.T, data []byte, err error)
+	}{
+		{
+			name:       "download piece over tls",
+			encryption: types.PieceEncryptionRequire,
+			tls:        true,
+			expect: func(t *testing.T, data []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal([]byte("test test "), data)
+			},
+		},
+		{
+			name:       "download piece over plain http from tls server",
+			encryption: types.PieceEncryptionDisable,
+			tls:        true,
+			expect: func(t *testing.T, data []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.Error(err)
+			},
+		},
+		{
+			name:       "prefer encryption downloads piece over tls",
+			encryption: types.PieceEncryptionPrefer,
+			tls:        true,
+			expect: func(t *testing.T, data []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal([]byte("test test "), data)
+			},
+		},
+		{
+			name:       "prefer encryption falls back to plain http",
+			encryption: types.PieceEncryptionPrefer,
+			tls:        false,
+			expect: func(t *testing.T, data []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal([]byte("test test "), data)
+			},
+		},
+		{
+			name:       "require encryption does not fall back to plain http",
+			encryption: types.PieceEncryptionRequire,
+			tls:        false,
+			expect: func(t *testing.T, data []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(headers.ContentLength, "10")
+				if _, err := w.Write([]byte("test test ")); err != nil {
+					t.Error(err)
+				}
+			})
+
+			var server *httptest.Server
+			if tc.tls {
+				server = httptest.NewTLSServer(handler)
+			} else {
+				server = httptest.NewServer(handler)
+			}
+			defer server.Close()
+
+			certPool := x509.NewCertPool()
+			if tc.tls {
+				certPool.AddCert(server.Certificate())
+			}
+			addr, _ := url.Parse(server.URL)
+
+			pd := NewPieceDownloader(30*time.Second, nil, WithTLSTransport(&tls.Config{RootCAs: certPool}, func() string {
+				return tc.encryption
+			}))
+			r, c, err := pd.DownloadPiece(context.Background(), &DownloadPieceRequest{
+				TaskID:  "task-0",
+				DstAddr: addr.Host,
+				piece: &commonv1.PieceInfo{
+					RangeSize: 10,
+				},
+				log: logger.With("test", "test"),
+			})
+			if err != nil {
+				tc.expect(t, nil, err)
+				return
+			}
+			defer c.Close()
+
+			data, err := io.ReadAll(r)
+			tc.expect(t, data, err)
+		})
+	}
+}
+
+func TestPieceDownloader_parseQUICAddr(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		altSvcs  []string
+		expect   string
+		expectOK bool
+	}{
+		{
+			name:     "announce port",
+			addr:     "127.0.0.1:65002",
+			altSvcs:  []string{`h3=":65002"; ma=2592000`},
+			expect:   "127.0.0.1:65002",
+			expectOK: true,
+		},
+		{
+			name:     "announce host and port",
+			addr:     "127.0.0.1:65002",
+			altSvcs:  []string{`h3-29=":65003", h3="127.0.0.2:65004"; ma=2592000`},
+			expect:   "127.0.0.2:65004",
+			expectOK: true,
+		},
+		{
+			name:     "announce other protocols",
+			addr:     "127.0.0.1:65002",
+			altSvcs:  []string{`h2=":443"; ma=2592000`},
+			expectOK: false,
+		},
+		{
+			name:     "announce invalid authority",
+			addr:     "127.0.0.1:65002",
+			altSvcs:  []string{`h3="foo"`},
+			expectOK: false,
+		},
+		{
+			name:     "without announcement",
+			addr:     "127.0.0.1:65002",
+			expectOK: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			addr, ok := parseQUICAddr(tc.addr, tc.altSvcs)
+			assert.Equal(tc.expectOK, ok)
+			assert.Equal(tc.expect, addr)
+		})
+	}
+}
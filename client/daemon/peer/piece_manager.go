@@ -20,6 +20,7 @@ package peer
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -67,6 +68,18 @@ type pieceManager struct {
 	concurrentOption  *config.ConcurrentOption
 	syncPieceViaHTTPS bool
 	certPool          *x509.CertPool
+	enableQUIC        bool
+
+	// encryptionTLSConfig is the tls config of downloading pieces over tls, and encryption returns
+	// the encryption mode of the pieces, they are nil when the encryption is disabled.
+	encryptionTLSConfig *tls.Config
+	encryption          func() string
+
+	// pieceDigestAlgorithm returns the digest algorithm of the pieces downloaded from source.
+	pieceDigestAlgorithm func() string
+
+	// backToSourceLimiter limits the pieces downloaded from source in addition to the download limiter.
+	backToSourceLimiter *rate.Limiter
 }
 
 type PieceManagerOption func(*pieceManager)
@@ -81,7 +94,16 @@ func NewPieceManager(pieceDownloadTimeout time.Duration, opts ...PieceManagerOpt
 		opt(pm)
 	}
 
-	pm.pieceDownloader = NewPieceDownloader(pieceDownloadTimeout, pm.certPool)
+	var pdOpts []PieceDownloaderOption
+	if pm.enableQUIC {
+		pdOpts = append(pdOpts, WithQUICTransport(pieceDownloadTimeout, pm.certPool))
+	}
+
+	if pm.encryption != nil {
+		pdOpts = append(pdOpts, WithTLSTransport(pm.encryptionTLSConfig, pm.encryption))
+	}
+
+	pm.pieceDownloader = NewPieceDownloader(pieceDownloadTimeout, pm.certPool, pdOpts...)
 
 	return pm, nil
 }
@@ -93,6 +115,14 @@ func WithCalculateDigest(enable bool) func(*pieceManager) {
 	}
 }
 
+// WithPieceDigestAlgorithm sets the getter of the digest algorithm of the pieces downloaded from source,
+// the algorithm is formatted into the piece digest, so the peers downloading the pieces use the same one.
+func WithPieceDigestAlgorithm(algorithm func() string) func(*pieceManager) {
+	return func(pm *pieceManager) {
+		pm.pieceDigestAlgorithm = algorithm
+	}
+}
+
 // WithLimiter sets upload rate limiter, the burst size must be bigger than piece size
 func WithLimiter(limiter *rate.Limiter) func(*pieceManager) {
 	return func(manager *pieceManager) {
@@ -101,6 +131,14 @@ func WithLimiter(limiter *rate.Limiter) func(*pieceManager) {
 	}
 }
 
+// WithBackToSourceLimiter sets back-to-source rate limiter, the burst size must be bigger than piece size
+func WithBackToSourceLimiter(limiter *rate.Limiter) func(*pieceManager) {
+	return func(manager *pieceManager) {
+		logger.Infof("set back-to-source limiter %f for piece manager", limiter.Limit())
+		manager.backToSourceLimiter = limiter
+	}
+}
+
 func WithTransportOption(opt *config.TransportOption) func(*pieceManager) {
 	return func(manager *pieceManager) {
 		if opt == nil {
@@ -166,6 +204,23 @@ func WithSyncPieceViaHTTPS(caCertPEM string) func(*pieceManager) {
 	}
 }
 
+// WithEncryption downloads pieces over tls by the encryption mode returned by encryption.
+func WithEncryption(tlsConfig *tls.Config, encryption func() string) func(*pieceManager) {
+	return func(pm *pieceManager) {
+		logger.Infof("enable encryption for piece manager")
+		pm.encryptionTLSConfig = tlsConfig
+		pm.encryption = encryption
+	}
+}
+
+// WithQUIC downloads pieces over quic when the parent announces it.
+func WithQUIC(enable bool) func(*pieceManager) {
+	return func(pm *pieceManager) {
+		logger.Infof("set enableQUIC to %t for piece manager", enable)
+		pm.enableQUIC = enable
+	}
+}
+
 func (pm *pieceManager) DownloadPiece(ctx context.Context, request *DownloadPieceRequest) (*DownloadPieceResult, error) {
 	var result = &DownloadPieceResult{
 		Size:       -1,
@@ -257,9 +312,16 @@ func (pm *pieceManager) processPieceFromSource(pt Task,
 			return
 		}
 	}
+	if pm.backToSourceLimiter != nil {
+		if err = pm.backToSourceLimiter.WaitN(pt.Context(), int(pieceSize)); err != nil {
+			result.FinishTime = time.Now().UnixNano()
+			pt.Log().Errorf("require back-to-source rate limit access error: %s", err)
+			return
+		}
+	}
 	if pm.calculateDigest {
 		pt.Log().Debugf("piece %d calculate digest", pieceNum)
-		reader, _ = digest.NewReader(digest.AlgorithmMD5, reader, digest.WithLogger(pt.Log()))
+		reader, _ = digest.NewReader(pm.digestAlgorithm(), reader, digest.WithLogger(pt.Log()))
 	}
 
 	result.Size, err = pt.GetStorage().WritePiece(
@@ -293,11 +355,24 @@ func (pm *pieceManager) processPieceFromSource(pt Task,
 		return
 	}
 	if pm.calculateDigest {
-		md5 = reader.(digest.Reader).Encoded()
+		md5 = digest.FormatPieceDigest(reader.(digest.Reader).Algorithm(), reader.(digest.Reader).Encoded())
 	}
 	return
 }
 
+// digestAlgorithm returns the digest algorithm of the pieces downloaded from source, md5 is used by default.
+func (pm *pieceManager) digestAlgorithm() string {
+	if pm.pieceDigestAlgorithm == nil {
+		return digest.AlgorithmMD5
+	}
+
+	if algorithm := pm.pieceDigestAlgorithm(); algorithm != "" {
+		return algorithm
+	}
+
+	return digest.AlgorithmMD5
+}
+
 func (pm *pieceManager) DownloadSource(ctx context.Context, pt Task, peerTaskRequest *schedulerv1.PeerTaskRequest, parsedRange *nethttp.Range) error {
 	if peerTaskRequest.UrlMeta == nil {
 		peerTaskRequest.UrlMeta = &commonv1.UrlMeta{
@@ -453,7 +528,7 @@ singleDownload:
 				return err
 			}
 		} else {
-			reader, err = digest.NewReader(digest.AlgorithmMD5, response.Body, digest.WithLogger(pt.Log()))
+			reader, err = digest.NewReader(pm.digestAlgorithm(), response.Body, digest.WithLogger(pt.Log()))
 			if err != nil {
 				log.Errorf("init digest reader error: %s", err.Error())
 				return err
@@ -619,7 +694,7 @@ func detectBackSourceError(err error) error {
 
 func (pm *pieceManager) processPieceFromFile(ctx context.Context, ptm storage.PeerTaskMetadata,
 	tsd storage.TaskStorageDriver, r io.Reader, pieceNum int32, pieceOffset uint64,
-	pieceSize uint32, isLastPiece func(n int64) (int32, int64, bool)) (int64, error) {
+	pieceSize uint32, unknownLength bool, isLastPiece func(n int64) (int32, int64, bool)) (int64, error) {
 	var (
 		n      int64
 		reader = r
@@ -628,11 +703,11 @@ func (pm *pieceManager) processPieceFromFile(ctx context.Context, ptm storage.Pe
 
 	if pm.calculateDigest {
 		log.Debugf("calculate digest in processPieceFromFile")
-		reader, _ = digest.NewReader(digest.AlgorithmMD5, r, digest.WithLogger(log))
+		reader, _ = digest.NewReader(pm.digestAlgorithm(), r, digest.WithLogger(log))
 	}
 	n, err := tsd.WritePiece(ctx,
 		&storage.WritePieceRequest{
-			UnknownLength:    false,
+			UnknownLength:    unknownLength,
 			PeerTaskMetadata: ptm,
 			PieceMetadata: storage.PieceMetadata{
 				Num: pieceNum,
@@ -690,7 +765,7 @@ func (pm *pieceManager) ImportFile(ctx context.Context, ptm storage.PeerTaskMeta
 		}
 
 		log.Debugf("import piece %d", pieceNum)
-		n, er := pm.processPieceFromFile(ctx, ptm, tsd, reader, pieceNum, offset, size, isLastPiece)
+		n, er := pm.processPieceFromFile(ctx, ptm, tsd, reader, pieceNum, offset, size, false, isLastPiece)
 		if er != nil {
 			log.Errorf("import piece %d of task %s error: %s", pieceNum, ptm.TaskID, er)
 			return er
@@ -731,32 +806,40 @@ func (pm *pieceManager) ImportFile(ctx context.Context, ptm storage.PeerTaskMeta
 	return nil
 }
 
+// Import imports the content of reader into storage, when the content length is less than 0,
+// the reader is read piece by piece until EOF, e.g. stdin.
 func (pm *pieceManager) Import(ctx context.Context, ptm storage.PeerTaskMetadata, tsd storage.TaskStorageDriver, contentLength int64, reader io.Reader) error {
 	log := logger.WithTaskAndPeerID(ptm.TaskID, ptm.PeerID)
 	pieceSize := pm.computePieceSize(contentLength)
 	maxPieceNum := util.ComputePieceCount(contentLength, pieceSize)
-
-	for pieceNum := int32(0); pieceNum < maxPieceNum; pieceNum++ {
-		size := pieceSize
-		offset := uint64(pieceNum) * uint64(pieceSize)
-
-		// Calculate piece size for last piece.
-		if contentLength > 0 && int64(offset)+int64(size) > contentLength {
-			size = uint32(contentLength - int64(offset))
-		}
-
-		log.Debugf("import piece %d", pieceNum)
-		n, err := pm.processPieceFromFile(ctx, ptm, tsd, reader, pieceNum, offset, size, func(int64) (int32, int64, bool) {
-			return maxPieceNum, contentLength, pieceNum == maxPieceNum-1
-		})
-		if err != nil {
-			log.Errorf("import piece %d error: %s", pieceNum, err)
+	if contentLength < 0 {
+		var err error
+		if contentLength, maxPieceNum, err = pm.importUnknownLength(ctx, ptm, tsd, pieceSize, reader); err != nil {
 			return err
 		}
+	} else {
+		for pieceNum := int32(0); pieceNum < maxPieceNum; pieceNum++ {
+			size := pieceSize
+			offset := uint64(pieceNum) * uint64(pieceSize)
 
-		if n != int64(size) {
-			log.Errorf("import piece %d size not match, desired: %d, actual: %d", pieceNum, size, n)
-			return storage.ErrShortRead
+			// Calculate piece size for last piece.
+			if contentLength > 0 && int64(offset)+int64(size) > contentLength {
+				size = uint32(contentLength - int64(offset))
+			}
+
+			log.Debugf("import piece %d", pieceNum)
+			n, err := pm.processPieceFromFile(ctx, ptm, tsd, reader, pieceNum, offset, size, false, func(int64) (int32, int64, bool) {
+				return maxPieceNum, contentLength, pieceNum == maxPieceNum-1
+			})
+			if err != nil {
+				log.Errorf("import piece %d error: %s", pieceNum, err)
+				return err
+			}
+
+			if n != int64(size) {
+				log.Errorf("import piece %d size not match, desired: %d, actual: %d", pieceNum, size, n)
+				return storage.ErrShortRead
+			}
 		}
 	}
 
@@ -788,6 +871,39 @@ func (pm *pieceManager) Import(ctx context.Context, ptm storage.PeerTaskMetadata
 	return nil
 }
 
+// importUnknownLength imports the content of reader until EOF, and returns the content length and total pieces.
+func (pm *pieceManager) importUnknownLength(ctx context.Context, ptm storage.PeerTaskMetadata, tsd storage.TaskStorageDriver, pieceSize uint32, reader io.Reader) (int64, int32, error) {
+	var (
+		contentLength int64 = -1
+		totalPieces   int32 = -1
+		log                 = logger.WithTaskAndPeerID(ptm.TaskID, ptm.PeerID)
+	)
+
+	for pieceNum := int32(0); contentLength < 0; pieceNum++ {
+		offset := uint64(pieceNum) * uint64(pieceSize)
+
+		log.Debugf("import piece %d", pieceNum)
+		if _, err := pm.processPieceFromFile(ctx, ptm, tsd, reader, pieceNum, offset, pieceSize, true, func(n int64) (int32, int64, bool) {
+			if n >= int64(pieceSize) {
+				return -1, -1, false
+			}
+
+			// Last piece, piece size maybe 0 when content length is aligned at piece size.
+			contentLength = int64(pieceSize)*int64(pieceNum) + n
+			totalPieces = pieceNum
+			if n > 0 {
+				totalPieces = pieceNum + 1
+			}
+			return totalPieces, contentLength, true
+		}); err != nil {
+			log.Errorf("import piece %d error: %s", pieceNum, err)
+			return -1, -1, err
+		}
+	}
+
+	return contentLength, totalPieces, nil
+}
+
 func (pm *pieceManager) concurrentDownloadSource(ctx context.Context, pt Task, peerTaskRequest *schedulerv1.PeerTaskRequest, parsedRange *nethttp.Range, startPieceNum int32) error {
 	// parsedRange is always exist
 	pieceSize := pm.computePieceSize(parsedRange.Length)
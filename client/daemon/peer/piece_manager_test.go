@@ -486,6 +486,83 @@ func TestPieceManager_DownloadSource(t *testing.T) {
 	}
 }
 
+func TestPieceManager_Import(t *testing.T) {
+	testBytes, err := os.ReadFile(test.File)
+	require.Nil(t, err, "load test file")
+
+	tests := []struct {
+		name          string
+		pieceSize     uint32
+		content       []byte
+		contentLength int64
+	}{
+		{
+			name:          "import with content length",
+			pieceSize:     1024,
+			content:       testBytes,
+			contentLength: int64(len(testBytes)),
+		},
+		{
+			name:          "import without content length",
+			pieceSize:     1024,
+			content:       testBytes,
+			contentLength: -1,
+		},
+		{
+			name:          "import without content length and content is aligned at piece size",
+			pieceSize:     1024,
+			content:       testBytes[:4096],
+			contentLength: -1,
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			storageManager, err := storage.NewStorageManager(
+				config.SimpleLocalTaskStoreStrategy,
+				&config.StorageOption{
+					DataPath: t.TempDir(),
+					TaskExpireTime: clientutil.Duration{
+						Duration: -1 * time.Second,
+					},
+				}, func(request storage.CommonTaskRequest) {}, os.FileMode(0700))
+			assert.Nil(err)
+
+			ptm := storage.PeerTaskMetadata{
+				PeerID: fmt.Sprintf("peer%d", i),
+				TaskID: fmt.Sprintf("task%d", i),
+			}
+			tsd, err := storageManager.RegisterTask(context.Background(), &storage.RegisterTaskRequest{
+				PeerTaskMetadata: ptm,
+			})
+			assert.Nil(err)
+
+			pm, err := NewPieceManager(30 * time.Second)
+			assert.Nil(err)
+			pm.(*pieceManager).computePieceSize = func(length int64) uint32 {
+				return tc.pieceSize
+			}
+
+			assert.Nil(pm.Import(context.Background(), ptm, tsd, tc.contentLength, bytes.NewReader(tc.content)))
+
+			output := t.TempDir() + "/output"
+			assert.Nil(storageManager.Store(context.Background(), &storage.StoreRequest{
+				CommonTaskRequest: storage.CommonTaskRequest{
+					PeerID:      ptm.PeerID,
+					TaskID:      ptm.TaskID,
+					Destination: output,
+				},
+			}))
+
+			outputBytes, err := os.ReadFile(output)
+			assert.Nil(err)
+			assert.Equal(tc.content, outputBytes)
+			assert.NotNil(storageManager.FindCompletedTask(ptm.TaskID))
+		})
+	}
+}
+
 func TestDetectBackSourceError(t *testing.T) {
 	assert := testifyassert.New(t)
 	testCases := []struct {
@@ -541,3 +618,39 @@ func TestDetectBackSourceError(t *testing.T) {
 		})
 	}
 }
+
+func TestPieceManager_digestAlgorithm(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []PieceManagerOption
+		expected string
+	}{
+		{
+			name:     "default algorithm",
+			expected: digest.AlgorithmMD5,
+		},
+		{
+			name: "empty algorithm",
+			opts: []PieceManagerOption{WithPieceDigestAlgorithm(func() string {
+				return ""
+			})},
+			expected: digest.AlgorithmMD5,
+		},
+		{
+			name: "configured algorithm",
+			opts: []PieceManagerOption{WithPieceDigestAlgorithm(func() string {
+				return digest.AlgorithmXXH3
+			})},
+			expected: digest.AlgorithmXXH3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			pm, err := NewPieceManager(time.Second, tc.opts...)
+			assert.Nil(err)
+			assert.Equal(tc.expected, pm.(*pieceManager).digestAlgorithm())
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package peer
+
+import (
+	"math/rand"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// PieceSelectionStrategyRandom selects a random piece of the peer.
+	PieceSelectionStrategyRandom = "random"
+	// PieceSelectionStrategySequential selects the piece with the lowest number of the peer.
+	PieceSelectionStrategySequential = "sequential"
+	// PieceSelectionStrategyRarestFirst selects the piece held by the fewest peers.
+	PieceSelectionStrategyRarestFirst = "rarest-first"
+	// PieceSelectionStrategyRandomWindow selects a random piece within the window of the lowest numbers of the peer.
+	PieceSelectionStrategyRandomWindow = "random-window"
+)
+
+// pieceSelectionWindowSize is the window size of random-window piece selection strategy.
+const pieceSelectionWindowSize = 16
+
+// PieceSelector selects the piece request to download from the piece requests of a peer.
+type PieceSelector interface {
+	// Select returns the index of the selected piece request, availability is the count of peers holding the piece.
+	Select(reqs []*DownloadPieceRequest, availability map[int32]int) int
+}
+
+// NewPieceSelector returns a PieceSelector of the strategy, rand is not thread-safe,
+// the caller should hold the lock when selecting.
+func NewPieceSelector(strategy string, rand *rand.Rand) PieceSelector {
+	switch strategy {
+	case PieceSelectionStrategyRandom, "":
+		return &randomPieceSelector{rand: rand}
+	case PieceSelectionStrategySequential:
+		return &sequentialPieceSelector{}
+	case PieceSelectionStrategyRarestFirst:
+		return &rarestFirstPieceSelector{rand: rand}
+	case PieceSelectionStrategyRandomWindow:
+		return &randomWindowPieceSelector{rand: rand, windowSize: pieceSelectionWindowSize}
+	default:
+		logger.Warnf("piece selection strategy \"%s\" doesn't exist, use random piece selector instead", strategy)
+		return &randomPieceSelector{rand: rand}
+	}
+}
+
+type randomPieceSelector struct {
+	rand *rand.Rand
+}
+
+func (s *randomPieceSelector) Select(reqs []*DownloadPieceRequest, _ map[int32]int) int {
+	return s.rand.Intn(len(reqs))
+}
+
+type sequentialPieceSelector struct{}
+
+func (s *sequentialPieceSelector) Select(reqs []*DownloadPieceRequest, _ map[int32]int) int {
+	var n int
+	for i, req := range reqs {
+		if req.piece.PieceNum < reqs[n].piece.PieceNum {
+			n = i
+		}
+	}
+	return n
+}
+
+type rarestFirstPieceSelector struct {
+	rand *rand.Rand
+}
+
+// Select chooses the piece held by the fewest peers, ties are broken randomly
+// to avoid all peers requesting the same rarest piece.
+func (s *rarestFirstPieceSelector) Select(reqs []*DownloadPieceRequest, availability map[int32]int) int {
+	var (
+		rarest      []int
+		rarestCount int
+	)
+	for i, req := range reqs {
+		count := availability[req.piece.PieceNum]
+		switch {
+		case len(rarest) == 0 || count < rarestCount:
+			rarest = []int{i}
+			rarestCount = count
+		case count == rarestCount:
+			rarest = append(rarest, i)
+		}
+	}
+	return rarest[s.rand.Intn(len(rarest))]
+}
+
+type randomWindowPieceSelector struct {
+	rand       *rand.Rand
+	windowSize int32
+}
+
+// Select chooses a random piece whose number is within the window from the lowest piece number,
+// it keeps the download nearly sequential while spreading the requests of peers.
+func (s *randomWindowPieceSelector) Select(reqs []*DownloadPieceRequest, _ map[int32]int) int {
+	lowest := reqs[0].piece.PieceNum
+	for _, req := range reqs {
+		if req.piece.PieceNum < lowest {
+			lowest = req.piece.PieceNum
+		}
+	}
+
+	var window []int
+	for i, req := range reqs {
+		if req.piece.PieceNum < lowest+s.windowSize {
+			window = append(window, i)
+		}
+	}
+	return window[s.rand.Intn(len(window))]
+}
@@ -0,0 +1,107 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package peer
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	testifyassert "github.com/stretchr/testify/assert"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+)
+
+func TestPieceSelector_Select(t *testing.T) {
+	newRequests := func(pieceNums ...int32) []*DownloadPieceRequest {
+		var reqs []*DownloadPieceRequest
+		for _, pieceNum := range pieceNums {
+			reqs = append(reqs, &DownloadPieceRequest{piece: &commonv1.PieceInfo{PieceNum: pieceNum}})
+		}
+		return reqs
+	}
+
+	tests := []struct {
+		name         string
+		strategy     string
+		reqs         []*DownloadPieceRequest
+		availability map[int32]int
+		expect       func(assert *testifyassert.Assertions, req *DownloadPieceRequest)
+	}{
+		{
+			name:     "sequential selects the lowest piece",
+			strategy: PieceSelectionStrategySequential,
+			reqs:     newRequests(5, 3, 9, 4),
+			expect: func(assert *testifyassert.Assertions, req *DownloadPieceRequest) {
+				assert.Equal(int32(3), req.piece.PieceNum)
+			},
+		},
+		{
+			name:         "rarest-first selects the piece held by the fewest peers",
+			strategy:     PieceSelectionStrategyRarestFirst,
+			reqs:         newRequests(0, 1, 2, 3),
+			availability: map[int32]int{0: 5, 1: 3, 2: 1, 3: 4},
+			expect: func(assert *testifyassert.Assertions, req *DownloadPieceRequest) {
+				assert.Equal(int32(2), req.piece.PieceNum)
+			},
+		},
+		{
+			name:         "rarest-first selects one of the rarest pieces",
+			strategy:     PieceSelectionStrategyRarestFirst,
+			reqs:         newRequests(0, 1, 2, 3),
+			availability: map[int32]int{0: 2, 1: 1, 2: 3, 3: 1},
+			expect: func(assert *testifyassert.Assertions, req *DownloadPieceRequest) {
+				assert.Contains([]int32{1, 3}, req.piece.PieceNum)
+			},
+		},
+		{
+			name:     "random-window selects the piece within the window",
+			strategy: PieceSelectionStrategyRandomWindow,
+			reqs:     newRequests(100, 10, 25, 26, 40),
+			expect: func(assert *testifyassert.Assertions, req *DownloadPieceRequest) {
+				assert.Contains([]int32{10, 25}, req.piece.PieceNum)
+			},
+		},
+		{
+			name:     "random selects any piece",
+			strategy: PieceSelectionStrategyRandom,
+			reqs:     newRequests(0, 1, 2),
+			expect: func(assert *testifyassert.Assertions, req *DownloadPieceRequest) {
+				assert.Contains([]int32{0, 1, 2}, req.piece.PieceNum)
+			},
+		},
+		{
+			name:     "unknown strategy falls back to random",
+			strategy: "foo",
+			reqs:     newRequests(0, 1, 2),
+			expect: func(assert *testifyassert.Assertions, req *DownloadPieceRequest) {
+				assert.Contains([]int32{0, 1, 2}, req.piece.PieceNum)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			selector := NewPieceSelector(tc.strategy, rand.New(rand.NewSource(time.Now().Unix())))
+			for i := 0; i < 10; i++ {
+				tc.expect(assert, tc.reqs[selector.Select(tc.reqs, tc.availability)])
+			}
+		})
+	}
+}
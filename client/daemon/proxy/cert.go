@@ -25,11 +25,24 @@ import (
 	"errors"
 	"math/big"
 	"net"
+	"sync"
 	"time"
 
+	"github.com/golang/groupcache/lru"
+	"golang.org/x/sync/singleflight"
+
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 )
 
+const (
+	// DefaultLeafCertTTL is the default validity period of the generated leaf certificates.
+	DefaultLeafCertTTL = 24 * time.Hour
+
+	// DefaultCertCacheSize is the default max number of the generated leaf certificates in cache.
+	DefaultCertCacheSize = 100
+)
+
 type LeafCertSpec struct {
 	publicKey crypto.PublicKey
 
@@ -39,8 +52,8 @@ type LeafCertSpec struct {
 }
 
 // genLeafCert generates a Leaf TLS certificate and sign it with given CA
-func genLeafCert(ca *tls.Certificate, leafCertSpec *LeafCertSpec, host string) (*tls.Certificate, error) {
-	now := time.Now().Add(-1 * time.Hour).UTC()
+func genLeafCert(ca *tls.Certificate, leafCertSpec *LeafCertSpec, host string, ttl time.Duration) (*tls.Certificate, error) {
+	now := time.Now().UTC()
 	if !ca.Leaf.IsCA {
 		return nil, errors.New("CA cert is not a CA")
 	}
@@ -51,11 +64,13 @@ func genLeafCert(ca *tls.Certificate, leafCertSpec *LeafCertSpec, host string) (
 		return nil, err
 	}
 	tmpl := &x509.Certificate{
-		SerialNumber:          serialNumber,
-		Subject:               pkix.Name{CommonName: host},
-		NotBefore:             now,
-		NotAfter:              now.Add(24 * time.Hour),
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: host},
+		// tolerate the clock skew of clients
+		NotBefore:             now.Add(-1 * time.Hour),
+		NotAfter:              now.Add(ttl),
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageKeyAgreement,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
 		SignatureAlgorithm:    leafCertSpec.signatureAlgorithm,
 	}
@@ -76,3 +91,90 @@ func genLeafCert(ca *tls.Certificate, leafCertSpec *LeafCertSpec, host string) (
 	cert.Leaf, _ = x509.ParseCertificate(newCert)
 	return cert, nil
 }
+
+// leafCertManager generates leaf certificates signed by the CA on the fly and caches them by host.
+type leafCertManager struct {
+	ca   *tls.Certificate
+	spec *LeafCertSpec
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	cache *lru.Cache
+
+	// group merges the concurrent generations of the same host
+	group singleflight.Group
+}
+
+// newLeafCertManager returns a new leafCertManager, ca must be a CA certificate.
+func newLeafCertManager(ca *tls.Certificate, cacheSize int, ttl time.Duration) *leafCertManager {
+	if cacheSize <= 0 {
+		cacheSize = DefaultCertCacheSize
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultLeafCertTTL
+	}
+
+	return &leafCertManager{
+		ca: ca,
+		spec: &LeafCertSpec{
+			publicKey:          ca.Leaf.PublicKey,
+			privateKey:         ca.PrivateKey,
+			signatureAlgorithm: ca.Leaf.SignatureAlgorithm,
+		},
+		ttl:   ttl,
+		cache: lru.New(cacheSize),
+	}
+}
+
+// getCertificate returns the cached leaf certificate of host, or generates a new one
+// when it is not cached or it is about to expire.
+func (m *leafCertManager) getCertificate(host string) (*tls.Certificate, error) {
+	m.mu.Lock()
+	cached, hit := m.cache.Get(host)
+	m.mu.Unlock()
+
+	// renew the certificate before it expires, avoid clients receive an expired certificate
+	if hit && time.Now().Add(m.ttl/10).Before(cached.(*tls.Certificate).Leaf.NotAfter) {
+		logger.Debugf("TLS cert cache hit, cacheKey = <%s>", host)
+		metrics.ProxyLeafCertCount.WithLabelValues("hit").Inc()
+		return cached.(*tls.Certificate), nil
+	}
+
+	cert, err, _ := m.group.Do(host, func() (any, error) {
+		logger.Debugf("Generate temporal leaf TLS cert for host <%s>", host)
+		cert, err := genLeafCert(m.ca, m.spec, host, m.ttl)
+		if err != nil {
+			metrics.ProxyLeafCertCount.WithLabelValues("failed").Inc()
+			return nil, err
+		}
+
+		// Put cert in cache only if there is no error. So all certs in cache are always valid.
+		m.mu.Lock()
+		m.cache.Add(host, cert)
+		m.mu.Unlock()
+		metrics.ProxyLeafCertCount.WithLabelValues("generated").Inc()
+		return cert, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cert.(*tls.Certificate), nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate, the server name in client hello is preferred,
+// and host is used when the client does not send SNI, like requests to ip.
+func (m *leafCertManager) GetCertificate(host string) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		if hello.ServerName != "" {
+			return m.getCertificate(hello.ServerName)
+		}
+
+		if host == "" {
+			return nil, errors.New("server name is empty")
+		}
+
+		return m.getCertificate(host)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCA(t *testing.T) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "dragonfly test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestLeafCertManager_GetCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		host   string
+		hello  *tls.ClientHelloInfo
+		expect func(t *testing.T, m *leafCertManager, cert *tls.Certificate, err error)
+	}{
+		{
+			name:  "generate cert for server name",
+			host:  "example.com",
+			hello: &tls.ClientHelloInfo{ServerName: "registry.example.com"},
+			expect: func(t *testing.T, m *leafCertManager, cert *tls.Certificate, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal([]string{"registry.example.com"}, cert.Leaf.DNSNames)
+				assert.Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, cert.Leaf.ExtKeyUsage)
+				_, err = cert.Leaf.Verify(x509.VerifyOptions{DNSName: "registry.example.com", Roots: roots})
+				assert.NoError(err)
+				assert.WithinDuration(time.Now().Add(DefaultLeafCertTTL), cert.Leaf.NotAfter, time.Minute)
+
+				cached, err := m.getCertificate("registry.example.com")
+				assert.NoError(err)
+				assert.Same(cert, cached)
+			},
+		},
+		{
+			name:  "generate cert for host without server name",
+			host:  "127.0.0.1",
+			hello: &tls.ClientHelloInfo{},
+			expect: func(t *testing.T, m *leafCertManager, cert *tls.Certificate, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Len(cert.Leaf.IPAddresses, 1)
+				assert.Equal("127.0.0.1", cert.Leaf.IPAddresses[0].String())
+			},
+		},
+		{
+			name:  "without server name and host",
+			hello: &tls.ClientHelloInfo{},
+			expect: func(t *testing.T, m *leafCertManager, cert *tls.Certificate, err error) {
+				assert.EqualError(t, err, "server name is empty")
+			},
+		},
+		{
+			name:  "renew cert which is about to expire",
+			ttl:   time.Minute,
+			host:  "example.com",
+			hello: &tls.ClientHelloInfo{},
+			expect: func(t *testing.T, m *leafCertManager, cert *tls.Certificate, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.WithinDuration(time.Now().Add(time.Minute), cert.Leaf.NotAfter, 10*time.Second)
+
+				cert.Leaf.NotAfter = time.Now().Add(time.Second)
+				renewed, err := m.getCertificate("example.com")
+				assert.NoError(err)
+				assert.NotSame(cert, renewed)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newLeafCertManager(ca, 0, tc.ttl)
+			cert, err := m.GetCertificate(tc.host)(tc.hello)
+			tc.expect(t, m, cert, err)
+		})
+	}
+}
+
+func TestLeafCertManager_ConcurrentGetCertificate(t *testing.T) {
+	assert := assert.New(t)
+	m := newLeafCertManager(newTestCA(t), 1, 0)
+
+	var (
+		wg    sync.WaitGroup
+		certs = make([]*tls.Certificate, 10)
+	)
+	for i := range certs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cert, err := m.getCertificate("example.com")
+			assert.NoError(err)
+			certs[i] = cert
+		}(i)
+	}
+	wg.Wait()
+
+	for _, cert := range certs {
+		assert.Equal([]string{"example.com"}, cert.Leaf.DNSNames)
+	}
+	assert.Equal(1, m.cache.Len())
+
+	// evict the cert of example.com when the cache is full
+	_, err := m.getCertificate("foo.example.com")
+	assert.NoError(err)
+	assert.Equal(1, m.cache.Len())
+}
@@ -77,6 +77,34 @@ func (mr *MockManagerMockRecorder) ServeSNI(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServeSNI", reflect.TypeOf((*MockManager)(nil).ServeSNI), arg0)
 }
 
+// ServeSocks5 mocks base method.
+func (m *MockManager) ServeSocks5(arg0 net.Listener) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServeSocks5", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ServeSocks5 indicates an expected call of ServeSocks5.
+func (mr *MockManagerMockRecorder) ServeSocks5(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServeSocks5", reflect.TypeOf((*MockManager)(nil).ServeSocks5), arg0)
+}
+
+// ServeTransparent mocks base method.
+func (m *MockManager) ServeTransparent(arg0 net.Listener, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServeTransparent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ServeTransparent indicates an expected call of ServeTransparent.
+func (mr *MockManagerMockRecorder) ServeTransparent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServeTransparent", reflect.TypeOf((*MockManager)(nil).ServeTransparent), arg0, arg1)
+}
+
 // Stop mocks base method.
 func (m *MockManager) Stop() error {
 	m.ctrl.T.Helper()
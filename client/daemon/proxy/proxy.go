@@ -32,7 +32,6 @@ import (
 	"time"
 
 	"github.com/go-http-utils/headers"
-	"github.com/golang/groupcache/lru"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
@@ -46,6 +45,7 @@ import (
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/metrics"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/signature"
 	"d7y.io/dragonfly/v2/client/daemon/transport"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	pkgstrings "d7y.io/dragonfly/v2/pkg/strings"
@@ -65,6 +65,10 @@ type Proxy struct {
 	// reverse proxy upstream url for the default registry
 	registry *config.RegistryMirror
 
+	// registryUpstreams fails over the registry mirror requests to the fallback upstreams,
+	// it is nil when no fallback is configured
+	registryUpstreams *registryUpstreams
+
 	// proxy rules
 	rules atomic.Value
 
@@ -74,9 +78,15 @@ type Proxy struct {
 	// cert is the certificate used to hijack https proxy requests
 	cert *tls.Certificate
 
-	// certCache is an in-memory cache store for TLS certs used in HTTPS hijack
-	certCache    *lru.Cache
-	cacheRWMutex sync.RWMutex
+	// leafCerts generates and caches the leaf certificates signed by cert used in HTTPS hijack,
+	// it is nil when cert is not a CA
+	leafCerts *leafCertManager
+
+	// leafCertTTL is the validity period of the generated leaf certificates
+	leafCertTTL time.Duration
+
+	// certCacheSize is the max number of the generated leaf certificates in cache
+	certCacheSize int
 
 	// directHandler are used to handle non-proxy requests
 	directHandler http.Handler
@@ -116,6 +126,20 @@ type Proxy struct {
 	// dumpHTTPContent indicates to dump http request header and response header
 	dumpHTTPContent bool
 
+	// rangeCoalescer coalesces the small range requests of the same url into aligned blocks,
+	// it is shared by all the transports of proxy
+	rangeCoalescer *transport.RangeCoalescer
+
+	// sequentialPrefetcher prefetches the following ranges ahead of the sequential range reads,
+	// it is shared by all the transports of proxy
+	sequentialPrefetcher *transport.SequentialPrefetcher
+
+	// signatureVerifier verifies the signatures of the proxied artifacts
+	signatureVerifier signature.Verifier
+
+	// dynconfig provides the application policies requiring the signatures
+	dynconfig config.Dynconfig
+
 	peerIDGenerator peer.IDGenerator
 }
 
@@ -170,6 +194,22 @@ func WithCert(cert *tls.Certificate) Option {
 	}
 }
 
+// WithLeafCertTTL sets the validity period of the leaf certificates generated by the CA
+func WithLeafCertTTL(ttl time.Duration) Option {
+	return func(p *Proxy) *Proxy {
+		p.leafCertTTL = ttl
+		return p
+	}
+}
+
+// WithCertCacheSize sets the max number of the generated leaf certificates in cache
+func WithCertCacheSize(size int) Option {
+	return func(p *Proxy) *Proxy {
+		p.certCacheSize = size
+		return p
+	}
+}
+
 // WithDirectHandler sets the handler for non-proxy requests
 func WithDirectHandler(h *http.ServeMux) Option {
 	return func(p *Proxy) *Proxy {
@@ -262,6 +302,47 @@ func WithDumpHTTPContent(dump bool) Option {
 	}
 }
 
+// WithRangeCoalescing sets the range coalescing option for proxy
+func WithRangeCoalescing(rangeCoalescing config.RangeCoalescingOption) Option {
+	return func(p *Proxy) *Proxy {
+		if rangeCoalescing.Enable {
+			var options []transport.RangeCoalescerOption
+			if rangeCoalescing.Estargz.Enable {
+				options = append(options, transport.WithEstargz(rangeCoalescing.Estargz.Prefetch))
+			}
+			p.rangeCoalescer = transport.NewRangeCoalescer(int64(rangeCoalescing.BlockSize), rangeCoalescing.PrefetchBlocks, options...)
+		}
+		return p
+	}
+}
+
+// WithSequentialPrefetch sets the sequential prefetch option for proxy
+func WithSequentialPrefetch(sequentialPrefetch config.SequentialPrefetchOption) Option {
+	return func(p *Proxy) *Proxy {
+		if sequentialPrefetch.Enable {
+			p.sequentialPrefetcher = transport.NewSequentialPrefetcher(int64(sequentialPrefetch.Window),
+				sequentialPrefetch.Threshold, int64(sequentialPrefetch.MinContentLength))
+		}
+		return p
+	}
+}
+
+// WithSignatureVerifier sets the verifier of the signatures of the proxied artifacts
+func WithSignatureVerifier(v signature.Verifier) Option {
+	return func(p *Proxy) *Proxy {
+		p.signatureVerifier = v
+		return p
+	}
+}
+
+// WithDynconfig sets the dynconfig providing the application policies
+func WithDynconfig(d config.Dynconfig) Option {
+	return func(p *Proxy) *Proxy {
+		p.dynconfig = d
+		return p
+	}
+}
+
 // NewProxy returns a new transparent proxy from the given options
 func NewProxy(options ...Option) (*Proxy, error) {
 	return NewProxyWithOptions(options...)
@@ -272,16 +353,30 @@ func NewProxyWithOptions(options ...Option) (*Proxy, error) {
 	proxy := &Proxy{
 		directHandler: http.NewServeMux(),
 		tracer:        otel.Tracer("dfget-daemon-proxy"),
-		certCache:     lru.New(100),
 	}
 
 	for _, opt := range options {
 		opt(proxy)
 	}
 
+	if proxy.cert != nil && proxy.cert.Leaf != nil && proxy.cert.Leaf.IsCA {
+		proxy.leafCerts = newLeafCertManager(proxy.cert, proxy.certCacheSize, proxy.leafCertTTL)
+	}
+
 	if proxy.transport == nil {
 		proxy.transport = proxy.newTransport(nil)
 	}
+
+	if proxy.registry != nil && proxy.registry.Remote != nil && proxy.registry.Remote.URL != nil &&
+		len(proxy.registry.Fallbacks) > 0 {
+		upstreams, err := newRegistryUpstreams(proxy.registry, proxy.newMirrorTransport)
+		if err != nil {
+			return nil, err
+		}
+
+		proxy.registryUpstreams = upstreams
+		go upstreams.serve()
+	}
 	return proxy, nil
 }
 
@@ -293,6 +388,11 @@ func isBasicAuthMatch(basicAuth *config.BasicAuth, user, pass string) bool {
 
 // ServeHTTP implements http.Handler.ServeHTTP
 func (proxy *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	proxy.serveHTTP(w, r, true)
+}
+
+// serveHTTP serves the proxy requests, the basic auth is checked when authenticate is true.
+func (proxy *Proxy) serveHTTP(w http.ResponseWriter, r *http.Request, authenticate bool) {
 	metrics.ProxyRequestCount.WithLabelValues(r.Method).Add(1)
 	metrics.ProxyRequestRunningCount.WithLabelValues(r.Method).Add(1)
 	defer metrics.ProxyRequestRunningCount.WithLabelValues(r.Method).Sub(1)
@@ -310,7 +410,7 @@ func (proxy *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r = r.WithContext(ctx)
 
 	// check authenticity
-	if proxy.basicAuth != nil {
+	if authenticate && proxy.basicAuth != nil {
 		user, pass, ok := proxyBasicAuth(r)
 		if !ok {
 			status := http.StatusProxyAuthRequired
@@ -436,36 +536,12 @@ func (proxy *Proxy) handleHTTPS(w http.ResponseWriter, r *http.Request) {
 
 	logger.Debugf("hijack https request to %s", r.Host)
 
+	host, _, _ := net.SplitHostPort(r.Host)
+	cConfig.ServerName = host
+
 	sConfig := new(tls.Config)
-	if proxy.cert.Leaf != nil && proxy.cert.Leaf.IsCA {
-		leafCertSpec := LeafCertSpec{
-			proxy.cert.Leaf.PublicKey,
-			proxy.cert.PrivateKey,
-			proxy.cert.Leaf.SignatureAlgorithm}
-		host, _, _ := net.SplitHostPort(r.Host)
-		sConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
-			cConfig.ServerName = host
-			// It's assumed that `hello.ServerName` is always same as `host`, in practice.
-			cacheKey := host
-			proxy.cacheRWMutex.RLock()
-			cached, hit := proxy.certCache.Get(cacheKey)
-			proxy.cacheRWMutex.RUnlock()
-			if hit && time.Now().Before(cached.(*tls.Certificate).Leaf.NotAfter) { // If cache hit and the cert is not expired
-				logger.Debugf("TLS cert cache hit, cacheKey = <%s>", cacheKey)
-				return cached.(*tls.Certificate), nil
-			}
-			logger.Debugf("Generate temporal leaf TLS cert for ServerName <%s>, host <%s>", hello.ServerName, host)
-			cert, err := genLeafCert(proxy.cert, &leafCertSpec, host)
-			if err == nil {
-				// Put cert in cache only if there is no error. So all certs in cache are always valid.
-				// But certs in cache maybe expired (After 24 hours, see the default duration of generated certs)
-				proxy.cacheRWMutex.Lock()
-				proxy.certCache.Add(cacheKey, cert)
-				proxy.cacheRWMutex.Unlock()
-			}
-			// If err != nil, means unrecoverable error happened in genLeafCert(...)
-			return cert, err
-		}
+	if proxy.leafCerts != nil {
+		sConfig.GetCertificate = proxy.leafCerts.GetCertificate(host)
 	} else {
 		sConfig.Certificates = []tls.Certificate{*proxy.cert}
 	}
@@ -521,34 +597,36 @@ func (proxy *Proxy) newTransport(tlsConfig *tls.Config) http.RoundTripper {
 		transport.WithPeerIDGenerator(proxy.peerIDGenerator),
 		transport.WithPeerTaskManager(proxy.peerTaskManager),
 		transport.WithTLS(tlsConfig),
-		transport.WithCondition(proxy.shouldUseDragonfly),
+		transport.WithPolicy(proxy.requestPolicy),
 		transport.WithDefaultFilter(proxy.defaultFilter),
 		transport.WithDefaultTag(proxy.defaultTag),
 		transport.WithDefaultApplication(proxy.defaultApplication),
 		transport.WithDefaultPriority(proxy.defaultPriority),
 		transport.WithDumpHTTPContent(proxy.dumpHTTPContent),
+		transport.WithRangeCoalescer(proxy.rangeCoalescer),
+		transport.WithSequentialPrefetcher(proxy.sequentialPrefetcher),
+		transport.WithSignatureVerifier(proxy.signatureVerifier),
+		transport.WithDynconfig(proxy.dynconfig),
 	)
 	return rt
 }
 
 func (proxy *Proxy) mirrorRegistry(w http.ResponseWriter, r *http.Request) {
-	reverseProxy := newReverseProxy(proxy.registry)
-	t, err := transport.New(
-		transport.WithPeerIDGenerator(proxy.peerIDGenerator),
-		transport.WithPeerTaskManager(proxy.peerTaskManager),
-		transport.WithTLS(proxy.registry.TLSConfig()),
-		transport.WithCondition(proxy.shouldUseDragonflyForMirror),
-		transport.WithDefaultFilter(proxy.defaultFilter),
-		transport.WithDefaultTag(proxy.defaultTag),
-		transport.WithDefaultApplication(proxy.defaultApplication),
-		transport.WithDefaultPriority(proxy.defaultPriority),
-		transport.WithDumpHTTPContent(proxy.dumpHTTPContent),
-	)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to get transport: %v", err), http.StatusInternalServerError)
+	var reverseProxy *httputil.ReverseProxy
+	if proxy.registryUpstreams != nil &&
+		!(proxy.registry.DynamicRemote && r.Header.Get(config.HeaderDragonflyRegistry) != "") {
+		reverseProxy = newUpstreamsReverseProxy(proxy.registryUpstreams)
+	} else {
+		t, err := proxy.newMirrorTransport(proxy.registry.TLSConfig())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to get transport: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		reverseProxy = newReverseProxy(proxy.registry)
+		reverseProxy.Transport = t
 	}
 
-	reverseProxy.Transport = t
 	reverseProxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
 		rw.WriteHeader(http.StatusInternalServerError)
 		// write error string to response body
@@ -559,6 +637,25 @@ func (proxy *Proxy) mirrorRegistry(w http.ResponseWriter, r *http.Request) {
 	reverseProxy.ServeHTTP(w, r)
 }
 
+// newMirrorTransport returns the transport of registry mirror with the tls config.
+func (proxy *Proxy) newMirrorTransport(tlsConfig *tls.Config) (http.RoundTripper, error) {
+	return transport.New(
+		transport.WithPeerIDGenerator(proxy.peerIDGenerator),
+		transport.WithPeerTaskManager(proxy.peerTaskManager),
+		transport.WithTLS(tlsConfig),
+		transport.WithPolicy(proxy.requestPolicyForMirror),
+		transport.WithDefaultFilter(proxy.defaultFilter),
+		transport.WithDefaultTag(proxy.defaultTag),
+		transport.WithDefaultApplication(proxy.defaultApplication),
+		transport.WithDefaultPriority(proxy.defaultPriority),
+		transport.WithDumpHTTPContent(proxy.dumpHTTPContent),
+		transport.WithRangeCoalescer(proxy.rangeCoalescer),
+		transport.WithSequentialPrefetcher(proxy.sequentialPrefetcher),
+		transport.WithSignatureVerifier(proxy.signatureVerifier),
+		transport.WithDynconfig(proxy.dynconfig),
+	)
+}
+
 // remoteConfig returns the tls.Config used to connect to the given remote host.
 // If the host should not be hijacked, and it will return nil.
 func (proxy *Proxy) remoteConfig(host string) *tls.Config {
@@ -576,9 +673,12 @@ func (proxy *Proxy) remoteConfig(host string) *tls.Config {
 
 // checkWhiteList check proxy white list.
 func (proxy *Proxy) checkWhiteList(r *http.Request) bool {
+	return proxy.checkWhiteListHost(r.URL.Hostname(), r.URL.Port())
+}
+
+// checkWhiteListHost check proxy white list with host and port.
+func (proxy *Proxy) checkWhiteListHost(host, port string) bool {
 	whiteList := proxy.whiteList
-	host := r.URL.Hostname()
-	port := r.URL.Port()
 
 	// No whitelist
 	if len(whiteList) <= 0 {
@@ -608,8 +708,20 @@ func (proxy *Proxy) checkWhiteList(r *http.Request) bool {
 // also changes the scheme of the given request if the matched rule has
 // UseHTTPS = true
 func (proxy *Proxy) shouldUseDragonfly(req *http.Request) bool {
+	return proxy.requestPolicy(req).UseDragonfly
+}
+
+// requestPolicy returns the policy of the first matched rule to proxy a request. It
+// also rewrites the given request with UseHTTPS and Redirect of the matched rule
+// unless the policy is bypass.
+func (proxy *Proxy) requestPolicy(req *http.Request) transport.Policy {
 	for _, rule := range proxy.rules.Load().([]*config.ProxyRule) {
 		if rule.Match(req.URL.String()) {
+			policy := rule.GetPolicy()
+			if policy == config.ProxyPolicyBypass {
+				return transport.Policy{Limiter: rule.Limiter()}
+			}
+
 			if rule.UseHTTPS {
 				req.URL.Scheme = schemaHTTPS
 			}
@@ -617,7 +729,7 @@ func (proxy *Proxy) shouldUseDragonfly(req *http.Request) bool {
 				u, err := url.Parse(rule.Regx.ReplaceAllString(req.URL.String(), rule.Redirect))
 				if err != nil {
 					logger.Errorf("failed to rewrite url", err)
-					return false
+					return transport.Policy{}
 				}
 				req.URL = u
 				req.Host = req.URL.Host
@@ -628,24 +740,34 @@ func (proxy *Proxy) shouldUseDragonfly(req *http.Request) bool {
 			}
 
 			if req.Method != http.MethodGet {
-				return false
+				return transport.Policy{Limiter: rule.Limiter()}
+			}
+
+			return transport.Policy{
+				UseDragonfly: policy == config.ProxyPolicyP2P || policy == config.ProxyPolicyCacheOnly,
+				CacheOnly:    policy == config.ProxyPolicyCacheOnly,
+				Limiter:      rule.Limiter(),
 			}
-			return !rule.Direct
 		}
 	}
-	return false
+	return transport.Policy{}
 }
 
 // shouldUseDragonflyForMirror returns whether we should use dragonfly to proxy a request
 // when we use registry mirror.
 func (proxy *Proxy) shouldUseDragonflyForMirror(req *http.Request) bool {
+	return proxy.requestPolicyForMirror(req).UseDragonfly
+}
+
+// requestPolicyForMirror returns the policy to proxy a request when we use registry mirror.
+func (proxy *Proxy) requestPolicyForMirror(req *http.Request) transport.Policy {
 	if proxy.registry == nil || proxy.registry.Direct {
-		return false
+		return transport.Policy{}
 	}
 	if proxy.registry.UseProxies {
-		return proxy.shouldUseDragonfly(req)
+		return proxy.requestPolicy(req)
 	}
-	return transport.NeedUseDragonfly(req)
+	return transport.Policy{UseDragonfly: transport.NeedUseDragonfly(req)}
 }
 
 // tunnelHTTPS handles the CONNECT request and proxy the https request through http tunnel.
@@ -36,6 +36,7 @@ import (
 
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/signature"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 )
 
@@ -43,6 +44,8 @@ type Manager interface {
 	ConfigWatcher
 	Serve(net.Listener) error
 	ServeSNI(net.Listener) error
+	ServeTransparent(net.Listener, string) error
+	ServeSocks5(net.Listener) error
 	Stop() error
 	IsEnabled() bool
 }
@@ -59,7 +62,8 @@ type proxyManager struct {
 
 var _ Manager = (*proxyManager)(nil)
 
-func NewProxyManager(peerHost *schedulerv1.PeerHost, peerTaskManager peer.TaskManager, proxyOption *config.ProxyOption) (Manager, error) {
+func NewProxyManager(peerHost *schedulerv1.PeerHost, peerTaskManager peer.TaskManager, signatureVerifier signature.Verifier,
+	dynconfig config.Dynconfig, proxyOption *config.ProxyOption) (Manager, error) {
 	// proxy is option, when nil, just disable it
 	if proxyOption == nil {
 		logger.Infof("proxy config is empty, disabled")
@@ -83,6 +87,10 @@ func NewProxyManager(peerHost *schedulerv1.PeerHost, peerTaskManager peer.TaskMa
 		WithDefaultPriority(proxyOption.DefaultPriority),
 		WithBasicAuth(proxyOption.BasicAuth),
 		WithDumpHTTPContent(proxyOption.DumpHTTPContent),
+		WithRangeCoalescing(proxyOption.RangeCoalescing),
+		WithSequentialPrefetch(proxyOption.SequentialPrefetch),
+		WithSignatureVerifier(signatureVerifier),
+		WithDynconfig(dynconfig),
 	}
 
 	if registry != nil {
@@ -106,7 +114,10 @@ func NewProxyManager(peerHost *schedulerv1.PeerHost, peerTaskManager peer.TaskMa
 	}
 
 	if hijackHTTPS != nil {
-		options = append(options, WithHTTPSHosts(hijackHTTPS.Hosts...))
+		options = append(options,
+			WithHTTPSHosts(hijackHTTPS.Hosts...),
+			WithLeafCertTTL(hijackHTTPS.LeafCertTTL),
+			WithCertCacheSize(hijackHTTPS.CertCacheSize))
 		if hijackHTTPS.Cert != "" && hijackHTTPS.Key != "" {
 			cert, err := certFromFile(hijackHTTPS.Cert, hijackHTTPS.Key)
 			if err != nil {
@@ -142,6 +153,9 @@ func (pm *proxyManager) ServeSNI(listener net.Listener) error {
 }
 
 func (pm *proxyManager) Stop() error {
+	if pm.Proxy != nil && pm.Proxy.registryUpstreams != nil {
+		pm.Proxy.registryUpstreams.stop()
+	}
 	return pm.Server.Shutdown(context.Background())
 }
 
@@ -24,7 +24,6 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"sync"
-	"time"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 )
@@ -68,35 +67,14 @@ func handshakeTLSConn(clientConn net.Conn, config *tls.Config) (net.Conn, error)
 func (proxy *Proxy) handleTLSConn(clientConn net.Conn, port int) {
 	var serverName string
 	sConfig := new(tls.Config)
-	if !proxy.cert.Leaf.IsCA {
+	if proxy.leafCerts == nil {
 		sConfig.Certificates = []tls.Certificate{*proxy.cert}
 	} else {
-		leafCertSpec := LeafCertSpec{
-			proxy.cert.Leaf.PublicKey,
-			proxy.cert.PrivateKey,
-			proxy.cert.Leaf.SignatureAlgorithm}
+		getCertificate := proxy.leafCerts.GetCertificate("")
 		sConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 			// It's assumed that `hello.ServerName` is always same as `host`, in practice.
 			serverName = hello.ServerName
-
-			proxy.cacheRWMutex.RLock()
-			cached, hit := proxy.certCache.Get(serverName)
-			proxy.cacheRWMutex.RUnlock()
-			if hit && time.Now().Before(cached.(*tls.Certificate).Leaf.NotAfter) { // If cache hit and the cert is not expired
-				logger.Debugf("TLS Cache hit, cacheKey = <%s>", serverName)
-				return cached.(*tls.Certificate), nil
-			}
-			logger.Debugf("Generate temporal leaf TLS cert for ServerName <%s>", hello.ServerName)
-			cert, err := genLeafCert(proxy.cert, &leafCertSpec, serverName)
-			if err == nil {
-				// Put cert in cache only if there is no error. So all certs in cache are always valid.
-				// But certs in cache maybe expired (After 24 hours, see the default duration of generated certs)
-				proxy.cacheRWMutex.Lock()
-				proxy.certCache.Add(serverName, cert)
-				proxy.cacheRWMutex.Unlock()
-			}
-			// If err != nil, means unrecoverable error happened in genLeafCert(...)
-			return cert, err
+			return getCertificate(hello)
 		}
 	}
 
@@ -0,0 +1,236 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// The socks5 protocol, see https://datatracker.ietf.org/doc/html/rfc1928
+// and https://datatracker.ietf.org/doc/html/rfc1929.
+const (
+	socks5Version          = 0x05
+	socks5PasswordVersion  = 0x01
+	socks5AuthNone         = 0x00
+	socks5AuthPassword     = 0x02
+	socks5AuthNoAcceptable = 0xff
+	socks5CmdConnect       = 0x01
+	socks5AddrIPv4         = 0x01
+	socks5AddrDomain       = 0x03
+	socks5AddrIPv6         = 0x04
+
+	socks5ReplySucceeded           = 0x00
+	socks5ReplyGeneralFailure      = 0x01
+	socks5ReplyNotAllowed          = 0x02
+	socks5ReplyCommandNotSupported = 0x07
+	socks5ReplyAddrNotSupported    = 0x08
+
+	// socks5HandshakeTimeout is the timeout of socks5 negotiation.
+	socks5HandshakeTimeout = 10 * time.Second
+)
+
+// ServeSocks5 serves the socks5 connections, only CONNECT command is supported. The connections
+// to the CONNECT targets are handled like the transparent proxy connections, so the proxy rules,
+// registry mirror and hijackHTTPS hosts are applied to them.
+func (proxy *Proxy) ServeSocks5(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+
+			logger.Errorf("accept socks5 connection error: %s", err)
+			continue
+		}
+
+		go proxy.handleSocks5Conn(conn)
+	}
+}
+
+func (proxy *Proxy) handleSocks5Conn(conn net.Conn) {
+	if err := conn.SetDeadline(time.Now().Add(socks5HandshakeTimeout)); err != nil {
+		logger.Errorf("set deadline of %s error: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	target, err := proxy.socks5Handshake(conn)
+	if err != nil {
+		logger.Errorf("socks5 handshake with %s error: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		logger.Errorf("reset deadline of %s error: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	logger.Debugf("socks5 connect %s from %s", target, conn.RemoteAddr())
+	proxy.handleTargetConn(conn, target)
+}
+
+// socks5Handshake negotiates the authentication method and reads the CONNECT request,
+// it returns the target address of request.
+func (proxy *Proxy) socks5Handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+
+	if header[0] != socks5Version {
+		return "", fmt.Errorf("socks version %d is not supported", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", err
+	}
+
+	method := byte(socks5AuthNone)
+	if proxy.basicAuth != nil {
+		method = socks5AuthPassword
+	}
+
+	if !bytes.Contains(methods, []byte{method}) {
+		_, _ = conn.Write([]byte{socks5Version, socks5AuthNoAcceptable})
+		return "", errors.New("no acceptable authentication method")
+	}
+
+	if _, err := conn.Write([]byte{socks5Version, method}); err != nil {
+		return "", err
+	}
+
+	if method == socks5AuthPassword {
+		if err := proxy.socks5Authenticate(conn); err != nil {
+			return "", err
+		}
+	}
+
+	// VER, CMD, RSV and ATYP
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+
+	if request[0] != socks5Version {
+		return "", fmt.Errorf("socks version %d is not supported", request[0])
+	}
+
+	var host string
+	switch request[3] {
+	case socks5AddrIPv4, socks5AddrIPv6:
+		size := net.IPv4len
+		if request[3] == socks5AddrIPv6 {
+			size = net.IPv6len
+		}
+
+		ip := make([]byte, size)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case socks5AddrDomain:
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return "", err
+		}
+
+		domain := make([]byte, size[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", err
+		}
+		host = string(domain)
+	default:
+		_ = writeSocks5Reply(conn, socks5ReplyAddrNotSupported)
+		return "", fmt.Errorf("address type %d is not supported", request[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+	portStr := strconv.Itoa(int(port[0])<<8 | int(port[1]))
+
+	if request[1] != socks5CmdConnect {
+		_ = writeSocks5Reply(conn, socks5ReplyCommandNotSupported)
+		return "", fmt.Errorf("command %d is not supported", request[1])
+	}
+
+	if !proxy.checkWhiteListHost(host, portStr) {
+		_ = writeSocks5Reply(conn, socks5ReplyNotAllowed)
+		return "", fmt.Errorf("%s is not in whitelist", host)
+	}
+
+	if err := writeSocks5Reply(conn, socks5ReplySucceeded); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, portStr), nil
+}
+
+// socks5Authenticate authenticates the client with username and password in basic auth.
+func (proxy *Proxy) socks5Authenticate(conn net.Conn) error {
+	// VER and ULEN
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+
+	if header[0] != socks5PasswordVersion {
+		return fmt.Errorf("password authentication version %d is not supported", header[0])
+	}
+
+	username := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, username); err != nil {
+		return err
+	}
+
+	size := make([]byte, 1)
+	if _, err := io.ReadFull(conn, size); err != nil {
+		return err
+	}
+
+	password := make([]byte, size[0])
+	if _, err := io.ReadFull(conn, password); err != nil {
+		return err
+	}
+
+	if !isBasicAuthMatch(proxy.basicAuth, string(username), string(password)) {
+		_, _ = conn.Write([]byte{socks5PasswordVersion, socks5ReplyGeneralFailure})
+		return fmt.Errorf("mismatch auth info of user %s", username)
+	}
+
+	_, err := conn.Write([]byte{socks5PasswordVersion, socks5ReplySucceeded})
+	return err
+}
+
+// writeSocks5Reply writes the reply of CONNECT request, the bound address is not used by clients.
+func writeSocks5Reply(conn net.Conn, reply byte) error {
+	_, err := conn.Write([]byte{socks5Version, reply, 0x00, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
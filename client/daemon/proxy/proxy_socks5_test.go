@@ -0,0 +1,193 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+
+	"d7y.io/dragonfly/v2/client/config"
+)
+
+// socks5ConnectRequest returns the CONNECT request with domain address.
+func socks5ConnectRequest(cmd byte, host string, port int) []byte {
+	request := []byte{socks5Version, cmd, 0x00, socks5AddrDomain, byte(len(host))}
+	request = append(request, host...)
+	return append(request, byte(port>>8), byte(port))
+}
+
+func TestProxy_Socks5Handshake(t *testing.T) {
+	tests := []struct {
+		name      string
+		basicAuth *config.BasicAuth
+		whiteList []*config.WhiteList
+		request   []byte
+		expect    func(t *testing.T, target string, err error, reply []byte)
+	}{
+		{
+			name:    "connect without authentication",
+			request: append([]byte{socks5Version, 1, socks5AuthNone}, socks5ConnectRequest(socks5CmdConnect, "example.com", 80)...),
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("example.com:80", target)
+				assert.Equal([]byte{socks5Version, socks5AuthNone, socks5Version, socks5ReplySucceeded}, reply[:4])
+			},
+		},
+		{
+			name: "connect ipv4 address",
+			request: []byte{socks5Version, 1, socks5AuthNone,
+				socks5Version, socks5CmdConnect, 0x00, socks5AddrIPv4, 127, 0, 0, 1, 0x01, 0xbb},
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("127.0.0.1:443", target)
+			},
+		},
+		{
+			name:      "connect with password authentication",
+			basicAuth: &config.BasicAuth{Username: "foo", Password: "bar"},
+			request: append([]byte{socks5Version, 2, socks5AuthNone, socks5AuthPassword,
+				socks5PasswordVersion, 3, 'f', 'o', 'o', 3, 'b', 'a', 'r'}, socks5ConnectRequest(socks5CmdConnect, "example.com", 80)...),
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("example.com:80", target)
+				assert.Equal([]byte{socks5Version, socks5AuthPassword, socks5PasswordVersion, socks5ReplySucceeded}, reply[:4])
+			},
+		},
+		{
+			name:      "connect with wrong password",
+			basicAuth: &config.BasicAuth{Username: "foo", Password: "bar"},
+			request: []byte{socks5Version, 1, socks5AuthPassword,
+				socks5PasswordVersion, 3, 'f', 'o', 'o', 3, 'b', 'a', 'z'},
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert := assert.New(t)
+				assert.EqualError(err, "mismatch auth info of user foo")
+				assert.Equal([]byte{socks5Version, socks5AuthPassword, socks5PasswordVersion, socks5ReplyGeneralFailure}, reply)
+			},
+		},
+		{
+			name:      "connect without required authentication",
+			basicAuth: &config.BasicAuth{Username: "foo", Password: "bar"},
+			request:   []byte{socks5Version, 1, socks5AuthNone},
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert := assert.New(t)
+				assert.EqualError(err, "no acceptable authentication method")
+				assert.Equal([]byte{socks5Version, socks5AuthNoAcceptable}, reply)
+			},
+		},
+		{
+			name:    "bind command is not supported",
+			request: append([]byte{socks5Version, 1, socks5AuthNone}, socks5ConnectRequest(0x02, "example.com", 80)...),
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert := assert.New(t)
+				assert.EqualError(err, "command 2 is not supported")
+				assert.Equal(byte(socks5ReplyCommandNotSupported), reply[3])
+			},
+		},
+		{
+			name:      "target is not in whitelist",
+			whiteList: []*config.WhiteList{{Host: "foo.com"}},
+			request:   append([]byte{socks5Version, 1, socks5AuthNone}, socks5ConnectRequest(socks5CmdConnect, "example.com", 80)...),
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert := assert.New(t)
+				assert.EqualError(err, "example.com is not in whitelist")
+				assert.Equal(byte(socks5ReplyNotAllowed), reply[3])
+			},
+		},
+		{
+			name:    "socks4 is not supported",
+			request: []byte{0x04, 0x01},
+			expect: func(t *testing.T, target string, err error, reply []byte) {
+				assert.EqualError(t, err, "socks version 4 is not supported")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			proxy, err := NewProxy(WithBasicAuth(tc.basicAuth), WithWhiteList(tc.whiteList))
+			assert.NoError(t, err)
+
+			clientConn, serverConn := net.Pipe()
+			go func() {
+				_, _ = clientConn.Write(tc.request)
+			}()
+
+			var reply []byte
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				reply, _ = io.ReadAll(clientConn)
+			}()
+
+			target, err := proxy.socks5Handshake(serverConn)
+			serverConn.Close()
+			<-done
+			tc.expect(t, target, err, reply)
+		})
+	}
+}
+
+func TestProxy_ServeSocks5(t *testing.T) {
+	assert := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	defer server.Close()
+	dst := newTransparentTestServer(t, server)
+
+	proxy, err := NewProxy(WithPeerHost(&schedulerv1.PeerHost{}), WithRules(nil))
+	assert.NoError(err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(err)
+	defer ln.Close()
+	go proxy.ServeSocks5(ln)
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	assert.NoError(err)
+	defer conn.Close()
+
+	_, err = conn.Write(append([]byte{socks5Version, 1, socks5AuthNone}, socks5ConnectRequest(socks5CmdConnect, "127.0.0.1", dst.Port)...))
+	assert.NoError(err)
+	reply := make([]byte, 12)
+	_, err = io.ReadFull(conn, reply)
+	assert.NoError(err)
+	assert.Equal(byte(socks5ReplySucceeded), reply[3])
+
+	req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:"+strconv.Itoa(dst.Port)+"/foo", nil)
+	assert.NoError(err)
+	assert.NoError(req.Write(conn))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	assert.NoError(err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	assert.NoError(err)
+	assert.Equal("/foo", string(data))
+}
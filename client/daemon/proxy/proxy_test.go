@@ -23,8 +23,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/transport"
 )
 
 type testItem struct {
@@ -235,3 +237,100 @@ func TestMatchWithRedirect(t *testing.T) {
 		TestMirror(t)
 
 }
+
+func TestRequestPolicy(t *testing.T) {
+	newRule := func(regx, policy, redirect string, rateLimit float64) *config.ProxyRule {
+		rule, err := config.NewProxyRule(regx, false, false, redirect)
+		assert.NoError(t, err)
+		rule.Policy = policy
+		rule.RateLimit.Limit = rate.Limit(rateLimit)
+		return rule
+	}
+
+	tests := []struct {
+		name   string
+		rules  []*config.ProxyRule
+		method string
+		url    string
+		expect func(t *testing.T, req *http.Request, policy transport.Policy)
+	}{
+		{
+			name:   "p2p policy",
+			rules:  []*config.ProxyRule{newRule("/blobs/sha256/", "", "", 0)},
+			method: http.MethodGet,
+			url:    "http://h/v2/blobs/sha256/xxx",
+			expect: func(t *testing.T, req *http.Request, policy transport.Policy) {
+				assert := assert.New(t)
+				assert.True(policy.UseDragonfly)
+				assert.False(policy.CacheOnly)
+				assert.Nil(policy.Limiter)
+			},
+		},
+		{
+			name:   "cache only policy with rate limit",
+			rules:  []*config.ProxyRule{newRule("/blobs/sha256/", config.ProxyPolicyCacheOnly, "", 1024)},
+			method: http.MethodGet,
+			url:    "http://h/v2/blobs/sha256/xxx",
+			expect: func(t *testing.T, req *http.Request, policy transport.Policy) {
+				assert := assert.New(t)
+				assert.True(policy.UseDragonfly)
+				assert.True(policy.CacheOnly)
+				assert.NotNil(policy.Limiter)
+				assert.Equal(rate.Limit(1024), policy.Limiter.Limit())
+			},
+		},
+		{
+			name:   "direct policy with redirect",
+			rules:  []*config.ProxyRule{newRule("/blobs/sha256/", config.ProxyPolicyDirect, "r", 0)},
+			method: http.MethodGet,
+			url:    "http://h/v2/blobs/sha256/xxx",
+			expect: func(t *testing.T, req *http.Request, policy transport.Policy) {
+				assert := assert.New(t)
+				assert.False(policy.UseDragonfly)
+				assert.Equal("http://r/v2/blobs/sha256/xxx", req.URL.String())
+			},
+		},
+		{
+			name:   "bypass policy without redirect",
+			rules:  []*config.ProxyRule{newRule("/blobs/sha256/", config.ProxyPolicyBypass, "r", 0), newRule("/blobs/", "", "", 0)},
+			method: http.MethodGet,
+			url:    "http://h/v2/blobs/sha256/xxx",
+			expect: func(t *testing.T, req *http.Request, policy transport.Policy) {
+				assert := assert.New(t)
+				assert.False(policy.UseDragonfly)
+				assert.Equal("http://h/v2/blobs/sha256/xxx", req.URL.String())
+			},
+		},
+		{
+			name:   "p2p policy with post method",
+			rules:  []*config.ProxyRule{newRule("/blobs/sha256/", config.ProxyPolicyP2P, "", 1024)},
+			method: http.MethodPost,
+			url:    "http://h/v2/blobs/sha256/xxx",
+			expect: func(t *testing.T, req *http.Request, policy transport.Policy) {
+				assert := assert.New(t)
+				assert.False(policy.UseDragonfly)
+				assert.NotNil(policy.Limiter)
+			},
+		},
+		{
+			name:   "no rule matches",
+			rules:  []*config.ProxyRule{newRule("/blobs/sha256/", config.ProxyPolicyP2P, "", 0)},
+			method: http.MethodGet,
+			url:    "http://h/v2/manifests/latest",
+			expect: func(t *testing.T, req *http.Request, policy transport.Policy) {
+				assert.Equal(t, transport.Policy{}, policy)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			proxy, err := NewProxy(WithRules(tc.rules))
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(tc.method, tc.url, nil)
+			assert.NoError(t, err)
+			tc.expect(t, req, proxy.requestPolicy(req))
+		})
+	}
+}
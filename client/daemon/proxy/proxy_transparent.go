@@ -0,0 +1,335 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// recordTypeHandshake is the first byte of tls connection.
+	recordTypeHandshake = 0x16
+
+	// peekTimeout is the timeout to wait for the first byte sent by client.
+	peekTimeout = 3 * time.Second
+)
+
+// errClientHelloRead is returned when the client hello is read.
+var errClientHelloRead = errors.New("client hello is read")
+
+// ListenTransparent listens for transparent proxy, TPROXY mode requires CAP_NET_ADMIN.
+func ListenTransparent(opt *config.TransparentOption) (net.Listener, error) {
+	addr := net.JoinHostPort(opt.Listen, strconv.Itoa(opt.PortRange.Start))
+	return transparentListenConfig(opt.Mode).Listen(context.Background(), "tcp", addr)
+}
+
+// ServeTransparent serves the connections redirected by iptables REDIRECT or TPROXY rules,
+// the plain http requests are proxied like http proxy requests, and the tls connections are
+// hijacked when the server name matches the hijackHTTPS hosts, otherwise they are tunneled
+// to the original destination.
+func (proxy *Proxy) ServeTransparent(l net.Listener, mode string) error {
+	var port int
+	if tcpAddr, ok := l.Addr().(*net.TCPAddr); ok {
+		port = tcpAddr.Port
+	}
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+
+			logger.Errorf("accept transparent connection error: %s", err)
+			continue
+		}
+
+		go proxy.handleTransparentConn(conn, mode, port)
+	}
+}
+
+func (proxy *Proxy) handleTransparentConn(conn net.Conn, mode string, port int) {
+	dst, err := originalDst(conn, mode)
+	if err != nil {
+		logger.Errorf("get original destination of %s error: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	// the connections to the transparent proxy port are not redirected, reject them to avoid loop
+	if dst.Port == port {
+		logger.Warnf("reject connection from %s to transparent proxy port %d", conn.RemoteAddr(), port)
+		conn.Close()
+		return
+	}
+
+	proxy.handleTargetConn(conn, dst.String())
+}
+
+// handleTargetConn serves the connection to target, which is redirected by iptables or requested by socks5 client,
+// the first byte is peeked to detect whether it is a tls connection.
+func (proxy *Proxy) handleTargetConn(conn net.Conn, target string) {
+	// the server first protocols, like ssh, wait for the server to send data, tunnel them after timeout
+	if err := conn.SetReadDeadline(time.Now().Add(peekTimeout)); err != nil {
+		logger.Errorf("set read deadline of %s error: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	reader := bufio.NewReader(conn)
+	head, err := reader.Peek(1)
+	if resetErr := conn.SetReadDeadline(time.Time{}); resetErr != nil {
+		logger.Errorf("reset read deadline of %s error: %s", conn.RemoteAddr(), resetErr)
+		conn.Close()
+		return
+	}
+
+	conn = &bufferedConn{Conn: conn, reader: reader}
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			tunnelConn(conn, target)
+			return
+		}
+
+		logger.Errorf("read connection from %s error: %s", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	if head[0] == recordTypeHandshake {
+		proxy.handleTargetTLS(conn, target)
+		return
+	}
+
+	proxy.serveTargetHTTP(conn, target)
+}
+
+// serveTargetHTTP serves the plain http requests like http proxy requests, the requests to the
+// remote of registry mirror are served by registry mirror.
+func (proxy *Proxy) serveTargetHTTP(conn net.Conn, target string) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Scheme = "http"
+		r.URL.Host = r.Host
+		if r.URL.Host == "" {
+			r.URL.Host = target
+		}
+
+		if proxy.matchRegistryMirror(target) {
+			proxy.mirrorRegistry(w, r)
+			return
+		}
+
+		// the redirected requests do not carry proxy authorization
+		proxy.serveHTTP(w, r, false)
+	})
+
+	serveConn(conn, handler)
+}
+
+// handleTargetTLS hijacks the tls connection when the server name matches the hijackHTTPS hosts or
+// the remote of registry mirror, otherwise it tunnels the connection to target.
+func (proxy *Proxy) handleTargetTLS(conn net.Conn, target string) {
+	buf := &bytes.Buffer{}
+	serverName := readServerName(io.TeeReader(conn, buf))
+	conn = &bufferedConn{Conn: conn, reader: io.MultiReader(buf, conn)}
+
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		logger.Errorf("invalid target %s: %s", target, err)
+		conn.Close()
+		return
+	}
+
+	if serverName != "" {
+		host = serverName
+	}
+	addr := net.JoinHostPort(host, port)
+
+	var (
+		cConfig *tls.Config
+		mirror  bool
+	)
+	if proxy.cert != nil {
+		cConfig = proxy.remoteConfig(addr)
+		if cConfig == nil && proxy.matchRegistryMirror(addr) {
+			cConfig, mirror = proxy.registry.TLSConfig(), true
+		}
+	}
+
+	if cConfig == nil {
+		logger.Debugf("hijackHTTPS hosts not match, tunneling connection for %s", addr)
+		tunnelConn(conn, target)
+		return
+	}
+
+	logger.Debugf("hijack https connection to %s", addr)
+	cConfig.ServerName = host
+	sConfig := new(tls.Config)
+	if proxy.leafCerts != nil {
+		sConfig.GetCertificate = proxy.leafCerts.GetCertificate(host)
+	} else {
+		sConfig.Certificates = []tls.Certificate{*proxy.cert}
+	}
+
+	tlsConn, err := handshakeTLSConn(conn, sConfig)
+	if err != nil {
+		logger.Errorf("handshake failed for %s: %v", addr, err)
+		return
+	}
+
+	if mirror {
+		serveConn(tlsConn, http.HandlerFunc(proxy.mirrorRegistry))
+		return
+	}
+
+	rp := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			propagation.TraceContext{}.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+			req.URL.Scheme = schemaHTTPS
+			req.URL.Host = req.Host
+			if req.URL.Host == "" {
+				req.URL.Host = addr
+			}
+		},
+		Transport: proxy.newTransport(cConfig),
+	}
+
+	serveConn(tlsConn, rp)
+}
+
+// matchRegistryMirror returns whether the target address is the remote of registry mirror.
+func (proxy *Proxy) matchRegistryMirror(target string) bool {
+	if proxy.registry == nil || proxy.registry.Remote == nil || proxy.registry.Remote.URL == nil {
+		return false
+	}
+
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		return false
+	}
+
+	remote := proxy.registry.Remote.URL
+	remotePort := remote.Port()
+	if remotePort == "" {
+		remotePort = "80"
+		if remote.Scheme == schemaHTTPS {
+			remotePort = strconv.Itoa(portHTTPS)
+		}
+	}
+
+	return host == remote.Hostname() && port == remotePort
+}
+
+// serveConn serves the http requests in connection until it is closed.
+func serveConn(conn net.Conn, handler http.Handler) {
+	// We have to wait until the connection is closed
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	// NOTE: http.Serve always returns a non-nil error
+	err := http.Serve(&singleUseListener{&customCloseConn{conn, wg.Done}}, handler)
+	if err != errServerClosed && err != http.ErrServerClosed {
+		logger.Errorf("failed to accept incoming transparent connections: %v", err)
+	}
+	wg.Wait()
+}
+
+// tunnelConn copies the data between the connection and the destination.
+func tunnelConn(conn net.Conn, dst string) {
+	metrics.ProxyRequestNotViaDragonflyCount.Add(1)
+	defer conn.Close()
+
+	dstConn, err := net.DialTimeout("tcp", dst, 10*time.Second)
+	if err != nil {
+		logger.Errorf("dial %s error: %s", dst, err)
+		return
+	}
+	defer dstConn.Close()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := io.Copy(dstConn, conn); err != nil {
+			logger.Errorf("copy transparent stream from client to destination error: %s", err)
+		}
+
+		// notify the destination that the client finished sending
+		if tcpConn, ok := dstConn.(*net.TCPConn); ok {
+			tcpConn.CloseWrite()
+		}
+	}()
+
+	if _, err := io.Copy(conn, dstConn); err != nil {
+		logger.Errorf("copy transparent stream from destination to client error: %s", err)
+	}
+	wg.Wait()
+}
+
+// readServerName reads the tls client hello and returns the server name in it.
+func readServerName(r io.Reader) string {
+	var serverName string
+	_ = tls.Server(&readOnlyConn{reader: r}, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, errClientHelloRead
+		},
+	}).Handshake()
+
+	return serverName
+}
+
+// bufferedConn is a net.Conn which reads from reader.
+type bufferedConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// readOnlyConn is a net.Conn which only reads from reader, it is used to read the tls client hello.
+type readOnlyConn struct {
+	reader io.Reader
+}
+
+func (c *readOnlyConn) Read(p []byte) (int, error)         { return c.reader.Read(p) }
+func (c *readOnlyConn) Write(p []byte) (int, error)        { return 0, io.ErrClosedPipe }
+func (c *readOnlyConn) Close() error                       { return nil }
+func (c *readOnlyConn) LocalAddr() net.Addr                { return nil }
+func (c *readOnlyConn) RemoteAddr() net.Addr               { return nil }
+func (c *readOnlyConn) SetDeadline(t time.Time) error      { return nil }
+func (c *readOnlyConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *readOnlyConn) SetWriteDeadline(t time.Time) error { return nil }
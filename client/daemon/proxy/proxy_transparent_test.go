@@ -0,0 +1,180 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+
+	"d7y.io/dragonfly/v2/client/config"
+)
+
+func newTransparentTestServer(t *testing.T, server *httptest.Server) *net.TCPAddr {
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	addr, err := net.ResolveTCPAddr("tcp", u.Host)
+	assert.NoError(t, err)
+	return addr
+}
+
+func TestReadServerName(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverName string
+	}{
+		{
+			name:       "client hello with server name",
+			serverName: "registry.example.com",
+		},
+		{
+			name: "client hello without server name",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clientConn, serverConn := net.Pipe()
+			defer serverConn.Close()
+			go func() {
+				_ = tls.Client(clientConn, &tls.Config{ServerName: tc.serverName, InsecureSkipVerify: true}).Handshake()
+			}()
+
+			assert.Equal(t, tc.serverName, readServerName(serverConn))
+			clientConn.Close()
+		})
+	}
+}
+
+func TestProxy_ServeTargetHTTP(t *testing.T) {
+	assert := assert.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.Host, r.URL.Path)
+	}))
+	defer server.Close()
+	dst := newTransparentTestServer(t, server)
+
+	proxy, err := NewProxy(
+		WithPeerHost(&schedulerv1.PeerHost{}),
+		WithRules(nil),
+		WithBasicAuth(&config.BasicAuth{Username: "foo", Password: "bar"}),
+	)
+	assert.NoError(err)
+
+	clientConn, serverConn := net.Pipe()
+	go proxy.serveTargetHTTP(&bufferedConn{Conn: serverConn, reader: bufio.NewReader(serverConn)}, dst.String())
+
+	// the transparent requests are not authenticated and not in proxy form
+	req, err := http.NewRequest(http.MethodGet, "http://"+dst.String()+"/blobs/sha256:foo", nil)
+	assert.NoError(err)
+	assert.NoError(req.Write(clientConn))
+
+	resp, err := http.ReadResponse(bufio.NewReader(clientConn), req)
+	assert.NoError(err)
+	defer resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	assert.NoError(err)
+	assert.Equal(dst.String()+" /blobs/sha256:foo", string(data))
+	clientConn.Close()
+}
+
+func TestProxy_HandleTargetTLS(t *testing.T) {
+	assert := assert.New(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "tunneled")
+	}))
+	defer server.Close()
+	dst := newTransparentTestServer(t, server)
+
+	proxy, err := NewProxy(WithPeerHost(&schedulerv1.PeerHost{}))
+	assert.NoError(err)
+
+	clientConn, serverConn := net.Pipe()
+	go proxy.handleTargetTLS(serverConn, dst.String())
+
+	// without cert, the tls connections are tunneled to the original destination
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialTLS: func(network, addr string) (net.Conn, error) {
+				conn := tls.Client(clientConn, &tls.Config{ServerName: "example.com", InsecureSkipVerify: true})
+				return conn, conn.Handshake()
+			},
+		},
+	}
+	resp, err := client.Get("https://example.com/")
+	assert.NoError(err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	assert.NoError(err)
+	assert.Equal("tunneled", string(data))
+	assert.Equal("example.com", resp.TLS.ServerName)
+}
+
+func TestProxy_MatchRegistryMirror(t *testing.T) {
+	tests := []struct {
+		name   string
+		remote string
+		target string
+		expect bool
+	}{
+		{
+			name:   "match https remote with default port",
+			remote: "https://index.docker.io",
+			target: "index.docker.io:443",
+			expect: true,
+		},
+		{
+			name:   "match http remote with port",
+			remote: "http://127.0.0.1:5000",
+			target: "127.0.0.1:5000",
+			expect: true,
+		},
+		{
+			name:   "port not match",
+			remote: "https://index.docker.io",
+			target: "index.docker.io:80",
+		},
+		{
+			name:   "host not match",
+			remote: "https://index.docker.io",
+			target: "example.com:443",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.remote)
+			assert.NoError(t, err)
+
+			proxy, err := NewProxy(WithRegistryMirror(&config.RegistryMirror{Remote: &config.URL{URL: u}}))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, proxy.matchRegistryMirror(tc.target))
+		})
+	}
+}
@@ -0,0 +1,276 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// registryUpstream is an upstream registry of registry mirror.
+type registryUpstream struct {
+	url *url.URL
+
+	// transport round trips the requests to the upstream, with or without dragonfly
+	transport http.RoundTripper
+
+	// client checks the health of the upstream directly
+	client *http.Client
+
+	healthy *atomic.Bool
+}
+
+// registryUpstreams is the ordered upstream registries of registry mirror, it round trips the requests
+// to the healthy upstreams in order, and fails over to the next one when the upstream is unavailable,
+// responds 5xx, 429 or 404. The unhealthy upstreams are tried after all the healthy ones.
+type registryUpstreams struct {
+	upstreams []*registryUpstream
+	interval  time.Duration
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newRegistryUpstreams returns the upstreams of the remote and fallbacks of registry mirror,
+// newTransport returns the transport to the upstream with its tls config.
+func newRegistryUpstreams(mirror *config.RegistryMirror, newTransport func(*tls.Config) (http.RoundTripper, error)) (*registryUpstreams, error) {
+	interval := mirror.HealthCheck.Interval
+	if interval <= 0 {
+		interval = config.DefaultRegistryHealthCheckInterval
+	}
+
+	timeout := mirror.HealthCheck.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultRegistryHealthCheckTimeout
+	}
+
+	upstreams := &registryUpstreams{
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+
+	add := func(u *url.URL, tlsConfig *tls.Config) error {
+		rt, err := newTransport(tlsConfig)
+		if err != nil {
+			return err
+		}
+
+		metrics.ProxyRegistryUpstreamHealthy.WithLabelValues(u.Host).Set(1)
+		upstreams.upstreams = append(upstreams.upstreams, &registryUpstream{
+			url:       u,
+			transport: rt,
+			client: &http.Client{
+				Timeout:   timeout,
+				Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+			},
+			healthy: atomic.NewBool(true),
+		})
+		return nil
+	}
+
+	if err := add(mirror.Remote.URL, mirror.TLSConfig()); err != nil {
+		return nil, err
+	}
+
+	for _, fallback := range mirror.Fallbacks {
+		if err := add(fallback.Remote.URL, fallback.TLSConfig()); err != nil {
+			return nil, err
+		}
+	}
+
+	return upstreams, nil
+}
+
+// RoundTrip implements http.RoundTripper, the url of request is rewritten to the upstream.
+func (u *registryUpstreams) RoundTrip(req *http.Request) (*http.Response, error) {
+	upstreams := u.ordered()
+
+	var lastErr error
+	for i, upstream := range upstreams {
+		last := i == len(upstreams)-1
+		// the request with body can not be retried
+		if i > 0 && req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				break
+			}
+		}
+
+		outReq, err := u.rewrite(req, upstream.url, i > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := upstream.transport.RoundTrip(outReq)
+		if err != nil {
+			if errors.Is(req.Context().Err(), context.Canceled) {
+				return nil, err
+			}
+
+			logger.Warnf("registry upstream %s is unavailable: %s", upstream.url.Host, err)
+			u.setHealthy(upstream, false)
+			metrics.ProxyRegistryUpstreamRequestCount.WithLabelValues(upstream.url.Host, "failed").Inc()
+			lastErr = err
+			continue
+		}
+
+		if !last && shouldFailover(resp.StatusCode) {
+			logger.Warnf("registry upstream %s responds %d for %s, fail over to next upstream",
+				upstream.url.Host, resp.StatusCode, req.URL.Path)
+			if resp.StatusCode >= http.StatusInternalServerError {
+				u.setHealthy(upstream, false)
+			}
+			metrics.ProxyRegistryUpstreamRequestCount.WithLabelValues(upstream.url.Host, "failover").Inc()
+
+			// drain the body for connection reusing
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+			continue
+		}
+
+		metrics.ProxyRegistryUpstreamRequestCount.WithLabelValues(upstream.url.Host, "served").Inc()
+		resp.Header.Set(config.HeaderDragonflyRegistryUpstream, upstream.url.Host)
+		return resp, nil
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no available registry upstream")
+	}
+	return nil, lastErr
+}
+
+// rewrite returns the request to upstream, the body is renewed when retrying.
+func (u *registryUpstreams) rewrite(req *http.Request, target *url.URL, retry bool) (*http.Request, error) {
+	outReq := req.Clone(req.Context())
+	if retry && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		outReq.Body = body
+	}
+
+	outReq.URL.Scheme = target.Scheme
+	outReq.URL.Host = target.Host
+	outReq.URL.Path, outReq.URL.RawPath = joinURLPath(target, req.URL)
+	if target.RawQuery != "" {
+		if outReq.URL.RawQuery == "" {
+			outReq.URL.RawQuery = target.RawQuery
+		} else {
+			outReq.URL.RawQuery = target.RawQuery + "&" + outReq.URL.RawQuery
+		}
+	}
+	outReq.Host = ""
+	return outReq, nil
+}
+
+// ordered returns the healthy upstreams in order followed by the unhealthy ones.
+func (u *registryUpstreams) ordered() []*registryUpstream {
+	upstreams := make([]*registryUpstream, 0, len(u.upstreams))
+	for _, upstream := range u.upstreams {
+		if upstream.healthy.Load() {
+			upstreams = append(upstreams, upstream)
+		}
+	}
+
+	for _, upstream := range u.upstreams {
+		if !upstream.healthy.Load() {
+			upstreams = append(upstreams, upstream)
+		}
+	}
+
+	return upstreams
+}
+
+// serve checks the health of upstreams periodically until stopped.
+func (u *registryUpstreams) serve() {
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			u.checkHealth()
+		case <-u.done:
+			return
+		}
+	}
+}
+
+// stop stops checking the health of upstreams.
+func (u *registryUpstreams) stop() {
+	u.stopOnce.Do(func() {
+		close(u.done)
+	})
+}
+
+// checkHealth checks the health of all upstreams with GET /v2/.
+func (u *registryUpstreams) checkHealth() {
+	var wg sync.WaitGroup
+	for _, upstream := range u.upstreams {
+		wg.Add(1)
+		go func(upstream *registryUpstream) {
+			defer wg.Done()
+			u.setHealthy(upstream, checkRegistryHealth(upstream))
+		}(upstream)
+	}
+	wg.Wait()
+}
+
+func (u *registryUpstreams) setHealthy(upstream *registryUpstream, healthy bool) {
+	if upstream.healthy.Swap(healthy) != healthy {
+		logger.Infof("registry upstream %s healthy changed to %t", upstream.url.Host, healthy)
+	}
+
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	metrics.ProxyRegistryUpstreamHealthy.WithLabelValues(upstream.url.Host).Set(value)
+}
+
+// checkRegistryHealth returns whether the upstream is healthy, the unauthorized response is healthy.
+func checkRegistryHealth(upstream *registryUpstream) bool {
+	u := *upstream.url
+	u.Path, u.RawPath = joinURLPath(&u, &url.URL{Path: "/v2/"})
+	resp, err := upstream.client.Get(u.String())
+	if err != nil {
+		logger.Warnf("check health of registry upstream %s error: %s", upstream.url.Host, err)
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode < http.StatusInternalServerError
+}
+
+// shouldFailover returns whether to fail over to the next upstream with the status code.
+func shouldFailover(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode == http.StatusNotFound
+}
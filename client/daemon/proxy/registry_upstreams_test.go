@@ -0,0 +1,167 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+)
+
+func newTestRegistryUpstreams(t *testing.T, servers ...*httptest.Server) *registryUpstreams {
+	var urls []*config.URL
+	for _, server := range servers {
+		u, err := url.Parse(server.URL)
+		assert.NoError(t, err)
+		urls = append(urls, &config.URL{URL: u})
+	}
+
+	mirror := &config.RegistryMirror{Remote: urls[0]}
+	for _, u := range urls[1:] {
+		mirror.Fallbacks = append(mirror.Fallbacks, &config.RegistryUpstream{Remote: u})
+	}
+
+	upstreams, err := newRegistryUpstreams(mirror, func(tlsConfig *tls.Config) (http.RoundTripper, error) {
+		return &http.Transport{TLSClientConfig: tlsConfig}, nil
+	})
+	assert.NoError(t, err)
+	return upstreams
+}
+
+func newTestRegistryServer(name string, statusCode int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		fmt.Fprintf(w, "%s %s", name, r.URL.Path)
+	}))
+}
+
+func TestRegistryUpstreams_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCodes []int
+		expectBody  string
+		expectCode  int
+		expectIndex int
+		healthy     []bool
+	}{
+		{
+			name:        "remote is available",
+			statusCodes: []int{http.StatusOK, http.StatusOK},
+			expectBody:  "upstream-0 /v2/foo/manifests/latest",
+			expectCode:  http.StatusOK,
+			expectIndex: 0,
+			healthy:     []bool{true, true},
+		},
+		{
+			name:        "fail over when remote responds 5xx",
+			statusCodes: []int{http.StatusServiceUnavailable, http.StatusOK},
+			expectBody:  "upstream-1 /v2/foo/manifests/latest",
+			expectCode:  http.StatusOK,
+			expectIndex: 1,
+			healthy:     []bool{false, true},
+		},
+		{
+			name:        "fail over when remote responds 404",
+			statusCodes: []int{http.StatusNotFound, http.StatusOK, http.StatusOK},
+			expectBody:  "upstream-1 /v2/foo/manifests/latest",
+			expectCode:  http.StatusOK,
+			expectIndex: 1,
+			healthy:     []bool{true, true, true},
+		},
+		{
+			name:        "last upstream response is returned",
+			statusCodes: []int{http.StatusTooManyRequests, http.StatusNotFound},
+			expectBody:  "upstream-1 /v2/foo/manifests/latest",
+			expectCode:  http.StatusNotFound,
+			expectIndex: 1,
+			healthy:     []bool{true, true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			var servers []*httptest.Server
+			for i, statusCode := range tc.statusCodes {
+				server := newTestRegistryServer(fmt.Sprintf("upstream-%d", i), statusCode)
+				defer server.Close()
+				servers = append(servers, server)
+			}
+
+			upstreams := newTestRegistryUpstreams(t, servers...)
+			req, err := http.NewRequest(http.MethodGet, "/v2/foo/manifests/latest", nil)
+			assert.NoError(err)
+
+			resp, err := upstreams.RoundTrip(req)
+			assert.NoError(err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(err)
+			assert.Equal(tc.expectCode, resp.StatusCode)
+			assert.Equal(tc.expectBody, string(body))
+			assert.Equal(upstreams.upstreams[tc.expectIndex].url.Host, resp.Header.Get(config.HeaderDragonflyRegistryUpstream))
+			for i, healthy := range tc.healthy {
+				assert.Equal(healthy, upstreams.upstreams[i].healthy.Load())
+			}
+		})
+	}
+}
+
+func TestRegistryUpstreams_RoundTripUnavailable(t *testing.T) {
+	assert := assert.New(t)
+	unavailable := newTestRegistryServer("upstream-0", http.StatusOK)
+	unavailable.Close()
+	fallback := newTestRegistryServer("upstream-1", http.StatusOK)
+	defer fallback.Close()
+
+	upstreams := newTestRegistryUpstreams(t, unavailable, fallback)
+	req, err := http.NewRequest(http.MethodGet, "/v2/", nil)
+	assert.NoError(err)
+
+	resp, err := upstreams.RoundTrip(req)
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.False(upstreams.upstreams[0].healthy.Load())
+
+	// the unhealthy upstream is tried after the healthy ones
+	assert.Equal(upstreams.upstreams[1], upstreams.ordered()[0])
+	assert.Equal(upstreams.upstreams[0], upstreams.ordered()[1])
+}
+
+func TestRegistryUpstreams_CheckHealth(t *testing.T) {
+	assert := assert.New(t)
+	unauthorized := newTestRegistryServer("upstream-0", http.StatusUnauthorized)
+	defer unauthorized.Close()
+	unavailable := newTestRegistryServer("upstream-1", http.StatusBadGateway)
+	defer unavailable.Close()
+
+	upstreams := newTestRegistryUpstreams(t, unauthorized, unavailable)
+	upstreams.upstreams[0].healthy.Store(false)
+	upstreams.checkHealth()
+	assert.True(upstreams.upstreams[0].healthy.Load())
+	assert.False(upstreams.upstreams[1].healthy.Load())
+}
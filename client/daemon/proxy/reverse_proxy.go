@@ -34,6 +34,20 @@ func newReverseProxy(mirror *config.RegistryMirror) *httputil.ReverseProxy {
 	return reverseProxy
 }
 
+// newUpstreamsReverseProxy returns the reverse proxy to the upstreams of registry mirror,
+// the url of request is rewritten by the upstreams.
+func newUpstreamsReverseProxy(upstreams *registryUpstreams) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			if _, ok := req.Header["User-Agent"]; !ok {
+				// explicitly disable User-Agent so it's not set to default value
+				req.Header.Set("User-Agent", "")
+			}
+		},
+		Transport: upstreams,
+	}
+}
+
 func newDynamicDirector(remote *url.URL) func(*http.Request) {
 	director := func(req *http.Request) {
 		var target = remote
@@ -0,0 +1,123 @@
+//go:build linux
+
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"d7y.io/dragonfly/v2/client/config"
+)
+
+// ip6tSoOriginalDst is IP6T_SO_ORIGINAL_DST in linux/netfilter_ipv6/ip6_tables.h.
+const ip6tSoOriginalDst = 80
+
+// originalDst returns the original destination of the connection redirected by iptables.
+func originalDst(conn net.Conn, mode string) (*net.TCPAddr, error) {
+	if mode == config.TransparentModeTProxy {
+		// the local address of connection accepted by TPROXY is the original destination
+		addr, ok := conn.LocalAddr().(*net.TCPAddr)
+		if !ok {
+			return nil, errors.New("connection is not tcp")
+		}
+		return addr, nil
+	}
+
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil, errors.New("connection is not tcp")
+	}
+
+	rawConn, err := tcpConn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addr    *net.TCPAddr
+		sockErr error
+	)
+	if err := rawConn.Control(func(fd uintptr) {
+		addr, sockErr = getOriginalDst(int(fd), conn.LocalAddr().(*net.TCPAddr).IP.To4() == nil)
+	}); err != nil {
+		return nil, err
+	}
+
+	return addr, sockErr
+}
+
+// getOriginalDst gets the SO_ORIGINAL_DST option from conntrack.
+func getOriginalDst(fd int, ipv6 bool) (*net.TCPAddr, error) {
+	if ipv6 {
+		info, err := unix.GetsockoptIPv6MTUInfo(fd, unix.SOL_IPV6, ip6tSoOriginalDst)
+		if err != nil {
+			return nil, err
+		}
+
+		// port of sockaddr_in6 is in network byte order
+		port := make([]byte, 2)
+		binary.NativeEndian.PutUint16(port, info.Addr.Port)
+		return &net.TCPAddr{
+			IP:   net.IP(info.Addr.Addr[:]),
+			Port: int(binary.BigEndian.Uint16(port)),
+		}, nil
+	}
+
+	// sockaddr_in is returned in the 16 bytes of ipv6_mreq
+	mreq, err := unix.GetsockoptIPv6Mreq(fd, unix.SOL_IP, unix.SO_ORIGINAL_DST)
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.TCPAddr{
+		IP:   net.IPv4(mreq.Multiaddr[4], mreq.Multiaddr[5], mreq.Multiaddr[6], mreq.Multiaddr[7]),
+		Port: int(binary.BigEndian.Uint16(mreq.Multiaddr[2:4])),
+	}, nil
+}
+
+// transparentListenConfig returns the listen config of transparent proxy, TPROXY requires IP_TRANSPARENT option.
+func transparentListenConfig(mode string) *net.ListenConfig {
+	if mode != config.TransparentModeTProxy {
+		return &net.ListenConfig{}
+	}
+
+	return &net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				if sockErr = unix.SetsockoptInt(int(fd), unix.SOL_IP, unix.IP_TRANSPARENT, 1); sockErr != nil {
+					return
+				}
+
+				if network == "tcp6" || network == "tcp" {
+					// ignore the error when the socket is ipv4 only
+					_ = unix.SetsockoptInt(int(fd), unix.SOL_IPV6, unix.IPV6_TRANSPARENT, 1)
+				}
+			}); err != nil {
+				return err
+			}
+
+			return sockErr
+		},
+	}
+}
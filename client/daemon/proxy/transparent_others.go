@@ -0,0 +1,34 @@
+//go:build !linux
+
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package proxy
+
+import (
+	"errors"
+	"net"
+)
+
+// originalDst returns the original destination of the connection redirected by iptables.
+func originalDst(conn net.Conn, mode string) (*net.TCPAddr, error) {
+	return nil, errors.New("transparent proxy is only supported on linux")
+}
+
+// transparentListenConfig returns the listen config of transparent proxy.
+func transparentListenConfig(mode string) *net.ListenConfig {
+	return &net.ListenConfig{}
+}
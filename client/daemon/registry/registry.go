@@ -0,0 +1,316 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/registry_mock.go -source registry.go -package mocks
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-http-utils/headers"
+	ginprometheus "github.com/mcuadros/go-gin-prometheus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/transport"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	PrometheusSubsystemName = "dragonfly_dfdaemon_registry"
+	OtelServiceName         = "dragonfly-dfdaemon-registry"
+)
+
+const (
+	RouterGroupV2 = "/v2"
+)
+
+var GinLogFileName = "gin-registry.log"
+
+const (
+	// HeaderDistributionAPIVersion is the header of distribution api version.
+	HeaderDistributionAPIVersion = "Docker-Distribution-API-Version"
+
+	// HeaderContentDigest is the header of content digest.
+	HeaderContentDigest = "Docker-Content-Digest"
+
+	// distributionAPIVersion is the distribution api version of registry.
+	distributionAPIVersion = "registry/2.0"
+
+	// maxManifestSize is the max size of manifest to read media type.
+	maxManifestSize = 4 * 1024 * 1024
+)
+
+// routeRegexp matches the path of manifests and blobs, like /v2/<name>/manifests/<reference>.
+var routeRegexp = regexp.MustCompile(`/v2/(.+)/(manifests|blobs)/([^/]+)$`)
+
+// Registry is the interface used for registry server.
+type Registry interface {
+	// Started registry server.
+	Serve(lis net.Listener) error
+
+	// Stop registry server.
+	Stop() error
+}
+
+// registry implements the pull side of OCI distribution spec, the manifests by digest and blobs
+// are downloaded by P2P, and the other requests are forwarded to the upstream registry.
+type registry struct {
+	*http.Server
+	remote    *url.URL
+	transport http.RoundTripper
+}
+
+// New returns a new Registry instence.
+func New(cfg *config.DaemonOption, peerTaskManager peer.TaskManager, logDir string) (Registry, error) {
+	r := &registry{
+		remote: cfg.Registry.Remote.URL,
+	}
+
+	rt, err := transport.New(
+		transport.WithPeerIDGenerator(peer.NewPeerIDGenerator(cfg.Host.AdvertiseIP.String())),
+		transport.WithPeerTaskManager(peerTaskManager),
+		transport.WithTLS(cfg.Registry.TLSConfig()),
+		transport.WithPolicy(requestPolicy),
+	)
+	if err != nil {
+		return nil, err
+	}
+	r.transport = rt
+
+	r.Server = &http.Server{
+		Handler: r.initRouter(cfg, logDir),
+	}
+
+	return r, nil
+}
+
+// Started registry server.
+func (r *registry) Serve(lis net.Listener) error {
+	return r.Server.Serve(lis)
+}
+
+// Stop registry server.
+func (r *registry) Stop() error {
+	return r.Server.Shutdown(context.Background())
+}
+
+// Initialize router of gin.
+func (r *registry) initRouter(cfg *config.DaemonOption, logDir string) *gin.Engine {
+	// Set mode
+	if !cfg.Verbose {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// Logging to a file
+	if !cfg.Console {
+		gin.DisableConsoleColor()
+		logDir := filepath.Join(logDir, "daemon")
+		f, _ := os.Create(filepath.Join(logDir, GinLogFileName))
+		gin.DefaultWriter = io.MultiWriter(f)
+	}
+
+	e := gin.New()
+
+	// Middleware
+	e.Use(gin.Logger())
+	e.Use(gin.Recovery())
+
+	// Prometheus metrics
+	p := ginprometheus.NewPrometheus(PrometheusSubsystemName)
+	// Prometheus metrics need to reduce label,
+	// refer to https://prometheus.io/docs/practices/instrumentation/#do-not-overuse-labels.
+	p.ReqCntURLLabelMappingFn = func(c *gin.Context) string {
+		if strings.HasPrefix(c.Request.URL.Path, RouterGroupV2) {
+			return RouterGroupV2
+		}
+
+		return c.Request.URL.Path
+	}
+	p.Use(e)
+
+	// Opentelemetry
+	if cfg.Options.Telemetry.Jaeger != "" {
+		e.Use(otelgin.Middleware(OtelServiceName))
+	}
+
+	// Health Check.
+	e.GET("/healthy", r.getHealth)
+
+	// Distribution API.
+	v2 := e.Group(RouterGroupV2)
+	v2.GET("*path", r.pull)
+	v2.HEAD("*path", r.pull)
+
+	return e
+}
+
+// getHealth uses to check server health.
+func (r *registry) getHealth(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, http.StatusText(http.StatusOK))
+}
+
+// pull serves the api version check, manifests and blobs requests.
+func (r *registry) pull(ctx *gin.Context) {
+	path := ctx.Param("path")
+	route := parseRoute(RouterGroupV2 + path)
+	if path != "/" && route == nil {
+		ctx.JSON(http.StatusNotFound, newErrorResponse("UNSUPPORTED", "the operation is unsupported"))
+		return
+	}
+
+	if route != nil && route.kind == "blobs" && !route.isDigest() {
+		ctx.JSON(http.StatusBadRequest, newErrorResponse("DIGEST_INVALID", "the reference of blob must be a digest"))
+		return
+	}
+
+	u := *r.remote
+	u.Path = strings.TrimSuffix(r.remote.Path, "/") + RouterGroupV2 + path
+	u.RawPath = ""
+	u.RawQuery = ctx.Request.URL.RawQuery
+
+	req, err := http.NewRequestWithContext(ctx.Request.Context(), ctx.Request.Method, u.String(), nil)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, newErrorResponse("UNKNOWN", err.Error()))
+		return
+	}
+	req.Header = ctx.Request.Header.Clone()
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		logger.Errorf("pull %s error: %s", u.String(), err)
+		ctx.JSON(http.StatusBadGateway, newErrorResponse("UNKNOWN", err.Error()))
+		return
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			ctx.Writer.Header().Add(k, v)
+		}
+	}
+	ctx.Header(HeaderDistributionAPIVersion, distributionAPIVersion)
+
+	if route != nil && route.isDigest() && resp.StatusCode == http.StatusOK {
+		if resp.Header.Get(HeaderContentDigest) == "" {
+			ctx.Header(HeaderContentDigest, route.reference)
+		}
+
+		// the manifest downloaded by P2P may be without the content type, read it from the manifest
+		if route.kind == "manifests" && ctx.Request.Method == http.MethodGet && resp.Header.Get(headers.ContentType) == "" {
+			var mediaType string
+			if mediaType, body, err = readMediaType(body); err != nil {
+				logger.Errorf("read media type of manifest %s error: %s", route.reference, err)
+				ctx.JSON(http.StatusBadGateway, newErrorResponse("UNKNOWN", err.Error()))
+				return
+			}
+
+			if mediaType != "" {
+				ctx.Header(headers.ContentType, mediaType)
+			}
+		}
+	}
+
+	ctx.Status(resp.StatusCode)
+	if _, err := io.Copy(ctx.Writer, body); err != nil {
+		logger.Errorf("write response of %s error: %s", u.String(), err)
+	}
+}
+
+// route is the route of manifests and blobs.
+type route struct {
+	name      string
+	kind      string
+	reference string
+}
+
+// parseRoute parses the route of manifests and blobs, it returns nil when the path is not matched.
+func parseRoute(path string) *route {
+	matches := routeRegexp.FindStringSubmatch(path)
+	if len(matches) != 4 {
+		return nil
+	}
+
+	return &route{
+		name:      matches[1],
+		kind:      matches[2],
+		reference: matches[3],
+	}
+}
+
+// isDigest returns whether the reference is a digest, the tag can not contain colon.
+func (r *route) isDigest() bool {
+	return strings.Contains(r.reference, ":")
+}
+
+// requestPolicy downloads the GET requests of manifests by digest and blobs with dragonfly,
+// they are immutable and shareable between peers.
+func requestPolicy(req *http.Request) transport.Policy {
+	if req.Method != http.MethodGet {
+		return transport.Policy{}
+	}
+
+	route := parseRoute(req.URL.Path)
+	return transport.Policy{
+		UseDragonfly: route != nil && route.isDigest(),
+	}
+}
+
+// readMediaType reads the media type of manifest, and returns the reader of the whole manifest.
+func readMediaType(r io.Reader) (string, io.Reader, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxManifestSize))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var manifest struct {
+		MediaType string `json:"mediaType"`
+	}
+	body := io.MultiReader(bytes.NewReader(data), r)
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", body, nil
+	}
+
+	return manifest.MediaType, body, nil
+}
+
+// errorResponse is the error response of OCI distribution spec.
+type errorResponse struct {
+	Errors []errorInfo `json:"errors"`
+}
+
+type errorInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newErrorResponse(code, message string) errorResponse {
+	return errorResponse{Errors: []errorInfo{{Code: code, Message: message}}}
+}
@@ -0,0 +1,186 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/cmd/dependency/base"
+)
+
+const testManifest = `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json"}`
+
+func TestRegistry_Pull(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.Header().Set("WWW-Authenticate", `Bearer realm="https://auth.example.com/token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/v2/library/alpine/manifests/latest":
+			w.Header().Set(headers.ContentType, "application/vnd.oci.image.manifest.v1+json")
+			w.Header().Set(HeaderContentDigest, "sha256:foo")
+			_, _ = io.WriteString(w, testManifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		mock   func(m *peer.MockTaskManagerMockRecorder)
+		expect func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "check api version",
+			method: http.MethodGet,
+			path:   "/v2/",
+			mock:   func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusUnauthorized, resp.StatusCode)
+				assert.Equal(`Bearer realm="https://auth.example.com/token"`, resp.Header.Get("WWW-Authenticate"))
+				assert.Equal(distributionAPIVersion, resp.Header.Get(HeaderDistributionAPIVersion))
+			},
+		},
+		{
+			name:   "get manifest by tag from upstream",
+			method: http.MethodGet,
+			path:   "/v2/library/alpine/manifests/latest",
+			mock:   func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				assert.Equal("sha256:foo", resp.Header.Get(HeaderContentDigest))
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(testManifest, string(body))
+			},
+		},
+		{
+			name:   "get manifest by digest with P2P",
+			method: http.MethodGet,
+			path:   "/v2/library/alpine/manifests/sha256:foo",
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+						assert.Equal(t, upstream.URL+"/v2/library/alpine/manifests/sha256:foo", req.URL)
+						return io.NopCloser(strings.NewReader(testManifest)), map[string]string{}, nil
+					}).Times(1)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				assert.Equal("sha256:foo", resp.Header.Get(HeaderContentDigest))
+				assert.Equal("application/vnd.oci.image.manifest.v1+json", resp.Header.Get(headers.ContentType))
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(testManifest, string(body))
+			},
+		},
+		{
+			name:   "get blob with P2P",
+			method: http.MethodGet,
+			path:   "/v2/library/alpine/blobs/sha256:bar",
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+						assert.Equal(t, upstream.URL+"/v2/library/alpine/blobs/sha256:bar", req.URL)
+						assert.Equal(t, "Bearer token", req.URLMeta.Header["Authorization"])
+						return io.NopCloser(strings.NewReader("blob")), map[string]string{headers.ContentLength: "4"}, nil
+					}).Times(1)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				assert.Equal("sha256:bar", resp.Header.Get(HeaderContentDigest))
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal("blob", string(body))
+			},
+		},
+		{
+			name:   "head blob from upstream",
+			method: http.MethodHead,
+			path:   "/v2/library/alpine/blobs/sha256:bar",
+			mock:   func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "get blob by tag",
+			method: http.MethodGet,
+			path:   "/v2/library/alpine/blobs/latest",
+			mock:   func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "unsupported operation",
+			method: http.MethodGet,
+			path:   "/v2/_catalog",
+			mock:   func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			peerTaskManager := peer.NewMockTaskManager(ctl)
+			tc.mock(peerTaskManager.EXPECT())
+
+			remote, err := url.Parse(upstream.URL)
+			assert.NoError(t, err)
+
+			cfg := &config.DaemonOption{
+				Options:  base.Options{Console: true},
+				Host:     config.HostOption{AdvertiseIP: net.IPv4(127, 0, 0, 1)},
+				Registry: config.RegistryOption{Remote: &config.URL{URL: remote}},
+			}
+			r, err := New(cfg, peerTaskManager, t.TempDir())
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+			r.(*registry).Server.Handler.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+			tc.expect(t, resp)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reputation
+
+import (
+	"sort"
+	"sync"
+
+	"d7y.io/dragonfly/v2/pkg/rpc"
+)
+
+const (
+	// maxParentReports is the max count of the parents tracked in a report window,
+	// the parents beyond it are not tracked until the reports are taken.
+	maxParentReports = 256
+)
+
+// Tracker tracks the piece results of the parents, the reports are carried by announcing host,
+// so scheduler scores the reputation of the parents and blocklists the misbehaving ones.
+type Tracker interface {
+	// Success records a piece downloaded from the parent.
+	Success(peerID string)
+
+	// Failure records a piece failed to download from the parent.
+	Failure(peerID string, corrupted bool)
+
+	// Reports returns the parent reports since the last call and resets them.
+	Reports() []rpc.ParentReport
+}
+
+// tracker provides the tracking of the parents.
+type tracker struct {
+	mu      sync.Mutex
+	reports map[string]*rpc.ParentReport
+}
+
+// NewTracker returns a new Tracker instance.
+func NewTracker() Tracker {
+	return &tracker{
+		reports: map[string]*rpc.ParentReport{},
+	}
+}
+
+// Success records a piece downloaded from the parent.
+func (t *tracker) Success(peerID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if report, ok := t.load(peerID); ok {
+		report.Pieces++
+	}
+}
+
+// Failure records a piece failed to download from the parent.
+func (t *tracker) Failure(peerID string, corrupted bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report, ok := t.load(peerID)
+	if !ok {
+		return
+	}
+
+	report.Pieces++
+	report.Failures++
+	if corrupted {
+		report.Corruptions++
+	}
+}
+
+// Reports returns the parent reports since the last call and resets them.
+func (t *tracker) Reports() []rpc.ParentReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reports := make([]rpc.ParentReport, 0, len(t.reports))
+	for _, report := range t.reports {
+		reports = append(reports, *report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].PeerID < reports[j].PeerID
+	})
+
+	t.reports = map[string]*rpc.ParentReport{}
+	return reports
+}
+
+// load returns the report of the parent, it is created if the tracked parents are not full.
+func (t *tracker) load(peerID string) (*rpc.ParentReport, bool) {
+	if peerID == "" {
+		return nil, false
+	}
+
+	if report, ok := t.reports[peerID]; ok {
+		return report, true
+	}
+
+	if len(t.reports) >= maxParentReports {
+		return nil, false
+	}
+
+	report := &rpc.ParentReport{PeerID: peerID}
+	t.reports[peerID] = report
+	return report, true
+}
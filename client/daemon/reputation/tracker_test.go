@@ -0,0 +1,80 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reputation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/rpc"
+)
+
+func TestTracker(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(t Tracker)
+		expect func(t *testing.T, reports []rpc.ParentReport)
+	}{
+		{
+			name: "track piece results of parents",
+			record: func(t Tracker) {
+				t.Success("foo")
+				t.Success("foo")
+				t.Failure("foo", false)
+				t.Failure("bar", true)
+				t.Success("")
+			},
+			expect: func(t *testing.T, reports []rpc.ParentReport) {
+				assert.Equal(t, []rpc.ParentReport{
+					{PeerID: "bar", Pieces: 1, Failures: 1, Corruptions: 1},
+					{PeerID: "foo", Pieces: 3, Failures: 1},
+				}, reports)
+			},
+		},
+		{
+			name:   "no piece results",
+			record: func(t Tracker) {},
+			expect: func(t *testing.T, reports []rpc.ParentReport) {
+				assert.Empty(t, reports)
+			},
+		},
+		{
+			name: "parents beyond limit are not tracked",
+			record: func(t Tracker) {
+				for i := 0; i < maxParentReports+1; i++ {
+					t.Success(fmt.Sprintf("peer-%d", i))
+				}
+			},
+			expect: func(t *testing.T, reports []rpc.ParentReport) {
+				assert.Len(t, reports, maxParentReports)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := NewTracker()
+			tc.record(tracker)
+			tc.expect(t, tracker.Reports())
+
+			// Reports are reset after taken.
+			assert.Empty(t, tracker.Reports())
+		})
+	}
+}
@@ -56,6 +56,7 @@ import (
 
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/signature"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	"d7y.io/dragonfly/v2/client/util"
 	"d7y.io/dragonfly/v2/internal/dferrors"
@@ -63,6 +64,7 @@ import (
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/net/http"
 	"d7y.io/dragonfly/v2/pkg/os/user"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	dfdaemonserver "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/server"
 	"d7y.io/dragonfly/v2/pkg/safe"
 	"d7y.io/dragonfly/v2/pkg/source"
@@ -90,6 +92,9 @@ type server struct {
 
 	recursiveConcurrent    int
 	cacheRecursiveMetadata time.Duration
+
+	signatureVerifier signature.Verifier
+	dynconfig         config.Dynconfig
 }
 
 var tracer trace.Tracer
@@ -98,9 +103,26 @@ func init() {
 	tracer = otel.Tracer("dfget-rpcserver")
 }
 
+// Option is a functional option for configuring the rpc server.
+type Option func(s *server)
+
+// WithSignatureVerifier sets the verifier of the signatures of the downloaded artifacts.
+func WithSignatureVerifier(verifier signature.Verifier) Option {
+	return func(s *server) {
+		s.signatureVerifier = verifier
+	}
+}
+
+// WithDynconfig sets the dynconfig providing the application policies.
+func WithDynconfig(dynconfig config.Dynconfig) Option {
+	return func(s *server) {
+		s.dynconfig = dynconfig
+	}
+}
+
 func New(peerHost *schedulerv1.PeerHost, peerTaskManager peer.TaskManager,
 	storageManager storage.Manager, recursiveConcurrent int, cacheRecursiveMetadata time.Duration,
-	downloadOpts []grpc.ServerOption, peerOpts []grpc.ServerOption) (Server, error) {
+	downloadOpts []grpc.ServerOption, peerOpts []grpc.ServerOption, opts ...Option) (Server, error) {
 	s := &server{
 		KeepAlive:       util.NewKeepAlive("rpc server"),
 		peerHost:        peerHost,
@@ -113,6 +135,10 @@ func New(peerHost *schedulerv1.PeerHost, peerTaskManager peer.TaskManager,
 		healthServer: health.NewServer(),
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	sd := &seeder{
 		server: s,
 	}
@@ -306,6 +332,28 @@ func (s *server) SyncPieceTasks(sync dfdaemonv1.Daemon_SyncPieceTasksServer) err
 		return p, e
 	}
 
+	// The range task is scheduled to the peer of the full task, serve the range from the full task.
+	// The ids of the full task and the range task are derived from the url and url meta here,
+	// so the remote peer can only register the genuine range task of the full task.
+	if url, urlMeta, ok := rpc.RangeFromIncomingContext(sync.Context()); ok {
+		parentTaskID := idgen.ParentTaskIDV1(url, urlMeta)
+		if taskID := idgen.TaskIDV1(url, urlMeta); taskID != request.TaskId || parentTaskID == request.TaskId {
+			log.Warnf("range task %s does not match the url and url meta, expected %s", request.TaskId, taskID)
+		} else if _, err := s.storageManager.RegisterRangeTask(sync.Context(), &storage.RegisterRangeTaskRequest{
+			Parent: storage.PeerTaskMetadata{
+				PeerID: request.DstPid,
+				TaskID: parentTaskID,
+			},
+			SubTask: storage.PeerTaskMetadata{
+				PeerID: request.DstPid,
+				TaskID: request.TaskId,
+			},
+			Range: urlMeta.Range,
+		}); err != nil {
+			log.Debugf("register range task of the full task %s error: %s", parentTaskID, err)
+		}
+	}
+
 	// TODO if not found, try to send to peer task conductor, then download it first
 	total, err := s.sendFirstPieceTasks(log, request, sync, getPieces, sentMap)
 	if err != nil {
@@ -411,7 +459,7 @@ func (s *server) recursiveDownload(ctx context.Context, req *dfdaemonv1.DownRequ
 		logKV = append(logKV, "trace", traceID.String())
 	}
 
-	log := logger.With(logKV...)
+	log := logger.With(logKV...).WithContext(ctx)
 
 	stat, err := os.Stat(req.Output)
 	if err == nil {
@@ -771,7 +819,23 @@ func (s *server) download(ctx context.Context, req *dfdaemonv1.DownRequest, stre
 	if traceID.IsValid() {
 		logKV = append(logKV, "trace", traceID.String())
 	}
-	log := logger.With(logKV...)
+	log := logger.With(logKV...).WithContext(ctx)
+
+	signatureType, sig, hasSignature := rpc.SignatureFromIncomingContext(ctx)
+	if !hasSignature {
+		required, err := signature.Required(s.dynconfig, req.UrlMeta.Application)
+		if err != nil {
+			msg := fmt.Sprintf("get application policies error: %s", err)
+			log.Error(msg)
+			return dferrors.New(commonv1.Code_UnknownError, msg)
+		}
+
+		if required {
+			msg := fmt.Sprintf("application %s requires the signature of the artifact", req.UrlMeta.Application)
+			log.Error(msg)
+			return dferrors.New(commonv1.Code_BadRequest, msg)
+		}
+	}
 
 	peerTaskProgress, err := s.peerTaskManager.StartFileTask(ctx, peerTask)
 	if err != nil {
@@ -790,6 +854,17 @@ func (s *server) download(ctx context.Context, req *dfdaemonv1.DownRequest, stre
 				log.Errorf("task %s/%s failed: %d/%s", p.PeerID, p.TaskID, p.State.Code, p.State.Msg)
 				return dferrors.New(p.State.Code, p.State.Msg)
 			}
+			// verify the artifact before the download is reported successful
+			if p.PeerTaskDone && hasSignature {
+				if err = s.verifySignature(ctx, signatureType, sig, req.Output); err != nil {
+					p.DoneCallback()
+					log.Errorf("verify signature of %s error: %s", req.Output, err)
+					if err := os.Remove(req.Output); err != nil && !os.IsNotExist(err) {
+						log.Errorf("remove unverified output %s error: %s", req.Output, err)
+					}
+					return dferrors.New(commonv1.Code_BadRequest, fmt.Sprintf("verify signature error: %s", err))
+				}
+			}
 			err = stream.Send(&dfdaemonv1.DownResult{
 				TaskId:          p.TaskID,
 				PeerId:          p.PeerID,
@@ -830,6 +905,16 @@ func (s *server) download(ctx context.Context, req *dfdaemonv1.DownRequest, stre
 	}
 }
 
+// verifySignature verifies the signature of the downloaded artifact, it fails closed
+// when the signature verifier is not configured.
+func (s *server) verifySignature(ctx context.Context, typ, sig, path string) error {
+	if s.signatureVerifier == nil {
+		return errors.New("signature verifier is not configured")
+	}
+
+	return s.signatureVerifier.Verify(ctx, typ, sig, path)
+}
+
 func (s *server) StatTask(ctx context.Context, req *dfdaemonv1.StatTaskRequest) (*emptypb.Empty, error) {
 	s.Keep()
 	taskID := idgen.TaskIDV1(req.Url, req.UrlMeta)
@@ -878,10 +963,14 @@ func (s *server) ImportTask(ctx context.Context, req *dfdaemonv1.ImportTaskReque
 		PeerID: peerID,
 		TaskID: taskID,
 	}
+
+	// The replication policy of the persistent cache task is carried to scheduler,
+	// and the task is pinned, so it is not reclaimed by gc while scheduler maintains its replicas.
+	replication, persistent := rpc.ReplicationFromIncomingContext(ctx)
 	announceFunc := func() {
 		// TODO: retry announce on error
 		start := time.Now()
-		err := s.peerTaskManager.AnnouncePeerTask(context.Background(), ptm, req.Url, req.Type, req.UrlMeta)
+		err := s.peerTaskManager.AnnouncePeerTask(rpc.ContextWithReplication(context.Background(), replication), ptm, req.Url, req.Type, req.UrlMeta)
 		if err != nil {
 			log.Warnf("Failed to announce task to scheduler: %s", err)
 		} else {
@@ -896,6 +985,10 @@ func (s *server) ImportTask(ctx context.Context, req *dfdaemonv1.ImportTaskReque
 
 		// Announce to scheduler as well, but in background
 		ptm.PeerID = task.PeerID
+		if persistent {
+			s.storageManager.PinTask(taskID)
+		}
+
 		go announceFunc()
 		return new(emptypb.Empty), nil
 	}
@@ -923,6 +1016,10 @@ func (s *server) ImportTask(ctx context.Context, req *dfdaemonv1.ImportTaskReque
 	}
 	log.Info("import file succeeded")
 
+	if persistent {
+		s.storageManager.PinTask(taskID)
+	}
+
 	// 3. Announce to scheduler asynchronously
 	go announceFunc()
 
@@ -930,6 +1027,11 @@ func (s *server) ImportTask(ctx context.Context, req *dfdaemonv1.ImportTaskReque
 }
 
 func (s *server) ExportTask(ctx context.Context, req *dfdaemonv1.ExportTaskRequest) (*emptypb.Empty, error) {
+	// Scheduler asks to hold a replica of the persistent cache task without output.
+	if rpc.IsReplicateFromIncomingContext(ctx) {
+		return s.replicateTask(ctx, req)
+	}
+
 	_, err := os.Stat(req.Output)
 	if err == nil {
 		// we did not export file to exist file
@@ -966,6 +1068,47 @@ func (s *server) ExportTask(ctx context.Context, req *dfdaemonv1.ExportTaskReque
 	return new(emptypb.Empty), nil
 }
 
+// replicateTask downloads the persistent cache task from peers into local storage, and pins it.
+func (s *server) replicateTask(ctx context.Context, req *dfdaemonv1.ExportTaskRequest) (*emptypb.Empty, error) {
+	s.Keep()
+	taskID := idgen.TaskIDV1(req.Url, req.UrlMeta)
+	if id, ok := rpc.TaskIDFromIncomingContext(ctx); ok && id != taskID {
+		msg := fmt.Sprintf("task id %s is not matched with the replicated task %s", taskID, id)
+		logger.Error(msg)
+		return nil, dferrors.New(commonv1.Code_BadRequest, msg)
+	}
+
+	log := logger.With("function", "ReplicateTask", "URL", req.Url, "Tag", req.UrlMeta.Tag, "taskID", taskID)
+	log.Info("new replicate task request")
+	if s.isTaskCompleted(taskID) {
+		log.Info("task found in local storage")
+		s.storageManager.PinTask(taskID)
+		return new(emptypb.Empty), nil
+	}
+
+	start := time.Now()
+	rc, _, err := s.peerTaskManager.StartStreamTask(ctx, &peer.StreamTaskRequest{
+		URL:     req.Url,
+		URLMeta: req.UrlMeta,
+		PeerID:  idgen.PeerIDV1(s.peerHost.Ip),
+	})
+	if err != nil {
+		log.Errorf("start replicate task failed: %s", err)
+		return nil, dferrors.New(commonv1.Code_ClientError, err.Error())
+	}
+	defer rc.Close()
+
+	n, err := io.Copy(io.Discard, rc)
+	if err != nil {
+		log.Errorf("replicate task failed: %s", err)
+		return nil, dferrors.New(commonv1.Code_ClientError, err.Error())
+	}
+
+	s.storageManager.PinTask(taskID)
+	log.Infof("replicate task successfully, length: %d bytes cost: %.6f s", n, time.Since(start).Seconds())
+	return new(emptypb.Empty), nil
+}
+
 func (s *server) exportFromLocal(ctx context.Context, req *dfdaemonv1.ExportTaskRequest, peerID string, log *logger.SugaredLoggerOnWith) error {
 	if err := s.storageManager.Store(ctx, &storage.StoreRequest{
 		CommonTaskRequest: storage.CommonTaskRequest{
@@ -1077,6 +1220,13 @@ func call(ctx context.Context, peerID string, sender ResultSender, s *server, re
 func (s *server) DeleteTask(ctx context.Context, req *dfdaemonv1.DeleteTaskRequest) (*emptypb.Empty, error) {
 	s.Keep()
 	taskID := idgen.TaskIDV1(req.Url, req.UrlMeta)
+
+	// Scheduler announces the task deletion with the task id,
+	// because the url meta of the task may not be recovered completely.
+	if id, ok := rpc.TaskIDFromIncomingContext(ctx); ok {
+		taskID = id
+	}
+
 	log := logger.With("function", "DeleteTask", "URL", req.Url, "Tag", req.UrlMeta.Tag, "taskID", taskID)
 
 	log.Info("new delete task request")
@@ -18,10 +18,12 @@ package rpcserver
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"testing"
 
@@ -30,12 +32,15 @@ import (
 	testifyassert "github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/metadata"
 
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
 	dfdaemonv1 "d7y.io/api/v2/pkg/apis/dfdaemon/v1"
 	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
 
+	configmocks "d7y.io/dragonfly/v2/client/config/mocks"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	signaturemocks "d7y.io/dragonfly/v2/client/daemon/signature/mocks"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	"d7y.io/dragonfly/v2/client/daemon/storage/mocks"
 	"d7y.io/dragonfly/v2/client/util"
@@ -44,8 +49,10 @@ import (
 	"d7y.io/dragonfly/v2/pkg/dfnet"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/net/ip"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
 	dfdaemonserver "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/server"
+	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/resource"
 )
 
@@ -86,6 +93,7 @@ func TestServer_DeleteTask(t *testing.T) {
 
 	tests := []struct {
 		name   string
+		ctx    context.Context
 		r      *dfdaemonv1.DeleteTaskRequest
 		mock   func(mockStorageManger *mocks.MockManagerMockRecorder, mockTaskManager *peer.MockTaskManagerMockRecorder, mockTask *mocks.MockTaskStorageDriver, mockPieceManager *peer.MockPieceManager)
 		expect func(t *testing.T, r *dfdaemonv1.DeleteTaskRequest, err error)
@@ -131,6 +139,24 @@ func TestServer_DeleteTask(t *testing.T) {
 				assert.Nil(err)
 			},
 		},
+		{
+			name: "delete task by task id announced by scheduler",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(rpc.ContextWithTaskID(context.Background(), "foo"))
+				return metadata.NewIncomingContext(context.Background(), md)
+			}(),
+			r: &dfdaemonv1.DeleteTaskRequest{
+				UrlMeta: &commonv1.UrlMeta{},
+			},
+			mock: func(mockStorageManger *mocks.MockManagerMockRecorder, mockTaskManager *peer.MockTaskManagerMockRecorder, mocktsd *mocks.MockTaskStorageDriver, mockPieceManager *peer.MockPieceManager) {
+				mockStorageManger.FindCompletedTask("foo").Return(&storage.ReusePeerTask{})
+				mockStorageManger.UnregisterTask(gomock.Any(), storage.CommonTaskRequest{TaskID: "foo"}).Return(nil)
+			},
+			expect: func(t *testing.T, r *dfdaemonv1.DeleteTaskRequest, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -145,7 +171,12 @@ func TestServer_DeleteTask(t *testing.T) {
 				storageManager:  mockStorageManger,
 				peerTaskManager: mockTaskManager,
 			}
-			_, err := s.DeleteTask(context.Background(), tc.r)
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx
+			}
+
+			_, err := s.DeleteTask(ctx, tc.r)
 			tc.expect(t, tc.r, err)
 		})
 	}
@@ -157,6 +188,7 @@ func TestServer_ExportTask(t *testing.T) {
 
 	tests := []struct {
 		name   string
+		ctx    context.Context
 		r      *dfdaemonv1.ExportTaskRequest
 		mock   func(mockStorageManger *mocks.MockManagerMockRecorder, mockTaskManager *peer.MockTaskManagerMockRecorder, mockTask *mocks.MockTaskStorageDriver, mockPieceManager *peer.MockPieceManager)
 		expect func(t *testing.T, r *dfdaemonv1.ExportTaskRequest, err error)
@@ -280,6 +312,66 @@ func TestServer_ExportTask(t *testing.T) {
 				assert.Nil(err)
 			},
 		},
+		{
+			name: "replicate task found in local storage",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(rpc.ContextWithReplicate(context.Background()))
+				return metadata.NewIncomingContext(context.Background(), md)
+			}(),
+			r: &dfdaemonv1.ExportTaskRequest{
+				Url:     "d7y:/foo",
+				UrlMeta: &commonv1.UrlMeta{},
+			},
+			mock: func(mockStorageManger *mocks.MockManagerMockRecorder, mockTaskManager *peer.MockTaskManagerMockRecorder, mocktsd *mocks.MockTaskStorageDriver, mockPieceManager *peer.MockPieceManager) {
+				taskID := idgen.TaskIDV1("d7y:/foo", &commonv1.UrlMeta{})
+				mockStorageManger.FindCompletedTask(taskID).Return(&storage.ReusePeerTask{})
+				mockStorageManger.PinTask(taskID)
+			},
+			expect: func(t *testing.T, r *dfdaemonv1.ExportTaskRequest, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+			},
+		},
+		{
+			name: "replicate task from peers",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(rpc.ContextWithReplicate(context.Background()))
+				return metadata.NewIncomingContext(context.Background(), md)
+			}(),
+			r: &dfdaemonv1.ExportTaskRequest{
+				Url:     "d7y:/foo",
+				UrlMeta: &commonv1.UrlMeta{},
+			},
+			mock: func(mockStorageManger *mocks.MockManagerMockRecorder, mockTaskManager *peer.MockTaskManagerMockRecorder, mocktsd *mocks.MockTaskStorageDriver, mockPieceManager *peer.MockPieceManager) {
+				taskID := idgen.TaskIDV1("d7y:/foo", &commonv1.UrlMeta{})
+				mockStorageManger.FindCompletedTask(taskID).Return(nil)
+				mockTaskManager.StartStreamTask(gomock.Any(), gomock.Any()).Return(io.NopCloser(strings.NewReader("foo")), nil, nil)
+				mockStorageManger.PinTask(taskID)
+			},
+			expect: func(t *testing.T, r *dfdaemonv1.ExportTaskRequest, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+			},
+		},
+		{
+			name: "replicate task failed",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(rpc.ContextWithReplicate(context.Background()))
+				return metadata.NewIncomingContext(context.Background(), md)
+			}(),
+			r: &dfdaemonv1.ExportTaskRequest{
+				Url:     "d7y:/foo",
+				UrlMeta: &commonv1.UrlMeta{},
+			},
+			mock: func(mockStorageManger *mocks.MockManagerMockRecorder, mockTaskManager *peer.MockTaskManagerMockRecorder, mocktsd *mocks.MockTaskStorageDriver, mockPieceManager *peer.MockPieceManager) {
+				mockStorageManger.FindCompletedTask(gomock.Any()).Return(nil)
+				mockTaskManager.StartStreamTask(gomock.Any(), gomock.Any()).Return(nil, nil, errors.New("foo"))
+			},
+			expect: func(t *testing.T, r *dfdaemonv1.ExportTaskRequest, err error) {
+				assert := testifyassert.New(t)
+				assert.Error(err)
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -294,7 +386,12 @@ func TestServer_ExportTask(t *testing.T) {
 				storageManager:  mockStorageManger,
 				peerTaskManager: mockTaskManager,
 			}
-			_, err := s.ExportTask(context.Background(), tc.r)
+			ctx := context.Background()
+			if tc.ctx != nil {
+				ctx = tc.ctx
+			}
+
+			_, err := s.ExportTask(ctx, tc.r)
 			tc.expect(t, tc.r, err)
 		})
 	}
@@ -714,6 +811,148 @@ func TestServer_ServeDownload(t *testing.T) {
 	assert.True(lastResult.Done)
 }
 
+func TestServer_ServeDownloadWithSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder)
+		ctx    func(ctx context.Context) context.Context
+		expect func(t *testing.T, result *dfdaemonv1.DownResult, err error, output string)
+	}{
+		{
+			name: "verify signature",
+			mock: func(verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				verifier.Verify(gomock.Any(), types.SignatureTypeCosign, "foo", gomock.Any()).Return(nil).Times(1)
+			},
+			ctx: func(ctx context.Context) context.Context {
+				return rpc.ContextWithSignature(ctx, types.SignatureTypeCosign, "foo")
+			},
+			expect: func(t *testing.T, result *dfdaemonv1.DownResult, err error, output string) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.True(result.Done)
+				assert.FileExists(output)
+			},
+		},
+		{
+			name: "verify signature failed",
+			mock: func(verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				verifier.Verify(gomock.Any(), types.SignatureTypeCosign, "foo", gomock.Any()).Return(errors.New("bar")).Times(1)
+			},
+			ctx: func(ctx context.Context) context.Context {
+				return rpc.ContextWithSignature(ctx, types.SignatureTypeCosign, "foo")
+			},
+			expect: func(t *testing.T, result *dfdaemonv1.DownResult, err error, output string) {
+				assert := testifyassert.New(t)
+				assert.Error(err)
+				assert.NoFileExists(output)
+			},
+		},
+		{
+			name: "application requires signature",
+			mock: func(verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				dynconfig.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{
+					"baz": {RequireSignature: true},
+				}, nil).Times(1)
+			},
+			ctx: func(ctx context.Context) context.Context {
+				return ctx
+			},
+			expect: func(t *testing.T, result *dfdaemonv1.DownResult, err error, output string) {
+				assert := testifyassert.New(t)
+				assert.Error(err)
+				assert.Nil(result)
+			},
+		},
+		{
+			name: "application policies are unavailable",
+			mock: func(verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				dynconfig.GetApplicationPolicies().Return(nil, errors.New("foo")).Times(1)
+			},
+			ctx: func(ctx context.Context) context.Context {
+				return ctx
+			},
+			expect: func(t *testing.T, result *dfdaemonv1.DownResult, err error, output string) {
+				assert := testifyassert.New(t)
+				assert.Error(err)
+				assert.Nil(result)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			output := path.Join(t.TempDir(), "output")
+			mockPeerTaskManager := peer.NewMockTaskManager(ctrl)
+			mockPeerTaskManager.EXPECT().StartFileTask(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, req *peer.FileTaskRequest) (chan *peer.FileTaskProgress, bool, error) {
+					if err := os.WriteFile(req.Output, []byte("foo"), 0644); err != nil {
+						return nil, false, err
+					}
+
+					ch := make(chan *peer.FileTaskProgress, 1)
+					ch <- &peer.FileTaskProgress{
+						State:           &peer.ProgressState{Success: true},
+						ContentLength:   3,
+						CompletedLength: 3,
+						PeerTaskDone:    true,
+						DoneCallback:    func() {},
+					}
+					close(ch)
+					return ch, false, nil
+				}).AnyTimes()
+
+			verifier := signaturemocks.NewMockVerifier(ctrl)
+			dynconfig := configmocks.NewMockDynconfig(ctrl)
+			tc.mock(verifier.EXPECT(), dynconfig.EXPECT())
+			s := &server{
+				KeepAlive:         util.NewKeepAlive("test"),
+				peerHost:          &schedulerv1.PeerHost{},
+				peerTaskManager:   mockPeerTaskManager,
+				signatureVerifier: verifier,
+				dynconfig:         dynconfig,
+			}
+
+			socketDir, err := os.MkdirTemp(os.TempDir(), "d7y-test-***")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(socketDir)
+
+			client := setupPeerServerAndClient(t, path.Join(socketDir, "rpc.sock"), s, testifyassert.New(t), s.ServeDownload)
+			defer s.downloadServer.GracefulStop()
+
+			down, err := client.Download(tc.ctx(context.Background()), &dfdaemonv1.DownRequest{
+				Uuid:    uuid.Generate().String(),
+				Url:     "http://localhost/test",
+				Output:  output,
+				UrlMeta: &commonv1.UrlMeta{Application: "baz"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var lastResult *dfdaemonv1.DownResult
+			for {
+				result, err := down.Recv()
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					tc.expect(t, lastResult, err, output)
+					return
+				}
+				lastResult = result
+			}
+
+			tc.expect(t, lastResult, nil, output)
+		})
+	}
+}
+
 func TestServer_ServePeer(t *testing.T) {
 	assert := testifyassert.New(t)
 	ctrl := gomock.NewController(t)
@@ -0,0 +1,324 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/signature_mock.go -source signature.go -package mocks
+
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// maxSignatureSize is the max size of the signature fetched from the url.
+	maxSignatureSize = 1 << 20
+
+	// fetchTimeout is the timeout of fetching the signature from the url.
+	fetchTimeout = 30 * time.Second
+)
+
+var (
+	// ErrNoTrustedKeys is returned when no keys of the signature type are trusted.
+	ErrNoTrustedKeys = errors.New("no trusted keys of the signature type")
+
+	// ErrInvalidSignature is returned when the artifact is not signed by the trusted keys.
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrURLNotAllowed is returned when the signature url is not in the allowed urls.
+	ErrURLNotAllowed = errors.New("signature url is not allowed")
+)
+
+// Verifier verifies the signatures of the downloaded artifacts.
+type Verifier interface {
+	// Verify verifies the signature of the file against the trusted keys of the signature type,
+	// the signature is fetched when it is an url.
+	Verify(ctx context.Context, typ, signature, path string) error
+}
+
+// verifier verifies the cosign signatures and the gpg detached signatures.
+type verifier struct {
+	cosignKeys  []crypto.PublicKey
+	gpgKeyring  openpgp.EntityList
+	allowedURLs []*url.URL
+	client      *http.Client
+}
+
+// New returns a new Verifier instance.
+func New(cfg config.SignatureOption) (Verifier, error) {
+	v := &verifier{}
+	v.client = &http.Client{
+		Timeout: fetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !v.allowURL(req.URL) {
+				return fmt.Errorf("%w: redirect to %s", ErrURLNotAllowed, req.URL.Redacted())
+			}
+
+			return nil
+		},
+	}
+
+	for _, rawURL := range cfg.AllowedURLs {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, err
+		}
+
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid allowed signature url %s", rawURL)
+		}
+
+		v.allowedURLs = append(v.allowedURLs, u)
+	}
+
+	for _, path := range cfg.CosignPublicKeys {
+		key, err := loadCosignPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		v.cosignKeys = append(v.cosignKeys, key)
+	}
+
+	for _, path := range cfg.GPGPublicKeys {
+		keyring, err := loadGPGPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		v.gpgKeyring = append(v.gpgKeyring, keyring...)
+	}
+
+	return v, nil
+}
+
+// Verify verifies the signature of the file against the trusted keys of the signature type,
+// the signature is fetched when it is an url.
+func (v *verifier) Verify(ctx context.Context, typ, signature, path string) error {
+	data, err := v.load(ctx, signature)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch typ {
+	case types.SignatureTypeCosign:
+		return v.verifyCosign(data, file)
+	case types.SignatureTypeGPG:
+		return v.verifyGPG(data, file)
+	default:
+		return fmt.Errorf("unsupported signature type: %s", typ)
+	}
+}
+
+// Required returns whether the application policy requires the signature of the artifact,
+// an error is returned when the policies are unavailable, so the callers fail closed.
+func Required(dynconfig config.Dynconfig, application string) (bool, error) {
+	if dynconfig == nil || application == "" {
+		return false, nil
+	}
+
+	policies, err := dynconfig.GetApplicationPolicies()
+	if err != nil {
+		// The local dynconfig has no application policies.
+		if errors.Is(err, config.ErrUnimplemented) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return policies[application].RequireSignature, nil
+}
+
+// load returns the inline signature, or fetches the signature when it is an url
+// under the allowed urls.
+func (v *verifier) load(ctx context.Context, signature string) ([]byte, error) {
+	if !strings.HasPrefix(signature, "http://") && !strings.HasPrefix(signature, "https://") {
+		return []byte(signature), nil
+	}
+
+	u, err := url.Parse(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if !v.allowURL(u) {
+		return nil, fmt.Errorf("%w: %s", ErrURLNotAllowed, u.Redacted())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch signature from %s failed, status code: %d", signature, resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxSignatureSize))
+}
+
+// allowURL returns whether the url is under one of the allowed urls, the scheme and host
+// must be the same and the path must be under the path of the allowed url, the urls
+// with user info or unclean paths are not allowed.
+func (v *verifier) allowURL(u *url.URL) bool {
+	p := path.Clean("/" + u.Path)
+	if u.User != nil || p != u.Path {
+		return false
+	}
+
+	for _, allowed := range v.allowedURLs {
+		if u.Scheme != allowed.Scheme || !strings.EqualFold(u.Host, allowed.Host) {
+			continue
+		}
+
+		prefix := path.Clean("/" + allowed.Path)
+		if prefix == "/" || p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyCosign verifies the base64 encoded cosign signature over the sha256 digest of the artifact.
+func (v *verifier) verifyCosign(signature []byte, r io.Reader) error {
+	if len(v.cosignKeys) == 0 {
+		return ErrNoTrustedKeys
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("decode cosign signature error: %w", err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return err
+	}
+	digest := h.Sum(nil)
+
+	for _, key := range v.cosignKeys {
+		switch key := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest, sig) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) == nil {
+				return nil
+			}
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+// verifyGPG verifies the armored or binary gpg detached signature of the artifact.
+func (v *verifier) verifyGPG(signature []byte, r io.Reader) error {
+	if len(v.gpgKeyring) == 0 {
+		return ErrNoTrustedKeys
+	}
+
+	var err error
+	if isArmored(signature) {
+		_, err = openpgp.CheckArmoredDetachedSignature(v.gpgKeyring, r, bytes.NewReader(signature), nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(v.gpgKeyring, r, bytes.NewReader(signature), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
+
+// loadCosignPublicKey loads the pem encoded ecdsa or rsa cosign public key.
+func loadCosignPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid pem file %s", path)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("public key %s is not an ecdsa or rsa key", path)
+	}
+}
+
+// loadGPGPublicKey loads the armored or binary gpg public keys.
+func loadGPGPublicKey(path string) (openpgp.EntityList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if isArmored(data) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	}
+
+	return openpgp.ReadKeyRing(bytes.NewReader(data))
+}
+
+// isArmored returns whether the data is ascii armored.
+func isArmored(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN "))
+}
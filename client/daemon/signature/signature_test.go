@@ -0,0 +1,410 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signature
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+	configmocks "d7y.io/dragonfly/v2/client/config/mocks"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+var testData = []byte("dragonfly signature test data")
+
+func writeCosignKey(t *testing.T, dir string) (*ecdsa.PrivateKey, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "cosign.pub")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return privateKey, path
+}
+
+func writeGPGKey(t *testing.T, dir string) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("dragonfly", "", "dragonfly@d7y.io", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "gpg.pub")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return entity, path
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	dir := t.TempDir()
+	cosignKey, cosignPath := writeCosignKey(t, dir)
+	gpgEntity, gpgPath := writeGPGKey(t, dir)
+
+	artifactPath := filepath.Join(dir, "artifact")
+	if err := os.WriteFile(artifactPath, testData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256(testData)
+	rawCosignSignature, err := ecdsa.SignASN1(rand.Reader, cosignKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	cosignSignature := base64.StdEncoding.EncodeToString(rawCosignSignature)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawOtherSignature, err := ecdsa.SignASN1(rand.Reader, otherKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSignature := base64.StdEncoding.EncodeToString(rawOtherSignature)
+
+	armoredGPGSignature := &bytes.Buffer{}
+	if err := openpgp.ArmoredDetachSign(armoredGPGSignature, gpgEntity, bytes.NewReader(testData), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	binaryGPGSignature := &bytes.Buffer{}
+	if err := openpgp.DetachSign(binaryGPGSignature, gpgEntity, bytes.NewReader([]byte("other data")), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/signatures/artifact.sig":
+			w.Write([]byte(cosignSignature))
+		case "/redirect.sig":
+			http.Redirect(w, r, "/other/artifact.sig", http.StatusFound)
+		case "/other/artifact.sig":
+			w.Write([]byte(cosignSignature))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		cfg       config.SignatureOption
+		typ       string
+		signature string
+		expect    func(t *testing.T, err error)
+	}{
+		{
+			name:      "verify inline cosign signature",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}},
+			typ:       types.SignatureTypeCosign,
+			signature: cosignSignature,
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:      "verify cosign signature from url",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}, AllowedURLs: []string{server.URL + "/signatures"}},
+			typ:       types.SignatureTypeCosign,
+			signature: server.URL + "/signatures/artifact.sig",
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:      "fetch cosign signature from url failed",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}, AllowedURLs: []string{server.URL + "/signatures"}},
+			typ:       types.SignatureTypeCosign,
+			signature: server.URL + "/signatures/missing.sig",
+			expect: func(t *testing.T, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name:      "signature url without allowed urls",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}},
+			typ:       types.SignatureTypeCosign,
+			signature: server.URL + "/signatures/artifact.sig",
+			expect: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, ErrURLNotAllowed))
+			},
+		},
+		{
+			name:      "signature url outside the allowed urls",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}, AllowedURLs: []string{server.URL + "/signatures"}},
+			typ:       types.SignatureTypeCosign,
+			signature: server.URL + "/signatures/../other/artifact.sig",
+			expect: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, ErrURLNotAllowed))
+			},
+		},
+		{
+			name:      "signature url redirects outside the allowed urls",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}, AllowedURLs: []string{server.URL + "/redirect.sig"}},
+			typ:       types.SignatureTypeCosign,
+			signature: server.URL + "/redirect.sig",
+			expect: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, ErrURLNotAllowed))
+			},
+		},
+		{
+			name:      "cosign signature signed by untrusted key",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}},
+			typ:       types.SignatureTypeCosign,
+			signature: otherSignature,
+			expect: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, ErrInvalidSignature))
+			},
+		},
+		{
+			name:      "cosign signature without trusted keys",
+			cfg:       config.SignatureOption{GPGPublicKeys: []string{gpgPath}},
+			typ:       types.SignatureTypeCosign,
+			signature: cosignSignature,
+			expect: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, ErrNoTrustedKeys))
+			},
+		},
+		{
+			name:      "verify armored gpg signature",
+			cfg:       config.SignatureOption{GPGPublicKeys: []string{gpgPath}},
+			typ:       types.SignatureTypeGPG,
+			signature: armoredGPGSignature.String(),
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:      "gpg signature of other data",
+			cfg:       config.SignatureOption{GPGPublicKeys: []string{gpgPath}},
+			typ:       types.SignatureTypeGPG,
+			signature: binaryGPGSignature.String(),
+			expect: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, ErrInvalidSignature))
+			},
+		},
+		{
+			name:      "gpg signature without trusted keys",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}},
+			typ:       types.SignatureTypeGPG,
+			signature: armoredGPGSignature.String(),
+			expect: func(t *testing.T, err error) {
+				assert.True(t, errors.Is(err, ErrNoTrustedKeys))
+			},
+		},
+		{
+			name:      "unsupported signature type",
+			cfg:       config.SignatureOption{CosignPublicKeys: []string{cosignPath}},
+			typ:       "foo",
+			signature: cosignSignature,
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "unsupported signature type: foo")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier, err := New(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, verifier.Verify(context.Background(), tc.typ, tc.signature, artifactPath))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	invalidPath := filepath.Join(dir, "invalid.pub")
+	if err := os.WriteFile(invalidPath, []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		cfg    config.SignatureOption
+		expect func(t *testing.T, err error)
+	}{
+		{
+			name: "without keys",
+			cfg:  config.SignatureOption{},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "cosign public key not found",
+			cfg:  config.SignatureOption{CosignPublicKeys: []string{filepath.Join(dir, "missing.pub")}},
+			expect: func(t *testing.T, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "invalid cosign public key",
+			cfg:  config.SignatureOption{CosignPublicKeys: []string{invalidPath}},
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "invalid pem file "+invalidPath)
+			},
+		},
+		{
+			name: "invalid gpg public key",
+			cfg:  config.SignatureOption{GPGPublicKeys: []string{invalidPath}},
+			expect: func(t *testing.T, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "invalid allowed url",
+			cfg:  config.SignatureOption{AllowedURLs: []string{"ftp://example.com"}},
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "invalid allowed signature url ftp://example.com")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.cfg)
+			tc.expect(t, err)
+		})
+	}
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		name        string
+		application string
+		mock        func(m *configmocks.MockDynconfigMockRecorder)
+		expect      func(t *testing.T, required bool, err error)
+	}{
+		{
+			name:        "application requires signature",
+			application: "foo",
+			mock: func(m *configmocks.MockDynconfigMockRecorder) {
+				m.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{"foo": {RequireSignature: true}}, nil).Times(1)
+			},
+			expect: func(t *testing.T, required bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.True(required)
+			},
+		},
+		{
+			name:        "application without policy",
+			application: "bar",
+			mock: func(m *configmocks.MockDynconfigMockRecorder) {
+				m.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{"foo": {RequireSignature: true}}, nil).Times(1)
+			},
+			expect: func(t *testing.T, required bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.False(required)
+			},
+		},
+		{
+			name: "without application",
+			mock: func(m *configmocks.MockDynconfigMockRecorder) {},
+			expect: func(t *testing.T, required bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.False(required)
+			},
+		},
+		{
+			name:        "application policies are unimplemented",
+			application: "foo",
+			mock: func(m *configmocks.MockDynconfigMockRecorder) {
+				m.GetApplicationPolicies().Return(nil, config.ErrUnimplemented).Times(1)
+			},
+			expect: func(t *testing.T, required bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.False(required)
+			},
+		},
+		{
+			name:        "get application policies failed",
+			application: "foo",
+			mock: func(m *configmocks.MockDynconfigMockRecorder) {
+				m.GetApplicationPolicies().Return(nil, errors.New("baz")).Times(1)
+			},
+			expect: func(t *testing.T, required bool, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "baz")
+				assert.False(required)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			dynconfig := configmocks.NewMockDynconfig(ctl)
+			tc.mock(dynconfig.EXPECT())
+			required, err := Required(dynconfig, tc.application)
+			tc.expect(t, required, err)
+		})
+	}
+}
@@ -25,6 +25,9 @@ const (
 	taskData     = "data"
 	taskMetadata = "metadata"
 
+	// metadataTmpSuffix is the suffix of the temporary metadata file during saving
+	metadataTmpSuffix = ".tmp"
+
 	defaultFileMode      = os.FileMode(0644)
 	defaultDirectoryMode = os.FileMode(0700) // used unless overridden in config
 )
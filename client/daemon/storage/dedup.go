@@ -0,0 +1,138 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"sync"
+
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+)
+
+// errDedupeDiffers is returned when the contents of the deduplicated ranges differ.
+var errDedupeDiffers = errors.New("dedupe range contents differ")
+
+// pieceLocation is the range of a piece in the data file.
+type pieceLocation struct {
+	dataFilePath string
+	start        int64
+	length       int64
+}
+
+// dedupKey addresses the piece by its content.
+type dedupKey struct {
+	digest string
+	length int64
+}
+
+// dedupRef is a reference of the piece from a peer task.
+type dedupRef struct {
+	meta PeerTaskMetadata
+	num  int32
+}
+
+// dedupIndex is a content addressable index of the pieces, the pieces with the same digest
+// are stored once on the file systems supporting deduplication and reference counted here.
+type dedupIndex struct {
+	mu sync.Mutex
+	// entries are the references of the unique pieces, key is the digest and length of the piece.
+	entries map[dedupKey]map[dedupRef]pieceLocation
+	// keys are the pieces referenced by the peer task.
+	keys map[PeerTaskMetadata][]dedupKey
+}
+
+func newDedupIndex() *dedupIndex {
+	return &dedupIndex{
+		entries: map[dedupKey]map[dedupRef]pieceLocation{},
+		keys:    map[PeerTaskMetadata][]dedupKey{},
+	}
+}
+
+// Add references the piece of the peer task, and returns the location of the identical piece
+// stored by another reference if exists.
+func (d *dedupIndex) Add(meta PeerTaskMetadata, num int32, digest string, loc pieceLocation) (pieceLocation, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := dedupKey{digest: digest, length: loc.length}
+	ref := dedupRef{meta: meta, num: num}
+	refs, ok := d.entries[key]
+	if !ok {
+		refs = map[dedupRef]pieceLocation{}
+		d.entries[key] = refs
+		metrics.StorageDedupPieceGauge.Inc()
+	}
+
+	if _, ok := refs[ref]; ok {
+		return pieceLocation{}, false
+	}
+
+	var (
+		src   pieceLocation
+		found bool
+	)
+	for _, l := range refs {
+		src, found = l, true
+		break
+	}
+
+	refs[ref] = loc
+	d.keys[meta] = append(d.keys[meta], key)
+	return src, found
+}
+
+// Remove dereferences all the pieces of the peer task, the pieces without references are removed from the index.
+func (d *dedupIndex) Remove(meta PeerTaskMetadata) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range d.keys[meta] {
+		refs, ok := d.entries[key]
+		if !ok {
+			continue
+		}
+
+		for ref := range refs {
+			if ref.meta == meta {
+				delete(refs, ref)
+			}
+		}
+
+		if len(refs) == 0 {
+			delete(d.entries, key)
+			metrics.StorageDedupPieceGauge.Dec()
+		}
+	}
+
+	delete(d.keys, meta)
+}
+
+// Len returns the number of the unique pieces in the index.
+func (d *dedupIndex) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.entries)
+}
+
+// Refs returns the number of the references of the piece.
+func (d *dedupIndex) Refs(digest string, length int64) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.entries[dedupKey{digest: digest, length: length}])
+}
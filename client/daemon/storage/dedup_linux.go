@@ -0,0 +1,67 @@
+//go:build linux
+
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// dedupeRange shares the extents of the src range with the dst range, the file system verifies
+// the contents are identical before sharing, and returns the bytes deduplicated.
+func dedupeRange(src, dst pieceLocation) (int64, error) {
+	srcFile, err := os.Open(src.dataFilePath)
+	if err != nil {
+		return 0, err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst.dataFilePath, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer dstFile.Close()
+
+	value := &unix.FileDedupeRange{
+		Src_offset: uint64(src.start),
+		Src_length: uint64(src.length),
+		Info: []unix.FileDedupeRangeInfo{
+			{
+				Dest_fd:     int64(dstFile.Fd()),
+				Dest_offset: uint64(dst.start),
+			},
+		},
+	}
+	if err := unix.IoctlFileDedupeRange(int(srcFile.Fd()), value); err != nil {
+		return 0, err
+	}
+
+	info := value.Info[0]
+	if info.Status < 0 {
+		return 0, syscall.Errno(-info.Status)
+	}
+
+	if info.Status == unix.FILE_DEDUPE_RANGE_DIFFERS {
+		return 0, errDedupeDiffers
+	}
+
+	return int64(info.Bytes_deduped), nil
+}
@@ -0,0 +1,132 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func Test_dedupIndex(t *testing.T) {
+	task1 := PeerTaskMetadata{TaskID: "task1", PeerID: "peer1"}
+	task2 := PeerTaskMetadata{TaskID: "task2", PeerID: "peer2"}
+	loc1 := pieceLocation{dataFilePath: "data1", start: 0, length: 10}
+	loc2 := pieceLocation{dataFilePath: "data2", start: 10, length: 10}
+
+	testCases := []struct {
+		name string
+		run  func(t *testing.T, d *dedupIndex)
+	}{
+		{
+			name: "add unique piece",
+			run: func(t *testing.T, d *dedupIndex) {
+				assert := testifyassert.New(t)
+				_, ok := d.Add(task1, 0, "digest", loc1)
+				assert.False(ok)
+				assert.Equal(1, d.Len())
+				assert.Equal(1, d.Refs("digest", 10))
+			},
+		},
+		{
+			name: "add identical piece of another task",
+			run: func(t *testing.T, d *dedupIndex) {
+				assert := testifyassert.New(t)
+				d.Add(task1, 0, "digest", loc1)
+				src, ok := d.Add(task2, 1, "digest", loc2)
+				assert.True(ok)
+				assert.Equal(loc1, src)
+				assert.Equal(1, d.Len())
+				assert.Equal(2, d.Refs("digest", 10))
+			},
+		},
+		{
+			name: "add same piece twice",
+			run: func(t *testing.T, d *dedupIndex) {
+				assert := testifyassert.New(t)
+				d.Add(task1, 0, "digest", loc1)
+				_, ok := d.Add(task1, 0, "digest", loc1)
+				assert.False(ok)
+				assert.Equal(1, d.Refs("digest", 10))
+			},
+		},
+		{
+			name: "same digest with different length",
+			run: func(t *testing.T, d *dedupIndex) {
+				assert := testifyassert.New(t)
+				d.Add(task1, 0, "digest", loc1)
+				_, ok := d.Add(task2, 0, "digest", pieceLocation{dataFilePath: "data2", length: 5})
+				assert.False(ok)
+				assert.Equal(2, d.Len())
+			},
+		},
+		{
+			name: "remove peer task",
+			run: func(t *testing.T, d *dedupIndex) {
+				assert := testifyassert.New(t)
+				d.Add(task1, 0, "digest", loc1)
+				d.Add(task1, 1, "digest", pieceLocation{dataFilePath: "data1", start: 10, length: 10})
+				d.Add(task2, 1, "digest", loc2)
+				d.Add(task1, 2, "other", loc1)
+
+				d.Remove(task1)
+				assert.Equal(1, d.Len())
+				assert.Equal(1, d.Refs("digest", 10))
+				assert.Equal(0, d.Refs("other", 10))
+
+				src, ok := d.Add(task1, 0, "digest", loc1)
+				assert.True(ok)
+				assert.Equal(loc2, src)
+
+				d.Remove(task1)
+				d.Remove(task2)
+				assert.Equal(0, d.Len())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newDedupIndex())
+		})
+	}
+}
+
+func Test_dedupeRange(t *testing.T) {
+	assert := testifyassert.New(t)
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte{'a'}, 4096)
+
+	src := pieceLocation{dataFilePath: filepath.Join(dir, "src"), length: int64(len(data))}
+	dst := pieceLocation{dataFilePath: filepath.Join(dir, "dst"), length: int64(len(data))}
+	assert.Nil(os.WriteFile(src.dataFilePath, data, defaultFileMode))
+	assert.Nil(os.WriteFile(dst.dataFilePath, data, defaultFileMode))
+
+	// the file system of the temp dir may not support deduplication
+	n, err := dedupeRange(src, dst)
+	if err != nil {
+		t.Skipf("dedupe range is not supported: %s", err)
+	}
+	assert.Equal(int64(len(data)), n)
+
+	content, err := os.ReadFile(dst.dataFilePath)
+	assert.Nil(err)
+	assert.Equal(data, content)
+}
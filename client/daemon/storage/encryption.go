@@ -0,0 +1,227 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"d7y.io/dragonfly/v2/client/config"
+)
+
+const (
+	// encryptionKeySize is the size of the aes-256 key of the node.
+	encryptionKeySize = 32
+
+	// encryptionKeyIDMessage is the message authenticated by the key to identify the key.
+	encryptionKeyIDMessage = "dragonfly-storage-encryption"
+)
+
+// encryptionMetadata is the encryption of the data file persisted in the metadata.
+type encryptionMetadata struct {
+	// KeyID identifies the key encrypting the data file.
+	KeyID string `json:"keyID"`
+
+	// IV is the initial counter of the data file.
+	IV []byte `json:"iv"`
+}
+
+// dataCipher encrypts the data files with aes-256-ctr, the counter is derived from the offset
+// in the data file, so the pieces are written and read at any offset without changing the layout
+// of the data file. A piece is written at its offset only once, so the key stream is not reused
+// for different data, and the integrity of the pieces is guaranteed by their digests.
+type dataCipher struct {
+	block cipher.Block
+	keyID string
+}
+
+// newDataCipher returns a cipher of the key of the node.
+func newDataCipher(key []byte) (*dataCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encryptionKeyIDMessage))
+	return &dataCipher{
+		block: block,
+		keyID: hex.EncodeToString(mac.Sum(nil)[:8]),
+	}, nil
+}
+
+// newEncryption returns the encryption of a new data file with a random initial counter.
+func (c *dataCipher) newEncryption() (*encryptionMetadata, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	return &encryptionMetadata{KeyID: c.keyID, IV: iv}, nil
+}
+
+// stream returns the key stream of the data file starting at the offset.
+func (c *dataCipher) stream(iv []byte, offset int64) cipher.Stream {
+	counter := make([]byte, aes.BlockSize)
+	copy(counter, iv)
+
+	// Add the block index to the 128 bits big endian counter.
+	low := binary.BigEndian.Uint64(counter[8:])
+	sum := low + uint64(offset/aes.BlockSize)
+	binary.BigEndian.PutUint64(counter[8:], sum)
+	if sum < low {
+		binary.BigEndian.PutUint64(counter[:8], binary.BigEndian.Uint64(counter[:8])+1)
+	}
+
+	stream := cipher.NewCTR(c.block, counter)
+	if skip := offset % aes.BlockSize; skip > 0 {
+		discard := make([]byte, skip)
+		stream.XORKeyStream(discard, discard)
+	}
+
+	return stream
+}
+
+// xorAt encrypts or decrypts the data at the offset of the data file in place.
+func (c *dataCipher) xorAt(iv []byte, data []byte, offset int64) {
+	c.stream(iv, offset).XORKeyStream(data, data)
+}
+
+// reader returns the reader decrypting the data read from the offset of the data file.
+func (c *dataCipher) reader(iv []byte, r io.Reader, offset int64) io.Reader {
+	return &cipher.StreamReader{S: c.stream(iv, offset), R: r}
+}
+
+// writer returns the writer encrypting the data written to the offset of the data file.
+func (c *dataCipher) writer(iv []byte, w io.Writer, offset int64) io.Writer {
+	return &cipher.StreamWriter{S: c.stream(iv, offset), W: w}
+}
+
+// loadEncryptionKey loads the hex encoded key of the node from the key file,
+// or from the output of the key command when the key file is not configured.
+func loadEncryptionKey(opt *config.StorageEncryptionOption) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if opt.KeyFile != "" {
+		data, err = os.ReadFile(opt.KeyFile)
+	} else {
+		data, err = exec.Command("sh", "-c", opt.KeyCommand).Output()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load encryption key error: %w", err)
+	}
+
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("decode encryption key error: %w", err)
+	}
+
+	if len(key) != encryptionKeySize {
+		return nil, errors.New("encryption key must be 32 bytes")
+	}
+
+	return key, nil
+}
+
+// encrypted returns whether the data file of the task is encrypted.
+func (t *localTaskStore) encrypted() bool {
+	return t.cipher != nil && t.Encryption != nil
+}
+
+// decryptReader returns the reader decrypting the data read from the offset of the data file,
+// the reader is returned as it is when the data file is not encrypted.
+func (t *localTaskStore) decryptReader(r io.Reader, offset int64) io.Reader {
+	if !t.encrypted() {
+		return r
+	}
+
+	return t.cipher.reader(t.Encryption.IV, r, offset)
+}
+
+// encryptWriter returns the writer encrypting the data written to the offset of the data file,
+// the writer is returned as it is when the data file is not encrypted.
+func (t *localTaskStore) encryptWriter(w io.Writer, offset int64) io.Writer {
+	if !t.encrypted() {
+		return w
+	}
+
+	return t.cipher.writer(t.Encryption.IV, w, offset)
+}
+
+// xorAt encrypts or decrypts the data at the offset of the data file in place when the data file is encrypted.
+func (t *localTaskStore) xorAt(data []byte, offset int64) {
+	if t.encrypted() {
+		t.cipher.xorAt(t.Encryption.IV, data, offset)
+	}
+}
+
+// storeDecrypted copies the decrypted data in the range of the data file to the destination,
+// the encrypted data file can not be linked to the destination.
+func (t *localTaskStore) storeDecrypted(destination string, start, length int64) (err error) {
+	file, err := os.Open(t.DataFilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err = file.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+
+	dstFile, err := os.OpenFile(destination, os.O_CREATE|os.O_RDWR|os.O_TRUNC, defaultFileMode)
+	if err != nil {
+		t.Errorf("open tasks destination file error: %s", err)
+		return err
+	}
+	defer func() {
+		if cerr := dstFile.Close(); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	n, err := io.Copy(dstFile, t.decryptReader(io.LimitReader(file, length), start))
+	t.Debugf("copied decrypted tasks data %d bytes to %s", n, destination)
+	return err
+}
+
+// checkEncryption checks whether the data file with the encryption is readable by the storage manager.
+func (s *storageManager) checkEncryption(encryption *encryptionMetadata) error {
+	switch {
+	case s.cipher == nil && encryption != nil:
+		return errors.New("data file is encrypted but encryption is disabled")
+	case s.cipher != nil && encryption == nil:
+		return errors.New("data file is not encrypted but encryption is enabled")
+	case s.cipher != nil && encryption.KeyID != s.cipher.keyID:
+		return fmt.Errorf("data file is encrypted by key %s, not key %s", encryption.KeyID, s.cipher.keyID)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/net/http"
+)
+
+func Test_dataCipher(t *testing.T) {
+	assert := testifyassert.New(t)
+	c, err := newDataCipher(bytes.Repeat([]byte{1}, encryptionKeySize))
+	assert.Nil(err)
+
+	encryption, err := c.newEncryption()
+	assert.Nil(err)
+	assert.Equal(c.keyID, encryption.KeyID)
+
+	// The counter overflows the low 64 bits.
+	encryption.IV = bytes.Repeat([]byte{0xff}, len(encryption.IV))
+
+	plain := []byte(strings.Repeat("dragonfly", 10))
+	whole := make([]byte, len(plain))
+	cipher.NewCTR(c.block, encryption.IV).XORKeyStream(whole, plain)
+
+	for _, offset := range []int64{0, 1, 15, 16, 17, 33, 89} {
+		data := append([]byte{}, plain[offset:]...)
+		c.xorAt(encryption.IV, data, offset)
+		assert.Equal(whole[offset:], data, fmt.Sprintf("offset %d", offset))
+
+		decrypted, err := io.ReadAll(c.reader(encryption.IV, bytes.NewReader(whole[offset:]), offset))
+		assert.Nil(err)
+		assert.Equal(plain[offset:], decrypted, fmt.Sprintf("offset %d", offset))
+
+		var buf bytes.Buffer
+		_, err = c.writer(encryption.IV, &buf, offset).Write(plain[offset:])
+		assert.Nil(err)
+		assert.Equal(whole[offset:], buf.Bytes(), fmt.Sprintf("offset %d", offset))
+	}
+}
+
+func Test_loadEncryptionKey(t *testing.T) {
+	key := hex.EncodeToString(bytes.Repeat([]byte{1}, encryptionKeySize))
+	keyFile := filepath.Join(t.TempDir(), "storage.key")
+	testifyassert.Nil(t, os.WriteFile(keyFile, []byte(key+"\n"), defaultFileMode))
+
+	testCases := []struct {
+		name   string
+		opt    *config.StorageEncryptionOption
+		expect func(t *testing.T, key []byte, err error)
+	}{
+		{
+			name: "load key from file",
+			opt:  &config.StorageEncryptionOption{KeyFile: keyFile},
+			expect: func(t *testing.T, key []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+				assert.Equal(bytes.Repeat([]byte{1}, encryptionKeySize), key)
+			},
+		},
+		{
+			name: "load key from command",
+			opt:  &config.StorageEncryptionOption{KeyCommand: "echo " + key},
+			expect: func(t *testing.T, key []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+				assert.Equal(bytes.Repeat([]byte{1}, encryptionKeySize), key)
+			},
+		},
+		{
+			name: "key file does not exist",
+			opt:  &config.StorageEncryptionOption{KeyFile: keyFile + ".foo"},
+			expect: func(t *testing.T, key []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.ErrorIs(err, os.ErrNotExist)
+			},
+		},
+		{
+			name: "key is not hex encoded",
+			opt:  &config.StorageEncryptionOption{KeyCommand: "echo foo"},
+			expect: func(t *testing.T, key []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.ErrorContains(err, "decode encryption key error")
+			},
+		},
+		{
+			name: "key size is invalid",
+			opt:  &config.StorageEncryptionOption{KeyCommand: "echo 0102"},
+			expect: func(t *testing.T, key []byte, err error) {
+				assert := testifyassert.New(t)
+				assert.EqualError(err, "encryption key must be 32 bytes")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := loadEncryptionKey(tc.opt)
+			tc.expect(t, key, err)
+		})
+	}
+}
+
+func TestLocalTaskStore_Encryption(t *testing.T) {
+	assert := testifyassert.New(t)
+	dataDir := t.TempDir()
+	dataFilePath := filepath.Join(dataDir, taskData)
+	assert.Nil(os.WriteFile(dataFilePath, nil, defaultFileMode))
+
+	c, err := newDataCipher(bytes.Repeat([]byte{1}, encryptionKeySize))
+	assert.Nil(err)
+	encryption, err := c.newEncryption()
+	assert.Nil(err)
+
+	ts := &localTaskStore{
+		SugaredLoggerOnWith: logger.With("task", "task"),
+		persistentMetadata: persistentMetadata{
+			TaskID:        "task",
+			PeerID:        "peer",
+			DataFilePath:  dataFilePath,
+			ContentLength: 10,
+			TotalPieces:   2,
+			Pieces:        map[int32]PieceMetadata{},
+			Encryption:    encryption,
+		},
+		dataDir:          dataDir,
+		metadataFilePath: filepath.Join(dataDir, taskMetadata),
+		cipher:           c,
+	}
+
+	pieces := []PieceMetadata{
+		{Num: 0, Range: http.Range{Start: 0, Length: 3}},
+		{Num: 1, Range: http.Range{Start: 3, Length: 7}},
+	}
+	for _, piece := range pieces {
+		_, err := ts.WritePiece(context.Background(), &WritePieceRequest{
+			PeerTaskMetadata: PeerTaskMetadata{TaskID: "task", PeerID: "peer"},
+			PieceMetadata:    piece,
+			Reader:           strings.NewReader("aaabbbbbbb"[piece.Range.Start : piece.Range.Start+piece.Range.Length]),
+		})
+		assert.Nil(err)
+	}
+
+	// The data file is not plain on disk.
+	data, err := os.ReadFile(dataFilePath)
+	assert.Nil(err)
+	assert.Len(data, 10)
+	assert.NotEqual("aaabbbbbbb", string(data))
+
+	r, closer, err := ts.ReadPiece(context.Background(), &ReadPieceRequest{PieceMetadata: PieceMetadata{Num: 1}})
+	assert.Nil(err)
+	data, err = io.ReadAll(r)
+	assert.Nil(err)
+	assert.Nil(closer.Close())
+	assert.Equal("bbbbbbb", string(data))
+
+	rc, err := ts.ReadAllPieces(context.Background(), &ReadAllPiecesRequest{})
+	assert.Nil(err)
+	data, err = io.ReadAll(rc)
+	assert.Nil(err)
+	assert.Nil(rc.Close())
+	assert.Equal("aaabbbbbbb", string(data))
+
+	destination := filepath.Join(dataDir, "destination")
+	assert.Nil(ts.Store(context.Background(), &StoreRequest{
+		CommonTaskRequest: CommonTaskRequest{TaskID: "task", PeerID: "peer", Destination: destination},
+	}))
+	data, err = os.ReadFile(destination)
+	assert.Nil(err)
+	assert.Equal("aaabbbbbbb", string(data))
+}
@@ -17,6 +17,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -25,6 +26,7 @@ import (
 	"math"
 	"os"
 	"path"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
@@ -35,9 +37,11 @@ import (
 
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
 
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/internal/util"
 	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/io/uring"
 	"d7y.io/dragonfly/v2/pkg/net/http"
 	"d7y.io/dragonfly/v2/pkg/source"
 )
@@ -65,6 +69,27 @@ type localTaskStore struct {
 	content []byte
 
 	subtasks map[PeerTaskMetadata]*localSubTaskStore
+
+	// tieredCache caches the hot pieces, it is nil when the tiered storage is disabled
+	tieredCache *tieredCache
+
+	// ring batches the piece reads and writes with io_uring, it is nil when io_uring is disabled
+	ring *uring.Ring
+
+	// dedupIndex references the pieces by digest, it is nil when the dedup is disabled
+	dedupIndex *dedupIndex
+
+	// cipher encrypts the data file, it is nil when the encryption is disabled
+	cipher *dataCipher
+
+	// reservation reserves the disk space before preallocating the data file, it is nil when the preallocate is disabled
+	reservation *reservation
+
+	// persistInterval is the interval to persist the written pieces for resuming, 0 means never persist
+	persistInterval time.Duration
+	lastPersist     atomic.Int64
+	// resumable is set when the unfinished task is reloaded from disk and not resumed yet
+	resumable atomic.Bool
 }
 
 var _ TaskStorageDriver = (*localTaskStore)(nil)
@@ -135,11 +160,15 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 		}
 	}()
 
-	if _, err = file.Seek(req.Range.Start, io.SeekStart); err != nil {
-		return 0, err
-	}
+	if t.ring != nil {
+		n, err = t.writePieceWithRing(file, req)
+	} else {
+		if _, err = file.Seek(req.Range.Start, io.SeekStart); err != nil {
+			return 0, err
+		}
 
-	n, err = io.Copy(file, io.LimitReader(req.Reader, req.Range.Length))
+		n, err = io.Copy(t.encryptWriter(file, req.Range.Start), io.LimitReader(req.Reader, req.Range.Length))
+	}
 	if err != nil {
 		return n, err
 	}
@@ -172,15 +201,23 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 	if req.PieceMetadata.Md5 == "" {
 		t.Debugf("piece %d md5 not found in metadata, read from reader", req.PieceMetadata.Num)
 		if get, ok := req.Reader.(digest.Reader); ok {
-			req.PieceMetadata.Md5 = get.Encoded()
+			req.PieceMetadata.Md5 = digest.FormatPieceDigest(get.Algorithm(), get.Encoded())
 			t.Infof("read piece %d md5 from reader, value: %s", req.PieceMetadata.Num, req.PieceMetadata.Md5)
 		} else {
 			t.Warnf("piece %d reader is not a digest.Reader", req.PieceMetadata.Num)
 		}
 	}
 
+	if t.dedupIndex != nil && req.PieceMetadata.Md5 != "" {
+		t.dedupPiece(req.PieceMetadata)
+	}
+
 	t.Debugf("wrote %d bytes to file %s, piece %d, start %d, length: %d",
 		n, t.DataFilePath, req.Num, req.Range.Start, req.Range.Length)
+	if t.persistInterval > 0 {
+		defer t.persistPieces()
+	}
+
 	t.Lock()
 	defer t.Unlock()
 	// double check
@@ -193,6 +230,138 @@ func (t *localTaskStore) WritePiece(ctx context.Context, req *WritePieceRequest)
 	return n, nil
 }
 
+// writePieceWithRing reads the piece from the reader and writes it to the file with io_uring.
+func (t *localTaskStore) writePieceWithRing(file *os.File, req *WritePieceRequest) (int64, error) {
+	data := make([]byte, req.Range.Length)
+	n, err := io.ReadFull(req.Reader, data)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return int64(n), err
+	}
+
+	if n == 0 {
+		return 0, nil
+	}
+
+	t.xorAt(data[:n], req.Range.Start)
+	written, err := t.ring.WriteAt(file, data[:n], req.Range.Start)
+	return int64(written), err
+}
+
+// dedupPiece references the piece in the dedup index, and shares the extents of the identical piece
+// stored by another task, the piece is kept as it is when the file system does not support deduplication.
+func (t *localTaskStore) dedupPiece(piece PieceMetadata) {
+	loc := pieceLocation{
+		dataFilePath: t.DataFilePath,
+		start:        piece.Range.Start,
+		length:       piece.Range.Length,
+	}
+	src, ok := t.dedupIndex.Add(PeerTaskMetadata{TaskID: t.TaskID, PeerID: t.PeerID}, piece.Num, piece.Md5, loc)
+	if !ok {
+		return
+	}
+
+	n, err := dedupeRange(src, loc)
+	if err != nil {
+		t.Debugf("dedupe piece %d from %s error: %s", piece.Num, src.dataFilePath, err)
+		return
+	}
+
+	metrics.StorageDedupBytesCount.Add(float64(n))
+}
+
+// persistPieces saves the metadata with the written pieces at most once per persist interval,
+// so the unfinished task can be resumed from the written pieces after dfdaemon restarts.
+// The data file is synced before saving the metadata, so the persisted pieces are on disk.
+func (t *localTaskStore) persistPieces() {
+	now := time.Now().UnixNano()
+	last := t.lastPersist.Load()
+	if now-last < int64(t.persistInterval) || !t.lastPersist.CompareAndSwap(last, now) {
+		return
+	}
+
+	if err := t.syncDataFile(); err != nil {
+		t.Warnf("sync data file error: %s", err)
+		return
+	}
+
+	if err := t.saveMetadata(); err != nil {
+		t.Warnf("persist pieces error: %s", err)
+	}
+}
+
+// syncDataFile commits the written pieces of the data file to disk.
+func (t *localTaskStore) syncDataFile() error {
+	file, err := os.OpenFile(t.DataFilePath, os.O_RDWR, defaultFileMode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return file.Sync()
+}
+
+// resumablePieces returns the written pieces ordered by number, the pieces are verified
+// against the data file and the mismatched pieces are dropped to be downloaded again,
+// e.g. the pieces are not flushed to disk before dfdaemon crashes.
+func (t *localTaskStore) resumablePieces() []PieceMetadata {
+	t.RLock()
+	pieces := make([]PieceMetadata, 0, len(t.Pieces))
+	for _, piece := range t.Pieces {
+		pieces = append(pieces, piece)
+	}
+	t.RUnlock()
+
+	sort.Slice(pieces, func(i, j int) bool {
+		return pieces[i].Num < pieces[j].Num
+	})
+
+	verified := pieces[:0]
+	for _, piece := range pieces {
+		if err := t.verifyPiece(piece); err != nil {
+			t.Warnf("drop resumable piece %d: %s", piece.Num, err)
+			t.Lock()
+			delete(t.Pieces, piece.Num)
+			t.Unlock()
+			continue
+		}
+
+		verified = append(verified, piece)
+	}
+
+	return verified
+}
+
+// verifyPiece reads the piece from the data file and verifies its digest.
+func (t *localTaskStore) verifyPiece(piece PieceMetadata) error {
+	if piece.Md5 == "" {
+		return errors.New("piece digest not found")
+	}
+
+	d, err := digest.ParsePieceDigest(piece.Md5)
+	if err != nil {
+		return err
+	}
+
+	data, err := t.readPieceData(&ReadPieceRequest{
+		PeerTaskMetadata: PeerTaskMetadata{
+			TaskID: t.TaskID,
+			PeerID: t.PeerID,
+		},
+		PieceMetadata: piece,
+	})
+	if err != nil {
+		return err
+	}
+
+	reader, err := digest.NewReader(d.Algorithm, bytes.NewReader(data), digest.WithEncoded(d.Encoded))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(io.Discard, reader)
+	return err
+}
+
 func (t *localTaskStore) genMetadata(n int64, req *WritePieceRequest) {
 	if req.GenMetadata == nil {
 		return
@@ -221,6 +390,10 @@ func (t *localTaskStore) UpdateTask(ctx context.Context, req *UpdateTaskRequest)
 	t.Lock()
 	defer t.Unlock()
 	if req.ContentLength > t.persistentMetadata.ContentLength {
+		if err := t.preallocate(req.ContentLength); err != nil {
+			return err
+		}
+
 		t.ContentLength = req.ContentLength
 		t.Debugf("update content length: %d", t.ContentLength)
 		// update empty file TotalPieces
@@ -241,6 +414,15 @@ func (t *localTaskStore) UpdateTask(ctx context.Context, req *UpdateTaskRequest)
 		t.Header = req.Header
 		t.Debugf("update header: %#v", t.Header)
 	}
+	if len(req.TaskMeta) > 0 {
+		if t.TaskMeta == nil {
+			t.TaskMeta = map[string]string{}
+		}
+		for k, v := range req.TaskMeta {
+			t.TaskMeta[k] = v
+		}
+		t.Debugf("update task meta: %#v", t.TaskMeta)
+	}
 	return nil
 }
 
@@ -287,10 +469,6 @@ func (t *localTaskStore) ReadPiece(ctx context.Context, req *ReadPieceRequest) (
 	}
 
 	t.touch()
-	file, err := os.Open(t.DataFilePath)
-	if err != nil {
-		return nil, nil, err
-	}
 
 	// If req.Num is equal to -1, range has a fixed value.
 	if req.Num != -1 {
@@ -300,10 +478,27 @@ func (t *localTaskStore) ReadPiece(ctx context.Context, req *ReadPieceRequest) (
 			req.Range = piece.Range
 		} else {
 			t.RUnlock()
-			file.Close()
 			t.Errorf("invalid piece num: %d", req.Num)
 			return nil, nil, ErrPieceNotFound
 		}
+
+		if t.tieredCache != nil {
+			return t.readTieredPiece(req)
+		}
+
+		if t.ring != nil {
+			data, err := t.readPieceData(req)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return bytes.NewReader(data), io.NopCloser(nil), nil
+		}
+	}
+
+	file, err := os.Open(t.DataFilePath)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if _, err = file.Seek(req.Range.Start, io.SeekStart); err != nil {
@@ -312,7 +507,47 @@ func (t *localTaskStore) ReadPiece(ctx context.Context, req *ReadPieceRequest) (
 		return nil, nil, err
 	}
 	// who call ReadPiece, who close the io.ReadCloser
-	return io.LimitReader(file, req.Range.Length), file, nil
+	return t.decryptReader(io.LimitReader(file, req.Range.Length), req.Range.Start), file, nil
+}
+
+// readTieredPiece reads the piece from the tiered cache, and caches the piece read from the data file.
+func (t *localTaskStore) readTieredPiece(req *ReadPieceRequest) (io.Reader, io.Closer, error) {
+	key := pieceKey{taskID: t.TaskID, peerID: t.PeerID, num: req.Num}
+	if data, ok := t.tieredCache.Get(key); ok {
+		return bytes.NewReader(data), io.NopCloser(nil), nil
+	}
+
+	data, err := t.readPieceData(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics.StorageTierHitCount.WithLabelValues(metrics.StorageTierHDD).Inc()
+
+	t.tieredCache.Put(key, data)
+	return bytes.NewReader(data), io.NopCloser(nil), nil
+}
+
+// readPieceData reads the piece from the data file, with io_uring when it is enabled.
+func (t *localTaskStore) readPieceData(req *ReadPieceRequest) ([]byte, error) {
+	file, err := os.Open(t.DataFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data := make([]byte, req.Range.Length)
+	if t.ring != nil {
+		_, err = t.ring.ReadAt(file, data, req.Range.Start)
+	} else {
+		_, err = file.ReadAt(data, req.Range.Start)
+	}
+	if err != nil {
+		t.Errorf("read piece %d failed: %v", req.Num, err)
+		return nil, err
+	}
+
+	t.xorAt(data, req.Range.Start)
+	return data, nil
 }
 
 func (t *localTaskStore) ReadAllPieces(ctx context.Context, req *ReadAllPiecesRequest) (io.ReadCloser, error) {
@@ -333,7 +568,7 @@ func (t *localTaskStore) ReadAllPieces(ctx context.Context, req *ReadAllPiecesRe
 		// by jim: for some corner case, avoid the io.Copy call superfluous sendfile syscall
 		// then increase network latency
 		return &limitedReadFile{
-			reader: io.LimitReader(file, t.ContentLength),
+			reader: t.decryptReader(io.LimitReader(file, t.ContentLength), 0),
 			closer: file,
 		}, nil
 	}
@@ -345,7 +580,7 @@ func (t *localTaskStore) ReadAllPieces(ctx context.Context, req *ReadAllPiecesRe
 	}
 
 	return &limitedReadFile{
-		reader: io.LimitReader(file, req.Range.Length),
+		reader: t.decryptReader(io.LimitReader(file, req.Range.Length), req.Range.Start),
 		closer: file,
 	}, nil
 }
@@ -354,6 +589,7 @@ func (t *localTaskStore) Store(ctx context.Context, req *StoreRequest) (err erro
 	// Store is called in callback.Done, mark local task store done, for fast search
 	t.Done = true
 	t.touch()
+	t.releaseReservation()
 	if req.TotalPieces > 0 && t.TotalPieces == -1 {
 		t.Lock()
 		t.TotalPieces = req.TotalPieces
@@ -375,6 +611,10 @@ func (t *localTaskStore) Store(ctx context.Context, req *StoreRequest) (err erro
 	globalFSWriteLock.LockKey(req.Destination)
 	defer globalFSWriteLock.UnlockKey(req.Destination)
 
+	if t.encrypted() {
+		return t.storeDecrypted(req.Destination, 0, t.ContentLength)
+	}
+
 	if req.OriginalOffset {
 		return hardlink(t.SugaredLoggerOnWith, req.Destination, t.DataFilePath)
 	}
@@ -501,18 +741,38 @@ func (t *localTaskStore) GetExtendAttribute(ctx context.Context, req *PeerTaskMe
 	return &commonv1.ExtendAttribute{Header: hdr}, nil
 }
 
+func (t *localTaskStore) GetTaskMeta(ctx context.Context, req *PeerTaskMetadata) (map[string]string, error) {
+	if t.invalid.Load() {
+		t.Errorf("invalid digest, refuse to get task meta")
+		return nil, ErrInvalidDigest
+	}
+	t.RLock()
+	defer t.RUnlock()
+	taskMeta := make(map[string]string, len(t.TaskMeta))
+	for k, v := range t.TaskMeta {
+		taskMeta[k] = v
+	}
+	return taskMeta, nil
+}
+
 func (t *localTaskStore) CanReclaim() bool {
 	// task is invalid
 	if t.invalid.Load() {
 		return true
 	}
 
+	now := time.Now()
+	// task ttl reached
+	if expiresAt := t.expiresAt(); !expiresAt.IsZero() && expiresAt.Before(now) {
+		t.Debugf("task ttl reached")
+		return true
+	}
+
 	// don't gc if expire time is 0
 	if t.expireTime == 0 {
 		return false
 	}
 
-	now := time.Now()
 	// task soft cache time reached
 	access := time.Unix(0, t.lastAccess.Load())
 	reclaim := access.Add(t.expireTime).Before(now)
@@ -536,6 +796,24 @@ func (t *localTaskStore) CanReclaim() bool {
 	return false
 }
 
+// expiresAt returns the hard expire time of the task in task meta, zero time is never expired.
+func (t *localTaskStore) expiresAt() time.Time {
+	t.RLock()
+	expiresAt, ok := t.TaskMeta[TaskMetaExpiresAt]
+	t.RUnlock()
+	if !ok {
+		return time.Time{}
+	}
+
+	at, err := time.Parse(time.RFC3339, expiresAt)
+	if err != nil {
+		t.Errorf("parse task expire time %s error: %s", expiresAt, err)
+		return time.Time{}
+	}
+
+	return at
+}
+
 // MarkReclaim will try to invoke gcCallback (normal leave peer task)
 func (t *localTaskStore) MarkReclaim() {
 	if t.reclaimMarked.Load() {
@@ -567,6 +845,16 @@ func (t *localTaskStore) MarkReclaim() {
 
 func (t *localTaskStore) Reclaim() error {
 	t.Infof("start gc task data")
+	if t.tieredCache != nil {
+		t.tieredCache.DeletePeerTask(t.TaskID, t.PeerID)
+	}
+
+	if t.dedupIndex != nil {
+		t.dedupIndex.Remove(PeerTaskMetadata{TaskID: t.TaskID, PeerID: t.PeerID})
+	}
+
+	t.releaseReservation()
+
 	err := t.reclaimData()
 	if err != nil && !os.IsNotExist(err) {
 		return err
@@ -639,6 +927,11 @@ func (t *localTaskStore) reclaimMeta() error {
 		t.Warnf("remove task meta data %q error: %s", t.metadataFilePath, err)
 		return err
 	}
+
+	// remove the temporary metadata left by the interrupted save
+	if err := os.Remove(t.metadataFilePath + metadataTmpSuffix); err != nil && !os.IsNotExist(err) {
+		t.Warnf("remove task temporary meta data error: %s", err)
+	}
 	t.Infof("purged task mata data: %s", t.metadataFilePath)
 	return nil
 }
@@ -650,20 +943,15 @@ func (t *localTaskStore) saveMetadata() (err error) {
 	if err != nil {
 		return err
 	}
-	metadata, err := os.OpenFile(t.metadataFilePath, os.O_CREATE|os.O_RDWR, defaultFileMode)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if cerr := metadata.Close(); cerr != nil {
-			err = errors.Join(err, cerr)
-		}
-	}()
-	_, err = metadata.Write(data)
-	if err != nil {
+
+	// write to a temporary file and rename it, the metadata is never left partially written
+	tmpFilePath := t.metadataFilePath + metadataTmpSuffix
+	if err = os.WriteFile(tmpFilePath, data, defaultFileMode); err != nil {
 		t.Errorf("save metadata error: %s", err)
+		return err
 	}
-	return err
+
+	return os.Rename(tmpFilePath, t.metadataFilePath)
 }
 
 func (t *localTaskStore) partialCompleted(rg *http.Range) bool {
@@ -28,6 +28,7 @@ import (
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/internal/util"
 	"d7y.io/dragonfly/v2/pkg/digest"
 	"d7y.io/dragonfly/v2/pkg/net/http"
 )
@@ -42,6 +43,10 @@ type localSubTaskStore struct {
 	// when digest not match, invalid will be set
 	invalid atomic.Bool
 
+	// lazyDigest indicates the digests of pieces are computed when the pieces are got,
+	// it is set for the range served by the completed parent task.
+	lazyDigest bool
+
 	Range *http.Range
 }
 
@@ -83,7 +88,7 @@ func (t *localSubTaskStore) WritePiece(ctx context.Context, req *WritePieceReque
 		return 0, err
 	}
 
-	n, err = io.Copy(file, io.LimitReader(req.Reader, req.Range.Length))
+	n, err = io.Copy(t.parent.encryptWriter(file, t.Range.Start+req.Range.Start), io.LimitReader(req.Reader, req.Range.Length))
 	if err != nil {
 		return 0, err
 	}
@@ -116,7 +121,7 @@ func (t *localSubTaskStore) WritePiece(ctx context.Context, req *WritePieceReque
 	if req.PieceMetadata.Md5 == "" {
 		t.Debugf("piece %d md5 not found in metadata, read from reader", req.PieceMetadata.Num)
 		if get, ok := req.Reader.(digest.Reader); ok {
-			req.PieceMetadata.Md5 = get.Encoded()
+			req.PieceMetadata.Md5 = digest.FormatPieceDigest(get.Algorithm(), get.Encoded())
 			t.Infof("read piece %d md5 from reader, value: %s", req.PieceMetadata.Num, req.PieceMetadata.Md5)
 		} else {
 			t.Warnf("piece %d reader is not a digest.Reader", req.PieceMetadata.Num)
@@ -170,7 +175,7 @@ func (t *localSubTaskStore) ReadPiece(ctx context.Context, req *ReadPieceRequest
 		return nil, nil, err
 	}
 	// who call ReadPiece, who close the io.ReadCloser
-	return io.LimitReader(file, req.Range.Length), file, nil
+	return t.parent.decryptReader(io.LimitReader(file, req.Range.Length), t.Range.Start+req.Range.Start), file, nil
 }
 
 func (t *localSubTaskStore) ReadAllPieces(ctx context.Context, req *ReadAllPiecesRequest) (io.ReadCloser, error) {
@@ -205,7 +210,7 @@ func (t *localSubTaskStore) ReadAllPieces(ctx context.Context, req *ReadAllPiece
 	}
 
 	return &limitedReadFile{
-		reader: io.LimitReader(file, length),
+		reader: t.parent.decryptReader(io.LimitReader(file, length), start),
 		closer: file,
 	}, nil
 }
@@ -216,6 +221,11 @@ func (t *localSubTaskStore) GetPieces(ctx context.Context, req *commonv1.PieceTa
 		return nil, ErrInvalidDigest
 	}
 
+	if err := t.computePieceDigests(int32(req.StartNum), int32(req.Limit)); err != nil {
+		t.Errorf("compute piece digests error: %s", err)
+		return nil, err
+	}
+
 	t.RLock()
 	defer t.RUnlock()
 	t.parent.touch()
@@ -277,6 +287,15 @@ func (t *localSubTaskStore) UpdateTask(ctx context.Context, req *UpdateTaskReque
 		t.PieceMd5Sign = req.PieceMd5Sign
 		t.Debugf("update piece md5 sign: %s", t.PieceMd5Sign)
 	}
+	if len(req.TaskMeta) > 0 {
+		if t.TaskMeta == nil {
+			t.TaskMeta = map[string]string{}
+		}
+		for k, v := range req.TaskMeta {
+			t.TaskMeta[k] = v
+		}
+		t.Debugf("update task meta: %#v", t.TaskMeta)
+	}
 	return nil
 }
 
@@ -297,6 +316,14 @@ func (t *localSubTaskStore) Store(ctx context.Context, req *StoreRequest) (err e
 	globalFSWriteLock.LockKey(req.Destination)
 	defer globalFSWriteLock.UnlockKey(req.Destination)
 
+	if t.parent.encrypted() {
+		if req.OriginalOffset {
+			return t.parent.storeDecrypted(req.Destination, 0, t.parent.ContentLength)
+		}
+
+		return t.parent.storeDecrypted(req.Destination, t.Range.Start, t.ContentLength)
+	}
+
 	if req.OriginalOffset {
 		return hardlink(t.SugaredLoggerOnWith, req.Destination, t.parent.DataFilePath)
 	}
@@ -399,6 +426,100 @@ func (t *localSubTaskStore) genMetadata(n int64, req *WritePieceRequest) {
 	t.Infof("generated digest: %s, total pieces: %d, content length: %d", digest, t.TotalPieces, t.ContentLength)
 }
 
+// initPieces slices the range of the completed parent task into pieces,
+// the subtask is served as a completed task without downloading.
+// initPieces initializes the pieces of the range served by the completed parent task,
+// the digests of pieces are computed when the pieces are got, so registering the range is cheap.
+func (t *localSubTaskStore) initPieces() error {
+	if _, err := os.Stat(t.parent.DataFilePath); err != nil {
+		return err
+	}
+
+	pieceSize := util.ComputePieceSize(t.ContentLength)
+	totalPieces := util.ComputePieceCount(t.ContentLength, pieceSize)
+
+	t.Lock()
+	defer t.Unlock()
+	for i := int32(0); i < totalPieces; i++ {
+		start := int64(i) * int64(pieceSize)
+		length := int64(pieceSize)
+		if start+length > t.ContentLength {
+			length = t.ContentLength - start
+		}
+
+		t.Pieces[i] = PieceMetadata{
+			Num:    i,
+			Offset: uint64(start),
+			Range: http.Range{
+				Start:  start,
+				Length: length,
+			},
+			Style: commonv1.PieceStyle_PLAIN,
+		}
+	}
+
+	t.TotalPieces = totalPieces
+	t.Done = true
+	t.lazyDigest = true
+	return nil
+}
+
+// computePieceDigests computes the digests of the pieces in [start, start+limit) which are not computed yet,
+// the data file is read without holding the lock. The piece md5 sign is set when all of the digests are computed.
+func (t *localSubTaskStore) computePieceDigests(start, limit int32) error {
+	t.RLock()
+	var pieces []PieceMetadata
+	if t.lazyDigest {
+		for i := start; i < start+limit && i < t.TotalPieces; i++ {
+			if piece, ok := t.Pieces[i]; ok && piece.Md5 == "" {
+				pieces = append(pieces, piece)
+			}
+		}
+	}
+	t.RUnlock()
+
+	if len(pieces) == 0 {
+		return nil
+	}
+
+	file, err := os.Open(t.parent.DataFilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for i := range pieces {
+		offset := t.Range.Start + pieces[i].Range.Start
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		pieces[i].Md5 = digest.MD5FromReader(t.parent.decryptReader(io.LimitReader(file, pieces[i].Range.Length), offset))
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	for _, piece := range pieces {
+		t.Pieces[piece.Num] = piece
+	}
+
+	if t.PieceMd5Sign != "" {
+		return nil
+	}
+
+	var pieceDigests []string
+	for i := int32(0); i < t.TotalPieces; i++ {
+		if t.Pieces[i].Md5 == "" {
+			return nil
+		}
+
+		pieceDigests = append(pieceDigests, t.Pieces[i].Md5)
+	}
+
+	t.PieceMd5Sign = digest.SHA256FromStrings(pieceDigests...)
+	return nil
+}
+
 func (t *localSubTaskStore) CanReclaim() bool {
 	if t.parent.Done || t.invalid.Load() {
 		return true
@@ -444,3 +565,17 @@ func (t *localSubTaskStore) GetExtendAttribute(ctx context.Context, req *PeerTas
 	}
 	return &commonv1.ExtendAttribute{Header: hdr}, nil
 }
+
+func (t *localSubTaskStore) GetTaskMeta(ctx context.Context, req *PeerTaskMetadata) (map[string]string, error) {
+	if t.invalid.Load() {
+		t.Errorf("invalid digest, refuse to get task meta")
+		return nil, ErrInvalidDigest
+	}
+	t.RLock()
+	defer t.RUnlock()
+	taskMeta := make(map[string]string, len(t.TaskMeta))
+	for k, v := range t.TaskMeta {
+		taskMeta[k] = v
+	}
+	return taskMeta, nil
+}
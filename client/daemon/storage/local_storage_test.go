@@ -56,6 +56,8 @@ func TestLocalTaskStore_PutAndGetPiece(t *testing.T) {
 	testCases := []struct {
 		name     string
 		strategy config.StoreStrategy
+		ioUring  bool
+		dedup    bool
 		create   func(s *storageManager, taskID, peerID string) (TaskStorageDriver, error)
 	}{
 		{
@@ -88,6 +90,38 @@ func TestLocalTaskStore_PutAndGetPiece(t *testing.T) {
 					})
 			},
 		},
+		{
+			name:     "io_uring",
+			strategy: config.SimpleLocalTaskStoreStrategy,
+			ioUring:  true,
+			create: func(s *storageManager, taskID, peerID string) (TaskStorageDriver, error) {
+				return s.CreateTask(
+					&RegisterTaskRequest{
+						PeerTaskMetadata: PeerTaskMetadata{
+							PeerID: peerID,
+							TaskID: taskID,
+						},
+						DesiredLocation: dst,
+						ContentLength:   int64(len(testBytes)),
+					})
+			},
+		},
+		{
+			name:     "dedup",
+			strategy: config.SimpleLocalTaskStoreStrategy,
+			dedup:    true,
+			create: func(s *storageManager, taskID, peerID string) (TaskStorageDriver, error) {
+				return s.CreateTask(
+					&RegisterTaskRequest{
+						PeerTaskMetadata: PeerTaskMetadata{
+							PeerID: peerID,
+							TaskID: taskID,
+						},
+						DesiredLocation: dst,
+						ContentLength:   int64(len(testBytes)),
+					})
+			},
+		},
 		{
 			name:     "subtask",
 			strategy: config.AdvanceLocalTaskStoreStrategy,
@@ -141,6 +175,13 @@ func TestLocalTaskStore_PutAndGetPiece(t *testing.T) {
 					TaskExpireTime: clientutil.Duration{
 						Duration: time.Minute,
 					},
+					IOUring: config.IOUringOption{
+						Enable:  tc.ioUring,
+						Entries: 8,
+					},
+					Dedup: config.DedupOption{
+						Enable: tc.dedup,
+					},
 				}, func(request CommonTaskRequest) {
 				}, defaultDirectoryMode)
 			assert.Nil(err)
@@ -361,6 +402,58 @@ func TestLocalTaskStore_StoreTaskData_Simple(t *testing.T) {
 	assert.Equal(testData, bs, "data must match")
 }
 
+func TestLocalSubTaskStore_initPieces(t *testing.T) {
+	assert := testifyassert.New(t)
+	src := path.Join(test.DataDir, taskData+".range")
+	testData := []byte("0123456789")
+	err := os.WriteFile(src, testData, defaultFileMode)
+	assert.Nil(err, "prepare test data")
+	defer os.Remove(src)
+
+	ts := &localTaskStore{
+		SugaredLoggerOnWith: logger.With("test", "localTaskStore"),
+		persistentMetadata: persistentMetadata{
+			TaskID:        "test",
+			ContentLength: int64(len(testData)),
+			DataFilePath:  src,
+			Done:          true,
+		},
+		subtasks: map[PeerTaskMetadata]*localSubTaskStore{},
+	}
+	subtask := ts.SubTask(&RegisterSubTaskRequest{
+		Parent:  PeerTaskMetadata{TaskID: "test", PeerID: "peer"},
+		SubTask: PeerTaskMetadata{TaskID: "test-range", PeerID: "peer"},
+		Range:   &http.Range{Start: 2, Length: 5},
+	})
+	assert.Nil(subtask.initPieces())
+	assert.True(subtask.Done)
+	assert.Equal(int32(1), subtask.TotalPieces)
+	assert.Empty(subtask.Pieces[0].Md5)
+	assert.Empty(subtask.PieceMd5Sign)
+
+	// The digests of pieces are computed when the pieces are got.
+	piecePacket, err := subtask.GetPieces(context.Background(), &commonv1.PieceTaskRequest{
+		TaskId: "test-range",
+		DstPid: "peer",
+		Limit:  16,
+	})
+	assert.Nil(err)
+	assert.Len(piecePacket.PieceInfos, 1)
+	assert.Equal(calcPieceMd5(testData[2:7]), piecePacket.PieceInfos[0].PieceMd5)
+	assert.Equal(digest.SHA256FromStrings(calcPieceMd5(testData[2:7])), piecePacket.PieceMd5Sign)
+	assert.Equal(calcPieceMd5(testData[2:7]), subtask.Pieces[0].Md5)
+
+	r, c, err := subtask.ReadPiece(context.Background(), &ReadPieceRequest{
+		PeerTaskMetadata: PeerTaskMetadata{TaskID: "test-range", PeerID: "peer"},
+		PieceMetadata:    PieceMetadata{Num: 0},
+	})
+	assert.Nil(err)
+	defer c.Close()
+	data, err := io.ReadAll(r)
+	assert.Nil(err)
+	assert.Equal(testData[2:7], data)
+}
+
 func calcFileMd5(filePath string, rg *http.Range) (string, error) {
 	var md5String string
 	file, err := os.Open(filePath)
@@ -573,6 +666,24 @@ func TestLocalTaskStore_CanReclaim(t *testing.T) {
 			},
 			expect: true,
 		},
+		{
+			name: "ttl reached task",
+			lts: &localTaskStore{
+				persistentMetadata: persistentMetadata{
+					TaskMeta: map[string]string{TaskMetaExpiresAt: time.Now().Add(-time.Minute).Format(time.RFC3339)},
+				},
+			},
+			expect: true,
+		},
+		{
+			name: "ttl not reached task",
+			lts: &localTaskStore{
+				persistentMetadata: persistentMetadata{
+					TaskMeta: map[string]string{TaskMetaExpiresAt: time.Now().Add(time.Hour).Format(time.RFC3339)},
+				},
+			},
+			expect: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -582,3 +693,124 @@ func TestLocalTaskStore_CanReclaim(t *testing.T) {
 		})
 	}
 }
+
+func TestStorageManager_FindResumableTask(t *testing.T) {
+	var (
+		taskID    = "task-resumable"
+		peerID    = "peer-resumable"
+		pieceSize = 16
+		data      = bytes.Repeat([]byte{'a'}, 3*pieceSize)
+	)
+
+	testCases := []struct {
+		name    string
+		resume  config.ResumeOption
+		done    bool
+		corrupt bool
+		expect  func(t *testing.T, sm Manager)
+	}{
+		{
+			name:   "resume the unfinished task with the persisted pieces",
+			resume: config.ResumeOption{Enable: true, PersistInterval: clientutil.Duration{Duration: time.Hour}},
+			expect: func(t *testing.T, sm Manager) {
+				assert := testifyassert.New(t)
+				task := sm.FindResumableTask(taskID)
+				assert.NotNil(task)
+				assert.Equal(peerID, task.PeerID)
+				assert.Equal(int64(len(data)), task.ContentLength)
+				// only the first piece is persisted in the persist interval
+				assert.Len(task.Pieces, 1)
+				assert.Equal(int32(0), task.Pieces[0].Num)
+				assert.Nil(sm.FindResumableTask(taskID))
+			},
+		},
+		{
+			name:    "drop the persisted pieces mismatched with the data file",
+			resume:  config.ResumeOption{Enable: true, PersistInterval: clientutil.Duration{Duration: time.Hour}},
+			corrupt: true,
+			expect: func(t *testing.T, sm Manager) {
+				assert := testifyassert.New(t)
+				task := sm.FindResumableTask(taskID)
+				assert.NotNil(task)
+				assert.Len(task.Pieces, 0)
+			},
+		},
+		{
+			name:   "finished task is not resumable",
+			resume: config.ResumeOption{Enable: true, PersistInterval: clientutil.Duration{Duration: time.Hour}},
+			done:   true,
+			expect: func(t *testing.T, sm Manager) {
+				assert := testifyassert.New(t)
+				assert.Nil(sm.FindResumableTask(taskID))
+				assert.NotNil(sm.FindCompletedTask(taskID))
+			},
+		},
+		{
+			name: "resume disabled",
+			expect: func(t *testing.T, sm Manager) {
+				assert := testifyassert.New(t)
+				assert.Nil(sm.FindResumableTask(taskID))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			opt := &config.StorageOption{
+				DataPath:       t.TempDir(),
+				TaskExpireTime: clientutil.Duration{Duration: time.Minute},
+				Resume:         tc.resume,
+			}
+			sm, err := NewStorageManager(config.SimpleLocalTaskStoreStrategy, opt, func(CommonTaskRequest) {}, defaultDirectoryMode)
+			assert.Nil(err)
+
+			meta := PeerTaskMetadata{TaskID: taskID, PeerID: peerID}
+			ts, err := sm.RegisterTask(context.Background(), &RegisterTaskRequest{
+				PeerTaskMetadata: meta,
+				ContentLength:    int64(len(data)),
+				TotalPieces:      3,
+			})
+			assert.Nil(err)
+
+			for i := 0; i < 3; i++ {
+				_, err = ts.WritePiece(context.Background(), &WritePieceRequest{
+					PeerTaskMetadata: meta,
+					PieceMetadata: PieceMetadata{
+						Num:    int32(i),
+						Md5:    calcPieceMd5(data[i*pieceSize : (i+1)*pieceSize]),
+						Offset: uint64(i * pieceSize),
+						Range: http.Range{
+							Start:  int64(i * pieceSize),
+							Length: int64(pieceSize),
+						},
+						Style: commonv1.PieceStyle_PLAIN,
+					},
+					Reader: bytes.NewBuffer(data[i*pieceSize : (i+1)*pieceSize]),
+				})
+				assert.Nil(err)
+			}
+
+			if tc.corrupt {
+				file, err := os.OpenFile(ts.(*localTaskStore).DataFilePath, os.O_RDWR, defaultFileMode)
+				assert.Nil(err)
+				_, err = file.WriteAt(bytes.Repeat([]byte{'b'}, pieceSize), 0)
+				assert.Nil(err)
+				assert.Nil(file.Close())
+			}
+
+			if tc.done {
+				assert.Nil(ts.Store(context.Background(), &StoreRequest{
+					CommonTaskRequest: CommonTaskRequest{PeerID: peerID, TaskID: taskID},
+					MetadataOnly:      true,
+					TotalPieces:       3,
+				}))
+			}
+
+			// reload the tasks from disk like dfdaemon restarts
+			sm, err = NewStorageManager(config.SimpleLocalTaskStoreStrategy, opt, func(CommonTaskRequest) {}, defaultDirectoryMode)
+			assert.Nil(err)
+			tc.expect(t, sm)
+		})
+	}
+}
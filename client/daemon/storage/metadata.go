@@ -25,6 +25,12 @@ import (
 	"d7y.io/dragonfly/v2/pkg/source"
 )
 
+const (
+	// TaskMetaExpiresAt is the key of the hard expire time of the task in task meta, formatted in RFC3339,
+	// the task is reclaimed after the time is reached.
+	TaskMetaExpiresAt = "expiresAt"
+)
+
 type persistentMetadata struct {
 	StoreStrategy string                  `json:"storeStrategy"`
 	TaskID        string                  `json:"taskID"`
@@ -37,6 +43,8 @@ type persistentMetadata struct {
 	DataFilePath  string                  `json:"dataFilePath"`
 	Done          bool                    `json:"done"`
 	Header        *source.Header          `json:"header"`
+	// Encryption is the encryption of the data file, it is nil when the data file is not encrypted.
+	Encryption *encryptionMetadata `json:"encryption,omitempty"`
 }
 
 type PeerTaskMetadata struct {
@@ -103,12 +111,21 @@ type RegisterSubTaskRequest struct {
 	Range   *http.Range
 }
 
+type RegisterRangeTaskRequest struct {
+	Parent  PeerTaskMetadata
+	SubTask PeerTaskMetadata
+	// Range is the range of the url meta, it is parsed with the content length of the parent task
+	Range string
+}
+
 type UpdateTaskRequest struct {
 	PeerTaskMetadata
 	ContentLength int64
 	TotalPieces   int32
 	PieceMd5Sign  string
 	Header        *source.Header
+	// TaskMeta is merged into the task meta of the task
+	TaskMeta map[string]string
 }
 
 type ReusePeerTask struct {
@@ -117,5 +134,9 @@ type ReusePeerTask struct {
 	TotalPieces   int32
 	PieceMd5Sign  string
 	Header        *source.Header
-	Storage       TaskStorageDriver
+	// TaskMeta is a copy of the task meta, only set for the listed task
+	TaskMeta map[string]string
+	Storage  TaskStorageDriver
+	// Pieces are the written pieces ordered by number, only set for the resumable task
+	Pieces []PieceMetadata
 }
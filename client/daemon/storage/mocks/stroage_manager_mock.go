@@ -69,6 +69,21 @@ func (mr *MockTaskStorageDriverMockRecorder) GetPieces(ctx, req interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPieces", reflect.TypeOf((*MockTaskStorageDriver)(nil).GetPieces), ctx, req)
 }
 
+// GetTaskMeta mocks base method.
+func (m *MockTaskStorageDriver) GetTaskMeta(ctx context.Context, req *storage.PeerTaskMetadata) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskMeta", ctx, req)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskMeta indicates an expected call of GetTaskMeta.
+func (mr *MockTaskStorageDriverMockRecorder) GetTaskMeta(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskMeta", reflect.TypeOf((*MockTaskStorageDriver)(nil).GetTaskMeta), ctx, req)
+}
+
 // GetTotalPieces mocks base method.
 func (m *MockTaskStorageDriver) GetTotalPieces(ctx context.Context, req *storage.PeerTaskMetadata) (int32, error) {
 	m.ctrl.T.Helper()
@@ -341,6 +356,20 @@ func (mr *MockManagerMockRecorder) FindPartialCompletedTask(taskID, rg interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPartialCompletedTask", reflect.TypeOf((*MockManager)(nil).FindPartialCompletedTask), taskID, rg)
 }
 
+// FindResumableTask mocks base method.
+func (m *MockManager) FindResumableTask(taskID string) *storage.ReusePeerTask {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindResumableTask", taskID)
+	ret0, _ := ret[0].(*storage.ReusePeerTask)
+	return ret0
+}
+
+// FindResumableTask indicates an expected call of FindResumableTask.
+func (mr *MockManagerMockRecorder) FindResumableTask(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResumableTask", reflect.TypeOf((*MockManager)(nil).FindResumableTask), taskID)
+}
+
 // GetExtendAttribute mocks base method.
 func (m *MockManager) GetExtendAttribute(ctx context.Context, req *storage.PeerTaskMetadata) (*common.ExtendAttribute, error) {
 	m.ctrl.T.Helper()
@@ -371,6 +400,21 @@ func (mr *MockManagerMockRecorder) GetPieces(ctx, req interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPieces", reflect.TypeOf((*MockManager)(nil).GetPieces), ctx, req)
 }
 
+// GetTaskMeta mocks base method.
+func (m *MockManager) GetTaskMeta(ctx context.Context, req *storage.PeerTaskMetadata) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskMeta", ctx, req)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskMeta indicates an expected call of GetTaskMeta.
+func (mr *MockManagerMockRecorder) GetTaskMeta(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskMeta", reflect.TypeOf((*MockManager)(nil).GetTaskMeta), ctx, req)
+}
+
 // GetTotalPieces mocks base method.
 func (m *MockManager) GetTotalPieces(ctx context.Context, req *storage.PeerTaskMetadata) (int32, error) {
 	m.ctrl.T.Helper()
@@ -413,6 +457,46 @@ func (mr *MockManagerMockRecorder) Keep() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keep", reflect.TypeOf((*MockManager)(nil).Keep))
 }
 
+// ListCompletedTasks mocks base method.
+func (m *MockManager) ListCompletedTasks() []*storage.ReusePeerTask {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCompletedTasks")
+	ret0, _ := ret[0].([]*storage.ReusePeerTask)
+	return ret0
+}
+
+// ListCompletedTasks indicates an expected call of ListCompletedTasks.
+func (mr *MockManagerMockRecorder) ListCompletedTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCompletedTasks", reflect.TypeOf((*MockManager)(nil).ListCompletedTasks))
+}
+
+// PinTask mocks base method.
+func (m *MockManager) PinTask(taskID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PinTask", taskID)
+}
+
+// PinTask indicates an expected call of PinTask.
+func (mr *MockManagerMockRecorder) PinTask(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinTask", reflect.TypeOf((*MockManager)(nil).PinTask), taskID)
+}
+
+// PinnedTasks mocks base method.
+func (m *MockManager) PinnedTasks() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinnedTasks")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// PinnedTasks indicates an expected call of PinnedTasks.
+func (mr *MockManagerMockRecorder) PinnedTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinnedTasks", reflect.TypeOf((*MockManager)(nil).PinnedTasks))
+}
+
 // ReadAllPieces mocks base method.
 func (m *MockManager) ReadAllPieces(ctx context.Context, req *storage.ReadAllPiecesRequest) (io.ReadCloser, error) {
 	m.ctrl.T.Helper()
@@ -444,6 +528,21 @@ func (mr *MockManagerMockRecorder) ReadPiece(ctx, req interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadPiece", reflect.TypeOf((*MockManager)(nil).ReadPiece), ctx, req)
 }
 
+// RegisterRangeTask mocks base method.
+func (m *MockManager) RegisterRangeTask(ctx context.Context, req *storage.RegisterRangeTaskRequest) (storage.TaskStorageDriver, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterRangeTask", ctx, req)
+	ret0, _ := ret[0].(storage.TaskStorageDriver)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterRangeTask indicates an expected call of RegisterRangeTask.
+func (mr *MockManagerMockRecorder) RegisterRangeTask(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRangeTask", reflect.TypeOf((*MockManager)(nil).RegisterRangeTask), ctx, req)
+}
+
 // RegisterSubTask mocks base method.
 func (m *MockManager) RegisterSubTask(ctx context.Context, req *storage.RegisterSubTaskRequest) (storage.TaskStorageDriver, error) {
 	m.ctrl.T.Helper()
@@ -488,6 +587,18 @@ func (mr *MockManagerMockRecorder) Store(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Store", reflect.TypeOf((*MockManager)(nil).Store), ctx, req)
 }
 
+// UnpinTask mocks base method.
+func (m *MockManager) UnpinTask(taskID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnpinTask", taskID)
+}
+
+// UnpinTask indicates an expected call of UnpinTask.
+func (mr *MockManagerMockRecorder) UnpinTask(taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinTask", reflect.TypeOf((*MockManager)(nil).UnpinTask), taskID)
+}
+
 // UnregisterTask mocks base method.
 func (m *MockManager) UnregisterTask(ctx context.Context, req storage.CommonTaskRequest) error {
 	m.ctrl.T.Helper()
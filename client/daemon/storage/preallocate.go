@@ -0,0 +1,153 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+)
+
+// ErrInsufficientCapacity is returned when the projected disk usage of the tasks exceeds the capacity.
+var ErrInsufficientCapacity = errors.New("insufficient storage capacity")
+
+// reservation reserves the disk space of the tasks before their data files are allocated,
+// so the concurrent tasks never run out of the disk space in the middle of downloading.
+type reservation struct {
+	mu       sync.Mutex
+	capacity int64
+	dataPath string
+	reserved map[PeerTaskMetadata]int64
+
+	// usage returns the used and total bytes of the disk of the path.
+	usage func(path string) (int64, int64, error)
+}
+
+// newReservation returns a new reservation of the disk of the data path.
+func newReservation(opt *config.PreallocateOption, dataPath string) *reservation {
+	return &reservation{
+		capacity: int64(opt.Capacity),
+		dataPath: dataPath,
+		reserved: map[PeerTaskMetadata]int64{},
+		usage:    diskUsage,
+	}
+}
+
+// reserve reserves the size bytes of the task, it returns ErrInsufficientCapacity when the used bytes
+// of the disk, the reserved bytes of the unallocated tasks and the size exceed the capacity.
+func (r *reservation) reserve(key PeerTaskMetadata, size int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, capacity, err := r.usage(r.dataPath)
+	if err != nil {
+		return err
+	}
+
+	if r.capacity > 0 {
+		capacity = r.capacity
+	}
+
+	projected := used + size
+	for k, reserved := range r.reserved {
+		if k != key {
+			projected += reserved
+		}
+	}
+
+	if projected > capacity {
+		metrics.StorageReservationRejectedCount.Inc()
+		return fmt.Errorf("%w: projected usage %d bytes exceeds capacity %d bytes", ErrInsufficientCapacity, projected, capacity)
+	}
+
+	r.set(key, size)
+	return nil
+}
+
+// release releases the reserved bytes of the task, it is called when the data file of the task
+// is allocated, the task is done or the task is reclaimed.
+func (r *reservation) release(key PeerTaskMetadata) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.set(key, 0)
+}
+
+// set sets the reserved bytes of the task, the reservation is removed when the size is 0.
+func (r *reservation) set(key PeerTaskMetadata, size int64) {
+	metrics.StorageReservedBytes.Add(float64(size - r.reserved[key]))
+	if size > 0 {
+		r.reserved[key] = size
+		return
+	}
+
+	delete(r.reserved, key)
+}
+
+// diskUsage returns the used and total bytes of the disk of the path.
+func diskUsage(path string) (int64, int64, error) {
+	usage, err := disk.Usage(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int64(usage.Used), int64(usage.Total), nil
+}
+
+// preallocate reserves the disk space of the task and preallocates the data file with the content length,
+// so the pieces written at their final offsets never fragment the file or run out of the disk space.
+func (t *localTaskStore) preallocate(contentLength int64) error {
+	if t.reservation == nil || contentLength <= 0 {
+		return nil
+	}
+
+	key := PeerTaskMetadata{TaskID: t.TaskID, PeerID: t.PeerID}
+	if err := t.reservation.reserve(key, contentLength); err != nil {
+		return err
+	}
+
+	if err := fallocate(t.DataFilePath, contentLength); err != nil {
+		if errors.Is(err, syscall.ENOSPC) {
+			t.reservation.release(key)
+			return fmt.Errorf("%w: %s", ErrInsufficientCapacity, err)
+		}
+
+		// The reservation is kept until the task is done when the file system does not support fallocate.
+		t.Warnf("preallocate %d bytes error: %s", contentLength, err)
+		return nil
+	}
+
+	// The allocated space is counted in the used bytes of the disk.
+	t.reservation.release(key)
+	t.Debugf("preallocate %d bytes", contentLength)
+	return nil
+}
+
+// releaseReservation releases the reserved bytes of the task.
+func (t *localTaskStore) releaseReservation() {
+	if t.reservation == nil {
+		return
+	}
+
+	t.reservation.release(PeerTaskMetadata{TaskID: t.TaskID, PeerID: t.PeerID})
+}
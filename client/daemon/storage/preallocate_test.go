@@ -0,0 +1,142 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+func Test_reservation(t *testing.T) {
+	task1 := PeerTaskMetadata{TaskID: "task1", PeerID: "peer1"}
+	task2 := PeerTaskMetadata{TaskID: "task2", PeerID: "peer2"}
+
+	testCases := []struct {
+		name     string
+		capacity int64
+		run      func(t *testing.T, r *reservation)
+	}{
+		{
+			name: "reserve within the disk capacity",
+			run: func(t *testing.T, r *reservation) {
+				assert := testifyassert.New(t)
+				assert.Nil(r.reserve(task1, 30))
+				assert.Nil(r.reserve(task2, 30))
+				assert.Equal(map[PeerTaskMetadata]int64{task1: 30, task2: 30}, r.reserved)
+			},
+		},
+		{
+			name: "reject when the projected usage exceeds the disk capacity",
+			run: func(t *testing.T, r *reservation) {
+				assert := testifyassert.New(t)
+				assert.Nil(r.reserve(task1, 30))
+				assert.True(errors.Is(r.reserve(task2, 31), ErrInsufficientCapacity))
+				assert.Equal(map[PeerTaskMetadata]int64{task1: 30}, r.reserved)
+			},
+		},
+		{
+			name:     "reject when the projected usage exceeds the configured capacity",
+			capacity: 50,
+			run: func(t *testing.T, r *reservation) {
+				assert := testifyassert.New(t)
+				assert.True(errors.Is(r.reserve(task1, 11), ErrInsufficientCapacity))
+				assert.Nil(r.reserve(task1, 10))
+			},
+		},
+		{
+			name: "reserve again replaces the reservation of the task",
+			run: func(t *testing.T, r *reservation) {
+				assert := testifyassert.New(t)
+				assert.Nil(r.reserve(task1, 50))
+				assert.Nil(r.reserve(task1, 60))
+				assert.Equal(map[PeerTaskMetadata]int64{task1: 60}, r.reserved)
+			},
+		},
+		{
+			name: "release the reservation",
+			run: func(t *testing.T, r *reservation) {
+				assert := testifyassert.New(t)
+				assert.Nil(r.reserve(task1, 60))
+				r.release(task1)
+				r.release(task1)
+				assert.Empty(r.reserved)
+				assert.Nil(r.reserve(task2, 60))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &reservation{
+				capacity: tc.capacity,
+				reserved: map[PeerTaskMetadata]int64{},
+				usage: func(string) (int64, int64, error) {
+					return 40, 100, nil
+				},
+			}
+			tc.run(t, r)
+		})
+	}
+}
+
+func TestLocalTaskStore_Preallocate(t *testing.T) {
+	assert := testifyassert.New(t)
+	dataDir := t.TempDir()
+	dataFilePath := filepath.Join(dataDir, taskData)
+	assert.Nil(os.WriteFile(dataFilePath, nil, defaultFileMode))
+
+	r := &reservation{
+		reserved: map[PeerTaskMetadata]int64{},
+		usage: func(string) (int64, int64, error) {
+			return 0, 100, nil
+		},
+	}
+	ts := &localTaskStore{
+		SugaredLoggerOnWith: logger.With("task", "task"),
+		persistentMetadata: persistentMetadata{
+			TaskID:        "task",
+			PeerID:        "peer",
+			DataFilePath:  dataFilePath,
+			ContentLength: -1,
+			Pieces:        map[int32]PieceMetadata{},
+		},
+		dataDir:          dataDir,
+		metadataFilePath: filepath.Join(dataDir, taskMetadata),
+		reservation:      r,
+	}
+
+	assert.True(errors.Is(ts.UpdateTask(context.Background(), &UpdateTaskRequest{ContentLength: 101}), ErrInsufficientCapacity))
+	assert.Equal(int64(-1), ts.ContentLength)
+
+	assert.Nil(ts.UpdateTask(context.Background(), &UpdateTaskRequest{ContentLength: 10}))
+	assert.Equal(int64(10), ts.ContentLength)
+
+	// The file size is kept when the space is allocated.
+	stat, err := os.Stat(dataFilePath)
+	assert.Nil(err)
+	assert.Equal(int64(0), stat.Size())
+
+	ts.releaseReservation()
+	assert.Empty(r.reserved)
+}
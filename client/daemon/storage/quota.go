@@ -0,0 +1,137 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// exceedQuota returns whether the usage of the quota exceeds the high watermark,
+// and the bytes to evict for making the usage below the low watermark.
+func exceedQuota(opt *config.QuotaOption, dataPath string, taskBytes int64) (exceed bool, bytes int64) {
+	used, capacity := taskBytes, int64(opt.Size)
+	if capacity <= 0 {
+		usage, err := disk.Usage(dataPath)
+		if err != nil {
+			logger.Warnf("get %s disk usage error: %s", dataPath, err)
+			return false, 0
+		}
+
+		used, capacity = int64(usage.Used), int64(usage.Total)
+	}
+
+	metrics.StorageQuotaUsedBytes.Set(float64(used))
+	if float64(used) < float64(capacity)*opt.HighWatermark/100 {
+		return false, 0
+	}
+
+	bytes = used - int64(float64(capacity)*opt.LowWatermark/100)
+	logger.Infof("quota used %d bytes of %d bytes, exceed high watermark %f, %d bytes to evict",
+		used, capacity, opt.HighWatermark, bytes)
+	return true, bytes
+}
+
+// recordEviction collects the metrics of the evicted task.
+func recordEviction(reason string, contentLength int64) {
+	metrics.StorageEvictedTaskCount.WithLabelValues(reason).Inc()
+	if contentLength > 0 {
+		metrics.StorageEvictedBytes.WithLabelValues(reason).Add(float64(contentLength))
+	}
+}
+
+// PinTask pins the task, the pinned task is never reclaimed unless it is invalid.
+func (s *storageManager) PinTask(taskID string) {
+	if _, loaded := s.pinnedTasks.LoadOrStore(taskID, struct{}{}); !loaded {
+		metrics.StoragePinnedTaskGauge.Inc()
+		logger.Infof("task %s pinned", taskID)
+	}
+}
+
+// UnpinTask unpins the task, the task is reclaimed by gc again.
+func (s *storageManager) UnpinTask(taskID string) {
+	if _, loaded := s.pinnedTasks.LoadAndDelete(taskID); loaded {
+		metrics.StoragePinnedTaskGauge.Dec()
+		logger.Infof("task %s unpinned", taskID)
+	}
+}
+
+// PinnedTasks returns the ids of the pinned tasks.
+func (s *storageManager) PinnedTasks() []string {
+	taskIDs := []string{}
+	s.pinnedTasks.Range(func(key, _ any) bool {
+		taskIDs = append(taskIDs, key.(string))
+		return true
+	})
+
+	sort.Strings(taskIDs)
+	return taskIDs
+}
+
+// isPinned returns whether the task is pinned.
+func (s *storageManager) isPinned(taskID string) bool {
+	_, ok := s.pinnedTasks.Load(taskID)
+	return ok
+}
+
+// pinRequest is the request body of pinning or unpinning a task.
+type pinRequest struct {
+	TaskID string `json:"task_id"`
+}
+
+// PinHandler returns the http handler for managing the pinned tasks,
+// GET lists the pinned tasks, PUT pins the task and DELETE unpins the task.
+func PinHandler(manager Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodDelete:
+			var req pinRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if req.TaskID == "" {
+				http.Error(w, "task_id is required", http.StatusBadRequest)
+				return
+			}
+
+			if r.Method == http.MethodPut {
+				manager.PinTask(req.TaskID)
+			} else {
+				manager.UnpinTask(req.TaskID)
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(manager.PinnedTasks()); err != nil {
+			logger.Errorf("encode pinned tasks failed: %s", err)
+		}
+	})
+}
@@ -0,0 +1,251 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	testifyassert "github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/unit"
+)
+
+func newQuotaTestTask(taskID string, contentLength, lastAccess int64) *localTaskStore {
+	t := &localTaskStore{
+		SugaredLoggerOnWith: logger.With("task", taskID),
+		persistentMetadata: persistentMetadata{
+			TaskID:        taskID,
+			PeerID:        "peer-" + taskID,
+			ContentLength: contentLength,
+			Done:          true,
+		},
+		expireTime: time.Second,
+		gcCallback: func(CommonTaskRequest) {},
+		subtasks:   map[PeerTaskMetadata]*localSubTaskStore{},
+	}
+	t.lastAccess.Store(lastAccess)
+	return t
+}
+
+func Test_exceedQuota(t *testing.T) {
+	testCases := []struct {
+		name        string
+		taskBytes   int64
+		expectOK    bool
+		expectBytes int64
+	}{
+		{
+			name:        "usage is below the high watermark",
+			taskBytes:   80,
+			expectOK:    false,
+			expectBytes: 0,
+		},
+		{
+			name:        "usage reaches the high watermark",
+			taskBytes:   90,
+			expectOK:    true,
+			expectBytes: 40,
+		},
+		{
+			name:        "usage exceeds the quota",
+			taskBytes:   120,
+			expectOK:    true,
+			expectBytes: 70,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			ok, bytes := exceedQuota(&config.QuotaOption{
+				Enable:        true,
+				Size:          100 * unit.B,
+				HighWatermark: 90,
+				LowWatermark:  50,
+			}, t.TempDir(), tc.taskBytes)
+			assert.Equal(tc.expectOK, ok)
+			assert.Equal(tc.expectBytes, bytes)
+		})
+	}
+}
+
+func TestStorageManager_TryGCWithQuota(t *testing.T) {
+	testCases := []struct {
+		name         string
+		quota        config.QuotaOption
+		pinned       []string
+		tasks        []*localTaskStore
+		expectMarked []string
+	}{
+		{
+			name:  "expired tasks are kept when usage is below the high watermark",
+			quota: config.QuotaOption{Enable: true, Size: 100 * unit.B, HighWatermark: 90, LowWatermark: 50},
+			tasks: []*localTaskStore{
+				newQuotaTestTask("foo", 30, 1),
+				newQuotaTestTask("bar", 30, 2),
+			},
+		},
+		{
+			name:  "evict least recently used tasks until usage is below the low watermark",
+			quota: config.QuotaOption{Enable: true, Size: 100 * unit.B, HighWatermark: 90, LowWatermark: 50},
+			tasks: []*localTaskStore{
+				newQuotaTestTask("foo", 40, 1),
+				newQuotaTestTask("bar", 30, 2),
+				newQuotaTestTask("baz", 30, 3),
+			},
+			expectMarked: []string{"foo", "bar"},
+		},
+		{
+			name:   "pinned tasks are not evicted",
+			quota:  config.QuotaOption{Enable: true, Size: 100 * unit.B, HighWatermark: 90, LowWatermark: 50},
+			pinned: []string{"foo"},
+			tasks: []*localTaskStore{
+				newQuotaTestTask("foo", 40, 1),
+				newQuotaTestTask("bar", 30, 2),
+				newQuotaTestTask("baz", 30, 3),
+			},
+			expectMarked: []string{"bar", "baz"},
+		},
+		{
+			name:   "pinned tasks are not expired when quota is disabled",
+			pinned: []string{"foo"},
+			tasks: []*localTaskStore{
+				newQuotaTestTask("foo", 40, 1),
+				newQuotaTestTask("bar", 30, 2),
+			},
+			expectMarked: []string{"bar"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			s := &storageManager{
+				storeOption: &config.StorageOption{
+					DataPath: t.TempDir(),
+					Quota:    tc.quota,
+				},
+				gcInterval: time.Minute,
+			}
+			for _, taskID := range tc.pinned {
+				s.PinTask(taskID)
+			}
+
+			for _, task := range tc.tasks {
+				s.tasks.Store(PeerTaskMetadata{PeerID: task.PeerID, TaskID: task.TaskID}, task)
+			}
+
+			ok, err := s.TryGC()
+			assert.True(ok)
+			assert.Nil(err)
+
+			var marked []string
+			for _, task := range tc.tasks {
+				if task.reclaimMarked.Load() {
+					marked = append(marked, task.TaskID)
+				}
+			}
+			assert.Equal(tc.expectMarked, marked)
+		})
+	}
+}
+
+func TestStorageManager_PinTask(t *testing.T) {
+	assert := testifyassert.New(t)
+	s := &storageManager{}
+	assert.Empty(s.PinnedTasks())
+
+	s.PinTask("foo")
+	s.PinTask("bar")
+	s.PinTask("foo")
+	assert.Equal([]string{"bar", "foo"}, s.PinnedTasks())
+	assert.True(s.isPinned("foo"))
+
+	s.UnpinTask("foo")
+	s.UnpinTask("baz")
+	assert.Equal([]string{"bar"}, s.PinnedTasks())
+	assert.False(s.isPinned("foo"))
+}
+
+func TestPinHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "list pinned tasks",
+			method:     http.MethodGet,
+			expectCode: http.StatusOK,
+			expectBody: `["foo"]`,
+		},
+		{
+			name:       "pin task",
+			method:     http.MethodPut,
+			body:       `{"task_id":"bar"}`,
+			expectCode: http.StatusOK,
+			expectBody: `["bar","foo"]`,
+		},
+		{
+			name:       "unpin task",
+			method:     http.MethodDelete,
+			body:       `{"task_id":"foo"}`,
+			expectCode: http.StatusOK,
+			expectBody: `[]`,
+		},
+		{
+			name:       "invalid body",
+			method:     http.MethodPut,
+			body:       `foo`,
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "task id is empty",
+			method:     http.MethodPut,
+			body:       `{}`,
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			expectCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := testifyassert.New(t)
+			s := &storageManager{}
+			s.PinTask("foo")
+
+			w := httptest.NewRecorder()
+			PinHandler(s).ServeHTTP(w, httptest.NewRequest(tc.method, "/admin/storage/pins", strings.NewReader(tc.body)))
+			assert.Equal(tc.expectCode, w.Code)
+			if tc.expectBody != "" {
+				assert.JSONEq(tc.expectBody, w.Body.String())
+			}
+		})
+	}
+}
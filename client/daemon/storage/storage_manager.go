@@ -44,8 +44,10 @@ import (
 
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/gc"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
 	"d7y.io/dragonfly/v2/client/util"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/io/uring"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
 )
 
@@ -66,6 +68,9 @@ type TaskStorageDriver interface {
 
 	GetExtendAttribute(ctx context.Context, req *PeerTaskMetadata) (*commonv1.ExtendAttribute, error)
 
+	// GetTaskMeta returns a copy of the task meta of the task
+	GetTaskMeta(ctx context.Context, req *PeerTaskMetadata) (map[string]string, error)
+
 	UpdateTask(ctx context.Context, req *UpdateTaskRequest) error
 
 	// Store stores task data to the target path
@@ -96,16 +101,29 @@ type Manager interface {
 	RegisterTask(ctx context.Context, req *RegisterTaskRequest) (TaskStorageDriver, error)
 	// RegisterSubTask registers a subtask in storage driver
 	RegisterSubTask(ctx context.Context, req *RegisterSubTaskRequest) (TaskStorageDriver, error)
+	// RegisterRangeTask registers a completed subtask of the range which is served by the completed parent task
+	RegisterRangeTask(ctx context.Context, req *RegisterRangeTaskRequest) (TaskStorageDriver, error)
 	// UnregisterTask unregisters a task in storage driver
 	UnregisterTask(ctx context.Context, req CommonTaskRequest) error
 	// FindCompletedTask try to find a completed task for fast path
 	FindCompletedTask(taskID string) *ReusePeerTask
 	// FindCompletedSubTask try to find a completed subtask for fast path
 	FindCompletedSubTask(taskID string) *ReusePeerTask
+	// ListCompletedTasks lists all completed tasks with a copy of task meta
+	ListCompletedTasks() []*ReusePeerTask
 	// FindPartialCompletedTask try to find a partial completed task for fast path
 	FindPartialCompletedTask(taskID string, rg *nethttp.Range) *ReusePeerTask
+	// FindResumableTask try to find an unfinished task reloaded from disk to resume from its written pieces,
+	// the task is only returned once
+	FindResumableTask(taskID string) *ReusePeerTask
 	// CleanUp cleans all storage data
 	CleanUp()
+	// PinTask pins a task, the pinned task is never reclaimed by gc unless it is invalid
+	PinTask(taskID string)
+	// UnpinTask unpins a task
+	UnpinTask(taskID string)
+	// PinnedTasks returns the ids of all pinned tasks
+	PinnedTasks() []string
 }
 
 var (
@@ -138,6 +156,13 @@ type storageManager struct {
 	gcCallback         func(CommonTaskRequest)
 	gcInterval         time.Duration
 	dataDirMode        fs.FileMode
+	pinnedTasks        sync.Map // key: task id, value: struct{}
+	tieredCache        *tieredCache
+	ring               *uring.Ring
+	dedupIndex         *dedupIndex
+	cipher             *dataCipher
+	persistInterval    time.Duration
+	reservation        *reservation
 
 	indexRWMutex       sync.RWMutex
 	indexTask2PeerTask map[string][]*localTaskStore // key: task id, value: slice of localTaskStore
@@ -200,6 +225,45 @@ func NewStorageManager(storeStrategy config.StoreStrategy, opt *config.StorageOp
 		}
 	}
 
+	if s.storeOption.Tier.Enable {
+		if s.tieredCache, err = newTieredCache(&s.storeOption.Tier); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.storeOption.IOUring.Enable {
+		if s.ring, err = uring.New(s.storeOption.IOUring.Entries); err != nil {
+			if !errors.Is(err, uring.ErrNotSupported) {
+				return nil, err
+			}
+
+			logger.Warnf("io_uring is not supported, fall back to the standard file io: %s", err)
+		}
+	}
+
+	if s.storeOption.Dedup.Enable {
+		s.dedupIndex = newDedupIndex()
+	}
+
+	if s.storeOption.Encryption.Enable {
+		key, err := loadEncryptionKey(&s.storeOption.Encryption)
+		if err != nil {
+			return nil, err
+		}
+
+		if s.cipher, err = newDataCipher(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.storeOption.Resume.Enable {
+		s.persistInterval = s.storeOption.Resume.PersistInterval.Duration
+	}
+
+	if s.storeOption.Preallocate.Enable {
+		s.reservation = newReservation(&s.storeOption.Preallocate, s.storeOption.DataPath)
+	}
+
 	if err := s.ReloadPersistentTask(gcCallback); err != nil {
 		logger.Warnf("reload tasks error: %s", err)
 	}
@@ -276,6 +340,55 @@ func (s *storageManager) RegisterSubTask(ctx context.Context, req *RegisterSubTa
 	return subtask, nil
 }
 
+func (s *storageManager) RegisterRangeTask(ctx context.Context, req *RegisterRangeTaskRequest) (TaskStorageDriver, error) {
+	if t, ok := s.LoadTask(req.SubTask); ok {
+		return t, nil
+	}
+
+	t, ok := s.LoadTask(req.Parent)
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	parent, ok := t.(*localTaskStore)
+	if !ok {
+		return nil, fmt.Errorf("task %s is not a parent task", req.Parent.TaskID)
+	}
+
+	parent.RLock()
+	done, contentLength := parent.Done, parent.ContentLength
+	parent.RUnlock()
+	if !done {
+		return nil, fmt.Errorf("task %s is not completed", req.Parent.TaskID)
+	}
+
+	rg, err := nethttp.ParseURLMetaRange(req.Range, contentLength)
+	if err != nil {
+		return nil, err
+	}
+
+	subtask := parent.SubTask(&RegisterSubTaskRequest{
+		Parent:  req.Parent,
+		SubTask: req.SubTask,
+		Range:   &rg,
+	})
+	if err := subtask.initPieces(); err != nil {
+		parent.Lock()
+		delete(parent.subtasks, req.SubTask)
+		parent.Unlock()
+		return nil, err
+	}
+
+	s.subIndexRWMutex.Lock()
+	s.subIndexTask2PeerTask[req.SubTask.TaskID] = append(s.subIndexTask2PeerTask[req.SubTask.TaskID], subtask)
+	s.subIndexRWMutex.Unlock()
+
+	s.Lock()
+	s.tasks.Store(req.SubTask, subtask)
+	s.Unlock()
+	return subtask, nil
+}
+
 func (s *storageManager) WritePiece(ctx context.Context, req *WritePieceRequest) (int64, error) {
 	t, ok := s.LoadTask(
 		PeerTaskMetadata{
@@ -363,6 +476,18 @@ func (s *storageManager) GetExtendAttribute(ctx context.Context, req *PeerTaskMe
 	return t.(TaskStorageDriver).GetExtendAttribute(ctx, req)
 }
 
+func (s *storageManager) GetTaskMeta(ctx context.Context, req *PeerTaskMetadata) (map[string]string, error) {
+	t, ok := s.LoadTask(
+		PeerTaskMetadata{
+			TaskID: req.TaskID,
+			PeerID: req.PeerID,
+		})
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	return t.(TaskStorageDriver).GetTaskMeta(ctx, req)
+}
+
 func (s *storageManager) LoadTask(meta PeerTaskMetadata) (TaskStorageDriver, bool) {
 	s.Keep()
 	d, ok := s.tasks.Load(meta)
@@ -414,10 +539,24 @@ func (s *storageManager) CreateTask(req *RegisterTaskRequest) (TaskStorageDriver
 		metadataFilePath: path.Join(dataDir, taskMetadata),
 		expireTime:       s.storeOption.TaskExpireTime.Duration,
 		subtasks:         map[PeerTaskMetadata]*localSubTaskStore{},
+		tieredCache:      s.tieredCache,
+		ring:             s.ring,
+		dedupIndex:       s.dedupIndex,
+		cipher:           s.cipher,
+		persistInterval:  s.persistInterval,
+		reservation:      s.reservation,
 
 		SugaredLoggerOnWith: logger.With("task", req.TaskID, "peer", req.PeerID, "component", "localTaskStore"),
 	}
 
+	if s.cipher != nil {
+		encryption, err := s.cipher.newEncryption()
+		if err != nil {
+			return nil, err
+		}
+		t.Encryption = encryption
+	}
+
 	dataDirMode := defaultDirectoryMode
 	// If dirMode isn't in config, use default
 	if s.dataDirMode != os.FileMode(0) {
@@ -477,6 +616,14 @@ func (s *storageManager) CreateTask(req *RegisterTaskRequest) (TaskStorageDriver
 			}
 		}
 	}
+
+	if err := t.preallocate(req.ContentLength); err != nil {
+		if rerr := t.Reclaim(); rerr != nil {
+			logger.Warnf("reclaim task %s/%s error: %s", req.TaskID, req.PeerID, rerr)
+		}
+		return nil, err
+	}
+
 	s.tasks.Store(
 		PeerTaskMetadata{
 			PeerID: req.PeerID,
@@ -528,6 +675,42 @@ func (s *storageManager) FindCompletedTask(taskID string) *ReusePeerTask {
 	return nil
 }
 
+func (s *storageManager) ListCompletedTasks() []*ReusePeerTask {
+	s.indexRWMutex.RLock()
+	defer s.indexRWMutex.RUnlock()
+
+	var tasks []*ReusePeerTask
+	for taskID, ts := range s.indexTask2PeerTask {
+		for _, t := range ts {
+			if t.invalid.Load() || t.reclaimMarked.Load() || !t.Done {
+				continue
+			}
+
+			t.RLock()
+			taskMeta := make(map[string]string, len(t.TaskMeta))
+			for k, v := range t.TaskMeta {
+				taskMeta[k] = v
+			}
+			t.RUnlock()
+
+			tasks = append(tasks, &ReusePeerTask{
+				Storage: t,
+				PeerTaskMetadata: PeerTaskMetadata{
+					PeerID: t.PeerID,
+					TaskID: taskID,
+				},
+				ContentLength: t.ContentLength,
+				TotalPieces:   t.TotalPieces,
+				Header:        t.Header,
+				TaskMeta:      taskMeta,
+			})
+			break
+		}
+	}
+
+	return tasks
+}
+
 func (s *storageManager) FindPartialCompletedTask(taskID string, rg *nethttp.Range) *ReusePeerTask {
 	s.indexRWMutex.RLock()
 	defer s.indexRWMutex.RUnlock()
@@ -562,6 +745,52 @@ func (s *storageManager) FindPartialCompletedTask(taskID string, rg *nethttp.Ran
 	return nil
 }
 
+func (s *storageManager) FindResumableTask(taskID string) *ReusePeerTask {
+	t := s.findResumableTask(taskID)
+	if t == nil {
+		return nil
+	}
+
+	// the pieces are verified against the data file out of the index lock
+	pieces := t.resumablePieces()
+	return &ReusePeerTask{
+		Storage: t,
+		PeerTaskMetadata: PeerTaskMetadata{
+			PeerID: t.PeerID,
+			TaskID: taskID,
+		},
+		ContentLength: t.ContentLength,
+		TotalPieces:   t.TotalPieces,
+		PieceMd5Sign:  t.PieceMd5Sign,
+		Header:        t.Header,
+		Pieces:        pieces,
+	}
+}
+
+// findResumableTask returns the unfinished task reloaded from disk and marks it resumed.
+func (s *storageManager) findResumableTask(taskID string) *localTaskStore {
+	s.indexRWMutex.RLock()
+	defer s.indexRWMutex.RUnlock()
+	ts, ok := s.indexTask2PeerTask[taskID]
+	if !ok {
+		return nil
+	}
+	for _, t := range ts {
+		if t.invalid.Load() || t.reclaimMarked.Load() {
+			continue
+		}
+
+		// only one peer task resumes the task
+		if !t.resumable.CompareAndSwap(true, false) {
+			continue
+		}
+
+		t.touch()
+		return t
+	}
+	return nil
+}
+
 func (s *storageManager) FindCompletedSubTask(taskID string) *ReusePeerTask {
 	s.subIndexRWMutex.RLock()
 	defer s.subIndexRWMutex.RUnlock()
@@ -697,6 +926,12 @@ func (s *storageManager) ReloadPersistentTask(gcCallback GCCallback) error {
 				metadataFilePath:    path.Join(dataDir, taskMetadata),
 				expireTime:          s.storeOption.TaskExpireTime.Duration,
 				gcCallback:          gcCallback,
+				tieredCache:         s.tieredCache,
+				ring:                s.ring,
+				dedupIndex:          s.dedupIndex,
+				cipher:              s.cipher,
+				persistInterval:     s.persistInterval,
+				reservation:         s.reservation,
 				SugaredLoggerOnWith: logger.With("task", taskID, "peer", peerID, "component", s.storeStrategy),
 			}
 			t.touch()
@@ -727,6 +962,15 @@ func (s *storageManager) ReloadPersistentTask(gcCallback GCCallback) error {
 					Warnf("load task from disk error: %s, data base64 encode: %s", err0, base64.StdEncoding.EncodeToString(bytes))
 				continue
 			}
+
+			// the data file is unreadable when it is encrypted by the other key, or when the encryption is switched
+			if err0 = s.checkEncryption(t.Encryption); err0 != nil {
+				loadErrs = append(loadErrs, err0)
+				loadErrDirs = append(loadErrDirs, dataDir)
+				logger.With("action", "reload", "stage", "check encryption", "taskID", taskID, "peerID", peerID).
+					Warnf("load task from disk error: %s", err0)
+				continue
+			}
 			logger.Debugf("load task %s/%s from disk, metadata %s, last access: %v, expire time: %s",
 				t.persistentMetadata.TaskID, t.persistentMetadata.PeerID, t.metadataFilePath, time.Unix(0, t.lastAccess.Load()), t.expireTime)
 			s.tasks.Store(PeerTaskMetadata{
@@ -734,6 +978,26 @@ func (s *storageManager) ReloadPersistentTask(gcCallback GCCallback) error {
 				TaskID: taskID,
 			}, t)
 
+			// the unfinished task is resumed by the next download of the task
+			if s.persistInterval > 0 && !t.Done && len(t.Pieces) > 0 {
+				t.resumable.Store(true)
+			}
+
+			// the reloaded pieces are referenced only, they were deduplicated when written
+			if s.dedupIndex != nil {
+				for _, piece := range t.Pieces {
+					if piece.Md5 == "" {
+						continue
+					}
+
+					s.dedupIndex.Add(PeerTaskMetadata{TaskID: taskID, PeerID: peerID}, piece.Num, piece.Md5, pieceLocation{
+						dataFilePath: t.DataFilePath,
+						start:        piece.Range.Start,
+						length:       piece.Range.Length,
+					})
+				}
+			}
+
 			// update index
 			if ts, ok := s.indexTask2PeerTask[taskID]; ok {
 				ts = append(ts, t)
@@ -792,33 +1056,49 @@ func (s *storageManager) TryGC() (bool, error) {
 	var markedTasks []PeerTaskMetadata
 	var totalNotMarkedSize int64
 	s.tasks.Range(func(key, task any) bool {
-		if task.(Reclaimer).CanReclaim() {
+		lts, ok := task.(*localTaskStore)
+		// the pinned tasks and the tasks managed by the quota are not expired
+		// by the task expire time, only the invalid ones are reclaimed
+		keep := ok && !lts.invalid.Load() && (s.storeOption.Quota.Enable || s.isPinned(lts.TaskID))
+		if !keep && task.(Reclaimer).CanReclaim() {
 			task.(Reclaimer).MarkReclaim()
 			markedTasks = append(markedTasks, key.(PeerTaskMetadata))
-		} else {
-			lts, ok := task.(*localTaskStore)
-			if ok {
-				// just calculate not reclaimed task
-				totalNotMarkedSize += lts.ContentLength
-				logger.Debugf("task %s/%s not reach gc time",
-					key.(PeerTaskMetadata).TaskID, key.(PeerTaskMetadata).PeerID)
+			if ok && !lts.invalid.Load() {
+				recordEviction(metrics.StorageEvictReasonExpired, lts.ContentLength)
 			}
+		} else if ok {
+			// just calculate not reclaimed task
+			totalNotMarkedSize += lts.ContentLength
+			logger.Debugf("task %s/%s not reach gc time",
+				key.(PeerTaskMetadata).TaskID, key.(PeerTaskMetadata).PeerID)
 		}
 		return true
 	})
 
-	quotaBytesExceed := totalNotMarkedSize - int64(s.storeOption.DiskGCThreshold)
-	quotaExceed := s.storeOption.DiskGCThreshold > 0 && quotaBytesExceed > 0
-	usageExceed, usageBytesExceed := s.diskUsageExceed()
+	var (
+		exceed      bool
+		bytesExceed int64
+		reason      string
+	)
+	if s.storeOption.Quota.Enable {
+		exceed, bytesExceed = exceedQuota(&s.storeOption.Quota, s.storeOption.DataPath, totalNotMarkedSize)
+		reason = metrics.StorageEvictReasonQuota
+	} else {
+		quotaBytesExceed := totalNotMarkedSize - int64(s.storeOption.DiskGCThreshold)
+		quotaExceed := s.storeOption.DiskGCThreshold > 0 && quotaBytesExceed > 0
+		usageExceed, usageBytesExceed := s.diskUsageExceed()
 
-	if quotaExceed || usageExceed {
-		var bytesExceed int64
+		exceed = quotaExceed || usageExceed
 		// only use quotaBytesExceed when s.storeOption.DiskGCThreshold > 0
 		if s.storeOption.DiskGCThreshold > 0 && quotaBytesExceed > usageBytesExceed {
 			bytesExceed = quotaBytesExceed
 		} else {
 			bytesExceed = usageBytesExceed
 		}
+		reason = metrics.StorageEvictReasonThreshold
+	}
+
+	if exceed {
 		logger.Infof("quota threshold reached, start gc oldest task, size: %d bytes", bytesExceed)
 		var tasks []*localTaskStore
 		s.tasks.Range(func(key, val any) bool {
@@ -830,6 +1110,10 @@ func (s *storageManager) TryGC() (bool, error) {
 			if task.reclaimMarked.Load() {
 				return true
 			}
+			// skip pinned task
+			if s.isPinned(task.TaskID) {
+				return true
+			}
 			// task is not done, and is active in s.gcInterval
 			// next gc loop will check it again
 			if !task.Done && time.Since(time.Unix(0, task.lastAccess.Load())) < s.gcInterval {
@@ -848,6 +1132,7 @@ func (s *storageManager) TryGC() (bool, error) {
 			logger.Infof("quota threshold reached, mark task %s/%s reclaimed, last access: %s, size: %s",
 				task.TaskID, task.PeerID, time.Unix(0, task.lastAccess.Load()).Format(time.RFC3339Nano),
 				units.BytesSize(float64(task.ContentLength)))
+			recordEviction(reason, task.ContentLength)
 			bytesExceed -= task.ContentLength
 			if bytesExceed <= 0 {
 				break
@@ -0,0 +1,287 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// tierPiecesDir is the directory of the cached pieces in the ssd path.
+const tierPiecesDir = "pieces"
+
+// pieceKey identifies a piece in the tiered cache.
+type pieceKey struct {
+	taskID string
+	peerID string
+	num    int32
+}
+
+// tierEntry is a cached piece, data is only set in the memory tier.
+type tierEntry struct {
+	key  pieceKey
+	size int64
+	data []byte
+}
+
+// tier is a byte bounded lru list of the cached pieces.
+type tier struct {
+	name     string
+	capacity int64
+	used     int64
+	entries  *list.List
+	index    map[pieceKey]*list.Element
+}
+
+func newTier(name string, capacity int64) *tier {
+	return &tier{
+		name:     name,
+		capacity: capacity,
+		entries:  list.New(),
+		index:    map[pieceKey]*list.Element{},
+	}
+}
+
+// get returns the entry and marks it as the most recently used.
+func (t *tier) get(key pieceKey) (*tierEntry, bool) {
+	elem, ok := t.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	t.entries.MoveToFront(elem)
+	return elem.Value.(*tierEntry), true
+}
+
+// add inserts the entry and returns the least recently used entries evicted for it,
+// the entry itself is returned when it is larger than the capacity.
+func (t *tier) add(entry *tierEntry) []*tierEntry {
+	if entry.size > t.capacity {
+		return []*tierEntry{entry}
+	}
+
+	if elem, ok := t.index[entry.key]; ok {
+		t.entries.MoveToFront(elem)
+		return nil
+	}
+
+	t.index[entry.key] = t.entries.PushFront(entry)
+	t.used += entry.size
+
+	var evicted []*tierEntry
+	for t.used > t.capacity {
+		evicted = append(evicted, t.removeElement(t.entries.Back()))
+	}
+
+	metrics.StorageTierUsedBytes.WithLabelValues(t.name).Set(float64(t.used))
+	return evicted
+}
+
+// remove deletes the entry of the key.
+func (t *tier) remove(key pieceKey) (*tierEntry, bool) {
+	elem, ok := t.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := t.removeElement(elem)
+	metrics.StorageTierUsedBytes.WithLabelValues(t.name).Set(float64(t.used))
+	return entry, true
+}
+
+// removePeerTask deletes all the entries of the peer task.
+func (t *tier) removePeerTask(taskID, peerID string) {
+	for key, elem := range t.index {
+		if key.taskID == taskID && key.peerID == peerID {
+			t.removeElement(elem)
+		}
+	}
+
+	metrics.StorageTierUsedBytes.WithLabelValues(t.name).Set(float64(t.used))
+}
+
+func (t *tier) removeElement(elem *list.Element) *tierEntry {
+	entry := t.entries.Remove(elem).(*tierEntry)
+	delete(t.index, entry.key)
+	t.used -= entry.size
+	return entry
+}
+
+// tieredCache keeps the hot pieces in the memory and ssd tiers in front of the data path.
+// A piece read from the data path is promoted to the highest tier, the least recently used
+// pieces are demoted to the lower tier, and the pieces evicted from the lowest tier are only
+// kept in the data path. A piece lives in one tier at most.
+type tieredCache struct {
+	mu      sync.Mutex
+	memory  *tier
+	ssd     *tier
+	ssdPath string
+}
+
+// newTieredCache returns a tiered cache, a tier is disabled when its size is not configured.
+func newTieredCache(opt *config.TierOption) (*tieredCache, error) {
+	c := &tieredCache{}
+	if opt.MemorySize > 0 {
+		c.memory = newTier(metrics.StorageTierMemory, int64(opt.MemorySize))
+	}
+
+	if opt.SSDPath != "" {
+		// The index of the ssd tier is not persistent, clean the pieces cached by the last run.
+		c.ssdPath = filepath.Join(opt.SSDPath, tierPiecesDir)
+		if err := os.RemoveAll(c.ssdPath); err != nil {
+			return nil, err
+		}
+
+		if err := os.MkdirAll(c.ssdPath, defaultDirectoryMode); err != nil {
+			return nil, err
+		}
+
+		c.ssd = newTier(metrics.StorageTierSSD, int64(opt.SSDSize))
+	}
+
+	return c, nil
+}
+
+// Get returns the cached piece, the piece hit in the ssd tier is promoted to the memory tier.
+func (c *tieredCache) Get(key pieceKey) ([]byte, bool) {
+	c.mu.Lock()
+	if c.memory != nil {
+		if entry, ok := c.memory.get(key); ok {
+			c.mu.Unlock()
+			metrics.StorageTierHitCount.WithLabelValues(metrics.StorageTierMemory).Inc()
+			return entry.data, true
+		}
+	}
+
+	if c.ssd == nil {
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	if _, ok := c.ssd.get(key); !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.mu.Unlock()
+
+	data, err := os.ReadFile(c.piecePath(key))
+	if err != nil {
+		logger.Warnf("read piece %d of task %s from ssd tier error: %s", key.num, key.taskID, err)
+		return nil, false
+	}
+	metrics.StorageTierHitCount.WithLabelValues(metrics.StorageTierSSD).Inc()
+
+	if c.memory != nil {
+		c.mu.Lock()
+		if _, ok := c.ssd.remove(key); ok {
+			c.removeFile(key)
+			c.putMemory(key, data)
+		}
+		c.mu.Unlock()
+	}
+
+	return data, true
+}
+
+// Put caches the piece read from the data path in the highest tier.
+func (c *tieredCache) Put(key pieceKey, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.memory != nil {
+		c.putMemory(key, data)
+		return
+	}
+
+	if c.ssd != nil {
+		c.putSSD(key, data)
+	}
+}
+
+// DeletePeerTask removes all the cached pieces of the peer task.
+func (c *tieredCache) DeletePeerTask(taskID, peerID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.memory != nil {
+		c.memory.removePeerTask(taskID, peerID)
+	}
+
+	if c.ssd != nil {
+		c.ssd.removePeerTask(taskID, peerID)
+		taskDir := filepath.Join(c.ssdPath, taskID)
+		if err := os.RemoveAll(filepath.Join(taskDir, peerID)); err != nil {
+			logger.Warnf("remove pieces of task %s in ssd tier error: %s", taskID, err)
+		}
+
+		// The task directory is kept when there are pieces of other peers.
+		_ = os.Remove(taskDir)
+	}
+}
+
+// putMemory caches the piece in the memory tier, and demotes the evicted pieces to the ssd tier.
+func (c *tieredCache) putMemory(key pieceKey, data []byte) {
+	metrics.StorageTierPromotedCount.WithLabelValues(metrics.StorageTierMemory).Inc()
+	for _, entry := range c.memory.add(&tierEntry{key: key, size: int64(len(data)), data: data}) {
+		metrics.StorageTierDemotedCount.WithLabelValues(metrics.StorageTierMemory).Inc()
+		if c.ssd != nil {
+			c.putSSD(entry.key, entry.data)
+		}
+	}
+}
+
+// putSSD caches the piece in the ssd tier, and drops the evicted pieces which are kept in the data path.
+func (c *tieredCache) putSSD(key pieceKey, data []byte) {
+	entry := &tierEntry{key: key, size: int64(len(data))}
+	if entry.size <= c.ssd.capacity {
+		if err := c.writeFile(key, data); err != nil {
+			logger.Warnf("write piece %d of task %s to ssd tier error: %s", key.num, key.taskID, err)
+			return
+		}
+	}
+
+	for _, evicted := range c.ssd.add(entry) {
+		metrics.StorageTierDemotedCount.WithLabelValues(metrics.StorageTierSSD).Inc()
+		c.removeFile(evicted.key)
+	}
+}
+
+func (c *tieredCache) piecePath(key pieceKey) string {
+	return filepath.Join(c.ssdPath, key.taskID, key.peerID, strconv.Itoa(int(key.num)))
+}
+
+func (c *tieredCache) writeFile(key pieceKey, data []byte) error {
+	path := c.piecePath(key)
+	if err := os.MkdirAll(filepath.Dir(path), defaultDirectoryMode); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, defaultFileMode)
+}
+
+func (c *tieredCache) removeFile(key pieceKey) {
+	if err := os.Remove(c.piecePath(key)); err != nil && !os.IsNotExist(err) {
+		logger.Warnf("remove piece %d of task %s in ssd tier error: %s", key.num, key.taskID, err)
+	}
+}
@@ -0,0 +1,250 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testifyassert "github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/unit"
+)
+
+func Test_newTieredCache(t *testing.T) {
+	ssdPath := t.TempDir()
+	stalePath := filepath.Join(ssdPath, tierPiecesDir, "task", "peer")
+	testifyassert.Nil(t, os.MkdirAll(stalePath, defaultDirectoryMode))
+
+	testCases := []struct {
+		name   string
+		opt    *config.TierOption
+		expect func(t *testing.T, c *tieredCache, err error)
+	}{
+		{
+			name: "memory tier only",
+			opt:  &config.TierOption{Enable: true, MemorySize: 10 * unit.B},
+			expect: func(t *testing.T, c *tieredCache, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+				assert.NotNil(c.memory)
+				assert.Nil(c.ssd)
+			},
+		},
+		{
+			name: "memory and ssd tiers",
+			opt:  &config.TierOption{Enable: true, MemorySize: 10 * unit.B, SSDPath: ssdPath, SSDSize: 20 * unit.B},
+			expect: func(t *testing.T, c *tieredCache, err error) {
+				assert := testifyassert.New(t)
+				assert.Nil(err)
+				assert.NotNil(c.memory)
+				assert.NotNil(c.ssd)
+				assert.Equal(int64(20), c.ssd.capacity)
+				assert.NoDirExists(stalePath)
+				assert.DirExists(filepath.Join(ssdPath, tierPiecesDir))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newTieredCache(tc.opt)
+			tc.expect(t, c, err)
+		})
+	}
+}
+
+func Test_tieredCache(t *testing.T) {
+	key := func(num int32) pieceKey {
+		return pieceKey{taskID: "task", peerID: "peer", num: num}
+	}
+
+	testCases := []struct {
+		name string
+		opt  func(ssdPath string) *config.TierOption
+		run  func(t *testing.T, c *tieredCache)
+	}{
+		{
+			name: "get missing piece",
+			opt: func(ssdPath string) *config.TierOption {
+				return &config.TierOption{MemorySize: 8 * unit.B, SSDPath: ssdPath, SSDSize: 8 * unit.B}
+			},
+			run: func(t *testing.T, c *tieredCache) {
+				assert := testifyassert.New(t)
+				_, ok := c.Get(key(0))
+				assert.False(ok)
+			},
+		},
+		{
+			name: "demote least recently used piece to ssd tier",
+			opt: func(ssdPath string) *config.TierOption {
+				return &config.TierOption{MemorySize: 8 * unit.B, SSDPath: ssdPath, SSDSize: 8 * unit.B}
+			},
+			run: func(t *testing.T, c *tieredCache) {
+				assert := testifyassert.New(t)
+				c.Put(key(0), []byte("aaaa"))
+				c.Put(key(1), []byte("bbbb"))
+				_, ok := c.Get(key(0))
+				assert.True(ok)
+
+				c.Put(key(2), []byte("cccc"))
+				_, ok = c.memory.index[key(1)]
+				assert.False(ok)
+				_, ok = c.ssd.index[key(1)]
+				assert.True(ok)
+				assert.FileExists(c.piecePath(key(1)))
+			},
+		},
+		{
+			name: "promote piece from ssd tier on access",
+			opt: func(ssdPath string) *config.TierOption {
+				return &config.TierOption{MemorySize: 4 * unit.B, SSDPath: ssdPath, SSDSize: 8 * unit.B}
+			},
+			run: func(t *testing.T, c *tieredCache) {
+				assert := testifyassert.New(t)
+				c.Put(key(0), []byte("aaaa"))
+				c.Put(key(1), []byte("bbbb"))
+
+				data, ok := c.Get(key(0))
+				assert.True(ok)
+				assert.Equal([]byte("aaaa"), data)
+				_, ok = c.memory.index[key(0)]
+				assert.True(ok)
+				_, ok = c.ssd.index[key(0)]
+				assert.False(ok)
+				assert.NoFileExists(c.piecePath(key(0)))
+
+				_, ok = c.ssd.index[key(1)]
+				assert.True(ok)
+				assert.Equal(int64(4), c.ssd.used)
+			},
+		},
+		{
+			name: "drop piece evicted from ssd tier",
+			opt: func(ssdPath string) *config.TierOption {
+				return &config.TierOption{SSDPath: ssdPath, SSDSize: 4 * unit.B}
+			},
+			run: func(t *testing.T, c *tieredCache) {
+				assert := testifyassert.New(t)
+				c.Put(key(0), []byte("aaaa"))
+				c.Put(key(1), []byte("bbbb"))
+
+				_, ok := c.Get(key(0))
+				assert.False(ok)
+				assert.NoFileExists(c.piecePath(key(0)))
+				data, ok := c.Get(key(1))
+				assert.True(ok)
+				assert.Equal([]byte("bbbb"), data)
+			},
+		},
+		{
+			name: "skip piece larger than tier",
+			opt: func(ssdPath string) *config.TierOption {
+				return &config.TierOption{MemorySize: 2 * unit.B}
+			},
+			run: func(t *testing.T, c *tieredCache) {
+				assert := testifyassert.New(t)
+				c.Put(key(0), []byte("aaaa"))
+				_, ok := c.Get(key(0))
+				assert.False(ok)
+				assert.Equal(int64(0), c.memory.used)
+			},
+		},
+		{
+			name: "delete peer task",
+			opt: func(ssdPath string) *config.TierOption {
+				return &config.TierOption{MemorySize: 4 * unit.B, SSDPath: ssdPath, SSDSize: 8 * unit.B}
+			},
+			run: func(t *testing.T, c *tieredCache) {
+				assert := testifyassert.New(t)
+				c.Put(key(0), []byte("aaaa"))
+				c.Put(key(1), []byte("bbbb"))
+				other := pieceKey{taskID: "task", peerID: "other", num: 0}
+				c.Put(other, []byte("cccc"))
+
+				c.DeletePeerTask("task", "peer")
+				_, ok := c.Get(key(0))
+				assert.False(ok)
+				_, ok = c.Get(key(1))
+				assert.False(ok)
+				assert.NoDirExists(filepath.Join(c.ssdPath, "task", "peer"))
+
+				data, ok := c.Get(other)
+				assert.True(ok)
+				assert.Equal([]byte("cccc"), data)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newTieredCache(tc.opt(t.TempDir()))
+			testifyassert.Nil(t, err)
+			tc.run(t, c)
+		})
+	}
+}
+
+func TestLocalTaskStore_ReadTieredPiece(t *testing.T) {
+	assert := testifyassert.New(t)
+	dataFilePath := filepath.Join(t.TempDir(), "data")
+	assert.Nil(os.WriteFile(dataFilePath, []byte("aaaabbbb"), defaultFileMode))
+
+	c, err := newTieredCache(&config.TierOption{MemorySize: 8 * unit.B})
+	assert.Nil(err)
+
+	ts := &localTaskStore{
+		SugaredLoggerOnWith: logger.With("task", "task"),
+		persistentMetadata: persistentMetadata{
+			TaskID:       "task",
+			PeerID:       "peer",
+			DataFilePath: dataFilePath,
+			Pieces: map[int32]PieceMetadata{
+				0: {Num: 0, Range: http.Range{Start: 0, Length: 4}},
+				1: {Num: 1, Range: http.Range{Start: 4, Length: 4}},
+			},
+		},
+		tieredCache: c,
+	}
+
+	read := func(num int32) string {
+		r, closer, err := ts.ReadPiece(context.Background(), &ReadPieceRequest{PeerTaskMetadata: PeerTaskMetadata{TaskID: "task", PeerID: "peer"}, PieceMetadata: PieceMetadata{Num: num}})
+		assert.Nil(err)
+		defer closer.Close()
+
+		data, err := io.ReadAll(r)
+		assert.Nil(err)
+		return string(data)
+	}
+
+	assert.Equal("bbbb", read(1))
+	_, ok := c.memory.index[pieceKey{taskID: "task", peerID: "peer", num: 1}]
+	assert.True(ok)
+
+	// The cached piece is served after the data file is removed.
+	assert.Nil(os.Remove(dataFilePath))
+	assert.Equal("bbbb", read(1))
+
+	_, _, err = ts.ReadPiece(context.Background(), &ReadPieceRequest{PieceMetadata: PieceMetadata{Num: 2}})
+	assert.ErrorIs(err, ErrPieceNotFound)
+}
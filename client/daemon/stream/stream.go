@@ -0,0 +1,494 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/stream_mock.go -source stream.go -package mocks
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-http-utils/headers"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// PathDownload is the path of the streaming download api.
+	PathDownload = "/download"
+
+	// PathImport is the path of the streaming import api.
+	PathImport = "/import"
+
+	// PathCaches is the path of the api to list and delete the imported caches.
+	PathCaches = "/caches"
+
+	// PathProgress is the path of the api to get the progress of the running task.
+	PathProgress = "/progress"
+)
+
+const (
+	// taskMetaURL is the key of the task url in task meta.
+	taskMetaURL = "url"
+
+	// taskMetaTag is the key of the task tag in task meta.
+	taskMetaTag = "tag"
+
+	// taskMetaNamespace is the key of the cache namespace in task meta.
+	taskMetaNamespace = "namespace"
+
+	// taskMetaLabelPrefix is the key prefix of the cache labels in task meta.
+	taskMetaLabelPrefix = "label."
+)
+
+// Stream is the interface used for streaming download server.
+type Stream interface {
+	// Started streaming download server.
+	Serve(lis net.Listener) error
+
+	// Stop streaming download server.
+	Stop() error
+}
+
+// stream writes the verified pieces to the caller in order as they arrive,
+// and imports the request body to local storage piece by piece.
+type stream struct {
+	*http.Server
+	peerTaskManager peer.TaskManager
+	storageManager  storage.Manager
+	peerIDGenerator peer.IDGenerator
+}
+
+// New returns a new Stream instence.
+func New(cfg *config.DaemonOption, peerTaskManager peer.TaskManager, storageManager storage.Manager) Stream {
+	s := &stream{
+		peerTaskManager: peerTaskManager,
+		storageManager:  storageManager,
+		peerIDGenerator: peer.NewPeerIDGenerator(cfg.Host.AdvertiseIP.String()),
+	}
+
+	s.Server = &http.Server{
+		Handler: s.initRouter(cfg),
+	}
+
+	return s
+}
+
+// Started streaming download server.
+func (s *stream) Serve(lis net.Listener) error {
+	return s.Server.Serve(lis)
+}
+
+// Stop streaming download server.
+func (s *stream) Stop() error {
+	return s.Server.Shutdown(context.Background())
+}
+
+// Initialize router of gin.
+func (s *stream) initRouter(cfg *config.DaemonOption) *gin.Engine {
+	if !cfg.Verbose {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET(PathDownload, s.download)
+	r.PUT(PathImport, s.importTask)
+	r.GET(PathCaches, s.listCaches)
+	r.DELETE(PathCaches, s.deleteCaches)
+	r.GET(PathProgress, s.progress)
+
+	return r
+}
+
+// download streams the content of the task in order as the pieces are verified.
+func (s *stream) download(ctx *gin.Context) {
+	var query DownloadQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var (
+		urlMeta = &commonv1.UrlMeta{
+			Digest:      query.Digest,
+			Tag:         query.Tag,
+			Range:       query.Range,
+			Filter:      query.Filter,
+			Header:      parseHeader(query.Header),
+			Application: query.Application,
+			Priority:    commonv1.Priority(query.Priority),
+		}
+		rg *nethttp.Range
+	)
+
+	if query.Range != "" {
+		rangeValue, err := nethttp.ParseURLMetaRange(query.Range, math.MaxInt64)
+		if err != nil {
+			ctx.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"errors": err.Error()})
+			return
+		}
+		rg = &rangeValue
+
+		// When the request has a range, there is no need to calculate digest.
+		urlMeta.Digest = ""
+	}
+
+	taskID := idgen.TaskIDV1(query.URL, urlMeta)
+	peerID := s.peerIDGenerator.PeerID()
+	log := logger.With("peer", peerID, "task", taskID, "component", "stream")
+	log.Infof("stream download %s meta: %#v", query.URL, urlMeta)
+
+	reader, attr, err := s.peerTaskManager.StartStreamTask(ctx, &peer.StreamTaskRequest{
+		URL:       query.URL,
+		URLMeta:   urlMeta,
+		Range:     rg,
+		PeerID:    peerID,
+		CacheOnly: query.LocalOnly,
+	})
+	if err != nil {
+		log.Errorf("start stream task error: %s", err)
+		if errors.Is(err, peer.ErrTaskNotCached) {
+			ctx.JSON(http.StatusNotFound, gin.H{"errors": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		return
+	}
+	defer reader.Close()
+
+	var contentLength int64 = -1
+	if l, ok := attr[headers.ContentLength]; ok {
+		if i, err := strconv.ParseInt(l, 10, 64); err == nil {
+			contentLength = i
+		}
+	}
+
+	log.Infof("stream content length is %d and content type is %s", contentLength, attr[headers.ContentType])
+	ctx.DataFromReader(http.StatusOK, contentLength, attr[headers.ContentType], reader, map[string]string{
+		config.HeaderDragonflyTask: attr[config.HeaderDragonflyTask],
+		config.HeaderDragonflyPeer: attr[config.HeaderDragonflyPeer],
+	})
+}
+
+// progress returns the progress of the running task with the traffic breakdown.
+func (s *stream) progress(ctx *gin.Context) {
+	var query ProgressQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	progress, ok := s.peerTaskManager.GetTaskProgress(query.TaskID, query.PeerID)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{"errors": "task is not running"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &Progress{
+		ContentLength:       progress.ContentLength,
+		CompletedLength:     progress.CompletedLength,
+		P2PTraffic:          progress.P2PTraffic,
+		SeedPeerTraffic:     progress.SeedPeerTraffic,
+		BackToSourceTraffic: progress.BackToSourceTraffic,
+	})
+}
+
+// importTask imports the request body to local storage piece by piece, and announces the task to scheduler.
+func (s *stream) importTask(ctx *gin.Context) {
+	var query ImportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var (
+		urlMeta = &commonv1.UrlMeta{
+			Tag:         query.Tag,
+			Application: query.Application,
+		}
+		taskID = idgen.TaskIDV1(query.URL, urlMeta)
+		peerID = s.peerIDGenerator.PeerID()
+		log    = logger.With("peer", peerID, "task", taskID, "component", "stream")
+	)
+
+	labels, err := parseLabels(query.Labels)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	taskMeta := map[string]string{
+		taskMetaURL:       query.URL,
+		taskMetaTag:       query.Tag,
+		taskMetaNamespace: query.Namespace,
+	}
+	for k, v := range labels {
+		taskMeta[taskMetaLabelPrefix+k] = v
+	}
+
+	if query.TTL > 0 {
+		taskMeta[storage.TaskMetaExpiresAt] = time.Now().Add(query.TTL).Format(time.RFC3339)
+	}
+
+	// The persistent cache is pinned, so it is not reclaimed by gc while scheduler maintains its replicas.
+	replication := types.Replication{Replicas: query.Replicas, SpreadBy: query.SpreadBy}
+
+	log.Infof("stream import %s meta: %#v, task meta: %#v", query.URL, urlMeta, taskMeta)
+	ptm := storage.PeerTaskMetadata{
+		PeerID: peerID,
+		TaskID: taskID,
+	}
+
+	// Task exists in local storage, refresh the task meta and announce it as well.
+	if task := s.storageManager.FindCompletedTask(taskID); task != nil {
+		log.Infof("import skipped, task already exists with peer %s", task.PeerID)
+		ptm.PeerID = task.PeerID
+		if err := task.Storage.UpdateTask(ctx, &storage.UpdateTaskRequest{PeerTaskMetadata: ptm, TaskMeta: taskMeta}); err != nil {
+			log.Errorf("update task meta failed: %s", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+			return
+		}
+
+		if err := task.Storage.Store(ctx, &storage.StoreRequest{
+			CommonTaskRequest: storage.CommonTaskRequest{
+				PeerID: ptm.PeerID,
+				TaskID: ptm.TaskID,
+			},
+			MetadataOnly: true,
+		}); err != nil {
+			log.Errorf("store task meta failed: %s", err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+			return
+		}
+
+		if replication.IsPersistent() {
+			s.storageManager.PinTask(taskID)
+		}
+
+		go s.announceTask(ptm, query.URL, urlMeta, replication, log)
+		ctx.Status(http.StatusOK)
+		return
+	}
+
+	tsd, err := s.storageManager.RegisterTask(ctx, &storage.RegisterTaskRequest{
+		PeerTaskMetadata: ptm,
+	})
+	if err != nil {
+		log.Errorf("register task to storage manager failed: %s", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		return
+	}
+
+	// Task meta is stored with the metadata after importing.
+	if err := tsd.UpdateTask(ctx, &storage.UpdateTaskRequest{PeerTaskMetadata: ptm, TaskMeta: taskMeta}); err != nil {
+		log.Errorf("update task meta failed: %s", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		return
+	}
+
+	// The content length of request body is unknown in chunked encoding, e.g. stdin.
+	if err := s.peerTaskManager.GetPieceManager().Import(ctx, ptm, tsd, ctx.Request.ContentLength, ctx.Request.Body); err != nil {
+		log.Errorf("import task failed: %s", err)
+		if err := s.storageManager.UnregisterTask(context.Background(), storage.CommonTaskRequest{
+			PeerID: ptm.PeerID,
+			TaskID: ptm.TaskID,
+		}); err != nil {
+			log.Errorf("unregister task failed: %s", err)
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		return
+	}
+	log.Info("import task succeeded")
+
+	if replication.IsPersistent() {
+		s.storageManager.PinTask(taskID)
+	}
+
+	go s.announceTask(ptm, query.URL, urlMeta, replication, log)
+	ctx.Status(http.StatusOK)
+}
+
+// listCaches lists the imported caches matched the namespace and labels.
+func (s *stream) listCaches(ctx *gin.Context) {
+	var query CacheQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	caches, err := s.findCaches(&query)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, caches)
+}
+
+// deleteCaches deletes the imported caches matched the namespace and labels,
+// the namespace or labels is required to avoid deleting all caches.
+func (s *stream) deleteCaches(ctx *gin.Context) {
+	var query CacheQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	if query.Namespace == "" && len(query.Labels) == 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": "namespace or label is required"})
+		return
+	}
+
+	caches, err := s.findCaches(&query)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	deleted := []*Cache{}
+	for _, cache := range caches {
+		if err := s.storageManager.UnregisterTask(ctx, storage.CommonTaskRequest{
+			PeerID: cache.PeerID,
+			TaskID: cache.TaskID,
+		}); err != nil {
+			logger.Errorf("delete cache %s failed: %s", cache.URL, err)
+			continue
+		}
+
+		logger.Infof("cache %s is deleted", cache.URL)
+		deleted = append(deleted, cache)
+	}
+
+	ctx.JSON(http.StatusOK, deleted)
+}
+
+// findCaches returns the imported caches matched the namespace and labels, sorted by url.
+func (s *stream) findCaches(query *CacheQuery) ([]*Cache, error) {
+	labels, err := parseLabels(query.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	caches := []*Cache{}
+	for _, task := range s.storageManager.ListCompletedTasks() {
+		// Only the caches imported by the streaming import api have url in task meta.
+		url, ok := task.TaskMeta[taskMetaURL]
+		if !ok {
+			continue
+		}
+
+		if query.Namespace != "" && task.TaskMeta[taskMetaNamespace] != query.Namespace {
+			continue
+		}
+
+		cache := &Cache{
+			TaskID:        task.TaskID,
+			PeerID:        task.PeerID,
+			URL:           url,
+			Tag:           task.TaskMeta[taskMetaTag],
+			Namespace:     task.TaskMeta[taskMetaNamespace],
+			Labels:        map[string]string{},
+			ContentLength: task.ContentLength,
+		}
+
+		for k, v := range task.TaskMeta {
+			if strings.HasPrefix(k, taskMetaLabelPrefix) {
+				cache.Labels[strings.TrimPrefix(k, taskMetaLabelPrefix)] = v
+			}
+		}
+
+		if expiresAt, err := time.Parse(time.RFC3339, task.TaskMeta[storage.TaskMetaExpiresAt]); err == nil {
+			cache.ExpiresAt = &expiresAt
+		}
+
+		matched := true
+		for k, v := range labels {
+			if cache.Labels[k] != v {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			caches = append(caches, cache)
+		}
+	}
+
+	sort.Slice(caches, func(i, j int) bool {
+		return caches[i].URL < caches[j].URL
+	})
+
+	return caches, nil
+}
+
+// announceTask announces the imported task to scheduler, the replication policy is carried
+// for the persistent cache.
+func (s *stream) announceTask(ptm storage.PeerTaskMetadata, url string, urlMeta *commonv1.UrlMeta, replication types.Replication, log *logger.SugaredLoggerOnWith) {
+	ctx := rpc.ContextWithReplication(context.Background(), replication)
+	if err := s.peerTaskManager.AnnouncePeerTask(ctx, ptm, url, commonv1.TaskType_DfCache, urlMeta); err != nil {
+		log.Warnf("announce task to scheduler failed: %s", err)
+	}
+}
+
+// parseLabels parses the labels formatted as "key=value".
+func parseLabels(s []string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, l := range s {
+		idx := strings.Index(l, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid label %s, it should be formatted as key=value", l)
+		}
+
+		labels[l[:idx]] = l[idx+1:]
+	}
+
+	return labels, nil
+}
+
+// parseHeader parses the headers formatted as "Key: Value".
+func parseHeader(s []string) map[string]string {
+	hdr := make(map[string]string)
+	for _, h := range s {
+		idx := strings.Index(h, ":")
+		if idx > 0 {
+			hdr[strings.TrimSpace(h[:idx])] = strings.TrimSpace(h[idx+1:])
+		}
+	}
+
+	return hdr
+}
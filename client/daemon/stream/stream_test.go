@@ -0,0 +1,521 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/storage"
+	"d7y.io/dragonfly/v2/client/daemon/storage/mocks"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestStream_Download(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  *DownloadQuery
+		mock   func(m *peer.MockTaskManagerMockRecorder)
+		expect func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "stream content",
+			query: &DownloadQuery{URL: "http://example.com/foo", Digest: "sha256:xxx", Header: []string{"Accept: *"}},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+						assert.Equal(t, "http://example.com/foo", req.URL)
+						assert.Equal(t, "sha256:xxx", req.URLMeta.Digest)
+						assert.Equal(t, map[string]string{"Accept": "*"}, req.URLMeta.Header)
+						assert.Nil(t, req.Range)
+						return io.NopCloser(strings.NewReader("foo")), map[string]string{
+							headers.ContentLength:      "3",
+							config.HeaderDragonflyTask: "task",
+							config.HeaderDragonflyPeer: "peer",
+						}, nil
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				assert.Equal(int64(3), resp.ContentLength)
+				assert.Equal("task", resp.Header.Get(config.HeaderDragonflyTask))
+				assert.Equal("peer", resp.Header.Get(config.HeaderDragonflyPeer))
+				data, err := io.ReadAll(resp.Body)
+				assert.NoError(err)
+				assert.Equal("foo", string(data))
+			},
+		},
+		{
+			name:  "stream range content",
+			query: &DownloadQuery{URL: "http://example.com/foo", Digest: "sha256:xxx", Range: "0-1"},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+						assert.Equal(t, "", req.URLMeta.Digest)
+						assert.Equal(t, "0-1", req.URLMeta.Range)
+						assert.Equal(t, int64(2), req.Range.Length)
+						return io.NopCloser(strings.NewReader("fo")), map[string]string{}, nil
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				data, err := io.ReadAll(resp.Body)
+				assert.NoError(err)
+				assert.Equal("fo", string(data))
+			},
+		},
+		{
+			name:  "range is invalid",
+			query: &DownloadQuery{URL: "http://example.com/foo", Range: "x-y"},
+			mock:  func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+			},
+		},
+		{
+			name:  "url is empty",
+			query: &DownloadQuery{},
+			mock:  func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			},
+		},
+		{
+			name:  "task is not cached",
+			query: &DownloadQuery{URL: "d7y:/foo", LocalOnly: true},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ any, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+						assert.True(t, req.CacheOnly)
+						return nil, nil, peer.ErrTaskNotCached
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:  "start stream task failed",
+			query: &DownloadQuery{URL: "http://example.com/foo"},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).Return(nil, nil, errors.New("foo"))
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			peerTaskManager := peer.NewMockTaskManager(ctl)
+			tc.mock(peerTaskManager.EXPECT())
+
+			s := New(&config.DaemonOption{Host: config.HostOption{AdvertiseIP: net.IPv4(127, 0, 0, 1)}}, peerTaskManager, nil)
+			server := httptest.NewServer(s.(*stream).Handler)
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + PathDownload + "?" + tc.query.Encode())
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			tc.expect(t, resp)
+		})
+	}
+}
+
+func TestStream_Import(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  *ImportQuery
+		mock   func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{})
+		expect func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "import content",
+			query: &ImportQuery{URL: "d7y:/foo", Tag: "bar", Namespace: "baz", Labels: []string{"foo=bar"}, TTL: time.Hour},
+			mock: func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{}) {
+				sm.FindCompletedTask(gomock.Any()).Return(nil)
+				sm.RegisterTask(gomock.Any(), gomock.Any()).Return(tsd, nil)
+				tsd.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, req *storage.UpdateTaskRequest) error {
+						assert.Equal(t, "d7y:/foo", req.TaskMeta[taskMetaURL])
+						assert.Equal(t, "bar", req.TaskMeta[taskMetaTag])
+						assert.Equal(t, "baz", req.TaskMeta[taskMetaNamespace])
+						assert.Equal(t, "bar", req.TaskMeta[taskMetaLabelPrefix+"foo"])
+						assert.NotEmpty(t, req.TaskMeta[storage.TaskMetaExpiresAt])
+						return nil
+					})
+				pieceManager.Import(gomock.Any(), gomock.Any(), gomock.Any(), int64(-1), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ storage.PeerTaskMetadata, _ storage.TaskStorageDriver, _ int64, reader io.Reader) error {
+						data, err := io.ReadAll(reader)
+						assert.NoError(t, err)
+						assert.Equal(t, "foo", string(data))
+						return nil
+					})
+				pm.AnnouncePeerTask(gomock.Any(), gomock.Any(), "d7y:/foo", commonv1.TaskType_DfCache, gomock.Any()).DoAndReturn(
+					func(context.Context, storage.PeerTaskMetadata, string, commonv1.TaskType, *commonv1.UrlMeta) error {
+						close(done)
+						return nil
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:  "task exists in local storage",
+			query: &ImportQuery{URL: "d7y:/foo"},
+			mock: func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{}) {
+				ptm := storage.PeerTaskMetadata{PeerID: "peer", TaskID: idgen.TaskIDV1("d7y:/foo", &commonv1.UrlMeta{})}
+				sm.FindCompletedTask(gomock.Any()).Return(&storage.ReusePeerTask{PeerTaskMetadata: ptm, Storage: tsd})
+				tsd.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil)
+				tsd.EXPECT().Store(gomock.Any(), gomock.Any()).Return(nil)
+				pm.AnnouncePeerTask(gomock.Any(), ptm, "d7y:/foo", commonv1.TaskType_DfCache, gomock.Any()).DoAndReturn(
+					func(context.Context, storage.PeerTaskMetadata, string, commonv1.TaskType, *commonv1.UrlMeta) error {
+						close(done)
+						return nil
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:  "import persistent cache",
+			query: &ImportQuery{URL: "d7y:/foo", Replicas: 3, SpreadBy: types.TopologyLabelZone},
+			mock: func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{}) {
+				ptm := storage.PeerTaskMetadata{PeerID: "peer", TaskID: idgen.TaskIDV1("d7y:/foo", &commonv1.UrlMeta{})}
+				sm.FindCompletedTask(gomock.Any()).Return(&storage.ReusePeerTask{PeerTaskMetadata: ptm, Storage: tsd})
+				tsd.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil)
+				tsd.EXPECT().Store(gomock.Any(), gomock.Any()).Return(nil)
+				sm.PinTask(ptm.TaskID)
+				pm.AnnouncePeerTask(gomock.Any(), ptm, "d7y:/foo", commonv1.TaskType_DfCache, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, _ storage.PeerTaskMetadata, _ string, _ commonv1.TaskType, _ *commonv1.UrlMeta) error {
+						md, _ := metadata.FromOutgoingContext(ctx)
+						replication, ok := rpc.ReplicationFromIncomingContext(metadata.NewIncomingContext(context.Background(), md))
+						assert.True(t, ok)
+						assert.Equal(t, types.Replication{Replicas: 3, SpreadBy: types.TopologyLabelZone}, replication)
+						close(done)
+						return nil
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:  "import failed",
+			query: &ImportQuery{URL: "d7y:/foo"},
+			mock: func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{}) {
+				sm.FindCompletedTask(gomock.Any()).Return(nil)
+				sm.RegisterTask(gomock.Any(), gomock.Any()).Return(tsd, nil)
+				tsd.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Return(nil)
+				pieceManager.Import(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("foo"))
+				sm.UnregisterTask(gomock.Any(), gomock.Any()).Return(nil)
+				close(done)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+		{
+			name:  "spread label is invalid",
+			query: &ImportQuery{URL: "d7y:/foo", Replicas: 3, SpreadBy: "foo"},
+			mock: func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{}) {
+				close(done)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			},
+		},
+		{
+			name:  "label is invalid",
+			query: &ImportQuery{URL: "d7y:/foo", Labels: []string{"foo"}},
+			mock: func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{}) {
+				close(done)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			},
+		},
+		{
+			name:  "url is empty",
+			query: &ImportQuery{},
+			mock: func(t *testing.T, pm *peer.MockTaskManagerMockRecorder, pieceManager *peer.MockPieceManagerMockRecorder, sm *mocks.MockManagerMockRecorder, tsd *mocks.MockTaskStorageDriver, done chan struct{}) {
+				close(done)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			peerTaskManager := peer.NewMockTaskManager(ctl)
+			pieceManager := peer.NewMockPieceManager(ctl)
+			storageManager := mocks.NewMockManager(ctl)
+			taskStorageDriver := mocks.NewMockTaskStorageDriver(ctl)
+			done := make(chan struct{})
+			peerTaskManager.EXPECT().GetPieceManager().Return(pieceManager).AnyTimes()
+			tc.mock(t, peerTaskManager.EXPECT(), pieceManager.EXPECT(), storageManager.EXPECT(), taskStorageDriver, done)
+
+			s := New(&config.DaemonOption{Host: config.HostOption{AdvertiseIP: net.IPv4(127, 0, 0, 1)}}, peerTaskManager, storageManager)
+			server := httptest.NewServer(s.(*stream).Handler)
+			defer server.Close()
+
+			// Hide the length of body to import with chunked encoding.
+			req, err := http.NewRequest(http.MethodPut, server.URL+PathImport+"?"+tc.query.Encode(), io.MultiReader(strings.NewReader("foo")))
+			assert.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			tc.expect(t, resp)
+			<-done
+		})
+	}
+}
+
+func TestStream_Caches(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	tasks := []*storage.ReusePeerTask{
+		{
+			PeerTaskMetadata: storage.PeerTaskMetadata{PeerID: "peer", TaskID: "foo"},
+			ContentLength:    1,
+			TaskMeta: map[string]string{
+				taskMetaURL:                 "d7y:/foo",
+				taskMetaNamespace:           "ci",
+				taskMetaLabelPrefix + "app": "foo",
+				storage.TaskMetaExpiresAt:   expiresAt.Format(time.RFC3339),
+			},
+		},
+		{
+			PeerTaskMetadata: storage.PeerTaskMetadata{PeerID: "peer", TaskID: "bar"},
+			TaskMeta: map[string]string{
+				taskMetaURL:                 "d7y:/bar",
+				taskMetaNamespace:           "ci",
+				taskMetaLabelPrefix + "app": "bar",
+			},
+		},
+		{
+			PeerTaskMetadata: storage.PeerTaskMetadata{PeerID: "peer", TaskID: "baz"},
+			TaskMeta:         map[string]string{},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		query  *CacheQuery
+		mock   func(sm *mocks.MockManagerMockRecorder)
+		expect func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "list all caches",
+			method: http.MethodGet,
+			query:  &CacheQuery{},
+			mock: func(sm *mocks.MockManagerMockRecorder) {
+				sm.ListCompletedTasks().Return(tasks)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				var caches []*Cache
+				assert.NoError(json.NewDecoder(resp.Body).Decode(&caches))
+				assert.Len(caches, 2)
+				assert.Equal("d7y:/bar", caches[0].URL)
+				assert.Nil(caches[0].ExpiresAt)
+				assert.Equal("d7y:/foo", caches[1].URL)
+				assert.Equal(map[string]string{"app": "foo"}, caches[1].Labels)
+				assert.Equal(int64(1), caches[1].ContentLength)
+				assert.True(expiresAt.Equal(*caches[1].ExpiresAt))
+			},
+		},
+		{
+			name:   "list caches by label",
+			method: http.MethodGet,
+			query:  &CacheQuery{Namespace: "ci", Labels: []string{"app=foo"}},
+			mock: func(sm *mocks.MockManagerMockRecorder) {
+				sm.ListCompletedTasks().Return(tasks)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				var caches []*Cache
+				assert.NoError(json.NewDecoder(resp.Body).Decode(&caches))
+				assert.Len(caches, 1)
+				assert.Equal("foo", caches[0].TaskID)
+			},
+		},
+		{
+			name:   "delete caches by namespace",
+			method: http.MethodDelete,
+			query:  &CacheQuery{Namespace: "ci"},
+			mock: func(sm *mocks.MockManagerMockRecorder) {
+				sm.ListCompletedTasks().Return(tasks)
+				sm.UnregisterTask(gomock.Any(), storage.CommonTaskRequest{PeerID: "peer", TaskID: "foo"}).Return(nil)
+				sm.UnregisterTask(gomock.Any(), storage.CommonTaskRequest{PeerID: "peer", TaskID: "bar"}).Return(errors.New("foo"))
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				var caches []*Cache
+				assert.NoError(json.NewDecoder(resp.Body).Decode(&caches))
+				assert.Len(caches, 1)
+				assert.Equal("foo", caches[0].TaskID)
+			},
+		},
+		{
+			name:   "delete caches without namespace and label",
+			method: http.MethodDelete,
+			query:  &CacheQuery{},
+			mock:   func(sm *mocks.MockManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			},
+		},
+		{
+			name:   "label is invalid",
+			method: http.MethodGet,
+			query:  &CacheQuery{Labels: []string{"=foo"}},
+			mock:   func(sm *mocks.MockManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			storageManager := mocks.NewMockManager(ctl)
+			tc.mock(storageManager.EXPECT())
+
+			s := New(&config.DaemonOption{Host: config.HostOption{AdvertiseIP: net.IPv4(127, 0, 0, 1)}}, peer.NewMockTaskManager(ctl), storageManager)
+			server := httptest.NewServer(s.(*stream).Handler)
+			defer server.Close()
+
+			req, err := http.NewRequest(tc.method, server.URL+PathCaches+"?"+tc.query.Encode(), nil)
+			assert.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			tc.expect(t, resp)
+		})
+	}
+}
+
+func TestStream_Progress(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  *ProgressQuery
+		mock   func(m *peer.MockTaskManagerMockRecorder)
+		expect func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "task is running",
+			query: &ProgressQuery{TaskID: "task", PeerID: "peer"},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.GetTaskProgress("task", "peer").Return(&peer.TaskProgress{
+					ContentLength:       10,
+					CompletedLength:     6,
+					P2PTraffic:          2,
+					SeedPeerTraffic:     3,
+					BackToSourceTraffic: 1,
+				}, true)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert := assert.New(t)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				var progress Progress
+				assert.NoError(json.NewDecoder(resp.Body).Decode(&progress))
+				assert.Equal(Progress{
+					ContentLength:       10,
+					CompletedLength:     6,
+					P2PTraffic:          2,
+					SeedPeerTraffic:     3,
+					BackToSourceTraffic: 1,
+				}, progress)
+			},
+		},
+		{
+			name:  "task is not running",
+			query: &ProgressQuery{TaskID: "task", PeerID: "peer"},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.GetTaskProgress("task", "peer").Return(nil, false)
+			},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:  "peer id is empty",
+			query: &ProgressQuery{TaskID: "task"},
+			mock:  func(m *peer.MockTaskManagerMockRecorder) {},
+			expect: func(t *testing.T, resp *http.Response) {
+				assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			peerTaskManager := peer.NewMockTaskManager(ctl)
+			tc.mock(peerTaskManager.EXPECT())
+
+			s := New(&config.DaemonOption{Host: config.HostOption{AdvertiseIP: net.IPv4(127, 0, 0, 1)}}, peerTaskManager, mocks.NewMockManager(ctl))
+			server := httptest.NewServer(s.(*stream).Handler)
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + PathProgress + "?" + tc.query.Encode())
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			tc.expect(t, resp)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stream
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type DownloadQuery struct {
+	// URL is the url of the task.
+	URL string `form:"url" binding:"required"`
+
+	// Tag is the tag of the task.
+	Tag string `form:"tag" binding:"omitempty"`
+
+	// Application is the application of the task.
+	Application string `form:"application" binding:"omitempty"`
+
+	// Filter is the filter of the task url.
+	Filter string `form:"filter" binding:"omitempty"`
+
+	// Digest is the digest of the task content.
+	Digest string `form:"digest" binding:"omitempty"`
+
+	// Range is the range of the task content, without "bytes=".
+	Range string `form:"range" binding:"omitempty"`
+
+	// Header is the headers of the source request, formatted as "Key: Value".
+	Header []string `form:"header" binding:"omitempty"`
+
+	// Priority is the priority of the task.
+	Priority int32 `form:"priority" binding:"omitempty"`
+
+	// LocalOnly serves the task from local completed tasks only.
+	LocalOnly bool `form:"localOnly" binding:"omitempty"`
+}
+
+// Encode encodes the query into url query string.
+func (q *DownloadQuery) Encode() string {
+	values := url.Values{}
+	values.Set("url", q.URL)
+	for key, value := range map[string]string{
+		"tag":         q.Tag,
+		"application": q.Application,
+		"filter":      q.Filter,
+		"digest":      q.Digest,
+		"range":       q.Range,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	for _, h := range q.Header {
+		values.Add("header", h)
+	}
+
+	if q.Priority != 0 {
+		values.Set("priority", strconv.Itoa(int(q.Priority)))
+	}
+
+	if q.LocalOnly {
+		values.Set("localOnly", strconv.FormatBool(q.LocalOnly))
+	}
+
+	return values.Encode()
+}
+
+type ImportQuery struct {
+	// URL is the url of the task.
+	URL string `form:"url" binding:"required"`
+
+	// Tag is the tag of the task.
+	Tag string `form:"tag" binding:"omitempty"`
+
+	// Application is the application of the task.
+	Application string `form:"application" binding:"omitempty"`
+
+	// Namespace is the namespace of the cache.
+	Namespace string `form:"namespace" binding:"omitempty"`
+
+	// Labels are the key/value tags of the cache, formatted as "key=value".
+	Labels []string `form:"label" binding:"omitempty"`
+
+	// TTL is the time to live of the cache, the cache is reclaimed after ttl is reached.
+	TTL time.Duration `form:"ttl" binding:"omitempty,gte=0"`
+
+	// Replicas is the replica count of the persistent cache maintained by scheduler.
+	Replicas int `form:"replicas" binding:"omitempty,gte=0"`
+
+	// SpreadBy is the topology label which the replicas are spread across, e.g. zone.
+	SpreadBy string `form:"spread_by" binding:"omitempty,oneof=region zone rack switch hypervisor"`
+}
+
+// Encode encodes the query into url query string.
+func (q *ImportQuery) Encode() string {
+	values := url.Values{}
+	values.Set("url", q.URL)
+	for key, value := range map[string]string{
+		"tag":         q.Tag,
+		"application": q.Application,
+		"namespace":   q.Namespace,
+		"spread_by":   q.SpreadBy,
+	} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+
+	for _, label := range q.Labels {
+		values.Add("label", label)
+	}
+
+	if q.TTL > 0 {
+		values.Set("ttl", q.TTL.String())
+	}
+
+	if q.Replicas > 0 {
+		values.Set("replicas", strconv.Itoa(q.Replicas))
+	}
+
+	return values.Encode()
+}
+
+type ProgressQuery struct {
+	// TaskID is the id of the task.
+	TaskID string `form:"task_id" binding:"required"`
+
+	// PeerID is the id of the peer.
+	PeerID string `form:"peer_id" binding:"required"`
+}
+
+// Encode encodes the query into url query string.
+func (q *ProgressQuery) Encode() string {
+	values := url.Values{}
+	values.Set("task_id", q.TaskID)
+	values.Set("peer_id", q.PeerID)
+	return values.Encode()
+}
+
+// Progress is the progress of the running task with the traffic breakdown.
+type Progress struct {
+	// ContentLength is the content length of the task, -1 is unknown.
+	ContentLength int64 `json:"contentLength"`
+
+	// CompletedLength is the length of the downloaded pieces.
+	CompletedLength int64 `json:"completedLength"`
+
+	// P2PTraffic is the traffic downloaded from normal peers.
+	P2PTraffic uint64 `json:"p2pTraffic"`
+
+	// SeedPeerTraffic is the traffic downloaded from seed peers.
+	SeedPeerTraffic uint64 `json:"seedPeerTraffic"`
+
+	// BackToSourceTraffic is the traffic downloaded from the source.
+	BackToSourceTraffic uint64 `json:"backToSourceTraffic"`
+}
+
+type CacheQuery struct {
+	// Namespace is the namespace of the caches.
+	Namespace string `form:"namespace" binding:"omitempty"`
+
+	// Labels are the key/value tags of the caches, formatted as "key=value".
+	Labels []string `form:"label" binding:"omitempty"`
+}
+
+// Encode encodes the query into url query string.
+func (q *CacheQuery) Encode() string {
+	values := url.Values{}
+	if q.Namespace != "" {
+		values.Set("namespace", q.Namespace)
+	}
+
+	for _, label := range q.Labels {
+		values.Add("label", label)
+	}
+
+	return values.Encode()
+}
+
+// Cache is the cache imported by the streaming import api.
+type Cache struct {
+	// TaskID is the id of the task.
+	TaskID string `json:"taskID"`
+
+	// PeerID is the id of the peer.
+	PeerID string `json:"peerID"`
+
+	// URL is the url of the task.
+	URL string `json:"url"`
+
+	// Tag is the tag of the task.
+	Tag string `json:"tag,omitempty"`
+
+	// Namespace is the namespace of the cache.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Labels are the key/value tags of the cache.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ContentLength is the content length of the cache.
+	ContentLength int64 `json:"contentLength"`
+
+	// ExpiresAt is the time when the cache is reclaimed.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
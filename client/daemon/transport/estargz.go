@@ -0,0 +1,272 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+)
+
+const (
+	// estargzFooterSize is the size of eStargz footer.
+	estargzFooterSize = 51
+
+	// legacyStargzFooterSize is the size of legacy stargz footer.
+	legacyStargzFooterSize = 47
+
+	// estargzTOCName is the name of TOC in the tar of TOC gzip stream.
+	estargzTOCName = "stargz.index.json"
+
+	// estargzPrefetchLandmark is the landmark file, the files before it are prioritized.
+	estargzPrefetchLandmark = ".prefetch.landmark"
+
+	// estargzNoPrefetchLandmark is the landmark file indicates no file is prioritized.
+	estargzNoPrefetchLandmark = ".no.prefetch.landmark"
+
+	// estargzMaxTOCSize is the max size of TOC, avoid reading the huge fake TOC.
+	estargzMaxTOCSize = 64 * 1024 * 1024
+)
+
+var (
+	// errNotEstargz indicates the layer is not eStargz.
+	errNotEstargz = errors.New("layer is not estargz")
+)
+
+// estargzTOC is the TOC of eStargz layer.
+type estargzTOC struct {
+	Version int                `json:"version"`
+	Entries []*estargzTOCEntry `json:"entries"`
+}
+
+// estargzTOCEntry is the entry of eStargz TOC, only the fields used to build the chunk map are decoded.
+type estargzTOCEntry struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Offset int64  `json:"offset,omitempty"`
+}
+
+// estargzLayer is the chunk map of eStargz layer.
+type estargzLayer struct {
+	// boundaries are the sorted compressed offsets of the chunks, the first one is 0,
+	// the last two are the offset of TOC and the content length.
+	boundaries []int64
+
+	// prefetchSize is the size of the prioritized files before the prefetch landmark,
+	// it is 0 when no file is prioritized.
+	prefetchSize int64
+}
+
+// parseEstargzFooter parses the offset of TOC from the eStargz or legacy stargz footer.
+func parseEstargzFooter(footer []byte) (int64, int64, error) {
+	for _, size := range []int{estargzFooterSize, legacyStargzFooterSize} {
+		if len(footer) < size {
+			continue
+		}
+
+		zr, err := gzip.NewReader(bytes.NewReader(footer[len(footer)-size:]))
+		if err != nil {
+			continue
+		}
+
+		extra := zr.Header.Extra
+		zr.Close()
+		if size == estargzFooterSize {
+			// the extra field of eStargz is a subfield with id "SG"
+			if len(extra) != 26 || extra[0] != 'S' || extra[1] != 'G' || extra[2] != 22 || extra[3] != 0 {
+				continue
+			}
+			extra = extra[4:]
+		}
+
+		if len(extra) != 22 || string(extra[16:]) != "STARGZ" {
+			continue
+		}
+
+		tocOffset, err := strconv.ParseInt(string(extra[:16]), 16, 64)
+		if err != nil {
+			continue
+		}
+
+		return tocOffset, int64(size), nil
+	}
+
+	return 0, 0, errNotEstargz
+}
+
+// parseEstargzTOC parses the TOC from the TOC gzip stream.
+func parseEstargzTOC(r io.Reader) (*estargzTOC, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if hdr.Name != estargzTOCName {
+		return nil, fmt.Errorf("unexpected toc name %s", hdr.Name)
+	}
+
+	toc := &estargzTOC{}
+	if err := json.NewDecoder(io.LimitReader(tr, estargzMaxTOCSize)).Decode(toc); err != nil {
+		return nil, err
+	}
+
+	return toc, nil
+}
+
+// newEstargzLayer builds the chunk map from the TOC.
+func newEstargzLayer(toc *estargzTOC, tocOffset, contentLength int64) *estargzLayer {
+	offsets := map[int64]struct{}{0: {}, tocOffset: {}, contentLength: {}}
+	var prefetchSize int64
+	for _, entry := range toc.Entries {
+		if entry.Type != "reg" && entry.Type != "chunk" {
+			continue
+		}
+
+		if entry.Offset > 0 && entry.Offset < tocOffset {
+			offsets[entry.Offset] = struct{}{}
+		}
+
+		switch entry.Name {
+		case estargzPrefetchLandmark:
+			prefetchSize = entry.Offset
+		case estargzNoPrefetchLandmark:
+			prefetchSize = 0
+		}
+	}
+
+	boundaries := make([]int64, 0, len(offsets))
+	for offset := range offsets {
+		boundaries = append(boundaries, offset)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+
+	return &estargzLayer{boundaries: boundaries, prefetchSize: prefetchSize}
+}
+
+// align returns the range aligned to the chunks covering the range, the ranges in the prioritized files are
+// aligned to the whole prioritized files, so they are downloaded as one task.
+func (l *estargzLayer) align(rg *nethttp.Range) (*nethttp.Range, bool) {
+	end := rg.Start + rg.Length
+	contentLength := l.boundaries[len(l.boundaries)-1]
+	if rg.Start < 0 || rg.Length <= 0 || end > contentLength {
+		return nil, false
+	}
+
+	if end <= l.prefetchSize {
+		return &nethttp.Range{Start: 0, Length: l.prefetchSize}, true
+	}
+
+	// the last boundary not greater than the start
+	i := sort.Search(len(l.boundaries), func(i int) bool { return l.boundaries[i] > rg.Start }) - 1
+	// the first boundary not less than the end
+	j := sort.Search(len(l.boundaries), func(i int) bool { return l.boundaries[i] >= end })
+	return &nethttp.Range{Start: l.boundaries[i], Length: l.boundaries[j] - l.boundaries[i]}, true
+}
+
+// learnEstargzLayer reads the footer and TOC of the layer, and stores the chunk map when the layer is eStargz.
+// The footer and TOC are downloaded with dragonfly, so they are shared between peers.
+func (rt *transport) learnEstargzLayer(key, url string, meta *commonv1.UrlMeta, contentLength int64) {
+	log := logger.With("component", "transport")
+	layer, err := rt.readEstargzLayer(url, meta, contentLength)
+	if err != nil {
+		if !errors.Is(err, errNotEstargz) {
+			log.Warnf("read estargz layer of %s error: %s", url, err)
+		}
+		metrics.ProxyEstargzLayerCount.WithLabelValues("other").Add(1)
+		rt.rangeCoalescer.SetLayer(key, nil)
+		return
+	}
+
+	log.Infof("estargz layer of %s has %d chunks, prioritized size %d", url, len(layer.boundaries)-1, layer.prefetchSize)
+	metrics.ProxyEstargzLayerCount.WithLabelValues("estargz").Add(1)
+	rt.rangeCoalescer.SetLayer(key, layer)
+
+	if rt.rangeCoalescer.estargzPrefetch && layer.prefetchSize > 0 {
+		rt.prefetchRange(url, meta, &nethttp.Range{Start: 0, Length: layer.prefetchSize})
+	}
+}
+
+// readEstargzLayer reads the chunk map of the layer.
+func (rt *transport) readEstargzLayer(url string, meta *commonv1.UrlMeta, contentLength int64) (*estargzLayer, error) {
+	if contentLength < estargzFooterSize {
+		return nil, errNotEstargz
+	}
+
+	footer, err := rt.readRange(url, meta, &nethttp.Range{Start: contentLength - estargzFooterSize, Length: estargzFooterSize})
+	if err != nil {
+		return nil, err
+	}
+
+	tocOffset, footerSize, err := parseEstargzFooter(footer)
+	if err != nil {
+		return nil, err
+	}
+
+	tocSize := contentLength - footerSize - tocOffset
+	if tocOffset <= 0 || tocSize <= 0 || tocSize > estargzMaxTOCSize {
+		return nil, fmt.Errorf("invalid toc offset %d", tocOffset)
+	}
+
+	data, err := rt.readRange(url, meta, &nethttp.Range{Start: tocOffset, Length: tocSize})
+	if err != nil {
+		return nil, err
+	}
+
+	toc, err := parseEstargzTOC(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return newEstargzLayer(toc, tocOffset, contentLength), nil
+}
+
+// readRange reads the range of url with dragonfly.
+func (rt *transport) readRange(url string, meta *commonv1.UrlMeta, rg *nethttp.Range) ([]byte, error) {
+	body, _, err := rt.peerTaskManager.StartStreamTask(context.Background(), &peer.StreamTaskRequest{
+		URL:     url,
+		URLMeta: cloneURLMeta(meta, rg),
+		Range:   rg,
+		PeerID:  rt.peerIDGenerator.PeerID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(io.LimitReader(body, rg.Length))
+}
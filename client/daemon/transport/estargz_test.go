@@ -0,0 +1,294 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+)
+
+// newTestEstargz builds an eStargz layer, every file is written in a gzip stream, and the landmark is
+// written after the prioritized files. It returns the layer and the offsets of files.
+func newTestEstargz(t *testing.T, files []string, prioritized int, legacy bool) ([]byte, []int64) {
+	var (
+		buf     bytes.Buffer
+		offsets []int64
+		toc     estargzTOC
+	)
+
+	writeGzip := func(data []byte) {
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, gz.Close())
+	}
+
+	for i, file := range files {
+		if i == prioritized {
+			toc.Entries = append(toc.Entries, &estargzTOCEntry{Name: estargzPrefetchLandmark, Type: "reg", Offset: int64(buf.Len())})
+			writeGzip([]byte{0xf})
+		}
+
+		offsets = append(offsets, int64(buf.Len()))
+		toc.Entries = append(toc.Entries, &estargzTOCEntry{Name: file, Type: "reg", Offset: int64(buf.Len())})
+		writeGzip(bytes.Repeat([]byte(file), 100))
+	}
+
+	// TOC
+	tocOffset := int64(buf.Len())
+	data, err := json.Marshal(toc)
+	assert.NoError(t, err)
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: estargzTOCName, Typeflag: tar.TypeReg, Size: int64(len(data))}))
+	_, err = tw.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	// Footer
+	buf.Write(newTestEstargzFooter(tocOffset, legacy))
+	return buf.Bytes(), offsets
+}
+
+// newTestEstargzFooter builds the footer as an empty gzip stream with the extra field, the empty
+// stored block is written with sync marker, same as the footer written by eStargz.
+func newTestEstargzFooter(tocOffset int64, legacy bool) []byte {
+	extra := []byte(fmt.Sprintf("%016xSTARGZ", tocOffset))
+	if !legacy {
+		extra = append([]byte{'S', 'G', 22, 0}, extra...)
+	}
+
+	footer := []byte{0x1f, 0x8b, 0x08, 0x04, 0, 0, 0, 0, 0, 0xff, byte(len(extra)), 0}
+	footer = append(footer, extra...)
+	footer = append(footer, 0x01, 0x00, 0x00, 0xff, 0xff)
+	return append(footer, make([]byte, 8)...)
+}
+
+func TestParseEstargzFooter(t *testing.T) {
+	estargz, _ := newTestEstargz(t, []string{"foo"}, 1, false)
+	legacy, _ := newTestEstargz(t, []string{"foo"}, 1, true)
+
+	tests := []struct {
+		name   string
+		footer []byte
+		expect func(t *testing.T, tocOffset, footerSize int64, err error)
+	}{
+		{
+			name:   "estargz footer",
+			footer: estargz[len(estargz)-estargzFooterSize:],
+			expect: func(t *testing.T, tocOffset, footerSize int64, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Greater(tocOffset, int64(0))
+				assert.Equal(int64(estargzFooterSize), footerSize)
+			},
+		},
+		{
+			name:   "legacy stargz footer",
+			footer: legacy[len(legacy)-estargzFooterSize:],
+			expect: func(t *testing.T, tocOffset, footerSize int64, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Greater(tocOffset, int64(0))
+				assert.Equal(int64(legacyStargzFooterSize), footerSize)
+			},
+		},
+		{
+			name:   "not estargz",
+			footer: bytes.Repeat([]byte{0}, estargzFooterSize),
+			expect: func(t *testing.T, tocOffset, footerSize int64, err error) {
+				assert.ErrorIs(t, err, errNotEstargz)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tocOffset, footerSize, err := parseEstargzFooter(tc.footer)
+			tc.expect(t, tocOffset, footerSize, err)
+		})
+	}
+}
+
+func TestEstargzLayer_Align(t *testing.T) {
+	layer := newEstargzLayer(&estargzTOC{Entries: []*estargzTOCEntry{
+		{Name: "foo", Type: "reg", Offset: 10},
+		{Name: estargzPrefetchLandmark, Type: "reg", Offset: 20},
+		{Name: "bar", Type: "reg", Offset: 30},
+		{Name: "bar", Type: "chunk", Offset: 40},
+		{Name: "dir", Type: "dir"},
+	}}, 50, 100)
+
+	tests := []struct {
+		name   string
+		rg     *nethttp.Range
+		expect func(t *testing.T, rg *nethttp.Range, ok bool)
+	}{
+		{
+			name: "range in prioritized files",
+			rg:   &nethttp.Range{Start: 12, Length: 5},
+			expect: func(t *testing.T, rg *nethttp.Range, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(&nethttp.Range{Start: 0, Length: 20}, rg)
+			},
+		},
+		{
+			name: "range in one chunk",
+			rg:   &nethttp.Range{Start: 42, Length: 5},
+			expect: func(t *testing.T, rg *nethttp.Range, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(&nethttp.Range{Start: 40, Length: 10}, rg)
+			},
+		},
+		{
+			name: "range across chunks",
+			rg:   &nethttp.Range{Start: 35, Length: 10},
+			expect: func(t *testing.T, rg *nethttp.Range, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(&nethttp.Range{Start: 30, Length: 20}, rg)
+			},
+		},
+		{
+			name: "range in toc",
+			rg:   &nethttp.Range{Start: 60, Length: 40},
+			expect: func(t *testing.T, rg *nethttp.Range, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(&nethttp.Range{Start: 50, Length: 50}, rg)
+			},
+		},
+		{
+			name: "range exceeds content length",
+			rg:   &nethttp.Range{Start: 90, Length: 20},
+			expect: func(t *testing.T, rg *nethttp.Range, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rg, ok := layer.align(tc.rg)
+			tc.expect(t, rg, ok)
+		})
+	}
+}
+
+func TestTransport_RoundTripWithEstargz(t *testing.T) {
+	assert := assert.New(t)
+	ctrl := gomock.NewController(t)
+	testData, offsets := newTestEstargz(t, []string{"foo", "bar", "baz"}, 1, false)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(testData))
+	}))
+	defer server.Close()
+
+	var (
+		mu     sync.Mutex
+		ranges []nethttp.Range
+	)
+	peerTaskManager := peer.NewMockTaskManager(ctrl)
+	peerTaskManager.EXPECT().StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+			mu.Lock()
+			ranges = append(ranges, *req.Range)
+			mu.Unlock()
+
+			data := testData[req.Range.Start : req.Range.Start+req.Range.Length]
+			return io.NopCloser(bytes.NewReader(data)), map[string]string{}, nil
+		},
+	).AnyTimes()
+
+	rt, _ := New(
+		WithPeerIDGenerator(peer.NewPeerIDGenerator("127.0.0.1")),
+		WithPeerTaskManager(peerTaskManager),
+		WithRangeCoalescer(NewRangeCoalescer(4, 1, WithEstargz(true))),
+		WithCondition(func(r *http.Request) bool {
+			return true
+		}))
+
+	roundTrip := func(rg string) *http.Response {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		req.Header.Set(headers.Range, rg)
+		resp, err := rt.RoundTrip(req)
+		assert.Nil(err)
+		return resp
+	}
+
+	// The first range learns the content length and the chunk map, then prefetches the prioritized files.
+	resp := roundTrip("bytes=0-1")
+	_, _ = io.ReadAll(resp.Body)
+	key := idgen.ParentTaskIDV1(server.URL, &commonv1.UrlMeta{})
+	assert.Eventually(func() bool {
+		_, ok := rt.(*transport).rangeCoalescer.Chunk(key, &nethttp.Range{Start: 0, Length: 1})
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(ranges) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	value, _ := rt.(*transport).rangeCoalescer.layers.Get(key)
+	layer := value.(*estargzLayer)
+	tocOffset := layer.boundaries[len(layer.boundaries)-2]
+	contentLength := int64(len(testData))
+	assert.Greater(layer.prefetchSize, offsets[0])
+	assert.Less(layer.prefetchSize, offsets[1])
+	assert.Equal([]nethttp.Range{
+		{Start: 0, Length: 2},
+		{Start: contentLength - estargzFooterSize, Length: estargzFooterSize},
+		{Start: tocOffset, Length: contentLength - estargzFooterSize - tocOffset},
+		{Start: 0, Length: layer.prefetchSize},
+	}, ranges)
+
+	// The range in the file is aligned to the chunk.
+	mu.Lock()
+	ranges = nil
+	mu.Unlock()
+	resp = roundTrip(fmt.Sprintf("bytes=%d-%d", offsets[1]+1, offsets[1]+2))
+	data, _ := io.ReadAll(resp.Body)
+	assert.Equal(testData[offsets[1]+1:offsets[1]+3], data)
+	assert.Equal(http.StatusPartialContent, resp.StatusCode)
+	assert.Equal([]nethttp.Range{{Start: offsets[1], Length: offsets[2] - offsets[1]}}, ranges)
+}
@@ -0,0 +1,286 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/daemon/metrics"
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/cache"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+)
+
+const (
+	// rangeCoalescingExpiration is the expiration of the content length and the prefetched blocks of urls.
+	rangeCoalescingExpiration = 10 * time.Minute
+
+	// rangeCoalescingCleanupInterval is the interval to cleanup the expired items.
+	rangeCoalescingCleanupInterval = time.Minute
+
+	// unknownContentLength indicates the content length of url is unknown, the ranges are not coalesced.
+	unknownContentLength int64 = -1
+)
+
+// RangeCoalescer coalesces the small range requests of the same url into aligned blocks,
+// the ranges in the same block share one task, so the block is downloaded once and the following
+// ranges are served from local cache, and the following blocks are prefetched in background.
+type RangeCoalescer struct {
+	blockSize      int64
+	prefetchBlocks int
+
+	// contentLengths stores the content length of urls, it is learned by head requests.
+	contentLengths cache.Cache
+
+	// blocks stores the blocks requested or prefetched, to avoid prefetching repeatedly.
+	blocks cache.Cache
+
+	// estargz aligns the ranges of eStargz layers to the chunks in TOC instead of the blocks.
+	estargz bool
+
+	// estargzPrefetch prefetches the prioritized files of eStargz layers.
+	estargzPrefetch bool
+
+	// layers stores the chunk maps of eStargz layers, the value is nil when the layer is not eStargz.
+	layers cache.Cache
+}
+
+// RangeCoalescerOption is a functional option for configuring the RangeCoalescer.
+type RangeCoalescerOption func(c *RangeCoalescer)
+
+// WithEstargz aligns the ranges of eStargz layers to the chunks, and prefetches the prioritized files
+// when prefetch is true.
+func WithEstargz(prefetch bool) RangeCoalescerOption {
+	return func(c *RangeCoalescer) {
+		c.estargz = true
+		c.estargzPrefetch = prefetch
+	}
+}
+
+// NewRangeCoalescer returns a new RangeCoalescer instance.
+func NewRangeCoalescer(blockSize int64, prefetchBlocks int, options ...RangeCoalescerOption) *RangeCoalescer {
+	c := &RangeCoalescer{
+		blockSize:      blockSize,
+		prefetchBlocks: prefetchBlocks,
+		contentLengths: cache.New(rangeCoalescingExpiration, rangeCoalescingCleanupInterval),
+		blocks:         cache.New(rangeCoalescingExpiration, rangeCoalescingCleanupInterval),
+		layers:         cache.New(rangeCoalescingExpiration, rangeCoalescingCleanupInterval),
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// ContentLength returns the content length of url, it returns false when the content length is not learned.
+func (c *RangeCoalescer) ContentLength(key string) (int64, bool) {
+	value, ok := c.contentLengths.Get(key)
+	if !ok {
+		return unknownContentLength, false
+	}
+
+	return value.(int64), true
+}
+
+// Block returns the aligned block covering the range, it returns false when the range is not coalesced.
+func (c *RangeCoalescer) Block(key string, rg *nethttp.Range, contentLength int64) (*nethttp.Range, bool) {
+	if contentLength == unknownContentLength || rg.Length >= c.blockSize || rg.Start+rg.Length > contentLength {
+		return nil, false
+	}
+
+	start := rg.Start / c.blockSize * c.blockSize
+	end := min((rg.Start+rg.Length+c.blockSize-1)/c.blockSize*c.blockSize, contentLength)
+	block := &nethttp.Range{Start: start, Length: end - start}
+	c.blocks.SetDefault(blockKey(key, block.Start), struct{}{})
+
+	return block, true
+}
+
+// Chunk returns the range aligned to the chunks of eStargz layer, it returns false when the layer is not eStargz
+// or the chunk map is not learned.
+func (c *RangeCoalescer) Chunk(key string, rg *nethttp.Range) (*nethttp.Range, bool) {
+	if !c.estargz {
+		return nil, false
+	}
+
+	value, ok := c.layers.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	layer, ok := value.(*estargzLayer)
+	if !ok || layer == nil {
+		return nil, false
+	}
+
+	return layer.align(rg)
+}
+
+// SetLayer stores the chunk map of eStargz layer, the layer is nil when it is not eStargz.
+func (c *RangeCoalescer) SetLayer(key string, layer *estargzLayer) {
+	c.layers.SetDefault(key, layer)
+}
+
+// Learn learns the content length of url with head request, it returns false when the content length is
+// already known or being learned.
+func (c *RangeCoalescer) Learn(key string, headFunc func() (int64, error)) bool {
+	// Mark the content length unknown until learned, avoid learning concurrently.
+	if err := c.contentLengths.Add(key, unknownContentLength, cache.DefaultExpiration); err != nil {
+		return false
+	}
+
+	contentLength, err := headFunc()
+	if err != nil || contentLength <= 0 {
+		logger.Debugf("learn content length of %s failed, content length: %d, error: %v", key, contentLength, err)
+		return true
+	}
+
+	c.contentLengths.SetDefault(key, contentLength)
+	return true
+}
+
+// PrefetchBlocks returns the following blocks of the block which are not requested or prefetched.
+func (c *RangeCoalescer) PrefetchBlocks(key string, block *nethttp.Range, contentLength int64) []*nethttp.Range {
+	var blocks []*nethttp.Range
+	for i := 0; i < c.prefetchBlocks; i++ {
+		start := block.Start + block.Length + int64(i)*c.blockSize
+		if start >= contentLength {
+			break
+		}
+
+		if err := c.blocks.Add(blockKey(key, start), struct{}{}, cache.DefaultExpiration); err != nil {
+			continue
+		}
+
+		blocks = append(blocks, &nethttp.Range{Start: start, Length: min(c.blockSize, contentLength-start)})
+	}
+
+	return blocks
+}
+
+func blockKey(key string, start int64) string {
+	return fmt.Sprintf("%s/%d", key, start)
+}
+
+// rangeReadCloser reads the range from the reader of block.
+type rangeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// newRangeReadCloser skips the bytes before the range and limits the length of the range.
+func newRangeReadCloser(rc io.ReadCloser, skip, length int64) (io.ReadCloser, error) {
+	if _, err := io.CopyN(io.Discard, rc, skip); err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	return &rangeReadCloser{Reader: io.LimitReader(rc, length), Closer: rc}, nil
+}
+
+// cloneURLMeta clones url meta with the range, the task may update the header of url meta.
+func cloneURLMeta(meta *commonv1.UrlMeta, rg *nethttp.Range) *commonv1.UrlMeta {
+	header := make(map[string]string, len(meta.Header))
+	for k, v := range meta.Header {
+		header[k] = v
+	}
+
+	return &commonv1.UrlMeta{
+		Digest:      meta.Digest,
+		Tag:         meta.Tag,
+		Range:       rg.URLMetaString(),
+		Filter:      meta.Filter,
+		Header:      header,
+		Application: meta.Application,
+		Priority:    meta.Priority,
+	}
+}
+
+// learnContentLength learns the content length of url with head request in background,
+// and then learns the chunk map when the url is an eStargz layer.
+func (rt *transport) learnContentLength(key string, req *http.Request, meta *commonv1.UrlMeta) {
+	headReq := req.Clone(context.Background())
+	headReq.Method = http.MethodHead
+	headReq.Header.Del("Range")
+	headReq.Host = headReq.URL.Host
+	headReq.Body = nil
+
+	url := req.URL.String()
+	go func() {
+		learned := rt.rangeCoalescer.Learn(key, func() (int64, error) {
+			client := &http.Client{Transport: rt.baseRoundTripper, Timeout: 30 * time.Second}
+			resp, err := client.Do(headReq)
+			if err != nil {
+				return unknownContentLength, err
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return unknownContentLength, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+			}
+
+			return resp.ContentLength, nil
+		})
+		if !learned || !rt.rangeCoalescer.estargz {
+			return
+		}
+
+		if contentLength, ok := rt.rangeCoalescer.ContentLength(key); ok && contentLength != unknownContentLength {
+			rt.learnEstargzLayer(key, url, meta, contentLength)
+		}
+	}()
+}
+
+// prefetchBlocks prefetches the following blocks of the block in background.
+func (rt *transport) prefetchBlocks(key, url string, meta *commonv1.UrlMeta, block *nethttp.Range, contentLength int64) {
+	for _, prefetch := range rt.rangeCoalescer.PrefetchBlocks(key, block, contentLength) {
+		go rt.prefetchRange(url, meta, prefetch)
+	}
+}
+
+// prefetchRange downloads the range of url as a task, the following requests of the range are served from local cache.
+func (rt *transport) prefetchRange(url string, meta *commonv1.UrlMeta, rg *nethttp.Range) {
+	peerID := rt.peerIDGenerator.PeerID()
+	log := logger.With("peer", peerID, "component", "transport")
+	log.Infof("prefetch range %s of %s", rg.String(), url)
+	metrics.ProxyRangePrefetchCount.Add(1)
+
+	body, _, err := rt.peerTaskManager.StartStreamTask(context.Background(), &peer.StreamTaskRequest{
+		URL:     url,
+		URLMeta: cloneURLMeta(meta, rg),
+		Range:   rg,
+		PeerID:  peerID,
+	})
+	if err != nil {
+		log.Warnf("prefetch range %s of %s error: %s", rg.String(), url, err)
+		return
+	}
+	defer body.Close()
+
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		log.Warnf("read prefetch range %s of %s error: %s", rg.String(), url, err)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+)
+
+func TestRangeCoalescer_Block(t *testing.T) {
+	tests := []struct {
+		name          string
+		rg            *nethttp.Range
+		contentLength int64
+		expect        func(t *testing.T, block *nethttp.Range, ok bool)
+	}{
+		{
+			name:          "range in one block",
+			rg:            &nethttp.Range{Start: 5, Length: 2},
+			contentLength: 10,
+			expect: func(t *testing.T, block *nethttp.Range, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(&nethttp.Range{Start: 4, Length: 4}, block)
+			},
+		},
+		{
+			name:          "range across blocks",
+			rg:            &nethttp.Range{Start: 3, Length: 2},
+			contentLength: 10,
+			expect: func(t *testing.T, block *nethttp.Range, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(&nethttp.Range{Start: 0, Length: 8}, block)
+			},
+		},
+		{
+			name:          "range in the last block",
+			rg:            &nethttp.Range{Start: 8, Length: 1},
+			contentLength: 10,
+			expect: func(t *testing.T, block *nethttp.Range, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(&nethttp.Range{Start: 8, Length: 2}, block)
+			},
+		},
+		{
+			name:          "range is not smaller than block",
+			rg:            &nethttp.Range{Start: 0, Length: 4},
+			contentLength: 10,
+			expect: func(t *testing.T, block *nethttp.Range, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name:          "range exceeds content length",
+			rg:            &nethttp.Range{Start: 9, Length: 2},
+			contentLength: 10,
+			expect: func(t *testing.T, block *nethttp.Range, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name:          "content length is unknown",
+			rg:            &nethttp.Range{Start: 5, Length: 2},
+			contentLength: unknownContentLength,
+			expect: func(t *testing.T, block *nethttp.Range, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewRangeCoalescer(4, 1)
+			block, ok := c.Block("foo", tc.rg, tc.contentLength)
+			tc.expect(t, block, ok)
+		})
+	}
+}
+
+func TestRangeCoalescer_PrefetchBlocks(t *testing.T) {
+	assert := assert.New(t)
+	c := NewRangeCoalescer(4, 2)
+
+	block, ok := c.Block("foo", &nethttp.Range{Start: 1, Length: 1}, 10)
+	assert.True(ok)
+	assert.Equal([]*nethttp.Range{{Start: 4, Length: 4}, {Start: 8, Length: 2}}, c.PrefetchBlocks("foo", block, 10))
+
+	// The blocks are prefetched already.
+	assert.Empty(c.PrefetchBlocks("foo", block, 10))
+}
+
+func TestRangeCoalescer_Learn(t *testing.T) {
+	assert := assert.New(t)
+	c := NewRangeCoalescer(4, 1)
+
+	_, ok := c.ContentLength("foo")
+	assert.False(ok)
+
+	assert.True(c.Learn("foo", func() (int64, error) { return 10, nil }))
+	contentLength, ok := c.ContentLength("foo")
+	assert.True(ok)
+	assert.Equal(int64(10), contentLength)
+
+	// The content length is learned already.
+	assert.False(c.Learn("foo", func() (int64, error) { return 20, nil }))
+}
+
+func TestTransport_RoundTripWithRangeCoalescer(t *testing.T) {
+	assert := assert.New(t)
+	ctrl := gomock.NewController(t)
+	testData := []byte("0123456789")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(testData))
+	}))
+	defer server.Close()
+
+	var (
+		mu     sync.Mutex
+		ranges []nethttp.Range
+	)
+	peerTaskManager := peer.NewMockTaskManager(ctrl)
+	peerTaskManager.EXPECT().StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+			mu.Lock()
+			ranges = append(ranges, *req.Range)
+			mu.Unlock()
+
+			assert.Equal(req.Range.URLMetaString(), req.URLMeta.Range)
+			data := testData[req.Range.Start : req.Range.Start+req.Range.Length]
+			return io.NopCloser(bytes.NewReader(data)), map[string]string{}, nil
+		},
+	).AnyTimes()
+
+	rt, _ := New(
+		WithPeerIDGenerator(peer.NewPeerIDGenerator("127.0.0.1")),
+		WithPeerTaskManager(peerTaskManager),
+		WithRangeCoalescer(NewRangeCoalescer(4, 1)),
+		WithCondition(func(r *http.Request) bool {
+			return true
+		}))
+
+	roundTrip := func(rg string) *http.Response {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		req.Header.Set(headers.Range, rg)
+		resp, err := rt.RoundTrip(req)
+		assert.Nil(err)
+		return resp
+	}
+
+	// The first range learns the content length.
+	resp := roundTrip("bytes=1-2")
+	data, _ := io.ReadAll(resp.Body)
+	assert.Equal("12", string(data))
+	assert.Eventually(func() bool {
+		contentLength, _ := rt.(*transport).rangeCoalescer.ContentLength(idgen.ParentTaskIDV1(server.URL, &commonv1.UrlMeta{}))
+		return contentLength == int64(len(testData))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The following range is coalesced into the block and the next block is prefetched.
+	resp = roundTrip("bytes=5-6")
+	data, _ = io.ReadAll(resp.Body)
+	assert.Equal("56", string(data))
+	assert.Equal(http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(int64(2), resp.ContentLength)
+	assert.Equal("bytes 5-6/10", resp.Header.Get(headers.ContentRange))
+	assert.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(ranges) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal([]nethttp.Range{{Start: 1, Length: 2}, {Start: 4, Length: 4}, {Start: 8, Length: 2}}, ranges)
+}
@@ -0,0 +1,137 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	"d7y.io/dragonfly/v2/pkg/cache"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+)
+
+const (
+	// defaultSequentialThreshold is the default count of the sequential reads to start prefetching.
+	defaultSequentialThreshold = 2
+)
+
+// SequentialPrefetcher detects the sequential range reads of the same url, and prefetches the following
+// ranges ahead of the reader within the window. The following ranges are predicted with the length of
+// the last read, so the reads of the reader hit the prefetched tasks.
+type SequentialPrefetcher struct {
+	window           int64
+	threshold        int
+	minContentLength int64
+
+	// streams stores the sequential read states of urls.
+	streams cache.Cache
+}
+
+// sequentialStream is the sequential read state of url.
+type sequentialStream struct {
+	mu sync.Mutex
+
+	// end is the end offset of the last read.
+	end int64
+
+	// length is the length of the last read.
+	length int64
+
+	// count is the count of the sequential reads with the same length.
+	count int
+
+	// prefetched is the end offset of the prefetched ranges.
+	prefetched int64
+}
+
+// NewSequentialPrefetcher returns a new SequentialPrefetcher instance, the objects smaller than
+// minContentLength are not prefetched.
+func NewSequentialPrefetcher(window int64, threshold int, minContentLength int64) *SequentialPrefetcher {
+	if threshold <= 0 {
+		threshold = defaultSequentialThreshold
+	}
+
+	return &SequentialPrefetcher{
+		window:           window,
+		threshold:        threshold,
+		minContentLength: minContentLength,
+		streams:          cache.New(rangeCoalescingExpiration, rangeCoalescingCleanupInterval),
+	}
+}
+
+// Observe records the range read of url, and returns the following ranges to prefetch
+// when the reads are sequential, the content length bounds the prefetched ranges.
+func (p *SequentialPrefetcher) Observe(key string, rg *nethttp.Range, contentLength int64) []*nethttp.Range {
+	value, ok := p.streams.Get(key)
+	if !ok {
+		stream := &sequentialStream{end: rg.Start + rg.Length, length: rg.Length, count: 1, prefetched: rg.Start + rg.Length}
+		if err := p.streams.Add(key, stream, cache.DefaultExpiration); err == nil {
+			return nil
+		}
+
+		if value, ok = p.streams.Get(key); !ok {
+			return nil
+		}
+	}
+
+	stream := value.(*sequentialStream)
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	p.streams.SetDefault(key, stream)
+
+	end := rg.Start + rg.Length
+	if rg.Start != stream.end || rg.Length != stream.length {
+		// The reader seeks or changes the read size, restart detecting.
+		stream.end, stream.length, stream.count, stream.prefetched = end, rg.Length, 1, end
+		return nil
+	}
+
+	stream.end = end
+	stream.count++
+	if stream.prefetched < end {
+		stream.prefetched = end
+	}
+
+	if stream.count < p.threshold || contentLength == unknownContentLength || contentLength < p.minContentLength {
+		return nil
+	}
+
+	var ranges []*nethttp.Range
+	for start := stream.prefetched; start < end+p.window && start < contentLength; start += rg.Length {
+		ranges = append(ranges, &nethttp.Range{Start: start, Length: min(rg.Length, contentLength-start)})
+		stream.prefetched = start + rg.Length
+	}
+
+	return ranges
+}
+
+// parseContentRangeTotal returns the total length in the Content-Range header,
+// it returns unknownContentLength when the total length is unknown.
+func parseContentRangeTotal(contentRange string) int64 {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return unknownContentLength
+	}
+
+	total, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil || total <= 0 {
+		return unknownContentLength
+	}
+
+	return total
+}
@@ -0,0 +1,182 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/client/daemon/peer"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+)
+
+func TestSequentialPrefetcher_Observe(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentLength int64
+		run           func(t *testing.T, p *SequentialPrefetcher)
+		expect        func(t *testing.T, ranges []*nethttp.Range)
+	}{
+		{
+			name:          "first read is not prefetched",
+			contentLength: 100,
+			run:           func(t *testing.T, p *SequentialPrefetcher) {},
+			expect: func(t *testing.T, ranges []*nethttp.Range) {
+				assert.Empty(t, ranges)
+			},
+		},
+		{
+			name:          "sequential reads are prefetched within window",
+			contentLength: 100,
+			run: func(t *testing.T, p *SequentialPrefetcher) {
+				assert.Empty(t, p.Observe("foo", &nethttp.Range{Start: 0, Length: 10}, 100))
+			},
+			expect: func(t *testing.T, ranges []*nethttp.Range) {
+				assert.Equal(t, []*nethttp.Range{{Start: 20, Length: 10}, {Start: 30, Length: 10}, {Start: 40, Length: 10}}, ranges)
+			},
+		},
+		{
+			name:          "prefetched ranges are not prefetched again",
+			contentLength: 100,
+			run: func(t *testing.T, p *SequentialPrefetcher) {
+				p.Observe("foo", &nethttp.Range{Start: 0, Length: 10}, 100)
+				p.Observe("foo", &nethttp.Range{Start: 10, Length: 10}, 100)
+				p.Observe("foo", &nethttp.Range{Start: 20, Length: 10}, 100)
+			},
+			expect: func(t *testing.T, ranges []*nethttp.Range) {
+				assert.Empty(t, ranges)
+			},
+		},
+		{
+			name:          "reader seeks",
+			contentLength: 100,
+			run: func(t *testing.T, p *SequentialPrefetcher) {
+				p.Observe("foo", &nethttp.Range{Start: 50, Length: 10}, 100)
+			},
+			expect: func(t *testing.T, ranges []*nethttp.Range) {
+				assert.Empty(t, ranges)
+			},
+		},
+		{
+			name:          "prefetched ranges are bounded by content length",
+			contentLength: 25,
+			run: func(t *testing.T, p *SequentialPrefetcher) {
+				p.Observe("foo", &nethttp.Range{Start: 0, Length: 10}, 25)
+			},
+			expect: func(t *testing.T, ranges []*nethttp.Range) {
+				assert.Equal(t, []*nethttp.Range{{Start: 20, Length: 5}}, ranges)
+			},
+		},
+		{
+			name:          "unknown content length is not prefetched",
+			contentLength: unknownContentLength,
+			run: func(t *testing.T, p *SequentialPrefetcher) {
+				p.Observe("foo", &nethttp.Range{Start: 0, Length: 10}, unknownContentLength)
+			},
+			expect: func(t *testing.T, ranges []*nethttp.Range) {
+				assert.Empty(t, ranges)
+			},
+		},
+		{
+			name:          "small object is not prefetched",
+			contentLength: 20,
+			run: func(t *testing.T, p *SequentialPrefetcher) {
+				p.Observe("foo", &nethttp.Range{Start: 0, Length: 10}, 20)
+			},
+			expect: func(t *testing.T, ranges []*nethttp.Range) {
+				assert.Empty(t, ranges)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewSequentialPrefetcher(30, 2, 21)
+			tc.run(t, p)
+			tc.expect(t, p.Observe("foo", &nethttp.Range{Start: 10, Length: 10}, tc.contentLength))
+		})
+	}
+}
+
+func TestParseContentRangeTotal(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(int64(100), parseContentRangeTotal("bytes 0-9/100"))
+	assert.Equal(unknownContentLength, parseContentRangeTotal("bytes 0-9/*"))
+	assert.Equal(unknownContentLength, parseContentRangeTotal(""))
+}
+
+func TestTransport_RoundTripWithSequentialPrefetcher(t *testing.T) {
+	assert := assert.New(t)
+	ctrl := gomock.NewController(t)
+	testData := []byte("0123456789")
+
+	var (
+		mu     sync.Mutex
+		ranges []nethttp.Range
+	)
+	peerTaskManager := peer.NewMockTaskManager(ctrl)
+	peerTaskManager.EXPECT().StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+			mu.Lock()
+			ranges = append(ranges, *req.Range)
+			mu.Unlock()
+
+			assert.Equal(req.Range.URLMetaString(), req.URLMeta.Range)
+			data := testData[req.Range.Start : req.Range.Start+req.Range.Length]
+			return io.NopCloser(bytes.NewReader(data)), map[string]string{
+				headers.ContentRange: fmt.Sprintf("bytes %d-%d/%d", req.Range.Start, req.Range.Start+req.Range.Length-1, len(testData)),
+			}, nil
+		},
+	).AnyTimes()
+
+	rt, _ := New(
+		WithPeerIDGenerator(peer.NewPeerIDGenerator("127.0.0.1")),
+		WithPeerTaskManager(peerTaskManager),
+		WithSequentialPrefetcher(NewSequentialPrefetcher(4, 2, 0)),
+		WithCondition(func(r *http.Request) bool {
+			return true
+		}))
+
+	roundTrip := func(rg string) {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com/foo", nil)
+		req.Header.Set(headers.Range, rg)
+		resp, err := rt.RoundTrip(req)
+		assert.Nil(err)
+		_, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	// The second sequential read prefetches the following ranges within the window.
+	roundTrip("bytes=0-1")
+	roundTrip("bytes=2-3")
+	assert.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(ranges) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch([]nethttp.Range{{Start: 0, Length: 2}, {Start: 2, Length: 2}, {Start: 4, Length: 2}, {Start: 6, Length: 2}}, ranges)
+}
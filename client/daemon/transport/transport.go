@@ -20,12 +20,14 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net"
 	"net/http"
 	"net/http/httputil"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -35,6 +37,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc/status"
 
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
@@ -43,8 +46,11 @@ import (
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/daemon/metrics"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	"d7y.io/dragonfly/v2/client/daemon/signature"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 var _ *logger.SugaredLoggerOnWith // pin this package for no log code generation
@@ -65,6 +71,9 @@ type transport struct {
 	// shouldUseDragonfly is used to determine to download resources with or without dragonfly
 	shouldUseDragonfly func(req *http.Request) bool
 
+	// policy is used to determine how to round trip the requests, it overrides shouldUseDragonfly
+	policy func(req *http.Request) Policy
+
 	// peerTaskManager is the peer task manager
 	peerTaskManager peer.TaskManager
 
@@ -83,9 +92,34 @@ type transport struct {
 	// dumpHTTPContent indicates to dump http request header and response header
 	dumpHTTPContent bool
 
+	// rangeCoalescer coalesces the small range requests of the same url into aligned blocks
+	rangeCoalescer *RangeCoalescer
+
+	// sequentialPrefetcher prefetches the following ranges ahead of the sequential range reads
+	sequentialPrefetcher *SequentialPrefetcher
+
+	// signatureVerifier verifies the signatures of the artifacts before they are responded
+	signatureVerifier signature.Verifier
+
+	// dynconfig provides the application policies requiring the signatures
+	dynconfig config.Dynconfig
+
 	peerIDGenerator peer.IDGenerator
 }
 
+// Policy is how to round trip a request.
+type Policy struct {
+	// UseDragonfly downloads the request with dragonfly
+	UseDragonfly bool
+
+	// CacheOnly serves the request from the local cache only, it works with UseDragonfly,
+	// and 504 is responded when the request is not cached
+	CacheOnly bool
+
+	// Limiter limits the read rate of the response body
+	Limiter *rate.Limiter
+}
+
 // Option is functional config for transport.
 type Option func(rt *transport) *transport
 
@@ -121,6 +155,14 @@ func WithCondition(c func(r *http.Request) bool) Option {
 	}
 }
 
+// WithPolicy configures how to round trip the requests, it overrides WithCondition.
+func WithPolicy(p func(r *http.Request) Policy) Option {
+	return func(rt *transport) *transport {
+		rt.policy = p
+		return rt
+	}
+}
+
 // WithDefaultFilter sets default filter for http requests with X-Dragonfly-Filter Header
 func WithDefaultFilter(f string) Option {
 	return func(rt *transport) *transport {
@@ -161,6 +203,38 @@ func WithDumpHTTPContent(b bool) Option {
 	}
 }
 
+// WithRangeCoalescer sets the range coalescer for transport, nil disables the range coalescing
+func WithRangeCoalescer(c *RangeCoalescer) Option {
+	return func(rt *transport) *transport {
+		rt.rangeCoalescer = c
+		return rt
+	}
+}
+
+// WithSequentialPrefetcher sets the sequential prefetcher for transport, nil disables the sequential prefetching
+func WithSequentialPrefetcher(p *SequentialPrefetcher) Option {
+	return func(rt *transport) *transport {
+		rt.sequentialPrefetcher = p
+		return rt
+	}
+}
+
+// WithSignatureVerifier sets the signature verifier for transport
+func WithSignatureVerifier(v signature.Verifier) Option {
+	return func(rt *transport) *transport {
+		rt.signatureVerifier = v
+		return rt
+	}
+}
+
+// WithDynconfig sets the dynconfig for transport
+func WithDynconfig(d config.Dynconfig) Option {
+	return func(rt *transport) *transport {
+		rt.dynconfig = d
+		return rt
+	}
+}
+
 var tracer trace.Tracer
 
 func init() {
@@ -183,7 +257,14 @@ func New(options ...Option) (http.RoundTripper, error) {
 
 // RoundTrip only process first redirect at present
 func (rt *transport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
-	if rt.shouldUseDragonfly(req) {
+	policy := Policy{}
+	if rt.policy != nil {
+		policy = rt.policy(req)
+	} else {
+		policy.UseDragonfly = rt.shouldUseDragonfly(req)
+	}
+
+	if policy.UseDragonfly {
 		// delete the Accept-Encoding header to avoid returning the same cached
 		// result for different requests
 		req.Header.Del("Accept-Encoding")
@@ -196,7 +277,7 @@ func (rt *transport) RoundTrip(req *http.Request) (resp *http.Response, err erro
 
 		logger.Debugf("round trip with dragonfly: %s", req.URL.String())
 		metrics.ProxyRequestViaDragonflyCount.Add(1)
-		resp, err = rt.download(ctx, req)
+		resp, err = rt.download(ctx, req, policy.CacheOnly)
 	} else {
 		logger.Debugf("round trip directly, method: %s, url: %s", req.Method, req.URL.String())
 		req.Host = req.URL.Host
@@ -214,6 +295,10 @@ func (rt *transport) RoundTrip(req *http.Request) (resp *http.Response, err erro
 		metrics.ProxyRequestBytesCount.WithLabelValues(req.Method).Add(float64(resp.ContentLength))
 	}
 
+	if policy.Limiter != nil && resp.Body != nil {
+		resp.Body = newLimitedReadCloser(req.Context(), resp.Body, policy.Limiter)
+	}
+
 	rt.processDumpHTTPContent(req, resp)
 	return resp, err
 }
@@ -226,7 +311,7 @@ func NeedUseDragonfly(req *http.Request) bool {
 
 // download uses dragonfly to download.
 // the ctx has span info from transport, did not use the ctx from request
-func (rt *transport) download(ctx context.Context, req *http.Request) (*http.Response, error) {
+func (rt *transport) download(ctx context.Context, req *http.Request, cacheOnly bool) (*http.Response, error) {
 	url := req.URL.String()
 	peerID := rt.peerIDGenerator.PeerID()
 
@@ -276,6 +361,27 @@ func (rt *transport) download(ctx context.Context, req *http.Request) (*http.Res
 		priority = commonv1.Priority(priorityInt)
 	}
 
+	// Pick the signature of the artifact, the signature headers are not sent to the origin
+	signatureType, sig := req.Header.Get(config.HeaderDragonflySignatureType), req.Header.Get(config.HeaderDragonflySignature)
+	req.Header.Del(config.HeaderDragonflySignatureType)
+	req.Header.Del(config.HeaderDragonflySignature)
+	hasSignature := signatureType != "" && sig != ""
+	if !hasSignature {
+		required, err := signature.Required(rt.dynconfig, application)
+		if err != nil {
+			log.Errorf("get application policies error: %v", err)
+			return internalServerError(req, fmt.Sprintf("get application policies error: %s", err))
+		}
+
+		if required {
+			log.Infof("application %s requires the signature of the artifact", application)
+			return forbidden(req, fmt.Sprintf("application %s requires the signature of the artifact", application))
+		}
+	} else if rg != nil {
+		// the signature covers the whole artifact
+		return badRequest(req, "range request with signature is not supported")
+	}
+
 	// Delete hop-by-hop headers
 	delHopHeaders(req.Header)
 
@@ -285,16 +391,54 @@ func (rt *transport) download(ctx context.Context, req *http.Request) (*http.Res
 	meta.Application = application
 	meta.Priority = priority
 
+	// Coalesce the small range into the aligned block when the content length is learned
+	var (
+		taskRange    = rg
+		block        *nethttp.Range
+		coalesceKey  string
+		totalLength  int64
+		learnedTotal bool
+		chunked      bool
+	)
+	// the cache only requests are not coalesced, the prefetching blocks are downloaded from the origin
+	if rt.rangeCoalescer != nil && rg != nil && !cacheOnly {
+		coalesceKey = idgen.ParentTaskIDV1(url, meta)
+		if totalLength, learnedTotal = rt.rangeCoalescer.ContentLength(coalesceKey); !learnedTotal {
+			rt.learnContentLength(coalesceKey, req, meta)
+		} else if c, ok := rt.rangeCoalescer.Chunk(coalesceKey, rg); ok {
+			log.Debugf("align range %s to chunks %s", rg.String(), c.String())
+			metrics.ProxyRangeCoalescedCount.Add(1)
+			block, taskRange, chunked = c, c, true
+			meta = cloneURLMeta(meta, c)
+		} else if b, ok := rt.rangeCoalescer.Block(coalesceKey, rg, totalLength); ok {
+			log.Debugf("coalesce range %s into block %s", rg.String(), b.String())
+			metrics.ProxyRangeCoalescedCount.Add(1)
+			block, taskRange = b, b
+			meta = cloneURLMeta(meta, b)
+		}
+	}
+
 	body, attr, err := rt.peerTaskManager.StartStreamTask(
 		ctx,
 		&peer.StreamTaskRequest{
-			URL:     url,
-			URLMeta: meta,
-			Range:   rg,
-			PeerID:  peerID,
+			URL:       url,
+			URLMeta:   meta,
+			Range:     taskRange,
+			PeerID:    peerID,
+			CacheOnly: cacheOnly,
 		},
 	)
 	if err != nil {
+		if errors.Is(err, peer.ErrTaskNotCached) {
+			log.Infof("task is not cached: %s", url)
+			return gatewayTimeout(req, err.Error())
+		}
+
+		if errors.Is(err, types.ErrURLDenied) {
+			log.Infof("url is denied: %s", err)
+			return forbidden(req, err.Error())
+		}
+
 		log.Errorf("start stream task error: %v", err)
 		// check underlay status code
 		if st, ok := status.FromError(err); ok {
@@ -326,6 +470,40 @@ func (rt *transport) download(ctx context.Context, req *http.Request) (*http.Res
 		return nil, err
 	}
 
+	// Verify the whole artifact before it is responded
+	if hasSignature {
+		if body, err = rt.verifyBody(ctx, body, signatureType, sig); err != nil {
+			log.Errorf("verify signature error: %v", err)
+			return forbidden(req, fmt.Sprintf("verify signature error: %s", err))
+		}
+	}
+
+	// Serve the range from the block and prefetch the following blocks
+	if block != nil {
+		if body, err = newRangeReadCloser(body, rg.Start-block.Start, rg.Length); err != nil {
+			log.Errorf("read range %s from block %s error: %v", rg.String(), block.String(), err)
+			return nil, err
+		}
+
+		attr[headers.ContentLength] = strconv.FormatInt(rg.Length, 10)
+		attr[headers.ContentRange] = fmt.Sprintf("bytes %d-%d/%d", rg.Start, rg.Start+rg.Length-1, totalLength)
+		// the chunks of eStargz layer are not aligned to the blocks
+		if !chunked {
+			rt.prefetchBlocks(coalesceKey, url, meta, block, totalLength)
+		}
+	}
+
+	// Prefetch the following ranges ahead of the sequential reads, the coalesced ranges prefetch the following blocks instead
+	if rt.sequentialPrefetcher != nil && rg != nil && block == nil && !cacheOnly {
+		if totalLength <= 0 {
+			totalLength = parseContentRangeTotal(attr[headers.ContentRange])
+		}
+
+		for _, prefetch := range rt.sequentialPrefetcher.Observe(idgen.ParentTaskIDV1(url, meta), rg, totalLength) {
+			go rt.prefetchRange(url, meta, prefetch)
+		}
+	}
+
 	hdr := nethttp.MapToHeader(attr)
 	log.Infof("download stream attribute: %v", hdr)
 
@@ -359,6 +537,52 @@ func (rt *transport) download(ctx context.Context, req *http.Request) (*http.Res
 	return resp, nil
 }
 
+// verifyBody buffers the body into a temporary file and verifies the signature of it,
+// the returned body reads the verified file and removes it when closed.
+func (rt *transport) verifyBody(ctx context.Context, body io.ReadCloser, typ, sig string) (io.ReadCloser, error) {
+	defer body.Close()
+	if rt.signatureVerifier == nil {
+		return nil, errors.New("signature verifier is not configured")
+	}
+
+	file, err := os.CreateTemp("", "dragonfly-signature-*")
+	if err != nil {
+		return nil, err
+	}
+
+	verified := &removeOnCloseFile{file}
+	if _, err := io.Copy(file, body); err != nil {
+		verified.Close()
+		return nil, err
+	}
+
+	if err := rt.signatureVerifier.Verify(ctx, typ, sig, file.Name()); err != nil {
+		verified.Close()
+		return nil, err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		verified.Close()
+		return nil, err
+	}
+
+	return verified, nil
+}
+
+// removeOnCloseFile removes the file when it is closed.
+type removeOnCloseFile struct {
+	*os.File
+}
+
+func (f *removeOnCloseFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); removeErr != nil && err == nil {
+		err = removeErr
+	}
+
+	return err
+}
+
 func (rt *transport) processDumpHTTPContent(req *http.Request, resp *http.Response) {
 	if !rt.dumpHTTPContent {
 		return
@@ -456,3 +680,42 @@ func notImplemented(req *http.Request, body string) (*http.Response, error) {
 func requestedRangeNotSatisfiable(req *http.Request, body string) (*http.Response, error) {
 	return compositeErrorHTTPResponse(req, http.StatusRequestedRangeNotSatisfiable, body)
 }
+
+func forbidden(req *http.Request, body string) (*http.Response, error) {
+	return compositeErrorHTTPResponse(req, http.StatusForbidden, body)
+}
+
+func internalServerError(req *http.Request, body string) (*http.Response, error) {
+	return compositeErrorHTTPResponse(req, http.StatusInternalServerError, body)
+}
+
+func gatewayTimeout(req *http.Request, body string) (*http.Response, error) {
+	return compositeErrorHTTPResponse(req, http.StatusGatewayTimeout, body)
+}
+
+// limitedReadCloser limits the read rate of the response body with limiter.
+type limitedReadCloser struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func newLimitedReadCloser(ctx context.Context, rc io.ReadCloser, limiter *rate.Limiter) io.ReadCloser {
+	return &limitedReadCloser{ReadCloser: rc, ctx: ctx, limiter: limiter}
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	// the size of waiting must not exceed the burst of limiter
+	if burst := l.limiter.Burst(); burst > 0 && len(p) > burst {
+		p = p[:burst]
+	}
+
+	n, err := l.ReadCloser.Read(p)
+	if n > 0 {
+		if waitErr := l.limiter.WaitN(l.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
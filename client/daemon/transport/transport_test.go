@@ -19,16 +19,24 @@ package transport
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	testifyassert "github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
 
+	"d7y.io/dragonfly/v2/client/config"
+	configmocks "d7y.io/dragonfly/v2/client/config/mocks"
 	"d7y.io/dragonfly/v2/client/daemon/peer"
+	signaturemocks "d7y.io/dragonfly/v2/client/daemon/signature/mocks"
 	"d7y.io/dragonfly/v2/client/daemon/test"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 func TestTransport_RoundTrip(t *testing.T) {
@@ -66,3 +74,212 @@ func TestTransport_RoundTrip(t *testing.T) {
 	}
 	assert.Equal(testData, output)
 }
+
+func TestTransport_RoundTripWithPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		mock   func(m *peer.MockTaskManagerMockRecorder)
+		expect func(t *testing.T, resp *http.Response, err error)
+	}{
+		{
+			name:   "cache only request is cached",
+			policy: Policy{UseDragonfly: true, CacheOnly: true},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+						testifyassert.True(t, req.CacheOnly)
+						return io.NopCloser(bytes.NewBufferString("foo")), nil, nil
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				data, err := io.ReadAll(resp.Body)
+				assert.NoError(err)
+				assert.Equal("foo", string(data))
+			},
+		},
+		{
+			name:   "cache only request is not cached",
+			policy: Policy{UseDragonfly: true, CacheOnly: true},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).Return(nil, nil, peer.ErrTaskNotCached)
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(http.StatusGatewayTimeout, resp.StatusCode)
+			},
+		},
+		{
+			name:   "rate limited response body",
+			policy: Policy{UseDragonfly: true, Limiter: rate.NewLimiter(rate.Limit(10), 1)},
+			mock: func(m *peer.MockTaskManagerMockRecorder) {
+				m.StartStreamTask(gomock.Any(), gomock.Any()).Return(io.NopCloser(bytes.NewBufferString("foobar")), nil, nil)
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				start := time.Now()
+				data, err := io.ReadAll(resp.Body)
+				assert.NoError(err)
+				assert.Equal("foobar", string(data))
+				// 6 bytes with 10 bytes per second and 1 burst
+				assert.GreaterOrEqual(time.Since(start), 400*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			peerTaskManager := peer.NewMockTaskManager(ctrl)
+			tc.mock(peerTaskManager.EXPECT())
+
+			rt, err := New(
+				WithPeerIDGenerator(peer.NewPeerIDGenerator("127.0.0.1")),
+				WithPeerTaskManager(peerTaskManager),
+				WithPolicy(func(r *http.Request) Policy {
+					return tc.policy
+				}))
+			testifyassert.NoError(t, err)
+
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://x/y", nil)
+			resp, err := rt.RoundTrip(req)
+			if resp != nil {
+				defer resp.Body.Close()
+			}
+			tc.expect(t, resp, err)
+		})
+	}
+}
+
+func TestTransport_RoundTripWithSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		mock   func(peerTaskManager *peer.MockTaskManagerMockRecorder, verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder)
+		expect func(t *testing.T, resp *http.Response, err error)
+	}{
+		{
+			name: "verify signature",
+			header: http.Header{
+				config.HeaderDragonflySignatureType: []string{types.SignatureTypeCosign},
+				config.HeaderDragonflySignature:     []string{"bar"},
+			},
+			mock: func(peerTaskManager *peer.MockTaskManagerMockRecorder, verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				peerTaskManager.StartStreamTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, req *peer.StreamTaskRequest) (io.ReadCloser, map[string]string, error) {
+						testifyassert.NotContains(t, req.URLMeta.Header, config.HeaderDragonflySignature)
+						return io.NopCloser(bytes.NewBufferString("foo")), nil, nil
+					})
+				verifier.Verify(gomock.Any(), types.SignatureTypeCosign, "bar", gomock.Any()).DoAndReturn(
+					func(ctx context.Context, typ, signature, path string) error {
+						data, err := os.ReadFile(path)
+						testifyassert.NoError(t, err)
+						testifyassert.Equal(t, "foo", string(data))
+						return nil
+					})
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(http.StatusOK, resp.StatusCode)
+				data, err := io.ReadAll(resp.Body)
+				assert.NoError(err)
+				assert.Equal("foo", string(data))
+			},
+		},
+		{
+			name: "verify signature failed",
+			header: http.Header{
+				config.HeaderDragonflySignatureType: []string{types.SignatureTypeCosign},
+				config.HeaderDragonflySignature:     []string{"bar"},
+			},
+			mock: func(peerTaskManager *peer.MockTaskManagerMockRecorder, verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				peerTaskManager.StartStreamTask(gomock.Any(), gomock.Any()).Return(io.NopCloser(bytes.NewBufferString("foo")), nil, nil)
+				verifier.Verify(gomock.Any(), types.SignatureTypeCosign, "bar", gomock.Any()).Return(errors.New("baz"))
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name: "range request with signature",
+			header: http.Header{
+				"Range":                             []string{"bytes=0-1"},
+				config.HeaderDragonflySignatureType: []string{types.SignatureTypeCosign},
+				config.HeaderDragonflySignature:     []string{"bar"},
+			},
+			mock: func(peerTaskManager *peer.MockTaskManagerMockRecorder, verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "application requires signature",
+			header: http.Header{
+				config.HeaderDragonflyApplication: []string{"foo"},
+			},
+			mock: func(peerTaskManager *peer.MockTaskManagerMockRecorder, verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				dynconfig.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{"foo": {RequireSignature: true}}, nil)
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name: "application policies are unavailable",
+			header: http.Header{
+				config.HeaderDragonflyApplication: []string{"foo"},
+			},
+			mock: func(peerTaskManager *peer.MockTaskManagerMockRecorder, verifier *signaturemocks.MockVerifierMockRecorder, dynconfig *configmocks.MockDynconfigMockRecorder) {
+				dynconfig.GetApplicationPolicies().Return(nil, errors.New("bar"))
+			},
+			expect: func(t *testing.T, resp *http.Response, err error) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			peerTaskManager := peer.NewMockTaskManager(ctrl)
+			verifier := signaturemocks.NewMockVerifier(ctrl)
+			dynconfig := configmocks.NewMockDynconfig(ctrl)
+			tc.mock(peerTaskManager.EXPECT(), verifier.EXPECT(), dynconfig.EXPECT())
+
+			rt, err := New(
+				WithPeerIDGenerator(peer.NewPeerIDGenerator("127.0.0.1")),
+				WithPeerTaskManager(peerTaskManager),
+				WithSignatureVerifier(verifier),
+				WithDynconfig(dynconfig),
+				WithCondition(func(r *http.Request) bool {
+					return true
+				}))
+			testifyassert.NoError(t, err)
+
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://x/y", nil)
+			req.Header = tc.header
+			resp, err := rt.RoundTrip(req)
+			if resp != nil {
+				defer resp.Body.Close()
+			}
+			tc.expect(t, resp, err)
+		})
+	}
+}
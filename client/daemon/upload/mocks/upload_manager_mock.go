@@ -48,6 +48,20 @@ func (mr *MockManagerMockRecorder) Serve(lis interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockManager)(nil).Serve), lis)
 }
 
+// ServeQUIC mocks base method.
+func (m *MockManager) ServeQUIC(conn net.PacketConn) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServeQUIC", conn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ServeQUIC indicates an expected call of ServeQUIC.
+func (mr *MockManagerMockRecorder) ServeQUIC(conn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServeQUIC", reflect.TypeOf((*MockManager)(nil).ServeQUIC), conn)
+}
+
 // Stop mocks base method.
 func (m *MockManager) Stop() error {
 	m.ctrl.T.Helper()
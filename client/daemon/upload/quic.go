@@ -0,0 +1,123 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// quicCertValidityPeriod is the validity period of self-signed certificate for quic.
+	quicCertValidityPeriod = 10 * 365 * 24 * time.Hour
+)
+
+// ServeQUIC started upload manager server over quic, peers negotiate quic
+// by the Alt-Svc header of the responses over tcp.
+func (um *uploadManager) ServeQUIC(conn net.PacketConn) error {
+	if um.quicServer == nil {
+		return errors.New("quic is disabled")
+	}
+
+	tlsConfig, err := um.quicTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	um.quicServer.TLSConfig = tlsConfig
+	return um.quicServer.Serve(conn)
+}
+
+// setQUICHeaders announces the quic port by Alt-Svc header for the requests over tcp.
+func (um *uploadManager) setQUICHeaders(ctx *gin.Context) {
+	if um.quicServer != nil && ctx.Request.ProtoMajor < 3 {
+		if err := um.quicServer.SetQuicHeaders(ctx.Writer.Header()); err != nil {
+			logger.Debugf("set quic headers failed: %s", err)
+		}
+	}
+
+	ctx.Next()
+}
+
+// quicTLSConfig returns the tls config of quic, quic requires tls, so a self-signed
+// certificate is used when certify is not enabled, then the same as pieces over plain http,
+// the integrity of pieces is guaranteed by digest.
+func (um *uploadManager) quicTLSConfig() (*tls.Config, error) {
+	if um.certify != nil {
+		return &tls.Config{
+			GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+				// FIXME peers need pure ip cert, certify checks the ServerName, so workaround here
+				hello.ServerName = "peer"
+				return um.certify.GetCertificate(hello)
+			},
+		}, nil
+	}
+
+	cert, err := generateSelfSignedCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{*cert},
+	}, nil
+}
+
+// generateSelfSignedCertificate generates a self-signed certificate.
+func generateSelfSignedCertificate() (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: "peer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(quicCertValidityPeriod),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}, nil
+}
@@ -0,0 +1,96 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upload
+
+import (
+	"sync"
+	"time"
+)
+
+// reservations tracks the upload slots occupied by the in-flight uploads and the reservations of scheduler.
+type reservations struct {
+	// mu protects the fields below.
+	mu sync.Mutex
+
+	// limit is the number of the upload slots.
+	limit int
+
+	// ttl is the time to keep a reservation until the child starts downloading.
+	ttl time.Duration
+
+	// inflight is the number of the in-flight uploads.
+	inflight int
+
+	// expirations are the expiration times of the reservations keyed by child peer id.
+	expirations map[string]time.Time
+}
+
+// newReservations returns a new reservations instance.
+func newReservations(limit int, ttl time.Duration) *reservations {
+	return &reservations{
+		limit:       limit,
+		ttl:         ttl,
+		expirations: make(map[string]time.Time),
+	}
+}
+
+// reserve reserves an upload slot for the child peer, the reservation of the same child is renewed,
+// it is denied when the slots are used up, and returns the number of the free slots after the reservation.
+func (r *reservations) reserve(peerID string, now time.Time) (bool, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(now)
+	if _, ok := r.expirations[peerID]; ok {
+		r.expirations[peerID] = now.Add(r.ttl)
+		return true, r.limit - r.inflight - len(r.expirations)
+	}
+
+	free := r.limit - r.inflight - len(r.expirations)
+	if free <= 0 {
+		return false, 0
+	}
+
+	r.expirations[peerID] = now.Add(r.ttl)
+	return true, free - 1
+}
+
+// acquire occupies an upload slot by the upload to the child peer, and the reservation of the child is consumed.
+func (r *reservations) acquire(peerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inflight++
+	delete(r.expirations, peerID)
+}
+
+// release releases the upload slot occupied by the upload.
+func (r *reservations) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inflight--
+}
+
+// expire deletes the expired reservations.
+func (r *reservations) expire(now time.Time) {
+	for peerID, expiration := range r.expirations {
+		if !expiration.After(now) {
+			delete(r.expirations, peerID)
+		}
+	}
+}
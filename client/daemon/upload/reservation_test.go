@@ -0,0 +1,120 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservations(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		run    func(r *reservations)
+		expect func(t *testing.T, r *reservations)
+	}{
+		{
+			name: "reserve upload slots",
+			run: func(r *reservations) {
+				r.reserve("foo", now)
+			},
+			expect: func(t *testing.T, r *reservations) {
+				assert := assert.New(t)
+				granted, free := r.reserve("bar", now)
+				assert.True(granted)
+				assert.Equal(0, free)
+				granted, free = r.reserve("baz", now)
+				assert.False(granted)
+				assert.Equal(0, free)
+			},
+		},
+		{
+			name: "in-flight uploads occupy upload slots",
+			run: func(r *reservations) {
+				r.acquire("foo")
+				r.acquire("bar")
+			},
+			expect: func(t *testing.T, r *reservations) {
+				assert := assert.New(t)
+				granted, _ := r.reserve("baz", now)
+				assert.False(granted)
+				r.release()
+				granted, _ = r.reserve("baz", now)
+				assert.True(granted)
+			},
+		},
+		{
+			name: "upload consumes the reservation of child",
+			run: func(r *reservations) {
+				r.reserve("foo", now)
+				r.reserve("bar", now)
+				r.acquire("foo")
+			},
+			expect: func(t *testing.T, r *reservations) {
+				assert := assert.New(t)
+				assert.Equal(1, r.inflight)
+				assert.NotContains(r.expirations, "foo")
+				assert.Contains(r.expirations, "bar")
+				r.release()
+				granted, free := r.reserve("baz", now)
+				assert.True(granted)
+				assert.Equal(0, free)
+			},
+		},
+		{
+			name: "reservation of the same child is renewed",
+			run: func(r *reservations) {
+				r.reserve("foo", now)
+				r.reserve("bar", now)
+			},
+			expect: func(t *testing.T, r *reservations) {
+				assert := assert.New(t)
+				granted, free := r.reserve("foo", now.Add(time.Second))
+				assert.True(granted)
+				assert.Equal(0, free)
+				assert.Equal(now.Add(11*time.Second), r.expirations["foo"])
+				assert.Len(r.expirations, 2)
+			},
+		},
+		{
+			name: "reservations expire",
+			run: func(r *reservations) {
+				r.reserve("foo", now)
+				r.reserve("bar", now.Add(time.Second))
+			},
+			expect: func(t *testing.T, r *reservations) {
+				assert := assert.New(t)
+				granted, _ := r.reserve("baz", now.Add(time.Minute))
+				assert.True(granted)
+				assert.Len(r.expirations, 1)
+				assert.NotContains(r.expirations, "foo")
+				assert.NotContains(r.expirations, "bar")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newReservations(2, 10*time.Second)
+			tc.run(r)
+			tc.expect(t, r)
+		})
+	}
+}
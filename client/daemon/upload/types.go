@@ -23,4 +23,10 @@ type DownloadParams struct {
 
 type DownalodQuery struct {
 	PeerID string `form:"peerId" binding:"required"`
+	// SrcPeerID is the id of the child peer downloading the pieces, it consumes the upload slot reservation of the child.
+	SrcPeerID string `form:"srcPeerId"`
+}
+
+type MerkleTreeParams struct {
+	TaskID string `uri:"task_id" binding:"required"`
 }
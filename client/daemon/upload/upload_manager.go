@@ -21,6 +21,8 @@ package upload
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -34,14 +36,17 @@ import (
 	"github.com/go-http-utils/headers"
 	"github.com/johanbrandhorst/certify"
 	ginprometheus "github.com/mcuadros/go-gin-prometheus"
+	"github.com/quic-go/quic-go/http3"
 	"github.com/soheilhy/cmux"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"golang.org/x/time/rate"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 const (
@@ -60,6 +65,9 @@ type Manager interface {
 	// Started upload manager server.
 	Serve(lis net.Listener) error
 
+	// Started upload manager server over quic.
+	ServeQUIC(conn net.PacketConn) error
+
 	// Stop upload manager server.
 	Stop() error
 }
@@ -70,6 +78,22 @@ type uploadManager struct {
 	*rate.Limiter
 	storageManager storage.Manager
 	certify        *certify.Certify
+
+	// quicServer serves pieces over quic, it is nil when quic is disabled.
+	quicServer *http3.Server
+
+	// reservations tracks the upload slots, it is nil when the reservation is disabled.
+	reservations *reservations
+
+	// reservationSigner authenticates the reservations of scheduler.
+	reservationSigner *nethttp.HMACSigner
+
+	// clientCAs verifies the client certificates of the peers downloading pieces over tls.
+	clientCAs *x509.CertPool
+
+	// encryptionRequired returns whether to refuse the pieces downloaded over plain http,
+	// it is nil when the encryption is disabled.
+	encryptionRequired func() bool
 }
 
 // Option is a functional option for configuring the upload manager.
@@ -88,17 +112,37 @@ func WithCertify(ct *certify.Certify) func(manager *uploadManager) {
 	}
 }
 
+// WithEncryption verifies the client certificates issued by the manager ca if clientCAs is not nil, and refuses
+// the pieces downloaded over plain http when required returns true.
+func WithEncryption(clientCAs *x509.CertPool, required func() bool) func(*uploadManager) {
+	return func(manager *uploadManager) {
+		manager.clientCAs = clientCAs
+		manager.encryptionRequired = required
+	}
+}
+
 // New returns a new Manager instence.
 func NewUploadManager(cfg *config.DaemonOption, storageManager storage.Manager, logDir string, opts ...Option) (Manager, error) {
 	um := &uploadManager{
 		storageManager: storageManager,
 	}
 
+	if cfg.Upload.Reservation.Enable {
+		um.reservations = newReservations(cfg.Upload.Reservation.ConcurrentLimit, cfg.Upload.Reservation.TTL)
+		um.reservationSigner = nethttp.NewHMACSigner(cfg.Upload.Reservation.HMACSecret, nethttp.DefaultHMACMaxSkew)
+	}
+
 	router := um.initRouter(cfg, logDir)
 	um.Server = &http.Server{
 		Handler: router,
 	}
 
+	if cfg.Upload.QUIC.Enable {
+		um.quicServer = &http3.Server{
+			Handler: router,
+		}
+	}
+
 	for _, opt := range opts {
 		opt(um)
 	}
@@ -114,7 +158,7 @@ func (um *uploadManager) Serve(listener net.Listener) error {
 
 	logger.Debugf("use http and https uploader in same listener")
 	m := cmux.New(listener)
-	httpListener := m.Match(cmux.HTTP1Fast())
+	httpListener := &readerFromListener{m.Match(cmux.HTTP1Fast())}
 	tlsListener := m.Match(cmux.Any())
 
 	go func() {
@@ -132,6 +176,11 @@ func (um *uploadManager) Serve(listener net.Listener) error {
 			},
 		}
 
+		if um.clientCAs != nil {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+			tlsConfig.ClientCAs = um.clientCAs
+		}
+
 		tlsListener = tls.NewListener(tlsListener, tlsConfig)
 		if err := um.Server.Serve(tlsListener); err != nil {
 			logger.Debugf("upload server exit: %s", err)
@@ -142,6 +191,12 @@ func (um *uploadManager) Serve(listener net.Listener) error {
 
 // Stop upload manager server.
 func (um *uploadManager) Stop() error {
+	if um.quicServer != nil {
+		if err := um.quicServer.Close(); err != nil {
+			logger.Errorf("quic upload server closed failed: %s", err)
+		}
+	}
+
 	return um.Server.Shutdown(context.Background())
 }
 
@@ -180,18 +235,110 @@ func (um *uploadManager) initRouter(cfg *config.DaemonOption, logDir string) *gi
 	// Health Check.
 	r.GET("/healthy", um.getHealth)
 
+	// Upload slot reservation of scheduler.
+	if um.reservations != nil {
+		r.POST(types.UploadReservationPath, um.authenticateReservation, um.createReservation)
+	}
+
+	// Signed merkle tree of the task pieces.
+	if cfg.Download.Integrity.Enable {
+		r.GET(types.UploadMerkleTreePath+"/:task_id", um.getMerkleTree)
+	}
+
 	// Peer download task.
-	d := r.Group(RouterGroupDownload)
+	d := r.Group(RouterGroupDownload, um.requireEncryption, um.setQUICHeaders)
 	d.GET(":task_prefix/:task_id", um.getDownload)
 
 	return r
 }
 
+// requireEncryption refuses the pieces downloaded over plain http when the encryption is required,
+// so the pieces are not exposed on untrusted network segments.
+func (um *uploadManager) requireEncryption(ctx *gin.Context) {
+	if um.encryptionRequired == nil || ctx.Request.TLS != nil || !um.encryptionRequired() {
+		ctx.Next()
+		return
+	}
+
+	logger.Warnf("refuse to upload pieces over plain http to %s", ctx.Request.RemoteAddr)
+	ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"errors": "piece transfer requires tls"})
+}
+
 // getHealth uses to check server health.
 func (um *uploadManager) getHealth(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, http.StatusText(http.StatusOK))
 }
 
+// authenticateReservation authenticates the hmac signature of the reservation signed by scheduler.
+func (um *uploadManager) authenticateReservation(ctx *gin.Context) {
+	if err := um.reservationSigner.Verify(ctx.Request); err != nil {
+		logger.Warnf("authenticate upload reservation from %s error: %s", ctx.Request.RemoteAddr, err.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"errors": err.Error()})
+		return
+	}
+
+	ctx.Next()
+}
+
+// createReservation reserves an upload slot for the child scheduled by scheduler, it is denied
+// when the upload slots are used up or the upload bandwidth is saturated.
+func (um *uploadManager) createReservation(ctx *gin.Context) {
+	var req types.UploadReservationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	// The uploads are waiting for the tokens of the limiter.
+	if um.Limiter != nil && um.Limiter.Limit() != rate.Inf && um.Limiter.Tokens() < 0 {
+		logger.WithTaskAndPeerID(req.TaskID, req.PeerID).Infof("deny upload reservation, upload bandwidth is saturated")
+		ctx.JSON(http.StatusOK, types.UploadReservationResponse{Granted: false})
+		return
+	}
+
+	granted, free := um.reservations.reserve(req.PeerID, time.Now())
+	if !granted {
+		logger.WithTaskAndPeerID(req.TaskID, req.PeerID).Infof("deny upload reservation, upload slots are used up")
+	}
+
+	ctx.JSON(http.StatusOK, types.UploadReservationResponse{Granted: granted, FreeSlots: free})
+}
+
+// getMerkleTree returns the signed merkle tree of the task, the children verify the pieces against it.
+func (um *uploadManager) getMerkleTree(ctx *gin.Context) {
+	var params MerkleTreeParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var query DownalodQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	taskMeta, err := um.storageManager.GetTaskMeta(ctx, &storage.PeerTaskMetadata{TaskID: params.TaskID, PeerID: query.PeerID})
+	if err != nil {
+		if errors.Is(err, storage.ErrTaskNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"errors": err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, gin.H{"errors": err.Error()})
+		return
+	}
+
+	// The discarded merkle tree is left empty in the task meta.
+	tree, ok := taskMeta[integrity.TaskMetaMerkleTree]
+	if !ok || tree == "" {
+		ctx.JSON(http.StatusNotFound, gin.H{"errors": "merkle tree not found"})
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/json", []byte(tree))
+}
+
 // getDownload uses to upload a task file when other peers download from it.
 func (um *uploadManager) getDownload(ctx *gin.Context) {
 	var params DownloadParams
@@ -209,6 +356,11 @@ func (um *uploadManager) getDownload(ctx *gin.Context) {
 	taskID := params.TaskID
 	peerID := query.PeerID
 
+	if um.reservations != nil {
+		um.reservations.acquire(query.SrcPeerID)
+		defer um.reservations.release()
+	}
+
 	log := logger.WithTaskAndPeerID(taskID, peerID).With("component", "uploadManager")
 	log.Debugf("upload piece for task %s/%s to %s, request header: %#v", taskID, peerID, ctx.Request.RemoteAddr, ctx.Request.Header)
 	rg, err := nethttp.ParseRange(ctx.GetHeader(headers.Range), math.MaxInt64)
@@ -257,9 +409,8 @@ func (um *uploadManager) getDownload(ctx *gin.Context) {
 		}
 	}
 
-	// If w is a socket, golang will use sendfile or splice syscall for zero copy feature
-	// when start to transfer data, we could not call http.Error with header.
-	if n, err := io.Copy(ctx.Writer, reader); err != nil {
+	// When start to transfer data, we could not call http.Error with header.
+	if n, err := transfer(ctx.Writer, reader); err != nil {
 		log.Errorf("transfer data failed: %s", err)
 		return
 	} else if n != rg[0].Length {
@@ -268,3 +419,52 @@ func (um *uploadManager) getDownload(ctx *gin.Context) {
 		return
 	}
 }
+
+// transfer copies the piece data to the response. The wrappers of the response writer are unwrapped
+// to the underlying connection, so that golang uses sendfile or splice syscall for zero copy feature
+// when the reader is a file and the connection is a tcp socket, otherwise the data is copied
+// through user space.
+func transfer(w http.ResponseWriter, r io.Reader) (int64, error) {
+	for {
+		if rf, ok := w.(io.ReaderFrom); ok {
+			return rf.ReadFrom(r)
+		}
+
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return io.Copy(w, r)
+		}
+		w = u.Unwrap()
+	}
+}
+
+// readerFromListener restores io.ReaderFrom of the tcp connections wrapped by cmux,
+// cmux only buffers the reading side of the connections, so it is safe to write to them directly.
+type readerFromListener struct {
+	net.Listener
+}
+
+func (l *readerFromListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if mc, ok := conn.(*cmux.MuxConn); ok {
+		if rf, ok := mc.Conn.(io.ReaderFrom); ok {
+			return &readerFromConn{Conn: conn, readerFrom: rf}, nil
+		}
+	}
+
+	return conn, nil
+}
+
+// readerFromConn writes to the underlying connection with io.ReaderFrom.
+type readerFromConn struct {
+	net.Conn
+	readerFrom io.ReaderFrom
+}
+
+func (c *readerFromConn) ReadFrom(r io.Reader) (int64, error) {
+	return c.readerFrom.ReadFrom(r)
+}
@@ -19,22 +19,33 @@ package upload
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/soheilhy/cmux"
 	testifyassert "github.com/stretchr/testify/assert"
 	"golang.org/x/time/rate"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/integrity"
 	"d7y.io/dragonfly/v2/client/daemon/storage"
 	"d7y.io/dragonfly/v2/client/daemon/storage/mocks"
 	"d7y.io/dragonfly/v2/client/daemon/test"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
 	_ "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/server"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 func TestUploadManager_Serve(t *testing.T) {
@@ -104,3 +115,376 @@ func TestUploadManager_Serve(t *testing.T) {
 		assert.Equal(tt.targetPieceData, data)
 	}
 }
+
+func TestUploadManager_createReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name    string
+		limiter *rate.Limiter
+		secret  string
+		body    string
+		expect  func(t *testing.T, code int, resp types.UploadReservationResponse)
+	}{
+		{
+			name:    "reserve upload slot",
+			limiter: rate.NewLimiter(16*1024, 16*1024),
+			secret:  "foo",
+			body:    `{"task_id":"foo","peer_id":"bar"}`,
+			expect: func(t *testing.T, code int, resp types.UploadReservationResponse) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusOK, code)
+				assert.True(resp.Granted)
+				assert.Equal(0, resp.FreeSlots)
+			},
+		},
+		{
+			name: "upload bandwidth is saturated",
+			limiter: func() *rate.Limiter {
+				limiter := rate.NewLimiter(16*1024, 16*1024)
+				limiter.ReserveN(time.Now(), 16*1024)
+				limiter.ReserveN(time.Now(), 16*1024)
+				return limiter
+			}(),
+			secret: "foo",
+			body:   `{"task_id":"foo","peer_id":"bar"}`,
+			expect: func(t *testing.T, code int, resp types.UploadReservationResponse) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusOK, code)
+				assert.False(resp.Granted)
+			},
+		},
+		{
+			name:    "invalid request",
+			limiter: rate.NewLimiter(16*1024, 16*1024),
+			secret:  "foo",
+			body:    `{"task_id":"foo"}`,
+			expect: func(t *testing.T, code int, resp types.UploadReservationResponse) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusUnprocessableEntity, code)
+			},
+		},
+		{
+			name:    "unauthenticated reservation",
+			limiter: rate.NewLimiter(16*1024, 16*1024),
+			secret:  "baz",
+			body:    `{"task_id":"foo","peer_id":"bar"}`,
+			expect: func(t *testing.T, code int, resp types.UploadReservationResponse) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusUnauthorized, code)
+				assert.False(resp.Granted)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.NewDaemonConfig()
+			cfg.Upload.Reservation = config.UploadReservationOption{Enable: true, ConcurrentLimit: 1, TTL: time.Minute, HMACSecret: "foo"}
+			um, err := NewUploadManager(cfg, mocks.NewMockManager(ctrl), os.TempDir(), WithLimiter(tc.limiter))
+			testifyassert.Nil(t, err, "NewUploadManager")
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, types.UploadReservationPath, strings.NewReader(tc.body))
+			if err := nethttp.NewHMACSigner(tc.secret, time.Minute).Sign(req, []byte(tc.body)); err != nil {
+				t.Fatal(err)
+			}
+			um.(*uploadManager).Server.Handler.ServeHTTP(w, req)
+
+			var resp types.UploadReservationResponse
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			tc.expect(t, w.Code, resp)
+		})
+	}
+}
+
+func TestUploadManager_requireEncryption(t *testing.T) {
+	tests := []struct {
+		name     string
+		required func() bool
+		tls      bool
+		expect   func(t *testing.T, aborted bool, code int)
+	}{
+		{
+			name:     "refuse plain http when encryption is required",
+			required: func() bool { return true },
+			expect: func(t *testing.T, aborted bool, code int) {
+				assert := testifyassert.New(t)
+				assert.True(aborted)
+				assert.Equal(http.StatusForbidden, code)
+			},
+		},
+		{
+			name:     "accept tls when encryption is required",
+			required: func() bool { return true },
+			tls:      true,
+			expect: func(t *testing.T, aborted bool, code int) {
+				assert := testifyassert.New(t)
+				assert.False(aborted)
+			},
+		},
+		{
+			name:     "accept plain http when encryption is preferred",
+			required: func() bool { return false },
+			expect: func(t *testing.T, aborted bool, code int) {
+				assert := testifyassert.New(t)
+				assert.False(aborted)
+			},
+		},
+		{
+			name: "accept plain http when encryption is disabled",
+			expect: func(t *testing.T, aborted bool, code int) {
+				assert := testifyassert.New(t)
+				assert.False(aborted)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			um := &uploadManager{encryptionRequired: tc.required}
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, RouterGroupDownload+"/foo/foo", nil)
+			if tc.tls {
+				ctx.Request.TLS = &tls.ConnectionState{}
+			}
+
+			um.requireEncryption(ctx)
+			tc.expect(t, ctx.IsAborted(), w.Code)
+		})
+	}
+}
+
+func TestUploadManager_getMerkleTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name   string
+		url    string
+		mock   func(m *mocks.MockManagerMockRecorder)
+		expect func(t *testing.T, code int, body string)
+	}{
+		{
+			name: "get merkle tree",
+			url:  types.UploadMerkleTreePath + "/foo?peerId=bar",
+			mock: func(m *mocks.MockManagerMockRecorder) {
+				m.GetTaskMeta(gomock.Any(), &storage.PeerTaskMetadata{TaskID: "foo", PeerID: "bar"}).
+					Return(map[string]string{integrity.TaskMetaMerkleTree: `{"task_id":"foo"}`}, nil).Times(1)
+			},
+			expect: func(t *testing.T, code int, body string) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusOK, code)
+				assert.Equal(`{"task_id":"foo"}`, body)
+			},
+		},
+		{
+			name: "merkle tree not signed",
+			url:  types.UploadMerkleTreePath + "/foo?peerId=bar",
+			mock: func(m *mocks.MockManagerMockRecorder) {
+				m.GetTaskMeta(gomock.Any(), gomock.Any()).Return(map[string]string{}, nil).Times(1)
+			},
+			expect: func(t *testing.T, code int, body string) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusNotFound, code)
+			},
+		},
+		{
+			name: "task not found",
+			url:  types.UploadMerkleTreePath + "/foo?peerId=bar",
+			mock: func(m *mocks.MockManagerMockRecorder) {
+				m.GetTaskMeta(gomock.Any(), gomock.Any()).Return(nil, storage.ErrTaskNotFound).Times(1)
+			},
+			expect: func(t *testing.T, code int, body string) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusNotFound, code)
+			},
+		},
+		{
+			name: "missing peer id",
+			url:  types.UploadMerkleTreePath + "/foo",
+			mock: func(m *mocks.MockManagerMockRecorder) {},
+			expect: func(t *testing.T, code int, body string) {
+				assert := testifyassert.New(t)
+				assert.Equal(http.StatusUnprocessableEntity, code)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			storageManager := mocks.NewMockManager(ctrl)
+			tc.mock(storageManager.EXPECT())
+
+			cfg := config.NewDaemonConfig()
+			cfg.Download.Integrity.Enable = true
+			um, err := NewUploadManager(cfg, storageManager, os.TempDir())
+			testifyassert.Nil(t, err, "NewUploadManager")
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			um.(*uploadManager).Server.Handler.ServeHTTP(w, req)
+			tc.expect(t, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestUploadManager_ServeQUIC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	assert := testifyassert.New(t)
+	testData, err := os.ReadFile(test.File)
+	assert.Nil(err, "load test file")
+
+	mockStorageManager := mocks.NewMockManager(ctrl)
+	mockStorageManager.EXPECT().ReadPiece(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(ctx context.Context, req *storage.ReadPieceRequest) (io.Reader, io.Closer, error) {
+			return bytes.NewBuffer(testData[req.Range.Start : req.Range.Start+req.Range.Length]),
+				io.NopCloser(nil), nil
+		})
+
+	cfg := config.NewDaemonConfig()
+	cfg.Upload.QUIC.Enable = true
+	um, err := NewUploadManager(cfg, mockStorageManager, os.TempDir())
+	assert.Nil(err, "NewUploadManager")
+	defer um.Stop()
+
+	listen, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.Nil(err, "Listen")
+	addr := listen.Addr().String()
+
+	conn, err := net.ListenPacket("udp4", addr)
+	assert.Nil(err, "ListenPacket")
+
+	go um.Serve(listen)
+	go um.ServeQUIC(conn)
+
+	// Wait for the quic listener announced by Alt-Svc header.
+	var altSvc string
+	assert.Eventually(func() bool {
+		resp, err := http.Get(fmt.Sprintf("http://%s/download/666/task-0?peerId=peer-0", addr))
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+
+		altSvc = resp.Header.Get("Alt-Svc")
+		return altSvc != ""
+	}, 5*time.Second, 100*time.Millisecond)
+	assert.Contains(altSvc, fmt.Sprintf(`h3=":%d"`, conn.LocalAddr().(*net.UDPAddr).Port))
+
+	roundTripper := &http3.RoundTripper{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	defer roundTripper.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("https://%s/download/666/task-0?peerId=peer-0", addr), nil)
+	req.Header.Add("Range", "bytes=0-9")
+	resp, err := (&http.Client{Transport: roundTripper}).Do(req)
+	assert.Nil(err, "get piece data over quic")
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	assert.Equal(3, resp.ProtoMajor)
+	assert.Equal(testData[0:10], data)
+}
+
+type readerFromResponseWriter struct {
+	*httptest.ResponseRecorder
+	readFrom bool
+}
+
+func (w *readerFromResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.readFrom = true
+	return io.Copy(w.ResponseRecorder, r)
+}
+
+type unwrapResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *unwrapResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func TestTransfer(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(t *testing.T)
+	}{
+		{
+			name: "response writer implements io.ReaderFrom",
+			expect: func(t *testing.T) {
+				assert := testifyassert.New(t)
+				w := &readerFromResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+				n, err := transfer(w, bytes.NewBufferString("foo"))
+				assert.Nil(err)
+				assert.Equal(int64(3), n)
+				assert.True(w.readFrom)
+				assert.Equal("foo", w.Body.String())
+			},
+		},
+		{
+			name: "unwrap response writer to io.ReaderFrom",
+			expect: func(t *testing.T) {
+				assert := testifyassert.New(t)
+				w := &readerFromResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+				n, err := transfer(&unwrapResponseWriter{&unwrapResponseWriter{w}}, bytes.NewBufferString("foo"))
+				assert.Nil(err)
+				assert.Equal(int64(3), n)
+				assert.True(w.readFrom)
+				assert.Equal("foo", w.Body.String())
+			},
+		},
+		{
+			name: "copy through user space",
+			expect: func(t *testing.T) {
+				assert := testifyassert.New(t)
+				w := httptest.NewRecorder()
+				n, err := transfer(&unwrapResponseWriter{w}, bytes.NewBufferString("foo"))
+				assert.Nil(err)
+				assert.Equal(int64(3), n)
+				assert.Equal("foo", w.Body.String())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t)
+		})
+	}
+}
+
+func TestReaderFromListener(t *testing.T) {
+	assert := testifyassert.New(t)
+	listen, err := net.Listen("tcp4", "127.0.0.1:0")
+	assert.Nil(err, "Listen")
+
+	m := cmux.New(listen)
+	l := &readerFromListener{m.Match(cmux.Any())}
+	go m.Serve()
+	defer m.Close()
+
+	conn, err := net.Dial("tcp4", listen.Addr().String())
+	assert.Nil(err, "Dial")
+	defer conn.Close()
+
+	accepted, err := l.Accept()
+	assert.Nil(err, "Accept")
+	defer accepted.Close()
+
+	_, ok := accepted.(io.ReaderFrom)
+	assert.True(ok)
+
+	n, err := accepted.(io.ReaderFrom).ReadFrom(bytes.NewBufferString("foo"))
+	assert.Nil(err)
+	assert.Equal(int64(3), n)
+
+	data := make([]byte, 3)
+	_, err = io.ReadFull(conn, data)
+	assert.Nil(err)
+	assert.Equal("foo", string(data))
+}
@@ -18,27 +18,65 @@ package dfcache
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
 	dfdaemonv1 "d7y.io/api/v2/pkg/apis/dfdaemon/v1"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/stream"
 	"d7y.io/dragonfly/v2/internal/dferrors"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 // Format that's used to cast the given cid to URI.
 // "d7y" as scheme, and absolute path starts with "/"
 const cidURIFormat = "d7y:/%s"
 
-func newCid(cid string) string {
-	return fmt.Sprintf(cidURIFormat, url.QueryEscape(cid))
+// Format that's used to cast the given cid in namespace to URI,
+// the namespace is escaped, so it is separated from cid by the first "/".
+const namespacedCidURIFormat = "d7y:/%s/%s"
+
+func newCid(namespace, cid string) string {
+	if namespace == "" {
+		return fmt.Sprintf(cidURIFormat, url.QueryEscape(cid))
+	}
+
+	return fmt.Sprintf(namespacedCidURIFormat, url.QueryEscape(namespace), url.QueryEscape(cid))
+}
+
+// parseCid parses the namespace and cid from URI.
+func parseCid(uri string) (string, string) {
+	path := strings.TrimPrefix(uri, "d7y:/")
+	namespace, cid, ok := strings.Cut(path, "/")
+	if !ok {
+		namespace, cid = "", path
+	}
+
+	if unescaped, err := url.QueryUnescape(namespace); err == nil {
+		namespace = unescaped
+	}
+
+	if unescaped, err := url.QueryUnescape(cid); err == nil {
+		cid = unescaped
+	}
+
+	return namespace, cid
 }
 
 // Stat checks if the given cache entry exists in local storage and/or in P2P network, and returns
@@ -100,7 +138,7 @@ func statTask(ctx context.Context, client dfdaemonclient.V1, cfg *config.Dfcache
 
 func newStatRequest(cfg *config.DfcacheConfig) *dfdaemonv1.StatTaskRequest {
 	return &dfdaemonv1.StatTaskRequest{
-		Url: newCid(cfg.Cid),
+		Url: newCid(cfg.Namespace, cfg.Cid),
 		UrlMeta: &commonv1.UrlMeta{
 			Tag: cfg.Tag,
 		},
@@ -148,7 +186,17 @@ func importTask(ctx context.Context, client dfdaemonclient.V1, cfg *config.Dfcac
 	}
 
 	start := time.Now()
-	importError := client.ImportTask(ctx, newImportRequest(cfg))
+	var importError error
+	switch {
+	case cfg.Path == config.StdinInput:
+		importError = importStream(ctx, cfg, os.Stdin, -1)
+	case cfg.Namespace != "" || len(cfg.Labels) > 0 || cfg.TTL > 0:
+		// The namespace, labels and ttl are stored in task meta by the streaming import api.
+		importError = importFile(ctx, cfg)
+	default:
+		// The replication policy of the persistent cache is carried by metadata.
+		importError = client.ImportTask(rpc.ContextWithReplication(ctx, newReplication(cfg)), newImportRequest(cfg))
+	}
 	if importError != nil {
 		wLog.Errorf("daemon import file error: %s", importError)
 		return importError
@@ -158,10 +206,17 @@ func importTask(ctx context.Context, client dfdaemonclient.V1, cfg *config.Dfcac
 	return nil
 }
 
+func newReplication(cfg *config.DfcacheConfig) types.Replication {
+	return types.Replication{
+		Replicas: cfg.Replicas,
+		SpreadBy: cfg.SpreadBy,
+	}
+}
+
 func newImportRequest(cfg *config.DfcacheConfig) *dfdaemonv1.ImportTaskRequest {
 	return &dfdaemonv1.ImportTaskRequest{
 		Type: commonv1.TaskType_DfCache,
-		Url:  newCid(cfg.Cid),
+		Url:  newCid(cfg.Namespace, cfg.Cid),
 		Path: cfg.Path,
 		UrlMeta: &commonv1.UrlMeta{
 			Tag: cfg.Tag,
@@ -210,7 +265,13 @@ func exportTask(ctx context.Context, client dfdaemonclient.V1, cfg *config.Dfcac
 	}
 
 	start := time.Now()
-	exportError := client.ExportTask(ctx, newExportRequest(cfg))
+	var exportError error
+	if cfg.Output == config.StdoutOutput {
+		// os.Stdout may be redirected for messages, write the content to the real stdout.
+		exportError = exportStream(ctx, client, cfg, os.NewFile(uintptr(syscall.Stdout), "/dev/stdout"))
+	} else {
+		exportError = client.ExportTask(ctx, newExportRequest(cfg))
+	}
 	if exportError == nil {
 		wLog.Infof("task exported successfully in %.6f s", time.Since(start).Seconds())
 		return nil
@@ -228,7 +289,7 @@ func exportTask(ctx context.Context, client dfdaemonclient.V1, cfg *config.Dfcac
 
 func newExportRequest(cfg *config.DfcacheConfig) *dfdaemonv1.ExportTaskRequest {
 	return &dfdaemonv1.ExportTaskRequest{
-		Url:     newCid(cfg.Cid),
+		Url:     newCid(cfg.Namespace, cfg.Cid),
 		Output:  cfg.Output,
 		Timeout: uint64(cfg.Timeout),
 		Limit:   float64(cfg.RateLimit),
@@ -241,6 +302,109 @@ func newExportRequest(cfg *config.DfcacheConfig) *dfdaemonv1.ExportTaskRequest {
 	}
 }
 
+// importFile imports the file with the streaming import api.
+func importFile(ctx context.Context, cfg *config.DfcacheConfig) error {
+	f, err := os.Open(cfg.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	return importStream(ctx, cfg, f, stat.Size())
+}
+
+// importStream imports the content of reader piece by piece with the streaming import api over the daemon unix socket,
+// the content is not buffered in temporary files. When the content length is less than 0, the request body is sent
+// with chunked encoding.
+func importStream(ctx context.Context, cfg *config.DfcacheConfig, reader io.Reader, contentLength int64) error {
+	query := &stream.ImportQuery{
+		URL:       newCid(cfg.Namespace, cfg.Cid),
+		Tag:       cfg.Tag,
+		Namespace: cfg.Namespace,
+		Labels:    formatLabels(cfg.Labels),
+		TTL:       cfg.TTL,
+		Replicas:  cfg.Replicas,
+		SpreadBy:  cfg.SpreadBy,
+	}
+
+	// Hide the type of reader, so the content length is not detected by http client.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, fmt.Sprintf("http://unix%s?%s", stream.PathImport, query.Encode()), io.MultiReader(reader))
+	if err != nil {
+		return err
+	}
+
+	if contentLength >= 0 {
+		req.ContentLength = contentLength
+	}
+
+	resp, err := newStreamClient(cfg.DaemonSock).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("bad response status %s: %s", resp.Status, msg)
+	}
+
+	return nil
+}
+
+// exportStream writes the pieces of the cache in order to w with the streaming download api over the daemon unix socket.
+func exportStream(ctx context.Context, client dfdaemonclient.V1, cfg *config.DfcacheConfig, w io.Writer) error {
+	// Stat the task first, the cache is not downloaded from source when it does not exist in P2P network.
+	if err := client.StatTask(ctx, newStatRequest(cfg)); err != nil {
+		return err
+	}
+
+	query := &stream.DownloadQuery{
+		URL:       newCid(cfg.Namespace, cfg.Cid),
+		Tag:       cfg.Tag,
+		LocalOnly: cfg.LocalOnly,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://unix%s?%s", stream.PathDownload, query.Encode()), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := newStreamClient(cfg.DaemonSock).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return dferrors.New(commonv1.Code_PeerTaskNotFound, "task not found in local storage")
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("bad response status %s: %s", resp.Status, msg)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// newStreamClient returns the http client of the streaming api over the daemon unix socket.
+func newStreamClient(daemonSock string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", daemonSock)
+			},
+		},
+	}
+}
+
 func Delete(cfg *config.DfcacheConfig, client dfdaemonclient.V1) error {
 	var (
 		ctx         = context.Background()
@@ -280,6 +444,18 @@ func deleteTask(ctx context.Context, client dfdaemonclient.V1, cfg *config.Dfcac
 	}
 
 	start := time.Now()
+	if cfg.Cid == "" {
+		caches, err := requestCaches(ctx, cfg, http.MethodDelete)
+		if err != nil {
+			wLog.Errorf("daemon delete caches error: %s", err)
+			return err
+		}
+
+		wLog.Infof("%d caches deleted successfully in %.6f s", len(caches), time.Since(start).Seconds())
+		fmt.Printf("%d caches deleted\n", len(caches))
+		return nil
+	}
+
 	deleteError := client.DeleteTask(ctx, newDeleteRequest(cfg))
 	if deleteError != nil {
 		wLog.Errorf("daemon delete file error: %s", deleteError)
@@ -292,9 +468,92 @@ func deleteTask(ctx context.Context, client dfdaemonclient.V1, cfg *config.Dfcac
 
 func newDeleteRequest(cfg *config.DfcacheConfig) *dfdaemonv1.DeleteTaskRequest {
 	return &dfdaemonv1.DeleteTaskRequest{
-		Url: newCid(cfg.Cid),
+		Url: newCid(cfg.Namespace, cfg.Cid),
 		UrlMeta: &commonv1.UrlMeta{
 			Tag: cfg.Tag,
 		},
 	}
 }
+
+// List lists the caches imported with namespace, labels or ttl in local storage, the caches are filtered
+// by namespace and labels.
+func List(cfg *config.DfcacheConfig, w io.Writer) error {
+	var (
+		ctx    = context.Background()
+		cancel context.CancelFunc
+	)
+
+	if err := cfg.Validate(config.CmdList); err != nil {
+		return fmt.Errorf("validate list option failed: %w", err)
+	}
+
+	if cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	caches, err := requestCaches(ctx, cfg, http.MethodGet)
+	if err != nil {
+		logger.Errorf("daemon list caches error: %s", err)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tCID\tTAG\tSIZE\tLABELS\tEXPIRES AT")
+	for _, cache := range caches {
+		namespace, cid := parseCid(cache.URL)
+		expiresAt := "-"
+		if cache.ExpiresAt != nil {
+			expiresAt = cache.ExpiresAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", namespace, cid, cache.Tag, cache.ContentLength,
+			strings.Join(formatLabels(cache.Labels), ","), expiresAt)
+	}
+
+	return tw.Flush()
+}
+
+// requestCaches lists or deletes the caches with the cache api over the daemon unix socket.
+func requestCaches(ctx context.Context, cfg *config.DfcacheConfig, method string) ([]*stream.Cache, error) {
+	query := &stream.CacheQuery{
+		Namespace: cfg.Namespace,
+		Labels:    formatLabels(cfg.Labels),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("http://unix%s?%s", stream.PathCaches, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := newStreamClient(cfg.DaemonSock).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("bad response status %s: %s", resp.Status, msg)
+	}
+
+	var caches []*stream.Cache
+	if err := json.NewDecoder(resp.Body).Decode(&caches); err != nil {
+		return nil, err
+	}
+
+	return caches, nil
+}
+
+// formatLabels formats the labels as "key=value" sorted by key.
+func formatLabels(labels map[string]string) []string {
+	var s []string
+	for k, v := range labels {
+		s = append(s, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	sort.Strings(s)
+	return s
+}
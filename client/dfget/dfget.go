@@ -21,24 +21,31 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gammazero/deque"
 	"github.com/go-http-utils/headers"
 	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
 
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
 	dfdaemonv1 "d7y.io/api/v2/pkg/apis/dfdaemon/v1"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/stream"
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
 	"d7y.io/dragonfly/v2/pkg/source"
 	pkgstrings "d7y.io/dragonfly/v2/pkg/strings"
@@ -78,12 +85,30 @@ func download(ctx context.Context, client dfdaemonclient.V1, cfg *config.DfgetCo
 	if cfg.Recursive {
 		return recursiveDownload(ctx, client, cfg)
 	}
+
+	if cfg.InputFile != "" {
+		return manifestDownload(ctx, client, cfg)
+	}
 	return singleDownload(ctx, client, cfg, wLog)
 }
 
 func singleDownload(ctx context.Context, client dfdaemonclient.V1, cfg *config.DfgetConfig, wLog *logger.SugaredLoggerOnWith) error {
 	hdr := parseHeader(cfg.Header)
 
+	if cfg.Stream {
+		return streamDownload(ctx, cfg, hdr, wLog)
+	}
+
+	// The artifact is verified by the daemon only, so the download with the signature
+	// fails closed instead of falling back to download from source.
+	if cfg.Signature != "" {
+		if client == nil {
+			return errors.New("signature verification requires dfdaemon")
+		}
+
+		ctx = rpc.ContextWithSignature(ctx, cfg.SignatureType, cfg.Signature)
+	}
+
 	if client == nil {
 		return downloadFromSource(ctx, cfg, hdr)
 	}
@@ -93,6 +118,7 @@ func singleDownload(ctx context.Context, client dfdaemonclient.V1, cfg *config.D
 		stream    dfdaemonv1.Daemon_DownloadClient
 		result    *dfdaemonv1.DownResult
 		pb        *progressbar.ProgressBar
+		jp        = newJSONProgress(ctx, cfg)
 		request   = newDownRequest(cfg, hdr)
 		downError error
 	)
@@ -111,6 +137,11 @@ func singleDownload(ctx context.Context, client dfdaemonclient.V1, cfg *config.D
 				_ = pb.Set64(int64(result.CompletedLength))
 			}
 
+			if jp != nil {
+				jp.SetTask(result.TaskId, result.PeerId)
+				jp.Update(int64(result.CompletedLength), result.Done)
+			}
+
 			// success
 			if result.Done {
 				if pb != nil {
@@ -126,7 +157,7 @@ func singleDownload(ctx context.Context, client dfdaemonclient.V1, cfg *config.D
 		}
 	}
 
-	if downError != nil && !cfg.KeepOriginalOffset {
+	if downError != nil && !cfg.KeepOriginalOffset && cfg.Signature == "" {
 		wLog.Warnf("daemon downloads file error: %v", downError)
 		fmt.Printf("daemon downloads file error: %v\n", downError)
 		downError = downloadFromSource(ctx, cfg, hdr)
@@ -185,7 +216,15 @@ func downloadFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[st
 		return err
 	}
 
-	if written, err = io.Copy(tempFile, response.Body); err != nil {
+	var w io.Writer = tempFile
+	jp := newJSONProgress(ctx, cfg)
+	if jp != nil {
+		jp.SetBackToSource()
+		jp.SetContentLength(response.ContentLength)
+		w = io.MultiWriter(w, jp)
+	}
+
+	if written, err = io.Copy(w, response.Body); err != nil {
 		return err
 	}
 
@@ -215,12 +254,217 @@ func downloadFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[st
 	}
 	renameOK = true
 
+	if jp != nil {
+		jp.Update(written, true)
+	}
+
 	wLog.Infof("download from source success, length: %d bytes cost: %d ms", written, time.Since(start).Milliseconds())
 	fmt.Printf("finish total length %d bytes\n", written)
 
 	return nil
 }
 
+// streamDownload writes the verified pieces from daemon in order to the output as they arrive,
+// it falls back to the source only when nothing has been written.
+func streamDownload(ctx context.Context, cfg *config.DfgetConfig, hdr map[string]string, wLog *logger.SugaredLoggerOnWith) (err error) {
+	var (
+		start    = time.Now()
+		w        io.Writer
+		tempFile *os.File
+		written  int64
+	)
+
+	if cfg.Output == config.StdoutOutput {
+		// os.Stdout may be redirected for messages, write the content to the real stdout.
+		w = os.NewFile(uintptr(syscall.Stdout), "/dev/stdout")
+	} else if cfg.OutputPipe {
+		var pipe *os.File
+		if pipe, err = openOutputPipe(cfg.Output); err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := pipe.Close(); cerr != nil && !errors.Is(cerr, os.ErrClosed) {
+				err = errors.Join(err, cerr)
+			}
+		}()
+
+		// The pipe is written without buffering, the write blocks when the reader is slow,
+		// and the blocked read of the response slows down the daemon.
+		w = pipe
+	} else {
+		if tempFile, err = os.CreateTemp(filepath.Dir(cfg.Output), ".df_"); err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := tempFile.Close(); cerr != nil && !errors.Is(cerr, os.ErrClosed) {
+				err = errors.Join(err, cerr)
+			}
+			if err != nil {
+				_ = os.Remove(tempFile.Name())
+			}
+		}()
+		w = tempFile
+	}
+
+	if written, err = streamFromDaemon(ctx, cfg, hdr, w); err != nil {
+		wLog.Warnf("daemon streams file error: %v", err)
+		fmt.Printf("daemon streams file error: %v\n", err)
+		// The reader of the pipe has gone away, there is no need to back to source.
+		if written > 0 || cfg.KeepOriginalOffset || errors.Is(err, syscall.EPIPE) {
+			return err
+		}
+
+		if written, err = streamFromSource(ctx, cfg, hdr, w); err != nil {
+			return err
+		}
+	}
+
+	if tempFile != nil {
+		if err = os.Chown(tempFile.Name(), os.Getuid(), os.Getgid()); err != nil {
+			return fmt.Errorf("change file owner to uid[%d] gid[%d]: %w", os.Getuid(), os.Getgid(), err)
+		}
+
+		if err = os.Rename(tempFile.Name(), cfg.Output); err != nil {
+			return err
+		}
+	}
+
+	wLog.Infof("stream download success, length: %d bytes cost: %d ms", written, time.Since(start).Milliseconds())
+	fmt.Printf("finish total length %d bytes\n", written)
+
+	return nil
+}
+
+// openOutputPipe opens the file descriptor or the named pipe for writing,
+// opening the named pipe blocks until the reader opens it.
+func openOutputPipe(output string) (*os.File, error) {
+	if fd, ok := strings.CutPrefix(output, config.FdOutputPrefix); ok {
+		n, err := strconv.Atoi(fd)
+		if err != nil {
+			return nil, err
+		}
+
+		return os.NewFile(uintptr(n), output), nil
+	}
+
+	return os.OpenFile(output, os.O_WRONLY, 0)
+}
+
+// streamFromDaemon requests the streaming download api over the daemon unix socket.
+func streamFromDaemon(ctx context.Context, cfg *config.DfgetConfig, hdr map[string]string, w io.Writer) (int64, error) {
+	query := &stream.DownloadQuery{
+		URL:         cfg.URL,
+		Tag:         cfg.Tag,
+		Application: cfg.Application,
+		Filter:      cfg.Filter,
+		Digest:      cfg.Digest,
+		Range:       cfg.Range,
+		Header:      cfg.Header,
+		Priority:    cfg.Priority,
+	}
+	if r, ok := hdr[headers.Range]; ok {
+		query.Range = strings.TrimPrefix(r, "bytes=")
+	}
+
+	httpClient := newDaemonHTTPClient(cfg.DaemonSock)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://unix%s?%s", stream.PathDownload, query.Encode()), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("bad response status %s: %s", resp.Status, msg)
+	}
+
+	if cfg.ShowProgress && cfg.Output != config.StdoutOutput {
+		pb := newProgressBar(resp.ContentLength)
+		defer pb.Close()
+		w = io.MultiWriter(w, pb)
+	}
+
+	jp := newJSONProgress(ctx, cfg)
+	if jp != nil {
+		jp.SetTask(resp.Header.Get(config.HeaderDragonflyTask), resp.Header.Get(config.HeaderDragonflyPeer))
+		jp.SetContentLength(resp.ContentLength)
+		w = io.MultiWriter(w, jp)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err == nil && jp != nil {
+		jp.Update(n, true)
+	}
+
+	return n, err
+}
+
+// newDaemonHTTPClient returns the http client over the daemon unix socket.
+func newDaemonHTTPClient(daemonSock string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", daemonSock)
+			},
+		},
+	}
+}
+
+// streamFromSource streams the content from the source when the daemon is not available.
+func streamFromSource(ctx context.Context, cfg *config.DfgetConfig, hdr map[string]string, w io.Writer) (int64, error) {
+	if cfg.DisableBackSource {
+		return 0, errors.New("try to download from source but back source is disabled")
+	}
+
+	fmt.Println("try to stream from source and ignore rate limit")
+	request, err := source.NewRequestWithContext(ctx, cfg.URL, hdr)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := source.Download(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if err := response.Validate(); err != nil {
+		return 0, err
+	}
+
+	var r io.Reader = response.Body
+	if !pkgstrings.IsBlank(cfg.Digest) {
+		d, err := digest.Parse(cfg.Digest)
+		if err != nil {
+			return 0, err
+		}
+
+		if r, err = digest.NewReader(d.Algorithm, r, digest.WithEncoded(d.Encoded)); err != nil {
+			return 0, err
+		}
+	}
+
+	jp := newJSONProgress(ctx, cfg)
+	if jp != nil {
+		jp.SetBackToSource()
+		jp.SetContentLength(response.ContentLength)
+		w = io.MultiWriter(w, jp)
+	}
+
+	n, err := io.Copy(w, r)
+	if err == nil && jp != nil {
+		jp.Update(n, true)
+	}
+
+	return n, err
+}
+
 func parseHeader(s []string) map[string]string {
 	hdr := make(map[string]string)
 	var key, value string
@@ -324,14 +568,17 @@ func rejectRegex(u string, reject string) bool {
 	return regexp.MustCompile(reject).Match([]byte(u))
 }
 
-// recursiveDownload breadth-first download all resources
+// recursiveDownload breadth-first lists all resources, then downloads the files concurrently
 func recursiveDownload(ctx context.Context, client dfdaemonclient.V1, cfg *config.DfgetConfig) error {
 	// if recursive level is 0, skip recursive level check
 	var skipLevel bool
 	if cfg.RecursiveLevel == 0 {
 		skipLevel = true
 	}
-	var queue deque.Deque[*config.DfgetConfig]
+	var (
+		queue    deque.Deque[*config.DfgetConfig]
+		children []*config.DfgetConfig
+	)
 	queue.PushBack(cfg)
 	downloadMap := map[url.URL]struct{}{}
 	for {
@@ -363,6 +610,7 @@ func recursiveDownload(ctx context.Context, client dfdaemonclient.V1, cfg *confi
 		for _, urlEntry := range urlEntries {
 			childCfg := *parentCfg //create new cfg
 			childCfg.Output = path.Join(parentCfg.Output, urlEntry.Name)
+			relPath := strings.TrimPrefix(strings.TrimPrefix(childCfg.Output, cfg.Output), "/")
 			fmt.Printf("%s\n", strings.TrimPrefix(childCfg.Output, cfg.Output))
 			u := urlEntry.URL
 			childCfg.URL = u.String()
@@ -372,12 +620,22 @@ func recursiveDownload(ctx context.Context, client dfdaemonclient.V1, cfg *confi
 				continue
 			}
 
+			if matchPatterns(relPath, childCfg.RecursiveExclude) {
+				logger.Infof("path %s is excluded, skip", relPath)
+				continue
+			}
+
 			if urlEntry.IsDir {
 				logger.Infof("download directory %s to %s", childCfg.URL, childCfg.Output)
 				queue.PushBack(&childCfg)
 				continue
 			}
 
+			if len(childCfg.RecursiveInclude) > 0 && !matchPatterns(relPath, childCfg.RecursiveInclude) {
+				logger.Infof("path %s is not included, skip", relPath)
+				continue
+			}
+
 			if childCfg.RecursiveList {
 				continue
 			}
@@ -387,11 +645,61 @@ func recursiveDownload(ctx context.Context, client dfdaemonclient.V1, cfg *confi
 				logger.Errorf("validate failed: %s", err)
 				return err
 			}
+			children = append(children, &childCfg)
+		}
+	}
+
+	return concurrentDownload(ctx, client, cfg, children)
+}
+
+// concurrentDownload downloads the files as separate tasks concurrently with a combined progress bar
+func concurrentDownload(ctx context.Context, client dfdaemonclient.V1, cfg *config.DfgetConfig, children []*config.DfgetConfig) error {
+	if len(children) == 0 {
+		return nil
+	}
+
+	var pb *progressbar.ProgressBar
+	if cfg.ShowProgress {
+		pb = progressbar.NewOptions(len(children),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetDescription("Downloading"),
+			progressbar.OptionFullWidth())
+		defer pb.Close()
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(cfg.RecursiveConcurrent, 1))
+	for _, childCfg := range children {
+		childCfg := childCfg
+		// the combined progress bar takes place of the progress bar of every file
+		childCfg.ShowProgress = false
+		g.Go(func() error {
 			logger.Infof("download file %s to %s", childCfg.URL, childCfg.Output)
-			if err = singleDownload(ctx, client, &childCfg, logger.With("url", childCfg.URL)); err != nil {
+			if err := singleDownload(ctx, client, childCfg, logger.With("url", childCfg.URL)); err != nil {
 				return err
 			}
+
+			if pb != nil {
+				_ = pb.Add(1)
+			}
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// matchPatterns reports whether any glob pattern matches the relative path or the name
+func matchPatterns(relPath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, relPath); ok {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern, path.Base(relPath)); ok {
+			return true
 		}
 	}
-	return nil
+
+	return false
 }
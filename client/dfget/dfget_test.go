@@ -18,18 +18,25 @@ package dfget
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 
 	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/util"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/digest"
 	"d7y.io/dragonfly/v2/pkg/source"
 	"d7y.io/dragonfly/v2/pkg/source/mocks"
@@ -61,6 +68,184 @@ func Test_downloadFromSource(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func Test_streamDownload(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	assert.Nil(t, err)
+	output := filepath.Join(homeDir, uuid.New().String())
+	defer os.Remove(output)
+
+	content := uuid.New().String()
+	sourceClient := mocks.NewMockResourceClient(gomock.NewController(t))
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	// daemon socket is not available, so it streams from source.
+	cfg := &config.DfgetConfig{
+		URL:        "http://a.b.c/xx",
+		Output:     output,
+		Digest:     strings.Join([]string{digest.AlgorithmSHA256, digest.SHA256FromStrings(content)}, ":"),
+		DaemonSock: filepath.Join(t.TempDir(), "dfdaemon.sock"),
+		Stream:     true,
+	}
+	sourceClient.EXPECT().Download(gomock.Any()).Return(source.NewResponse(io.NopCloser(strings.NewReader(content))), nil)
+
+	err = streamDownload(context.Background(), cfg, nil, logger.With("url", cfg.URL))
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(output)
+	assert.Nil(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func Test_streamDownloadToPipe(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "pipe")
+	require.Nil(t, syscall.Mkfifo(output, 0600))
+
+	content := uuid.New().String()
+	sourceClient := mocks.NewMockResourceClient(gomock.NewController(t))
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	cfg := &config.DfgetConfig{
+		URL:        "http://a.b.c/xx",
+		Output:     output,
+		DaemonSock: filepath.Join(t.TempDir(), "dfdaemon.sock"),
+		Stream:     true,
+		OutputPipe: true,
+	}
+	sourceClient.EXPECT().Download(gomock.Any()).Return(source.NewResponse(io.NopCloser(strings.NewReader(content))), nil)
+
+	received := make(chan []byte)
+	go func() {
+		f, err := os.Open(output)
+		if err != nil {
+			close(received)
+			return
+		}
+		defer f.Close()
+
+		data, _ := io.ReadAll(f)
+		received <- data
+	}()
+
+	err := streamDownload(context.Background(), cfg, nil, logger.With("url", cfg.URL))
+	assert.Nil(t, err)
+	assert.Equal(t, content, string(<-received))
+
+	// The named pipe is written in place without renaming.
+	info, err := os.Stat(output)
+	assert.Nil(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeNamedPipe)
+}
+
+func Test_recursiveDownload(t *testing.T) {
+	ctl := gomock.NewController(t)
+	sourceClient := &listableResourceClient{
+		MockResourceClient: mocks.NewMockResourceClient(ctl),
+		MockResourceLister: mocks.NewMockResourceLister(ctl),
+	}
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	newEntry := func(rawURL string, isDir bool) source.URLEntry {
+		u, err := url.Parse(rawURL)
+		require.Nil(t, err)
+		return source.URLEntry{URL: u, Name: path.Base(u.Path), IsDir: isDir}
+	}
+	sourceClient.MockResourceLister.EXPECT().List(gomock.Any()).DoAndReturn(func(request *source.Request) ([]source.URLEntry, error) {
+		switch request.URL.Path {
+		case "/dir/":
+			return []source.URLEntry{
+				newEntry("http://a.b.c/dir/a.bin", false),
+				newEntry("http://a.b.c/dir/b.tmp", false),
+				newEntry("http://a.b.c/dir/c.txt", false),
+				newEntry("http://a.b.c/dir/sub/", true),
+				newEntry("http://a.b.c/dir/skip/", true),
+			}, nil
+		case "/dir/sub/":
+			return []source.URLEntry{newEntry("http://a.b.c/dir/sub/d.bin", false)}, nil
+		}
+		return nil, fmt.Errorf("unexpected list %s", request.URL)
+	}).Times(2)
+	sourceClient.MockResourceClient.EXPECT().Download(gomock.Any()).DoAndReturn(func(request *source.Request) (*source.Response, error) {
+		return source.NewResponse(io.NopCloser(strings.NewReader(request.URL.Path))), nil
+	}).Times(2)
+
+	output := t.TempDir()
+	cfg := &config.DfgetConfig{
+		URL:                 "http://a.b.c/dir/",
+		Output:              output,
+		Recursive:           true,
+		RecursiveInclude:    []string{"*.bin"},
+		RecursiveExclude:    []string{"*.tmp", "skip"},
+		RecursiveConcurrent: 2,
+		ShowProgress:        true,
+		RateLimit:           util.RateLimit{Limit: rate.Limit(config.DefaultTotalDownloadLimit)},
+	}
+	assert.Nil(t, recursiveDownload(context.Background(), nil, cfg))
+
+	for _, name := range []string{"a.bin", "sub/d.bin"} {
+		data, err := os.ReadFile(filepath.Join(output, name))
+		assert.Nil(t, err)
+		assert.Equal(t, "/dir/"+name, string(data))
+	}
+
+	for _, name := range []string{"b.tmp", "c.txt"} {
+		_, err := os.Stat(filepath.Join(output, name))
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+type listableResourceClient struct {
+	*mocks.MockResourceClient
+	*mocks.MockResourceLister
+}
+
+func Test_matchPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		relPath  string
+		patterns []string
+		expect   bool
+	}{
+		{
+			name:     "match name",
+			relPath:  "sub/a.bin",
+			patterns: []string{"*.txt", "*.bin"},
+			expect:   true,
+		},
+		{
+			name:     "match relative path",
+			relPath:  "sub/a.bin",
+			patterns: []string{"sub/*"},
+			expect:   true,
+		},
+		{
+			name:     "not match",
+			relPath:  "sub/a.bin",
+			patterns: []string{"*.txt"},
+			expect:   false,
+		},
+		{
+			name:    "empty patterns",
+			relPath: "sub/a.bin",
+			expect:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, matchPatterns(tc.relPath, tc.patterns))
+		})
+	}
+}
+
 func Test_parseHeader(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,238 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"golang.org/x/sync/errgroup"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+)
+
+// manifestEntry is the url and its options in the manifest.
+type manifestEntry struct {
+	URL    string   `json:"url"`
+	Output string   `json:"output,omitempty"`
+	Digest string   `json:"digest,omitempty"`
+	Tag    string   `json:"tag,omitempty"`
+	Header []string `json:"header,omitempty"`
+}
+
+// manifestResult is the downloading result of the url in the manifest.
+type manifestResult struct {
+	URL     string `json:"url"`
+	Output  string `json:"output"`
+	Success bool   `json:"success"`
+	Length  int64  `json:"length"`
+	CostMS  int64  `json:"costMS"`
+	Error   string `json:"error,omitempty"`
+}
+
+// manifestReport is the machine-readable report of the manifest downloading.
+type manifestReport struct {
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []*manifestResult `json:"results"`
+}
+
+// parseManifest parses the json manifest with per-url options, or the text manifest
+// with one url and the optional output per line, the empty lines and the lines start with # are skipped.
+func parseManifest(r io.Reader) ([]*manifestEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*manifestEntry
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			fields := strings.Fields(line)
+			entry := &manifestEntry{URL: fields[0]}
+			if len(fields) > 1 {
+				entry.Output = fields[1]
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for i, entry := range entries {
+		if entry.URL == "" {
+			return nil, fmt.Errorf("url of entry %d is empty", i)
+		}
+	}
+
+	return entries, nil
+}
+
+// manifestDownload downloads the urls in the manifest concurrently with a combined progress bar,
+// the failed urls do not interrupt the others and all results are written to the report.
+func manifestDownload(ctx context.Context, client dfdaemonclient.V1, cfg *config.DfgetConfig) error {
+	f, err := os.Open(cfg.InputFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entries, err := parseManifest(f)
+	if err != nil {
+		return fmt.Errorf("parse manifest %s: %w", cfg.InputFile, err)
+	}
+
+	var pb *progressbar.ProgressBar
+	if cfg.ShowProgress {
+		pb = progressbar.NewOptions(len(entries),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetDescription("Downloading"),
+			progressbar.OptionFullWidth())
+		defer pb.Close()
+	}
+
+	results := make([]*manifestResult, len(entries))
+	g := errgroup.Group{}
+	g.SetLimit(max(cfg.InputConcurrent, 1))
+	for i, entry := range entries {
+		i, childCfg := i, newManifestConfig(cfg, entry)
+		g.Go(func() error {
+			results[i] = downloadManifestEntry(ctx, client, childCfg)
+			if pb != nil {
+				_ = pb.Add(1)
+			}
+
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	report := &manifestReport{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.Success {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+
+	if cfg.Report != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(cfg.Report, data, 0644); err != nil {
+			return fmt.Errorf("write report %s: %w", cfg.Report, err)
+		}
+	}
+
+	fmt.Printf("finish downloading %d urls, succeeded: %d, failed: %d\n", report.Total, report.Succeeded, report.Failed)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d urls failed to download", report.Failed, report.Total)
+	}
+
+	return nil
+}
+
+// newManifestConfig overrides the options of the manifest downloading with the entry,
+// the relative output is placed under the output directory.
+func newManifestConfig(cfg *config.DfgetConfig, entry *manifestEntry) *config.DfgetConfig {
+	childCfg := *cfg
+	childCfg.InputFile = ""
+	childCfg.URL = entry.URL
+	// the combined progress bar takes place of the progress bar of every file
+	childCfg.ShowProgress = false
+
+	output := entry.Output
+	if output == "" {
+		u := strings.TrimRight(entry.URL, "/")
+		output = u[strings.LastIndexByte(u, '/')+1:]
+	}
+
+	if filepath.IsAbs(output) {
+		childCfg.Output = output
+	} else {
+		childCfg.Output = filepath.Join(cfg.Output, output)
+	}
+
+	if entry.Digest != "" {
+		childCfg.Digest = entry.Digest
+		childCfg.Tag = ""
+	}
+
+	if entry.Tag != "" {
+		childCfg.Tag = entry.Tag
+	}
+
+	childCfg.Header = append(slices.Clone(cfg.Header), entry.Header...)
+	return &childCfg
+}
+
+// downloadManifestEntry downloads the url in the manifest and returns the result.
+func downloadManifestEntry(ctx context.Context, client dfdaemonclient.V1, cfg *config.DfgetConfig) *manifestResult {
+	var (
+		start  = time.Now()
+		result = &manifestResult{URL: cfg.URL, Output: cfg.Output}
+		err    error
+	)
+
+	defer func() {
+		result.CostMS = time.Since(start).Milliseconds()
+		if err != nil {
+			logger.Errorf("download %s to %s failed: %s", cfg.URL, cfg.Output, err)
+			result.Error = err.Error()
+		}
+	}()
+
+	if err = cfg.Validate(); err != nil {
+		return result
+	}
+
+	logger.Infof("download file %s to %s", cfg.URL, cfg.Output)
+	if err = singleDownload(ctx, client, cfg, logger.With("url", cfg.URL)); err != nil {
+		return result
+	}
+
+	info, err := os.Stat(cfg.Output)
+	if err != nil {
+		return result
+	}
+
+	result.Success = true
+	result.Length = info.Size()
+	return result
+}
@@ -0,0 +1,161 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/source/mocks"
+)
+
+func Test_parseManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expect   func(t *testing.T, entries []*manifestEntry, err error)
+	}{
+		{
+			name:     "text manifest",
+			manifest: "# images\nhttp://a.b.c/x\n\n  http://a.b.c/y  y.bin\n",
+			expect: func(t *testing.T, entries []*manifestEntry, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal([]*manifestEntry{
+					{URL: "http://a.b.c/x"},
+					{URL: "http://a.b.c/y", Output: "y.bin"},
+				}, entries)
+			},
+		},
+		{
+			name:     "json manifest",
+			manifest: `[{"url": "http://a.b.c/x", "output": "/tmp/x", "digest": "sha256:foo", "tag": "bar", "header": ["Accept: *"]}]`,
+			expect: func(t *testing.T, entries []*manifestEntry, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal([]*manifestEntry{
+					{URL: "http://a.b.c/x", Output: "/tmp/x", Digest: "sha256:foo", Tag: "bar", Header: []string{"Accept: *"}},
+				}, entries)
+			},
+		},
+		{
+			name:     "json manifest is invalid",
+			manifest: `[{"url": 1}]`,
+			expect: func(t *testing.T, entries []*manifestEntry, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+		{
+			name:     "url is empty",
+			manifest: `[{"output": "x"}]`,
+			expect: func(t *testing.T, entries []*manifestEntry, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "url of entry 0 is empty")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := parseManifest(strings.NewReader(tc.manifest))
+			tc.expect(t, entries, err)
+		})
+	}
+}
+
+func Test_newManifestConfig(t *testing.T) {
+	cfg := &config.DfgetConfig{
+		Output:    "/data",
+		Tag:       "foo",
+		Header:    []string{"Accept: *"},
+		InputFile: "/tmp/urls.txt",
+	}
+
+	childCfg := newManifestConfig(cfg, &manifestEntry{URL: "http://a.b.c/x/", Digest: "sha256:bar", Header: []string{"Host: abc"}})
+	assert := assert.New(t)
+	assert.Equal("/data/x", childCfg.Output)
+	assert.Equal("", childCfg.Tag)
+	assert.Equal("", childCfg.InputFile)
+	assert.Equal([]string{"Accept: *", "Host: abc"}, childCfg.Header)
+	assert.Equal([]string{"Accept: *"}, cfg.Header)
+
+	childCfg = newManifestConfig(cfg, &manifestEntry{URL: "http://a.b.c/x", Output: "/tmp/y"})
+	assert.Equal("/tmp/y", childCfg.Output)
+	assert.Equal("foo", childCfg.Tag)
+}
+
+func Test_manifestDownload(t *testing.T) {
+	dir := t.TempDir()
+	inputFile := filepath.Join(dir, "urls.txt")
+	require.Nil(t, os.WriteFile(inputFile, []byte("http://a.b.c/x\nhttp://a.b.c/y\n"), 0644))
+
+	sourceClient := mocks.NewMockResourceClient(gomock.NewController(t))
+	require.Nil(t, source.Register("http", sourceClient, func(request *source.Request) *source.Request {
+		return request
+	}))
+	defer source.UnRegister("http")
+
+	sourceClient.EXPECT().Download(gomock.Any()).DoAndReturn(func(request *source.Request) (*source.Response, error) {
+		if strings.HasSuffix(request.URL.Path, "y") {
+			return nil, errors.New("foo")
+		}
+
+		return source.NewResponse(io.NopCloser(strings.NewReader("bar"))), nil
+	}).Times(2)
+
+	cfg := &config.DfgetConfig{
+		Output:          filepath.Join(dir, "output"),
+		InputFile:       inputFile,
+		InputConcurrent: 2,
+		Report:          filepath.Join(dir, "report.json"),
+	}
+	cfg.RateLimit.Limit = 20971520
+
+	err := manifestDownload(context.Background(), nil, cfg)
+	assert := assert.New(t)
+	assert.EqualError(err, "1 of 2 urls failed to download")
+
+	data, err := os.ReadFile(filepath.Join(dir, "output", "x"))
+	assert.NoError(err)
+	assert.Equal("bar", string(data))
+
+	data, err = os.ReadFile(cfg.Report)
+	assert.NoError(err)
+
+	var report manifestReport
+	assert.NoError(json.Unmarshal(data, &report))
+	assert.Equal(2, report.Total)
+	assert.Equal(1, report.Succeeded)
+	assert.Equal(1, report.Failed)
+	assert.True(report.Results[0].Success)
+	assert.Equal(int64(3), report.Results[0].Length)
+	assert.False(report.Results[1].Success)
+	assert.Equal("foo", report.Results[1].Error)
+}
@@ -0,0 +1,204 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/stream"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// jsonProgressInterval is the minimum interval between the progress events of the url.
+	jsonProgressInterval = time.Second
+
+	// jsonProgressQueryTimeout is the timeout of querying the traffic breakdown from daemon.
+	jsonProgressQueryTimeout = 500 * time.Millisecond
+)
+
+var (
+	// jsonProgressOutput is the real stdout, os.Stdout is redirected to stderr for messages.
+	jsonProgressOutput io.Writer = os.NewFile(uintptr(syscall.Stdout), "/dev/stdout")
+
+	// jsonProgressLock serializes the events of the urls downloading concurrently.
+	jsonProgressLock sync.Mutex
+)
+
+// progressEvent is the json progress event of the url.
+type progressEvent struct {
+	Time                time.Time `json:"time"`
+	URL                 string    `json:"url"`
+	TaskID              string    `json:"taskID,omitempty"`
+	PeerID              string    `json:"peerID,omitempty"`
+	ContentLength       int64     `json:"contentLength"`
+	CompletedLength     int64     `json:"completedLength"`
+	P2PTraffic          uint64    `json:"p2pTraffic"`
+	SeedPeerTraffic     uint64    `json:"seedPeerTraffic"`
+	BackToSourceTraffic uint64    `json:"backToSourceTraffic"`
+	Speed               int64     `json:"speed"`
+	ETA                 int64     `json:"eta"`
+	Done                bool      `json:"done"`
+}
+
+// jsonProgress writes the newline-delimited json progress events of the url,
+// it also implements io.Writer to track the progress of the written content.
+type jsonProgress struct {
+	ctx          context.Context
+	httpClient   *http.Client
+	backToSource bool
+	start        time.Time
+	lastTime     time.Time
+	lastLength   int64
+	event        progressEvent
+}
+
+// newJSONProgress returns the json progress of the url when the progress mode is json, otherwise returns nil.
+func newJSONProgress(ctx context.Context, cfg *config.DfgetConfig) *jsonProgress {
+	if cfg.Progress != config.ProgressJSON {
+		return nil
+	}
+
+	now := time.Now()
+	return &jsonProgress{
+		ctx:        ctx,
+		httpClient: newDaemonHTTPClient(cfg.DaemonSock),
+		start:      now,
+		lastTime:   now,
+		event: progressEvent{
+			URL:           cfg.URL,
+			ContentLength: -1,
+		},
+	}
+}
+
+// SetTask sets the task and peer in daemon, the traffic breakdown is queried from daemon.
+func (p *jsonProgress) SetTask(taskID, peerID string) {
+	p.event.TaskID = taskID
+	p.event.PeerID = peerID
+}
+
+// SetContentLength sets the content length, -1 is unknown.
+func (p *jsonProgress) SetContentLength(contentLength int64) {
+	p.event.ContentLength = contentLength
+}
+
+// SetBackToSource marks all traffic is downloaded from the source directly.
+func (p *jsonProgress) SetBackToSource() {
+	p.backToSource = true
+}
+
+// Write tracks the length of the written content.
+func (p *jsonProgress) Write(b []byte) (int, error) {
+	p.Update(p.event.CompletedLength+int64(len(b)), false)
+	return len(b), nil
+}
+
+// Update writes the event when the interval is reached or the url is done.
+func (p *jsonProgress) Update(completedLength int64, done bool) {
+	p.event.CompletedLength = completedLength
+	now := time.Now()
+	if !done && now.Sub(p.lastTime) < jsonProgressInterval {
+		return
+	}
+
+	if p.backToSource {
+		p.event.BackToSourceTraffic = uint64(completedLength)
+	} else if p.event.TaskID != "" {
+		p.queryDaemon()
+	}
+
+	// The speed of the done event is the average speed.
+	if done {
+		p.event.Speed = speed(completedLength, now.Sub(p.start))
+	} else {
+		p.event.Speed = speed(completedLength-p.lastLength, now.Sub(p.lastTime))
+	}
+
+	p.event.ETA = -1
+	if done {
+		p.event.ETA = 0
+	} else if p.event.ContentLength >= 0 && p.event.Speed > 0 {
+		p.event.ETA = (p.event.ContentLength - completedLength) / p.event.Speed
+	}
+
+	p.event.Time = now
+	p.event.Done = done
+	p.lastTime = now
+	p.lastLength = completedLength
+
+	jsonProgressLock.Lock()
+	defer jsonProgressLock.Unlock()
+	if err := json.NewEncoder(jsonProgressOutput).Encode(&p.event); err != nil {
+		logger.Warnf("write progress event error: %s", err)
+	}
+}
+
+// queryDaemon queries the traffic breakdown of the running task from daemon,
+// the last breakdown is kept when the task is not running.
+func (p *jsonProgress) queryDaemon() {
+	ctx, cancel := context.WithTimeout(p.ctx, jsonProgressQueryTimeout)
+	defer cancel()
+
+	query := &stream.ProgressQuery{TaskID: p.event.TaskID, PeerID: p.event.PeerID}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://unix%s?%s", stream.PathProgress, query.Encode()), nil)
+	if err != nil {
+		return
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		logger.Debugf("query progress error: %s", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var progress stream.Progress
+	if err := json.NewDecoder(resp.Body).Decode(&progress); err != nil {
+		logger.Debugf("decode progress error: %s", err)
+		return
+	}
+
+	if progress.ContentLength >= 0 {
+		p.event.ContentLength = progress.ContentLength
+	}
+	p.event.P2PTraffic = progress.P2PTraffic
+	p.event.SeedPeerTraffic = progress.SeedPeerTraffic
+	p.event.BackToSourceTraffic = progress.BackToSourceTraffic
+}
+
+// speed returns the bytes per second.
+func speed(length int64, cost time.Duration) int64 {
+	if cost <= 0 {
+		return 0
+	}
+
+	return int64(float64(length) / cost.Seconds())
+}
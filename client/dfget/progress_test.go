@@ -0,0 +1,148 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/daemon/stream"
+)
+
+func Test_jsonProgress(t *testing.T) {
+	daemonSock := filepath.Join(t.TempDir(), "dfdaemon.sock")
+	listener, err := net.Listen("unix", daemonSock)
+	require.Nil(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(stream.PathProgress, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("task_id") != "task" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(&stream.Progress{
+			ContentLength:       10,
+			CompletedLength:     10,
+			P2PTraffic:          4,
+			SeedPeerTraffic:     5,
+			BackToSourceTraffic: 1,
+		})
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		cfg    *config.DfgetConfig
+		run    func(jp *jsonProgress)
+		expect func(t *testing.T, events []*progressEvent)
+	}{
+		{
+			name: "progress is not json",
+			cfg:  &config.DfgetConfig{URL: "http://a.b.c/x"},
+			expect: func(t *testing.T, events []*progressEvent) {
+				assert.Len(t, events, 0)
+			},
+		},
+		{
+			name: "traffic breakdown from daemon",
+			cfg:  &config.DfgetConfig{URL: "http://a.b.c/x", DaemonSock: daemonSock, Progress: config.ProgressJSON},
+			run: func(jp *jsonProgress) {
+				jp.SetTask("task", "peer")
+				jp.Update(5, false)
+				jp.Update(10, true)
+			},
+			expect: func(t *testing.T, events []*progressEvent) {
+				assert := assert.New(t)
+				assert.Len(events, 1)
+				assert.Equal("http://a.b.c/x", events[0].URL)
+				assert.Equal("task", events[0].TaskID)
+				assert.Equal(int64(10), events[0].ContentLength)
+				assert.Equal(int64(10), events[0].CompletedLength)
+				assert.Equal(uint64(4), events[0].P2PTraffic)
+				assert.Equal(uint64(5), events[0].SeedPeerTraffic)
+				assert.Equal(uint64(1), events[0].BackToSourceTraffic)
+				assert.Equal(int64(0), events[0].ETA)
+				assert.True(events[0].Done)
+			},
+		},
+		{
+			name: "task is not running in daemon",
+			cfg:  &config.DfgetConfig{URL: "http://a.b.c/x", DaemonSock: daemonSock, Progress: config.ProgressJSON},
+			run: func(jp *jsonProgress) {
+				jp.SetTask("foo", "peer")
+				jp.Update(10, true)
+			},
+			expect: func(t *testing.T, events []*progressEvent) {
+				assert := assert.New(t)
+				assert.Len(events, 1)
+				assert.Equal(int64(-1), events[0].ContentLength)
+				assert.Equal(uint64(0), events[0].P2PTraffic)
+			},
+		},
+		{
+			name: "back to source",
+			cfg:  &config.DfgetConfig{URL: "http://a.b.c/x", DaemonSock: daemonSock, Progress: config.ProgressJSON},
+			run: func(jp *jsonProgress) {
+				jp.SetBackToSource()
+				jp.SetContentLength(6)
+				_, _ = jp.Write([]byte("foo"))
+				_, _ = jp.Write([]byte("bar"))
+				jp.Update(6, true)
+			},
+			expect: func(t *testing.T, events []*progressEvent) {
+				assert := assert.New(t)
+				assert.Len(events, 1)
+				assert.Equal(int64(6), events[0].ContentLength)
+				assert.Equal(int64(6), events[0].CompletedLength)
+				assert.Equal(uint64(6), events[0].BackToSourceTraffic)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			jsonProgressOutput = output
+
+			jp := newJSONProgress(context.Background(), tc.cfg)
+			if jp != nil {
+				tc.run(jp)
+			}
+
+			var events []*progressEvent
+			decoder := json.NewDecoder(output)
+			for decoder.More() {
+				event := &progressEvent{}
+				require.Nil(t, decoder.Decode(event))
+				events = append(events, event)
+			}
+			tc.expect(t, events)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	dfdaemonv1 "d7y.io/api/v2/pkg/apis/dfdaemon/v1"
+
+	"d7y.io/dragonfly/v2/client/config"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	neturl "d7y.io/dragonfly/v2/pkg/net/url"
+	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+)
+
+// uploadURLFormat is the format of the url generated by the sha256 and the name of the uploaded file,
+// the same content is always uploaded to the same task.
+const uploadURLFormat = "d7y://upload/%s/%s"
+
+// UploadResult is the result of uploading the local file.
+type UploadResult struct {
+	// URL is the url to download the uploaded file.
+	URL string
+
+	// TaskID is the id of the task.
+	TaskID string
+}
+
+// Upload imports the local file into the storage of daemon and announces it to the scheduler,
+// others download the file with the url and the tag of the result in P2P network.
+func Upload(cfg *config.DfgetConfig, client dfdaemonclient.V1, file string) (*UploadResult, error) {
+	var (
+		ctx    = context.Background()
+		cancel context.CancelFunc
+		start  = time.Now()
+	)
+
+	if client == nil {
+		return nil, errors.New("upload has no daemon client")
+	}
+
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("file %s is not a regular file", file)
+	}
+
+	rawURL := cfg.URL
+	if rawURL == "" {
+		encoded, err := digest.HashFile(file, digest.AlgorithmSHA256)
+		if err != nil {
+			return nil, err
+		}
+
+		rawURL = fmt.Sprintf(uploadURLFormat, encoded, url.PathEscape(filepath.Base(file)))
+	}
+
+	if !neturl.IsValid(rawURL) {
+		return nil, fmt.Errorf("invalid url %s", rawURL)
+	}
+
+	urlMeta := &commonv1.UrlMeta{
+		Tag:         cfg.Tag,
+		Application: cfg.Application,
+	}
+	result := &UploadResult{
+		URL:    rawURL,
+		TaskID: idgen.TaskIDV1(rawURL, urlMeta),
+	}
+
+	wLog := logger.With("url", rawURL, "task", result.TaskID, "file", file)
+	wLog.Info("init success and start to upload")
+
+	if cfg.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	if err := client.ImportTask(ctx, &dfdaemonv1.ImportTaskRequest{
+		Type:    commonv1.TaskType_Normal,
+		Url:     rawURL,
+		Path:    file,
+		UrlMeta: urlMeta,
+	}); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("upload timeout(%s)", cfg.Timeout)
+		}
+
+		wLog.Errorf("daemon imports file error: %s", err)
+		return nil, err
+	}
+
+	wLog.Infof("upload success, length: %d bytes cost: %d ms", info.Size(), time.Since(start).Milliseconds())
+	return result, nil
+}
@@ -0,0 +1,128 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dfget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	dfdaemonv1 "d7y.io/api/v2/pkg/apis/dfdaemon/v1"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client/mocks"
+)
+
+func TestUpload(t *testing.T) {
+	content := "foo"
+	file := filepath.Join(t.TempDir(), "foo bar.txt")
+	assert.Nil(t, os.WriteFile(file, []byte(content), 0644))
+	generatedURL := fmt.Sprintf("d7y://upload/%s/foo%%20bar.txt", digest.SHA256FromStrings(content))
+
+	tests := []struct {
+		name   string
+		cfg    *config.DfgetConfig
+		file   string
+		mock   func(m *mocks.MockV1MockRecorder)
+		expect func(t *testing.T, result *UploadResult, err error)
+	}{
+		{
+			name: "upload with generated url",
+			cfg:  &config.DfgetConfig{Tag: "bar"},
+			file: file,
+			mock: func(m *mocks.MockV1MockRecorder) {
+				m.ImportTask(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, req *dfdaemonv1.ImportTaskRequest, _ ...grpc.CallOption) error {
+						assert.Equal(t, generatedURL, req.Url)
+						assert.Equal(t, file, req.Path)
+						assert.Equal(t, "bar", req.UrlMeta.Tag)
+						return nil
+					})
+			},
+			expect: func(t *testing.T, result *UploadResult, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(generatedURL, result.URL)
+				assert.Equal(idgen.TaskIDV1(generatedURL, &commonv1.UrlMeta{Tag: "bar"}), result.TaskID)
+			},
+		},
+		{
+			name: "upload with url",
+			cfg:  &config.DfgetConfig{URL: "d7y://artifacts/foo"},
+			file: file,
+			mock: func(m *mocks.MockV1MockRecorder) {
+				m.ImportTask(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expect: func(t *testing.T, result *UploadResult, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("d7y://artifacts/foo", result.URL)
+			},
+		},
+		{
+			name: "url is invalid",
+			cfg:  &config.DfgetConfig{URL: "d7y:/foo"},
+			file: file,
+			mock: func(m *mocks.MockV1MockRecorder) {},
+			expect: func(t *testing.T, result *UploadResult, err error) {
+				assert.EqualError(t, err, "invalid url d7y:/foo")
+			},
+		},
+		{
+			name: "file is a directory",
+			cfg:  &config.DfgetConfig{},
+			file: filepath.Dir(file),
+			mock: func(m *mocks.MockV1MockRecorder) {},
+			expect: func(t *testing.T, result *UploadResult, err error) {
+				assert.EqualError(t, err, fmt.Sprintf("file %s is not a regular file", filepath.Dir(file)))
+			},
+		},
+		{
+			name: "import task failed",
+			cfg:  &config.DfgetConfig{},
+			file: file,
+			mock: func(m *mocks.MockV1MockRecorder) {
+				m.ImportTask(gomock.Any(), gomock.Any()).Return(errors.New("foo"))
+			},
+			expect: func(t *testing.T, result *UploadResult, err error) {
+				assert.EqualError(t, err, "foo")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			client := mocks.NewMockV1(ctl)
+			tc.mock(client.EXPECT())
+			result, err := Upload(tc.cfg, client, tc.file)
+			tc.expect(t, result, err)
+		})
+	}
+}
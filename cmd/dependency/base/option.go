@@ -16,9 +16,12 @@
 
 package base
 
+import "time"
+
 type Options struct {
 	Console   bool            `yaml:"console" mapstructure:"console"`
 	Verbose   bool            `yaml:"verbose" mapstructure:"verbose"`
+	LogFormat string          `yaml:"log-format" mapstructure:"log-format"`
 	PProfPort int             `yaml:"pprof-port" mapstructure:"pprof-port"`
 	Telemetry TelemetryOption `yaml:",inline" mapstructure:",squash"`
 }
@@ -27,4 +30,13 @@ type Options struct {
 type TelemetryOption struct {
 	Jaeger      string `yaml:"jaeger" mapstructure:"jaeger"`
 	ServiceName string `yaml:"service-name" mapstructure:"service-name"`
+
+	// OTLPMetrics is the grpc endpoint of the OpenTelemetry collector to push metrics, like: localhost:4317.
+	OTLPMetrics string `yaml:"otlp-metrics" mapstructure:"otlp-metrics"`
+
+	// OTLPMetricsInterval is the interval of pushing metrics to the OpenTelemetry collector.
+	OTLPMetricsInterval time.Duration `yaml:"otlp-metrics-interval" mapstructure:"otlp-metrics-interval"`
+
+	// OTLPMetricsInsecure disables the transport security of pushing metrics.
+	OTLPMetricsInsecure bool `yaml:"otlp-metrics-insecure" mapstructure:"otlp-metrics-insecure"`
 }
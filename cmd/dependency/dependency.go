@@ -68,8 +68,12 @@ func InitCommandAndConfig(cmd *cobra.Command, useConfigFile bool, config any) {
 		flags := cmd.PersistentFlags()
 		flags.Bool("console", false, "whether logger output records to the stdout")
 		flags.Bool("verbose", false, "whether logger use debug level")
+		flags.String("log-format", "", "logger output format, text or json, file logs use json and console logs use text by default")
 		flags.Int("pprof-port", -1, "listen port for pprof, 0 represents random port")
 		flags.String("jaeger", "", "jaeger endpoint url, like: http://localhost:14250/api/traces")
+		flags.String("otlp-metrics", "", "otlp grpc endpoint of the OpenTelemetry collector to push metrics, like: localhost:4317")
+		flags.Duration("otlp-metrics-interval", 30*time.Second, "interval of pushing metrics to the OpenTelemetry collector")
+		flags.Bool("otlp-metrics-insecure", false, "whether to push metrics to the OpenTelemetry collector without transport security")
 		flags.String("service-name", fmt.Sprintf("%s-%s", "dragonfly", cmd.Name()), "name of the service for tracer")
 		flags.String("config", "", fmt.Sprintf("the path of configuration file with yaml extension name, default is %s, it can also be set by env var: %s", filepath.Join(dfpath.DefaultConfigDir, rootName+".yaml"), strings.ToUpper(rootName+"_config")))
 
@@ -123,6 +127,13 @@ func InitMonitor(pprofPort int, otelOption base.TelemetryOption) func() {
 	}
 	fc <- ff
 
+	mf, err := initOTLPMeter(otelOption)
+	if err != nil {
+		logger.Warnf("init otlp meter error: %v", err)
+	} else {
+		fc <- mf
+	}
+
 	return func() {
 		logger.Infof("do %d monitor finalizer", len(fc))
 		for {
@@ -259,6 +270,16 @@ func initDecoderConfig(dc *mapstructure.DecoderConfig) {
 }
 
 // initTracer creates a new trace provider instance and registers it as global trace provider.
+// newResource returns the resource of the OpenTelemetry telemetry about this application.
+func newResource(serviceName string) *resource.Resource {
+	return resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(serviceName),
+		semconv.ServiceInstanceIDKey.String(fmt.Sprintf("%s|%s", fqdn.FQDNHostname, ip.IPv4.String())),
+		semconv.ServiceNamespaceKey.String("dragonfly"),
+		semconv.ServiceVersionKey.String(version.GitVersion))
+}
+
 func initJaegerTracer(otelOption base.TelemetryOption) (func(), error) {
 	// currently, only support jaeger, otherwise just keeps the context of the caller but does not record spans.
 	if otelOption.Jaeger == "" {
@@ -280,12 +301,7 @@ func initJaegerTracer(otelOption base.TelemetryOption) (func(), error) {
 		sdktrace.WithBatcher(exp),
 		sdktrace.WithSampler(sdktrace.AlwaysSample()),
 		// Record information about this application in an Resource.
-		sdktrace.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String(otelOption.ServiceName),
-			semconv.ServiceInstanceIDKey.String(fmt.Sprintf("%s|%s", fqdn.FQDNHostname, ip.IPv4.String())),
-			semconv.ServiceNamespaceKey.String("dragonfly"),
-			semconv.ServiceVersionKey.String(version.GitVersion))),
+		sdktrace.WithResource(newResource(otelOption.ServiceName)),
 	)
 
 	// Register our TracerProvider as the global so any imported
@@ -0,0 +1,222 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dependency
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"d7y.io/dragonfly/v2/cmd/dependency/base"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// defaultOTLPMetricsInterval is the default interval of pushing metrics.
+	defaultOTLPMetricsInterval = 30 * time.Second
+
+	// otlpMetricsScope is the instrumentation scope of the metrics collected from prometheus.
+	otlpMetricsScope = "d7y.io/dragonfly/v2"
+)
+
+// initOTLPMeter pushes the prometheus metrics to the OpenTelemetry collector periodically,
+// so the deployments don't need to scrape the metrics by prometheus.
+func initOTLPMeter(otelOption base.TelemetryOption) (func(), error) {
+	if otelOption.OTLPMetrics == "" {
+		return func() {}, nil
+	}
+
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(otelOption.OTLPMetrics)}
+	if otelOption.OTLPMetricsInsecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+
+	exp, err := otlpmetricgrpc.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := otelOption.OTLPMetricsInterval
+	if interval <= 0 {
+		interval = defaultOTLPMetricsInterval
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp,
+			sdkmetric.WithInterval(interval),
+			sdkmetric.WithProducer(newPrometheusProducer(prometheus.DefaultGatherer)),
+		)),
+		sdkmetric.WithResource(newResource(otelOption.ServiceName)),
+	)
+
+	// Register our MeterProvider as the global so the otel instrumentation reports metrics by it.
+	otel.SetMeterProvider(mp)
+	logger.Infof("push metrics to otlp endpoint %s every %s", otelOption.OTLPMetrics, interval)
+
+	return func() {
+		// Do not make the application hang when it is shutdown.
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = mp.Shutdown(ctx)
+	}, nil
+}
+
+// prometheusProducer produces the OpenTelemetry metrics from the prometheus gatherer.
+type prometheusProducer struct {
+	gatherer  prometheus.Gatherer
+	startTime time.Time
+}
+
+// newPrometheusProducer returns a new prometheus producer.
+func newPrometheusProducer(gatherer prometheus.Gatherer) sdkmetric.Producer {
+	return &prometheusProducer{
+		gatherer:  gatherer,
+		startTime: time.Now(),
+	}
+}
+
+// Produce converts the gathered prometheus metric families to the OpenTelemetry metrics,
+// the summary metrics are not supported and will be skipped.
+func (p *prometheusProducer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	families, err := p.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return nil, err
+	}
+
+	now := time.Now()
+	metrics := make([]metricdata.Metrics, 0, len(families))
+	for _, family := range families {
+		var data metricdata.Aggregation
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			data = p.convertCounter(family, now)
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			data = p.convertGauge(family, now)
+		case dto.MetricType_HISTOGRAM:
+			data = p.convertHistogram(family, now)
+		default:
+			continue
+		}
+
+		metrics = append(metrics, metricdata.Metrics{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+			Data:        data,
+		})
+	}
+
+	return []metricdata.ScopeMetrics{{
+		Scope:   instrumentation.Scope{Name: otlpMetricsScope},
+		Metrics: metrics,
+	}}, err
+}
+
+// convertCounter converts the prometheus counter to the cumulative monotonic sum.
+func (p *prometheusProducer) convertCounter(family *dto.MetricFamily, now time.Time) metricdata.Sum[float64] {
+	dataPoints := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		dataPoints = append(dataPoints, metricdata.DataPoint[float64]{
+			Attributes: convertLabels(m.GetLabel()),
+			StartTime:  p.startTime,
+			Time:       now,
+			Value:      m.GetCounter().GetValue(),
+		})
+	}
+
+	return metricdata.Sum[float64]{
+		DataPoints:  dataPoints,
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}
+}
+
+// convertGauge converts the prometheus gauge and untyped metric to the gauge.
+func (p *prometheusProducer) convertGauge(family *dto.MetricFamily, now time.Time) metricdata.Gauge[float64] {
+	dataPoints := make([]metricdata.DataPoint[float64], 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		value := m.GetGauge().GetValue()
+		if family.GetType() == dto.MetricType_UNTYPED {
+			value = m.GetUntyped().GetValue()
+		}
+
+		dataPoints = append(dataPoints, metricdata.DataPoint[float64]{
+			Attributes: convertLabels(m.GetLabel()),
+			Time:       now,
+			Value:      value,
+		})
+	}
+
+	return metricdata.Gauge[float64]{DataPoints: dataPoints}
+}
+
+// convertHistogram converts the prometheus histogram to the cumulative histogram, the prometheus
+// buckets are cumulative but the OpenTelemetry bucket counts are not.
+func (p *prometheusProducer) convertHistogram(family *dto.MetricFamily, now time.Time) metricdata.Histogram[float64] {
+	dataPoints := make([]metricdata.HistogramDataPoint[float64], 0, len(family.GetMetric()))
+	for _, m := range family.GetMetric() {
+		histogram := m.GetHistogram()
+		bounds := make([]float64, 0, len(histogram.GetBucket()))
+		bucketCounts := make([]uint64, 0, len(histogram.GetBucket())+1)
+
+		var cumulativeCount uint64
+		for _, bucket := range histogram.GetBucket() {
+			// The +Inf bucket is the overflow bucket of the OpenTelemetry histogram.
+			if math.IsInf(bucket.GetUpperBound(), 1) {
+				continue
+			}
+
+			bounds = append(bounds, bucket.GetUpperBound())
+			bucketCounts = append(bucketCounts, bucket.GetCumulativeCount()-cumulativeCount)
+			cumulativeCount = bucket.GetCumulativeCount()
+		}
+		bucketCounts = append(bucketCounts, histogram.GetSampleCount()-cumulativeCount)
+
+		dataPoints = append(dataPoints, metricdata.HistogramDataPoint[float64]{
+			Attributes:   convertLabels(m.GetLabel()),
+			StartTime:    p.startTime,
+			Time:         now,
+			Count:        histogram.GetSampleCount(),
+			Sum:          histogram.GetSampleSum(),
+			Bounds:       bounds,
+			BucketCounts: bucketCounts,
+		})
+	}
+
+	return metricdata.Histogram[float64]{
+		DataPoints:  dataPoints,
+		Temporality: metricdata.CumulativeTemporality,
+	}
+}
+
+// convertLabels converts the prometheus labels to the OpenTelemetry attributes.
+func convertLabels(labels []*dto.LabelPair) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		kvs = append(kvs, attribute.String(label.GetName(), label.GetValue()))
+	}
+
+	return attribute.NewSet(kvs...)
+}
@@ -17,7 +17,10 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"d7y.io/dragonfly/v2/client/config"
 	"d7y.io/dragonfly/v2/client/dfcache"
@@ -28,7 +31,7 @@ const deleteDesc = "delete file from P2P cache system"
 
 // deleteCmd represents the cache delete command
 var deleteCmd = &cobra.Command{
-	Use:                "delete <-i cid> [flags]",
+	Use:                "delete <-i cid>|<--namespace namespace>|<--label key=value> [flags]",
 	Short:              deleteDesc,
 	Long:               deleteDesc,
 	Args:               cobra.NoArgs,
@@ -43,6 +46,12 @@ var deleteCmd = &cobra.Command{
 func initDelete() {
 	// Add the command to parent
 	rootCmd.AddCommand(deleteCmd)
+
+	flags := deleteCmd.Flags()
+	flags.StringToStringVar(&dfcacheConfig.Labels, "label", dfcacheConfig.Labels, "delete the caches matching all the key/value labels when cid is not given")
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache delete flags to viper: %w", err))
+	}
 }
 
 func runDelete(cfg *config.DfcacheConfig, client client.V1) error {
@@ -31,7 +31,7 @@ const exportDesc = "export file from P2P cache system"
 
 // exportCmd represents the cache export command
 var exportCmd = &cobra.Command{
-	Use:                "export <-i cid> <output>|<-O output> [flags]",
+	Use:                "export <-i cid> <output>|<-O output>|- [flags]",
 	Short:              exportDesc,
 	Long:               exportDesc,
 	Args:               cobra.MaximumNArgs(1),
@@ -48,7 +48,7 @@ func initExport() {
 	rootCmd.AddCommand(exportCmd)
 
 	flags := exportCmd.Flags()
-	flags.StringVarP(&dfcacheConfig.Output, "output", "O", "", "export file path")
+	flags.StringVarP(&dfcacheConfig.Output, "output", "O", "", "export file path, - writes the content to stdout")
 	flags.BoolVarP(&dfcacheConfig.LocalOnly, "local", "l", false, "only export file from local cache")
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache export flags to viper: %w", err))
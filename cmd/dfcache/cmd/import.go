@@ -31,7 +31,7 @@ const importDesc = "import file into P2P cache system"
 
 // importCmd represents the cache import command
 var importCmd = &cobra.Command{
-	Use:                "import <-i cid> <file>|<-I file> [flags]",
+	Use:                "import <-i cid> <file>|<-I file>|- [flags]",
 	Short:              importDesc,
 	Long:               importDesc,
 	Args:               cobra.MaximumNArgs(1),
@@ -48,7 +48,11 @@ func initImport() {
 	rootCmd.AddCommand(importCmd)
 
 	flags := importCmd.Flags()
-	flags.StringVarP(&dfcacheConfig.Path, "input", "I", "", "import the given file into P2P network")
+	flags.StringVarP(&dfcacheConfig.Path, "input", "I", "", "import the given file into P2P network, - reads the content from stdin")
+	flags.StringToStringVar(&dfcacheConfig.Labels, "label", dfcacheConfig.Labels, "key/value labels of the cache, e.g. --label env=dev")
+	flags.DurationVar(&dfcacheConfig.TTL, "ttl", dfcacheConfig.TTL, "time to live of the cache, 0 is never expired")
+	flags.IntVar(&dfcacheConfig.Replicas, "replicas", dfcacheConfig.Replicas, "replica count of the persistent cache maintained by scheduler, 0 is not persistent")
+	flags.StringVar(&dfcacheConfig.SpreadBy, "spread-by", dfcacheConfig.SpreadBy, "topology label which the replicas are spread across, e.g. zone")
 	if err := viper.BindPFlags(flags); err != nil {
 		panic(fmt.Errorf("bind cache import flags to viper: %w", err))
 	}
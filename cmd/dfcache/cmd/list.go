@@ -0,0 +1,60 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"d7y.io/dragonfly/v2/client/config"
+	"d7y.io/dragonfly/v2/client/dfcache"
+	"d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+)
+
+const listDesc = "list the caches imported with namespace, labels or ttl in local P2P cache system"
+
+// listCmd represents the cache list command
+var listCmd = &cobra.Command{
+	Use:                "list [--namespace namespace] [--label key=value]",
+	Short:              listDesc,
+	Long:               listDesc,
+	Args:               cobra.NoArgs,
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDfcacheSubcmd(config.CmdList, args)
+	},
+}
+
+func initList() {
+	// Add the command to parent
+	rootCmd.AddCommand(listCmd)
+
+	flags := listCmd.Flags()
+	flags.StringToStringVar(&dfcacheConfig.Labels, "label", dfcacheConfig.Labels, "only list the caches matching all the key/value labels")
+	if err := viper.BindPFlags(flags); err != nil {
+		panic(fmt.Errorf("bind cache list flags to viper: %w", err))
+	}
+}
+
+func runList(cfg *config.DfcacheConfig, client client.V1) error {
+	return dfcache.List(cfg, os.Stdout)
+}
@@ -80,6 +80,7 @@ func init() {
 
 	flags.StringP("cid", "i", "", "content or cache ID, e.g. sha256 digest of the content")
 	flags.StringP("tag", "t", "", "different tags for the same cid will be recognized as different  files in P2P network")
+	flags.String("namespace", "", "namespace of the cache, the same cid in different namespaces will be recognized as different files")
 	flags.Duration("timeout", dfcacheConfig.Timeout, "Timeout for this cache operation, 0 is infinite")
 	flags.String("workhome", dfcacheConfig.WorkHome, "Dfcache working directory")
 	flags.String("logdir", dfcacheConfig.LogDir, "Dfcache log directory")
@@ -93,6 +94,7 @@ func init() {
 	initImport()
 	initExport()
 	initDelete()
+	initList()
 }
 
 func initDfcacheDfpath(cfg *config.CacheOption) (dfpath.Dfpath, error) {
@@ -120,6 +122,11 @@ func runDfcacheSubcmd(cmdName string, args []string) error {
 		return err
 	}
 
+	// Content is written to stdout, so print messages to stderr
+	if cmdName == config.CmdExport && dfcacheConfig.Output == config.StdoutOutput {
+		os.Stdout = os.Stderr
+	}
+
 	var (
 		dfdaemonClient client.V1
 		err            error
@@ -130,6 +137,7 @@ func runDfcacheSubcmd(cmdName string, args []string) error {
 	if err != nil {
 		return err
 	}
+	dfcacheConfig.DaemonSock = d.DaemonSockPath()
 
 	// Initialize logger
 	if err := logger.InitDfcache(dfcacheConfig.Console, d.LogDir()); err != nil {
@@ -155,6 +163,8 @@ func runDfcacheSubcmd(cmdName string, args []string) error {
 		runCmd = runExport
 	case config.CmdDelete:
 		runCmd = runDelete
+	case config.CmdList:
+		runCmd = runList
 	default:
 		msg := fmt.Sprintf("unknown sub-command %s", cmdName)
 		logger.Error(msg)
@@ -79,6 +79,12 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
+		// Content or progress events are written to stdout, so print messages to stderr
+		if dfgetConfig.Output == config.StdoutOutput || (dfgetConfig.OutputPipe && dfgetConfig.Output == config.FdOutputPrefix+"1") ||
+			dfgetConfig.Progress == config.ProgressJSON {
+			os.Stdout = os.Stderr
+		}
+
 		// Initialize daemon dfpath
 		d, err := initDfgetDfpath(dfgetConfig)
 		if err != nil {
@@ -185,12 +191,45 @@ func init() {
 	flagSet.String("reject-regex", dfgetConfig.RecursiveRejectRegex,
 		`Recursively download only. Specify a regular expression to reject the complete URL. In this case, you have to enclose the pattern into quotes to prevent your shell from expanding it`)
 
+	flagSet.StringSlice("include", dfgetConfig.RecursiveInclude,
+		`Recursively download only. Specify glob patterns of the files to download, matched against the relative path or the name, eg: --include='*.bin' --include='models/*'`)
+
+	flagSet.StringSlice("exclude", dfgetConfig.RecursiveExclude,
+		`Recursively download only. Specify glob patterns of the files or directories to skip, matched against the relative path or the name, eg: --exclude='*.tmp'`)
+
+	flagSet.Int("recursive-concurrent", dfgetConfig.RecursiveConcurrent,
+		"Recursively download only. Set the number of files downloading concurrently")
+
 	flagSet.Bool("original-offset", dfgetConfig.KeepOriginalOffset,
 		`Range request only. Download ranged data into target file with original offset. Daemon will make a hardlink to target file. Client can download many ranged data into one file for same url. When enabled, back source in client will be disabled`)
 
 	flagSet.String("range", dfgetConfig.Range,
 		`Download range. Like: 0-9, stands download 10 bytes from 0 -9, [0:9] in real url`)
 
+	flagSet.Bool("stream", dfgetConfig.Stream,
+		`Stream the verified pieces in order to the output as they arrive. The output '-' writes the content to stdout`)
+
+	flagSet.Bool("output-pipe", dfgetConfig.OutputPipe,
+		`Stream the content into the output which is an existing named pipe or a file descriptor like fd://3, without writing a temporary file`)
+
+	flagSet.StringP("input-file", "i", dfgetConfig.InputFile,
+		`Download the urls in the manifest file concurrently, in format of one url with the optional output per line, or json like [{"url": "", "output": "", "digest": "", "tag": "", "header": []}]. The relative output is placed under the output directory`)
+
+	flagSet.Int("input-concurrent", dfgetConfig.InputConcurrent,
+		"Manifest download only. Set the number of urls downloading concurrently")
+
+	flagSet.String("report", dfgetConfig.Report,
+		"Manifest download only. Write the json result report of every url to the path")
+
+	flagSet.String("progress", dfgetConfig.Progress,
+		"Progress mode, json writes the newline-delimited json progress events of bytes done, traffic from P2P and source, speed and eta to stdout, and the messages to stderr")
+
+	flagSet.String("signature", dfgetConfig.Signature,
+		"The url or the inline content of the signature, dfdaemon verifies the artifact against its trusted keys before the download is reported successful, it never falls back to download from source")
+
+	flagSet.String("signature-type", dfgetConfig.SignatureType,
+		"The type of the signature, cosign or gpg")
+
 	// Bind cmd flags
 	if err := viper.BindPFlags(flagSet); err != nil {
 		panic(fmt.Errorf("bind dfget flags to viper: %w", err))
@@ -230,6 +269,8 @@ func runDfget(cmd *cobra.Command, dfgetLockPath, daemonSockPath string) error {
 		return err
 	}
 
+	dfgetConfig.DaemonSock = daemonSockPath
+
 	logger.Info("start to check and spawn daemon")
 	if dfdaemonClient, err = checkAndSpawnDaemon(dfgetLockPath, daemonSockPath); err != nil {
 		logger.Errorf("check and spawn daemon error: %v", err)
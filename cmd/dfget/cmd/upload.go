@@ -0,0 +1,105 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"d7y.io/dragonfly/v2/client/dfget"
+	"d7y.io/dragonfly/v2/cmd/dependency"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/version"
+)
+
+// uploadCmd represents the upload command
+var uploadCmd = &cobra.Command{
+	Use:   "upload <file> [flags]",
+	Short: "upload the local file into P2P network",
+	Long: `upload imports the local file into the storage of daemon and registers it as a task to the scheduler,
+then others download the file with the printed url and tag by dfget in P2P network.`,
+	Args:               cobra.ExactArgs(1),
+	DisableAutoGenTag:  true,
+	SilenceUsage:       true,
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
+
+		// Initialize daemon dfpath
+		d, err := initDfgetDfpath(dfgetConfig)
+		if err != nil {
+			return err
+		}
+
+		// Initialize logger
+		if err := logger.InitDfget(dfgetConfig.Verbose, dfgetConfig.Console, d.LogDir()); err != nil {
+			return fmt.Errorf("init client dfget logger: %w", err)
+		}
+		logger.Infof("version:\n%s", version.Version())
+
+		ff := dependency.InitMonitor(dfgetConfig.PProfPort, dfgetConfig.Telemetry)
+		defer ff()
+
+		dfdaemonClient, err := checkAndSpawnDaemon(d.DfgetLockPath(), d.DaemonSockPath())
+		if err != nil {
+			logger.Errorf("check and spawn daemon error: %v", err)
+			return fmt.Errorf("upload file %s: %w", args[0], err)
+		}
+
+		result, err := dfget.Upload(dfgetConfig, dfdaemonClient, args[0])
+		if err != nil {
+			return fmt.Errorf("upload file %s: %w", args[0], err)
+		}
+
+		fmt.Printf("upload success cost: %d ms\n", time.Since(start).Milliseconds())
+		fmt.Printf("url: %s\n", result.URL)
+		fmt.Printf("task id: %s\n", result.TaskID)
+		if dfgetConfig.Tag != "" {
+			fmt.Printf("download with: dfget --tag %s -O <output> %s\n", dfgetConfig.Tag, result.URL)
+		} else {
+			fmt.Printf("download with: dfget -O <output> %s\n", result.URL)
+		}
+		return nil
+	},
+}
+
+func init() {
+	// Add the command to parent
+	rootCmd.AddCommand(uploadCmd)
+
+	if len(os.Args) > 1 && os.Args[1] == uploadCmd.Name() {
+		flags := uploadCmd.Flags()
+		flags.StringP("url", "u", "",
+			"The url to download the uploaded file, default is generated by the sha256 and the name of the file")
+		flags.String("tag", dfgetConfig.Tag,
+			"Different tags for the same url will be divided into different P2P overlay, the downloader must use the same tag")
+		flags.String("application", dfgetConfig.Application, "The caller name which is mainly used for statistics and access control")
+		flags.Duration("timeout", dfgetConfig.Timeout, "Timeout for the uploading, 0 is infinite")
+		flags.String("daemon-sock", dfgetConfig.DaemonSock, "Download socket path of daemon")
+		flags.String("workhome", dfgetConfig.WorkHome, "Dfget working directory")
+		flags.String("logdir", dfgetConfig.LogDir, "Dfget log directory")
+
+		// Bind cmd flags
+		if err := viper.BindPFlags(flags); err != nil {
+			panic(fmt.Errorf("bind dfget upload flags to viper: %w", err))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	inferenceclient "d7y.io/dragonfly/v2/pkg/rpc/inference/client"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/inference"
+	"d7y.io/dragonfly/v2/scheduler/scheduling"
+	"d7y.io/dragonfly/v2/scheduler/scheduling/evaluator"
+	"d7y.io/dragonfly/v2/scheduler/simulation"
+)
+
+var (
+	workloadPath string
+)
+
+// simulateCmd represents the command replaying the synthetic workload offline.
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "replay the synthetic workload offline",
+	Long: `Simulate replays the scheduling decisions of the synthetic workload offline with the scheduler config,
+and prints the metrics, so operators can compare evaluator configurations before rollout.`,
+	Args:              cobra.NoArgs,
+	DisableAutoGenTag: true,
+	SilenceUsage:      true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if workloadPath == "" {
+			return errors.New("simulate requires parameter workload")
+		}
+
+		// Convert config.
+		if err := cfg.Convert(); err != nil {
+			return err
+		}
+
+		// Validate config.
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+
+		// Initialize dfpath.
+		d, err := initDfpath(&cfg.Server)
+		if err != nil {
+			return err
+		}
+
+		// Initialize logger, the stdout is reserved for the report.
+		if err := logger.InitScheduler(cfg.Verbose, false, d.LogDir()); err != nil {
+			return fmt.Errorf("init scheduler logger: %w", err)
+		}
+
+		workload, err := simulation.LoadWorkload(workloadPath)
+		if err != nil {
+			return err
+		}
+
+		var options []scheduling.Option
+		if cfg.Scheduler.Algorithm == config.SchedulerAlgorithmML {
+			inferenceClient, err := inferenceclient.GetV1(context.Background(), cfg.Scheduler.Inference.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return err
+			}
+			defer inferenceClient.Close()
+
+			options = append(options, scheduling.WithEvaluatorOptions(evaluator.WithInferencer(
+				inference.New(&cfg.Scheduler.Inference, inferenceClient, idgen.GNNModelIDV1(cfg.Server.AdvertiseIP.String(), cfg.Server.Host)))))
+		}
+
+		report, err := simulation.New(cfg, workload, d.PluginDir(), options...).Run(context.Background())
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	},
+}
+
+func init() {
+	simulateCmd.Flags().StringVar(&workloadPath, "workload", "", "the path of synthetic workload file")
+	rootCmd.AddCommand(simulateCmd)
+}
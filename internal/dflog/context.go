@@ -0,0 +1,50 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"context"
+)
+
+// requestIDContextKey is the context key of request id.
+type requestIDContextKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request id.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id carried by ctx.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDContextKey{}).(string)
+	return requestID, ok && requestID != ""
+}
+
+// WithContext returns the logger with the request id carried by ctx.
+func WithContext(ctx context.Context) *SugaredLoggerOnWith {
+	return (&SugaredLoggerOnWith{}).WithContext(ctx)
+}
+
+// WithContext appends the request id carried by ctx to the logger.
+func (log *SugaredLoggerOnWith) WithContext(ctx context.Context) *SugaredLoggerOnWith {
+	requestID, ok := RequestIDFromContext(ctx)
+	if !ok {
+		return log
+	}
+
+	return log.With("requestID", requestID)
+}
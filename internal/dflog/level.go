@@ -0,0 +1,134 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	// componentLevels is the log level of every component, the key is the component name.
+	componentLevels   = map[string]zap.AtomicLevel{}
+	componentLevelsMu sync.RWMutex
+)
+
+// componentName returns the component name of the log file name, like core.log is core, stat/seed.log is stat-seed.
+func componentName(fileName string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(fileName, path.Ext(fileName)), "/", "-")
+}
+
+// resetComponentLevels clears the registered component levels.
+func resetComponentLevels() {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+
+	componentLevels = map[string]zap.AtomicLevel{}
+}
+
+// registerComponentLevel registers the log level of the component.
+func registerComponentLevel(component string, level zap.AtomicLevel) {
+	componentLevelsMu.Lock()
+	defer componentLevelsMu.Unlock()
+
+	componentLevels[component] = level
+}
+
+// GetComponentLevels returns the log level of every registered component.
+func GetComponentLevels() map[string]string {
+	componentLevelsMu.RLock()
+	defer componentLevelsMu.RUnlock()
+
+	levels := make(map[string]string, len(componentLevels))
+	for component, level := range componentLevels {
+		levels[component] = level.String()
+	}
+
+	return levels
+}
+
+// SetComponentLevel updates the log level of the component at runtime.
+func SetComponentLevel(component string, level zapcore.Level) error {
+	componentLevelsMu.RLock()
+	defer componentLevelsMu.RUnlock()
+
+	l, ok := componentLevels[component]
+	if !ok {
+		return fmt.Errorf("component %s not found", component)
+	}
+
+	Infof("change %s log level to %s", component, level.String())
+	l.SetLevel(level)
+	return nil
+}
+
+// levelRequest is the request body of updating the log level.
+type levelRequest struct {
+	// Component is the name of the component, all components will be updated if it is empty.
+	Component string `json:"component"`
+
+	// Level is the log level, like debug, info, warn and error.
+	Level string `json:"level"`
+}
+
+// LevelHandler returns the admin handler of the component log levels,
+// GET lists the log level of every component and PUT updates the log level of the component.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			level, err := zapcore.ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if req.Component == "" {
+				SetLevel(level)
+				break
+			}
+
+			if err := SetComponentLevel(req.Component, level); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(GetComponentLevels()); err != nil {
+			Errorf("encode log levels failed: %s", err)
+		}
+	})
+}
@@ -35,6 +35,7 @@ var (
 	StatSeedLogFileName   = "stat/seed.log"
 	DownloaderLogFileName = "downloader.log"
 	KeepAliveLogFileName  = "keepalive.log"
+	AuditLogFileName      = "audit.log"
 )
 
 const (
@@ -52,7 +53,12 @@ var customCoreLevel atomic.Bool
 var grpcLevel = zap.NewAtomicLevelAt(zapcore.WarnLevel)
 var customGrpcLevel atomic.Bool
 
-func CreateLogger(filePath string, compress bool, stats bool, verbose bool) (*zap.Logger, zap.AtomicLevel, error) {
+func CreateLogger(filePath string, compress bool, stats bool, verbose bool, logOpts ...Option) (*zap.Logger, zap.AtomicLevel, error) {
+	o := &options{}
+	for _, opt := range logOpts {
+		opt(o)
+	}
+
 	rotateConfig := &lumberjack.Logger{
 		Filename:   filePath,
 		MaxSize:    defaultRotateMaxSize,
@@ -75,8 +81,13 @@ func CreateLogger(filePath string, compress bool, stats bool, verbose bool) (*za
 		level = coreLevel
 	}
 
+	encoder := zapcore.NewJSONEncoder(encoderConfig)
+	if o.format == TextFormat {
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+
 	core := zapcore.NewCore(
-		zapcore.NewJSONEncoder(encoderConfig),
+		encoder,
 		syncer,
 		level,
 	)
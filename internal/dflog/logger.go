@@ -39,6 +39,7 @@ var (
 	KeepAliveLogger  *zap.SugaredLogger
 	StatSeedLogger   *zap.Logger
 	DownloaderLogger *zap.Logger
+	AuditLogger      *zap.SugaredLogger
 
 	coreLogLevelEnabler zapcore.LevelEnabler
 )
@@ -58,6 +59,7 @@ func init() {
 		SetStatSeedLogger(log)
 		SetDownloadLogger(log)
 		SetJobLogger(sugar)
+		SetAuditLogger(sugar)
 	}
 	levels = append(levels, config.Level)
 }
@@ -113,6 +115,11 @@ func SetJobLogger(log *zap.SugaredLogger) {
 	JobLogger = log
 }
 
+// SetAuditLogger sets the logger of the audit events, like the denied downloads.
+func SetAuditLogger(log *zap.SugaredLogger) {
+	AuditLogger = log
+}
+
 type SugaredLoggerOnWith struct {
 	withArgs []any
 }
@@ -23,19 +23,45 @@ import (
 	"path/filepath"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"d7y.io/dragonfly/v2/pkg/types"
 )
 
+const (
+	// TextFormat is the human-readable log format.
+	TextFormat = "text"
+
+	// JSONFormat is the structured log format.
+	JSONFormat = "json"
+)
+
+// Option is a functional option for initializing loggers.
+type Option func(o *options)
+
+// options is the options of initializing loggers.
+type options struct {
+	// format is the log format, the file logs use JSONFormat
+	// and the console logs use TextFormat by default.
+	format string
+}
+
+// WithFormat sets the log format, supports TextFormat and JSONFormat.
+func WithFormat(format string) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
 type logInitMeta struct {
 	fileName             string
 	setSugaredLoggerFunc func(*zap.SugaredLogger)
 	setLoggerFunc        func(log *zap.Logger)
 }
 
-func InitManager(verbose, console bool, dir string) error {
+func InitManager(verbose, console bool, dir string, opts ...Option) error {
 	if console {
-		return createConsoleLogger(verbose)
+		return createConsoleLogger(verbose, opts...)
 	}
 
 	logDir := filepath.Join(dir, types.ManagerName)
@@ -62,12 +88,12 @@ func InitManager(verbose, console bool, dir string) error {
 		},
 	}
 
-	return createFileLogger(verbose, meta, logDir)
+	return createFileLogger(verbose, meta, logDir, opts...)
 }
 
-func InitScheduler(verbose, console bool, dir string) error {
+func InitScheduler(verbose, console bool, dir string, opts ...Option) error {
 	if console {
-		return createConsoleLogger(verbose)
+		return createConsoleLogger(verbose, opts...)
 	}
 
 	logDir := filepath.Join(dir, types.SchedulerName)
@@ -88,14 +114,18 @@ func InitScheduler(verbose, console bool, dir string) error {
 			fileName:             JobLogFileName,
 			setSugaredLoggerFunc: SetJobLogger,
 		},
+		{
+			fileName:             AuditLogFileName,
+			setSugaredLoggerFunc: SetAuditLogger,
+		},
 	}
 
-	return createFileLogger(verbose, meta, logDir)
+	return createFileLogger(verbose, meta, logDir, opts...)
 }
 
-func InitDaemon(verbose, console bool, dir string) error {
+func InitDaemon(verbose, console bool, dir string, opts ...Option) error {
 	if console {
-		return createConsoleLogger(verbose)
+		return createConsoleLogger(verbose, opts...)
 	}
 
 	logDir := filepath.Join(dir, types.DaemonName)
@@ -116,14 +146,18 @@ func InitDaemon(verbose, console bool, dir string) error {
 			fileName:             GCLogFileName,
 			setSugaredLoggerFunc: SetGCLogger,
 		},
+		{
+			fileName:             AuditLogFileName,
+			setSugaredLoggerFunc: SetAuditLogger,
+		},
 	}
 
-	return createFileLogger(verbose, meta, logDir)
+	return createFileLogger(verbose, meta, logDir, opts...)
 }
 
-func InitDfget(verbose, console bool, dir string) error {
+func InitDfget(verbose, console bool, dir string, opts ...Option) error {
 	if console {
-		return createConsoleLogger(verbose)
+		return createConsoleLogger(verbose, opts...)
 	}
 
 	logDir := filepath.Join(dir, types.DfgetName)
@@ -138,10 +172,10 @@ func InitDfget(verbose, console bool, dir string) error {
 		},
 	}
 
-	return createFileLogger(verbose, meta, logDir)
+	return createFileLogger(verbose, meta, logDir, opts...)
 }
 
-func InitDfcache(console bool, dir string) error {
+func InitDfcache(console bool, dir string, opts ...Option) error {
 	logDir := filepath.Join(dir, types.DfcacheName)
 	var meta = []logInitMeta{
 		{
@@ -154,12 +188,12 @@ func InitDfcache(console bool, dir string) error {
 		},
 	}
 
-	return createFileLogger(console, meta, logDir)
+	return createFileLogger(console, meta, logDir, opts...)
 }
 
-func InitTrainer(verbose, console bool, dir string) error {
+func InitTrainer(verbose, console bool, dir string, opts ...Option) error {
 	if console {
-		return createConsoleLogger(verbose)
+		return createConsoleLogger(verbose, opts...)
 	}
 
 	logDir := filepath.Join(dir, types.TrainerName)
@@ -174,41 +208,103 @@ func InitTrainer(verbose, console bool, dir string) error {
 		},
 	}
 
-	return createFileLogger(console, meta, logDir)
+	return createFileLogger(console, meta, logDir, opts...)
 }
 
-func createConsoleLogger(verbose bool) error {
+func createConsoleLogger(verbose bool, opts ...Option) error {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	levels = nil
-	config := zap.NewDevelopmentConfig()
-	config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	if verbose {
-		config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-	}
-	log, err := config.Build(zap.AddCaller(), zap.AddStacktrace(zap.WarnLevel), zap.AddCallerSkip(1))
-	if err == nil {
-		sugar := log.Sugar()
-		SetCoreLogger(sugar)
-		SetGrpcLogger(sugar)
-		SetGinLogger(sugar)
-		SetGCLogger(sugar)
-		SetStorageGCLogger(sugar)
-		SetKeepAliveLogger(sugar)
-		SetStatSeedLogger(log)
-		SetDownloadLogger(log)
-		SetJobLogger(sugar)
-	}
-	levels = append(levels, config.Level)
+	resetComponentLevels()
+	var meta = []logInitMeta{
+		{
+			fileName:             CoreLogFileName,
+			setSugaredLoggerFunc: SetCoreLogger,
+		},
+		{
+			fileName:             GrpcLogFileName,
+			setSugaredLoggerFunc: SetGrpcLogger,
+		},
+		{
+			fileName:             GinLogFileName,
+			setSugaredLoggerFunc: SetGinLogger,
+		},
+		{
+			fileName:             GCLogFileName,
+			setSugaredLoggerFunc: SetGCLogger,
+		},
+		{
+			fileName:             StorageGCLogFileName,
+			setSugaredLoggerFunc: SetStorageGCLogger,
+		},
+		{
+			fileName:             KeepAliveLogFileName,
+			setSugaredLoggerFunc: SetKeepAliveLogger,
+		},
+		{
+			fileName:      StatSeedLogFileName,
+			setLoggerFunc: SetStatSeedLogger,
+		},
+		{
+			fileName:      DownloaderLogFileName,
+			setLoggerFunc: SetDownloadLogger,
+		},
+		{
+			fileName:             JobLogFileName,
+			setSugaredLoggerFunc: SetJobLogger,
+		},
+		{
+			fileName:             AuditLogFileName,
+			setSugaredLoggerFunc: SetAuditLogger,
+		},
+	}
+
+	for _, m := range meta {
+		config := zap.NewDevelopmentConfig()
+		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+		if verbose {
+			config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+		}
+
+		// Structured JSON output is routed by the component field, because all components share the stdout.
+		if o.format == JSONFormat {
+			config.Encoding = JSONFormat
+			config.EncoderConfig = zap.NewProductionEncoderConfig()
+			config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(encodeTimeFormat)
+		}
+
+		component := componentName(m.fileName)
+		log, err := config.Build(zap.AddCaller(), zap.AddStacktrace(zap.WarnLevel), zap.AddCallerSkip(1),
+			zap.Fields(zap.String("component", component)))
+		if err != nil {
+			return err
+		}
+
+		if m.setSugaredLoggerFunc != nil {
+			m.setSugaredLoggerFunc(log.Sugar())
+		} else {
+			m.setLoggerFunc(log)
+		}
+
+		levels = append(levels, config.Level)
+		registerComponentLevel(component, config.Level)
+	}
+
 	startLoggerSignalHandler()
 	return nil
 }
 
-func createFileLogger(verbose bool, meta []logInitMeta, logDir string) error {
+func createFileLogger(verbose bool, meta []logInitMeta, logDir string, opts ...Option) error {
 	levels = nil
+	resetComponentLevels()
 	// create parent dir first
 	_ = os.MkdirAll(logDir, fs.FileMode(0700))
 
 	for _, m := range meta {
-		log, level, err := CreateLogger(path.Join(logDir, m.fileName), false, false, verbose)
+		log, level, err := CreateLogger(path.Join(logDir, m.fileName), false, false, verbose, opts...)
 		if err != nil {
 			return err
 		}
@@ -219,6 +315,7 @@ func createFileLogger(verbose bool, meta []logInitMeta, logDir string) error {
 		}
 
 		levels = append(levels, level)
+		registerComponentLevel(componentName(m.fileName), level)
 	}
 	startLoggerSignalHandler()
 	return nil
@@ -29,6 +29,21 @@ const (
 
 	// SyncPeersJob is the name of syncing peers job.
 	SyncPeersJob = "sync_peers"
+
+	// DrainHostJob is the name of draining host job.
+	DrainHostJob = "drain_host"
+
+	// ReannounceHostJob is the name of reannouncing host job.
+	ReannounceHostJob = "reannounce_host"
+
+	// HostReputationJob is the name of host reputation job.
+	HostReputationJob = "host_reputation"
+
+	// DeleteTaskJob is the name of deleting task job.
+	DeleteTaskJob = "delete_task"
+
+	// GetTaskJob is the name of getting task job.
+	GetTaskJob = "get_task"
 )
 
 // Machinery server configuration.
@@ -16,6 +16,12 @@
 
 package job
 
+import (
+	"time"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
 type PreheatRequest struct {
 	URL         string            `json:"url" validate:"required,url"`
 	Tag         string            `json:"tag" validate:"omitempty"`
@@ -27,4 +33,91 @@ type PreheatRequest struct {
 }
 
 type PreheatResponse struct {
+	URL                string `json:"url"`
+	TaskID             string `json:"task_id"`
+	SeedPeerHostID     string `json:"seed_peer_host_id"`
+	SeedPeerID         string `json:"seed_peer_id"`
+	ContentLength      int64  `json:"content_length"`
+	TotalPieceCount    int32  `json:"total_piece_count"`
+	FinishedPieceCount int32  `json:"finished_piece_count"`
+}
+
+type DrainHostRequest struct {
+	HostID string `json:"host_id" validate:"required"`
+	Cancel bool   `json:"cancel" validate:"omitempty"`
+}
+
+type DrainHostResponse struct {
+	HostID     string `json:"host_id"`
+	Draining   bool   `json:"draining"`
+	ChildCount int    `json:"child_count"`
+}
+
+type ReannounceHostRequest struct {
+	HostID string `json:"host_id" validate:"required"`
+}
+
+type ReannounceHostResponse struct {
+	HostID  string `json:"host_id"`
+	Evicted bool   `json:"evicted"`
+}
+
+type HostReputationRequest struct {
+	HostID string `json:"host_id" validate:"required"`
+	Action string `json:"action" validate:"omitempty,oneof=block unblock"`
+}
+
+type HostReputationResponse struct {
+	HostID       string    `json:"host_id"`
+	Score        float64   `json:"score"`
+	Pieces       uint64    `json:"pieces"`
+	Failures     uint64    `json:"failures"`
+	Corruptions  uint64    `json:"corruptions"`
+	Blocked      bool      `json:"blocked"`
+	BlockedUntil time.Time `json:"blocked_until"`
+}
+
+type DeleteTaskRequest struct {
+	TaskID string `json:"task_id" validate:"required"`
+}
+
+type DeleteTaskResponse struct {
+	TaskID           string   `json:"task_id"`
+	SucceededHostIDs []string `json:"succeeded_host_ids"`
+	FailedHostIDs    []string `json:"failed_host_ids"`
+}
+
+type GetTaskRequest struct {
+	TaskID string `json:"task_id" validate:"required"`
+}
+
+type GetTaskResponse struct {
+	TaskID             string     `json:"task_id"`
+	SchedulerHostname  string     `json:"scheduler_hostname"`
+	SchedulerClusterID uint       `json:"scheduler_cluster_id"`
+	URL                string     `json:"url"`
+	State              string     `json:"state"`
+	ContentLength      int64      `json:"content_length"`
+	TotalPieceCount    int32      `json:"total_piece_count"`
+	Peers              []TaskPeer `json:"peers"`
+
+	// Replication is the replication policy of the persistent cache task.
+	Replication *types.Replication `json:"replication,omitempty"`
+
+	// Placement is the replica count of the persistent cache task in each failure domain.
+	Placement map[string]int `json:"placement,omitempty"`
+}
+
+type TaskPeer struct {
+	ID                 string         `json:"id"`
+	State              string         `json:"state"`
+	FinishedPieceCount uint           `json:"finished_piece_count"`
+	HostID             string         `json:"host_id"`
+	HostType           string         `json:"host_type"`
+	Hostname           string         `json:"hostname"`
+	IP                 string         `json:"ip"`
+	Port               int32          `json:"port"`
+	Topology           types.Topology `json:"topology"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
 }
@@ -0,0 +1,444 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/oidc_mock.go -source oidc.go -package mocks
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+
+	"d7y.io/dragonfly/v2/manager/config"
+)
+
+const (
+	// discoveryPath is the path of the OIDC provider configuration.
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// timeout is the timeout of requesting the OIDC provider.
+	timeout = 2 * time.Minute
+)
+
+// signingMethods are the supported signing methods of the id token.
+var signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// User is the user identified by the id token, the user is
+// identified by the issuer and the subject across the signins.
+type User struct {
+	Issuer        string
+	Subject       string
+	Name          string
+	Email         string
+	EmailVerified bool
+	Avatar        string
+	Groups        []string
+}
+
+// Token is the token issued by the OIDC provider.
+type Token struct {
+	IDToken      string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+// OIDC is the interface used for OpenID Connect authentication.
+type OIDC interface {
+	// AuthCodeURL returns the url of the OIDC provider to sign in.
+	AuthCodeURL(context.Context, string) (string, error)
+
+	// Exchange exchanges the authorization code for the token.
+	Exchange(context.Context, string) (*Token, error)
+
+	// Refresh refreshes the token with the refresh token.
+	Refresh(context.Context, string) (*Token, error)
+
+	// Verify verifies the id token and returns the user.
+	Verify(context.Context, string) (*User, error)
+}
+
+// provider is the configuration of the OIDC provider.
+type provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// jsonWebKey is the json web key of the OIDC provider.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// oidc implements OIDC.
+type oidc struct {
+	// config is the OIDC configuration.
+	config config.OIDCConfig
+
+	// httpClient is the client of requesting the OIDC provider.
+	httpClient *http.Client
+
+	// provider is the discovered configuration of the OIDC provider.
+	provider *provider
+
+	// keys are the public keys of the OIDC provider indexed by key id.
+	keys map[string]any
+
+	// mu protects provider and keys.
+	mu sync.RWMutex
+}
+
+// Option is a functional option for configuring the OIDC.
+type Option func(o *oidc)
+
+// WithHTTPClient sets the http client of requesting the OIDC provider.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *oidc) {
+		o.httpClient = client
+	}
+}
+
+// New returns a new OIDC interface, the OIDC provider configuration is discovered lazily.
+func New(cfg config.OIDCConfig, options ...Option) OIDC {
+	o := &oidc{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: timeout},
+		keys:       map[string]any{},
+	}
+
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return o
+}
+
+// AuthCodeURL returns the url of the OIDC provider to sign in.
+func (o *oidc) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	oauth2Config, err := o.oauth2Config(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2Config.AuthCodeURL(state), nil
+}
+
+// Exchange exchanges the authorization code for the token.
+func (o *oidc) Exchange(ctx context.Context, code string) (*Token, error) {
+	oauth2Config, err := o.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth2Config.Exchange(context.WithValue(ctx, oauth2.HTTPClient, o.httpClient), code)
+	if err != nil {
+		return nil, err
+	}
+
+	return newToken(token)
+}
+
+// Refresh refreshes the token with the refresh token.
+func (o *oidc) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	oauth2Config, err := o.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth2Config.TokenSource(context.WithValue(ctx, oauth2.HTTPClient, o.httpClient), &oauth2.Token{
+		RefreshToken: refreshToken,
+	}).Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return newToken(token)
+}
+
+// Verify verifies the id token and returns the user.
+func (o *oidc) Verify(ctx context.Context, rawIDToken string) (*User, error) {
+	p, err := o.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return o.getKey(ctx, p, kid)
+	}, jwt.WithValidMethods(signingMethods)); err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyIssuer(p.Issuer, true) {
+		return nil, errors.New("invalid issuer of id token")
+	}
+
+	if !claims.VerifyAudience(o.config.ClientID, true) {
+		return nil, errors.New("invalid audience of id token")
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("id token requires claim exp")
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("id token requires claim sub")
+	}
+
+	user := &User{
+		Issuer:        p.Issuer,
+		Subject:       subject,
+		EmailVerified: boolClaim(claims["email_verified"]),
+		Groups:        stringsClaim(claims[o.config.GroupsClaim]),
+	}
+	user.Email, _ = claims["email"].(string)
+	user.Avatar, _ = claims["picture"].(string)
+	if user.Name, _ = claims["preferred_username"].(string); user.Name == "" {
+		user.Name, _ = claims["name"].(string)
+	}
+
+	return user, nil
+}
+
+// IsIssuedBy returns whether the token is issued by the issuer, the token is not verified.
+func IsIssuedBy(rawToken, issuer string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(rawToken, claims); err != nil {
+		return false
+	}
+
+	iss, _ := claims["iss"].(string)
+	return iss != "" && strings.TrimSuffix(iss, "/") == strings.TrimSuffix(issuer, "/")
+}
+
+// oauth2Config returns the oauth2 configuration of the OIDC provider.
+func (o *oidc) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	p, err := o.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     o.config.ClientID,
+		ClientSecret: o.config.ClientSecret,
+		RedirectURL:  o.config.RedirectURL,
+		Scopes:       o.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.AuthorizationEndpoint,
+			TokenURL: p.TokenEndpoint,
+		},
+	}, nil
+}
+
+// getProvider returns the configuration of the OIDC provider, it is discovered at the first call.
+func (o *oidc) getProvider(ctx context.Context) (*provider, error) {
+	o.mu.RLock()
+	p := o.provider
+	o.mu.RUnlock()
+	if p != nil {
+		return p, nil
+	}
+
+	p = &provider{}
+	if err := o.getJSON(ctx, strings.TrimSuffix(o.config.Issuer, "/")+discoveryPath, p); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(p.Issuer, "/") != strings.TrimSuffix(o.config.Issuer, "/") {
+		return nil, fmt.Errorf("issuer %s of provider does not match %s", p.Issuer, o.config.Issuer)
+	}
+
+	o.mu.Lock()
+	o.provider = p
+	o.mu.Unlock()
+	return p, nil
+}
+
+// getKey returns the public key of the OIDC provider by key id, the keys are
+// reloaded when the key id is not found because of the key rotation.
+func (o *oidc) getKey(ctx context.Context, p *provider, kid string) (any, error) {
+	o.mu.RLock()
+	key, ok := o.keys[kid]
+	o.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := o.getJSON(ctx, p.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]any{}
+	for _, jwk := range jwks.Keys {
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	o.mu.Lock()
+	o.keys = keys
+	o.mu.Unlock()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("key %s of id token not found", kid)
+}
+
+// getJSON requests the url and decodes the json response.
+func (o *oidc) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request %s failed with status code %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// publicKey returns the public key of the json web key.
+func (k *jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+// newToken returns the token which contains the id token.
+func newToken(token *oauth2.Token) (*Token, error) {
+	idToken, ok := token.Extra("id_token").(string)
+	if !ok || idToken == "" {
+		return nil, errors.New("id token not found in token response")
+	}
+
+	return &Token{
+		IDToken:      idToken,
+		RefreshToken: token.RefreshToken,
+		Expiry:       token.Expiry,
+	}, nil
+}
+
+// stringsClaim returns the claim as strings, the claim is either a string or an array of strings.
+func stringsClaim(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var values []string
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+// boolClaim returns the claim as bool, some OIDC providers issue the boolean claim as a string.
+func boolClaim(claim any) bool {
+	switch v := claim.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,275 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/manager/config"
+)
+
+const (
+	mockKeyID    = "foo"
+	mockClientID = "bar"
+)
+
+func newMockProvider(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(provider{ // nolint: errcheck
+			Issuer:                server.URL,
+			AuthorizationEndpoint: server.URL + "/authorize",
+			TokenEndpoint:         server.URL + "/token",
+			JWKSURI:               server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{ // nolint: errcheck
+			"keys": []jsonWebKey{{
+				Kty: "RSA",
+				Kid: mockKeyID,
+				N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+
+	return server
+}
+
+func newMockIDToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	rawIDToken, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rawIDToken
+}
+
+func TestOIDC_AuthCodeURL(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newMockProvider(t, key)
+	defer server.Close()
+
+	o := New(config.OIDCConfig{
+		Issuer:      server.URL,
+		ClientID:    mockClientID,
+		RedirectURL: "http://localhost/callback",
+		Scopes:      []string{"openid", "email"},
+	})
+
+	authCodeURL, err := o.AuthCodeURL(context.Background(), "baz")
+	assert := assert.New(t)
+	assert.NoError(err)
+
+	u, err := url.Parse(authCodeURL)
+	assert.NoError(err)
+	assert.Equal("/authorize", u.Path)
+	assert.Equal("baz", u.Query().Get("state"))
+	assert.Equal(mockClientID, u.Query().Get("client_id"))
+	assert.Equal("openid email", u.Query().Get("scope"))
+}
+
+func TestOIDC_Verify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newMockProvider(t, key)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		token  func() string
+		expect func(t *testing.T, user *User, err error)
+	}{
+		{
+			name: "verify id token",
+			token: func() string {
+				return newMockIDToken(t, key, mockKeyID, jwt.MapClaims{
+					"iss":                server.URL,
+					"aud":                mockClientID,
+					"sub":                "1",
+					"exp":                time.Now().Add(time.Hour).Unix(),
+					"email":              "foo@example.com",
+					"email_verified":     true,
+					"preferred_username": "foo",
+					"groups":             []string{"admin", "dev"},
+				})
+			},
+			expect: func(t *testing.T, user *User, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(&User{
+					Issuer:        server.URL,
+					Subject:       "1",
+					Name:          "foo",
+					Email:         "foo@example.com",
+					EmailVerified: true,
+					Groups:        []string{"admin", "dev"},
+				}, user)
+			},
+		},
+		{
+			name: "id token has unverified email",
+			token: func() string {
+				return newMockIDToken(t, key, mockKeyID, jwt.MapClaims{
+					"iss":            server.URL,
+					"aud":            mockClientID,
+					"sub":            "1",
+					"exp":            time.Now().Add(time.Hour).Unix(),
+					"email":          "foo@example.com",
+					"email_verified": "false",
+				})
+			},
+			expect: func(t *testing.T, user *User, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("foo@example.com", user.Email)
+				assert.False(user.EmailVerified)
+			},
+		},
+		{
+			name: "id token is expired",
+			token: func() string {
+				return newMockIDToken(t, key, mockKeyID, jwt.MapClaims{
+					"iss": server.URL,
+					"aud": mockClientID,
+					"sub": "1",
+					"exp": time.Now().Add(-time.Hour).Unix(),
+				})
+			},
+			expect: func(t *testing.T, user *User, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+		{
+			name: "id token has invalid audience",
+			token: func() string {
+				return newMockIDToken(t, key, mockKeyID, jwt.MapClaims{
+					"iss": server.URL,
+					"aud": "baz",
+					"sub": "1",
+					"exp": time.Now().Add(time.Hour).Unix(),
+				})
+			},
+			expect: func(t *testing.T, user *User, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "invalid audience of id token")
+			},
+		},
+		{
+			name: "id token has invalid issuer",
+			token: func() string {
+				return newMockIDToken(t, key, mockKeyID, jwt.MapClaims{
+					"iss": "https://example.com",
+					"aud": mockClientID,
+					"sub": "1",
+					"exp": time.Now().Add(time.Hour).Unix(),
+				})
+			},
+			expect: func(t *testing.T, user *User, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "invalid issuer of id token")
+			},
+		},
+		{
+			name: "id token is signed by unknown key",
+			token: func() string {
+				return newMockIDToken(t, key, "baz", jwt.MapClaims{
+					"iss": server.URL,
+					"aud": mockClientID,
+					"sub": "1",
+					"exp": time.Now().Add(time.Hour).Unix(),
+				})
+			},
+			expect: func(t *testing.T, user *User, err error) {
+				assert := assert.New(t)
+				assert.ErrorContains(err, "key baz of id token not found")
+			},
+		},
+		{
+			name: "id token is signed by hmac",
+			token: func() string {
+				rawIDToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"iss": server.URL,
+					"aud": mockClientID,
+					"sub": "1",
+					"exp": time.Now().Add(time.Hour).Unix(),
+				}).SignedString([]byte("foo"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return rawIDToken
+			},
+			expect: func(t *testing.T, user *User, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := New(config.OIDCConfig{
+				Issuer:      server.URL,
+				ClientID:    mockClientID,
+				GroupsClaim: config.DefaultOIDCGroupsClaim,
+			})
+
+			user, err := o.Verify(context.Background(), tc.token())
+			tc.expect(t, user, err)
+		})
+	}
+}
+
+func TestIsIssuedBy(t *testing.T) {
+	rawToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": "https://example.com/",
+	}).SignedString([]byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := assert.New(t)
+	assert.True(IsIssuedBy(rawToken, "https://example.com"))
+	assert.False(IsIssuedBy(rawToken, "https://example.org"))
+	assert.False(IsIssuedBy("foo", "https://example.com"))
+}
@@ -49,6 +49,9 @@ type Config struct {
 	// Job configuration.
 	Job JobConfig `yaml:"job" mapstructure:"job"`
 
+	// AuditLog configuration.
+	AuditLog AuditLogConfig `yaml:"auditLog" mapstructure:"auditLog"`
+
 	// ObjectStorage configuration.
 	ObjectStorage ObjectStorageConfig `yaml:"objectStorage" mapstructure:"objectStorage"`
 
@@ -91,6 +94,9 @@ type ServerConfig struct {
 type AuthConfig struct {
 	// JWT configuration.
 	JWT JWTConfig `yaml:"jwt" mapstructure:"jwt"`
+
+	// OIDC configuration.
+	OIDC OIDCConfig `yaml:"oidc" mapstructure:"oidc"`
 }
 
 type JWTConfig struct {
@@ -107,6 +113,36 @@ type JWTConfig struct {
 	MaxRefresh time.Duration `yaml:"maxRefresh" mapstructure:"maxRefresh"`
 }
 
+type OIDCConfig struct {
+	// Enable OpenID Connect authentication, users can sign in with the OIDC provider
+	// and call the apis with the id tokens issued by the OIDC provider.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Issuer is the issuer url of the OIDC provider, the provider configuration is
+	// discovered from {issuer}/.well-known/openid-configuration.
+	Issuer string `yaml:"issuer" mapstructure:"issuer"`
+
+	// ClientID is the client id registered in the OIDC provider.
+	ClientID string `yaml:"clientID" mapstructure:"clientID"`
+
+	// ClientSecret is the client secret registered in the OIDC provider.
+	ClientSecret string `yaml:"clientSecret" mapstructure:"clientSecret"`
+
+	// RedirectURL is the callback url of manager registered in the OIDC provider,
+	// e.g. https://manager.example.com/api/v1/oidc/signin/callback.
+	RedirectURL string `yaml:"redirectURL" mapstructure:"redirectURL"`
+
+	// Scopes are the requested scopes, default value is openid, profile, email and groups.
+	Scopes []string `yaml:"scopes" mapstructure:"scopes"`
+
+	// GroupsClaim is the claim of the id token which contains the groups of user, default value is groups.
+	GroupsClaim string `yaml:"groupsClaim" mapstructure:"groupsClaim"`
+
+	// RoleMapping maps the groups of the OIDC provider to the roles of manager, the roles
+	// of user are synchronized at every sign in. Users without mapped groups get the guest role.
+	RoleMapping map[string]string `yaml:"roleMapping" mapstructure:"roleMapping"`
+}
+
 type DatabaseConfig struct {
 	// Database type.
 	Type string `yaml:"type" mapstructure:"type"`
@@ -187,10 +223,27 @@ type PostgresConfig struct {
 	// Server timezone.
 	Timezone string `yaml:"timezone" mapstructure:"timezone"`
 
+	// Schema is the search path of the tables, default is the public schema.
+	Schema string `yaml:"schema" mapstructure:"schema"`
+
+	// Custom TLS client configuration, used by sslMode verify-ca or verify-full.
+	TLS *PostgresTLSClientConfig `yaml:"tls" mapstructure:"tls"`
+
 	// Enable migration.
 	Migrate bool `yaml:"migrate" mapstructure:"migrate"`
 }
 
+type PostgresTLSClientConfig struct {
+	// Client certificate file path.
+	Cert string `yaml:"cert" mapstructure:"cert"`
+
+	// Client key file path.
+	Key string `yaml:"key" mapstructure:"key"`
+
+	// CA file path.
+	CA string `yaml:"ca" mapstructure:"ca"`
+}
+
 type RedisConfig struct {
 	// DEPRECATED: Please use the `addrs` field instead.
 	Host string `yaml:"host" mapstructure:"host"`
@@ -277,6 +330,17 @@ type GRPCConfig struct {
 
 	// Port is listen port.
 	PortRange TCPListenPortRange `yaml:"port" mapstructure:"port"`
+
+	// Auth is the authentication of grpc calls.
+	Auth GRPCAuthConfig `yaml:"auth" mapstructure:"auth"`
+}
+
+type GRPCAuthConfig struct {
+	// Token is the bearer token, empty disables token authentication.
+	Token string `yaml:"token" mapstructure:"token"`
+
+	// HMACSecret is the secret of hmac signature, empty disables hmac authentication.
+	HMACSecret string `yaml:"hmacSecret" mapstructure:"hmacSecret"`
 }
 
 type TCPListenPortRange struct {
@@ -290,12 +354,19 @@ type JobConfig struct {
 
 	// Sync peers configuration.
 	SyncPeers SyncPeersConfig `yaml:"syncPeers" mapstructure:"syncPeers"`
+
+	// Preheat schedule configuration.
+	PreheatSchedule PreheatScheduleConfig `yaml:"preheatSchedule" mapstructure:"preheatSchedule"`
 }
 
 type PreheatConfig struct {
 	// RegistryTimeout is the timeout for requesting registry to get token and manifest.
 	RegistryTimeout time.Duration `yaml:"registryTimeout" mapstructure:"registryTimeout"`
 
+	// TaskTTL is the duration for which the preheated task is counted in the seed peer storage of tenant,
+	// it should be the same as the task expire time of seed peers.
+	TaskTTL time.Duration `yaml:"taskTTL" mapstructure:"taskTTL"`
+
 	// TLS client configuration.
 	TLS *PreheatTLSClientConfig `yaml:"tls" mapstructure:"tls"`
 }
@@ -309,11 +380,27 @@ type SyncPeersConfig struct {
 	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
 }
 
+type PreheatScheduleConfig struct {
+	// Interval is the interval for checking whether the recurring preheat schedules are due.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+}
+
 type PreheatTLSClientConfig struct {
 	// CACert is the CA certificate for preheat tls handshake, it can be path or PEM format string.
 	CACert types.PEMContent `yaml:"caCert" mapstructure:"caCert"`
 }
 
+type AuditLogConfig struct {
+	// Enable records the mutating api calls to the audit log.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Retention is the retention period of the audit logs, the expired audit logs are deleted.
+	Retention time.Duration `yaml:"retention" mapstructure:"retention"`
+
+	// GCInterval is the interval of deleting the expired audit logs.
+	GCInterval time.Duration `yaml:"gcInterval" mapstructure:"gcInterval"`
+}
+
 type ObjectStorageConfig struct {
 	// Enable object storage.
 	Enable bool `yaml:"enable" mapstructure:"enable"`
@@ -346,6 +433,21 @@ type SecurityConfig struct {
 	// AutoIssueCert indicates to issue client certificates for all grpc call.
 	AutoIssueCert bool `yaml:"autoIssueCert" mapstructure:"autoIssueCert"`
 
+	// EmbeddedCA indicates to issue certificates by the CA generated by manager instead of caCert and caKey,
+	// the CA is stored in the database and shared by all of the manager instances.
+	// IssueCertSpec.VerifyMembership is required when EmbeddedCA is true.
+	EmbeddedCA bool `yaml:"embeddedCA" mapstructure:"embeddedCA"`
+
+	// CertFile is the certificate file for grpc tls handshake when AutoIssueCert is false,
+	// the certificate files are reloaded when they are rotated.
+	CertFile string `mapstructure:"certFile" yaml:"certFile"`
+
+	// KeyFile is the private key file of CertFile.
+	KeyFile string `mapstructure:"keyFile" yaml:"keyFile"`
+
+	// CAFile is the CA bundle file of CertFile.
+	CAFile string `mapstructure:"caFile" yaml:"caFile"`
+
 	// CACert is the CA certificate for all grpc tls handshake, it can be path or PEM format string.
 	CACert types.PEMContent `mapstructure:"caCert" yaml:"caCert"`
 
@@ -360,6 +462,9 @@ type SecurityConfig struct {
 
 	// CertSpec is the desired state of certificate.
 	CertSpec CertSpec `mapstructure:"certSpec" yaml:"certSpec"`
+
+	// IssueCertSpec is the spec of certificates issued to the schedulers and peers.
+	IssueCertSpec IssueCertSpec `mapstructure:"issueCertSpec" yaml:"issueCertSpec"`
 }
 
 type CertSpec struct {
@@ -373,6 +478,21 @@ type CertSpec struct {
 	ValidityPeriod time.Duration `mapstructure:"validityPeriod" yaml:"validityPeriod"`
 }
 
+type IssueCertSpec struct {
+	// MaxValidityPeriod is the max validity period of issued certificates, the longer validity
+	// period requested is shortened, and the certificates are rotated by the clients before expired.
+	MaxValidityPeriod time.Duration `mapstructure:"maxValidityPeriod" yaml:"maxValidityPeriod"`
+
+	// VerifyMembership indicates to issue certificates only for the ip addresses of the schedulers,
+	// seed peers and peers in the clusters, or the ip addresses in the cidrs of the scheduler clusters.
+	// The address of the caller is required to be one of the ip addresses of the certificate.
+	VerifyMembership bool `mapstructure:"verifyMembership" yaml:"verifyMembership"`
+
+	// DNSNames is the dns names allowed in the issued certificates, the certificate
+	// requests with the other dns names are rejected.
+	DNSNames []string `mapstructure:"dnsNames" yaml:"dnsNames"`
+}
+
 type NetworkConfig struct {
 	// EnableIPv6 enables ipv6 for server.
 	EnableIPv6 bool `mapstructure:"enableIPv6" yaml:"enableIPv6"`
@@ -408,6 +528,11 @@ func New() *Config {
 				Timeout:    DefaultJWTTimeout,
 				MaxRefresh: DefaultJWTMaxRefresh,
 			},
+			OIDC: OIDCConfig{
+				Enable:      false,
+				Scopes:      DefaultOIDCScopes,
+				GroupsClaim: DefaultOIDCGroupsClaim,
+			},
 		},
 		Database: DatabaseConfig{
 			Type: DatabaseTypeMysql,
@@ -442,11 +567,20 @@ func New() *Config {
 		Job: JobConfig{
 			Preheat: PreheatConfig{
 				RegistryTimeout: DefaultJobPreheatRegistryTimeout,
+				TaskTTL:         DefaultJobPreheatTaskTTL,
 			},
 			SyncPeers: SyncPeersConfig{
 				Interval: DefaultJobSyncPeersInterval,
 				Timeout:  DefaultJobSyncPeersTimeout,
 			},
+			PreheatSchedule: PreheatScheduleConfig{
+				Interval: DefaultJobPreheatScheduleInterval,
+			},
+		},
+		AuditLog: AuditLogConfig{
+			Enable:     true,
+			Retention:  DefaultAuditLogRetention,
+			GCInterval: DefaultAuditLogGCInterval,
 		},
 		ObjectStorage: ObjectStorageConfig{
 			Enable:           false,
@@ -460,6 +594,11 @@ func New() *Config {
 				IPAddresses:    DefaultCertIPAddresses,
 				ValidityPeriod: DefaultCertValidityPeriod,
 			},
+			IssueCertSpec: IssueCertSpec{
+				MaxValidityPeriod: DefaultIssueCertMaxValidityPeriod,
+				VerifyMembership:  false,
+				DNSNames:          DefaultIssueCertDNSNames,
+			},
 		},
 		Metrics: MetricsConfig{
 			Enable: false,
@@ -519,6 +658,28 @@ func (cfg *Config) Validate() error {
 		return errors.New("jwt requires parameter maxRefresh")
 	}
 
+	if cfg.Auth.OIDC.Enable {
+		if cfg.Auth.OIDC.Issuer == "" {
+			return errors.New("oidc requires parameter issuer")
+		}
+
+		if cfg.Auth.OIDC.ClientID == "" {
+			return errors.New("oidc requires parameter clientID")
+		}
+
+		if cfg.Auth.OIDC.RedirectURL == "" {
+			return errors.New("oidc requires parameter redirectURL")
+		}
+
+		if len(cfg.Auth.OIDC.Scopes) == 0 {
+			return errors.New("oidc requires parameter scopes")
+		}
+
+		if cfg.Auth.OIDC.GroupsClaim == "" {
+			return errors.New("oidc requires parameter groupsClaim")
+		}
+	}
+
 	if cfg.Database.Type == "" {
 		return errors.New("database requires parameter type")
 	}
@@ -587,6 +748,20 @@ func (cfg *Config) Validate() error {
 		if cfg.Database.Postgres.Timezone == "" {
 			return errors.New("postgres requires parameter timezone")
 		}
+
+		if cfg.Database.Postgres.TLS != nil {
+			if cfg.Database.Postgres.TLS.CA == "" {
+				return errors.New("tls requires parameter ca")
+			}
+
+			if cfg.Database.Postgres.TLS.Cert != "" && cfg.Database.Postgres.TLS.Key == "" {
+				return errors.New("tls requires parameter key")
+			}
+
+			if cfg.Database.Postgres.TLS.Key != "" && cfg.Database.Postgres.TLS.Cert == "" {
+				return errors.New("tls requires parameter cert")
+			}
+		}
 	}
 
 	if len(cfg.Database.Redis.Addrs) == 0 {
@@ -627,6 +802,10 @@ func (cfg *Config) Validate() error {
 		return errors.New("preheat requires parameter registryTimeout")
 	}
 
+	if cfg.Job.Preheat.TaskTTL <= 0 {
+		return errors.New("preheat requires parameter taskTTL")
+	}
+
 	if cfg.Job.SyncPeers.Interval <= MinJobSyncPeersInterval {
 		return errors.New("syncPeers requires parameter interval and it must be greater than 12 hours")
 	}
@@ -635,6 +814,20 @@ func (cfg *Config) Validate() error {
 		return errors.New("syncPeers requires parameter timeout")
 	}
 
+	if cfg.Job.PreheatSchedule.Interval == 0 {
+		return errors.New("preheatSchedule requires parameter interval")
+	}
+
+	if cfg.AuditLog.Enable {
+		if cfg.AuditLog.Retention == 0 {
+			return errors.New("auditLog requires parameter retention")
+		}
+
+		if cfg.AuditLog.GCInterval == 0 {
+			return errors.New("auditLog requires parameter gcInterval")
+		}
+	}
+
 	if cfg.ObjectStorage.Enable {
 		if cfg.ObjectStorage.Name == "" {
 			return errors.New("objectStorage requires parameter name")
@@ -660,12 +853,24 @@ func (cfg *Config) Validate() error {
 	}
 
 	if cfg.Security.AutoIssueCert {
-		if cfg.Security.CACert == "" {
-			return errors.New("security requires parameter caCert")
-		}
+		if cfg.Security.EmbeddedCA {
+			if cfg.Security.CACert != "" || cfg.Security.CAKey != "" {
+				return errors.New("security embeddedCA conflicts with parameter caCert and caKey")
+			}
+
+			// The embedded CA issues certificates to any caller which can reach the manager,
+			// so the membership of the caller must be verified.
+			if !cfg.Security.IssueCertSpec.VerifyMembership {
+				return errors.New("security embeddedCA requires parameter verifyMembership")
+			}
+		} else {
+			if cfg.Security.CACert == "" {
+				return errors.New("security requires parameter caCert")
+			}
 
-		if cfg.Security.CAKey == "" {
-			return errors.New("security requires parameter caKey")
+			if cfg.Security.CAKey == "" {
+				return errors.New("security requires parameter caKey")
+			}
 		}
 
 		if !slices.Contains([]string{rpc.DefaultTLSPolicy, rpc.ForceTLSPolicy, rpc.PreferTLSPolicy}, cfg.Security.TLSPolicy) {
@@ -683,6 +888,22 @@ func (cfg *Config) Validate() error {
 		if cfg.Security.CertSpec.ValidityPeriod <= 0 {
 			return errors.New("certSpec requires parameter validityPeriod")
 		}
+
+		if cfg.Security.IssueCertSpec.MaxValidityPeriod <= 0 {
+			return errors.New("issueCertSpec requires parameter maxValidityPeriod")
+		}
+	} else if cfg.Security.CertFile != "" {
+		if cfg.Security.KeyFile == "" {
+			return errors.New("security requires parameter keyFile")
+		}
+
+		if cfg.Security.CAFile == "" {
+			return errors.New("security requires parameter caFile")
+		}
+
+		if !slices.Contains([]string{rpc.DefaultTLSPolicy, rpc.ForceTLSPolicy, rpc.PreferTLSPolicy}, cfg.Security.TLSPolicy) {
+			return errors.New("security requires parameter tlsPolicy")
+		}
 	}
 
 	if cfg.Trainer.Enable {
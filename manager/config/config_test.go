@@ -102,6 +102,9 @@ var (
 			IPAddresses:    DefaultCertIPAddresses,
 			ValidityPeriod: DefaultCertValidityPeriod,
 		},
+		IssueCertSpec: IssueCertSpec{
+			MaxValidityPeriod: DefaultIssueCertMaxValidityPeriod,
+		},
 	}
 
 	mockTrainerConfig = TrainerConfig{
@@ -142,6 +145,18 @@ func TestConfig_Load(t *testing.T) {
 				Timeout:    30 * time.Second,
 				MaxRefresh: 1 * time.Minute,
 			},
+			OIDC: OIDCConfig{
+				Enable:       true,
+				Issuer:       "https://foo.com",
+				ClientID:     "foo",
+				ClientSecret: "bar",
+				RedirectURL:  "https://bar.com/api/v1/oidc/signin/callback",
+				Scopes:       []string{"openid", "email"},
+				GroupsClaim:  "roles",
+				RoleMapping: map[string]string{
+					"admin": "root",
+				},
+			},
 		},
 		Database: DatabaseConfig{
 			Type: "mysql",
@@ -169,7 +184,13 @@ func TestConfig_Load(t *testing.T) {
 				SSLMode:              "disable",
 				PreferSimpleProtocol: false,
 				Timezone:             "UTC",
-				Migrate:              true,
+				Schema:               "foo",
+				TLS: &PostgresTLSClientConfig{
+					Cert: "foo",
+					Key:  "foo",
+					CA:   "foo",
+				},
+				Migrate: true,
 			},
 			Redis: RedisConfig{
 				Password:   "bar",
@@ -192,6 +213,7 @@ func TestConfig_Load(t *testing.T) {
 		Job: JobConfig{
 			Preheat: PreheatConfig{
 				RegistryTimeout: DefaultJobPreheatRegistryTimeout,
+				TaskTTL:         DefaultJobPreheatTaskTTL,
 				TLS: &PreheatTLSClientConfig{
 					CACert: "foo",
 				},
@@ -200,6 +222,14 @@ func TestConfig_Load(t *testing.T) {
 				Interval: 13 * time.Hour,
 				Timeout:  2 * time.Minute,
 			},
+			PreheatSchedule: PreheatScheduleConfig{
+				Interval: 30 * time.Second,
+			},
+		},
+		AuditLog: AuditLogConfig{
+			Enable:     true,
+			Retention:  720 * time.Hour,
+			GCInterval: 30 * time.Minute,
 		},
 		ObjectStorage: ObjectStorageConfig{
 			Enable:           true,
@@ -220,6 +250,11 @@ func TestConfig_Load(t *testing.T) {
 				IPAddresses:    []net.IP{net.IPv4zero},
 				ValidityPeriod: 1 * time.Second,
 			},
+			IssueCertSpec: IssueCertSpec{
+				MaxValidityPeriod: 1 * time.Hour,
+				VerifyMembership:  true,
+				DNSNames:          []string{"foo"},
+			},
 		},
 		Metrics: MetricsConfig{
 			Enable: true,
@@ -382,6 +417,45 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "jwt requires parameter maxRefresh")
 			},
 		},
+		{
+			name:   "oidc requires parameter issuer",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Auth.OIDC.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "oidc requires parameter issuer")
+			},
+		},
+		{
+			name:   "oidc requires parameter clientID",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Auth.OIDC.Enable = true
+				cfg.Auth.OIDC.Issuer = "https://foo.com"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "oidc requires parameter clientID")
+			},
+		},
+		{
+			name:   "oidc requires parameter redirectURL",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Auth.OIDC.Enable = true
+				cfg.Auth.OIDC.Issuer = "https://foo.com"
+				cfg.Auth.OIDC.ClientID = "foo"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "oidc requires parameter redirectURL")
+			},
+		},
 		{
 			name:   "database requires parameter type",
 			config: New(),
@@ -610,6 +684,57 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "postgres requires parameter timezone")
 			},
 		},
+		{
+			name:   "postgres tls requires parameter ca",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypePostgres
+				cfg.Database.Postgres = mockPostgresConfig
+				cfg.Database.Postgres.TLS = &PostgresTLSClientConfig{
+					Cert: "foo",
+					Key:  "foo",
+				}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "tls requires parameter ca")
+			},
+		},
+		{
+			name:   "postgres tls requires parameter key",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypePostgres
+				cfg.Database.Postgres = mockPostgresConfig
+				cfg.Database.Postgres.TLS = &PostgresTLSClientConfig{
+					Cert: "foo",
+					CA:   "foo",
+				}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "tls requires parameter key")
+			},
+		},
+		{
+			name:   "postgres tls requires parameter cert",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypePostgres
+				cfg.Database.Postgres = mockPostgresConfig
+				cfg.Database.Postgres.TLS = &PostgresTLSClientConfig{
+					Key: "foo",
+					CA:  "foo",
+				}
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "tls requires parameter cert")
+			},
+		},
 		{
 			name:   "redis requires parameter addrs",
 			config: New(),
@@ -747,6 +872,21 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "preheat requires parameter registryTimeout")
 			},
 		},
+		{
+			name:   "preheat requires parameter taskTTL",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job.Preheat.TaskTTL = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "preheat requires parameter taskTTL")
+			},
+		},
 		{
 			name:   "syncPeers requires parameter interval",
 			config: New(),
@@ -777,6 +917,51 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "syncPeers requires parameter timeout")
 			},
 		},
+		{
+			name:   "preheatSchedule requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job.PreheatSchedule.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "preheatSchedule requires parameter interval")
+			},
+		},
+		{
+			name:   "auditLog requires parameter retention",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.AuditLog.Retention = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "auditLog requires parameter retention")
+			},
+		},
+		{
+			name:   "auditLog requires parameter gcInterval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.AuditLog.GCInterval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "auditLog requires parameter gcInterval")
+			},
+		},
 		{
 			name:   "objectStorage requires parameter name",
 			config: New(),
@@ -953,6 +1138,108 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "certSpec requires parameter validityPeriod")
 			},
 		},
+		{
+			name:   "issueCertSpec requires parameter maxValidityPeriod",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Security = mockSecurityConfig
+				cfg.Security.IssueCertSpec.MaxValidityPeriod = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "issueCertSpec requires parameter maxValidityPeriod")
+			},
+		},
+		{
+			name:   "security requires parameter keyFile",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Security.CertFile = "foo"
+				cfg.Security.CAFile = "bar"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security requires parameter keyFile")
+			},
+		},
+		{
+			name:   "security requires parameter caFile",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Security.CertFile = "foo"
+				cfg.Security.KeyFile = "bar"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security requires parameter caFile")
+			},
+		},
+		{
+			name:   "security embeddedCA conflicts with parameter caCert and caKey",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Security = mockSecurityConfig
+				cfg.Security.EmbeddedCA = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security embeddedCA conflicts with parameter caCert and caKey")
+			},
+		},
+		{
+			name:   "security embeddedCA requires parameter verifyMembership",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Security = mockSecurityConfig
+				cfg.Security.EmbeddedCA = true
+				cfg.Security.CACert = ""
+				cfg.Security.CAKey = ""
+				cfg.Security.IssueCertSpec.VerifyMembership = false
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security embeddedCA requires parameter verifyMembership")
+			},
+		},
+		{
+			name:   "security with embeddedCA does not require parameter caCert and caKey",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Auth.JWT = mockJWTConfig
+				cfg.Database.Type = DatabaseTypeMysql
+				cfg.Database.Mysql = mockMysqlConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Security = mockSecurityConfig
+				cfg.Security.EmbeddedCA = true
+				cfg.Security.CACert = ""
+				cfg.Security.CAKey = ""
+				cfg.Security.IssueCertSpec.VerifyMembership = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+			},
+		},
 		{
 			name:   "trainer requires parameter bucketName",
 			config: New(),
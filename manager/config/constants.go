@@ -59,6 +59,16 @@ const (
 	DefaultJWTMaxRefresh = 2 * 24 * time.Hour
 )
 
+const (
+	// DefaultOIDCGroupsClaim is default of the groups claim in oidc id token.
+	DefaultOIDCGroupsClaim = "groups"
+)
+
+var (
+	// DefaultOIDCScopes is default of the requested scopes in oidc.
+	DefaultOIDCScopes = []string{"openid", "profile", "email", "groups"}
+)
+
 const (
 	// DefaultRedisDB is default db for redis.
 	DefaultRedisDB = 0
@@ -93,6 +103,10 @@ const (
 	// DefaultJobPreheatRegistryTimeout is the default timeout for requesting registry to get token and manifest.
 	DefaultJobPreheatRegistryTimeout = 1 * time.Minute
 
+	// DefaultJobPreheatTaskTTL is the default duration for which the preheated task is counted
+	// in the seed peer storage of tenant, it is the same as the default task expire time of seed peers.
+	DefaultJobPreheatTaskTTL = 6 * time.Hour
+
 	// DefaultJobSyncPeersInterval is the default interval for syncing all peers information from the scheduler.
 	DefaultJobSyncPeersInterval = 24 * time.Hour
 
@@ -101,6 +115,17 @@ const (
 
 	// DefaultJobSyncPeersTimeout is the default timeout for syncing all peers information from the scheduler.
 	DefaultJobSyncPeersTimeout = 10 * time.Minute
+
+	// DefaultJobPreheatScheduleInterval is the default interval for checking whether the preheat schedules are due.
+	DefaultJobPreheatScheduleInterval = 1 * time.Minute
+)
+
+const (
+	// DefaultAuditLogRetention is the default retention period of the audit logs.
+	DefaultAuditLogRetention = 90 * 24 * time.Hour
+
+	// DefaultAuditLogGCInterval is the default interval of deleting the expired audit logs.
+	DefaultAuditLogGCInterval = 1 * time.Hour
 )
 
 const (
@@ -134,6 +159,22 @@ var (
 
 	// DefaultCertValidityPeriod is default validity period of certificate.
 	DefaultCertValidityPeriod = 10 * 365 * 24 * time.Hour
+
+	// DefaultIssueCertMaxValidityPeriod is default max validity period of issued certificate.
+	DefaultIssueCertMaxValidityPeriod = 24 * time.Hour
+
+	// DefaultIssueCertDNSNames is default dns names allowed in the issued certificates,
+	// they are the default dns names of the peers, schedulers and trainers.
+	DefaultIssueCertDNSNames = []string{"dragonfly-peer", "dragonfly-peer.dragonfly-system.svc", "dragonfly-peer.dragonfly-system.svc.cluster.local",
+		"dragonfly-seed-peer", "dragonfly-seed-peer.dragonfly-system.svc", "dragonfly-seed-peer.dragonfly-system.svc.cluster.local",
+		"dragonfly-proxy", "dragonfly-proxy.dragonfly-system.svc", "dragonfly-proxy.dragonfly-system.svc.cluster.local",
+		"dragonfly-dfdaemon", "dragonfly-dfdaemon.dragonfly-system.svc", "dragonfly-dfdaemon.dragonfly-system.svc.cluster.local",
+		"dragonfly-scheduler", "dragonfly-scheduler.dragonfly-system.svc", "dragonfly-scheduler.dragonfly-system.svc.cluster.local",
+		"dragonfly-trainer", "dragonfly-trainer.dragonfly-system.svc", "dragonfly-trainer.dragonfly-system.svc.cluster.local",
+	}
+
+	// DefaultEmbeddedCAValidityPeriod is default validity period of embedded CA.
+	DefaultEmbeddedCAValidityPeriod = 10 * 365 * 24 * time.Hour
 )
 
 var (
@@ -19,6 +19,7 @@ package database
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
@@ -27,7 +28,9 @@ import (
 	"d7y.io/dragonfly/v2/manager/config"
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/issuer"
 	pkgredis "d7y.io/dragonfly/v2/pkg/redis"
+	pkgtypes "d7y.io/dragonfly/v2/pkg/types"
 	schedulerconfig "d7y.io/dragonfly/v2/scheduler/config"
 )
 
@@ -95,6 +98,14 @@ func migrate(db *gorm.DB) error {
 		&models.Model{},
 		&models.PersonalAccessToken{},
 		&models.Peer{},
+		&models.PreheatSchedule{},
+		&models.Tenant{},
+		&models.TenantTask{},
+		&models.AuditLog{},
+		&models.Webhook{},
+		&models.ClusterConfigVersion{},
+		&models.FeatureFlag{},
+		&models.CertificateAuthority{},
 	)
 }
 
@@ -114,6 +125,7 @@ func seed(cfg *config.Config, db *gorm.DB) error {
 			Config: map[string]any{
 				"candidate_parent_limit": schedulerconfig.DefaultSchedulerCandidateParentLimit,
 				"filter_parent_limit":    schedulerconfig.DefaultSchedulerFilterParentLimit,
+				"evaluator_weights":      types.DefaultSchedulerClusterEvaluatorWeights,
 			},
 			ClientConfig: map[string]any{
 				"load_limit":             schedulerconfig.DefaultPeerConcurrentUploadLimit,
@@ -160,6 +172,18 @@ func seed(cfg *config.Config, db *gorm.DB) error {
 		}
 	}
 
+	// Record the initial config version of the clusters without config version.
+	if err := seedClusterConfigVersions(db); err != nil {
+		return err
+	}
+
+	// Generate the embedded CA for issuing certificates.
+	if cfg.Security.AutoIssueCert && cfg.Security.EmbeddedCA {
+		if err := seedCertificateAuthority(db); err != nil {
+			return err
+		}
+	}
+
 	// TODO Compatible with old version.
 	// Update scheduler features when features is NULL.
 	var schedulers []models.Scheduler
@@ -184,3 +208,90 @@ func seed(cfg *config.Config, db *gorm.DB) error {
 
 	return nil
 }
+
+// seedClusterConfigVersions records the current config as the first version
+// for the clusters created before config versioning.
+func seedClusterConfigVersions(db *gorm.DB) error {
+	var schedulerClusters []models.SchedulerCluster
+	if err := db.Find(&schedulerClusters).Error; err != nil {
+		return err
+	}
+
+	for _, schedulerCluster := range schedulerClusters {
+		if err := seedClusterConfigVersion(db, models.ClusterConfigVersionTypeSchedulerCluster, schedulerCluster.ID, schedulerCluster.Config, schedulerCluster.ClientConfig); err != nil {
+			return err
+		}
+	}
+
+	var seedPeerClusters []models.SeedPeerCluster
+	if err := db.Find(&seedPeerClusters).Error; err != nil {
+		return err
+	}
+
+	for _, seedPeerCluster := range seedPeerClusters {
+		if err := seedClusterConfigVersion(db, models.ClusterConfigVersionTypeSeedPeerCluster, seedPeerCluster.ID, seedPeerCluster.Config, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedClusterConfigVersion records the first config version if the cluster has no config version.
+func seedClusterConfigVersion(db *gorm.DB, clusterType string, clusterID uint, config, clientConfig models.JSONMap) error {
+	var count int64
+	if err := db.Model(models.ClusterConfigVersion{}).Where(&models.ClusterConfigVersion{
+		ClusterType: clusterType,
+		ClusterID:   clusterID,
+	}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	return db.Create(&models.ClusterConfigVersion{
+		ClusterType:  clusterType,
+		ClusterID:    clusterID,
+		Version:      1,
+		Config:       config,
+		ClientConfig: clientConfig,
+		BIO:          "initial config",
+	}).Error
+}
+
+// seedCertificateAuthority generates the embedded CA if it does not exist, the CA
+// created by the other manager instance simultaneously is kept.
+func seedCertificateAuthority(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(models.CertificateAuthority{}).Where("name = ?", models.CertificateAuthorityNameEmbedded).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	cert, key, err := issuer.GenerateCA(pkgtypes.ManagerName, config.DefaultEmbeddedCAValidityPeriod)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Create(&models.CertificateAuthority{
+		Name:       models.CertificateAuthorityNameEmbedded,
+		Cert:       string(cert),
+		PrivateKey: string(key),
+		ExpiredAt:  time.Now().Add(config.DefaultEmbeddedCAValidityPeriod),
+	}).Error; err != nil {
+		// The CA is created by the other manager instance.
+		if db.Model(models.CertificateAuthority{}).Where("name = ?", models.CertificateAuthorityNameEmbedded).Count(&count).Error == nil && count > 0 {
+			return nil
+		}
+
+		return err
+	}
+
+	logger.Info("generate embedded certificate authority")
+	return nil
+}
@@ -21,11 +21,13 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 	"moul.io/zapgorm2"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/manager/config"
+	"d7y.io/dragonfly/v2/manager/models"
 )
 
 func newPostgres(cfg *config.Config) (*gorm.DB, error) {
@@ -34,6 +36,13 @@ func newPostgres(cfg *config.Config) (*gorm.DB, error) {
 	// Format dsn string.
 	dsn := formatPostgresDSN(postgresCfg)
 
+	// Initialize gorm logger.
+	logLevel := gormlogger.Info
+	if !cfg.Verbose {
+		logLevel = gormlogger.Warn
+	}
+	gormLogger := zapgorm2.New(logger.CoreLogger.Desugar()).LogMode(logLevel)
+
 	// Connect to postgres.
 	db, err := gorm.Open(postgres.New(postgres.Config{
 		DSN:                  dsn,
@@ -43,7 +52,7 @@ func newPostgres(cfg *config.Config) (*gorm.DB, error) {
 			SingularTable: true,
 		},
 		DisableForeignKeyConstraintWhenMigrating: true,
-		Logger:                                   zapgorm2.New(logger.CoreLogger.Desugar()),
+		Logger:                                   gormLogger,
 	})
 	if err != nil {
 		return nil, err
@@ -61,11 +70,17 @@ func newPostgres(cfg *config.Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// Seed inserts the default clusters with explicit ids, which does not advance
+	// the sequences of postgres, so sync the sequences with the max ids.
+	if err := syncPostgresSequences(db, &models.SchedulerCluster{}, &models.SeedPeerCluster{}); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
 func formatPostgresDSN(cfg *config.PostgresConfig) string {
-	return fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=%v TimeZone=%v",
+	dsn := fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v sslmode=%v TimeZone=%v",
 		cfg.Host,
 		cfg.User,
 		cfg.Password,
@@ -74,4 +89,36 @@ func formatPostgresDSN(cfg *config.PostgresConfig) string {
 		cfg.SSLMode,
 		cfg.Timezone,
 	)
+
+	// Support tables in the custom schema.
+	if cfg.Schema != "" {
+		dsn = fmt.Sprintf("%s search_path=%v", dsn, cfg.Schema)
+	}
+
+	// Support TLS connection.
+	if cfg.TLS != nil {
+		dsn = fmt.Sprintf("%s sslrootcert=%v", dsn, cfg.TLS.CA)
+		if cfg.TLS.Cert != "" {
+			dsn = fmt.Sprintf("%s sslcert=%v sslkey=%v", dsn, cfg.TLS.Cert, cfg.TLS.Key)
+		}
+	}
+
+	return dsn
+}
+
+// syncPostgresSequences sets the sequences of the primary keys to the max ids of the tables.
+func syncPostgresSequences(db *gorm.DB, values ...any) error {
+	for _, value := range values {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(value); err != nil {
+			return err
+		}
+
+		table := stmt.Schema.Table
+		if err := db.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE((SELECT MAX(id) FROM %s), 0) + 1, false)", table, table)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
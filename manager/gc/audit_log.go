@@ -0,0 +1,77 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gc
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/config"
+	"d7y.io/dragonfly/v2/manager/models"
+	pkggc "d7y.io/dragonfly/v2/pkg/gc"
+)
+
+const (
+	// AuditLogGCID is the id of the gc task of deleting the expired audit logs.
+	AuditLogGCID = "audit-log"
+)
+
+// auditLog deletes the audit logs which exceed the retention period.
+type auditLog struct {
+	// db is the database of manager.
+	db *gorm.DB
+
+	// retention is the retention period of the audit logs.
+	retention time.Duration
+
+	// timeout is the timeout of deleting the expired audit logs.
+	timeout time.Duration
+}
+
+// NewAuditLog adds the gc task of deleting the expired audit logs.
+func NewAuditLog(gc pkggc.GC, db *gorm.DB, cfg config.AuditLogConfig) error {
+	a := &auditLog{
+		db:        db,
+		retention: cfg.Retention,
+		timeout:   cfg.GCInterval,
+	}
+
+	return gc.Add(pkggc.Task{
+		ID:       AuditLogGCID,
+		Interval: cfg.GCInterval,
+		Timeout:  cfg.GCInterval,
+		Runner:   a,
+	})
+}
+
+// RunGC deletes the audit logs created before the retention period.
+func (a *auditLog) RunGC() error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	expiredAt := time.Now().Add(-a.retention)
+	result := a.db.WithContext(ctx).Unscoped().Where("created_at < ?", expiredAt).Delete(&models.AuditLog{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	logger.Infof("delete %d audit logs created before %s", result.RowsAffected, expiredAt.Format(time.RFC3339))
+	return nil
+}
@@ -18,6 +18,7 @@ package handlers
 
 import (
 	"net/http"
+	"regexp"
 
 	"github.com/gin-gonic/gin"
 
@@ -44,6 +45,11 @@ func (h *Handlers) CreateApplication(ctx *gin.Context) {
 		return
 	}
 
+	if _, err := regexp.Compile(json.AllowedURLRegex); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
 	application, err := h.service.CreateApplication(ctx.Request.Context(), json)
 	if err != nil {
 		ctx.Error(err) // nolint: errcheck
@@ -104,6 +110,13 @@ func (h *Handlers) UpdateApplication(ctx *gin.Context) {
 		return
 	}
 
+	if json.AllowedURLRegex != nil {
+		if _, err := regexp.Compile(*json.AllowedURLRegex); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+	}
+
 	application, err := h.service.UpdateApplication(ctx.Request.Context(), params.ID, json)
 	if err != nil {
 		ctx.Error(err) // nolint: errcheck
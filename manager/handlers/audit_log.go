@@ -0,0 +1,179 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// auditLogCSVHeader is the header of the exported audit logs in csv format.
+var auditLogCSVHeader = []string{
+	"id", "created_at", "user_id", "user_name", "ip", "method", "path", "resource",
+	"resource_id", "action", "status_code", "request", "before", "after", "diff",
+}
+
+// @Summary Get AuditLog
+// @Description Get AuditLog by id
+// @Tags AuditLog
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.AuditLog
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /audit-logs/{id} [get]
+func (h *Handlers) GetAuditLog(ctx *gin.Context) {
+	var params types.AuditLogParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	auditLog, err := h.service.GetAuditLog(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, auditLog)
+}
+
+// @Summary Get AuditLogs
+// @Description Get AuditLogs
+// @Tags AuditLog
+// @Accept json
+// @Produce json
+// @Param page query int true "current page" default(0)
+// @Param per_page query int true "return max item count, default 10, max 50" default(10) minimum(2) maximum(50)
+// @Success 200 {object} []models.AuditLog
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /audit-logs [get]
+func (h *Handlers) GetAuditLogs(ctx *gin.Context) {
+	var query types.GetAuditLogsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	h.setPaginationDefault(&query.Page, &query.PerPage)
+	auditLogs, count, err := h.service.GetAuditLogs(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
+	ctx.JSON(http.StatusOK, auditLogs)
+}
+
+// @Summary Export AuditLogs
+// @Description Export AuditLogs in csv or json format
+// @Tags AuditLog
+// @Produce text/csv
+// @Produce json
+// @Param format query string false "export format, csv or json" default(csv)
+// @Success 200 {object} []models.AuditLog
+// @Failure 400
+// @Failure 500
+// @Router /audit-logs/export [get]
+func (h *Handlers) ExportAuditLogs(ctx *gin.Context) {
+	var query types.ExportAuditLogsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	auditLogs, err := h.service.ExportAuditLogs(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	if query.Format == types.AuditLogExportFormatJSON {
+		ctx.Header("Content-Disposition", "attachment; filename=audit-logs.json")
+		ctx.JSON(http.StatusOK, auditLogs)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=audit-logs.csv")
+	ctx.Header("Content-Type", "text/csv")
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+	if err := w.Write(auditLogCSVHeader); err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	for _, auditLog := range auditLogs {
+		if err := w.Write(auditLogCSVRecord(auditLog)); err != nil {
+			ctx.Error(err) // nolint: errcheck
+			return
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		ctx.Error(err) // nolint: errcheck
+	}
+}
+
+// auditLogCSVRecord returns the csv record of the audit log, the json fields are encoded as json strings.
+func auditLogCSVRecord(auditLog models.AuditLog) []string {
+	encode := func(m models.JSONMap) string {
+		if m == nil {
+			return ""
+		}
+
+		b, err := json.Marshal(m)
+		if err != nil {
+			return ""
+		}
+
+		return string(b)
+	}
+
+	return []string{
+		fmt.Sprint(auditLog.ID),
+		auditLog.CreatedAt.Format(time.RFC3339),
+		fmt.Sprint(auditLog.UserID),
+		auditLog.UserName,
+		auditLog.IP,
+		auditLog.Method,
+		auditLog.Path,
+		auditLog.Resource,
+		auditLog.ResourceID,
+		auditLog.Action,
+		fmt.Sprint(auditLog.StatusCode),
+		encode(auditLog.Request),
+		encode(auditLog.Before),
+		encode(auditLog.After),
+		encode(auditLog.Diff),
+	}
+}
@@ -0,0 +1,43 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get CA Certificate
+// @Description Get the PEM encoded CA certificate used to issue peer certificates
+// @Tags Certificate
+// @Accept json
+// @Produce application/x-pem-file
+// @Success 200 {string} string
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /certificates/ca [get]
+func (h *Handlers) GetCACertificate(ctx *gin.Context) {
+	cert, err := h.service.GetCACertificate(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/x-pem-file", cert)
+}
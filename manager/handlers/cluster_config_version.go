@@ -0,0 +1,283 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	// nolint
+	_ "d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// @Summary Get SchedulerCluster Config Versions
+// @Description Get config versions of the SchedulerCluster
+// @Tags SchedulerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param page query int true "current page" default(0)
+// @Param per_page query int true "return max item count, default 10, max 50" default(10) minimum(2) maximum(50)
+// @Success 200 {object} []models.ClusterConfigVersion
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /scheduler-clusters/{id}/config-versions [get]
+func (h *Handlers) GetSchedulerClusterConfigVersions(ctx *gin.Context) {
+	var params types.SchedulerClusterParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var query types.GetClusterConfigVersionsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	h.setPaginationDefault(&query.Page, &query.PerPage)
+	clusterConfigVersions, count, err := h.service.GetSchedulerClusterConfigVersions(ctx.Request.Context(), params.ID, query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
+	ctx.JSON(http.StatusOK, clusterConfigVersions)
+}
+
+// @Summary Get SchedulerCluster Config Version
+// @Description Get config version of the SchedulerCluster by version
+// @Tags SchedulerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param version path string true "version"
+// @Success 200 {object} models.ClusterConfigVersion
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /scheduler-clusters/{id}/config-versions/{version} [get]
+func (h *Handlers) GetSchedulerClusterConfigVersion(ctx *gin.Context) {
+	var params types.ClusterConfigVersionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	clusterConfigVersion, err := h.service.GetSchedulerClusterConfigVersion(ctx.Request.Context(), params.ID, params.Version)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, clusterConfigVersion)
+}
+
+// @Summary Get SchedulerCluster Config Version Diff
+// @Description Get changes of the config version compared with the base version, default base is the previous version
+// @Tags SchedulerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param version path string true "version"
+// @Param base query int false "base version"
+// @Success 200 {object} types.ClusterConfigVersionDiff
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /scheduler-clusters/{id}/config-versions/{version}/diff [get]
+func (h *Handlers) GetSchedulerClusterConfigVersionDiff(ctx *gin.Context) {
+	var params types.ClusterConfigVersionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var query types.GetClusterConfigVersionDiffQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	diff, err := h.service.GetSchedulerClusterConfigVersionDiff(ctx.Request.Context(), params.ID, params.Version, query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// @Summary Rollback SchedulerCluster Config
+// @Description Rollback config of the SchedulerCluster to the version
+// @Tags SchedulerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param version path string true "version"
+// @Success 200 {object} models.SchedulerCluster
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /scheduler-clusters/{id}/config-versions/{version}/rollback [post]
+func (h *Handlers) RollbackSchedulerClusterConfig(ctx *gin.Context) {
+	var params types.ClusterConfigVersionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	schedulerCluster, err := h.service.RollbackSchedulerClusterConfig(ctx.Request.Context(), params.ID, params.Version)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedulerCluster)
+}
+
+// @Summary Get SeedPeerCluster Config Versions
+// @Description Get config versions of the SeedPeerCluster
+// @Tags SeedPeerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param page query int true "current page" default(0)
+// @Param per_page query int true "return max item count, default 10, max 50" default(10) minimum(2) maximum(50)
+// @Success 200 {object} []models.ClusterConfigVersion
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /seed-peer-clusters/{id}/config-versions [get]
+func (h *Handlers) GetSeedPeerClusterConfigVersions(ctx *gin.Context) {
+	var params types.SeedPeerClusterParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var query types.GetClusterConfigVersionsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	h.setPaginationDefault(&query.Page, &query.PerPage)
+	clusterConfigVersions, count, err := h.service.GetSeedPeerClusterConfigVersions(ctx.Request.Context(), params.ID, query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
+	ctx.JSON(http.StatusOK, clusterConfigVersions)
+}
+
+// @Summary Get SeedPeerCluster Config Version
+// @Description Get config version of the SeedPeerCluster by version
+// @Tags SeedPeerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param version path string true "version"
+// @Success 200 {object} models.ClusterConfigVersion
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /seed-peer-clusters/{id}/config-versions/{version} [get]
+func (h *Handlers) GetSeedPeerClusterConfigVersion(ctx *gin.Context) {
+	var params types.ClusterConfigVersionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	clusterConfigVersion, err := h.service.GetSeedPeerClusterConfigVersion(ctx.Request.Context(), params.ID, params.Version)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, clusterConfigVersion)
+}
+
+// @Summary Get SeedPeerCluster Config Version Diff
+// @Description Get changes of the config version compared with the base version, default base is the previous version
+// @Tags SeedPeerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param version path string true "version"
+// @Param base query int false "base version"
+// @Success 200 {object} types.ClusterConfigVersionDiff
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /seed-peer-clusters/{id}/config-versions/{version}/diff [get]
+func (h *Handlers) GetSeedPeerClusterConfigVersionDiff(ctx *gin.Context) {
+	var params types.ClusterConfigVersionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var query types.GetClusterConfigVersionDiffQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	diff, err := h.service.GetSeedPeerClusterConfigVersionDiff(ctx.Request.Context(), params.ID, params.Version, query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// @Summary Rollback SeedPeerCluster Config
+// @Description Rollback config of the SeedPeerCluster to the version
+// @Tags SeedPeerCluster
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param version path string true "version"
+// @Success 200 {object} models.SeedPeerCluster
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /seed-peer-clusters/{id}/config-versions/{version}/rollback [post]
+func (h *Handlers) RollbackSeedPeerClusterConfig(ctx *gin.Context) {
+	var params types.ClusterConfigVersionParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	seedPeerCluster, err := h.service.RollbackSeedPeerClusterConfig(ctx.Request.Context(), params.ID, params.Version)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, seedPeerCluster)
+}
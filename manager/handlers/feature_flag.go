@@ -0,0 +1,171 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	// nolint
+	_ "d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// @Summary Create FeatureFlag
+// @Description Create by json config
+// @Tags FeatureFlag
+// @Accept json
+// @Produce json
+// @Param FeatureFlag body types.CreateFeatureFlagRequest true "FeatureFlag"
+// @Success 200 {object} models.FeatureFlag
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /feature-flags [post]
+func (h *Handlers) CreateFeatureFlag(ctx *gin.Context) {
+	var json types.CreateFeatureFlagRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	featureFlag, err := h.service.CreateFeatureFlag(ctx.Request.Context(), json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, featureFlag)
+}
+
+// @Summary Destroy FeatureFlag
+// @Description Destroy by id
+// @Tags FeatureFlag
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /feature-flags/{id} [delete]
+func (h *Handlers) DestroyFeatureFlag(ctx *gin.Context) {
+	var params types.FeatureFlagParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	if err := h.service.DestroyFeatureFlag(ctx.Request.Context(), params.ID); err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// @Summary Update FeatureFlag
+// @Description Update by json config
+// @Tags FeatureFlag
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param FeatureFlag body types.UpdateFeatureFlagRequest true "FeatureFlag"
+// @Success 200 {object} models.FeatureFlag
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /feature-flags/{id} [patch]
+func (h *Handlers) UpdateFeatureFlag(ctx *gin.Context) {
+	var params types.FeatureFlagParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var json types.UpdateFeatureFlagRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	featureFlag, err := h.service.UpdateFeatureFlag(ctx.Request.Context(), params.ID, json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, featureFlag)
+}
+
+// @Summary Get FeatureFlag
+// @Description Get FeatureFlag by id
+// @Tags FeatureFlag
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.FeatureFlag
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /feature-flags/{id} [get]
+func (h *Handlers) GetFeatureFlag(ctx *gin.Context) {
+	var params types.FeatureFlagParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	featureFlag, err := h.service.GetFeatureFlag(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, featureFlag)
+}
+
+// @Summary Get FeatureFlags
+// @Description Get FeatureFlags
+// @Tags FeatureFlag
+// @Accept json
+// @Produce json
+// @Param page query int true "current page" default(0)
+// @Param per_page query int true "return max item count, default 10, max 50" default(10) minimum(2) maximum(50)
+// @Success 200 {object} []models.FeatureFlag
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /feature-flags [get]
+func (h *Handlers) GetFeatureFlags(ctx *gin.Context) {
+	var query types.GetFeatureFlagsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	h.setPaginationDefault(&query.Page, &query.PerPage)
+	featureFlags, count, err := h.service.GetFeatureFlags(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
+	ctx.JSON(http.StatusOK, featureFlags)
+}
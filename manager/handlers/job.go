@@ -17,8 +17,11 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
+	"time"
 
+	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 
@@ -27,8 +30,13 @@ import (
 	"d7y.io/dragonfly/v2/manager/types"
 )
 
+const (
+	// preheatJobProgressInterval is the interval of pushing preheat job progress events.
+	preheatJobProgressInterval = time.Second
+)
+
 // @Summary Create Job
-// @Description Create by json config
+// @Description Create by json config, the task is looked up by url and digest across scheduler clusters with the get_task job, and purged from the seed peers, peers and scheduler state with the delete_task job
 // @Tags Job
 // @Accept json
 // @Produce json
@@ -59,6 +67,76 @@ func (h *Handlers) CreateJob(ctx *gin.Context) {
 			return
 		}
 
+		ctx.JSON(http.StatusOK, job)
+	case job.DrainHostJob:
+		var json types.CreateDrainHostJobRequest
+		if err := ctx.ShouldBindBodyWith(&json, binding.JSON); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+
+		job, err := h.service.CreateDrainHostJob(ctx.Request.Context(), json)
+		if err != nil {
+			ctx.Error(err) // nolint: errcheck
+			return
+		}
+
+		ctx.JSON(http.StatusOK, job)
+	case job.ReannounceHostJob:
+		var json types.CreateReannounceHostJobRequest
+		if err := ctx.ShouldBindBodyWith(&json, binding.JSON); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+
+		job, err := h.service.CreateReannounceHostJob(ctx.Request.Context(), json)
+		if err != nil {
+			ctx.Error(err) // nolint: errcheck
+			return
+		}
+
+		ctx.JSON(http.StatusOK, job)
+	case job.HostReputationJob:
+		var json types.CreateHostReputationJobRequest
+		if err := ctx.ShouldBindBodyWith(&json, binding.JSON); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+
+		job, err := h.service.CreateHostReputationJob(ctx.Request.Context(), json)
+		if err != nil {
+			ctx.Error(err) // nolint: errcheck
+			return
+		}
+
+		ctx.JSON(http.StatusOK, job)
+	case job.DeleteTaskJob:
+		var json types.CreateDeleteTaskJobRequest
+		if err := ctx.ShouldBindBodyWith(&json, binding.JSON); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+
+		job, err := h.service.CreateDeleteTaskJob(ctx.Request.Context(), json)
+		if err != nil {
+			ctx.Error(err) // nolint: errcheck
+			return
+		}
+
+		ctx.JSON(http.StatusOK, job)
+	case job.GetTaskJob:
+		var json types.CreateGetTaskJobRequest
+		if err := ctx.ShouldBindBodyWith(&json, binding.JSON); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+
+		job, err := h.service.CreateGetTaskJob(ctx.Request.Context(), json)
+		if err != nil {
+			ctx.Error(err) // nolint: errcheck
+			return
+		}
+
 		ctx.JSON(http.StatusOK, job)
 	default:
 		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": "Unknow type"})
@@ -152,6 +230,109 @@ func (h *Handlers) GetJob(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, job)
 }
 
+// @Summary Get Preheat Job Progress
+// @Description Get per layer and per seed peer progress of the preheat job by id
+// @Tags Job
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.PreheatJobProgress
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /jobs/{id}/progress [get]
+func (h *Handlers) GetPreheatJobProgress(ctx *gin.Context) {
+	var params types.JobParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	progress, err := h.service.GetPreheatJobProgress(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, progress)
+}
+
+// @Summary Get Task Job Result
+// @Description Get the seed peers and peers which hold the task of the get task job by id
+// @Tags Job
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} types.GetTaskJobResult
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /jobs/{id}/task [get]
+func (h *Handlers) GetTaskJobResult(ctx *gin.Context) {
+	var params types.JobParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	result, err := h.service.GetTaskJobResult(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// @Summary Stream Preheat Job Progress
+// @Description Stream progress of the preheat job by id with server-sent events until the job is finished
+// @Tags Job
+// @Accept json
+// @Produce text/event-stream
+// @Param id path string true "id"
+// @Success 200 {object} types.PreheatJobProgress
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /jobs/{id}/progress/stream [get]
+func (h *Handlers) StreamPreheatJobProgress(ctx *gin.Context) {
+	var params types.JobParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	// Make sure the job exists before the event stream starts.
+	progress, err := h.service.GetPreheatJobProgress(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ticker := time.NewTicker(preheatJobProgressInterval)
+	defer ticker.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		ctx.SSEvent("progress", progress)
+		if progress.State == machineryv1tasks.StateSuccess || progress.State == machineryv1tasks.StateFailure {
+			return false
+		}
+
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case <-ticker.C:
+		}
+
+		if progress, err = h.service.GetPreheatJobProgress(ctx.Request.Context(), params.ID); err != nil {
+			ctx.SSEvent("error", gin.H{"errors": err.Error()})
+			return false
+		}
+
+		return true
+	})
+}
+
 // @Summary Get Jobs
 // @Description Get Jobs
 // @Tags Job
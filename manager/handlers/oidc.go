@@ -0,0 +1,125 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+const (
+	// oidcStateCookieName is the cookie name of the oidc state, it prevents
+	// the signin callback from cross-site request forgery.
+	oidcStateCookieName = "oidc_state"
+
+	// oidcStateCookieMaxAge is the max age of the oidc state cookie in seconds.
+	oidcStateCookieMaxAge = 600
+)
+
+// @Summary OIDC Signin
+// @Description Redirect to the OIDC provider to signin
+// @Tags OIDC
+// @Success 302
+// @Failure 400
+// @Failure 500
+// @Router /oidc/signin [get]
+func (h *Handlers) OIDCSignin(ctx *gin.Context) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+	state := base64.RawURLEncoding.EncodeToString(b)
+
+	authURL, err := h.service.OIDCSignin(ctx.Request.Context(), state)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.SetCookie(oidcStateCookieName, state, oidcStateCookieMaxAge, "/", "", false, true)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+// @Summary OIDC Signin Callback
+// @Description Signin callback of the OIDC provider, the user is signed in manager
+// @Tags OIDC
+// @Param code query string true "code"
+// @Param state query string true "state"
+// @Success 302
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Router /oidc/signin/callback [get]
+func (h *Handlers) OIDCSigninCallback(j *jwt.GinJWTMiddleware) func(*gin.Context) {
+	return func(ctx *gin.Context) {
+		var query types.OIDCSigninCallbackQuery
+		if err := ctx.ShouldBindQuery(&query); err != nil {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+			return
+		}
+
+		state, err := ctx.Cookie(oidcStateCookieName)
+		if err != nil || state != query.State {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"errors": "invalid oidc state"})
+			return
+		}
+		ctx.SetCookie(oidcStateCookieName, "", -1, "/", "", false, true)
+
+		user, err := h.service.OIDCSigninCallback(ctx.Request.Context(), query.Code)
+		if err != nil {
+			ctx.Error(err) // nolint: errcheck
+			return
+		}
+
+		ctx.Set("user", user)
+		j.LoginHandler(ctx)
+	}
+}
+
+// @Summary OIDC Refresh Token
+// @Description Refresh the id token by the refresh token of the OIDC provider
+// @Tags OIDC
+// @Accept json
+// @Produce json
+// @Param OIDCRefreshToken body types.OIDCRefreshTokenRequest true "OIDCRefreshToken"
+// @Success 200 {object} types.OIDCToken
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Router /oidc/refresh_token [post]
+func (h *Handlers) OIDCRefreshToken(ctx *gin.Context) {
+	var json types.OIDCRefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	token, err := h.service.OIDCRefreshToken(ctx.Request.Context(), json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, token)
+}
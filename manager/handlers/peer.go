@@ -135,3 +135,30 @@ func (h *Handlers) GetPeers(ctx *gin.Context) {
 	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
 	ctx.JSON(http.StatusOK, peers)
 }
+
+// @Summary Batch Peers
+// @Description Tag, drain, reannounce or delete peers in batch
+// @Tags Peer
+// @Accept json
+// @Produce json
+// @Param Peer body types.BatchPeersRequest true "Peer"
+// @Success 200 {object} types.BatchPeersResponse
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /peers/batch [post]
+func (h *Handlers) BatchPeers(ctx *gin.Context) {
+	var json types.BatchPeersRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	resp, err := h.service.BatchPeers(ctx.Request.Context(), json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,262 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	// nolint
+	_ "d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// @Summary Create PreheatSchedule
+// @Description Create by json config
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param PreheatSchedule body types.CreatePreheatScheduleRequest true "PreheatSchedule"
+// @Success 200 {object} models.PreheatSchedule
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules [post]
+func (h *Handlers) CreatePreheatSchedule(ctx *gin.Context) {
+	var json types.CreatePreheatScheduleRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	preheatSchedule, err := h.service.CreatePreheatSchedule(ctx.Request.Context(), json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preheatSchedule)
+}
+
+// @Summary Destroy PreheatSchedule
+// @Description Destroy by id
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules/{id} [delete]
+func (h *Handlers) DestroyPreheatSchedule(ctx *gin.Context) {
+	var params types.PreheatScheduleParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	if err := h.service.DestroyPreheatSchedule(ctx.Request.Context(), params.ID); err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// @Summary Update PreheatSchedule
+// @Description Update by json config
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param PreheatSchedule body types.UpdatePreheatScheduleRequest true "PreheatSchedule"
+// @Success 200 {object} models.PreheatSchedule
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules/{id} [patch]
+func (h *Handlers) UpdatePreheatSchedule(ctx *gin.Context) {
+	var params types.PreheatScheduleParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var json types.UpdatePreheatScheduleRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	preheatSchedule, err := h.service.UpdatePreheatSchedule(ctx.Request.Context(), params.ID, json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preheatSchedule)
+}
+
+// @Summary Pause PreheatSchedule
+// @Description Pause by id, no preheat job will be created until it is resumed
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.PreheatSchedule
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules/{id}/pause [post]
+func (h *Handlers) PausePreheatSchedule(ctx *gin.Context) {
+	var params types.PreheatScheduleParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	preheatSchedule, err := h.service.PausePreheatSchedule(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preheatSchedule)
+}
+
+// @Summary Resume PreheatSchedule
+// @Description Resume by id
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.PreheatSchedule
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules/{id}/resume [post]
+func (h *Handlers) ResumePreheatSchedule(ctx *gin.Context) {
+	var params types.PreheatScheduleParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	preheatSchedule, err := h.service.ResumePreheatSchedule(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preheatSchedule)
+}
+
+// @Summary Get PreheatSchedule
+// @Description Get PreheatSchedule by id
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.PreheatSchedule
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules/{id} [get]
+func (h *Handlers) GetPreheatSchedule(ctx *gin.Context) {
+	var params types.PreheatScheduleParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	preheatSchedule, err := h.service.GetPreheatSchedule(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preheatSchedule)
+}
+
+// @Summary Get PreheatSchedules
+// @Description Get PreheatSchedules
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param page query int true "current page" default(0)
+// @Param per_page query int true "return max item count, default 10, max 50" default(10) minimum(2) maximum(50)
+// @Success 200 {object} []models.PreheatSchedule
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules [get]
+func (h *Handlers) GetPreheatSchedules(ctx *gin.Context) {
+	var query types.GetPreheatSchedulesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	h.setPaginationDefault(&query.Page, &query.PerPage)
+	preheatSchedules, count, err := h.service.GetPreheatSchedules(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
+	ctx.JSON(http.StatusOK, preheatSchedules)
+}
+
+// @Summary Get PreheatSchedule Jobs
+// @Description Get history preheat jobs created by the PreheatSchedule
+// @Tags PreheatSchedule
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param page query int true "current page" default(0)
+// @Param per_page query int true "return max item count, default 10, max 50" default(10) minimum(2) maximum(50)
+// @Success 200 {object} []models.Job
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /preheat-schedules/{id}/jobs [get]
+func (h *Handlers) GetPreheatScheduleJobs(ctx *gin.Context) {
+	var params types.PreheatScheduleParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var query types.GetPreheatScheduleJobsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	h.setPaginationDefault(&query.Page, &query.PerPage)
+	jobs, count, err := h.service.GetPreheatScheduleJobs(ctx.Request.Context(), params.ID, query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
+	ctx.JSON(http.StatusOK, jobs)
+}
@@ -0,0 +1,197 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	// nolint
+	_ "d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// @Summary Create Webhook
+// @Description Create by json config
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param Webhook body types.CreateWebhookRequest true "Webhook"
+// @Success 200 {object} models.Webhook
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /webhooks [post]
+func (h *Handlers) CreateWebhook(ctx *gin.Context) {
+	var json types.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(ctx.Request.Context(), json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+// @Summary Destroy Webhook
+// @Description Destroy by id
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /webhooks/{id} [delete]
+func (h *Handlers) DestroyWebhook(ctx *gin.Context) {
+	var params types.WebhookParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	if err := h.service.DestroyWebhook(ctx.Request.Context(), params.ID); err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// @Summary Update Webhook
+// @Description Update by json config
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param Webhook body types.UpdateWebhookRequest true "Webhook"
+// @Success 200 {object} models.Webhook
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /webhooks/{id} [patch]
+func (h *Handlers) UpdateWebhook(ctx *gin.Context) {
+	var params types.WebhookParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	var json types.UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&json); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(ctx.Request.Context(), params.ID, json)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+// @Summary Get Webhook
+// @Description Get Webhook by id
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.Webhook
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /webhooks/{id} [get]
+func (h *Handlers) GetWebhook(ctx *gin.Context) {
+	var params types.WebhookParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	webhook, err := h.service.GetWebhook(ctx.Request.Context(), params.ID)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+// @Summary Get Webhooks
+// @Description Get Webhooks
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param page query int true "current page" default(0)
+// @Param per_page query int true "return max item count, default 10, max 50" default(10) minimum(2) maximum(50)
+// @Success 200 {object} []models.Webhook
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /webhooks [get]
+func (h *Handlers) GetWebhooks(ctx *gin.Context) {
+	var query types.GetWebhooksQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	h.setPaginationDefault(&query.Page, &query.PerPage)
+	webhooks, count, err := h.service.GetWebhooks(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	h.setPaginationLinkHeader(ctx, query.Page, query.PerPage, int(count))
+	ctx.JSON(http.StatusOK, webhooks)
+}
+
+// @Summary Ping Webhook
+// @Description Deliver the ping event to the Webhook synchronously
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /webhooks/{id}/ping [post]
+func (h *Handlers) PingWebhook(ctx *gin.Context) {
+	var params types.WebhookParams
+	if err := ctx.ShouldBindUri(&params); err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"errors": err.Error()})
+		return
+	}
+
+	if err := h.service.PingWebhook(ctx.Request.Context(), params.ID); err != nil {
+		ctx.Error(err) // nolint: errcheck
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
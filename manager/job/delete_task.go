@@ -0,0 +1,87 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/delete_task_mock.go -source delete_task.go -package mocks
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/google/uuid"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// DeleteTask is an interface for deleting task job.
+type DeleteTask interface {
+	// CreateDeleteTask creates a deleting task job.
+	CreateDeleteTask(context.Context, []models.Scheduler, types.DeleteTaskArgs) (*internaljob.GroupJobState, error)
+}
+
+// deleteTask is an implementation of DeleteTask.
+type deleteTask struct {
+	job *internaljob.Job
+}
+
+// newDeleteTask returns a new DeleteTask.
+func newDeleteTask(job *internaljob.Job) (DeleteTask, error) {
+	return &deleteTask{job: job}, nil
+}
+
+// CreateDeleteTask creates a deleting task job, the job is sent to all of the schedulers,
+// because the task may be downloaded by the peers of any scheduler in the clusters.
+func (d *deleteTask) CreateDeleteTask(ctx context.Context, schedulers []models.Scheduler, json types.DeleteTaskArgs) (*internaljob.GroupJobState, error) {
+	taskID := types.MakeTaskIDOfDeleteTaskArgs(json)
+	args, err := internaljob.MarshalRequest(internaljob.DeleteTaskRequest{TaskID: taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []*machineryv1tasks.Signature
+	queues := getSchedulerQueues(schedulers)
+	for _, queue := range queues {
+		signatures = append(signatures, &machineryv1tasks.Signature{
+			UUID:       fmt.Sprintf("task_%s", uuid.New().String()),
+			Name:       internaljob.DeleteTaskJob,
+			RoutingKey: queue.String(),
+			Args:       args,
+		})
+	}
+
+	group, err := machineryv1tasks.NewGroup(signatures...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("create delete task group %s in queues %v, task: %s", group.GroupUUID, queues, taskID)
+	if _, err := d.job.Server.SendGroupWithContext(ctx, group, 0); err != nil {
+		logger.Errorf("create delete task group %s failed: %s", group.GroupUUID, err)
+		return nil, err
+	}
+
+	return &internaljob.GroupJobState{
+		GroupUUID: group.GroupUUID,
+		State:     machineryv1tasks.StatePending,
+		CreatedAt: time.Now(),
+	}, nil
+}
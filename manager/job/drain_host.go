@@ -0,0 +1,89 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/drain_host_mock.go -source drain_host.go -package mocks
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/google/uuid"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// DrainHost is an interface for draining host job.
+type DrainHost interface {
+	// CreateDrainHost creates a draining host job.
+	CreateDrainHost(context.Context, []models.Scheduler, types.DrainHostArgs) (*internaljob.GroupJobState, error)
+}
+
+// drainHost is an implementation of DrainHost.
+type drainHost struct {
+	job *internaljob.Job
+}
+
+// newDrainHost returns a new DrainHost.
+func newDrainHost(job *internaljob.Job) (DrainHost, error) {
+	return &drainHost{job: job}, nil
+}
+
+// CreateDrainHost creates a draining host job, the job is sent to all of the schedulers,
+// because the host may be announced to any scheduler in the clusters.
+func (d *drainHost) CreateDrainHost(ctx context.Context, schedulers []models.Scheduler, json types.DrainHostArgs) (*internaljob.GroupJobState, error) {
+	args, err := internaljob.MarshalRequest(internaljob.DrainHostRequest{
+		HostID: json.HostID,
+		Cancel: json.Cancel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []*machineryv1tasks.Signature
+	queues := getSchedulerQueues(schedulers)
+	for _, queue := range queues {
+		signatures = append(signatures, &machineryv1tasks.Signature{
+			UUID:       fmt.Sprintf("task_%s", uuid.New().String()),
+			Name:       internaljob.DrainHostJob,
+			RoutingKey: queue.String(),
+			Args:       args,
+		})
+	}
+
+	group, err := machineryv1tasks.NewGroup(signatures...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("create drain host group %s in queues %v, host: %s", group.GroupUUID, queues, json.HostID)
+	if _, err := d.job.Server.SendGroupWithContext(ctx, group, 0); err != nil {
+		logger.Errorf("create drain host group %s failed: %s", group.GroupUUID, err)
+		return nil, err
+	}
+
+	return &internaljob.GroupJobState{
+		GroupUUID: group.GroupUUID,
+		State:     machineryv1tasks.StatePending,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,99 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/get_task_mock.go -source get_task.go -package mocks
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/google/uuid"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+)
+
+// GetTask is an interface for getting task job.
+type GetTask interface {
+	// CreateGetTask creates a getting task job.
+	CreateGetTask(context.Context, []models.Scheduler, types.GetTaskArgs) (*internaljob.GroupJobState, error)
+}
+
+// getTask is an implementation of GetTask.
+type getTask struct {
+	job *internaljob.Job
+}
+
+// newGetTask returns a new GetTask.
+func newGetTask(job *internaljob.Job) (GetTask, error) {
+	return &getTask{job: job}, nil
+}
+
+// CreateGetTask creates a getting task job, the job is sent to all of the schedulers,
+// because the task may be held by the peers of any scheduler in the clusters.
+func (g *getTask) CreateGetTask(ctx context.Context, schedulers []models.Scheduler, json types.GetTaskArgs) (*internaljob.GroupJobState, error) {
+	taskID := json.TaskID
+	if taskID == "" {
+		taskID = idgen.TaskIDV1(json.URL, &commonv1.UrlMeta{
+			Digest:      json.Digest,
+			Tag:         json.Tag,
+			Filter:      json.Filter,
+			Application: json.Application,
+		})
+	}
+
+	args, err := internaljob.MarshalRequest(internaljob.GetTaskRequest{TaskID: taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []*machineryv1tasks.Signature
+	queues := getSchedulerQueues(schedulers)
+	for _, queue := range queues {
+		signatures = append(signatures, &machineryv1tasks.Signature{
+			UUID:       fmt.Sprintf("task_%s", uuid.New().String()),
+			Name:       internaljob.GetTaskJob,
+			RoutingKey: queue.String(),
+			Args:       args,
+		})
+	}
+
+	group, err := machineryv1tasks.NewGroup(signatures...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("create get task group %s in queues %v, task: %s", group.GroupUUID, queues, taskID)
+	if _, err := g.job.Server.SendGroupWithContext(ctx, group, 0); err != nil {
+		logger.Errorf("create get task group %s failed: %s", group.GroupUUID, err)
+		return nil, err
+	}
+
+	return &internaljob.GroupJobState{
+		GroupUUID: group.GroupUUID,
+		State:     machineryv1tasks.StatePending,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,89 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/host_reputation_mock.go -source host_reputation.go -package mocks
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/google/uuid"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// HostReputation is an interface for host reputation job.
+type HostReputation interface {
+	// CreateHostReputation creates a host reputation job.
+	CreateHostReputation(context.Context, []models.Scheduler, types.HostReputationArgs) (*internaljob.GroupJobState, error)
+}
+
+// hostReputation is an implementation of HostReputation.
+type hostReputation struct {
+	job *internaljob.Job
+}
+
+// newHostReputation returns a new HostReputation.
+func newHostReputation(job *internaljob.Job) (HostReputation, error) {
+	return &hostReputation{job: job}, nil
+}
+
+// CreateHostReputation creates a host reputation job, the job is sent to all of the schedulers,
+// because the host may be announced to any scheduler in the clusters.
+func (h *hostReputation) CreateHostReputation(ctx context.Context, schedulers []models.Scheduler, json types.HostReputationArgs) (*internaljob.GroupJobState, error) {
+	args, err := internaljob.MarshalRequest(internaljob.HostReputationRequest{
+		HostID: json.HostID,
+		Action: json.Action,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []*machineryv1tasks.Signature
+	queues := getSchedulerQueues(schedulers)
+	for _, queue := range queues {
+		signatures = append(signatures, &machineryv1tasks.Signature{
+			UUID:       fmt.Sprintf("task_%s", uuid.New().String()),
+			Name:       internaljob.HostReputationJob,
+			RoutingKey: queue.String(),
+			Args:       args,
+		})
+	}
+
+	group, err := machineryv1tasks.NewGroup(signatures...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("create host reputation group %s in queues %v, host: %s", group.GroupUUID, queues, json.HostID)
+	if _, err := h.job.Server.SendGroupWithContext(ctx, group, 0); err != nil {
+		logger.Errorf("create host reputation group %s failed: %s", group.GroupUUID, err)
+		return nil, err
+	}
+
+	return &internaljob.GroupJobState{
+		GroupUUID: group.GroupUUID,
+		State:     machineryv1tasks.StatePending,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -40,6 +40,11 @@ type Job struct {
 	*internaljob.Job
 	Preheat
 	SyncPeers
+	DrainHost
+	ReannounceHost
+	HostReputation
+	DeleteTask
+	GetTask
 }
 
 // New returns a new Job.
@@ -74,10 +79,40 @@ func New(cfg *config.Config, gdb *gorm.DB) (*Job, error) {
 		return nil, err
 	}
 
+	drainHost, err := newDrainHost(j)
+	if err != nil {
+		return nil, err
+	}
+
+	reannounceHost, err := newReannounceHost(j)
+	if err != nil {
+		return nil, err
+	}
+
+	hostReputation, err := newHostReputation(j)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteTask, err := newDeleteTask(j)
+	if err != nil {
+		return nil, err
+	}
+
+	getTask, err := newGetTask(j)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Job{
-		Job:       j,
-		Preheat:   preheat,
-		SyncPeers: syncPeers,
+		Job:            j,
+		Preheat:        preheat,
+		SyncPeers:      syncPeers,
+		DrainHost:      drainHost,
+		ReannounceHost: reannounceHost,
+		HostReputation: hostReputation,
+		DeleteTask:     deleteTask,
+		GetTask:        getTask,
 	}, nil
 }
 
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: delete_task.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	job "d7y.io/dragonfly/v2/internal/job"
+	models "d7y.io/dragonfly/v2/manager/models"
+	types "d7y.io/dragonfly/v2/manager/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeleteTask is a mock of DeleteTask interface.
+type MockDeleteTask struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeleteTaskMockRecorder
+}
+
+// MockDeleteTaskMockRecorder is the mock recorder for MockDeleteTask.
+type MockDeleteTaskMockRecorder struct {
+	mock *MockDeleteTask
+}
+
+// NewMockDeleteTask creates a new mock instance.
+func NewMockDeleteTask(ctrl *gomock.Controller) *MockDeleteTask {
+	mock := &MockDeleteTask{ctrl: ctrl}
+	mock.recorder = &MockDeleteTaskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeleteTask) EXPECT() *MockDeleteTaskMockRecorder {
+	return m.recorder
+}
+
+// CreateDeleteTask mocks base method.
+func (m *MockDeleteTask) CreateDeleteTask(arg0 context.Context, arg1 []models.Scheduler, arg2 types.DeleteTaskArgs) (*job.GroupJobState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeleteTask", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*job.GroupJobState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDeleteTask indicates an expected call of CreateDeleteTask.
+func (mr *MockDeleteTaskMockRecorder) CreateDeleteTask(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeleteTask", reflect.TypeOf((*MockDeleteTask)(nil).CreateDeleteTask), arg0, arg1, arg2)
+}
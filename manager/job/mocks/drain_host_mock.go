@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: drain_host.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	job "d7y.io/dragonfly/v2/internal/job"
+	models "d7y.io/dragonfly/v2/manager/models"
+	types "d7y.io/dragonfly/v2/manager/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDrainHost is a mock of DrainHost interface.
+type MockDrainHost struct {
+	ctrl     *gomock.Controller
+	recorder *MockDrainHostMockRecorder
+}
+
+// MockDrainHostMockRecorder is the mock recorder for MockDrainHost.
+type MockDrainHostMockRecorder struct {
+	mock *MockDrainHost
+}
+
+// NewMockDrainHost creates a new mock instance.
+func NewMockDrainHost(ctrl *gomock.Controller) *MockDrainHost {
+	mock := &MockDrainHost{ctrl: ctrl}
+	mock.recorder = &MockDrainHostMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDrainHost) EXPECT() *MockDrainHostMockRecorder {
+	return m.recorder
+}
+
+// CreateDrainHost mocks base method.
+func (m *MockDrainHost) CreateDrainHost(arg0 context.Context, arg1 []models.Scheduler, arg2 types.DrainHostArgs) (*job.GroupJobState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDrainHost", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*job.GroupJobState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDrainHost indicates an expected call of CreateDrainHost.
+func (mr *MockDrainHostMockRecorder) CreateDrainHost(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDrainHost", reflect.TypeOf((*MockDrainHost)(nil).CreateDrainHost), arg0, arg1, arg2)
+}
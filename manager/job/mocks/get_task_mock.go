@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: get_task.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	job "d7y.io/dragonfly/v2/internal/job"
+	models "d7y.io/dragonfly/v2/manager/models"
+	types "d7y.io/dragonfly/v2/manager/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGetTask is a mock of GetTask interface.
+type MockGetTask struct {
+	ctrl     *gomock.Controller
+	recorder *MockGetTaskMockRecorder
+}
+
+// MockGetTaskMockRecorder is the mock recorder for MockGetTask.
+type MockGetTaskMockRecorder struct {
+	mock *MockGetTask
+}
+
+// NewMockGetTask creates a new mock instance.
+func NewMockGetTask(ctrl *gomock.Controller) *MockGetTask {
+	mock := &MockGetTask{ctrl: ctrl}
+	mock.recorder = &MockGetTaskMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGetTask) EXPECT() *MockGetTaskMockRecorder {
+	return m.recorder
+}
+
+// CreateGetTask mocks base method.
+func (m *MockGetTask) CreateGetTask(arg0 context.Context, arg1 []models.Scheduler, arg2 types.GetTaskArgs) (*job.GroupJobState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGetTask", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*job.GroupJobState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGetTask indicates an expected call of CreateGetTask.
+func (mr *MockGetTaskMockRecorder) CreateGetTask(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGetTask", reflect.TypeOf((*MockGetTask)(nil).CreateGetTask), arg0, arg1, arg2)
+}
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: host_reputation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	job "d7y.io/dragonfly/v2/internal/job"
+	models "d7y.io/dragonfly/v2/manager/models"
+	types "d7y.io/dragonfly/v2/manager/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHostReputation is a mock of HostReputation interface.
+type MockHostReputation struct {
+	ctrl     *gomock.Controller
+	recorder *MockHostReputationMockRecorder
+}
+
+// MockHostReputationMockRecorder is the mock recorder for MockHostReputation.
+type MockHostReputationMockRecorder struct {
+	mock *MockHostReputation
+}
+
+// NewMockHostReputation creates a new mock instance.
+func NewMockHostReputation(ctrl *gomock.Controller) *MockHostReputation {
+	mock := &MockHostReputation{ctrl: ctrl}
+	mock.recorder = &MockHostReputationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHostReputation) EXPECT() *MockHostReputationMockRecorder {
+	return m.recorder
+}
+
+// CreateHostReputation mocks base method.
+func (m *MockHostReputation) CreateHostReputation(arg0 context.Context, arg1 []models.Scheduler, arg2 types.HostReputationArgs) (*job.GroupJobState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHostReputation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*job.GroupJobState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHostReputation indicates an expected call of CreateHostReputation.
+func (mr *MockHostReputationMockRecorder) CreateHostReputation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHostReputation", reflect.TypeOf((*MockHostReputation)(nil).CreateHostReputation), arg0, arg1, arg2)
+}
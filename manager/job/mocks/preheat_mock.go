@@ -51,3 +51,18 @@ func (mr *MockPreheatMockRecorder) CreatePreheat(arg0, arg1, arg2 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePreheat", reflect.TypeOf((*MockPreheat)(nil).CreatePreheat), arg0, arg1, arg2)
 }
+
+// GetDigest mocks base method.
+func (m *MockPreheat) GetDigest(arg0 context.Context, arg1 types.PreheatArgs) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDigest", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDigest indicates an expected call of GetDigest.
+func (mr *MockPreheatMockRecorder) GetDigest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigest", reflect.TypeOf((*MockPreheat)(nil).GetDigest), arg0, arg1)
+}
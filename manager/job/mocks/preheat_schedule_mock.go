@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: preheat_schedule.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPreheatSchedule is a mock of PreheatSchedule interface.
+type MockPreheatSchedule struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreheatScheduleMockRecorder
+}
+
+// MockPreheatScheduleMockRecorder is the mock recorder for MockPreheatSchedule.
+type MockPreheatScheduleMockRecorder struct {
+	mock *MockPreheatSchedule
+}
+
+// NewMockPreheatSchedule creates a new mock instance.
+func NewMockPreheatSchedule(ctrl *gomock.Controller) *MockPreheatSchedule {
+	mock := &MockPreheatSchedule{ctrl: ctrl}
+	mock.recorder = &MockPreheatScheduleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreheatSchedule) EXPECT() *MockPreheatScheduleMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockPreheatSchedule) Run(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockPreheatScheduleMockRecorder) Run(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockPreheatSchedule)(nil).Run), arg0)
+}
+
+// Serve mocks base method.
+func (m *MockPreheatSchedule) Serve() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Serve")
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockPreheatScheduleMockRecorder) Serve() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockPreheatSchedule)(nil).Serve))
+}
+
+// Stop mocks base method.
+func (m *MockPreheatSchedule) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockPreheatScheduleMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockPreheatSchedule)(nil).Stop))
+}
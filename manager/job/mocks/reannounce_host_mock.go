@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reannounce_host.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	job "d7y.io/dragonfly/v2/internal/job"
+	models "d7y.io/dragonfly/v2/manager/models"
+	types "d7y.io/dragonfly/v2/manager/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReannounceHost is a mock of ReannounceHost interface.
+type MockReannounceHost struct {
+	ctrl     *gomock.Controller
+	recorder *MockReannounceHostMockRecorder
+}
+
+// MockReannounceHostMockRecorder is the mock recorder for MockReannounceHost.
+type MockReannounceHostMockRecorder struct {
+	mock *MockReannounceHost
+}
+
+// NewMockReannounceHost creates a new mock instance.
+func NewMockReannounceHost(ctrl *gomock.Controller) *MockReannounceHost {
+	mock := &MockReannounceHost{ctrl: ctrl}
+	mock.recorder = &MockReannounceHostMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReannounceHost) EXPECT() *MockReannounceHostMockRecorder {
+	return m.recorder
+}
+
+// CreateReannounceHost mocks base method.
+func (m *MockReannounceHost) CreateReannounceHost(arg0 context.Context, arg1 []models.Scheduler, arg2 types.ReannounceHostArgs) (*job.GroupJobState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReannounceHost", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*job.GroupJobState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReannounceHost indicates an expected call of CreateReannounceHost.
+func (mr *MockReannounceHostMockRecorder) CreateReannounceHost(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReannounceHost", reflect.TypeOf((*MockReannounceHost)(nil).CreateReannounceHost), arg0, arg1, arg2)
+}
@@ -33,9 +33,13 @@ import (
 
 	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
 	"github.com/distribution/distribution/v3"
+	"github.com/distribution/distribution/v3/manifest/manifestlist"
+	_ "github.com/distribution/distribution/v3/manifest/ocischema" // nolint
 	"github.com/distribution/distribution/v3/manifest/schema2"
+	"github.com/distribution/distribution/v3/reference"
 	"github.com/go-http-utils/headers"
 	"github.com/google/uuid"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"go.opentelemetry.io/otel/trace"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
@@ -43,6 +47,7 @@ import (
 	"d7y.io/dragonfly/v2/manager/config"
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/digest"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
 )
 
@@ -56,6 +61,18 @@ const (
 	PreheatFileType PreheatType = "file"
 )
 
+const (
+	// defaultPreheatPlatform is the platform preheated in the manifest list
+	// when no platform is requested.
+	defaultPreheatPlatform = "linux/amd64"
+
+	// dockerHubDomain is the domain of docker hub in the image reference.
+	dockerHubDomain = "docker.io"
+
+	// dockerHubRegistryDomain is the registry domain of docker hub.
+	dockerHubRegistryDomain = "registry-1.docker.io"
+)
+
 // accessURLPattern is the pattern of access url.
 var accessURLPattern, _ = regexp.Compile("^(.*)://(.*)/v2/(.*)/manifests/(.*)")
 
@@ -63,6 +80,11 @@ var accessURLPattern, _ = regexp.Compile("^(.*)://(.*)/v2/(.*)/manifests/(.*)")
 type Preheat interface {
 	// CreatePreheat creates a preheat job.
 	CreatePreheat(context.Context, []models.Scheduler, types.PreheatArgs) (*internaljob.GroupJobState, error)
+
+	// GetDigest returns the digest of the content to be preheated, it is used to skip
+	// the recurring preheat when the content is unchanged. Empty digest represents
+	// the content can not be identified.
+	GetDigest(context.Context, types.PreheatArgs) (string, error)
 }
 
 // preheat is an implementation of Preheat.
@@ -80,6 +102,11 @@ type preheatImage struct {
 	tag      string
 }
 
+// manifestURL gets manifest url of the image by the tag or digest.
+func (i *preheatImage) manifestURL(ref string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/manifests/%s", i.protocol, i.domain, i.name, ref)
+}
+
 // newPreheat creates a new Preheat.
 func newPreheat(job *internaljob.Job, httpRequestTimeout time.Duration, rootCAs *x509.CertPool) (Preheat, error) {
 	return &preheat{job, httpRequestTimeout, rootCAs}, nil
@@ -105,13 +132,13 @@ func (p *preheat) CreatePreheat(ctx context.Context, schedulers []models.Schedul
 	var files []internaljob.PreheatRequest
 	switch PreheatType(json.Type) {
 	case PreheatImageType:
-		// Parse image manifest url.
+		// Parse image manifest url or image reference.
 		image, err := parseAccessURL(url)
 		if err != nil {
 			return nil, err
 		}
 
-		files, err = p.getLayers(ctx, url, tag, filter, nethttp.MapToHeader(rawheader), image)
+		files, err = p.getLayers(ctx, image.manifestURL(image.tag), tag, filter, json.Platforms, nethttp.MapToHeader(rawheader), image)
 		if err != nil {
 			return nil, err
 		}
@@ -131,6 +158,68 @@ func (p *preheat) CreatePreheat(ctx context.Context, schedulers []models.Schedul
 	return p.createGroupJob(ctx, files, queues)
 }
 
+// GetDigest returns the digest of the content to be preheated.
+func (p *preheat) GetDigest(ctx context.Context, json types.PreheatArgs) (string, error) {
+	switch PreheatType(json.Type) {
+	case PreheatImageType:
+		image, err := parseAccessURL(json.URL)
+		if err != nil {
+			return "", err
+		}
+
+		files, err := p.getLayers(ctx, image.manifestURL(image.tag), json.Tag, json.Filter, json.Platforms, nethttp.MapToHeader(json.Headers), image)
+		if err != nil {
+			return "", err
+		}
+
+		// Layer urls are addressed by the layer digests.
+		var urls []string
+		for _, file := range files {
+			urls = append(urls, file.URL)
+		}
+
+		return digest.SHA256FromStrings(urls...), nil
+	case PreheatFileType:
+		return p.getFileDigest(ctx, json.URL, nethttp.MapToHeader(json.Headers))
+	default:
+		return "", errors.New("unknow preheat type")
+	}
+}
+
+// getFileDigest returns the digest of the file by the validators in response headers.
+func (p *preheat) getFileDigest(ctx context.Context, url string, header http.Header) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header = header
+	client := &http.Client{
+		Timeout: p.httpRequestTimeout,
+		Transport: &http.Transport{
+			DialContext:     nethttp.NewSafeDialer().DialContext,
+			TLSClientConfig: &tls.Config{RootCAs: p.rootCAs},
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("head %s failed: %s", url, resp.Status)
+	}
+
+	etag, lastModified := resp.Header.Get(headers.ETag), resp.Header.Get(headers.LastModified)
+	if etag == "" && lastModified == "" {
+		return "", nil
+	}
+
+	return digest.SHA256FromStrings(etag, lastModified, resp.Header.Get(headers.ContentLength)), nil
+}
+
 // createGroupJob creates a group job.
 func (p *preheat) createGroupJob(ctx context.Context, files []internaljob.PreheatRequest, queues []internaljob.Queue) (*internaljob.GroupJobState, error) {
 	var signatures []*machineryv1tasks.Signature
@@ -175,7 +264,7 @@ func (p *preheat) createGroupJob(ctx context.Context, files []internaljob.Prehea
 }
 
 // getLayers gets layers of image.
-func (p *preheat) getLayers(ctx context.Context, url, tag, filter string, header http.Header, image *preheatImage) ([]internaljob.PreheatRequest, error) {
+func (p *preheat) getLayers(ctx context.Context, url, tag, filter string, platforms []string, header http.Header, image *preheatImage) ([]internaljob.PreheatRequest, error) {
 	ctx, span := tracer.Start(ctx, config.SpanGetLayers, trace.WithSpanKind(trace.SpanKindProducer))
 	defer span.End()
 
@@ -202,7 +291,7 @@ func (p *preheat) getLayers(ctx context.Context, url, tag, filter string, header
 		}
 	}
 
-	layers, err := p.parseLayers(resp, url, tag, filter, header, image)
+	layers, err := p.parseLayers(ctx, resp, tag, filter, platforms, header, image)
 	if err != nil {
 		return nil, err
 	}
@@ -217,8 +306,15 @@ func (p *preheat) getManifests(ctx context.Context, url string, header http.Head
 		return nil, err
 	}
 
-	req.Header = header
-	req.Header.Add(headers.Accept, schema2.MediaTypeManifest)
+	req.Header = header.Clone()
+	for _, mediaType := range []string{
+		schema2.MediaTypeManifest,
+		manifestlist.MediaTypeManifestList,
+		ocispec.MediaTypeImageManifest,
+		ocispec.MediaTypeImageIndex,
+	} {
+		req.Header.Add(headers.Accept, mediaType)
+	}
 
 	client := &http.Client{
 		Timeout: timeout,
@@ -236,18 +332,28 @@ func (p *preheat) getManifests(ctx context.Context, url string, header http.Head
 	return resp, nil
 }
 
-// parseLayers parses layers of image.
-func (p *preheat) parseLayers(resp *http.Response, url, tag, filter string, header http.Header, image *preheatImage) ([]internaljob.PreheatRequest, error) {
+// parseLayers parses layers of image, the manifest list is resolved to
+// the manifests of the requested platforms.
+func (p *preheat) parseLayers(ctx context.Context, resp *http.Response, tag, filter string, platforms []string, header http.Header, image *preheatImage) ([]internaljob.PreheatRequest, error) {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	manifest, _, err := distribution.UnmarshalManifest(schema2.MediaTypeManifest, body)
+	mediaType := resp.Header.Get(headers.ContentType)
+	if mediaType == "" {
+		mediaType = schema2.MediaTypeManifest
+	}
+
+	manifest, _, err := distribution.UnmarshalManifest(mediaType, body)
 	if err != nil {
 		return nil, err
 	}
 
+	if manifestList, ok := manifest.(*manifestlist.DeserializedManifestList); ok {
+		return p.parseManifestList(ctx, manifestList, tag, filter, platforms, header, image)
+	}
+
 	var layers []internaljob.PreheatRequest
 	for _, v := range manifest.References() {
 		layer := internaljob.PreheatRequest{
@@ -263,6 +369,63 @@ func (p *preheat) parseLayers(resp *http.Response, url, tag, filter string, head
 	return layers, nil
 }
 
+// parseManifestList parses layers of the manifests matching the platforms in the manifest list.
+func (p *preheat) parseManifestList(ctx context.Context, manifestList *manifestlist.DeserializedManifestList, tag, filter string, platforms []string, header http.Header, image *preheatImage) ([]internaljob.PreheatRequest, error) {
+	if len(platforms) == 0 {
+		platforms = []string{defaultPreheatPlatform}
+	}
+
+	var layers []internaljob.PreheatRequest
+	for _, m := range manifestList.Manifests {
+		if !matchPlatforms(m.Platform, platforms) {
+			continue
+		}
+
+		resp, err := p.getManifests(ctx, image.manifestURL(m.Digest.String()), header, p.httpRequestTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode/100 != 2 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("request registry %d", resp.StatusCode)
+		}
+
+		platformLayers, err := p.parseLayers(ctx, resp, tag, filter, platforms, header, image)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, platformLayers...)
+	}
+
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("no manifest matches platforms %v", platforms)
+	}
+
+	return layers, nil
+}
+
+// matchPlatforms returns whether the platform matches one of the platforms,
+// platform is in the form of os/arch[/variant], such as linux/arm64/v8.
+func matchPlatforms(platform manifestlist.PlatformSpec, platforms []string) bool {
+	for _, v := range platforms {
+		elems := strings.Split(v, "/")
+		if len(elems) < 2 || elems[0] != platform.OS || elems[1] != platform.Architecture {
+			continue
+		}
+
+		if len(elems) > 2 && elems[2] != platform.Variant {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
 // getAuthToken gets auth token from registry.
 func getAuthToken(ctx context.Context, header http.Header, timeout time.Duration, rootCAs *x509.CertPool) (string, error) {
 	ctx, span := tracer.Start(ctx, config.SpanAuthWithRegistry, trace.WithSpanKind(trace.SpanKindProducer))
@@ -330,11 +493,17 @@ func layerURL(protocol string, domain string, name string, digest string) string
 	return fmt.Sprintf("%s://%s/v2/%s/blobs/%s", protocol, domain, name, digest)
 }
 
-// parseAccessURL parses access url.
+// parseAccessURL parses access url, the url is the manifest url of the image
+// or the image reference, such as docker.io/library/alpine:3.18.
 func parseAccessURL(url string) (*preheatImage, error) {
 	r := accessURLPattern.FindStringSubmatch(url)
 	if len(r) != 5 {
-		return nil, errors.New("parse access url failed")
+		image, err := parseImageReference(url)
+		if err != nil {
+			return nil, errors.New("parse access url failed")
+		}
+
+		return image, nil
 	}
 
 	return &preheatImage{
@@ -344,3 +513,34 @@ func parseAccessURL(url string) (*preheatImage, error) {
 		tag:      r[4],
 	}, nil
 }
+
+// parseImageReference parses the image reference, reference without tag
+// and digest is tagged with latest.
+func parseImageReference(ref string) (*preheatImage, error) {
+	named, err := reference.ParseDockerRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := reference.Domain(named)
+	if domain == dockerHubDomain {
+		domain = dockerHubRegistryDomain
+	}
+
+	var tag string
+	switch r := named.(type) {
+	case reference.Canonical:
+		tag = r.Digest().String()
+	case reference.Tagged:
+		tag = r.Tag()
+	default:
+		return nil, fmt.Errorf("invalid image reference %s", ref)
+	}
+
+	return &preheatImage{
+		protocol: "https",
+		domain:   domain,
+		name:     reference.Path(named),
+		tag:      tag,
+	}, nil
+}
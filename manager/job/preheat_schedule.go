@@ -0,0 +1,175 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/preheat_schedule_mock.go -source preheat_schedule.go -package mocks
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/config"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/structure"
+)
+
+// CreatePreheatJobFunc creates the preheat job, and polls the state of the job.
+type CreatePreheatJobFunc func(context.Context, types.CreatePreheatJobRequest) (*models.Job, error)
+
+// PreheatSchedule is an interface for creating the recurring preheat jobs.
+type PreheatSchedule interface {
+	// Run creates preheat jobs for the due preheat schedules.
+	Run(context.Context) error
+
+	// Started preheat schedule server.
+	Serve()
+
+	// Stop preheat schedule server.
+	Stop()
+}
+
+// preheatSchedule is an implementation of PreheatSchedule.
+type preheatSchedule struct {
+	config           *config.Config
+	db               *gorm.DB
+	preheat          Preheat
+	createPreheatJob CreatePreheatJobFunc
+	done             chan struct{}
+}
+
+// NewPreheatSchedule returns a new PreheatSchedule.
+func NewPreheatSchedule(cfg *config.Config, gdb *gorm.DB, preheat Preheat, createPreheatJob CreatePreheatJobFunc) (PreheatSchedule, error) {
+	return &preheatSchedule{
+		config:           cfg,
+		db:               gdb,
+		preheat:          preheat,
+		createPreheatJob: createPreheatJob,
+		done:             make(chan struct{}),
+	}, nil
+}
+
+// Run creates preheat jobs for the due preheat schedules.
+func (p *preheatSchedule) Run(ctx context.Context) error {
+	var preheatSchedules []models.PreheatSchedule
+	if err := p.db.WithContext(ctx).Preload("SchedulerClusters").Find(&preheatSchedules, models.PreheatSchedule{
+		State: models.PreheatScheduleStateActive,
+	}).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, preheatSchedule := range preheatSchedules {
+		log := logger.With("preheatSchedule", preheatSchedule.Name)
+		schedule, err := cron.ParseStandard(preheatSchedule.Cron)
+		if err != nil {
+			log.Errorf("parse cron %s failed: %s", preheatSchedule.Cron, err.Error())
+			continue
+		}
+
+		lastScheduledAt := preheatSchedule.CreatedAt
+		if preheatSchedule.LastScheduledAt != nil {
+			lastScheduledAt = *preheatSchedule.LastScheduledAt
+		}
+
+		if schedule.Next(lastScheduledAt).After(now) {
+			continue
+		}
+
+		if err := p.run(ctx, preheatSchedule, now); err != nil {
+			log.Errorf("run preheat schedule failed: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Started preheat schedule server.
+func (p *preheatSchedule) Serve() {
+	tick := time.NewTicker(p.config.Job.PreheatSchedule.Interval)
+	for {
+		select {
+		case <-tick.C:
+			if err := p.Run(context.Background()); err != nil {
+				logger.Errorf("preheat schedule failed: %v", err)
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Stop preheat schedule server.
+func (p *preheatSchedule) Stop() {
+	close(p.done)
+}
+
+// run creates the preheat job of the preheat schedule, the preheat job is skipped
+// when the digest of the content is unchanged since the last preheat.
+func (p *preheatSchedule) run(ctx context.Context, preheatSchedule models.PreheatSchedule, now time.Time) error {
+	log := logger.With("preheatSchedule", preheatSchedule.Name)
+
+	var args types.PreheatArgs
+	if err := structure.MapToStruct(preheatSchedule.Args, &args); err != nil {
+		return err
+	}
+
+	// Preheat without digest if the content can not be identified.
+	digest, err := p.preheat.GetDigest(ctx, args)
+	if err != nil {
+		log.Warnf("get digest of %s failed: %s", args.URL, err.Error())
+	}
+
+	if digest != "" && digest == preheatSchedule.LastDigest {
+		log.Infof("skip preheat %s, digest %s is unchanged", args.URL, digest)
+		return p.db.WithContext(ctx).Model(&preheatSchedule).Updates(models.PreheatSchedule{
+			LastScheduledAt: &now,
+		}).Error
+	}
+
+	var schedulerClusterIDs []uint
+	for _, schedulerCluster := range preheatSchedule.SchedulerClusters {
+		schedulerClusterIDs = append(schedulerClusterIDs, schedulerCluster.ID)
+	}
+
+	job, err := p.createPreheatJob(ctx, types.CreatePreheatJobRequest{
+		BIO:                 fmt.Sprintf("created by preheat schedule %s", preheatSchedule.Name),
+		Type:                internaljob.PreheatJob,
+		Args:                args,
+		UserID:              preheatSchedule.UserID,
+		SchedulerClusterIDs: schedulerClusterIDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := p.db.WithContext(ctx).Model(job).Update("preheat_schedule_id", preheatSchedule.ID).Error; err != nil {
+		return err
+	}
+
+	log.Infof("create preheat job %d of %s with digest %s", job.ID, args.URL, digest)
+	return p.db.WithContext(ctx).Model(&preheatSchedule).Updates(map[string]any{
+		"last_scheduled_at": now,
+		"last_digest":       digest,
+	}).Error
+}
@@ -0,0 +1,88 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/reannounce_host_mock.go -source reannounce_host.go -package mocks
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
+	"github.com/google/uuid"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// ReannounceHost is an interface for reannouncing host job.
+type ReannounceHost interface {
+	// CreateReannounceHost creates a reannouncing host job.
+	CreateReannounceHost(context.Context, []models.Scheduler, types.ReannounceHostArgs) (*internaljob.GroupJobState, error)
+}
+
+// reannounceHost is an implementation of ReannounceHost.
+type reannounceHost struct {
+	job *internaljob.Job
+}
+
+// newReannounceHost returns a new ReannounceHost.
+func newReannounceHost(job *internaljob.Job) (ReannounceHost, error) {
+	return &reannounceHost{job: job}, nil
+}
+
+// CreateReannounceHost creates a reannouncing host job, the job is sent to all of the schedulers,
+// because the host may be announced to any scheduler in the clusters.
+func (r *reannounceHost) CreateReannounceHost(ctx context.Context, schedulers []models.Scheduler, json types.ReannounceHostArgs) (*internaljob.GroupJobState, error) {
+	args, err := internaljob.MarshalRequest(internaljob.ReannounceHostRequest{
+		HostID: json.HostID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []*machineryv1tasks.Signature
+	queues := getSchedulerQueues(schedulers)
+	for _, queue := range queues {
+		signatures = append(signatures, &machineryv1tasks.Signature{
+			UUID:       fmt.Sprintf("task_%s", uuid.New().String()),
+			Name:       internaljob.ReannounceHostJob,
+			RoutingKey: queue.String(),
+			Args:       args,
+		})
+	}
+
+	group, err := machineryv1tasks.NewGroup(signatures...)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("create reannounce host group %s in queues %v, host: %s", group.GroupUUID, queues, json.HostID)
+	if _, err := r.job.Server.SendGroupWithContext(ctx, group, 0); err != nil {
+		logger.Errorf("create reannounce host group %s failed: %s", group.GroupUUID, err)
+		return nil, err
+	}
+
+	return &internaljob.GroupJobState{
+		GroupUUID: group.GroupUUID,
+		State:     machineryv1tasks.StatePending,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -34,15 +34,19 @@ import (
 	"d7y.io/dragonfly/v2/manager/cache"
 	"d7y.io/dragonfly/v2/manager/config"
 	"d7y.io/dragonfly/v2/manager/database"
+	managergc "d7y.io/dragonfly/v2/manager/gc"
 	"d7y.io/dragonfly/v2/manager/job"
 	"d7y.io/dragonfly/v2/manager/metrics"
+	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/permission/rbac"
 	"d7y.io/dragonfly/v2/manager/router"
 	"d7y.io/dragonfly/v2/manager/rpcserver"
 	"d7y.io/dragonfly/v2/manager/searcher"
 	"d7y.io/dragonfly/v2/manager/service"
+	"d7y.io/dragonfly/v2/manager/webhook"
 	pkgcache "d7y.io/dragonfly/v2/pkg/cache"
 	"d7y.io/dragonfly/v2/pkg/dfpath"
+	pkggc "d7y.io/dragonfly/v2/pkg/gc"
 	"d7y.io/dragonfly/v2/pkg/issuer"
 	"d7y.io/dragonfly/v2/pkg/objectstorage"
 	"d7y.io/dragonfly/v2/pkg/rpc"
@@ -92,6 +96,12 @@ type Server struct {
 	// Job server.
 	job *job.Job
 
+	// Preheat schedule server.
+	preheatSchedule job.PreheatSchedule
+
+	// GC server.
+	gc pkggc.GC
+
 	// GRPC server.
 	grpcServer *grpc.Server
 
@@ -100,6 +110,9 @@ type Server struct {
 
 	// Metrics server.
 	metricsServer *http.Server
+
+	// Cert reloader.
+	certReloader *rpc.CertReloader
 }
 
 // New creates a new manager server.
@@ -128,11 +141,10 @@ func New(cfg *config.Config, d dfpath.Dfpath) (*Server, error) {
 	searcher := searcher.New(d.PluginDir())
 
 	// Initialize job.
-	job, err := job.New(cfg, db.DB)
+	s.job, err = job.New(cfg, db.DB)
 	if err != nil {
 		return nil, err
 	}
-	s.job = job
 
 	// Initialize object storage.
 	var objectStorage objectstorage.ObjectStorage
@@ -150,8 +162,11 @@ func New(cfg *config.Config, d dfpath.Dfpath) (*Server, error) {
 		}
 	}
 
+	// Initialize webhook.
+	webhook := webhook.New(db.DB)
+
 	// Initialize REST server.
-	restService := service.New(cfg, db, cache, job, enforcer, objectStorage)
+	restService := service.New(cfg, db, cache, s.job, enforcer, objectStorage, webhook)
 	router, err := router.Init(cfg, d.LogDir(), restService, db, enforcer, EmbedFolder(assets, assetsTargetPath))
 	if err != nil {
 		return nil, err
@@ -161,6 +176,20 @@ func New(cfg *config.Config, d dfpath.Dfpath) (*Server, error) {
 		Handler: router,
 	}
 
+	// Initialize preheat schedule.
+	s.preheatSchedule, err = job.NewPreheatSchedule(cfg, db.DB, s.job.Preheat, restService.CreatePreheatJob)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize garbage collector of audit logs.
+	s.gc = pkggc.New(pkggc.WithLogger(logger.GCLogger))
+	if cfg.AuditLog.Enable {
+		if err := managergc.NewAuditLog(s.gc, db.DB, cfg.AuditLog); err != nil {
+			return nil, err
+		}
+	}
+
 	// Initialize roles and check roles.
 	err = rbac.InitRBAC(enforcer, router, db.DB)
 	if err != nil {
@@ -168,9 +197,23 @@ func New(cfg *config.Config, d dfpath.Dfpath) (*Server, error) {
 	}
 
 	// Initialize signing certificate and tls credentials of grpc server.
-	var options []rpcserver.Option
+	var (
+		options           []rpcserver.Option
+		grpcServerOptions []grpc.ServerOption
+	)
 	if cfg.Security.AutoIssueCert {
-		cert, err := tls.X509KeyPair([]byte(cfg.Security.CACert), []byte(cfg.Security.CAKey))
+		// Load the embedded CA generated by manager instead of the CA in config.
+		caCert, caKey := []byte(cfg.Security.CACert), []byte(cfg.Security.CAKey)
+		if cfg.Security.EmbeddedCA {
+			ca := models.CertificateAuthority{}
+			if err := db.DB.Where("name = ?", models.CertificateAuthorityNameEmbedded).First(&ca).Error; err != nil {
+				return nil, err
+			}
+
+			caCert, caKey = []byte(ca.Cert), []byte(ca.PrivateKey)
+		}
+
+		cert, err := tls.X509KeyPair(caCert, caKey)
 		if err != nil {
 			return nil, err
 		}
@@ -203,22 +246,42 @@ func New(cfg *config.Config, d dfpath.Dfpath) (*Server, error) {
 
 		// Manager GRPC server's tls varify must be false. If ClientCAs are required for client verification,
 		// the client cannot call the IssueCertificate api.
-		transportCredentials, err := rpc.NewServerCredentialsByCertify(cfg.Security.TLSPolicy, false, []byte(cfg.Security.CACert), certifyClient)
+		transportCredentials, err := rpc.NewServerCredentialsByCertify(cfg.Security.TLSPolicy, false, caCert, certifyClient)
 		if err != nil {
 			return nil, err
 		}
 
-		options = append(
-			options,
-			// Set ca certificate for issuing certificate.
-			rpcserver.WithSelfSignedCert(&cert),
-			// Set tls credentials for grpc server.
-			rpcserver.WithGRPCServerOptions([]grpc.ServerOption{grpc.Creds(transportCredentials)}),
-		)
+		// Set ca certificate for issuing certificate.
+		options = append(options, rpcserver.WithSelfSignedCert(&cert))
+
+		// Set tls credentials for grpc server.
+		grpcServerOptions = append(grpcServerOptions, grpc.Creds(transportCredentials))
+	} else if cfg.Security.CertFile != "" {
+		// Initialize cert reloader, the rotated certificates take effect without restarting.
+		s.certReloader, err = rpc.NewCertReloader(cfg.Security.CertFile, cfg.Security.KeyFile, cfg.Security.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		// Manager GRPC server does not verify the client certificates, because the clients
+		// without certificates call the IssueCertificate api.
+		transportCredentials, err := rpc.NewServerCredentialsByCertReloader(cfg.Security.TLSPolicy, false, s.certReloader)
+		if err != nil {
+			return nil, err
+		}
+
+		grpcServerOptions = append(grpcServerOptions, grpc.Creds(transportCredentials))
 	}
 
+	// Authenticate the calls of grpc server.
+	grpcServerOptions = append(grpcServerOptions, rpc.AuthServerOptions(rpc.NewAuthenticator(
+		rpc.WithAuthToken(cfg.Server.GRPC.Auth.Token),
+		rpc.WithAuthHMACSecret(cfg.Server.GRPC.Auth.HMACSecret),
+	))...)
+	options = append(options, rpcserver.WithGRPCServerOptions(grpcServerOptions))
+
 	// Initialize GRPC server.
-	_, grpcServer, err := rpcserver.New(cfg, db, cache, searcher, objectStorage, options...)
+	_, grpcServer, err := rpcserver.New(cfg, db, cache, searcher, objectStorage, webhook, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +290,7 @@ func New(cfg *config.Config, d dfpath.Dfpath) (*Server, error) {
 
 	// Initialize prometheus.
 	if cfg.Metrics.Enable {
-		s.metricsServer = metrics.New(&cfg.Metrics, grpcServer)
+		s.metricsServer = metrics.New(&cfg.Metrics, grpcServer, db.DB)
 	}
 
 	return s, nil
@@ -274,6 +337,24 @@ func (s *Server) Serve() error {
 		s.job.Serve()
 	}()
 
+	// Started preheat schedule server.
+	go func() {
+		logger.Info("started preheat schedule server")
+		s.preheatSchedule.Serve()
+	}()
+
+	// Serve GC.
+	s.gc.Start()
+	logger.Info("gc start successfully")
+
+	// Serve cert reloader.
+	if s.certReloader != nil {
+		go func() {
+			logger.Info("started cert reloader")
+			s.certReloader.Serve()
+		}()
+	}
+
 	// Generate GRPC listener.
 	lis, _, err := rpc.ListenWithPortRange(s.config.Server.GRPC.ListenIP.String(), s.config.Server.GRPC.PortRange.Start, s.config.Server.GRPC.PortRange.End)
 	if err != nil {
@@ -312,6 +393,19 @@ func (s *Server) Stop() {
 	// Stop job server.
 	s.job.Stop()
 
+	// Stop preheat schedule server.
+	s.preheatSchedule.Stop()
+
+	// Stop GC.
+	s.gc.Stop()
+	logger.Info("gc closed")
+
+	// Stop cert reloader.
+	if s.certReloader != nil {
+		s.certReloader.Stop()
+		logger.Info("cert reloader closed")
+	}
+
 	// Stop GRPC server.
 	stopped := make(chan struct{})
 	go func() {
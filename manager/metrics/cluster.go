@@ -0,0 +1,99 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// collectTimeout is the timeout of collecting cluster metrics.
+	collectTimeout = 10 * time.Second
+)
+
+var (
+	// activeSchedulerDesc is the description of the active schedulers of the scheduler cluster.
+	activeSchedulerDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.MetricsNamespace, types.ManagerMetricsName, "active_scheduler_total"),
+		"Gauge of the number of the active schedulers in the scheduler cluster.",
+		[]string{"scheduler_cluster_id"}, nil,
+	)
+
+	// activeSeedPeerDesc is the description of the active seed peers of the seed peer cluster.
+	activeSeedPeerDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(types.MetricsNamespace, types.ManagerMetricsName, "active_seed_peer_total"),
+		"Gauge of the number of the active seed peers in the seed peer cluster.",
+		[]string{"seed_peer_cluster_id"}, nil,
+	)
+)
+
+// clusterCount is the count of the members in the cluster.
+type clusterCount struct {
+	ClusterID uint
+	Count     int64
+}
+
+// clusterCollector collects the active members of the clusters from database.
+type clusterCollector struct {
+	db *gorm.DB
+}
+
+// newClusterCollector returns a new cluster collector.
+func newClusterCollector(db *gorm.DB) prometheus.Collector {
+	return &clusterCollector{db: db}
+}
+
+// Describe implements prometheus.Collector.
+func (c *clusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeSchedulerDesc
+	ch <- activeSeedPeerDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *clusterCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	var schedulerCounts []clusterCount
+	if err := c.db.WithContext(ctx).Model(&models.Scheduler{}).Select("scheduler_cluster_id AS cluster_id, count(*) AS count").
+		Where("state = ?", models.SchedulerStateActive).Group("scheduler_cluster_id").Scan(&schedulerCounts).Error; err != nil {
+		logger.Errorf("collect active schedulers failed: %s", err.Error())
+	}
+
+	for _, schedulerCount := range schedulerCounts {
+		ch <- prometheus.MustNewConstMetric(activeSchedulerDesc, prometheus.GaugeValue, float64(schedulerCount.Count), fmt.Sprint(schedulerCount.ClusterID))
+	}
+
+	var seedPeerCounts []clusterCount
+	if err := c.db.WithContext(ctx).Model(&models.SeedPeer{}).Select("seed_peer_cluster_id AS cluster_id, count(*) AS count").
+		Where("state = ?", models.SeedPeerStateActive).Group("seed_peer_cluster_id").Scan(&seedPeerCounts).Error; err != nil {
+		logger.Errorf("collect active seed peers failed: %s", err.Error())
+	}
+
+	for _, seedPeerCount := range seedPeerCounts {
+		ch <- prometheus.MustNewConstMetric(activeSeedPeerDesc, prometheus.GaugeValue, float64(seedPeerCount.Count), fmt.Sprint(seedPeerCount.ClusterID))
+	}
+}
@@ -24,7 +24,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"gorm.io/gorm"
 
+	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/manager/config"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/version"
@@ -46,6 +48,35 @@ var (
 		Help:      "Counter of the number of failed of searching scheduler cluster.",
 	}, []string{"version", "commit"})
 
+	SearchSchedulerClusterDecisionCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.ManagerMetricsName,
+		Name:      "search_scheduler_cluster_decision_total",
+		Help:      "Counter of the number of the scheduler cluster decided by searcher.",
+	}, []string{"scheduler_cluster_id"})
+
+	DynconfigFetchCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.ManagerMetricsName,
+		Name:      "dynconfig_fetch_total",
+		Help:      "Counter of the number of fetching dynconfig.",
+	}, []string{"type", "source_type"})
+
+	JobCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.ManagerMetricsName,
+		Name:      "job_total",
+		Help:      "Counter of the number of the completed job.",
+	}, []string{"type", "state"})
+
+	JobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.ManagerMetricsName,
+		Name:      "job_duration_milliseconds",
+		Help:      "Histogram of the time each job running.",
+		Buckets:   []float64{100, 500, 1000, 5 * 1000, 10 * 1000, 30 * 1000, 60 * 1000, 300 * 1000, 600 * 1000, 1800 * 1000, 3600 * 1000},
+	}, []string{"type", "state"})
+
 	VersionGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: types.MetricsNamespace,
 		Subsystem: types.ManagerMetricsName,
@@ -54,12 +85,20 @@ var (
 	}, []string{"major", "minor", "git_version", "git_commit", "platform", "build_time", "go_version", "go_tags", "go_gcflags"})
 )
 
-func New(cfg *config.MetricsConfig, grpcServer *grpc.Server) *http.Server {
+func New(cfg *config.MetricsConfig, grpcServer *grpc.Server, db *gorm.DB) *http.Server {
 	grpc_prometheus.Register(grpcServer)
 
+	// Collect the cluster metrics from database when scraping.
+	if db != nil {
+		prometheus.MustRegister(newClusterCollector(db))
+	}
+
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 
+	// Adjust the log level of every component at runtime.
+	mux.Handle("/admin/log/levels", logger.LevelHandler())
+
 	VersionGauge.WithLabelValues(version.Major, version.Minor, version.GitVersion, version.GitCommit, version.Platform, version.BuildTime, version.GoVersion, version.Gotags, version.Gogcflags).Set(1)
 	return &http.Server{
 		Addr:    cfg.Addr,
@@ -30,7 +30,7 @@ func TestNew(t *testing.T) {
 		Addr: "localhost:8080",
 	}
 	svr := grpc.NewServer()
-	server := New(cfg, svr)
+	server := New(cfg, svr, nil)
 
 	if server.Addr != cfg.Addr {
 		t.Errorf("expected server.Addr to be %s, but got %s", cfg.Addr, server.Addr)
@@ -0,0 +1,277 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/config"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/pkg/structure"
+)
+
+const (
+	// maxAuditBodySize is the max size of the request and response body recorded in the audit log.
+	maxAuditBodySize = 1 << 20
+
+	// auditRedacted is the value of the redacted fields in the audit log.
+	auditRedacted = "******"
+)
+
+// auditModels are the models of the resources, the resources are snapshotted
+// before and after the action for the audit log.
+var auditModels = map[string]func() any{
+	"applications":           func() any { return &models.Application{} },
+	"configs":                func() any { return &models.Config{} },
+	"feature-flags":          func() any { return &models.FeatureFlag{} },
+	"jobs":                   func() any { return &models.Job{} },
+	"models":                 func() any { return &models.Model{} },
+	"oauth":                  func() any { return &models.Oauth{} },
+	"peers":                  func() any { return &models.Peer{} },
+	"personal-access-tokens": func() any { return &models.PersonalAccessToken{} },
+	"preheat-schedules":      func() any { return &models.PreheatSchedule{} },
+	"scheduler-clusters":     func() any { return &models.SchedulerCluster{} },
+	"schedulers":             func() any { return &models.Scheduler{} },
+	"seed-peer-clusters":     func() any { return &models.SeedPeerCluster{} },
+	"seed-peers":             func() any { return &models.SeedPeer{} },
+	"tenants":                func() any { return &models.Tenant{} },
+	"users":                  func() any { return &models.User{} },
+	"webhooks":               func() any { return &models.Webhook{} },
+}
+
+// auditSensitiveFields are the fields redacted in the audit log.
+var auditSensitiveFields = []string{"password", "secret", "token", "key"}
+
+// auditResponseWriter records the response body for the audit log.
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write writes the response body.
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.body.Len()+len(b) <= maxAuditBodySize {
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Audit records the mutating api calls of the authenticated users to the audit log,
+// including the actor, source ip, request and the resource before and after the action.
+func Audit(cfg config.AuditLogConfig, gdb *gorm.DB, enforcer *casbin.Enforcer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.Enable {
+			c.Next()
+			return
+		}
+
+		action := auditAction(c.Request.Method)
+		if action == "" {
+			c.Next()
+			return
+		}
+
+		auditLog := models.AuditLog{
+			IP:       c.ClientIP(),
+			Method:   c.Request.Method,
+			Path:     c.Request.URL.Path,
+			Resource: auditResource(c.FullPath()),
+			Action:   action,
+		}
+		if len(c.Params) > 0 {
+			auditLog.ResourceID = c.Params[0].Value
+		}
+
+		// Record the json request body and restore it for the handlers.
+		if c.ContentType() == gin.MIMEJSON && c.Request.ContentLength >= 0 && c.Request.ContentLength <= maxAuditBodySize {
+			body, err := io.ReadAll(c.Request.Body)
+			if err == nil {
+				auditLog.Request = redactAuditFields(unmarshalAuditJSON(body))
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		if action != models.AuditLogActionCreate && auditLog.ResourceID != "" {
+			auditLog.Before = snapshotAuditResource(c, gdb, enforcer, auditLog.Resource, auditLog.ResourceID)
+		}
+
+		w := &auditResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		// Only the calls of the authenticated users are recorded.
+		rawID, ok := c.Get(defaultIdentityKey)
+		if !ok {
+			return
+		}
+
+		id, ok := rawID.(float64)
+		if !ok {
+			return
+		}
+
+		auditLog.UserID = uint(id)
+		auditLog.StatusCode = c.Writer.Status()
+		user := models.User{}
+		if err := gdb.WithContext(c).Select("name").First(&user, auditLog.UserID).Error; err == nil {
+			auditLog.UserName = user.Name
+		}
+
+		if auditLog.StatusCode >= http.StatusOK && auditLog.StatusCode < http.StatusMultipleChoices && action != models.AuditLogActionDelete {
+			if auditLog.ResourceID != "" {
+				auditLog.After = snapshotAuditResource(c, gdb, enforcer, auditLog.Resource, auditLog.ResourceID)
+			} else if after := redactAuditFields(unmarshalAuditJSON(w.body.Bytes())); after != nil {
+				auditLog.After = after
+				if id, ok := after["id"]; ok {
+					auditLog.ResourceID = fmt.Sprint(id)
+				}
+			}
+		}
+
+		auditLog.Diff = diffAuditResource(auditLog.Before, auditLog.After)
+		if err := gdb.WithContext(c).Create(&auditLog).Error; err != nil {
+			logger.Errorf("create audit log error: %s", err.Error())
+		}
+	}
+}
+
+// auditAction returns the action of the http method, the read-only methods have no action.
+func auditAction(method string) string {
+	switch method {
+	case http.MethodPost:
+		return models.AuditLogActionCreate
+	case http.MethodPatch, http.MethodPut:
+		return models.AuditLogActionUpdate
+	case http.MethodDelete:
+		return models.AuditLogActionDelete
+	default:
+		return ""
+	}
+}
+
+// auditResource returns the resource of the route, e.g. scheduler-clusters of /api/v1/scheduler-clusters/:id.
+func auditResource(fullPath string) string {
+	parts := strings.Split(strings.TrimPrefix(fullPath, "/"), "/")
+	if len(parts) < 3 {
+		return fullPath
+	}
+
+	return parts[2]
+}
+
+// snapshotAuditResource returns the resource as map, the roles are included in the snapshot of user.
+func snapshotAuditResource(c *gin.Context, gdb *gorm.DB, enforcer *casbin.Enforcer, resource, id string) models.JSONMap {
+	newModel, ok := auditModels[resource]
+	if !ok {
+		return nil
+	}
+
+	model := newModel()
+	if err := gdb.WithContext(c).First(model, "id = ?", id).Error; err != nil {
+		return nil
+	}
+
+	snapshot, err := structure.StructToMap(model)
+	if err != nil {
+		return nil
+	}
+
+	if resource == "users" {
+		if roles, err := enforcer.GetRolesForUser(id); err == nil {
+			snapshot["roles"] = roles
+		}
+	}
+
+	return redactAuditFields(snapshot)
+}
+
+// diffAuditResource returns the changed fields between the resource before and after the action.
+func diffAuditResource(before, after models.JSONMap) models.JSONMap {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	diff := models.JSONMap{}
+	for key, value := range after {
+		if key == "updated_at" {
+			continue
+		}
+
+		if !reflect.DeepEqual(before[key], value) {
+			diff[key] = map[string]any{"before": before[key], "after": value}
+		}
+	}
+
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			diff[key] = map[string]any{"before": value, "after": nil}
+		}
+	}
+
+	return diff
+}
+
+// unmarshalAuditJSON unmarshals the json object, it returns nil if the body is not a json object.
+func unmarshalAuditJSON(body []byte) models.JSONMap {
+	var m models.JSONMap
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil
+	}
+
+	return m
+}
+
+// redactAuditFields redacts the values of the sensitive fields recursively.
+func redactAuditFields(m models.JSONMap) models.JSONMap {
+	for key, value := range m {
+		if isAuditSensitiveField(key) {
+			m[key] = auditRedacted
+			continue
+		}
+
+		if nested, ok := value.(map[string]any); ok {
+			m[key] = map[string]any(redactAuditFields(nested))
+		}
+	}
+
+	return m
+}
+
+// isAuditSensitiveField returns whether the field is sensitive.
+func isAuditSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range auditSensitiveFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+
+	return false
+}
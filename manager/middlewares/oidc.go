@@ -0,0 +1,61 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-http-utils/headers"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/auth/oidc"
+	"d7y.io/dragonfly/v2/manager/config"
+	"d7y.io/dragonfly/v2/manager/service"
+)
+
+// OIDC authenticates the requests with the id tokens issued by the OIDC provider,
+// the other requests are authenticated by the jwt middleware of manager. The user of
+// id token must have signed in, the roles of user are not synchronized by the requests.
+func OIDC(cfg config.OIDCConfig, service service.Service, jwtMiddleware gin.HandlerFunc) gin.HandlerFunc {
+	if !cfg.Enable {
+		return jwtMiddleware
+	}
+
+	return func(c *gin.Context) {
+		tokenFields := strings.Fields(c.GetHeader(headers.Authorization))
+		if len(tokenFields) != 2 || tokenFields[0] != "Bearer" || !oidc.IsIssuedBy(tokenFields[1], cfg.Issuer) {
+			jwtMiddleware(c)
+			return
+		}
+
+		user, err := service.OIDCAuthenticate(c.Request.Context(), tokenFields[1])
+		if err != nil {
+			logger.Errorf("oidc authenticate error: %s", err)
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Message: http.StatusText(http.StatusUnauthorized),
+			})
+			c.Abort()
+			return
+		}
+
+		// The identity is float64 as the same as the identity in the claims of jwt.
+		c.Set(defaultIdentityKey, float64(user.ID))
+		c.Next()
+	}
+}
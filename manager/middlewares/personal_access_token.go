@@ -41,8 +41,8 @@ func PersonalAccessToken(gdb *gorm.DB) gin.HandlerFunc {
 		}
 
 		// Check if the personal access token is valid.
-		personalAccessToken := tokenFields[1]
-		if err := gdb.WithContext(c).Where("token = ?", personalAccessToken).First(&models.PersonalAccessToken{}).Error; err != nil {
+		personalAccessToken := models.PersonalAccessToken{}
+		if err := gdb.WithContext(c).Where("token = ?", tokenFields[1]).First(&personalAccessToken).Error; err != nil {
 			c.JSON(http.StatusUnauthorized, ErrorResponse{
 				Message: http.StatusText(http.StatusUnauthorized),
 			})
@@ -50,6 +50,10 @@ func PersonalAccessToken(gdb *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		// Set the owner of the personal access token as the identity, the identity
+		// is float64 as the same as the identity in the claims of jwt.
+		c.Set(defaultIdentityKey, float64(personalAccessToken.UserID))
+
 		c.Next()
 	}
 }
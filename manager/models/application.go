@@ -18,10 +18,16 @@ package models
 
 type Application struct {
 	BaseModel
-	Name     string  `gorm:"column:name;type:varchar(256);index:uk_application_name,unique;not null;comment:name" json:"name"`
-	URL      string  `gorm:"column:url;not null;comment:url" json:"url"`
-	BIO      string  `gorm:"column:bio;type:varchar(1024);comment:biography" json:"bio"`
-	Priority JSONMap `gorm:"column:priority;not null;comment:download priority" json:"priority"`
-	UserID   uint    `gorm:"comment:user id" json:"user_id"`
-	User     User    `json:"user"`
+	Name                    string  `gorm:"column:name;type:varchar(256);index:uk_application_name,unique;not null;comment:name" json:"name"`
+	URL                     string  `gorm:"column:url;not null;comment:url" json:"url"`
+	BIO                     string  `gorm:"column:bio;type:varchar(1024);comment:biography" json:"bio"`
+	Priority                JSONMap `gorm:"column:priority;not null;comment:download priority" json:"priority"`
+	AntiAffinity            Array   `gorm:"column:anti_affinity;comment:anti-affinity topology labels" json:"anti_affinity"`
+	RateLimit               uint64  `gorm:"column:rate_limit;default:0;comment:download rate limit in bytes per second" json:"rate_limit"`
+	AllowedURLRegex         string  `gorm:"column:allowed_url_regex;type:varchar(1024);comment:allowed url regex" json:"allowed_url_regex"`
+	BackToSourceConcurrency uint32  `gorm:"column:back_to_source_concurrency;default:0;comment:back-to-source concurrency of task" json:"back_to_source_concurrency"`
+	RequireSignature        bool    `gorm:"column:require_signature;default:false;comment:require signature of downloaded artifacts" json:"require_signature"`
+	TenantID                uint    `gorm:"column:tenant_id;index:idx_application_tenant_id;comment:tenant id" json:"tenant_id"`
+	UserID                  uint    `gorm:"comment:user id" json:"user_id"`
+	User                    User    `json:"user"`
 }
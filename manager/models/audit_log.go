@@ -0,0 +1,45 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+const (
+	// AuditLogActionCreate represents the audit log of creating resource.
+	AuditLogActionCreate = "create"
+
+	// AuditLogActionUpdate represents the audit log of updating resource.
+	AuditLogActionUpdate = "update"
+
+	// AuditLogActionDelete represents the audit log of deleting resource.
+	AuditLogActionDelete = "delete"
+)
+
+type AuditLog struct {
+	BaseModel
+	UserID     uint    `gorm:"column:user_id;index:idx_audit_log_user_id;comment:user id of actor" json:"user_id"`
+	UserName   string  `gorm:"column:user_name;type:varchar(256);comment:user name of actor" json:"user_name"`
+	IP         string  `gorm:"column:ip;type:varchar(256);comment:source ip" json:"ip"`
+	Method     string  `gorm:"column:method;type:varchar(32);comment:http method" json:"method"`
+	Path       string  `gorm:"column:path;type:varchar(1024);comment:request path" json:"path"`
+	Resource   string  `gorm:"column:resource;type:varchar(256);index:idx_audit_log_resource;comment:resource type" json:"resource"`
+	ResourceID string  `gorm:"column:resource_id;type:varchar(256);comment:resource id" json:"resource_id"`
+	Action     string  `gorm:"column:action;type:varchar(32);comment:action" json:"action"`
+	StatusCode int     `gorm:"column:status_code;comment:http status code" json:"status_code"`
+	Request    JSONMap `gorm:"column:request;comment:request body" json:"request"`
+	Before     JSONMap `gorm:"column:before_state;comment:resource before the action" json:"before"`
+	After      JSONMap `gorm:"column:after_state;comment:resource after the action" json:"after"`
+	Diff       JSONMap `gorm:"column:diff;comment:changed fields of resource" json:"diff"`
+}
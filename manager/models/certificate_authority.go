@@ -0,0 +1,32 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import "time"
+
+const (
+	// CertificateAuthorityNameEmbedded is the name of the CA generated by manager.
+	CertificateAuthorityNameEmbedded = "embedded"
+)
+
+type CertificateAuthority struct {
+	BaseModel
+	Name       string    `gorm:"column:name;type:varchar(256);index:uk_certificate_authority_name,unique;not null;comment:name" json:"name"`
+	Cert       string    `gorm:"column:cert;type:text;not null;comment:certificate in pem format" json:"cert"`
+	PrivateKey string    `gorm:"column:private_key;type:text;not null;comment:private key in pem format" json:"-"`
+	ExpiredAt  time.Time `gorm:"column:expired_at;comment:expired at" json:"expired_at"`
+}
@@ -0,0 +1,35 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+const (
+	// ClusterConfigVersionTypeSchedulerCluster is the config version of scheduler cluster.
+	ClusterConfigVersionTypeSchedulerCluster = "scheduler_cluster"
+
+	// ClusterConfigVersionTypeSeedPeerCluster is the config version of seed peer cluster.
+	ClusterConfigVersionTypeSeedPeerCluster = "seed_peer_cluster"
+)
+
+type ClusterConfigVersion struct {
+	BaseModel
+	ClusterType  string  `gorm:"column:cluster_type;type:varchar(256);index:uk_cluster_config_version,unique;not null;comment:cluster type" json:"cluster_type"`
+	ClusterID    uint    `gorm:"column:cluster_id;index:uk_cluster_config_version,unique;not null;comment:cluster id" json:"cluster_id"`
+	Version      uint    `gorm:"column:version;index:uk_cluster_config_version,unique;not null;comment:config version" json:"version"`
+	Config       JSONMap `gorm:"column:config;comment:configuration" json:"config"`
+	ClientConfig JSONMap `gorm:"column:client_config;comment:client configuration" json:"client_config"`
+	BIO          string  `gorm:"column:bio;type:varchar(1024);comment:biography" json:"bio"`
+}
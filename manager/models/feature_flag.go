@@ -0,0 +1,37 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+const (
+	// FeatureFlagStateActive represents the feature flag whose state is active.
+	FeatureFlagStateActive = "active"
+
+	// FeatureFlagStateInactive represents the feature flag whose state is inactive.
+	FeatureFlagStateInactive = "inactive"
+)
+
+type FeatureFlag struct {
+	BaseModel
+	Name               string           `gorm:"column:name;type:varchar(256);index:uk_feature_flag,unique;not null;comment:name" json:"name"`
+	BIO                string           `gorm:"column:bio;type:varchar(1024);comment:biography" json:"bio"`
+	State              string           `gorm:"column:state;type:varchar(256);default:'active';comment:service state" json:"state"`
+	Percentage         uint             `gorm:"column:percentage;not null;comment:percentage of rolled out hosts" json:"percentage"`
+	SchedulerClusterID uint             `gorm:"column:scheduler_cluster_id;index:uk_feature_flag,unique;not null;comment:scheduler cluster id" json:"scheduler_cluster_id"`
+	SchedulerCluster   SchedulerCluster `json:"scheduler_cluster"`
+	UserID             uint             `gorm:"column:user_id;comment:user id" json:"user_id"`
+	User               User             `json:"user"`
+}
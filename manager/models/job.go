@@ -25,7 +25,9 @@ type Job struct {
 	Args              JSONMap            `gorm:"column:args;not null;comment:task request args" json:"args"`
 	Result            JSONMap            `gorm:"column:result;comment:task result" json:"result"`
 	UserID            uint               `gorm:"column:user_id;comment:user id" json:"user_id"`
+	TenantID          uint               `gorm:"column:tenant_id;index:idx_job_tenant_id;comment:tenant id" json:"tenant_id"`
 	User              User               `json:"user"`
 	SeedPeerClusters  []SeedPeerCluster  `gorm:"many2many:job_seed_peer_cluster;" json:"seed_peer_clusters"`
 	SchedulerClusters []SchedulerCluster `gorm:"many2many:job_scheduler_cluster;" json:"scheduler_clusters"`
+	PreheatScheduleID uint               `gorm:"column:preheat_schedule_id;index:idx_job_preheat_schedule_id;comment:preheat schedule id" json:"preheat_schedule_id"`
 }
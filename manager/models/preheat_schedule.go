@@ -0,0 +1,42 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+import "time"
+
+const (
+	// PreheatScheduleStateActive represents the preheat schedule whose state is active.
+	PreheatScheduleStateActive = "active"
+
+	// PreheatScheduleStateInactive represents the preheat schedule whose state is inactive,
+	// inactive preheat schedule is paused and no preheat job will be created.
+	PreheatScheduleStateInactive = "inactive"
+)
+
+type PreheatSchedule struct {
+	BaseModel
+	Name              string             `gorm:"column:name;type:varchar(256);index:uk_preheat_schedule_name,unique;not null;comment:name" json:"name"`
+	BIO               string             `gorm:"column:bio;type:varchar(1024);comment:biography" json:"bio"`
+	Cron              string             `gorm:"column:cron;type:varchar(256);not null;comment:cron expression" json:"cron"`
+	State             string             `gorm:"column:state;type:varchar(256);default:'active';comment:service state" json:"state"`
+	Args              JSONMap            `gorm:"column:args;not null;comment:preheat request args" json:"args"`
+	LastDigest        string             `gorm:"column:last_digest;type:varchar(256);comment:digest of the last preheated content" json:"last_digest"`
+	LastScheduledAt   *time.Time         `gorm:"column:last_scheduled_at;type:timestamp;comment:last scheduled at" json:"last_scheduled_at"`
+	UserID            uint               `gorm:"column:user_id;comment:user id" json:"user_id"`
+	User              User               `json:"user"`
+	SchedulerClusters []SchedulerCluster `gorm:"many2many:preheat_schedule_scheduler_cluster;" json:"scheduler_clusters"`
+}
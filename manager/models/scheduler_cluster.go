@@ -24,6 +24,7 @@ type SchedulerCluster struct {
 	ClientConfig     JSONMap           `gorm:"column:client_config;not null;comment:client configuration" json:"client_config"`
 	Scopes           JSONMap           `gorm:"column:scopes;comment:match scopes" json:"scopes"`
 	IsDefault        bool              `gorm:"column:is_default;not null;default:false;comment:default scheduler cluster" json:"is_default"`
+	TenantID         uint              `gorm:"column:tenant_id;index:idx_scheduler_cluster_tenant_id;comment:tenant id" json:"tenant_id"`
 	SeedPeerClusters []SeedPeerCluster `gorm:"many2many:seed_peer_cluster_scheduler_cluster;" json:"seed_peer_clusters"`
 	Schedulers       []Scheduler       `json:"schedulers"`
 	Peers            []Peer            `json:"peers"`
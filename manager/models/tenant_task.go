@@ -0,0 +1,26 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+// TenantTask is the task preheated to the seed peers for the tenant, the content length of tasks
+// is counted in the seed peer storage of tenant until the task is deleted or expired.
+type TenantTask struct {
+	BaseModel
+	TenantID      uint   `gorm:"column:tenant_id;index:uk_tenant_task,unique;not null;comment:tenant id" json:"tenant_id"`
+	TaskID        string `gorm:"column:task_id;type:varchar(256);index:uk_tenant_task,unique;index:idx_tenant_task_task_id;not null;comment:task id" json:"task_id"`
+	ContentLength int64  `gorm:"column:content_length;not null;comment:content length" json:"content_length"`
+}
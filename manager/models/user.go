@@ -32,5 +32,7 @@ type User struct {
 	State             string   `gorm:"column:state;type:varchar(256);default:'enable';comment:state" json:"state"`
 	Location          string   `gorm:"column:location;type:varchar(256);comment:location" json:"location"`
 	BIO               string   `gorm:"column:bio;type:varchar(256);comment:biography" json:"bio"`
+	OIDCIssuer        string   `gorm:"column:oidc_issuer;type:varchar(256);index:idx_user_oidc,priority:1;comment:issuer of oidc provider" json:"-"`
+	OIDCSubject       string   `gorm:"column:oidc_subject;type:varchar(256);index:idx_user_oidc,priority:2;comment:subject of oidc provider" json:"-"`
 	Configs           []Config `json:"configs"`
 }
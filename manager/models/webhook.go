@@ -0,0 +1,37 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package models
+
+const (
+	// WebhookStateActive represents the webhook whose state is active.
+	WebhookStateActive = "active"
+
+	// WebhookStateInactive represents the webhook whose state is inactive.
+	WebhookStateInactive = "inactive"
+)
+
+type Webhook struct {
+	BaseModel
+	Name   string `gorm:"column:name;type:varchar(256);index:uk_webhook_name,unique;not null;comment:name" json:"name"`
+	BIO    string `gorm:"column:bio;type:varchar(1024);comment:biography" json:"bio"`
+	URL    string `gorm:"column:url;type:varchar(1024);not null;comment:url of endpoint" json:"url"`
+	Secret string `gorm:"column:secret;type:varchar(1024);comment:secret of hmac signature" json:"-"`
+	Events Array  `gorm:"column:events;not null;comment:subscribed events" json:"events"`
+	State  string `gorm:"column:state;type:varchar(256);default:'active';comment:service state" json:"state"`
+	UserID uint   `gorm:"column:user_id;comment:user id" json:"user_id"`
+	User   User   `json:"user"`
+}
@@ -84,20 +84,26 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 		return nil, err
 	}
 
+	// Authentication middleware, it accepts the tokens issued by manager and OIDC provider.
+	auth := middlewares.OIDC(cfg.Auth.OIDC, service, jwt.MiddlewareFunc())
+
 	// Personal access token middleware.
 	personalAccessToken := middlewares.PersonalAccessToken(database.DB)
 
+	// Audit log middleware.
+	audit := middlewares.Audit(cfg.AuditLog, database.DB, enforcer)
+
 	// Manager view.
 	r.Use(static.Serve("/", assets))
 
 	// API router.
-	apiv1 := r.Group("/api/v1")
+	apiv1 := r.Group("/api/v1", audit)
 
 	// User.
 	u := apiv1.Group("/users")
-	u.PATCH(":id", jwt.MiddlewareFunc(), rbac, h.UpdateUser)
-	u.GET(":id", jwt.MiddlewareFunc(), rbac, h.GetUser)
-	u.GET("", jwt.MiddlewareFunc(), rbac, h.GetUsers)
+	u.PATCH(":id", auth, rbac, h.UpdateUser)
+	u.GET(":id", auth, rbac, h.GetUser)
+	u.GET("", auth, rbac, h.GetUsers)
 	u.POST("signin", jwt.LoginHandler)
 	u.POST("signout", jwt.LogoutHandler)
 	u.POST("signup", h.SignUp)
@@ -105,12 +111,18 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	u.GET("signin/:name/callback", h.OauthSigninCallback(jwt))
 	u.POST("refresh_token", jwt.RefreshHandler)
 	u.POST(":id/reset_password", h.ResetPassword)
-	u.GET(":id/roles", jwt.MiddlewareFunc(), rbac, h.GetRolesForUser)
-	u.PUT(":id/roles/:role", jwt.MiddlewareFunc(), rbac, h.AddRoleToUser)
-	u.DELETE(":id/roles/:role", jwt.MiddlewareFunc(), rbac, h.DeleteRoleForUser)
+	u.GET(":id/roles", auth, rbac, h.GetRolesForUser)
+	u.PUT(":id/roles/:role", auth, rbac, h.AddRoleToUser)
+	u.DELETE(":id/roles/:role", auth, rbac, h.DeleteRoleForUser)
+
+	// OIDC.
+	oi := apiv1.Group("/oidc")
+	oi.GET("signin", h.OIDCSignin)
+	oi.GET("signin/callback", h.OIDCSigninCallback(jwt))
+	oi.POST("refresh_token", h.OIDCRefreshToken)
 
 	// Role.
-	re := apiv1.Group("/roles", jwt.MiddlewareFunc(), rbac)
+	re := apiv1.Group("/roles", auth, rbac)
 	re.POST("", h.CreateRole)
 	re.DELETE(":role", h.DestroyRole)
 	re.GET(":role", h.GetRole)
@@ -119,19 +131,19 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	re.DELETE(":role/permissions", h.DeletePermissionForRole)
 
 	// Permission.
-	pm := apiv1.Group("/permissions", jwt.MiddlewareFunc(), rbac)
+	pm := apiv1.Group("/permissions", auth, rbac)
 	pm.GET("", h.GetPermissions(r))
 
 	// Oauth.
 	oa := apiv1.Group("/oauth")
-	oa.POST("", jwt.MiddlewareFunc(), rbac, h.CreateOauth)
-	oa.DELETE(":id", jwt.MiddlewareFunc(), rbac, h.DestroyOauth)
-	oa.PATCH(":id", jwt.MiddlewareFunc(), rbac, h.UpdateOauth)
+	oa.POST("", auth, rbac, h.CreateOauth)
+	oa.DELETE(":id", auth, rbac, h.DestroyOauth)
+	oa.PATCH(":id", auth, rbac, h.UpdateOauth)
 	oa.GET(":id", h.GetOauth)
 	oa.GET("", h.GetOauths)
 
 	// Cluster.
-	c := apiv1.Group("/clusters", jwt.MiddlewareFunc(), rbac)
+	c := apiv1.Group("/clusters", auth, rbac)
 	c.POST("", h.CreateCluster)
 	c.DELETE(":id", h.DestroyCluster)
 	c.PATCH(":id", h.UpdateCluster)
@@ -139,16 +151,20 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	c.GET("", h.GetClusters)
 
 	// Scheduler Cluster.
-	sc := apiv1.Group("/scheduler-clusters", jwt.MiddlewareFunc(), rbac)
+	sc := apiv1.Group("/scheduler-clusters", auth, rbac)
 	sc.POST("", h.CreateSchedulerCluster)
 	sc.DELETE(":id", h.DestroySchedulerCluster)
 	sc.PATCH(":id", h.UpdateSchedulerCluster)
 	sc.GET(":id", h.GetSchedulerCluster)
 	sc.GET("", h.GetSchedulerClusters)
 	sc.PUT(":id/schedulers/:scheduler_id", h.AddSchedulerToSchedulerCluster)
+	sc.GET(":id/config-versions", h.GetSchedulerClusterConfigVersions)
+	sc.GET(":id/config-versions/:version", h.GetSchedulerClusterConfigVersion)
+	sc.GET(":id/config-versions/:version/diff", h.GetSchedulerClusterConfigVersionDiff)
+	sc.POST(":id/config-versions/:version/rollback", h.RollbackSchedulerClusterConfig)
 
 	// Scheduler.
-	s := apiv1.Group("/schedulers", jwt.MiddlewareFunc(), rbac)
+	s := apiv1.Group("/schedulers", auth, rbac)
 	s.POST("", h.CreateScheduler)
 	s.DELETE(":id", h.DestroyScheduler)
 	s.PATCH(":id", h.UpdateScheduler)
@@ -156,7 +172,7 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	s.GET("", h.GetSchedulers)
 
 	// Seed Peer Cluster.
-	spc := apiv1.Group("/seed-peer-clusters", jwt.MiddlewareFunc(), rbac)
+	spc := apiv1.Group("/seed-peer-clusters", auth, rbac)
 	spc.POST("", h.CreateSeedPeerCluster)
 	spc.DELETE(":id", h.DestroySeedPeerCluster)
 	spc.PATCH(":id", h.UpdateSeedPeerCluster)
@@ -164,9 +180,13 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	spc.GET("", h.GetSeedPeerClusters)
 	spc.PUT(":id/seed-peers/:seed_peer_id", h.AddSeedPeerToSeedPeerCluster)
 	spc.PUT(":id/scheduler-clusters/:scheduler_cluster_id", h.AddSchedulerClusterToSeedPeerCluster)
+	spc.GET(":id/config-versions", h.GetSeedPeerClusterConfigVersions)
+	spc.GET(":id/config-versions/:version", h.GetSeedPeerClusterConfigVersion)
+	spc.GET(":id/config-versions/:version/diff", h.GetSeedPeerClusterConfigVersionDiff)
+	spc.POST(":id/config-versions/:version/rollback", h.RollbackSeedPeerClusterConfig)
 
 	// Seed Peer.
-	sp := apiv1.Group("/seed-peers", jwt.MiddlewareFunc(), rbac)
+	sp := apiv1.Group("/seed-peers", auth, rbac)
 	sp.POST("", h.CreateSeedPeer)
 	sp.DELETE(":id", h.DestroySeedPeer)
 	sp.PATCH(":id", h.UpdateSeedPeer)
@@ -174,14 +194,15 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	sp.GET("", h.GetSeedPeers)
 
 	// Peer.
-	peer := apiv1.Group("/peers", jwt.MiddlewareFunc(), rbac)
+	peer := apiv1.Group("/peers", auth, rbac)
 	peer.POST("", h.CreatePeer)
 	peer.DELETE(":id", h.DestroyPeer)
 	peer.GET(":id", h.GetPeer)
 	peer.GET("", h.GetPeers)
+	peer.POST("batch", h.BatchPeers)
 
 	// Bucket.
-	bucket := apiv1.Group("/buckets", jwt.MiddlewareFunc(), rbac)
+	bucket := apiv1.Group("/buckets", auth, rbac)
 	bucket.POST("", h.CreateBucket)
 	bucket.DELETE(":id", h.DestroyBucket)
 	bucket.GET(":id", h.GetBucket)
@@ -189,10 +210,10 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 
 	// Config.
 	config := apiv1.Group("/configs")
-	config.POST("", jwt.MiddlewareFunc(), rbac, h.CreateConfig)
-	config.DELETE(":id", jwt.MiddlewareFunc(), rbac, h.DestroyConfig)
-	config.PATCH(":id", jwt.MiddlewareFunc(), rbac, h.UpdateConfig)
-	config.GET(":id", jwt.MiddlewareFunc(), rbac, h.GetConfig)
+	config.POST("", auth, rbac, h.CreateConfig)
+	config.DELETE(":id", auth, rbac, h.DestroyConfig)
+	config.PATCH(":id", auth, rbac, h.UpdateConfig)
+	config.GET(":id", auth, rbac, h.GetConfig)
 	config.GET("", h.GetConfigs)
 
 	// TODO Add auth to the following routes and fix the tests.
@@ -202,10 +223,56 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	job.DELETE(":id", h.DestroyJob)
 	job.PATCH(":id", h.UpdateJob)
 	job.GET(":id", h.GetJob)
+	job.GET(":id/progress", h.GetPreheatJobProgress)
+	job.GET(":id/progress/stream", h.StreamPreheatJobProgress)
+	job.GET(":id/task", h.GetTaskJobResult)
 	job.GET("", h.GetJobs)
 
+	// Preheat Schedule.
+	ps := apiv1.Group("/preheat-schedules", auth, rbac)
+	ps.POST("", h.CreatePreheatSchedule)
+	ps.DELETE(":id", h.DestroyPreheatSchedule)
+	ps.PATCH(":id", h.UpdatePreheatSchedule)
+	ps.POST(":id/pause", h.PausePreheatSchedule)
+	ps.POST(":id/resume", h.ResumePreheatSchedule)
+	ps.GET(":id", h.GetPreheatSchedule)
+	ps.GET(":id/jobs", h.GetPreheatScheduleJobs)
+	ps.GET("", h.GetPreheatSchedules)
+
+	// Tenant.
+	tn := apiv1.Group("/tenants", auth, rbac)
+	tn.POST("", h.CreateTenant)
+	tn.DELETE(":id", h.DestroyTenant)
+	tn.PATCH(":id", h.UpdateTenant)
+	tn.GET(":id", h.GetTenant)
+	tn.GET(":id/usage", h.GetTenantUsage)
+	tn.GET("", h.GetTenants)
+
+	// Webhook.
+	wh := apiv1.Group("/webhooks", auth, rbac)
+	wh.POST("", h.CreateWebhook)
+	wh.DELETE(":id", h.DestroyWebhook)
+	wh.PATCH(":id", h.UpdateWebhook)
+	wh.GET(":id", h.GetWebhook)
+	wh.GET("", h.GetWebhooks)
+	wh.POST(":id/ping", h.PingWebhook)
+
+	// Feature Flag.
+	ff := apiv1.Group("/feature-flags", auth, rbac)
+	ff.POST("", h.CreateFeatureFlag)
+	ff.DELETE(":id", h.DestroyFeatureFlag)
+	ff.PATCH(":id", h.UpdateFeatureFlag)
+	ff.GET(":id", h.GetFeatureFlag)
+	ff.GET("", h.GetFeatureFlags)
+
+	// Audit log.
+	al := apiv1.Group("/audit-logs", auth, rbac)
+	al.GET(":id", h.GetAuditLog)
+	al.GET("export", h.ExportAuditLogs)
+	al.GET("", h.GetAuditLogs)
+
 	// Application.
-	cs := apiv1.Group("/applications", jwt.MiddlewareFunc(), rbac)
+	cs := apiv1.Group("/applications", auth, rbac)
 	cs.POST("", h.CreateApplication)
 	cs.DELETE(":id", h.DestroyApplication)
 	cs.PATCH(":id", h.UpdateApplication)
@@ -213,22 +280,26 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	cs.GET("", h.GetApplications)
 
 	// Model.
-	model := apiv1.Group("/models", jwt.MiddlewareFunc(), rbac)
+	model := apiv1.Group("/models", auth, rbac)
 	model.DELETE(":id", h.DestroyModel)
 	model.PATCH(":id", h.UpdateModel)
 	model.GET(":id", h.GetModel)
 	model.GET("", h.GetModels)
 
 	// Personal Access Token.
-	pat := apiv1.Group("/personal-access-tokens", jwt.MiddlewareFunc(), rbac)
+	pat := apiv1.Group("/personal-access-tokens", auth, rbac)
 	pat.POST("", h.CreatePersonalAccessToken)
 	pat.DELETE(":id", h.DestroyPersonalAccessToken)
 	pat.PATCH(":id", h.UpdatePersonalAccessToken)
 	pat.GET(":id", h.GetPersonalAccessToken)
 	pat.GET("", h.GetPersonalAccessTokens)
 
+	// Certificate.
+	cert := apiv1.Group("/certificates")
+	cert.GET("ca", h.GetCACertificate)
+
 	// Open API router.
-	oapiv1 := r.Group("/oapi/v1")
+	oapiv1 := r.Group("/oapi/v1", audit)
 
 	// Job.
 	ojob := oapiv1.Group("/jobs", personalAccessToken)
@@ -236,6 +307,9 @@ func Init(cfg *config.Config, logDir string, service service.Service, database *
 	ojob.DELETE(":id", h.DestroyJob)
 	ojob.PATCH(":id", h.UpdateJob)
 	ojob.GET(":id", h.GetJob)
+	ojob.GET(":id/progress", h.GetPreheatJobProgress)
+	ojob.GET(":id/progress/stream", h.StreamPreheatJobProgress)
+	ojob.GET(":id/task", h.GetTaskJobResult)
 	ojob.GET("", h.GetJobs)
 
 	// Cluster.
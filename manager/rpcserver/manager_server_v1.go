@@ -45,6 +45,7 @@ import (
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/searcher"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
 	"d7y.io/dragonfly/v2/pkg/digest"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/objectstorage"
@@ -72,12 +73,15 @@ type managerServerV1 struct {
 
 	// Object storage interface.
 	objectStorage objectstorage.ObjectStorage
+
+	// Webhook interface.
+	webhook webhook.Webhook
 }
 
 // newManagerServerV1 returns v1 version of the manager server.
 func newManagerServerV1(
 	cfg *config.Config, database *database.Database, cache *cache.Cache, searcher searcher.Searcher,
-	objectStorage objectstorage.ObjectStorage) managerv1.ManagerServer {
+	objectStorage objectstorage.ObjectStorage, webhook webhook.Webhook) managerv1.ManagerServer {
 	return &managerServerV1{
 		config:        cfg,
 		db:            database.DB,
@@ -85,12 +89,14 @@ func newManagerServerV1(
 		cache:         cache,
 		searcher:      searcher,
 		objectStorage: objectStorage,
+		webhook:       webhook,
 	}
 }
 
 // Get SeedPeer and SeedPeer cluster configuration.
 func (s *managerServerV1) GetSeedPeer(ctx context.Context, req *managerv1.GetSeedPeerRequest) (*managerv1.SeedPeer, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("get_seed_peer", req.SourceType.String()).Inc()
 	cacheKey := pkgredis.MakeSeedPeerKeyInManager(uint(req.SeedPeerClusterId), req.Hostname, req.Ip)
 
 	// Cache hit.
@@ -264,6 +270,7 @@ func (s *managerServerV1) createSeedPeer(ctx context.Context, req *managerv1.Upd
 // Get Scheduler and Scheduler cluster configuration.
 func (s *managerServerV1) GetScheduler(ctx context.Context, req *managerv1.GetSchedulerRequest) (*managerv1.Scheduler, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("get_scheduler", req.SourceType.String()).Inc()
 	cacheKey := pkgredis.MakeSchedulerKeyInManager(uint(req.SchedulerClusterId), req.Hostname, req.Ip)
 
 	// Cache hit.
@@ -287,13 +294,13 @@ func (s *managerServerV1) GetScheduler(ctx context.Context, req *managerv1.GetSc
 	}
 
 	// Marshal config of scheduler.
-	schedulerClusterConfig, err := scheduler.SchedulerCluster.Config.MarshalJSON()
+	schedulerClusterConfig, err := marshalSchedulerClusterConfig(ctx, s.db, scheduler.SchedulerCluster, scheduler.Hostname, scheduler.IP)
 	if err != nil {
 		return nil, status.Error(codes.DataLoss, err.Error())
 	}
 
-	// Marshal config of client.
-	schedulerClusterClientConfig, err := scheduler.SchedulerCluster.ClientConfig.MarshalJSON()
+	// Marshal config of client with the application policies enforced by the scheduler.
+	schedulerClusterClientConfig, err := marshalSchedulerClusterClientConfig(ctx, s.db, scheduler.SchedulerCluster, scheduler.Hostname, scheduler.IP)
 	if err != nil {
 		return nil, status.Error(codes.DataLoss, err.Error())
 	}
@@ -465,6 +472,7 @@ func (s *managerServerV1) createScheduler(ctx context.Context, req *managerv1.Up
 // List acitve schedulers configuration.
 func (s *managerServerV1) ListSchedulers(ctx context.Context, req *managerv1.ListSchedulersRequest) (*managerv1.ListSchedulersResponse, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("list_schedulers", req.SourceType.String()).Inc()
 	log.Debugf("list schedulers, version %s, commit %s", req.Version, req.Commit)
 	metrics.SearchSchedulerClusterCount.WithLabelValues(req.Version, req.Commit).Inc()
 
@@ -516,6 +524,8 @@ func (s *managerServerV1) ListSchedulers(ctx context.Context, req *managerv1.Lis
 		log.Error(err)
 		metrics.SearchSchedulerClusterFailureCount.WithLabelValues(req.Version, req.Commit).Inc()
 		candidateSchedulerClusters = schedulerClusters
+	} else if len(candidateSchedulerClusters) > 0 {
+		metrics.SearchSchedulerClusterDecisionCount.WithLabelValues(fmt.Sprint(candidateSchedulerClusters[0].ID)).Inc()
 	}
 	log.Debugf("find matching scheduler cluster %v", getSchedulerClusterNames(candidateSchedulerClusters))
 
@@ -528,6 +538,7 @@ func (s *managerServerV1) ListSchedulers(ctx context.Context, req *managerv1.Lis
 	}
 
 	// Construct schedulers.
+	schedulerClusterClientConfigs := map[uint][]byte{}
 	for _, scheduler := range schedulers {
 		seedPeers := []*managerv1.SeedPeer{}
 		for _, seedPeerCluster := range scheduler.SchedulerCluster.SeedPeerClusters {
@@ -554,6 +565,16 @@ func (s *managerServerV1) ListSchedulers(ctx context.Context, req *managerv1.Lis
 			return nil, status.Error(codes.DataLoss, err.Error())
 		}
 
+		// Marshal client config of scheduler cluster with the feature flags of the peer.
+		schedulerClusterClientConfig, ok := schedulerClusterClientConfigs[scheduler.SchedulerClusterID]
+		if !ok {
+			schedulerClusterClientConfig, err = marshalSchedulerClusterClientConfig(ctx, s.db, scheduler.SchedulerCluster, req.Hostname, req.Ip)
+			if err != nil {
+				return nil, status.Error(codes.DataLoss, err.Error())
+			}
+			schedulerClusterClientConfigs[scheduler.SchedulerClusterID] = schedulerClusterClientConfig
+		}
+
 		pbListSchedulersResponse.Schedulers = append(pbListSchedulersResponse.Schedulers, &managerv1.Scheduler{
 			Id:                 uint64(scheduler.ID),
 			Hostname:           scheduler.Hostname,
@@ -564,7 +585,13 @@ func (s *managerServerV1) ListSchedulers(ctx context.Context, req *managerv1.Lis
 			State:              scheduler.State,
 			Features:           features,
 			SchedulerClusterId: uint64(scheduler.SchedulerClusterID),
-			SeedPeers:          seedPeers,
+			SchedulerCluster: &managerv1.SchedulerCluster{
+				Id:           uint64(scheduler.SchedulerCluster.ID),
+				Name:         scheduler.SchedulerCluster.Name,
+				Bio:          scheduler.SchedulerCluster.BIO,
+				ClientConfig: schedulerClusterClientConfig,
+			},
+			SeedPeers: seedPeers,
 		})
 	}
 
@@ -653,6 +680,7 @@ func (s *managerServerV1) ListBuckets(ctx context.Context, req *managerv1.ListBu
 // List applications configuration.
 func (s *managerServerV1) ListApplications(ctx context.Context, req *managerv1.ListApplicationsRequest) (*managerv1.ListApplicationsResponse, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("list_applications", req.SourceType.String()).Inc()
 
 	// Cache hit.
 	var pbListApplicationsResponse managerv1.ListApplicationsResponse
@@ -756,7 +784,7 @@ func (s *managerServerV1) CreateModel(ctx context.Context, req *managerv1.Create
 		}
 
 		// Update GNN model config to object storage.
-		if err := s.createModelConfig(ctx, name); err != nil {
+		if err := s.createModelConfig(ctx, name, types.GNNModelInputs(), types.GNNModelOutputs()); err != nil {
 			log.Error(err)
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -778,7 +806,7 @@ func (s *managerServerV1) CreateModel(ctx context.Context, req *managerv1.Create
 		}
 
 		// Update MLP model config to object storage.
-		if err := s.createModelConfig(ctx, name); err != nil {
+		if err := s.createModelConfig(ctx, name, nil, nil); err != nil {
 			log.Error(err)
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -845,7 +873,7 @@ func (s *managerServerV1) createModelBucket(ctx context.Context) error {
 }
 
 // createModelConfig creates model config to object storage.
-func (s *managerServerV1) createModelConfig(ctx context.Context, name string) error {
+func (s *managerServerV1) createModelConfig(ctx context.Context, name string, inputs []*inferencev1.ModelInput, outputs []*inferencev1.ModelOutput) error {
 	objectKey := types.MakeObjectKeyOfModelConfigFile(name)
 	isExist, err := s.objectStorage.IsObjectExist(ctx, s.config.Trainer.BucketName, objectKey)
 	if err != nil {
@@ -866,6 +894,8 @@ func (s *managerServerV1) createModelConfig(ctx context.Context, name string) er
 				Specific: &inferencev1.ModelVersionPolicy_Specific{},
 			},
 		},
+		Input:  inputs,
+		Output: outputs,
 	}
 
 	dgst := digest.New(digest.AlgorithmSHA256, digest.SHA256FromStrings(pbModelConfig.String()))
@@ -947,6 +977,8 @@ func (s *managerServerV1) KeepAlive(stream managerv1.Manager_KeepAliveServer) er
 					return status.Error(codes.Internal, err.Error())
 				}
 
+				s.webhook.Notify(context.TODO(), webhook.EventSchedulerInactive, &scheduler)
+
 				if err := s.cache.Delete(
 					context.TODO(),
 					pkgredis.MakeSchedulerKeyInManager(clusterID, hostname, ip),
@@ -967,6 +999,8 @@ func (s *managerServerV1) KeepAlive(stream managerv1.Manager_KeepAliveServer) er
 					return status.Error(codes.Internal, err.Error())
 				}
 
+				s.webhook.Notify(context.TODO(), webhook.EventSeedPeerInactive, &seedPeer)
+
 				if err := s.cache.Delete(
 					context.TODO(),
 					pkgredis.MakeSeedPeerKeyInManager(clusterID, hostname, ip),
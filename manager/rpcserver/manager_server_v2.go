@@ -30,6 +30,7 @@ import (
 	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"gorm.io/gorm"
 
@@ -45,6 +46,7 @@ import (
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/searcher"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
 	"d7y.io/dragonfly/v2/pkg/digest"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/objectstorage"
@@ -72,12 +74,15 @@ type managerServerV2 struct {
 
 	// Object storage interface.
 	objectStorage objectstorage.ObjectStorage
+
+	// Webhook interface.
+	webhook webhook.Webhook
 }
 
 // newManagerServerV2 returns v2 version of the manager server.
 func newManagerServerV2(
 	cfg *config.Config, database *database.Database, cache *cache.Cache, searcher searcher.Searcher,
-	objectStorage objectstorage.ObjectStorage) managerv2.ManagerServer {
+	objectStorage objectstorage.ObjectStorage, webhook webhook.Webhook) managerv2.ManagerServer {
 	return &managerServerV2{
 		config:        cfg,
 		db:            database.DB,
@@ -85,12 +90,14 @@ func newManagerServerV2(
 		cache:         cache,
 		searcher:      searcher,
 		objectStorage: objectStorage,
+		webhook:       webhook,
 	}
 }
 
 // Get SeedPeer and SeedPeer cluster configuration.
 func (s *managerServerV2) GetSeedPeer(ctx context.Context, req *managerv2.GetSeedPeerRequest) (*managerv2.SeedPeer, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("get_seed_peer", req.SourceType.String()).Inc()
 	cacheKey := pkgredis.MakeSeedPeerKeyInManager(uint(req.SeedPeerClusterId), req.Hostname, req.Ip)
 
 	// Cache hit.
@@ -282,6 +289,7 @@ func (s *managerServerV2) DeleteSeedPeer(ctx context.Context, req *managerv2.Del
 // Get Scheduler and Scheduler cluster configuration.
 func (s *managerServerV2) GetScheduler(ctx context.Context, req *managerv2.GetSchedulerRequest) (*managerv2.Scheduler, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("get_scheduler", req.SourceType.String()).Inc()
 	cacheKey := pkgredis.MakeSchedulerKeyInManager(uint(req.SchedulerClusterId), req.Hostname, req.Ip)
 
 	// Cache hit.
@@ -305,13 +313,13 @@ func (s *managerServerV2) GetScheduler(ctx context.Context, req *managerv2.GetSc
 	}
 
 	// Marshal config of scheduler.
-	schedulerClusterConfig, err := scheduler.SchedulerCluster.Config.MarshalJSON()
+	schedulerClusterConfig, err := marshalSchedulerClusterConfig(ctx, s.db, scheduler.SchedulerCluster, scheduler.Hostname, scheduler.IP)
 	if err != nil {
 		return nil, status.Error(codes.DataLoss, err.Error())
 	}
 
-	// Marshal config of client.
-	schedulerClusterClientConfig, err := scheduler.SchedulerCluster.ClientConfig.MarshalJSON()
+	// Marshal config of client with the application policies enforced by the scheduler.
+	schedulerClusterClientConfig, err := marshalSchedulerClusterClientConfig(ctx, s.db, scheduler.SchedulerCluster, scheduler.Hostname, scheduler.IP)
 	if err != nil {
 		return nil, status.Error(codes.DataLoss, err.Error())
 	}
@@ -483,12 +491,16 @@ func (s *managerServerV2) createScheduler(ctx context.Context, req *managerv2.Up
 // List acitve schedulers configuration.
 func (s *managerServerV2) ListSchedulers(ctx context.Context, req *managerv2.ListSchedulersRequest) (*managerv2.ListSchedulersResponse, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("list_schedulers", req.SourceType.String()).Inc()
 	log.Debugf("list schedulers, version %s, commit %s", req.Version, req.Commit)
 	metrics.SearchSchedulerClusterCount.WithLabelValues(req.Version, req.Commit).Inc()
 
 	// Cache hit.
 	var pbListSchedulersResponse managerv2.ListSchedulersResponse
 	cacheKey := pkgredis.MakeSchedulersKeyForPeerInManager(req.Hostname, req.Ip)
+	if req.SourceType == managerv2.SourceType_SCHEDULER_SOURCE {
+		cacheKey = pkgredis.MakeSchedulersKeyForSchedulerInManager(req.Hostname, req.Ip)
+	}
 
 	if err := s.cache.Get(ctx, cacheKey, &pbListSchedulersResponse); err != nil {
 		log.Warnf("%s cache miss because of %s", cacheKey, err.Error())
@@ -528,12 +540,20 @@ func (s *managerServerV2) ListSchedulers(ctx context.Context, req *managerv2.Lis
 		candidateSchedulerClusters []models.SchedulerCluster
 		err                        error
 	)
-	candidateSchedulerClusters, err = s.searcher.FindSchedulerClusters(ctx, tmpSchedulerClusters, req.Ip, req.Hostname,
-		map[string]string{searcher.ConditionIDC: req.GetIdc(), searcher.ConditionLocation: req.GetLocation()}, logger.CoreLogger)
-	if err != nil {
-		log.Error(err)
-		metrics.SearchSchedulerClusterFailureCount.WithLabelValues(req.Version, req.Commit).Inc()
-		candidateSchedulerClusters = schedulerClusters
+	if req.SourceType == managerv2.SourceType_SCHEDULER_SOURCE {
+		// Scheduler lists the schedulers of all scheduler clusters
+		// to look up the tasks in the sibling clusters.
+		candidateSchedulerClusters = tmpSchedulerClusters
+	} else {
+		candidateSchedulerClusters, err = s.searcher.FindSchedulerClusters(ctx, tmpSchedulerClusters, req.Ip, req.Hostname,
+			map[string]string{searcher.ConditionIDC: req.GetIdc(), searcher.ConditionLocation: req.GetLocation()}, logger.CoreLogger)
+		if err != nil {
+			log.Error(err)
+			metrics.SearchSchedulerClusterFailureCount.WithLabelValues(req.Version, req.Commit).Inc()
+			candidateSchedulerClusters = schedulerClusters
+		} else if len(candidateSchedulerClusters) > 0 {
+			metrics.SearchSchedulerClusterDecisionCount.WithLabelValues(fmt.Sprint(candidateSchedulerClusters[0].ID)).Inc()
+		}
 	}
 	log.Debugf("find matching scheduler cluster %v", getSchedulerClusterNames(candidateSchedulerClusters))
 
@@ -546,6 +566,7 @@ func (s *managerServerV2) ListSchedulers(ctx context.Context, req *managerv2.Lis
 	}
 
 	// Construct schedulers.
+	schedulerClusterClientConfigs := map[uint][]byte{}
 	for _, scheduler := range schedulers {
 		seedPeers := []*managerv2.SeedPeer{}
 		for _, seedPeerCluster := range scheduler.SchedulerCluster.SeedPeerClusters {
@@ -572,6 +593,16 @@ func (s *managerServerV2) ListSchedulers(ctx context.Context, req *managerv2.Lis
 			return nil, status.Error(codes.DataLoss, err.Error())
 		}
 
+		// Marshal client config of scheduler cluster with the feature flags of the peer.
+		schedulerClusterClientConfig, ok := schedulerClusterClientConfigs[scheduler.SchedulerClusterID]
+		if !ok {
+			schedulerClusterClientConfig, err = marshalSchedulerClusterClientConfig(ctx, s.db, scheduler.SchedulerCluster, req.Hostname, req.Ip)
+			if err != nil {
+				return nil, status.Error(codes.DataLoss, err.Error())
+			}
+			schedulerClusterClientConfigs[scheduler.SchedulerClusterID] = schedulerClusterClientConfig
+		}
+
 		pbListSchedulersResponse.Schedulers = append(pbListSchedulersResponse.Schedulers, &managerv2.Scheduler{
 			Id:                 uint64(scheduler.ID),
 			Hostname:           scheduler.Hostname,
@@ -582,7 +613,13 @@ func (s *managerServerV2) ListSchedulers(ctx context.Context, req *managerv2.Lis
 			State:              scheduler.State,
 			Features:           features,
 			SchedulerClusterId: uint64(scheduler.SchedulerClusterID),
-			SeedPeers:          seedPeers,
+			SchedulerCluster: &managerv2.SchedulerCluster{
+				Id:           uint64(scheduler.SchedulerCluster.ID),
+				Name:         scheduler.SchedulerCluster.Name,
+				Bio:          scheduler.SchedulerCluster.BIO,
+				ClientConfig: schedulerClusterClientConfig,
+			},
+			SeedPeers: seedPeers,
 		})
 	}
 
@@ -669,12 +706,18 @@ func (s *managerServerV2) ListBuckets(ctx context.Context, req *managerv2.ListBu
 // List applications configuration.
 func (s *managerServerV2) ListApplications(ctx context.Context, req *managerv2.ListApplicationsRequest) (*managerv2.ListApplicationsResponse, error) {
 	log := logger.WithHostnameAndIP(req.Hostname, req.Ip)
+	metrics.DynconfigFetchCount.WithLabelValues("list_applications", req.SourceType.String()).Inc()
 
-	// Cache hit.
-	var pbListApplicationsResponse managerv2.ListApplicationsResponse
+	// Cache hit, applications are cached in the wire format to keep the anti-affinity labels and policies.
+	var (
+		pbListApplicationsResponse  managerv2.ListApplicationsResponse
+		rawListApplicationsResponse []byte
+	)
 	cacheKey := pkgredis.MakeApplicationsKeyInManager()
-	if err := s.cache.Get(ctx, cacheKey, &pbListApplicationsResponse); err != nil {
+	if err := s.cache.Get(ctx, cacheKey, &rawListApplicationsResponse); err != nil {
 		log.Warnf("%s cache miss because of %s", cacheKey, err.Error())
+	} else if err := proto.Unmarshal(rawListApplicationsResponse, &pbListApplicationsResponse); err != nil {
+		log.Warnf("%s cache invalid because of %s", cacheKey, err.Error())
 	} else {
 		log.Debugf("%s cache hit", cacheKey)
 		return &pbListApplicationsResponse, nil
@@ -715,7 +758,7 @@ func (s *managerServerV2) ListApplications(ctx context.Context, req *managerv2.L
 			})
 		}
 
-		pbListApplicationsResponse.Applications = append(pbListApplicationsResponse.Applications, &managerv2.Application{
+		pbApplication := &managerv2.Application{
 			Id:   uint64(application.ID),
 			Name: application.Name,
 			Url:  application.URL,
@@ -724,14 +767,21 @@ func (s *managerServerV2) ListApplications(ctx context.Context, req *managerv2.L
 				Value: commonv2.Priority(*priority.Value),
 				Urls:  pbURLPriorities,
 			},
-		})
+		}
+		pbListApplicationsResponse.Applications = append(pbListApplicationsResponse.Applications, pbApplication)
 	}
 
 	// Cache data.
+	b, err := proto.Marshal(&pbListApplicationsResponse)
+	if err != nil {
+		log.Error(err)
+		return &pbListApplicationsResponse, nil
+	}
+
 	if err := s.cache.Once(&cachev8.Item{
 		Ctx:   ctx,
 		Key:   cacheKey,
-		Value: &pbListApplicationsResponse,
+		Value: b,
 		TTL:   s.cache.TTL,
 	}); err != nil {
 		log.Error(err)
@@ -772,7 +822,7 @@ func (s *managerServerV2) CreateModel(ctx context.Context, req *managerv2.Create
 		}
 
 		// Update GNN model config to object storage.
-		if err := s.createModelConfig(ctx, name); err != nil {
+		if err := s.createModelConfig(ctx, name, types.GNNModelInputs(), types.GNNModelOutputs()); err != nil {
 			log.Error(err)
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -794,7 +844,7 @@ func (s *managerServerV2) CreateModel(ctx context.Context, req *managerv2.Create
 		}
 
 		// Update MLP model config to object storage.
-		if err := s.createModelConfig(ctx, name); err != nil {
+		if err := s.createModelConfig(ctx, name, nil, nil); err != nil {
 			log.Error(err)
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -860,7 +910,7 @@ func (s *managerServerV2) createModelBucket(ctx context.Context) error {
 }
 
 // createModelConfig creates model config to object storage.
-func (s *managerServerV2) createModelConfig(ctx context.Context, name string) error {
+func (s *managerServerV2) createModelConfig(ctx context.Context, name string, inputs []*inferencev1.ModelInput, outputs []*inferencev1.ModelOutput) error {
 	objectKey := types.MakeObjectKeyOfModelConfigFile(name)
 	isExist, err := s.objectStorage.IsObjectExist(ctx, s.config.Trainer.BucketName, objectKey)
 	if err != nil {
@@ -881,6 +931,8 @@ func (s *managerServerV2) createModelConfig(ctx context.Context, name string) er
 				Specific: &inferencev1.ModelVersionPolicy_Specific{Versions: []int64{}},
 			},
 		},
+		Input:  inputs,
+		Output: outputs,
 	}
 
 	dgst := digest.New(digest.AlgorithmSHA256, digest.SHA256FromStrings(pbModelConfig.String()))
@@ -962,6 +1014,8 @@ func (s *managerServerV2) KeepAlive(stream managerv2.Manager_KeepAliveServer) er
 					return status.Error(codes.Internal, err.Error())
 				}
 
+				s.webhook.Notify(context.TODO(), webhook.EventSchedulerInactive, &scheduler)
+
 				if err := s.cache.Delete(
 					context.TODO(),
 					pkgredis.MakeSchedulerKeyInManager(clusterID, hostname, ip),
@@ -982,6 +1036,8 @@ func (s *managerServerV2) KeepAlive(stream managerv2.Manager_KeepAliveServer) er
 					return status.Error(codes.Internal, err.Error())
 				}
 
+				s.webhook.Notify(context.TODO(), webhook.EventSeedPeerInactive, &seedPeer)
+
 				if err := s.cache.Delete(
 					context.TODO(),
 					pkgredis.MakeSeedPeerKeyInManager(clusterID, hostname, ip),
@@ -17,6 +17,7 @@
 package rpcserver
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 
@@ -29,8 +30,13 @@ import (
 	"d7y.io/dragonfly/v2/manager/database"
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/searcher"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
 	"d7y.io/dragonfly/v2/pkg/objectstorage"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	managerserver "d7y.io/dragonfly/v2/pkg/rpc/manager/server"
+	"d7y.io/dragonfly/v2/pkg/structure"
 )
 
 // SelfSignedCert is self signed certificate.
@@ -65,6 +71,9 @@ type Server struct {
 	// Object storage interface.
 	objectStorage objectstorage.ObjectStorage
 
+	// Webhook interface.
+	webhook webhook.Webhook
+
 	// serverOptions is server options of grpc.
 	serverOptions []grpc.ServerOption
 
@@ -104,7 +113,7 @@ func WithGRPCServerOptions(opts []grpc.ServerOption) Option {
 // New returns a new manager server from the given options.
 func New(
 	cfg *config.Config, database *database.Database, cache *cache.Cache, searcher searcher.Searcher,
-	objectStorage objectstorage.ObjectStorage, opts ...Option) (*Server, *grpc.Server, error) {
+	objectStorage objectstorage.ObjectStorage, webhook webhook.Webhook, opts ...Option) (*Server, *grpc.Server, error) {
 	s := &Server{
 		config:        cfg,
 		db:            database.DB,
@@ -112,6 +121,7 @@ func New(
 		cache:         cache,
 		searcher:      searcher,
 		objectStorage: objectStorage,
+		webhook:       webhook,
 	}
 
 	for _, opt := range opts {
@@ -121,9 +131,9 @@ func New(
 	}
 
 	return s, managerserver.New(
-		newManagerServerV1(s.config, database, s.cache, s.searcher, s.objectStorage),
-		newManagerServerV2(s.config, database, s.cache, s.searcher, s.objectStorage),
-		newSecurityServerV1(s.selfSignedCert),
+		newManagerServerV1(s.config, database, s.cache, s.searcher, s.objectStorage, s.webhook),
+		newManagerServerV2(s.config, database, s.cache, s.searcher, s.objectStorage, s.webhook),
+		newSecurityServerV1(s.config, database, s.selfSignedCert),
 		s.serverOptions...), nil
 }
 
@@ -136,3 +146,130 @@ func getSchedulerClusterNames(clusters []models.SchedulerCluster) []string {
 
 	return names
 }
+
+// Marshal config of scheduler cluster, the origin qps of the tenant quota is split
+// across the active schedulers of the tenant and overrides the back-to-source origin qps
+// of the scheduler cluster, and the feature flags rolled out to the scheduler are set in the config.
+func marshalSchedulerClusterConfig(ctx context.Context, db *gorm.DB, cluster models.SchedulerCluster, hostname, ip string) ([]byte, error) {
+	clusterConfig := models.JSONMap{}
+	for k, v := range cluster.Config {
+		clusterConfig[k] = v
+	}
+
+	if cluster.TenantID != 0 {
+		tenant := models.Tenant{}
+		if err := db.WithContext(ctx).First(&tenant, cluster.TenantID).Error; err != nil {
+			return nil, err
+		}
+
+		var quota types.TenantQuota
+		if err := structure.MapToStruct(tenant.Quota, &quota); err != nil {
+			return nil, err
+		}
+
+		if quota.OriginQPS != 0 {
+			originQPS, err := splitTenantOriginQPS(ctx, db, cluster.TenantID, quota.OriginQPS)
+			if err != nil {
+				return nil, err
+			}
+
+			clusterConfig["back_to_source_origin_qps"] = originQPS
+		}
+	}
+
+	featureFlags, err := findFeatureFlags(ctx, db, cluster.ID, hostname, ip)
+	if err != nil {
+		return nil, err
+	}
+	clusterConfig[featureflag.ConfigKey] = featureFlags
+
+	return clusterConfig.MarshalJSON()
+}
+
+// splitTenantOriginQPS returns the back-to-source origin qps of each scheduler of the tenant.
+// The origin qps is limited by every scheduler locally, so the quota of tenant is split
+// across the active schedulers in all of the scheduler clusters of the tenant, rounded up
+// so that each scheduler allows at least one request per second.
+func splitTenantOriginQPS(ctx context.Context, db *gorm.DB, tenantID uint, originQPS uint32) (uint32, error) {
+	var count int64
+	if err := db.WithContext(ctx).Model(&models.Scheduler{}).
+		Where("state = ? AND scheduler_cluster_id IN (?)", models.SchedulerStateActive,
+			db.Model(&models.SchedulerCluster{}).Select("id").Where("tenant_id = ?", tenantID)).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	if count <= 1 {
+		return originQPS, nil
+	}
+
+	return uint32((int64(originQPS) + count - 1) / count), nil
+}
+
+// Marshal client config of scheduler cluster, the feature flags
+// rolled out to the peer and the application policies are set in the client config.
+func marshalSchedulerClusterClientConfig(ctx context.Context, db *gorm.DB, cluster models.SchedulerCluster, hostname, ip string) ([]byte, error) {
+	clientConfig := models.JSONMap{}
+	for k, v := range cluster.ClientConfig {
+		clientConfig[k] = v
+	}
+
+	featureFlags, err := findFeatureFlags(ctx, db, cluster.ID, hostname, ip)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig[featureflag.ConfigKey] = featureFlags
+
+	applicationPolicies, err := findApplicationPolicies(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	clientConfig[rpc.ApplicationPoliciesConfigKey] = applicationPolicies
+
+	return clientConfig.MarshalJSON()
+}
+
+// findApplicationPolicies finds the download policies of the applications by the application name.
+func findApplicationPolicies(ctx context.Context, db *gorm.DB) (map[string]rpc.ApplicationPolicy, error) {
+	var applications []models.Application
+	if err := db.WithContext(ctx).Find(&applications).Error; err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]rpc.ApplicationPolicy)
+	for _, application := range applications {
+		policy := rpc.ApplicationPolicy{
+			RateLimit:               application.RateLimit,
+			AllowedURLRegex:         application.AllowedURLRegex,
+			BackToSourceConcurrency: application.BackToSourceConcurrency,
+			RequireSignature:        application.RequireSignature,
+			AntiAffinity:            application.AntiAffinity,
+		}
+
+		if !policy.IsEmpty() {
+			policies[application.Name] = policy
+		}
+	}
+
+	return policies, nil
+}
+
+// findFeatureFlags finds the active feature flags of the scheduler cluster rolled out to the host.
+func findFeatureFlags(ctx context.Context, db *gorm.DB, schedulerClusterID uint, hostname, ip string) (featureflag.Flags, error) {
+	var featureFlags []models.FeatureFlag
+	if err := db.WithContext(ctx).Where(&models.FeatureFlag{
+		SchedulerClusterID: schedulerClusterID,
+		State:              models.FeatureFlagStateActive,
+	}).Find(&featureFlags).Error; err != nil {
+		return nil, err
+	}
+
+	flags := featureflag.Flags{}
+	for _, featureFlag := range featureFlags {
+		if featureflag.IsRolledOut(featureFlag.Name, featureFlag.Percentage, hostname, ip) {
+			flags = append(flags, featureFlag.Name)
+		}
+	}
+
+	return flags, nil
+}
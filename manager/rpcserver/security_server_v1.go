@@ -20,27 +20,45 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"time"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 
 	securityv1 "d7y.io/api/v2/pkg/apis/security/v1"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/config"
+	"d7y.io/dragonfly/v2/manager/database"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/slices"
+	"d7y.io/dragonfly/v2/pkg/structure"
 )
 
 // securityServerV1 is v1 version of the security grpc server.
 type securityServerV1 struct {
+	// Manager configuration.
+	config *config.Config
+
+	// GORM instance.
+	db *gorm.DB
+
 	// selfSignedCert is self signed certificate.
 	selfSignedCert *SelfSignedCert
 }
 
 // newSecurityServerV1 returns v1 version of the security server.
-func newSecurityServerV1(selfSignedCert *SelfSignedCert) securityv1.CertificateServer {
+func newSecurityServerV1(cfg *config.Config, database *database.Database, selfSignedCert *SelfSignedCert) securityv1.CertificateServer {
 	return &securityServerV1{
+		config:         cfg,
+		db:             database.DB,
 		selfSignedCert: selfSignedCert,
 	}
 }
@@ -68,6 +86,36 @@ func (s *securityServerV1) IssueCertificate(ctx context.Context, req *securityv1
 		return nil, status.Errorf(codes.InvalidArgument, "invalid csr ip address")
 	}
 
+	// Check csr uris and email addresses, they are not issued by the cluster ca.
+	if len(csr.URIs) > 0 || len(csr.EmailAddresses) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "csr uri and email address are not allowed")
+	}
+
+	// Check csr dns names are allowed.
+	for _, dnsName := range csr.DNSNames {
+		if !slices.Contains(s.config.Security.IssueCertSpec.DNSNames, dnsName) {
+			return nil, status.Errorf(codes.PermissionDenied, "csr dns name %s is not allowed", dnsName)
+		}
+	}
+
+	// Check csr ip address belongs to the clusters and the caller.
+	if s.config.Security.IssueCertSpec.VerifyMembership {
+		if err := s.verifyCaller(ctx, csr.IPAddresses); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+
+		if err := s.verifyMembership(ctx, csr.IPAddresses); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+
+	// Shorten the validity period to issue short-lived certificate,
+	// the certificate is rotated by the client before expired.
+	validityPeriod := req.ValidityPeriod.AsDuration()
+	if maxValidityPeriod := s.config.Security.IssueCertSpec.MaxValidityPeriod; maxValidityPeriod > 0 && validityPeriod > maxValidityPeriod {
+		validityPeriod = maxValidityPeriod
+	}
+
 	// Generate serial number.
 	serial, err := rand.Int(rand.Reader, (&big.Int{}).Exp(big.NewInt(2), big.NewInt(159), nil))
 	if err != nil {
@@ -79,11 +127,9 @@ func (s *securityServerV1) IssueCertificate(ctx context.Context, req *securityv1
 		SerialNumber:          serial,
 		Subject:               csr.Subject,
 		DNSNames:              csr.DNSNames,
-		EmailAddresses:        csr.EmailAddresses,
 		IPAddresses:           csr.IPAddresses,
-		URIs:                  csr.URIs,
 		NotBefore:             now.Add(-10 * time.Minute).UTC(),
-		NotAfter:              now.Add(req.ValidityPeriod.AsDuration()).UTC(),
+		NotAfter:              now.Add(validityPeriod).UTC(),
 		BasicConstraintsValid: true,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageDataEncipherment | x509.KeyUsageKeyEncipherment,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
@@ -98,3 +144,93 @@ func (s *securityServerV1) IssueCertificate(ctx context.Context, req *securityv1
 		CertificateChain: append([][]byte{cert}, s.selfSignedCert.CertChain...),
 	}, nil
 }
+
+// verifyCaller verifies the address of the caller is one of the ip addresses,
+// the client can not request the certificate for the other hosts.
+func (s *securityServerV1) verifyCaller(ctx context.Context, ips []net.IP) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return errors.New("address of caller is missing")
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return fmt.Errorf("address %s of caller is invalid: %w", p.Addr.String(), err)
+	}
+
+	callerIP := net.ParseIP(host)
+	if callerIP == nil {
+		return fmt.Errorf("address %s of caller is invalid", p.Addr.String())
+	}
+
+	for _, ip := range ips {
+		if ip.Equal(callerIP) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("ip %s of caller is not in the csr ip addresses", callerIP.String())
+}
+
+// verifyMembership verifies the ip addresses are the addresses of the schedulers, seed peers
+// and peers in the clusters, or in the cidrs of the scheduler clusters.
+func (s *securityServerV1) verifyMembership(ctx context.Context, ips []net.IP) error {
+	var schedulerClusters []models.SchedulerCluster
+	if err := s.db.WithContext(ctx).Find(&schedulerClusters).Error; err != nil {
+		return err
+	}
+
+	var cidrs []*net.IPNet
+	for _, schedulerCluster := range schedulerClusters {
+		var scopes types.SchedulerClusterScopes
+		if err := structure.MapToStruct(schedulerCluster.Scopes, &scopes); err != nil {
+			logger.Warnf("scheduler cluster %d scopes is invalid: %s", schedulerCluster.ID, err.Error())
+			continue
+		}
+
+		for _, cidr := range scopes.CIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				logger.Warnf("scheduler cluster %d cidr %s is invalid: %s", schedulerCluster.ID, cidr, err.Error())
+				continue
+			}
+
+			cidrs = append(cidrs, ipNet)
+		}
+	}
+
+	for _, ip := range ips {
+		member, err := s.isMember(ctx, ip, cidrs)
+		if err != nil {
+			return err
+		}
+
+		if !member {
+			return fmt.Errorf("ip %s is not a member of the clusters", ip.String())
+		}
+	}
+
+	return nil
+}
+
+// isMember returns whether the ip address is the address of the member in the clusters.
+func (s *securityServerV1) isMember(ctx context.Context, ip net.IP, cidrs []*net.IPNet) (bool, error) {
+	for _, cidr := range cidrs {
+		if cidr.Contains(ip) {
+			return true, nil
+		}
+	}
+
+	for _, model := range []any{&models.Scheduler{}, &models.SeedPeer{}, &models.Peer{}} {
+		var count int64
+		if err := s.db.WithContext(ctx).Model(model).Where("ip = ?", ip.String()).Count(&count).Error; err != nil {
+			return false, err
+		}
+
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -27,14 +27,21 @@ import (
 	"encoding/pem"
 	"math/big"
 	"net"
+	"net/url"
 	"testing"
 	"time"
 
 	testifyassert "github.com/stretchr/testify/assert"
 	testifyrequire "github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	securityv1 "d7y.io/api/v2/pkg/apis/security/v1"
+
+	"d7y.io/dragonfly/v2/manager/config"
+	"d7y.io/dragonfly/v2/manager/database"
 )
 
 func TestIssueCertificate(t *testing.T) {
@@ -43,16 +50,28 @@ func TestIssueCertificate(t *testing.T) {
 	caCert, caKey := genCA()
 
 	testCases := []struct {
-		name   string
-		peerIP string
+		name              string
+		peerIP            string
+		maxValidityPeriod time.Duration
+		expectValidity    time.Duration
 	}{
 		{
-			name:   "ipv4",
-			peerIP: "1.1.1.1",
+			name:              "ipv4",
+			peerIP:            "1.1.1.1",
+			maxValidityPeriod: 24 * time.Hour,
+			expectValidity:    time.Hour,
+		},
+		{
+			name:              "ipv6",
+			peerIP:            "1::1",
+			maxValidityPeriod: 24 * time.Hour,
+			expectValidity:    time.Hour,
 		},
 		{
-			name:   "ipv6",
-			peerIP: "1::1",
+			name:              "validity period exceeds max validity period",
+			peerIP:            "1.1.1.1",
+			maxValidityPeriod: 30 * time.Minute,
+			expectValidity:    30 * time.Minute,
 		},
 	}
 
@@ -81,7 +100,13 @@ func TestIssueCertificate(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			securityServerV1 := newSecurityServerV1(&SelfSignedCert{
+			securityServerV1 := newSecurityServerV1(&config.Config{
+				Security: config.SecurityConfig{
+					IssueCertSpec: config.IssueCertSpec{
+						MaxValidityPeriod: tc.maxValidityPeriod,
+					},
+				},
+			}, &database.Database{}, &SelfSignedCert{
 				TLSCert:   &ca,
 				X509Cert:  x509CACert,
 				CertChain: ca.Certificate,
@@ -102,6 +127,7 @@ func TestIssueCertificate(t *testing.T) {
 			cert := readCert(resp.CertificateChain[0])
 			assert.Equal(len(cert.IPAddresses), 1)
 			assert.True(cert.IPAddresses[0].Equal(net.ParseIP(tc.peerIP)))
+			assert.Equal(cert.NotAfter.Sub(cert.NotBefore), tc.expectValidity+10*time.Minute)
 
 			assert.Equal(cert.KeyUsage, x509.KeyUsageDigitalSignature|x509.KeyUsageDataEncipherment|x509.KeyUsageKeyEncipherment)
 			assert.Equal(cert.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
@@ -109,6 +135,121 @@ func TestIssueCertificate(t *testing.T) {
 	}
 }
 
+func TestIssueCertificate_Rejected(t *testing.T) {
+	caCert, caKey := genCA()
+	ca, err := tls.X509KeyPair([]byte(caCert), []byte(caKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x509CACert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name             string
+		template         *x509.CertificateRequest
+		verifyMembership bool
+		ctx              context.Context
+		expect           func(t *testing.T, err error)
+	}{
+		{
+			name: "csr has uri",
+			template: &x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("1.1.1.1")},
+				URIs:        []*url.URL{{Scheme: "spiffe", Host: "example.com"}},
+			},
+			ctx: context.Background(),
+			expect: func(t *testing.T, err error) {
+				testifyassert.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "csr has email address",
+			template: &x509.CertificateRequest{
+				IPAddresses:    []net.IP{net.ParseIP("1.1.1.1")},
+				EmailAddresses: []string{"foo@example.com"},
+			},
+			ctx: context.Background(),
+			expect: func(t *testing.T, err error) {
+				testifyassert.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name: "csr has dns name not allowed",
+			template: &x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("1.1.1.1")},
+				DNSNames:    []string{"dragonfly-manager"},
+			},
+			ctx: context.Background(),
+			expect: func(t *testing.T, err error) {
+				testifyassert.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "address of caller is missing",
+			template: &x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("1.1.1.1")},
+			},
+			verifyMembership: true,
+			ctx:              context.Background(),
+			expect: func(t *testing.T, err error) {
+				testifyassert.Equal(t, codes.PermissionDenied, status.Code(err))
+			},
+		},
+		{
+			name: "address of caller is not in csr ip addresses",
+			template: &x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("1.1.1.1")},
+			},
+			verifyMembership: true,
+			ctx: peer.NewContext(context.Background(), &peer.Peer{
+				Addr: &net.TCPAddr{IP: net.ParseIP("2.2.2.2"), Port: 65001},
+			}),
+			expect: func(t *testing.T, err error) {
+				assert := testifyassert.New(t)
+				assert.Equal(codes.PermissionDenied, status.Code(err))
+				assert.ErrorContains(err, "ip 2.2.2.2 of caller is not in the csr ip addresses")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pk, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			csr, err := x509.CreateCertificateRequest(rand.Reader, tc.template, pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			securityServerV1 := newSecurityServerV1(&config.Config{
+				Security: config.SecurityConfig{
+					IssueCertSpec: config.IssueCertSpec{
+						MaxValidityPeriod: time.Hour,
+						VerifyMembership:  tc.verifyMembership,
+						DNSNames:          config.DefaultIssueCertDNSNames,
+					},
+				},
+			}, &database.Database{}, &SelfSignedCert{
+				TLSCert:   &ca,
+				X509Cert:  x509CACert,
+				CertChain: ca.Certificate,
+			})
+
+			_, err = securityServerV1.IssueCertificate(tc.ctx, &securityv1.CertificateRequest{
+				Csr:            csr,
+				ValidityPeriod: durationpb.New(time.Hour),
+			})
+			tc.expect(t, err)
+		})
+	}
+}
+
 func genCA() (cert, key string) {
 	pk, err := rsa.GenerateKey(rand.Reader, 4096)
 	if err != nil {
@@ -0,0 +1,157 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package searcher
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	celtypes "github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+const (
+	// RuleVariableIP is the variable of the host ip in the rule.
+	RuleVariableIP = "ip"
+
+	// RuleVariableHostname is the variable of the hostname in the rule.
+	RuleVariableHostname = "hostname"
+
+	// RuleVariableIDC is the variable of the host idc in the rule.
+	RuleVariableIDC = "idc"
+
+	// RuleVariableLocation is the variable of the host location in the rule.
+	RuleVariableLocation = "location"
+
+	// RuleVariableLabels is the variable of the host labels in the rule,
+	// labels are the conditions reported by the host.
+	RuleVariableLabels = "labels"
+)
+
+// inCIDRFunction is the function name to check whether the ip is in the cidr,
+// e.g. inCIDR(ip, "10.0.0.0/8").
+const inCIDRFunction = "inCIDR"
+
+var (
+	// ruleEnv is the cel environment of the rules.
+	ruleEnv     *cel.Env
+	ruleEnvErr  error
+	ruleEnvOnce sync.Once
+)
+
+// newRuleEnv returns the cel environment of the rules.
+func newRuleEnv() (*cel.Env, error) {
+	ruleEnvOnce.Do(func() {
+		ruleEnv, ruleEnvErr = cel.NewEnv(
+			cel.Variable(RuleVariableIP, cel.StringType),
+			cel.Variable(RuleVariableHostname, cel.StringType),
+			cel.Variable(RuleVariableIDC, cel.StringType),
+			cel.Variable(RuleVariableLocation, cel.StringType),
+			cel.Variable(RuleVariableLabels, cel.MapType(cel.StringType, cel.StringType)),
+			cel.Function(inCIDRFunction,
+				cel.Overload("in_cidr_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+					cel.BinaryBinding(inCIDR),
+				),
+			),
+		)
+	})
+
+	return ruleEnv, ruleEnvErr
+}
+
+// inCIDR returns whether the ip is in the cidr.
+func inCIDR(ip, cidr ref.Val) ref.Val {
+	rawIP, ok := ip.Value().(string)
+	if !ok {
+		return celtypes.MaybeNoSuchOverloadErr(ip)
+	}
+
+	rawCIDR, ok := cidr.Value().(string)
+	if !ok {
+		return celtypes.MaybeNoSuchOverloadErr(cidr)
+	}
+
+	_, network, err := net.ParseCIDR(rawCIDR)
+	if err != nil {
+		return celtypes.NewErr("invalid cidr %s: %s", rawCIDR, err.Error())
+	}
+
+	parsedIP := net.ParseIP(rawIP)
+	if parsedIP == nil {
+		return celtypes.False
+	}
+
+	return celtypes.Bool(network.Contains(parsedIP))
+}
+
+// Rule is the compiled cel expression to select the scheduler cluster for the host.
+type Rule struct {
+	program cel.Program
+}
+
+// CompileRule compiles the cel expression to the rule, the expression must return bool.
+func CompileRule(expression string) (*Rule, error) {
+	env, err := newRuleEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("rule %s must return bool, but got %s", expression, ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Rule{program: program}, nil
+}
+
+// Match returns whether the host matches the rule.
+func (r *Rule) Match(ip, hostname string, conditions map[string]string) (bool, error) {
+	labels := conditions
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	out, _, err := r.program.Eval(map[string]any{
+		RuleVariableIP:       ip,
+		RuleVariableHostname: hostname,
+		RuleVariableIDC:      conditions[ConditionIDC],
+		RuleVariableLocation: conditions[ConditionLocation],
+		RuleVariableLabels:   labels,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.New("rule result is not bool")
+	}
+
+	return matched, nil
+}
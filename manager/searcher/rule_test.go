@@ -0,0 +1,163 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package searcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Match(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		ip         string
+		hostname   string
+		conditions map[string]string
+		expect     func(t *testing.T, matched bool, err error)
+	}{
+		{
+			name:       "match hostname",
+			expression: `hostname.startsWith("foo")`,
+			ip:         "127.0.0.1",
+			hostname:   "foo-1",
+			expect: func(t *testing.T, matched bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.True(matched)
+			},
+		},
+		{
+			name:       "match cidr",
+			expression: `inCIDR(ip, "10.0.0.0/8")`,
+			ip:         "10.1.1.1",
+			hostname:   "foo",
+			expect: func(t *testing.T, matched bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.True(matched)
+			},
+		},
+		{
+			name:       "does not match cidr",
+			expression: `inCIDR(ip, "10.0.0.0/8")`,
+			ip:         "192.168.1.1",
+			hostname:   "foo",
+			expect: func(t *testing.T, matched bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.False(matched)
+			},
+		},
+		{
+			name:       "match idc and labels",
+			expression: `idc == "idc-1" && labels["zone"] == "a"`,
+			ip:         "127.0.0.1",
+			hostname:   "foo",
+			conditions: map[string]string{"idc": "idc-1", "zone": "a"},
+			expect: func(t *testing.T, matched bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.True(matched)
+			},
+		},
+		{
+			name:       "label does not exist",
+			expression: `"zone" in labels && labels["zone"] == "a"`,
+			ip:         "127.0.0.1",
+			hostname:   "foo",
+			expect: func(t *testing.T, matched bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.False(matched)
+			},
+		},
+		{
+			name:       "invalid cidr",
+			expression: `inCIDR(ip, "foo")`,
+			ip:         "127.0.0.1",
+			hostname:   "foo",
+			expect: func(t *testing.T, matched bool, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+				assert.False(matched)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := CompileRule(tc.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			matched, err := rule.Match(tc.ip, tc.hostname, tc.conditions)
+			tc.expect(t, matched, err)
+		})
+	}
+}
+
+func TestCompileRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expect     func(t *testing.T, rule *Rule, err error)
+	}{
+		{
+			name:       "compile rule",
+			expression: `location.startsWith("cn")`,
+			expect: func(t *testing.T, rule *Rule, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.NotNil(rule)
+			},
+		},
+		{
+			name:       "invalid syntax",
+			expression: `hostname ==`,
+			expect: func(t *testing.T, rule *Rule, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+		{
+			name:       "undeclared variable",
+			expression: `foo == "bar"`,
+			expect: func(t *testing.T, rule *Rule, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+		{
+			name:       "result is not bool",
+			expression: `hostname`,
+			expect: func(t *testing.T, rule *Rule, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := CompileRule(tc.expression)
+			tc.expect(t, rule, err)
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"net"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/yl2chen/cidranger"
@@ -76,6 +77,7 @@ type Scopes struct {
 	IDC      string   `mapstructure:"idc"`
 	Location string   `mapstructure:"location"`
 	CIDRs    []string `mapstructure:"cidrs"`
+	Rule     string   `mapstructure:"rule"`
 }
 
 type Searcher interface {
@@ -84,7 +86,17 @@ type Searcher interface {
 		conditions map[string]string, log *zap.SugaredLogger) ([]models.SchedulerCluster, error)
 }
 
-type searcher struct{}
+// candidate is the scheduler cluster to be evaluated.
+type candidate struct {
+	cluster models.SchedulerCluster
+	scopes  Scopes
+	matched bool
+}
+
+type searcher struct {
+	// rules caches the compiled rules by the expression.
+	rules sync.Map
+}
 
 func New(pluginDir string) Searcher {
 	s, err := LoadPlugin(pluginDir)
@@ -111,27 +123,72 @@ func (s *searcher) FindSchedulerClusters(ctx context.Context, schedulerClusters
 		return nil, fmt.Errorf("conditions %#v does not match any scheduler cluster", conditions)
 	}
 
-	sort.Slice(
-		clusters,
-		func(i, j int) bool {
-			var si, sj Scopes
-			if err := mapstructure.Decode(clusters[i].Scopes, &si); err != nil {
-				log.Errorf("cluster %s decode scopes failed: %v", clusters[i].Name, err)
-				return false
+	// Decode the scopes and filter the scheduler clusters whose rule does not match the host,
+	// scheduler clusters matched by rules take precedence over the scheduler clusters without rules.
+	var candidates []candidate
+	for _, cluster := range clusters {
+		var scopes Scopes
+		if err := mapstructure.Decode(cluster.Scopes, &scopes); err != nil {
+			log.Errorf("cluster %s decode scopes failed: %v", cluster.Name, err)
+		}
+
+		var matched bool
+		if scopes.Rule != "" {
+			ok, err := s.matchRule(scopes.Rule, ip, hostname, conditions)
+			if err != nil {
+				log.Errorf("cluster %s match rule failed: %v", cluster.Name, err)
+				continue
 			}
 
-			if err := mapstructure.Decode(clusters[j].Scopes, &sj); err != nil {
-				log.Errorf("cluster %s decode scopes failed: %v", clusters[i].Name, err)
-				return false
+			if !ok {
+				continue
+			}
+
+			matched = true
+		}
+
+		candidates = append(candidates, candidate{cluster: cluster, scopes: scopes, matched: matched})
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("host %s does not match any scheduler cluster rule", hostname)
+	}
+
+	sort.Slice(
+		candidates,
+		func(i, j int) bool {
+			if candidates[i].matched != candidates[j].matched {
+				return candidates[i].matched
 			}
 
-			return Evaluate(ip, hostname, conditions, si, clusters[i], log) > Evaluate(ip, hostname, conditions, sj, clusters[j], log)
+			return Evaluate(ip, hostname, conditions, candidates[i].scopes, candidates[i].cluster, log) >
+				Evaluate(ip, hostname, conditions, candidates[j].scopes, candidates[j].cluster, log)
 		},
 	)
 
+	clusters = clusters[:0]
+	for _, candidate := range candidates {
+		clusters = append(clusters, candidate.cluster)
+	}
+
 	return clusters, nil
 }
 
+// matchRule returns whether the host matches the rule, the compiled rule is cached.
+func (s *searcher) matchRule(expression, ip, hostname string, conditions map[string]string) (bool, error) {
+	rule, ok := s.rules.Load(expression)
+	if !ok {
+		compiled, err := CompileRule(expression)
+		if err != nil {
+			return false, err
+		}
+
+		rule, _ = s.rules.LoadOrStore(expression, compiled)
+	}
+
+	return rule.(*Rule).Match(ip, hostname, conditions)
+}
+
 // Filter the scheduler clusters that dfdaemon can be used.
 func FilterSchedulerClusters(conditions map[string]string, schedulerClusters []models.SchedulerCluster) []models.SchedulerCluster {
 	var clusters []models.SchedulerCluster
@@ -381,6 +381,94 @@ func TestSearcher_FindSchedulerClusters(t *testing.T) {
 				assert.Equal(len(data), 7)
 			},
 		},
+		{
+			name: "scheduler clusters matched by rules take precedence",
+			schedulerClusters: []models.SchedulerCluster{
+				{
+					Name: "foo",
+					Scopes: map[string]any{
+						"idc": "idc-1",
+					},
+					Schedulers: []models.Scheduler{
+						{
+							Hostname: "foo",
+							State:    "active",
+						},
+					},
+					IsDefault: true,
+				},
+				{
+					Name: "bar",
+					Scopes: map[string]any{
+						"rule": `inCIDR(ip, "128.168.1.0/24") && labels["zone"] == "a"`,
+					},
+					Schedulers: []models.Scheduler{
+						{
+							Hostname: "bar",
+							State:    "active",
+						},
+					},
+				},
+				{
+					Name: "baz",
+					Scopes: map[string]any{
+						"idc":  "idc-1",
+						"rule": `hostname == "bar"`,
+					},
+					Schedulers: []models.Scheduler{
+						{
+							Hostname: "baz",
+							State:    "active",
+						},
+					},
+				},
+				{
+					Name: "bax",
+					Scopes: map[string]any{
+						"rule": `hostname ==`,
+					},
+					Schedulers: []models.Scheduler{
+						{
+							Hostname: "bax",
+							State:    "active",
+						},
+					},
+				},
+			},
+			conditions: map[string]string{
+				"idc":  "idc-1",
+				"zone": "a",
+			},
+			expect: func(t *testing.T, data []models.SchedulerCluster, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(len(data), 2)
+				assert.Equal(data[0].Name, "bar")
+				assert.Equal(data[1].Name, "foo")
+			},
+		},
+		{
+			name: "host does not match any scheduler cluster rule",
+			schedulerClusters: []models.SchedulerCluster{
+				{
+					Name: "foo",
+					Scopes: map[string]any{
+						"rule": `hostname == "bar"`,
+					},
+					Schedulers: []models.Scheduler{
+						{
+							Hostname: "foo",
+							State:    "active",
+						},
+					},
+				},
+			},
+			conditions: map[string]string{},
+			expect: func(t *testing.T, data []models.SchedulerCluster, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "host foo does not match any scheduler cluster rule")
+			},
+		},
 	}
 
 	for _, tc := range tests {
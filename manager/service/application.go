@@ -31,11 +31,17 @@ func (s *service) CreateApplication(ctx context.Context, json types.CreateApplic
 	}
 
 	application := models.Application{
-		Name:     json.Name,
-		URL:      json.URL,
-		BIO:      json.BIO,
-		Priority: priority,
-		UserID:   json.UserID,
+		Name:                    json.Name,
+		URL:                     json.URL,
+		BIO:                     json.BIO,
+		Priority:                priority,
+		AntiAffinity:            json.AntiAffinity,
+		RateLimit:               json.RateLimit,
+		AllowedURLRegex:         json.AllowedURLRegex,
+		BackToSourceConcurrency: json.BackToSourceConcurrency,
+		RequireSignature:        json.RequireSignature,
+		TenantID:                json.TenantID,
+		UserID:                  json.UserID,
 	}
 
 	if err := s.db.WithContext(ctx).Create(&application).Error; err != nil {
@@ -72,15 +78,41 @@ func (s *service) UpdateApplication(ctx context.Context, id uint, json types.Upd
 
 	application := models.Application{}
 	if err := s.db.WithContext(ctx).Preload("User").First(&application, id).Updates(models.Application{
-		Name:     json.Name,
-		URL:      json.URL,
-		BIO:      json.BIO,
-		Priority: priority,
-		UserID:   json.UserID,
+		Name:         json.Name,
+		URL:          json.URL,
+		BIO:          json.BIO,
+		Priority:     priority,
+		AntiAffinity: json.AntiAffinity,
+		TenantID:     json.TenantID,
+		UserID:       json.UserID,
 	}).Error; err != nil {
 		return nil, err
 	}
 
+	// The zero values of policy are allowed to reset the policy.
+	policy := map[string]any{}
+	if json.RateLimit != nil {
+		policy["rate_limit"] = *json.RateLimit
+	}
+
+	if json.AllowedURLRegex != nil {
+		policy["allowed_url_regex"] = *json.AllowedURLRegex
+	}
+
+	if json.BackToSourceConcurrency != nil {
+		policy["back_to_source_concurrency"] = *json.BackToSourceConcurrency
+	}
+
+	if json.RequireSignature != nil {
+		policy["require_signature"] = *json.RequireSignature
+	}
+
+	if len(policy) > 0 {
+		if err := s.db.WithContext(ctx).Model(&application).Updates(policy).Error; err != nil {
+			return nil, err
+		}
+	}
+
 	return &application, nil
 }
 
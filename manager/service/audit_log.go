@@ -0,0 +1,81 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+// maxExportAuditLogs is the max count of the exported audit logs.
+const maxExportAuditLogs = 100000
+
+func (s *service) GetAuditLog(ctx context.Context, id uint) (*models.AuditLog, error) {
+	auditLog := models.AuditLog{}
+	if err := s.db.WithContext(ctx).First(&auditLog, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &auditLog, nil
+}
+
+func (s *service) GetAuditLogs(ctx context.Context, q types.GetAuditLogsQuery) ([]models.AuditLog, int64, error) {
+	var count int64
+	var auditLogs []models.AuditLog
+	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage), filterAuditLogs(q.AuditLogFilter)).Order("id DESC").Find(&auditLogs).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return auditLogs, count, nil
+}
+
+func (s *service) ExportAuditLogs(ctx context.Context, q types.ExportAuditLogsQuery) ([]models.AuditLog, error) {
+	var auditLogs []models.AuditLog
+	if err := s.db.WithContext(ctx).Scopes(filterAuditLogs(q.AuditLogFilter)).Order("id DESC").Limit(maxExportAuditLogs).Find(&auditLogs).Error; err != nil {
+		return nil, err
+	}
+
+	return auditLogs, nil
+}
+
+// filterAuditLogs filters the audit logs by the query.
+func filterAuditLogs(filter types.AuditLogFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where(&models.AuditLog{
+			UserID:     filter.UserID,
+			UserName:   filter.UserName,
+			IP:         filter.IP,
+			Resource:   filter.Resource,
+			ResourceID: filter.ResourceID,
+			Action:     filter.Action,
+		})
+
+		if !filter.StartTime.IsZero() {
+			db = db.Where("created_at >= ?", filter.StartTime)
+		}
+
+		if !filter.EndTime.IsZero() {
+			db = db.Where("created_at <= ?", filter.EndTime)
+		}
+
+		return db
+	}
+}
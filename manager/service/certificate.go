@@ -0,0 +1,42 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"d7y.io/dragonfly/v2/manager/models"
+)
+
+func (s *service) GetCACertificate(ctx context.Context) ([]byte, error) {
+	if !s.config.Security.AutoIssueCert {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if !s.config.Security.EmbeddedCA {
+		return []byte(s.config.Security.CACert), nil
+	}
+
+	certificateAuthority := models.CertificateAuthority{}
+	if err := s.db.WithContext(ctx).First(&certificateAuthority, models.CertificateAuthority{Name: models.CertificateAuthorityNameEmbedded}).Error; err != nil {
+		return nil, err
+	}
+
+	return []byte(certificateAuthority.Cert), nil
+}
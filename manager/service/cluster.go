@@ -88,6 +88,18 @@ func (s *service) CreateCluster(ctx context.Context, json types.CreateClusterReq
 		return nil, err
 	}
 
+	if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSchedulerCluster, schedulerCluster.ID,
+		schedulerCluster.Config, schedulerCluster.ClientConfig, "create config"); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSeedPeerCluster, seedPeerCluster.ID,
+		seedPeerCluster.Config, nil, "create config"); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	if tx.Commit().Error != nil {
 		return nil, err
 	}
@@ -145,6 +157,11 @@ func (s *service) DestroyCluster(ctx context.Context, id uint) error {
 			tx.Rollback()
 			return err
 		}
+
+		if err := destroyClusterConfigVersions(ctx, tx, models.ClusterConfigVersionTypeSeedPeerCluster, seedPeerCluster.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
 	}
 
 	if err := tx.WithContext(ctx).Model(&schedulerCluster).Association("SeedPeerClusters").Clear(); err != nil {
@@ -157,6 +174,11 @@ func (s *service) DestroyCluster(ctx context.Context, id uint) error {
 		return err
 	}
 
+	if err := destroyClusterConfigVersions(ctx, tx, models.ClusterConfigVersionTypeSchedulerCluster, id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	return tx.Commit().Error
 }
 
@@ -244,6 +266,22 @@ func (s *service) UpdateCluster(ctx context.Context, id uint, json types.UpdateC
 		return nil, err
 	}
 
+	if json.SchedulerClusterConfig != nil || json.PeerClusterConfig != nil {
+		if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSchedulerCluster, schedulerCluster.ID,
+			schedulerCluster.Config, schedulerCluster.ClientConfig, "update config"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if json.SeedPeerClusterConfig != nil {
+		if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSeedPeerCluster, seedPeerCluster.ID,
+			seedPeerCluster.Config, nil, "update config"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	if tx.Commit().Error != nil {
 		return nil, err
 	}
@@ -0,0 +1,305 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
+	pkgredis "d7y.io/dragonfly/v2/pkg/redis"
+)
+
+func (s *service) GetSchedulerClusterConfigVersions(ctx context.Context, id uint, q types.GetClusterConfigVersionsQuery) ([]models.ClusterConfigVersion, int64, error) {
+	return s.getClusterConfigVersions(ctx, models.ClusterConfigVersionTypeSchedulerCluster, id, q)
+}
+
+func (s *service) GetSchedulerClusterConfigVersion(ctx context.Context, id, version uint) (*models.ClusterConfigVersion, error) {
+	return s.getClusterConfigVersion(ctx, models.ClusterConfigVersionTypeSchedulerCluster, id, version)
+}
+
+func (s *service) GetSchedulerClusterConfigVersionDiff(ctx context.Context, id, version uint, q types.GetClusterConfigVersionDiffQuery) (*types.ClusterConfigVersionDiff, error) {
+	return s.getClusterConfigVersionDiff(ctx, models.ClusterConfigVersionTypeSchedulerCluster, id, version, q)
+}
+
+func (s *service) RollbackSchedulerClusterConfig(ctx context.Context, id, version uint) (*models.SchedulerCluster, error) {
+	clusterConfigVersion, err := s.getClusterConfigVersion(ctx, models.ClusterConfigVersionTypeSchedulerCluster, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rollback config with transaction.
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
+	schedulerCluster := models.SchedulerCluster{}
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&schedulerCluster, id).Updates(map[string]any{
+		"config":        clusterConfigVersion.Config,
+		"client_config": clusterConfigVersion.ClientConfig,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	schedulerCluster.Config = clusterConfigVersion.Config
+	schedulerCluster.ClientConfig = clusterConfigVersion.ClientConfig
+
+	if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSchedulerCluster, id,
+		schedulerCluster.Config, schedulerCluster.ClientConfig, fmt.Sprintf("rollback to version %d", version)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Invalidate the cached schedulers, then dynconfig of schedulers
+	// will fetch the rollback config in the next refresh.
+	s.invalidateSchedulerClusterCache(ctx, id)
+	s.webhook.Notify(ctx, webhook.EventSchedulerClusterConfigUpdated, &schedulerCluster)
+	return &schedulerCluster, nil
+}
+
+func (s *service) GetSeedPeerClusterConfigVersions(ctx context.Context, id uint, q types.GetClusterConfigVersionsQuery) ([]models.ClusterConfigVersion, int64, error) {
+	return s.getClusterConfigVersions(ctx, models.ClusterConfigVersionTypeSeedPeerCluster, id, q)
+}
+
+func (s *service) GetSeedPeerClusterConfigVersion(ctx context.Context, id, version uint) (*models.ClusterConfigVersion, error) {
+	return s.getClusterConfigVersion(ctx, models.ClusterConfigVersionTypeSeedPeerCluster, id, version)
+}
+
+func (s *service) GetSeedPeerClusterConfigVersionDiff(ctx context.Context, id, version uint, q types.GetClusterConfigVersionDiffQuery) (*types.ClusterConfigVersionDiff, error) {
+	return s.getClusterConfigVersionDiff(ctx, models.ClusterConfigVersionTypeSeedPeerCluster, id, version, q)
+}
+
+func (s *service) RollbackSeedPeerClusterConfig(ctx context.Context, id, version uint) (*models.SeedPeerCluster, error) {
+	clusterConfigVersion, err := s.getClusterConfigVersion(ctx, models.ClusterConfigVersionTypeSeedPeerCluster, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rollback config with transaction.
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
+	seedPeerCluster := models.SeedPeerCluster{}
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&seedPeerCluster, id).Updates(map[string]any{
+		"config": clusterConfigVersion.Config,
+	}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	seedPeerCluster.Config = clusterConfigVersion.Config
+
+	if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSeedPeerCluster, id,
+		seedPeerCluster.Config, nil, fmt.Sprintf("rollback to version %d", version)); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Invalidate the cached seed peers and the cached schedulers which use
+	// the seed peers, then dynconfig will fetch the rollback config in the next refresh.
+	s.invalidateSeedPeerClusterCache(ctx, id)
+	s.webhook.Notify(ctx, webhook.EventSeedPeerClusterConfigUpdated, &seedPeerCluster)
+	return &seedPeerCluster, nil
+}
+
+func (s *service) getClusterConfigVersions(ctx context.Context, clusterType string, clusterID uint, q types.GetClusterConfigVersionsQuery) ([]models.ClusterConfigVersion, int64, error) {
+	var count int64
+	var clusterConfigVersions []models.ClusterConfigVersion
+	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.ClusterConfigVersion{
+		ClusterType: clusterType,
+		ClusterID:   clusterID,
+	}).Order("version DESC").Find(&clusterConfigVersions).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return clusterConfigVersions, count, nil
+}
+
+func (s *service) getClusterConfigVersion(ctx context.Context, clusterType string, clusterID, version uint) (*models.ClusterConfigVersion, error) {
+	clusterConfigVersion := models.ClusterConfigVersion{}
+	if err := s.db.WithContext(ctx).Where(&models.ClusterConfigVersion{
+		ClusterType: clusterType,
+		ClusterID:   clusterID,
+		Version:     version,
+	}).First(&clusterConfigVersion).Error; err != nil {
+		return nil, err
+	}
+
+	return &clusterConfigVersion, nil
+}
+
+func (s *service) getClusterConfigVersionDiff(ctx context.Context, clusterType string, clusterID, version uint, q types.GetClusterConfigVersionDiffQuery) (*types.ClusterConfigVersionDiff, error) {
+	clusterConfigVersion, err := s.getClusterConfigVersion(ctx, clusterType, clusterID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare with the previous version by default, the first version
+	// is compared with the empty config.
+	baseVersion := q.Base
+	if baseVersion == 0 {
+		baseVersion = version - 1
+	}
+
+	baseClusterConfigVersion := &models.ClusterConfigVersion{}
+	if baseVersion > 0 {
+		if baseClusterConfigVersion, err = s.getClusterConfigVersion(ctx, clusterType, clusterID, baseVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	return &types.ClusterConfigVersionDiff{
+		BaseVersion: baseVersion,
+		Version:     version,
+		Changes:     diffClusterConfigVersions(baseClusterConfigVersion, clusterConfigVersion),
+	}, nil
+}
+
+// invalidateSchedulerClusterCache deletes the cached schedulers of the scheduler cluster.
+func (s *service) invalidateSchedulerClusterCache(ctx context.Context, id uint) {
+	var schedulers []models.Scheduler
+	if err := s.db.WithContext(ctx).Where(&models.Scheduler{SchedulerClusterID: id}).Find(&schedulers).Error; err != nil {
+		logger.Warnf("find schedulers of scheduler cluster %d failed: %s", id, err.Error())
+		return
+	}
+
+	for _, scheduler := range schedulers {
+		if err := s.cache.Delete(ctx, pkgredis.MakeSchedulerKeyInManager(scheduler.SchedulerClusterID, scheduler.Hostname, scheduler.IP)); err != nil {
+			logger.Warnf("delete scheduler %d cache failed: %s", scheduler.ID, err.Error())
+		}
+	}
+}
+
+// invalidateSeedPeerClusterCache deletes the cached seed peers of the seed peer cluster,
+// and the cached schedulers of the scheduler clusters which use the seed peer cluster.
+func (s *service) invalidateSeedPeerClusterCache(ctx context.Context, id uint) {
+	seedPeerCluster := models.SeedPeerCluster{}
+	if err := s.db.WithContext(ctx).Preload("SeedPeers").Preload("SchedulerClusters").First(&seedPeerCluster, id).Error; err != nil {
+		logger.Warnf("find seed peer cluster %d failed: %s", id, err.Error())
+		return
+	}
+
+	for _, seedPeer := range seedPeerCluster.SeedPeers {
+		if err := s.cache.Delete(ctx, pkgredis.MakeSeedPeerKeyInManager(seedPeer.SeedPeerClusterID, seedPeer.Hostname, seedPeer.IP)); err != nil {
+			logger.Warnf("delete seed peer %d cache failed: %s", seedPeer.ID, err.Error())
+		}
+	}
+
+	for _, schedulerCluster := range seedPeerCluster.SchedulerClusters {
+		s.invalidateSchedulerClusterCache(ctx, schedulerCluster.ID)
+	}
+}
+
+// createClusterConfigVersion records the config of the cluster as the next version.
+func createClusterConfigVersion(ctx context.Context, db *gorm.DB, clusterType string, clusterID uint, config, clientConfig models.JSONMap, bio string) error {
+	var latest models.ClusterConfigVersion
+	if err := db.WithContext(ctx).Where(&models.ClusterConfigVersion{
+		ClusterType: clusterType,
+		ClusterID:   clusterID,
+	}).Order("version DESC").First(&latest).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return db.WithContext(ctx).Create(&models.ClusterConfigVersion{
+		ClusterType:  clusterType,
+		ClusterID:    clusterID,
+		Version:      latest.Version + 1,
+		Config:       config,
+		ClientConfig: clientConfig,
+		BIO:          bio,
+	}).Error
+}
+
+// destroyClusterConfigVersions deletes all config versions of the cluster.
+func destroyClusterConfigVersions(ctx context.Context, db *gorm.DB, clusterType string, clusterID uint) error {
+	return db.WithContext(ctx).Unscoped().Where(&models.ClusterConfigVersion{
+		ClusterType: clusterType,
+		ClusterID:   clusterID,
+	}).Delete(&models.ClusterConfigVersion{}).Error
+}
+
+// diffClusterConfigVersions returns the changed fields between the config versions.
+func diffClusterConfigVersions(base, target *models.ClusterConfigVersion) map[string]types.ClusterConfigChange {
+	before := map[string]any{}
+	flattenClusterConfig("config", map[string]any(base.Config), before)
+	flattenClusterConfig("client_config", map[string]any(base.ClientConfig), before)
+
+	after := map[string]any{}
+	flattenClusterConfig("config", map[string]any(target.Config), after)
+	flattenClusterConfig("client_config", map[string]any(target.ClientConfig), after)
+
+	changes := map[string]types.ClusterConfigChange{}
+	for key, value := range before {
+		if afterValue, ok := after[key]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changes[key] = types.ClusterConfigChange{Before: value, After: afterValue}
+		}
+	}
+
+	for key, value := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = types.ClusterConfigChange{After: value}
+		}
+	}
+
+	return changes
+}
+
+// flattenClusterConfig flattens the nested config into fields, key is the field path joined by dot.
+func flattenClusterConfig(prefix string, value any, fields map[string]any) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		if value != nil {
+			fields[prefix] = value
+		}
+
+		return
+	}
+
+	for k, v := range m {
+		flattenClusterConfig(prefix+"."+k, v, fields)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+)
+
+const (
+	// defaultFeatureFlagPercentage is the default percentage of rolled out hosts.
+	defaultFeatureFlagPercentage = 100
+)
+
+func (s *service) CreateFeatureFlag(ctx context.Context, json types.CreateFeatureFlagRequest) (*models.FeatureFlag, error) {
+	percentage := uint(defaultFeatureFlagPercentage)
+	if json.Percentage != nil {
+		percentage = *json.Percentage
+	}
+
+	schedulerCluster := models.SchedulerCluster{}
+	if err := s.db.WithContext(ctx).First(&schedulerCluster, json.SchedulerClusterID).Error; err != nil {
+		return nil, err
+	}
+
+	featureFlag := models.FeatureFlag{
+		Name:               json.Name,
+		BIO:                json.BIO,
+		State:              models.FeatureFlagStateActive,
+		Percentage:         percentage,
+		SchedulerClusterID: json.SchedulerClusterID,
+		UserID:             json.UserID,
+	}
+
+	if err := s.db.WithContext(ctx).Create(&featureFlag).Error; err != nil {
+		return nil, err
+	}
+
+	s.invalidateSchedulerClusterCache(ctx, featureFlag.SchedulerClusterID)
+	return &featureFlag, nil
+}
+
+func (s *service) DestroyFeatureFlag(ctx context.Context, id uint) error {
+	featureFlag := models.FeatureFlag{}
+	if err := s.db.WithContext(ctx).First(&featureFlag, id).Error; err != nil {
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Unscoped().Delete(&models.FeatureFlag{}, id).Error; err != nil {
+		return err
+	}
+
+	s.invalidateSchedulerClusterCache(ctx, featureFlag.SchedulerClusterID)
+	return nil
+}
+
+func (s *service) UpdateFeatureFlag(ctx context.Context, id uint, json types.UpdateFeatureFlagRequest) (*models.FeatureFlag, error) {
+	featureFlag := models.FeatureFlag{}
+	if err := s.db.WithContext(ctx).First(&featureFlag, id).Updates(models.FeatureFlag{
+		BIO:    json.BIO,
+		State:  json.State,
+		UserID: json.UserID,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// Updates does not accept uint as zero, percentage of zero stops the rollout.
+	if json.Percentage != nil {
+		if err := s.db.WithContext(ctx).Model(&featureFlag).Update("percentage", *json.Percentage).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	s.invalidateSchedulerClusterCache(ctx, featureFlag.SchedulerClusterID)
+	return &featureFlag, nil
+}
+
+func (s *service) GetFeatureFlag(ctx context.Context, id uint) (*models.FeatureFlag, error) {
+	featureFlag := models.FeatureFlag{}
+	if err := s.db.WithContext(ctx).Preload("SchedulerCluster").Preload("User").First(&featureFlag, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &featureFlag, nil
+}
+
+func (s *service) GetFeatureFlags(ctx context.Context, q types.GetFeatureFlagsQuery) ([]models.FeatureFlag, int64, error) {
+	var count int64
+	var featureFlags []models.FeatureFlag
+	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.FeatureFlag{
+		Name:               q.Name,
+		State:              q.State,
+		SchedulerClusterID: q.SchedulerClusterID,
+	}).Preload("SchedulerCluster").Preload("User").Find(&featureFlags).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return featureFlags, count, nil
+}
@@ -20,25 +20,56 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/metrics"
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
 	"d7y.io/dragonfly/v2/pkg/retry"
 	"d7y.io/dragonfly/v2/pkg/slices"
 	"d7y.io/dragonfly/v2/pkg/structure"
+	pkgtypes "d7y.io/dragonfly/v2/pkg/types"
 )
 
 func (s *service) CreatePreheatJob(ctx context.Context, json types.CreatePreheatJobRequest) (*models.Job, error) {
-	candidateSchedulers, err := s.findCandidateSchedulers(ctx, json.SchedulerClusterIDs)
+	tenantID, schedulerClusterIDs, err := s.findTenantSchedulerClusters(ctx, json.TenantID, json.SchedulerClusterIDs)
 	if err != nil {
 		return nil, err
 	}
 
+	candidateSchedulers, err := s.findCandidateSchedulers(ctx, schedulerClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the quotas of tenant and create job with transaction, the tenant is locked
+	// until the job is created, so the concurrent preheats do not exceed the quota.
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
+	if tenantID != 0 {
+		if err := checkTenantPreheatQuota(ctx, tx, tenantID, s.config.Job.Preheat.TaskTTL); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	groupJobState, err := s.job.CreatePreheat(ctx, candidateSchedulers, json.Args)
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
@@ -49,6 +80,7 @@ func (s *service) CreatePreheatJob(ctx context.Context, json types.CreatePreheat
 
 	args, err := structure.StructToMap(json.Args)
 	if err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
@@ -59,9 +91,155 @@ func (s *service) CreatePreheatJob(ctx context.Context, json types.CreatePreheat
 		State:             groupJobState.State,
 		Args:              args,
 		UserID:            json.UserID,
+		TenantID:          tenantID,
 		SchedulerClusters: candidateSchedulerClusters,
 	}
 
+	if err := tx.WithContext(ctx).Create(&job).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	go s.pollingJob(context.Background(), job.ID, job.TaskID)
+
+	return &job, nil
+}
+
+func (s *service) CreateDrainHostJob(ctx context.Context, json types.CreateDrainHostJobRequest) (*models.Job, error) {
+	activeSchedulers, err := s.findActiveSchedulers(ctx, json.SchedulerClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupJobState, err := s.job.CreateDrainHost(ctx, activeSchedulers, json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := structure.StructToMap(json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.Job{
+		TaskID:            groupJobState.GroupUUID,
+		BIO:               json.BIO,
+		Type:              json.Type,
+		State:             groupJobState.State,
+		Args:              args,
+		UserID:            json.UserID,
+		SchedulerClusters: schedulerClustersOf(activeSchedulers),
+	}
+
+	if err := s.db.WithContext(ctx).Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	go s.pollingJob(context.Background(), job.ID, job.TaskID)
+
+	return &job, nil
+}
+
+func (s *service) CreateReannounceHostJob(ctx context.Context, json types.CreateReannounceHostJobRequest) (*models.Job, error) {
+	activeSchedulers, err := s.findActiveSchedulers(ctx, json.SchedulerClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupJobState, err := s.job.CreateReannounceHost(ctx, activeSchedulers, json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := structure.StructToMap(json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.Job{
+		TaskID:            groupJobState.GroupUUID,
+		BIO:               json.BIO,
+		Type:              json.Type,
+		State:             groupJobState.State,
+		Args:              args,
+		UserID:            json.UserID,
+		SchedulerClusters: schedulerClustersOf(activeSchedulers),
+	}
+
+	if err := s.db.WithContext(ctx).Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	go s.pollingJob(context.Background(), job.ID, job.TaskID)
+
+	return &job, nil
+}
+
+func (s *service) CreateHostReputationJob(ctx context.Context, json types.CreateHostReputationJobRequest) (*models.Job, error) {
+	activeSchedulers, err := s.findActiveSchedulers(ctx, json.SchedulerClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupJobState, err := s.job.CreateHostReputation(ctx, activeSchedulers, json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := structure.StructToMap(json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.Job{
+		TaskID:            groupJobState.GroupUUID,
+		BIO:               json.BIO,
+		Type:              json.Type,
+		State:             groupJobState.State,
+		Args:              args,
+		UserID:            json.UserID,
+		SchedulerClusters: schedulerClustersOf(activeSchedulers),
+	}
+
+	if err := s.db.WithContext(ctx).Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	go s.pollingJob(context.Background(), job.ID, job.TaskID)
+
+	return &job, nil
+}
+
+func (s *service) CreateDeleteTaskJob(ctx context.Context, json types.CreateDeleteTaskJobRequest) (*models.Job, error) {
+	activeSchedulers, err := s.findActiveSchedulers(ctx, json.SchedulerClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupJobState, err := s.job.CreateDeleteTask(ctx, activeSchedulers, json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := structure.StructToMap(json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.Job{
+		TaskID:            groupJobState.GroupUUID,
+		BIO:               json.BIO,
+		Type:              json.Type,
+		State:             groupJobState.State,
+		Args:              args,
+		UserID:            json.UserID,
+		SchedulerClusters: schedulerClustersOf(activeSchedulers),
+	}
+
 	if err := s.db.WithContext(ctx).Create(&job).Error; err != nil {
 		return nil, err
 	}
@@ -71,6 +249,79 @@ func (s *service) CreatePreheatJob(ctx context.Context, json types.CreatePreheat
 	return &job, nil
 }
 
+func (s *service) CreateGetTaskJob(ctx context.Context, json types.CreateGetTaskJobRequest) (*models.Job, error) {
+	activeSchedulers, err := s.findActiveSchedulers(ctx, json.SchedulerClusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	groupJobState, err := s.job.CreateGetTask(ctx, activeSchedulers, json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := structure.StructToMap(json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.Job{
+		TaskID:            groupJobState.GroupUUID,
+		BIO:               json.BIO,
+		Type:              json.Type,
+		State:             groupJobState.State,
+		Args:              args,
+		UserID:            json.UserID,
+		SchedulerClusters: schedulerClustersOf(activeSchedulers),
+	}
+
+	if err := s.db.WithContext(ctx).Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	go s.pollingJob(context.Background(), job.ID, job.TaskID)
+
+	return &job, nil
+}
+
+// schedulerClustersOf returns the distinct scheduler clusters of the schedulers.
+func schedulerClustersOf(schedulers []models.Scheduler) []models.SchedulerCluster {
+	var (
+		schedulerClusters   []models.SchedulerCluster
+		schedulerClusterIDs = make(map[uint]struct{})
+	)
+	for _, scheduler := range schedulers {
+		if _, ok := schedulerClusterIDs[scheduler.SchedulerClusterID]; ok {
+			continue
+		}
+
+		schedulerClusterIDs[scheduler.SchedulerClusterID] = struct{}{}
+		schedulerClusters = append(schedulerClusters, scheduler.SchedulerCluster)
+	}
+
+	return schedulerClusters
+}
+
+// findActiveSchedulers finds all of the active schedulers in the scheduler clusters,
+// if the scheduler clusters are not specified, all of the scheduler clusters are used.
+func (s *service) findActiveSchedulers(ctx context.Context, schedulerClusterIDs []uint) ([]models.Scheduler, error) {
+	db := s.db.WithContext(ctx).Preload("SchedulerCluster").Where(&models.Scheduler{State: models.SchedulerStateActive})
+	if len(schedulerClusterIDs) != 0 {
+		db = db.Where("scheduler_cluster_id IN ?", schedulerClusterIDs)
+	}
+
+	var activeSchedulers []models.Scheduler
+	if err := db.Find(&activeSchedulers).Error; err != nil {
+		return nil, err
+	}
+
+	if len(activeSchedulers) == 0 {
+		return nil, errors.New("active schedulers not found")
+	}
+
+	return activeSchedulers, nil
+}
+
 func (s *service) findCandidateSchedulers(ctx context.Context, schedulerClusterIDs []uint) ([]models.Scheduler, error) {
 	var candidateSchedulers []models.Scheduler
 	if len(schedulerClusterIDs) != 0 {
@@ -159,9 +410,17 @@ func (s *service) pollingJob(ctx context.Context, id uint, groupID string) {
 		switch job.State {
 		case machineryv1tasks.StateSuccess:
 			log.Info("polling group succeeded")
+			if err := s.updateTenantTasks(ctx, &job, groupJob); err != nil {
+				log.Errorf("update tenant tasks failed: %s", err.Error())
+			}
+
+			collectJobMetrics(&job)
+			s.notifyJobCompleted(ctx, &job)
 			return nil, true, nil
 		case machineryv1tasks.StateFailure:
 			log.Error("polling group failed")
+			collectJobMetrics(&job)
+			s.notifyJobCompleted(ctx, &job)
 			return nil, true, nil
 		default:
 			msg := fmt.Sprintf("polling job state is %s", job.State)
@@ -181,9 +440,31 @@ func (s *service) pollingJob(ctx context.Context, id uint, groupID string) {
 			log.Errorf("polling group failed: %s", err.Error())
 		}
 		log.Error("polling group timeout")
+		collectJobMetrics(&job)
+		s.notifyJobCompleted(ctx, &job)
 	}
 }
 
+// collectJobMetrics collects the metrics of the completed job.
+func collectJobMetrics(job *models.Job) {
+	metrics.JobCount.WithLabelValues(job.Type, job.State).Inc()
+	metrics.JobDuration.WithLabelValues(job.Type, job.State).Observe(float64(time.Since(job.CreatedAt).Milliseconds()))
+}
+
+// notifyJobCompleted notifies the webhooks that the preheat job is completed.
+func (s *service) notifyJobCompleted(ctx context.Context, job *models.Job) {
+	if job.Type != internaljob.PreheatJob {
+		return
+	}
+
+	event := webhook.EventPreheatSucceeded
+	if job.State != machineryv1tasks.StateSuccess {
+		event = webhook.EventPreheatFailed
+	}
+
+	s.webhook.Notify(ctx, event, job)
+}
+
 func (s *service) DestroyJob(ctx context.Context, id uint) error {
 	job := models.Job{}
 	if err := s.db.WithContext(ctx).First(&job, id).Error; err != nil {
@@ -218,13 +499,196 @@ func (s *service) GetJob(ctx context.Context, id uint) (*models.Job, error) {
 	return &job, nil
 }
 
+func (s *service) GetPreheatJobProgress(ctx context.Context, id uint) (*types.PreheatJobProgress, error) {
+	job := models.Job{}
+	if err := s.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+
+	if job.Type != internaljob.PreheatJob {
+		return nil, fmt.Errorf("job %d is not a preheat job", id)
+	}
+
+	// Read the live group state from the job backend, fallback to the result
+	// persisted by polling when the backend results are expired.
+	groupJob, err := s.job.GetGroupJobState(job.TaskID)
+	if err != nil {
+		logger.Warnf("get group %s state failed: %s", job.TaskID, err.Error())
+		groupJob = &internaljob.GroupJobState{}
+		if err := structure.MapToStruct(job.Result, groupJob); err != nil {
+			return nil, err
+		}
+		groupJob.State = job.State
+	}
+
+	progress := &types.PreheatJobProgress{
+		ID:         job.ID,
+		State:      groupJob.State,
+		TotalCount: len(groupJob.JobStates),
+		Layers:     make([]types.PreheatLayerProgress, 0, len(groupJob.JobStates)),
+	}
+	for _, taskState := range groupJob.JobStates {
+		layer := types.PreheatLayerProgress{
+			TaskUUID: taskState.TaskUUID,
+			State:    taskState.State,
+			Error:    taskState.Error,
+		}
+
+		switch {
+		case taskState.IsSuccess():
+			progress.SucceededCount++
+			resp, err := unmarshalPreheatResponse(taskState)
+			if err != nil {
+				logger.Warnf("unmarshal task %s response failed: %s", taskState.TaskUUID, err.Error())
+				break
+			}
+
+			layer.URL = resp.URL
+			layer.TaskID = resp.TaskID
+			layer.SeedPeerHostID = resp.SeedPeerHostID
+			layer.SeedPeerID = resp.SeedPeerID
+			layer.ContentLength = resp.ContentLength
+			layer.TotalPieceCount = resp.TotalPieceCount
+			layer.FinishedPieceCount = resp.FinishedPieceCount
+		case taskState.IsFailure():
+			progress.FailedCount++
+		default:
+			progress.PendingCount++
+		}
+
+		progress.Layers = append(progress.Layers, layer)
+	}
+
+	return progress, nil
+}
+
+// unmarshalPreheatResponse unmarshals the response of the succeeded preheat task.
+func unmarshalPreheatResponse(taskState *machineryv1tasks.TaskState) (*internaljob.PreheatResponse, error) {
+	results, err := machineryv1tasks.ReflectTaskResults(taskState.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp internaljob.PreheatResponse
+	if err := internaljob.UnmarshalResponse(results, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (s *service) GetTaskJobResult(ctx context.Context, id uint) (*types.GetTaskJobResult, error) {
+	job := models.Job{}
+	if err := s.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+
+	if job.Type != internaljob.GetTaskJob {
+		return nil, fmt.Errorf("job %d is not a get task job", id)
+	}
+
+	groupJob, err := s.job.GetGroupJobState(job.TaskID)
+	if err != nil {
+		logger.Warnf("get group %s state failed: %s", job.TaskID, err.Error())
+		groupJob = &internaljob.GroupJobState{}
+		if err := structure.MapToStruct(job.Result, groupJob); err != nil {
+			return nil, err
+		}
+		groupJob.State = job.State
+	}
+
+	result := &types.GetTaskJobResult{
+		ID:        job.ID,
+		State:     groupJob.State,
+		SeedPeers: []types.TaskPeer{},
+		Peers:     []types.TaskPeer{},
+	}
+	if taskID, ok := job.Args["task_id"].(string); ok {
+		result.TaskID = taskID
+	}
+
+	for _, taskState := range groupJob.JobStates {
+		if !taskState.IsSuccess() {
+			continue
+		}
+
+		resp, err := unmarshalGetTaskResponse(taskState)
+		if err != nil {
+			logger.Warnf("unmarshal task %s response failed: %s", taskState.TaskUUID, err.Error())
+			continue
+		}
+
+		result.TaskID = resp.TaskID
+		if resp.URL != "" {
+			result.URL = resp.URL
+			result.ContentLength = resp.ContentLength
+			result.TotalPieceCount = resp.TotalPieceCount
+		}
+
+		if resp.Replication != nil {
+			result.Replication = resp.Replication
+		}
+
+		for domain, count := range resp.Placement {
+			if result.Placement == nil {
+				result.Placement = make(map[string]int)
+			}
+
+			result.Placement[domain] += count
+		}
+
+		for _, peer := range resp.Peers {
+			taskPeer := types.TaskPeer{
+				ID:                 peer.ID,
+				State:              peer.State,
+				FinishedPieceCount: peer.FinishedPieceCount,
+				HostID:             peer.HostID,
+				HostType:           peer.HostType,
+				Hostname:           peer.Hostname,
+				IP:                 peer.IP,
+				Port:               peer.Port,
+				SchedulerHostname:  resp.SchedulerHostname,
+				SchedulerClusterID: resp.SchedulerClusterID,
+				Topology:           peer.Topology,
+				CreatedAt:          peer.CreatedAt,
+				UpdatedAt:          peer.UpdatedAt,
+			}
+
+			if peer.HostType == pkgtypes.HostTypeNormalName {
+				result.Peers = append(result.Peers, taskPeer)
+				continue
+			}
+
+			result.SeedPeers = append(result.SeedPeers, taskPeer)
+		}
+	}
+
+	return result, nil
+}
+
+// unmarshalGetTaskResponse unmarshals the response of the succeeded get task job.
+func unmarshalGetTaskResponse(taskState *machineryv1tasks.TaskState) (*internaljob.GetTaskResponse, error) {
+	results, err := machineryv1tasks.ReflectTaskResults(taskState.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp internaljob.GetTaskResponse
+	if err := internaljob.UnmarshalResponse(results, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
 func (s *service) GetJobs(ctx context.Context, q types.GetJobsQuery) ([]models.Job, int64, error) {
 	var count int64
 	var jobs []models.Job
 	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.Job{
-		Type:   q.Type,
-		State:  q.State,
-		UserID: q.UserID,
+		Type:     q.Type,
+		State:    q.State,
+		UserID:   q.UserID,
+		TenantID: q.TenantID,
 	}).Find(&jobs).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
@@ -111,6 +111,21 @@ func (mr *MockServiceMockRecorder) AddSeedPeerToSeedPeerCluster(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSeedPeerToSeedPeerCluster", reflect.TypeOf((*MockService)(nil).AddSeedPeerToSeedPeerCluster), arg0, arg1, arg2)
 }
 
+// BatchPeers mocks base method.
+func (m *MockService) BatchPeers(arg0 context.Context, arg1 types.BatchPeersRequest) (*types.BatchPeersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchPeers", arg0, arg1)
+	ret0, _ := ret[0].(*types.BatchPeersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchPeers indicates an expected call of BatchPeers.
+func (mr *MockServiceMockRecorder) BatchPeers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchPeers", reflect.TypeOf((*MockService)(nil).BatchPeers), arg0, arg1)
+}
+
 // CreateApplication mocks base method.
 func (m *MockService) CreateApplication(arg0 context.Context, arg1 types.CreateApplicationRequest) (*models.Application, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +185,81 @@ func (mr *MockServiceMockRecorder) CreateConfig(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfig", reflect.TypeOf((*MockService)(nil).CreateConfig), arg0, arg1)
 }
 
+// CreateDeleteTaskJob mocks base method.
+func (m *MockService) CreateDeleteTaskJob(arg0 context.Context, arg1 types.CreateDeleteTaskJobRequest) (*models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeleteTaskJob", arg0, arg1)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDeleteTaskJob indicates an expected call of CreateDeleteTaskJob.
+func (mr *MockServiceMockRecorder) CreateDeleteTaskJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeleteTaskJob", reflect.TypeOf((*MockService)(nil).CreateDeleteTaskJob), arg0, arg1)
+}
+
+// CreateDrainHostJob mocks base method.
+func (m *MockService) CreateDrainHostJob(arg0 context.Context, arg1 types.CreateDrainHostJobRequest) (*models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDrainHostJob", arg0, arg1)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDrainHostJob indicates an expected call of CreateDrainHostJob.
+func (mr *MockServiceMockRecorder) CreateDrainHostJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDrainHostJob", reflect.TypeOf((*MockService)(nil).CreateDrainHostJob), arg0, arg1)
+}
+
+// CreateFeatureFlag mocks base method.
+func (m *MockService) CreateFeatureFlag(arg0 context.Context, arg1 types.CreateFeatureFlagRequest) (*models.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeatureFlag", arg0, arg1)
+	ret0, _ := ret[0].(*models.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeatureFlag indicates an expected call of CreateFeatureFlag.
+func (mr *MockServiceMockRecorder) CreateFeatureFlag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeatureFlag", reflect.TypeOf((*MockService)(nil).CreateFeatureFlag), arg0, arg1)
+}
+
+// CreateGetTaskJob mocks base method.
+func (m *MockService) CreateGetTaskJob(arg0 context.Context, arg1 types.CreateGetTaskJobRequest) (*models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGetTaskJob", arg0, arg1)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGetTaskJob indicates an expected call of CreateGetTaskJob.
+func (mr *MockServiceMockRecorder) CreateGetTaskJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGetTaskJob", reflect.TypeOf((*MockService)(nil).CreateGetTaskJob), arg0, arg1)
+}
+
+// CreateHostReputationJob mocks base method.
+func (m *MockService) CreateHostReputationJob(arg0 context.Context, arg1 types.CreateHostReputationJobRequest) (*models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHostReputationJob", arg0, arg1)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHostReputationJob indicates an expected call of CreateHostReputationJob.
+func (mr *MockServiceMockRecorder) CreateHostReputationJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHostReputationJob", reflect.TypeOf((*MockService)(nil).CreateHostReputationJob), arg0, arg1)
+}
+
 // CreateOauth mocks base method.
 func (m *MockService) CreateOauth(arg0 context.Context, arg1 types.CreateOauthRequest) (*models.Oauth, error) {
 	m.ctrl.T.Helper()
@@ -185,6 +275,21 @@ func (mr *MockServiceMockRecorder) CreateOauth(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOauth", reflect.TypeOf((*MockService)(nil).CreateOauth), arg0, arg1)
 }
 
+// CreatePeer mocks base method.
+func (m *MockService) CreatePeer(arg0 context.Context, arg1 types.CreatePeerRequest) (*models.Peer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePeer", arg0, arg1)
+	ret0, _ := ret[0].(*models.Peer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePeer indicates an expected call of CreatePeer.
+func (mr *MockServiceMockRecorder) CreatePeer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePeer", reflect.TypeOf((*MockService)(nil).CreatePeer), arg0, arg1)
+}
+
 // CreatePersonalAccessToken mocks base method.
 func (m *MockService) CreatePersonalAccessToken(arg0 context.Context, arg1 types.CreatePersonalAccessTokenRequest) (*models.PersonalAccessToken, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +320,36 @@ func (mr *MockServiceMockRecorder) CreatePreheatJob(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePreheatJob", reflect.TypeOf((*MockService)(nil).CreatePreheatJob), arg0, arg1)
 }
 
+// CreatePreheatSchedule mocks base method.
+func (m *MockService) CreatePreheatSchedule(arg0 context.Context, arg1 types.CreatePreheatScheduleRequest) (*models.PreheatSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePreheatSchedule", arg0, arg1)
+	ret0, _ := ret[0].(*models.PreheatSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePreheatSchedule indicates an expected call of CreatePreheatSchedule.
+func (mr *MockServiceMockRecorder) CreatePreheatSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePreheatSchedule", reflect.TypeOf((*MockService)(nil).CreatePreheatSchedule), arg0, arg1)
+}
+
+// CreateReannounceHostJob mocks base method.
+func (m *MockService) CreateReannounceHostJob(arg0 context.Context, arg1 types.CreateReannounceHostJobRequest) (*models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReannounceHostJob", arg0, arg1)
+	ret0, _ := ret[0].(*models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReannounceHostJob indicates an expected call of CreateReannounceHostJob.
+func (mr *MockServiceMockRecorder) CreateReannounceHostJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReannounceHostJob", reflect.TypeOf((*MockService)(nil).CreateReannounceHostJob), arg0, arg1)
+}
+
 // CreateRole mocks base method.
 func (m *MockService) CreateRole(arg0 context.Context, arg1 types.CreateRoleRequest) error {
 	m.ctrl.T.Helper()
@@ -289,6 +424,21 @@ func (mr *MockServiceMockRecorder) CreateSeedPeerCluster(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeedPeerCluster", reflect.TypeOf((*MockService)(nil).CreateSeedPeerCluster), arg0, arg1)
 }
 
+// CreateTenant mocks base method.
+func (m *MockService) CreateTenant(arg0 context.Context, arg1 types.CreateTenantRequest) (*models.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", arg0, arg1)
+	ret0, _ := ret[0].(*models.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockServiceMockRecorder) CreateTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockService)(nil).CreateTenant), arg0, arg1)
+}
+
 // CreateV1Preheat mocks base method.
 func (m *MockService) CreateV1Preheat(arg0 context.Context, arg1 types.CreateV1PreheatRequest) (*types.CreateV1PreheatResponse, error) {
 	m.ctrl.T.Helper()
@@ -304,6 +454,21 @@ func (mr *MockServiceMockRecorder) CreateV1Preheat(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateV1Preheat", reflect.TypeOf((*MockService)(nil).CreateV1Preheat), arg0, arg1)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockService) CreateWebhook(arg0 context.Context, arg1 types.CreateWebhookRequest) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockServiceMockRecorder) CreateWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockService)(nil).CreateWebhook), arg0, arg1)
+}
+
 // DeletePermissionForRole mocks base method.
 func (m *MockService) DeletePermissionForRole(arg0 context.Context, arg1 string, arg2 types.DeletePermissionForRoleRequest) (bool, error) {
 	m.ctrl.T.Helper()
@@ -390,6 +555,20 @@ func (mr *MockServiceMockRecorder) DestroyConfig(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyConfig", reflect.TypeOf((*MockService)(nil).DestroyConfig), arg0, arg1)
 }
 
+// DestroyFeatureFlag mocks base method.
+func (m *MockService) DestroyFeatureFlag(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DestroyFeatureFlag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestroyFeatureFlag indicates an expected call of DestroyFeatureFlag.
+func (mr *MockServiceMockRecorder) DestroyFeatureFlag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyFeatureFlag", reflect.TypeOf((*MockService)(nil).DestroyFeatureFlag), arg0, arg1)
+}
+
 // DestroyJob mocks base method.
 func (m *MockService) DestroyJob(arg0 context.Context, arg1 uint) error {
 	m.ctrl.T.Helper()
@@ -460,6 +639,20 @@ func (mr *MockServiceMockRecorder) DestroyPersonalAccessToken(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyPersonalAccessToken", reflect.TypeOf((*MockService)(nil).DestroyPersonalAccessToken), arg0, arg1)
 }
 
+// DestroyPreheatSchedule mocks base method.
+func (m *MockService) DestroyPreheatSchedule(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DestroyPreheatSchedule", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestroyPreheatSchedule indicates an expected call of DestroyPreheatSchedule.
+func (mr *MockServiceMockRecorder) DestroyPreheatSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyPreheatSchedule", reflect.TypeOf((*MockService)(nil).DestroyPreheatSchedule), arg0, arg1)
+}
+
 // DestroyRole mocks base method.
 func (m *MockService) DestroyRole(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -531,6 +724,49 @@ func (mr *MockServiceMockRecorder) DestroySeedPeerCluster(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroySeedPeerCluster", reflect.TypeOf((*MockService)(nil).DestroySeedPeerCluster), arg0, arg1)
 }
 
+// DestroyTenant mocks base method.
+func (m *MockService) DestroyTenant(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DestroyTenant", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestroyTenant indicates an expected call of DestroyTenant.
+func (mr *MockServiceMockRecorder) DestroyTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyTenant", reflect.TypeOf((*MockService)(nil).DestroyTenant), arg0, arg1)
+}
+
+// DestroyWebhook mocks base method.
+func (m *MockService) DestroyWebhook(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DestroyWebhook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DestroyWebhook indicates an expected call of DestroyWebhook.
+func (mr *MockServiceMockRecorder) DestroyWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DestroyWebhook", reflect.TypeOf((*MockService)(nil).DestroyWebhook), arg0, arg1)
+}
+
+// ExportAuditLogs mocks base method.
+func (m *MockService) ExportAuditLogs(arg0 context.Context, arg1 types.ExportAuditLogsQuery) ([]models.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].([]models.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportAuditLogs indicates an expected call of ExportAuditLogs.
+func (mr *MockServiceMockRecorder) ExportAuditLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAuditLogs", reflect.TypeOf((*MockService)(nil).ExportAuditLogs), arg0, arg1)
+}
+
 // GetApplication mocks base method.
 func (m *MockService) GetApplication(arg0 context.Context, arg1 uint) (*models.Application, error) {
 	m.ctrl.T.Helper()
@@ -562,6 +798,37 @@ func (mr *MockServiceMockRecorder) GetApplications(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplications", reflect.TypeOf((*MockService)(nil).GetApplications), arg0, arg1)
 }
 
+// GetAuditLog mocks base method.
+func (m *MockService) GetAuditLog(arg0 context.Context, arg1 uint) (*models.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", arg0, arg1)
+	ret0, _ := ret[0].(*models.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockServiceMockRecorder) GetAuditLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockService)(nil).GetAuditLog), arg0, arg1)
+}
+
+// GetAuditLogs mocks base method.
+func (m *MockService) GetAuditLogs(arg0 context.Context, arg1 types.GetAuditLogsQuery) ([]models.AuditLog, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].([]models.AuditLog)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuditLogs indicates an expected call of GetAuditLogs.
+func (mr *MockServiceMockRecorder) GetAuditLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogs", reflect.TypeOf((*MockService)(nil).GetAuditLogs), arg0, arg1)
+}
+
 // GetBucket mocks base method.
 func (m *MockService) GetBucket(arg0 context.Context, arg1 string) (*objectstorage.BucketMetadata, error) {
 	m.ctrl.T.Helper()
@@ -592,6 +859,21 @@ func (mr *MockServiceMockRecorder) GetBuckets(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBuckets", reflect.TypeOf((*MockService)(nil).GetBuckets), arg0)
 }
 
+// GetCACertificate mocks base method.
+func (m *MockService) GetCACertificate(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCACertificate", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCACertificate indicates an expected call of GetCACertificate.
+func (mr *MockServiceMockRecorder) GetCACertificate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCACertificate", reflect.TypeOf((*MockService)(nil).GetCACertificate), arg0)
+}
+
 // GetCluster mocks base method.
 func (m *MockService) GetCluster(arg0 context.Context, arg1 uint) (*types.GetClusterResponse, error) {
 	m.ctrl.T.Helper()
@@ -654,6 +936,37 @@ func (mr *MockServiceMockRecorder) GetConfigs(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigs", reflect.TypeOf((*MockService)(nil).GetConfigs), arg0, arg1)
 }
 
+// GetFeatureFlag mocks base method.
+func (m *MockService) GetFeatureFlag(arg0 context.Context, arg1 uint) (*models.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureFlag", arg0, arg1)
+	ret0, _ := ret[0].(*models.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlag indicates an expected call of GetFeatureFlag.
+func (mr *MockServiceMockRecorder) GetFeatureFlag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlag", reflect.TypeOf((*MockService)(nil).GetFeatureFlag), arg0, arg1)
+}
+
+// GetFeatureFlags mocks base method.
+func (m *MockService) GetFeatureFlags(arg0 context.Context, arg1 types.GetFeatureFlagsQuery) ([]models.FeatureFlag, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureFlags", arg0, arg1)
+	ret0, _ := ret[0].([]models.FeatureFlag)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFeatureFlags indicates an expected call of GetFeatureFlags.
+func (mr *MockServiceMockRecorder) GetFeatureFlags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlags", reflect.TypeOf((*MockService)(nil).GetFeatureFlags), arg0, arg1)
+}
+
 // GetJob mocks base method.
 func (m *MockService) GetJob(arg0 context.Context, arg1 uint) (*models.Job, error) {
 	m.ctrl.T.Helper()
@@ -823,6 +1136,68 @@ func (mr *MockServiceMockRecorder) GetPersonalAccessTokens(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokens", reflect.TypeOf((*MockService)(nil).GetPersonalAccessTokens), arg0, arg1)
 }
 
+// GetPreheatJobProgress mocks base method.
+func (m *MockService) GetPreheatJobProgress(arg0 context.Context, arg1 uint) (*types.PreheatJobProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreheatJobProgress", arg0, arg1)
+	ret0, _ := ret[0].(*types.PreheatJobProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreheatJobProgress indicates an expected call of GetPreheatJobProgress.
+func (mr *MockServiceMockRecorder) GetPreheatJobProgress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreheatJobProgress", reflect.TypeOf((*MockService)(nil).GetPreheatJobProgress), arg0, arg1)
+}
+
+// GetPreheatSchedule mocks base method.
+func (m *MockService) GetPreheatSchedule(arg0 context.Context, arg1 uint) (*models.PreheatSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreheatSchedule", arg0, arg1)
+	ret0, _ := ret[0].(*models.PreheatSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreheatSchedule indicates an expected call of GetPreheatSchedule.
+func (mr *MockServiceMockRecorder) GetPreheatSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreheatSchedule", reflect.TypeOf((*MockService)(nil).GetPreheatSchedule), arg0, arg1)
+}
+
+// GetPreheatScheduleJobs mocks base method.
+func (m *MockService) GetPreheatScheduleJobs(arg0 context.Context, arg1 uint, arg2 types.GetPreheatScheduleJobsQuery) ([]models.Job, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreheatScheduleJobs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPreheatScheduleJobs indicates an expected call of GetPreheatScheduleJobs.
+func (mr *MockServiceMockRecorder) GetPreheatScheduleJobs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreheatScheduleJobs", reflect.TypeOf((*MockService)(nil).GetPreheatScheduleJobs), arg0, arg1, arg2)
+}
+
+// GetPreheatSchedules mocks base method.
+func (m *MockService) GetPreheatSchedules(arg0 context.Context, arg1 types.GetPreheatSchedulesQuery) ([]models.PreheatSchedule, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreheatSchedules", arg0, arg1)
+	ret0, _ := ret[0].([]models.PreheatSchedule)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPreheatSchedules indicates an expected call of GetPreheatSchedules.
+func (mr *MockServiceMockRecorder) GetPreheatSchedules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreheatSchedules", reflect.TypeOf((*MockService)(nil).GetPreheatSchedules), arg0, arg1)
+}
+
 // GetRole mocks base method.
 func (m *MockService) GetRole(arg0 context.Context, arg1 string) [][]string {
 	m.ctrl.T.Helper()
@@ -896,6 +1271,52 @@ func (mr *MockServiceMockRecorder) GetSchedulerCluster(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedulerCluster", reflect.TypeOf((*MockService)(nil).GetSchedulerCluster), arg0, arg1)
 }
 
+// GetSchedulerClusterConfigVersion mocks base method.
+func (m *MockService) GetSchedulerClusterConfigVersion(arg0 context.Context, arg1, arg2 uint) (*models.ClusterConfigVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedulerClusterConfigVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ClusterConfigVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedulerClusterConfigVersion indicates an expected call of GetSchedulerClusterConfigVersion.
+func (mr *MockServiceMockRecorder) GetSchedulerClusterConfigVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedulerClusterConfigVersion", reflect.TypeOf((*MockService)(nil).GetSchedulerClusterConfigVersion), arg0, arg1, arg2)
+}
+
+// GetSchedulerClusterConfigVersionDiff mocks base method.
+func (m *MockService) GetSchedulerClusterConfigVersionDiff(arg0 context.Context, arg1, arg2 uint, arg3 types.GetClusterConfigVersionDiffQuery) (*types.ClusterConfigVersionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedulerClusterConfigVersionDiff", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types.ClusterConfigVersionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedulerClusterConfigVersionDiff indicates an expected call of GetSchedulerClusterConfigVersionDiff.
+func (mr *MockServiceMockRecorder) GetSchedulerClusterConfigVersionDiff(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedulerClusterConfigVersionDiff", reflect.TypeOf((*MockService)(nil).GetSchedulerClusterConfigVersionDiff), arg0, arg1, arg2, arg3)
+}
+
+// GetSchedulerClusterConfigVersions mocks base method.
+func (m *MockService) GetSchedulerClusterConfigVersions(arg0 context.Context, arg1 uint, arg2 types.GetClusterConfigVersionsQuery) ([]models.ClusterConfigVersion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedulerClusterConfigVersions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.ClusterConfigVersion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSchedulerClusterConfigVersions indicates an expected call of GetSchedulerClusterConfigVersions.
+func (mr *MockServiceMockRecorder) GetSchedulerClusterConfigVersions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedulerClusterConfigVersions", reflect.TypeOf((*MockService)(nil).GetSchedulerClusterConfigVersions), arg0, arg1, arg2)
+}
+
 // GetSchedulerClusters mocks base method.
 func (m *MockService) GetSchedulerClusters(arg0 context.Context, arg1 types.GetSchedulerClustersQuery) ([]models.SchedulerCluster, int64, error) {
 	m.ctrl.T.Helper()
@@ -958,6 +1379,52 @@ func (mr *MockServiceMockRecorder) GetSeedPeerCluster(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeedPeerCluster", reflect.TypeOf((*MockService)(nil).GetSeedPeerCluster), arg0, arg1)
 }
 
+// GetSeedPeerClusterConfigVersion mocks base method.
+func (m *MockService) GetSeedPeerClusterConfigVersion(arg0 context.Context, arg1, arg2 uint) (*models.ClusterConfigVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeedPeerClusterConfigVersion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ClusterConfigVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeedPeerClusterConfigVersion indicates an expected call of GetSeedPeerClusterConfigVersion.
+func (mr *MockServiceMockRecorder) GetSeedPeerClusterConfigVersion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeedPeerClusterConfigVersion", reflect.TypeOf((*MockService)(nil).GetSeedPeerClusterConfigVersion), arg0, arg1, arg2)
+}
+
+// GetSeedPeerClusterConfigVersionDiff mocks base method.
+func (m *MockService) GetSeedPeerClusterConfigVersionDiff(arg0 context.Context, arg1, arg2 uint, arg3 types.GetClusterConfigVersionDiffQuery) (*types.ClusterConfigVersionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeedPeerClusterConfigVersionDiff", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*types.ClusterConfigVersionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeedPeerClusterConfigVersionDiff indicates an expected call of GetSeedPeerClusterConfigVersionDiff.
+func (mr *MockServiceMockRecorder) GetSeedPeerClusterConfigVersionDiff(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeedPeerClusterConfigVersionDiff", reflect.TypeOf((*MockService)(nil).GetSeedPeerClusterConfigVersionDiff), arg0, arg1, arg2, arg3)
+}
+
+// GetSeedPeerClusterConfigVersions mocks base method.
+func (m *MockService) GetSeedPeerClusterConfigVersions(arg0 context.Context, arg1 uint, arg2 types.GetClusterConfigVersionsQuery) ([]models.ClusterConfigVersion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeedPeerClusterConfigVersions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.ClusterConfigVersion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetSeedPeerClusterConfigVersions indicates an expected call of GetSeedPeerClusterConfigVersions.
+func (mr *MockServiceMockRecorder) GetSeedPeerClusterConfigVersions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeedPeerClusterConfigVersions", reflect.TypeOf((*MockService)(nil).GetSeedPeerClusterConfigVersions), arg0, arg1, arg2)
+}
+
 // GetSeedPeerClusters mocks base method.
 func (m *MockService) GetSeedPeerClusters(arg0 context.Context, arg1 types.GetSeedPeerClustersQuery) ([]models.SeedPeerCluster, int64, error) {
 	m.ctrl.T.Helper()
@@ -990,6 +1457,67 @@ func (mr *MockServiceMockRecorder) GetSeedPeers(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeedPeers", reflect.TypeOf((*MockService)(nil).GetSeedPeers), arg0, arg1)
 }
 
+// GetTaskJobResult mocks base method.
+func (m *MockService) GetTaskJobResult(arg0 context.Context, arg1 uint) (*types.GetTaskJobResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskJobResult", arg0, arg1)
+	ret0, _ := ret[0].(*types.GetTaskJobResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskJobResult indicates an expected call of GetTaskJobResult.
+func (mr *MockServiceMockRecorder) GetTaskJobResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskJobResult", reflect.TypeOf((*MockService)(nil).GetTaskJobResult), arg0, arg1)
+}
+
+// GetTenant mocks base method.
+func (m *MockService) GetTenant(arg0 context.Context, arg1 uint) (*models.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenant", arg0, arg1)
+	ret0, _ := ret[0].(*models.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenant indicates an expected call of GetTenant.
+func (mr *MockServiceMockRecorder) GetTenant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenant", reflect.TypeOf((*MockService)(nil).GetTenant), arg0, arg1)
+}
+
+// GetTenantUsage mocks base method.
+func (m *MockService) GetTenantUsage(arg0 context.Context, arg1 uint) (*types.TenantUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenantUsage", arg0, arg1)
+	ret0, _ := ret[0].(*types.TenantUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenantUsage indicates an expected call of GetTenantUsage.
+func (mr *MockServiceMockRecorder) GetTenantUsage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenantUsage", reflect.TypeOf((*MockService)(nil).GetTenantUsage), arg0, arg1)
+}
+
+// GetTenants mocks base method.
+func (m *MockService) GetTenants(arg0 context.Context, arg1 types.GetTenantsQuery) ([]models.Tenant, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenants", arg0, arg1)
+	ret0, _ := ret[0].([]models.Tenant)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTenants indicates an expected call of GetTenants.
+func (mr *MockServiceMockRecorder) GetTenants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenants", reflect.TypeOf((*MockService)(nil).GetTenants), arg0, arg1)
+}
+
 // GetUser mocks base method.
 func (m *MockService) GetUser(arg0 context.Context, arg1 uint) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -1036,6 +1564,97 @@ func (mr *MockServiceMockRecorder) GetV1Preheat(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetV1Preheat", reflect.TypeOf((*MockService)(nil).GetV1Preheat), arg0, arg1)
 }
 
+// GetWebhook mocks base method.
+func (m *MockService) GetWebhook(arg0 context.Context, arg1 uint) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0, arg1)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockServiceMockRecorder) GetWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockService)(nil).GetWebhook), arg0, arg1)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockService) GetWebhooks(arg0 context.Context, arg1 types.GetWebhooksQuery) ([]models.Webhook, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", arg0, arg1)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockServiceMockRecorder) GetWebhooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockService)(nil).GetWebhooks), arg0, arg1)
+}
+
+// OIDCAuthenticate mocks base method.
+func (m *MockService) OIDCAuthenticate(arg0 context.Context, arg1 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCAuthenticate", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCAuthenticate indicates an expected call of OIDCAuthenticate.
+func (mr *MockServiceMockRecorder) OIDCAuthenticate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCAuthenticate", reflect.TypeOf((*MockService)(nil).OIDCAuthenticate), arg0, arg1)
+}
+
+// OIDCRefreshToken mocks base method.
+func (m *MockService) OIDCRefreshToken(arg0 context.Context, arg1 types.OIDCRefreshTokenRequest) (*types.OIDCToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCRefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(*types.OIDCToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCRefreshToken indicates an expected call of OIDCRefreshToken.
+func (mr *MockServiceMockRecorder) OIDCRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCRefreshToken", reflect.TypeOf((*MockService)(nil).OIDCRefreshToken), arg0, arg1)
+}
+
+// OIDCSignin mocks base method.
+func (m *MockService) OIDCSignin(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCSignin", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCSignin indicates an expected call of OIDCSignin.
+func (mr *MockServiceMockRecorder) OIDCSignin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCSignin", reflect.TypeOf((*MockService)(nil).OIDCSignin), arg0, arg1)
+}
+
+// OIDCSigninCallback mocks base method.
+func (m *MockService) OIDCSigninCallback(arg0 context.Context, arg1 string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCSigninCallback", arg0, arg1)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCSigninCallback indicates an expected call of OIDCSigninCallback.
+func (mr *MockServiceMockRecorder) OIDCSigninCallback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCSigninCallback", reflect.TypeOf((*MockService)(nil).OIDCSigninCallback), arg0, arg1)
+}
+
 // OauthSignin mocks base method.
 func (m *MockService) OauthSignin(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -1066,6 +1685,35 @@ func (mr *MockServiceMockRecorder) OauthSigninCallback(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OauthSigninCallback", reflect.TypeOf((*MockService)(nil).OauthSigninCallback), arg0, arg1, arg2)
 }
 
+// PausePreheatSchedule mocks base method.
+func (m *MockService) PausePreheatSchedule(arg0 context.Context, arg1 uint) (*models.PreheatSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PausePreheatSchedule", arg0, arg1)
+	ret0, _ := ret[0].(*models.PreheatSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PausePreheatSchedule indicates an expected call of PausePreheatSchedule.
+func (mr *MockServiceMockRecorder) PausePreheatSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PausePreheatSchedule", reflect.TypeOf((*MockService)(nil).PausePreheatSchedule), arg0, arg1)
+}
+
+// PingWebhook mocks base method.
+func (m *MockService) PingWebhook(arg0 context.Context, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PingWebhook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PingWebhook indicates an expected call of PingWebhook.
+func (mr *MockServiceMockRecorder) PingWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PingWebhook", reflect.TypeOf((*MockService)(nil).PingWebhook), arg0, arg1)
+}
+
 // ResetPassword mocks base method.
 func (m *MockService) ResetPassword(arg0 context.Context, arg1 uint, arg2 types.ResetPasswordRequest) error {
 	m.ctrl.T.Helper()
@@ -1080,6 +1728,51 @@ func (mr *MockServiceMockRecorder) ResetPassword(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockService)(nil).ResetPassword), arg0, arg1, arg2)
 }
 
+// ResumePreheatSchedule mocks base method.
+func (m *MockService) ResumePreheatSchedule(arg0 context.Context, arg1 uint) (*models.PreheatSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumePreheatSchedule", arg0, arg1)
+	ret0, _ := ret[0].(*models.PreheatSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumePreheatSchedule indicates an expected call of ResumePreheatSchedule.
+func (mr *MockServiceMockRecorder) ResumePreheatSchedule(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumePreheatSchedule", reflect.TypeOf((*MockService)(nil).ResumePreheatSchedule), arg0, arg1)
+}
+
+// RollbackSchedulerClusterConfig mocks base method.
+func (m *MockService) RollbackSchedulerClusterConfig(arg0 context.Context, arg1, arg2 uint) (*models.SchedulerCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackSchedulerClusterConfig", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.SchedulerCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackSchedulerClusterConfig indicates an expected call of RollbackSchedulerClusterConfig.
+func (mr *MockServiceMockRecorder) RollbackSchedulerClusterConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackSchedulerClusterConfig", reflect.TypeOf((*MockService)(nil).RollbackSchedulerClusterConfig), arg0, arg1, arg2)
+}
+
+// RollbackSeedPeerClusterConfig mocks base method.
+func (m *MockService) RollbackSeedPeerClusterConfig(arg0 context.Context, arg1, arg2 uint) (*models.SeedPeerCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackSeedPeerClusterConfig", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.SeedPeerCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackSeedPeerClusterConfig indicates an expected call of RollbackSeedPeerClusterConfig.
+func (mr *MockServiceMockRecorder) RollbackSeedPeerClusterConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackSeedPeerClusterConfig", reflect.TypeOf((*MockService)(nil).RollbackSeedPeerClusterConfig), arg0, arg1, arg2)
+}
+
 // SignIn mocks base method.
 func (m *MockService) SignIn(arg0 context.Context, arg1 types.SignInRequest) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -1155,6 +1848,21 @@ func (mr *MockServiceMockRecorder) UpdateConfig(arg0, arg1, arg2 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockService)(nil).UpdateConfig), arg0, arg1, arg2)
 }
 
+// UpdateFeatureFlag mocks base method.
+func (m *MockService) UpdateFeatureFlag(arg0 context.Context, arg1 uint, arg2 types.UpdateFeatureFlagRequest) (*models.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFeatureFlag", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFeatureFlag indicates an expected call of UpdateFeatureFlag.
+func (mr *MockServiceMockRecorder) UpdateFeatureFlag(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeatureFlag", reflect.TypeOf((*MockService)(nil).UpdateFeatureFlag), arg0, arg1, arg2)
+}
+
 // UpdateJob mocks base method.
 func (m *MockService) UpdateJob(arg0 context.Context, arg1 uint, arg2 types.UpdateJobRequest) (*models.Job, error) {
 	m.ctrl.T.Helper()
@@ -1215,6 +1923,21 @@ func (mr *MockServiceMockRecorder) UpdatePersonalAccessToken(arg0, arg1, arg2 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePersonalAccessToken", reflect.TypeOf((*MockService)(nil).UpdatePersonalAccessToken), arg0, arg1, arg2)
 }
 
+// UpdatePreheatSchedule mocks base method.
+func (m *MockService) UpdatePreheatSchedule(arg0 context.Context, arg1 uint, arg2 types.UpdatePreheatScheduleRequest) (*models.PreheatSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreheatSchedule", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.PreheatSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePreheatSchedule indicates an expected call of UpdatePreheatSchedule.
+func (mr *MockServiceMockRecorder) UpdatePreheatSchedule(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreheatSchedule", reflect.TypeOf((*MockService)(nil).UpdatePreheatSchedule), arg0, arg1, arg2)
+}
+
 // UpdateScheduler mocks base method.
 func (m *MockService) UpdateScheduler(arg0 context.Context, arg1 uint, arg2 types.UpdateSchedulerRequest) (*models.Scheduler, error) {
 	m.ctrl.T.Helper()
@@ -1275,6 +1998,21 @@ func (mr *MockServiceMockRecorder) UpdateSeedPeerCluster(arg0, arg1, arg2 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSeedPeerCluster", reflect.TypeOf((*MockService)(nil).UpdateSeedPeerCluster), arg0, arg1, arg2)
 }
 
+// UpdateTenant mocks base method.
+func (m *MockService) UpdateTenant(arg0 context.Context, arg1 uint, arg2 types.UpdateTenantRequest) (*models.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenant", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTenant indicates an expected call of UpdateTenant.
+func (mr *MockServiceMockRecorder) UpdateTenant(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenant", reflect.TypeOf((*MockService)(nil).UpdateTenant), arg0, arg1, arg2)
+}
+
 // UpdateUser mocks base method.
 func (m *MockService) UpdateUser(arg0 context.Context, arg1 uint, arg2 types.UpdateUserRequest) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -1289,3 +2027,18 @@ func (mr *MockServiceMockRecorder) UpdateUser(arg0, arg1, arg2 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockService)(nil).UpdateUser), arg0, arg1, arg2)
 }
+
+// UpdateWebhook mocks base method.
+func (m *MockService) UpdateWebhook(arg0 context.Context, arg1 uint, arg2 types.UpdateWebhookRequest) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockServiceMockRecorder) UpdateWebhook(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockService)(nil).UpdateWebhook), arg0, arg1, arg2)
+}
@@ -0,0 +1,226 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"d7y.io/dragonfly/v2/manager/auth/oidc"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/permission/rbac"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/slices"
+)
+
+// errOIDCDisabled is the error when oidc is not enabled in manager.
+var errOIDCDisabled = errors.New("oidc is disabled")
+
+func (s *service) OIDCSignin(ctx context.Context, state string) (string, error) {
+	if s.oidc == nil {
+		return "", errOIDCDisabled
+	}
+
+	return s.oidc.AuthCodeURL(ctx, state)
+}
+
+func (s *service) OIDCSigninCallback(ctx context.Context, code string) (*models.User, error) {
+	if s.oidc == nil {
+		return nil, errOIDCDisabled
+	}
+
+	token, err := s.oidc.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.oidcSignin(ctx, token.IDToken)
+}
+
+func (s *service) OIDCRefreshToken(ctx context.Context, json types.OIDCRefreshTokenRequest) (*types.OIDCToken, error) {
+	if s.oidc == nil {
+		return nil, errOIDCDisabled
+	}
+
+	token, err := s.oidc.Refresh(ctx, json.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Synchronize the roles of user with the refreshed groups.
+	if _, err := s.oidcSignin(ctx, token.IDToken); err != nil {
+		return nil, err
+	}
+
+	return &types.OIDCToken{
+		Token:        token.IDToken,
+		RefreshToken: token.RefreshToken,
+		Expire:       token.Expiry,
+	}, nil
+}
+
+// OIDCAuthenticate authenticates the request by the id token, the user must have signed in
+// by the OIDC provider. It does not write the user and the roles, they are synchronized
+// at signin and token refresh.
+func (s *service) OIDCAuthenticate(ctx context.Context, rawIDToken string) (*models.User, error) {
+	oidcUser, err := s.verifyOIDCUser(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user := models.User{}
+	if err := s.db.WithContext(ctx).First(&user, models.User{OIDCIssuer: oidcUser.Issuer, OIDCSubject: oidcUser.Subject}).Error; err != nil {
+		return nil, err
+	}
+
+	if user.State != models.UserStateEnabled {
+		return nil, fmt.Errorf("user %s is disabled", user.Name)
+	}
+
+	return &user, nil
+}
+
+// oidcSignin signs in the user by the id token and synchronizes the roles of user. Users are
+// identified by the issuer and the subject, the user signed in by the OIDC provider at the
+// first time is created. The existing users are never linked by the email.
+func (s *service) oidcSignin(ctx context.Context, rawIDToken string) (*models.User, error) {
+	oidcUser, err := s.verifyOIDCUser(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user := models.User{}
+	isNew := false
+	if err := s.db.WithContext(ctx).First(&user, models.User{OIDCIssuer: oidcUser.Issuer, OIDCSubject: oidcUser.Subject}).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		if err := s.db.WithContext(ctx).First(&models.User{}, models.User{Email: oidcUser.Email}).Error; err == nil {
+			return nil, fmt.Errorf("email %s is used by another user", oidcUser.Email)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		user = models.User{
+			Name:        oidcUser.Name,
+			Email:       oidcUser.Email,
+			Avatar:      oidcUser.Avatar,
+			State:       models.UserStateEnabled,
+			OIDCIssuer:  oidcUser.Issuer,
+			OIDCSubject: oidcUser.Subject,
+		}
+		if user.Name == "" {
+			user.Name = oidcUser.Email
+		}
+
+		if err := s.db.WithContext(ctx).Create(&user).Error; err != nil {
+			return nil, err
+		}
+
+		isNew = true
+	}
+
+	if user.State != models.UserStateEnabled {
+		return nil, fmt.Errorf("user %s is disabled", user.Name)
+	}
+
+	if err := s.syncOIDCRoles(&user, oidcUser.Groups, isNew); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// verifyOIDCUser verifies the id token and returns the user of OIDC provider, the email of user is required to be verified.
+func (s *service) verifyOIDCUser(ctx context.Context, rawIDToken string) (*oidc.User, error) {
+	if s.oidc == nil {
+		return nil, errOIDCDisabled
+	}
+
+	oidcUser, err := s.oidc.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if oidcUser.Email == "" {
+		return nil, errors.New("id token requires claim email")
+	}
+
+	if !oidcUser.EmailVerified {
+		return nil, fmt.Errorf("email %s is not verified", oidcUser.Email)
+	}
+
+	return oidcUser, nil
+}
+
+// syncOIDCRoles synchronizes the roles of user with the groups of the OIDC provider
+// by the role mapping. If the role mapping is not configured, the new user gets
+// the guest role and the roles of the existing user are not changed.
+func (s *service) syncOIDCRoles(user *models.User, groups []string, isNew bool) error {
+	id := fmt.Sprint(user.ID)
+	if len(s.config.Auth.OIDC.RoleMapping) == 0 {
+		if isNew {
+			if _, err := s.enforcer.AddRoleForUser(id, rbac.GuestRole); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// The keys of the role mapping are lowercased by the config loader,
+	// so the groups are matched case-insensitively.
+	roles := []string{}
+	for _, group := range groups {
+		role, ok := s.config.Auth.OIDC.RoleMapping[strings.ToLower(group)]
+		if ok && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	if len(roles) == 0 {
+		roles = append(roles, rbac.GuestRole)
+	}
+
+	currentRoles, err := s.enforcer.GetRolesForUser(id)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range currentRoles {
+		if !slices.Contains(roles, role) {
+			if _, err := s.enforcer.DeleteRoleForUser(id, role); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, role := range roles {
+		if !slices.Contains(currentRoles, role) {
+			if _, err := s.enforcer.AddRoleForUser(id, role); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
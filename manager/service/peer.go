@@ -18,9 +18,14 @@ package service
 
 import (
 	"context"
+	"fmt"
 
+	"gorm.io/gorm"
+
+	internaljob "d7y.io/dragonfly/v2/internal/job"
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/idgen"
 )
 
 func (s *service) CreatePeer(ctx context.Context, json types.CreatePeerRequest) (*models.Peer, error) {
@@ -75,9 +80,14 @@ func (s *service) GetPeer(ctx context.Context, id uint) (*models.Peer, error) {
 }
 
 func (s *service) GetPeers(ctx context.Context, q types.GetPeersQuery) ([]models.Peer, int64, error) {
+	db := s.db.WithContext(ctx)
+	if q.Tag != "" {
+		db = db.Where("tags LIKE ?", fmt.Sprintf("%%%q%%", q.Tag))
+	}
+
 	var count int64
 	var peers []models.Peer
-	if err := s.db.WithContext(ctx).Preload("SchedulerCluster").Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.Peer{
+	if err := db.Preload("SchedulerCluster").Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.Peer{
 		Type:               q.Type,
 		Hostname:           q.Hostname,
 		IDC:                q.IDC,
@@ -102,3 +112,105 @@ func (s *service) GetPeers(ctx context.Context, q types.GetPeersQuery) ([]models
 
 	return peers, count, nil
 }
+
+func (s *service) BatchPeers(ctx context.Context, json types.BatchPeersRequest) (*types.BatchPeersResponse, error) {
+	var peers []models.Peer
+	if err := s.db.WithContext(ctx).Find(&peers, json.IDs).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]models.Peer, len(peers))
+	for _, peer := range peers {
+		found[peer.ID] = peer
+	}
+
+	resp := &types.BatchPeersResponse{}
+	for _, id := range json.IDs {
+		result := types.BatchPeerResult{ID: id}
+		peer, ok := found[id]
+		if !ok {
+			result.Error = gorm.ErrRecordNotFound.Error()
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+
+		jobID, err := s.batchPeer(ctx, peer, json)
+		if err != nil {
+			result.Error = err.Error()
+		}
+
+		result.JobID = jobID
+		resp.Results = append(resp.Results, result)
+	}
+
+	return resp, nil
+}
+
+// batchPeer performs the action of batch request on the peer, and returns the job id
+// if the action is performed by the schedulers.
+func (s *service) batchPeer(ctx context.Context, peer models.Peer, json types.BatchPeersRequest) (uint, error) {
+	switch json.Action {
+	case types.BatchPeersActionTag:
+		tags := peer.Tags
+		for _, tag := range json.Tags {
+			if !containsTag(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		return 0, s.db.WithContext(ctx).Model(&models.Peer{}).Where("id = ?", peer.ID).Update("tags", tags).Error
+	case types.BatchPeersActionUntag:
+		tags := models.Array{}
+		for _, tag := range peer.Tags {
+			if !containsTag(json.Tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		return 0, s.db.WithContext(ctx).Model(&models.Peer{}).Where("id = ?", peer.ID).Update("tags", tags).Error
+	case types.BatchPeersActionDrain, types.BatchPeersActionUndrain:
+		job, err := s.CreateDrainHostJob(ctx, types.CreateDrainHostJobRequest{
+			Type: internaljob.DrainHostJob,
+			Args: types.DrainHostArgs{
+				HostID: idgen.HostIDV2(peer.IP, peer.Hostname),
+				Cancel: json.Action == types.BatchPeersActionUndrain,
+			},
+			UserID:              json.UserID,
+			SchedulerClusterIDs: []uint{peer.SchedulerClusterID},
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return job.ID, nil
+	case types.BatchPeersActionReannounce:
+		job, err := s.CreateReannounceHostJob(ctx, types.CreateReannounceHostJobRequest{
+			Type: internaljob.ReannounceHostJob,
+			Args: types.ReannounceHostArgs{
+				HostID: idgen.HostIDV2(peer.IP, peer.Hostname),
+			},
+			UserID:              json.UserID,
+			SchedulerClusterIDs: []uint{peer.SchedulerClusterID},
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return job.ID, nil
+	case types.BatchPeersActionDelete:
+		return 0, s.db.WithContext(ctx).Unscoped().Delete(&models.Peer{}, peer.ID).Error
+	default:
+		return 0, fmt.Errorf("unknown action %s", json.Action)
+	}
+}
+
+// containsTag returns whether the tags contain the tag.
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,188 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/robfig/cron/v3"
+
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/structure"
+)
+
+func (s *service) CreatePreheatSchedule(ctx context.Context, json types.CreatePreheatScheduleRequest) (*models.PreheatSchedule, error) {
+	if _, err := cron.ParseStandard(json.Cron); err != nil {
+		return nil, fmt.Errorf("invalid cron %s: %w", json.Cron, err)
+	}
+
+	args, err := structure.StructToMap(json.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedulerClusters []models.SchedulerCluster
+	if len(json.SchedulerClusterIDs) != 0 {
+		if err := s.db.WithContext(ctx).Find(&schedulerClusters, json.SchedulerClusterIDs).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	preheatSchedule := models.PreheatSchedule{
+		Name:              json.Name,
+		BIO:               json.BIO,
+		Cron:              json.Cron,
+		State:             models.PreheatScheduleStateActive,
+		Args:              args,
+		UserID:            json.UserID,
+		SchedulerClusters: schedulerClusters,
+	}
+
+	if err := s.db.WithContext(ctx).Create(&preheatSchedule).Error; err != nil {
+		return nil, err
+	}
+
+	return &preheatSchedule, nil
+}
+
+func (s *service) DestroyPreheatSchedule(ctx context.Context, id uint) error {
+	preheatSchedule := models.PreheatSchedule{}
+	if err := s.db.WithContext(ctx).First(&preheatSchedule, id).Error; err != nil {
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Model(&preheatSchedule).Association("SchedulerClusters").Clear(); err != nil {
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Unscoped().Delete(&models.PreheatSchedule{}, id).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdatePreheatSchedule(ctx context.Context, id uint, json types.UpdatePreheatScheduleRequest) (*models.PreheatSchedule, error) {
+	if json.Cron != "" {
+		if _, err := cron.ParseStandard(json.Cron); err != nil {
+			return nil, fmt.Errorf("invalid cron %s: %w", json.Cron, err)
+		}
+	}
+
+	var args models.JSONMap
+	if json.Args != nil {
+		var err error
+		if args, err = structure.StructToMap(json.Args); err != nil {
+			return nil, err
+		}
+	}
+
+	preheatSchedule := models.PreheatSchedule{}
+	if err := s.db.WithContext(ctx).First(&preheatSchedule, id).Updates(models.PreheatSchedule{
+		BIO:    json.BIO,
+		Cron:   json.Cron,
+		Args:   args,
+		UserID: json.UserID,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// Content of the new args may differ, preheat it in the next schedule.
+	if json.Args != nil {
+		if err := s.db.WithContext(ctx).Model(&preheatSchedule).Update("last_digest", "").Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if len(json.SchedulerClusterIDs) != 0 {
+		var schedulerClusters []models.SchedulerCluster
+		if err := s.db.WithContext(ctx).Find(&schedulerClusters, json.SchedulerClusterIDs).Error; err != nil {
+			return nil, err
+		}
+
+		if err := s.db.WithContext(ctx).Model(&preheatSchedule).Association("SchedulerClusters").Replace(schedulerClusters); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPreheatSchedule(ctx, id)
+}
+
+func (s *service) PausePreheatSchedule(ctx context.Context, id uint) (*models.PreheatSchedule, error) {
+	preheatSchedule := models.PreheatSchedule{}
+	if err := s.db.WithContext(ctx).First(&preheatSchedule, id).Updates(models.PreheatSchedule{
+		State: models.PreheatScheduleStateInactive,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &preheatSchedule, nil
+}
+
+func (s *service) ResumePreheatSchedule(ctx context.Context, id uint) (*models.PreheatSchedule, error) {
+	preheatSchedule := models.PreheatSchedule{}
+	if err := s.db.WithContext(ctx).First(&preheatSchedule, id).Updates(models.PreheatSchedule{
+		State: models.PreheatScheduleStateActive,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &preheatSchedule, nil
+}
+
+func (s *service) GetPreheatSchedule(ctx context.Context, id uint) (*models.PreheatSchedule, error) {
+	preheatSchedule := models.PreheatSchedule{}
+	if err := s.db.WithContext(ctx).Preload("SchedulerClusters").First(&preheatSchedule, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &preheatSchedule, nil
+}
+
+func (s *service) GetPreheatSchedules(ctx context.Context, q types.GetPreheatSchedulesQuery) ([]models.PreheatSchedule, int64, error) {
+	var count int64
+	var preheatSchedules []models.PreheatSchedule
+	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.PreheatSchedule{
+		Name:   q.Name,
+		State:  q.State,
+		UserID: q.UserID,
+	}).Preload("SchedulerClusters").Find(&preheatSchedules).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return preheatSchedules, count, nil
+}
+
+func (s *service) GetPreheatScheduleJobs(ctx context.Context, id uint, q types.GetPreheatScheduleJobsQuery) ([]models.Job, int64, error) {
+	preheatSchedule := models.PreheatSchedule{}
+	if err := s.db.WithContext(ctx).First(&preheatSchedule, id).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	var jobs []models.Job
+	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.Job{
+		State:             q.State,
+		PreheatScheduleID: preheatSchedule.ID,
+	}).Order("created_at DESC").Find(&jobs).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return jobs, count, nil
+}
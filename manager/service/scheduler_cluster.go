@@ -20,8 +20,11 @@ import (
 	"context"
 	"errors"
 
+	"gorm.io/gorm/clause"
+
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
 	"d7y.io/dragonfly/v2/pkg/structure"
 )
 
@@ -48,12 +51,18 @@ func (s *service) CreateSchedulerCluster(ctx context.Context, json types.CreateS
 		ClientConfig: clientConfig,
 		Scopes:       scopes,
 		IsDefault:    json.IsDefault,
+		TenantID:     json.TenantID,
 	}
 
 	if err := s.db.WithContext(ctx).Create(&schedulerCluster).Error; err != nil {
 		return nil, err
 	}
 
+	if err := createClusterConfigVersion(ctx, s.db, models.ClusterConfigVersionTypeSchedulerCluster, schedulerCluster.ID,
+		schedulerCluster.Config, schedulerCluster.ClientConfig, "create config"); err != nil {
+		return nil, err
+	}
+
 	if json.SeedPeerClusterID > 0 {
 		if err := s.AddSchedulerClusterToSeedPeerCluster(ctx, json.SeedPeerClusterID, schedulerCluster.ID); err != nil {
 			return nil, err
@@ -77,6 +86,10 @@ func (s *service) DestroySchedulerCluster(ctx context.Context, id uint) error {
 		return err
 	}
 
+	if err := destroyClusterConfigVersions(ctx, s.db, models.ClusterConfigVersionTypeSchedulerCluster, id); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -108,31 +121,68 @@ func (s *service) UpdateSchedulerCluster(ctx context.Context, id uint, json type
 		}
 	}
 
+	// Update config and create config version with transaction, the scheduler cluster is locked,
+	// so the concurrent updates and rollbacks create the config versions in order.
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
 	schedulerCluster := models.SchedulerCluster{}
-	if err := s.db.WithContext(ctx).First(&schedulerCluster, id).Updates(models.SchedulerCluster{
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&schedulerCluster, id).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.WithContext(ctx).Model(&schedulerCluster).Updates(models.SchedulerCluster{
 		Name:         json.Name,
 		BIO:          json.BIO,
 		Config:       config,
 		ClientConfig: clientConfig,
 		Scopes:       scopes,
+		TenantID:     json.TenantID,
 	}).Error; err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
 	// Updates does not accept bool as false.
 	// Refer to https://stackoverflow.com/questions/56653423/gorm-doesnt-update-boolean-field-to-false.
 	if json.IsDefault != schedulerCluster.IsDefault {
-		if err := s.db.WithContext(ctx).First(&schedulerCluster, id).Update("is_default", json.IsDefault).Error; err != nil {
+		if err := tx.WithContext(ctx).Model(&schedulerCluster).Update("is_default", json.IsDefault).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if json.Config != nil || json.ClientConfig != nil {
+		if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSchedulerCluster, schedulerCluster.ID,
+			schedulerCluster.Config, schedulerCluster.ClientConfig, "update config"); err != nil {
+			tx.Rollback()
 			return nil, err
 		}
 	}
 
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
 	if json.SeedPeerClusterID > 0 {
 		if err := s.AddSchedulerClusterToSeedPeerCluster(ctx, json.SeedPeerClusterID, schedulerCluster.ID); err != nil {
 			return nil, err
 		}
 	}
 
+	if json.Config != nil || json.ClientConfig != nil {
+		s.webhook.Notify(ctx, webhook.EventSchedulerClusterConfigUpdated, &schedulerCluster)
+	}
+
 	return &schedulerCluster, nil
 }
 
@@ -20,8 +20,11 @@ import (
 	"context"
 	"errors"
 
+	"gorm.io/gorm/clause"
+
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
 	"d7y.io/dragonfly/v2/pkg/structure"
 )
 
@@ -32,15 +35,21 @@ func (s *service) CreateSeedPeerCluster(ctx context.Context, json types.CreateSe
 	}
 
 	seedPeerCluster := models.SeedPeerCluster{
-		Name:   json.Name,
-		BIO:    json.BIO,
-		Config: config,
+		Name:     json.Name,
+		BIO:      json.BIO,
+		Config:   config,
+		TenantID: json.TenantID,
 	}
 
 	if err := s.db.WithContext(ctx).Create(&seedPeerCluster).Error; err != nil {
 		return nil, err
 	}
 
+	if err := createClusterConfigVersion(ctx, s.db, models.ClusterConfigVersionTypeSeedPeerCluster, seedPeerCluster.ID,
+		seedPeerCluster.Config, nil, "create config"); err != nil {
+		return nil, err
+	}
+
 	return &seedPeerCluster, nil
 }
 
@@ -58,6 +67,10 @@ func (s *service) DestroySeedPeerCluster(ctx context.Context, id uint) error {
 		return err
 	}
 
+	if err := destroyClusterConfigVersions(ctx, s.db, models.ClusterConfigVersionTypeSeedPeerCluster, id); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -73,15 +86,51 @@ func (s *service) UpdateSeedPeerCluster(ctx context.Context, id uint, json types
 		}
 	}
 
+	// Update config and create config version with transaction, the seed peer cluster is locked,
+	// so the concurrent updates and rollbacks create the config versions in order.
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Error; err != nil {
+		return nil, err
+	}
+
 	seedPeerCluster := models.SeedPeerCluster{}
-	if err := s.db.WithContext(ctx).First(&seedPeerCluster, id).Updates(models.SeedPeerCluster{
-		Name:   json.Name,
-		BIO:    json.BIO,
-		Config: config,
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&seedPeerCluster, id).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.WithContext(ctx).Model(&seedPeerCluster).Updates(models.SeedPeerCluster{
+		Name:     json.Name,
+		BIO:      json.BIO,
+		Config:   config,
+		TenantID: json.TenantID,
 	}).Error; err != nil {
+		tx.Rollback()
 		return nil, err
 	}
 
+	if json.Config != nil {
+		if err := createClusterConfigVersion(ctx, tx, models.ClusterConfigVersionTypeSeedPeerCluster, seedPeerCluster.ID,
+			seedPeerCluster.Config, nil, "update config"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	if json.Config != nil {
+		s.webhook.Notify(ctx, webhook.EventSeedPeerClusterConfigUpdated, &seedPeerCluster)
+	}
+
 	return &seedPeerCluster, nil
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 
+	"d7y.io/dragonfly/v2/manager/auth/oidc"
 	"d7y.io/dragonfly/v2/manager/cache"
 	"d7y.io/dragonfly/v2/manager/config"
 	"d7y.io/dragonfly/v2/manager/database"
@@ -33,6 +34,7 @@ import (
 	"d7y.io/dragonfly/v2/manager/models"
 	"d7y.io/dragonfly/v2/manager/permission/rbac"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
 	"d7y.io/dragonfly/v2/pkg/objectstorage"
 )
 
@@ -44,6 +46,10 @@ type Service interface {
 	SignUp(context.Context, types.SignUpRequest) (*models.User, error)
 	OauthSignin(context.Context, string) (string, error)
 	OauthSigninCallback(context.Context, string, string) (*models.User, error)
+	OIDCSignin(context.Context, string) (string, error)
+	OIDCSigninCallback(context.Context, string) (*models.User, error)
+	OIDCRefreshToken(context.Context, types.OIDCRefreshTokenRequest) (*types.OIDCToken, error)
+	OIDCAuthenticate(context.Context, string) (*models.User, error)
 	ResetPassword(context.Context, uint, types.ResetPasswordRequest) error
 	GetRolesForUser(context.Context, uint) ([]string, error)
 	AddRoleForUser(context.Context, types.AddRoleForUserParams) (bool, error)
@@ -77,6 +83,10 @@ type Service interface {
 	GetSeedPeerClusters(context.Context, types.GetSeedPeerClustersQuery) ([]models.SeedPeerCluster, int64, error)
 	AddSeedPeerToSeedPeerCluster(context.Context, uint, uint) error
 	AddSchedulerClusterToSeedPeerCluster(context.Context, uint, uint) error
+	GetSeedPeerClusterConfigVersions(context.Context, uint, types.GetClusterConfigVersionsQuery) ([]models.ClusterConfigVersion, int64, error)
+	GetSeedPeerClusterConfigVersion(context.Context, uint, uint) (*models.ClusterConfigVersion, error)
+	GetSeedPeerClusterConfigVersionDiff(context.Context, uint, uint, types.GetClusterConfigVersionDiffQuery) (*types.ClusterConfigVersionDiff, error)
+	RollbackSeedPeerClusterConfig(context.Context, uint, uint) (*models.SeedPeerCluster, error)
 
 	CreateSeedPeer(context.Context, types.CreateSeedPeerRequest) (*models.SeedPeer, error)
 	DestroySeedPeer(context.Context, uint) error
@@ -88,6 +98,7 @@ type Service interface {
 	DestroyPeer(context.Context, uint) error
 	GetPeer(context.Context, uint) (*models.Peer, error)
 	GetPeers(context.Context, types.GetPeersQuery) ([]models.Peer, int64, error)
+	BatchPeers(context.Context, types.BatchPeersRequest) (*types.BatchPeersResponse, error)
 
 	CreateSchedulerCluster(context.Context, types.CreateSchedulerClusterRequest) (*models.SchedulerCluster, error)
 	DestroySchedulerCluster(context.Context, uint) error
@@ -95,6 +106,10 @@ type Service interface {
 	GetSchedulerCluster(context.Context, uint) (*models.SchedulerCluster, error)
 	GetSchedulerClusters(context.Context, types.GetSchedulerClustersQuery) ([]models.SchedulerCluster, int64, error)
 	AddSchedulerToSchedulerCluster(context.Context, uint, uint) error
+	GetSchedulerClusterConfigVersions(context.Context, uint, types.GetClusterConfigVersionsQuery) ([]models.ClusterConfigVersion, int64, error)
+	GetSchedulerClusterConfigVersion(context.Context, uint, uint) (*models.ClusterConfigVersion, error)
+	GetSchedulerClusterConfigVersionDiff(context.Context, uint, uint, types.GetClusterConfigVersionDiffQuery) (*types.ClusterConfigVersionDiff, error)
+	RollbackSchedulerClusterConfig(context.Context, uint, uint) (*models.SchedulerCluster, error)
 
 	CreateScheduler(context.Context, types.CreateSchedulerRequest) (*models.Scheduler, error)
 	DestroyScheduler(context.Context, uint) error
@@ -114,11 +129,51 @@ type Service interface {
 	GetConfigs(context.Context, types.GetConfigsQuery) ([]models.Config, int64, error)
 
 	CreatePreheatJob(context.Context, types.CreatePreheatJobRequest) (*models.Job, error)
+	CreateDrainHostJob(context.Context, types.CreateDrainHostJobRequest) (*models.Job, error)
+	CreateReannounceHostJob(context.Context, types.CreateReannounceHostJobRequest) (*models.Job, error)
+	CreateHostReputationJob(context.Context, types.CreateHostReputationJobRequest) (*models.Job, error)
+	CreateDeleteTaskJob(context.Context, types.CreateDeleteTaskJobRequest) (*models.Job, error)
+	CreateGetTaskJob(context.Context, types.CreateGetTaskJobRequest) (*models.Job, error)
 	DestroyJob(context.Context, uint) error
 	UpdateJob(context.Context, uint, types.UpdateJobRequest) (*models.Job, error)
 	GetJob(context.Context, uint) (*models.Job, error)
+	GetPreheatJobProgress(context.Context, uint) (*types.PreheatJobProgress, error)
+	GetTaskJobResult(context.Context, uint) (*types.GetTaskJobResult, error)
 	GetJobs(context.Context, types.GetJobsQuery) ([]models.Job, int64, error)
 
+	CreatePreheatSchedule(context.Context, types.CreatePreheatScheduleRequest) (*models.PreheatSchedule, error)
+	DestroyPreheatSchedule(context.Context, uint) error
+	UpdatePreheatSchedule(context.Context, uint, types.UpdatePreheatScheduleRequest) (*models.PreheatSchedule, error)
+	PausePreheatSchedule(context.Context, uint) (*models.PreheatSchedule, error)
+	ResumePreheatSchedule(context.Context, uint) (*models.PreheatSchedule, error)
+	GetPreheatSchedule(context.Context, uint) (*models.PreheatSchedule, error)
+	GetPreheatSchedules(context.Context, types.GetPreheatSchedulesQuery) ([]models.PreheatSchedule, int64, error)
+	GetPreheatScheduleJobs(context.Context, uint, types.GetPreheatScheduleJobsQuery) ([]models.Job, int64, error)
+
+	CreateTenant(context.Context, types.CreateTenantRequest) (*models.Tenant, error)
+	DestroyTenant(context.Context, uint) error
+	UpdateTenant(context.Context, uint, types.UpdateTenantRequest) (*models.Tenant, error)
+	GetTenant(context.Context, uint) (*models.Tenant, error)
+	GetTenants(context.Context, types.GetTenantsQuery) ([]models.Tenant, int64, error)
+	GetTenantUsage(context.Context, uint) (*types.TenantUsage, error)
+
+	GetAuditLog(context.Context, uint) (*models.AuditLog, error)
+	GetAuditLogs(context.Context, types.GetAuditLogsQuery) ([]models.AuditLog, int64, error)
+	ExportAuditLogs(context.Context, types.ExportAuditLogsQuery) ([]models.AuditLog, error)
+
+	CreateFeatureFlag(context.Context, types.CreateFeatureFlagRequest) (*models.FeatureFlag, error)
+	DestroyFeatureFlag(context.Context, uint) error
+	UpdateFeatureFlag(context.Context, uint, types.UpdateFeatureFlagRequest) (*models.FeatureFlag, error)
+	GetFeatureFlag(context.Context, uint) (*models.FeatureFlag, error)
+	GetFeatureFlags(context.Context, types.GetFeatureFlagsQuery) ([]models.FeatureFlag, int64, error)
+
+	CreateWebhook(context.Context, types.CreateWebhookRequest) (*models.Webhook, error)
+	DestroyWebhook(context.Context, uint) error
+	UpdateWebhook(context.Context, uint, types.UpdateWebhookRequest) (*models.Webhook, error)
+	GetWebhook(context.Context, uint) (*models.Webhook, error)
+	GetWebhooks(context.Context, types.GetWebhooksQuery) ([]models.Webhook, int64, error)
+	PingWebhook(context.Context, uint) error
+
 	CreateV1Preheat(context.Context, types.CreateV1PreheatRequest) (*types.CreateV1PreheatResponse, error)
 	GetV1Preheat(context.Context, string) (*types.GetV1PreheatResponse, error)
 
@@ -138,6 +193,8 @@ type Service interface {
 	UpdatePersonalAccessToken(context.Context, uint, types.UpdatePersonalAccessTokenRequest) (*models.PersonalAccessToken, error)
 	GetPersonalAccessToken(context.Context, uint) (*models.PersonalAccessToken, error)
 	GetPersonalAccessTokens(context.Context, types.GetPersonalAccessTokensQuery) ([]models.PersonalAccessToken, int64, error)
+
+	GetCACertificate(context.Context) ([]byte, error)
 }
 
 type service struct {
@@ -148,11 +205,13 @@ type service struct {
 	job           *job.Job
 	enforcer      *casbin.Enforcer
 	objectStorage objectstorage.ObjectStorage
+	oidc          oidc.OIDC
+	webhook       webhook.Webhook
 }
 
 // NewREST returns a new REST instence
-func New(cfg *config.Config, database *database.Database, cache *cache.Cache, job *job.Job, enforcer *casbin.Enforcer, objectStorage objectstorage.ObjectStorage) Service {
-	return &service{
+func New(cfg *config.Config, database *database.Database, cache *cache.Cache, job *job.Job, enforcer *casbin.Enforcer, objectStorage objectstorage.ObjectStorage, webhook webhook.Webhook) Service {
+	s := &service{
 		config:        cfg,
 		db:            database.DB,
 		rdb:           database.RDB,
@@ -160,5 +219,12 @@ func New(cfg *config.Config, database *database.Database, cache *cache.Cache, jo
 		job:           job,
 		enforcer:      enforcer,
 		objectStorage: objectStorage,
+		webhook:       webhook,
 	}
+
+	if cfg.Auth.OIDC.Enable {
+		s.oidc = oidc.New(cfg.Auth.OIDC)
+	}
+
+	return s
 }
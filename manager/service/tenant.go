@@ -0,0 +1,284 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	machineryv1tasks "github.com/RichardKnop/machinery/v1/tasks"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/structure"
+)
+
+func (s *service) CreateTenant(ctx context.Context, json types.CreateTenantRequest) (*models.Tenant, error) {
+	var quota map[string]any
+	if json.Quota != nil {
+		var err error
+		if quota, err = structure.StructToMap(json.Quota); err != nil {
+			return nil, err
+		}
+	}
+
+	tenant := models.Tenant{
+		Name:   json.Name,
+		BIO:    json.BIO,
+		Quota:  quota,
+		UserID: json.UserID,
+	}
+
+	if err := s.db.WithContext(ctx).Create(&tenant).Error; err != nil {
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+func (s *service) DestroyTenant(ctx context.Context, id uint) error {
+	tenant := models.Tenant{}
+	if err := s.db.WithContext(ctx).First(&tenant, id).Error; err != nil {
+		return err
+	}
+
+	// Release the resources of the tenant before the tenant is deleted.
+	for _, model := range []any{&models.SchedulerCluster{}, &models.SeedPeerCluster{}, &models.Application{}} {
+		if err := s.db.WithContext(ctx).Model(model).Where("tenant_id = ?", id).Update("tenant_id", 0).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Unscoped().Delete(&models.Tenant{}, id).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateTenant(ctx context.Context, id uint, json types.UpdateTenantRequest) (*models.Tenant, error) {
+	var quota map[string]any
+	if json.Quota != nil {
+		var err error
+		if quota, err = structure.StructToMap(json.Quota); err != nil {
+			return nil, err
+		}
+	}
+
+	tenant := models.Tenant{}
+	if err := s.db.WithContext(ctx).First(&tenant, id).Updates(models.Tenant{
+		Name:   json.Name,
+		BIO:    json.BIO,
+		Quota:  quota,
+		UserID: json.UserID,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+func (s *service) GetTenant(ctx context.Context, id uint) (*models.Tenant, error) {
+	tenant := models.Tenant{}
+	if err := s.db.WithContext(ctx).First(&tenant, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+func (s *service) GetTenants(ctx context.Context, q types.GetTenantsQuery) ([]models.Tenant, int64, error) {
+	var count int64
+	var tenants []models.Tenant
+	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.Tenant{
+		Name:   q.Name,
+		UserID: q.UserID,
+	}).Find(&tenants).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return tenants, count, nil
+}
+
+func (s *service) GetTenantUsage(ctx context.Context, id uint) (*types.TenantUsage, error) {
+	tenant := models.Tenant{}
+	if err := s.db.WithContext(ctx).First(&tenant, id).Error; err != nil {
+		return nil, err
+	}
+
+	return findTenantUsage(ctx, s.db, tenant, s.config.Job.Preheat.TaskTTL)
+}
+
+// findTenantUsage returns the usage of the tenant.
+func findTenantUsage(ctx context.Context, db *gorm.DB, tenant models.Tenant, taskTTL time.Duration) (*types.TenantUsage, error) {
+	usage := &types.TenantUsage{}
+	if err := structure.MapToStruct(tenant.Quota, &usage.Quota); err != nil {
+		return nil, err
+	}
+
+	var concurrentPreheats int64
+	if err := db.WithContext(ctx).Model(&models.Job{}).Where("tenant_id = ? AND type = ? AND state NOT IN ?", tenant.ID, internaljob.PreheatJob,
+		[]string{machineryv1tasks.StateSuccess, machineryv1tasks.StateFailure}).Count(&concurrentPreheats).Error; err != nil {
+		return nil, err
+	}
+	usage.ConcurrentPreheats = uint32(concurrentPreheats)
+
+	// Storage of seed peers is consumed by the tasks preheated for the tenant, which are
+	// neither deleted nor expired in the seed peers.
+	var seedPeerStorageBytes int64
+	if err := db.WithContext(ctx).Model(&models.TenantTask{}).Where("tenant_id = ? AND updated_at > ?", tenant.ID, time.Now().Add(-taskTTL)).
+		Select("COALESCE(SUM(content_length), 0)").Scan(&seedPeerStorageBytes).Error; err != nil {
+		return nil, err
+	}
+	usage.SeedPeerStorageBytes = uint64(seedPeerStorageBytes)
+
+	return usage, nil
+}
+
+// checkTenantPreheatQuota checks the preheat quotas of the tenant in the transaction,
+// the tenant is locked until the transaction is committed or rolled back.
+func checkTenantPreheatQuota(ctx context.Context, tx *gorm.DB, tenantID uint, taskTTL time.Duration) error {
+	tenant := models.Tenant{}
+	if err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&tenant, tenantID).Error; err != nil {
+		return err
+	}
+
+	usage, err := findTenantUsage(ctx, tx, tenant, taskTTL)
+	if err != nil {
+		return err
+	}
+
+	if usage.Quota.ConcurrentPreheats > 0 && usage.ConcurrentPreheats >= usage.Quota.ConcurrentPreheats {
+		return fmt.Errorf("tenant %d exceeds the quota of concurrent preheats %d", tenantID, usage.Quota.ConcurrentPreheats)
+	}
+
+	if usage.Quota.SeedPeerStorageBytes > 0 && usage.SeedPeerStorageBytes >= usage.Quota.SeedPeerStorageBytes {
+		return fmt.Errorf("tenant %d exceeds the quota of seed peer storage %d bytes", tenantID, usage.Quota.SeedPeerStorageBytes)
+	}
+
+	return nil
+}
+
+// findTenantSchedulerClusters returns the tenant and the scheduler clusters to preheat. The tenant is
+// derived from the scheduler clusters when it is not specified, so the quotas of tenant can not be bypassed
+// by omitting the tenant. If the scheduler clusters are not specified, all of the scheduler clusters
+// of the tenant are returned, and the scheduler clusters without tenant are returned for no tenant.
+func (s *service) findTenantSchedulerClusters(ctx context.Context, tenantID uint, schedulerClusterIDs []uint) (uint, []uint, error) {
+	if len(schedulerClusterIDs) == 0 {
+		var tenantSchedulerClusterIDs []uint
+		if err := s.db.WithContext(ctx).Model(&models.SchedulerCluster{}).Where("tenant_id = ?", tenantID).
+			Pluck("id", &tenantSchedulerClusterIDs).Error; err != nil {
+			return 0, nil, err
+		}
+
+		if len(tenantSchedulerClusterIDs) == 0 {
+			if tenantID == 0 {
+				return 0, nil, errors.New("no scheduler clusters without tenant")
+			}
+
+			return 0, nil, fmt.Errorf("tenant %d has no scheduler clusters", tenantID)
+		}
+
+		return tenantID, tenantSchedulerClusterIDs, nil
+	}
+
+	var schedulerClusters []models.SchedulerCluster
+	if err := s.db.WithContext(ctx).Find(&schedulerClusters, schedulerClusterIDs).Error; err != nil {
+		return 0, nil, err
+	}
+
+	if len(schedulerClusters) == 0 {
+		return 0, nil, fmt.Errorf("scheduler clusters %v not found", schedulerClusterIDs)
+	}
+
+	derivedTenantID := schedulerClusters[0].TenantID
+	for _, schedulerCluster := range schedulerClusters {
+		if schedulerCluster.TenantID != derivedTenantID {
+			return 0, nil, fmt.Errorf("scheduler clusters %v belong to different tenants", schedulerClusterIDs)
+		}
+	}
+
+	if tenantID != 0 && tenantID != derivedTenantID {
+		return 0, nil, fmt.Errorf("scheduler clusters %v do not belong to tenant %d", schedulerClusterIDs, tenantID)
+	}
+
+	return derivedTenantID, schedulerClusterIDs, nil
+}
+
+// updateTenantTasks updates the tasks counted in the seed peer storage of tenant by the succeeded job.
+// The tasks preheated by the preheat job are added to the tenant, repeated preheats of the same task
+// only refresh the task, and the task deleted by the delete task job is removed from all of the tenants.
+func (s *service) updateTenantTasks(ctx context.Context, job *models.Job, groupJob *internaljob.GroupJobState) error {
+	switch job.Type {
+	case internaljob.PreheatJob:
+		if job.TenantID == 0 {
+			return nil
+		}
+
+		// Remove the expired tasks of tenant, they have been reclaimed by the seed peers.
+		if err := s.db.WithContext(ctx).Unscoped().Where("tenant_id = ? AND updated_at <= ?", job.TenantID,
+			time.Now().Add(-s.config.Job.Preheat.TaskTTL)).Delete(&models.TenantTask{}).Error; err != nil {
+			return err
+		}
+
+		contentLengths := make(map[string]int64)
+		for _, taskState := range groupJob.JobStates {
+			resp, err := unmarshalPreheatResponse(taskState)
+			if err != nil || resp.TaskID == "" || resp.ContentLength < 0 {
+				continue
+			}
+
+			// The same task may be preheated by the seed peers in different clusters.
+			if contentLength, ok := contentLengths[resp.TaskID]; !ok || resp.ContentLength > contentLength {
+				contentLengths[resp.TaskID] = resp.ContentLength
+			}
+		}
+
+		if len(contentLengths) == 0 {
+			return nil
+		}
+
+		tenantTasks := make([]models.TenantTask, 0, len(contentLengths))
+		for taskID, contentLength := range contentLengths {
+			tenantTasks = append(tenantTasks, models.TenantTask{
+				TenantID:      job.TenantID,
+				TaskID:        taskID,
+				ContentLength: contentLength,
+			})
+		}
+
+		return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "task_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"content_length", "updated_at"}),
+		}).Create(&tenantTasks).Error
+	case internaljob.DeleteTaskJob:
+		var args types.DeleteTaskArgs
+		if err := structure.MapToStruct(job.Args, &args); err != nil {
+			return err
+		}
+
+		return s.db.WithContext(ctx).Unscoped().Where("task_id = ?", types.MakeTaskIDOfDeleteTaskArgs(args)).
+			Delete(&models.TenantTask{}).Error
+	default:
+		return nil
+	}
+}
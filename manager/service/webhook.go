@@ -0,0 +1,106 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/manager/webhook"
+)
+
+func (s *service) CreateWebhook(ctx context.Context, json types.CreateWebhookRequest) (*models.Webhook, error) {
+	webhook := models.Webhook{
+		Name:   json.Name,
+		BIO:    json.BIO,
+		URL:    json.URL,
+		Secret: json.Secret,
+		Events: json.Events,
+		State:  models.WebhookStateActive,
+		UserID: json.UserID,
+	}
+
+	if err := s.db.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (s *service) DestroyWebhook(ctx context.Context, id uint) error {
+	webhook := models.Webhook{}
+	if err := s.db.WithContext(ctx).First(&webhook, id).Error; err != nil {
+		return err
+	}
+
+	if err := s.db.WithContext(ctx).Unscoped().Delete(&models.Webhook{}, id).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *service) UpdateWebhook(ctx context.Context, id uint, json types.UpdateWebhookRequest) (*models.Webhook, error) {
+	webhook := models.Webhook{}
+	if err := s.db.WithContext(ctx).Preload("User").First(&webhook, id).Updates(models.Webhook{
+		BIO:    json.BIO,
+		URL:    json.URL,
+		Secret: json.Secret,
+		Events: json.Events,
+		State:  json.State,
+		UserID: json.UserID,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (s *service) GetWebhook(ctx context.Context, id uint) (*models.Webhook, error) {
+	webhook := models.Webhook{}
+	if err := s.db.WithContext(ctx).Preload("User").First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (s *service) GetWebhooks(ctx context.Context, q types.GetWebhooksQuery) ([]models.Webhook, int64, error) {
+	var count int64
+	var webhooks []models.Webhook
+	if err := s.db.WithContext(ctx).Scopes(models.Paginate(q.Page, q.PerPage)).Where(&models.Webhook{
+		Name:  q.Name,
+		State: q.State,
+	}).Preload("User").Find(&webhooks).Limit(-1).Offset(-1).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return webhooks, count, nil
+}
+
+func (s *service) PingWebhook(ctx context.Context, id uint) error {
+	w := models.Webhook{}
+	if err := s.db.WithContext(ctx).First(&w, id).Error; err != nil {
+		return err
+	}
+
+	return s.webhook.Deliver(ctx, &w, webhook.NewEvent(webhook.EventPing, map[string]any{
+		"webhook_id": w.ID,
+		"name":       w.Name,
+	}))
+}
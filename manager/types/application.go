@@ -21,19 +21,31 @@ type ApplicationParams struct {
 }
 
 type CreateApplicationRequest struct {
-	Name     string          `json:"name" binding:"required"`
-	URL      string          `json:"url" binding:"required"`
-	BIO      string          `json:"bio" binding:"omitempty"`
-	Priority *PriorityConfig `json:"priority" binding:"required"`
-	UserID   uint            `json:"user_id" binding:"required"`
+	Name                    string          `json:"name" binding:"required"`
+	URL                     string          `json:"url" binding:"required"`
+	BIO                     string          `json:"bio" binding:"omitempty"`
+	Priority                *PriorityConfig `json:"priority" binding:"required"`
+	AntiAffinity            []string        `json:"anti_affinity" binding:"omitempty,dive,oneof=region zone rack switch hypervisor"`
+	RateLimit               uint64          `json:"rate_limit" binding:"omitempty"`
+	AllowedURLRegex         string          `json:"allowed_url_regex" binding:"omitempty"`
+	BackToSourceConcurrency uint32          `json:"back_to_source_concurrency" binding:"omitempty"`
+	RequireSignature        bool            `json:"require_signature" binding:"omitempty"`
+	TenantID                uint            `json:"tenant_id" binding:"omitempty"`
+	UserID                  uint            `json:"user_id" binding:"required"`
 }
 
 type UpdateApplicationRequest struct {
-	Name     string          `json:"name" binding:"omitempty"`
-	URL      string          `json:"url" binding:"omitempty"`
-	BIO      string          `json:"bio" binding:"omitempty"`
-	Priority *PriorityConfig `json:"priority" binding:"omitempty"`
-	UserID   uint            `json:"user_id" binding:"required"`
+	Name                    string          `json:"name" binding:"omitempty"`
+	URL                     string          `json:"url" binding:"omitempty"`
+	BIO                     string          `json:"bio" binding:"omitempty"`
+	Priority                *PriorityConfig `json:"priority" binding:"omitempty"`
+	AntiAffinity            []string        `json:"anti_affinity" binding:"omitempty,dive,oneof=region zone rack switch hypervisor"`
+	RateLimit               *uint64         `json:"rate_limit" binding:"omitempty"`
+	AllowedURLRegex         *string         `json:"allowed_url_regex" binding:"omitempty"`
+	BackToSourceConcurrency *uint32         `json:"back_to_source_concurrency" binding:"omitempty"`
+	RequireSignature        *bool           `json:"require_signature" binding:"omitempty"`
+	TenantID                uint            `json:"tenant_id" binding:"omitempty"`
+	UserID                  uint            `json:"user_id" binding:"required"`
 }
 
 type GetApplicationsQuery struct {
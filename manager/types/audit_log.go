@@ -0,0 +1,53 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import "time"
+
+const (
+	// AuditLogExportFormatCSV is the csv format of exporting audit logs.
+	AuditLogExportFormatCSV = "csv"
+
+	// AuditLogExportFormatJSON is the json format of exporting audit logs.
+	AuditLogExportFormatJSON = "json"
+)
+
+type AuditLogParams struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type AuditLogFilter struct {
+	UserID     uint      `form:"user_id" binding:"omitempty"`
+	UserName   string    `form:"user_name" binding:"omitempty"`
+	IP         string    `form:"ip" binding:"omitempty"`
+	Resource   string    `form:"resource" binding:"omitempty"`
+	ResourceID string    `form:"resource_id" binding:"omitempty"`
+	Action     string    `form:"action" binding:"omitempty,oneof=create update delete"`
+	StartTime  time.Time `form:"start_time" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+	EndTime    time.Time `form:"end_time" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"`
+}
+
+type GetAuditLogsQuery struct {
+	AuditLogFilter
+	Page    int `form:"page" binding:"omitempty,gte=1"`
+	PerPage int `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+}
+
+type ExportAuditLogsQuery struct {
+	AuditLogFilter
+	Format string `form:"format" binding:"omitempty,oneof=csv json"`
+}
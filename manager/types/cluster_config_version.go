@@ -0,0 +1,48 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+type ClusterConfigVersionParams struct {
+	ID      uint `uri:"id" binding:"required"`
+	Version uint `uri:"version" binding:"required"`
+}
+
+type GetClusterConfigVersionsQuery struct {
+	Page    int `form:"page" binding:"omitempty,gte=1"`
+	PerPage int `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+}
+
+type GetClusterConfigVersionDiffQuery struct {
+	// Base is the version compared with, default is the previous version.
+	Base uint `form:"base" binding:"omitempty,gte=1"`
+}
+
+type ClusterConfigVersionDiff struct {
+	// BaseVersion is the version compared with, zero means empty config.
+	BaseVersion uint `json:"base_version"`
+
+	// Version is the compared version.
+	Version uint `json:"version"`
+
+	// Changes is the changed fields, key is the field path joined by dot.
+	Changes map[string]ClusterConfigChange `json:"changes"`
+}
+
+type ClusterConfigChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
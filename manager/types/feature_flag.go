@@ -0,0 +1,44 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+type CreateFeatureFlagRequest struct {
+	Name               string `json:"name" binding:"required,max=256"`
+	BIO                string `json:"bio" binding:"omitempty"`
+	Percentage         *uint  `json:"percentage" binding:"omitempty,lte=100"`
+	SchedulerClusterID uint   `json:"scheduler_cluster_id" binding:"required"`
+	UserID             uint   `json:"user_id" binding:"omitempty"`
+}
+
+type UpdateFeatureFlagRequest struct {
+	BIO        string `json:"bio" binding:"omitempty"`
+	State      string `json:"state" binding:"omitempty,oneof=active inactive"`
+	Percentage *uint  `json:"percentage" binding:"omitempty,lte=100"`
+	UserID     uint   `json:"user_id" binding:"omitempty"`
+}
+
+type FeatureFlagParams struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type GetFeatureFlagsQuery struct {
+	Name               string `form:"name" binding:"omitempty"`
+	State              string `form:"state" binding:"omitempty,oneof=active inactive"`
+	SchedulerClusterID uint   `form:"scheduler_cluster_id" binding:"omitempty"`
+	Page               int    `form:"page" binding:"omitempty,gte=1"`
+	PerPage            int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+}
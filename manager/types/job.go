@@ -16,12 +16,22 @@
 
 package types
 
+import (
+	"time"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	pkgtypes "d7y.io/dragonfly/v2/pkg/types"
+)
+
 type CreateJobRequest struct {
 	BIO                 string         `json:"bio" binding:"omitempty"`
 	Type                string         `json:"type" binding:"required"`
 	Args                map[string]any `json:"args" binding:"omitempty"`
 	Result              map[string]any `json:"result" binding:"omitempty"`
 	UserID              uint           `json:"user_id" binding:"omitempty"`
+	TenantID            uint           `json:"tenant_id" binding:"omitempty"`
 	SeedPeerClusterIDs  []uint         `json:"seed_peer_cluster_ids" binding:"omitempty"`
 	SchedulerClusterIDs []uint         `json:"scheduler_cluster_ids" binding:"omitempty"`
 }
@@ -36,11 +46,12 @@ type JobParams struct {
 }
 
 type GetJobsQuery struct {
-	Type    string `form:"type" binding:"omitempty"`
-	State   string `form:"state" binding:"omitempty,oneof=PENDING RECEIVED STARTED RETRY SUCCESS FAILURE"`
-	UserID  uint   `form:"user_id" binding:"omitempty"`
-	Page    int    `form:"page" binding:"omitempty,gte=1"`
-	PerPage int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+	Type     string `form:"type" binding:"omitempty"`
+	State    string `form:"state" binding:"omitempty,oneof=PENDING RECEIVED STARTED RETRY SUCCESS FAILURE"`
+	UserID   uint   `form:"user_id" binding:"omitempty"`
+	TenantID uint   `form:"tenant_id" binding:"omitempty"`
+	Page     int    `form:"page" binding:"omitempty,gte=1"`
+	PerPage  int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
 }
 
 type CreatePreheatJobRequest struct {
@@ -49,13 +60,163 @@ type CreatePreheatJobRequest struct {
 	Args                PreheatArgs    `json:"args" binding:"omitempty"`
 	Result              map[string]any `json:"result" binding:"omitempty"`
 	UserID              uint           `json:"user_id" binding:"omitempty"`
+	TenantID            uint           `json:"tenant_id" binding:"omitempty"`
 	SchedulerClusterIDs []uint         `json:"scheduler_cluster_ids" binding:"omitempty"`
 }
 
 type PreheatArgs struct {
-	Type    string            `json:"type" binding:"required,oneof=image file"`
-	URL     string            `json:"url" binding:"required"`
-	Tag     string            `json:"tag" binding:"omitempty"`
-	Filter  string            `json:"filter" binding:"omitempty"`
-	Headers map[string]string `json:"headers" binding:"omitempty"`
+	Type      string            `json:"type" binding:"required,oneof=image file"`
+	URL       string            `json:"url" binding:"required"`
+	Tag       string            `json:"tag" binding:"omitempty"`
+	Filter    string            `json:"filter" binding:"omitempty"`
+	Headers   map[string]string `json:"headers" binding:"omitempty"`
+	Platforms []string          `json:"platforms" binding:"omitempty"`
+}
+
+type CreateDrainHostJobRequest struct {
+	BIO                 string         `json:"bio" binding:"omitempty"`
+	Type                string         `json:"type" binding:"required"`
+	Args                DrainHostArgs  `json:"args" binding:"required"`
+	Result              map[string]any `json:"result" binding:"omitempty"`
+	UserID              uint           `json:"user_id" binding:"omitempty"`
+	SchedulerClusterIDs []uint         `json:"scheduler_cluster_ids" binding:"omitempty"`
+}
+
+type DrainHostArgs struct {
+	HostID string `json:"host_id" binding:"required"`
+	Cancel bool   `json:"cancel" binding:"omitempty"`
+}
+
+type CreateReannounceHostJobRequest struct {
+	BIO                 string             `json:"bio" binding:"omitempty"`
+	Type                string             `json:"type" binding:"required"`
+	Args                ReannounceHostArgs `json:"args" binding:"required"`
+	Result              map[string]any     `json:"result" binding:"omitempty"`
+	UserID              uint               `json:"user_id" binding:"omitempty"`
+	SchedulerClusterIDs []uint             `json:"scheduler_cluster_ids" binding:"omitempty"`
+}
+
+type ReannounceHostArgs struct {
+	HostID string `json:"host_id" binding:"required"`
+}
+
+type CreateHostReputationJobRequest struct {
+	BIO                 string             `json:"bio" binding:"omitempty"`
+	Type                string             `json:"type" binding:"required"`
+	Args                HostReputationArgs `json:"args" binding:"required"`
+	Result              map[string]any     `json:"result" binding:"omitempty"`
+	UserID              uint               `json:"user_id" binding:"omitempty"`
+	SchedulerClusterIDs []uint             `json:"scheduler_cluster_ids" binding:"omitempty"`
+}
+
+type HostReputationArgs struct {
+	HostID string `json:"host_id" binding:"required"`
+	Action string `json:"action" binding:"omitempty,oneof=block unblock"`
+}
+
+type CreateDeleteTaskJobRequest struct {
+	BIO                 string         `json:"bio" binding:"omitempty"`
+	Type                string         `json:"type" binding:"required"`
+	Args                DeleteTaskArgs `json:"args" binding:"required"`
+	Result              map[string]any `json:"result" binding:"omitempty"`
+	UserID              uint           `json:"user_id" binding:"omitempty"`
+	SchedulerClusterIDs []uint         `json:"scheduler_cluster_ids" binding:"omitempty"`
+}
+
+type DeleteTaskArgs struct {
+	TaskID      string `json:"task_id" binding:"required_without=URL"`
+	URL         string `json:"url" binding:"required_without=TaskID"`
+	Tag         string `json:"tag" binding:"omitempty"`
+	Application string `json:"application" binding:"omitempty"`
+	Filter      string `json:"filter" binding:"omitempty"`
+	Digest      string `json:"digest" binding:"omitempty"`
+}
+
+// MakeTaskIDOfDeleteTaskArgs returns the id of the task to delete, it is generated
+// from the url and meta of task when the task id is not specified.
+func MakeTaskIDOfDeleteTaskArgs(args DeleteTaskArgs) string {
+	if args.TaskID != "" {
+		return args.TaskID
+	}
+
+	return idgen.TaskIDV1(args.URL, &commonv1.UrlMeta{
+		Digest:      args.Digest,
+		Tag:         args.Tag,
+		Filter:      args.Filter,
+		Application: args.Application,
+	})
+}
+
+type CreateGetTaskJobRequest struct {
+	BIO                 string         `json:"bio" binding:"omitempty"`
+	Type                string         `json:"type" binding:"required"`
+	Args                GetTaskArgs    `json:"args" binding:"required"`
+	Result              map[string]any `json:"result" binding:"omitempty"`
+	UserID              uint           `json:"user_id" binding:"omitempty"`
+	SchedulerClusterIDs []uint         `json:"scheduler_cluster_ids" binding:"omitempty"`
+}
+
+type GetTaskArgs struct {
+	TaskID      string `json:"task_id" binding:"required_without=URL"`
+	URL         string `json:"url" binding:"required_without=TaskID"`
+	Tag         string `json:"tag" binding:"omitempty"`
+	Application string `json:"application" binding:"omitempty"`
+	Filter      string `json:"filter" binding:"omitempty"`
+	Digest      string `json:"digest" binding:"omitempty"`
+}
+
+type GetTaskJobResult struct {
+	ID              uint       `json:"id"`
+	State           string     `json:"state"`
+	TaskID          string     `json:"task_id"`
+	URL             string     `json:"url,omitempty"`
+	ContentLength   int64      `json:"content_length,omitempty"`
+	TotalPieceCount int32      `json:"total_piece_count,omitempty"`
+	SeedPeers       []TaskPeer `json:"seed_peers"`
+	Peers           []TaskPeer `json:"peers"`
+
+	// Replication is the replication policy of the persistent cache task.
+	Replication *pkgtypes.Replication `json:"replication,omitempty"`
+
+	// Placement is the replica count of the persistent cache task in each failure domain.
+	Placement map[string]int `json:"placement,omitempty"`
+}
+
+type TaskPeer struct {
+	ID                 string            `json:"id"`
+	State              string            `json:"state"`
+	FinishedPieceCount uint              `json:"finished_piece_count"`
+	HostID             string            `json:"host_id"`
+	HostType           string            `json:"host_type"`
+	Hostname           string            `json:"hostname"`
+	IP                 string            `json:"ip"`
+	Port               int32             `json:"port"`
+	SchedulerHostname  string            `json:"scheduler_hostname"`
+	SchedulerClusterID uint              `json:"scheduler_cluster_id"`
+	Topology           pkgtypes.Topology `json:"topology"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+type PreheatJobProgress struct {
+	ID             uint                   `json:"id"`
+	State          string                 `json:"state"`
+	TotalCount     int                    `json:"total_count"`
+	SucceededCount int                    `json:"succeeded_count"`
+	FailedCount    int                    `json:"failed_count"`
+	PendingCount   int                    `json:"pending_count"`
+	Layers         []PreheatLayerProgress `json:"layers"`
+}
+
+type PreheatLayerProgress struct {
+	TaskUUID           string `json:"task_uuid"`
+	State              string `json:"state"`
+	URL                string `json:"url,omitempty"`
+	TaskID             string `json:"task_id,omitempty"`
+	SeedPeerHostID     string `json:"seed_peer_host_id,omitempty"`
+	SeedPeerID         string `json:"seed_peer_id,omitempty"`
+	ContentLength      int64  `json:"content_length,omitempty"`
+	TotalPieceCount    int32  `json:"total_piece_count,omitempty"`
+	FinishedPieceCount int32  `json:"finished_piece_count,omitempty"`
+	Error              string `json:"error,omitempty"`
 }
@@ -18,6 +18,10 @@ package types
 
 import (
 	"fmt"
+
+	inferencev1 "d7y.io/api/v2/pkg/apis/inference/v1"
+
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 const (
@@ -72,3 +76,19 @@ func MakeObjectKeyOfModelFile(id string, version int) string {
 func MakeObjectKeyOfModelConfigFile(id string) string {
 	return fmt.Sprintf("%s/%s", id, ModelConfigFileName)
 }
+
+// GNNModelInputs returns the inputs of GNN model, the node and edge counts are variable.
+func GNNModelInputs() []*inferencev1.ModelInput {
+	return []*inferencev1.ModelInput{
+		{Name: types.GNNNodeFeaturesName, DataType: inferencev1.DataType_TYPE_FP32, Dims: []int64{-1, types.GNNNodeFeatureCount}},
+		{Name: types.GNNEdgeIndexName, DataType: inferencev1.DataType_TYPE_INT64, Dims: []int64{2, -1}},
+		{Name: types.GNNEdgeFeaturesName, DataType: inferencev1.DataType_TYPE_FP32, Dims: []int64{-1, types.GNNEdgeFeatureCount}},
+	}
+}
+
+// GNNModelOutputs returns the outputs of GNN model.
+func GNNModelOutputs() []*inferencev1.ModelOutput {
+	return []*inferencev1.ModelOutput{
+		{Name: types.GNNScoresName, DataType: inferencev1.DataType_TYPE_FP32, Dims: []int64{-1}},
+	}
+}
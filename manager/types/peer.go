@@ -16,6 +16,26 @@
 
 package types
 
+const (
+	// BatchPeersActionTag adds the tags to the peers.
+	BatchPeersActionTag = "tag"
+
+	// BatchPeersActionUntag removes the tags from the peers.
+	BatchPeersActionUntag = "untag"
+
+	// BatchPeersActionDrain drains the peers.
+	BatchPeersActionDrain = "drain"
+
+	// BatchPeersActionUndrain cancels draining the peers.
+	BatchPeersActionUndrain = "undrain"
+
+	// BatchPeersActionReannounce forces the peers to reannounce to the schedulers.
+	BatchPeersActionReannounce = "reannounce"
+
+	// BatchPeersActionDelete deletes the peer records.
+	BatchPeersActionDelete = "delete"
+)
+
 type PeerParams struct {
 	ID uint `uri:"id" binding:"required"`
 }
@@ -60,6 +80,24 @@ type GetPeersQuery struct {
 	GitCommit          string `form:"git_commit" binding:"omitempty"`
 	BuildPlatform      string `form:"build_platform" binding:"omitempty"`
 	SchedulerClusterID uint   `form:"scheduler_cluster_id" binding:"omitempty"`
+	Tag                string `form:"tag" binding:"omitempty"`
 	Page               int    `form:"page" binding:"omitempty,gte=1"`
 	PerPage            int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
 }
+
+type BatchPeersRequest struct {
+	Action string   `json:"action" binding:"required,oneof=tag untag drain undrain reannounce delete"`
+	IDs    []uint   `json:"ids" binding:"required,min=1,max=1000"`
+	Tags   []string `json:"tags" binding:"required_if=Action tag,required_if=Action untag,dive,required"`
+	UserID uint     `json:"user_id" binding:"omitempty"`
+}
+
+type BatchPeersResponse struct {
+	Results []BatchPeerResult `json:"results"`
+}
+
+type BatchPeerResult struct {
+	ID    uint   `json:"id"`
+	JobID uint   `json:"job_id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,52 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+type CreatePreheatScheduleRequest struct {
+	Name                string      `json:"name" binding:"required"`
+	BIO                 string      `json:"bio" binding:"omitempty"`
+	Cron                string      `json:"cron" binding:"required"`
+	Args                PreheatArgs `json:"args" binding:"required"`
+	UserID              uint        `json:"user_id" binding:"omitempty"`
+	SchedulerClusterIDs []uint      `json:"scheduler_cluster_ids" binding:"omitempty"`
+}
+
+type UpdatePreheatScheduleRequest struct {
+	BIO                 string       `json:"bio" binding:"omitempty"`
+	Cron                string       `json:"cron" binding:"omitempty"`
+	Args                *PreheatArgs `json:"args" binding:"omitempty"`
+	UserID              uint         `json:"user_id" binding:"omitempty"`
+	SchedulerClusterIDs []uint       `json:"scheduler_cluster_ids" binding:"omitempty"`
+}
+
+type PreheatScheduleParams struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type GetPreheatSchedulesQuery struct {
+	Name    string `form:"name" binding:"omitempty"`
+	State   string `form:"state" binding:"omitempty,oneof=active inactive"`
+	UserID  uint   `form:"user_id" binding:"omitempty"`
+	Page    int    `form:"page" binding:"omitempty,gte=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+}
+
+type GetPreheatScheduleJobsQuery struct {
+	State   string `form:"state" binding:"omitempty,oneof=PENDING RECEIVED STARTED RETRY SUCCESS FAILURE"`
+	Page    int    `form:"page" binding:"omitempty,gte=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+}
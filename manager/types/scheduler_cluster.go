@@ -16,6 +16,24 @@
 
 package types
 
+import (
+	pkgtypes "d7y.io/dragonfly/v2/pkg/types"
+)
+
+var (
+	// DefaultSchedulerClusterEvaluatorWeights is the evaluator weights of the default scheduler cluster,
+	// the free bandwidth is weighted to prefer the parents with spare bandwidth.
+	DefaultSchedulerClusterEvaluatorWeights = SchedulerClusterEvaluatorWeights{
+		FinishedPiece:           0.2,
+		ParentHostUploadSuccess: 0.2,
+		FreeUpload:              0.1,
+		FreeBandwidth:           0.1,
+		HostType:                0.1,
+		IDCAffinity:             0.15,
+		LocationAffinity:        0.15,
+	}
+)
+
 type SchedulerClusterParams struct {
 	ID uint `uri:"id" binding:"required"`
 }
@@ -33,6 +51,7 @@ type CreateSchedulerClusterRequest struct {
 	Scopes            *SchedulerClusterScopes       `json:"scopes" binding:"omitempty"`
 	IsDefault         bool                          `json:"is_default" binding:"omitempty"`
 	SeedPeerClusterID uint                          `json:"seed_peer_cluster_id" binding:"omitempty"`
+	TenantID          uint                          `json:"tenant_id" binding:"omitempty"`
 }
 
 type UpdateSchedulerClusterRequest struct {
@@ -43,6 +62,7 @@ type UpdateSchedulerClusterRequest struct {
 	Scopes            *SchedulerClusterScopes       `json:"scopes" binding:"omitempty"`
 	IsDefault         bool                          `json:"is_default" binding:"omitempty"`
 	SeedPeerClusterID uint                          `json:"seed_peer_cluster_id" binding:"omitempty"`
+	TenantID          uint                          `json:"tenant_id" binding:"omitempty"`
 }
 
 type GetSchedulerClustersQuery struct {
@@ -52,17 +72,39 @@ type GetSchedulerClustersQuery struct {
 }
 
 type SchedulerClusterConfig struct {
-	CandidateParentLimit uint32 `yaml:"candidateParentLimit" mapstructure:"candidateParentLimit" json:"candidate_parent_limit" binding:"omitempty,gte=1,lte=20"`
-	FilterParentLimit    uint32 `yaml:"filterParentLimit" mapstructure:"filterParentLimit" json:"filter_parent_limit" binding:"omitempty,gte=10,lte=1000"`
+	CandidateParentLimit    uint32                            `yaml:"candidateParentLimit" mapstructure:"candidateParentLimit" json:"candidate_parent_limit" binding:"omitempty,gte=1,lte=20"`
+	FilterParentLimit       uint32                            `yaml:"filterParentLimit" mapstructure:"filterParentLimit" json:"filter_parent_limit" binding:"omitempty,gte=10,lte=1000"`
+	BackToSourceTaskLimit   uint32                            `yaml:"backToSourceTaskLimit" mapstructure:"backToSourceTaskLimit" json:"back_to_source_task_limit" binding:"omitempty,gte=1,lte=1000"`
+	BackToSourceOriginLimit uint32                            `yaml:"backToSourceOriginLimit" mapstructure:"backToSourceOriginLimit" json:"back_to_source_origin_limit" binding:"omitempty,gte=1,lte=10000"`
+	BackToSourceOriginQPS   uint32                            `yaml:"backToSourceOriginQPS" mapstructure:"backToSourceOriginQPS" json:"back_to_source_origin_qps" binding:"omitempty,gte=1"`
+	EvaluatorWeights        *SchedulerClusterEvaluatorWeights `yaml:"evaluatorWeights" mapstructure:"evaluatorWeights" json:"evaluator_weights" binding:"omitempty"`
+}
+
+type SchedulerClusterEvaluatorWeights struct {
+	FinishedPiece           float64 `yaml:"finishedPiece" mapstructure:"finishedPiece" json:"finished_piece" binding:"omitempty,gte=0,lte=1"`
+	ParentHostUploadSuccess float64 `yaml:"parentHostUploadSuccess" mapstructure:"parentHostUploadSuccess" json:"parent_host_upload_success" binding:"omitempty,gte=0,lte=1"`
+	FreeUpload              float64 `yaml:"freeUpload" mapstructure:"freeUpload" json:"free_upload" binding:"omitempty,gte=0,lte=1"`
+	FreeBandwidth           float64 `yaml:"freeBandwidth" mapstructure:"freeBandwidth" json:"free_bandwidth" binding:"omitempty,gte=0,lte=1"`
+	HostType                float64 `yaml:"hostType" mapstructure:"hostType" json:"host_type" binding:"omitempty,gte=0,lte=1"`
+	IDCAffinity             float64 `yaml:"idcAffinity" mapstructure:"idcAffinity" json:"idc_affinity" binding:"omitempty,gte=0,lte=1"`
+	LocationAffinity        float64 `yaml:"locationAffinity" mapstructure:"locationAffinity" json:"location_affinity" binding:"omitempty,gte=0,lte=1"`
+	TopologyAffinity        float64 `yaml:"topologyAffinity" mapstructure:"topologyAffinity" json:"topology_affinity" binding:"omitempty,gte=0,lte=1"`
 }
 
 type SchedulerClusterClientConfig struct {
-	LoadLimit            uint32 `yaml:"loadLimit" mapstructure:"loadLimit" json:"load_limit" binding:"omitempty,gte=1,lte=2000"`
-	ConcurrentPieceCount uint32 `yaml:"concurrentPieceCount" mapstructure:"concurrentPieceCount" json:"concurrent_piece_count" binding:"omitempty,gte=1,lte=50"`
+	LoadLimit             uint32                     `yaml:"loadLimit" mapstructure:"loadLimit" json:"load_limit" binding:"omitempty,gte=1,lte=2000"`
+	ConcurrentPieceCount  uint32                     `yaml:"concurrentPieceCount" mapstructure:"concurrentPieceCount" json:"concurrent_piece_count" binding:"omitempty,gte=1,lte=50"`
+	PieceDigestAlgorithm  string                     `yaml:"pieceDigestAlgorithm" mapstructure:"pieceDigestAlgorithm" json:"piece_digest_algorithm" binding:"omitempty,oneof=md5 xxh3 blake3"`
+	BandwidthPolicies     []pkgtypes.BandwidthPolicy `yaml:"bandwidthPolicies" mapstructure:"bandwidthPolicies" json:"bandwidth_policies" binding:"omitempty,dive"`
+	PieceEncryption       string                     `yaml:"pieceEncryption" mapstructure:"pieceEncryption" json:"piece_encryption" binding:"omitempty,oneof=disable prefer require"`
+	URLPolicy             *pkgtypes.URLPolicy        `yaml:"urlPolicy" mapstructure:"urlPolicy" json:"url_policy" binding:"omitempty"`
+	RequestTimeout        string                     `yaml:"requestTimeout" mapstructure:"requestTimeout" json:"request_timeout" binding:"omitempty"`
+	MethodRequestTimeouts map[string]string          `yaml:"methodRequestTimeouts" mapstructure:"methodRequestTimeouts" json:"method_request_timeouts" binding:"omitempty"`
 }
 
 type SchedulerClusterScopes struct {
 	IDC      string   `yaml:"idc" mapstructure:"idc" json:"idc" binding:"omitempty"`
 	Location string   `yaml:"location" mapstructure:"location" json:"location" binding:"omitempty"`
 	CIDRs    []string `yaml:"cidrs" mapstructure:"cidrs" json:"cidrs" binding:"omitempty"`
+	Rule     string   `yaml:"rule" mapstructure:"rule" json:"rule" binding:"omitempty"`
 }
@@ -31,15 +31,17 @@ type AddSchedulerClusterToSeedPeerClusterParams struct {
 }
 
 type CreateSeedPeerClusterRequest struct {
-	Name   string                 `json:"name" binding:"required"`
-	BIO    string                 `json:"bio" binding:"omitempty"`
-	Config *SeedPeerClusterConfig `json:"config" binding:"required"`
+	Name     string                 `json:"name" binding:"required"`
+	BIO      string                 `json:"bio" binding:"omitempty"`
+	Config   *SeedPeerClusterConfig `json:"config" binding:"required"`
+	TenantID uint                   `json:"tenant_id" binding:"omitempty"`
 }
 
 type UpdateSeedPeerClusterRequest struct {
-	Name   string                 `json:"name" binding:"omitempty"`
-	BIO    string                 `json:"bio" binding:"omitempty"`
-	Config *SeedPeerClusterConfig `json:"config" binding:"omitempty"`
+	Name     string                 `json:"name" binding:"omitempty"`
+	BIO      string                 `json:"bio" binding:"omitempty"`
+	Config   *SeedPeerClusterConfig `json:"config" binding:"omitempty"`
+	TenantID uint                   `json:"tenant_id" binding:"omitempty"`
 }
 
 type GetSeedPeerClustersQuery struct {
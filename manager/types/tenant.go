@@ -0,0 +1,54 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+type CreateTenantRequest struct {
+	Name   string       `json:"name" binding:"required"`
+	BIO    string       `json:"bio" binding:"omitempty"`
+	Quota  *TenantQuota `json:"quota" binding:"omitempty"`
+	UserID uint         `json:"user_id" binding:"omitempty"`
+}
+
+type UpdateTenantRequest struct {
+	Name   string       `json:"name" binding:"omitempty"`
+	BIO    string       `json:"bio" binding:"omitempty"`
+	Quota  *TenantQuota `json:"quota" binding:"omitempty"`
+	UserID uint         `json:"user_id" binding:"omitempty"`
+}
+
+type TenantParams struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type GetTenantsQuery struct {
+	Name    string `form:"name" binding:"omitempty"`
+	UserID  uint   `form:"user_id" binding:"omitempty"`
+	Page    int    `form:"page" binding:"omitempty,gte=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+}
+
+type TenantQuota struct {
+	OriginQPS            uint32 `yaml:"originQPS" mapstructure:"originQPS" json:"origin_qps" binding:"omitempty,gte=1"`
+	SeedPeerStorageBytes uint64 `yaml:"seedPeerStorageBytes" mapstructure:"seedPeerStorageBytes" json:"seed_peer_storage_bytes" binding:"omitempty,gte=1"`
+	ConcurrentPreheats   uint32 `yaml:"concurrentPreheats" mapstructure:"concurrentPreheats" json:"concurrent_preheats" binding:"omitempty,gte=1"`
+}
+
+type TenantUsage struct {
+	Quota                TenantQuota `json:"quota"`
+	SeedPeerStorageBytes uint64      `json:"seed_peer_storage_bytes"`
+	ConcurrentPreheats   uint32      `json:"concurrent_preheats"`
+}
@@ -0,0 +1,46 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+type CreateWebhookRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	BIO    string   `json:"bio" binding:"omitempty"`
+	URL    string   `json:"url" binding:"required,url"`
+	Secret string   `json:"secret" binding:"omitempty"`
+	Events []string `json:"events" binding:"required,dive,oneof=preheat.succeeded preheat.failed scheduler.inactive seed_peer.inactive scheduler_cluster.config_updated seed_peer_cluster.config_updated"`
+	UserID uint     `json:"user_id" binding:"omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	BIO    string   `json:"bio" binding:"omitempty"`
+	URL    string   `json:"url" binding:"omitempty,url"`
+	Secret string   `json:"secret" binding:"omitempty"`
+	Events []string `json:"events" binding:"omitempty,dive,oneof=preheat.succeeded preheat.failed scheduler.inactive seed_peer.inactive scheduler_cluster.config_updated seed_peer_cluster.config_updated"`
+	State  string   `json:"state" binding:"omitempty,oneof=active inactive"`
+	UserID uint     `json:"user_id" binding:"omitempty"`
+}
+
+type WebhookParams struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+type GetWebhooksQuery struct {
+	Name    string `form:"name" binding:"omitempty"`
+	State   string `form:"state" binding:"omitempty,oneof=active inactive"`
+	Page    int    `form:"page" binding:"omitempty,gte=1"`
+	PerPage int    `form:"per_page" binding:"omitempty,gte=1,lte=10000000"`
+}
@@ -0,0 +1,227 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/webhook_mock.go -source webhook.go -package mocks
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/models"
+	"d7y.io/dragonfly/v2/pkg/retry"
+	"d7y.io/dragonfly/v2/pkg/slices"
+)
+
+const (
+	// EventPing is the event of testing the webhook.
+	EventPing = "ping"
+
+	// EventPreheatSucceeded is the event of the preheat job succeeded.
+	EventPreheatSucceeded = "preheat.succeeded"
+
+	// EventPreheatFailed is the event of the preheat job failed.
+	EventPreheatFailed = "preheat.failed"
+
+	// EventSchedulerInactive is the event of the scheduler going inactive.
+	EventSchedulerInactive = "scheduler.inactive"
+
+	// EventSeedPeerInactive is the event of the seed peer going inactive.
+	EventSeedPeerInactive = "seed_peer.inactive"
+
+	// EventSchedulerClusterConfigUpdated is the event of the scheduler cluster config updated.
+	EventSchedulerClusterConfigUpdated = "scheduler_cluster.config_updated"
+
+	// EventSeedPeerClusterConfigUpdated is the event of the seed peer cluster config updated.
+	EventSeedPeerClusterConfigUpdated = "seed_peer_cluster.config_updated"
+)
+
+const (
+	// HeaderEvent is the header of the event type.
+	HeaderEvent = "X-Dragonfly-Event"
+
+	// HeaderDelivery is the header of the event id.
+	HeaderDelivery = "X-Dragonfly-Delivery"
+
+	// HeaderSignature is the header of the hmac-sha256 signature of the request body,
+	// the signature is hex encoded with the prefix sha256=.
+	HeaderSignature = "X-Dragonfly-Signature-256"
+)
+
+const (
+	// defaultTimeout is the default timeout of delivering the event.
+	defaultTimeout = 10 * time.Second
+
+	// defaultRetryLimit is the default retry limit of delivering the event.
+	defaultRetryLimit = 3
+
+	// defaultRetryInitBackoff is the default initial backoff of retrying in seconds.
+	defaultRetryInitBackoff = 1
+
+	// defaultRetryMaxBackoff is the default max backoff of retrying in seconds.
+	defaultRetryMaxBackoff = 10
+)
+
+// Event is the event delivered to the webhooks.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// NewEvent returns a new event.
+func NewEvent(eventType string, data any) *Event {
+	return &Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+}
+
+// Webhook is the interface used for notifying the events to the webhooks.
+type Webhook interface {
+	// Notify delivers the event to the active webhooks subscribing the event asynchronously.
+	Notify(context.Context, string, any)
+
+	// Deliver delivers the event to the webhook.
+	Deliver(context.Context, *models.Webhook, *Event) error
+}
+
+// webhook implements Webhook.
+type webhook struct {
+	// db is the database of manager.
+	db *gorm.DB
+
+	// httpClient is the client of delivering the events.
+	httpClient *http.Client
+
+	// retryLimit is the retry limit of delivering the event.
+	retryLimit int
+}
+
+// Option is a functional option for configuring the webhook.
+type Option func(w *webhook)
+
+// WithHTTPClient sets the http client of delivering the events.
+func WithHTTPClient(client *http.Client) Option {
+	return func(w *webhook) {
+		w.httpClient = client
+	}
+}
+
+// WithRetryLimit sets the retry limit of delivering the event.
+func WithRetryLimit(retryLimit int) Option {
+	return func(w *webhook) {
+		w.retryLimit = retryLimit
+	}
+}
+
+// New returns a new Webhook interface.
+func New(db *gorm.DB, options ...Option) Webhook {
+	w := &webhook{
+		db:         db,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retryLimit: defaultRetryLimit,
+	}
+
+	for _, opt := range options {
+		opt(w)
+	}
+
+	return w
+}
+
+// Notify delivers the event to the active webhooks subscribing the event asynchronously.
+func (w *webhook) Notify(ctx context.Context, eventType string, data any) {
+	var webhooks []models.Webhook
+	if err := w.db.WithContext(ctx).Where(&models.Webhook{State: models.WebhookStateActive}).Find(&webhooks).Error; err != nil {
+		logger.Errorf("find webhooks of event %s failed: %s", eventType, err.Error())
+		return
+	}
+
+	event := NewEvent(eventType, data)
+	for _, webhook := range webhooks {
+		if !slices.Contains(webhook.Events, eventType) {
+			continue
+		}
+
+		go func(webhook models.Webhook) {
+			if err := w.Deliver(context.Background(), &webhook, event); err != nil {
+				logger.Errorf("deliver event %s %s to webhook %s failed: %s", event.Type, event.ID, webhook.Name, err.Error())
+			}
+		}(webhook)
+	}
+}
+
+// Deliver delivers the event to the webhook, it retries when the endpoint is unavailable.
+func (w *webhook) Deliver(ctx context.Context, webhook *models.Webhook, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = retry.Run(ctx, defaultRetryInitBackoff, defaultRetryMaxBackoff, w.retryLimit, func() (any, bool, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+		if err != nil {
+			return nil, true, err
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(HeaderEvent, event.Type)
+		req.Header.Set(HeaderDelivery, event.ID)
+		if webhook.Secret != "" {
+			req.Header.Set(HeaderSignature, Sign(webhook.Secret, body))
+		}
+
+		resp, err := w.httpClient.Do(req)
+		if err != nil {
+			return nil, false, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			// Client errors are not retried except too many requests.
+			cancel := resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError &&
+				resp.StatusCode != http.StatusTooManyRequests
+			return nil, cancel, fmt.Errorf("webhook %s responded with status code %d", webhook.URL, resp.StatusCode)
+		}
+
+		return nil, false, nil
+	})
+
+	return err
+}
+
+// Sign returns the hmac-sha256 signature of the body with the prefix sha256=.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,121 @@
+/*
+ *     Copyright 2020 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/manager/models"
+)
+
+func TestWebhook_Deliver(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      string
+		statusCodes []int
+		expect      func(t *testing.T, err error, requests int32)
+	}{
+		{
+			name:        "deliver event with signature",
+			secret:      "foo",
+			statusCodes: []int{http.StatusOK},
+			expect: func(t *testing.T, err error, requests int32) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(int32(1), requests)
+			},
+		},
+		{
+			name:        "deliver event without signature",
+			statusCodes: []int{http.StatusNoContent},
+			expect: func(t *testing.T, err error, requests int32) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(int32(1), requests)
+			},
+		},
+		{
+			name:        "retry when endpoint is unavailable",
+			secret:      "foo",
+			statusCodes: []int{http.StatusServiceUnavailable, http.StatusOK},
+			expect: func(t *testing.T, err error, requests int32) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(int32(2), requests)
+			},
+		},
+		{
+			name:        "do not retry when endpoint rejects event",
+			secret:      "foo",
+			statusCodes: []int{http.StatusBadRequest, http.StatusOK},
+			expect: func(t *testing.T, err error, requests int32) {
+				assert := assert.New(t)
+				assert.ErrorContains(err, "responded with status code 400")
+				assert.Equal(int32(1), requests)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			event := NewEvent(EventPing, map[string]any{"foo": "bar"})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&requests, 1)
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert := assert.New(t)
+				assert.Equal(EventPing, r.Header.Get(HeaderEvent))
+				assert.Equal(event.ID, r.Header.Get(HeaderDelivery))
+				if tc.secret != "" {
+					assert.Equal(Sign(tc.secret, body), r.Header.Get(HeaderSignature))
+				} else {
+					assert.Empty(r.Header.Get(HeaderSignature))
+				}
+
+				var e Event
+				assert.NoError(json.Unmarshal(body, &e))
+				assert.Equal(event.ID, e.ID)
+				assert.Equal(map[string]any{"foo": "bar"}, e.Data)
+
+				w.WriteHeader(tc.statusCodes[n-1])
+			}))
+			defer server.Close()
+
+			w := New(nil, WithRetryLimit(len(tc.statusCodes)))
+			err := w.Deliver(context.Background(), &models.Webhook{URL: server.URL, Secret: tc.secret}, event)
+			tc.expect(t, err, atomic.LoadInt32(&requests))
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("sha256=f9320baf0249169e73850cd6156ded0106e2bb6ad8cab01b7bbbebe6d1065317", Sign("foo", []byte("bar")))
+	assert.NotEqual(Sign("foo", []byte("bar")), Sign("baz", []byte("bar")))
+}
@@ -17,9 +17,11 @@
 package balancer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 
 	"google.golang.org/grpc/balancer"
 	"google.golang.org/grpc/balancer/base"
@@ -40,6 +42,15 @@ const (
 	// ContextKey is the key for the grpc request's context.Context which points to
 	// the key to hash for the request.
 	ContextKey = ContextKeyType("consistent-hashing-key")
+
+	// HedgingContextKey is the key for the grpc request's context.Context which points to
+	// the index of hedged attempt, the picker picks the next distinct member in the hashring
+	// for the hedged attempt, so the duplicate request lands on a secondary address.
+	HedgingContextKey = ContextKeyType("consistent-hashing-hedging-key")
+
+	// PickFilterContextKey is the key for the grpc request's context.Context which points to
+	// the filter of picked address, e.g. the circuit breaker tracking failures per address.
+	PickFilterContextKey = ContextKeyType("consistent-hashing-pick-filter-key")
 )
 
 // searchCircleLimit is the limit of searching circle.
@@ -57,7 +68,12 @@ func NewConsistentHashingBuilder() (balancer.Builder, *ConsistentHashingPickerBu
 	), pickerBuilder
 }
 
+// ConsistentHashingPickerBuilder builds the pickers which consistent-hash on the key
+// in request context, so the requests of the same task always land on the same address.
 type ConsistentHashingPickerBuilder struct {
+	// mu protects the fields below, Build is called by balancer and
+	// GetCircle is called by clients concurrently.
+	mu       sync.Mutex
 	hashring *consistent.Consistent
 	members  []string
 	circle   map[string]string
@@ -70,21 +86,33 @@ func (b *ConsistentHashingPickerBuilder) Build(info base.PickerBuildInfo) balanc
 	}
 
 	// Build hashring and init sub connections map.
-	b.hashring = consistent.New()
+	hashring := consistent.New()
 	scs := make(map[string]balancer.SubConn, len(info.ReadySCs))
+	addrs := make(map[string]string, len(info.ReadySCs))
 	for sc, scInfo := range info.ReadySCs {
 		element := fmt.Sprintf("%s:%s", scInfo.Address.Addr, scInfo.Address.ServerName)
-		b.hashring.Add(element)
+		hashring.Add(element)
 		scs[element] = sc
+		addrs[element] = scInfo.Address.Addr
 	}
 
+	b.mu.Lock()
+	b.hashring = hashring
+	b.mu.Unlock()
+
 	return &consistentHashingPicker{
 		subConns: scs,
-		hashring: b.hashring,
+		addrs:    addrs,
+		hashring: hashring,
 	}
 }
 
+// GetCircle returns a virtual key of every member in the hashring, it is used to
+// send the requests to all the members, e.g. announcing host to all schedulers.
 func (b *ConsistentHashingPickerBuilder) GetCircle() (map[string]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	if b.hashring == nil {
 		return nil, errors.New("invalid hashring")
 	}
@@ -116,8 +144,21 @@ func (b *ConsistentHashingPickerBuilder) GetCircle() (map[string]string, error)
 	return nil, errors.New("can not generate circle")
 }
 
+// WithHedgingAttempt returns a copy of ctx carrying the index of hedged attempt.
+func WithHedgingAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, HedgingContextKey, attempt)
+}
+
+// WithPickFilter returns a copy of ctx carrying the filter of picked address, the picker fails
+// the request with the error returned by filter, otherwise the returned done is called with
+// the result of request.
+func WithPickFilter(ctx context.Context, filter func(addr string) (done func(err error), err error)) context.Context {
+	return context.WithValue(ctx, PickFilterContextKey, filter)
+}
+
 type consistentHashingPicker struct {
 	subConns map[string]balancer.SubConn
+	addrs    map[string]string
 	hashring *consistent.Consistent
 }
 
@@ -131,9 +172,33 @@ func (p *consistentHashingPicker) Pick(info balancer.PickInfo) (balancer.PickRes
 	if err != nil {
 		return balancer.PickResult{}, err
 	}
+
+	// Pick the secondary member for the hedged attempt, if there are not enough
+	// members in the hashring, the last member is picked.
+	if attempt, ok := info.Ctx.Value(HedgingContextKey).(int); ok && attempt > 0 {
+		elements, err := p.hashring.GetN(taskID, attempt+1)
+		if err != nil {
+			return balancer.PickResult{}, err
+		}
+
+		element = elements[len(elements)-1]
+	}
 	logger.Infof("task %s picks connection %s", taskID, element)
 
-	return balancer.PickResult{
+	result := balancer.PickResult{
 		SubConn: p.subConns[element],
-	}, nil
+	}
+
+	if filter, ok := info.Ctx.Value(PickFilterContextKey).(func(string) (func(error), error)); ok {
+		done, err := filter(p.addrs[element])
+		if err != nil {
+			return balancer.PickResult{}, err
+		}
+
+		result.Done = func(info balancer.DoneInfo) {
+			done(info.Err)
+		}
+	}
+
+	return result, nil
 }
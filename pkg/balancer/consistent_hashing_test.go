@@ -0,0 +1,155 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package balancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+)
+
+type testSubConn struct {
+	balancer.SubConn
+	addr string
+}
+
+func newTestPickerBuildInfo(n int) base.PickerBuildInfo {
+	info := base.PickerBuildInfo{ReadySCs: make(map[balancer.SubConn]base.SubConnInfo)}
+	for i := 0; i < n; i++ {
+		addr := fmt.Sprintf("127.0.0.%d:8002", i)
+		info.ReadySCs[&testSubConn{addr: addr}] = base.SubConnInfo{Address: resolver.Address{Addr: addr}}
+	}
+
+	return info
+}
+
+func TestConsistentHashingPicker_Pick(t *testing.T) {
+	tests := []struct {
+		name   string
+		info   base.PickerBuildInfo
+		expect func(t *testing.T, picker balancer.Picker)
+	}{
+		{
+			name: "pick without sub connections",
+			info: base.PickerBuildInfo{},
+			expect: func(t *testing.T, picker balancer.Picker) {
+				assert := assert.New(t)
+				_, err := picker.Pick(balancer.PickInfo{Ctx: context.WithValue(context.Background(), ContextKey, "foo")})
+				assert.ErrorIs(err, balancer.ErrNoSubConnAvailable)
+			},
+		},
+		{
+			name: "pick without key",
+			info: newTestPickerBuildInfo(3),
+			expect: func(t *testing.T, picker balancer.Picker) {
+				assert := assert.New(t)
+				_, err := picker.Pick(balancer.PickInfo{Ctx: context.Background()})
+				assert.EqualError(err, "picker can not found task id")
+			},
+		},
+		{
+			name: "pick the same sub connection with the same key",
+			info: newTestPickerBuildInfo(3),
+			expect: func(t *testing.T, picker balancer.Picker) {
+				assert := assert.New(t)
+				ctx := context.WithValue(context.Background(), ContextKey, "foo")
+				result, err := picker.Pick(balancer.PickInfo{Ctx: ctx})
+				assert.NoError(err)
+				for i := 0; i < 10; i++ {
+					r, err := picker.Pick(balancer.PickInfo{Ctx: ctx})
+					assert.NoError(err)
+					assert.Equal(result.SubConn, r.SubConn)
+				}
+			},
+		},
+		{
+			name: "pick the secondary sub connection with hedged attempt",
+			info: newTestPickerBuildInfo(3),
+			expect: func(t *testing.T, picker balancer.Picker) {
+				assert := assert.New(t)
+				ctx := context.WithValue(context.Background(), ContextKey, "foo")
+				primary, err := picker.Pick(balancer.PickInfo{Ctx: ctx})
+				assert.NoError(err)
+				secondary, err := picker.Pick(balancer.PickInfo{Ctx: WithHedgingAttempt(ctx, 1)})
+				assert.NoError(err)
+				assert.NotEqual(primary.SubConn, secondary.SubConn)
+			},
+		},
+		{
+			name: "pick sub connection allowed by filter",
+			info: newTestPickerBuildInfo(3),
+			expect: func(t *testing.T, picker balancer.Picker) {
+				assert := assert.New(t)
+				var (
+					picked string
+					result error
+				)
+				ctx := WithPickFilter(context.WithValue(context.Background(), ContextKey, "foo"), func(addr string) (func(error), error) {
+					picked = addr
+					return func(err error) {
+						result = err
+					}, nil
+				})
+
+				r, err := picker.Pick(balancer.PickInfo{Ctx: ctx})
+				assert.NoError(err)
+				assert.Equal(r.SubConn.(*testSubConn).addr, picked)
+
+				r.Done(balancer.DoneInfo{Err: errors.New("foo")})
+				assert.EqualError(result, "foo")
+			},
+		},
+		{
+			name: "pick sub connection rejected by filter",
+			info: newTestPickerBuildInfo(3),
+			expect: func(t *testing.T, picker balancer.Picker) {
+				assert := assert.New(t)
+				ctx := WithPickFilter(context.WithValue(context.Background(), ContextKey, "foo"), func(addr string) (func(error), error) {
+					return nil, errors.New("bar")
+				})
+
+				_, err := picker.Pick(balancer.PickInfo{Ctx: ctx})
+				assert.EqualError(err, "bar")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, pickerBuilder := NewConsistentHashingBuilder()
+			tc.expect(t, pickerBuilder.Build(tc.info))
+		})
+	}
+}
+
+func TestConsistentHashingPickerBuilder_GetCircle(t *testing.T) {
+	assert := assert.New(t)
+	_, pickerBuilder := NewConsistentHashingBuilder()
+	_, err := pickerBuilder.GetCircle()
+	assert.EqualError(err, "invalid hashring")
+
+	pickerBuilder.Build(newTestPickerBuildInfo(3))
+	circle, err := pickerBuilder.GetCircle()
+	assert.NoError(err)
+	assert.Len(circle, 3)
+}
@@ -29,6 +29,9 @@ import (
 	"io"
 	"os"
 	"strings"
+
+	"github.com/zeebo/blake3"
+	"github.com/zeebo/xxh3"
 )
 
 const (
@@ -43,6 +46,12 @@ const (
 
 	// AlgorithmMD5 is md5 algorithm name of hash.
 	AlgorithmMD5 = "md5"
+
+	// AlgorithmXXH3 is 64 bits xxh3 algorithm name of hash.
+	AlgorithmXXH3 = "xxh3"
+
+	// AlgorithmBLAKE3 is 256 bits blake3 algorithm name of hash.
+	AlgorithmBLAKE3 = "blake3"
 )
 
 // Digest provides digest operation function.
@@ -85,6 +94,10 @@ func HashFile(path string, algorithm string) (string, error) {
 		h = sha512.New()
 	case AlgorithmMD5:
 		h = md5.New()
+	case AlgorithmXXH3:
+		h = xxh3.New()
+	case AlgorithmBLAKE3:
+		h = blake3.New()
 	default:
 		return "", fmt.Errorf("unsupport digest method: %s", algorithm)
 	}
@@ -125,6 +138,14 @@ func Parse(digest string) (*Digest, error) {
 		if len(encoded) != 32 {
 			return nil, errors.New("invalid encoded")
 		}
+	case AlgorithmXXH3:
+		if len(encoded) != 16 {
+			return nil, errors.New("invalid encoded")
+		}
+	case AlgorithmBLAKE3:
+		if len(encoded) != 64 {
+			return nil, errors.New("invalid encoded")
+		}
 	default:
 		return nil, errors.New("invalid algorithm")
 	}
@@ -135,6 +156,25 @@ func Parse(digest string) (*Digest, error) {
 	}, nil
 }
 
+// FormatPieceDigest formats the digest of the piece, the md5 digest is formatted without
+// the algorithm to be compatible with the peers which only support md5 piece digest.
+func FormatPieceDigest(algorithm, encoded string) string {
+	if algorithm == AlgorithmMD5 || encoded == "" {
+		return encoded
+	}
+
+	return New(algorithm, encoded).String()
+}
+
+// ParsePieceDigest parses the digest of the piece formatted by FormatPieceDigest.
+func ParsePieceDigest(pieceDigest string) (*Digest, error) {
+	if !strings.Contains(pieceDigest, ":") {
+		return New(AlgorithmMD5, pieceDigest), nil
+	}
+
+	return Parse(pieceDigest)
+}
+
 // MD5FromReader computes the MD5 checksum with io.Reader.
 func MD5FromReader(reader io.Reader) string {
 	h := md5.New()
@@ -29,21 +29,29 @@ import (
 	"hash"
 	"io"
 
+	"github.com/zeebo/blake3"
+	"github.com/zeebo/xxh3"
+
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 )
 
+// ErrDigestNotMatch is returned when the digest of the read content does not match the desired encoded.
+var ErrDigestNotMatch = errors.New("digest encoded not match")
+
 // Reader is the interface used for reading resource.
 type Reader interface {
 	io.Reader
 	Encoded() string
+	Algorithm() string
 }
 
 // reader reads stream with RateLimiter.
 type reader struct {
-	r       io.Reader
-	encoded string
-	hash    hash.Hash
-	logger  *logger.SugaredLoggerOnWith
+	r         io.Reader
+	algorithm string
+	encoded   string
+	hash      hash.Hash
+	logger    *logger.SugaredLoggerOnWith
 }
 
 // Option is a functional option for digest reader.
@@ -75,14 +83,19 @@ func NewReader(algorithm string, r io.Reader, options ...Option) (Reader, error)
 		h = sha512.New()
 	case AlgorithmMD5:
 		h = md5.New()
+	case AlgorithmXXH3:
+		h = xxh3.New()
+	case AlgorithmBLAKE3:
+		h = blake3.New()
 	default:
 		return nil, fmt.Errorf("invalid algorithm: %s", algorithm)
 	}
 
 	reader := &reader{
-		r:      r,
-		hash:   h,
-		logger: &logger.SugaredLoggerOnWith{},
+		r:         r,
+		algorithm: algorithm,
+		hash:      h,
+		logger:    &logger.SugaredLoggerOnWith{},
 	}
 
 	for _, opt := range options {
@@ -107,7 +120,7 @@ func (r *reader) Read(p []byte) (int, error) {
 		encoded := r.Encoded()
 		if encoded != r.encoded {
 			r.logger.Warnf("digest encoded not match, desired: %s, actual: %s", r.encoded, encoded)
-			return n, errors.New("digest encoded not match")
+			return n, ErrDigestNotMatch
 		}
 
 		r.logger.Debugf("digest encoded match: %s", encoded)
@@ -120,3 +133,8 @@ func (r *reader) Read(p []byte) (int, error) {
 func (r *reader) Encoded() string {
 	return hex.EncodeToString(r.hash.Sum(nil))
 }
+
+// Algorithm returns the algorithm of hash.
+func (r *reader) Algorithm() string {
+	return r.algorithm
+}
@@ -96,6 +96,38 @@ func TestDigest_Reader(t *testing.T) {
 				assert.Equal(reader.Encoded(), "acbd18db4cc2f85cedef654fccc4a4d8")
 			},
 		},
+		{
+			name:      "xxh3 reader",
+			algorithm: AlgorithmXXH3,
+			data:      []byte("foo"),
+			options:   []Option{WithLogger(log)},
+			run: func(t *testing.T, data []byte, reader Reader, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(reader.Algorithm(), AlgorithmXXH3)
+				assert.Equal(reader.Encoded(), "2d06800538d394c2")
+				b, err := io.ReadAll(reader)
+				assert.NoError(err)
+				assert.Equal(b, data)
+				assert.Equal(reader.Encoded(), "ab6e5f64077e7d8a")
+			},
+		},
+		{
+			name:      "blake3 reader",
+			algorithm: AlgorithmBLAKE3,
+			data:      []byte("foo"),
+			options:   []Option{WithLogger(log), WithEncoded("04e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9")},
+			run: func(t *testing.T, data []byte, reader Reader, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(reader.Algorithm(), AlgorithmBLAKE3)
+				assert.Equal(reader.Encoded(), "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262")
+				b, err := io.ReadAll(reader)
+				assert.NoError(err)
+				assert.Equal(b, data)
+				assert.Equal(reader.Encoded(), "04e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9")
+			},
+		},
 		{
 			name:      "sha1 reader with encoded",
 			algorithm: AlgorithmSHA1,
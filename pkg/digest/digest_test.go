@@ -46,6 +46,8 @@ func TestDigest_HashFile(t *testing.T) {
 		{AlgorithmSHA256, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
 		{AlgorithmSHA512, "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"},
 		{AlgorithmMD5, "5d41402abc4b2a76b9719d911017c592"},
+		{AlgorithmXXH3, "9555e8555c62dcfd"},
+		{AlgorithmBLAKE3, "ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f"},
 	}
 
 	if _, err := f.Write([]byte("hello")); err != nil {
@@ -133,6 +135,40 @@ func TestDigest_Parse(t *testing.T) {
 				assert.Error(err)
 			},
 		},
+		{
+			name:  "xxh3 digest",
+			value: "xxh3:9555e8555c62dcfd",
+			expect: func(t *testing.T, d *Digest, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.EqualValues(d, New(AlgorithmXXH3, "9555e8555c62dcfd"))
+			},
+		},
+		{
+			name:  "invalid xxh3 encoded",
+			value: "xxh3:9555e8555c62dcf",
+			expect: func(t *testing.T, d *Digest, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+		{
+			name:  "blake3 digest",
+			value: "blake3:ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f",
+			expect: func(t *testing.T, d *Digest, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.EqualValues(d, New(AlgorithmBLAKE3, "ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f"))
+			},
+		},
+		{
+			name:  "invalid blake3 encoded",
+			value: "blake3:ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200",
+			expect: func(t *testing.T, d *Digest, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
 		{
 			name:  "invalid algorithm",
 			value: "foo:5d41402abc4b2a76b9719d911017c592",
@@ -160,6 +196,55 @@ func TestDigest_Parse(t *testing.T) {
 	}
 }
 
+func TestDigest_FormatPieceDigest(t *testing.T) {
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", FormatPieceDigest(AlgorithmMD5, "5d41402abc4b2a76b9719d911017c592"))
+	assert.Equal(t, "xxh3:9555e8555c62dcfd", FormatPieceDigest(AlgorithmXXH3, "9555e8555c62dcfd"))
+	assert.Equal(t, "", FormatPieceDigest(AlgorithmXXH3, ""))
+}
+
+func TestDigest_ParsePieceDigest(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		expect func(t *testing.T, digest *Digest, err error)
+	}{
+		{
+			name:  "md5 piece digest without algorithm",
+			value: "5d41402abc4b2a76b9719d911017c592",
+			expect: func(t *testing.T, d *Digest, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.EqualValues(d, New(AlgorithmMD5, "5d41402abc4b2a76b9719d911017c592"))
+			},
+		},
+		{
+			name:  "blake3 piece digest",
+			value: "blake3:ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f",
+			expect: func(t *testing.T, d *Digest, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.EqualValues(d, New(AlgorithmBLAKE3, "ea8f163db38682925e4491c5e58d4bb3506ef8c14eb78a86e908c5624a67200f"))
+			},
+		},
+		{
+			name:  "invalid piece digest",
+			value: "foo:5d41402abc4b2a76b9719d911017c592",
+			expect: func(t *testing.T, d *Digest, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := ParsePieceDigest(tc.value)
+
+			tc.expect(t, d, err)
+		})
+	}
+}
+
 func TestDigest_MD5FromReader(t *testing.T) {
 	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", MD5FromReader(strings.NewReader("hello")))
 }
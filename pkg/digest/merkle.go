@@ -0,0 +1,157 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package digest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const (
+	// merkleLeafPrefix and merkleNodePrefix separate the leaf and node hashes,
+	// so an inner node can not be presented as a leaf.
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// MerkleTree is a binary sha256 hash tree over the piece digests of a task.
+// When a level has an odd number of nodes, the last node is promoted to the next level.
+type MerkleTree struct {
+	leaves []string
+	levels [][][]byte
+}
+
+// NewMerkleTree builds the merkle tree over the leaves in order.
+func NewMerkleTree(leaves []string) (*MerkleTree, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("merkle tree requires at least one leaf")
+	}
+
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = hashMerkleLeaf(leaf)
+	}
+
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+
+			next = append(next, hashMerkleNode(level[i], level[i+1]))
+		}
+
+		levels = append(levels, next)
+		level = next
+	}
+
+	return &MerkleTree{
+		leaves: append([]string(nil), leaves...),
+		levels: levels,
+	}, nil
+}
+
+// Root returns the hex encoded root hash of the tree.
+func (m *MerkleTree) Root() string {
+	return hex.EncodeToString(m.levels[len(m.levels)-1][0])
+}
+
+// Leaves returns the leaves of the tree.
+func (m *MerkleTree) Leaves() []string {
+	return m.leaves
+}
+
+// Proof returns the hex encoded sibling hashes from the leaf to the root.
+func (m *MerkleTree) Proof(index int) ([]string, error) {
+	if index < 0 || index >= len(m.leaves) {
+		return nil, fmt.Errorf("merkle leaf index %d out of range [0, %d)", index, len(m.leaves))
+	}
+
+	var proof []string
+	for _, level := range m.levels[:len(m.levels)-1] {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, hex.EncodeToString(level[sibling]))
+		}
+
+		index /= 2
+	}
+
+	return proof, nil
+}
+
+// Verify returns whether the leaf is at the index of the tree.
+func (m *MerkleTree) Verify(index int, leaf string) bool {
+	proof, err := m.Proof(index)
+	if err != nil {
+		return false
+	}
+
+	return VerifyMerkleProof(m.Root(), leaf, index, len(m.leaves), proof)
+}
+
+// VerifyMerkleProof returns whether the leaf is at the index of the tree
+// with the given root and leaf count.
+func VerifyMerkleProof(root, leaf string, index, count int, proof []string) bool {
+	if index < 0 || index >= count {
+		return false
+	}
+
+	hash := hashMerkleLeaf(leaf)
+	for width := count; width > 1; width = (width + 1) / 2 {
+		if sibling := index ^ 1; sibling < width {
+			if len(proof) == 0 {
+				return false
+			}
+
+			siblingHash, err := hex.DecodeString(proof[0])
+			if err != nil {
+				return false
+			}
+			proof = proof[1:]
+
+			if index%2 == 0 {
+				hash = hashMerkleNode(hash, siblingHash)
+			} else {
+				hash = hashMerkleNode(siblingHash, hash)
+			}
+		}
+
+		index /= 2
+	}
+
+	return len(proof) == 0 && hex.EncodeToString(hash) == root
+}
+
+func hashMerkleLeaf(leaf string) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleLeafPrefix})
+	h.Write([]byte(leaf))
+	return h.Sum(nil)
+}
+
+func hashMerkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,80 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package digest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkleTree(t *testing.T) {
+	tests := []struct {
+		name   string
+		leaves int
+	}{
+		{name: "single leaf", leaves: 1},
+		{name: "even leaves", leaves: 4},
+		{name: "odd leaves", leaves: 7},
+		{name: "many leaves", leaves: 33},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			leaves := make([]string, tc.leaves)
+			for i := range leaves {
+				leaves[i] = FormatPieceDigest(AlgorithmMD5, MD5FromBytes([]byte(fmt.Sprintf("piece-%d", i))))
+			}
+
+			tree, err := NewMerkleTree(leaves)
+			assert.Nil(err)
+			assert.Equal(leaves, tree.Leaves())
+
+			same, err := NewMerkleTree(leaves)
+			assert.Nil(err)
+			assert.Equal(tree.Root(), same.Root())
+
+			for i, leaf := range leaves {
+				proof, err := tree.Proof(i)
+				assert.Nil(err)
+				assert.True(VerifyMerkleProof(tree.Root(), leaf, i, len(leaves), proof))
+				assert.True(tree.Verify(i, leaf))
+				assert.False(tree.Verify(i, "md5:corrupted"))
+				assert.False(VerifyMerkleProof(tree.Root(), leaf, i, len(leaves), append(proof, tree.Root())))
+				if len(leaves) > 1 {
+					assert.False(tree.Verify((i+1)%len(leaves), leaf))
+				}
+			}
+
+			_, err = tree.Proof(len(leaves))
+			assert.Error(err)
+			assert.False(tree.Verify(-1, leaves[0]))
+
+			leaves[len(leaves)-1] = "md5:corrupted"
+			corrupted, err := NewMerkleTree(leaves)
+			assert.Nil(err)
+			assert.NotEqual(tree.Root(), corrupted.Root())
+		})
+	}
+}
+
+func TestNewMerkleTree_Empty(t *testing.T) {
+	_, err := NewMerkleTree(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package featureflag
+
+import (
+	"encoding/json"
+	"hash/fnv"
+
+	"d7y.io/dragonfly/v2/pkg/slices"
+)
+
+// ConfigKey is the key of the feature flags in the scheduler cluster config and client config.
+const ConfigKey = "feature_flags"
+
+const (
+	// MLEvaluator enables the scheduler to score the candidate parents by the machine learning
+	// algorithm, it takes effect when the inference of scheduler is configured.
+	MLEvaluator = "ml_evaluator"
+
+	// PeerExchange enables the scheduler to find the sibling peers of hot tasks,
+	// and enables the peer to gossip piece availability with the sibling peers.
+	PeerExchange = "peer_exchange"
+)
+
+// Flags is the names of the enabled feature flags.
+type Flags []string
+
+// Enabled returns whether the feature flag is enabled.
+func (f Flags) Enabled(name string) bool {
+	return slices.Contains(f, name)
+}
+
+// Parse parses the enabled feature flags from the cluster config.
+func Parse(config []byte) (Flags, error) {
+	if len(config) == 0 {
+		return Flags{}, nil
+	}
+
+	var c struct {
+		FeatureFlags Flags `json:"feature_flags"`
+	}
+	if err := json.Unmarshal(config, &c); err != nil {
+		return nil, err
+	}
+
+	if c.FeatureFlags == nil {
+		return Flags{}, nil
+	}
+
+	return c.FeatureFlags, nil
+}
+
+// IsRolledOut returns whether the feature flag is rolled out to the host by percentage.
+// The host is bucketed by the hash of the feature flag name and the host, so the host
+// always gets the same result, and keeps enabled when the percentage increases.
+func IsRolledOut(name string, percentage uint, hostname, ip string) bool {
+	if percentage == 0 {
+		return false
+	}
+
+	if percentage >= 100 {
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name + "/" + hostname + "-" + ip)) // nolint: errcheck
+	return uint(h.Sum32()%100) < percentage
+}
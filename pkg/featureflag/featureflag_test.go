@@ -0,0 +1,166 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package featureflag
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlags_Enabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		flags  Flags
+		flag   string
+		expect bool
+	}{
+		{
+			name:   "flag is enabled",
+			flags:  Flags{"foo", "bar"},
+			flag:   "foo",
+			expect: true,
+		},
+		{
+			name:   "flag is not enabled",
+			flags:  Flags{"foo"},
+			flag:   "bar",
+			expect: false,
+		},
+		{
+			name:   "flags is empty",
+			flags:  nil,
+			flag:   "foo",
+			expect: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, tc.flags.Enabled(tc.flag))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		config []byte
+		expect func(t *testing.T, flags Flags, err error)
+	}{
+		{
+			name:   "parse feature flags",
+			config: []byte(`{"load_limit":10,"feature_flags":["foo","bar"]}`),
+			expect: func(t *testing.T, flags Flags, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(Flags{"foo", "bar"}, flags)
+			},
+		},
+		{
+			name:   "config without feature flags",
+			config: []byte(`{"load_limit":10}`),
+			expect: func(t *testing.T, flags Flags, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(Flags{}, flags)
+			},
+		},
+		{
+			name:   "config is empty",
+			config: nil,
+			expect: func(t *testing.T, flags Flags, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(Flags{}, flags)
+			},
+		},
+		{
+			name:   "config is invalid",
+			config: []byte(`{`),
+			expect: func(t *testing.T, flags Flags, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			flags, err := Parse(tc.config)
+			tc.expect(t, flags, err)
+		})
+	}
+}
+
+func TestIsRolledOut(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(t *testing.T)
+	}{
+		{
+			name: "percentage is zero",
+			expect: func(t *testing.T) {
+				assert.False(t, IsRolledOut("foo", 0, "bar", "127.0.0.1"))
+			},
+		},
+		{
+			name: "percentage is full",
+			expect: func(t *testing.T) {
+				assert.True(t, IsRolledOut("foo", 100, "bar", "127.0.0.1"))
+			},
+		},
+		{
+			name: "result is stable for the host",
+			expect: func(t *testing.T) {
+				assert.Equal(t, IsRolledOut("foo", 50, "bar", "127.0.0.1"), IsRolledOut("foo", 50, "bar", "127.0.0.1"))
+			},
+		},
+		{
+			name: "enabled hosts keep enabled when percentage increases",
+			expect: func(t *testing.T) {
+				assert := assert.New(t)
+				for i := 0; i < 100; i++ {
+					hostname := fmt.Sprintf("host-%d", i)
+					if IsRolledOut("foo", 30, hostname, "127.0.0.1") {
+						assert.True(IsRolledOut("foo", 60, hostname, "127.0.0.1"))
+					}
+				}
+			},
+		},
+		{
+			name: "hosts are rolled out by percentage",
+			expect: func(t *testing.T) {
+				var count int
+				for i := 0; i < 1000; i++ {
+					if IsRolledOut("foo", 50, fmt.Sprintf("host-%d", i), "127.0.0.1") {
+						count++
+					}
+				}
+
+				assert.InDelta(t, 500, count, 100)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t)
+		})
+	}
+}
@@ -19,6 +19,7 @@ package idgen
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -33,6 +34,11 @@ func SeedPeerIDV1(ip string) string {
 	return fmt.Sprintf("%s_%s", PeerIDV1(ip), "Seed")
 }
 
+// IsSeedPeerIDV1 returns whether the peer id is v1 version of seed peer id.
+func IsSeedPeerIDV1(peerID string) bool {
+	return strings.HasSuffix(peerID, "_Seed")
+}
+
 // PeerIDV2 generates v2 version of peer id.
 func PeerIDV2() string {
 	return uuid.NewString()
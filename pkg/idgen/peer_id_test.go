@@ -102,6 +102,37 @@ func TestSeedPeerIDV1(t *testing.T) {
 	}
 }
 
+func TestIsSeedPeerIDV1(t *testing.T) {
+	tests := []struct {
+		name   string
+		peerID string
+		expect bool
+	}{
+		{
+			name:   "seed peer id",
+			peerID: SeedPeerIDV1("127.0.0.1"),
+			expect: true,
+		},
+		{
+			name:   "normal peer id",
+			peerID: PeerIDV1("127.0.0.1"),
+			expect: false,
+		},
+		{
+			name:   "empty peer id",
+			peerID: "",
+			expect: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(tc.expect, IsSeedPeerIDV1(tc.peerID))
+		})
+	}
+}
+
 func TestPeerIDV2(t *testing.T) {
 	assert := assert.New(t)
 	assert.Len(PeerIDV2(), 36)
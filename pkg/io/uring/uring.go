@@ -0,0 +1,31 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package uring batches the reads and writes of files with io_uring on linux.
+package uring
+
+import "errors"
+
+var (
+	// ErrNotSupported is returned when io_uring is not supported by the platform or the kernel.
+	ErrNotSupported = errors.New("io_uring is not supported")
+
+	// ErrClosed is returned when the ring is closed.
+	ErrClosed = errors.New("io_uring is closed")
+)
+
+// DefaultEntries is the default number of the submission queue entries.
+const DefaultEntries = 256
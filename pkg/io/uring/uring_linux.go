@@ -0,0 +1,428 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package uring
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// IORING_OP_READ and IORING_OP_WRITE, supported since linux 5.6.
+	opRead  = 22
+	opWrite = 23
+
+	// IORING_ENTER_GETEVENTS waits for the completions.
+	enterGetEvents = 1
+
+	// Offsets of the mmap of the rings.
+	offSQRing = 0
+	offCQRing = 0x8000000
+	offSQEs   = 0x10000000
+
+	// maxIOSize is the max size of the buffer of a submission.
+	maxIOSize = 1 << 30
+
+	// minBackoff and maxBackoff bound the wait before entering the ring again
+	// when the kernel is short of resources.
+	minBackoff = 50 * time.Microsecond
+	maxBackoff = 10 * time.Millisecond
+)
+
+// sqRingOffsets is struct io_sqring_offsets.
+type sqRingOffsets struct {
+	head        uint32
+	tail        uint32
+	ringMask    uint32
+	ringEntries uint32
+	flags       uint32
+	dropped     uint32
+	array       uint32
+	resv1       uint32
+	userAddr    uint64
+}
+
+// cqRingOffsets is struct io_cqring_offsets.
+type cqRingOffsets struct {
+	head        uint32
+	tail        uint32
+	ringMask    uint32
+	ringEntries uint32
+	overflow    uint32
+	cqes        uint32
+	flags       uint32
+	resv1       uint32
+	userAddr    uint64
+}
+
+// params is struct io_uring_params.
+type params struct {
+	sqEntries    uint32
+	cqEntries    uint32
+	flags        uint32
+	sqThreadCPU  uint32
+	sqThreadIdle uint32
+	features     uint32
+	wqFD         uint32
+	resv         [3]uint32
+	sqOff        sqRingOffsets
+	cqOff        cqRingOffsets
+}
+
+// sqe is struct io_uring_sqe.
+type sqe struct {
+	opcode      uint8
+	flags       uint8
+	ioprio      uint16
+	fd          int32
+	off         uint64
+	addr        uint64
+	len         uint32
+	rwFlags     uint32
+	userData    uint64
+	bufIndex    uint16
+	personality uint16
+	spliceFDIn  int32
+	addr3       uint64
+	_           uint64
+}
+
+// cqe is struct io_uring_cqe.
+type cqe struct {
+	userData uint64
+	res      int32
+	flags    uint32
+}
+
+// request is a read or write submitted to the ring.
+type request struct {
+	opcode uint8
+	fd     int32
+	p      []byte
+	off    int64
+	done   chan result
+
+	// pinner pins the buffer while the kernel accesses it.
+	pinner runtime.Pinner
+}
+
+// result is the completion of the request.
+type result struct {
+	n   int
+	err error
+}
+
+// Ring is an io_uring instance, the concurrent reads and writes are submitted to the kernel in batches
+// and new batches are submitted while the earlier ones are still in flight.
+type Ring struct {
+	fd      int
+	entries uint32
+
+	sqRing  []byte
+	cqRing  []byte
+	sqeRing []byte
+
+	sqHead  *uint32
+	sqTail  *uint32
+	sqMask  uint32
+	sqArray []uint32
+	sqes    []sqe
+
+	cqHead *uint32
+	cqTail *uint32
+	cqMask uint32
+	cqes   []cqe
+
+	requests  chan *request
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// New returns a ring with the number of the submission queue entries,
+// ErrNotSupported is returned when io_uring is not available in the kernel.
+func New(entries uint32) (*Ring, error) {
+	var p params
+	fd, _, errno := unix.Syscall(unix.SYS_IO_URING_SETUP, uintptr(entries), uintptr(unsafe.Pointer(&p)), 0)
+	if errno != 0 {
+		if errno == unix.ENOSYS || errno == unix.EPERM {
+			return nil, fmt.Errorf("%w: %s", ErrNotSupported, errno)
+		}
+
+		return nil, os.NewSyscallError("io_uring_setup", errno)
+	}
+
+	r := &Ring{
+		fd:       int(fd),
+		entries:  p.sqEntries,
+		requests: make(chan *request),
+		done:     make(chan struct{}),
+	}
+
+	if err := r.mmap(&p); err != nil {
+		r.munmap()
+		unix.Close(r.fd)
+		return nil, err
+	}
+
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+// ReadAt reads len(p) bytes from the file at the offset.
+func (r *Ring) ReadAt(file *os.File, p []byte, off int64) (int, error) {
+	var read int
+	for read < len(p) {
+		n, err := r.submit(file, opRead, p[read:min(len(p), read+maxIOSize)], off+int64(read))
+		if err != nil {
+			return read, err
+		}
+
+		if n == 0 {
+			return read, io.EOF
+		}
+		read += n
+	}
+
+	return read, nil
+}
+
+// WriteAt writes len(p) bytes to the file at the offset.
+func (r *Ring) WriteAt(file *os.File, p []byte, off int64) (int, error) {
+	var written int
+	for written < len(p) {
+		n, err := r.submit(file, opWrite, p[written:min(len(p), written+maxIOSize)], off+int64(written))
+		if err != nil {
+			return written, err
+		}
+
+		if n == 0 {
+			return written, io.ErrShortWrite
+		}
+		written += n
+	}
+
+	return written, nil
+}
+
+// Close waits for the submitted requests and releases the ring.
+func (r *Ring) Close() error {
+	var err error
+	r.closeOnce.Do(func() {
+		close(r.done)
+		r.wg.Wait()
+		r.munmap()
+		err = unix.Close(r.fd)
+	})
+
+	return err
+}
+
+// submit hands the request over to the ring and waits for its completion.
+func (r *Ring) submit(file *os.File, opcode uint8, p []byte, off int64) (int, error) {
+	req := &request{
+		opcode: opcode,
+		fd:     int32(file.Fd()),
+		p:      p,
+		off:    off,
+		done:   make(chan result, 1),
+	}
+
+	select {
+	case r.requests <- req:
+	case <-r.done:
+		return 0, ErrClosed
+	}
+
+	res := <-req.done
+	runtime.KeepAlive(file)
+	return res.n, res.err
+}
+
+// run submits the concurrent requests to the ring and delivers their completions, the requests
+// arriving while others are in flight are submitted without waiting for the earlier completions.
+func (r *Ring) run() {
+	defer r.wg.Done()
+
+	var (
+		nextID   uint64
+		toSubmit int
+		backoff  time.Duration
+		inflight = make(map[uint64]*request, r.entries)
+	)
+
+	accept := func(req *request) {
+		r.push(nextID, req)
+		inflight[nextID] = req
+		nextID++
+		toSubmit++
+	}
+
+	for {
+		// Block for a request only when there is nothing in flight.
+		if len(inflight) == 0 {
+			select {
+			case req := <-r.requests:
+				accept(req)
+			case <-r.done:
+				return
+			}
+		}
+
+	drain:
+		for uint32(len(inflight)) < r.entries {
+			select {
+			case req := <-r.requests:
+				accept(req)
+			default:
+				break drain
+			}
+		}
+
+		// Submit the pending entries and wait for at least one completion, the in flight
+		// requests never exceed the submission entries, so the completion queue can not overflow.
+		n, _, errno := unix.Syscall6(unix.SYS_IO_URING_ENTER, uintptr(r.fd), uintptr(toSubmit), 1, enterGetEvents, 0, 0)
+		switch errno {
+		case 0:
+			toSubmit -= int(n)
+			backoff = 0
+		case unix.EINTR:
+		case unix.EAGAIN, unix.EBUSY:
+			backoff = r.backoff(backoff)
+		default:
+			r.drop(inflight, os.NewSyscallError("io_uring_enter", errno))
+			toSubmit = 0
+			backoff = r.backoff(backoff)
+		}
+
+		r.reap(inflight)
+	}
+}
+
+// push writes the request to the submission queue with the id as its user data.
+func (r *Ring) push(id uint64, req *request) {
+	var addr uint64
+	if len(req.p) > 0 {
+		req.pinner.Pin(&req.p[0])
+		addr = uint64(uintptr(unsafe.Pointer(&req.p[0])))
+	}
+
+	tail := *r.sqTail
+	index := tail & r.sqMask
+	r.sqes[index] = sqe{
+		opcode:   req.opcode,
+		fd:       req.fd,
+		off:      uint64(req.off),
+		addr:     addr,
+		len:      uint32(len(req.p)),
+		userData: id,
+	}
+	r.sqArray[index] = index
+	atomic.StoreUint32(r.sqTail, tail+1)
+}
+
+// drop fails the requests which are not consumed by the kernel yet and removes them from the
+// submission queue, the consumed ones are still delivered by reap.
+func (r *Ring) drop(inflight map[uint64]*request, err error) {
+	head, tail := atomic.LoadUint32(r.sqHead), *r.sqTail
+	for i := head; i != tail; i++ {
+		id := r.sqes[r.sqArray[i&r.sqMask]].userData
+		inflight[id].complete(result{err: err})
+		delete(inflight, id)
+	}
+	atomic.StoreUint32(r.sqTail, head)
+}
+
+// backoff sleeps before entering the ring again and returns the doubled duration.
+func (r *Ring) backoff(d time.Duration) time.Duration {
+	d = min(max(2*d, minBackoff), maxBackoff)
+	time.Sleep(d)
+	return d
+}
+
+// reap delivers the completions to the in flight requests.
+func (r *Ring) reap(inflight map[uint64]*request) {
+	head, tail := *r.cqHead, atomic.LoadUint32(r.cqTail)
+	for ; head != tail; head++ {
+		c := r.cqes[head&r.cqMask]
+		req, ok := inflight[c.userData]
+		if !ok {
+			continue
+		}
+		delete(inflight, c.userData)
+
+		if c.res < 0 {
+			req.complete(result{err: unix.Errno(-c.res)})
+		} else {
+			req.complete(result{n: int(c.res)})
+		}
+	}
+	atomic.StoreUint32(r.cqHead, head)
+}
+
+// complete unpins the buffer and delivers the result of the request.
+func (req *request) complete(res result) {
+	req.pinner.Unpin()
+	req.done <- res
+}
+
+// mmap maps the submission queue, the completion queue and the submission queue entries.
+func (r *Ring) mmap(p *params) error {
+	var err error
+	prot, flags := unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE
+	if r.sqRing, err = unix.Mmap(r.fd, offSQRing, int(p.sqOff.array+p.sqEntries*4), prot, flags); err != nil {
+		return os.NewSyscallError("mmap", err)
+	}
+
+	if r.cqRing, err = unix.Mmap(r.fd, offCQRing, int(p.cqOff.cqes+p.cqEntries*uint32(unsafe.Sizeof(cqe{}))), prot, flags); err != nil {
+		return os.NewSyscallError("mmap", err)
+	}
+
+	if r.sqeRing, err = unix.Mmap(r.fd, offSQEs, int(p.sqEntries*uint32(unsafe.Sizeof(sqe{}))), prot, flags); err != nil {
+		return os.NewSyscallError("mmap", err)
+	}
+
+	r.sqHead = (*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.head]))
+	r.sqTail = (*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.tail]))
+	r.sqMask = *(*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.ringMask]))
+	r.sqArray = unsafe.Slice((*uint32)(unsafe.Pointer(&r.sqRing[p.sqOff.array])), p.sqEntries)
+	r.sqes = unsafe.Slice((*sqe)(unsafe.Pointer(&r.sqeRing[0])), p.sqEntries)
+
+	r.cqHead = (*uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.head]))
+	r.cqTail = (*uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.tail]))
+	r.cqMask = *(*uint32)(unsafe.Pointer(&r.cqRing[p.cqOff.ringMask]))
+	r.cqes = unsafe.Slice((*cqe)(unsafe.Pointer(&r.cqRing[p.cqOff.cqes])), p.cqEntries)
+	return nil
+}
+
+// munmap unmaps the rings.
+func (r *Ring) munmap() {
+	for _, b := range [][]byte{r.sqRing, r.cqRing, r.sqeRing} {
+		if b != nil {
+			_ = unix.Munmap(b)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+//go:build !linux
+
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package uring
+
+import "os"
+
+// Ring is not supported on this platform.
+type Ring struct{}
+
+// New returns ErrNotSupported on this platform.
+func New(entries uint32) (*Ring, error) {
+	return nil, ErrNotSupported
+}
+
+// ReadAt returns ErrNotSupported on this platform.
+func (r *Ring) ReadAt(file *os.File, p []byte, off int64) (int, error) {
+	return 0, ErrNotSupported
+}
+
+// WriteAt returns ErrNotSupported on this platform.
+func (r *Ring) WriteAt(file *os.File, p []byte, off int64) (int, error) {
+	return 0, ErrNotSupported
+}
+
+// Close returns ErrNotSupported on this platform.
+func (r *Ring) Close() error {
+	return ErrNotSupported
+}
@@ -0,0 +1,200 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package uring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRing(t *testing.T) *Ring {
+	r, err := New(8)
+	if errors.Is(err, ErrNotSupported) {
+		t.Skipf("skip test: %s", err)
+	}
+	assert.Nil(t, err)
+
+	t.Cleanup(func() {
+		assert.Nil(t, r.Close())
+	})
+	return r
+}
+
+func TestRing_ReadWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		off    int64
+		expect func(t *testing.T, r *Ring, file *os.File, data []byte, off int64)
+	}{
+		{
+			name: "write and read at offset",
+			data: []byte("foo"),
+			off:  4,
+			expect: func(t *testing.T, r *Ring, file *os.File, data []byte, off int64) {
+				assert := assert.New(t)
+				n, err := r.WriteAt(file, data, off)
+				assert.Nil(err)
+				assert.Equal(len(data), n)
+
+				p := make([]byte, len(data))
+				n, err = r.ReadAt(file, p, off)
+				assert.Nil(err)
+				assert.Equal(len(data), n)
+				assert.Equal(data, p)
+			},
+		},
+		{
+			name: "read beyond end of file",
+			data: []byte("foo"),
+			off:  0,
+			expect: func(t *testing.T, r *Ring, file *os.File, data []byte, off int64) {
+				assert := assert.New(t)
+				_, err := r.WriteAt(file, data, off)
+				assert.Nil(err)
+
+				p := make([]byte, len(data)+1)
+				n, err := r.ReadAt(file, p, off)
+				assert.ErrorIs(err, io.EOF)
+				assert.Equal(len(data), n)
+			},
+		},
+		{
+			name: "read and write empty buffer",
+			data: []byte{},
+			off:  0,
+			expect: func(t *testing.T, r *Ring, file *os.File, data []byte, off int64) {
+				assert := assert.New(t)
+				n, err := r.WriteAt(file, data, off)
+				assert.Nil(err)
+				assert.Equal(0, n)
+
+				n, err = r.ReadAt(file, nil, off)
+				assert.Nil(err)
+				assert.Equal(0, n)
+
+				n, err = r.submit(file, opRead, nil, off)
+				assert.Nil(err)
+				assert.Equal(0, n)
+			},
+		},
+		{
+			name: "read with closed file",
+			data: []byte("foo"),
+			off:  0,
+			expect: func(t *testing.T, r *Ring, file *os.File, data []byte, off int64) {
+				assert := assert.New(t)
+				fd, err := os.Open(file.Name())
+				assert.Nil(err)
+				assert.Nil(fd.Close())
+
+				_, err = r.ReadAt(fd, make([]byte, 1), off)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestRing(t)
+			file, err := os.Create(filepath.Join(t.TempDir(), "data"))
+			assert.Nil(t, err)
+			defer file.Close()
+
+			tc.expect(t, r, file, tc.data, tc.off)
+		})
+	}
+}
+
+func TestRing_ConcurrentWrites(t *testing.T) {
+	assert := assert.New(t)
+	r := newTestRing(t)
+	file, err := os.Create(filepath.Join(t.TempDir(), "data"))
+	assert.Nil(err)
+	defer file.Close()
+
+	const pieces, pieceSize = 64, 1024
+	var wg sync.WaitGroup
+	for i := 0; i < pieces; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			data := bytes.Repeat([]byte{byte(i)}, pieceSize)
+			n, err := r.WriteAt(file, data, int64(i*pieceSize))
+			assert.Nil(err)
+			assert.Equal(pieceSize, n)
+		}(i)
+	}
+	wg.Wait()
+
+	content, err := os.ReadFile(file.Name())
+	assert.Nil(err)
+	assert.Len(content, pieces*pieceSize)
+	for i := 0; i < pieces; i++ {
+		assert.Equal(bytes.Repeat([]byte{byte(i)}, pieceSize), content[i*pieceSize:(i+1)*pieceSize], fmt.Sprintf("piece %d", i))
+	}
+}
+
+func TestRing_ConcurrentReads(t *testing.T) {
+	assert := assert.New(t)
+	r := newTestRing(t)
+	file, err := os.Create(filepath.Join(t.TempDir(), "data"))
+	assert.Nil(err)
+	defer file.Close()
+
+	const pieces, pieceSize = 256, 4096
+	for i := 0; i < pieces; i++ {
+		_, err := file.WriteAt(bytes.Repeat([]byte{byte(i)}, pieceSize), int64(i*pieceSize))
+		assert.Nil(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < pieces; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p := make([]byte, pieceSize)
+			n, err := r.ReadAt(file, p, int64(i*pieceSize))
+			assert.Nil(err)
+			assert.Equal(pieceSize, n)
+			assert.Equal(bytes.Repeat([]byte{byte(i)}, pieceSize), p, fmt.Sprintf("piece %d", i))
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestRing_Close(t *testing.T) {
+	assert := assert.New(t)
+	r, err := New(8)
+	if errors.Is(err, ErrNotSupported) {
+		t.Skipf("skip test: %s", err)
+	}
+	assert.Nil(err)
+	assert.Nil(r.Close())
+	assert.Nil(r.Close())
+
+	_, err = r.ReadAt(os.Stdin, make([]byte, 1), 0)
+	assert.ErrorIs(err, ErrClosed)
+}
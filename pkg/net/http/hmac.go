@@ -0,0 +1,140 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"d7y.io/dragonfly/v2/pkg/cache"
+)
+
+const (
+	// HeaderTimestamp is the header of hmac signature timestamp.
+	HeaderTimestamp = "X-Dragonfly-Timestamp"
+
+	// HeaderNonce is the header of hmac signature nonce.
+	HeaderNonce = "X-Dragonfly-Nonce"
+
+	// HeaderSignature is the header of hmac signature.
+	HeaderSignature = "X-Dragonfly-Signature"
+
+	// DefaultHMACMaxSkew is default max clock skew between client and server for hmac signature.
+	DefaultHMACMaxSkew = 5 * time.Minute
+
+	// nonceLength is the length of random bytes of the nonce.
+	nonceLength = 16
+)
+
+// ErrInvalidSignature is returned when the hmac signature of request is invalid.
+var ErrInvalidSignature = errors.New("invalid hmac signature")
+
+// HMACSigner signs and verifies the requests by the hmac-sha256 signature over the method,
+// path, timestamp, nonce and body digest, the replayed nonces are rejected by the verifier.
+type HMACSigner struct {
+	// secret is the secret of hmac signature.
+	secret []byte
+
+	// maxSkew is the max clock skew between client and server.
+	maxSkew time.Duration
+
+	// nonces is the nonces of the verified signatures.
+	nonces cache.Cache
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+}
+
+// NewHMACSigner returns a new HMACSigner instance.
+func NewHMACSigner(secret string, maxSkew time.Duration) *HMACSigner {
+	return &HMACSigner{
+		secret:  []byte(secret),
+		maxSkew: maxSkew,
+		nonces:  cache.New(2*maxSkew, maxSkew),
+		now:     time.Now,
+	}
+}
+
+// Sign sets the hmac signature headers of the request with the body.
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	b := make([]byte, nonceLength)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	timestamp, nonce := strconv.FormatInt(s.now().Unix(), 10), hex.EncodeToString(b)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, s.sign(req.Method, req.URL.Path, timestamp, nonce, body))
+	return nil
+}
+
+// Verify verifies the hmac signature headers of the request, the body of the request
+// is read and replaced by a new reader of the same content.
+func (s *HMACSigner) Verify(req *http.Request) error {
+	timestamp, nonce, signature := req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderNonce), req.Header.Get(HeaderSignature)
+	if timestamp == "" || nonce == "" || signature == "" {
+		return fmt.Errorf("%w: missing signature headers", ErrInvalidSignature)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %s", ErrInvalidSignature, timestamp)
+	}
+
+	skew := s.now().Sub(time.Unix(unix, 0))
+	if skew > s.maxSkew || skew < -s.maxSkew {
+		return fmt.Errorf("%w: timestamp %s is expired", ErrInvalidSignature, timestamp)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(req.Method, req.URL.Path, timestamp, nonce, body))) {
+		return ErrInvalidSignature
+	}
+
+	// The nonce is kept longer than the timestamp is valid.
+	if err := s.nonces.Add(nonce, struct{}{}, 2*s.maxSkew); err != nil {
+		return fmt.Errorf("%w: nonce %s is replayed", ErrInvalidSignature, nonce)
+	}
+
+	return nil
+}
+
+// sign returns the hex encoded hmac-sha256 signature.
+func (s *HMACSigner) sign(method, path, timestamp, nonce string, body []byte) string {
+	digest := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(digest[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
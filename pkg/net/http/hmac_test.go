@@ -0,0 +1,116 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	testifyassert "github.com/stretchr/testify/assert"
+)
+
+func TestHMACSigner(t *testing.T) {
+	tests := []struct {
+		name   string
+		signer *HMACSigner
+		mutate func(req *http.Request) *http.Request
+		expect func(t *testing.T, err error, req *http.Request)
+	}{
+		{
+			name:   "signature is valid",
+			signer: NewHMACSigner("foo", time.Minute),
+			mutate: func(req *http.Request) *http.Request { return req },
+			expect: func(t *testing.T, err error, req *http.Request) {
+				assert := testifyassert.New(t)
+				assert.NoError(err)
+				body, err := io.ReadAll(req.Body)
+				assert.NoError(err)
+				assert.Equal("bar", string(body))
+			},
+		},
+		{
+			name:   "signature of other secret",
+			signer: NewHMACSigner("baz", time.Minute),
+			mutate: func(req *http.Request) *http.Request { return req },
+			expect: func(t *testing.T, err error, req *http.Request) {
+				testifyassert.True(t, errors.Is(err, ErrInvalidSignature))
+			},
+		},
+		{
+			name:   "signature with other body",
+			signer: NewHMACSigner("foo", time.Minute),
+			mutate: func(req *http.Request) *http.Request {
+				req.Body = io.NopCloser(bytes.NewBufferString("baz"))
+				return req
+			},
+			expect: func(t *testing.T, err error, req *http.Request) {
+				testifyassert.True(t, errors.Is(err, ErrInvalidSignature))
+			},
+		},
+		{
+			name:   "signature without headers",
+			signer: NewHMACSigner("foo", time.Minute),
+			mutate: func(req *http.Request) *http.Request {
+				req.Header.Del(HeaderSignature)
+				return req
+			},
+			expect: func(t *testing.T, err error, req *http.Request) {
+				testifyassert.True(t, errors.Is(err, ErrInvalidSignature))
+			},
+		},
+		{
+			name: "signature is expired",
+			signer: func() *HMACSigner {
+				s := NewHMACSigner("foo", time.Minute)
+				s.now = func() time.Time { return time.Now().Add(time.Hour) }
+				return s
+			}(),
+			mutate: func(req *http.Request) *http.Request { return req },
+			expect: func(t *testing.T, err error, req *http.Request) {
+				testifyassert.True(t, errors.Is(err, ErrInvalidSignature))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "http://localhost/foo", bytes.NewBufferString("bar"))
+			if err := NewHMACSigner("foo", time.Minute).Sign(req, []byte("bar")); err != nil {
+				t.Fatal(err)
+			}
+
+			req = tc.mutate(req)
+			tc.expect(t, tc.signer.Verify(req), req)
+		})
+	}
+}
+
+func TestHMACSigner_replay(t *testing.T) {
+	signer := NewHMACSigner("foo", time.Minute)
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost/foo", bytes.NewBufferString("bar"))
+	if err := signer.Sign(req, []byte("bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	assert := testifyassert.New(t)
+	assert.NoError(signer.Verify(req))
+	assert.True(errors.Is(signer.Verify(req), ErrInvalidSignature))
+}
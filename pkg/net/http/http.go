@@ -17,9 +17,13 @@
 package http
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -58,6 +62,28 @@ func PickHeader(header http.Header, key, defaultValue string) string {
 	return defaultValue
 }
 
+// IsTLSHandshakeError returns whether the request error is caused by the tls handshake,
+// the server closing the connection in handshake is also regarded as the failure.
+func IsTLSHandshakeError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// The http transport replaces the record header error by the plain error
+	// when the server serves plain http.
+	if strings.Contains(err.Error(), "server gave HTTP response to HTTPS client") {
+		return true
+	}
+
+	var (
+		recordHeaderError            tls.RecordHeaderError
+		alertError                   tls.AlertError
+		certificateVerificationError *tls.CertificateVerificationError
+	)
+	return errors.As(err, &recordHeaderError) || errors.As(err, &alertError) ||
+		errors.As(err, &certificateVerificationError) || errors.Is(err, io.EOF)
+}
+
 // NewSafeDialer returns a new net.Dialer with safe socket control.
 func NewSafeDialer() *net.Dialer {
 	return &net.Dialer{
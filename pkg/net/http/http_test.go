@@ -17,7 +17,10 @@
 package http
 
 import (
+	"crypto/tls"
+	"errors"
 	"net/http"
+	"net/url"
 	"syscall"
 	"testing"
 
@@ -218,3 +221,42 @@ func Test_safeSocketControl(t *testing.T) {
 		})
 	}
 }
+
+func TestIsTLSHandshakeError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{
+			name:   "record header error",
+			err:    &url.Error{Op: "Get", URL: "https://127.0.0.1", Err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}},
+			expect: true,
+		},
+		{
+			name:   "server gave http response",
+			err:    &url.Error{Op: "Get", URL: "https://127.0.0.1", Err: errors.New("http: server gave HTTP response to HTTPS client")},
+			expect: true,
+		},
+		{
+			name:   "certificate verification error",
+			err:    &url.Error{Op: "Get", URL: "https://127.0.0.1", Err: &tls.CertificateVerificationError{Err: errors.New("foo")}},
+			expect: true,
+		},
+		{
+			name:   "connection refused",
+			err:    &url.Error{Op: "Get", URL: "https://127.0.0.1", Err: syscall.ECONNREFUSED},
+			expect: false,
+		},
+		{
+			name:   "no error",
+			expect: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testifyassert.Equal(t, tc.expect, IsTLSHandshakeError(tc.err))
+		})
+	}
+}
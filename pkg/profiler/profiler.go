@@ -0,0 +1,227 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// DefaultInterval is the default duration of collecting a cpu profile before pushing.
+	DefaultInterval = 15 * time.Second
+
+	// defaultPushTimeout is the default timeout of pushing a profile.
+	defaultPushTimeout = 10 * time.Second
+
+	// ingestPath is the ingest path of the pyroscope compatible server.
+	ingestPath = "/ingest"
+
+	// cpuSampleRate is the sample rate of the go cpu profiler.
+	cpuSampleRate = 100
+)
+
+// Profiler is the interface used for pushing the continuous profiles.
+type Profiler interface {
+	// Serve starts collecting and pushing profiles.
+	Serve()
+
+	// Stop stops collecting and pushing profiles.
+	Stop()
+}
+
+// profiler collects the cpu profiles and pushes them to the pyroscope compatible server.
+type profiler struct {
+	addr       string
+	name       string
+	labels     map[string]string
+	interval   time.Duration
+	httpClient *http.Client
+	done       chan struct{}
+}
+
+// Option is a functional option for configuring the profiler.
+type Option func(p *profiler)
+
+// WithLabels sets the labels of the profiles, like component, cluster and version.
+func WithLabels(labels map[string]string) Option {
+	return func(p *profiler) {
+		for k, v := range labels {
+			p.labels[k] = v
+		}
+	}
+}
+
+// WithInterval sets the duration of collecting a cpu profile before pushing.
+func WithInterval(interval time.Duration) Option {
+	return func(p *profiler) {
+		if interval > 0 {
+			p.interval = interval
+		}
+	}
+}
+
+// WithHTTPClient sets the http client of pushing profiles.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *profiler) {
+		p.httpClient = client
+	}
+}
+
+// New returns a new Profiler interface, addr is the address of the pyroscope compatible server
+// and name is the application name of the profiles.
+func New(addr, name string, options ...Option) Profiler {
+	p := &profiler{
+		addr:       strings.TrimSuffix(addr, "/"),
+		name:       name,
+		labels:     map[string]string{},
+		interval:   DefaultInterval,
+		httpClient: &http.Client{Timeout: defaultPushTimeout},
+		done:       make(chan struct{}),
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	return p
+}
+
+// Serve starts collecting and pushing profiles.
+func (p *profiler) Serve() {
+	logger.Infof("push cpu profiles to %s every %s", p.addr, p.interval)
+	for {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		from := time.Now()
+		profile, err := p.collectCPUProfile()
+		if err != nil {
+			// The cpu profiler may be used by pprof endpoint at the same time, retry in next round.
+			logger.Warnf("collect cpu profile failed: %s", err.Error())
+			select {
+			case <-time.After(p.interval):
+				continue
+			case <-p.done:
+				return
+			}
+		}
+
+		if err := p.push(context.Background(), profile, from, time.Now()); err != nil {
+			logger.Warnf("push cpu profile failed: %s", err.Error())
+		}
+	}
+}
+
+// Stop stops collecting and pushing profiles.
+func (p *profiler) Stop() {
+	close(p.done)
+}
+
+// collectCPUProfile collects the cpu profile during the interval.
+func (p *profiler) collectCPUProfile() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-time.After(p.interval):
+	case <-p.done:
+	}
+
+	pprof.StopCPUProfile()
+	return buf.Bytes(), nil
+}
+
+// push uploads the profile to the ingest api of the pyroscope compatible server.
+func (p *profiler) push(ctx context.Context, profile []byte, from, until time.Time) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+
+	if _, err := part.Write(profile); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("name", p.appName())
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("until", strconv.FormatInt(until.Unix(), 10))
+	query.Set("format", "pprof")
+	query.Set("spyName", "gospy")
+	query.Set("sampleRate", strconv.Itoa(cpuSampleRate))
+	query.Set("units", "samples")
+	query.Set("aggregationType", "sum")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s?%s", p.addr, ingestPath, query.Encode()), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("push profile failed, status code: %d, message: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
+
+// appName returns the application name with the sorted labels, like: dragonfly-scheduler.cpu{cluster=1,component=scheduler}.
+func (p *profiler) appName() string {
+	keys := make([]string, 0, len(p.labels))
+	for k := range p.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	labels := make([]string, 0, len(keys))
+	for _, k := range keys {
+		labels = append(labels, fmt.Sprintf("%s=%s", k, p.labels[k]))
+	}
+
+	return fmt.Sprintf("%s.cpu{%s}", p.name, strings.Join(labels, ","))
+}
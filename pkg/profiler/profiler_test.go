@@ -0,0 +1,134 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package profiler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiler_appName(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		expect  string
+	}{
+		{
+			name:   "profiler without labels",
+			expect: "dragonfly-scheduler.cpu{}",
+		},
+		{
+			name: "profiler with sorted labels",
+			options: []Option{WithLabels(map[string]string{
+				"version":   "v2.1.0",
+				"component": "scheduler",
+				"cluster":   "1",
+			})},
+			expect: "dragonfly-scheduler.cpu{cluster=1,component=scheduler,version=v2.1.0}",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := New("http://127.0.0.1:4040", "dragonfly-scheduler", tc.options...).(*profiler)
+			assert.Equal(t, tc.expect, p.appName())
+		})
+	}
+}
+
+func TestProfiler_push(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expect     func(t *testing.T, r *http.Request, profile []byte, err error)
+	}{
+		{
+			name:       "push profile",
+			statusCode: http.StatusOK,
+			expect: func(t *testing.T, r *http.Request, profile []byte, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(ingestPath, r.URL.Path)
+				assert.Equal("dragonfly-dfdaemon.cpu{component=dfdaemon}", r.URL.Query().Get("name"))
+				assert.Equal("pprof", r.URL.Query().Get("format"))
+				assert.Equal("100", r.URL.Query().Get("from"))
+				assert.Equal("200", r.URL.Query().Get("until"))
+				assert.Equal([]byte("foo"), profile)
+			},
+		},
+		{
+			name:       "push profile failed",
+			statusCode: http.StatusBadRequest,
+			expect: func(t *testing.T, r *http.Request, profile []byte, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "push profile failed, status code: 400, message: bar\n")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req     *http.Request
+				profile []byte
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req = r
+				file, _, err := r.FormFile("profile")
+				if err == nil {
+					profile, _ = io.ReadAll(file)
+				}
+
+				if tc.statusCode != http.StatusOK {
+					http.Error(w, "bar", tc.statusCode)
+				}
+			}))
+			defer server.Close()
+
+			p := New(server.URL, "dragonfly-dfdaemon", WithLabels(map[string]string{"component": "dfdaemon"})).(*profiler)
+			err := p.push(context.Background(), []byte("foo"), time.Unix(100, 0), time.Unix(200, 0))
+			tc.expect(t, req, profile, err)
+		})
+	}
+}
+
+func TestProfiler_Serve(t *testing.T) {
+	pushed := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case pushed <- struct{}{}:
+		default:
+		}
+	}))
+	defer server.Close()
+
+	p := New(server.URL, "dragonfly-scheduler", WithInterval(100*time.Millisecond))
+	go p.Serve()
+	defer p.Stop()
+
+	select {
+	case <-pushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("profile is not pushed")
+	}
+}
@@ -55,6 +55,9 @@ const (
 
 	// ProbedCountNamespace prefix of probed count namespace cache key.
 	ProbedCountNamespace = "probed-count"
+
+	// SnapshotsNamespace prefix of snapshots namespace cache key.
+	SnapshotsNamespace = "snapshots"
 )
 
 // NewRedis returns a new redis client.
@@ -110,6 +113,11 @@ func MakeSchedulersKeyForPeerInManager(hostname, ip string) string {
 	return MakeKeyInManager(PeersNamespace, fmt.Sprintf("%s-%s:schedulers", hostname, ip))
 }
 
+// MakeSchedulersKeyForSchedulerInManager make schedulers key for scheduler in manager.
+func MakeSchedulersKeyForSchedulerInManager(hostname, ip string) string {
+	return MakeKeyInManager(SchedulersNamespace, fmt.Sprintf("%s-%s:schedulers", hostname, ip))
+}
+
 // MakeApplicationsKeyInManager make applications key in manager.
 func MakeApplicationsKeyInManager() string {
 	return MakeNamespaceKeyInManager(ApplicationsNamespace)
@@ -164,3 +172,8 @@ func ParseProbedCountKeyInScheduler(key string) (string, string, string, error)
 func MakeProbedCountKeyInScheduler(hostID string) string {
 	return MakeKeyInScheduler(ProbedCountNamespace, hostID)
 }
+
+// MakeSnapshotKeyInScheduler make snapshot key of the scheduler instance in scheduler.
+func MakeSnapshotKeyInScheduler(hostname, ip string) string {
+	return MakeKeyInScheduler(SnapshotsNamespace, fmt.Sprintf("%s:%s", hostname, ip))
+}
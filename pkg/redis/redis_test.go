@@ -331,6 +331,39 @@ func Test_MakeSchedulersKeyForPeerInManager(t *testing.T) {
 	}
 }
 
+func Test_MakeSchedulersKeyForSchedulerInManager(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		ip       string
+		expect   func(t *testing.T, s string)
+	}{
+		{
+			name:     "make schedulers key for scheduler in manager",
+			hostname: "bar",
+			ip:       "127.0.0.1",
+			expect: func(t *testing.T, s string) {
+				assert := assert.New(t)
+				assert.Equal(s, "manager:schedulers:bar-127.0.0.1:schedulers")
+			},
+		},
+		{
+			name:     "hostname and ip are empty",
+			hostname: "",
+			ip:       "",
+			expect: func(t *testing.T, s string) {
+				assert := assert.New(t)
+				assert.Equal(s, "manager:schedulers:-:schedulers")
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, MakeSchedulersKeyForSchedulerInManager(tc.hostname, tc.ip))
+		})
+	}
+}
+
 func Test_MakeApplicationsKeyInManager(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -669,3 +702,36 @@ func Test_ParseProbedCountKeyInScheduler(t *testing.T) {
 		})
 	}
 }
+
+func Test_MakeSnapshotKeyInScheduler(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		ip       string
+		expect   func(t *testing.T, s string)
+	}{
+		{
+			name:     "make snapshot key in scheduler",
+			hostname: "foo",
+			ip:       "127.0.0.1",
+			expect: func(t *testing.T, s string) {
+				assert := assert.New(t)
+				assert.Equal(s, "scheduler:snapshots:foo:127.0.0.1")
+			},
+		},
+		{
+			name:     "hostname and ip are empty",
+			hostname: "",
+			ip:       "",
+			expect: func(t *testing.T, s string) {
+				assert := assert.New(t)
+				assert.Equal(s, "scheduler:snapshots::")
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, MakeSnapshotKeyInScheduler(tc.hostname, tc.ip))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"regexp"
+)
+
+// ApplicationPoliciesConfigKey is the key of application policies in the client config
+// of scheduler cluster, the daemons enforce the policies by the application name.
+const ApplicationPoliciesConfigKey = "application_policies"
+
+// ApplicationPolicy is the download policy of the application, the priority
+// of the application is carried by the priority field of application.
+type ApplicationPolicy struct {
+	// RateLimit is the download rate limit of the peer in bytes per second,
+	// zero means no limit.
+	RateLimit uint64 `json:"rate_limit"`
+
+	// AllowedURLRegex is the regex which the download urls must match,
+	// empty means all urls are allowed.
+	AllowedURLRegex string `json:"allowed_url_regex"`
+
+	// BackToSourceConcurrency is the max number of peers of the task
+	// downloading back-to-source simultaneously, zero means no limit.
+	BackToSourceConcurrency uint32 `json:"back_to_source_concurrency"`
+
+	// RequireSignature requires the downloads to carry the signature of the artifact,
+	// the daemons fail the downloads without the signature.
+	RequireSignature bool `json:"require_signature"`
+
+	// AntiAffinity is the topology labels of anti-affinity, the parents sharing
+	// any of the labels with the peer are not scheduled.
+	AntiAffinity []string `json:"anti_affinity"`
+}
+
+// IsEmpty returns whether the policy does not restrict the downloads.
+func (p ApplicationPolicy) IsEmpty() bool {
+	return p.RateLimit == 0 && p.AllowedURLRegex == "" && p.BackToSourceConcurrency == 0 &&
+		!p.RequireSignature && len(p.AntiAffinity) == 0
+}
+
+// AllowURL returns whether the url is allowed to download by the policy.
+func (p ApplicationPolicy) AllowURL(url string) (bool, error) {
+	if p.AllowedURLRegex == "" {
+		return true, nil
+	}
+
+	return regexp.MatchString(p.AllowedURLRegex, url)
+}
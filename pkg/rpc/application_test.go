@@ -0,0 +1,110 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplicationPolicy_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ApplicationPolicy
+		expect bool
+	}{
+		{
+			name:   "policy is empty",
+			policy: ApplicationPolicy{},
+			expect: true,
+		},
+		{
+			name:   "policy has rate limit",
+			policy: ApplicationPolicy{RateLimit: 1024},
+			expect: false,
+		},
+		{
+			name:   "policy has anti-affinity",
+			policy: ApplicationPolicy{AntiAffinity: []string{"hypervisor"}},
+			expect: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, tc.policy.IsEmpty())
+		})
+	}
+}
+
+func TestApplicationPolicy_AllowURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ApplicationPolicy
+		url    string
+		expect func(t *testing.T, allowed bool, err error)
+	}{
+		{
+			name:   "allowed url regex is empty",
+			policy: ApplicationPolicy{},
+			url:    "https://example.com/foo",
+			expect: func(t *testing.T, allowed bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.True(allowed)
+			},
+		},
+		{
+			name:   "url matches allowed url regex",
+			policy: ApplicationPolicy{AllowedURLRegex: "^https://example.com/"},
+			url:    "https://example.com/foo",
+			expect: func(t *testing.T, allowed bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.True(allowed)
+			},
+		},
+		{
+			name:   "url does not match allowed url regex",
+			policy: ApplicationPolicy{AllowedURLRegex: "^https://example.com/"},
+			url:    "https://foo.com/bar",
+			expect: func(t *testing.T, allowed bool, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.False(allowed)
+			},
+		},
+		{
+			name:   "allowed url regex is invalid",
+			policy: ApplicationPolicy{AllowedURLRegex: "["},
+			url:    "https://example.com/foo",
+			expect: func(t *testing.T, allowed bool, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+				assert.False(allowed)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, err := tc.policy.AllowURL(tc.url)
+			tc.expect(t, allowed, err)
+		})
+	}
+}
@@ -0,0 +1,340 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"d7y.io/dragonfly/v2/pkg/cache"
+)
+
+const (
+	// AuthorizationMetadataKey is the metadata key of bearer token.
+	AuthorizationMetadataKey = "authorization"
+
+	// TimestampMetadataKey is the metadata key of hmac signature timestamp.
+	TimestampMetadataKey = "x-dragonfly-timestamp"
+
+	// SignatureMetadataKey is the metadata key of hmac signature.
+	SignatureMetadataKey = "x-dragonfly-signature"
+
+	// NonceMetadataKey is the metadata key of hmac signature nonce, the nonces
+	// are rejected when they are seen again within the max clock skew.
+	NonceMetadataKey = "x-dragonfly-nonce"
+
+	// DefaultAuthMaxSkew is default max clock skew between client and server for hmac signature.
+	DefaultAuthMaxSkew = 5 * time.Minute
+
+	// bearerPrefix is the prefix of bearer token in authorization metadata.
+	bearerPrefix = "Bearer "
+
+	// nonceLength is the length of random bytes of the nonce.
+	nonceLength = 16
+)
+
+// defaultAuthSkipMethods is the methods skipped by authentication by default,
+// health check must be available for the clients resolving the addresses.
+var defaultAuthSkipMethods = []string{
+	"/grpc.health.v1.Health/Check",
+	"/grpc.health.v1.Health/Watch",
+}
+
+// AuthOption is a functional option for configuring the authenticator.
+type AuthOption func(a *Authenticator)
+
+// WithAuthToken sets the bearer token.
+func WithAuthToken(token string) AuthOption {
+	return func(a *Authenticator) {
+		a.token = token
+	}
+}
+
+// WithAuthHMACSecret sets the secret of hmac signature.
+func WithAuthHMACSecret(secret string) AuthOption {
+	return func(a *Authenticator) {
+		a.hmacSecret = []byte(secret)
+	}
+}
+
+// WithAuthMaxSkew sets the max clock skew between client and server for hmac signature.
+func WithAuthMaxSkew(maxSkew time.Duration) AuthOption {
+	return func(a *Authenticator) {
+		a.maxSkew = maxSkew
+	}
+}
+
+// WithAuthSkipMethods sets the full methods skipped by authentication.
+func WithAuthSkipMethods(methods ...string) AuthOption {
+	return func(a *Authenticator) {
+		for _, method := range methods {
+			a.skipMethods[method] = struct{}{}
+		}
+	}
+}
+
+// Authenticator authenticates the calls by bearer token or hmac signature in metadata.
+type Authenticator struct {
+	// token is the bearer token.
+	token string
+
+	// hmacSecret is the secret of hmac signature.
+	hmacSecret []byte
+
+	// maxSkew is the max clock skew between client and server for hmac signature.
+	maxSkew time.Duration
+
+	// skipMethods is the full methods skipped by authentication.
+	skipMethods map[string]struct{}
+
+	// nonces is the nonces of the authenticated signatures, they are
+	// kept until the timestamps of the signatures are expired.
+	nonces cache.Cache
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+}
+
+// NewAuthenticator returns an Authenticator instance, authentication is
+// disabled if neither token nor hmac secret is set.
+func NewAuthenticator(opts ...AuthOption) *Authenticator {
+	a := &Authenticator{
+		maxSkew:     DefaultAuthMaxSkew,
+		skipMethods: make(map[string]struct{}),
+		now:         time.Now,
+	}
+
+	for _, method := range defaultAuthSkipMethods {
+		a.skipMethods[method] = struct{}{}
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if len(a.hmacSecret) > 0 {
+		a.nonces = cache.New(2*a.maxSkew, a.maxSkew)
+	}
+
+	return a
+}
+
+// Enabled returns whether authentication is enabled.
+func (a *Authenticator) Enabled() bool {
+	return a.token != "" || len(a.hmacSecret) > 0
+}
+
+// AuthDialOptions returns the dial options that inject the credentials into the calls.
+func AuthDialOptions(a *Authenticator) []grpc.DialOption {
+	if !a.Enabled() {
+		return nil
+	}
+
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(AuthUnaryClientInterceptor(a)),
+		grpc.WithChainStreamInterceptor(AuthStreamClientInterceptor(a)),
+	}
+}
+
+// AuthServerOptions returns the server options that authenticate the calls.
+func AuthServerOptions(a *Authenticator) []grpc.ServerOption {
+	if !a.Enabled() {
+		return nil
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(AuthUnaryServerInterceptor(a)),
+		grpc.ChainStreamInterceptor(AuthStreamServerInterceptor(a)),
+	}
+}
+
+// AuthUnaryServerInterceptor returns a new unary server interceptor that authenticates the calls.
+func AuthUnaryServerInterceptor(a *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authenticate(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamServerInterceptor returns a new stream server interceptor that authenticates the streams,
+// the signatures of streams are not bound to the messages which are sent after the metadata.
+func AuthStreamServerInterceptor(a *Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authenticate(ss.Context(), info.FullMethod, nil); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// AuthUnaryClientInterceptor returns a new unary client interceptor that injects the credentials.
+func AuthUnaryClientInterceptor(a *Authenticator) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := a.withCredentials(ctx, method, req)
+		if err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// AuthStreamClientInterceptor returns a new stream client interceptor that injects the credentials.
+func AuthStreamClientInterceptor(a *Authenticator) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := a.withCredentials(ctx, method, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// withCredentials appends the credentials of method and request to the outgoing metadata.
+func (a *Authenticator) withCredentials(ctx context.Context, method string, req any) (context.Context, error) {
+	if a.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, AuthorizationMetadataKey, bearerPrefix+a.token)
+	}
+
+	if len(a.hmacSecret) > 0 {
+		digest, err := requestDigest(req)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "digest request error: %s", err)
+		}
+
+		b := make([]byte, nonceLength)
+		if _, err := rand.Read(b); err != nil {
+			return nil, status.Errorf(codes.Internal, "generate nonce error: %s", err)
+		}
+		nonce := hex.EncodeToString(b)
+
+		timestamp := strconv.FormatInt(a.now().Unix(), 10)
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			TimestampMetadataKey, timestamp,
+			NonceMetadataKey, nonce,
+			SignatureMetadataKey, a.sign(method, timestamp, nonce, digest),
+		)
+	}
+
+	return ctx, nil
+}
+
+// authenticate verifies the credentials of method and request in the incoming metadata,
+// the call is authenticated if either bearer token or hmac signature is valid.
+func (a *Authenticator) authenticate(ctx context.Context, method string, req any) error {
+	if !a.Enabled() {
+		return nil
+	}
+
+	if _, ok := a.skipMethods[method]; ok {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	if a.token != "" {
+		for _, authorization := range md.Get(AuthorizationMetadataKey) {
+			token, ok := strings.CutPrefix(authorization, bearerPrefix)
+			if ok && subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1 {
+				return nil
+			}
+		}
+	}
+
+	if len(a.hmacSecret) > 0 {
+		timestamps, nonces, signatures := md.Get(TimestampMetadataKey), md.Get(NonceMetadataKey), md.Get(SignatureMetadataKey)
+		if len(timestamps) > 0 && len(nonces) > 0 && len(signatures) > 0 {
+			return a.verify(method, req, timestamps[0], nonces[0], signatures[0])
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid credentials")
+}
+
+// verify verifies the hmac signature of method and request, the nonce of the
+// valid signature is remembered to reject the replayed signatures.
+func (a *Authenticator) verify(method string, req any, timestamp, nonce, signature string) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "invalid timestamp %s", timestamp)
+	}
+
+	skew := a.now().Sub(time.Unix(unix, 0))
+	if skew > a.maxSkew || skew < -a.maxSkew {
+		return status.Errorf(codes.Unauthenticated, "timestamp %s is expired", timestamp)
+	}
+
+	digest, err := requestDigest(req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "digest request error: %s", err)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(a.sign(method, timestamp, nonce, digest))) {
+		return status.Error(codes.Unauthenticated, "invalid signature")
+	}
+
+	// The nonce is kept longer than the timestamp is valid.
+	if err := a.nonces.Add(nonce, struct{}{}, 2*a.maxSkew); err != nil {
+		return status.Errorf(codes.Unauthenticated, "nonce %s is replayed", nonce)
+	}
+
+	return nil
+}
+
+// sign returns the hex encoded hmac-sha256 signature of method, timestamp, nonce and request digest.
+func (a *Authenticator) sign(method, timestamp, nonce, digest string) string {
+	mac := hmac.New(sha256.New, a.hmacSecret)
+	mac.Write([]byte(method + "\n" + timestamp + "\n" + nonce + "\n" + digest))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestDigest returns the hex encoded sha256 digest of the request, the request is
+// marshaled deterministically, so the client and server get the same digest.
+func requestDigest(req any) (string, error) {
+	h := sha256.New()
+	if msg, ok := req.(proto.Message); ok {
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return "", err
+		}
+
+		h.Write(b)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
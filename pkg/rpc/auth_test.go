@@ -0,0 +1,196 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	tests := []struct {
+		name   string
+		client *Authenticator
+		server *Authenticator
+		method string
+		req    any
+		// serverReq is the request received by server, it is req if nil.
+		serverReq any
+		// mutate mutates the metadata received by server.
+		mutate func(md metadata.MD)
+		expect func(t *testing.T, err error)
+	}{
+		{
+			name:   "authentication is disabled",
+			client: NewAuthenticator(),
+			server: NewAuthenticator(),
+			method: "/foo",
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "token is valid",
+			client: NewAuthenticator(WithAuthToken("foo")),
+			server: NewAuthenticator(WithAuthToken("foo")),
+			method: "/foo",
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "token is invalid",
+			client: NewAuthenticator(WithAuthToken("bar")),
+			server: NewAuthenticator(WithAuthToken("foo")),
+			method: "/foo",
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:   "credentials are missing",
+			client: NewAuthenticator(),
+			server: NewAuthenticator(WithAuthToken("foo")),
+			method: "/foo",
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:   "health check skips authentication",
+			client: NewAuthenticator(),
+			server: NewAuthenticator(WithAuthToken("foo")),
+			method: "/grpc.health.v1.Health/Check",
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "signature is valid",
+			client: NewAuthenticator(WithAuthHMACSecret("foo")),
+			server: NewAuthenticator(WithAuthHMACSecret("foo")),
+			method: "/foo",
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "signature is invalid",
+			client: NewAuthenticator(WithAuthHMACSecret("bar")),
+			server: NewAuthenticator(WithAuthHMACSecret("foo")),
+			method: "/foo",
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:   "nonce is missing",
+			client: NewAuthenticator(WithAuthHMACSecret("foo")),
+			server: NewAuthenticator(WithAuthHMACSecret("foo")),
+			method: "/foo",
+			mutate: func(md metadata.MD) {
+				md.Delete(NonceMetadataKey)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name:      "signature of other request",
+			client:    NewAuthenticator(WithAuthHMACSecret("foo")),
+			server:    NewAuthenticator(WithAuthHMACSecret("foo")),
+			method:    "/foo",
+			req:       wrapperspb.String("bar"),
+			serverReq: wrapperspb.String("baz"),
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "signature is expired",
+			client: func() *Authenticator {
+				a := NewAuthenticator(WithAuthHMACSecret("foo"))
+				a.now = func() time.Time { return time.Now().Add(-time.Hour) }
+				return a
+			}(),
+			server: NewAuthenticator(WithAuthHMACSecret("foo")),
+			method: "/foo",
+			expect: func(t *testing.T, err error) {
+				assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				if tc.mutate != nil {
+					tc.mutate(md)
+				}
+
+				if tc.serverReq != nil {
+					req = tc.serverReq
+				}
+
+				_, err := AuthUnaryServerInterceptor(tc.server)(metadata.NewIncomingContext(ctx, md), req,
+					&grpc.UnaryServerInfo{FullMethod: method},
+					func(ctx context.Context, req any) (any, error) { return nil, nil },
+				)
+				return err
+			}
+
+			tc.expect(t, AuthUnaryClientInterceptor(tc.client)(context.Background(), tc.method, tc.req, nil, nil, invoker))
+		})
+	}
+}
+
+func TestAuthInterceptor_replay(t *testing.T) {
+	client := NewAuthenticator(WithAuthHMACSecret("foo"))
+	server := NewAuthenticator(WithAuthHMACSecret("foo"))
+
+	var md metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := AuthUnaryClientInterceptor(client)(context.Background(), "/foo", wrapperspb.String("bar"), nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticate := func(req any) error {
+		_, err := AuthUnaryServerInterceptor(server)(metadata.NewIncomingContext(context.Background(), md), req,
+			&grpc.UnaryServerInfo{FullMethod: "/foo"},
+			func(ctx context.Context, req any) (any, error) { return nil, nil },
+		)
+		return err
+	}
+
+	assert := assert.New(t)
+	assert.NoError(authenticate(wrapperspb.String("bar")))
+	// the replayed signature is rejected with the same or a different payload
+	assert.Equal(codes.Unauthenticated, status.Code(authenticate(wrapperspb.String("bar"))))
+	assert.Equal(codes.Unauthenticated, status.Code(authenticate(wrapperspb.String("baz"))))
+}
@@ -0,0 +1,59 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// UploadRateLimitMetadataKey is the metadata key of the effective upload rate limit of host in bytes per second.
+	UploadRateLimitMetadataKey = "x-dragonfly-upload-rate-limit"
+)
+
+// ContextWithUploadRateLimit returns the outgoing context carrying the effective upload rate limit of host,
+// the unlimited rate is not carried.
+func ContextWithUploadRateLimit(ctx context.Context, limit uint64) context.Context {
+	if limit == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, UploadRateLimitMetadataKey, strconv.FormatUint(limit, 10))
+}
+
+// UploadRateLimitFromIncomingContext returns the effective upload rate limit of host carried by the incoming context.
+func UploadRateLimitFromIncomingContext(ctx context.Context) (uint64, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, false
+	}
+
+	values := md.Get(UploadRateLimitMetadataKey)
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	limit, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil || limit == 0 {
+		return 0, false
+	}
+
+	return limit, true
+}
@@ -0,0 +1,79 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUploadRateLimit(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() context.Context
+		expect func(t *testing.T, limit uint64, ok bool)
+	}{
+		{
+			name: "propagate upload rate limit",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithUploadRateLimit(context.Background(), 1024))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, limit uint64, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal(uint64(1024), limit)
+			},
+		},
+		{
+			name: "unlimited upload rate limit is not propagated",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithUploadRateLimit(context.Background(), 0))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, limit uint64, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "invalid upload rate limit",
+			ctx: func() context.Context {
+				return metadata.NewIncomingContext(context.Background(), metadata.Pairs(UploadRateLimitMetadataKey, "foo"))
+			},
+			expect: func(t *testing.T, limit uint64, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "context without metadata",
+			ctx:  context.Background,
+			expect: func(t *testing.T, limit uint64, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			limit, ok := UploadRateLimitFromIncomingContext(tc.ctx())
+			tc.expect(t, limit, ok)
+		})
+	}
+}
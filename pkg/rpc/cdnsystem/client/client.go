@@ -24,7 +24,6 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/balancer"
@@ -44,12 +43,17 @@ const (
 	// contextTimeout is timeout of grpc invoke.
 	contextTimeout = 2 * time.Minute
 
-	// maxRetries is maximum number of retries.
-	maxRetries = 3
+	// maxAttempts is maximum number of attempts, including the first call.
+	maxAttempts = 4
 
-	// backoffWaitBetween is waiting for a fixed period of
-	// time between calls in backoff linear.
-	backoffWaitBetween = 500 * time.Millisecond
+	// initBackoff is the backoff of the first retry.
+	initBackoff = 500 * time.Millisecond
+
+	// maxBackoff is the upper limit of backoff.
+	maxBackoff = 5 * time.Second
+
+	// backoffMultiplier is the factor multiplied by backoff after each retry.
+	backoffMultiplier = 2.0
 )
 
 func GetClientByAddr(ctx context.Context, netAddr dfnet.NetAddr, opts ...grpc.DialOption) (Client, error) {
@@ -62,9 +66,9 @@ func GetClientByAddr(ctx context.Context, netAddr dfnet.NetAddr, opts ...grpc.Di
 				rpc.OTELUnaryClientInterceptor(),
 				grpc_prometheus.UnaryClientInterceptor,
 				grpc_zap.UnaryClientInterceptor(logger.GrpcLogger.Desugar()),
-				grpc_retry.UnaryClientInterceptor(
-					grpc_retry.WithMax(maxRetries),
-					grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffWaitBetween)),
+				rpc.RetryUnaryClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
 				),
 			)),
 			grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(
@@ -72,6 +76,10 @@ func GetClientByAddr(ctx context.Context, netAddr dfnet.NetAddr, opts ...grpc.Di
 				rpc.OTELStreamClientInterceptor(),
 				grpc_prometheus.StreamClientInterceptor,
 				grpc_zap.StreamClientInterceptor(logger.GrpcLogger.Desugar()),
+				rpc.RetryStreamClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
 			)),
 		}, opts...)...,
 	)
@@ -101,9 +109,9 @@ func GetClient(ctx context.Context, dynconfig config.DynconfigInterface, opts ..
 				rpc.OTELUnaryClientInterceptor(),
 				grpc_prometheus.UnaryClientInterceptor,
 				grpc_zap.UnaryClientInterceptor(logger.GrpcLogger.Desugar()),
-				grpc_retry.UnaryClientInterceptor(
-					grpc_retry.WithMax(maxRetries),
-					grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffWaitBetween)),
+				rpc.RetryUnaryClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
 				),
 				rpc.RefresherUnaryClientInterceptor(dynconfig),
 			)),
@@ -112,6 +120,10 @@ func GetClient(ctx context.Context, dynconfig config.DynconfigInterface, opts ..
 				rpc.OTELStreamClientInterceptor(),
 				grpc_prometheus.StreamClientInterceptor,
 				grpc_zap.StreamClientInterceptor(logger.GrpcLogger.Desugar()),
+				rpc.RetryStreamClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
 				rpc.RefresherStreamClientInterceptor(dynconfig),
 			)),
 		}, opts...)...,
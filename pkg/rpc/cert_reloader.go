@@ -0,0 +1,279 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// DefaultCertReloadInterval is default interval of checking the certificate files.
+	DefaultCertReloadInterval = 30 * time.Second
+)
+
+// CertReloaderOption is a functional option for configuring the cert reloader.
+type CertReloaderOption func(r *CertReloader)
+
+// WithCertReloadInterval sets the interval of checking the certificate files.
+func WithCertReloadInterval(interval time.Duration) CertReloaderOption {
+	return func(r *CertReloader) {
+		r.interval = interval
+	}
+}
+
+// CertReloader watches the certificate, private key and CA bundle files, and reloads them
+// when the content is changed, so the rotated certificates take effect without restarting.
+type CertReloader struct {
+	// certFile is the certificate file.
+	certFile string
+
+	// keyFile is the private key file.
+	keyFile string
+
+	// caFile is the CA bundle file verifying the peers.
+	caFile string
+
+	// interval is the interval of checking the files.
+	interval time.Duration
+
+	// mu protects the fields below.
+	mu sync.RWMutex
+
+	// cert is the current certificate.
+	cert *tls.Certificate
+
+	// caPool is the current CA bundle.
+	caPool *x509.CertPool
+
+	// data is the content of files loaded last time.
+	data [][]byte
+
+	// done is the channel of stopping watching.
+	done chan struct{}
+}
+
+// NewCertReloader returns a CertReloader instance with the files loaded.
+func NewCertReloader(certFile, keyFile, caFile string, opts ...CertReloaderOption) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		interval: DefaultCertReloadInterval,
+		done:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Serve checks the files periodically and reloads them when changed.
+func (r *CertReloader) Serve() {
+	tick := time.NewTicker(r.interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				logger.Errorf("reload certificate %s failed: %s", r.certFile, err.Error())
+				continue
+			}
+
+			if reloaded {
+				logger.Infof("certificate %s is reloaded", r.certFile)
+			}
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// Stop stops watching the files.
+func (r *CertReloader) Stop() {
+	close(r.done)
+}
+
+// Reload loads the files if the content is changed, the current certificates
+// are kept if the new files are invalid.
+func (r *CertReloader) Reload() (bool, error) {
+	var data [][]byte
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return false, err
+		}
+
+		data = append(data, b)
+	}
+
+	r.mu.RLock()
+	changed := !equalData(r.data, data)
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(data[0], data[1])
+	if err != nil {
+		return false, err
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(data[2]) {
+		return false, errors.New("invalid CA Cert")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.caPool = caPool
+	r.data = data
+	return true, nil
+}
+
+// GetCertificate returns the current certificate for tls server.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// GetClientCertificate returns the current certificate for tls client.
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// CAPool returns the current CA bundle.
+func (r *CertReloader) CAPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.caPool
+}
+
+// ServerTLSConfig returns the tls config of server, the client certificates are verified
+// by the current CA bundle if tlsVerify is true.
+func (r *CertReloader) ServerTLSConfig(tlsVerify bool) *tls.Config {
+	clientAuth := tls.NoClientCert
+	if tlsVerify {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				GetCertificate: r.GetCertificate,
+				ClientCAs:      r.CAPool(),
+				ClientAuth:     clientAuth,
+			}, nil
+		},
+	}
+}
+
+// ClientTLSConfig returns the tls config of client, the server certificates are verified
+// by the current CA bundle, so the rotated CA bundle takes effect for the new connections.
+func (r *CertReloader) ClientTLSConfig() *tls.Config {
+	return &tls.Config{
+		GetClientCertificate: r.GetClientCertificate,
+		// The default verification uses the static RootCAs, it is replaced
+		// by VerifyConnection with the current CA bundle.
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("missing server certificate")
+			}
+
+			opts := x509.VerifyOptions{
+				Roots:         r.CAPool(),
+				Intermediates: x509.NewCertPool(),
+			}
+
+			// Server name is the host of target, refer to credentials.NewTLS.
+			if host, _, err := net.SplitHostPort(cs.ServerName); err == nil {
+				opts.DNSName = host
+			} else {
+				opts.DNSName = cs.ServerName
+			}
+
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		},
+	}
+}
+
+// NewServerCredentialsByCertReloader returns server transport credentials by cert reloader.
+func NewServerCredentialsByCertReloader(tlsPolicy string, tlsVerify bool, r *CertReloader) (credentials.TransportCredentials, error) {
+	return newCredentialsByTLSPolicy(tlsPolicy, r.ServerTLSConfig(tlsVerify))
+}
+
+// NewClientCredentialsByCertReloader returns client transport credentials by cert reloader.
+func NewClientCredentialsByCertReloader(tlsPolicy string, r *CertReloader) (credentials.TransportCredentials, error) {
+	return newCredentialsByTLSPolicy(tlsPolicy, r.ClientTLSConfig())
+}
+
+// newCredentialsByTLSPolicy returns transport credentials of tls config by tls policy.
+func newCredentialsByTLSPolicy(tlsPolicy string, tlsConfig *tls.Config) (credentials.TransportCredentials, error) {
+	switch tlsPolicy {
+	case DefaultTLSPolicy, PreferTLSPolicy:
+		return NewMuxTransportCredentials(tlsConfig,
+			WithTLSPreferClientHandshake(tlsPolicy == PreferTLSPolicy)), nil
+	case ForceTLSPolicy:
+		return credentials.NewTLS(tlsConfig), nil
+	default:
+		return nil, fmt.Errorf("invalid tlsPolicy: %s", tlsPolicy)
+	}
+}
+
+// equalData returns whether the contents of files are equal.
+func equalData(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
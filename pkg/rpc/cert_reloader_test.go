@@ -0,0 +1,156 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCert is the certificate and private key in PEM format.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert issues a certificate signed by parent, the certificate is self-signed if parent is nil.
+func newTestCert(t *testing.T, commonName string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	parentCert, parentKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeTestCert writes the certificate, private key and CA bundle to dir.
+func writeTestCert(t *testing.T, dir string, cert, ca *testCert) (string, string, string) {
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	assert.NoError(t, os.WriteFile(certFile, cert.certPEM, 0600))
+	assert.NoError(t, os.WriteFile(keyFile, cert.keyPEM, 0600))
+	assert.NoError(t, os.WriteFile(caFile, ca.certPEM, 0600))
+	return certFile, keyFile, caFile
+}
+
+func TestCertReloader_Reload(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	ca := newTestCert(t, "ca", nil)
+	certFile, keyFile, caFile := writeTestCert(t, dir, newTestCert(t, "foo", ca), ca)
+
+	r, err := NewCertReloader(certFile, keyFile, caFile)
+	assert.NoError(err)
+	cert, err := r.GetCertificate(nil)
+	assert.NoError(err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(err)
+	assert.Equal("foo", leaf.Subject.CommonName)
+
+	// Files are not changed.
+	reloaded, err := r.Reload()
+	assert.NoError(err)
+	assert.False(reloaded)
+
+	// Certificate is rotated.
+	writeTestCert(t, dir, newTestCert(t, "bar", ca), ca)
+	reloaded, err = r.Reload()
+	assert.NoError(err)
+	assert.True(reloaded)
+	cert, err = r.GetClientCertificate(nil)
+	assert.NoError(err)
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(err)
+	assert.Equal("bar", leaf.Subject.CommonName)
+
+	// Invalid files are not loaded.
+	assert.NoError(os.WriteFile(keyFile, []byte("foo"), 0600))
+	_, err = r.Reload()
+	assert.Error(err)
+	cert, err = r.GetCertificate(nil)
+	assert.NoError(err)
+	leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(err)
+	assert.Equal("bar", leaf.Subject.CommonName)
+}
+
+func TestCertReloader_ClientTLSConfig(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "foo", ca)
+	certFile, keyFile, caFile := writeTestCert(t, dir, server, ca)
+
+	r, err := NewCertReloader(certFile, keyFile, caFile)
+	assert.NoError(err)
+
+	verifyConnection := r.ClientTLSConfig().VerifyConnection
+	assert.NoError(verifyConnection(tls.ConnectionState{ServerName: "foo", PeerCertificates: []*x509.Certificate{server.cert}}))
+	assert.Error(verifyConnection(tls.ConnectionState{ServerName: "bar", PeerCertificates: []*x509.Certificate{server.cert}}))
+	assert.Error(verifyConnection(tls.ConnectionState{ServerName: "foo"}))
+
+	// CA bundle is rotated, the certificate signed by old CA is rejected.
+	newCA := newTestCert(t, "ca", nil)
+	newServer := newTestCert(t, "foo", newCA)
+	writeTestCert(t, dir, newServer, newCA)
+	reloaded, err := r.Reload()
+	assert.NoError(err)
+	assert.True(reloaded)
+	assert.Error(verifyConnection(tls.ConnectionState{ServerName: "foo", PeerCertificates: []*x509.Certificate{server.cert}}))
+	assert.NoError(verifyConnection(tls.ConnectionState{ServerName: "foo", PeerCertificates: []*x509.Certificate{newServer.cert}}))
+}
@@ -0,0 +1,382 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultCircuitBreakerFailureRate is default failure rate to open the circuit.
+	DefaultCircuitBreakerFailureRate = 0.5
+
+	// DefaultCircuitBreakerMinRequests is default min requests in window before the circuit can be opened.
+	DefaultCircuitBreakerMinRequests = 20
+
+	// DefaultCircuitBreakerWindow is default window of counting requests.
+	DefaultCircuitBreakerWindow = 10 * time.Second
+
+	// DefaultCircuitBreakerOpenTimeout is default duration of open state before probing.
+	DefaultCircuitBreakerOpenTimeout = 30 * time.Second
+
+	// DefaultCircuitBreakerHalfOpenMaxRequests is default max probing requests in half-open state.
+	DefaultCircuitBreakerHalfOpenMaxRequests = 1
+)
+
+// circuitState is the state of circuit breaker.
+type circuitState int
+
+const (
+	// circuitClosed is the state that requests are allowed.
+	circuitClosed circuitState = iota
+
+	// circuitOpen is the state that requests are rejected.
+	circuitOpen
+
+	// circuitHalfOpen is the state that limited probing requests are allowed.
+	circuitHalfOpen
+)
+
+// defaultCircuitBreakerCodes is the default codes that are counted as failures.
+var defaultCircuitBreakerCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted}
+
+// CircuitBreakerOption is a functional option for configuring the circuit breaker interceptor.
+type CircuitBreakerOption func(c *CircuitBreakerInterceptor)
+
+// WithCircuitBreakerFailureRate sets the failure rate and min requests to open the circuit.
+func WithCircuitBreakerFailureRate(failureRate float64, minRequests int) CircuitBreakerOption {
+	return func(c *CircuitBreakerInterceptor) {
+		c.failureRate = failureRate
+		c.minRequests = minRequests
+	}
+}
+
+// WithCircuitBreakerWindow sets the window of counting requests.
+func WithCircuitBreakerWindow(window time.Duration) CircuitBreakerOption {
+	return func(c *CircuitBreakerInterceptor) {
+		c.window = window
+	}
+}
+
+// WithCircuitBreakerOpenTimeout sets the duration of open state before probing.
+func WithCircuitBreakerOpenTimeout(openTimeout time.Duration) CircuitBreakerOption {
+	return func(c *CircuitBreakerInterceptor) {
+		c.openTimeout = openTimeout
+	}
+}
+
+// WithCircuitBreakerHalfOpenMaxRequests sets the max probing requests in half-open state.
+func WithCircuitBreakerHalfOpenMaxRequests(halfOpenMaxRequests int) CircuitBreakerOption {
+	return func(c *CircuitBreakerInterceptor) {
+		c.halfOpenMaxRequests = halfOpenMaxRequests
+	}
+}
+
+// WithCircuitBreakerCodes sets the codes that are counted as failures.
+func WithCircuitBreakerCodes(failureCodes ...codes.Code) CircuitBreakerOption {
+	return func(c *CircuitBreakerInterceptor) {
+		c.codes = failureCodes
+	}
+}
+
+// WithCircuitBreakerContext sets the function returning a copy of ctx carrying the filter of
+// picked address, then the circuits are tracked per address picked by the balancer instead of
+// the target of connection, e.g. the addresses of schedulers behind the virtual target.
+func WithCircuitBreakerContext(pickContext func(ctx context.Context, filter func(addr string) (func(error), error)) context.Context) CircuitBreakerOption {
+	return func(c *CircuitBreakerInterceptor) {
+		c.pickContext = pickContext
+	}
+}
+
+// CircuitBreakerInterceptor tracks failure rates per address and fast-fails
+// the calls when the circuit of address is open.
+type CircuitBreakerInterceptor struct {
+	// failureRate is the failure rate to open the circuit.
+	failureRate float64
+
+	// minRequests is the min requests in window before the circuit can be opened.
+	minRequests int
+
+	// window is the window of counting requests.
+	window time.Duration
+
+	// openTimeout is the duration of open state before probing.
+	openTimeout time.Duration
+
+	// halfOpenMaxRequests is the max probing requests in half-open state.
+	halfOpenMaxRequests int
+
+	// codes is the codes that are counted as failures.
+	codes []codes.Code
+
+	// pickContext returns a copy of ctx carrying the filter of picked address.
+	pickContext func(ctx context.Context, filter func(addr string) (func(error), error)) context.Context
+
+	// breakers is the circuit breakers keyed by address.
+	breakers map[string]*circuitBreaker
+
+	// mu protects breakers.
+	mu sync.Mutex
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+}
+
+// NewCircuitBreakerInterceptor returns a CircuitBreakerInterceptor instance.
+func NewCircuitBreakerInterceptor(opts ...CircuitBreakerOption) *CircuitBreakerInterceptor {
+	c := &CircuitBreakerInterceptor{
+		failureRate:         DefaultCircuitBreakerFailureRate,
+		minRequests:         DefaultCircuitBreakerMinRequests,
+		window:              DefaultCircuitBreakerWindow,
+		openTimeout:         DefaultCircuitBreakerOpenTimeout,
+		halfOpenMaxRequests: DefaultCircuitBreakerHalfOpenMaxRequests,
+		codes:               defaultCircuitBreakerCodes,
+		breakers:            make(map[string]*circuitBreaker),
+		now:                 time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// UnaryClientInterceptor returns a new unary client interceptor that fast-fails the calls when the circuit is open.
+func (c *CircuitBreakerInterceptor) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if c.pickContext != nil {
+			return invoker(c.pickContext(ctx, c.filter), method, req, reply, cc, opts...)
+		}
+
+		breaker := c.breaker(cc.Target())
+		if err := breaker.allow(c.now()); err != nil {
+			return err
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		c.record(breaker, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a new stream client interceptor that fast-fails
+// establishing the stream when the circuit is open.
+func (c *CircuitBreakerInterceptor) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if c.pickContext != nil {
+			return streamer(c.pickContext(ctx, c.filter), desc, cc, method, opts...)
+		}
+
+		breaker := c.breaker(cc.Target())
+		if err := breaker.allow(c.now()); err != nil {
+			return nil, err
+		}
+
+		clientStream, err := streamer(ctx, desc, cc, method, opts...)
+		c.record(breaker, err)
+		return clientStream, err
+	}
+}
+
+// filter returns an error if the circuit of picked address is open, otherwise
+// returns the function recording the result of request.
+func (c *CircuitBreakerInterceptor) filter(addr string) (func(error), error) {
+	breaker := c.breaker(addr)
+	if err := breaker.allow(c.now()); err != nil {
+		return nil, err
+	}
+
+	return func(err error) {
+		c.record(breaker, err)
+	}, nil
+}
+
+// record records the result of request to circuit breaker, the result is ignored
+// if it tells nothing about the health of address.
+func (c *CircuitBreakerInterceptor) record(breaker *circuitBreaker, err error) {
+	if c.isIgnored(err) {
+		breaker.ignore()
+		return
+	}
+
+	breaker.done(c.now(), c.isFailure(err))
+}
+
+// isIgnored returns whether the error is neither a success nor a failure of address,
+// e.g. the request is canceled by caller or the error is not returned by grpc.
+func (c *CircuitBreakerInterceptor) isIgnored(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return true
+	}
+
+	return s.Code() == codes.Canceled
+}
+
+// isFailure returns whether the error is counted as a failure.
+func (c *CircuitBreakerInterceptor) isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	for _, code := range c.codes {
+		if s.Code() == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// breaker returns the circuit breaker of address.
+func (c *CircuitBreakerInterceptor) breaker(target string) *circuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker, ok := c.breakers[target]
+	if !ok {
+		breaker = &circuitBreaker{
+			target:              target,
+			failureRate:         c.failureRate,
+			minRequests:         c.minRequests,
+			window:              c.window,
+			openTimeout:         c.openTimeout,
+			halfOpenMaxRequests: c.halfOpenMaxRequests,
+			windowStart:         c.now(),
+		}
+		c.breakers[target] = breaker
+	}
+
+	return breaker
+}
+
+// circuitBreaker is the circuit breaker of a single address.
+type circuitBreaker struct {
+	target              string
+	failureRate         float64
+	minRequests         int
+	window              time.Duration
+	openTimeout         time.Duration
+	halfOpenMaxRequests int
+
+	// mu protects the fields below.
+	mu               sync.Mutex
+	state            circuitState
+	requests         int
+	failures         int
+	windowStart      time.Time
+	openedAt         time.Time
+	halfOpenRequests int
+}
+
+// allow returns an error if the request is rejected by circuit breaker.
+func (b *circuitBreaker) allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if now.Sub(b.openedAt) < b.openTimeout {
+			return status.Errorf(codes.Unavailable, "circuit breaker of %s is open", b.target)
+		}
+
+		b.state = circuitHalfOpen
+		b.halfOpenRequests = 0
+		fallthrough
+	case circuitHalfOpen:
+		if b.halfOpenRequests >= b.halfOpenMaxRequests {
+			return status.Errorf(codes.Unavailable, "circuit breaker of %s is half-open", b.target)
+		}
+
+		b.halfOpenRequests++
+	default:
+		if now.Sub(b.windowStart) >= b.window {
+			b.reset(now)
+		}
+	}
+
+	return nil
+}
+
+// done records the result of request.
+func (b *circuitBreaker) done(now time.Time, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitHalfOpen:
+		// Probing request failed, open the circuit again,
+		// otherwise close the circuit.
+		if failed {
+			b.open(now)
+			return
+		}
+
+		b.state = circuitClosed
+		b.reset(now)
+	case circuitClosed:
+		b.requests++
+		if failed {
+			b.failures++
+		}
+
+		if b.requests >= b.minRequests && float64(b.failures)/float64(b.requests) >= b.failureRate {
+			b.open(now)
+		}
+	}
+}
+
+// ignore releases the probing slot of request in half-open state without changing the state,
+// so the circuit keeps half-open until a probing request succeeds or fails.
+func (b *circuitBreaker) ignore() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitHalfOpen && b.halfOpenRequests > 0 {
+		b.halfOpenRequests--
+	}
+}
+
+// open opens the circuit.
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = circuitOpen
+	b.openedAt = now
+	b.halfOpenRequests = 0
+}
+
+// reset resets the counters of window.
+func (b *circuitBreaker) reset(now time.Time) {
+	b.requests = 0
+	b.failures = 0
+	b.windowStart = now
+}
@@ -0,0 +1,189 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCircuitBreakerInterceptor_isFailure(t *testing.T) {
+	c := NewCircuitBreakerInterceptor()
+	assert := assert.New(t)
+	assert.False(c.isFailure(nil))
+	assert.False(c.isFailure(status.Error(codes.NotFound, "")))
+	assert.True(c.isFailure(status.Error(codes.Unavailable, "")))
+	assert.True(c.isFailure(status.Error(codes.DeadlineExceeded, "")))
+}
+
+func TestCircuitBreakerInterceptor_isIgnored(t *testing.T) {
+	c := NewCircuitBreakerInterceptor()
+	assert := assert.New(t)
+	assert.False(c.isIgnored(nil))
+	assert.False(c.isIgnored(status.Error(codes.NotFound, "")))
+	assert.False(c.isIgnored(status.Error(codes.Unavailable, "")))
+	assert.True(c.isIgnored(status.Error(codes.Canceled, "")))
+	assert.True(c.isIgnored(errors.New("foo")))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name   string
+		run    func(t *testing.T, b *circuitBreaker, now time.Time)
+		expect func(t *testing.T, b *circuitBreaker)
+	}{
+		{
+			name: "circuit is closed when failure rate is low",
+			run: func(t *testing.T, b *circuitBreaker, now time.Time) {
+				for i := 0; i < 10; i++ {
+					assert.NoError(t, b.allow(now))
+					b.done(now, i%5 == 0)
+				}
+			},
+			expect: func(t *testing.T, b *circuitBreaker) {
+				assert.Equal(t, circuitClosed, b.state)
+			},
+		},
+		{
+			name: "circuit is opened when failure rate is high",
+			run: func(t *testing.T, b *circuitBreaker, now time.Time) {
+				for i := 0; i < 4; i++ {
+					assert.NoError(t, b.allow(now))
+					b.done(now, true)
+				}
+
+				assert.Equal(t, codes.Unavailable, status.Code(b.allow(now)))
+			},
+			expect: func(t *testing.T, b *circuitBreaker) {
+				assert.Equal(t, circuitOpen, b.state)
+			},
+		},
+		{
+			name: "circuit is closed after probing succeeded",
+			run: func(t *testing.T, b *circuitBreaker, now time.Time) {
+				for i := 0; i < 4; i++ {
+					assert.NoError(t, b.allow(now))
+					b.done(now, true)
+				}
+
+				now = now.Add(time.Minute)
+				assert.NoError(t, b.allow(now))
+				assert.Equal(t, codes.Unavailable, status.Code(b.allow(now)))
+				b.done(now, false)
+			},
+			expect: func(t *testing.T, b *circuitBreaker) {
+				assert.Equal(t, circuitClosed, b.state)
+				assert.Equal(t, 0, b.requests)
+			},
+		},
+		{
+			name: "circuit keeps half-open after probing is ignored",
+			run: func(t *testing.T, b *circuitBreaker, now time.Time) {
+				for i := 0; i < 4; i++ {
+					assert.NoError(t, b.allow(now))
+					b.done(now, true)
+				}
+
+				now = now.Add(time.Minute)
+				assert.NoError(t, b.allow(now))
+				b.ignore()
+				assert.NoError(t, b.allow(now))
+				assert.Equal(t, codes.Unavailable, status.Code(b.allow(now)))
+			},
+			expect: func(t *testing.T, b *circuitBreaker) {
+				assert.Equal(t, circuitHalfOpen, b.state)
+				assert.Equal(t, 1, b.halfOpenRequests)
+			},
+		},
+		{
+			name: "circuit is opened again after probing failed",
+			run: func(t *testing.T, b *circuitBreaker, now time.Time) {
+				for i := 0; i < 4; i++ {
+					assert.NoError(t, b.allow(now))
+					b.done(now, true)
+				}
+
+				now = now.Add(time.Minute)
+				assert.NoError(t, b.allow(now))
+				b.done(now, true)
+				assert.Equal(t, codes.Unavailable, status.Code(b.allow(now)))
+			},
+			expect: func(t *testing.T, b *circuitBreaker) {
+				assert.Equal(t, circuitOpen, b.state)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			b := &circuitBreaker{
+				target:              "foo",
+				failureRate:         0.5,
+				minRequests:         4,
+				window:              time.Hour,
+				openTimeout:         time.Second,
+				halfOpenMaxRequests: 1,
+				windowStart:         now,
+			}
+
+			tc.run(t, b, now)
+			tc.expect(t, b)
+		})
+	}
+}
+
+func TestCircuitBreakerInterceptor_UnaryClientInterceptor(t *testing.T) {
+	assert := assert.New(t)
+	c := NewCircuitBreakerInterceptor(
+		WithCircuitBreakerFailureRate(0.5, 2),
+		WithCircuitBreakerContext(func(ctx context.Context, filter func(addr string) (func(error), error)) context.Context {
+			return context.WithValue(ctx, testPickFilterKey{}, filter)
+		}),
+	)
+
+	// The invoker picks the address in context and calls the filter as the balancer.
+	interceptor := c.UnaryClientInterceptor()
+	invoke := func(addr string, err error) error {
+		return interceptor(context.Background(), "/foo", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			done, pickErr := ctx.Value(testPickFilterKey{}).(func(string) (func(error), error))(addr)
+			if pickErr != nil {
+				return pickErr
+			}
+
+			done(err)
+			return err
+		})
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(codes.Unavailable, status.Code(invoke("127.0.0.1:8002", status.Error(codes.Unavailable, ""))))
+	}
+
+	// The circuit of the failed address is open, and the other addresses are not affected.
+	assert.ErrorContains(invoke("127.0.0.1:8002", nil), "circuit breaker of 127.0.0.1:8002 is open")
+	assert.NoError(invoke("127.0.0.2:8002", nil))
+}
+
+type testPickFilterKey struct{}
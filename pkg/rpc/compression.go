@@ -0,0 +1,276 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// GzipCompressor is the name of gzip compressor.
+	GzipCompressor = gzip.Name
+
+	// ZstdCompressor is the name of zstd compressor.
+	ZstdCompressor = "zstd"
+
+	// DefaultCompressionThreshold is default size of message below which compression is skipped.
+	DefaultCompressionThreshold = 4 * 1024
+)
+
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// CompressionOption is a functional option for configuring the compression.
+type CompressionOption func(c *compression)
+
+// WithCompressionThreshold sets the size of message below which compression is skipped.
+func WithCompressionThreshold(threshold int) CompressionOption {
+	return func(c *compression) {
+		c.threshold = threshold
+	}
+}
+
+// compression compresses the large messages with the compressor.
+type compression struct {
+	// name is the name of compressor.
+	name string
+
+	// threshold is the size of message below which compression is skipped.
+	threshold int
+
+	// unsupportedTargets is the targets which can not decompress the messages.
+	unsupportedTargets sync.Map
+}
+
+// newCompression returns a compression instance.
+func newCompression(name string, opts ...CompressionOption) *compression {
+	c := &compression{
+		name:      name,
+		threshold: DefaultCompressionThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// CompressionDialOptions returns the dial options that compress the requests larger than threshold.
+// Compression is negotiated per connection, if the server can not decompress the request,
+// the request is retried without compression and the later requests of the connection are not compressed.
+func CompressionDialOptions(name string, opts ...CompressionOption) []grpc.DialOption {
+	c := newCompression(name, opts...)
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(c.unaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(c.streamClientInterceptor),
+	}
+}
+
+// CompressionServerOptions returns the server options that compress the responses larger than threshold,
+// if the client supports the compressor.
+func CompressionServerOptions(name string, opts ...CompressionOption) []grpc.ServerOption {
+	c := newCompression(name, opts...)
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.unaryServerInterceptor),
+		grpc.ChainStreamInterceptor(c.streamServerInterceptor),
+	}
+}
+
+// unaryClientInterceptor compresses the request larger than threshold.
+func (c *compression) unaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if !c.shouldCompress(req) || !c.isSupported(cc.Target()) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.UseCompressor(c.name))...)
+	if !isUnsupportedCompressorError(err) {
+		return err
+	}
+
+	// Server can not decompress the request, disable compression of the connection.
+	c.unsupportedTargets.Store(cc.Target(), struct{}{})
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// streamClientInterceptor compresses the messages of stream, the size of messages
+// is unknown when stream is established, so threshold is not applied.
+func (c *compression) streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if !c.isSupported(cc.Target()) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	return streamer(ctx, desc, cc, method, append(opts, grpc.UseCompressor(c.name))...)
+}
+
+// unaryServerInterceptor compresses the response larger than threshold.
+func (c *compression) unaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	// The response of small message is not compressed, even if the request is compressed.
+	name := encoding.Identity
+	if c.shouldCompress(resp) && c.isClientSupported(ctx) {
+		name = c.name
+	}
+
+	if err := grpc.SetSendCompressor(ctx, name); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// streamServerInterceptor compresses the messages of stream, if the client supports the compressor.
+func (c *compression) streamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if c.isClientSupported(ss.Context()) {
+		if err := grpc.SetSendCompressor(ss.Context(), c.name); err != nil {
+			return err
+		}
+	}
+
+	return handler(srv, ss)
+}
+
+// shouldCompress returns whether the message is larger than threshold.
+func (c *compression) shouldCompress(msg any) bool {
+	message, ok := msg.(proto.Message)
+	if !ok {
+		return false
+	}
+
+	return proto.Size(message) >= c.threshold
+}
+
+// isSupported returns whether the target can decompress the messages.
+func (c *compression) isSupported(target string) bool {
+	_, ok := c.unsupportedTargets.Load(target)
+	return !ok
+}
+
+// isClientSupported returns whether the client of call can decompress the messages.
+func (c *compression) isClientSupported(ctx context.Context) bool {
+	names, err := grpc.ClientSupportedCompressors(ctx)
+	if err != nil {
+		return false
+	}
+
+	for _, name := range names {
+		if name == c.name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnsupportedCompressorError returns whether the error is caused by the server without the compressor.
+func isUnsupportedCompressorError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.Unimplemented {
+		return false
+	}
+
+	return strings.Contains(s.Message(), "Decompressor is not installed")
+}
+
+// zstdCompressor is the grpc compressor of zstd.
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+// zstdWriter is the writer of zstd which is put back to pool after closing.
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+// zstdReader is the reader of zstd which is put back to pool after reading EOF.
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+// Name returns the name of compressor.
+func (c *zstdCompressor) Name() string {
+	return ZstdCompressor
+}
+
+// Compress returns a writer which compresses the bytes into w.
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if encoder, ok := c.encoders.Get().(*zstd.Encoder); ok {
+		encoder.Reset(w)
+		return &zstdWriter{Encoder: encoder, pool: &c.encoders}, nil
+	}
+
+	encoder, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &zstdWriter{Encoder: encoder, pool: &c.encoders}, nil
+}
+
+// Decompress returns a reader which decompresses the bytes from r.
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if decoder, ok := c.decoders.Get().(*zstd.Decoder); ok {
+		if err := decoder.Reset(r); err != nil {
+			c.decoders.Put(decoder)
+			return nil, err
+		}
+
+		return &zstdReader{Decoder: decoder, pool: &c.decoders}, nil
+	}
+
+	decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &zstdReader{Decoder: decoder, pool: &c.decoders}, nil
+}
+
+// Close flushes the compressed bytes and puts the encoder back to pool.
+func (w *zstdWriter) Close() error {
+	defer w.pool.Put(w.Encoder)
+	return w.Encoder.Close()
+}
+
+// Read reads the decompressed bytes and puts the decoder back to pool after reading EOF.
+func (r *zstdReader) Read(p []byte) (int, error) {
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Decoder)
+	}
+
+	return n, err
+}
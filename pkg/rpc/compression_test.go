@@ -0,0 +1,108 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestZstdCompressor(t *testing.T) {
+	assert := assert.New(t)
+	compressor := encoding.GetCompressor(ZstdCompressor)
+	assert.NotNil(compressor)
+
+	data := []byte(strings.Repeat("dragonfly", 1024))
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		w, err := compressor.Compress(&buf)
+		assert.NoError(err)
+		_, err = w.Write(data)
+		assert.NoError(err)
+		assert.NoError(w.Close())
+		assert.Less(buf.Len(), len(data))
+
+		r, err := compressor.Decompress(&buf)
+		assert.NoError(err)
+		b, err := io.ReadAll(r)
+		assert.NoError(err)
+		assert.Equal(data, b)
+	}
+}
+
+func TestCompression_unaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         any
+		errs        []error
+		compressors []bool
+		supported   bool
+	}{
+		{
+			name:        "skip compression of small message",
+			req:         wrapperspb.String("foo"),
+			errs:        []error{nil},
+			compressors: []bool{false},
+			supported:   true,
+		},
+		{
+			name:        "compress large message",
+			req:         wrapperspb.String(strings.Repeat("foo", 1024)),
+			errs:        []error{nil},
+			compressors: []bool{true},
+			supported:   true,
+		},
+		{
+			name:        "retry without compression if server can not decompress",
+			req:         wrapperspb.String(strings.Repeat("foo", 1024)),
+			errs:        []error{status.Error(codes.Unimplemented, "grpc: Decompressor is not installed for grpc-encoding \"zstd\""), nil},
+			compressors: []bool{true, false},
+			supported:   false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			cc, err := grpc.Dial("foo", grpc.WithTransportCredentials(insecure.NewCredentials()))
+			assert.NoError(err)
+			defer cc.Close()
+
+			var compressors []bool
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				compressors = append(compressors, len(opts) > 0)
+				return tc.errs[len(compressors)-1]
+			}
+
+			c := newCompression(ZstdCompressor, WithCompressionThreshold(1024))
+			assert.NoError(c.unaryClientInterceptor(context.Background(), "/foo", tc.req, nil, cc, invoker))
+			assert.Equal(tc.compressors, compressors)
+			assert.Equal(tc.supported, c.isSupported(cc.Target()))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// TimeoutGetter is the interface used for getting the default timeout of method.
+type TimeoutGetter interface {
+	// GetRequestTimeout returns the default timeout of full method,
+	// returns false if the call of method has no default timeout.
+	GetRequestTimeout(method string) (time.Duration, bool)
+}
+
+// MethodTimeouts is the static default timeouts of methods.
+type MethodTimeouts struct {
+	// Default is the default timeout of methods which are not in Methods.
+	Default time.Duration
+
+	// Methods is the default timeouts keyed by full method.
+	Methods map[string]time.Duration
+}
+
+// GetRequestTimeout returns the default timeout of full method.
+func (m MethodTimeouts) GetRequestTimeout(method string) (time.Duration, bool) {
+	if timeout, ok := m.Methods[method]; ok {
+		return timeout, timeout > 0
+	}
+
+	return m.Default, m.Default > 0
+}
+
+// DeadlineUnaryClientInterceptor returns a new unary client interceptor that injects the
+// default timeout of method if the context of caller has no deadline, the timeout is fetched
+// from getter for each call, so the timeout can be changed by dynconfig.
+func DeadlineUnaryClientInterceptor(getter TimeoutGetter) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			if timeout, ok := getter.GetRequestTimeout(method); ok {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// DeadlineUnaryServerInterceptor returns a new unary server interceptor that propagates the
+// remaining deadline of caller to the handler, and reserves margin for the handler to respond,
+// so the downstream calls using the context of handler time out before the caller gives up.
+func DeadlineUnaryServerInterceptor(margin time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) <= margin {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithDeadline(ctx, deadline.Add(-margin))
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
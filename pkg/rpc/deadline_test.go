@@ -0,0 +1,118 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestMethodTimeouts_GetRequestTimeout(t *testing.T) {
+	m := MethodTimeouts{
+		Default: time.Second,
+		Methods: map[string]time.Duration{
+			"/foo": time.Minute,
+			"/bar": 0,
+		},
+	}
+
+	assert := assert.New(t)
+	timeout, ok := m.GetRequestTimeout("/foo")
+	assert.True(ok)
+	assert.Equal(time.Minute, timeout)
+
+	_, ok = m.GetRequestTimeout("/bar")
+	assert.False(ok)
+
+	timeout, ok = m.GetRequestTimeout("/baz")
+	assert.True(ok)
+	assert.Equal(time.Second, timeout)
+
+	_, ok = MethodTimeouts{}.GetRequestTimeout("/baz")
+	assert.False(ok)
+}
+
+func TestDeadlineUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() (context.Context, context.CancelFunc)
+		expect func(t *testing.T, deadline time.Time, ok bool)
+	}{
+		{
+			name: "inject default timeout",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			expect: func(t *testing.T, deadline time.Time, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.WithinDuration(time.Now().Add(time.Minute), deadline, time.Second)
+			},
+		},
+		{
+			name: "keep deadline of caller",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Hour)
+			},
+			expect: func(t *testing.T, deadline time.Time, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.WithinDuration(time.Now().Add(time.Hour), deadline, time.Second)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			interceptor := DeadlineUnaryClientInterceptor(MethodTimeouts{Default: time.Minute})
+			assert.NoError(t, interceptor(ctx, "/foo", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				deadline, ok := ctx.Deadline()
+				tc.expect(t, deadline, ok)
+				return nil
+			}))
+		})
+	}
+}
+
+func TestDeadlineUnaryServerInterceptor(t *testing.T) {
+	interceptor := DeadlineUnaryServerInterceptor(time.Second)
+	info := &grpc.UnaryServerInfo{FullMethod: "/foo"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute-time.Second), deadline, 100*time.Millisecond)
+		return nil, nil
+	})
+	assert.NoError(t, err)
+
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+		return nil, nil
+	})
+	assert.NoError(t, err)
+}
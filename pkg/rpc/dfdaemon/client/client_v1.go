@@ -24,7 +24,6 @@ import (
 	"github.com/google/uuid"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -49,19 +48,27 @@ func GetV1(ctx context.Context, target string, opts ...grpc.DialOption) (V1, err
 		append([]grpc.DialOption{
 			grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(
 				rpc.ConvertErrorUnaryClientInterceptor,
+				rpc.RequestIDUnaryClientInterceptor,
 				rpc.OTELUnaryClientInterceptor(),
 				grpc_prometheus.UnaryClientInterceptor,
+				rpc.MetricsUnaryClientInterceptor,
 				grpc_zap.UnaryClientInterceptor(logger.GrpcLogger.Desugar()),
-				grpc_retry.UnaryClientInterceptor(
-					grpc_retry.WithMax(maxRetries),
-					grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffWaitBetween)),
+				rpc.RetryUnaryClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
 				),
 			)),
 			grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(
 				rpc.ConvertErrorStreamClientInterceptor,
+				rpc.RequestIDStreamClientInterceptor,
 				rpc.OTELStreamClientInterceptor(),
 				grpc_prometheus.StreamClientInterceptor,
+				rpc.MetricsStreamClientInterceptor,
 				grpc_zap.StreamClientInterceptor(logger.GrpcLogger.Desugar()),
+				rpc.RetryStreamClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
 			)),
 		}, opts...)...,
 	)
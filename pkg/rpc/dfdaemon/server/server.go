@@ -21,8 +21,6 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_ratelimit "github.com/grpc-ecosystem/go-grpc-middleware/ratelimit"
-	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_validator "github.com/grpc-ecosystem/go-grpc-middleware/validator"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -44,6 +42,12 @@ const (
 	// DefaultBurst is default burst of grpc server.
 	DefaultBurst = 20 * 1000
 
+	// DefaultPreheatQPS is default qps of the requests triggered by preheat.
+	DefaultPreheatQPS = 100
+
+	// DefaultPreheatBurst is default burst of the requests triggered by preheat.
+	DefaultPreheatBurst = 200
+
 	// DefaultMaxConnectionIdle is default max connection idle of grpc keepalive.
 	DefaultMaxConnectionIdle = 10 * time.Minute
 
@@ -60,7 +64,11 @@ type DaemonServer interface {
 
 // New returns a grpc server instance and register service on grpc server.
 func New(svr dfdaemonv1.DaemonServer, healthServer healthpb.HealthServer, opts ...grpc.ServerOption) *grpc.Server {
-	limiter := rpc.NewRateLimiterInterceptor(DefaultQPS, DefaultBurst)
+	// Preheat traffic is throttled independently, so it can be shed under load
+	// while the interactive downloads keep flowing.
+	limiter := rpc.NewRateLimiterInterceptor(DefaultQPS, DefaultBurst,
+		rpc.WithPriorityRateLimit(rpc.PreheatPriority, DefaultPreheatQPS, DefaultPreheatBurst),
+	)
 
 	grpcServer := grpc.NewServer(append([]grpc.ServerOption{
 		grpc.KeepaliveParams(keepalive.ServerParameters{
@@ -69,22 +77,26 @@ func New(svr dfdaemonv1.DaemonServer, healthServer healthpb.HealthServer, opts .
 			MaxConnectionAgeGrace: DefaultMaxConnectionAgeGrace,
 		}),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_ratelimit.UnaryServerInterceptor(limiter),
+			limiter.UnaryServerInterceptor(),
 			rpc.ConvertErrorUnaryServerInterceptor,
 			otelgrpc.UnaryServerInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
+			rpc.MetricsUnaryServerInterceptor,
 			grpc_zap.UnaryServerInterceptor(logger.GrpcLogger.Desugar()),
+			rpc.RequestIDUnaryServerInterceptor,
 			grpc_validator.UnaryServerInterceptor(),
-			grpc_recovery.UnaryServerInterceptor(),
+			rpc.RecoveryUnaryServerInterceptor,
 		)),
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
-			grpc_ratelimit.StreamServerInterceptor(limiter),
+			limiter.StreamServerInterceptor(),
 			rpc.ConvertErrorStreamServerInterceptor,
 			otelgrpc.StreamServerInterceptor(),
 			grpc_prometheus.StreamServerInterceptor,
+			rpc.MetricsStreamServerInterceptor,
 			grpc_zap.StreamServerInterceptor(logger.GrpcLogger.Desugar()),
+			rpc.RequestIDStreamServerInterceptor,
 			grpc_validator.StreamServerInterceptor(),
-			grpc_recovery.StreamServerInterceptor(),
+			rpc.RecoveryStreamServerInterceptor,
 		)),
 	}, opts...)...)
 
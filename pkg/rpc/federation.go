@@ -0,0 +1,93 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+)
+
+const (
+	// FederationServiceName is the name of the federation service served by the scheduler.
+	// The service reuses the messages of scheduler v1 instead of extending them, so the wire format
+	// of the scheduler service stays compatible with the schedulers not supporting federation.
+	FederationServiceName = "dragonfly.federation.v1.Federation"
+
+	// FindTaskPeersMethod is the full method name of FindTaskPeers.
+	FindTaskPeersMethod = "/" + FederationServiceName + "/FindTaskPeers"
+)
+
+// FederationServer is the server API for federation service.
+type FederationServer interface {
+	// FindTaskPeers returns the peers holding the task which can serve the peers of the sibling cluster,
+	// the peers are carried by the candidate peers of the packet.
+	FindTaskPeers(context.Context, *schedulerv1.StatTaskRequest) (*schedulerv1.PeerPacket, error)
+}
+
+// RegisterFederationServer registers the federation service on the grpc server.
+func RegisterFederationServer(s grpc.ServiceRegistrar, srv FederationServer) {
+	s.RegisterService(&federationServiceDesc, srv)
+}
+
+// FindTaskPeers returns the peers holding the task in the sibling scheduler,
+// the scheduler not supporting federation returns the unimplemented error.
+func FindTaskPeers(ctx context.Context, cc grpc.ClientConnInterface, req *schedulerv1.StatTaskRequest, opts ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error) {
+	packet := new(schedulerv1.PeerPacket)
+	if err := cc.Invoke(ctx, FindTaskPeersMethod, req, packet, opts...); err != nil {
+		return nil, err
+	}
+
+	return packet.CandidatePeers, nil
+}
+
+// federationServiceDesc is the grpc service descriptor for federation service.
+var federationServiceDesc = grpc.ServiceDesc{
+	ServiceName: FederationServiceName,
+	HandlerType: (*FederationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindTaskPeers",
+			Handler:    findTaskPeersHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/rpc/federation.go",
+}
+
+// findTaskPeersHandler handles FindTaskPeers of federation service.
+func findTaskPeersHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := new(schedulerv1.StatTaskRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(FederationServer).FindTaskPeers(ctx, req)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FindTaskPeersMethod,
+	}
+
+	return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return srv.(FederationServer).FindTaskPeers(ctx, req.(*schedulerv1.StatTaskRequest))
+	})
+}
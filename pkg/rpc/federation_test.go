@@ -0,0 +1,103 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+)
+
+type mockFederationServer struct {
+	peers []*schedulerv1.PeerPacket_DestPeer
+}
+
+func (s *mockFederationServer) FindTaskPeers(ctx context.Context, req *schedulerv1.StatTaskRequest) (*schedulerv1.PeerPacket, error) {
+	if req.TaskId != "foo" {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+
+	return &schedulerv1.PeerPacket{TaskId: req.TaskId, CandidatePeers: s.peers}, nil
+}
+
+func TestFindTaskPeers(t *testing.T) {
+	peers := []*schedulerv1.PeerPacket_DestPeer{
+		{Ip: "127.0.0.1", RpcPort: 8003, PeerId: "foo"},
+		{Ip: "127.0.0.2", RpcPort: 8003, PeerId: "bar"},
+	}
+
+	tests := []struct {
+		name     string
+		register func(*grpc.Server)
+		req      *schedulerv1.StatTaskRequest
+		expect   func(t *testing.T, peers []*schedulerv1.PeerPacket_DestPeer, err error)
+	}{
+		{
+			name: "find task peers",
+			register: func(s *grpc.Server) {
+				RegisterFederationServer(s, &mockFederationServer{peers: peers})
+			},
+			req: &schedulerv1.StatTaskRequest{TaskId: "foo"},
+			expect: func(t *testing.T, federatedPeers []*schedulerv1.PeerPacket_DestPeer, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Len(federatedPeers, len(peers))
+				for i, peer := range federatedPeers {
+					assert.Equal(peers[i].PeerId, peer.PeerId)
+					assert.Equal(peers[i].Ip, peer.Ip)
+					assert.Equal(peers[i].RpcPort, peer.RpcPort)
+				}
+			},
+		},
+		{
+			name: "task not found",
+			register: func(s *grpc.Server) {
+				RegisterFederationServer(s, &mockFederationServer{peers: peers})
+			},
+			req: &schedulerv1.StatTaskRequest{TaskId: "bar"},
+			expect: func(t *testing.T, federatedPeers []*schedulerv1.PeerPacket_DestPeer, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.NotFound, status.Code(err))
+				assert.Empty(federatedPeers)
+			},
+		},
+		{
+			name:     "scheduler does not support federation",
+			register: func(s *grpc.Server) {},
+			req:      &schedulerv1.StatTaskRequest{TaskId: "foo"},
+			expect: func(t *testing.T, federatedPeers []*schedulerv1.PeerPacket_DestPeer, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.Unimplemented, status.Code(err))
+				assert.Empty(federatedPeers)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := dialBufconn(t, tc.register)
+			federatedPeers, err := FindTaskPeers(context.Background(), conn, tc.req)
+			tc.expect(t, federatedPeers, err)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultHedgingMaxAttempts is default max attempts of hedging interceptor, including the first call.
+	DefaultHedgingMaxAttempts = 2
+)
+
+// HedgingOption is a functional option for configuring the hedging interceptor.
+type HedgingOption func(h *hedgingInterceptor)
+
+// WithHedgingMaxAttempts sets the max attempts of a call, including the first call.
+func WithHedgingMaxAttempts(maxAttempts int) HedgingOption {
+	return func(h *hedgingInterceptor) {
+		h.maxAttempts = maxAttempts
+	}
+}
+
+// WithHedgingMethods sets the full methods that can be hedged, only idempotent
+// methods should be hedged. If no methods are set, all methods are hedged.
+func WithHedgingMethods(methods ...string) HedgingOption {
+	return func(h *hedgingInterceptor) {
+		for _, method := range methods {
+			h.methods[method] = struct{}{}
+		}
+	}
+}
+
+// WithHedgingContext sets the function decorating the context of hedged attempt,
+// it is used to route the hedged attempt to a secondary address, e.g. balancer.WithHedgingAttempt.
+func WithHedgingContext(withAttempt func(ctx context.Context, attempt int) context.Context) HedgingOption {
+	return func(h *hedgingInterceptor) {
+		h.withAttempt = withAttempt
+	}
+}
+
+// hedgingInterceptor fires duplicate requests after delay and uses the first response.
+type hedgingInterceptor struct {
+	// delay is the waiting duration before firing the next hedged attempt.
+	delay time.Duration
+
+	// maxAttempts is max attempts of a call, including the first call.
+	maxAttempts int
+
+	// methods is the full methods that can be hedged.
+	methods map[string]struct{}
+
+	// withAttempt decorates the context of hedged attempt.
+	withAttempt func(ctx context.Context, attempt int) context.Context
+}
+
+// hedgingResult is the result of an attempt.
+type hedgingResult struct {
+	reply proto.Message
+	err   error
+}
+
+// HedgingUnaryClientInterceptor returns a new unary client interceptor that fires a duplicate
+// request after delay if the previous attempts have not returned, uses the first succeeded
+// response and cancels the others.
+func HedgingUnaryClientInterceptor(delay time.Duration, opts ...HedgingOption) grpc.UnaryClientInterceptor {
+	h := &hedgingInterceptor{
+		delay:       delay,
+		maxAttempts: DefaultHedgingMaxAttempts,
+		methods:     make(map[string]struct{}),
+		withAttempt: func(ctx context.Context, attempt int) context.Context { return ctx },
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		// Reply must be cloned for each attempt, so only proto message can be hedged.
+		replyMessage, ok := reply.(proto.Message)
+		if !ok || h.maxAttempts <= 1 || !h.shouldHedge(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		// Cancel the attempts which have not returned.
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan hedgingResult, h.maxAttempts)
+		attempt := func(i int) {
+			attemptReply := replyMessage.ProtoReflect().New().Interface()
+			err := invoker(h.withAttempt(ctx, i), method, req, attemptReply, cc, opts...)
+			results <- hedgingResult{reply: attemptReply, err: err}
+		}
+
+		go attempt(0)
+		timer := time.NewTimer(h.delay)
+		defer timer.Stop()
+
+		var (
+			inflight = 1
+			fired    = 1
+			lastErr  error
+		)
+		for inflight > 0 {
+			select {
+			case <-timer.C:
+				if fired < h.maxAttempts {
+					go attempt(fired)
+					fired++
+					inflight++
+					timer.Reset(h.delay)
+				}
+			case result := <-results:
+				inflight--
+				if result.err == nil {
+					proto.Reset(replyMessage)
+					proto.Merge(replyMessage, result.reply)
+					return nil
+				}
+
+				// The first attempt failed before hedging, return the error directly.
+				if fired == 1 {
+					return result.err
+				}
+
+				lastErr = result.err
+			}
+		}
+
+		return lastErr
+	}
+}
+
+// shouldHedge returns whether the method can be hedged.
+func (h *hedgingInterceptor) shouldHedge(method string) bool {
+	if len(h.methods) == 0 {
+		return true
+	}
+
+	_, ok := h.methods[method]
+	return ok
+}
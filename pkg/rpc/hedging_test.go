@@ -0,0 +1,113 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type hedgingAttemptKey struct{}
+
+func TestHedgingUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []HedgingOption
+		invoker func(ctx context.Context, reply *wrapperspb.StringValue) error
+		expect  func(t *testing.T, reply *wrapperspb.StringValue, err error)
+	}{
+		{
+			name: "first attempt succeeded",
+			invoker: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+				reply.Value = "foo"
+				return nil
+			},
+			expect: func(t *testing.T, reply *wrapperspb.StringValue, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("foo", reply.Value)
+			},
+		},
+		{
+			name: "hedged attempt succeeded before slow attempt",
+			invoker: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+				if ctx.Value(hedgingAttemptKey{}).(int) == 0 {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+
+				reply.Value = "bar"
+				return nil
+			},
+			expect: func(t *testing.T, reply *wrapperspb.StringValue, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("bar", reply.Value)
+			},
+		},
+		{
+			name: "first attempt failed before hedging",
+			invoker: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+				return errors.New("foo")
+			},
+			expect: func(t *testing.T, reply *wrapperspb.StringValue, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "foo")
+			},
+		},
+		{
+			name: "method can not be hedged",
+			opts: []HedgingOption{WithHedgingMethods("/bar")},
+			invoker: func(ctx context.Context, reply *wrapperspb.StringValue) error {
+				if ctx.Value(hedgingAttemptKey{}) != nil {
+					return errors.New("foo")
+				}
+
+				reply.Value = "baz"
+				return nil
+			},
+			expect: func(t *testing.T, reply *wrapperspb.StringValue, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("baz", reply.Value)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			interceptor := HedgingUnaryClientInterceptor(10*time.Millisecond, append([]HedgingOption{
+				WithHedgingContext(func(ctx context.Context, attempt int) context.Context {
+					return context.WithValue(ctx, hedgingAttemptKey{}, attempt)
+				}),
+			}, tc.opts...)...)
+
+			reply := &wrapperspb.StringValue{}
+			err := interceptor(context.Background(), "/foo", nil, reply, nil,
+				func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					return tc.invoker(ctx, reply.(*wrapperspb.StringValue))
+				})
+			tc.expect(t, reply, err)
+		})
+	}
+}
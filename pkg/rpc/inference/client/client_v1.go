@@ -24,7 +24,6 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
 
@@ -38,12 +37,17 @@ const (
 	// contextTimeout is timeout of grpc invoke.
 	contextTimeout = 2 * time.Minute
 
-	// maxRetries is maximum number of retries.
-	maxRetries = 3
+	// maxAttempts is maximum number of attempts, including the first call.
+	maxAttempts = 4
 
-	// backoffWaitBetween is waiting for a fixed period of
-	// time between calls in backoff linear.
-	backoffWaitBetween = 500 * time.Millisecond
+	// initBackoff is the backoff of the first retry.
+	initBackoff = 500 * time.Millisecond
+
+	// maxBackoff is the upper limit of backoff.
+	maxBackoff = 5 * time.Second
+
+	// backoffMultiplier is the factor multiplied by backoff after each retry.
+	backoffMultiplier = 2.0
 )
 
 // GetV1 returns v1 version of the prediction client.
@@ -56,15 +60,19 @@ func GetV1(ctx context.Context, target string, opts ...grpc.DialOption) (V1, err
 				rpc.OTELUnaryClientInterceptor(),
 				grpc_prometheus.UnaryClientInterceptor,
 				grpc_zap.UnaryClientInterceptor(logger.GrpcLogger.Desugar()),
-				grpc_retry.UnaryClientInterceptor(
-					grpc_retry.WithMax(maxRetries),
-					grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffWaitBetween)),
+				rpc.RetryUnaryClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
 				),
 			)),
 			grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(
 				rpc.OTELStreamClientInterceptor(),
 				grpc_prometheus.StreamClientInterceptor,
 				grpc_zap.StreamClientInterceptor(logger.GrpcLogger.Desugar()),
+				rpc.RetryStreamClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
 			)),
 		}, opts...)...,
 	)
@@ -89,6 +97,9 @@ type V1 interface {
 	// ServerReady checks readiness of the inference server.
 	ServerReady(context.Context, *inferencev1.ServerReadyRequest, ...grpc.CallOption) (*inferencev1.ServerReadyResponse, error)
 
+	// RepositoryModelLoad loads or reloads a model from the model repository.
+	RepositoryModelLoad(context.Context, *inferencev1.RepositoryModelLoadRequest, ...grpc.CallOption) (*inferencev1.RepositoryModelLoadResponse, error)
+
 	// Close tears down the ClientConn and all underlying connections.
 	Close() error
 }
@@ -122,3 +133,11 @@ func (v *v1) ServerReady(ctx context.Context, req *inferencev1.ServerReadyReques
 
 	return v.GRPCInferenceServiceClient.ServerReady(ctx, req, opts...)
 }
+
+// RepositoryModelLoad loads or reloads a model from the model repository.
+func (v *v1) RepositoryModelLoad(ctx context.Context, req *inferencev1.RepositoryModelLoadRequest, opts ...grpc.CallOption) (*inferencev1.RepositoryModelLoadResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return v.GRPCInferenceServiceClient.RepositoryModelLoad(ctx, req, opts...)
+}
@@ -90,6 +90,26 @@ func (mr *MockV1MockRecorder) ModelReady(arg0, arg1 interface{}, arg2 ...interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModelReady", reflect.TypeOf((*MockV1)(nil).ModelReady), varargs...)
 }
 
+// RepositoryModelLoad mocks base method.
+func (m *MockV1) RepositoryModelLoad(arg0 context.Context, arg1 *inference.RepositoryModelLoadRequest, arg2 ...grpc.CallOption) (*inference.RepositoryModelLoadResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RepositoryModelLoad", varargs...)
+	ret0, _ := ret[0].(*inference.RepositoryModelLoadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepositoryModelLoad indicates an expected call of RepositoryModelLoad.
+func (mr *MockV1MockRecorder) RepositoryModelLoad(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepositoryModelLoad", reflect.TypeOf((*MockV1)(nil).RepositoryModelLoad), varargs...)
+}
+
 // ServerReady mocks base method.
 func (m *MockV1) ServerReady(arg0 context.Context, arg1 *inference.ServerReadyRequest, arg2 ...grpc.CallOption) (*inference.ServerReadyResponse, error) {
 	m.ctrl.T.Helper()
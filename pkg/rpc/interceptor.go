@@ -18,15 +18,21 @@ package rpc
 
 import (
 	"context"
+	"path"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/juju/ratelimit"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"d7y.io/dragonfly/v2/internal/dferrors"
+	"d7y.io/dragonfly/v2/pkg/types"
 )
 
 var (
@@ -67,15 +73,69 @@ type Refresher interface {
 	Refresh() error
 }
 
+const (
+	// DefaultRefreshInterval is default min interval between refreshing dynconfig.
+	DefaultRefreshInterval = time.Second
+)
+
+// RefresherOption is a functional option for configuring the refresher interceptor.
+type RefresherOption func(r *RefresherInterceptor)
+
+// WithRefreshInterval sets the min interval between refreshing dynconfig,
+// the errors within interval after the last refreshing do not trigger refreshing.
+func WithRefreshInterval(interval time.Duration) RefresherOption {
+	return func(r *RefresherInterceptor) {
+		r.interval = interval
+	}
+}
+
+// WithRefreshPredicate sets the predicate deciding whether the error of call triggers refreshing.
+func WithRefreshPredicate(predicate func(err error) bool) RefresherOption {
+	return func(r *RefresherInterceptor) {
+		r.predicate = predicate
+	}
+}
+
+// RefresherInterceptor refreshes dynconfig when calling error, the concurrent
+// refreshing is merged and the refreshing is debounced by interval.
+type RefresherInterceptor struct {
+	// refresher is the refresher of dynconfig.
+	refresher Refresher
+
+	// interval is the min interval between refreshing dynconfig.
+	interval time.Duration
+
+	// predicate decides whether the error of call triggers refreshing.
+	predicate func(err error) bool
+
+	// group merges the concurrent refreshing.
+	group singleflight.Group
+
+	// lastRefreshAt is the unix nano time of the last refreshing.
+	lastRefreshAt atomic.Int64
+}
+
+// NewRefresherInterceptor returns a RefresherInterceptor instance.
+func NewRefresherInterceptor(r Refresher, opts ...RefresherOption) *RefresherInterceptor {
+	ri := &RefresherInterceptor{
+		refresher: r,
+		interval:  DefaultRefreshInterval,
+		predicate: isRefreshError,
+	}
+
+	for _, opt := range opts {
+		opt(ri)
+	}
+
+	return ri
+}
+
 // UnaryClientInterceptor returns a new unary client interceptor that refresh dynconfig addresses when calling error.
-func RefresherUnaryClientInterceptor(r Refresher) grpc.UnaryClientInterceptor {
+func (r *RefresherInterceptor) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		err := invoker(ctx, method, req, reply, cc, opts...)
-		if s, ok := status.FromError(err); ok {
-			if s.Code() == codes.ResourceExhausted || s.Code() == codes.Unavailable {
-				// nolint
-				r.Refresh()
-			}
+		if err != nil && r.predicate(err) {
+			r.refresh()
 		}
 
 		return err
@@ -83,30 +143,162 @@ func RefresherUnaryClientInterceptor(r Refresher) grpc.UnaryClientInterceptor {
 }
 
 // StreamClientInterceptor returns a new stream client interceptor that refresh dynconfig addresses when calling error.
-func RefresherStreamClientInterceptor(r Refresher) grpc.StreamClientInterceptor {
+func (r *RefresherInterceptor) StreamClientInterceptor() grpc.StreamClientInterceptor {
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 		clientStream, err := streamer(ctx, desc, cc, method, opts...)
-		if s, ok := status.FromError(err); ok {
-			if s.Code() == codes.ResourceExhausted || s.Code() == codes.Unavailable {
-				// nolint
-				r.Refresh()
-			}
+		if err != nil && r.predicate(err) {
+			r.refresh()
 		}
+
 		return clientStream, err
 	}
 }
 
+// refresh refreshes dynconfig if the last refreshing is earlier than interval,
+// the concurrent callers wait for the same refreshing.
+func (r *RefresherInterceptor) refresh() {
+	if time.Since(time.Unix(0, r.lastRefreshAt.Load())) < r.interval {
+		return
+	}
+
+	// nolint
+	r.group.Do("refresh", func() (any, error) {
+		if time.Since(time.Unix(0, r.lastRefreshAt.Load())) < r.interval {
+			return nil, nil
+		}
+
+		defer r.lastRefreshAt.Store(time.Now().UnixNano())
+		return nil, r.refresher.Refresh()
+	})
+}
+
+// isRefreshError is the default predicate, the errors of ResourceExhausted and Unavailable trigger refreshing.
+func isRefreshError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	return s.Code() == codes.ResourceExhausted || s.Code() == codes.Unavailable
+}
+
+// UnaryClientInterceptor returns a new unary client interceptor that refresh dynconfig addresses when calling error.
+func RefresherUnaryClientInterceptor(r Refresher, opts ...RefresherOption) grpc.UnaryClientInterceptor {
+	return NewRefresherInterceptor(r, opts...).UnaryClientInterceptor()
+}
+
+// StreamClientInterceptor returns a new stream client interceptor that refresh dynconfig addresses when calling error.
+func RefresherStreamClientInterceptor(r Refresher, opts ...RefresherOption) grpc.StreamClientInterceptor {
+	return NewRefresherInterceptor(r, opts...).StreamClientInterceptor()
+}
+
+const (
+	// PriorityMetadataKey is the metadata key of priority class.
+	PriorityMetadataKey = "x-dragonfly-priority"
+
+	// InteractivePriority is the priority class of the downloads waited by users.
+	InteractivePriority = "interactive"
+
+	// PreheatPriority is the priority class of the downloads triggered by preheat,
+	// it can be shed under load.
+	PreheatPriority = "preheat"
+
+	// UrgentPriority is the priority class of the urgent downloads, e.g. incident rollouts,
+	// it is scheduled ahead of the other priority classes.
+	UrgentPriority = "urgent"
+)
+
+// ContextWithPriority returns a copy of ctx carrying the priority class in the outgoing metadata.
+func ContextWithPriority(ctx context.Context, priority string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, PriorityMetadataKey, priority)
+}
+
+// PriorityFromIncomingContext returns the priority class in the incoming metadata.
+func PriorityFromIncomingContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(PriorityMetadataKey)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
+}
+
+// QueuePriorityFromIncomingContext returns the queue priority of the priority class in the incoming metadata.
+func QueuePriorityFromIncomingContext(ctx context.Context) types.QueuePriority {
+	priority, _ := PriorityFromIncomingContext(ctx)
+	switch priority {
+	case UrgentPriority:
+		return types.QueuePriorityUrgent
+	case PreheatPriority:
+		return types.QueuePriorityBulk
+	}
+
+	return types.QueuePriorityNormal
+}
+
 // RateLimiterInterceptor is the interface for ratelimit interceptor.
 type RateLimiterInterceptor struct {
 	// tokenBucket is token bucket of ratelimit.
 	tokenBucket *ratelimit.Bucket
+
+	// methodRateLimiters is the rate limiters of methods, the first
+	// matched rate limiter is used, otherwise tokenBucket is used.
+	methodRateLimiters []*methodRateLimiter
+
+	// priorityTokenBuckets is the token buckets keyed by priority class, the requests
+	// of priority class must pass its token bucket before the method rate limiters.
+	priorityTokenBuckets map[string]*ratelimit.Bucket
+}
+
+// methodRateLimiter is the rate limiter of the methods matched the pattern.
+type methodRateLimiter struct {
+	// pattern is the pattern of full method name, refer to path.Match.
+	pattern string
+
+	// tokenBucket is token bucket of ratelimit.
+	tokenBucket *ratelimit.Bucket
+}
+
+// RateLimiterOption is a functional option for configuring the ratelimit interceptor.
+type RateLimiterOption func(r *RateLimiterInterceptor)
+
+// WithMethodRateLimit sets the token bucket of the methods matched the pattern,
+// the pattern matches full method name like /scheduler.Scheduler/RegisterPeerTask
+// and supports the syntax of path.Match, e.g. /scheduler.Scheduler/*.
+func WithMethodRateLimit(pattern string, qps float64, burst int64) RateLimiterOption {
+	return func(r *RateLimiterInterceptor) {
+		r.methodRateLimiters = append(r.methodRateLimiters, &methodRateLimiter{
+			pattern:     pattern,
+			tokenBucket: ratelimit.NewBucketWithRate(qps, burst),
+		})
+	}
+}
+
+// WithPriorityRateLimit sets the token bucket of the priority class carried by request metadata,
+// e.g. the preheat traffic is throttled by its own token bucket and interactive downloads keep flowing.
+func WithPriorityRateLimit(priority string, qps float64, burst int64) RateLimiterOption {
+	return func(r *RateLimiterInterceptor) {
+		r.priorityTokenBuckets[priority] = ratelimit.NewBucketWithRate(qps, burst)
+	}
 }
 
 // NewRateLimiterInterceptor returns a RateLimiterInterceptor instance.
-func NewRateLimiterInterceptor(qps float64, burst int64) *RateLimiterInterceptor {
-	return &RateLimiterInterceptor{
-		tokenBucket: ratelimit.NewBucketWithRate(qps, burst),
+func NewRateLimiterInterceptor(qps float64, burst int64, opts ...RateLimiterOption) *RateLimiterInterceptor {
+	r := &RateLimiterInterceptor{
+		tokenBucket:          ratelimit.NewBucketWithRate(qps, burst),
+		priorityTokenBuckets: make(map[string]*ratelimit.Bucket),
+	}
+
+	for _, opt := range opts {
+		opt(r)
 	}
+
+	return r
 }
 
 // Limit is the predicate which limits the requests.
@@ -118,6 +310,63 @@ func (r *RateLimiterInterceptor) Limit() bool {
 	return false
 }
 
+// LimitMethod is the predicate which limits the requests of method.
+func (r *RateLimiterInterceptor) LimitMethod(method string) bool {
+	for _, limiter := range r.methodRateLimiters {
+		if matched, err := path.Match(limiter.pattern, method); err == nil && matched {
+			return limiter.tokenBucket.TakeAvailable(1) == 0
+		}
+	}
+
+	return r.Limit()
+}
+
+// LimitPriority is the predicate which limits the requests of priority class,
+// the requests of priority class without token bucket are not limited.
+func (r *RateLimiterInterceptor) LimitPriority(priority string) bool {
+	tokenBucket, ok := r.priorityTokenBuckets[priority]
+	if !ok {
+		return false
+	}
+
+	return tokenBucket.TakeAvailable(1) == 0
+}
+
+// limit returns the error if the request is rejected by rate limiter.
+func (r *RateLimiterInterceptor) limit(ctx context.Context, method string) error {
+	if priority, ok := PriorityFromIncomingContext(ctx); ok && r.LimitPriority(priority) {
+		return status.Errorf(codes.ResourceExhausted, "%s of priority %s is rejected by rate limiter, please retry later", method, priority)
+	}
+
+	if r.LimitMethod(method) {
+		return status.Errorf(codes.ResourceExhausted, "%s is rejected by rate limiter, please retry later", method)
+	}
+
+	return nil
+}
+
+// UnaryServerInterceptor returns a new unary server interceptor that performs per-method and per-priority rate limiting.
+func (r *RateLimiterInterceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := r.limit(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a new stream server interceptor that performs per-method and per-priority rate limiting.
+func (r *RateLimiterInterceptor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := r.limit(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
 // ConvertErrorUnaryServerInterceptor returns a new unary server interceptor that convert error when trigger custom error.
 func ConvertErrorUnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	h, err := handler(ctx, req)
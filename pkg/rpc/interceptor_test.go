@@ -0,0 +1,217 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestRateLimiterInterceptor_LimitMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []RateLimiterOption
+		expect func(t *testing.T, r *RateLimiterInterceptor)
+	}{
+		{
+			name: "method uses global token bucket",
+			expect: func(t *testing.T, r *RateLimiterInterceptor) {
+				assert := assert.New(t)
+				assert.False(r.LimitMethod("/scheduler.Scheduler/RegisterPeerTask"))
+				assert.True(r.LimitMethod("/scheduler.Scheduler/AnnounceHost"))
+			},
+		},
+		{
+			name: "method uses its own token bucket",
+			opts: []RateLimiterOption{WithMethodRateLimit("/scheduler.Scheduler/RegisterPeerTask", 1, 1)},
+			expect: func(t *testing.T, r *RateLimiterInterceptor) {
+				assert := assert.New(t)
+				assert.False(r.LimitMethod("/scheduler.Scheduler/RegisterPeerTask"))
+				assert.True(r.LimitMethod("/scheduler.Scheduler/RegisterPeerTask"))
+				assert.False(r.LimitMethod("/scheduler.Scheduler/AnnounceHost"))
+			},
+		},
+		{
+			name: "methods match the pattern",
+			opts: []RateLimiterOption{WithMethodRateLimit("/scheduler.Scheduler/*", 1, 2)},
+			expect: func(t *testing.T, r *RateLimiterInterceptor) {
+				assert := assert.New(t)
+				assert.False(r.LimitMethod("/scheduler.Scheduler/RegisterPeerTask"))
+				assert.False(r.LimitMethod("/scheduler.Scheduler/AnnounceHost"))
+				assert.True(r.LimitMethod("/scheduler.Scheduler/LeaveHost"))
+				assert.False(r.LimitMethod("/manager.Manager/GetScheduler"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, NewRateLimiterInterceptor(1, 1, tc.opts...))
+		})
+	}
+}
+
+type mockRefresher struct {
+	count atomic.Int32
+}
+
+func (m *mockRefresher) Refresh() error {
+	m.count.Add(1)
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func TestRefresherInterceptor(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []RefresherOption
+		err    error
+		calls  int
+		expect func(t *testing.T, count int32)
+	}{
+		{
+			name:  "call succeeded",
+			calls: 10,
+			expect: func(t *testing.T, count int32) {
+				assert.Equal(t, int32(0), count)
+			},
+		},
+		{
+			name:  "concurrent errors trigger refreshing once",
+			err:   status.Error(codes.Unavailable, ""),
+			calls: 10,
+			expect: func(t *testing.T, count int32) {
+				assert.Equal(t, int32(1), count)
+			},
+		},
+		{
+			name:  "error does not trigger refreshing",
+			err:   status.Error(codes.NotFound, ""),
+			calls: 10,
+			expect: func(t *testing.T, count int32) {
+				assert.Equal(t, int32(0), count)
+			},
+		},
+		{
+			name: "error triggers refreshing by predicate",
+			opts: []RefresherOption{WithRefreshPredicate(func(err error) bool {
+				return status.Code(err) == codes.NotFound
+			})},
+			err:   status.Error(codes.NotFound, ""),
+			calls: 10,
+			expect: func(t *testing.T, count int32) {
+				assert.Equal(t, int32(1), count)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			refresher := &mockRefresher{}
+			interceptor := NewRefresherInterceptor(refresher, append([]RefresherOption{WithRefreshInterval(time.Minute)}, tc.opts...)...).UnaryClientInterceptor()
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return tc.err
+			}
+
+			var wg sync.WaitGroup
+			for i := 0; i < tc.calls; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					// nolint
+					interceptor(context.Background(), "/foo", nil, nil, nil, invoker)
+				}()
+			}
+
+			wg.Wait()
+			tc.expect(t, refresher.count.Load())
+		})
+	}
+}
+
+func TestRateLimiterInterceptor_limit(t *testing.T) {
+	r := NewRateLimiterInterceptor(100, 100, WithPriorityRateLimit(PreheatPriority, 1, 1))
+	preheatCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(PriorityMetadataKey, PreheatPriority))
+	interactiveCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(PriorityMetadataKey, InteractivePriority))
+
+	assert := assert.New(t)
+	assert.NoError(r.limit(preheatCtx, "/foo"))
+	assert.Equal(codes.ResourceExhausted, status.Code(r.limit(preheatCtx, "/foo")))
+	assert.NoError(r.limit(interactiveCtx, "/foo"))
+	assert.NoError(r.limit(context.Background(), "/foo"))
+}
+
+func TestPriorityFromIncomingContext(t *testing.T) {
+	assert := assert.New(t)
+	_, ok := PriorityFromIncomingContext(context.Background())
+	assert.False(ok)
+
+	md, _ := metadata.FromOutgoingContext(ContextWithPriority(context.Background(), PreheatPriority))
+	priority, ok := PriorityFromIncomingContext(metadata.NewIncomingContext(context.Background(), md))
+	assert.True(ok)
+	assert.Equal(PreheatPriority, priority)
+}
+
+func TestQueuePriorityFromIncomingContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority string
+		expect   types.QueuePriority
+	}{
+		{
+			name:   "priority class is empty",
+			expect: types.QueuePriorityNormal,
+		},
+		{
+			name:     "priority class is interactive",
+			priority: InteractivePriority,
+			expect:   types.QueuePriorityNormal,
+		},
+		{
+			name:     "priority class is preheat",
+			priority: PreheatPriority,
+			expect:   types.QueuePriorityBulk,
+		},
+		{
+			name:     "priority class is urgent",
+			priority: UrgentPriority,
+			expect:   types.QueuePriorityUrgent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.priority != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(PriorityMetadataKey, tc.priority))
+			}
+
+			assert.Equal(t, tc.expect, QueuePriorityFromIncomingContext(ctx))
+		})
+	}
+}
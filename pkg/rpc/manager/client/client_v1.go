@@ -25,7 +25,6 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -49,16 +48,22 @@ func GetV1ByAddr(ctx context.Context, target string, opts ...grpc.DialOption) (V
 			grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(
 				rpc.OTELUnaryClientInterceptor(),
 				grpc_prometheus.UnaryClientInterceptor,
+				rpc.MetricsUnaryClientInterceptor,
 				grpc_zap.UnaryClientInterceptor(logger.GrpcLogger.Desugar()),
-				grpc_retry.UnaryClientInterceptor(
-					grpc_retry.WithMax(maxRetries),
-					grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffWaitBetween)),
+				rpc.RetryUnaryClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
 				),
 			)),
 			grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(
 				rpc.OTELStreamClientInterceptor(),
 				grpc_prometheus.StreamClientInterceptor,
+				rpc.MetricsStreamClientInterceptor,
 				grpc_zap.StreamClientInterceptor(logger.GrpcLogger.Desugar()),
+				rpc.RetryStreamClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
 			)),
 		}, opts...)...,
 	)
@@ -0,0 +1,114 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MaxInflightOption is a functional option for configuring the max inflight interceptor.
+type MaxInflightOption func(m *MaxInflightInterceptor)
+
+// WithMethodMaxInflight sets the max concurrent handler executions of full method,
+// it overrides the default limit.
+func WithMethodMaxInflight(method string, limit int64) MaxInflightOption {
+	return func(m *MaxInflightInterceptor) {
+		m.methodLimits[method] = limit
+	}
+}
+
+// MaxInflightInterceptor bounds the concurrent handler executions per method,
+// the calls exceeding the limit are rejected with codes.ResourceExhausted.
+type MaxInflightInterceptor struct {
+	// limit is the default max concurrent handler executions of method,
+	// zero means unlimited.
+	limit int64
+
+	// methodLimits is the max concurrent handler executions keyed by full method.
+	methodLimits map[string]int64
+
+	// inflights is the counter of concurrent handler executions keyed by full method.
+	inflights sync.Map
+}
+
+// NewMaxInflightInterceptor returns a MaxInflightInterceptor instance,
+// limit is the default max concurrent handler executions of method and zero means unlimited.
+func NewMaxInflightInterceptor(limit int64, opts ...MaxInflightOption) *MaxInflightInterceptor {
+	m := &MaxInflightInterceptor{
+		limit:        limit,
+		methodLimits: make(map[string]int64),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// UnaryServerInterceptor returns a new unary server interceptor that bounds the concurrent handler executions.
+func (m *MaxInflightInterceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, err := m.acquire(info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a new stream server interceptor that bounds the concurrent streams.
+func (m *MaxInflightInterceptor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, err := m.acquire(info.FullMethod)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		return handler(srv, ss)
+	}
+}
+
+// acquire takes a slot of method, and returns the function releasing the slot.
+func (m *MaxInflightInterceptor) acquire(method string) (func(), error) {
+	limit, ok := m.methodLimits[method]
+	if !ok {
+		limit = m.limit
+	}
+
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	value, _ := m.inflights.LoadOrStore(method, &atomic.Int64{})
+	inflight := value.(*atomic.Int64)
+	if inflight.Add(1) > limit {
+		inflight.Add(-1)
+		return nil, status.Errorf(codes.ResourceExhausted, "%s exceeds max inflight %d, please retry later", method, limit)
+	}
+
+	return func() { inflight.Add(-1) }, nil
+}
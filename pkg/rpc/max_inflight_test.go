@@ -0,0 +1,87 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMaxInflightInterceptor_acquire(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int64
+		opts   []MaxInflightOption
+		expect func(t *testing.T, m *MaxInflightInterceptor)
+	}{
+		{
+			name:  "method is unlimited",
+			limit: 0,
+			expect: func(t *testing.T, m *MaxInflightInterceptor) {
+				assert := assert.New(t)
+				for i := 0; i < 10; i++ {
+					_, err := m.acquire("/foo")
+					assert.NoError(err)
+				}
+			},
+		},
+		{
+			name:  "method exceeds default limit",
+			limit: 2,
+			expect: func(t *testing.T, m *MaxInflightInterceptor) {
+				assert := assert.New(t)
+				release, err := m.acquire("/foo")
+				assert.NoError(err)
+				_, err = m.acquire("/foo")
+				assert.NoError(err)
+				_, err = m.acquire("/foo")
+				assert.Equal(codes.ResourceExhausted, status.Code(err))
+
+				_, err = m.acquire("/bar")
+				assert.NoError(err)
+
+				release()
+				_, err = m.acquire("/foo")
+				assert.NoError(err)
+			},
+		},
+		{
+			name:  "method exceeds its own limit",
+			limit: 0,
+			opts:  []MaxInflightOption{WithMethodMaxInflight("/foo", 1)},
+			expect: func(t *testing.T, m *MaxInflightInterceptor) {
+				assert := assert.New(t)
+				_, err := m.acquire("/foo")
+				assert.NoError(err)
+				_, err = m.acquire("/foo")
+				assert.Equal(codes.ResourceExhausted, status.Code(err))
+
+				_, err = m.acquire("/bar")
+				assert.NoError(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, NewMaxInflightInterceptor(tc.limit, tc.opts...))
+		})
+	}
+}
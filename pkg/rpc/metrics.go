@@ -0,0 +1,210 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// metricsSubsystem is subsystem of grpc metrics.
+	metricsSubsystem = "grpc"
+)
+
+// Variables declared for metrics.
+var (
+	ClientRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "client_requests_total",
+		Help:      "Counter of the number of the requests sent by client.",
+	}, []string{"method", "code"})
+
+	ClientRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "client_request_duration_seconds",
+		Help:      "Histogram of the duration of the requests sent by client.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"method", "code"})
+
+	ClientInflightRequestCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "client_inflight_requests",
+		Help:      "Gauge of the number of the inflight requests sent by client.",
+	}, []string{"method"})
+
+	ServerRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "server_requests_total",
+		Help:      "Counter of the number of the requests handled by server.",
+	}, []string{"method", "code"})
+
+	ServerRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "server_request_duration_seconds",
+		Help:      "Histogram of the duration of the requests handled by server.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"method", "code"})
+
+	ServerInflightRequestCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "server_inflight_requests",
+		Help:      "Gauge of the number of the inflight requests handled by server.",
+	}, []string{"method"})
+
+	ServerPanicCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "server_panics_total",
+		Help:      "Counter of the number of the panics recovered in handlers.",
+	}, []string{"method"})
+)
+
+// MetricsUnaryClientInterceptor returns a new unary client interceptor that records metrics of requests.
+func MetricsUnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ClientInflightRequestCount.WithLabelValues(method).Inc()
+	defer ClientInflightRequestCount.WithLabelValues(method).Dec()
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	observeClientRequest(method, start, err)
+	return err
+}
+
+// MetricsStreamClientInterceptor returns a new stream client interceptor that records metrics of streams,
+// the stream is finished with the status of the terminal error returned by RecvMsg or CloseSend.
+func MetricsStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	start := time.Now()
+	clientStream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		observeClientRequest(method, start, err)
+		return nil, err
+	}
+
+	ClientInflightRequestCount.WithLabelValues(method).Inc()
+	return &metricsClientStream{
+		ClientStream:  clientStream,
+		method:        method,
+		start:         start,
+		serverStreams: desc.ServerStreams,
+	}, nil
+}
+
+// metricsClientStream wraps the client stream to record metrics when the stream is finished.
+type metricsClientStream struct {
+	grpc.ClientStream
+
+	// method is the full method name of stream.
+	method string
+
+	// start is the time when the stream is created.
+	start time.Time
+
+	// serverStreams indicates the server sends a stream of messages,
+	// otherwise the stream is finished after the only message is received.
+	serverStreams bool
+
+	// finishOnce makes sure the stream is finished only once.
+	finishOnce sync.Once
+}
+
+// RecvMsg receives the message, and finishes the stream when the receiving is terminated.
+func (s *metricsClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	switch {
+	case err == nil:
+		if !s.serverStreams {
+			s.finish(nil)
+		}
+	case errors.Is(err, io.EOF):
+		s.finish(nil)
+	default:
+		s.finish(err)
+	}
+
+	return err
+}
+
+// CloseSend closes the sending direction, and finishes the stream if the closing failed.
+func (s *metricsClientStream) CloseSend() error {
+	err := s.ClientStream.CloseSend()
+	if err != nil {
+		s.finish(err)
+	}
+
+	return err
+}
+
+// finish records the metrics of the finished stream.
+func (s *metricsClientStream) finish(err error) {
+	s.finishOnce.Do(func() {
+		ClientInflightRequestCount.WithLabelValues(s.method).Dec()
+		observeClientRequest(s.method, s.start, err)
+	})
+}
+
+// MetricsUnaryServerInterceptor returns a new unary server interceptor that records metrics of requests.
+func MetricsUnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ServerInflightRequestCount.WithLabelValues(info.FullMethod).Inc()
+	defer ServerInflightRequestCount.WithLabelValues(info.FullMethod).Dec()
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observeServerRequest(info.FullMethod, start, err)
+	return resp, err
+}
+
+// MetricsStreamServerInterceptor returns a new stream server interceptor that records metrics of streams.
+func MetricsStreamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ServerInflightRequestCount.WithLabelValues(info.FullMethod).Inc()
+	defer ServerInflightRequestCount.WithLabelValues(info.FullMethod).Dec()
+
+	start := time.Now()
+	err := handler(srv, ss)
+	observeServerRequest(info.FullMethod, start, err)
+	return err
+}
+
+// observeClientRequest records the count and duration of client request.
+func observeClientRequest(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	ClientRequestCount.WithLabelValues(method, code).Inc()
+	ClientRequestDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
+
+// observeServerRequest records the count and duration of server request.
+func observeServerRequest(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+	ServerRequestCount.WithLabelValues(method, code).Inc()
+	ServerRequestDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,128 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsStreamClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverStreams bool
+		recvErrs      []error
+		closeSendErr  error
+		expect        func(t *testing.T, method string)
+	}{
+		{
+			name:          "server stream is finished by io.EOF",
+			serverStreams: true,
+			recvErrs:      []error{nil, nil, io.EOF},
+			expect: func(t *testing.T, method string) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), testutil.ToFloat64(ClientRequestCount.WithLabelValues(method, codes.OK.String())))
+				assert.Equal(float64(0), testutil.ToFloat64(ClientInflightRequestCount.WithLabelValues(method)))
+			},
+		},
+		{
+			name:          "server stream is finished by error",
+			serverStreams: true,
+			recvErrs:      []error{nil, status.Error(codes.Unavailable, "foo"), status.Error(codes.Unavailable, "foo")},
+			expect: func(t *testing.T, method string) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), testutil.ToFloat64(ClientRequestCount.WithLabelValues(method, codes.Unavailable.String())))
+				assert.Equal(float64(0), testutil.ToFloat64(ClientRequestCount.WithLabelValues(method, codes.OK.String())))
+				assert.Equal(float64(0), testutil.ToFloat64(ClientInflightRequestCount.WithLabelValues(method)))
+			},
+		},
+		{
+			name:     "client stream is finished by the only message",
+			recvErrs: []error{nil},
+			expect: func(t *testing.T, method string) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), testutil.ToFloat64(ClientRequestCount.WithLabelValues(method, codes.OK.String())))
+				assert.Equal(float64(0), testutil.ToFloat64(ClientInflightRequestCount.WithLabelValues(method)))
+			},
+		},
+		{
+			name:          "stream is finished by close send error",
+			serverStreams: true,
+			closeSendErr:  status.Error(codes.Internal, "foo"),
+			expect: func(t *testing.T, method string) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), testutil.ToFloat64(ClientRequestCount.WithLabelValues(method, codes.Internal.String())))
+				assert.Equal(float64(0), testutil.ToFloat64(ClientInflightRequestCount.WithLabelValues(method)))
+			},
+		},
+		{
+			name:          "stream is inflight",
+			serverStreams: true,
+			recvErrs:      []error{nil},
+			expect: func(t *testing.T, method string) {
+				assert := assert.New(t)
+				assert.Equal(float64(0), testutil.ToFloat64(ClientRequestCount.WithLabelValues(method, codes.OK.String())))
+				assert.Equal(float64(1), testutil.ToFloat64(ClientInflightRequestCount.WithLabelValues(method)))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			method := "/test.Metrics/" + tc.name
+			streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &testMetricsClientStream{recvErrs: tc.recvErrs, closeSendErr: tc.closeSendErr}, nil
+			}
+
+			clientStream, err := MetricsStreamClientInterceptor(context.Background(), &grpc.StreamDesc{ServerStreams: tc.serverStreams}, nil, method, streamer)
+			assert.NoError(t, err)
+
+			if err := clientStream.CloseSend(); err != nil {
+				assert.Equal(t, tc.closeSendErr, err)
+			}
+
+			for range tc.recvErrs {
+				clientStream.RecvMsg(nil) // nolint: errcheck
+			}
+
+			tc.expect(t, method)
+		})
+	}
+}
+
+type testMetricsClientStream struct {
+	grpc.ClientStream
+	recvErrs     []error
+	closeSendErr error
+}
+
+func (s *testMetricsClientStream) RecvMsg(m any) error {
+	err := s.recvErrs[0]
+	s.recvErrs = s.recvErrs[1:]
+	return err
+}
+
+func (s *testMetricsClientStream) CloseSend() error {
+	return s.closeSendErr
+}
@@ -0,0 +1,93 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+)
+
+const (
+	// PeerExchangeServiceName is the name of the peer exchange service served by the scheduler.
+	// The service reuses the messages of scheduler v1 instead of extending them, so the wire format
+	// of the scheduler service stays compatible with the daemons not supporting peer exchange.
+	PeerExchangeServiceName = "dragonfly.pex.v1.PeerExchange"
+
+	// ListSiblingPeersMethod is the full method name of ListSiblingPeers.
+	ListSiblingPeersMethod = "/" + PeerExchangeServiceName + "/ListSiblingPeers"
+)
+
+// PeerExchangeServer is the server API for peer exchange service.
+type PeerExchangeServer interface {
+	// ListSiblingPeers returns the sibling peers downloading the same task as the peer,
+	// the siblings are carried by the candidate peers of the packet.
+	ListSiblingPeers(context.Context, *schedulerv1.PeerTarget) (*schedulerv1.PeerPacket, error)
+}
+
+// RegisterPeerExchangeServer registers the peer exchange service on the grpc server.
+func RegisterPeerExchangeServer(s grpc.ServiceRegistrar, srv PeerExchangeServer) {
+	s.RegisterService(&peerExchangeServiceDesc, srv)
+}
+
+// ListSiblingPeers returns the sibling peers downloading the same task as the peer,
+// the scheduler not supporting peer exchange returns the unimplemented error.
+func ListSiblingPeers(ctx context.Context, cc grpc.ClientConnInterface, req *schedulerv1.PeerTarget, opts ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error) {
+	packet := new(schedulerv1.PeerPacket)
+	if err := cc.Invoke(ctx, ListSiblingPeersMethod, req, packet, opts...); err != nil {
+		return nil, err
+	}
+
+	return packet.CandidatePeers, nil
+}
+
+// peerExchangeServiceDesc is the grpc service descriptor for peer exchange service.
+var peerExchangeServiceDesc = grpc.ServiceDesc{
+	ServiceName: PeerExchangeServiceName,
+	HandlerType: (*PeerExchangeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSiblingPeers",
+			Handler:    listSiblingPeersHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/rpc/pex.go",
+}
+
+// listSiblingPeersHandler handles ListSiblingPeers of peer exchange service.
+func listSiblingPeersHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := new(schedulerv1.PeerTarget)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+
+	if interceptor == nil {
+		return srv.(PeerExchangeServer).ListSiblingPeers(ctx, req)
+	}
+
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ListSiblingPeersMethod,
+	}
+
+	return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+		return srv.(PeerExchangeServer).ListSiblingPeers(ctx, req.(*schedulerv1.PeerTarget))
+	})
+}
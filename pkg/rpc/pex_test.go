@@ -0,0 +1,126 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+)
+
+type mockPeerExchangeServer struct {
+	siblings []*schedulerv1.PeerPacket_DestPeer
+}
+
+func (s *mockPeerExchangeServer) ListSiblingPeers(ctx context.Context, req *schedulerv1.PeerTarget) (*schedulerv1.PeerPacket, error) {
+	if req.PeerId == "" {
+		return nil, status.Error(codes.NotFound, "peer not found")
+	}
+
+	return &schedulerv1.PeerPacket{TaskId: req.TaskId, SrcPid: req.PeerId, CandidatePeers: s.siblings}, nil
+}
+
+func dialBufconn(t *testing.T, register func(*grpc.Server)) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestListSiblingPeers(t *testing.T) {
+	siblings := []*schedulerv1.PeerPacket_DestPeer{
+		{Ip: "127.0.0.1", RpcPort: 8003, PeerId: "foo"},
+		{Ip: "127.0.0.2", RpcPort: 8003, PeerId: "bar"},
+	}
+
+	tests := []struct {
+		name     string
+		register func(*grpc.Server)
+		req      *schedulerv1.PeerTarget
+		expect   func(t *testing.T, siblings []*schedulerv1.PeerPacket_DestPeer, err error)
+	}{
+		{
+			name: "list sibling peers",
+			register: func(s *grpc.Server) {
+				RegisterPeerExchangeServer(s, &mockPeerExchangeServer{siblings: siblings})
+			},
+			req: &schedulerv1.PeerTarget{TaskId: "foo", PeerId: "bar"},
+			expect: func(t *testing.T, peers []*schedulerv1.PeerPacket_DestPeer, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Len(peers, len(siblings))
+				for i, peer := range peers {
+					assert.Equal(siblings[i].PeerId, peer.PeerId)
+					assert.Equal(siblings[i].Ip, peer.Ip)
+				}
+			},
+		},
+		{
+			name: "peer not found",
+			register: func(s *grpc.Server) {
+				RegisterPeerExchangeServer(s, &mockPeerExchangeServer{siblings: siblings})
+			},
+			req: &schedulerv1.PeerTarget{TaskId: "foo"},
+			expect: func(t *testing.T, peers []*schedulerv1.PeerPacket_DestPeer, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.NotFound, status.Code(err))
+				assert.Empty(peers)
+			},
+		},
+		{
+			name:     "scheduler does not support peer exchange",
+			register: func(s *grpc.Server) {},
+			req:      &schedulerv1.PeerTarget{TaskId: "foo", PeerId: "bar"},
+			expect: func(t *testing.T, peers []*schedulerv1.PeerPacket_DestPeer, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.Unimplemented, status.Code(err))
+				assert.Empty(peers)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := dialBufconn(t, tc.register)
+			peers, err := ListSiblingPeers(context.Background(), conn, tc.req)
+			tc.expect(t, peers, err)
+		})
+	}
+}
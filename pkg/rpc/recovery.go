@@ -0,0 +1,75 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+// RecoveryUnaryServerInterceptor returns a new unary server interceptor that recovers the panic in handler,
+// reports the crash with stack trace and returns codes.Internal to the caller.
+func RecoveryUnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = reportPanic(ctx, info.FullMethod, r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// RecoveryStreamServerInterceptor returns a new stream server interceptor that recovers the panic in handler,
+// reports the crash with stack trace and returns codes.Internal to the caller.
+func RecoveryStreamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = reportPanic(ss.Context(), info.FullMethod, r)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+// reportPanic logs the crash report of panic with request metadata, and returns the error of codes.Internal.
+func reportPanic(ctx context.Context, method string, r any) error {
+	ServerPanicCount.WithLabelValues(method).Inc()
+
+	log := logger.WithContext(ctx).With("method", method)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		log = log.With("peer", p.Addr.String())
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		// Credentials must not be written to the crash report.
+		md = md.Copy()
+		md.Delete(AuthorizationMetadataKey)
+		md.Delete(SignatureMetadataKey)
+		log = log.With("metadata", md)
+	}
+
+	log.Errorf("recovered from panic: %v\n%s", r, debug.Stack())
+	return status.Errorf(codes.Internal, "panic in %s: %v", method, r)
+}
@@ -0,0 +1,80 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler grpc.UnaryHandler
+		expect  func(t *testing.T, resp any, err error)
+	}{
+		{
+			name: "handler succeeded",
+			handler: func(ctx context.Context, req any) (any, error) {
+				return "foo", nil
+			},
+			expect: func(t *testing.T, resp any, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal("foo", resp)
+			},
+		},
+		{
+			name: "handler failed",
+			handler: func(ctx context.Context, req any) (any, error) {
+				return nil, status.Error(codes.NotFound, "")
+			},
+			expect: func(t *testing.T, resp any, err error) {
+				assert.Equal(t, codes.NotFound, status.Code(err))
+			},
+		},
+		{
+			name: "handler panicked",
+			handler: func(ctx context.Context, req any) (any, error) {
+				panic(errors.New("foo"))
+			},
+			expect: func(t *testing.T, resp any, err error) {
+				assert := assert.New(t)
+				assert.Nil(resp)
+				assert.Equal(codes.Internal, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationMetadataKey, "Bearer foo"))
+			resp, err := RecoveryUnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/" + tc.name}, tc.handler)
+			tc.expect(t, resp, err)
+		})
+	}
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(ServerPanicCount.WithLabelValues("/handler panicked")))
+}
@@ -0,0 +1,93 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// ReplicasMetadataKey is the metadata key of the desired replicas of the persistent cache task.
+	ReplicasMetadataKey = "x-dragonfly-replicas"
+
+	// ReplicaSpreadByMetadataKey is the metadata key of the topology label which the replicas are spread across.
+	ReplicaSpreadByMetadataKey = "x-dragonfly-replica-spread-by"
+
+	// ReplicateMetadataKey is the metadata key of replicating the task into local storage,
+	// it is set by scheduler when asking the host to hold a replica of the persistent cache task.
+	ReplicateMetadataKey = "x-dragonfly-replicate"
+)
+
+// ContextWithReplication returns the outgoing context carrying the replication policy of the persistent cache task.
+func ContextWithReplication(ctx context.Context, replication types.Replication) context.Context {
+	if !replication.IsPersistent() {
+		return ctx
+	}
+
+	kv := []string{ReplicasMetadataKey, strconv.Itoa(replication.Replicas)}
+	if replication.SpreadBy != "" {
+		kv = append(kv, ReplicaSpreadByMetadataKey, replication.SpreadBy)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// ReplicationFromIncomingContext returns the replication policy carried by the incoming context.
+func ReplicationFromIncomingContext(ctx context.Context) (types.Replication, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return types.Replication{}, false
+	}
+
+	values := md.Get(ReplicasMetadataKey)
+	if len(values) == 0 {
+		return types.Replication{}, false
+	}
+
+	replicas, err := strconv.Atoi(values[0])
+	if err != nil || replicas <= 0 {
+		return types.Replication{}, false
+	}
+
+	replication := types.Replication{Replicas: replicas}
+	if values := md.Get(ReplicaSpreadByMetadataKey); len(values) > 0 {
+		replication.SpreadBy = values[0]
+	}
+
+	return replication, true
+}
+
+// ContextWithReplicate returns the outgoing context asking the host to replicate the task into local storage.
+func ContextWithReplicate(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ReplicateMetadataKey, "true")
+}
+
+// IsReplicateFromIncomingContext returns whether the incoming context asks to replicate the task into local storage.
+func IsReplicateFromIncomingContext(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+
+	values := md.Get(ReplicateMetadataKey)
+	return len(values) > 0 && values[0] == "true"
+}
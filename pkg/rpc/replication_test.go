@@ -0,0 +1,73 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestReplication(t *testing.T) {
+	tests := []struct {
+		name        string
+		replication types.Replication
+		ok          bool
+	}{
+		{
+			name:        "propagate replicas and spread label",
+			replication: types.Replication{Replicas: 3, SpreadBy: types.TopologyLabelZone},
+			ok:          true,
+		},
+		{
+			name:        "propagate replicas",
+			replication: types.Replication{Replicas: 1},
+			ok:          true,
+		},
+		{
+			name:        "ignore task without replicas",
+			replication: types.Replication{SpreadBy: types.TopologyLabelZone},
+			ok:          false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			md, _ := metadata.FromOutgoingContext(ContextWithReplication(context.Background(), tc.replication))
+			replication, ok := ReplicationFromIncomingContext(metadata.NewIncomingContext(context.Background(), md))
+			assert.Equal(tc.ok, ok)
+			if tc.ok {
+				assert.Equal(tc.replication, replication)
+			}
+		})
+	}
+
+	_, ok := ReplicationFromIncomingContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs(ReplicasMetadataKey, "foo")))
+	assert.False(t, ok)
+}
+
+func TestReplicate(t *testing.T) {
+	assert := assert.New(t)
+	md, _ := metadata.FromOutgoingContext(ContextWithReplicate(context.Background()))
+	assert.True(IsReplicateFromIncomingContext(metadata.NewIncomingContext(context.Background(), md)))
+	assert.False(IsReplicateFromIncomingContext(context.Background()))
+}
@@ -0,0 +1,78 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// ParentReportsMetadataKey is the metadata key of the piece results of the parents observed by host.
+	ParentReportsMetadataKey = "x-dragonfly-parent-reports"
+)
+
+// ParentReport is the piece results of the parent observed by the child in a report window.
+type ParentReport struct {
+	// PeerID is the id of the parent.
+	PeerID string `json:"peer_id"`
+
+	// Pieces is the count of the pieces downloaded from the parent, including the failed pieces.
+	Pieces uint64 `json:"pieces"`
+
+	// Failures is the count of the pieces failed to download from the parent.
+	Failures uint64 `json:"failures"`
+
+	// Corruptions is the count of the failed pieces whose data or digest is corrupted.
+	Corruptions uint64 `json:"corruptions"`
+}
+
+// ContextWithParentReports returns the outgoing context carrying the parent reports of host.
+func ContextWithParentReports(ctx context.Context, reports []ParentReport) context.Context {
+	if len(reports) == 0 {
+		return ctx
+	}
+
+	value, err := json.Marshal(reports)
+	if err != nil {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, ParentReportsMetadataKey, string(value))
+}
+
+// ParentReportsFromIncomingContext returns the parent reports of host carried by the incoming context.
+func ParentReportsFromIncomingContext(ctx context.Context) []ParentReport {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	values := md.Get(ParentReportsMetadataKey)
+	if len(values) == 0 {
+		return nil
+	}
+
+	var reports []ParentReport
+	if err := json.Unmarshal([]byte(values[0]), &reports); err != nil {
+		return nil
+	}
+
+	return reports
+}
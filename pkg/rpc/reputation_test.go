@@ -0,0 +1,82 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParentReports(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() context.Context
+		expect func(t *testing.T, reports []ParentReport)
+	}{
+		{
+			name: "propagate parent reports",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithParentReports(context.Background(), []ParentReport{
+					{PeerID: "foo", Pieces: 10, Failures: 2, Corruptions: 1},
+					{PeerID: "bar", Pieces: 5},
+				}))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, reports []ParentReport) {
+				assert.Equal(t, []ParentReport{
+					{PeerID: "foo", Pieces: 10, Failures: 2, Corruptions: 1},
+					{PeerID: "bar", Pieces: 5},
+				}, reports)
+			},
+		},
+		{
+			name: "empty parent reports are not propagated",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithParentReports(context.Background(), nil))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, reports []ParentReport) {
+				assert.Empty(t, reports)
+			},
+		},
+		{
+			name: "invalid parent reports",
+			ctx: func() context.Context {
+				return metadata.NewIncomingContext(context.Background(), metadata.Pairs(ParentReportsMetadataKey, "foo"))
+			},
+			expect: func(t *testing.T, reports []ParentReport) {
+				assert.Empty(t, reports)
+			},
+		},
+		{
+			name: "context without metadata",
+			ctx:  context.Background,
+			expect: func(t *testing.T, reports []ParentReport) {
+				assert.Empty(t, reports)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, ParentReportsFromIncomingContext(tc.ctx()))
+		})
+	}
+}
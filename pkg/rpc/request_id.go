@@ -0,0 +1,96 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+const (
+	// RequestIDMetadataKey is the metadata key of request id.
+	RequestIDMetadataKey = "x-request-id"
+)
+
+// RequestIDUnaryClientInterceptor returns a new unary client interceptor that injects the request id
+// into the outgoing metadata, the request id is generated if the context carries none.
+func RequestIDUnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withOutgoingRequestID(ctx), method, req, reply, cc, opts...)
+}
+
+// RequestIDStreamClientInterceptor returns a new stream client interceptor that injects the request id
+// into the outgoing metadata, the request id is generated if the context carries none.
+func RequestIDStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withOutgoingRequestID(ctx), desc, cc, method, opts...)
+}
+
+// RequestIDUnaryServerInterceptor returns a new unary server interceptor that extracts the request id
+// from the incoming metadata, and attaches it to the context of handler and the grpc logger,
+// the request id is generated if the caller sends none.
+func RequestIDUnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withIncomingRequestID(ctx), req)
+}
+
+// RequestIDStreamServerInterceptor returns a new stream server interceptor that extracts the request id
+// from the incoming metadata, and attaches it to the context of handler and the grpc logger,
+// the request id is generated if the caller sends none.
+func RequestIDStreamServerInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	wrapped := grpc_middleware.WrapServerStream(ss)
+	wrapped.WrappedContext = withIncomingRequestID(ss.Context())
+	return handler(srv, wrapped)
+}
+
+// withOutgoingRequestID returns the context with the request id in the outgoing metadata.
+func withOutgoingRequestID(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(RequestIDMetadataKey)) > 0 {
+		return ctx
+	}
+
+	requestID, ok := logger.RequestIDFromContext(ctx)
+	if !ok {
+		requestID = uuid.NewString()
+		ctx = logger.ContextWithRequestID(ctx, requestID)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, requestID)
+}
+
+// withIncomingRequestID returns the context carrying the request id of the incoming metadata.
+func withIncomingRequestID(ctx context.Context) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+
+	// Add request id to the fields of grpc logger.
+	ctxzap.AddFields(ctx, zap.String("grpc.request_id", requestID))
+	return logger.ContextWithRequestID(ctx, requestID)
+}
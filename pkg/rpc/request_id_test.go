@@ -0,0 +1,80 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+)
+
+func TestRequestIDInterceptor(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		expect func(t *testing.T, requestID string)
+	}{
+		{
+			name: "generate request id",
+			ctx:  context.Background(),
+			expect: func(t *testing.T, requestID string) {
+				assert.NotEmpty(t, requestID)
+			},
+		},
+		{
+			name: "propagate request id of context",
+			ctx:  logger.ContextWithRequestID(context.Background(), "foo"),
+			expect: func(t *testing.T, requestID string) {
+				assert.Equal(t, "foo", requestID)
+			},
+		},
+		{
+			name: "propagate request id of outgoing metadata",
+			ctx:  metadata.AppendToOutgoingContext(context.Background(), RequestIDMetadataKey, "bar"),
+			expect: func(t *testing.T, requestID string) {
+				assert.Equal(t, "bar", requestID)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, ok := metadata.FromOutgoingContext(ctx)
+				assert.True(t, ok)
+
+				_, err := RequestIDUnaryServerInterceptor(metadata.NewIncomingContext(context.Background(), md), nil,
+					&grpc.UnaryServerInfo{FullMethod: method},
+					func(ctx context.Context, req any) (any, error) {
+						requestID, ok := logger.RequestIDFromContext(ctx)
+						assert.True(t, ok)
+						tc.expect(t, requestID)
+						return nil, nil
+					},
+				)
+				return err
+			}
+
+			assert.NoError(t, RequestIDUnaryClientInterceptor(tc.ctx, "/foo", nil, nil, nil, invoker))
+		})
+	}
+}
@@ -0,0 +1,330 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultRetryMaxAttempts is default max attempts of retry interceptor, including the first call.
+	DefaultRetryMaxAttempts = 3
+
+	// DefaultRetryInitBackoff is default initial backoff of retry interceptor.
+	DefaultRetryInitBackoff = 200 * time.Millisecond
+
+	// DefaultRetryMaxBackoff is default max backoff of retry interceptor.
+	DefaultRetryMaxBackoff = 5 * time.Second
+
+	// DefaultRetryBackoffMultiplier is default multiplier of exponential backoff.
+	DefaultRetryBackoffMultiplier = 2.0
+
+	// DefaultRetryJitter is default jitter fraction of backoff.
+	DefaultRetryJitter = 0.2
+
+	// DefaultRetryBudgetMaxTokens is default max tokens of per-method retry budget.
+	DefaultRetryBudgetMaxTokens = 10
+
+	// DefaultRetryBudgetTokenRatio is default tokens refilled by a successful call.
+	DefaultRetryBudgetTokenRatio = 0.1
+)
+
+// defaultRetryCodes is the default codes that can be retried.
+var defaultRetryCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded}
+
+// RetryOption is a functional option for configuring the retry interceptor.
+type RetryOption func(r *retryInterceptor)
+
+// WithRetryMaxAttempts sets the max attempts of a call, including the first call.
+func WithRetryMaxAttempts(maxAttempts int) RetryOption {
+	return func(r *retryInterceptor) {
+		r.maxAttempts = maxAttempts
+	}
+}
+
+// WithRetryBackoff sets the exponential backoff of retry interceptor.
+func WithRetryBackoff(initBackoff, maxBackoff time.Duration, multiplier float64) RetryOption {
+	return func(r *retryInterceptor) {
+		r.initBackoff = initBackoff
+		r.maxBackoff = maxBackoff
+		r.multiplier = multiplier
+	}
+}
+
+// WithRetryJitter sets the jitter fraction of backoff, the value is in range [0, 1].
+func WithRetryJitter(jitter float64) RetryOption {
+	return func(r *retryInterceptor) {
+		r.jitter = jitter
+	}
+}
+
+// WithRetryCodes sets the codes that can be retried.
+func WithRetryCodes(retryCodes ...codes.Code) RetryOption {
+	return func(r *retryInterceptor) {
+		r.codes = retryCodes
+	}
+}
+
+// WithRetryBudget sets the retry budget of the method. If the method is empty,
+// the budget is used as the default budget of the methods which have no budget.
+func WithRetryBudget(method string, maxTokens, tokenRatio float64) RetryOption {
+	return func(r *retryInterceptor) {
+		if method == "" {
+			r.defaultMaxTokens = maxTokens
+			r.defaultTokenRatio = tokenRatio
+			return
+		}
+
+		r.budgets[method] = newRetryBudget(maxTokens, tokenRatio)
+	}
+}
+
+// retryInterceptor retries the calls with exponential backoff.
+type retryInterceptor struct {
+	// maxAttempts is max attempts of a call, including the first call.
+	maxAttempts int
+
+	// initBackoff is the backoff of the first retry.
+	initBackoff time.Duration
+
+	// maxBackoff is the upper limit of backoff.
+	maxBackoff time.Duration
+
+	// multiplier is the factor multiplied by backoff after each retry.
+	multiplier float64
+
+	// jitter is the random fraction applied to backoff.
+	jitter float64
+
+	// codes is the codes that can be retried.
+	codes []codes.Code
+
+	// defaultMaxTokens is max tokens of the default retry budget.
+	defaultMaxTokens float64
+
+	// defaultTokenRatio is token ratio of the default retry budget.
+	defaultTokenRatio float64
+
+	// budgets is the retry budgets keyed by method.
+	budgets map[string]*retryBudget
+
+	// mu protects budgets.
+	mu sync.Mutex
+}
+
+// newRetryInterceptor returns a new retryInterceptor.
+func newRetryInterceptor(opts ...RetryOption) *retryInterceptor {
+	r := &retryInterceptor{
+		maxAttempts:       DefaultRetryMaxAttempts,
+		initBackoff:       DefaultRetryInitBackoff,
+		maxBackoff:        DefaultRetryMaxBackoff,
+		multiplier:        DefaultRetryBackoffMultiplier,
+		jitter:            DefaultRetryJitter,
+		codes:             defaultRetryCodes,
+		defaultMaxTokens:  DefaultRetryBudgetMaxTokens,
+		defaultTokenRatio: DefaultRetryBudgetTokenRatio,
+		budgets:           make(map[string]*retryBudget),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// RetryUnaryClientInterceptor returns a new unary client interceptor that retries
+// the calls with exponential backoff, jitter and per-method retry budgets.
+func RetryUnaryClientInterceptor(opts ...RetryOption) grpc.UnaryClientInterceptor {
+	r := newRetryInterceptor(opts...)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var err error
+		for attempt := 0; attempt < r.maxAttempts; attempt++ {
+			if attempt > 0 {
+				if err := r.wait(ctx, attempt); err != nil {
+					return err
+				}
+			}
+
+			err = invoker(ctx, method, req, reply, cc, opts...)
+			if !r.shouldRetry(ctx, method, err) {
+				return err
+			}
+		}
+
+		return err
+	}
+}
+
+// RetryStreamClientInterceptor returns a new stream client interceptor that retries
+// establishing the stream with exponential backoff, jitter and per-method retry budgets.
+// Messages are never resent, because the stream is only retried before it is returned to caller.
+func RetryStreamClientInterceptor(opts ...RetryOption) grpc.StreamClientInterceptor {
+	r := newRetryInterceptor(opts...)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		var (
+			clientStream grpc.ClientStream
+			err          error
+		)
+		for attempt := 0; attempt < r.maxAttempts; attempt++ {
+			if attempt > 0 {
+				if err := r.wait(ctx, attempt); err != nil {
+					return nil, err
+				}
+			}
+
+			clientStream, err = streamer(ctx, desc, cc, method, opts...)
+			if !r.shouldRetry(ctx, method, err) {
+				return clientStream, err
+			}
+		}
+
+		return clientStream, err
+	}
+}
+
+// shouldRetry returns whether the call should be retried, and records the result into retry budget.
+func (r *retryInterceptor) shouldRetry(ctx context.Context, method string, err error) bool {
+	budget := r.budget(method)
+	if err == nil {
+		budget.onSuccess()
+		return false
+	}
+
+	// The context of caller is done, the call can not be retried.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if !r.isRetryable(err) {
+		return false
+	}
+
+	return budget.onFailure()
+}
+
+// isRetryable returns whether the error code can be retried.
+func (r *retryInterceptor) isRetryable(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	for _, code := range r.codes {
+		if s.Code() == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wait blocks until the backoff of attempt is elapsed or the context is done.
+func (r *retryInterceptor) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(r.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// backoff returns the backoff of attempt with jitter.
+func (r *retryInterceptor) backoff(attempt int) time.Duration {
+	backoff := float64(r.initBackoff) * math.Pow(r.multiplier, float64(attempt-1))
+	if backoff > float64(r.maxBackoff) {
+		backoff = float64(r.maxBackoff)
+	}
+
+	if r.jitter > 0 {
+		backoff *= 1 + r.jitter*(2*rand.Float64()-1)
+	}
+
+	if backoff < 0 {
+		return 0
+	}
+
+	return time.Duration(backoff)
+}
+
+// budget returns the retry budget of method.
+func (r *retryInterceptor) budget(method string) *retryBudget {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	budget, ok := r.budgets[method]
+	if !ok {
+		budget = newRetryBudget(r.defaultMaxTokens, r.defaultTokenRatio)
+		r.budgets[method] = budget
+	}
+
+	return budget
+}
+
+// retryBudget is the token based retry throttling, each failure consumes one token
+// and each success refills tokenRatio tokens. Retries are allowed only when
+// the tokens are more than half of maxTokens.
+type retryBudget struct {
+	// maxTokens is the upper limit of tokens.
+	maxTokens float64
+
+	// tokenRatio is the tokens refilled by a successful call.
+	tokenRatio float64
+
+	// tokens is the current tokens.
+	tokens float64
+
+	// mu protects tokens.
+	mu sync.Mutex
+}
+
+// newRetryBudget returns a new retryBudget.
+func newRetryBudget(maxTokens, tokenRatio float64) *retryBudget {
+	return &retryBudget{
+		maxTokens:  maxTokens,
+		tokenRatio: tokenRatio,
+		tokens:     maxTokens,
+	}
+}
+
+// onSuccess refills tokens of budget.
+func (b *retryBudget) onSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.tokens+b.tokenRatio, b.maxTokens)
+}
+
+// onFailure consumes a token of budget and returns whether retry is allowed.
+func (b *retryBudget) onFailure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Max(b.tokens-1, 0)
+	return b.tokens > b.maxTokens/2
+}
@@ -0,0 +1,186 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []RetryOption
+		errs     []error
+		attempts int
+		expect   func(t *testing.T, err error)
+	}{
+		{
+			name:     "call succeeded",
+			errs:     []error{nil},
+			attempts: 1,
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name:     "call succeeded after retry",
+			errs:     []error{status.Error(codes.Unavailable, ""), status.Error(codes.DeadlineExceeded, ""), nil},
+			attempts: 3,
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+			},
+		},
+		{
+			name:     "call failed with non-retryable code",
+			errs:     []error{status.Error(codes.InvalidArgument, "")},
+			attempts: 1,
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.InvalidArgument, status.Code(err))
+			},
+		},
+		{
+			name:     "call failed after max attempts",
+			opts:     []RetryOption{WithRetryMaxAttempts(2)},
+			errs:     []error{status.Error(codes.Unavailable, ""), status.Error(codes.Unavailable, ""), nil},
+			attempts: 2,
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.Unavailable, status.Code(err))
+			},
+		},
+		{
+			name:     "retry budget is exhausted",
+			opts:     []RetryOption{WithRetryBudget("/foo", 2, 0.1)},
+			errs:     []error{status.Error(codes.Unavailable, ""), status.Error(codes.Unavailable, ""), nil},
+			attempts: 1,
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.Unavailable, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			}
+
+			interceptor := RetryUnaryClientInterceptor(append([]RetryOption{WithRetryBackoff(time.Millisecond, time.Millisecond, 1)}, tc.opts...)...)
+			tc.expect(t, interceptor(context.Background(), "/foo", nil, nil, nil, invoker))
+			assert.Equal(t, tc.attempts, attempts)
+		})
+	}
+}
+
+func TestRetryStreamClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []RetryOption
+		errs     []error
+		attempts int
+		expect   func(t *testing.T, clientStream grpc.ClientStream, err error)
+	}{
+		{
+			name:     "stream is established",
+			errs:     []error{nil},
+			attempts: 1,
+			expect: func(t *testing.T, clientStream grpc.ClientStream, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.NotNil(clientStream)
+			},
+		},
+		{
+			name:     "stream is established after retry",
+			errs:     []error{status.Error(codes.Unavailable, ""), status.Error(codes.DeadlineExceeded, ""), nil},
+			attempts: 3,
+			expect: func(t *testing.T, clientStream grpc.ClientStream, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.NotNil(clientStream)
+			},
+		},
+		{
+			name:     "stream failed with non-retryable code",
+			errs:     []error{status.Error(codes.InvalidArgument, "")},
+			attempts: 1,
+			expect: func(t *testing.T, clientStream grpc.ClientStream, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.InvalidArgument, status.Code(err))
+				assert.Nil(clientStream)
+			},
+		},
+		{
+			name:     "stream failed after max attempts",
+			opts:     []RetryOption{WithRetryMaxAttempts(2)},
+			errs:     []error{status.Error(codes.Unavailable, ""), status.Error(codes.Unavailable, ""), nil},
+			attempts: 2,
+			expect: func(t *testing.T, clientStream grpc.ClientStream, err error) {
+				assert := assert.New(t)
+				assert.Equal(codes.Unavailable, status.Code(err))
+				assert.Nil(clientStream)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int
+			streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				err := tc.errs[attempts]
+				attempts++
+				if err != nil {
+					return nil, err
+				}
+
+				return &testClientStream{}, nil
+			}
+
+			interceptor := RetryStreamClientInterceptor(append([]RetryOption{WithRetryBackoff(time.Millisecond, time.Millisecond, 1)}, tc.opts...)...)
+			clientStream, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/foo", streamer)
+			tc.expect(t, clientStream, err)
+			assert.Equal(t, tc.attempts, attempts)
+		})
+	}
+}
+
+type testClientStream struct {
+	grpc.ClientStream
+}
+
+func TestRetryInterceptor_backoff(t *testing.T) {
+	r := newRetryInterceptor(WithRetryBackoff(100*time.Millisecond, time.Second, 2), WithRetryJitter(0))
+	assert := assert.New(t)
+	assert.Equal(100*time.Millisecond, r.backoff(1))
+	assert.Equal(200*time.Millisecond, r.backoff(2))
+	assert.Equal(400*time.Millisecond, r.backoff(3))
+	assert.Equal(time.Second, r.backoff(10))
+}
@@ -23,7 +23,6 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -47,6 +46,14 @@ func GetV1(ctx context.Context, dynconfig config.Dynconfig, opts ...grpc.DialOpt
 	builder, pickerBuilder := pkgbalancer.NewConsistentHashingBuilder()
 	balancer.Register(builder)
 
+	// Fast-fail the calls when the schedulers are unavailable, the circuits are tracked
+	// per scheduler picked by the balancer.
+	circuitBreaker := rpc.NewCircuitBreakerInterceptor(rpc.WithCircuitBreakerContext(pkgbalancer.WithPickFilter))
+
+	// Refresh the addresses of schedulers when calling error, the unary and stream
+	// calls share the same refresher to debounce refreshing.
+	refresher := rpc.NewRefresherInterceptor(dynconfig)
+
 	conn, err := grpc.DialContext(
 		ctx,
 		resolver.SchedulerVirtualTarget,
@@ -54,21 +61,36 @@ func GetV1(ctx context.Context, dynconfig config.Dynconfig, opts ...grpc.DialOpt
 			grpc.WithDefaultServiceConfig(pkgbalancer.BalancerServiceConfig),
 			grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(
 				rpc.ConvertErrorUnaryClientInterceptor,
+				rpc.RequestIDUnaryClientInterceptor,
 				rpc.OTELUnaryClientInterceptor(),
 				grpc_prometheus.UnaryClientInterceptor,
+				rpc.MetricsUnaryClientInterceptor,
 				grpc_zap.UnaryClientInterceptor(logger.GrpcLogger.Desugar()),
-				grpc_retry.UnaryClientInterceptor(
-					grpc_retry.WithMax(maxRetries),
-					grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffWaitBetween)),
+				rpc.DeadlineUnaryClientInterceptor(dynconfig),
+				rpc.HedgingUnaryClientInterceptor(hedgingDelay,
+					rpc.WithHedgingMethods("/scheduler.Scheduler/StatTask"),
+					rpc.WithHedgingContext(pkgbalancer.WithHedgingAttempt),
 				),
-				rpc.RefresherUnaryClientInterceptor(dynconfig),
+				rpc.RetryUnaryClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
+				refresher.UnaryClientInterceptor(),
+				circuitBreaker.UnaryClientInterceptor(),
 			)),
 			grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(
 				rpc.ConvertErrorStreamClientInterceptor,
+				rpc.RequestIDStreamClientInterceptor,
 				rpc.OTELStreamClientInterceptor(),
 				grpc_prometheus.StreamClientInterceptor,
+				rpc.MetricsStreamClientInterceptor,
 				grpc_zap.StreamClientInterceptor(logger.GrpcLogger.Desugar()),
-				rpc.RefresherStreamClientInterceptor(dynconfig),
+				rpc.RetryStreamClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
+				refresher.StreamClientInterceptor(),
+				circuitBreaker.StreamClientInterceptor(),
 			)),
 		}, opts...)...,
 	)
@@ -94,19 +116,27 @@ func GetV1ByAddr(ctx context.Context, target string, opts ...grpc.DialOption) (V
 			grpc.WithDefaultServiceConfig(pkgbalancer.BalancerServiceConfig),
 			grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(
 				rpc.ConvertErrorUnaryClientInterceptor,
+				rpc.RequestIDUnaryClientInterceptor,
 				rpc.OTELUnaryClientInterceptor(),
 				grpc_prometheus.UnaryClientInterceptor,
+				rpc.MetricsUnaryClientInterceptor,
 				grpc_zap.UnaryClientInterceptor(logger.GrpcLogger.Desugar()),
-				grpc_retry.UnaryClientInterceptor(
-					grpc_retry.WithMax(maxRetries),
-					grpc_retry.WithBackoff(grpc_retry.BackoffLinear(backoffWaitBetween)),
+				rpc.RetryUnaryClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
 				),
 			)),
 			grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(
 				rpc.ConvertErrorStreamClientInterceptor,
+				rpc.RequestIDStreamClientInterceptor,
 				rpc.OTELStreamClientInterceptor(),
 				grpc_prometheus.StreamClientInterceptor,
+				rpc.MetricsStreamClientInterceptor,
 				grpc_zap.StreamClientInterceptor(logger.GrpcLogger.Desugar()),
+				rpc.RetryStreamClientInterceptor(
+					rpc.WithRetryMaxAttempts(maxAttempts),
+					rpc.WithRetryBackoff(initBackoff, maxBackoff, backoffMultiplier),
+				),
 			)),
 		}, opts...)...,
 	)
@@ -138,9 +168,15 @@ type V1 interface {
 	// Checks if any peer has the given task.
 	StatTask(context.Context, *schedulerv1.StatTaskRequest, ...grpc.CallOption) (*schedulerv1.Task, error)
 
+	// FindTaskPeers finds the peers holding the task for the sibling scheduler.
+	FindTaskPeers(context.Context, *schedulerv1.StatTaskRequest, ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error)
+
 	// LeaveTask releases peer in scheduler.
 	LeaveTask(context.Context, *schedulerv1.PeerTarget, ...grpc.CallOption) error
 
+	// ListSiblingPeers lists the sibling peers downloading the same task as the peer.
+	ListSiblingPeers(context.Context, *schedulerv1.PeerTarget, ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error)
+
 	// AnnounceHost announces host to scheduler.
 	AnnounceHost(context.Context, *schedulerv1.AnnounceHostRequest, ...grpc.CallOption) error
 
@@ -232,6 +268,32 @@ func (v *v1) StatTask(ctx context.Context, req *schedulerv1.StatTaskRequest, opt
 	)
 }
 
+// ListSiblingPeers lists the sibling peers downloading the same task as the peer.
+func (v *v1) ListSiblingPeers(ctx context.Context, req *schedulerv1.PeerTarget, opts ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return rpc.ListSiblingPeers(
+		context.WithValue(ctx, pkgbalancer.ContextKey, req.TaskId),
+		v.ClientConn,
+		req,
+		opts...,
+	)
+}
+
+// FindTaskPeers finds the peers holding the task for the sibling scheduler.
+func (v *v1) FindTaskPeers(ctx context.Context, req *schedulerv1.StatTaskRequest, opts ...grpc.CallOption) ([]*schedulerv1.PeerPacket_DestPeer, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return rpc.FindTaskPeers(
+		context.WithValue(ctx, pkgbalancer.ContextKey, req.TaskId),
+		v.ClientConn,
+		req,
+		opts...,
+	)
+}
+
 // LeaveTask releases peer in scheduler.
 func (v *v1) LeaveTask(ctx context.Context, req *schedulerv1.PeerTarget, opts ...grpc.CallOption) error {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
@@ -24,10 +24,19 @@ const (
 	// contextTimeout is timeout of grpc invoke.
 	contextTimeout = 2 * time.Minute
 
-	// maxRetries is maximum number of retries.
-	maxRetries = 3
+	// maxAttempts is maximum number of attempts, including the first call.
+	maxAttempts = 4
 
-	// backoffWaitBetween is waiting for a fixed period of
-	// time between calls in backoff linear.
-	backoffWaitBetween = 500 * time.Millisecond
+	// initBackoff is the backoff of the first retry.
+	initBackoff = 500 * time.Millisecond
+
+	// maxBackoff is the upper limit of backoff.
+	maxBackoff = 5 * time.Second
+
+	// backoffMultiplier is the factor multiplied by backoff after each retry.
+	backoffMultiplier = 2.0
+
+	// hedgingDelay is the waiting duration before firing the hedged request
+	// to the secondary scheduler.
+	hedgingDelay = 1 * time.Second
 )
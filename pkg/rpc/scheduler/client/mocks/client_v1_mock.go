@@ -88,6 +88,26 @@ func (mr *MockV1MockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockV1)(nil).Close))
 }
 
+// FindTaskPeers mocks base method.
+func (m *MockV1) FindTaskPeers(arg0 context.Context, arg1 *scheduler.StatTaskRequest, arg2 ...grpc.CallOption) ([]*scheduler.PeerPacket_DestPeer, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindTaskPeers", varargs...)
+	ret0, _ := ret[0].([]*scheduler.PeerPacket_DestPeer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTaskPeers indicates an expected call of FindTaskPeers.
+func (mr *MockV1MockRecorder) FindTaskPeers(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTaskPeers", reflect.TypeOf((*MockV1)(nil).FindTaskPeers), varargs...)
+}
+
 // LeaveHost mocks base method.
 func (m *MockV1) LeaveHost(arg0 context.Context, arg1 *scheduler.LeaveHostRequest, arg2 ...grpc.CallOption) error {
 	m.ctrl.T.Helper()
@@ -126,6 +146,26 @@ func (mr *MockV1MockRecorder) LeaveTask(arg0, arg1 interface{}, arg2 ...interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveTask", reflect.TypeOf((*MockV1)(nil).LeaveTask), varargs...)
 }
 
+// ListSiblingPeers mocks base method.
+func (m *MockV1) ListSiblingPeers(arg0 context.Context, arg1 *scheduler.PeerTarget, arg2 ...grpc.CallOption) ([]*scheduler.PeerPacket_DestPeer, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListSiblingPeers", varargs...)
+	ret0, _ := ret[0].([]*scheduler.PeerPacket_DestPeer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSiblingPeers indicates an expected call of ListSiblingPeers.
+func (mr *MockV1MockRecorder) ListSiblingPeers(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSiblingPeers", reflect.TypeOf((*MockV1)(nil).ListSiblingPeers), varargs...)
+}
+
 // RegisterPeerTask mocks base method.
 func (m *MockV1) RegisterPeerTask(arg0 context.Context, arg1 *scheduler.PeerTaskRequest, arg2 ...grpc.CallOption) (*scheduler.RegisterResult, error) {
 	m.ctrl.T.Helper()
@@ -21,8 +21,6 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
-	grpc_ratelimit "github.com/grpc-ecosystem/go-grpc-middleware/ratelimit"
-	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpc_validator "github.com/grpc-ecosystem/go-grpc-middleware/validator"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -46,6 +44,9 @@ const (
 	// DefaultBurst is default burst of grpc server.
 	DefaultBurst = 20 * 1000
 
+	// DefaultDeadlineMargin is default margin reserved for responding before the deadline of caller.
+	DefaultDeadlineMargin = 500 * time.Millisecond
+
 	// DefaultMaxConnectionIdle is default max connection idle of grpc keepalive.
 	DefaultMaxConnectionIdle = 10 * time.Minute
 
@@ -56,9 +57,65 @@ const (
 	DefaultMaxConnectionAgeGrace = 5 * time.Minute
 )
 
+// RateLimit is the rate limit of grpc server.
+type RateLimit struct {
+	// QPS is the qps of the methods not matched by method rate limits,
+	// DefaultQPS is used if it is zero.
+	QPS float64
+
+	// Burst is the burst of the methods not matched by method rate limits,
+	// DefaultBurst is used if it is zero.
+	Burst int64
+
+	// Methods is the rate limits of methods, the first matched method rate limit is used.
+	Methods []MethodRateLimit
+}
+
+// MethodRateLimit is the rate limit of method.
+type MethodRateLimit struct {
+	// Method is the full method name, like /scheduler.Scheduler/RegisterPeerTask.
+	Method string
+
+	// QPS is the qps of method.
+	QPS float64
+
+	// Burst is the burst of method.
+	Burst int64
+
+	// MaxInflight is the max concurrent executions of method, zero means unlimited.
+	MaxInflight int64
+}
+
 // New returns a grpc server instance and register service on grpc server.
-func New(schedulerServerV1 schedulerv1.SchedulerServer, schedulerServerV2 schedulerv2.SchedulerServer, opts ...grpc.ServerOption) *grpc.Server {
-	limiter := rpc.NewRateLimiterInterceptor(DefaultQPS, DefaultBurst)
+func New(schedulerServerV1 schedulerv1.SchedulerServer, schedulerServerV2 schedulerv2.SchedulerServer, rateLimit RateLimit, opts ...grpc.ServerOption) *grpc.Server {
+	qps, burst := rateLimit.QPS, rateLimit.Burst
+	if qps <= 0 {
+		qps = DefaultQPS
+	}
+
+	if burst <= 0 {
+		burst = DefaultBurst
+	}
+
+	// The expensive methods are throttled independently, e.g. RegisterPeerTask is throttled
+	// apart from keepalive traffic, and their concurrent executions are bounded to avoid
+	// goroutine explosions during registration storms.
+	var (
+		rateLimiterOpts []rpc.RateLimiterOption
+		maxInflightOpts []rpc.MaxInflightOption
+	)
+	for _, method := range rateLimit.Methods {
+		rateLimiterOpts = append(rateLimiterOpts, rpc.WithMethodRateLimit(method.Method, method.QPS, method.Burst))
+		if method.MaxInflight > 0 {
+			maxInflightOpts = append(maxInflightOpts, rpc.WithMethodMaxInflight(method.Method, method.MaxInflight))
+		}
+	}
+
+	limiter := rpc.NewRateLimiterInterceptor(qps, burst, rateLimiterOpts...)
+	maxInflight := rpc.NewMaxInflightInterceptor(0, maxInflightOpts...)
+
+	// Large responses are compressed, e.g. the candidate parents with piece metadata.
+	serverOpts := append(rpc.CompressionServerOptions(rpc.ZstdCompressor), opts...)
 
 	grpcServer := grpc.NewServer(append([]grpc.ServerOption{
 		grpc.KeepaliveParams(keepalive.ServerParameters{
@@ -67,24 +124,31 @@ func New(schedulerServerV1 schedulerv1.SchedulerServer, schedulerServerV2 schedu
 			MaxConnectionAgeGrace: DefaultMaxConnectionAgeGrace,
 		}),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_ratelimit.UnaryServerInterceptor(limiter),
+			limiter.UnaryServerInterceptor(),
+			maxInflight.UnaryServerInterceptor(),
 			rpc.ConvertErrorUnaryServerInterceptor,
 			otelgrpc.UnaryServerInterceptor(),
 			grpc_prometheus.UnaryServerInterceptor,
+			rpc.MetricsUnaryServerInterceptor,
 			grpc_zap.UnaryServerInterceptor(logger.GrpcLogger.Desugar()),
+			rpc.RequestIDUnaryServerInterceptor,
+			rpc.DeadlineUnaryServerInterceptor(DefaultDeadlineMargin),
 			grpc_validator.UnaryServerInterceptor(),
-			grpc_recovery.UnaryServerInterceptor(),
+			rpc.RecoveryUnaryServerInterceptor,
 		)),
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
-			grpc_ratelimit.StreamServerInterceptor(limiter),
+			limiter.StreamServerInterceptor(),
+			maxInflight.StreamServerInterceptor(),
 			rpc.ConvertErrorStreamServerInterceptor,
 			otelgrpc.StreamServerInterceptor(),
 			grpc_prometheus.StreamServerInterceptor,
+			rpc.MetricsStreamServerInterceptor,
 			grpc_zap.StreamServerInterceptor(logger.GrpcLogger.Desugar()),
+			rpc.RequestIDStreamServerInterceptor,
 			grpc_validator.StreamServerInterceptor(),
-			grpc_recovery.StreamServerInterceptor(),
+			rpc.RecoveryStreamServerInterceptor,
 		)),
-	}, opts...)...)
+	}, serverOpts...)...)
 
 	// Register servers on v1 version of the grpc server.
 	schedulerv1.RegisterSchedulerServer(grpcServer, schedulerServerV1)
@@ -92,6 +156,16 @@ func New(schedulerServerV1 schedulerv1.SchedulerServer, schedulerServerV2 schedu
 	// Register servers on v2 version of the grpc server.
 	schedulerv2.RegisterSchedulerServer(grpcServer, schedulerServerV2)
 
+	// Register peer exchange on grpc server, if v1 version of the server serves the sibling peers.
+	if peerExchangeServer, ok := schedulerServerV1.(rpc.PeerExchangeServer); ok {
+		rpc.RegisterPeerExchangeServer(grpcServer, peerExchangeServer)
+	}
+
+	// Register federation on grpc server, if v1 version of the server serves the sibling schedulers.
+	if federationServer, ok := schedulerServerV1.(rpc.FederationServer); ok {
+		rpc.RegisterFederationServer(grpcServer, federationServer)
+	}
+
 	// Register health on grpc server.
 	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
 
@@ -0,0 +1,54 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// ArtifactSignatureTypeMetadataKey is the metadata key of the signature type of the artifact.
+	ArtifactSignatureTypeMetadataKey = "x-dragonfly-artifact-signature-type"
+
+	// ArtifactSignatureMetadataKey is the binary metadata key of the signature of the artifact,
+	// the signature is the url of the signature or the inline signature, e.g. the armored
+	// gpg signature in multiple lines.
+	ArtifactSignatureMetadataKey = "x-dragonfly-artifact-signature-bin"
+)
+
+// ContextWithSignature returns the outgoing context carrying the signature of the artifact,
+// so dfdaemon verifies the artifact before the download is reported successful.
+func ContextWithSignature(ctx context.Context, typ, signature string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ArtifactSignatureTypeMetadataKey, typ, ArtifactSignatureMetadataKey, signature)
+}
+
+// SignatureFromIncomingContext returns the signature type and the signature carried by the incoming context.
+func SignatureFromIncomingContext(ctx context.Context) (string, string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", "", false
+	}
+
+	types, signatures := md.Get(ArtifactSignatureTypeMetadataKey), md.Get(ArtifactSignatureMetadataKey)
+	if len(types) == 0 || types[0] == "" || len(signatures) == 0 || signatures[0] == "" {
+		return "", "", false
+	}
+
+	return types[0], signatures[0], true
+}
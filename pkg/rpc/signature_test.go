@@ -0,0 +1,71 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestSignature(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() context.Context
+		expect func(t *testing.T, typ, signature string, ok bool)
+	}{
+		{
+			name: "propagate signature",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithSignature(context.Background(), "gpg", "-----BEGIN PGP SIGNATURE-----\nfoo\n"))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, typ, signature string, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal("gpg", typ)
+				assert.Equal("-----BEGIN PGP SIGNATURE-----\nfoo\n", signature)
+			},
+		},
+		{
+			name: "signature is empty",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithSignature(context.Background(), "cosign", ""))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, typ, signature string, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "metadata is empty",
+			ctx:  context.Background,
+			expect: func(t *testing.T, typ, signature string, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			typ, signature, ok := SignatureFromIncomingContext(tc.ctx())
+			tc.expect(t, typ, signature, ok)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/metadata"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+)
+
+const (
+	// TaskIDMetadataKey is the metadata key of task id.
+	TaskIDMetadataKey = "x-dragonfly-task-id"
+
+	// URLMetadataKey is the metadata key of the url of the range task.
+	URLMetadataKey = "x-dragonfly-url"
+
+	// URLMetaMetadataKey is the metadata key of the url meta of the range task, it is encoded in json.
+	URLMetaMetadataKey = "x-dragonfly-url-meta"
+)
+
+// ContextWithTaskID returns the outgoing context carrying the task id, so the task id
+// is used directly instead of generating it by the url and url meta of the request.
+func ContextWithTaskID(ctx context.Context, taskID string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, TaskIDMetadataKey, taskID)
+}
+
+// TaskIDFromIncomingContext returns the task id carried by the incoming context.
+func TaskIDFromIncomingContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	values := md.Get(TaskIDMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return "", false
+	}
+
+	return values[0], true
+}
+
+// ContextWithRange returns the outgoing context carrying the url and the url meta of the range task,
+// so the peer of the full task derives the ids of the full task and the range task by itself,
+// and serves the range from the pieces of the full task.
+func ContextWithRange(ctx context.Context, url string, urlMeta *commonv1.UrlMeta) context.Context {
+	data, err := json.Marshal(urlMeta)
+	if err != nil {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, URLMetadataKey, url, URLMetaMetadataKey, string(data))
+}
+
+// RangeFromIncomingContext returns the url and the url meta of the range task carried by the incoming context.
+func RangeFromIncomingContext(ctx context.Context) (string, *commonv1.UrlMeta, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", nil, false
+	}
+
+	urls, urlMetas := md.Get(URLMetadataKey), md.Get(URLMetaMetadataKey)
+	if len(urls) == 0 || urls[0] == "" || len(urlMetas) == 0 {
+		return "", nil, false
+	}
+
+	urlMeta := &commonv1.UrlMeta{}
+	if err := json.Unmarshal([]byte(urlMetas[0]), urlMeta); err != nil || urlMeta.Range == "" {
+		return "", nil, false
+	}
+
+	return urls[0], urlMeta, true
+}
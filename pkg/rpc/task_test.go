@@ -0,0 +1,128 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+)
+
+func TestTaskID(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() context.Context
+		expect func(t *testing.T, taskID string, ok bool)
+	}{
+		{
+			name: "propagate task id",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithTaskID(context.Background(), "foo"))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, taskID string, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal("foo", taskID)
+			},
+		},
+		{
+			name: "task id is empty",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithTaskID(context.Background(), ""))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, taskID string, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "metadata is empty",
+			ctx:  context.Background,
+			expect: func(t *testing.T, taskID string, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			taskID, ok := TaskIDFromIncomingContext(tc.ctx())
+			tc.expect(t, taskID, ok)
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() context.Context
+		expect func(t *testing.T, url string, urlMeta *commonv1.UrlMeta, ok bool)
+	}{
+		{
+			name: "propagate range",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithRange(context.Background(), "http://example.com/foo", &commonv1.UrlMeta{Tag: "bar", Range: "0-9"}))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, url string, urlMeta *commonv1.UrlMeta, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal("http://example.com/foo", url)
+				assert.Equal("bar", urlMeta.Tag)
+				assert.Equal("0-9", urlMeta.Range)
+			},
+		},
+		{
+			name: "range is empty",
+			ctx: func() context.Context {
+				md, _ := metadata.FromOutgoingContext(ContextWithRange(context.Background(), "http://example.com/foo", &commonv1.UrlMeta{Tag: "bar"}))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			expect: func(t *testing.T, url string, urlMeta *commonv1.UrlMeta, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "url meta is invalid",
+			ctx: func() context.Context {
+				return metadata.NewIncomingContext(context.Background(), metadata.Pairs(URLMetadataKey, "http://example.com/foo", URLMetaMetadataKey, "foo"))
+			},
+			expect: func(t *testing.T, url string, urlMeta *commonv1.UrlMeta, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "metadata is empty",
+			ctx:  context.Background,
+			expect: func(t *testing.T, url string, urlMeta *commonv1.UrlMeta, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			url, urlMeta, ok := RangeFromIncomingContext(tc.ctx())
+			tc.expect(t, url, urlMeta, ok)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// TopologyRegionMetadataKey is the metadata key of host region.
+	TopologyRegionMetadataKey = "x-dragonfly-topology-region"
+
+	// TopologyZoneMetadataKey is the metadata key of host zone.
+	TopologyZoneMetadataKey = "x-dragonfly-topology-zone"
+
+	// TopologyRackMetadataKey is the metadata key of host rack.
+	TopologyRackMetadataKey = "x-dragonfly-topology-rack"
+
+	// TopologySwitchMetadataKey is the metadata key of host switch.
+	TopologySwitchMetadataKey = "x-dragonfly-topology-switch"
+
+	// TopologyHypervisorMetadataKey is the metadata key of host hypervisor.
+	TopologyHypervisorMetadataKey = "x-dragonfly-topology-hypervisor"
+)
+
+// ContextWithTopology returns the outgoing context carrying the topology labels of host,
+// the labels are carried by metadata, so the schedulers not supporting topology ignore them.
+func ContextWithTopology(ctx context.Context, topology types.Topology) context.Context {
+	var kv []string
+	levels := topology.Levels()
+	for i, key := range []string{
+		TopologyRegionMetadataKey,
+		TopologyZoneMetadataKey,
+		TopologyRackMetadataKey,
+		TopologySwitchMetadataKey,
+	} {
+		if value := levels[i]; value != "" {
+			kv = append(kv, key, value)
+		}
+	}
+
+	if topology.Hypervisor != "" {
+		kv = append(kv, TopologyHypervisorMetadataKey, topology.Hypervisor)
+	}
+
+	if len(kv) == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// TopologyFromIncomingContext returns the topology labels of host carried by the incoming context.
+func TopologyFromIncomingContext(ctx context.Context) types.Topology {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return types.Topology{}
+	}
+
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+
+		return ""
+	}
+
+	return types.Topology{
+		Region:     get(TopologyRegionMetadataKey),
+		Zone:       get(TopologyZoneMetadataKey),
+		Rack:       get(TopologyRackMetadataKey),
+		Switch:     get(TopologySwitchMetadataKey),
+		Hypervisor: get(TopologyHypervisorMetadataKey),
+	}
+}
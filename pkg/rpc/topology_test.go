@@ -0,0 +1,57 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestTopology(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology types.Topology
+	}{
+		{
+			name:     "propagate all labels",
+			topology: types.Topology{Region: "foo", Zone: "bar", Rack: "baz", Switch: "qux", Hypervisor: "quux"},
+		},
+		{
+			name:     "propagate part of labels",
+			topology: types.Topology{Region: "foo", Zone: "bar"},
+		},
+		{
+			name:     "propagate empty labels",
+			topology: types.Topology{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			md, _ := metadata.FromOutgoingContext(ContextWithTopology(context.Background(), tc.topology))
+			assert.Equal(tc.topology, TopologyFromIncomingContext(metadata.NewIncomingContext(context.Background(), md)))
+		})
+	}
+
+	assert.Equal(t, types.Topology{}, TopologyFromIncomingContext(context.Background()))
+}
@@ -0,0 +1,111 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package credentialprovider defines the credential provider option shared by object storage source clients.
+package credentialprovider
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// ProviderDefault uses the default credential chain of cloud provider.
+	ProviderDefault = ""
+
+	// ProviderStatic uses the access key or credentials file in option.
+	ProviderStatic = "static"
+
+	// ProviderEnv reads credentials from environment variables.
+	ProviderEnv = "env"
+
+	// ProviderInstance reads credentials from instance metadata service.
+	ProviderInstance = "instance"
+
+	// ProviderAssumeRole assumes a role with the base credentials in option or default credential chain.
+	ProviderAssumeRole = "assumeRole"
+)
+
+const (
+	// DefaultRoleSessionName is the default session name of assumed role.
+	DefaultRoleSessionName = "dragonfly"
+
+	// DefaultDuration is the default duration of assumed role credentials.
+	DefaultDuration = time.Hour
+)
+
+// Option is the credential provider option of object storage source clients.
+type Option struct {
+	// Provider is the credential provider, default is the default credential chain.
+	Provider string `yaml:"provider"`
+
+	// AccessKeyID is the access key id of static provider and base credentials of assume role.
+	AccessKeyID string `yaml:"accessKeyID"`
+
+	// AccessKeySecret is the access key secret of static provider and base credentials of assume role.
+	AccessKeySecret string `yaml:"accessKeySecret"`
+
+	// SessionToken is the session token of static provider.
+	SessionToken string `yaml:"sessionToken"`
+
+	// CredentialsFile is the credentials file of static provider, like gcs service account key.
+	CredentialsFile string `yaml:"credentialsFile"`
+
+	// RoleARN is the role to assume, like aws role arn, alibaba ram role arn or gcs service account email.
+	RoleARN string `yaml:"roleARN"`
+
+	// RoleSessionName is the session name of assumed role.
+	RoleSessionName string `yaml:"roleSessionName"`
+
+	// ExternalID is the external id of assumed role.
+	ExternalID string `yaml:"externalID"`
+
+	// Duration is the duration of assumed role credentials.
+	Duration time.Duration `yaml:"duration"`
+}
+
+// Validate validates the option and sets default values.
+func (o *Option) Validate() error {
+	switch o.Provider {
+	case ProviderDefault, ProviderEnv, ProviderInstance:
+	case ProviderStatic:
+		if o.CredentialsFile == "" && (o.AccessKeyID == "" || o.AccessKeySecret == "") {
+			return errors.New("static credential provider requires parameter accessKeyID and accessKeySecret or credentialsFile")
+		}
+	case ProviderAssumeRole:
+		if o.RoleARN == "" {
+			return errors.New("assumeRole credential provider requires parameter roleARN")
+		}
+
+		if o.RoleSessionName == "" {
+			o.RoleSessionName = DefaultRoleSessionName
+		}
+
+		if o.Duration == 0 {
+			o.Duration = DefaultDuration
+		}
+	default:
+		return fmt.Errorf("unknown credential provider %s", o.Provider)
+	}
+
+	return nil
+}
+
+// HasAccessKey returns whether the option has access key.
+func (o *Option) HasAccessKey() bool {
+	return o.AccessKeyID != "" && o.AccessKeySecret != ""
+}
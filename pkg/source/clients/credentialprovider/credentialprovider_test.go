@@ -0,0 +1,99 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credentialprovider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOption_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		option *Option
+		expect func(t *testing.T, option *Option, err error)
+	}{
+		{
+			name:   "default provider",
+			option: &Option{},
+			expect: func(t *testing.T, option *Option, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+			},
+		},
+		{
+			name:   "static provider with access key",
+			option: &Option{Provider: ProviderStatic, AccessKeyID: "foo", AccessKeySecret: "bar"},
+			expect: func(t *testing.T, option *Option, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				assert.True(option.HasAccessKey())
+			},
+		},
+		{
+			name:   "static provider with credentials file",
+			option: &Option{Provider: ProviderStatic, CredentialsFile: "/etc/gcs.json"},
+			expect: func(t *testing.T, option *Option, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				assert.False(option.HasAccessKey())
+			},
+		},
+		{
+			name:   "static provider without access key",
+			option: &Option{Provider: ProviderStatic, AccessKeyID: "foo"},
+			expect: func(t *testing.T, option *Option, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "static credential provider requires parameter accessKeyID and accessKeySecret or credentialsFile")
+			},
+		},
+		{
+			name:   "assume role provider",
+			option: &Option{Provider: ProviderAssumeRole, RoleARN: "arn:aws:iam::123456789012:role/foo"},
+			expect: func(t *testing.T, option *Option, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				assert.Equal(DefaultRoleSessionName, option.RoleSessionName)
+				assert.Equal(time.Hour, option.Duration)
+			},
+		},
+		{
+			name:   "assume role provider without role",
+			option: &Option{Provider: ProviderAssumeRole},
+			expect: func(t *testing.T, option *Option, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "assumeRole credential provider requires parameter roleARN")
+			},
+		},
+		{
+			name:   "unknown provider",
+			option: &Option{Provider: "foo"},
+			expect: func(t *testing.T, option *Option, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "unknown credential provider foo")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, tc.option, tc.option.Validate())
+		})
+	}
+}
@@ -0,0 +1,294 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fileprotocol
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	"gopkg.in/yaml.v3"
+
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/source"
+)
+
+const (
+	// FileClient is the scheme of local or mounted shared filesystem, like file:///mnt/nfs/path/to/file.
+	FileClient = "file"
+)
+
+var (
+	_ source.ResourceClient         = (*fileSourceClient)(nil)
+	_ source.ResourceMetadataGetter = (*fileSourceClient)(nil)
+	_ source.ResourceLister         = (*fileSourceClient)(nil)
+
+	// ErrFileSourceDisabled is returned when no directory is allowed.
+	ErrFileSourceDisabled = errors.New("file source is disabled, allow directories with resourceClients.file.dirs")
+
+	// ErrPathNotAllowed is returned when the path is not in allowed directories.
+	ErrPathNotAllowed = errors.New("path is not in allowed directories")
+)
+
+func init() {
+	source.RegisterBuilder(FileClient, source.NewPlainResourceClientBuilder(Builder))
+}
+
+// fileSourceOption is the option of file source client.
+type fileSourceOption struct {
+	// Dirs are the directories allowed to read, like the mount point of nfs,
+	// the file source is disabled when it is empty.
+	Dirs []string `yaml:"dirs"`
+}
+
+func Builder(optionYaml []byte) (source.ResourceClient, source.RequestAdapter, []source.Hook, error) {
+	opt := &fileSourceOption{}
+	if err := yaml.Unmarshal(optionYaml, opt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	client, err := newFileSourceClient(opt.Dirs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return client, adapter, nil, nil
+}
+
+func adapter(request *source.Request) *source.Request {
+	return request.Clone(request.Context())
+}
+
+// fileSourceClient is an implementation of the interface of source.ResourceClient for filesystem.
+type fileSourceClient struct {
+	dirs []string
+}
+
+// NewFileSourceClient returns a new file source client which reads files in dirs.
+func NewFileSourceClient(dirs []string) (source.ResourceClient, error) {
+	return newFileSourceClient(dirs)
+}
+
+func newFileSourceClient(dirs []string) (*fileSourceClient, error) {
+	client := &fileSourceClient{}
+	for _, dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("dir %s is not absolute", dir)
+		}
+
+		// resolve symlinks, the mount point may be linked
+		realDir, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		client.dirs = append(client.dirs, filepath.Clean(realDir))
+	}
+
+	return client, nil
+}
+
+// resolvePath returns the real path of url, it must be in allowed directories.
+func (client *fileSourceClient) resolvePath(u *url.URL) (string, error) {
+	if len(client.dirs) == 0 {
+		return "", ErrFileSourceDisabled
+	}
+
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("file url %s with remote host is not supported", u)
+	}
+
+	realPath, err := filepath.EvalSymlinks(filepath.Clean(u.Path))
+	if err != nil {
+		return "", err
+	}
+
+	for _, dir := range client.dirs {
+		if realPath == dir || strings.HasPrefix(realPath, dir+string(filepath.Separator)) {
+			return realPath, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: %w", u.Path, ErrPathNotAllowed)
+}
+
+// stat returns the file info of regular file in url.
+func (client *fileSourceClient) stat(u *url.URL) (string, os.FileInfo, error) {
+	path, err := client.resolvePath(u)
+	if err != nil {
+		return "", nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return "", nil, fmt.Errorf("%s is not a regular file", u.Path)
+	}
+
+	return path, info, nil
+}
+
+func (client *fileSourceClient) GetContentLength(request *source.Request) (int64, error) {
+	_, info, err := client.stat(request.URL)
+	if err != nil {
+		return source.UnknownSourceFileLen, err
+	}
+
+	return info.Size(), nil
+}
+
+func (client *fileSourceClient) IsSupportRange(request *source.Request) (bool, error) {
+	if _, _, err := client.stat(request.URL); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (client *fileSourceClient) GetMetadata(request *source.Request) (*source.Metadata, error) {
+	_, info, err := client.stat(request.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	expireInfo := newExpireInfo(info)
+	hdr := source.Header{}
+	hdr.Set(headers.LastModified, expireInfo.LastModified)
+	hdr.Set(headers.ETag, expireInfo.ETag)
+	return &source.Metadata{
+		Header:             hdr,
+		Status:             http.StatusText(http.StatusOK),
+		StatusCode:         http.StatusOK,
+		SupportRange:       true,
+		TotalContentLength: info.Size(),
+		Validate: func() error {
+			return nil
+		},
+		Temporary: true,
+	}, nil
+}
+
+func (client *fileSourceClient) IsExpired(request *source.Request, info *source.ExpireInfo) (bool, error) {
+	_, fileInfo, err := client.stat(request.URL)
+	if err != nil {
+		return false, err
+	}
+
+	// The etag is compared only, the last modified has second precision and misses
+	// the file rewritten in the same second, the etag covers it with size and nanoseconds.
+	return newExpireInfo(fileInfo).ETag != info.ETag, nil
+}
+
+func (client *fileSourceClient) Download(request *source.Request) (*source.Response, error) {
+	path, info, err := client.stat(request.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	length := info.Size()
+	if rg := request.Header.Get(source.Range); rg != "" {
+		parsedRange, err := nethttp.ParseURLMetaRange(rg, info.Size())
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		if _, err := file.Seek(parsedRange.Start, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+		length = parsedRange.Length
+	}
+
+	return source.NewResponse(
+		&limitedReadCloser{Reader: io.LimitReader(file, length), Closer: file},
+		source.WithContentLength(length),
+		source.WithExpireInfo(newExpireInfo(info)),
+	), nil
+}
+
+func (client *fileSourceClient) GetLastModified(request *source.Request) (int64, error) {
+	_, info, err := client.stat(request.URL)
+	if err != nil {
+		return -1, err
+	}
+
+	return info.ModTime().UnixMilli(), nil
+}
+
+// List lists the files and subdirectories in the directory, it returns a single file entry when the url is a file.
+func (client *fileSourceClient) List(request *source.Request) ([]source.URLEntry, error) {
+	path, err := client.resolvePath(request.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []source.URLEntry{{URL: request.URL, Name: info.Name()}}, nil
+	}
+
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]source.URLEntry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		u := *request.URL
+		u.Path = filepath.Join(request.URL.Path, dirEntry.Name())
+		if dirEntry.IsDir() {
+			u.Path += "/"
+		}
+
+		entries = append(entries, source.URLEntry{URL: &u, Name: dirEntry.Name(), IsDir: dirEntry.IsDir()})
+	}
+
+	return entries, nil
+}
+
+// newExpireInfo returns the expire info of file, the etag is generated from size and modification time.
+func newExpireInfo(info os.FileInfo) source.ExpireInfo {
+	return source.ExpireInfo{
+		LastModified: info.ModTime().UTC().Format(source.LastModifiedLayout),
+		ETag:         fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()),
+	}
+}
+
+// limitedReadCloser reads the range of file and closes the file.
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
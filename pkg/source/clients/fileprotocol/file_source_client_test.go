@@ -0,0 +1,254 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fileprotocol
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/source"
+)
+
+const testContent = "file source content"
+
+func newTestClient(t *testing.T) (*fileSourceClient, string) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte(testContent), 0644))
+
+	client, err := newFileSourceClient([]string{dir})
+	assert.NoError(t, err)
+	return client, client.dirs[0]
+}
+
+func newRequest(t *testing.T, path string) *source.Request {
+	request, err := source.NewRequest((&url.URL{Scheme: FileClient, Path: path}).String())
+	assert.NoError(t, err)
+	return request
+}
+
+func TestBuilder(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		option string
+		expect func(t *testing.T, client *fileSourceClient, err error)
+	}{
+		{
+			name:   "build with dirs",
+			option: "dirs:\n- " + dir,
+			expect: func(t *testing.T, client *fileSourceClient, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Len(client.dirs, 1)
+			},
+		},
+		{
+			name:   "build without dirs",
+			option: "",
+			expect: func(t *testing.T, client *fileSourceClient, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				_, err = client.resolvePath(&url.URL{Scheme: FileClient, Path: dir})
+				assert.ErrorIs(err, ErrFileSourceDisabled)
+			},
+		},
+		{
+			name:   "build with relative dir",
+			option: "dirs:\n- data",
+			expect: func(t *testing.T, client *fileSourceClient, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _, _, err := Builder([]byte(tc.option))
+			if err != nil {
+				tc.expect(t, nil, err)
+				return
+			}
+
+			tc.expect(t, client.(*fileSourceClient), err)
+		})
+	}
+}
+
+func TestFileSourceClient_ResolvePath(t *testing.T) {
+	client, dir := newTestClient(t)
+	outside := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0644))
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(dir, "link")))
+
+	tests := []struct {
+		name      string
+		url       *url.URL
+		expectErr error
+	}{
+		{
+			name: "file in allowed dir",
+			url:  &url.URL{Scheme: FileClient, Path: filepath.Join(dir, "a.txt")},
+		},
+		{
+			name: "file with localhost",
+			url:  &url.URL{Scheme: FileClient, Host: "localhost", Path: filepath.Join(dir, "a.txt")},
+		},
+		{
+			name:      "file outside allowed dir",
+			url:       &url.URL{Scheme: FileClient, Path: filepath.Join(outside, "secret")},
+			expectErr: ErrPathNotAllowed,
+		},
+		{
+			name:      "path traversal",
+			url:       &url.URL{Scheme: FileClient, Path: dir + "/../" + filepath.Base(outside) + "/secret"},
+			expectErr: ErrPathNotAllowed,
+		},
+		{
+			name:      "symlink escapes allowed dir",
+			url:       &url.URL{Scheme: FileClient, Path: filepath.Join(dir, "link")},
+			expectErr: ErrPathNotAllowed,
+		},
+		{
+			name:      "remote host",
+			url:       &url.URL{Scheme: FileClient, Host: "example.com", Path: filepath.Join(dir, "a.txt")},
+			expectErr: errors.New("remote host"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.resolvePath(tc.url)
+			switch {
+			case tc.expectErr == nil:
+				assert.NoError(t, err)
+			case errors.Is(tc.expectErr, ErrPathNotAllowed):
+				assert.ErrorIs(t, err, ErrPathNotAllowed)
+			default:
+				assert.ErrorContains(t, err, tc.expectErr.Error())
+			}
+		})
+	}
+}
+
+func TestFileSourceClient_Metadata(t *testing.T) {
+	assert := assert.New(t)
+	client, dir := newTestClient(t)
+	request := newRequest(t, filepath.Join(dir, "a.txt"))
+
+	length, err := client.GetContentLength(request)
+	assert.NoError(err)
+	assert.Equal(int64(len(testContent)), length)
+
+	support, err := client.IsSupportRange(request)
+	assert.NoError(err)
+	assert.True(support)
+
+	metadata, err := client.GetMetadata(request)
+	assert.NoError(err)
+	assert.True(metadata.SupportRange)
+	assert.Equal(int64(len(testContent)), metadata.TotalContentLength)
+	assert.NotEmpty(metadata.Header.Get(headers.LastModified))
+
+	expired, err := client.IsExpired(request, &source.ExpireInfo{
+		LastModified: metadata.Header.Get(headers.LastModified),
+		ETag:         metadata.Header.Get(headers.ETag),
+	})
+	assert.NoError(err)
+	assert.False(expired)
+
+	expired, err = client.IsExpired(request, &source.ExpireInfo{LastModified: "Sun, 06 Jun 2021 12:52:30 GMT", ETag: `"foo"`})
+	assert.NoError(err)
+	assert.True(expired)
+
+	expired, err = client.IsExpired(request, &source.ExpireInfo{LastModified: metadata.Header.Get(headers.LastModified), ETag: `"foo"`})
+	assert.NoError(err)
+	assert.True(expired)
+
+	_, err = client.GetContentLength(newRequest(t, filepath.Join(dir, "sub")))
+	assert.Error(err)
+}
+
+func TestFileSourceClient_Download(t *testing.T) {
+	client, dir := newTestClient(t)
+	tests := []struct {
+		name      string
+		rang      string
+		expect    string
+		expectErr bool
+	}{
+		{
+			name:   "download file",
+			expect: testContent,
+		},
+		{
+			name:   "download range",
+			rang:   "5-10",
+			expect: testContent[5:11],
+		},
+		{
+			name:      "download invalid range",
+			rang:      "100-200",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+			request := newRequest(t, filepath.Join(dir, "a.txt"))
+			if tc.rang != "" {
+				request.Header.Add(source.Range, tc.rang)
+			}
+
+			resp, err := client.Download(request)
+			if tc.expectErr {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			defer resp.Body.Close()
+			assert.Equal(int64(len(tc.expect)), resp.ContentLength)
+			data, err := io.ReadAll(resp.Body)
+			assert.NoError(err)
+			assert.Equal(tc.expect, string(data))
+		})
+	}
+}
+
+func TestFileSourceClient_List(t *testing.T) {
+	assert := assert.New(t)
+	client, dir := newTestClient(t)
+
+	entries, err := client.List(newRequest(t, dir+"/"))
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	assert.Equal("a.txt", entries[0].Name)
+	assert.False(entries[0].IsDir)
+	assert.Equal(filepath.Join(dir, "a.txt"), entries[0].URL.Path)
+	assert.Equal("sub", entries[1].Name)
+	assert.True(entries[1].IsDir)
+	assert.Equal(filepath.Join(dir, "sub")+"/", entries[1].URL.Path)
+}
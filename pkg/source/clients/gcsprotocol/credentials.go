@@ -0,0 +1,185 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcsprotocol
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/source/clients/credentialprovider"
+)
+
+const (
+	// readOnlyScope is the oauth2 scope to read objects.
+	readOnlyScope = "https://www.googleapis.com/auth/devstorage.read_only"
+
+	// cloudPlatformScope is the oauth2 scope to impersonate service account.
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	// impersonateURL is the iam credentials api to generate access token of service account.
+	impersonateURL = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken"
+
+	// envCredentialsFile is the environment variable of credentials file.
+	envCredentialsFile = "GOOGLE_APPLICATION_CREDENTIALS"
+)
+
+// newTokenSource returns the token source of credential provider, nil means the default credentials.
+func newTokenSource(ctx context.Context, opt *credentialprovider.Option) (oauth2.TokenSource, error) {
+	switch opt.Provider {
+	case credentialprovider.ProviderStatic:
+		if opt.CredentialsFile == "" {
+			return nil, errors.New("gcs static credential provider requires parameter credentialsFile")
+		}
+
+		return tokenSourceFromFile(ctx, opt.CredentialsFile, readOnlyScope)
+	case credentialprovider.ProviderEnv:
+		credentialsFile := os.Getenv(envCredentialsFile)
+		if credentialsFile == "" {
+			return nil, fmt.Errorf("environment variable %s is empty", envCredentialsFile)
+		}
+
+		return tokenSourceFromFile(ctx, credentialsFile, readOnlyScope)
+	case credentialprovider.ProviderInstance:
+		return google.ComputeTokenSource("", readOnlyScope), nil
+	case credentialprovider.ProviderAssumeRole:
+		var (
+			base oauth2.TokenSource
+			err  error
+		)
+		if opt.CredentialsFile != "" {
+			base, err = tokenSourceFromFile(ctx, opt.CredentialsFile, cloudPlatformScope)
+		} else {
+			base, err = google.DefaultTokenSource(ctx, cloudPlatformScope)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return oauth2.ReuseTokenSource(nil, &impersonateTokenSource{
+			httpClient:     oauth2.NewClient(ctx, base),
+			serviceAccount: opt.RoleARN,
+			lifetime:       opt.Duration,
+		}), nil
+	default:
+		return nil, nil
+	}
+}
+
+// newTransport returns the transport authorized by token source, the default credentials are
+// found lazily because it may probe the metadata server.
+func newTransport(tokenSource oauth2.TokenSource, base http.RoundTripper) http.RoundTripper {
+	if tokenSource != nil {
+		return &oauth2.Transport{Source: tokenSource, Base: base}
+	}
+
+	return &defaultCredentialsTransport{base: base}
+}
+
+// defaultCredentialsTransport authorizes requests with default credentials,
+// the requests are anonymous when default credentials are not found.
+type defaultCredentialsTransport struct {
+	base      http.RoundTripper
+	once      sync.Once
+	transport http.RoundTripper
+}
+
+func (t *defaultCredentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.once.Do(func() {
+		tokenSource, err := google.DefaultTokenSource(context.Background(), readOnlyScope)
+		if err != nil {
+			logger.Warnf("gcs default credentials are not found, requests are anonymous: %s", err)
+			t.transport = t.base
+			return
+		}
+
+		t.transport = &oauth2.Transport{Source: tokenSource, Base: t.base}
+	})
+
+	return t.transport.RoundTrip(req)
+}
+
+func tokenSourceFromFile(ctx context.Context, credentialsFile string, scope string) (oauth2.TokenSource, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := google.CredentialsFromJSON(ctx, data, scope)
+	if err != nil {
+		return nil, fmt.Errorf("parse gcs credentials file %s: %w", credentialsFile, err)
+	}
+
+	return credentials.TokenSource, nil
+}
+
+// impersonateTokenSource generates access token of service account with the base credentials.
+type impersonateTokenSource struct {
+	httpClient     *http.Client
+	serviceAccount string
+	lifetime       time.Duration
+}
+
+func (s *impersonateTokenSource) Token() (*oauth2.Token, error) {
+	body, err := json.Marshal(map[string]any{
+		"scope":    []string{readOnlyScope},
+		"lifetime": fmt.Sprintf("%ds", int64(s.lifetime.Seconds())),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Post(fmt.Sprintf(impersonateURL, s.serviceAccount), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("impersonate service account %s: %w", s.serviceAccount, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("impersonate service account %s: unexpected status code %d: %s", s.serviceAccount, resp.StatusCode, string(data))
+	}
+
+	token := &struct {
+		AccessToken string    `json:"accessToken"`
+		ExpireTime  time.Time `json:"expireTime"`
+	}{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      token.ExpireTime,
+	}, nil
+}
@@ -0,0 +1,410 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcsprotocol
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"gopkg.in/yaml.v3"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/source/clients/credentialprovider"
+)
+
+const (
+	// GCSClient is the scheme of google cloud storage objects, like gs://bucket/path/to/object.
+	GCSClient = "gs"
+
+	// DefaultEndpoint is the default xml api endpoint of google cloud storage.
+	DefaultEndpoint = "https://storage.googleapis.com"
+)
+
+var (
+	_ source.ResourceClient         = (*gcsSourceClient)(nil)
+	_ source.ResourceMetadataGetter = (*gcsSourceClient)(nil)
+	_ source.ResourceLister         = (*gcsSourceClient)(nil)
+	_ source.ResourcePresigner      = (*gcsSourceClient)(nil)
+)
+
+func init() {
+	source.RegisterBuilder(GCSClient, source.NewPlainResourceClientBuilder(Builder))
+}
+
+// gcsSourceOption is the option of gcs source client besides the transport option.
+type gcsSourceOption struct {
+	// Endpoint is the xml api endpoint, default is https://storage.googleapis.com.
+	Endpoint string `yaml:"endpoint"`
+
+	// Credential is the credential provider of gcs.
+	Credential credentialprovider.Option `yaml:"credential"`
+}
+
+func Builder(optionYaml []byte) (source.ResourceClient, source.RequestAdapter, []source.Hook, error) {
+	opt := &gcsSourceOption{}
+	if err := yaml.Unmarshal(optionYaml, opt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := opt.Credential.Validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	httpClient, err := source.ParseToHTTPClient(optionYaml)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tokenSource, err := newTokenSource(context.Background(), &opt.Credential)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create gcs token source error: %w", err)
+	}
+
+	opts := []GCSSourceClientOption{
+		WithHTTPClient(&http.Client{Transport: newTransport(tokenSource, httpClient.Transport)}),
+	}
+
+	if opt.Endpoint != "" {
+		opts = append(opts, WithEndpoint(opt.Endpoint))
+	}
+
+	// signed url requires the private key of service account
+	var credentialsFile string
+	switch opt.Credential.Provider {
+	case credentialprovider.ProviderStatic:
+		credentialsFile = opt.Credential.CredentialsFile
+	case credentialprovider.ProviderEnv:
+		credentialsFile = os.Getenv(envCredentialsFile)
+	}
+	if credentialsFile != "" {
+		signer, err := newURLSignerFromFile(credentialsFile)
+		if err != nil {
+			logger.Warnf("gcs presign is disabled: %s", err)
+		} else {
+			opts = append(opts, withURLSigner(signer))
+		}
+	}
+
+	client, err := newGCSSourceClient(opts...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return client, adapter, nil, nil
+}
+
+func adapter(request *source.Request) *source.Request {
+	clonedRequest := request.Clone(request.Context())
+	if request.Header.Get(source.Range) != "" {
+		clonedRequest.Header.Set(headers.Range, fmt.Sprintf("bytes=%s", request.Header.Get(source.Range)))
+		clonedRequest.Header.Del(source.Range)
+	}
+	return clonedRequest
+}
+
+// gcsSourceClient is an implementation of the interface of source.ResourceClient with gcs xml api.
+type gcsSourceClient struct {
+	httpClient *http.Client
+	endpoint   *url.URL
+	signer     *urlSigner
+}
+
+type GCSSourceClientOption func(p *gcsSourceClient)
+
+// WithHTTPClient sets the http client with authorization of gcs.
+func WithHTTPClient(client *http.Client) GCSSourceClientOption {
+	return func(p *gcsSourceClient) {
+		p.httpClient = client
+	}
+}
+
+// WithEndpoint sets the xml api endpoint of gcs.
+func WithEndpoint(endpoint string) GCSSourceClientOption {
+	return func(p *gcsSourceClient) {
+		p.endpoint, _ = url.Parse(endpoint)
+	}
+}
+
+func withURLSigner(signer *urlSigner) GCSSourceClientOption {
+	return func(p *gcsSourceClient) {
+		p.signer = signer
+	}
+}
+
+// NewGCSSourceClient returns a new gcs source client.
+func NewGCSSourceClient(opts ...GCSSourceClientOption) (source.ResourceClient, error) {
+	return newGCSSourceClient(opts...)
+}
+
+func newGCSSourceClient(opts ...GCSSourceClientOption) (*gcsSourceClient, error) {
+	client := &gcsSourceClient{}
+	client.endpoint, _ = url.Parse(DefaultEndpoint)
+	for i := range opts {
+		opts[i](client)
+	}
+
+	if client.endpoint == nil || client.endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid gcs endpoint")
+	}
+
+	if client.httpClient == nil {
+		client.httpClient = http.DefaultClient
+	}
+
+	return client, nil
+}
+
+// objectURL returns the xml api url of object.
+func (client *gcsSourceClient) objectURL(u *url.URL) string {
+	return fmt.Sprintf("%s://%s/%s/%s", client.endpoint.Scheme, client.endpoint.Host,
+		escapePath(u.Host), escapePath(strings.TrimPrefix(u.Path, "/")))
+}
+
+func (client *gcsSourceClient) doRequest(method string, request *source.Request) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(request.Context(), method, client.objectURL(request.URL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range request.Header {
+		for i := range values {
+			req.Header.Add(key, values[i])
+		}
+	}
+
+	return client.httpClient.Do(req)
+}
+
+// head requests the metadata of object.
+func (client *gcsSourceClient) head(request *source.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.Header.Del(headers.Range)
+
+	resp, err := client.doRequest(http.MethodHead, request)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if err := source.CheckResponseCode(resp.StatusCode, []int{http.StatusOK}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (client *gcsSourceClient) GetContentLength(request *source.Request) (int64, error) {
+	resp, err := client.head(request)
+	if err != nil {
+		return source.UnknownSourceFileLen, err
+	}
+
+	return resp.ContentLength, nil
+}
+
+func (client *gcsSourceClient) IsSupportRange(request *source.Request) (bool, error) {
+	if _, err := client.head(request); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetMetadata gets metadata of object for concurrent range reads.
+func (client *gcsSourceClient) GetMetadata(request *source.Request) (*source.Metadata, error) {
+	resp, err := client.head(request)
+	if err != nil {
+		return nil, err
+	}
+
+	hdr := source.Header{}
+	for _, key := range []string{headers.ETag, headers.LastModified, headers.ContentType} {
+		if value := resp.Header.Get(key); value != "" {
+			hdr.Set(key, value)
+		}
+	}
+
+	return &source.Metadata{
+		Header:             hdr,
+		Status:             resp.Status,
+		StatusCode:         resp.StatusCode,
+		SupportRange:       true,
+		TotalContentLength: resp.ContentLength,
+		Validate: func() error {
+			return nil
+		},
+		Temporary: true,
+	}, nil
+}
+
+func (client *gcsSourceClient) IsExpired(request *source.Request, info *source.ExpireInfo) (bool, error) {
+	resp, err := client.head(request)
+	if err != nil {
+		return false, err
+	}
+
+	return !(resp.Header.Get(headers.ETag) == info.ETag || resp.Header.Get(headers.LastModified) == info.LastModified), nil
+}
+
+func (client *gcsSourceClient) Download(request *source.Request) (*source.Response, error) {
+	resp, err := client.doRequest(http.MethodGet, request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := source.NewResponse(
+		resp.Body,
+		source.WithStatus(resp.StatusCode, resp.Status),
+		source.WithValidate(func() error {
+			return source.CheckResponseCode(resp.StatusCode, []int{http.StatusOK, http.StatusPartialContent})
+		}),
+		source.WithTemporary(resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusForbidden),
+		source.WithExpireInfo(source.ExpireInfo{
+			LastModified: resp.Header.Get(headers.LastModified),
+			ETag:         resp.Header.Get(headers.ETag),
+		}),
+	)
+	if resp.ContentLength > 0 {
+		response.ContentLength = resp.ContentLength
+	}
+
+	return response, nil
+}
+
+func (client *gcsSourceClient) GetLastModified(request *source.Request) (int64, error) {
+	resp, err := client.head(request)
+	if err != nil {
+		return -1, err
+	}
+
+	t, err := time.ParseInLocation(source.TimeFormat, resp.Header.Get(headers.LastModified), time.UTC)
+	if err != nil {
+		return -1, err
+	}
+
+	return t.UnixMilli(), nil
+}
+
+// Presign generates v4 signed url of object, it requires the service account key in credentials file.
+func (client *gcsSourceClient) Presign(request *source.Request, expire time.Duration) (*url.URL, error) {
+	if client.signer == nil {
+		return nil, fmt.Errorf("scheme %s: %w, it requires service account key in credentialsFile", GCSClient, source.ErrClientNotSupportPresign)
+	}
+
+	return client.signer.sign(client.endpoint, request.URL.Host, request.URL.Path, expire)
+}
+
+// listBucketResult is the response of list objects v2 in xml api.
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List lists the objects and prefixes in the directory, it returns a single entry when the url is an object.
+func (client *gcsSourceClient) List(request *source.Request) ([]source.URLEntry, error) {
+	prefix := strings.TrimPrefix(request.URL.Path, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	var (
+		entries           []source.URLEntry
+		continuationToken string
+	)
+	for {
+		result, err := client.listObjects(request.Context(), request.URL.Host, prefix, continuationToken)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, content := range result.Contents {
+			if content.Key == prefix {
+				continue
+			}
+
+			u := *request.URL
+			u.Path = "/" + content.Key
+			entries = append(entries, source.URLEntry{URL: &u, Name: path.Base(content.Key)})
+		}
+
+		for _, commonPrefix := range result.CommonPrefixes {
+			u := *request.URL
+			u.Path = "/" + commonPrefix.Prefix
+			entries = append(entries, source.URLEntry{URL: &u, Name: path.Base(commonPrefix.Prefix), IsDir: true})
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+
+	// the url is an object when nothing is under the prefix
+	if len(entries) == 0 && !strings.HasSuffix(request.URL.Path, "/") {
+		return []source.URLEntry{{URL: request.URL, Name: path.Base(request.URL.Path)}}, nil
+	}
+
+	return entries, nil
+}
+
+func (client *gcsSourceClient) listObjects(ctx context.Context, bucket, prefix, continuationToken string) (*listBucketResult, error) {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("delimiter", "/")
+	query.Set("prefix", prefix)
+	if continuationToken != "" {
+		query.Set("continuation-token", continuationToken)
+	}
+
+	listURL := fmt.Sprintf("%s://%s/%s?%s", client.endpoint.Scheme, client.endpoint.Host, escapePath(bucket), query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := source.CheckResponseCode(resp.StatusCode, []int{http.StatusOK}); err != nil {
+		return nil, fmt.Errorf("list gcs objects %s/%s: %w", bucket, prefix, err)
+	}
+
+	result := &listBucketResult{}
+	if err := xml.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, fmt.Errorf("decode gcs list result: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,206 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcsprotocol
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/source"
+)
+
+const (
+	testContent      = "gcs object content"
+	testLastModified = "Sun, 06 Jun 2021 12:52:30 GMT"
+	testETag         = `"e1d9fbb6cf4d8ec3dc2d2e1d6e9e6a3b"`
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headers.Authorization) != "Bearer foo" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/bucket/dir/file%20a.txt":
+			w.Header().Set(headers.ETag, testETag)
+			w.Header().Set(headers.LastModified, testLastModified)
+			modTime, _ := time.Parse(source.TimeFormat, testLastModified)
+			http.ServeContent(w, r, "file a.txt", modTime, strings.NewReader(testContent))
+		case "/bucket":
+			assert.Equal(t, "2", r.URL.Query().Get("list-type"))
+			assert.Equal(t, "/", r.URL.Query().Get("delimiter"))
+			if r.URL.Query().Get("prefix") != "dir/" {
+				fmt.Fprint(w, `<ListBucketResult></ListBucketResult>`)
+				return
+			}
+
+			if r.URL.Query().Get("continuation-token") == "" {
+				fmt.Fprint(w, `<ListBucketResult><Contents><Key>dir/</Key></Contents><Contents><Key>dir/file a.txt</Key></Contents>`+
+					`<IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken></ListBucketResult>`)
+				return
+			}
+
+			fmt.Fprint(w, `<ListBucketResult><CommonPrefixes><Prefix>dir/sub/</Prefix></CommonPrefixes><IsTruncated>false</IsTruncated></ListBucketResult>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestClient(t *testing.T, endpoint string) *gcsSourceClient {
+	client, err := newGCSSourceClient(
+		WithEndpoint(endpoint),
+		WithHTTPClient(&http.Client{Transport: newTransport(staticTokenSource("foo"), http.DefaultTransport)}),
+	)
+	assert.Nil(t, err)
+	return client
+}
+
+func TestGCSSourceClient_Download(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	tests := []struct {
+		name   string
+		rawURL string
+		header map[string]string
+		expect func(t *testing.T, resp *source.Response, err error)
+	}{
+		{
+			name:   "download object",
+			rawURL: "gs://bucket/dir/file%20a.txt",
+			expect: func(t *testing.T, resp *source.Response, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				defer resp.Body.Close()
+				assert.Nil(resp.Validate())
+				assert.Equal(testETag, resp.ExpireInfo().ETag)
+				assert.Equal(testLastModified, resp.ExpireInfo().LastModified)
+				data, err := io.ReadAll(resp.Body)
+				assert.Nil(err)
+				assert.Equal(testContent, string(data))
+			},
+		},
+		{
+			name:   "download object with range",
+			rawURL: "gs://bucket/dir/file%20a.txt",
+			header: map[string]string{source.Range: "4-9"},
+			expect: func(t *testing.T, resp *source.Response, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				defer resp.Body.Close()
+				assert.Equal(http.StatusPartialContent, resp.StatusCode)
+				data, err := io.ReadAll(resp.Body)
+				assert.Nil(err)
+				assert.Equal(testContent[4:10], string(data))
+			},
+		},
+		{
+			name:   "object not found",
+			rawURL: "gs://bucket/dir/foo",
+			expect: func(t *testing.T, resp *source.Response, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				defer resp.Body.Close()
+				assert.Error(resp.Validate())
+				assert.False(resp.Temporary)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := source.NewRequestWithHeader(tc.rawURL, tc.header)
+			assert.Nil(t, err)
+			resp, err := client.Download(adapter(request))
+			tc.expect(t, resp, err)
+		})
+	}
+}
+
+func TestGCSSourceClient_Metadata(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	request, err := source.NewRequestWithHeader("gs://bucket/dir/file%20a.txt", map[string]string{source.Range: "0-3"})
+	assert.Nil(t, err)
+	request = adapter(request)
+
+	metadata, err := client.GetMetadata(request)
+	assert.Nil(t, err)
+	assert.True(t, metadata.SupportRange)
+	assert.Equal(t, int64(len(testContent)), metadata.TotalContentLength)
+	assert.Equal(t, testETag, metadata.Header.Get(headers.ETag))
+
+	length, err := client.GetContentLength(request)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(testContent)), length)
+
+	lastModified, err := client.GetLastModified(request)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1622983950000), lastModified)
+
+	expired, err := client.IsExpired(request, &source.ExpireInfo{ETag: testETag})
+	assert.Nil(t, err)
+	assert.False(t, expired)
+
+	expired, err = client.IsExpired(request, &source.ExpireInfo{ETag: "foo", LastModified: "bar"})
+	assert.Nil(t, err)
+	assert.True(t, expired)
+
+	request, err = source.NewRequest("gs://bucket/foo")
+	assert.Nil(t, err)
+	_, err = client.GetMetadata(request)
+	assert.Error(t, err)
+}
+
+func TestGCSSourceClient_List(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	request, err := source.NewRequest("gs://bucket/dir/")
+	assert.Nil(t, err)
+	entries, err := client.List(request)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "gs://bucket/dir/file%20a.txt", entries[0].URL.String())
+	assert.Equal(t, "file a.txt", entries[0].Name)
+	assert.False(t, entries[0].IsDir)
+	assert.Equal(t, "gs://bucket/dir/sub/", entries[1].URL.String())
+	assert.Equal(t, "sub", entries[1].Name)
+	assert.True(t, entries[1].IsDir)
+
+	request, err = source.NewRequest("gs://bucket/dir/file%20a.txt")
+	assert.Nil(t, err)
+	entries, err = client.List(request)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "file a.txt", entries[0].Name)
+}
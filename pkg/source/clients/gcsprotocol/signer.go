@@ -0,0 +1,156 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcsprotocol
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// signingAlgorithm is the algorithm of v4 signed url.
+	signingAlgorithm = "GOOG4-RSA-SHA256"
+
+	// maxSignedURLExpire is the max expiration of v4 signed url.
+	maxSignedURLExpire = 7 * 24 * time.Hour
+)
+
+// urlSigner generates v4 signed url with service account key.
+type urlSigner struct {
+	email string
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+// newURLSignerFromFile creates signer with service account key file.
+func newURLSignerFromFile(credentialsFile string) (*urlSigner, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccount := &struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}{}
+	if err := json.Unmarshal(data, serviceAccount); err != nil {
+		return nil, err
+	}
+
+	if serviceAccount.ClientEmail == "" || serviceAccount.PrivateKey == "" {
+		return nil, fmt.Errorf("credentials file %s is not a service account key", credentialsFile)
+	}
+
+	key, err := parsePrivateKey([]byte(serviceAccount.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &urlSigner{email: serviceAccount.ClientEmail, key: key, now: time.Now}, nil
+}
+
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not rsa")
+	}
+
+	return rsaKey, nil
+}
+
+// sign generates signed url to get the object in endpoint.
+func (s *urlSigner) sign(endpoint *url.URL, bucket, object string, expire time.Duration) (*url.URL, error) {
+	if expire <= 0 || expire > maxSignedURLExpire {
+		return nil, fmt.Errorf("invalid expire %s, it must be in (0, %s]", expire, maxSignedURLExpire)
+	}
+
+	now := s.now().UTC()
+	datetime := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/auto/storage/goog4_request"
+
+	query := url.Values{}
+	query.Set("X-Goog-Algorithm", signingAlgorithm)
+	query.Set("X-Goog-Credential", s.email+"/"+scope)
+	query.Set("X-Goog-Date", datetime)
+	query.Set("X-Goog-Expires", strconv.FormatInt(int64(expire.Seconds()), 10))
+	query.Set("X-Goog-SignedHeaders", "host")
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalURI := "/" + escapePath(bucket) + "/" + escapePath(strings.TrimPrefix(object, "/"))
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		canonicalURI,
+		canonicalQuery,
+		"host:" + endpoint.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{signingAlgorithm, datetime, scope, hex.EncodeToString(requestHash[:])}, "\n")
+	digest := sha256.Sum256([]byte(stringToSign))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return url.Parse(fmt.Sprintf("%s://%s%s?%s&X-Goog-Signature=%s",
+		endpoint.Scheme, endpoint.Host, canonicalURI, canonicalQuery, hex.EncodeToString(signature)))
+}
+
+// escapePath percent-encodes path except unreserved characters and slash.
+func escapePath(p string) string {
+	var b strings.Builder
+	for _, c := range []byte(p) {
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
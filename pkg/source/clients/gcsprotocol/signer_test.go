@@ -0,0 +1,97 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcsprotocol
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func staticTokenSource(token string) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token, TokenType: "Bearer"})
+}
+
+func TestURLSigner_Sign(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "dragonfly@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: mustMarshalPKCS8(t, key)})),
+	})
+	assert.Nil(t, err)
+	credentialsFile := filepath.Join(t.TempDir(), "credentials.json")
+	assert.Nil(t, os.WriteFile(credentialsFile, data, 0600))
+
+	signer, err := newURLSignerFromFile(credentialsFile)
+	assert.Nil(t, err)
+	signer.now = func() time.Time {
+		return time.Date(2023, 9, 1, 8, 0, 0, 0, time.UTC)
+	}
+
+	endpoint, err := url.Parse(DefaultEndpoint)
+	assert.Nil(t, err)
+	signed, err := signer.sign(endpoint, "bucket", "/dir/file a.txt", time.Hour)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "storage.googleapis.com", signed.Host)
+	assert.Equal(t, "/bucket/dir/file%20a.txt", signed.EscapedPath())
+	query := signed.Query()
+	assert.Equal(t, signingAlgorithm, query.Get("X-Goog-Algorithm"))
+	assert.Equal(t, "dragonfly@example.iam.gserviceaccount.com/20230901/auto/storage/goog4_request", query.Get("X-Goog-Credential"))
+	assert.Equal(t, "20230901T080000Z", query.Get("X-Goog-Date"))
+	assert.Equal(t, "3600", query.Get("X-Goog-Expires"))
+
+	// verify signature with the canonical request
+	rawQuery := signed.RawQuery[:strings.Index(signed.RawQuery, "&X-Goog-Signature=")]
+	canonicalRequest := "GET\n/bucket/dir/file%20a.txt\n" + rawQuery + "\nhost:storage.googleapis.com\n\nhost\nUNSIGNED-PAYLOAD"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	digest := sha256.Sum256([]byte(signingAlgorithm + "\n20230901T080000Z\n20230901/auto/storage/goog4_request\n" + hex.EncodeToString(requestHash[:])))
+	signature, err := hex.DecodeString(query.Get("X-Goog-Signature"))
+	assert.Nil(t, err)
+	assert.Nil(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	_, err = signer.sign(endpoint, "bucket", "file", 8*24*time.Hour)
+	assert.Error(t, err)
+}
+
+func TestEscapePath(t *testing.T) {
+	assert.Equal(t, "dir/file%20a%2Bb~c.txt", escapePath("dir/file a+b~c.txt"))
+	assert.Equal(t, "%E4%B8%AD", escapePath("中"))
+}
+
+func mustMarshalPKCS8(t *testing.T, key *rsa.PrivateKey) []byte {
+	data, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err)
+	return data
+}
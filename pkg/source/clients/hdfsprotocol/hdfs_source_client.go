@@ -21,11 +21,14 @@ import (
 	"io"
 	"net/url"
 	"os/user"
+	"path"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/colinmarc/hdfs/v2"
+	krb "github.com/jcmturner/gokrb5/v8/client"
+	"gopkg.in/yaml.v3"
 
 	"d7y.io/dragonfly/v2/pkg/net/http"
 	"d7y.io/dragonfly/v2/pkg/source"
@@ -48,7 +51,21 @@ func init() {
 }
 
 func Builder(optionYaml []byte) (source.ResourceClient, source.RequestAdapter, []source.Hook, error) {
-	return NewHDFSSourceClient(), adapter, nil, nil
+	opt := &hdfsSourceOption{}
+	if err := yaml.Unmarshal(optionYaml, opt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	opts := []HDFSSourceClientOption{WithUser(opt.User), WithDataTransferProtection(opt.DataTransferProtection)}
+	if opt.Kerberos.Enable {
+		kerberosClient, err := newKerberosClient(&opt.Kerberos)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("create kerberos client error: %w", err)
+		}
+		opts = append(opts, WithKerberos(kerberosClient, opt.Kerberos.ServicePrincipalName))
+	}
+
+	return NewHDFSSourceClient(opts...), adapter, nil, nil
 }
 
 func adapter(request *source.Request) *source.Request {
@@ -60,6 +77,11 @@ func adapter(request *source.Request) *source.Request {
 type hdfsSourceClient struct {
 	sync.RWMutex
 	clientMap map[string]*hdfs.Client
+
+	user                   string
+	dataTransferProtection string
+	kerberosClient         *krb.Client
+	servicePrincipalName   string
 }
 
 // hdfsFileReaderClose is a combination object of the  io.LimitedReader and io.Closer
@@ -77,6 +99,29 @@ func newHdfsFileReaderClose(r io.ReadCloser, n int64) io.ReadCloser {
 
 type HDFSSourceClientOption func(p *hdfsSourceClient)
 
+// WithUser sets the user to access hdfs, default is the current os user.
+func WithUser(user string) HDFSSourceClientOption {
+	return func(p *hdfsSourceClient) {
+		p.user = user
+	}
+}
+
+// WithDataTransferProtection sets the protection level of data transfer with datanodes,
+// it must match dfs.data.transfer.protection of the cluster.
+func WithDataTransferProtection(protection string) HDFSSourceClientOption {
+	return func(p *hdfsSourceClient) {
+		p.dataTransferProtection = protection
+	}
+}
+
+// WithKerberos enables kerberos authentication with namenodes and datanodes.
+func WithKerberos(client *krb.Client, servicePrincipalName string) HDFSSourceClientOption {
+	return func(p *hdfsSourceClient) {
+		p.kerberosClient = client
+		p.servicePrincipalName = servicePrincipalName
+	}
+}
+
 func (h *hdfsSourceClient) GetContentLength(request *source.Request) (int64, error) {
 	hdfsClient, path, err := h.getHDFSClientAndPath(request.URL)
 	if err != nil {
@@ -169,6 +214,42 @@ func (h *hdfsSourceClient) GetLastModified(request *source.Request) (int64, erro
 	return info.ModTime().UnixNano() / time.Millisecond.Nanoseconds(), nil
 }
 
+// List lists the files and subdirectories in the hdfs directory,
+// it returns a single file entry when the url is a file.
+func (h *hdfsSourceClient) List(request *source.Request) ([]source.URLEntry, error) {
+	hdfsClient, dirPath, err := h.getHDFSClientAndPath(request.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := hdfsClient.Stat(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []source.URLEntry{{URL: request.URL, Name: info.Name()}}, nil
+	}
+
+	infos, err := hdfsClient.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]source.URLEntry, 0, len(infos))
+	for _, fi := range infos {
+		u := *request.URL
+		u.Path = path.Join(dirPath, fi.Name())
+		if fi.IsDir() {
+			u.Path += "/"
+		}
+
+		entries = append(entries, source.URLEntry{URL: &u, Name: fi.Name(), IsDir: fi.IsDir()})
+	}
+
+	return entries, nil
+}
+
 // getHDFSClient return hdfs client
 func (h *hdfsSourceClient) getHDFSClient(url *url.URL) (*hdfs.Client, error) {
 	// get client for map
@@ -191,11 +272,21 @@ func (h *hdfsSourceClient) getHDFSClient(url *url.URL) (*hdfs.Client, error) {
 		options.Addresses = []string{url.Host + nameNodeDefaultPort}
 	}
 
-	u, err := user.Current()
-	if err != nil {
-		return nil, err
+	if h.user != "" {
+		options.User = h.user
+	} else {
+		u, err := user.Current()
+		if err != nil {
+			return nil, err
+		}
+		options.User = u.Username
+	}
+
+	options.DataTransferProtection = h.dataTransferProtection
+	if h.kerberosClient != nil {
+		options.KerberosClient = h.kerberosClient
+		options.KerberosServicePrincipleName = h.servicePrincipalName
 	}
-	options.User = u.Username
 
 	// create hdfs client and put map
 	h.RWMutex.Lock()
@@ -232,7 +323,10 @@ func newHDFSSourceClient(opts ...HDFSSourceClientOption) *hdfsSourceClient {
 	return sourceClient
 }
 
-var _ source.ResourceClient = (*hdfsSourceClient)(nil)
+var (
+	_ source.ResourceClient = (*hdfsSourceClient)(nil)
+	_ source.ResourceLister = (*hdfsSourceClient)(nil)
+)
 
 func (rc *hdfsFileReaderClose) Read(p []byte) (n int, err error) {
 	return rc.limitedReader.Read(p)
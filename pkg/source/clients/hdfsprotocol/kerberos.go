@@ -0,0 +1,149 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hdfsprotocol
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	krb "github.com/jcmturner/gokrb5/v8/client"
+	"github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+)
+
+const (
+	// defaultKerberosConfigPath is the default path of krb5.conf.
+	defaultKerberosConfigPath = "/etc/krb5.conf"
+
+	// defaultServicePrincipalName is the default kerberos principal of namenodes,
+	// _HOST will be replaced with the hostname of namenode.
+	defaultServicePrincipalName = "nn/_HOST"
+
+	// kerberosConfigEnv is the env of krb5.conf path.
+	kerberosConfigEnv = "KRB5_CONFIG"
+
+	// kerberosCCacheEnv is the env of kerberos credential cache path.
+	kerberosCCacheEnv = "KRB5CCNAME"
+)
+
+// hdfsSourceOption is the option of hdfs source client.
+type hdfsSourceOption struct {
+	// User is the user to access hdfs, default is the current os user.
+	User string `yaml:"user"`
+
+	// DataTransferProtection is the protection level of data transfer, like authentication, integrity or privacy.
+	DataTransferProtection string `yaml:"dataTransferProtection"`
+
+	// Kerberos is the kerberos authentication option.
+	Kerberos kerberosOption `yaml:"kerberos"`
+}
+
+// kerberosOption is the kerberos authentication option of hdfs source client.
+type kerberosOption struct {
+	// Enable kerberos authentication.
+	Enable bool `yaml:"enable"`
+
+	// Config is the path of krb5.conf, default is KRB5_CONFIG or /etc/krb5.conf.
+	Config string `yaml:"config"`
+
+	// Username is the principal name to login with keytab.
+	Username string `yaml:"username"`
+
+	// Realm is the realm of principal, default is the default_realm in krb5.conf.
+	Realm string `yaml:"realm"`
+
+	// Keytab is the path of keytab file, credential cache will be used when it is empty.
+	Keytab string `yaml:"keytab"`
+
+	// CCache is the path of credential cache, default is KRB5CCNAME or /tmp/krb5cc_<uid>.
+	CCache string `yaml:"ccache"`
+
+	// ServicePrincipalName is the kerberos principal of namenodes, default is nn/_HOST.
+	ServicePrincipalName string `yaml:"servicePrincipalName"`
+}
+
+// newKerberosClient creates kerberos client with keytab or credential cache.
+func newKerberosClient(opt *kerberosOption) (*krb.Client, error) {
+	configPath := opt.Config
+	if configPath == "" {
+		configPath = os.Getenv(kerberosConfigEnv)
+	}
+	if configPath == "" {
+		configPath = defaultKerberosConfigPath
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("load kerberos config %s error: %w", configPath, err)
+	}
+
+	if opt.ServicePrincipalName == "" {
+		opt.ServicePrincipalName = defaultServicePrincipalName
+	}
+
+	if opt.Keytab != "" {
+		if opt.Username == "" {
+			return nil, errors.New("kerberos keytab requires parameter username")
+		}
+
+		kt, err := keytab.Load(opt.Keytab)
+		if err != nil {
+			return nil, fmt.Errorf("load kerberos keytab %s error: %w", opt.Keytab, err)
+		}
+
+		realm := opt.Realm
+		if realm == "" {
+			realm = cfg.LibDefaults.DefaultRealm
+		}
+
+		return krb.NewWithKeytab(opt.Username, realm, kt, cfg, krb.DisablePAFXFAST(true)), nil
+	}
+
+	ccachePath, err := kerberosCCachePath(opt.CCache)
+	if err != nil {
+		return nil, err
+	}
+
+	ccache, err := credentials.LoadCCache(ccachePath)
+	if err != nil {
+		return nil, fmt.Errorf("load kerberos credential cache %s error: %w", ccachePath, err)
+	}
+
+	return krb.NewFromCCache(ccache, cfg, krb.DisablePAFXFAST(true))
+}
+
+// kerberosCCachePath returns the path of credential cache in the same order as kinit.
+func kerberosCCachePath(ccache string) (string, error) {
+	if ccache == "" {
+		ccache = os.Getenv(kerberosCCacheEnv)
+	}
+
+	if ccache == "" {
+		u, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("/tmp/krb5cc_%s", u.Uid), nil
+	}
+
+	return strings.TrimPrefix(ccache, "FILE:"), nil
+}
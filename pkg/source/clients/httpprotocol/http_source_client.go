@@ -20,11 +20,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/go-http-utils/headers"
+	"gopkg.in/yaml.v3"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/source"
@@ -58,13 +60,32 @@ func init() {
 	source.RegisterBuilder(HTTPSClient, source.NewPlainResourceClientBuilder(Builder))
 }
 
+// httpSourceOption is the option of http source client besides the transport option.
+type httpSourceOption struct {
+	// MaxConcurrentPerOrigin limits the concurrent requests to the same origin, 0 is unlimited.
+	MaxConcurrentPerOrigin int `yaml:"maxConcurrentPerOrigin"`
+
+	// Credentials are the auth info of artifact repositories, like nexus and artifactory.
+	Credentials []source.Credential `yaml:"credentials"`
+}
+
 func Builder(optionYaml []byte) (source.ResourceClient, source.RequestAdapter, []source.Hook, error) {
 	var httpClient *http.Client
 	httpClient, err := source.ParseToHTTPClient(optionYaml)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	sc := NewHTTPSourceClient(WithHTTPClient(httpClient))
+
+	opt := &httpSourceOption{}
+	if err := yaml.Unmarshal(optionYaml, opt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	sc := NewHTTPSourceClient(
+		WithHTTPClient(httpClient),
+		WithMaxConcurrentPerOrigin(opt.MaxConcurrentPerOrigin),
+		WithCredentials(opt.Credentials),
+	)
 	return sc, Adapter, nil, nil
 }
 
@@ -79,7 +100,9 @@ func Adapter(request *source.Request) *source.Request {
 
 // httpSourceClient is an implementation of the interface of source.ResourceClient.
 type httpSourceClient struct {
-	httpClient *http.Client
+	httpClient    *http.Client
+	originLimiter *originLimiter
+	credentials   []source.Credential
 }
 
 // NewHTTPSourceClient returns a new HTTPSourceClientOption.
@@ -106,6 +129,22 @@ func WithHTTPClient(client *http.Client) HTTPSourceClientOption {
 	}
 }
 
+// WithCredentials sets the auth info of source hosts.
+func WithCredentials(credentials []source.Credential) HTTPSourceClientOption {
+	return func(sourceClient *httpSourceClient) {
+		sourceClient.credentials = credentials
+	}
+}
+
+// WithMaxConcurrentPerOrigin limits the concurrent requests to the same origin.
+func WithMaxConcurrentPerOrigin(limit int) HTTPSourceClientOption {
+	return func(sourceClient *httpSourceClient) {
+		if limit > 0 {
+			sourceClient.originLimiter = newOriginLimiter(limit)
+		}
+	}
+}
+
 func (client *httpSourceClient) GetContentLength(request *source.Request) (int64, error) {
 	resp, err := client.doRequest(http.MethodGet, request)
 	if err != nil {
@@ -265,11 +304,32 @@ func (client *httpSourceClient) doRequest(method string, request *source.Request
 		}
 	}
 
+	if credential, ok := source.FindCredential(client.credentials, req.URL.Host); ok {
+		credential.Apply(req.Header)
+	}
+
+	origin := req.URL.Host
+	release, err := client.originLimiter.acquire(req.Context(), origin)
+	if err != nil {
+		return nil, err
+	}
+
+	OriginInflightRequestCount.WithLabelValues(origin).Inc()
+	done := func() {
+		OriginInflightRequestCount.WithLabelValues(origin).Dec()
+		release()
+	}
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), newOriginTrace(origin)))
 	logger.Debugf("request %s %s header: %#v", method, req.URL.String(), req.Header)
 	resp, err := client.httpClient.Do(req)
 	if err != nil {
+		done()
 		return nil, err
 	}
+
+	// the origin slot is held until the body is closed
+	resp.Body = &releaseReadCloser{ReadCloser: resp.Body, release: done}
 	return resp, nil
 }
 
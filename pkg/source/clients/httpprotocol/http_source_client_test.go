@@ -22,11 +22,14 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-http-utils/headers"
 	"github.com/jarcoal/httpmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/suite"
 
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
@@ -411,3 +414,141 @@ func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientDoRequest() {
 	suite.Nil(err)
 	suite.EqualValues("ok", string(bytes))
 }
+
+func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientList() {
+	var indexURL = "https://index.com/dir/"
+	index := `<html><body><h1>Index of /dir/</h1>
+<a href="../">../</a>
+<a href="?C=N;O=D">Name</a>
+<a href="sub/">sub/</a>
+<a href="a.txt">a.txt</a>
+<a href="/dir/b%20c.txt">b c.txt</a>
+<a href="a.txt">a.txt</a>
+<a href="sub/nested.txt">nested.txt</a>
+<a href="https://other.com/dir/x">x</a>
+</body></html>`
+	httpmock.RegisterResponder(http.MethodGet, indexURL, func(request *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, index)
+		resp.Header.Set(headers.ContentType, "text/html; charset=utf-8")
+		return resp, nil
+	})
+	request, err := source.NewRequest(indexURL)
+	suite.Nil(err)
+	entries, err := suite.httpClient.List(request)
+	suite.Nil(err)
+	suite.Len(entries, 3)
+	suite.Equal("https://index.com/dir/sub/", entries[0].URL.String())
+	suite.Equal("sub", entries[0].Name)
+	suite.True(entries[0].IsDir)
+	suite.Equal("https://index.com/dir/a.txt", entries[1].URL.String())
+	suite.Equal("a.txt", entries[1].Name)
+	suite.False(entries[1].IsDir)
+	suite.Equal("b c.txt", entries[2].Name)
+	suite.False(entries[2].IsDir)
+
+	var fileURL = "https://index.com/dir/a.txt"
+	request, err = source.NewRequest(fileURL)
+	suite.Nil(err)
+	entries, err = suite.httpClient.List(request)
+	suite.Nil(err)
+	suite.Len(entries, 1)
+	suite.Equal(fileURL, entries[0].URL.String())
+	suite.Equal("a.txt", entries[0].Name)
+	suite.False(entries[0].IsDir)
+}
+
+func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientBuilder() {
+	sc, _, _, err := Builder([]byte("maxConcurrentPerOrigin: 2\nmaxConnsPerHost: 8\n"))
+	suite.Nil(err)
+	client := sc.(*httpSourceClient)
+	suite.NotNil(client.originLimiter)
+	suite.Equal(2, client.originLimiter.limit)
+	transport := client.httpClient.Transport.(*http.Transport)
+	suite.True(transport.ForceAttemptHTTP2)
+	suite.Equal(8, transport.MaxConnsPerHost)
+	suite.Equal(source.DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+
+	sc, _, _, err = Builder([]byte("disableHTTP2: true\n"))
+	suite.Nil(err)
+	client = sc.(*httpSourceClient)
+	suite.Nil(client.originLimiter)
+	suite.False(client.httpClient.Transport.(*http.Transport).ForceAttemptHTTP2)
+}
+
+func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientMaxConcurrentPerOrigin() {
+	var testURL = "https://limited.com/file"
+	httpmock.RegisterResponder(http.MethodGet, testURL, httpmock.NewStringResponder(http.StatusOK, "ok"))
+	client := newHTTPSourceClient(WithHTTPClient(suite.httpClient.httpClient), WithMaxConcurrentPerOrigin(1))
+
+	request, err := source.NewRequest(testURL)
+	suite.Nil(err)
+	resp, err := client.doRequest(http.MethodGet, request)
+	suite.Nil(err)
+
+	// the only slot is held until the first body is closed
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.doRequest(http.MethodGet, request.Clone(ctx))
+	suite.ErrorIs(err, context.DeadlineExceeded)
+
+	suite.Nil(resp.Body.Close())
+	resp, err = client.doRequest(http.MethodGet, request)
+	suite.Nil(err)
+	suite.Nil(resp.Body.Close())
+}
+
+func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientReuseHTTP2Connection() {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client := newHTTPSourceClient(WithHTTPClient(&http.Client{Transport: source.DefaultTransport()}))
+	origin := strings.TrimPrefix(server.URL, "https://")
+	reused := OriginConnectionCount.WithLabelValues(origin, "true")
+	created := OriginConnectionCount.WithLabelValues(origin, "false")
+
+	for i := 0; i < 2; i++ {
+		request, err := source.NewRequest(server.URL)
+		suite.Nil(err)
+		resp, err := client.doRequest(http.MethodGet, request)
+		suite.Nil(err)
+		data, err := io.ReadAll(resp.Body)
+		suite.Nil(err)
+		suite.Nil(resp.Body.Close())
+		suite.Equal("HTTP/2.0", string(data))
+	}
+
+	suite.Equal(float64(1), testutil.ToFloat64(created))
+	suite.Equal(float64(1), testutil.ToFloat64(reused))
+	suite.Equal(float64(0), testutil.ToFloat64(OriginInflightRequestCount.WithLabelValues(origin)))
+}
+
+func (suite *HTTPSourceClientTestSuite) TestHttpSourceClientCredentials() {
+	var testURL = "https://artifactory.com/artifactory/generic/file"
+	httpmock.RegisterResponder(http.MethodGet, testURL, func(request *http.Request) (*http.Response, error) {
+		return httpmock.NewStringResponse(http.StatusOK, request.Header.Get("Authorization")), nil
+	})
+	client := newHTTPSourceClient(
+		WithHTTPClient(suite.httpClient.httpClient),
+		WithCredentials([]source.Credential{{Host: "artifactory.com", Token: "foo"}}),
+	)
+
+	request, err := source.NewRequest(testURL)
+	suite.Nil(err)
+	resp, err := client.doRequest(http.MethodGet, request)
+	suite.Nil(err)
+	data, err := io.ReadAll(resp.Body)
+	suite.Nil(err)
+	suite.Equal("Bearer foo", string(data))
+
+	request, err = source.NewRequestWithHeader(testURL, map[string]string{"Authorization": "Basic bar"})
+	suite.Nil(err)
+	resp, err = client.doRequest(http.MethodGet, request)
+	suite.Nil(err)
+	data, err = io.ReadAll(resp.Body)
+	suite.Nil(err)
+	suite.Equal("Basic bar", string(data))
+}
@@ -0,0 +1,121 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httpprotocol
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+
+	"d7y.io/dragonfly/v2/pkg/source"
+)
+
+// maxIndexSize is the max size of the html index page.
+const maxIndexSize = 32 * 1024 * 1024
+
+var (
+	_ source.ResourceLister = (*httpSourceClient)(nil)
+
+	// hrefRegexp matches the links in the html index page, like nginx autoindex and apache mod_autoindex.
+	hrefRegexp = regexp.MustCompile(`(?i)<a\s[^>]*href\s*=\s*["']([^"']+)["']`)
+)
+
+// List lists the entries of the html index page when the url is a directory ending with "/",
+// otherwise it returns the url as a single file entry.
+func (client *httpSourceClient) List(request *source.Request) ([]source.URLEntry, error) {
+	if !strings.HasSuffix(request.URL.Path, "/") {
+		return []source.URLEntry{buildURLEntry(request.URL, false)}, nil
+	}
+
+	resp, err := client.doRequest(http.MethodGet, request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := source.CheckResponseCode(resp.StatusCode, []int{http.StatusOK}); err != nil {
+		return nil, err
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get(headers.ContentType)); mediaType != "text/html" {
+		return []source.URLEntry{buildURLEntry(request.URL, false)}, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the links against the final url after redirects.
+	base := request.URL
+	if resp.Request != nil {
+		base = resp.Request.URL
+	}
+
+	return parseIndex(base, body), nil
+}
+
+// parseIndex returns the direct children of base linked in the html index page.
+func parseIndex(base *url.URL, body []byte) []source.URLEntry {
+	var (
+		entries []source.URLEntry
+		visited = map[string]struct{}{}
+	)
+
+	for _, match := range hrefRegexp.FindAllSubmatch(body, -1) {
+		href := string(match[1])
+		if strings.HasPrefix(href, "?") || strings.HasPrefix(href, "#") {
+			continue
+		}
+
+		ref, err := url.Parse(href)
+		if err != nil {
+			continue
+		}
+
+		u := base.ResolveReference(ref)
+		u.RawQuery, u.Fragment = "", ""
+		if u.Scheme != base.Scheme || u.Host != base.Host || !strings.HasPrefix(u.Path, base.Path) {
+			continue
+		}
+
+		// Skip the parent, the index itself and the nested entries.
+		name := strings.TrimSuffix(strings.TrimPrefix(u.Path, base.Path), "/")
+		if name == "" || strings.Contains(name, "/") {
+			continue
+		}
+
+		if _, ok := visited[u.Path]; ok {
+			continue
+		}
+		visited[u.Path] = struct{}{}
+
+		entries = append(entries, buildURLEntry(u, strings.HasSuffix(u.Path, "/")))
+	}
+
+	return entries
+}
+
+func buildURLEntry(u *url.URL, isDir bool) source.URLEntry {
+	return source.URLEntry{URL: u, Name: path.Base(u.Path), IsDir: isDir}
+}
@@ -0,0 +1,54 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httpprotocol
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+const (
+	// metricsSubsystem is subsystem of back-to-source http metrics.
+	metricsSubsystem = "source_http"
+)
+
+// Variables declared for metrics.
+var (
+	OriginConnectionCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "origin_connections_total",
+		Help:      "Counter of the number of the connections got for origin requests, labeled by whether the connection is reused.",
+	}, []string{"origin", "reused"})
+
+	OriginHandshakeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "origin_handshake_duration_seconds",
+		Help:      "Histogram of the duration of the tcp connect and tls handshake to origin.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+	}, []string{"origin", "phase"})
+
+	OriginInflightRequestCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "origin_inflight_requests",
+		Help:      "Gauge of the number of the inflight requests sent to origin.",
+	}, []string{"origin"})
+)
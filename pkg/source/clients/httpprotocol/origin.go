@@ -0,0 +1,127 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package httpprotocol
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	handshakePhaseConnect = "connect"
+	handshakePhaseTLS     = "tls"
+)
+
+// originLimiter limits the concurrent requests for every origin.
+type originLimiter struct {
+	limit int
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newOriginLimiter(limit int) *originLimiter {
+	return &originLimiter{
+		limit: limit,
+		slots: map[string]chan struct{}{},
+	}
+}
+
+// acquire blocks until a slot of origin is available, the returned function releases the slot,
+// a nil limiter never blocks.
+func (l *originLimiter) acquire(ctx context.Context, origin string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	slot, ok := l.slots[origin]
+	if !ok {
+		slot = make(chan struct{}, l.limit)
+		l.slots[origin] = slot
+	}
+	l.mu.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-slot })
+	}, nil
+}
+
+// releaseReadCloser calls release once the body is closed.
+type releaseReadCloser struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// newOriginTrace records connection reuse and handshake latency of origin.
+func newOriginTrace(origin string) *httptrace.ClientTrace {
+	var (
+		mu           sync.Mutex
+		connectStart = map[string]time.Time{}
+		tlsStart     time.Time
+	)
+
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			OriginConnectionCount.WithLabelValues(origin, strconv.FormatBool(info.Reused)).Inc()
+		},
+		ConnectStart: func(network, addr string) {
+			mu.Lock()
+			connectStart[addr] = time.Now()
+			mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			start, ok := connectStart[addr]
+			mu.Unlock()
+			if ok && err == nil {
+				OriginHandshakeDuration.WithLabelValues(origin, handshakePhaseConnect).Observe(time.Since(start).Seconds())
+			}
+		},
+		TLSHandshakeStart: func() {
+			mu.Lock()
+			tlsStart = time.Now()
+			mu.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			mu.Lock()
+			start := tlsStart
+			mu.Unlock()
+			if !start.IsZero() && err == nil {
+				OriginHandshakeDuration.WithLabelValues(origin, handshakePhaseTLS).Observe(time.Since(start).Seconds())
+			}
+		},
+	}
+}
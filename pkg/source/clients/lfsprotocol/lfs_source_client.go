@@ -0,0 +1,330 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lfsprotocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-http-utils/headers"
+	"gopkg.in/yaml.v3"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/source"
+)
+
+const (
+	// LFSClient is the scheme of git lfs objects, like lfs://github.com/owner/repo.git/<oid>?size=<size>.
+	LFSClient = "lfs"
+
+	// lfsMediaType is the media type of git lfs batch api.
+	lfsMediaType = "application/vnd.git-lfs+json"
+
+	// lfsOperationDownload is the download operation of git lfs batch api.
+	lfsOperationDownload = "download"
+
+	// lfsTransferBasic is the basic transfer adapter of git lfs batch api.
+	lfsTransferBasic = "basic"
+
+	// repositorySuffix separates the repository path and oid in url path.
+	repositorySuffix = ".git/"
+
+	// sizeQuery is the query of object size.
+	sizeQuery = "size"
+)
+
+var (
+	_ source.ResourceClient = (*lfsSourceClient)(nil)
+
+	oidRegexp = regexp.MustCompile(`^[a-f0-9]{64}$`)
+)
+
+func init() {
+	source.RegisterBuilder(LFSClient,
+		source.NewPlainResourceClientBuilder(Builder),
+		source.WithDirector(source.NewPlainDirector(Director)))
+}
+
+// lfsSourceOption is the option of git lfs source client besides the transport option.
+type lfsSourceOption struct {
+	// PlainHTTP uses http instead of https to access lfs server.
+	PlainHTTP bool `yaml:"plainHTTP"`
+
+	// Credentials are the auth info of lfs servers.
+	Credentials []source.Credential `yaml:"credentials"`
+}
+
+func Builder(optionYaml []byte) (source.ResourceClient, source.RequestAdapter, []source.Hook, error) {
+	httpClient, err := source.ParseToHTTPClient(optionYaml)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	opt := &lfsSourceOption{}
+	if err := yaml.Unmarshal(optionYaml, opt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return newLFSSourceClient(httpClient, opt), adapter, nil, nil
+}
+
+func adapter(request *source.Request) *source.Request {
+	return request.Clone(request.Context())
+}
+
+// Director sets the sha256 oid as digest of task when the whole object is downloaded,
+// the peers can check data without extra hashing.
+func Director(rawURL *url.URL, urlMeta *commonv1.UrlMeta) error {
+	obj, err := parseObject(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if urlMeta != nil && urlMeta.Digest == "" && urlMeta.Range == "" {
+		urlMeta.Digest = digest.New(digest.AlgorithmSHA256, obj.OID).String()
+	}
+
+	return nil
+}
+
+// lfsSourceClient is an implementation of the interface of source.ResourceClient for git lfs.
+type lfsSourceClient struct {
+	httpClient  *http.Client
+	scheme      string
+	credentials []source.Credential
+}
+
+func newLFSSourceClient(httpClient *http.Client, opt *lfsSourceOption) *lfsSourceClient {
+	scheme := "https"
+	if opt.PlainHTTP {
+		scheme = "http"
+	}
+
+	return &lfsSourceClient{
+		httpClient:  httpClient,
+		scheme:      scheme,
+		credentials: opt.Credentials,
+	}
+}
+
+// object is the git lfs object in url.
+type object struct {
+	// Repository is the repository path ends with .git.
+	Repository string
+
+	// OID is the sha256 of object.
+	OID string `json:"oid"`
+
+	// Size is the size of object.
+	Size int64 `json:"size"`
+}
+
+// parseObject parses url like lfs://github.com/owner/repo.git/<oid>?size=<size>.
+func parseObject(u *url.URL) (*object, error) {
+	idx := strings.LastIndex(u.Path, repositorySuffix)
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid lfs url %s: repository must end with .git", u)
+	}
+
+	oid := u.Path[idx+len(repositorySuffix):]
+	if !oidRegexp.MatchString(oid) {
+		return nil, fmt.Errorf("invalid lfs url %s: invalid oid %s", u, oid)
+	}
+
+	size, err := strconv.ParseInt(u.Query().Get(sizeQuery), 10, 64)
+	if err != nil || size < 0 {
+		return nil, fmt.Errorf("invalid lfs url %s: invalid size %q", u, u.Query().Get(sizeQuery))
+	}
+
+	return &object{
+		Repository: u.Path[:idx+len(repositorySuffix)-1],
+		OID:        oid,
+		Size:       size,
+	}, nil
+}
+
+type batchRequest struct {
+	Operation string    `json:"operation"`
+	Transfers []string  `json:"transfers"`
+	Objects   []*object `json:"objects"`
+}
+
+type batchResponse struct {
+	Objects []*batchObject `json:"objects"`
+}
+
+type batchObject struct {
+	OID     string             `json:"oid"`
+	Size    int64              `json:"size"`
+	Actions map[string]*action `json:"actions"`
+	Error   *batchError        `json:"error"`
+}
+
+type action struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header"`
+}
+
+type batchError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// downloadAction requests git lfs batch api for the download action of object.
+func (client *lfsSourceClient) downloadAction(request *source.Request) (*object, *action, error) {
+	obj, err := parseObject(request.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := json.Marshal(&batchRequest{
+		Operation: lfsOperationDownload,
+		Transfers: []string{lfsTransferBasic},
+		Objects:   []*object{obj},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batchURL := fmt.Sprintf("%s://%s%s/info/lfs/objects/batch", client.scheme, request.URL.Host, obj.Repository)
+	req, err := http.NewRequestWithContext(request.Context(), http.MethodPost, batchURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set(headers.Accept, lfsMediaType)
+	req.Header.Set(headers.ContentType, lfsMediaType)
+	if auth := request.Header.Get(headers.Authorization); auth != "" {
+		req.Header.Set(headers.Authorization, auth)
+	} else if credential, ok := source.FindCredential(client.credentials, request.URL.Host); ok {
+		credential.Apply(req.Header)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := source.CheckResponseCode(resp.StatusCode, []int{http.StatusOK}); err != nil {
+		return nil, nil, err
+	}
+
+	batch := &batchResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(batch); err != nil {
+		return nil, nil, fmt.Errorf("decode lfs batch response error: %w", err)
+	}
+
+	for _, o := range batch.Objects {
+		if o.OID != obj.OID {
+			continue
+		}
+
+		if o.Error != nil {
+			return nil, nil, fmt.Errorf("lfs object %s error: %d %s", obj.OID, o.Error.Code, o.Error.Message)
+		}
+
+		if a, ok := o.Actions[lfsOperationDownload]; ok && a.Href != "" {
+			return obj, a, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("lfs object %s has no download action", obj.OID)
+}
+
+// doDownload requests the download action of object with range header.
+func (client *lfsSourceClient) doDownload(request *source.Request, rg string) (*http.Response, error) {
+	_, a, err := client.downloadAction(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(request.Context(), http.MethodGet, a.Href, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range a.Header {
+		req.Header.Set(k, v)
+	}
+
+	if rg != "" {
+		req.Header.Set(headers.Range, "bytes="+rg)
+	}
+
+	return client.httpClient.Do(req)
+}
+
+func (client *lfsSourceClient) GetContentLength(request *source.Request) (int64, error) {
+	obj, err := parseObject(request.URL)
+	if err != nil {
+		return source.UnknownSourceFileLen, err
+	}
+
+	return obj.Size, nil
+}
+
+func (client *lfsSourceClient) IsSupportRange(request *source.Request) (bool, error) {
+	resp, err := client.doDownload(request, "0-0")
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusPartialContent, nil
+}
+
+// IsExpired returns false, lfs objects are addressed by content.
+func (client *lfsSourceClient) IsExpired(request *source.Request, info *source.ExpireInfo) (bool, error) {
+	return false, nil
+}
+
+func (client *lfsSourceClient) Download(request *source.Request) (*source.Response, error) {
+	resp, err := client.doDownload(request, request.Header.Get(source.Range))
+	if err != nil {
+		return nil, err
+	}
+
+	response := source.NewResponse(
+		resp.Body,
+		source.WithStatus(resp.StatusCode, resp.Status),
+		source.WithValidate(func() error {
+			return source.CheckResponseCode(resp.StatusCode, []int{http.StatusOK, http.StatusPartialContent})
+		}),
+	)
+	if resp.ContentLength > 0 {
+		response.ContentLength = resp.ContentLength
+	}
+
+	return response, nil
+}
+
+// GetLastModified returns -1, lfs objects have no last modified time.
+func (client *lfsSourceClient) GetLastModified(request *source.Request) (int64, error) {
+	if _, err := parseObject(request.URL); err != nil {
+		return -1, err
+	}
+
+	return -1, nil
+}
@@ -0,0 +1,250 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lfsprotocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/stretchr/testify/assert"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	"d7y.io/dragonfly/v2/pkg/source"
+)
+
+const (
+	testOID     = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+	testContent = "lfs object content"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/owner/repo.git/info/lfs/objects/batch", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, lfsMediaType, r.Header.Get(headers.Accept))
+		if r.Header.Get(headers.Authorization) != "Bearer foo" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		req := &batchRequest{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(req))
+		assert.Equal(t, lfsOperationDownload, req.Operation)
+
+		resp := &batchResponse{}
+		for _, o := range req.Objects {
+			if o.OID != testOID {
+				resp.Objects = append(resp.Objects, &batchObject{OID: o.OID, Size: o.Size, Error: &batchError{Code: 404, Message: "Object does not exist"}})
+				continue
+			}
+
+			resp.Objects = append(resp.Objects, &batchObject{
+				OID:  o.OID,
+				Size: o.Size,
+				Actions: map[string]*action{
+					lfsOperationDownload: {
+						Href:   fmt.Sprintf("http://%s/objects/%s", r.Host, o.OID),
+						Header: map[string]string{"X-Object-Token": "bar"},
+					},
+				},
+			})
+		}
+
+		w.Header().Set(headers.ContentType, lfsMediaType)
+		assert.Nil(t, json.NewEncoder(w).Encode(resp))
+	})
+	mux.HandleFunc("/objects/"+testOID, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Object-Token") != "bar" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.ServeContent(w, r, testOID, time.Time{}, strings.NewReader(testContent))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func newTestClient() *lfsSourceClient {
+	return newLFSSourceClient(http.DefaultClient, &lfsSourceOption{
+		PlainHTTP:   true,
+		Credentials: []source.Credential{{Host: "127.0.0.1:*", Token: "foo"}},
+	})
+}
+
+func TestParseObject(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawURL string
+		expect func(t *testing.T, obj *object, err error)
+	}{
+		{
+			name:   "parse object",
+			rawURL: fmt.Sprintf("lfs://github.com/owner/repo.git/%s?size=18", testOID),
+			expect: func(t *testing.T, obj *object, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				assert.Equal("/owner/repo.git", obj.Repository)
+				assert.Equal(testOID, obj.OID)
+				assert.Equal(int64(18), obj.Size)
+			},
+		},
+		{
+			name:   "repository without .git",
+			rawURL: fmt.Sprintf("lfs://github.com/owner/repo/%s?size=18", testOID),
+			expect: func(t *testing.T, obj *object, err error) {
+				assert := assert.New(t)
+				assert.ErrorContains(err, "repository must end with .git")
+			},
+		},
+		{
+			name:   "invalid oid",
+			rawURL: "lfs://github.com/owner/repo.git/foo?size=18",
+			expect: func(t *testing.T, obj *object, err error) {
+				assert := assert.New(t)
+				assert.ErrorContains(err, "invalid oid")
+			},
+		},
+		{
+			name:   "without size",
+			rawURL: fmt.Sprintf("lfs://github.com/owner/repo.git/%s", testOID),
+			expect: func(t *testing.T, obj *object, err error) {
+				assert := assert.New(t)
+				assert.ErrorContains(err, "invalid size")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.rawURL)
+			assert.Nil(t, err)
+			obj, err := parseObject(u)
+			tc.expect(t, obj, err)
+		})
+	}
+}
+
+func TestDirector(t *testing.T) {
+	u, err := url.Parse(fmt.Sprintf("lfs://github.com/owner/repo.git/%s?size=18", testOID))
+	assert.Nil(t, err)
+
+	urlMeta := &commonv1.UrlMeta{}
+	assert.Nil(t, Director(u, urlMeta))
+	assert.Equal(t, "sha256:"+testOID, urlMeta.Digest)
+
+	urlMeta = &commonv1.UrlMeta{Range: "0-3"}
+	assert.Nil(t, Director(u, urlMeta))
+	assert.Empty(t, urlMeta.Digest)
+}
+
+func TestLFSSourceClient_Download(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := newTestClient()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name   string
+		rawURL string
+		header map[string]string
+		expect func(t *testing.T, resp *source.Response, err error)
+	}{
+		{
+			name:   "download object",
+			rawURL: fmt.Sprintf("lfs://%s/owner/repo.git/%s?size=18", host, testOID),
+			expect: func(t *testing.T, resp *source.Response, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				defer resp.Body.Close()
+				assert.Nil(resp.Validate())
+				data, err := io.ReadAll(resp.Body)
+				assert.Nil(err)
+				assert.Equal(testContent, string(data))
+			},
+		},
+		{
+			name:   "download object with range",
+			rawURL: fmt.Sprintf("lfs://%s/owner/repo.git/%s?size=18", host, testOID),
+			header: map[string]string{source.Range: "4-9"},
+			expect: func(t *testing.T, resp *source.Response, err error) {
+				assert := assert.New(t)
+				assert.Nil(err)
+				defer resp.Body.Close()
+				assert.Equal(http.StatusPartialContent, resp.StatusCode)
+				data, err := io.ReadAll(resp.Body)
+				assert.Nil(err)
+				assert.Equal(testContent[4:10], string(data))
+			},
+		},
+		{
+			name:   "object not found",
+			rawURL: fmt.Sprintf("lfs://%s/owner/repo.git/%s?size=18", host, strings.Repeat("0", 64)),
+			expect: func(t *testing.T, resp *source.Response, err error) {
+				assert := assert.New(t)
+				assert.ErrorContains(err, "Object does not exist")
+			},
+		},
+		{
+			name:   "unauthorized",
+			rawURL: fmt.Sprintf("lfs://%s/owner/repo.git/%s?size=18", host, testOID),
+			header: map[string]string{headers.Authorization: "Bearer bar"},
+			expect: func(t *testing.T, resp *source.Response, err error) {
+				assert := assert.New(t)
+				assert.Error(err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := source.NewRequestWithHeader(tc.rawURL, tc.header)
+			assert.Nil(t, err)
+			resp, err := client.Download(request)
+			tc.expect(t, resp, err)
+		})
+	}
+}
+
+func TestLFSSourceClient_Metadata(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	client := newTestClient()
+
+	request, err := source.NewRequest(fmt.Sprintf("lfs://%s/owner/repo.git/%s?size=18", strings.TrimPrefix(server.URL, "http://"), testOID))
+	assert.Nil(t, err)
+
+	length, err := client.GetContentLength(request)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(testContent)), length)
+
+	supportRange, err := client.IsSupportRange(request)
+	assert.Nil(t, err)
+	assert.True(t, supportRange)
+
+	expired, err := client.IsExpired(request, &source.ExpireInfo{})
+	assert.Nil(t, err)
+	assert.False(t, expired)
+}
@@ -0,0 +1,296 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ossprotocol
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/google/uuid"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/source/clients/credentialprovider"
+)
+
+const (
+	// ecsMetadataURL is the ram role credentials url of ecs instance metadata.
+	ecsMetadataURL = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+
+	// stsEndpoint is the endpoint of alibaba cloud sts.
+	stsEndpoint = "https://sts.aliyuncs.com/"
+
+	// credentialsRefreshWindow refreshes credentials before they expire.
+	credentialsRefreshWindow = 5 * time.Minute
+
+	// credentialsRequestTimeout is the timeout of requesting credentials.
+	credentialsRequestTimeout = 10 * time.Second
+)
+
+// Environment variables of alibaba cloud credentials.
+const (
+	envAccessKeyID     = "OSS_ACCESS_KEY_ID"
+	envAccessKeySecret = "OSS_ACCESS_KEY_SECRET"
+	envSessionToken    = "OSS_SESSION_TOKEN"
+)
+
+// ossCredentials is an implementation of the interface of oss.Credentials.
+type ossCredentials struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	AccessKeySecret string `json:"AccessKeySecret"`
+	SecurityToken   string `json:"SecurityToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+func (c *ossCredentials) GetAccessKeyID() string     { return c.AccessKeyID }
+func (c *ossCredentials) GetAccessKeySecret() string { return c.AccessKeySecret }
+func (c *ossCredentials) GetSecurityToken() string   { return c.SecurityToken }
+
+// staticCredentialsProvider returns the same credentials.
+type staticCredentialsProvider struct {
+	credentials *ossCredentials
+}
+
+func (p *staticCredentialsProvider) GetCredentials() oss.Credentials {
+	return p.credentials
+}
+
+// envCredentialsProvider reads credentials from environment variables.
+type envCredentialsProvider struct{}
+
+func (p *envCredentialsProvider) GetCredentials() oss.Credentials {
+	return envCredentials()
+}
+
+func envCredentials() *ossCredentials {
+	return &ossCredentials{
+		AccessKeyID:     os.Getenv(envAccessKeyID),
+		AccessKeySecret: os.Getenv(envAccessKeySecret),
+		SecurityToken:   os.Getenv(envSessionToken),
+	}
+}
+
+// refreshingCredentialsProvider caches the temporary credentials and refreshes them before they expire.
+type refreshingCredentialsProvider struct {
+	mu          sync.Mutex
+	credentials *ossCredentials
+	expiration  time.Time
+	fetch       func(ctx context.Context) (*ossCredentials, error)
+}
+
+func newRefreshingCredentialsProvider(fetch func(ctx context.Context) (*ossCredentials, error)) *refreshingCredentialsProvider {
+	return &refreshingCredentialsProvider{
+		credentials: &ossCredentials{},
+		fetch:       fetch,
+	}
+}
+
+// GetCredentials returns the cached credentials when refreshing fails, the oss request will fail with them.
+func (p *refreshingCredentialsProvider) GetCredentials() oss.Credentials {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Now().Add(credentialsRefreshWindow).Before(p.expiration) {
+		return p.credentials
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialsRequestTimeout)
+	defer cancel()
+
+	credentials, err := p.fetch(ctx)
+	if err != nil {
+		logger.Errorf("refresh oss credentials error: %s", err)
+		return p.credentials
+	}
+
+	expiration, err := time.Parse(time.RFC3339, credentials.Expiration)
+	if err != nil {
+		logger.Errorf("parse oss credentials expiration %q error: %s", credentials.Expiration, err)
+		expiration = time.Now().Add(credentialsRefreshWindow)
+	}
+
+	p.credentials = credentials
+	p.expiration = expiration
+	return p.credentials
+}
+
+// newCredentialsProvider returns the credentials provider of option, the default credential chain
+// uses environment variables when they are set, otherwise the ecs ram role.
+func newCredentialsProvider(opt *credentialprovider.Option) oss.CredentialsProvider {
+	switch opt.Provider {
+	case credentialprovider.ProviderStatic:
+		return &staticCredentialsProvider{
+			credentials: &ossCredentials{
+				AccessKeyID:     opt.AccessKeyID,
+				AccessKeySecret: opt.AccessKeySecret,
+				SecurityToken:   opt.SessionToken,
+			},
+		}
+	case credentialprovider.ProviderEnv:
+		return &envCredentialsProvider{}
+	case credentialprovider.ProviderInstance:
+		return newRefreshingCredentialsProvider(fetchECSCredentials)
+	case credentialprovider.ProviderAssumeRole:
+		return newRefreshingCredentialsProvider(func(ctx context.Context) (*ossCredentials, error) {
+			base := &ossCredentials{AccessKeyID: opt.AccessKeyID, AccessKeySecret: opt.AccessKeySecret, SecurityToken: opt.SessionToken}
+			if !opt.HasAccessKey() {
+				base = envCredentials()
+			}
+
+			return assumeRole(ctx, base, opt)
+		})
+	default:
+		if os.Getenv(envAccessKeyID) != "" {
+			return &envCredentialsProvider{}
+		}
+
+		return newRefreshingCredentialsProvider(fetchECSCredentials)
+	}
+}
+
+// fetchECSCredentials fetches the ram role credentials from ecs instance metadata.
+func fetchECSCredentials(ctx context.Context) (*ossCredentials, error) {
+	role, err := getURL(ctx, ecsMetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("get ecs ram role: %w", err)
+	}
+
+	role = strings.TrimSpace(role)
+	if role == "" {
+		return nil, errors.New("ecs instance has no ram role")
+	}
+
+	data, err := getURL(ctx, ecsMetadataURL+role)
+	if err != nil {
+		return nil, fmt.Errorf("get ecs ram role %s credentials: %w", role, err)
+	}
+
+	credentials := &ossCredentials{}
+	if err := json.Unmarshal([]byte(data), credentials); err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
+
+// assumeRole requests sts AssumeRole api with the base credentials.
+func assumeRole(ctx context.Context, base *ossCredentials, opt *credentialprovider.Option) (*ossCredentials, error) {
+	params := map[string]string{
+		"Action":           "AssumeRole",
+		"Format":           "JSON",
+		"Version":          "2015-04-01",
+		"AccessKeyId":      base.AccessKeyID,
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   uuid.NewString(),
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		"RoleArn":          opt.RoleARN,
+		"RoleSessionName":  opt.RoleSessionName,
+		"DurationSeconds":  strconv.FormatInt(int64(opt.Duration.Seconds()), 10),
+	}
+	if base.SecurityToken != "" {
+		params["SecurityToken"] = base.SecurityToken
+	}
+	if opt.ExternalID != "" {
+		params["ExternalId"] = opt.ExternalID
+	}
+
+	query := canonicalizedQuery(params)
+	mac := hmac.New(sha1.New, []byte(base.AccessKeySecret+"&"))
+	mac.Write([]byte(http.MethodGet + "&" + percentEncode("/") + "&" + percentEncode(query)))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	data, err := getURL(ctx, stsEndpoint+"?"+query+"&Signature="+percentEncode(signature))
+	if err != nil {
+		return nil, fmt.Errorf("assume role %s: %w", opt.RoleARN, err)
+	}
+
+	resp := &struct {
+		Credentials *ossCredentials `json:"Credentials"`
+	}{}
+	if err := json.Unmarshal([]byte(data), resp); err != nil {
+		return nil, err
+	}
+
+	if resp.Credentials == nil {
+		return nil, fmt.Errorf("assume role %s: empty credentials", opt.RoleARN)
+	}
+
+	return resp.Credentials, nil
+}
+
+// canonicalizedQuery returns the sorted and encoded query of rpc signature.
+func canonicalizedQuery(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, percentEncode(k)+"="+percentEncode(params[k]))
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// percentEncode encodes string as RFC 3986 required by rpc signature.
+func percentEncode(s string) string {
+	s = url.QueryEscape(s)
+	s = strings.ReplaceAll(s, "+", "%20")
+	s = strings.ReplaceAll(s, "*", "%2A")
+	return strings.ReplaceAll(s, "%7E", "~")
+}
+
+func getURL(ctx context.Context, rawURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(data))
+	}
+
+	return string(data), nil
+}
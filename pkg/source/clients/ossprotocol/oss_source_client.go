@@ -30,8 +30,10 @@ import (
 
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/go-http-utils/headers"
+	"gopkg.in/yaml.v3"
 
 	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/source/clients/credentialprovider"
 	pkgstrings "d7y.io/dragonfly/v2/pkg/strings"
 )
 
@@ -44,14 +46,35 @@ const (
 	securityToken   = "securityToken"
 )
 
-var _ source.ResourceClient = (*ossSourceClient)(nil)
+var (
+	_ source.ResourceClient    = (*ossSourceClient)(nil)
+	_ source.ResourcePresigner = (*ossSourceClient)(nil)
+)
 
 func init() {
 	source.RegisterBuilder(OSSClient, source.NewPlainResourceClientBuilder(Builder))
 }
 
+// ossSourceOption is the option of oss source client, the oss headers in request take precedence over it.
+type ossSourceOption struct {
+	// Endpoint is the default endpoint of oss.
+	Endpoint string `yaml:"endpoint"`
+
+	// Credential is the credential provider used when the request has no access key.
+	Credential credentialprovider.Option `yaml:"credential"`
+}
+
 func Builder(optionYaml []byte) (source.ResourceClient, source.RequestAdapter, []source.Hook, error) {
-	return NewOSSSourceClient(), adaptor, nil, nil
+	opt := &ossSourceOption{}
+	if err := yaml.Unmarshal(optionYaml, opt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := opt.Credential.Validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return NewOSSSourceClient(WithEndpoint(opt.Endpoint), WithCredentialsProvider(newCredentialsProvider(&opt.Credential))), adaptor, nil, nil
 }
 
 func adaptor(request *source.Request) *source.Request {
@@ -81,11 +104,28 @@ func newOSSSourceClient(opts ...OSSSourceClientOption) source.ResourceClient {
 
 type OSSSourceClientOption func(p *ossSourceClient)
 
+// WithEndpoint sets the default endpoint of oss.
+func WithEndpoint(endpoint string) OSSSourceClientOption {
+	return func(p *ossSourceClient) {
+		p.endpoint = endpoint
+	}
+}
+
+// WithCredentialsProvider sets the credentials provider used when the request has no access key.
+func WithCredentialsProvider(provider oss.CredentialsProvider) OSSSourceClientOption {
+	return func(p *ossSourceClient) {
+		p.credentialsProvider = provider
+	}
+}
+
 // ossSourceClient is an implementation of the interface of source.ResourceClient.
 type ossSourceClient struct {
 	// endpoint_accessKeyID_accessKeySecret -> ossClient
 	clientMap sync.Map
 	accessMap sync.Map
+
+	endpoint            string
+	credentialsProvider oss.CredentialsProvider
 }
 
 func (osc *ossSourceClient) GetContentLength(request *source.Request) (int64, error) {
@@ -316,11 +356,17 @@ func (osc *ossSourceClient) isDirectory(bucket *oss.Bucket, request *source.Requ
 
 func (osc *ossSourceClient) getClient(header source.Header) (*oss.Client, error) {
 	endpoint := header.Get(endpoint)
+	if pkgstrings.IsBlank(endpoint) {
+		endpoint = osc.endpoint
+	}
 	if pkgstrings.IsBlank(endpoint) {
 		return nil, errors.New("endpoint is empty")
 	}
 	accessKeyID := header.Get(accessKeyID)
 	if pkgstrings.IsBlank(accessKeyID) {
+		if osc.credentialsProvider != nil {
+			return osc.getProviderClient(endpoint)
+		}
 		return nil, errors.New("accessKeyID is empty")
 	}
 	accessKeySecret := header.Get(accessKeySecret)
@@ -345,6 +391,37 @@ func (osc *ossSourceClient) getClient(header source.Header) (*oss.Client, error)
 	return actual.(*oss.Client), nil
 }
 
+// getProviderClient returns the client of endpoint with credentials provider.
+func (osc *ossSourceClient) getProviderClient(endpoint string) (*oss.Client, error) {
+	if client, ok := osc.clientMap.Load(endpoint); ok {
+		return client.(*oss.Client), nil
+	}
+
+	client, err := oss.New(endpoint, "", "", oss.SetCredentialsProvider(osc.credentialsProvider))
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := osc.clientMap.LoadOrStore(endpoint, client)
+	return actual.(*oss.Client), nil
+}
+
+// Presign generates signed url of object.
+func (osc *ossSourceClient) Presign(request *source.Request, expire time.Duration) (*url.URL, error) {
+	client, err := osc.getClient(request.Header)
+	if err != nil {
+		return nil, fmt.Errorf("get oss client: %w", err)
+	}
+	bucket, err := client.Bucket(request.URL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("get oss bucket %s: %w", request.URL.Host, err)
+	}
+	signed, err := bucket.SignURL(request.URL.Path, oss.HTTPGet, int64(expire.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("presign oss object %s: %w", request.URL.Path, err)
+	}
+	return url.Parse(signed)
+}
+
 func buildClientKey(endpoint, accessKeyID, accessKeySecret string) string {
 	return fmt.Sprintf("%s_%s_%s", endpoint, accessKeyID, accessKeySecret)
 }
@@ -0,0 +1,61 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3protocol
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	"d7y.io/dragonfly/v2/pkg/source/clients/credentialprovider"
+)
+
+// newCredentials returns the credentials of provider, nil means the default credential chain of session.
+func newCredentials(sess *session.Session, opt *credentialprovider.Option) (*credentials.Credentials, error) {
+	switch opt.Provider {
+	case credentialprovider.ProviderStatic:
+		return credentials.NewStaticCredentials(opt.AccessKeyID, opt.AccessKeySecret, opt.SessionToken), nil
+	case credentialprovider.ProviderEnv:
+		return credentials.NewEnvCredentials(), nil
+	case credentialprovider.ProviderInstance:
+		return ec2rolecreds.NewCredentialsWithClient(ec2metadata.New(sess)), nil
+	case credentialprovider.ProviderAssumeRole:
+		// assume role with access key in option, otherwise the default credential chain
+		baseSess := sess
+		if opt.HasAccessKey() {
+			var err error
+			baseSess, err = session.NewSession(sess.Config.Copy().WithCredentials(
+				credentials.NewStaticCredentials(opt.AccessKeyID, opt.AccessKeySecret, opt.SessionToken)))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return stscreds.NewCredentials(baseSess, opt.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = opt.RoleSessionName
+			p.Duration = opt.Duration
+			if opt.ExternalID != "" {
+				p.ExternalID = aws.String(opt.ExternalID)
+			}
+		}), nil
+	default:
+		return nil, nil
+	}
+}
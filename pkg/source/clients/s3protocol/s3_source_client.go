@@ -21,15 +21,19 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/go-http-utils/headers"
+	"gopkg.in/yaml.v3"
 
 	"d7y.io/dragonfly/v2/pkg/source"
+	"d7y.io/dragonfly/v2/pkg/source/clients/credentialprovider"
 )
 
 const S3Scheme = "s3"
@@ -47,21 +51,81 @@ const (
 	sessionToken = "awsSessionToken"
 
 	forcePathStyle = "awsS3ForcePathStyle"
+
+	// partsCountHeader is the parts count of multipart object.
+	partsCountHeader = "X-Amz-Mp-Parts-Count"
 )
 
-var _ source.ResourceClient = (*s3SourceClient)(nil)
+var (
+	_ source.ResourceClient         = (*s3SourceClient)(nil)
+	_ source.ResourceMetadataGetter = (*s3SourceClient)(nil)
+	_ source.ResourcePresigner      = (*s3SourceClient)(nil)
+)
 
 func init() {
 	source.RegisterBuilder(S3Scheme, source.NewPlainResourceClientBuilder(Builder))
 }
 
+// s3SourceOption is the option of s3 source client besides the transport option,
+// the aws headers in request take precedence over it.
+type s3SourceOption struct {
+	// Region is the default region of s3.
+	Region string `yaml:"region"`
+
+	// Endpoint is the default endpoint of s3 compatible storage.
+	Endpoint string `yaml:"endpoint"`
+
+	// ForcePathStyle uses path style addressing by default.
+	ForcePathStyle bool `yaml:"forcePathStyle"`
+
+	// Credential is the credential provider used when the request has no access key.
+	Credential credentialprovider.Option `yaml:"credential"`
+}
+
 func Builder(optionYaml []byte) (source.ResourceClient, source.RequestAdapter, []source.Hook, error) {
-	s3Client := &s3SourceClient{}
+	opt := &s3SourceOption{}
+	if err := yaml.Unmarshal(optionYaml, opt); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err := opt.Credential.Validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	httpClient, err := source.ParseToHTTPClient(optionYaml)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cfg := aws.NewConfig().WithHTTPClient(httpClient).WithS3ForcePathStyle(opt.ForcePathStyle)
+	if opt.Region != "" {
+		cfg = cfg.WithRegion(opt.Region)
+	}
+	if opt.Endpoint != "" {
+		cfg = cfg.WithEndpoint(opt.Endpoint)
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("new aws session failed: %s", err)
+	}
+
+	creds, err := newCredentials(sess, &opt.Credential)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("new aws credentials failed: %s", err)
+	}
+	if creds != nil {
+		sess.Config.Credentials = creds
+	}
+
+	s3Client := &s3SourceClient{session: sess}
 	return s3Client, s3Client.adaptor, nil, nil
 }
 
 // s3SourceClient is an implementation of the interface of source.ResourceClient.
 type s3SourceClient struct {
+	// session is shared by requests without aws headers.
+	session *session.Session
 }
 
 func (s *s3SourceClient) adaptor(request *source.Request) *source.Request {
@@ -74,22 +138,31 @@ func (s *s3SourceClient) adaptor(request *source.Request) *source.Request {
 }
 
 func (s *s3SourceClient) newAWSS3Client(request *source.Request) (*s3.S3, error) {
-	cfg := aws.NewConfig().WithCredentials(credentials.NewStaticCredentials(
-		request.Header.Get(accessKeyID), request.Header.Get(secretAccessKey), request.Header.Get(sessionToken)))
-	session, err := session.NewSession(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("new aws session failed: %s", err)
+	sess := s.session
+	if sess == nil {
+		var err error
+		if sess, err = session.NewSession(); err != nil {
+			return nil, fmt.Errorf("new aws session failed: %s", err)
+		}
 	}
 
-	opts := []*aws.Config{cfg.WithEndpoint(request.Header.Get(endpoint))}
+	// aws headers in request take precedence over the option
+	cfg := aws.NewConfig()
+	if id := request.Header.Get(accessKeyID); id != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(
+			id, request.Header.Get(secretAccessKey), request.Header.Get(sessionToken)))
+	}
+	if e := request.Header.Get(endpoint); e != "" {
+		cfg = cfg.WithEndpoint(e)
+	}
 	if r := request.Header.Get(region); r != "" {
-		opts = append(opts, cfg.WithRegion(r))
+		cfg = cfg.WithRegion(r)
 	}
 	if pathStyle := request.Header.Get(forcePathStyle); strings.ToLower(pathStyle) == "true" {
-		opts = append(opts, cfg.WithS3ForcePathStyle(true))
+		cfg = cfg.WithS3ForcePathStyle(true)
 	}
 
-	return s3.New(session, opts...), nil
+	return s3.New(sess, cfg), nil
 }
 
 // GetContentLength get length of resource content
@@ -122,7 +195,91 @@ func (s *s3SourceClient) IsSupportRange(request *source.Request) (bool, error) {
 // return false and non-nil err to prevent the source from exploding if
 // fails to get the result, it is considered that the source has not expired
 func (s *s3SourceClient) IsExpired(request *source.Request, info *source.ExpireInfo) (bool, error) {
-	return false, fmt.Errorf("not implemented") // TODO: Implement
+	client, err := s.newAWSS3Client(request)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.HeadObjectWithContext(request.Context(), &s3.HeadObjectInput{
+		Bucket: aws.String(request.URL.Host),
+		Key:    aws.String(request.URL.Path),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var lastModified string
+	if resp.LastModified != nil {
+		lastModified = resp.LastModified.UTC().Format(source.LastModifiedLayout)
+	}
+	return !(aws.StringValue(resp.ETag) == info.ETag || lastModified == info.LastModified), nil
+}
+
+// GetMetadata gets metadata of object for concurrent range reads, the total length is read
+// from the content range of the first byte, it is correct for both simple and multipart objects.
+func (s *s3SourceClient) GetMetadata(request *source.Request) (*source.Metadata, error) {
+	client, err := s.newAWSS3Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.GetObjectWithContext(request.Context(), &s3.GetObjectInput{
+		Bucket: aws.String(request.URL.Host),
+		Key:    aws.String(request.URL.Path),
+		Range:  aws.String("bytes=0-0"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	totalContentLength := aws.Int64Value(resp.ContentLength)
+	if total, ok := parseContentRangeTotal(aws.StringValue(resp.ContentRange)); ok {
+		totalContentLength = total
+	}
+
+	hdr := source.Header{}
+	if resp.ETag != nil {
+		hdr.Set(headers.ETag, *resp.ETag)
+
+		// the etag of multipart object is not the md5 of content, but ends with the parts count
+		if parts, ok := parseMultipartETag(*resp.ETag); ok {
+			hdr.Set(partsCountHeader, strconv.FormatInt(parts, 10))
+		}
+	}
+	if resp.LastModified != nil {
+		hdr.Set(headers.LastModified, resp.LastModified.UTC().Format(source.LastModifiedLayout))
+	}
+
+	return &source.Metadata{
+		Header:             hdr,
+		Status:             http.StatusText(http.StatusOK),
+		StatusCode:         http.StatusOK,
+		SupportRange:       true,
+		TotalContentLength: totalContentLength,
+		Validate: func() error {
+			return nil
+		},
+		Temporary: true,
+	}, nil
+}
+
+// Presign generates signed url of object.
+func (s *s3SourceClient) Presign(request *source.Request, expire time.Duration) (*url.URL, error) {
+	client, err := s.newAWSS3Client(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, _ := client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(request.URL.Host),
+		Key:    aws.String(request.URL.Path),
+	})
+	signed, err := req.Presign(expire)
+	if err != nil {
+		return nil, fmt.Errorf("presign s3 object %s%s: %w", request.URL.Host, request.URL.Path, err)
+	}
+
+	return url.Parse(signed)
 }
 
 // Download downloads from source
@@ -154,6 +311,14 @@ func (s *s3SourceClient) Download(request *source.Request) (*source.Response, er
 		hdr[headers.CacheControl] = []string{*resp.CacheControl}
 	}
 
+	if resp.ETag != nil {
+		hdr.Set(headers.ETag, *resp.ETag)
+	}
+
+	if resp.LastModified != nil {
+		hdr.Set(headers.LastModified, resp.LastModified.UTC().Format(source.LastModifiedLayout))
+	}
+
 	var contengLength int64 = -1
 	if resp.ContentLength != nil {
 		contengLength = *resp.ContentLength
@@ -263,6 +428,37 @@ func (s *s3SourceClient) isDirectory(client *s3.S3, request *source.Request) (bo
 	return false, nil
 }
 
+// parseContentRangeTotal parses the total length in content range like bytes 0-9/2443.
+func parseContentRangeTotal(contentRange string) (int64, bool) {
+	idx := strings.LastIndex(contentRange, "/")
+	if idx < 0 {
+		return 0, false
+	}
+
+	total, err := strconv.ParseInt(contentRange[idx+1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return total, true
+}
+
+// parseMultipartETag parses the parts count in etag of multipart object like "9b2cf535f27731c974343645a3985328-3".
+func parseMultipartETag(etag string) (int64, bool) {
+	etag = strings.Trim(etag, `"`)
+	idx := strings.LastIndex(etag, "-")
+	if idx < 0 {
+		return 0, false
+	}
+
+	parts, err := strconv.ParseInt(etag[idx+1:], 10, 64)
+	if err != nil || parts <= 0 {
+		return 0, false
+	}
+
+	return parts, true
+}
+
 func buildURLEntry(isDir bool, url *url.URL) source.URLEntry {
 	if isDir {
 		url.Path = addTrailingSlash(url.Path)
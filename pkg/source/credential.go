@@ -0,0 +1,100 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package source
+
+import (
+	"encoding/base64"
+	"net/http"
+	"path"
+)
+
+const (
+	// CredentialTypeBasic sends username and password with basic auth, like nexus user token.
+	CredentialTypeBasic = "basic"
+
+	// CredentialTypeBearer sends token with bearer auth, like artifactory access token.
+	CredentialTypeBearer = "bearer"
+
+	// CredentialTypeArtifactory sends token with artifactory api key header.
+	CredentialTypeArtifactory = "artifactory"
+
+	// ArtifactoryAPIKeyHeader is the api key header of artifactory.
+	ArtifactoryAPIKeyHeader = "X-JFrog-Art-Api"
+)
+
+// Credential is the auth info of source hosts, used when the request has no auth header.
+type Credential struct {
+	// Host is the host pattern of source, like example.com or *.example.com.
+	Host string `yaml:"host" mapstructure:"host"`
+
+	// Type is the credential type, default is bearer with token and basic with username.
+	Type string `yaml:"type" mapstructure:"type"`
+
+	// Username is the username of basic auth.
+	Username string `yaml:"username" mapstructure:"username"`
+
+	// Password is the password of basic auth.
+	Password string `yaml:"password" mapstructure:"password"`
+
+	// Token is the token of bearer auth or artifactory api key.
+	Token string `yaml:"token" mapstructure:"token"`
+}
+
+// Match returns whether the credential is used for the host.
+func (c *Credential) Match(host string) bool {
+	matched, err := path.Match(c.Host, host)
+	return err == nil && matched
+}
+
+// Apply sets auth header of the credential, the existing auth headers are kept.
+func (c *Credential) Apply(header http.Header) {
+	if header.Get("Authorization") != "" || header.Get(ArtifactoryAPIKeyHeader) != "" {
+		return
+	}
+
+	switch c.credentialType() {
+	case CredentialTypeBasic:
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password)))
+	case CredentialTypeBearer:
+		header.Set("Authorization", "Bearer "+c.Token)
+	case CredentialTypeArtifactory:
+		header.Set(ArtifactoryAPIKeyHeader, c.Token)
+	}
+}
+
+func (c *Credential) credentialType() string {
+	if c.Type != "" {
+		return c.Type
+	}
+
+	if c.Token != "" {
+		return CredentialTypeBearer
+	}
+
+	return CredentialTypeBasic
+}
+
+// FindCredential returns the first credential matched the host.
+func FindCredential(credentials []Credential, host string) (*Credential, bool) {
+	for i := range credentials {
+		if credentials[i].Match(host) {
+			return &credentials[i], true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,125 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package source
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredential_Apply(t *testing.T) {
+	tests := []struct {
+		name       string
+		credential Credential
+		header     http.Header
+		expect     func(t *testing.T, header http.Header)
+	}{
+		{
+			name:       "basic credential",
+			credential: Credential{Username: "foo", Password: "bar"},
+			header:     http.Header{},
+			expect: func(t *testing.T, header http.Header) {
+				assert := assert.New(t)
+				assert.Equal("Basic Zm9vOmJhcg==", header.Get("Authorization"))
+			},
+		},
+		{
+			name:       "bearer credential",
+			credential: Credential{Token: "foo"},
+			header:     http.Header{},
+			expect: func(t *testing.T, header http.Header) {
+				assert := assert.New(t)
+				assert.Equal("Bearer foo", header.Get("Authorization"))
+			},
+		},
+		{
+			name:       "artifactory credential",
+			credential: Credential{Type: CredentialTypeArtifactory, Token: "foo"},
+			header:     http.Header{},
+			expect: func(t *testing.T, header http.Header) {
+				assert := assert.New(t)
+				assert.Equal("foo", header.Get(ArtifactoryAPIKeyHeader))
+				assert.Empty(header.Get("Authorization"))
+			},
+		},
+		{
+			name:       "keep existing auth header",
+			credential: Credential{Token: "foo"},
+			header:     http.Header{"Authorization": []string{"Basic bar"}},
+			expect: func(t *testing.T, header http.Header) {
+				assert := assert.New(t)
+				assert.Equal("Basic bar", header.Get("Authorization"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.credential.Apply(tc.header)
+			tc.expect(t, tc.header)
+		})
+	}
+}
+
+func TestFindCredential(t *testing.T) {
+	credentials := []Credential{
+		{Host: "nexus.example.com", Username: "foo"},
+		{Host: "*.jfrog.io", Token: "bar"},
+	}
+
+	tests := []struct {
+		name   string
+		host   string
+		found  bool
+		expect string
+	}{
+		{
+			name:   "exact host",
+			host:   "nexus.example.com",
+			found:  true,
+			expect: "nexus.example.com",
+		},
+		{
+			name:   "wildcard host",
+			host:   "foo.jfrog.io",
+			found:  true,
+			expect: "*.jfrog.io",
+		},
+		{
+			name:  "host with port not matched",
+			host:  "nexus.example.com:8081",
+			found: false,
+		},
+		{
+			name:  "unknown host",
+			host:  "example.com",
+			found: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			credential, found := FindCredential(credentials, tc.host)
+			assert.Equal(t, tc.found, found)
+			if tc.found {
+				assert.Equal(t, tc.expect, credential.Host)
+			}
+		})
+	}
+}
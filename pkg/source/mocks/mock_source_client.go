@@ -5,7 +5,9 @@
 package mocks
 
 import (
+	url "net/url"
 	reflect "reflect"
+	time "time"
 
 	source "d7y.io/dragonfly/v2/pkg/source"
 	gomock "github.com/golang/mock/gomock"
@@ -185,6 +187,44 @@ func (mr *MockResourceListerMockRecorder) List(request interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockResourceLister)(nil).List), request)
 }
 
+// MockResourcePresigner is a mock of ResourcePresigner interface.
+type MockResourcePresigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourcePresignerMockRecorder
+}
+
+// MockResourcePresignerMockRecorder is the mock recorder for MockResourcePresigner.
+type MockResourcePresignerMockRecorder struct {
+	mock *MockResourcePresigner
+}
+
+// NewMockResourcePresigner creates a new mock instance.
+func NewMockResourcePresigner(ctrl *gomock.Controller) *MockResourcePresigner {
+	mock := &MockResourcePresigner{ctrl: ctrl}
+	mock.recorder = &MockResourcePresignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourcePresigner) EXPECT() *MockResourcePresignerMockRecorder {
+	return m.recorder
+}
+
+// Presign mocks base method.
+func (m *MockResourcePresigner) Presign(request *source.Request, expire time.Duration) (*url.URL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Presign", request, expire)
+	ret0, _ := ret[0].(*url.URL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Presign indicates an expected call of Presign.
+func (mr *MockResourcePresignerMockRecorder) Presign(request, expire interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Presign", reflect.TypeOf((*MockResourcePresigner)(nil).Presign), request, expire)
+}
+
 // MockClientManager is a mock of ClientManager interface.
 type MockClientManager struct {
 	ctrl     *gomock.Controller
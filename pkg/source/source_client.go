@@ -51,6 +51,9 @@ var (
 
 	// ErrClientNotSupportGetMetadata represents the source client not support get metadata
 	ErrClientNotSupportGetMetadata = errors.New("source client not support get metadata")
+
+	// ErrClientNotSupportPresign represents the source client not support presign
+	ErrClientNotSupportPresign = errors.New("source client not support presign")
 )
 
 // UnexpectedStatusCodeError is returned when a source responds with neither an error
@@ -152,6 +155,12 @@ type ResourceLister interface {
 	List(request *Request) (urls []URLEntry, err error)
 }
 
+// ResourcePresigner defines the interface to generate signed url of resource,
+// the signed url can be downloaded without credentials before it expires.
+type ResourcePresigner interface {
+	Presign(request *Request, expire time.Duration) (*url.URL, error)
+}
+
 type ClientManager interface {
 	// Register registers a source client with scheme
 	Register(scheme string, resourceClient ResourceClient, adapter RequestAdapter, hook ...Hook) error
@@ -408,3 +417,21 @@ func GetMetadata(request *Request) (*Metadata, error) {
 	}
 	return getter.GetMetadata(request)
 }
+
+func Presign(request *Request, expire time.Duration) (*url.URL, error) {
+	client, ok := _defaultManager.GetClient(request.URL.Scheme)
+	if !ok {
+		return nil, fmt.Errorf("scheme %s: %w", request.URL.Scheme, ErrNoClientFound)
+	}
+	// invoke in-tree plugin adapter
+	if wrap, ok := client.(*clientWrapper); ok {
+		if rc, ok := wrap.rc.(ResourcePresigner); ok {
+			return rc.Presign(wrap.adapter(request), expire)
+		}
+	}
+	presigner, ok := client.(ResourcePresigner)
+	if !ok {
+		return nil, fmt.Errorf("scheme %s: %w", request.URL.Scheme, ErrClientNotSupportPresign)
+	}
+	return presigner.Presign(request, expire)
+}
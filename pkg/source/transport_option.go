@@ -30,6 +30,9 @@ import (
 
 var ProxyEnv = "D7Y_SOURCE_PROXY"
 
+// DefaultMaxIdleConnsPerHost is the default number of idle connections kept for every origin.
+const DefaultMaxIdleConnsPerHost = 32
+
 type transportOption struct {
 	Proxy                 string        `yaml:"proxy"`
 	DialTimeout           time.Duration `yaml:"dialTimeout"`
@@ -40,6 +43,9 @@ type transportOption struct {
 	TLSHandshakeTimeout   time.Duration `yaml:"tlsHandshakeTimeout"`
 	ExpectContinueTimeout time.Duration `yaml:"expectContinueTimeout"`
 	InsecureSkipVerify    bool          `yaml:"insecureSkipVerify"`
+	MaxIdleConnsPerHost   int           `yaml:"maxIdleConnsPerHost"`
+	MaxConnsPerHost       int           `yaml:"maxConnsPerHost"`
+	DisableHTTP2          bool          `yaml:"disableHTTP2"`
 }
 
 func UpdateTransportOption(transport *http.Transport, optionYaml []byte) error {
@@ -83,6 +89,15 @@ func UpdateTransportOption(transport *http.Transport, optionYaml []byte) error {
 	if opt.InsecureSkipVerify {
 		transport.TLSClientConfig.InsecureSkipVerify = opt.InsecureSkipVerify
 	}
+	if opt.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = opt.MaxIdleConnsPerHost
+	}
+	if opt.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = opt.MaxConnsPerHost
+	}
+	if opt.DisableHTTP2 {
+		transport.ForceAttemptHTTP2 = false
+	}
 	return nil
 }
 
@@ -104,6 +119,10 @@ func DefaultTransport() *http.Transport {
 			KeepAlive: 30 * time.Second,
 			DualStack: true,
 		}).DialContext,
+		// Custom dialer and tls config disable http2 by default, force it to reuse
+		// multiplexed connections to the same origin.
+		ForceAttemptHTTP2:     true,
+		MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		ResponseHeaderTimeout: 30 * time.Second,
 		ExpectContinueTimeout: 10 * time.Second,
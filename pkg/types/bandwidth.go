@@ -0,0 +1,107 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// TrafficClassDownload is the traffic of the pieces downloaded by the peer, including back-to-source.
+	TrafficClassDownload = "download"
+
+	// TrafficClassUpload is the traffic of the pieces uploaded to other peers.
+	TrafficClassUpload = "upload"
+
+	// TrafficClassBackToSource is the traffic of the pieces downloaded from the source.
+	TrafficClassBackToSource = "back_to_source"
+)
+
+// BandwidthPolicy is the rate limit of the traffic class in the time window,
+// the first active policy of the traffic class takes effect.
+type BandwidthPolicy struct {
+	// TrafficClass is the traffic limited by the policy.
+	TrafficClass string `json:"traffic_class" binding:"required,oneof=download upload back_to_source"`
+
+	// RateLimit is the rate limit in bytes per second, zero means no limit.
+	RateLimit uint64 `json:"rate_limit" binding:"omitempty"`
+
+	// Start is the start time of day of the window in format of 15:04, empty means 00:00.
+	Start string `json:"start,omitempty" binding:"omitempty,datetime=15:04"`
+
+	// End is the end time of day of the window in format of 15:04, empty means the end of the day,
+	// the window crosses midnight when the end is before the start.
+	End string `json:"end,omitempty" binding:"omitempty,datetime=15:04"`
+
+	// Weekdays are the days of the window, e.g. Mon, empty means every day.
+	Weekdays []string `json:"weekdays,omitempty" binding:"omitempty,dive,oneof=Sun Mon Tue Wed Thu Fri Sat"`
+
+	// Timezone is the IANA time zone of the window, empty means the local time zone of the peer.
+	Timezone string `json:"timezone,omitempty" binding:"omitempty,timezone"`
+}
+
+// Active returns whether the time is in the window of the policy,
+// the weekdays are matched by the day of the time.
+func (p BandwidthPolicy) Active(t time.Time) (bool, error) {
+	if p.Timezone != "" {
+		loc, err := time.LoadLocation(p.Timezone)
+		if err != nil {
+			return false, err
+		}
+		t = t.In(loc)
+	}
+
+	if len(p.Weekdays) > 0 && !slices.Contains(p.Weekdays, t.Weekday().String()[:3]) {
+		return false, nil
+	}
+
+	start, end := 0, 24*60
+	if p.Start != "" {
+		minutes, err := parseTimeOfDay(p.Start)
+		if err != nil {
+			return false, err
+		}
+		start = minutes
+	}
+
+	if p.End != "" {
+		minutes, err := parseTimeOfDay(p.End)
+		if err != nil {
+			return false, err
+		}
+		end = minutes
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return now >= start && now < end, nil
+	}
+
+	return now >= start || now < end, nil
+}
+
+// parseTimeOfDay parses the time of day in format of 15:04 to the minutes since midnight.
+func parseTimeOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %s: %w", s, err)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
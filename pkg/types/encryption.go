@@ -0,0 +1,32 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+const (
+	// PieceEncryptionDisable transfers the pieces between peers over plain http.
+	PieceEncryptionDisable = "disable"
+
+	// PieceEncryptionPrefer downloads the pieces from parents over tls and falls back to plain http
+	// when the tls handshake fails, and the parents still upload the pieces over plain http for
+	// the peers not upgraded yet.
+	PieceEncryptionPrefer = "prefer"
+
+	// PieceEncryptionRequire downloads the pieces from parents over tls, and the parents
+	// refuse to upload the pieces over plain http. The daemons without the certificates
+	// issued by the manager ca fail to start.
+	PieceEncryptionRequire = "require"
+)
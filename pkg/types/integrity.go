@@ -0,0 +1,39 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+const (
+	// UploadMerkleTreePath is the http path of getting the signed merkle tree of the task
+	// from the upload server of the peer.
+	UploadMerkleTreePath = "/merkle-trees"
+)
+
+// SignedMerkleTree is the merkle tree over the piece digests of the task,
+// which is signed by the seed peer downloading the task from the origin.
+type SignedMerkleTree struct {
+	// TaskID is the id of the task.
+	TaskID string `json:"task_id"`
+
+	// Leaves is the piece digests of the task in order of the piece number.
+	Leaves []string `json:"leaves"`
+
+	// Root is the hex encoded root hash of the merkle tree.
+	Root string `json:"root"`
+
+	// Signature is the base64 encoded ed25519 signature of the task id, leaf count and root.
+	Signature string `json:"signature"`
+}
@@ -0,0 +1,42 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+// The GNN model exported by the trainer scores the edges of the host graph built from the network
+// topology, the hosts are the nodes and the parent to child relations are the edges.
+const (
+	// GNNNodeFeaturesName is the input of node features, the shape is [node count, GNNNodeFeatureCount].
+	GNNNodeFeaturesName = "node_features"
+
+	// GNNEdgeIndexName is the input of edges, the shape is [2, edge count],
+	// the first row is the source nodes and the second row is the destination nodes.
+	GNNEdgeIndexName = "edge_index"
+
+	// GNNEdgeFeaturesName is the input of edge features, the shape is [edge count, GNNEdgeFeatureCount].
+	GNNEdgeFeaturesName = "edge_features"
+
+	// GNNScoresName is the output of edge scores, the shape is [edge count].
+	GNNScoresName = "edge_scores"
+
+	// GNNNodeFeatureCount is the count of node features, they are host type, free upload ratio,
+	// upload success ratio, cpu usage, memory usage and disk usage of host.
+	GNNNodeFeatureCount = 6
+
+	// GNNEdgeFeatureCount is the count of edge features, they are whether the edge is probed,
+	// average rtt of probes in seconds, idc affinity and location affinity of hosts.
+	GNNEdgeFeatureCount = 4
+)
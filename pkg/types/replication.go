@@ -0,0 +1,32 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+// Replication is the replication policy of the persistent cache task,
+// scheduler maintains the replicas of the task as the peers come and go.
+type Replication struct {
+	// Replicas is the desired count of the replicas.
+	Replicas int `json:"replicas"`
+
+	// SpreadBy is the topology label which the replicas are spread across, e.g. zone.
+	SpreadBy string `json:"spread_by,omitempty"`
+}
+
+// IsPersistent returns whether the task is a persistent cache task maintained by scheduler.
+func (r Replication) IsPersistent() bool {
+	return r.Replicas > 0
+}
@@ -0,0 +1,85 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+const (
+	// TopologyLabelRegion is the label of region.
+	TopologyLabelRegion = "region"
+
+	// TopologyLabelZone is the label of zone.
+	TopologyLabelZone = "zone"
+
+	// TopologyLabelRack is the label of rack.
+	TopologyLabelRack = "rack"
+
+	// TopologyLabelSwitch is the label of switch.
+	TopologyLabelSwitch = "switch"
+
+	// TopologyLabelHypervisor is the label of hypervisor.
+	TopologyLabelHypervisor = "hypervisor"
+)
+
+// Topology is the labels of failure domains where the host is located.
+type Topology struct {
+	// Region is the region of host.
+	Region string `mapstructure:"region" yaml:"region" csv:"region" json:"region,omitempty"`
+
+	// Zone is the availability zone of host.
+	Zone string `mapstructure:"zone" yaml:"zone" csv:"zone" json:"zone,omitempty"`
+
+	// Rack is the rack of host.
+	Rack string `mapstructure:"rack" yaml:"rack" csv:"rack" json:"rack,omitempty"`
+
+	// Switch is the top-of-rack switch of host.
+	Switch string `mapstructure:"switch" yaml:"switch" csv:"switch" json:"switch,omitempty"`
+
+	// Hypervisor is the physical host or hypervisor where the host is running.
+	Hypervisor string `mapstructure:"hypervisor" yaml:"hypervisor" csv:"hypervisor" json:"hypervisor,omitempty"`
+}
+
+// IsTopologyLabel returns whether the label is a topology label.
+func IsTopologyLabel(label string) bool {
+	switch label {
+	case TopologyLabelRegion, TopologyLabelZone, TopologyLabelRack, TopologyLabelSwitch, TopologyLabelHypervisor:
+		return true
+	default:
+		return false
+	}
+}
+
+// Levels returns the labels ordered from the largest failure domain to the smallest.
+func (t Topology) Levels() []string {
+	return []string{t.Region, t.Zone, t.Rack, t.Switch}
+}
+
+// Label returns the value of the topology label.
+func (t Topology) Label(label string) string {
+	switch label {
+	case TopologyLabelRegion:
+		return t.Region
+	case TopologyLabelZone:
+		return t.Zone
+	case TopologyLabelRack:
+		return t.Rack
+	case TopologyLabelSwitch:
+		return t.Switch
+	case TopologyLabelHypervisor:
+		return t.Hypervisor
+	default:
+		return ""
+	}
+}
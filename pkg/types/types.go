@@ -136,6 +136,21 @@ func ParseHostType(name string) HostType {
 	return HostTypeNormal
 }
 
+// QueuePriority is the priority of task in the scheduling queues,
+// the larger the value, the earlier the task is scheduled.
+type QueuePriority int32
+
+const (
+	// QueuePriorityBulk is the queue priority of bulk downloads, e.g. preheat.
+	QueuePriorityBulk QueuePriority = iota
+
+	// QueuePriorityNormal is the queue priority of normal downloads.
+	QueuePriorityNormal
+
+	// QueuePriorityUrgent is the queue priority of urgent downloads, e.g. incident rollouts.
+	QueuePriorityUrgent
+)
+
 // TaskTypeV1ToV2 converts task type from v1 to v2.
 func TaskTypeV1ToV2(typ commonv1.TaskType) commonv2.TaskType {
 	switch typ {
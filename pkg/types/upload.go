@@ -0,0 +1,41 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+const (
+	// UploadReservationPath is the http path of reserving an upload slot on the upload server of the peer.
+	UploadReservationPath = "/reservations"
+)
+
+// UploadReservationRequest is the request of the scheduler reserving an upload slot
+// on the parent before scheduling it to the child.
+type UploadReservationRequest struct {
+	// TaskID is the id of the task uploaded by the parent.
+	TaskID string `json:"task_id" binding:"required"`
+
+	// PeerID is the id of the child downloading from the parent.
+	PeerID string `json:"peer_id" binding:"required"`
+}
+
+// UploadReservationResponse is the response of reserving an upload slot.
+type UploadReservationResponse struct {
+	// Granted is whether the upload slot is reserved, it is denied when the parent is saturated.
+	Granted bool `json:"granted"`
+
+	// FreeSlots is the number of the free upload slots of the parent after the reservation.
+	FreeSlots int `json:"free_slots"`
+}
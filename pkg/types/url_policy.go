@@ -0,0 +1,69 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrURLDenied is returned when the url is denied by the url policy.
+var ErrURLDenied = errors.New("url is denied by policy")
+
+// URLPolicy is the policy of the origin urls downloaded in the cluster, the urls
+// matching any deny pattern are denied, and the urls matching no allow pattern
+// are denied when the allow patterns are not empty.
+type URLPolicy struct {
+	// Allow are the regexes of the allowed urls, empty means all urls are allowed.
+	Allow []string `json:"allow,omitempty" binding:"omitempty,dive,required"`
+
+	// Deny are the regexes of the denied urls, the deny patterns take precedence.
+	Deny []string `json:"deny,omitempty" binding:"omitempty,dive,required"`
+}
+
+// Check returns ErrURLDenied with the reason if the url is denied by the policy,
+// the url is denied as well when the pattern is invalid.
+func (p URLPolicy) Check(url string) error {
+	for _, pattern := range p.Deny {
+		matched, err := regexp.MatchString(pattern, url)
+		if err != nil {
+			return fmt.Errorf("%w: invalid deny pattern %s: %s", ErrURLDenied, pattern, err)
+		}
+
+		if matched {
+			return fmt.Errorf("%w: %s matches deny pattern %s", ErrURLDenied, url, pattern)
+		}
+	}
+
+	if len(p.Allow) == 0 {
+		return nil
+	}
+
+	for _, pattern := range p.Allow {
+		matched, err := regexp.MatchString(pattern, url)
+		if err != nil {
+			return fmt.Errorf("%w: invalid allow pattern %s: %s", ErrURLDenied, pattern, err)
+		}
+
+		if matched {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s matches no allow pattern", ErrURLDenied, url)
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"d7y.io/dragonfly/v2/cmd/dependency/base"
@@ -28,6 +29,7 @@ import (
 	"d7y.io/dragonfly/v2/pkg/rpc"
 	"d7y.io/dragonfly/v2/pkg/slices"
 	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/pkg/unit"
 )
 
 type Config struct {
@@ -67,9 +69,18 @@ type Config struct {
 	// Metrics configuration.
 	Metrics MetricsConfig `yaml:"metrics" mapstructure:"metrics"`
 
+	// Profiling configuration.
+	Profiling ProfilingConfig `yaml:"profiling" mapstructure:"profiling"`
+
+	// Event configuration.
+	Event EventConfig `yaml:"event" mapstructure:"event"`
+
 	// Security configuration.
 	Security SecurityConfig `yaml:"security" mapstructure:"security"`
 
+	// Auth configuration of grpc server.
+	Auth AuthConfig `yaml:"auth" mapstructure:"auth"`
+
 	// Network configuration.
 	Network NetworkConfig `yaml:"network" mapstructure:"network"`
 
@@ -110,12 +121,75 @@ type ServerConfig struct {
 
 	// Server storage data directory.
 	DataDir string `yaml:"dataDir" mapstructure:"dataDir"`
+
+	// RateLimit is the rate limit configuration of grpc server.
+	RateLimit RateLimitConfig `yaml:"rateLimit" mapstructure:"rateLimit"`
+}
+
+type RateLimitConfig struct {
+	// QPS is the qps of the methods not matched by method rate limits.
+	QPS float64 `yaml:"qps" mapstructure:"qps"`
+
+	// Burst is the burst of the methods not matched by method rate limits.
+	Burst int64 `yaml:"burst" mapstructure:"burst"`
+
+	// Methods is the rate limits of methods, the first matched method rate limit is used.
+	Methods []MethodRateLimitConfig `yaml:"methods" mapstructure:"methods"`
+}
+
+type MethodRateLimitConfig struct {
+	// Method is the full method name, like /scheduler.Scheduler/RegisterPeerTask.
+	Method string `yaml:"method" mapstructure:"method"`
+
+	// QPS is the qps of method.
+	QPS float64 `yaml:"qps" mapstructure:"qps"`
+
+	// Burst is the burst of method.
+	Burst int64 `yaml:"burst" mapstructure:"burst"`
+
+	// MaxInflight is the max concurrent executions of method, zero means unlimited.
+	MaxInflight int64 `yaml:"maxInflight" mapstructure:"maxInflight"`
 }
 
 type SchedulerConfig struct {
 	// Algorithm is scheduling algorithm used by the scheduler.
 	Algorithm string `yaml:"algorithm" mapstructure:"algorithm"`
 
+	// Plugin is the evaluator plugin configuration, it takes effect when algorithm is plugin.
+	Plugin PluginConfig `yaml:"plugin" mapstructure:"plugin"`
+
+	// Topology is the topology affinity configuration, the topology affinity is weighted
+	// by the evaluator weights of scheduler cluster config.
+	Topology TopologyConfig `yaml:"topology" mapstructure:"topology"`
+
+	// Inference is the model inference configuration, it takes effect when algorithm is ml,
+	// or when the feature flag ml_evaluator is rolled out to the scheduler of default algorithm.
+	Inference InferenceConfig `yaml:"inference" mapstructure:"inference"`
+
+	// ParentProbe is the health probe configuration of candidate parents.
+	ParentProbe ParentProbeConfig `yaml:"parentProbe" mapstructure:"parentProbe"`
+
+	// UploadReservation is the configuration of reserving upload slots on candidate parents.
+	UploadReservation UploadReservationConfig `yaml:"uploadReservation" mapstructure:"uploadReservation"`
+
+	// Reputation is the configuration of scoring the reputation of parents.
+	Reputation ReputationConfig `yaml:"reputation" mapstructure:"reputation"`
+
+	// PeerExchange is the peer exchange configuration of hot tasks.
+	PeerExchange PeerExchangeConfig `yaml:"peerExchange" mapstructure:"peerExchange"`
+
+	// SeedPeerElection is the configuration of electing temporary seed peers for hot tasks.
+	SeedPeerElection SeedPeerElectionConfig `yaml:"seedPeerElection" mapstructure:"seedPeerElection"`
+
+	// FlashCrowd is the configuration of detecting flash crowd of tasks.
+	FlashCrowd FlashCrowdConfig `yaml:"flashCrowd" mapstructure:"flashCrowd"`
+
+	// Federation is the configuration of looking up tasks in the sibling scheduler clusters.
+	Federation FederationConfig `yaml:"federation" mapstructure:"federation"`
+
+	// Replication is the configuration of maintaining the replicas of the persistent cache tasks.
+	Replication ReplicationConfig `yaml:"replication" mapstructure:"replication"`
+
 	// BackToSourceCount is single task allows the peer to back-to-source count.
 	BackToSourceCount int `yaml:"backToSourceCount" mapstructure:"backToSourceCount"`
 
@@ -132,6 +206,160 @@ type SchedulerConfig struct {
 	GC GCConfig `yaml:"gc" mapstructure:"gc"`
 }
 
+type PluginConfig struct {
+	// Weight is the weight of score supplied by the plugin implementing scorer,
+	// the rest is weighted by the default algorithm.
+	Weight float64 `yaml:"weight" mapstructure:"weight"`
+
+	// Options is passed to the init function of plugin.
+	Options map[string]string `yaml:"options" mapstructure:"options"`
+}
+
+type TopologyConfig struct {
+	// RegionWeight is the relative weight of parent in the same region.
+	RegionWeight float64 `yaml:"regionWeight" mapstructure:"regionWeight"`
+
+	// ZoneWeight is the relative weight of parent in the same zone.
+	ZoneWeight float64 `yaml:"zoneWeight" mapstructure:"zoneWeight"`
+
+	// RackWeight is the relative weight of parent in the same rack.
+	RackWeight float64 `yaml:"rackWeight" mapstructure:"rackWeight"`
+
+	// SwitchWeight is the relative weight of parent under the same switch.
+	SwitchWeight float64 `yaml:"switchWeight" mapstructure:"switchWeight"`
+}
+
+type InferenceConfig struct {
+	// Addr is the grpc address of inference server serving the trained GNN model,
+	// e.g. 127.0.0.1:8001.
+	Addr string `yaml:"addr" mapstructure:"addr"`
+
+	// LatencyBudget is the latency budget of inference, scheduler falls back
+	// to the default algorithm when the inference exceeds the budget.
+	LatencyBudget time.Duration `yaml:"latencyBudget" mapstructure:"latencyBudget"`
+
+	// RefreshModelInterval is the interval of loading the latest model version.
+	RefreshModelInterval time.Duration `yaml:"refreshModelInterval" mapstructure:"refreshModelInterval"`
+}
+
+type ParentProbeConfig struct {
+	// Enable probes the candidate parents asynchronously, and evicts the stale parents
+	// which are unreachable or no longer hold the pieces.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Interval is the interval of probing the same parent.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+
+	// Timeout is the timeout of probing a parent.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+
+	// Concurrency is the number of concurrent probes.
+	Concurrency int `yaml:"concurrency" mapstructure:"concurrency"`
+
+	// QueueLength is the length of probe queue, parents are not probed when the queue is full.
+	QueueLength int `yaml:"queueLength" mapstructure:"queueLength"`
+}
+
+type UploadReservationConfig struct {
+	// Enable reserves an upload slot on each candidate parent before scheduling it to the child,
+	// and the parents denying the reservations are skipped. The reservations are sent to the
+	// upload servers of the parents with the scheme and tls configuration of downloadTiny.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Timeout is the overall timeout of reserving the upload slots on the candidate parents,
+	// the parents are skipped when the reservations fail or time out.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+
+	// HMACSecret is the secret shared with the peers to authenticate the reservations.
+	HMACSecret string `yaml:"hmacSecret" mapstructure:"hmacSecret"`
+}
+
+type ReputationConfig struct {
+	// Enable scores the reputation of the parents by the piece failures and corruptions reported
+	// by the children, and blocklists the misbehaving parents from parent selection temporarily.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Threshold is the reputation score in (0, 1) below which the parent is blocklisted,
+	// the parent serving corrupted pieces is blocklisted regardless of the score.
+	Threshold float64 `yaml:"threshold" mapstructure:"threshold"`
+
+	// BlockDuration is the duration of blocklisting the parent.
+	BlockDuration time.Duration `yaml:"blockDuration" mapstructure:"blockDuration"`
+}
+
+type PeerExchangeConfig struct {
+	// Enable returns the sibling peers of the hot tasks to the peers listing them, and the peers
+	// gossip piece availability with the siblings directly.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// HotTaskPeerCount is the peer count of task regarded as hot task.
+	HotTaskPeerCount int `yaml:"hotTaskPeerCount" mapstructure:"hotTaskPeerCount"`
+
+	// SiblingLimit is the limit count of sibling peers returned to the peer.
+	SiblingLimit int `yaml:"siblingLimit" mapstructure:"siblingLimit"`
+}
+
+type SeedPeerElectionConfig struct {
+	// Enable promotes the well-provisioned regular peers to act as the temporary seed peers
+	// of the hot tasks when the dedicated seed peers are saturated, and demotes them
+	// when demand subsides.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Interval is the interval of electing temporary seed peers.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+
+	// HotTaskPeerCount is the peer count of task regarded as hot task.
+	HotTaskPeerCount int `yaml:"hotTaskPeerCount" mapstructure:"hotTaskPeerCount"`
+
+	// SeedPeerLimit is the limit count of temporary seed peers of a task.
+	SeedPeerLimit int `yaml:"seedPeerLimit" mapstructure:"seedPeerLimit"`
+}
+
+type FlashCrowdConfig struct {
+	// Enable detects sudden spikes in registrations for the same task, and schedules
+	// the peers of the task in tree-building mode with deeper fan-out limits.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Interval is the window of counting registrations of the task.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+
+	// MinRegisterCount is the minimum registrations in a window regarded as flash crowd.
+	MinRegisterCount int64 `yaml:"minRegisterCount" mapstructure:"minRegisterCount"`
+
+	// GrowthRate is the growth rate of registrations between two windows regarded as flash crowd.
+	GrowthRate float64 `yaml:"growthRate" mapstructure:"growthRate"`
+
+	// CandidateParentLimit is the limit count of candidate parents in flash crowd.
+	CandidateParentLimit int `yaml:"candidateParentLimit" mapstructure:"candidateParentLimit"`
+
+	// FilterParentLimit is the limit count of filtering parents in flash crowd.
+	FilterParentLimit int `yaml:"filterParentLimit" mapstructure:"filterParentLimit"`
+}
+
+type FederationConfig struct {
+	// Enable looks up the peers holding the task in the sibling scheduler clusters registered
+	// in the manager, and schedules them as the parents before falling back to origin.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Interval is the interval of refreshing the sibling schedulers from the manager.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+
+	// Timeout is the timeout of looking up the task in a sibling scheduler.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+type ReplicationConfig struct {
+	// Enable maintains the replica count of the persistent cache tasks as the peers come and go,
+	// the missing replicas are replicated to the hosts spread across the failure domains.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Interval is the interval of checking the replicas of the persistent cache tasks.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+
+	// Timeout is the timeout of replicating the task to a host.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
 type DatabaseConfig struct {
 	// Redis configuration.
 	Redis RedisConfig `yaml:"redis" mapstructure:"redis"`
@@ -140,6 +368,28 @@ type DatabaseConfig struct {
 type ResourceConfig struct {
 	// Task resource configuration.
 	Task TaskConfig `yaml:"task" mapstructure:"task"`
+
+	// SeedPeer resource configuration.
+	SeedPeer SeedPeerResourceConfig `yaml:"seedPeer" mapstructure:"seedPeer"`
+
+	// Snapshot resource configuration.
+	Snapshot SnapshotConfig `yaml:"snapshot" mapstructure:"snapshot"`
+}
+
+type SnapshotConfig struct {
+	// Enable snapshot of hosts, tasks and peers, the snapshot is restored
+	// when scheduler restarts, it requires redis.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Interval is the interval of taking snapshot.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+}
+
+type SeedPeerResourceConfig struct {
+	// TriggerLimit is the limit of concurrent seed peer back-to-source triggers,
+	// the waiting triggers are admitted by the queue priority of task,
+	// and zero means unlimited.
+	TriggerLimit int `yaml:"triggerLimit" mapstructure:"triggerLimit"`
 }
 
 type TaskConfig struct {
@@ -185,6 +435,23 @@ type GCConfig struct {
 	// HostTTL is time to live of host. If host announces message to scheduler,
 	// then HostTTl will be reset.
 	HostTTL time.Duration `yaml:"hostTTL" mapstructure:"hostTTL"`
+
+	// PeerEviction is eviction configuration of peers.
+	PeerEviction PeerEvictionConfig `yaml:"peerEviction" mapstructure:"peerEviction"`
+}
+
+type PeerEvictionConfig struct {
+	// Policy is the eviction policy of peers, supports lru, score and memory.
+	// If policy is empty, peers are reclaimed by ttl only.
+	Policy string `yaml:"policy" mapstructure:"policy"`
+
+	// PeerLimit is the limit count of peers, the exceeded peers
+	// will be evicted by lru or score policy.
+	PeerLimit int `yaml:"peerLimit" mapstructure:"peerLimit"`
+
+	// MemoryLimit is the limit of heap memory, peers will be evicted
+	// by memory policy when the heap memory exceeds the limit.
+	MemoryLimit unit.Bytes `yaml:"memoryLimit" mapstructure:"memoryLimit"`
 }
 
 type DynConfig struct {
@@ -209,6 +476,9 @@ type ManagerConfig struct {
 
 	// KeepAlive configuration.
 	KeepAlive KeepAliveConfig `yaml:"keepAlive" mapstructure:"keepAlive"`
+
+	// Auth is the credentials of manager.
+	Auth AuthConfig `yaml:"auth" mapstructure:"auth"`
 }
 
 type SeedPeerConfig struct {
@@ -277,6 +547,9 @@ type RedisConfig struct {
 
 	// NetworkTopologyDB is network topology database name.
 	NetworkTopologyDB int `yaml:"networkTopologyDB" mapstructure:"networkTopologyDB"`
+
+	// SnapshotDB is resource snapshot database name.
+	SnapshotDB int `yaml:"snapshotDB" mapstructure:"snapshotDB"`
 }
 
 type MetricsConfig struct {
@@ -290,11 +563,69 @@ type MetricsConfig struct {
 	EnableHost bool `yaml:"enableHost" mapstructure:"enableHost"`
 }
 
+type ProfilingConfig struct {
+	// Enable pushing cpu profiles continuously.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Addr is the address of the pyroscope compatible server, like: http://pyroscope:4040.
+	Addr string `yaml:"addr" mapstructure:"addr"`
+
+	// Interval is the duration of collecting a cpu profile before pushing.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+}
+
+type EventConfig struct {
+	// Enable publishing task lifecycle events.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// BufferSize is the size of the event buffer, events are dropped when the buffer is full.
+	BufferSize int `yaml:"bufferSize" mapstructure:"bufferSize"`
+
+	// Kafka is the configuration of exporting events to kafka.
+	Kafka KafkaEventConfig `yaml:"kafka" mapstructure:"kafka"`
+
+	// NATS is the configuration of exporting events to nats.
+	NATS NATSEventConfig `yaml:"nats" mapstructure:"nats"`
+}
+
+type KafkaEventConfig struct {
+	// Enable exporting events to kafka.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// Brokers is the address list of the kafka brokers.
+	Brokers []string `yaml:"brokers" mapstructure:"brokers"`
+
+	// Topic is the kafka topic of the events.
+	Topic string `yaml:"topic" mapstructure:"topic"`
+}
+
+type NATSEventConfig struct {
+	// Enable exporting events to nats.
+	Enable bool `yaml:"enable" mapstructure:"enable"`
+
+	// URL is the url of the nats server, like: nats://127.0.0.1:4222.
+	URL string `yaml:"url" mapstructure:"url"`
+
+	// Subject is the subject prefix of the events, the event type is appended to it.
+	Subject string `yaml:"subject" mapstructure:"subject"`
+}
+
 type SecurityConfig struct {
 	// AutoIssueCert indicates to issue client certificates for all grpc call
-	// if AutoIssueCert is false, any other option in Security will be ignored.
+	// if AutoIssueCert is false, any other option in Security will be ignored,
+	// except the certificate files.
 	AutoIssueCert bool `mapstructure:"autoIssueCert" yaml:"autoIssueCert"`
 
+	// CertFile is the certificate file for all grpc tls handshake when AutoIssueCert is false,
+	// the certificate files are reloaded when they are rotated.
+	CertFile string `mapstructure:"certFile" yaml:"certFile"`
+
+	// KeyFile is the private key file of CertFile.
+	KeyFile string `mapstructure:"keyFile" yaml:"keyFile"`
+
+	// CAFile is the CA bundle file verifying the peers of CertFile.
+	CAFile string `mapstructure:"caFile" yaml:"caFile"`
+
 	// CACert is the root CA certificate for all grpc tls handshake, it can be path or PEM format string.
 	CACert types.PEMContent `mapstructure:"caCert" yaml:"caCert"`
 
@@ -311,6 +642,14 @@ type SecurityConfig struct {
 	CertSpec CertSpec `mapstructure:"certSpec" yaml:"certSpec"`
 }
 
+type AuthConfig struct {
+	// Token is the bearer token, empty disables token authentication.
+	Token string `mapstructure:"token" yaml:"token"`
+
+	// HMACSecret is the secret of hmac signature, empty disables hmac authentication.
+	HMACSecret string `mapstructure:"hmacSecret" yaml:"hmacSecret"`
+}
+
 type CertSpec struct {
 	// DNSNames is a list of dns names be set on the certificate.
 	DNSNames []string `mapstructure:"dnsNames" yaml:"dnsNames"`
@@ -367,9 +706,79 @@ func New() *Config {
 			Port:          DefaultServerPort,
 			AdvertisePort: DefaultServerAdvertisePort,
 			Host:          fqdn.FQDNHostname,
+			RateLimit: RateLimitConfig{
+				QPS:   DefaultServerRateLimitQPS,
+				Burst: DefaultServerRateLimitBurst,
+				Methods: []MethodRateLimitConfig{
+					{
+						Method:      DefaultServerRegisterPeerTaskMethod,
+						QPS:         DefaultServerRegisterPeerTaskQPS,
+						Burst:       DefaultServerRegisterPeerTaskBurst,
+						MaxInflight: DefaultServerRegisterPeerTaskMaxInflight,
+					},
+				},
+			},
 		},
 		Scheduler: SchedulerConfig{
-			Algorithm:              DefaultSchedulerAlgorithm,
+			Algorithm: DefaultSchedulerAlgorithm,
+			Plugin: PluginConfig{
+				Weight: DefaultSchedulerPluginWeight,
+			},
+			Topology: TopologyConfig{
+				RegionWeight: DefaultSchedulerTopologyRegionWeight,
+				ZoneWeight:   DefaultSchedulerTopologyZoneWeight,
+				RackWeight:   DefaultSchedulerTopologyRackWeight,
+				SwitchWeight: DefaultSchedulerTopologySwitchWeight,
+			},
+			Inference: InferenceConfig{
+				LatencyBudget:        DefaultSchedulerInferenceLatencyBudget,
+				RefreshModelInterval: DefaultRefreshModelInterval,
+			},
+			ParentProbe: ParentProbeConfig{
+				Enable:      false,
+				Interval:    DefaultSchedulerParentProbeInterval,
+				Timeout:     DefaultSchedulerParentProbeTimeout,
+				Concurrency: DefaultSchedulerParentProbeConcurrency,
+				QueueLength: DefaultSchedulerParentProbeQueueLength,
+			},
+			UploadReservation: UploadReservationConfig{
+				Enable:  false,
+				Timeout: DefaultSchedulerUploadReservationTimeout,
+			},
+			Reputation: ReputationConfig{
+				Enable:        false,
+				Threshold:     DefaultSchedulerReputationThreshold,
+				BlockDuration: DefaultSchedulerReputationBlockDuration,
+			},
+			PeerExchange: PeerExchangeConfig{
+				Enable:           false,
+				HotTaskPeerCount: DefaultSchedulerPeerExchangeHotTaskPeerCount,
+				SiblingLimit:     DefaultSchedulerPeerExchangeSiblingLimit,
+			},
+			SeedPeerElection: SeedPeerElectionConfig{
+				Enable:           false,
+				Interval:         DefaultSchedulerSeedPeerElectionInterval,
+				HotTaskPeerCount: DefaultSchedulerSeedPeerElectionHotTaskPeerCount,
+				SeedPeerLimit:    DefaultSchedulerSeedPeerElectionSeedPeerLimit,
+			},
+			FlashCrowd: FlashCrowdConfig{
+				Enable:               false,
+				Interval:             DefaultSchedulerFlashCrowdInterval,
+				MinRegisterCount:     DefaultSchedulerFlashCrowdMinRegisterCount,
+				GrowthRate:           DefaultSchedulerFlashCrowdGrowthRate,
+				CandidateParentLimit: DefaultSchedulerFlashCrowdCandidateParentLimit,
+				FilterParentLimit:    DefaultSchedulerFlashCrowdFilterParentLimit,
+			},
+			Federation: FederationConfig{
+				Enable:   false,
+				Interval: DefaultSchedulerFederationInterval,
+				Timeout:  DefaultSchedulerFederationTimeout,
+			},
+			Replication: ReplicationConfig{
+				Enable:   false,
+				Interval: DefaultSchedulerReplicationInterval,
+				Timeout:  DefaultSchedulerReplicationTimeout,
+			},
 			BackToSourceCount:      DefaultSchedulerBackToSourceCount,
 			RetryBackToSourceLimit: DefaultSchedulerRetryBackToSourceLimit,
 			RetryLimit:             DefaultSchedulerRetryLimit,
@@ -381,6 +790,10 @@ func New() *Config {
 				TaskGCInterval:       DefaultSchedulerTaskGCInterval,
 				HostGCInterval:       DefaultSchedulerHostGCInterval,
 				HostTTL:              DefaultSchedulerHostTTL,
+				PeerEviction: PeerEvictionConfig{
+					PeerLimit:   DefaultSchedulerPeerEvictionPeerLimit,
+					MemoryLimit: DefaultSchedulerPeerEvictionMemoryLimit,
+				},
 			},
 		},
 		Database: DatabaseConfig{
@@ -388,6 +801,7 @@ func New() *Config {
 				BrokerDB:          DefaultRedisBrokerDB,
 				BackendDB:         DefaultRedisBackendDB,
 				NetworkTopologyDB: DefaultNetworkTopologyDB,
+				SnapshotDB:        DefaultSnapshotDB,
 			},
 		},
 		Resource: ResourceConfig{
@@ -400,6 +814,13 @@ func New() *Config {
 					},
 				},
 			},
+			SeedPeer: SeedPeerResourceConfig{
+				TriggerLimit: DefaultResourceSeedPeerTriggerLimit,
+			},
+			Snapshot: SnapshotConfig{
+				Enable:   false,
+				Interval: DefaultResourceSnapshotInterval,
+			},
 		},
 		DynConfig: DynConfig{
 			RefreshInterval: DefaultDynConfigRefreshInterval,
@@ -430,6 +851,22 @@ func New() *Config {
 			Addr:       DefaultMetricsAddr,
 			EnableHost: false,
 		},
+		Profiling: ProfilingConfig{
+			Enable:   false,
+			Interval: DefaultProfilingInterval,
+		},
+		Event: EventConfig{
+			Enable:     false,
+			BufferSize: DefaultEventBufferSize,
+			Kafka: KafkaEventConfig{
+				Enable: false,
+				Topic:  DefaultEventKafkaTopic,
+			},
+			NATS: NATSEventConfig{
+				Enable:  false,
+				Subject: DefaultEventNATSSubject,
+			},
+		},
 		Security: SecurityConfig{
 			AutoIssueCert: false,
 			TLSVerify:     true,
@@ -482,10 +919,163 @@ func (cfg *Config) Validate() error {
 		return errors.New("server requires parameter host")
 	}
 
+	if cfg.Server.RateLimit.QPS <= 0 {
+		return errors.New("rateLimit requires parameter qps")
+	}
+
+	if cfg.Server.RateLimit.Burst <= 0 {
+		return errors.New("rateLimit requires parameter burst")
+	}
+
+	for _, method := range cfg.Server.RateLimit.Methods {
+		if !strings.HasPrefix(method.Method, "/") {
+			return errors.New("rateLimit requires parameter method")
+		}
+
+		if method.QPS <= 0 {
+			return errors.New("rateLimit requires parameter method qps")
+		}
+
+		if method.Burst <= 0 {
+			return errors.New("rateLimit requires parameter method burst")
+		}
+
+		if method.MaxInflight < 0 {
+			return errors.New("rateLimit requires parameter method maxInflight")
+		}
+	}
+
 	if cfg.Scheduler.Algorithm == "" {
 		return errors.New("scheduler requires parameter algorithm")
 	}
 
+	if cfg.Scheduler.Algorithm == SchedulerAlgorithmML {
+		if cfg.Scheduler.Inference.Addr == "" {
+			return errors.New("inference requires parameter addr")
+		}
+
+		if cfg.Scheduler.Inference.LatencyBudget <= 0 {
+			return errors.New("inference requires parameter latencyBudget")
+		}
+
+		if cfg.Scheduler.Inference.RefreshModelInterval <= 0 {
+			return errors.New("inference requires parameter refreshModelInterval")
+		}
+	}
+
+	if cfg.Scheduler.Plugin.Weight < 0 || cfg.Scheduler.Plugin.Weight > 1 {
+		return errors.New("plugin weight must be in [0, 1]")
+	}
+
+	if cfg.Scheduler.Topology.RegionWeight < 0 || cfg.Scheduler.Topology.ZoneWeight < 0 ||
+		cfg.Scheduler.Topology.RackWeight < 0 || cfg.Scheduler.Topology.SwitchWeight < 0 {
+		return errors.New("topology weights must be non-negative")
+	}
+
+	if cfg.Scheduler.ParentProbe.Enable {
+		if cfg.Scheduler.ParentProbe.Interval <= 0 {
+			return errors.New("parentProbe requires parameter interval")
+		}
+
+		if cfg.Scheduler.ParentProbe.Timeout <= 0 {
+			return errors.New("parentProbe requires parameter timeout")
+		}
+
+		if cfg.Scheduler.ParentProbe.Concurrency <= 0 {
+			return errors.New("parentProbe requires parameter concurrency")
+		}
+
+		if cfg.Scheduler.ParentProbe.QueueLength <= 0 {
+			return errors.New("parentProbe requires parameter queueLength")
+		}
+	}
+
+	if cfg.Scheduler.UploadReservation.Enable {
+		if cfg.Scheduler.UploadReservation.Timeout <= 0 {
+			return errors.New("uploadReservation requires parameter timeout")
+		}
+
+		if cfg.Scheduler.UploadReservation.HMACSecret == "" {
+			return errors.New("uploadReservation requires parameter hmacSecret")
+		}
+	}
+
+	if cfg.Scheduler.Reputation.Enable {
+		if cfg.Scheduler.Reputation.Threshold <= 0 || cfg.Scheduler.Reputation.Threshold >= 1 {
+			return errors.New("reputation threshold must be in (0, 1)")
+		}
+
+		if cfg.Scheduler.Reputation.BlockDuration <= 0 {
+			return errors.New("reputation requires parameter blockDuration")
+		}
+	}
+
+	if cfg.Scheduler.PeerExchange.Enable {
+		if cfg.Scheduler.PeerExchange.HotTaskPeerCount <= 0 {
+			return errors.New("peerExchange requires parameter hotTaskPeerCount")
+		}
+
+		if cfg.Scheduler.PeerExchange.SiblingLimit <= 0 {
+			return errors.New("peerExchange requires parameter siblingLimit")
+		}
+	}
+
+	if cfg.Scheduler.SeedPeerElection.Enable {
+		if cfg.Scheduler.SeedPeerElection.Interval <= 0 {
+			return errors.New("seedPeerElection requires parameter interval")
+		}
+
+		if cfg.Scheduler.SeedPeerElection.HotTaskPeerCount <= 0 {
+			return errors.New("seedPeerElection requires parameter hotTaskPeerCount")
+		}
+
+		if cfg.Scheduler.SeedPeerElection.SeedPeerLimit <= 0 {
+			return errors.New("seedPeerElection requires parameter seedPeerLimit")
+		}
+	}
+
+	if cfg.Scheduler.FlashCrowd.Enable {
+		if cfg.Scheduler.FlashCrowd.Interval <= 0 {
+			return errors.New("flashCrowd requires parameter interval")
+		}
+
+		if cfg.Scheduler.FlashCrowd.MinRegisterCount <= 0 {
+			return errors.New("flashCrowd requires parameter minRegisterCount")
+		}
+
+		if cfg.Scheduler.FlashCrowd.GrowthRate <= 1 {
+			return errors.New("flashCrowd requires parameter growthRate greater than 1")
+		}
+
+		if cfg.Scheduler.FlashCrowd.CandidateParentLimit <= 0 {
+			return errors.New("flashCrowd requires parameter candidateParentLimit")
+		}
+
+		if cfg.Scheduler.FlashCrowd.FilterParentLimit <= 0 {
+			return errors.New("flashCrowd requires parameter filterParentLimit")
+		}
+	}
+
+	if cfg.Scheduler.Federation.Enable {
+		if cfg.Scheduler.Federation.Interval <= 0 {
+			return errors.New("federation requires parameter interval")
+		}
+
+		if cfg.Scheduler.Federation.Timeout <= 0 {
+			return errors.New("federation requires parameter timeout")
+		}
+	}
+
+	if cfg.Scheduler.Replication.Enable {
+		if cfg.Scheduler.Replication.Interval <= 0 {
+			return errors.New("replication requires parameter interval")
+		}
+
+		if cfg.Scheduler.Replication.Timeout <= 0 {
+			return errors.New("replication requires parameter timeout")
+		}
+	}
+
 	if cfg.Scheduler.BackToSourceCount == 0 {
 		return errors.New("scheduler requires parameter backToSourceCount")
 	}
@@ -526,6 +1116,17 @@ func (cfg *Config) Validate() error {
 		return errors.New("scheduler requires parameter hostTTL")
 	}
 
+	switch cfg.Scheduler.GC.PeerEviction.Policy {
+	case PeerEvictionPolicyLRU, PeerEvictionPolicyScore:
+		if cfg.Scheduler.GC.PeerEviction.PeerLimit <= 0 {
+			return errors.New("peerEviction requires parameter peerLimit")
+		}
+	case PeerEvictionPolicyMemory:
+		if cfg.Scheduler.GC.PeerEviction.MemoryLimit <= 0 {
+			return errors.New("peerEviction requires parameter memoryLimit")
+		}
+	}
+
 	if cfg.Database.Redis.BrokerDB < 0 {
 		return errors.New("redis requires parameter brokerDB")
 	}
@@ -538,6 +1139,10 @@ func (cfg *Config) Validate() error {
 		return errors.New("redis requires parameter networkTopologyDB")
 	}
 
+	if cfg.Database.Redis.SnapshotDB < 0 {
+		return errors.New("redis requires parameter snapshotDB")
+	}
+
 	if !slices.Contains([]string{"http", "https"}, cfg.Resource.Task.DownloadTiny.Scheme) {
 		return errors.New("downloadTiny requires parameter scheme")
 	}
@@ -546,6 +1151,20 @@ func (cfg *Config) Validate() error {
 		return errors.New("downloadTiny requires parameter timeout")
 	}
 
+	if cfg.Resource.SeedPeer.TriggerLimit < 0 {
+		return errors.New("seedPeer requires parameter triggerLimit")
+	}
+
+	if cfg.Resource.Snapshot.Enable {
+		if cfg.Resource.Snapshot.Interval <= 0 {
+			return errors.New("snapshot requires parameter interval")
+		}
+
+		if len(cfg.Database.Redis.Addrs) == 0 {
+			return errors.New("snapshot requires parameter redis addrs")
+		}
+	}
+
 	if cfg.DynConfig.RefreshInterval <= 0 {
 		return errors.New("dynconfig requires parameter refreshInterval")
 	}
@@ -594,6 +1213,42 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	if cfg.Profiling.Enable {
+		if cfg.Profiling.Addr == "" {
+			return errors.New("profiling requires parameter addr")
+		}
+
+		if cfg.Profiling.Interval <= 0 {
+			return errors.New("profiling requires parameter interval")
+		}
+	}
+
+	if cfg.Event.Enable {
+		if cfg.Event.BufferSize <= 0 {
+			return errors.New("event requires parameter bufferSize")
+		}
+
+		if cfg.Event.Kafka.Enable {
+			if len(cfg.Event.Kafka.Brokers) == 0 {
+				return errors.New("kafka requires parameter brokers")
+			}
+
+			if cfg.Event.Kafka.Topic == "" {
+				return errors.New("kafka requires parameter topic")
+			}
+		}
+
+		if cfg.Event.NATS.Enable {
+			if cfg.Event.NATS.URL == "" {
+				return errors.New("nats requires parameter url")
+			}
+
+			if cfg.Event.NATS.Subject == "" {
+				return errors.New("nats requires parameter subject")
+			}
+		}
+	}
+
 	if cfg.Security.AutoIssueCert {
 		if cfg.Security.CACert == "" {
 			return errors.New("security requires parameter caCert")
@@ -614,6 +1269,18 @@ func (cfg *Config) Validate() error {
 		if cfg.Security.CertSpec.ValidityPeriod <= 0 {
 			return errors.New("certSpec requires parameter validityPeriod")
 		}
+	} else if cfg.Security.CertFile != "" {
+		if cfg.Security.KeyFile == "" {
+			return errors.New("security requires parameter keyFile")
+		}
+
+		if cfg.Security.CAFile == "" {
+			return errors.New("security requires parameter caFile")
+		}
+
+		if !slices.Contains([]string{rpc.DefaultTLSPolicy, rpc.ForceTLSPolicy, rpc.PreferTLSPolicy}, cfg.Security.TLSPolicy) {
+			return errors.New("security requires parameter tlsPolicy")
+		}
 	}
 
 	if cfg.NetworkTopology.CollectInterval <= 0 {
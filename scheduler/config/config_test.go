@@ -27,6 +27,7 @@ import (
 
 	"d7y.io/dragonfly/v2/pkg/rpc"
 	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/pkg/unit"
 )
 
 var (
@@ -69,13 +70,75 @@ var (
 		BrokerDB:          DefaultRedisBrokerDB,
 		BackendDB:         DefaultRedisBackendDB,
 		NetworkTopologyDB: DefaultNetworkTopologyDB,
+		SnapshotDB:        DefaultSnapshotDB,
 	}
 )
 
 func TestConfig_Load(t *testing.T) {
 	config := &Config{
 		Scheduler: SchedulerConfig{
-			Algorithm:              "default",
+			Algorithm: "default",
+			Plugin: PluginConfig{
+				Weight:  0.5,
+				Options: map[string]string{"foo": "bar"},
+			},
+			Topology: TopologyConfig{
+				RegionWeight: 0.05,
+				ZoneWeight:   0.05,
+				RackWeight:   0.03,
+				SwitchWeight: 0.02,
+			},
+			Inference: InferenceConfig{
+				Addr:                 "127.0.0.1:8001",
+				LatencyBudget:        50 * time.Millisecond,
+				RefreshModelInterval: 1 * time.Hour,
+			},
+			ParentProbe: ParentProbeConfig{
+				Enable:      true,
+				Interval:    30 * time.Second,
+				Timeout:     3 * time.Second,
+				Concurrency: 16,
+				QueueLength: 1000,
+			},
+			UploadReservation: UploadReservationConfig{
+				Enable:     true,
+				Timeout:    500 * time.Millisecond,
+				HMACSecret: "foo",
+			},
+			Reputation: ReputationConfig{
+				Enable:        true,
+				Threshold:     0.5,
+				BlockDuration: 10 * time.Minute,
+			},
+			PeerExchange: PeerExchangeConfig{
+				Enable:           true,
+				HotTaskPeerCount: 50,
+				SiblingLimit:     4,
+			},
+			SeedPeerElection: SeedPeerElectionConfig{
+				Enable:           true,
+				Interval:         30 * time.Second,
+				HotTaskPeerCount: 50,
+				SeedPeerLimit:    2,
+			},
+			FlashCrowd: FlashCrowdConfig{
+				Enable:               true,
+				Interval:             10 * time.Second,
+				MinRegisterCount:     100,
+				GrowthRate:           4,
+				CandidateParentLimit: 8,
+				FilterParentLimit:    80,
+			},
+			Federation: FederationConfig{
+				Enable:   true,
+				Interval: 5 * time.Minute,
+				Timeout:  3 * time.Second,
+			},
+			Replication: ReplicationConfig{
+				Enable:   true,
+				Interval: 30 * time.Second,
+				Timeout:  30 * time.Minute,
+			},
 			BackToSourceCount:      3,
 			RetryBackToSourceLimit: 2,
 			RetryLimit:             10,
@@ -87,6 +150,11 @@ func TestConfig_Load(t *testing.T) {
 				TaskGCInterval:       30 * time.Second,
 				HostGCInterval:       1 * time.Minute,
 				HostTTL:              1 * time.Minute,
+				PeerEviction: PeerEvictionConfig{
+					Policy:      PeerEvictionPolicyLRU,
+					PeerLimit:   1000,
+					MemoryLimit: unit.GB,
+				},
 			},
 		},
 		Server: ServerConfig{
@@ -100,6 +168,18 @@ func TestConfig_Load(t *testing.T) {
 			LogDir:        "foo",
 			PluginDir:     "foo",
 			DataDir:       "foo",
+			RateLimit: RateLimitConfig{
+				QPS:   1000,
+				Burst: 2000,
+				Methods: []MethodRateLimitConfig{
+					{
+						Method:      "/scheduler.Scheduler/RegisterPeerTask",
+						QPS:         100,
+						Burst:       200,
+						MaxInflight: 100,
+					},
+				},
+			},
 		},
 		Database: DatabaseConfig{
 			Redis: RedisConfig{
@@ -111,6 +191,7 @@ func TestConfig_Load(t *testing.T) {
 				BrokerDB:          DefaultRedisBrokerDB,
 				BackendDB:         DefaultRedisBackendDB,
 				NetworkTopologyDB: DefaultNetworkTopologyDB,
+				SnapshotDB:        DefaultSnapshotDB,
 			},
 		},
 		Resource: ResourceConfig{
@@ -123,6 +204,13 @@ func TestConfig_Load(t *testing.T) {
 					},
 				},
 			},
+			SeedPeer: SeedPeerResourceConfig{
+				TriggerLimit: 10,
+			},
+			Snapshot: SnapshotConfig{
+				Enable:   true,
+				Interval: 30 * time.Second,
+			},
 		},
 		DynConfig: DynConfig{
 			RefreshInterval: 10 * time.Second,
@@ -157,6 +245,25 @@ func TestConfig_Load(t *testing.T) {
 			Addr:       ":8000",
 			EnableHost: true,
 		},
+		Profiling: ProfilingConfig{
+			Enable:   true,
+			Addr:     "http://127.0.0.1:4040",
+			Interval: 10 * time.Second,
+		},
+		Event: EventConfig{
+			Enable:     true,
+			BufferSize: 100,
+			Kafka: KafkaEventConfig{
+				Enable:  true,
+				Brokers: []string{"127.0.0.1:9092"},
+				Topic:   "foo",
+			},
+			NATS: NATSEventConfig{
+				Enable:  true,
+				URL:     "nats://127.0.0.1:4222",
+				Subject: "bar",
+			},
+		},
 		Security: SecurityConfig{
 			AutoIssueCert: true,
 			CACert:        "foo",
@@ -282,6 +389,84 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "server requires parameter host")
 			},
 		},
+		{
+			name:   "rateLimit requires parameter qps",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Job = mockJobConfig
+				cfg.Server.RateLimit.QPS = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "rateLimit requires parameter qps")
+			},
+		},
+		{
+			name:   "rateLimit requires parameter burst",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Job = mockJobConfig
+				cfg.Server.RateLimit.Burst = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "rateLimit requires parameter burst")
+			},
+		},
+		{
+			name:   "rateLimit requires parameter method",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Job = mockJobConfig
+				cfg.Server.RateLimit.Methods[0].Method = ""
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "rateLimit requires parameter method")
+			},
+		},
+		{
+			name:   "rateLimit requires parameter method qps",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Job = mockJobConfig
+				cfg.Server.RateLimit.Methods[0].QPS = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "rateLimit requires parameter method qps")
+			},
+		},
+		{
+			name:   "rateLimit requires parameter method burst",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Job = mockJobConfig
+				cfg.Server.RateLimit.Methods[0].Burst = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "rateLimit requires parameter method burst")
+			},
+		},
+		{
+			name:   "rateLimit requires parameter method maxInflight",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Job = mockJobConfig
+				cfg.Server.RateLimit.Methods[0].MaxInflight = -1
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "rateLimit requires parameter method maxInflight")
+			},
+		},
 		{
 			name:   "redis requires parameter brokerDB",
 			config: New(),
@@ -321,6 +506,19 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "redis requires parameter networkTopologyDB")
 			},
 		},
+		{
+			name:   "redis requires parameter snapshotDB",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Database.Redis.SnapshotDB = -1
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "redis requires parameter snapshotDB")
+			},
+		},
 		{
 			name:   "scheduler requires parameter algorithm",
 			config: New(),
@@ -391,6 +589,380 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "scheduler requires parameter retryInterval")
 			},
 		},
+		{
+			name:   "inference requires parameter addr",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Algorithm = SchedulerAlgorithmML
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "inference requires parameter addr")
+			},
+		},
+		{
+			name:   "inference requires parameter latencyBudget",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Algorithm = SchedulerAlgorithmML
+				cfg.Scheduler.Inference.Addr = "127.0.0.1:8001"
+				cfg.Scheduler.Inference.LatencyBudget = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "inference requires parameter latencyBudget")
+			},
+		},
+		{
+			name:   "topology weights must be non-negative",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Topology.ZoneWeight = -1
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "topology weights must be non-negative")
+			},
+		},
+		{
+			name:   "uploadReservation requires parameter timeout",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.UploadReservation.Enable = true
+				cfg.Scheduler.UploadReservation.Timeout = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "uploadReservation requires parameter timeout")
+			},
+		},
+		{
+			name:   "uploadReservation requires parameter hmacSecret",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.UploadReservation.Enable = true
+				cfg.Scheduler.UploadReservation.HMACSecret = ""
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "uploadReservation requires parameter hmacSecret")
+			},
+		},
+		{
+			name:   "reputation threshold must be in (0, 1)",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Reputation.Enable = true
+				cfg.Scheduler.Reputation.Threshold = 1
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "reputation threshold must be in (0, 1)")
+			},
+		},
+		{
+			name:   "reputation requires parameter blockDuration",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Reputation.Enable = true
+				cfg.Scheduler.Reputation.BlockDuration = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "reputation requires parameter blockDuration")
+			},
+		},
+		{
+			name:   "parentProbe requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.ParentProbe.Enable = true
+				cfg.Scheduler.ParentProbe.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "parentProbe requires parameter interval")
+			},
+		},
+		{
+			name:   "parentProbe requires parameter timeout",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.ParentProbe.Enable = true
+				cfg.Scheduler.ParentProbe.Timeout = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "parentProbe requires parameter timeout")
+			},
+		},
+		{
+			name:   "parentProbe requires parameter concurrency",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.ParentProbe.Enable = true
+				cfg.Scheduler.ParentProbe.Concurrency = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "parentProbe requires parameter concurrency")
+			},
+		},
+		{
+			name:   "parentProbe requires parameter queueLength",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.ParentProbe.Enable = true
+				cfg.Scheduler.ParentProbe.QueueLength = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "parentProbe requires parameter queueLength")
+			},
+		},
+		{
+			name:   "peerExchange requires parameter hotTaskPeerCount",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.PeerExchange.Enable = true
+				cfg.Scheduler.PeerExchange.HotTaskPeerCount = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "peerExchange requires parameter hotTaskPeerCount")
+			},
+		},
+		{
+			name:   "peerExchange requires parameter siblingLimit",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.PeerExchange.Enable = true
+				cfg.Scheduler.PeerExchange.SiblingLimit = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "peerExchange requires parameter siblingLimit")
+			},
+		},
+		{
+			name:   "seedPeerElection requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.SeedPeerElection.Enable = true
+				cfg.Scheduler.SeedPeerElection.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "seedPeerElection requires parameter interval")
+			},
+		},
+		{
+			name:   "seedPeerElection requires parameter hotTaskPeerCount",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.SeedPeerElection.Enable = true
+				cfg.Scheduler.SeedPeerElection.HotTaskPeerCount = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "seedPeerElection requires parameter hotTaskPeerCount")
+			},
+		},
+		{
+			name:   "seedPeerElection requires parameter seedPeerLimit",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.SeedPeerElection.Enable = true
+				cfg.Scheduler.SeedPeerElection.SeedPeerLimit = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "seedPeerElection requires parameter seedPeerLimit")
+			},
+		},
+		{
+			name:   "flashCrowd requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.FlashCrowd.Enable = true
+				cfg.Scheduler.FlashCrowd.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "flashCrowd requires parameter interval")
+			},
+		},
+		{
+			name:   "flashCrowd requires parameter minRegisterCount",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.FlashCrowd.Enable = true
+				cfg.Scheduler.FlashCrowd.MinRegisterCount = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "flashCrowd requires parameter minRegisterCount")
+			},
+		},
+		{
+			name:   "flashCrowd requires parameter growthRate greater than 1",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.FlashCrowd.Enable = true
+				cfg.Scheduler.FlashCrowd.GrowthRate = 1
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "flashCrowd requires parameter growthRate greater than 1")
+			},
+		},
+		{
+			name:   "flashCrowd requires parameter candidateParentLimit",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.FlashCrowd.Enable = true
+				cfg.Scheduler.FlashCrowd.CandidateParentLimit = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "flashCrowd requires parameter candidateParentLimit")
+			},
+		},
+		{
+			name:   "flashCrowd requires parameter filterParentLimit",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.FlashCrowd.Enable = true
+				cfg.Scheduler.FlashCrowd.FilterParentLimit = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "flashCrowd requires parameter filterParentLimit")
+			},
+		},
+		{
+			name:   "federation requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Federation.Enable = true
+				cfg.Scheduler.Federation.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "federation requires parameter interval")
+			},
+		},
+		{
+			name:   "federation requires parameter timeout",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Federation.Enable = true
+				cfg.Scheduler.Federation.Timeout = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "federation requires parameter timeout")
+			},
+		},
+		{
+			name:   "replication requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Replication.Enable = true
+				cfg.Scheduler.Replication.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "replication requires parameter interval")
+			},
+		},
+		{
+			name:   "replication requires parameter timeout",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.Replication.Enable = true
+				cfg.Scheduler.Replication.Timeout = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "replication requires parameter timeout")
+			},
+		},
 		{
 			name:   "scheduler requires parameter pieceDownloadTimeout",
 			config: New(),
@@ -489,6 +1061,35 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "downloadTiny requires parameter timeout")
 			},
 		},
+		{
+			name:   "seedPeer requires parameter triggerLimit",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Resource.SeedPeer.TriggerLimit = -1
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "seedPeer requires parameter triggerLimit")
+			},
+		},
+		{
+			name:   "snapshot requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Resource.Snapshot.Enable = true
+				cfg.Resource.Snapshot.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "snapshot requires parameter interval")
+			},
+		},
 		{
 			name:   "scheduler requires parameter hostTTL",
 			config: New(),
@@ -503,6 +1104,36 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "scheduler requires parameter hostTTL")
 			},
 		},
+		{
+			name:   "peerEviction requires parameter peerLimit",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.GC.PeerEviction.Policy = PeerEvictionPolicyScore
+				cfg.Scheduler.GC.PeerEviction.PeerLimit = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "peerEviction requires parameter peerLimit")
+			},
+		},
+		{
+			name:   "peerEviction requires parameter memoryLimit",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Scheduler.GC.PeerEviction.Policy = PeerEvictionPolicyMemory
+				cfg.Scheduler.GC.PeerEviction.MemoryLimit = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "peerEviction requires parameter memoryLimit")
+			},
+		},
 		{
 			name:   "dynconfig requires parameter refreshInterval",
 			config: New(),
@@ -658,6 +1289,115 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "metrics requires parameter addr")
 			},
 		},
+		{
+			name:   "profiling requires parameter addr",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Profiling.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "profiling requires parameter addr")
+			},
+		},
+		{
+			name:   "profiling requires parameter interval",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Profiling.Enable = true
+				cfg.Profiling.Addr = "http://127.0.0.1:4040"
+				cfg.Profiling.Interval = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "profiling requires parameter interval")
+			},
+		},
+		{
+			name:   "event requires parameter bufferSize",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Event.Enable = true
+				cfg.Event.BufferSize = 0
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "event requires parameter bufferSize")
+			},
+		},
+		{
+			name:   "kafka requires parameter brokers",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Event.Enable = true
+				cfg.Event.Kafka.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "kafka requires parameter brokers")
+			},
+		},
+		{
+			name:   "kafka requires parameter topic",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Event.Enable = true
+				cfg.Event.Kafka.Enable = true
+				cfg.Event.Kafka.Brokers = []string{"127.0.0.1:9092"}
+				cfg.Event.Kafka.Topic = ""
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "kafka requires parameter topic")
+			},
+		},
+		{
+			name:   "nats requires parameter url",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Event.Enable = true
+				cfg.Event.NATS.Enable = true
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "nats requires parameter url")
+			},
+		},
+		{
+			name:   "nats requires parameter subject",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Event.Enable = true
+				cfg.Event.NATS.Enable = true
+				cfg.Event.NATS.URL = "nats://127.0.0.1:4222"
+				cfg.Event.NATS.Subject = ""
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "nats requires parameter subject")
+			},
+		},
 		{
 			name:   "security requires parameter caCert",
 			config: New(),
@@ -733,6 +1473,36 @@ func TestConfig_Validate(t *testing.T) {
 				assert.EqualError(err, "certSpec requires parameter validityPeriod")
 			},
 		},
+		{
+			name:   "security requires parameter keyFile",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Security.CertFile = "foo"
+				cfg.Security.CAFile = "bar"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security requires parameter keyFile")
+			},
+		},
+		{
+			name:   "security requires parameter caFile",
+			config: New(),
+			mock: func(cfg *Config) {
+				cfg.Manager = mockManagerConfig
+				cfg.Database.Redis = mockRedisConfig
+				cfg.Job = mockJobConfig
+				cfg.Security.CertFile = "foo"
+				cfg.Security.KeyFile = "bar"
+			},
+			expect: func(t *testing.T, err error) {
+				assert := assert.New(t)
+				assert.EqualError(err, "security requires parameter caFile")
+			},
+		},
 		{
 			name:   "networkTopology requires parameter collectInterval",
 			config: New(),
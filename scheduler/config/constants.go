@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"d7y.io/dragonfly/v2/pkg/net/ip"
+	"d7y.io/dragonfly/v2/pkg/unit"
 )
 
 const (
@@ -46,6 +47,25 @@ const (
 
 	// DefaultServerAdvertisePort is default advertise port for server.
 	DefaultServerAdvertisePort = 8002
+
+	// DefaultServerRateLimitQPS is default qps of grpc server.
+	DefaultServerRateLimitQPS = 10 * 1000
+
+	// DefaultServerRateLimitBurst is default burst of grpc server.
+	DefaultServerRateLimitBurst = 20 * 1000
+
+	// DefaultServerRegisterPeerTaskMethod is the full method name of RegisterPeerTask,
+	// it is throttled independently because it is more expensive than the other methods.
+	DefaultServerRegisterPeerTaskMethod = "/scheduler.Scheduler/RegisterPeerTask"
+
+	// DefaultServerRegisterPeerTaskQPS is default qps of RegisterPeerTask method.
+	DefaultServerRegisterPeerTaskQPS = 2 * 1000
+
+	// DefaultServerRegisterPeerTaskBurst is default burst of RegisterPeerTask method.
+	DefaultServerRegisterPeerTaskBurst = 4 * 1000
+
+	// DefaultServerRegisterPeerTaskMaxInflight is default max concurrent executions of RegisterPeerTask method.
+	DefaultServerRegisterPeerTaskMaxInflight = 2 * 1000
 )
 
 const (
@@ -57,12 +77,99 @@ const (
 
 	// DefaultNetworkTopologyDB is default db for network topology.
 	DefaultNetworkTopologyDB = 3
+
+	// DefaultSnapshotDB is default db for resource snapshot.
+	DefaultSnapshotDB = 4
 )
 
 const (
 	// DefaultSchedulerAlgorithm is default algorithm for scheduler.
 	DefaultSchedulerAlgorithm = "default"
 
+	// SchedulerAlgorithmML is the machine learning algorithm for scheduler.
+	SchedulerAlgorithmML = "ml"
+
+	// DefaultSchedulerInferenceLatencyBudget is default latency budget of model inference.
+	DefaultSchedulerInferenceLatencyBudget = 50 * time.Millisecond
+
+	// DefaultSchedulerPluginWeight is default weight of the score supplied by evaluator plugin.
+	DefaultSchedulerPluginWeight = 0.5
+
+	// DefaultSchedulerTopologyRegionWeight is default relative weight of parent in the same region.
+	DefaultSchedulerTopologyRegionWeight = 0.05
+
+	// DefaultSchedulerTopologyZoneWeight is default relative weight of parent in the same zone.
+	DefaultSchedulerTopologyZoneWeight = 0.05
+
+	// DefaultSchedulerTopologyRackWeight is default relative weight of parent in the same rack.
+	DefaultSchedulerTopologyRackWeight = 0.03
+
+	// DefaultSchedulerTopologySwitchWeight is default relative weight of parent under the same switch.
+	DefaultSchedulerTopologySwitchWeight = 0.02
+
+	// DefaultSchedulerParentProbeInterval is default interval of probing the same parent.
+	DefaultSchedulerParentProbeInterval = 30 * time.Second
+
+	// DefaultSchedulerParentProbeTimeout is default timeout of probing a parent.
+	DefaultSchedulerParentProbeTimeout = 3 * time.Second
+
+	// DefaultSchedulerParentProbeConcurrency is default number of concurrent parent probes.
+	DefaultSchedulerParentProbeConcurrency = 16
+
+	// DefaultSchedulerParentProbeQueueLength is default length of parent probe queue.
+	DefaultSchedulerParentProbeQueueLength = 1000
+
+	// DefaultSchedulerUploadReservationTimeout is default timeout of reserving an upload slot on a parent.
+	DefaultSchedulerUploadReservationTimeout = 500 * time.Millisecond
+
+	// DefaultSchedulerReputationThreshold is default reputation score below which the parent is blocklisted.
+	DefaultSchedulerReputationThreshold = 0.5
+
+	// DefaultSchedulerReputationBlockDuration is default duration of blocklisting the parent.
+	DefaultSchedulerReputationBlockDuration = 10 * time.Minute
+
+	// DefaultSchedulerPeerExchangeHotTaskPeerCount is default peer count of task regarded as hot task.
+	DefaultSchedulerPeerExchangeHotTaskPeerCount = 50
+
+	// DefaultSchedulerPeerExchangeSiblingLimit is default limit count of sibling peers.
+	DefaultSchedulerPeerExchangeSiblingLimit = 4
+
+	// DefaultSchedulerSeedPeerElectionInterval is default interval of electing temporary seed peers.
+	DefaultSchedulerSeedPeerElectionInterval = 30 * time.Second
+
+	// DefaultSchedulerSeedPeerElectionHotTaskPeerCount is default peer count of task regarded as hot task.
+	DefaultSchedulerSeedPeerElectionHotTaskPeerCount = 50
+
+	// DefaultSchedulerSeedPeerElectionSeedPeerLimit is default limit count of temporary seed peers of a task.
+	DefaultSchedulerSeedPeerElectionSeedPeerLimit = 2
+
+	// DefaultSchedulerFlashCrowdInterval is default window of counting registrations of the task.
+	DefaultSchedulerFlashCrowdInterval = 10 * time.Second
+
+	// DefaultSchedulerFlashCrowdMinRegisterCount is default minimum registrations in a window regarded as flash crowd.
+	DefaultSchedulerFlashCrowdMinRegisterCount = 100
+
+	// DefaultSchedulerFlashCrowdGrowthRate is default growth rate of registrations regarded as flash crowd.
+	DefaultSchedulerFlashCrowdGrowthRate = 4
+
+	// DefaultSchedulerFlashCrowdCandidateParentLimit is default limit count of candidate parents in flash crowd.
+	DefaultSchedulerFlashCrowdCandidateParentLimit = 8
+
+	// DefaultSchedulerFlashCrowdFilterParentLimit is default limit count of filtering parents in flash crowd.
+	DefaultSchedulerFlashCrowdFilterParentLimit = 80
+
+	// DefaultSchedulerFederationInterval is default interval of refreshing the sibling schedulers.
+	DefaultSchedulerFederationInterval = 5 * time.Minute
+
+	// DefaultSchedulerFederationTimeout is default timeout of looking up the task in a sibling scheduler.
+	DefaultSchedulerFederationTimeout = 3 * time.Second
+
+	// DefaultSchedulerReplicationInterval is default interval of checking the replicas of the persistent cache tasks.
+	DefaultSchedulerReplicationInterval = 30 * time.Second
+
+	// DefaultSchedulerReplicationTimeout is default timeout of replicating the task to a host.
+	DefaultSchedulerReplicationTimeout = 30 * time.Minute
+
 	// DefaultSchedulerBackToSourceCount is default back-to-source count for scheduler.
 	DefaultSchedulerBackToSourceCount = 3
 
@@ -93,6 +200,12 @@ const (
 	// DefaultSchedulerHostTTL is default ttl for host.
 	DefaultSchedulerHostTTL = 1 * time.Hour
 
+	// DefaultSchedulerPeerEvictionPeerLimit is default limit count of peers for eviction.
+	DefaultSchedulerPeerEvictionPeerLimit = 100000
+
+	// DefaultSchedulerPeerEvictionMemoryLimit is default limit of heap memory for eviction.
+	DefaultSchedulerPeerEvictionMemoryLimit = 4 * unit.GB
+
 	// DefaultRefreshModelInterval is model refresh interval.
 	DefaultRefreshModelInterval = 168 * time.Hour
 
@@ -100,12 +213,29 @@ const (
 	DefaultCPU = 1
 )
 
+const (
+	// PeerEvictionPolicyLRU evicts the least recently used peers.
+	PeerEvictionPolicyLRU = "lru"
+
+	// PeerEvictionPolicyScore evicts the peers with the lowest retention score.
+	PeerEvictionPolicyScore = "score"
+
+	// PeerEvictionPolicyMemory evicts the least recently used peers under memory pressure.
+	PeerEvictionPolicyMemory = "memory"
+)
+
 const (
 	// DefaultResourceTaskDownloadTinyScheme is default scheme of downloading tiny task.
 	DefaultResourceTaskDownloadTinyScheme = "http"
 
 	// DefaultResourceTaskDownloadTinyTimeout is default timeout of downloading tiny task.
 	DefaultResourceTaskDownloadTinyTimeout = 1 * time.Minute
+
+	// DefaultResourceSeedPeerTriggerLimit is default limit of concurrent seed peer back-to-source triggers.
+	DefaultResourceSeedPeerTriggerLimit = 100
+
+	// DefaultResourceSnapshotInterval is default interval of taking resource snapshot.
+	DefaultResourceSnapshotInterval = 1 * time.Minute
 )
 
 const (
@@ -143,6 +273,22 @@ const (
 	DefaultMetricsAddr = ":8000"
 )
 
+const (
+	// DefaultProfilingInterval is default interval for collecting a cpu profile.
+	DefaultProfilingInterval = 15 * time.Second
+)
+
+const (
+	// DefaultEventBufferSize is default size of the event buffer.
+	DefaultEventBufferSize = 10000
+
+	// DefaultEventKafkaTopic is default kafka topic of the events.
+	DefaultEventKafkaTopic = "dragonfly-scheduler-events"
+
+	// DefaultEventNATSSubject is default nats subject prefix of the events.
+	DefaultEventNATSSubject = "dragonfly.scheduler.events"
+)
+
 var (
 	// DefaultCertIPAddresses is default ip addresses of certificate.
 	DefaultCertIPAddresses = []net.IP{ip.IPv4, ip.IPv6}
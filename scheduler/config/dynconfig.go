@@ -40,7 +40,9 @@ import (
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	dc "d7y.io/dragonfly/v2/internal/dynconfig"
 	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
 	"d7y.io/dragonfly/v2/pkg/net/ip"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	healthclient "d7y.io/dragonfly/v2/pkg/rpc/health/client"
 	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
 	"d7y.io/dragonfly/v2/pkg/slices"
@@ -81,6 +83,12 @@ type DynconfigInterface interface {
 	// GetSchedulerClusterClientConfig returns the client config.
 	GetSchedulerClusterClientConfig() (types.SchedulerClusterClientConfig, error)
 
+	// GetApplicationPolicies returns the download policies of the applications.
+	GetApplicationPolicies() (map[string]rpc.ApplicationPolicy, error)
+
+	// GetFeatureFlags returns the feature flags rolled out to the scheduler.
+	GetFeatureFlags() (featureflag.Flags, error)
+
 	// Get returns the dynamic config from manager.
 	Get() (*DynconfigData, error)
 
@@ -320,6 +328,38 @@ func (d *dynconfig) GetSchedulerClusterClientConfig() (types.SchedulerClusterCli
 	return config, nil
 }
 
+// GetApplicationPolicies returns the download policies of the applications,
+// the policies are from the client config of the scheduler cluster.
+func (d *dynconfig) GetApplicationPolicies() (map[string]rpc.ApplicationPolicy, error) {
+	schedulerCluster, err := d.GetSchedulerCluster()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schedulerCluster.ClientConfig) == 0 {
+		return nil, nil
+	}
+
+	var clientConfig struct {
+		ApplicationPolicies map[string]rpc.ApplicationPolicy `json:"application_policies"`
+	}
+	if err := json.Unmarshal(schedulerCluster.ClientConfig, &clientConfig); err != nil {
+		return nil, err
+	}
+
+	return clientConfig.ApplicationPolicies, nil
+}
+
+// GetFeatureFlags returns the feature flags rolled out to the scheduler.
+func (d *dynconfig) GetFeatureFlags() (featureflag.Flags, error) {
+	schedulerCluster, err := d.GetSchedulerCluster()
+	if err != nil {
+		return nil, err
+	}
+
+	return featureflag.Parse(schedulerCluster.Config)
+}
+
 // Refresh refreshes dynconfig in cache.
 func (d *dynconfig) Refresh() error {
 	if err := d.Dynconfig.Refresh(); err != nil {
@@ -0,0 +1,66 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"sync"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
+)
+
+// DynamicFeatureFlags is the feature flags rolled out to the scheduler, the flags
+// are updated when dynconfig is refreshed without restarting scheduler.
+type DynamicFeatureFlags struct {
+	mu    sync.RWMutex
+	flags featureflag.Flags
+}
+
+// NewDynamicFeatureFlags returns a new DynamicFeatureFlags and registers it to dynconfig.
+func NewDynamicFeatureFlags(dynconfig DynconfigInterface) *DynamicFeatureFlags {
+	f := &DynamicFeatureFlags{flags: featureflag.Flags{}}
+	if flags, err := dynconfig.GetFeatureFlags(); err == nil {
+		f.flags = flags
+	}
+
+	dynconfig.Register(f)
+	return f
+}
+
+// Load returns the current feature flags.
+func (f *DynamicFeatureFlags) Load() featureflag.Flags {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.flags
+}
+
+// OnNotify updates the feature flags by the scheduler cluster config.
+func (f *DynamicFeatureFlags) OnNotify(data *DynconfigData) {
+	if data == nil || data.Scheduler == nil || data.Scheduler.SchedulerCluster == nil {
+		return
+	}
+
+	flags, err := featureflag.Parse(data.Scheduler.SchedulerCluster.Config)
+	if err != nil {
+		logger.Errorf("parse feature flags failed: %s", err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags = flags
+}
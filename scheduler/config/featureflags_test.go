@@ -0,0 +1,78 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	managerv2 "d7y.io/api/v2/pkg/apis/manager/v2"
+
+	"d7y.io/dragonfly/v2/pkg/featureflag"
+)
+
+func TestDynamicFeatureFlags_OnNotify(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []*DynconfigData
+		expect func(t *testing.T, flags featureflag.Flags)
+	}{
+		{
+			name: "feature flag is rolled out",
+			data: []*DynconfigData{
+				{Scheduler: &managerv2.Scheduler{SchedulerCluster: &managerv2.SchedulerCluster{Config: []byte(`{"feature_flags":["peer_exchange"]}`)}}},
+			},
+			expect: func(t *testing.T, flags featureflag.Flags) {
+				assert.True(t, flags.Enabled(featureflag.PeerExchange))
+			},
+		},
+		{
+			name: "feature flag is rolled back",
+			data: []*DynconfigData{
+				{Scheduler: &managerv2.Scheduler{SchedulerCluster: &managerv2.SchedulerCluster{Config: []byte(`{"feature_flags":["peer_exchange"]}`)}}},
+				{Scheduler: &managerv2.Scheduler{SchedulerCluster: &managerv2.SchedulerCluster{Config: []byte(`{"feature_flags":[]}`)}}},
+			},
+			expect: func(t *testing.T, flags featureflag.Flags) {
+				assert.False(t, flags.Enabled(featureflag.PeerExchange))
+			},
+		},
+		{
+			name: "invalid config is ignored",
+			data: []*DynconfigData{
+				{Scheduler: &managerv2.Scheduler{SchedulerCluster: &managerv2.SchedulerCluster{Config: []byte(`{"feature_flags":["peer_exchange"]}`)}}},
+				{Scheduler: &managerv2.Scheduler{SchedulerCluster: &managerv2.SchedulerCluster{Config: []byte(`foo`)}}},
+				{Scheduler: &managerv2.Scheduler{}},
+				nil,
+			},
+			expect: func(t *testing.T, flags featureflag.Flags) {
+				assert.True(t, flags.Enabled(featureflag.PeerExchange))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &DynamicFeatureFlags{flags: featureflag.Flags{}}
+			for _, data := range tc.data {
+				f.OnNotify(data)
+			}
+
+			tc.expect(t, f.Load())
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	manager "d7y.io/api/v2/pkg/apis/manager/v2"
 	types "d7y.io/dragonfly/v2/manager/types"
+	featureflag "d7y.io/dragonfly/v2/pkg/featureflag"
+	rpc "d7y.io/dragonfly/v2/pkg/rpc"
 	config "d7y.io/dragonfly/v2/scheduler/config"
 	gomock "github.com/golang/mock/gomock"
 	resolver "google.golang.org/grpc/resolver"
@@ -64,6 +66,21 @@ func (mr *MockDynconfigInterfaceMockRecorder) Get() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDynconfigInterface)(nil).Get))
 }
 
+// GetApplicationPolicies mocks base method.
+func (m *MockDynconfigInterface) GetApplicationPolicies() (map[string]rpc.ApplicationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicationPolicies")
+	ret0, _ := ret[0].(map[string]rpc.ApplicationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicationPolicies indicates an expected call of GetApplicationPolicies.
+func (mr *MockDynconfigInterfaceMockRecorder) GetApplicationPolicies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationPolicies", reflect.TypeOf((*MockDynconfigInterface)(nil).GetApplicationPolicies))
+}
+
 // GetApplications mocks base method.
 func (m *MockDynconfigInterface) GetApplications() ([]*manager.Application, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +96,21 @@ func (mr *MockDynconfigInterfaceMockRecorder) GetApplications() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplications", reflect.TypeOf((*MockDynconfigInterface)(nil).GetApplications))
 }
 
+// GetFeatureFlags mocks base method.
+func (m *MockDynconfigInterface) GetFeatureFlags() (featureflag.Flags, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeatureFlags")
+	ret0, _ := ret[0].(featureflag.Flags)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlags indicates an expected call of GetFeatureFlags.
+func (mr *MockDynconfigInterfaceMockRecorder) GetFeatureFlags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlags", reflect.TypeOf((*MockDynconfigInterface)(nil).GetFeatureFlags))
+}
+
 // GetResolveSeedPeerAddrs mocks base method.
 func (m *MockDynconfigInterface) GetResolveSeedPeerAddrs() ([]resolver.Address, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,213 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/event_mock.go -source event.go -package mocks
+
+package event
+
+import (
+	"context"
+	"time"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/scheduler/metrics"
+)
+
+const (
+	// defaultBufferSize is the default size of the event buffer.
+	defaultBufferSize = 10000
+
+	// defaultExportTimeout is the default timeout of exporting an event.
+	defaultExportTimeout = 5 * time.Second
+)
+
+// Type is the type of the task lifecycle event.
+type Type string
+
+const (
+	// TypeTaskCreated is the event of the task is created in the scheduler.
+	TypeTaskCreated Type = "task_created"
+
+	// TypePeerJoined is the event of the peer joins the task.
+	TypePeerJoined Type = "peer_joined"
+
+	// TypeParentSwitched is the event of the peer switches to the new parents.
+	TypeParentSwitched Type = "parent_switched"
+
+	// TypeTaskFailed is the event of the task downloads failed.
+	TypeTaskFailed Type = "task_failed"
+)
+
+// Event is the task lifecycle event.
+type Event struct {
+	// Type is the type of the event.
+	Type Type `json:"type"`
+
+	// Timestamp is the time of the event happened.
+	Timestamp time.Time `json:"timestamp"`
+
+	// SchedulerClusterID is the scheduler cluster id of the scheduler.
+	SchedulerClusterID uint `json:"scheduler_cluster_id"`
+
+	// TaskID is the id of the task.
+	TaskID string `json:"task_id"`
+
+	// URL is the url of the task.
+	URL string `json:"url,omitempty"`
+
+	// Application is the application of the task.
+	Application string `json:"application,omitempty"`
+
+	// Tag is the tag of the task.
+	Tag string `json:"tag,omitempty"`
+
+	// PeerID is the id of the peer.
+	PeerID string `json:"peer_id,omitempty"`
+
+	// HostID is the id of the host.
+	HostID string `json:"host_id,omitempty"`
+
+	// ParentIDs are the ids of the new parents when the parent is switched.
+	ParentIDs []string `json:"parent_ids,omitempty"`
+
+	// PreviousParentIDs are the ids of the previous parents when the parent is switched.
+	PreviousParentIDs []string `json:"previous_parent_ids,omitempty"`
+
+	// Message is the description of the event, like the failed reason.
+	Message string `json:"message,omitempty"`
+}
+
+// Exporter is the interface used for exporting events to the external systems.
+type Exporter interface {
+	// Name returns the name of the exporter.
+	Name() string
+
+	// Export exports the event.
+	Export(context.Context, *Event) error
+
+	// Close closes the exporter.
+	Close() error
+}
+
+// Bus is the interface used for publishing the task lifecycle events.
+type Bus interface {
+	// Publish publishes the event without blocking, the event is dropped if the buffer is full.
+	Publish(Event)
+
+	// Serve starts dispatching events to the exporters.
+	Serve()
+
+	// Stop stops dispatching events and closes the exporters.
+	Stop()
+}
+
+// bus dispatches the events to the exporters asynchronously.
+type bus struct {
+	schedulerClusterID uint
+	exporters          []Exporter
+	events             chan Event
+	done               chan struct{}
+}
+
+// Option is a functional option for configuring the bus.
+type Option func(b *bus)
+
+// WithBufferSize sets the size of the event buffer.
+func WithBufferSize(size int) Option {
+	return func(b *bus) {
+		if size > 0 {
+			b.events = make(chan Event, size)
+		}
+	}
+}
+
+// WithSchedulerClusterID sets the scheduler cluster id of the events.
+func WithSchedulerClusterID(id uint) Option {
+	return func(b *bus) {
+		b.schedulerClusterID = id
+	}
+}
+
+// WithExporters sets the exporters of the events.
+func WithExporters(exporters ...Exporter) Option {
+	return func(b *bus) {
+		b.exporters = append(b.exporters, exporters...)
+	}
+}
+
+// New returns a new Bus interface.
+func New(options ...Option) Bus {
+	b := &bus{
+		events: make(chan Event, defaultBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	for _, opt := range options {
+		opt(b)
+	}
+
+	return b
+}
+
+// Publish publishes the event without blocking, the event is dropped if the buffer is full.
+func (b *bus) Publish(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	event.SchedulerClusterID = b.schedulerClusterID
+
+	select {
+	case b.events <- event:
+		metrics.EventCount.WithLabelValues(string(event.Type)).Inc()
+	default:
+		metrics.EventDroppedCount.WithLabelValues(string(event.Type)).Inc()
+		logger.Warnf("event buffer is full, drop %s event of task %s", event.Type, event.TaskID)
+	}
+}
+
+// Serve starts dispatching events to the exporters.
+func (b *bus) Serve() {
+	for {
+		select {
+		case event := <-b.events:
+			b.export(&event)
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// Stop stops dispatching events and closes the exporters.
+func (b *bus) Stop() {
+	close(b.done)
+	for _, exporter := range b.exporters {
+		if err := exporter.Close(); err != nil {
+			logger.Errorf("close %s exporter failed: %s", exporter.Name(), err.Error())
+		}
+	}
+}
+
+// export exports the event to every exporter.
+func (b *bus) export(event *Event) {
+	for _, exporter := range b.exporters {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultExportTimeout)
+		if err := exporter.Export(ctx, event); err != nil {
+			metrics.EventExportFailureCount.WithLabelValues(exporter.Name()).Inc()
+			logger.Errorf("export %s event of task %s to %s failed: %s", event.Type, event.TaskID, exporter.Name(), err.Error())
+		}
+		cancel()
+	}
+}
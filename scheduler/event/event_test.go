@@ -0,0 +1,215 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockExporter struct {
+	mu     sync.Mutex
+	name   string
+	err    error
+	events []Event
+	closed bool
+}
+
+func (m *mockExporter) Name() string {
+	return m.name
+}
+
+func (m *mockExporter) Export(ctx context.Context, event *Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, *event)
+	return m.err
+}
+
+func (m *mockExporter) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	return m.err
+}
+
+func (m *mockExporter) Events() []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Event{}, m.events...)
+}
+
+func TestBus_New(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		expect  func(t *testing.T, b *bus)
+	}{
+		{
+			name: "new bus with default options",
+			expect: func(t *testing.T, b *bus) {
+				assert := assert.New(t)
+				assert.Equal(cap(b.events), defaultBufferSize)
+				assert.Equal(b.schedulerClusterID, uint(0))
+				assert.Empty(b.exporters)
+			},
+		},
+		{
+			name: "new bus with options",
+			options: []Option{
+				WithBufferSize(10),
+				WithSchedulerClusterID(1),
+				WithExporters(&mockExporter{name: "foo"}, &mockExporter{name: "bar"}),
+			},
+			expect: func(t *testing.T, b *bus) {
+				assert := assert.New(t)
+				assert.Equal(cap(b.events), 10)
+				assert.Equal(b.schedulerClusterID, uint(1))
+				assert.Len(b.exporters, 2)
+			},
+		},
+		{
+			name:    "new bus with invalid buffer size",
+			options: []Option{WithBufferSize(0)},
+			expect: func(t *testing.T, b *bus) {
+				assert := assert.New(t)
+				assert.Equal(cap(b.events), defaultBufferSize)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, New(tc.options...).(*bus))
+		})
+	}
+}
+
+func TestBus_Publish(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		events []Event
+		expect func(t *testing.T, b *bus)
+	}{
+		{
+			name:   "publish event",
+			size:   1,
+			events: []Event{{Type: TypeTaskCreated, TaskID: "foo"}},
+			expect: func(t *testing.T, b *bus) {
+				assert := assert.New(t)
+				assert.Len(b.events, 1)
+
+				event := <-b.events
+				assert.Equal(event.Type, TypeTaskCreated)
+				assert.Equal(event.TaskID, "foo")
+				assert.Equal(event.SchedulerClusterID, uint(1))
+				assert.False(event.Timestamp.IsZero())
+			},
+		},
+		{
+			name:   "publish event with timestamp",
+			size:   1,
+			events: []Event{{Type: TypePeerJoined, Timestamp: time.Unix(1, 0)}},
+			expect: func(t *testing.T, b *bus) {
+				assert := assert.New(t)
+				event := <-b.events
+				assert.Equal(event.Timestamp, time.Unix(1, 0))
+			},
+		},
+		{
+			name:   "drop event when buffer is full",
+			size:   1,
+			events: []Event{{Type: TypeTaskCreated, TaskID: "foo"}, {Type: TypeTaskFailed, TaskID: "bar"}},
+			expect: func(t *testing.T, b *bus) {
+				assert := assert.New(t)
+				assert.Len(b.events, 1)
+
+				event := <-b.events
+				assert.Equal(event.TaskID, "foo")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := New(WithBufferSize(tc.size), WithSchedulerClusterID(1)).(*bus)
+			for _, event := range tc.events {
+				b.Publish(event)
+			}
+
+			tc.expect(t, b)
+		})
+	}
+}
+
+func TestBus_Serve(t *testing.T) {
+	tests := []struct {
+		name      string
+		exporters []*mockExporter
+		expect    func(t *testing.T, exporters []*mockExporter)
+	}{
+		{
+			name:      "export events",
+			exporters: []*mockExporter{{name: "foo"}},
+			expect: func(t *testing.T, exporters []*mockExporter) {
+				assert := assert.New(t)
+				assert.Eventually(func() bool { return len(exporters[0].Events()) == 2 }, time.Second, 10*time.Millisecond)
+				assert.Equal(exporters[0].Events()[0].Type, TypeTaskCreated)
+				assert.Equal(exporters[0].Events()[1].Type, TypeParentSwitched)
+			},
+		},
+		{
+			name:      "export events to the other exporters when an exporter failed",
+			exporters: []*mockExporter{{name: "foo", err: errors.New("foo")}, {name: "bar"}},
+			expect: func(t *testing.T, exporters []*mockExporter) {
+				assert := assert.New(t)
+				assert.Eventually(func() bool { return len(exporters[1].Events()) == 2 }, time.Second, 10*time.Millisecond)
+				assert.Len(exporters[0].Events(), 2)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			exporters := []Exporter{}
+			for _, exporter := range tc.exporters {
+				exporters = append(exporters, exporter)
+			}
+
+			b := New(WithExporters(exporters...))
+			go b.Serve()
+
+			b.Publish(Event{Type: TypeTaskCreated, TaskID: "foo"})
+			b.Publish(Event{Type: TypeParentSwitched, TaskID: "foo", ParentIDs: []string{"bar"}, PreviousParentIDs: []string{"baz"}})
+			tc.expect(t, tc.exporters)
+
+			b.Stop()
+			for _, exporter := range tc.exporters {
+				assert.True(t, exporter.closed)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaExporter exports the events to the kafka topic.
+type kafkaExporter struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaExporter returns a new exporter which writes the events to the kafka topic,
+// the events of the same task are written to the same partition.
+func NewKafkaExporter(brokers []string, topic string) Exporter {
+	return &kafkaExporter{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+// Name returns the name of the exporter.
+func (k *kafkaExporter) Name() string {
+	return "kafka"
+}
+
+// Export writes the event to the kafka topic.
+func (k *kafkaExporter) Export(ctx context.Context, event *Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return k.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.TaskID),
+		Value: value,
+	})
+}
+
+// Close flushes the pending events and closes the writer.
+func (k *kafkaExporter) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,65 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsExporter exports the events to the nats subjects.
+type natsExporter struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATSExporter returns a new exporter which publishes the events to the nats subject,
+// the subject of the event is the subject prefix joined with the event type, like: dragonfly.scheduler.events.task_created.
+func NewNATSExporter(url, subject string) (Exporter, error) {
+	conn, err := nats.Connect(url, nats.Name("dragonfly-scheduler"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &natsExporter{
+		conn:    conn,
+		subject: subject,
+	}, nil
+}
+
+// Name returns the name of the exporter.
+func (n *natsExporter) Name() string {
+	return "nats"
+}
+
+// Export publishes the event to the nats subject.
+func (n *natsExporter) Export(ctx context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return n.conn.Publish(fmt.Sprintf("%s.%s", n.subject, event.Type), data)
+}
+
+// Close flushes the pending events and closes the connection.
+func (n *natsExporter) Close() error {
+	return n.conn.Drain()
+}
@@ -0,0 +1,244 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/inference_mock.go -source inference.go -package mocks
+
+package inference
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	inferencev1 "d7y.io/api/v2/pkg/apis/inference/v1"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	inferenceclient "d7y.io/dragonfly/v2/pkg/rpc/inference/client"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+const (
+	// datatypeFP32 is the datatype of float tensors.
+	datatypeFP32 = "FP32"
+
+	// datatypeINT64 is the datatype of integer tensors.
+	datatypeINT64 = "INT64"
+
+	// loadModelTimeout is the timeout of loading model.
+	loadModelTimeout = 1 * time.Minute
+)
+
+// Graph is the host graph scored by the GNN model, the layout of tensors is defined in pkg/types.
+type Graph struct {
+	// NodeFeatures is the features of nodes, each has types.GNNNodeFeatureCount features.
+	NodeFeatures [][]float32
+
+	// Edges is the source and destination node indexes of edges.
+	Edges [][2]int64
+
+	// EdgeFeatures is the features of edges, each has types.GNNEdgeFeatureCount features.
+	EdgeFeatures [][]float32
+}
+
+// Inference is the interface used for scoring by the trained GNN model, the model is served by
+// the triton inference server.
+type Inference interface {
+	// Infer returns the score of each edge of the graph by the model.
+	Infer(context.Context, *Graph) ([]float32, error)
+
+	// Serve loads the model and refreshes the model periodically.
+	Serve()
+
+	// Stop stops refreshing the model.
+	Stop()
+}
+
+// inference implements Inference.
+type inference struct {
+	// config is the inference configuration.
+	config *config.InferenceConfig
+
+	// client is the grpc client of inference server.
+	client inferenceclient.V1
+
+	// modelName is the name of model in the inference server.
+	modelName string
+
+	// done is the channel of stopping inference.
+	done chan struct{}
+}
+
+// New returns a new Inference interface.
+func New(cfg *config.InferenceConfig, client inferenceclient.V1, modelName string) Inference {
+	return &inference{
+		config:    cfg,
+		client:    client,
+		modelName: modelName,
+		done:      make(chan struct{}),
+	}
+}
+
+// Infer returns the score of each edge of the graph by the model.
+func (i *inference) Infer(ctx context.Context, graph *Graph) ([]float32, error) {
+	if len(graph.Edges) == 0 {
+		return []float32{}, nil
+	}
+
+	if err := graph.validate(); err != nil {
+		return nil, err
+	}
+
+	nodeFeatures, err := flatten(graph.NodeFeatures, types.GNNNodeFeatureCount)
+	if err != nil {
+		return nil, err
+	}
+
+	edgeFeatures, err := flatten(graph.EdgeFeatures, types.GNNEdgeFeatureCount)
+	if err != nil {
+		return nil, err
+	}
+
+	edgeIndex := make([]int64, 2*len(graph.Edges))
+	for n, edge := range graph.Edges {
+		edgeIndex[n], edgeIndex[len(graph.Edges)+n] = edge[0], edge[1]
+	}
+
+	resp, err := i.client.ModelInfer(ctx, &inferencev1.ModelInferRequest{
+		ModelName: i.modelName,
+		Inputs: []*inferencev1.ModelInferRequest_InferInputTensor{
+			{
+				Name:     types.GNNNodeFeaturesName,
+				Datatype: datatypeFP32,
+				Shape:    []int64{int64(len(graph.NodeFeatures)), types.GNNNodeFeatureCount},
+				Contents: &inferencev1.InferTensorContents{Fp32Contents: nodeFeatures},
+			},
+			{
+				Name:     types.GNNEdgeIndexName,
+				Datatype: datatypeINT64,
+				Shape:    []int64{2, int64(len(graph.Edges))},
+				Contents: &inferencev1.InferTensorContents{Int64Contents: edgeIndex},
+			},
+			{
+				Name:     types.GNNEdgeFeaturesName,
+				Datatype: datatypeFP32,
+				Shape:    []int64{int64(len(graph.EdgeFeatures)), types.GNNEdgeFeatureCount},
+				Contents: &inferencev1.InferTensorContents{Fp32Contents: edgeFeatures},
+			},
+		},
+		Outputs: []*inferencev1.ModelInferRequest_InferRequestedOutputTensor{{Name: types.GNNScoresName}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for n, output := range resp.GetOutputs() {
+		if output.GetName() != types.GNNScoresName {
+			continue
+		}
+
+		// Triton returns the outputs in raw contents by default.
+		scores := output.GetContents().GetFp32Contents()
+		if len(scores) == 0 && n < len(resp.GetRawOutputContents()) {
+			scores = decodeFP32(resp.GetRawOutputContents()[n])
+		}
+
+		if len(scores) != len(graph.Edges) {
+			return nil, fmt.Errorf("invalid score count %d, expected %d", len(scores), len(graph.Edges))
+		}
+
+		return scores, nil
+	}
+
+	return nil, fmt.Errorf("output %s not found", types.GNNScoresName)
+}
+
+// Serve loads the model and refreshes the model periodically.
+func (i *inference) Serve() {
+	i.loadModel()
+
+	tick := time.NewTicker(i.config.RefreshModelInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			i.loadModel()
+		case <-i.done:
+			return
+		}
+	}
+}
+
+// Stop stops refreshing the model.
+func (i *inference) Stop() {
+	close(i.done)
+}
+
+// loadModel loads the latest version of model in the inference server.
+func (i *inference) loadModel() {
+	ctx, cancel := context.WithTimeout(context.Background(), loadModelTimeout)
+	defer cancel()
+
+	if _, err := i.client.RepositoryModelLoad(ctx, &inferencev1.RepositoryModelLoadRequest{ModelName: i.modelName}); err != nil {
+		logger.Errorf("load model %s failed: %s", i.modelName, err.Error())
+		return
+	}
+
+	logger.Infof("load model %s successfully", i.modelName)
+}
+
+// validate checks the edges and features of graph.
+func (g *Graph) validate() error {
+	if len(g.EdgeFeatures) != len(g.Edges) {
+		return fmt.Errorf("invalid edge features count %d, expected %d", len(g.EdgeFeatures), len(g.Edges))
+	}
+
+	for _, edge := range g.Edges {
+		for _, node := range edge {
+			if node < 0 || node >= int64(len(g.NodeFeatures)) {
+				return fmt.Errorf("invalid node index %d, node count is %d", node, len(g.NodeFeatures))
+			}
+		}
+	}
+
+	return nil
+}
+
+// flatten flattens the features in row major order.
+func flatten(features [][]float32, featureCount int) ([]float32, error) {
+	data := make([]float32, 0, len(features)*featureCount)
+	for _, feature := range features {
+		if len(feature) != featureCount {
+			return nil, fmt.Errorf("invalid feature count %d, expected %d", len(feature), featureCount)
+		}
+
+		data = append(data, feature...)
+	}
+
+	return data, nil
+}
+
+// decodeFP32 decodes the little endian raw contents of FP32 tensor.
+func decodeFP32(raw []byte) []float32 {
+	data := make([]float32, 0, len(raw)/4)
+	for n := 0; n+4 <= len(raw); n += 4 {
+		data = append(data, math.Float32frombits(binary.LittleEndian.Uint32(raw[n:])))
+	}
+
+	return data
+}
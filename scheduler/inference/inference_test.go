@@ -0,0 +1,221 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inference
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	inferencev1 "d7y.io/api/v2/pkg/apis/inference/v1"
+
+	"d7y.io/dragonfly/v2/pkg/rpc/inference/client/mocks"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+var mockGraph = &Graph{
+	NodeFeatures: [][]float32{{1, 2, 3, 4, 5, 6}, {7, 8, 9, 10, 11, 12}},
+	Edges:        [][2]int64{{1, 0}, {0, 1}},
+	EdgeFeatures: [][]float32{{1, 2, 3, 4}, {5, 6, 7, 8}},
+}
+
+func TestInference_Infer(t *testing.T) {
+	tests := []struct {
+		name   string
+		graph  *Graph
+		mock   func(mv *mocks.MockV1MockRecorder)
+		expect func(t *testing.T, scores []float32, err error)
+	}{
+		{
+			name:  "infer scores",
+			graph: mockGraph,
+			mock: func(mv *mocks.MockV1MockRecorder) {
+				mv.ModelInfer(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req *inferencev1.ModelInferRequest, opts ...any) (*inferencev1.ModelInferResponse, error) {
+					assert := assert.New(t)
+					assert.Equal("foo", req.ModelName)
+					assert.Len(req.Inputs, 3)
+					assert.Equal(types.GNNNodeFeaturesName, req.Inputs[0].Name)
+					assert.Equal([]int64{2, types.GNNNodeFeatureCount}, req.Inputs[0].Shape)
+					assert.Equal([]float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, req.Inputs[0].Contents.Fp32Contents)
+					assert.Equal(types.GNNEdgeIndexName, req.Inputs[1].Name)
+					assert.Equal([]int64{2, 2}, req.Inputs[1].Shape)
+					assert.Equal([]int64{1, 0, 0, 1}, req.Inputs[1].Contents.Int64Contents)
+					assert.Equal(types.GNNEdgeFeaturesName, req.Inputs[2].Name)
+					assert.Equal([]int64{2, types.GNNEdgeFeatureCount}, req.Inputs[2].Shape)
+					assert.Equal([]float32{1, 2, 3, 4, 5, 6, 7, 8}, req.Inputs[2].Contents.Fp32Contents)
+
+					return &inferencev1.ModelInferResponse{
+						Outputs: []*inferencev1.ModelInferResponse_InferOutputTensor{{
+							Name:     types.GNNScoresName,
+							Contents: &inferencev1.InferTensorContents{Fp32Contents: []float32{0.1, 0.2}},
+						}},
+					}, nil
+				}).Times(1)
+			},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal([]float32{0.1, 0.2}, scores)
+			},
+		},
+		{
+			name:  "infer scores in raw output contents",
+			graph: mockGraph,
+			mock: func(mv *mocks.MockV1MockRecorder) {
+				raw := make([]byte, 8)
+				binary.LittleEndian.PutUint32(raw, math.Float32bits(0.1))
+				binary.LittleEndian.PutUint32(raw[4:], math.Float32bits(0.2))
+				mv.ModelInfer(gomock.Any(), gomock.Any()).Return(&inferencev1.ModelInferResponse{
+					Outputs:           []*inferencev1.ModelInferResponse_InferOutputTensor{{Name: types.GNNScoresName}},
+					RawOutputContents: [][]byte{raw},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal([]float32{0.1, 0.2}, scores)
+			},
+		},
+		{
+			name:  "graph has no edges",
+			graph: &Graph{},
+			mock:  func(mv *mocks.MockV1MockRecorder) {},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Empty(scores)
+			},
+		},
+		{
+			name: "node feature count is invalid",
+			graph: &Graph{
+				NodeFeatures: [][]float32{{1}},
+				Edges:        [][2]int64{{0, 0}},
+				EdgeFeatures: [][]float32{{1, 2, 3, 4}},
+			},
+			mock: func(mv *mocks.MockV1MockRecorder) {},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert.EqualError(t, err, "invalid feature count 1, expected 6")
+			},
+		},
+		{
+			name: "node index is invalid",
+			graph: &Graph{
+				NodeFeatures: [][]float32{{1, 2, 3, 4, 5, 6}},
+				Edges:        [][2]int64{{1, 0}},
+				EdgeFeatures: [][]float32{{1, 2, 3, 4}},
+			},
+			mock: func(mv *mocks.MockV1MockRecorder) {},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert.EqualError(t, err, "invalid node index 1, node count is 1")
+			},
+		},
+		{
+			name: "edge features count is invalid",
+			graph: &Graph{
+				NodeFeatures: [][]float32{{1, 2, 3, 4, 5, 6}},
+				Edges:        [][2]int64{{0, 0}},
+			},
+			mock: func(mv *mocks.MockV1MockRecorder) {},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert.EqualError(t, err, "invalid edge features count 0, expected 1")
+			},
+		},
+		{
+			name:  "inference server responses error",
+			graph: mockGraph,
+			mock: func(mv *mocks.MockV1MockRecorder) {
+				mv.ModelInfer(gomock.Any(), gomock.Any()).Return(nil, errors.New("bar")).Times(1)
+			},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert.EqualError(t, err, "bar")
+			},
+		},
+		{
+			name:  "output is not found",
+			graph: mockGraph,
+			mock: func(mv *mocks.MockV1MockRecorder) {
+				mv.ModelInfer(gomock.Any(), gomock.Any()).Return(&inferencev1.ModelInferResponse{}, nil).Times(1)
+			},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert.EqualError(t, err, "output edge_scores not found")
+			},
+		},
+		{
+			name:  "score count is invalid",
+			graph: mockGraph,
+			mock: func(mv *mocks.MockV1MockRecorder) {
+				mv.ModelInfer(gomock.Any(), gomock.Any()).Return(&inferencev1.ModelInferResponse{
+					Outputs: []*inferencev1.ModelInferResponse_InferOutputTensor{{
+						Name:     types.GNNScoresName,
+						Contents: &inferencev1.InferTensorContents{Fp32Contents: []float32{0.1}},
+					}},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, scores []float32, err error) {
+				assert.EqualError(t, err, "invalid score count 1, expected 2")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			client := mocks.NewMockV1(ctl)
+			tc.mock(client.EXPECT())
+
+			i := New(&config.InferenceConfig{}, client, "foo")
+			scores, err := i.Infer(context.Background(), tc.graph)
+			tc.expect(t, scores, err)
+		})
+	}
+}
+
+func TestInference_Serve(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	client := mocks.NewMockV1(ctl)
+
+	loaded := make(chan struct{}, 1)
+	client.EXPECT().RepositoryModelLoad(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, req *inferencev1.RepositoryModelLoadRequest, opts ...any) (*inferencev1.RepositoryModelLoadResponse, error) {
+		assert.Equal(t, "foo", req.ModelName)
+		select {
+		case loaded <- struct{}{}:
+		default:
+		}
+
+		return &inferencev1.RepositoryModelLoadResponse{}, nil
+	}).MinTimes(1)
+
+	i := New(&config.InferenceConfig{RefreshModelInterval: time.Hour}, client, "foo")
+	go i.Serve()
+	defer i.Stop()
+
+	select {
+	case <-loaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("model is not loaded")
+	}
+}
@@ -0,0 +1,75 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: inference.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	inference "d7y.io/dragonfly/v2/scheduler/inference"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockInference is a mock of Inference interface.
+type MockInference struct {
+	ctrl     *gomock.Controller
+	recorder *MockInferenceMockRecorder
+}
+
+// MockInferenceMockRecorder is the mock recorder for MockInference.
+type MockInferenceMockRecorder struct {
+	mock *MockInference
+}
+
+// NewMockInference creates a new mock instance.
+func NewMockInference(ctrl *gomock.Controller) *MockInference {
+	mock := &MockInference{ctrl: ctrl}
+	mock.recorder = &MockInferenceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInference) EXPECT() *MockInferenceMockRecorder {
+	return m.recorder
+}
+
+// Infer mocks base method.
+func (m *MockInference) Infer(arg0 context.Context, arg1 *inference.Graph) ([]float32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Infer", arg0, arg1)
+	ret0, _ := ret[0].([]float32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Infer indicates an expected call of Infer.
+func (mr *MockInferenceMockRecorder) Infer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Infer", reflect.TypeOf((*MockInference)(nil).Infer), arg0, arg1)
+}
+
+// Serve mocks base method.
+func (m *MockInference) Serve() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Serve")
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockInferenceMockRecorder) Serve() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockInference)(nil).Serve))
+}
+
+// Stop mocks base method.
+func (m *MockInference) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockInferenceMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockInference)(nil).Stop))
+}
@@ -21,27 +21,43 @@ package job
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/RichardKnop/machinery/v1"
 	"github.com/go-http-utils/headers"
 	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
 
 	cdnsystemv1 "d7y.io/api/v2/pkg/apis/cdnsystem/v1"
 	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	dfdaemonv1 "d7y.io/api/v2/pkg/apis/dfdaemon/v1"
 
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	internaljob "d7y.io/dragonfly/v2/internal/job"
+	"d7y.io/dragonfly/v2/pkg/container/set"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
 	"d7y.io/dragonfly/v2/scheduler/config"
 	"d7y.io/dragonfly/v2/scheduler/resource"
+	"d7y.io/dragonfly/v2/scheduler/scheduling"
 )
 
 const (
 	// preheatTimeout is timeout of preheating.
 	preheatTimeout = 20 * time.Minute
+
+	// drainHostTimeout is timeout of draining host.
+	drainHostTimeout = 10 * time.Minute
+
+	// deleteTaskTimeout is timeout of deleting task.
+	deleteTaskTimeout = 5 * time.Minute
 )
 
 // Job is an interface for job.
@@ -55,11 +71,13 @@ type job struct {
 	schedulerJob *internaljob.Job
 	localJob     *internaljob.Job
 	resource     resource.Resource
+	scheduling   scheduling.Scheduling
 	config       *config.Config
+	dialOptions  []grpc.DialOption
 }
 
 // New creates a new Job.
-func New(cfg *config.Config, resource resource.Resource) (Job, error) {
+func New(cfg *config.Config, resource resource.Resource, scheduling scheduling.Scheduling, dialOptions ...grpc.DialOption) (Job, error) {
 	redisConfig := &internaljob.Config{
 		Addrs:      cfg.Database.Redis.Addrs,
 		MasterName: cfg.Database.Redis.MasterName,
@@ -101,12 +119,19 @@ func New(cfg *config.Config, resource resource.Resource) (Job, error) {
 		schedulerJob: schedulerJob,
 		localJob:     localJob,
 		resource:     resource,
+		scheduling:   scheduling,
 		config:       cfg,
+		dialOptions:  dialOptions,
 	}
 
 	namedJobFuncs := map[string]any{
-		internaljob.PreheatJob:   t.preheat,
-		internaljob.SyncPeersJob: t.syncPeers,
+		internaljob.PreheatJob:        t.preheat,
+		internaljob.SyncPeersJob:      t.syncPeers,
+		internaljob.DrainHostJob:      t.drainHost,
+		internaljob.ReannounceHostJob: t.reannounceHost,
+		internaljob.HostReputationJob: t.hostReputation,
+		internaljob.DeleteTaskJob:     t.deleteTask,
+		internaljob.GetTaskJob:        t.getTask,
 	}
 
 	if err := localJob.RegisterJob(namedJobFuncs); err != nil {
@@ -148,23 +173,23 @@ func (j *job) Serve() {
 }
 
 // preheat is a job to preheat.
-func (j *job) preheat(ctx context.Context, req string) error {
+func (j *job) preheat(ctx context.Context, req string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, preheatTimeout)
 	defer cancel()
 
 	if !j.config.SeedPeer.Enable {
-		return errors.New("scheduler has disabled seed peer")
+		return "", errors.New("scheduler has disabled seed peer")
 	}
 
 	preheat := &internaljob.PreheatRequest{}
 	if err := internaljob.UnmarshalRequest(req, preheat); err != nil {
 		logger.Errorf("unmarshal request err: %s, request body: %s", err.Error(), req)
-		return err
+		return "", err
 	}
 
 	if err := validator.New().Struct(preheat); err != nil {
 		logger.Errorf("preheat %s validate failed: %s", preheat.URL, err.Error())
-		return err
+		return "", err
 	}
 
 	urlMeta := &commonv1.UrlMeta{
@@ -187,6 +212,8 @@ func (j *job) preheat(ctx context.Context, req string) error {
 	log := logger.WithTask(taskID, preheat.URL)
 	log.Infof("preheat %s headers: %#v, tag: %s, range: %s, filter: %s, digest: %s",
 		preheat.URL, urlMeta.Header, urlMeta.Tag, urlMeta.Range, urlMeta.Filter, urlMeta.Digest)
+	// Preheat traffic can be shed by seed peer under load.
+	ctx = rpc.ContextWithPriority(ctx, rpc.PreheatPriority)
 	stream, err := j.resource.SeedPeer().Client().ObtainSeeds(ctx, &cdnsystemv1.SeedRequest{
 		TaskId:  taskID,
 		Url:     preheat.URL,
@@ -194,19 +221,33 @@ func (j *job) preheat(ctx context.Context, req string) error {
 	})
 	if err != nil {
 		log.Errorf("preheat %s failed: %s", preheat.URL, err.Error())
-		return err
+		return "", fmt.Errorf("preheat %s failed: %w", preheat.URL, err)
 	}
 
+	resp := &internaljob.PreheatResponse{
+		URL:    preheat.URL,
+		TaskID: taskID,
+	}
 	for {
 		piece, err := stream.Recv()
 		if err != nil {
 			log.Errorf("preheat %s recive piece failed: %s", preheat.URL, err.Error())
-			return err
+			return "", fmt.Errorf("preheat %s by seed peer %s failed: %w", preheat.URL, resp.SeedPeerHostID, err)
+		}
+
+		// Record which seed peer serves the task, the manager reports
+		// per seed peer progress by the response.
+		resp.SeedPeerHostID = piece.HostId
+		resp.SeedPeerID = piece.PeerId
+		if piece.PieceInfo != nil && piece.PieceInfo.PieceNum >= 0 {
+			resp.FinishedPieceCount++
 		}
 
 		if piece.Done == true {
-			log.Infof("preheat %s succeeded", preheat.URL)
-			return nil
+			resp.ContentLength = piece.ContentLength
+			resp.TotalPieceCount = piece.TotalPieceCount
+			log.Infof("preheat %s succeeded by seed peer %s", preheat.URL, resp.SeedPeerHostID)
+			return internaljob.MarshalResponse(resp)
 		}
 	}
 }
@@ -227,3 +268,309 @@ func (j *job) syncPeers() (string, error) {
 
 	return internaljob.MarshalResponse(hosts)
 }
+
+// drainHost is a job to drain host for maintenance, the peers of the draining host
+// are not scheduled as parents, and the children of them migrate to other parents.
+func (j *job) drainHost(ctx context.Context, req string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, drainHostTimeout)
+	defer cancel()
+
+	drainHost := &internaljob.DrainHostRequest{}
+	if err := internaljob.UnmarshalRequest(req, drainHost); err != nil {
+		logger.Errorf("unmarshal request err: %s, request body: %s", err.Error(), req)
+		return "", err
+	}
+
+	if err := validator.New().Struct(drainHost); err != nil {
+		logger.Errorf("drain host %s validate failed: %s", drainHost.HostID, err.Error())
+		return "", err
+	}
+
+	// The host may be announced to other schedulers in the cluster,
+	// so it is not an error if the host is not found.
+	host, loaded := j.resource.HostManager().Load(drainHost.HostID)
+	if !loaded {
+		logger.Infof("drain host %s is not found", drainHost.HostID)
+		return internaljob.MarshalResponse(&internaljob.DrainHostResponse{HostID: drainHost.HostID})
+	}
+
+	if drainHost.Cancel {
+		host.Log.Info("cancel draining host")
+		host.Draining.Store(false)
+		return internaljob.MarshalResponse(&internaljob.DrainHostResponse{HostID: host.ID, ChildCount: host.ChildCount()})
+	}
+
+	host.Log.Info("drain host")
+	host.Draining.Store(true)
+	j.migrateChildren(ctx, host)
+
+	return internaljob.MarshalResponse(&internaljob.DrainHostResponse{
+		HostID:     host.ID,
+		Draining:   true,
+		ChildCount: host.ChildCount(),
+	})
+}
+
+// migrateChildren reschedules the children of the peers in host to other parents.
+func (j *job) migrateChildren(ctx context.Context, host *resource.Host) {
+	var wg sync.WaitGroup
+	host.Peers.Range(func(_, value any) bool {
+		peer, ok := value.(*resource.Peer)
+		if !ok {
+			host.Log.Error("invalid peer")
+			return true
+		}
+
+		for _, child := range peer.Children() {
+			blocklist := set.NewSafeSet[string]()
+			blocklist.Add(peer.ID)
+
+			wg.Add(1)
+			go func(child *resource.Peer) {
+				defer wg.Done()
+
+				child.Log.Infof("migrate from parent %s, because of host %s is draining", peer.ID, host.ID)
+				if _, loaded := child.LoadAnnouncePeerStream(); loaded {
+					if err := j.scheduling.ScheduleCandidateParents(ctx, child, blocklist); err != nil {
+						child.Log.Error(err)
+					}
+
+					return
+				}
+
+				j.scheduling.ScheduleParentAndCandidateParents(ctx, child, blocklist)
+			}(child)
+		}
+
+		return true
+	})
+
+	wg.Wait()
+}
+
+// reannounceHost is a job to force the host to reannounce, the peers of the host leave
+// and the host is evicted, then the host is added again by the next announcement.
+func (j *job) reannounceHost(ctx context.Context, req string) (string, error) {
+	reannounceHost := &internaljob.ReannounceHostRequest{}
+	if err := internaljob.UnmarshalRequest(req, reannounceHost); err != nil {
+		logger.Errorf("unmarshal request err: %s, request body: %s", err.Error(), req)
+		return "", err
+	}
+
+	if err := validator.New().Struct(reannounceHost); err != nil {
+		logger.Errorf("reannounce host %s validate failed: %s", reannounceHost.HostID, err.Error())
+		return "", err
+	}
+
+	// The host may be announced to other schedulers in the cluster,
+	// so it is not an error if the host is not found.
+	host, loaded := j.resource.HostManager().Load(reannounceHost.HostID)
+	if !loaded {
+		logger.Infof("reannounce host %s is not found", reannounceHost.HostID)
+		return internaljob.MarshalResponse(&internaljob.ReannounceHostResponse{HostID: reannounceHost.HostID})
+	}
+
+	host.Log.Info("evict host for reannouncement")
+	host.LeavePeers()
+	j.resource.HostManager().Delete(host.ID)
+
+	return internaljob.MarshalResponse(&internaljob.ReannounceHostResponse{
+		HostID:  host.ID,
+		Evicted: true,
+	})
+}
+
+// hostReputation is a job to get the reputation of the host as a parent,
+// and block or unblock the host from parent selection by the action.
+func (j *job) hostReputation(ctx context.Context, req string) (string, error) {
+	hostReputation := &internaljob.HostReputationRequest{}
+	if err := internaljob.UnmarshalRequest(req, hostReputation); err != nil {
+		logger.Errorf("unmarshal request err: %s, request body: %s", err.Error(), req)
+		return "", err
+	}
+
+	if err := validator.New().Struct(hostReputation); err != nil {
+		logger.Errorf("host reputation %s validate failed: %s", hostReputation.HostID, err.Error())
+		return "", err
+	}
+
+	// The host may be announced to other schedulers in the cluster,
+	// so it is not an error if the host is not found.
+	host, loaded := j.resource.HostManager().Load(hostReputation.HostID)
+	if !loaded {
+		logger.Infof("host reputation %s is not found", hostReputation.HostID)
+		return internaljob.MarshalResponse(&internaljob.HostReputationResponse{HostID: hostReputation.HostID})
+	}
+
+	switch hostReputation.Action {
+	case "block":
+		host.Log.Infof("block host for %s", j.config.Scheduler.Reputation.BlockDuration)
+		host.Reputation.Block(time.Now().Add(j.config.Scheduler.Reputation.BlockDuration))
+	case "unblock":
+		host.Log.Info("unblock host")
+		host.Reputation.Unblock()
+	}
+
+	pieces, failures, corruptions := host.Reputation.Counts()
+	return internaljob.MarshalResponse(&internaljob.HostReputationResponse{
+		HostID:       host.ID,
+		Score:        host.Reputation.Score(),
+		Pieces:       pieces,
+		Failures:     failures,
+		Corruptions:  corruptions,
+		Blocked:      host.Reputation.IsBlocked(),
+		BlockedUntil: host.Reputation.BlockedUntil(),
+	})
+}
+
+// deleteTask is a job to delete task, the peers of the task leave and the task deletion
+// is announced to the hosts holding the pieces, so the hosts delete them promptly
+// rather than waiting for local gc.
+func (j *job) deleteTask(ctx context.Context, req string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, deleteTaskTimeout)
+	defer cancel()
+
+	deleteTask := &internaljob.DeleteTaskRequest{}
+	if err := internaljob.UnmarshalRequest(req, deleteTask); err != nil {
+		logger.Errorf("unmarshal request err: %s, request body: %s", err.Error(), req)
+		return "", err
+	}
+
+	if err := validator.New().Struct(deleteTask); err != nil {
+		logger.Errorf("delete task %s validate failed: %s", deleteTask.TaskID, err.Error())
+		return "", err
+	}
+
+	// The task may not be downloaded by the peers of this scheduler,
+	// so it is not an error if the task is not found.
+	task, loaded := j.resource.TaskManager().Load(deleteTask.TaskID)
+	if !loaded {
+		logger.Infof("delete task %s is not found", deleteTask.TaskID)
+		return internaljob.MarshalResponse(&internaljob.DeleteTaskResponse{TaskID: deleteTask.TaskID})
+	}
+
+	// Peers of the task leave, so that they are not scheduled as parents.
+	hosts := make(map[string]*resource.Host)
+	for _, peer := range task.LoadRandomPeers(uint(task.PeerCount())) {
+		hosts[peer.Host.ID] = peer.Host
+		if peer.FSM.Is(resource.PeerStateLeave) {
+			continue
+		}
+
+		if err := peer.FSM.Event(ctx, resource.PeerEventLeave); err != nil {
+			peer.Log.Errorf("peer fsm event failed: %s", err.Error())
+		}
+	}
+
+	if !task.FSM.Is(resource.TaskStateLeave) {
+		if err := task.FSM.Event(ctx, resource.TaskEventLeave); err != nil {
+			task.Log.Errorf("task fsm event failed: %s", err.Error())
+		}
+	}
+
+	var (
+		resp = &internaljob.DeleteTaskResponse{TaskID: task.ID}
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+	)
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host *resource.Host) {
+			defer wg.Done()
+
+			err := j.announceTaskDeletion(ctx, task, host)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				host.Log.Errorf("announce task %s deletion failed: %s", task.ID, err.Error())
+				resp.FailedHostIDs = append(resp.FailedHostIDs, host.ID)
+				return
+			}
+
+			resp.SucceededHostIDs = append(resp.SucceededHostIDs, host.ID)
+		}(host)
+	}
+
+	wg.Wait()
+	task.Log.Infof("delete task in %d hosts, %d hosts failed", len(resp.SucceededHostIDs), len(resp.FailedHostIDs))
+	return internaljob.MarshalResponse(resp)
+}
+
+// getTask is a job to get the task and the peers which hold the task.
+func (j *job) getTask(ctx context.Context, req string) (string, error) {
+	getTask := &internaljob.GetTaskRequest{}
+	if err := internaljob.UnmarshalRequest(req, getTask); err != nil {
+		logger.Errorf("unmarshal request err: %s, request body: %s", err.Error(), req)
+		return "", err
+	}
+
+	if err := validator.New().Struct(getTask); err != nil {
+		logger.Errorf("get task %s validate failed: %s", getTask.TaskID, err.Error())
+		return "", err
+	}
+
+	resp := &internaljob.GetTaskResponse{
+		TaskID:             getTask.TaskID,
+		SchedulerHostname:  j.config.Server.Host,
+		SchedulerClusterID: j.config.Manager.SchedulerClusterID,
+		Peers:              []internaljob.TaskPeer{},
+	}
+
+	// The task may not be downloaded by the peers of this scheduler,
+	// so it is not an error if the task is not found.
+	task, loaded := j.resource.TaskManager().Load(getTask.TaskID)
+	if !loaded {
+		logger.Infof("get task %s is not found", getTask.TaskID)
+		return internaljob.MarshalResponse(resp)
+	}
+
+	resp.URL = task.URL
+	resp.State = task.FSM.Current()
+	resp.ContentLength = task.ContentLength.Load()
+	resp.TotalPieceCount = task.TotalPieceCount.Load()
+	for _, peer := range task.LoadRandomPeers(uint(task.PeerCount())) {
+		if peer.FSM.Is(resource.PeerStateLeave) {
+			continue
+		}
+
+		resp.Peers = append(resp.Peers, internaljob.TaskPeer{
+			ID:                 peer.ID,
+			State:              peer.FSM.Current(),
+			FinishedPieceCount: peer.FinishedPieces.Count(),
+			HostID:             peer.Host.ID,
+			HostType:           peer.Host.Type.Name(),
+			Hostname:           peer.Host.Hostname,
+			IP:                 peer.Host.IP,
+			Port:               peer.Host.Port,
+			Topology:           peer.Host.Topology,
+			CreatedAt:          peer.CreatedAt.Load(),
+			UpdatedAt:          peer.UpdatedAt.Load(),
+		})
+	}
+
+	if replication := task.Replication.Load(); replication.IsPersistent() {
+		resp.Replication = replication
+		resp.Placement = task.ReplicaPlacement()
+	}
+
+	task.Log.Infof("get task with %d peers", len(resp.Peers))
+	return internaljob.MarshalResponse(resp)
+}
+
+// announceTaskDeletion announces the task deletion to the host.
+func (j *job) announceTaskDeletion(ctx context.Context, task *resource.Task, host *resource.Host) error {
+	client, err := dfdaemonclient.GetV1(ctx, net.JoinHostPort(host.IP, strconv.Itoa(int(host.Port))), j.dialOptions...)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return client.DeleteTask(rpc.ContextWithTaskID(ctx, task.ID), &dfdaemonv1.DeleteTaskRequest{
+		Url: task.URL,
+		UrlMeta: &commonv1.UrlMeta{
+			Tag:         task.Tag,
+			Application: task.Application,
+		},
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 
+	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/config"
 	"d7y.io/dragonfly/v2/version"
@@ -238,6 +239,41 @@ var (
 		Help:      "Counter of the number of failed of the synchronizing probes.",
 	})
 
+	ParentReportPieceCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "parent_report_piece_total",
+		Help:      "Counter of the number of the pieces of the parents reported by the children.",
+	}, []string{"type"})
+
+	BlocklistHostCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "blocklist_host_total",
+		Help:      "Counter of the number of the hosts blocklisted from parent selection by reputation.",
+	}, []string{"reason", "host_type"})
+
+	HostReputation = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "host_reputation",
+		Help:      "Gauge of the reputation score of host as a parent.",
+	}, []string{"host_id", "host_ip", "host_name"})
+
+	URLPolicyDeniedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "url_policy_denied_total",
+		Help:      "Counter of the number of the tasks denied by the url policy.",
+	}, []string{"task_app"})
+
+	FlashCrowdCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "flash_crowd_total",
+		Help:      "Counter of the number of the flash crowd detected.",
+	}, []string{"task_type", "task_tag", "task_app"})
+
 	Traffic = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: types.MetricsNamespace,
 		Subsystem: types.SchedulerMetricsName,
@@ -267,6 +303,27 @@ var (
 		Help:      "Gauge of the number of concurrent of the scheduling.",
 	})
 
+	EventCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "event_total",
+		Help:      "Counter of the number of the published task lifecycle event.",
+	}, []string{"type"})
+
+	EventDroppedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "event_dropped_total",
+		Help:      "Counter of the number of dropped of the task lifecycle event.",
+	}, []string{"type"})
+
+	EventExportFailureCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: types.MetricsNamespace,
+		Subsystem: types.SchedulerMetricsName,
+		Name:      "event_export_failure_total",
+		Help:      "Counter of the number of failed of the exporting task lifecycle event.",
+	}, []string{"exporter"})
+
 	VersionGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: types.MetricsNamespace,
 		Subsystem: types.SchedulerMetricsName,
@@ -281,6 +338,9 @@ func New(cfg *config.MetricsConfig, svr *grpc.Server) *http.Server {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 
+	// Adjust the log level of every component at runtime.
+	mux.Handle("/admin/log/levels", logger.LevelHandler())
+
 	VersionGauge.WithLabelValues(version.Major, version.Minor, version.GitVersion, version.GitCommit, version.Platform, version.BuildTime, version.GoVersion, version.Gotags, version.Gogcflags).Set(1)
 	return &http.Server{
 		Addr:    cfg.Addr,
@@ -0,0 +1,60 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"fmt"
+
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+// LoadApplicationPolicy returns the download policy of the application from
+// the client config of the scheduler cluster.
+func LoadApplicationPolicy(dynconfig config.DynconfigInterface, name string) (rpc.ApplicationPolicy, bool) {
+	if name == "" {
+		return rpc.ApplicationPolicy{}, false
+	}
+
+	policies, err := dynconfig.GetApplicationPolicies()
+	if err != nil {
+		return rpc.ApplicationPolicy{}, false
+	}
+
+	policy, ok := policies[name]
+	return policy, ok
+}
+
+// CheckApplicationURL returns an error if the url is not allowed
+// by the download policy of the application.
+func CheckApplicationURL(dynconfig config.DynconfigInterface, name, url string) error {
+	policy, ok := LoadApplicationPolicy(dynconfig, name)
+	if !ok {
+		return nil
+	}
+
+	allowed, err := policy.AllowURL(url)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return fmt.Errorf("url %s is not allowed by application %s", url, name)
+	}
+
+	return nil
+}
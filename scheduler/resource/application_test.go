@@ -0,0 +1,107 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	configmocks "d7y.io/dragonfly/v2/scheduler/config/mocks"
+)
+
+func TestCheckApplicationURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		application string
+		url         string
+		mock        func(md *configmocks.MockDynconfigInterfaceMockRecorder)
+		expect      func(t *testing.T, err error)
+	}{
+		{
+			name:        "application is empty",
+			application: "",
+			url:         "https://example.com/foo",
+			mock:        func(md *configmocks.MockDynconfigInterfaceMockRecorder) {},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:        "get application policies failed",
+			application: "foo",
+			url:         "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, errors.New("bar")).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:        "application not found",
+			application: "foo",
+			url:         "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{"bar": {}}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:        "url is allowed",
+			application: "foo",
+			url:         "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{
+					"foo": {AllowedURLRegex: "^https://example.com/"},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:        "url is not allowed",
+			application: "foo",
+			url:         "https://example.org/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{
+					"foo": {AllowedURLRegex: "^https://example.com/"},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "url https://example.org/foo is not allowed by application foo")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+
+			tc.mock(dynconfig.EXPECT())
+			tc.expect(t, CheckApplicationURL(dynconfig, tc.application, tc.url))
+		})
+	}
+}
@@ -0,0 +1,277 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination eviction_mock.go -source eviction.go -package resource
+
+package resource
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+const (
+	// memoryPressureEvictionRatio is the ratio of peers evicted once
+	// when the heap memory exceeds the limit.
+	memoryPressureEvictionRatio = 0.1
+)
+
+const (
+	// stateWeight is the weight of peer state in retention score.
+	stateWeight float64 = 0.4
+
+	// childrenWeight is the weight of peer children in retention score.
+	childrenWeight = 0.3
+
+	// recencyWeight is the weight of peer recency in retention score.
+	recencyWeight = 0.3
+)
+
+// EvictionPolicy is the interface used for peer eviction policy.
+type EvictionPolicy interface {
+	// Name returns the name of policy.
+	Name() string
+
+	// Evict returns the peers to be evicted from the peers in scheduler.
+	Evict([]*Peer) []*Peer
+}
+
+// PolicyManager is the interface used for managing peer eviction policies.
+type PolicyManager interface {
+	// Register registers eviction policy, the policy with the same name will be replaced.
+	Register(EvictionPolicy)
+
+	// Load returns eviction policy by name.
+	Load(string) (EvictionPolicy, bool)
+
+	// Evict returns the peers to be evicted by the configured policy.
+	Evict([]*Peer) []*Peer
+}
+
+// policyManager contains content for policy manager.
+type policyManager struct {
+	// policy is the name of configured policy.
+	policy string
+
+	// policies is the registered policies.
+	policies map[string]EvictionPolicy
+
+	// mu protects policies.
+	mu sync.RWMutex
+}
+
+// newPolicyManager returns a new PolicyManager with the builtin policies.
+func newPolicyManager(cfg *config.PeerEvictionConfig) PolicyManager {
+	pm := &policyManager{
+		policy:   cfg.Policy,
+		policies: make(map[string]EvictionPolicy),
+	}
+
+	pm.Register(newLRUPolicy(cfg.PeerLimit))
+	pm.Register(newScorePolicy(cfg.PeerLimit))
+	pm.Register(newMemoryPolicy(uint64(cfg.MemoryLimit), heapAlloc))
+	return pm
+}
+
+// Register registers eviction policy, the policy with the same name will be replaced.
+func (pm *policyManager) Register(policy EvictionPolicy) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	pm.policies[policy.Name()] = policy
+}
+
+// Load returns eviction policy by name.
+func (pm *policyManager) Load(name string) (EvictionPolicy, bool) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	policy, ok := pm.policies[name]
+	return policy, ok
+}
+
+// Evict returns the peers to be evicted by the configured policy.
+func (pm *policyManager) Evict(peers []*Peer) []*Peer {
+	if pm.policy == "" {
+		return nil
+	}
+
+	policy, ok := pm.Load(pm.policy)
+	if !ok {
+		logger.Warnf("eviction policy %s is not registered", pm.policy)
+		return nil
+	}
+
+	return policy.Evict(peers)
+}
+
+// lruPolicy evicts the least recently used peers when the peers exceed the limit.
+type lruPolicy struct {
+	// limit is the limit count of peers.
+	limit int
+}
+
+// newLRUPolicy returns a new lru policy.
+func newLRUPolicy(limit int) EvictionPolicy {
+	return &lruPolicy{limit: limit}
+}
+
+// Name returns the name of policy.
+func (p *lruPolicy) Name() string {
+	return config.PeerEvictionPolicyLRU
+}
+
+// Evict returns the least recently used peers exceeding the limit.
+func (p *lruPolicy) Evict(peers []*Peer) []*Peer {
+	if p.limit <= 0 || len(peers) <= p.limit {
+		return nil
+	}
+
+	return leastRecentlyUsed(peers, len(peers)-p.limit)
+}
+
+// scorePolicy evicts the peers with the lowest retention score when the peers exceed the limit.
+type scorePolicy struct {
+	// limit is the limit count of peers.
+	limit int
+}
+
+// newScorePolicy returns a new score policy.
+func newScorePolicy(limit int) EvictionPolicy {
+	return &scorePolicy{limit: limit}
+}
+
+// Name returns the name of policy.
+func (p *scorePolicy) Name() string {
+	return config.PeerEvictionPolicyScore
+}
+
+// Evict returns the peers with the lowest retention score exceeding the limit.
+func (p *scorePolicy) Evict(peers []*Peer) []*Peer {
+	if p.limit <= 0 || len(peers) <= p.limit {
+		return nil
+	}
+
+	candidates := evictablePeers(peers)
+	scores := make(map[string]float64, len(candidates))
+	for _, peer := range candidates {
+		scores[peer.ID] = retentionScore(peer)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return scores[candidates[i].ID] < scores[candidates[j].ID]
+	})
+
+	return firstN(candidates, len(peers)-p.limit)
+}
+
+// memoryPolicy evicts the least recently used peers when the heap memory exceeds the limit.
+type memoryPolicy struct {
+	// limit is the limit of heap memory.
+	limit uint64
+
+	// heapAlloc returns the allocated heap memory.
+	heapAlloc func() uint64
+}
+
+// newMemoryPolicy returns a new memory policy.
+func newMemoryPolicy(limit uint64, heapAlloc func() uint64) EvictionPolicy {
+	return &memoryPolicy{limit: limit, heapAlloc: heapAlloc}
+}
+
+// Name returns the name of policy.
+func (p *memoryPolicy) Name() string {
+	return config.PeerEvictionPolicyMemory
+}
+
+// Evict returns a ratio of the least recently used peers when the heap memory exceeds the limit.
+func (p *memoryPolicy) Evict(peers []*Peer) []*Peer {
+	if p.limit == 0 || len(peers) == 0 {
+		return nil
+	}
+
+	alloc := p.heapAlloc()
+	if alloc <= p.limit {
+		return nil
+	}
+
+	logger.Infof("heap memory %d exceeds the limit %d, evict peers", alloc, p.limit)
+	return leastRecentlyUsed(peers, int(math.Ceil(float64(len(peers))*memoryPressureEvictionRatio)))
+}
+
+// evictablePeers returns the peers that have finished downloading,
+// the downloading peers are never evicted.
+func evictablePeers(peers []*Peer) []*Peer {
+	var candidates []*Peer
+	for _, peer := range peers {
+		if peer.FSM.Is(PeerStateSucceeded) || peer.FSM.Is(PeerStateFailed) {
+			candidates = append(candidates, peer)
+		}
+	}
+
+	return candidates
+}
+
+// leastRecentlyUsed returns the n least recently used evictable peers.
+func leastRecentlyUsed(peers []*Peer, n int) []*Peer {
+	candidates := evictablePeers(peers)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].UpdatedAt.Load().Before(candidates[j].UpdatedAt.Load())
+	})
+
+	return firstN(candidates, n)
+}
+
+// retentionScore returns the score of keeping peer, the succeeded peers
+// uploading to more children recently are preferred to retain.
+func retentionScore(peer *Peer) float64 {
+	var stateScore float64
+	if peer.FSM.Is(PeerStateSucceeded) {
+		stateScore = 1
+	}
+
+	children := float64(len(peer.Children()))
+	childrenScore := children / (children + 1)
+	recencyScore := 1 / (1 + time.Since(peer.UpdatedAt.Load()).Hours())
+
+	return stateScore*stateWeight + childrenScore*childrenWeight + recencyScore*recencyWeight
+}
+
+// firstN returns at most n peers from the head.
+func firstN(peers []*Peer, n int) []*Peer {
+	if n <= 0 {
+		return nil
+	}
+
+	if n > len(peers) {
+		n = len(peers)
+	}
+
+	return peers[:n]
+}
+
+// heapAlloc returns the allocated heap memory of process.
+func heapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: eviction.go
+
+// Package resource is a generated GoMock package.
+package resource
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEvictionPolicy is a mock of EvictionPolicy interface.
+type MockEvictionPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockEvictionPolicyMockRecorder
+}
+
+// MockEvictionPolicyMockRecorder is the mock recorder for MockEvictionPolicy.
+type MockEvictionPolicyMockRecorder struct {
+	mock *MockEvictionPolicy
+}
+
+// NewMockEvictionPolicy creates a new mock instance.
+func NewMockEvictionPolicy(ctrl *gomock.Controller) *MockEvictionPolicy {
+	mock := &MockEvictionPolicy{ctrl: ctrl}
+	mock.recorder = &MockEvictionPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvictionPolicy) EXPECT() *MockEvictionPolicyMockRecorder {
+	return m.recorder
+}
+
+// Evict mocks base method.
+func (m *MockEvictionPolicy) Evict(arg0 []*Peer) []*Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evict", arg0)
+	ret0, _ := ret[0].([]*Peer)
+	return ret0
+}
+
+// Evict indicates an expected call of Evict.
+func (mr *MockEvictionPolicyMockRecorder) Evict(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evict", reflect.TypeOf((*MockEvictionPolicy)(nil).Evict), arg0)
+}
+
+// Name mocks base method.
+func (m *MockEvictionPolicy) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockEvictionPolicyMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockEvictionPolicy)(nil).Name))
+}
+
+// MockPolicyManager is a mock of PolicyManager interface.
+type MockPolicyManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyManagerMockRecorder
+}
+
+// MockPolicyManagerMockRecorder is the mock recorder for MockPolicyManager.
+type MockPolicyManagerMockRecorder struct {
+	mock *MockPolicyManager
+}
+
+// NewMockPolicyManager creates a new mock instance.
+func NewMockPolicyManager(ctrl *gomock.Controller) *MockPolicyManager {
+	mock := &MockPolicyManager{ctrl: ctrl}
+	mock.recorder = &MockPolicyManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyManager) EXPECT() *MockPolicyManagerMockRecorder {
+	return m.recorder
+}
+
+// Evict mocks base method.
+func (m *MockPolicyManager) Evict(arg0 []*Peer) []*Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evict", arg0)
+	ret0, _ := ret[0].([]*Peer)
+	return ret0
+}
+
+// Evict indicates an expected call of Evict.
+func (mr *MockPolicyManagerMockRecorder) Evict(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evict", reflect.TypeOf((*MockPolicyManager)(nil).Evict), arg0)
+}
+
+// Load mocks base method.
+func (m *MockPolicyManager) Load(arg0 string) (EvictionPolicy, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", arg0)
+	ret0, _ := ret[0].(EvictionPolicy)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockPolicyManagerMockRecorder) Load(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockPolicyManager)(nil).Load), arg0)
+}
+
+// Register mocks base method.
+func (m *MockPolicyManager) Register(arg0 EvictionPolicy) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Register", arg0)
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockPolicyManagerMockRecorder) Register(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockPolicyManager)(nil).Register), arg0)
+}
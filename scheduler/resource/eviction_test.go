@@ -0,0 +1,220 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/pkg/gc"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+// newEvictionPeers returns peers with the given states, the former peer is less recently used.
+func newEvictionPeers(states ...string) []*Peer {
+	host := NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	task := NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+
+	var peers []*Peer
+	for i, state := range states {
+		peer := NewPeer(fmt.Sprintf("peer-%d", i), mockResourceConfig, task, host)
+		peer.FSM.SetState(state)
+		peer.UpdatedAt.Store(time.Now().Add(time.Duration(i-len(states)) * time.Minute))
+		peers = append(peers, peer)
+	}
+
+	return peers
+}
+
+func peerIDs(peers []*Peer) []string {
+	var ids []string
+	for _, peer := range peers {
+		ids = append(ids, peer.ID)
+	}
+
+	return ids
+}
+
+func TestEvictionPolicy_Evict(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy EvictionPolicy
+		states []string
+		expect func(t *testing.T, evicted []*Peer)
+	}{
+		{
+			name:   "lru policy evicts least recently used peers",
+			policy: newLRUPolicy(2),
+			states: []string{PeerStateSucceeded, PeerStateRunning, PeerStateFailed, PeerStateSucceeded},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Equal(t, []string{"peer-0", "peer-2"}, peerIDs(evicted))
+			},
+		},
+		{
+			name:   "lru policy does not exceed the limit",
+			policy: newLRUPolicy(4),
+			states: []string{PeerStateSucceeded, PeerStateSucceeded},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Empty(t, evicted)
+			},
+		},
+		{
+			name:   "lru policy never evicts downloading peers",
+			policy: newLRUPolicy(1),
+			states: []string{PeerStateRunning, PeerStateBackToSource, PeerStateSucceeded},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Equal(t, []string{"peer-2"}, peerIDs(evicted))
+			},
+		},
+		{
+			name:   "score policy evicts failed peers first",
+			policy: newScorePolicy(2),
+			states: []string{PeerStateSucceeded, PeerStateSucceeded, PeerStateFailed},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Equal(t, []string{"peer-2"}, peerIDs(evicted))
+			},
+		},
+		{
+			name:   "memory policy evicts peers when heap exceeds the limit",
+			policy: newMemoryPolicy(1, func() uint64 { return 2 }),
+			states: []string{PeerStateSucceeded, PeerStateSucceeded, PeerStateSucceeded},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Equal(t, []string{"peer-0"}, peerIDs(evicted))
+			},
+		},
+		{
+			name:   "memory policy does not evict peers when heap is under the limit",
+			policy: newMemoryPolicy(2, func() uint64 { return 1 }),
+			states: []string{PeerStateSucceeded, PeerStateSucceeded},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Empty(t, evicted)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, tc.policy.Evict(newEvictionPeers(tc.states...)))
+		})
+	}
+}
+
+func TestPolicyManager_Evict(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *config.PeerEvictionConfig
+		mock   func(policy *MockEvictionPolicyMockRecorder)
+		expect func(t *testing.T, evicted []*Peer)
+	}{
+		{
+			name:   "policy is empty",
+			config: &config.PeerEvictionConfig{PeerLimit: 1},
+			mock:   func(policy *MockEvictionPolicyMockRecorder) {},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Empty(t, evicted)
+			},
+		},
+		{
+			name:   "policy is not registered",
+			config: &config.PeerEvictionConfig{Policy: "bar"},
+			mock:   func(policy *MockEvictionPolicyMockRecorder) {},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Empty(t, evicted)
+			},
+		},
+		{
+			name:   "builtin policy",
+			config: &config.PeerEvictionConfig{Policy: config.PeerEvictionPolicyLRU, PeerLimit: 1},
+			mock:   func(policy *MockEvictionPolicyMockRecorder) {},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Equal(t, []string{"peer-0"}, peerIDs(evicted))
+			},
+		},
+		{
+			name:   "custom policy",
+			config: &config.PeerEvictionConfig{Policy: "foo"},
+			mock: func(policy *MockEvictionPolicyMockRecorder) {
+				policy.Name().Return("foo").Times(1)
+				policy.Evict(gomock.Len(2)).DoAndReturn(func(peers []*Peer) []*Peer {
+					return peers[1:]
+				}).Times(1)
+			},
+			expect: func(t *testing.T, evicted []*Peer) {
+				assert.Equal(t, []string{"peer-1"}, peerIDs(evicted))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			policy := NewMockEvictionPolicy(ctl)
+
+			pm := newPolicyManager(tc.config)
+			tc.mock(policy.EXPECT())
+			if _, ok := pm.Load(config.PeerEvictionPolicyLRU); !ok {
+				t.Fatal("lru policy is not registered")
+			}
+
+			if tc.config.Policy == "foo" {
+				pm.Register(policy)
+			}
+
+			tc.expect(t, pm.Evict(newEvictionPeers(PeerStateSucceeded, PeerStateSucceeded)))
+		})
+	}
+}
+
+func TestPeerManager_RunGC_Eviction(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	gc := gc.NewMockGC(ctl)
+	gc.EXPECT().Add(gomock.Any()).Return(nil).Times(1)
+
+	peerManager, err := newPeerManager(&config.GCConfig{
+		PeerGCInterval:       1 * time.Second,
+		PeerTTL:              1 * time.Hour,
+		HostTTL:              1 * time.Hour,
+		PieceDownloadTimeout: 1 * time.Hour,
+		PeerEviction: config.PeerEvictionConfig{
+			Policy:    config.PeerEvictionPolicyLRU,
+			PeerLimit: 1,
+		},
+	}, gc)
+	assert.NoError(t, err)
+
+	peers := newEvictionPeers(PeerStateSucceeded, PeerStateSucceeded)
+	for _, peer := range peers {
+		peerManager.Store(peer)
+	}
+
+	assert.NoError(t, peerManager.RunGC())
+	_, loaded := peerManager.Load(peers[0].ID)
+	assert.False(t, loaded)
+	assert.True(t, peers[0].FSM.Is(PeerStateLeave))
+	_, loaded = peerManager.Load(peers[1].ID)
+	assert.True(t, loaded)
+}
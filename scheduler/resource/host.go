@@ -52,6 +52,13 @@ func WithConcurrentUploadLimit(limit int32) HostOption {
 	}
 }
 
+// WithUploadRateLimit sets the bandwidth capacity of host's UploadThroughput by the upload rate limit.
+func WithUploadRateLimit(limit uint64) HostOption {
+	return func(h *Host) {
+		h.UploadThroughput.SetCapacity(float64(limit))
+	}
+}
+
 // WithOS sets host's os.
 func WithOS(os string) HostOption {
 	return func(h *Host) {
@@ -122,6 +129,13 @@ func WithBuild(build Build) HostOption {
 	}
 }
 
+// WithTopology sets host's topology labels.
+func WithTopology(topology types.Topology) HostOption {
+	return func(h *Host) {
+		h.Topology = topology
+	}
+}
+
 // Host contains content for host.
 type Host struct {
 	// ID is host id.
@@ -175,6 +189,9 @@ type Host struct {
 	// Build information.
 	Build Build
 
+	// Topology is the labels of failure domains.
+	Topology types.Topology
+
 	// SchedulerClusterID is the scheduler cluster id matched by scopes.
 	SchedulerClusterID uint64
 
@@ -190,12 +207,24 @@ type Host struct {
 	// UploadFailedCount is upload failed count.
 	UploadFailedCount *atomic.Int64
 
+	// UploadThroughput is the observed upload throughput,
+	// it is collected from the pieces downloaded by children.
+	UploadThroughput *Throughput
+
 	// Peer sync map.
 	Peers *sync.Map
 
 	// PeerCount is peer count.
 	PeerCount *atomic.Int32
 
+	// Draining is whether the host is draining for maintenance,
+	// the peers of the draining host are not scheduled as parents.
+	Draining *atomic.Bool
+
+	// Reputation is the reputation of host as a parent, the peers of
+	// the blocklisted host are not scheduled as parents.
+	Reputation *Reputation
+
 	// CreatedAt is host create time.
 	CreatedAt *atomic.Time
 
@@ -357,8 +386,11 @@ func NewHost(
 		ConcurrentUploadCount: atomic.NewInt32(0),
 		UploadCount:           atomic.NewInt64(0),
 		UploadFailedCount:     atomic.NewInt64(0),
+		UploadThroughput:      NewThroughput(),
 		Peers:                 &sync.Map{},
 		PeerCount:             atomic.NewInt32(0),
+		Draining:              atomic.NewBool(false),
+		Reputation:            NewReputation(),
 		CreatedAt:             atomic.NewTime(time.Now()),
 		UpdatedAt:             atomic.NewTime(time.Now()),
 		Log:                   logger.WithHost(id, hostname, ip),
@@ -413,6 +445,23 @@ func (h *Host) LeavePeers() {
 	})
 }
 
+// ChildCount return the count of children downloading from the peers of host.
+func (h *Host) ChildCount() int {
+	var count int
+	h.Peers.Range(func(_, value any) bool {
+		peer, ok := value.(*Peer)
+		if !ok {
+			h.Log.Error("invalid peer")
+			return true
+		}
+
+		count += len(peer.Children())
+		return true
+	})
+
+	return count
+}
+
 // FreeUploadCount return free upload count of host.
 func (h *Host) FreeUploadCount() int32 {
 	return h.ConcurrentUploadLimit.Load() - h.ConcurrentUploadCount.Load()
@@ -261,6 +261,17 @@ func TestHost_NewHost(t *testing.T) {
 				assert.NotNil(host.Log)
 			},
 		},
+		{
+			name:    "new host and set upload rate limit",
+			rawHost: mockRawHost,
+			options: []HostOption{WithUploadRateLimit(1024)},
+			expect: func(t *testing.T, host *Host) {
+				assert := assert.New(t)
+				assert.Equal(host.ID, mockRawHost.ID)
+				assert.Equal(host.UploadThroughput.capacity, float64(1024))
+				assert.Equal(host.UploadThroughput.FreeRatio(), float64(1))
+			},
+		},
 		{
 			name:    "new host and set os",
 			rawHost: mockRawHost,
@@ -725,6 +736,54 @@ func TestHost_LeavePeers(t *testing.T) {
 	}
 }
 
+func TestHost_ChildCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawHost Host
+		expect  func(t *testing.T, host *Host, mockTask *Task, mockPeer *Peer)
+	}{
+		{
+			name:    "count children of peers in host",
+			rawHost: mockRawHost,
+			expect: func(t *testing.T, host *Host, mockTask *Task, mockPeer *Peer) {
+				assert := assert.New(t)
+				mockChildHost := NewHost(
+					mockRawSeedHost.ID, mockRawSeedHost.IP, mockRawSeedHost.Hostname,
+					mockRawSeedHost.Port, mockRawSeedHost.DownloadPort, mockRawSeedHost.Type)
+				mockChildPeer := NewPeer(mockSeedPeerID, mockResourceConfig, mockTask, mockChildHost)
+				host.StorePeer(mockPeer)
+				mockTask.StorePeer(mockPeer)
+				mockTask.StorePeer(mockChildPeer)
+				assert.NoError(mockTask.AddPeerEdge(mockPeer, mockChildPeer))
+				assert.Equal(host.ChildCount(), 1)
+				assert.Equal(mockChildHost.ChildCount(), 0)
+				assert.NoError(mockTask.DeletePeerInEdges(mockChildPeer.ID))
+				assert.Equal(host.ChildCount(), 0)
+			},
+		},
+		{
+			name:    "peers is empty",
+			rawHost: mockRawHost,
+			expect: func(t *testing.T, host *Host, mockTask *Task, mockPeer *Peer) {
+				assert := assert.New(t)
+				assert.Equal(host.ChildCount(), 0)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			host := NewHost(
+				tc.rawHost.ID, tc.rawHost.IP, tc.rawHost.Hostname,
+				tc.rawHost.Port, tc.rawHost.DownloadPort, tc.rawHost.Type)
+			mockTask := NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, WithDigest(mockTaskDigest))
+			mockPeer := NewPeer(mockPeerID, mockResourceConfig, mockTask, host)
+
+			tc.expect(t, host, mockTask, mockPeer)
+		})
+	}
+}
+
 func TestHost_FreeUploadCount(t *testing.T) {
 	tests := []struct {
 		name    string
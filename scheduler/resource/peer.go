@@ -40,6 +40,7 @@ import (
 	logger "d7y.io/dragonfly/v2/internal/dflog"
 	"d7y.io/dragonfly/v2/pkg/container/set"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/config"
 )
 
@@ -126,6 +127,13 @@ func WithPriority(priority commonv2.Priority) PeerOption {
 	}
 }
 
+// WithQueuePriority set QueuePriority for peer.
+func WithQueuePriority(priority types.QueuePriority) PeerOption {
+	return func(p *Peer) {
+		p.QueuePriority = priority
+	}
+}
+
 // WithRange set Range for peer.
 func WithRange(rg nethttp.Range) PeerOption {
 	return func(p *Peer) {
@@ -147,6 +155,9 @@ type Peer struct {
 	// Priority is peer priority.
 	Priority commonv2.Priority
 
+	// QueuePriority is the priority of peer in the scheduling queues.
+	QueuePriority types.QueuePriority
+
 	// Piece sync map.
 	Pieces *sync.Map
 
@@ -186,6 +197,10 @@ type Peer struct {
 	// NeedBackToSource is set to true.
 	NeedBackToSource *atomic.Bool
 
+	// PromotedSeed is whether the peer is promoted to act as a temporary seed peer
+	// of the hot task, when the dedicated seed peers are saturated.
+	PromotedSeed *atomic.Bool
+
 	// PieceUpdatedAt is piece update time.
 	PieceUpdatedAt *atomic.Time
 
@@ -205,6 +220,7 @@ func NewPeer(id string, cfg *config.ResourceConfig, task *Task, host *Host, opti
 		ID:                      id,
 		Config:                  cfg,
 		Priority:                commonv2.Priority_LEVEL0,
+		QueuePriority:           types.QueuePriorityNormal,
 		Pieces:                  &sync.Map{},
 		FinishedPieces:          &bitset.BitSet{},
 		pieceCosts:              []time.Duration{},
@@ -215,6 +231,7 @@ func NewPeer(id string, cfg *config.ResourceConfig, task *Task, host *Host, opti
 		Host:                    host,
 		BlockParents:            set.NewSafeSet[string](),
 		NeedBackToSource:        atomic.NewBool(false),
+		PromotedSeed:            atomic.NewBool(false),
 		PieceUpdatedAt:          atomic.NewTime(time.Now()),
 		CreatedAt:               atomic.NewTime(time.Now()),
 		UpdatedAt:               atomic.NewTime(time.Now()),
@@ -268,7 +285,7 @@ func NewPeer(id string, cfg *config.ResourceConfig, task *Task, host *Host, opti
 				p.Log.Infof("peer state is %s", e.FSM.Current())
 			},
 			PeerEventDownloadBackToSource: func(ctx context.Context, e *fsm.Event) {
-				p.Task.BackToSourcePeers.Add(p.ID)
+				p.Task.AddBackToSourcePeer(p.ID)
 
 				if err := p.Task.DeletePeerInEdges(p.ID); err != nil {
 					p.Log.Errorf("delete peer inedges failed: %s", err.Error())
@@ -279,7 +296,7 @@ func NewPeer(id string, cfg *config.ResourceConfig, task *Task, host *Host, opti
 			},
 			PeerEventDownloadSucceeded: func(ctx context.Context, e *fsm.Event) {
 				if e.Src == PeerStateBackToSource {
-					p.Task.BackToSourcePeers.Delete(p.ID)
+					p.Task.DeleteBackToSourcePeer(p.ID)
 				}
 
 				if err := p.Task.DeletePeerInEdges(p.ID); err != nil {
@@ -293,7 +310,7 @@ func NewPeer(id string, cfg *config.ResourceConfig, task *Task, host *Host, opti
 			PeerEventDownloadFailed: func(ctx context.Context, e *fsm.Event) {
 				if e.Src == PeerStateBackToSource {
 					p.Task.PeerFailedCount.Inc()
-					p.Task.BackToSourcePeers.Delete(p.ID)
+					p.Task.DeleteBackToSourcePeer(p.ID)
 				}
 
 				if err := p.Task.DeletePeerInEdges(p.ID); err != nil {
@@ -308,7 +325,7 @@ func NewPeer(id string, cfg *config.ResourceConfig, task *Task, host *Host, opti
 					p.Log.Errorf("delete peer inedges failed: %s", err.Error())
 				}
 
-				p.Task.BackToSourcePeers.Delete(p.ID)
+				p.Task.DeleteBackToSourcePeer(p.ID)
 				p.Log.Infof("peer state is %s", e.FSM.Current())
 			},
 		},
@@ -52,6 +52,9 @@ type PeerManager interface {
 	// If f returns false, range stops the iteration.
 	Range(f func(any, any) bool)
 
+	// PolicyManager returns the manager of peer eviction policies.
+	PolicyManager() PolicyManager
+
 	// Try to reclaim peer.
 	RunGC() error
 }
@@ -70,6 +73,9 @@ type peerManager struct {
 	// pieceDownloadTimeout is timeout of downloading piece.
 	pieceDownloadTimeout time.Duration
 
+	// policyManager is the manager of peer eviction policies.
+	policyManager PolicyManager
+
 	// mu is peer mutex.
 	mu *sync.Mutex
 }
@@ -81,6 +87,7 @@ func newPeerManager(cfg *config.GCConfig, gc pkggc.GC) (PeerManager, error) {
 		peerTTL:              cfg.PeerTTL,
 		hostTTL:              cfg.HostTTL,
 		pieceDownloadTimeout: cfg.PieceDownloadTimeout,
+		policyManager:        newPolicyManager(&cfg.PeerEviction),
 		mu:                   &sync.Mutex{},
 	}
 
@@ -150,6 +157,11 @@ func (p *peerManager) Range(f func(key, value any) bool) {
 	p.Map.Range(f)
 }
 
+// PolicyManager returns the manager of peer eviction policies.
+func (p *peerManager) PolicyManager() PolicyManager {
+	return p.policyManager
+}
+
 // Try to reclaim peer.
 func (p *peerManager) RunGC() error {
 	p.Map.Range(func(_, value any) bool {
@@ -246,5 +258,30 @@ func (p *peerManager) RunGC() error {
 		return true
 	})
 
+	p.evict()
 	return nil
 }
+
+// evict reclaims the peers selected by the eviction policy.
+func (p *peerManager) evict() {
+	var peers []*Peer
+	p.Map.Range(func(_, value any) bool {
+		peer, ok := value.(*Peer)
+		if ok && !peer.FSM.Is(PeerStateLeave) {
+			peers = append(peers, peer)
+		}
+
+		return true
+	})
+
+	for _, peer := range p.policyManager.Evict(peers) {
+		peer.Log.Info("peer is selected by eviction policy, causing the peer to leave")
+		if err := peer.FSM.Event(context.Background(), PeerEventLeave); err != nil {
+			peer.Log.Errorf("peer fsm event failed: %s", err.Error())
+			continue
+		}
+
+		p.Delete(peer.ID)
+		peer.Log.Info("peer has been reclaimed")
+	}
+}
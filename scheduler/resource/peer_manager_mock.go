@@ -75,6 +75,20 @@ func (mr *MockPeerManagerMockRecorder) LoadOrStore(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOrStore", reflect.TypeOf((*MockPeerManager)(nil).LoadOrStore), arg0)
 }
 
+// PolicyManager mocks base method.
+func (m *MockPeerManager) PolicyManager() PolicyManager {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PolicyManager")
+	ret0, _ := ret[0].(PolicyManager)
+	return ret0
+}
+
+// PolicyManager indicates an expected call of PolicyManager.
+func (mr *MockPeerManagerMockRecorder) PolicyManager() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PolicyManager", reflect.TypeOf((*MockPeerManager)(nil).PolicyManager))
+}
+
 // Range mocks base method.
 func (m *MockPeerManager) Range(f func(any, any) bool) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,124 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// reputationWeight is the weight of the success ratio observed in a report window,
+	// the reputation score is the exponentially weighted moving average of the success ratios.
+	reputationWeight = 0.3
+)
+
+// Reputation is the reputation of host as a parent, it is scored by the piece results
+// reported by the children, and the misbehaving host is blocklisted temporarily.
+type Reputation struct {
+	// mu protects the fields below.
+	mu sync.RWMutex
+
+	// score is the reputation score in [0, 1].
+	score float64
+
+	// pieces, failures and corruptions are the total piece results reported by the children.
+	pieces      uint64
+	failures    uint64
+	corruptions uint64
+
+	// blockedUntil is the time until which host is blocklisted from parent selection.
+	blockedUntil time.Time
+}
+
+// NewReputation returns a new Reputation instance.
+func NewReputation() *Reputation {
+	return &Reputation{score: 1}
+}
+
+// Report updates the reputation score by the piece results in a report window,
+// the corrupted pieces take the success ratio of the window down to zero.
+func (r *Reputation) Report(pieces, failures, corruptions uint64) {
+	if pieces == 0 {
+		return
+	}
+
+	if failures > pieces {
+		failures = pieces
+	}
+
+	ratio := 1 - float64(failures)/float64(pieces)
+	if corruptions > 0 {
+		ratio = 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pieces += pieces
+	r.failures += failures
+	r.corruptions += corruptions
+	r.score = (1-reputationWeight)*r.score + reputationWeight*ratio
+}
+
+// Score returns the reputation score in [0, 1].
+func (r *Reputation) Score() float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.score
+}
+
+// Counts returns the total piece results reported by the children.
+func (r *Reputation) Counts() (pieces, failures, corruptions uint64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.pieces, r.failures, r.corruptions
+}
+
+// Block blocklists host from parent selection until the time.
+func (r *Reputation) Block(until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.blockedUntil = until
+}
+
+// Unblock removes host from the blocklist.
+func (r *Reputation) Unblock() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.blockedUntil = time.Time{}
+}
+
+// IsBlocked returns whether host is blocklisted from parent selection.
+func (r *Reputation) IsBlocked() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return time.Now().Before(r.blockedUntil)
+}
+
+// BlockedUntil returns the time until which host is blocklisted, it is zero if host is never blocklisted.
+func (r *Reputation) BlockedUntil() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.blockedUntil
+}
@@ -0,0 +1,117 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReputation_Report(t *testing.T) {
+	tests := []struct {
+		name    string
+		reports [][3]uint64
+		expect  func(t *testing.T, r *Reputation)
+	}{
+		{
+			name: "new reputation",
+			expect: func(t *testing.T, r *Reputation) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), r.Score())
+				assert.False(r.IsBlocked())
+			},
+		},
+		{
+			name:    "all pieces succeeded",
+			reports: [][3]uint64{{10, 0, 0}},
+			expect: func(t *testing.T, r *Reputation) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), r.Score())
+				pieces, failures, corruptions := r.Counts()
+				assert.Equal(uint64(10), pieces)
+				assert.Equal(uint64(0), failures)
+				assert.Equal(uint64(0), corruptions)
+			},
+		},
+		{
+			name:    "half pieces failed",
+			reports: [][3]uint64{{10, 5, 0}},
+			expect: func(t *testing.T, r *Reputation) {
+				assert.InDelta(t, 0.85, r.Score(), 1e-9)
+			},
+		},
+		{
+			name:    "pieces corrupted",
+			reports: [][3]uint64{{10, 1, 1}},
+			expect: func(t *testing.T, r *Reputation) {
+				assert := assert.New(t)
+				assert.InDelta(0.7, r.Score(), 1e-9)
+				_, _, corruptions := r.Counts()
+				assert.Equal(uint64(1), corruptions)
+			},
+		},
+		{
+			name:    "score decays with continuous failures",
+			reports: [][3]uint64{{10, 10, 0}, {10, 10, 0}},
+			expect: func(t *testing.T, r *Reputation) {
+				assert.InDelta(t, 0.49, r.Score(), 1e-9)
+			},
+		},
+		{
+			name:    "score recovers with successes",
+			reports: [][3]uint64{{10, 10, 0}, {10, 0, 0}},
+			expect: func(t *testing.T, r *Reputation) {
+				assert.InDelta(t, 0.79, r.Score(), 1e-9)
+			},
+		},
+		{
+			name:    "empty report is ignored",
+			reports: [][3]uint64{{0, 0, 0}},
+			expect: func(t *testing.T, r *Reputation) {
+				assert.Equal(t, float64(1), r.Score())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReputation()
+			for _, report := range tc.reports {
+				r.Report(report[0], report[1], report[2])
+			}
+
+			tc.expect(t, r)
+		})
+	}
+}
+
+func TestReputation_Block(t *testing.T) {
+	assert := assert.New(t)
+	r := NewReputation()
+	assert.True(r.BlockedUntil().IsZero())
+
+	r.Block(time.Now().Add(time.Minute))
+	assert.True(r.IsBlocked())
+
+	r.Unblock()
+	assert.False(r.IsBlocked())
+
+	r.Block(time.Now().Add(-time.Minute))
+	assert.False(r.IsBlocked())
+}
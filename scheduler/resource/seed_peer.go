@@ -73,15 +73,19 @@ type seedPeer struct {
 
 	// hostManager is HostManager interface.
 	hostManager HostManager
+
+	// triggerQueue limits the concurrent triggers by queue priority of task.
+	triggerQueue *triggerQueue
 }
 
 // New SeedPeer interface.
 func newSeedPeer(cfg *config.ResourceConfig, client SeedPeerClient, peerManager PeerManager, hostManager HostManager) SeedPeer {
 	return &seedPeer{
-		config:      cfg,
-		client:      client,
-		peerManager: peerManager,
-		hostManager: hostManager,
+		config:       cfg,
+		client:       client,
+		peerManager:  peerManager,
+		hostManager:  hostManager,
+		triggerQueue: newTriggerQueue(cfg.SeedPeer.TriggerLimit),
 	}
 }
 
@@ -98,6 +102,14 @@ func (s *seedPeer) DownloadTask(ctx context.Context, task *Task, hostType types.
 // TriggerTask triggers the seed peer to download task.
 // Used only in v1 version of the grpc.
 func (s *seedPeer) TriggerTask(ctx context.Context, rg *http.Range, task *Task) (*Peer, *schedulerv1.PeerResult, error) {
+	// Urgent tasks are admitted ahead of bulk tasks when the triggers reach the limit.
+	priority := types.QueuePriority(task.QueuePriority.Load())
+	if err := s.triggerQueue.Acquire(ctx, priority); err != nil {
+		return nil, nil, err
+	}
+	defer s.triggerQueue.Release()
+	task.Log.Infof("seed peer trigger is admitted with queue priority %d", priority)
+
 	urlMeta := &commonv1.UrlMeta{
 		Tag:         task.Tag,
 		Filter:      strings.Join(task.Filters, idgen.URLFilterSeparator),
@@ -176,7 +188,11 @@ func (s *seedPeer) TriggerTask(ctx context.Context, rg *http.Range, task *Task)
 			}
 
 			if len(pieceSeed.PieceInfo.PieceMd5) > 0 {
-				piece.Digest = digest.New(digest.AlgorithmMD5, pieceSeed.PieceInfo.PieceMd5)
+				if d, err := digest.ParsePieceDigest(pieceSeed.PieceInfo.PieceMd5); err != nil {
+					peer.Log.Errorf("parse piece digest failed: %s", err.Error())
+				} else {
+					piece.Digest = d
+				}
 			}
 
 			peer.StorePiece(piece)
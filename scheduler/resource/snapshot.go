@@ -0,0 +1,603 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination snapshot_mock.go -source snapshot.go -package resource
+
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bits-and-blooms/bitset"
+	"github.com/go-redis/redis/v8"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/digest"
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+const (
+	// snapshotTimeout is the timeout of taking or restoring snapshot.
+	snapshotTimeout = 30 * time.Second
+)
+
+// SnapshotStorage is the interface used for storing snapshot.
+type SnapshotStorage interface {
+	// Save saves the snapshot.
+	Save(context.Context, []byte) error
+
+	// Load loads the snapshot, it returns nil if there is no snapshot.
+	Load(context.Context) ([]byte, error)
+}
+
+// redisSnapshotStorage stores the snapshot in redis.
+type redisSnapshotStorage struct {
+	// rdb is redis universal client interface.
+	rdb redis.UniversalClient
+
+	// key is the redis key of snapshot.
+	key string
+}
+
+// NewRedisSnapshotStorage returns a SnapshotStorage storing the snapshot in redis key.
+func NewRedisSnapshotStorage(rdb redis.UniversalClient, key string) SnapshotStorage {
+	return &redisSnapshotStorage{rdb: rdb, key: key}
+}
+
+// Save saves the snapshot.
+func (r *redisSnapshotStorage) Save(ctx context.Context, data []byte) error {
+	return r.rdb.Set(ctx, r.key, data, 0).Err()
+}
+
+// Load loads the snapshot, it returns nil if there is no snapshot.
+func (r *redisSnapshotStorage) Load(ctx context.Context) ([]byte, error) {
+	data, err := r.rdb.Get(ctx, r.key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+// Snapshotter is the interface used for snapshotting hosts, tasks and peers.
+type Snapshotter interface {
+	// Snapshot takes snapshot of hosts, tasks and peers.
+	Snapshot(context.Context) error
+
+	// Restore restores hosts, tasks and peers from the snapshot.
+	Restore(context.Context) error
+
+	// Serve takes snapshot periodically.
+	Serve()
+
+	// Stop takes the last snapshot and stops taking snapshot.
+	Stop()
+}
+
+// snapshotter contains content for snapshotter.
+type snapshotter struct {
+	// config is the config of resource.
+	config *config.ResourceConfig
+
+	// storage is the storage of snapshot.
+	storage SnapshotStorage
+
+	// resource is the resource interface.
+	resource Resource
+
+	// done channel will be closed when snapshotter stops.
+	done chan struct{}
+}
+
+// NewSnapshotter returns a new Snapshotter interface.
+func NewSnapshotter(cfg *config.ResourceConfig, storage SnapshotStorage, resource Resource) Snapshotter {
+	return &snapshotter{
+		config:   cfg,
+		storage:  storage,
+		resource: resource,
+		done:     make(chan struct{}),
+	}
+}
+
+// Serve takes snapshot periodically.
+func (s *snapshotter) Serve() {
+	tick := time.NewTicker(s.config.Snapshot.Interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+			if err := s.Snapshot(ctx); err != nil {
+				logger.Errorf("take snapshot failed: %s", err.Error())
+			}
+			cancel()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Stop takes the last snapshot and stops taking snapshot.
+func (s *snapshotter) Stop() {
+	close(s.done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+	if err := s.Snapshot(ctx); err != nil {
+		logger.Errorf("take the last snapshot failed: %s", err.Error())
+	}
+}
+
+// Snapshot takes snapshot of hosts, tasks and peers.
+func (s *snapshotter) Snapshot(ctx context.Context) error {
+	snapshot := &resourceSnapshot{CreatedAt: time.Now()}
+	s.resource.HostManager().Range(func(_, value any) bool {
+		if host, ok := value.(*Host); ok {
+			snapshot.Hosts = append(snapshot.Hosts, newHostSnapshot(host))
+		}
+
+		return true
+	})
+
+	s.resource.TaskManager().Range(func(_, value any) bool {
+		if task, ok := value.(*Task); ok {
+			snapshot.Tasks = append(snapshot.Tasks, newTaskSnapshot(task))
+		}
+
+		return true
+	})
+
+	s.resource.PeerManager().Range(func(_, value any) bool {
+		if peer, ok := value.(*Peer); ok && !peer.FSM.Is(PeerStateLeave) {
+			snapshot.Peers = append(snapshot.Peers, newPeerSnapshot(peer))
+		}
+
+		return true
+	})
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storage.Save(ctx, data); err != nil {
+		return err
+	}
+
+	logger.Infof("take snapshot of %d hosts, %d tasks and %d peers", len(snapshot.Hosts), len(snapshot.Tasks), len(snapshot.Peers))
+	return nil
+}
+
+// Restore restores hosts, tasks and peers from the snapshot, the streams of
+// peers are not restored, and peers reconnect the streams by peer id.
+func (s *snapshotter) Restore(ctx context.Context) error {
+	data, err := s.storage.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if data == nil {
+		logger.Info("snapshot not found, skip restoring")
+		return nil
+	}
+
+	snapshot := &resourceSnapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return err
+	}
+
+	for _, h := range snapshot.Hosts {
+		if _, loaded := s.resource.HostManager().Load(h.ID); loaded {
+			continue
+		}
+
+		s.resource.HostManager().Store(h.restore())
+	}
+
+	for _, t := range snapshot.Tasks {
+		if _, loaded := s.resource.TaskManager().Load(t.ID); loaded {
+			continue
+		}
+
+		task, err := t.restore()
+		if err != nil {
+			logger.Errorf("restore task %s failed: %s", t.ID, err.Error())
+			continue
+		}
+
+		s.resource.TaskManager().Store(task)
+	}
+
+	peers := make(map[string]*Peer, len(snapshot.Peers))
+	for _, p := range snapshot.Peers {
+		if _, loaded := s.resource.PeerManager().Load(p.ID); loaded {
+			continue
+		}
+
+		task, loaded := s.resource.TaskManager().Load(p.TaskID)
+		if !loaded {
+			continue
+		}
+
+		host, loaded := s.resource.HostManager().Load(p.HostID)
+		if !loaded {
+			continue
+		}
+
+		peer := p.restore(s.config, task, host)
+		s.resource.PeerManager().Store(peer)
+		peers[peer.ID] = peer
+	}
+
+	// Restore the edges after all peers are stored.
+	for _, p := range snapshot.Peers {
+		peer, ok := peers[p.ID]
+		if !ok {
+			continue
+		}
+
+		for _, parentID := range p.ParentIDs {
+			parent, ok := peers[parentID]
+			if !ok {
+				continue
+			}
+
+			if err := peer.Task.AddPeerEdge(parent, peer); err != nil {
+				peer.Log.Warnf("restore edge from parent %s failed: %s", parentID, err.Error())
+				continue
+			}
+
+			// The upload count of host has been restored from snapshot.
+			parent.Host.UploadCount.Dec()
+		}
+	}
+
+	logger.Infof("restore snapshot taken at %s with %d hosts, %d tasks and %d peers",
+		snapshot.CreatedAt, len(snapshot.Hosts), len(snapshot.Tasks), len(peers))
+	return nil
+}
+
+// resourceSnapshot is the snapshot of hosts, tasks and peers.
+type resourceSnapshot struct {
+	Hosts     []*hostSnapshot `json:"hosts"`
+	Tasks     []*taskSnapshot `json:"tasks"`
+	Peers     []*peerSnapshot `json:"peers"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// hostSnapshot is the snapshot of host.
+type hostSnapshot struct {
+	ID                    string         `json:"id"`
+	Type                  types.HostType `json:"type"`
+	Hostname              string         `json:"hostname"`
+	IP                    string         `json:"ip"`
+	Port                  int32          `json:"port"`
+	DownloadPort          int32          `json:"downloadPort"`
+	ObjectStoragePort     int32          `json:"objectStoragePort"`
+	OS                    string         `json:"os"`
+	Platform              string         `json:"platform"`
+	PlatformFamily        string         `json:"platformFamily"`
+	PlatformVersion       string         `json:"platformVersion"`
+	KernelVersion         string         `json:"kernelVersion"`
+	CPU                   CPU            `json:"cpu"`
+	Memory                Memory         `json:"memory"`
+	Network               Network        `json:"network"`
+	Disk                  Disk           `json:"disk"`
+	Build                 Build          `json:"build"`
+	Topology              types.Topology `json:"topology"`
+	SchedulerClusterID    uint64         `json:"schedulerClusterID"`
+	ConcurrentUploadLimit int32          `json:"concurrentUploadLimit"`
+	UploadCount           int64          `json:"uploadCount"`
+	UploadFailedCount     int64          `json:"uploadFailedCount"`
+	CreatedAt             time.Time      `json:"createdAt"`
+	UpdatedAt             time.Time      `json:"updatedAt"`
+}
+
+// newHostSnapshot returns the snapshot of host.
+func newHostSnapshot(host *Host) *hostSnapshot {
+	return &hostSnapshot{
+		ID:                    host.ID,
+		Type:                  host.Type,
+		Hostname:              host.Hostname,
+		IP:                    host.IP,
+		Port:                  host.Port,
+		DownloadPort:          host.DownloadPort,
+		ObjectStoragePort:     host.ObjectStoragePort,
+		OS:                    host.OS,
+		Platform:              host.Platform,
+		PlatformFamily:        host.PlatformFamily,
+		PlatformVersion:       host.PlatformVersion,
+		KernelVersion:         host.KernelVersion,
+		CPU:                   host.CPU,
+		Memory:                host.Memory,
+		Network:               host.Network,
+		Disk:                  host.Disk,
+		Build:                 host.Build,
+		Topology:              host.Topology,
+		SchedulerClusterID:    host.SchedulerClusterID,
+		ConcurrentUploadLimit: host.ConcurrentUploadLimit.Load(),
+		UploadCount:           host.UploadCount.Load(),
+		UploadFailedCount:     host.UploadFailedCount.Load(),
+		CreatedAt:             host.CreatedAt.Load(),
+		UpdatedAt:             host.UpdatedAt.Load(),
+	}
+}
+
+// restore returns the host restored from snapshot.
+func (h *hostSnapshot) restore() *Host {
+	host := NewHost(
+		h.ID, h.IP, h.Hostname, h.Port, h.DownloadPort, h.Type,
+		WithObjectStoragePort(h.ObjectStoragePort),
+		WithConcurrentUploadLimit(h.ConcurrentUploadLimit),
+		WithOS(h.OS),
+		WithPlatform(h.Platform),
+		WithPlatformFamily(h.PlatformFamily),
+		WithPlatformVersion(h.PlatformVersion),
+		WithKernelVersion(h.KernelVersion),
+		WithCPU(h.CPU),
+		WithMemory(h.Memory),
+		WithNetwork(h.Network),
+		WithDisk(h.Disk),
+		WithBuild(h.Build),
+		WithTopology(h.Topology),
+		WithSchedulerClusterID(h.SchedulerClusterID),
+	)
+
+	host.UploadCount.Store(h.UploadCount)
+	host.UploadFailedCount.Store(h.UploadFailedCount)
+	host.CreatedAt.Store(h.CreatedAt)
+	host.UpdatedAt.Store(h.UpdatedAt)
+	return host
+}
+
+// taskSnapshot is the snapshot of task.
+type taskSnapshot struct {
+	ID                string            `json:"id"`
+	Type              commonv2.TaskType `json:"type"`
+	URL               string            `json:"url"`
+	Digest            string            `json:"digest"`
+	Tag               string            `json:"tag"`
+	Application       string            `json:"application"`
+	Filters           []string          `json:"filters"`
+	Header            map[string]string `json:"header"`
+	PieceLength       int32             `json:"pieceLength"`
+	DirectPiece       []byte            `json:"directPiece"`
+	ContentLength     int64             `json:"contentLength"`
+	TotalPieceCount   int32             `json:"totalPieceCount"`
+	BackToSourceLimit int32             `json:"backToSourceLimit"`
+	BackToSourcePeers []string          `json:"backToSourcePeers"`
+	QueuePriority     int32             `json:"queuePriority"`
+	PeerFailedCount   int32             `json:"peerFailedCount"`
+	State             string            `json:"state"`
+	Pieces            []*pieceSnapshot  `json:"pieces"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+}
+
+// newTaskSnapshot returns the snapshot of task.
+func newTaskSnapshot(task *Task) *taskSnapshot {
+	t := &taskSnapshot{
+		ID:                task.ID,
+		Type:              task.Type,
+		URL:               task.URL,
+		Tag:               task.Tag,
+		Application:       task.Application,
+		Filters:           task.Filters,
+		Header:            task.Header,
+		PieceLength:       task.PieceLength,
+		DirectPiece:       task.DirectPiece,
+		ContentLength:     task.ContentLength.Load(),
+		TotalPieceCount:   task.TotalPieceCount.Load(),
+		BackToSourceLimit: task.BackToSourceLimit.Load(),
+		BackToSourcePeers: task.BackToSourcePeers.Values(),
+		QueuePriority:     task.QueuePriority.Load(),
+		PeerFailedCount:   task.PeerFailedCount.Load(),
+		State:             task.FSM.Current(),
+		CreatedAt:         task.CreatedAt.Load(),
+		UpdatedAt:         task.UpdatedAt.Load(),
+	}
+
+	if task.Digest != nil {
+		t.Digest = task.Digest.String()
+	}
+
+	task.Pieces.Range(func(_, value any) bool {
+		if piece, ok := value.(*Piece); ok {
+			t.Pieces = append(t.Pieces, newPieceSnapshot(piece))
+		}
+
+		return true
+	})
+
+	return t
+}
+
+// restore returns the task restored from snapshot.
+func (t *taskSnapshot) restore() (*Task, error) {
+	options := []TaskOption{WithPieceLength(t.PieceLength)}
+	if t.Digest != "" {
+		d, err := digest.Parse(t.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, WithDigest(d))
+	}
+
+	task := NewTask(t.ID, t.URL, t.Tag, t.Application, t.Type, t.Filters, t.Header, t.BackToSourceLimit, options...)
+	task.DirectPiece = t.DirectPiece
+	task.ContentLength.Store(t.ContentLength)
+	task.TotalPieceCount.Store(t.TotalPieceCount)
+	task.QueuePriority.Store(t.QueuePriority)
+	task.PeerFailedCount.Store(t.PeerFailedCount)
+	task.FSM.SetState(t.State)
+	for _, peerID := range t.BackToSourcePeers {
+		task.BackToSourcePeers.Add(peerID)
+	}
+
+	for _, p := range t.Pieces {
+		piece, err := p.restore()
+		if err != nil {
+			return nil, err
+		}
+
+		task.StorePiece(piece)
+	}
+
+	task.CreatedAt.Store(t.CreatedAt)
+	task.UpdatedAt.Store(t.UpdatedAt)
+	return task, nil
+}
+
+// pieceSnapshot is the snapshot of piece.
+type pieceSnapshot struct {
+	Number      int32                `json:"number"`
+	ParentID    string               `json:"parentID"`
+	Offset      uint64               `json:"offset"`
+	Length      uint64               `json:"length"`
+	Digest      string               `json:"digest"`
+	TrafficType commonv2.TrafficType `json:"trafficType"`
+	Cost        time.Duration        `json:"cost"`
+	CreatedAt   time.Time            `json:"createdAt"`
+}
+
+// newPieceSnapshot returns the snapshot of piece.
+func newPieceSnapshot(piece *Piece) *pieceSnapshot {
+	p := &pieceSnapshot{
+		Number:      piece.Number,
+		ParentID:    piece.ParentID,
+		Offset:      piece.Offset,
+		Length:      piece.Length,
+		TrafficType: piece.TrafficType,
+		Cost:        piece.Cost,
+		CreatedAt:   piece.CreatedAt,
+	}
+
+	if piece.Digest != nil {
+		p.Digest = piece.Digest.String()
+	}
+
+	return p
+}
+
+// restore returns the piece restored from snapshot.
+func (p *pieceSnapshot) restore() (*Piece, error) {
+	piece := &Piece{
+		Number:      p.Number,
+		ParentID:    p.ParentID,
+		Offset:      p.Offset,
+		Length:      p.Length,
+		TrafficType: p.TrafficType,
+		Cost:        p.Cost,
+		CreatedAt:   p.CreatedAt,
+	}
+
+	if p.Digest != "" {
+		d, err := digest.Parse(p.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		piece.Digest = d
+	}
+
+	return piece, nil
+}
+
+// peerSnapshot is the snapshot of peer, the pieces of peer are not included,
+// and the finished pieces are restored.
+type peerSnapshot struct {
+	ID               string              `json:"id"`
+	TaskID           string              `json:"taskID"`
+	HostID           string              `json:"hostID"`
+	Range            *nethttp.Range      `json:"range"`
+	Priority         commonv2.Priority   `json:"priority"`
+	QueuePriority    types.QueuePriority `json:"queuePriority"`
+	FinishedPieces   *bitset.BitSet      `json:"finishedPieces"`
+	PieceCosts       []time.Duration     `json:"pieceCosts"`
+	Cost             time.Duration       `json:"cost"`
+	BlockParents     []string            `json:"blockParents"`
+	NeedBackToSource bool                `json:"needBackToSource"`
+	ParentIDs        []string            `json:"parentIDs"`
+	State            string              `json:"state"`
+	PieceUpdatedAt   time.Time           `json:"pieceUpdatedAt"`
+	CreatedAt        time.Time           `json:"createdAt"`
+	UpdatedAt        time.Time           `json:"updatedAt"`
+}
+
+// newPeerSnapshot returns the snapshot of peer.
+func newPeerSnapshot(peer *Peer) *peerSnapshot {
+	p := &peerSnapshot{
+		ID:               peer.ID,
+		TaskID:           peer.Task.ID,
+		HostID:           peer.Host.ID,
+		Range:            peer.Range,
+		Priority:         peer.Priority,
+		QueuePriority:    peer.QueuePriority,
+		FinishedPieces:   peer.FinishedPieces.Clone(),
+		PieceCosts:       peer.PieceCosts(),
+		Cost:             peer.Cost.Load(),
+		BlockParents:     peer.BlockParents.Values(),
+		NeedBackToSource: peer.NeedBackToSource.Load(),
+		State:            peer.FSM.Current(),
+		PieceUpdatedAt:   peer.PieceUpdatedAt.Load(),
+		CreatedAt:        peer.CreatedAt.Load(),
+		UpdatedAt:        peer.UpdatedAt.Load(),
+	}
+
+	for _, parent := range peer.Parents() {
+		p.ParentIDs = append(p.ParentIDs, parent.ID)
+	}
+
+	return p
+}
+
+// restore returns the peer restored from snapshot.
+func (p *peerSnapshot) restore(cfg *config.ResourceConfig, task *Task, host *Host) *Peer {
+	options := []PeerOption{WithPriority(p.Priority), WithQueuePriority(p.QueuePriority)}
+	if p.Range != nil {
+		options = append(options, WithRange(*p.Range))
+	}
+
+	peer := NewPeer(p.ID, cfg, task, host, options...)
+	if p.FinishedPieces != nil {
+		peer.FinishedPieces = p.FinishedPieces
+	}
+
+	for _, cost := range p.PieceCosts {
+		peer.AppendPieceCost(cost)
+	}
+
+	for _, parentID := range p.BlockParents {
+		peer.BlockParents.Add(parentID)
+	}
+
+	peer.Cost.Store(p.Cost)
+	peer.NeedBackToSource.Store(p.NeedBackToSource)
+	peer.FSM.SetState(p.State)
+	peer.PieceUpdatedAt.Store(p.PieceUpdatedAt)
+	peer.CreatedAt.Store(p.CreatedAt)
+	peer.UpdatedAt.Store(p.UpdatedAt)
+	return peer
+}
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: snapshot.go
+
+// Package resource is a generated GoMock package.
+package resource
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSnapshotStorage is a mock of SnapshotStorage interface.
+type MockSnapshotStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotStorageMockRecorder
+}
+
+// MockSnapshotStorageMockRecorder is the mock recorder for MockSnapshotStorage.
+type MockSnapshotStorageMockRecorder struct {
+	mock *MockSnapshotStorage
+}
+
+// NewMockSnapshotStorage creates a new mock instance.
+func NewMockSnapshotStorage(ctrl *gomock.Controller) *MockSnapshotStorage {
+	mock := &MockSnapshotStorage{ctrl: ctrl}
+	mock.recorder = &MockSnapshotStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotStorage) EXPECT() *MockSnapshotStorageMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method.
+func (m *MockSnapshotStorage) Load(arg0 context.Context) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockSnapshotStorageMockRecorder) Load(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockSnapshotStorage)(nil).Load), arg0)
+}
+
+// Save mocks base method.
+func (m *MockSnapshotStorage) Save(arg0 context.Context, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSnapshotStorageMockRecorder) Save(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSnapshotStorage)(nil).Save), arg0, arg1)
+}
+
+// MockSnapshotter is a mock of Snapshotter interface.
+type MockSnapshotter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotterMockRecorder
+}
+
+// MockSnapshotterMockRecorder is the mock recorder for MockSnapshotter.
+type MockSnapshotterMockRecorder struct {
+	mock *MockSnapshotter
+}
+
+// NewMockSnapshotter creates a new mock instance.
+func NewMockSnapshotter(ctrl *gomock.Controller) *MockSnapshotter {
+	mock := &MockSnapshotter{ctrl: ctrl}
+	mock.recorder = &MockSnapshotterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotter) EXPECT() *MockSnapshotterMockRecorder {
+	return m.recorder
+}
+
+// Restore mocks base method.
+func (m *MockSnapshotter) Restore(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockSnapshotterMockRecorder) Restore(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockSnapshotter)(nil).Restore), arg0)
+}
+
+// Serve mocks base method.
+func (m *MockSnapshotter) Serve() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Serve")
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockSnapshotterMockRecorder) Serve() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockSnapshotter)(nil).Serve))
+}
+
+// Snapshot mocks base method.
+func (m *MockSnapshotter) Snapshot(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockSnapshotterMockRecorder) Snapshot(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockSnapshotter)(nil).Snapshot), arg0)
+}
+
+// Stop mocks base method.
+func (m *MockSnapshotter) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockSnapshotterMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockSnapshotter)(nil).Stop))
+}
@@ -0,0 +1,222 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-redis/redismock/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/pkg/gc"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestRedisSnapshotStorage(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(mock redismock.ClientMock)
+		expect func(t *testing.T, storage SnapshotStorage)
+	}{
+		{
+			name: "save and load snapshot",
+			mock: func(mock redismock.ClientMock) {
+				mock.ExpectSet("foo", []byte("bar"), 0).SetVal("OK")
+				mock.ExpectGet("foo").SetVal("bar")
+			},
+			expect: func(t *testing.T, storage SnapshotStorage) {
+				assert := assert.New(t)
+				assert.NoError(storage.Save(context.Background(), []byte("bar")))
+				data, err := storage.Load(context.Background())
+				assert.NoError(err)
+				assert.Equal([]byte("bar"), data)
+			},
+		},
+		{
+			name: "snapshot not found",
+			mock: func(mock redismock.ClientMock) {
+				mock.ExpectGet("foo").RedisNil()
+			},
+			expect: func(t *testing.T, storage SnapshotStorage) {
+				assert := assert.New(t)
+				data, err := storage.Load(context.Background())
+				assert.NoError(err)
+				assert.Nil(data)
+			},
+		},
+		{
+			name: "load snapshot failed",
+			mock: func(mock redismock.ClientMock) {
+				mock.ExpectGet("foo").SetErr(errors.New("baz"))
+			},
+			expect: func(t *testing.T, storage SnapshotStorage) {
+				assert := assert.New(t)
+				_, err := storage.Load(context.Background())
+				assert.EqualError(err, "baz")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rdb, mock := redismock.NewClientMock()
+			tc.mock(mock)
+			tc.expect(t, NewRedisSnapshotStorage(rdb, "foo"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSnapshotter_SnapshotAndRestore(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	newResource := func() Resource {
+		gc := gc.NewMockGC(ctl)
+		gc.EXPECT().Add(gomock.Any()).Return(nil).Times(3)
+
+		hostManager, err := newHostManager(mockHostGCConfig, gc)
+		assert.NoError(t, err)
+		taskManager, err := newTaskManager(mockTaskGCConfig, gc)
+		assert.NoError(t, err)
+		peerManager, err := newPeerManager(mockPeerGCConfig, gc)
+		assert.NoError(t, err)
+
+		res := NewMockResource(ctl)
+		res.EXPECT().HostManager().Return(hostManager).AnyTimes()
+		res.EXPECT().TaskManager().Return(taskManager).AnyTimes()
+		res.EXPECT().PeerManager().Return(peerManager).AnyTimes()
+		return res
+	}
+
+	var data []byte
+	storage := NewMockSnapshotStorage(ctl)
+	storage.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d []byte) error {
+		data = d
+		return nil
+	}).Times(1)
+	storage.EXPECT().Load(gomock.Any()).DoAndReturn(func(_ context.Context) ([]byte, error) {
+		return data, nil
+	}).Times(1)
+
+	// Take snapshot of the resource.
+	src := newResource()
+	host := NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type,
+		WithTopology(types.Topology{Region: "foo", Zone: "bar"}))
+	host.UploadCount.Store(10)
+	task := NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, WithDigest(mockTaskDigest))
+	task.StorePiece(mockPiece)
+	task.TotalPieceCount.Store(2)
+	assert.NoError(t, task.FSM.Event(context.Background(), TaskEventDownload))
+	parent := NewPeer(mockPeerID, mockResourceConfig, task, host)
+	parent.FinishedPieces.Set(1)
+	assert.NoError(t, parent.FSM.Event(context.Background(), PeerEventRegisterNormal))
+	child := NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, task, host, WithQueuePriority(types.QueuePriorityUrgent))
+	src.HostManager().Store(host)
+	src.TaskManager().Store(task)
+	src.PeerManager().Store(parent)
+	src.PeerManager().Store(child)
+	assert.NoError(t, task.AddPeerEdge(parent, child))
+
+	assert.NoError(t, NewSnapshotter(mockResourceConfig, storage, src).Snapshot(context.Background()))
+
+	// Restore the resource from snapshot.
+	dst := newResource()
+	assert.NoError(t, NewSnapshotter(mockResourceConfig, storage, dst).Restore(context.Background()))
+
+	assert := assert.New(t)
+	restoredHost, loaded := dst.HostManager().Load(host.ID)
+	assert.True(loaded)
+	assert.Equal(host.Topology, restoredHost.Topology)
+	assert.Equal(host.UploadCount.Load(), restoredHost.UploadCount.Load())
+	assert.Equal(int32(2), restoredHost.PeerCount.Load())
+
+	restoredTask, loaded := dst.TaskManager().Load(task.ID)
+	assert.True(loaded)
+	assert.Equal(mockTaskDigest.String(), restoredTask.Digest.String())
+	assert.Equal(int32(2), restoredTask.TotalPieceCount.Load())
+	assert.True(restoredTask.FSM.Is(TaskStateRunning))
+	piece, loaded := restoredTask.LoadPiece(mockPiece.Number)
+	assert.True(loaded)
+	assert.Equal(mockPiece.Digest.String(), piece.Digest.String())
+
+	restoredParent, loaded := dst.PeerManager().Load(parent.ID)
+	assert.True(loaded)
+	assert.True(restoredParent.FSM.Is(PeerStateReceivedNormal))
+	assert.True(restoredParent.FinishedPieces.Test(1))
+
+	restoredChild, loaded := dst.PeerManager().Load(child.ID)
+	assert.True(loaded)
+	assert.Equal(types.QueuePriorityUrgent, restoredChild.QueuePriority)
+	assert.Len(restoredChild.Parents(), 1)
+	assert.Equal(parent.ID, restoredChild.Parents()[0].ID)
+}
+
+func TestSnapshotter_Restore(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(m *MockSnapshotStorageMockRecorder)
+		expect func(t *testing.T, err error)
+	}{
+		{
+			name: "snapshot not found",
+			mock: func(m *MockSnapshotStorageMockRecorder) {
+				m.Load(gomock.Any()).Return(nil, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "load snapshot failed",
+			mock: func(m *MockSnapshotStorageMockRecorder) {
+				m.Load(gomock.Any()).Return(nil, errors.New("foo")).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "foo")
+			},
+		},
+		{
+			name: "snapshot is invalid",
+			mock: func(m *MockSnapshotStorageMockRecorder) {
+				m.Load(gomock.Any()).Return([]byte("foo"), nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			storage := NewMockSnapshotStorage(ctl)
+			tc.mock(storage.EXPECT())
+
+			tc.expect(t, NewSnapshotter(mockResourceConfig, storage, NewMockResource(ctl)).Restore(context.Background()))
+		})
+	}
+}
@@ -19,6 +19,7 @@ package resource
 import (
 	"context"
 	"errors"
+	"net/url"
 	"sort"
 	"sync"
 	"time"
@@ -101,6 +102,13 @@ func WithDigest(d *digest.Digest) TaskOption {
 	}
 }
 
+// WithParentID set ParentID for task.
+func WithParentID(parentID string) TaskOption {
+	return func(t *Task) {
+		t.ParentID = parentID
+	}
+}
+
 // Task contains content for task.
 type Task struct {
 	// ID is task id.
@@ -130,6 +138,10 @@ type Task struct {
 	// Task piece length.
 	PieceLength int32
 
+	// ParentID is the id of the full task when the task downloads a range of it,
+	// the range is served by the succeeded peers of the full task.
+	ParentID string
+
 	// DirectPiece is tiny piece data.
 	DirectPiece []byte
 
@@ -142,9 +154,17 @@ type Task struct {
 	// BackToSourceLimit is back-to-source limit.
 	BackToSourceLimit *atomic.Int32
 
+	// QueuePriority is the priority of task in the scheduling queues,
+	// it is the highest queue priority of the peers.
+	QueuePriority *atomic.Int32
+
 	// BackToSourcePeers is back-to-source sync map.
 	BackToSourcePeers set.SafeSet[string]
 
+	// originPeers is the back-to-source peers of task manager grouped by origin,
+	// it is attached when the task is stored in task manager.
+	originPeers *atomic.Pointer[originPeers]
+
 	// Task state machine.
 	FSM *fsm.FSM
 
@@ -158,6 +178,17 @@ type Task struct {
 	// if one peer succeeds, the value is reset to zero.
 	PeerFailedCount *atomic.Int32
 
+	// RegisterCount is the total count of the peers registered to the task.
+	RegisterCount *atomic.Int64
+
+	// FlashCrowd is whether the task is in flash crowd, the peers of the task
+	// are scheduled in tree-building mode with deeper fan-out limits.
+	FlashCrowd *atomic.Bool
+
+	// Replication is the replication policy of the persistent cache task,
+	// the replicas of the task are maintained by scheduler.
+	Replication *atomic.Pointer[types.Replication]
+
 	// CreatedAt is task create time.
 	CreatedAt *atomic.Time
 
@@ -183,10 +214,15 @@ func NewTask(id, url, tag, application string, typ commonv2.TaskType, filters []
 		ContentLength:     atomic.NewInt64(-1),
 		TotalPieceCount:   atomic.NewInt32(0),
 		BackToSourceLimit: atomic.NewInt32(backToSourceLimit),
+		QueuePriority:     atomic.NewInt32(int32(types.QueuePriorityBulk)),
 		BackToSourcePeers: set.NewSafeSet[string](),
+		originPeers:       atomic.NewPointer[originPeers](nil),
 		Pieces:            &sync.Map{},
 		DAG:               dag.NewDAG[*Peer](),
 		PeerFailedCount:   atomic.NewInt32(0),
+		RegisterCount:     atomic.NewInt64(0),
+		FlashCrowd:        atomic.NewBool(false),
+		Replication:       atomic.NewPointer(&types.Replication{}),
 		CreatedAt:         atomic.NewTime(time.Now()),
 		UpdatedAt:         atomic.NewTime(time.Now()),
 		Log:               logger.WithTask(id, url),
@@ -251,7 +287,21 @@ func (t *Task) LoadRandomPeers(n uint) []*Peer {
 
 // StorePeer set peer.
 func (t *Task) StorePeer(peer *Peer) {
-	t.DAG.AddVertex(peer.ID, peer) // nolint: errcheck
+	if err := t.DAG.AddVertex(peer.ID, peer); err != nil {
+		return
+	}
+
+	t.RegisterCount.Inc()
+}
+
+// RaiseQueuePriority raises the queue priority of task if the priority is higher.
+func (t *Task) RaiseQueuePriority(priority types.QueuePriority) {
+	for {
+		current := t.QueuePriority.Load()
+		if int32(priority) <= current || t.QueuePriority.CompareAndSwap(current, int32(priority)) {
+			return
+		}
+	}
 }
 
 // DeletePeer deletes peer for a key.
@@ -265,6 +315,50 @@ func (t *Task) DeletePeer(key string) {
 	}
 
 	t.DAG.DeleteVertex(key)
+	t.DeleteBackToSourcePeer(key)
+}
+
+// AddBackToSourcePeer adds the peer downloading back-to-source.
+func (t *Task) AddBackToSourcePeer(key string) {
+	t.BackToSourcePeers.Add(key)
+	if originPeers := t.originPeers.Load(); originPeers != nil {
+		originPeers.add(t.Origin(), key)
+	}
+}
+
+// DeleteBackToSourcePeer deletes the peer downloading back-to-source.
+func (t *Task) DeleteBackToSourcePeer(key string) {
+	t.BackToSourcePeers.Delete(key)
+	if originPeers := t.originPeers.Load(); originPeers != nil {
+		originPeers.delete(t.Origin(), key)
+	}
+}
+
+// attachOriginPeers attaches the back-to-source peers of task manager grouped by origin,
+// the existing back-to-source peers of task are added to the origin.
+func (t *Task) attachOriginPeers(originPeers *originPeers) {
+	if !t.originPeers.CompareAndSwap(nil, originPeers) {
+		return
+	}
+
+	origin := t.Origin()
+	for _, key := range t.BackToSourcePeers.Values() {
+		originPeers.add(origin, key)
+	}
+}
+
+// detachOriginPeers detaches the back-to-source peers of task manager grouped by origin,
+// the back-to-source peers of task are deleted from the origin.
+func (t *Task) detachOriginPeers() {
+	originPeers := t.originPeers.Swap(nil)
+	if originPeers == nil {
+		return
+	}
+
+	origin := t.Origin()
+	for _, key := range t.BackToSourcePeers.Values() {
+		originPeers.delete(origin, key)
+	}
 }
 
 // PeerCount returns count of peer.
@@ -385,6 +479,66 @@ func (t *Task) HasAvailablePeer(blocklist set.SafeSet[string]) bool {
 	return hasAvailablePeer
 }
 
+// HasSucceededPeer returns whether the task has succeeded peer.
+func (t *Task) HasSucceededPeer(blocklist set.SafeSet[string]) bool {
+	for _, vertex := range t.DAG.GetVertices() {
+		peer := vertex.Value
+		if peer == nil {
+			continue
+		}
+
+		if blocklist.Contains(peer.ID) {
+			continue
+		}
+
+		if peer.FSM.Is(PeerStateSucceeded) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LoadReplicas returns the succeeded peers which hold the replicas of the task,
+// one peer per host, and the peers of the draining hosts are excluded.
+func (t *Task) LoadReplicas() []*Peer {
+	var (
+		replicas []*Peer
+		hostIDs  = set.New[string]()
+	)
+	for _, vertex := range t.DAG.GetVertices() {
+		peer := vertex.Value
+		if peer == nil {
+			continue
+		}
+
+		if !peer.FSM.Is(PeerStateSucceeded) || peer.Host.Draining.Load() || hostIDs.Contains(peer.Host.ID) {
+			continue
+		}
+
+		hostIDs.Add(peer.Host.ID)
+		replicas = append(replicas, peer)
+	}
+
+	return replicas
+}
+
+// ReplicaPlacement returns the replica count of the persistent cache task in each failure domain
+// of the topology label which the replicas are spread across.
+func (t *Task) ReplicaPlacement() map[string]int {
+	replication := t.Replication.Load()
+	if !replication.IsPersistent() || replication.SpreadBy == "" {
+		return nil
+	}
+
+	placement := make(map[string]int)
+	for _, peer := range t.LoadReplicas() {
+		placement[peer.Host.Topology.Label(replication.SpreadBy)]++
+	}
+
+	return placement
+}
+
 // LoadSeedPeer return latest seed peer in peers sync map.
 func (t *Task) LoadSeedPeer() (*Peer, bool) {
 	var peers []*Peer
@@ -469,6 +623,17 @@ func (t *Task) CanBackToSource() bool {
 	return int32(t.BackToSourcePeers.Len()) <= t.BackToSourceLimit.Load() && (t.Type == commonv2.TaskType_DFDAEMON || t.Type == commonv2.TaskType_DFSTORE)
 }
 
+// Origin returns the host of task url, the back-to-source peers of
+// the tasks with the same origin download from the same origin server.
+func (t *Task) Origin() string {
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
+
 // CanReuseDirectPiece represents whether task can reuse data of direct piece.
 func (t *Task) CanReuseDirectPiece() bool {
 	return len(t.DirectPiece) > 0 && int64(len(t.DirectPiece)) == t.ContentLength.Load()
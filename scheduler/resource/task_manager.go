@@ -50,6 +50,9 @@ type TaskManager interface {
 	// If f returns false, range stops the iteration.
 	Range(f func(any, any) bool)
 
+	// OriginBackToSourcePeerCount returns the count of peers downloading back-to-source from the origin.
+	OriginBackToSourcePeerCount(string) uint
+
 	// Try to reclaim task.
 	RunGC() error
 }
@@ -58,12 +61,16 @@ type TaskManager interface {
 type taskManager struct {
 	// Task sync map.
 	*sync.Map
+
+	// originPeers is the peers downloading back-to-source grouped by origin.
+	originPeers *originPeers
 }
 
 // New task manager interface.
 func newTaskManager(cfg *config.GCConfig, gc pkggc.GC) (TaskManager, error) {
 	t := &taskManager{
-		Map: &sync.Map{},
+		Map:         &sync.Map{},
+		originPeers: newOriginPeers(),
 	}
 
 	if err := gc.Add(pkggc.Task{
@@ -90,6 +97,7 @@ func (t *taskManager) Load(key string) (*Task, bool) {
 
 // Store sets task.
 func (t *taskManager) Store(task *Task) {
+	task.attachOriginPeers(t.originPeers)
 	t.Map.Store(task.ID, task)
 }
 
@@ -98,12 +106,23 @@ func (t *taskManager) Store(task *Task) {
 // The loaded result is true if the task was loaded, false if stored.
 func (t *taskManager) LoadOrStore(task *Task) (*Task, bool) {
 	rawTask, loaded := t.Map.LoadOrStore(task.ID, task)
+	if !loaded {
+		task.attachOriginPeers(t.originPeers)
+	}
+
 	return rawTask.(*Task), loaded
 }
 
 // Delete deletes task for a key.
 func (t *taskManager) Delete(key string) {
-	t.Map.Delete(key)
+	if rawTask, loaded := t.Map.LoadAndDelete(key); loaded {
+		rawTask.(*Task).detachOriginPeers()
+	}
+}
+
+// OriginBackToSourcePeerCount returns the count of peers downloading back-to-source from the origin.
+func (t *taskManager) OriginBackToSourcePeerCount(origin string) uint {
+	return t.originPeers.count(origin)
 }
 
 // Range calls f sequentially for each key and value present in the map.
@@ -132,3 +151,57 @@ func (t *taskManager) RunGC() error {
 
 	return nil
 }
+
+// originPeers is the peers downloading back-to-source grouped by the origin of tasks,
+// it is updated where the back-to-source peers of tasks are added and deleted,
+// so the count of origin is not calculated by iterating over the tasks.
+type originPeers struct {
+	// mu protects peers.
+	mu sync.Mutex
+
+	// peers is the set of peer ids keyed by origin.
+	peers map[string]map[string]struct{}
+}
+
+// newOriginPeers returns a new originPeers.
+func newOriginPeers() *originPeers {
+	return &originPeers{peers: map[string]map[string]struct{}{}}
+}
+
+// add adds the peer downloading back-to-source from the origin.
+func (o *originPeers) add(origin, peerID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	peers, ok := o.peers[origin]
+	if !ok {
+		peers = map[string]struct{}{}
+		o.peers[origin] = peers
+	}
+
+	peers[peerID] = struct{}{}
+}
+
+// delete deletes the peer downloading back-to-source from the origin.
+func (o *originPeers) delete(origin, peerID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	peers, ok := o.peers[origin]
+	if !ok {
+		return
+	}
+
+	delete(peers, peerID)
+	if len(peers) == 0 {
+		delete(o.peers, origin)
+	}
+}
+
+// count returns the count of peers downloading back-to-source from the origin.
+func (o *originPeers) count(origin string) uint {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return uint(len(o.peers[origin]))
+}
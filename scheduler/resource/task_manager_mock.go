@@ -75,6 +75,20 @@ func (mr *MockTaskManagerMockRecorder) LoadOrStore(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOrStore", reflect.TypeOf((*MockTaskManager)(nil).LoadOrStore), arg0)
 }
 
+// OriginBackToSourcePeerCount mocks base method.
+func (m *MockTaskManager) OriginBackToSourcePeerCount(arg0 string) uint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OriginBackToSourcePeerCount", arg0)
+	ret0, _ := ret[0].(uint)
+	return ret0
+}
+
+// OriginBackToSourcePeerCount indicates an expected call of OriginBackToSourcePeerCount.
+func (mr *MockTaskManagerMockRecorder) OriginBackToSourcePeerCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OriginBackToSourcePeerCount", reflect.TypeOf((*MockTaskManager)(nil).OriginBackToSourcePeerCount), arg0)
+}
+
 // Range mocks base method.
 func (m *MockTaskManager) Range(f func(any, any) bool) {
 	m.ctrl.T.Helper()
@@ -299,6 +299,82 @@ func TestTaskManager_Delete(t *testing.T) {
 	}
 }
 
+func TestTaskManager_OriginBackToSourcePeerCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(m *gc.MockGCMockRecorder)
+		expect func(t *testing.T, taskManager TaskManager, mockTask *Task)
+	}{
+		{
+			name: "back-to-source peers are added and deleted",
+			mock: func(m *gc.MockGCMockRecorder) {
+				m.Add(gomock.Any()).Return(nil).Times(1)
+			},
+			expect: func(t *testing.T, taskManager TaskManager, mockTask *Task) {
+				assert := assert.New(t)
+				taskManager.Store(mockTask)
+				mockTask.AddBackToSourcePeer("foo")
+				mockTask.AddBackToSourcePeer("foo")
+				mockTask.AddBackToSourcePeer("bar")
+				assert.Equal(uint(2), taskManager.OriginBackToSourcePeerCount(mockTask.Origin()))
+				assert.Equal(uint(0), taskManager.OriginBackToSourcePeerCount("example.org"))
+
+				mockTask.DeleteBackToSourcePeer("foo")
+				assert.Equal(uint(1), taskManager.OriginBackToSourcePeerCount(mockTask.Origin()))
+
+				mockTask.DeletePeer("bar")
+				assert.Equal(uint(0), taskManager.OriginBackToSourcePeerCount(mockTask.Origin()))
+			},
+		},
+		{
+			name: "back-to-source peers are added before task is stored",
+			mock: func(m *gc.MockGCMockRecorder) {
+				m.Add(gomock.Any()).Return(nil).Times(1)
+			},
+			expect: func(t *testing.T, taskManager TaskManager, mockTask *Task) {
+				assert := assert.New(t)
+				mockTask.AddBackToSourcePeer("foo")
+				assert.Equal(uint(0), taskManager.OriginBackToSourcePeerCount(mockTask.Origin()))
+
+				taskManager.Store(mockTask)
+				assert.Equal(uint(1), taskManager.OriginBackToSourcePeerCount(mockTask.Origin()))
+			},
+		},
+		{
+			name: "back-to-source peers are deleted with task",
+			mock: func(m *gc.MockGCMockRecorder) {
+				m.Add(gomock.Any()).Return(nil).Times(1)
+			},
+			expect: func(t *testing.T, taskManager TaskManager, mockTask *Task) {
+				assert := assert.New(t)
+				taskManager.LoadOrStore(mockTask)
+				mockTask.AddBackToSourcePeer("foo")
+				assert.Equal(uint(1), taskManager.OriginBackToSourcePeerCount(mockTask.Origin()))
+
+				taskManager.Delete(mockTask.ID)
+				assert.Equal(uint(0), taskManager.OriginBackToSourcePeerCount(mockTask.Origin()))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			gc := gc.NewMockGC(ctl)
+			tc.mock(gc.EXPECT())
+
+			mockTask := NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, WithDigest(mockTaskDigest))
+			taskManager, err := newTaskManager(mockTaskGCConfig, gc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tc.expect(t, taskManager, mockTask)
+		})
+	}
+}
+
 func TestTaskManager_RunGC(t *testing.T) {
 	tests := []struct {
 		name   string
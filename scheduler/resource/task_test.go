@@ -160,6 +160,16 @@ func TestTask_NewTask(t *testing.T) {
 				assert.NotNil(task.Log)
 			},
 		},
+		{
+			name:    "new task with parent id",
+			options: []TaskOption{WithParentID(mockTaskID)},
+			expect: func(t *testing.T, task *Task) {
+				assert := assert.New(t)
+				assert.Equal(task.ID, mockTaskID)
+				assert.Equal(task.ParentID, mockTaskID)
+				assert.Equal(task.FSM.Current(), TaskStatePending)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -182,6 +192,7 @@ func TestTask_LoadPeer(t *testing.T) {
 				assert := assert.New(t)
 				assert.Equal(loaded, true)
 				assert.Equal(peer.ID, mockPeerID)
+				assert.Equal(peer.Task.RegisterCount.Load(), int64(1))
 			},
 		},
 		{
@@ -1065,6 +1076,135 @@ func TestTask_HasAvailablePeer(t *testing.T) {
 	}
 }
 
+func TestTask_HasSucceededPeer(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(t *testing.T, task *Task, mockPeer *Peer)
+	}{
+		{
+			name: "peer state is PeerStateSucceeded",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer) {
+				assert := assert.New(t)
+				mockPeer.FSM.SetState(PeerStateSucceeded)
+				task.StorePeer(mockPeer)
+				assert.True(task.HasSucceededPeer(set.NewSafeSet[string]()))
+			},
+		},
+		{
+			name: "blocklist includes peer",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer) {
+				assert := assert.New(t)
+				mockPeer.FSM.SetState(PeerStateSucceeded)
+				task.StorePeer(mockPeer)
+
+				blocklist := set.NewSafeSet[string]()
+				blocklist.Add(mockPeer.ID)
+				assert.False(task.HasSucceededPeer(blocklist))
+			},
+		},
+		{
+			name: "peer state is PeerStateRunning",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer) {
+				assert := assert.New(t)
+				mockPeer.FSM.SetState(PeerStateRunning)
+				task.StorePeer(mockPeer)
+				assert.False(task.HasSucceededPeer(set.NewSafeSet[string]()))
+			},
+		},
+		{
+			name: "peer does not exist",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer) {
+				assert := assert.New(t)
+				assert.False(task.HasSucceededPeer(set.NewSafeSet[string]()))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockHost := NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			task := NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			mockPeer := NewPeer(mockPeerID, mockResourceConfig, task, mockHost)
+
+			tc.expect(t, task, mockPeer)
+		})
+	}
+}
+
+func TestTask_LoadReplicas(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect func(t *testing.T, task *Task, mockPeer *Peer, mockSeedPeer *Peer)
+	}{
+		{
+			name: "load succeeded peers",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer, mockSeedPeer *Peer) {
+				assert := assert.New(t)
+				mockPeer.FSM.SetState(PeerStateSucceeded)
+				mockSeedPeer.FSM.SetState(PeerStateSucceeded)
+				task.StorePeer(mockPeer)
+				task.StorePeer(mockSeedPeer)
+				assert.Len(task.LoadReplicas(), 2)
+			},
+		},
+		{
+			name: "peer state is PeerStateRunning",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer, mockSeedPeer *Peer) {
+				assert := assert.New(t)
+				mockPeer.FSM.SetState(PeerStateRunning)
+				mockSeedPeer.FSM.SetState(PeerStateSucceeded)
+				task.StorePeer(mockPeer)
+				task.StorePeer(mockSeedPeer)
+
+				replicas := task.LoadReplicas()
+				assert.Len(replicas, 1)
+				assert.Equal(mockSeedPeer.ID, replicas[0].ID)
+			},
+		},
+		{
+			name: "host is draining",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer, mockSeedPeer *Peer) {
+				assert := assert.New(t)
+				mockPeer.FSM.SetState(PeerStateSucceeded)
+				mockPeer.Host.Draining.Store(true)
+				task.StorePeer(mockPeer)
+				assert.Empty(task.LoadReplicas())
+			},
+		},
+		{
+			name: "peers in the same host",
+			expect: func(t *testing.T, task *Task, mockPeer *Peer, mockSeedPeer *Peer) {
+				assert := assert.New(t)
+				mockPeer.FSM.SetState(PeerStateSucceeded)
+				task.StorePeer(mockPeer)
+
+				peer := NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, task, mockPeer.Host)
+				peer.FSM.SetState(PeerStateSucceeded)
+				task.StorePeer(peer)
+				assert.Len(task.LoadReplicas(), 1)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockHost := NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockSeedHost := NewHost(
+				mockRawSeedHost.ID, mockRawSeedHost.IP, mockRawSeedHost.Hostname,
+				mockRawSeedHost.Port, mockRawSeedHost.DownloadPort, mockRawSeedHost.Type)
+			task := NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			mockPeer := NewPeer(mockPeerID, mockResourceConfig, task, mockHost)
+			mockSeedPeer := NewPeer(mockSeedPeerID, mockResourceConfig, task, mockSeedHost)
+
+			tc.expect(t, task, mockPeer, mockSeedPeer)
+		})
+	}
+}
+
 func TestTask_LoadSeedPeer(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -1462,6 +1602,71 @@ func TestTask_CanBackToSource(t *testing.T) {
 	}
 }
 
+func TestTask_RaiseQueuePriority(t *testing.T) {
+	tests := []struct {
+		name       string
+		priorities []types.QueuePriority
+		expect     types.QueuePriority
+	}{
+		{
+			name:   "task has no peers",
+			expect: types.QueuePriorityBulk,
+		},
+		{
+			name:       "raise queue priority",
+			priorities: []types.QueuePriority{types.QueuePriorityNormal, types.QueuePriorityUrgent},
+			expect:     types.QueuePriorityUrgent,
+		},
+		{
+			name:       "lower queue priority is ignored",
+			priorities: []types.QueuePriority{types.QueuePriorityUrgent, types.QueuePriorityBulk},
+			expect:     types.QueuePriorityUrgent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			task := NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			for _, priority := range tc.priorities {
+				task.RaiseQueuePriority(priority)
+			}
+
+			assert.Equal(t, tc.expect, types.QueuePriority(task.QueuePriority.Load()))
+		})
+	}
+}
+
+func TestTask_Origin(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		expect string
+	}{
+		{
+			name:   "origin of http url",
+			url:    mockTaskURL,
+			expect: "example.com",
+		},
+		{
+			name:   "origin with port",
+			url:    "https://example.com:8080/foo",
+			expect: "example.com:8080",
+		},
+		{
+			name:   "url is invalid",
+			url:    "http://[::1",
+			expect: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			task := NewTask(mockTaskID, tc.url, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			assert.Equal(t, tc.expect, task.Origin())
+		})
+	}
+}
+
 func TestTask_CanReuseDirectPiece(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,131 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// throughputSampleInterval is the minimum interval of sampling throughput.
+	throughputSampleInterval = time.Second
+
+	// throughputTimeConstant is the time constant of moving average, the samples
+	// older than it have less than 1/e of weight.
+	throughputTimeConstant = 10 * time.Second
+)
+
+// Throughput is the observed throughput in bytes per second, it is the
+// exponentially weighted moving average of the traffic sampled per second.
+type Throughput struct {
+	// mu protects the fields below.
+	mu sync.Mutex
+
+	// rate is the moving average of throughput.
+	rate float64
+
+	// peak is the peak of moving average, it estimates the bandwidth capacity.
+	peak float64
+
+	// capacity is the bandwidth capacity reported by host, it takes precedence over peak.
+	capacity float64
+
+	// pending is the traffic observed since last sample.
+	pending uint64
+
+	// sampledAt is the time of last sample.
+	sampledAt time.Time
+}
+
+// NewThroughput returns a new Throughput instance.
+func NewThroughput() *Throughput {
+	return &Throughput{sampledAt: time.Now()}
+}
+
+// Observe records the traffic.
+func (t *Throughput) Observe(size uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending += size
+	t.sample(time.Now())
+}
+
+// Rate returns the throughput in bytes per second, it decays when no traffic is observed.
+func (t *Throughput) Rate() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sample(time.Now())
+	return t.rate
+}
+
+// Peak returns the peak throughput in bytes per second.
+func (t *Throughput) Peak() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.peak
+}
+
+// SetCapacity sets the bandwidth capacity in bytes per second, e.g. the effective upload rate limit
+// reported by host, and the capacity is estimated by the peak throughput when it is zero.
+func (t *Throughput) SetCapacity(capacity float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.capacity = capacity
+}
+
+// FreeRatio returns the ratio of spare bandwidth, the bandwidth capacity is the reported capacity
+// or estimated by the peak throughput, and it returns 1 when no traffic has been observed.
+func (t *Throughput) FreeRatio() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sample(time.Now())
+	capacity := t.peak
+	if t.capacity > 0 {
+		capacity = t.capacity
+	}
+
+	if capacity <= 0 {
+		return 1
+	}
+
+	return math.Max(0, 1-t.rate/capacity)
+}
+
+// sample folds the pending traffic into moving average if the sample interval elapsed.
+func (t *Throughput) sample(now time.Time) {
+	elapsed := now.Sub(t.sampledAt)
+	if elapsed < throughputSampleInterval {
+		return
+	}
+
+	rate := float64(t.pending) / elapsed.Seconds()
+	alpha := 1 - math.Exp(-elapsed.Seconds()/throughputTimeConstant.Seconds())
+	t.rate += alpha * (rate - t.rate)
+	if t.rate > t.peak {
+		t.peak = t.rate
+	}
+
+	t.pending = 0
+	t.sampledAt = now
+}
@@ -0,0 +1,104 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThroughput(t *testing.T) {
+	tests := []struct {
+		name   string
+		run    func(throughput *Throughput)
+		expect func(t *testing.T, throughput *Throughput)
+	}{
+		{
+			name: "no traffic is observed",
+			run:  func(throughput *Throughput) {},
+			expect: func(t *testing.T, throughput *Throughput) {
+				assert := assert.New(t)
+				assert.Equal(float64(0), throughput.Rate())
+				assert.Equal(float64(0), throughput.Peak())
+				assert.Equal(float64(1), throughput.FreeRatio())
+			},
+		},
+		{
+			name: "traffic is not sampled within sample interval",
+			run: func(throughput *Throughput) {
+				throughput.Observe(1024)
+			},
+			expect: func(t *testing.T, throughput *Throughput) {
+				assert := assert.New(t)
+				assert.Equal(float64(0), throughput.Rate())
+				assert.Equal(float64(1), throughput.FreeRatio())
+			},
+		},
+		{
+			name: "traffic is sampled",
+			run: func(throughput *Throughput) {
+				throughput.sampledAt = time.Now().Add(-throughputTimeConstant)
+				throughput.Observe(10 * 1024)
+			},
+			expect: func(t *testing.T, throughput *Throughput) {
+				assert := assert.New(t)
+				assert.InDelta(1024*(1-math.Exp(-1)), throughput.Rate(), 1)
+				assert.Equal(throughput.Rate(), throughput.Peak())
+				assert.InDelta(float64(0), throughput.FreeRatio(), 0.01)
+			},
+		},
+		{
+			name: "throughput decays without traffic",
+			run: func(throughput *Throughput) {
+				throughput.sampledAt = time.Now().Add(-throughputTimeConstant)
+				throughput.Observe(10 * 1024)
+				throughput.sampledAt = time.Now().Add(-10 * throughputTimeConstant)
+			},
+			expect: func(t *testing.T, throughput *Throughput) {
+				assert := assert.New(t)
+				assert.InDelta(1024*(1-math.Exp(-1)), throughput.Peak(), 1)
+				assert.Less(throughput.Rate(), throughput.Peak()/1000)
+				assert.Greater(throughput.FreeRatio(), 0.99)
+			},
+		},
+		{
+			name: "free ratio is calculated by capacity",
+			run: func(throughput *Throughput) {
+				throughput.sampledAt = time.Now().Add(-throughputTimeConstant)
+				throughput.Observe(10 * 1024)
+				throughput.SetCapacity(2 * 1024 * (1 - math.Exp(-1)))
+			},
+			expect: func(t *testing.T, throughput *Throughput) {
+				assert := assert.New(t)
+				assert.InDelta(0.5, throughput.FreeRatio(), 0.01)
+				throughput.SetCapacity(0)
+				assert.InDelta(float64(0), throughput.FreeRatio(), 0.01)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			throughput := NewThroughput()
+			tc.run(throughput)
+			tc.expect(t, throughput)
+		})
+	}
+}
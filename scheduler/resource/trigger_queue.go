@@ -0,0 +1,155 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+// triggerQueue limits the concurrent triggers, the waiting triggers are admitted
+// by queue priority first, then by arrival order.
+type triggerQueue struct {
+	// mu protects the fields below.
+	mu sync.Mutex
+
+	// limit is the limit of concurrent triggers, zero means unlimited.
+	limit int
+
+	// running is the count of admitted triggers.
+	running int
+
+	// waiters is the heap of waiting triggers.
+	waiters triggerWaiters
+
+	// seq is the arrival sequence of waiting triggers.
+	seq uint64
+}
+
+// triggerWaiter is the waiting trigger.
+type triggerWaiter struct {
+	// priority is the queue priority of trigger.
+	priority types.QueuePriority
+
+	// seq is the arrival sequence of trigger.
+	seq uint64
+
+	// ready is closed when the trigger is admitted.
+	ready chan struct{}
+
+	// index is the index in heap, it is -1 when the trigger is admitted.
+	index int
+}
+
+// newTriggerQueue returns a new triggerQueue instance.
+func newTriggerQueue(limit int) *triggerQueue {
+	return &triggerQueue{limit: limit}
+}
+
+// Acquire waits until the trigger is admitted or the context is done.
+func (q *triggerQueue) Acquire(ctx context.Context, priority types.QueuePriority) error {
+	q.mu.Lock()
+	if q.limit <= 0 || (q.running < q.limit && len(q.waiters) == 0) {
+		q.running++
+		q.mu.Unlock()
+		return nil
+	}
+
+	q.seq++
+	w := &triggerWaiter{priority: priority, seq: q.seq, ready: make(chan struct{})}
+	heap.Push(&q.waiters, w)
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		// The trigger is admitted while the context is done, then hands over the slot.
+		if w.index < 0 {
+			q.releaseLocked()
+			return ctx.Err()
+		}
+
+		heap.Remove(&q.waiters, w.index)
+		return ctx.Err()
+	}
+}
+
+// Release releases the slot of admitted trigger.
+func (q *triggerQueue) Release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.limit <= 0 {
+		q.running--
+		return
+	}
+
+	q.releaseLocked()
+}
+
+// releaseLocked hands over the slot to the most urgent waiting trigger.
+func (q *triggerQueue) releaseLocked() {
+	if len(q.waiters) == 0 {
+		q.running--
+		return
+	}
+
+	w := heap.Pop(&q.waiters).(*triggerWaiter)
+	close(w.ready)
+}
+
+// triggerWaiters implements heap.Interface, the most urgent trigger is popped first.
+type triggerWaiters []*triggerWaiter
+
+func (ws triggerWaiters) Len() int { return len(ws) }
+
+func (ws triggerWaiters) Less(i, j int) bool {
+	if ws[i].priority != ws[j].priority {
+		return ws[i].priority > ws[j].priority
+	}
+
+	return ws[i].seq < ws[j].seq
+}
+
+func (ws triggerWaiters) Swap(i, j int) {
+	ws[i], ws[j] = ws[j], ws[i]
+	ws[i].index = i
+	ws[j].index = j
+}
+
+func (ws *triggerWaiters) Push(x any) {
+	w := x.(*triggerWaiter)
+	w.index = len(*ws)
+	*ws = append(*ws, w)
+}
+
+func (ws *triggerWaiters) Pop() any {
+	old := *ws
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*ws = old[:n-1]
+	return w
+}
@@ -0,0 +1,110 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+)
+
+func TestTriggerQueue_Acquire(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		expect func(t *testing.T, q *triggerQueue)
+	}{
+		{
+			name:  "acquire without limit",
+			limit: 0,
+			expect: func(t *testing.T, q *triggerQueue) {
+				assert := assert.New(t)
+				for i := 0; i < 10; i++ {
+					assert.NoError(q.Acquire(context.Background(), types.QueuePriorityBulk))
+				}
+
+				assert.Equal(10, q.running)
+				for i := 0; i < 10; i++ {
+					q.Release()
+				}
+
+				assert.Equal(0, q.running)
+			},
+		},
+		{
+			name:  "waiting triggers are admitted by priority and arrival order",
+			limit: 1,
+			expect: func(t *testing.T, q *triggerQueue) {
+				assert := assert.New(t)
+				assert.NoError(q.Acquire(context.Background(), types.QueuePriorityNormal))
+
+				admitted := make(chan string, 3)
+				acquire := func(name string, priority types.QueuePriority, waiting int) {
+					go func() {
+						assert.NoError(q.Acquire(context.Background(), priority))
+						admitted <- name
+					}()
+
+					// Wait for the trigger to be queued.
+					assert.Eventually(func() bool {
+						q.mu.Lock()
+						defer q.mu.Unlock()
+						return len(q.waiters) == waiting
+					}, time.Second, time.Millisecond)
+				}
+
+				acquire("bulk", types.QueuePriorityBulk, 1)
+				acquire("normal", types.QueuePriorityNormal, 2)
+				acquire("urgent", types.QueuePriorityUrgent, 3)
+
+				for _, name := range []string{"urgent", "normal", "bulk"} {
+					q.Release()
+					assert.Equal(name, <-admitted)
+				}
+
+				q.Release()
+				assert.Equal(0, q.running)
+			},
+		},
+		{
+			name:  "waiting trigger is canceled",
+			limit: 1,
+			expect: func(t *testing.T, q *triggerQueue) {
+				assert := assert.New(t)
+				assert.NoError(q.Acquire(context.Background(), types.QueuePriorityNormal))
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+				assert.ErrorIs(q.Acquire(ctx, types.QueuePriorityUrgent), context.DeadlineExceeded)
+				assert.Equal(0, q.waiters.Len())
+
+				q.Release()
+				assert.Equal(0, q.running)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, newTriggerQueue(tc.limit))
+		})
+	}
+}
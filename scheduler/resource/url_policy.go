@@ -0,0 +1,32 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+// CheckURLPolicy returns an error if the url is denied by the url policy
+// in the client config of the scheduler cluster.
+func CheckURLPolicy(dynconfig config.DynconfigInterface, url string) error {
+	clientConfig, err := dynconfig.GetSchedulerClusterClientConfig()
+	if err != nil || clientConfig.URLPolicy == nil {
+		return nil
+	}
+
+	return clientConfig.URLPolicy.Check(url)
+}
@@ -0,0 +1,108 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resource
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	managertypes "d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/types"
+	configmocks "d7y.io/dragonfly/v2/scheduler/config/mocks"
+)
+
+func TestCheckURLPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		mock   func(md *configmocks.MockDynconfigInterfaceMockRecorder)
+		expect func(t *testing.T, err error)
+	}{
+		{
+			name: "get client config failed",
+			url:  "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterClientConfig().Return(managertypes.SchedulerClusterClientConfig{}, errors.New("bar")).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "url policy is not configured",
+			url:  "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterClientConfig().Return(managertypes.SchedulerClusterClientConfig{}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "url is allowed",
+			url:  "https://example.com/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterClientConfig().Return(managertypes.SchedulerClusterClientConfig{
+					URLPolicy: &types.URLPolicy{Allow: []string{"^https://example.com/"}, Deny: []string{"\\.exe$"}},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "url is denied",
+			url:  "https://example.com/foo.exe",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterClientConfig().Return(managertypes.SchedulerClusterClientConfig{
+					URLPolicy: &types.URLPolicy{Allow: []string{"^https://example.com/"}, Deny: []string{"\\.exe$"}},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, types.ErrURLDenied)
+				assert.EqualError(t, err, "url is denied by policy: https://example.com/foo.exe matches deny pattern \\.exe$")
+			},
+		},
+		{
+			name: "url is not allowed",
+			url:  "https://example.org/foo",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterClientConfig().Return(managertypes.SchedulerClusterClientConfig{
+					URLPolicy: &types.URLPolicy{Allow: []string{"^https://example.com/"}},
+				}, nil).Times(1)
+			},
+			expect: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, types.ErrURLDenied)
+				assert.EqualError(t, err, "url is denied by policy: https://example.org/foo matches no allow pattern")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+
+			tc.mock(dynconfig.EXPECT())
+			tc.expect(t, CheckURLPolicy(dynconfig, tc.url))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"d7y.io/dragonfly/v2/pkg/rpc/scheduler/server"
 	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/event"
 	"d7y.io/dragonfly/v2/scheduler/networktopology"
 	"d7y.io/dragonfly/v2/scheduler/resource"
 	"d7y.io/dragonfly/v2/scheduler/scheduling"
@@ -35,10 +36,13 @@ func New(
 	dynconfig config.DynconfigInterface,
 	storage storage.Storage,
 	networkTopology networktopology.NetworkTopology,
+	eventBus event.Bus,
+	rateLimit server.RateLimit,
 	opts ...grpc.ServerOption,
 ) *grpc.Server {
 	return server.New(
-		newSchedulerServerV1(cfg, resource, scheduling, dynconfig, storage, networkTopology),
-		newSchedulerServerV2(cfg, resource, scheduling, dynconfig, storage, networkTopology),
+		newSchedulerServerV1(cfg, resource, scheduling, dynconfig, storage, networkTopology, eventBus),
+		newSchedulerServerV2(cfg, resource, scheduling, dynconfig, storage, networkTopology, eventBus),
+		rateLimit,
 		opts...)
 }
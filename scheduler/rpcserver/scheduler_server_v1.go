@@ -27,6 +27,7 @@ import (
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/event"
 	"d7y.io/dragonfly/v2/scheduler/metrics"
 	"d7y.io/dragonfly/v2/scheduler/networktopology"
 	"d7y.io/dragonfly/v2/scheduler/resource"
@@ -49,8 +50,9 @@ func newSchedulerServerV1(
 	dynconfig config.DynconfigInterface,
 	storage storage.Storage,
 	networkTopology networktopology.NetworkTopology,
+	eventBus event.Bus,
 ) schedulerv1.SchedulerServer {
-	return &schedulerServerV1{service.NewV1(cfg, resource, scheduling, dynconfig, storage, networkTopology)}
+	return &schedulerServerV1{service.NewV1(cfg, resource, scheduling, dynconfig, storage, networkTopology, eventBus)}
 }
 
 // RegisterPeerTask registers peer and triggers seed peer download task.
@@ -118,6 +120,16 @@ func (s *schedulerServerV1) StatTask(ctx context.Context, req *schedulerv1.StatT
 	return resp, nil
 }
 
+// FindTaskPeers returns the peers holding the task to the sibling scheduler.
+func (s *schedulerServerV1) FindTaskPeers(ctx context.Context, req *schedulerv1.StatTaskRequest) (*schedulerv1.PeerPacket, error) {
+	return s.service.FindTaskPeers(ctx, req)
+}
+
+// ListSiblingPeers returns the sibling peers downloading the same task as the peer.
+func (s *schedulerServerV1) ListSiblingPeers(ctx context.Context, req *schedulerv1.PeerTarget) (*schedulerv1.PeerPacket, error) {
+	return s.service.ListSiblingPeers(ctx, req)
+}
+
 // LeaveTask makes the peer unschedulable.
 func (s *schedulerServerV1) LeaveTask(ctx context.Context, req *schedulerv1.PeerTarget) (*emptypb.Empty, error) {
 	// Collect LeavePeerCount metrics.
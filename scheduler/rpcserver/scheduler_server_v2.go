@@ -25,6 +25,7 @@ import (
 	schedulerv2 "d7y.io/api/v2/pkg/apis/scheduler/v2"
 
 	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/event"
 	"d7y.io/dragonfly/v2/scheduler/metrics"
 	"d7y.io/dragonfly/v2/scheduler/networktopology"
 	"d7y.io/dragonfly/v2/scheduler/resource"
@@ -48,8 +49,9 @@ func newSchedulerServerV2(
 	dynconfig config.DynconfigInterface,
 	storage storage.Storage,
 	networkTopology networktopology.NetworkTopology,
+	eventBus event.Bus,
 ) schedulerv2.SchedulerServer {
-	return &schedulerServerV2{service.NewV2(cfg, resource, scheduling, dynconfig, storage, networkTopology)}
+	return &schedulerServerV2{service.NewV2(cfg, resource, scheduling, dynconfig, storage, networkTopology, eventBus)}
 }
 
 // AnnouncePeer announces peer to scheduler.
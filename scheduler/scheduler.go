@@ -24,6 +24,7 @@ import (
 	"net"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -38,23 +39,31 @@ import (
 	"d7y.io/dragonfly/v2/pkg/cache"
 	"d7y.io/dragonfly/v2/pkg/dfpath"
 	"d7y.io/dragonfly/v2/pkg/gc"
+	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/issuer"
 	"d7y.io/dragonfly/v2/pkg/net/ip"
+	"d7y.io/dragonfly/v2/pkg/profiler"
 	pkgredis "d7y.io/dragonfly/v2/pkg/redis"
 	"d7y.io/dragonfly/v2/pkg/rpc"
+	inferenceclient "d7y.io/dragonfly/v2/pkg/rpc/inference/client"
 	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
+	"d7y.io/dragonfly/v2/pkg/rpc/scheduler/server"
 	securityclient "d7y.io/dragonfly/v2/pkg/rpc/security/client"
 	trainerclient "d7y.io/dragonfly/v2/pkg/rpc/trainer/client"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/announcer"
 	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/event"
+	"d7y.io/dragonfly/v2/scheduler/inference"
 	"d7y.io/dragonfly/v2/scheduler/job"
 	"d7y.io/dragonfly/v2/scheduler/metrics"
 	"d7y.io/dragonfly/v2/scheduler/networktopology"
 	"d7y.io/dragonfly/v2/scheduler/resource"
 	"d7y.io/dragonfly/v2/scheduler/rpcserver"
 	"d7y.io/dragonfly/v2/scheduler/scheduling"
+	"d7y.io/dragonfly/v2/scheduler/scheduling/evaluator"
 	"d7y.io/dragonfly/v2/scheduler/storage"
+	"d7y.io/dragonfly/v2/version"
 )
 
 const (
@@ -74,6 +83,9 @@ type Server struct {
 	// Metrics server.
 	metricsServer *http.Server
 
+	// Profiler interface.
+	profiler profiler.Profiler
+
 	// Manager client.
 	managerClient managerclient.V2
 
@@ -83,9 +95,33 @@ type Server struct {
 	// Trainer client.
 	trainerClient trainerclient.V1
 
+	// Inference client of the trained model.
+	inferenceClient inferenceclient.V1
+
 	// Resource interface.
 	resource resource.Resource
 
+	// Snapshotter interface of resource.
+	snapshotter resource.Snapshotter
+
+	// Prober interface of candidate parents.
+	prober scheduling.Prober
+
+	// Seed peer elector of hot tasks.
+	seedPeerElector scheduling.SeedPeerElector
+
+	// Replicator of persistent cache tasks.
+	replicator scheduling.Replicator
+
+	// Flash crowd detector of tasks.
+	flashCrowdDetector scheduling.FlashCrowdDetector
+
+	// Federation of sibling scheduler clusters.
+	federation scheduling.Federation
+
+	// Inference interface of the trained model.
+	inference inference.Inference
+
 	// Dynamic config.
 	dynconfig config.DynconfigInterface
 
@@ -101,6 +137,12 @@ type Server struct {
 	// Network topology interface.
 	networkTopology networktopology.NetworkTopology
 
+	// Event bus of task lifecycle events.
+	eventBus event.Bus
+
+	// Cert reloader.
+	certReloader *rpc.CertReloader
+
 	// GC service.
 	gc gc.GC
 }
@@ -134,6 +176,12 @@ func New(ctx context.Context, cfg *config.Config, d dfpath.Dfpath) (*Server, err
 		managerDialOptions = append(managerDialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	// Inject credentials into the calls of manager.
+	managerDialOptions = append(managerDialOptions, rpc.AuthDialOptions(rpc.NewAuthenticator(
+		rpc.WithAuthToken(cfg.Manager.Auth.Token),
+		rpc.WithAuthHMACSecret(cfg.Manager.Auth.HMACSecret),
+	))...)
+
 	// Initialize manager client.
 	managerClient, err := managerclient.GetV2ByAddr(ctx, cfg.Manager.Addr, managerDialOptions...)
 	if err != nil {
@@ -215,6 +263,17 @@ func New(ctx context.Context, cfg *config.Config, d dfpath.Dfpath) (*Server, err
 		}); err != nil {
 			return nil, err
 		}
+	} else if cfg.Security.CertFile != "" {
+		// Initialize cert reloader, the rotated certificates take effect without restarting.
+		s.certReloader, err = rpc.NewCertReloader(cfg.Security.CertFile, cfg.Security.KeyFile, cfg.Security.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		clientTransportCredentials, err = rpc.NewClientCredentialsByCertReloader(cfg.Security.TLSPolicy, s.certReloader)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Initialize dynconfig client.
@@ -228,11 +287,10 @@ func New(ctx context.Context, cfg *config.Config, d dfpath.Dfpath) (*Server, err
 	s.gc = gc.New(gc.WithLogger(logger.GCLogger))
 
 	// Initialize resource.
-	resource, err := resource.New(cfg, s.gc, dynconfig, resource.WithTransportCredentials(clientTransportCredentials))
+	s.resource, err = resource.New(cfg, s.gc, dynconfig, resource.WithTransportCredentials(clientTransportCredentials))
 	if err != nil {
 		return nil, err
 	}
-	s.resource = resource
 
 	// Initialize redis client.
 	var rdb redis.UniversalClient
@@ -249,24 +307,150 @@ func New(ctx context.Context, cfg *config.Config, d dfpath.Dfpath) (*Server, err
 		}
 	}
 
-	// Initialize job service.
-	if cfg.Job.Enable && pkgredis.IsEnabled(cfg.Database.Redis.Addrs) {
-		s.job, err = job.New(cfg, resource)
+	// Initialize snapshotter and restore the resource from snapshot.
+	if cfg.Resource.Snapshot.Enable && pkgredis.IsEnabled(cfg.Database.Redis.Addrs) {
+		snapshotRDB, err := pkgredis.NewRedis(&redis.UniversalOptions{
+			Addrs:      cfg.Database.Redis.Addrs,
+			MasterName: cfg.Database.Redis.MasterName,
+			DB:         cfg.Database.Redis.SnapshotDB,
+			Username:   cfg.Database.Redis.Username,
+			Password:   cfg.Database.Redis.Password,
+		})
 		if err != nil {
 			return nil, err
 		}
+
+		snapshotStorage := resource.NewRedisSnapshotStorage(snapshotRDB, pkgredis.MakeSnapshotKeyInScheduler(cfg.Server.Host, cfg.Server.AdvertiseIP.String()))
+		s.snapshotter = resource.NewSnapshotter(&cfg.Resource, snapshotStorage, s.resource)
+		if err := s.snapshotter.Restore(ctx); err != nil {
+			logger.Errorf("restore resource from snapshot failed: %s", err.Error())
+		}
 	}
 
 	// Initialize network topology service.
 	if cfg.NetworkTopology.Enable && pkgredis.IsEnabled(cfg.Database.Redis.Addrs) {
-		s.networkTopology, err = networktopology.NewNetworkTopology(cfg.NetworkTopology, rdb, resource, s.storage)
+		s.networkTopology, err = networktopology.NewNetworkTopology(cfg.NetworkTopology, rdb, s.resource, s.storage)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Initialize dial options of dfdaemon grpc client.
+	dfdaemonDialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if clientTransportCredentials != nil {
+		dfdaemonDialOptions = []grpc.DialOption{grpc.WithTransportCredentials(clientTransportCredentials)}
+	}
+
+	// Initialize limiter of back-to-source peers.
+	schedulingOptions := []scheduling.Option{
+		scheduling.WithBackToSourceLimiter(scheduling.NewBackToSourceLimiter(dynconfig, s.resource.TaskManager())),
+	}
+
+	// Initialize anti-affinity of candidate parents configured by applications.
+	schedulingOptions = append(schedulingOptions, scheduling.WithAntiAffinity(scheduling.NewAntiAffinity(dynconfig)))
+
+	// Initialize task manager for scheduling the range task to the peers of the full task.
+	schedulingOptions = append(schedulingOptions, scheduling.WithTaskManager(s.resource.TaskManager()))
+
+	// Initialize feature flags rolled out to the scheduler at runtime.
+	schedulingOptions = append(schedulingOptions, scheduling.WithFeatureFlags(config.NewDynamicFeatureFlags(dynconfig).Load))
+
+	// Initialize evaluator weights tuned by dynconfig at runtime.
+	schedulingOptions = append(schedulingOptions, scheduling.WithEvaluatorOptions(evaluator.WithWeights(evaluator.NewDynamicWeights(dynconfig).Load)))
+
+	// Initialize prober of candidate parents.
+	if cfg.Scheduler.ParentProbe.Enable {
+		s.prober = scheduling.NewProber(&cfg.Scheduler.ParentProbe, dfdaemonDialOptions...)
+		schedulingOptions = append(schedulingOptions, scheduling.WithProber(s.prober))
+	}
+
+	// Initialize reserver of upload slots on candidate parents.
+	if cfg.Scheduler.UploadReservation.Enable {
+		schedulingOptions = append(schedulingOptions, scheduling.WithReserver(scheduling.NewReserver(&cfg.Scheduler.UploadReservation, &cfg.Resource.Task.DownloadTiny)))
+	}
+
+	// Initialize seed peer elector of hot tasks.
+	if cfg.Scheduler.SeedPeerElection.Enable {
+		s.seedPeerElector = scheduling.NewSeedPeerElector(&cfg.Scheduler.SeedPeerElection, s.resource.TaskManager())
+	}
+
+	// Initialize replicator of persistent cache tasks.
+	if cfg.Scheduler.Replication.Enable {
+		s.replicator = scheduling.NewReplicator(&cfg.Scheduler.Replication, s.resource.TaskManager(), s.resource.HostManager(), dfdaemonDialOptions...)
+	}
+
+	// Initialize flash crowd detector of tasks.
+	if cfg.Scheduler.FlashCrowd.Enable {
+		s.flashCrowdDetector = scheduling.NewFlashCrowdDetector(&cfg.Scheduler.FlashCrowd, s.resource.TaskManager())
+	}
+
+	// Initialize federation of sibling scheduler clusters.
+	if cfg.Scheduler.Federation.Enable {
+		schedulerDialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		if clientTransportCredentials != nil {
+			schedulerDialOptions = []grpc.DialOption{grpc.WithTransportCredentials(clientTransportCredentials)}
+		}
+
+		// The sibling schedulers authenticate the requests with the shared auth of schedulers.
+		schedulerDialOptions = append(schedulerDialOptions, rpc.AuthDialOptions(rpc.NewAuthenticator(
+			rpc.WithAuthToken(cfg.Auth.Token),
+			rpc.WithAuthHMACSecret(cfg.Auth.HMACSecret),
+		))...)
+
+		s.federation = scheduling.NewFederation(cfg, s.managerClient, schedulerDialOptions...)
+		schedulingOptions = append(schedulingOptions, scheduling.WithFederation(s.federation))
+	}
+
+	// Initialize inference of the trained GNN model, the machine learning algorithm
+	// can also be rolled out to the scheduler of default algorithm by feature flag.
+	if cfg.Scheduler.Algorithm == config.SchedulerAlgorithmML || cfg.Scheduler.Inference.Addr != "" {
+		inferenceClient, err := inferenceclient.GetV1(ctx, cfg.Scheduler.Inference.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 		if err != nil {
 			return nil, err
 		}
+		s.inferenceClient = inferenceClient
+
+		s.inference = inference.New(&cfg.Scheduler.Inference, s.inferenceClient, idgen.GNNModelIDV1(cfg.Server.AdvertiseIP.String(), cfg.Server.Host))
+		schedulingOptions = append(schedulingOptions, scheduling.WithEvaluatorOptions(
+			evaluator.WithInferencer(s.inference),
+			evaluator.WithNetworkTopology(s.networkTopology),
+		))
+	}
+
+	// Initialize event bus of task lifecycle events.
+	if cfg.Event.Enable {
+		eventOptions := []event.Option{
+			event.WithBufferSize(cfg.Event.BufferSize),
+			event.WithSchedulerClusterID(cfg.Manager.SchedulerClusterID),
+		}
+
+		if cfg.Event.Kafka.Enable {
+			eventOptions = append(eventOptions, event.WithExporters(event.NewKafkaExporter(cfg.Event.Kafka.Brokers, cfg.Event.Kafka.Topic)))
+		}
+
+		if cfg.Event.NATS.Enable {
+			natsExporter, err := event.NewNATSExporter(cfg.Event.NATS.URL, cfg.Event.NATS.Subject)
+			if err != nil {
+				return nil, err
+			}
+
+			eventOptions = append(eventOptions, event.WithExporters(natsExporter))
+		}
+
+		s.eventBus = event.New(eventOptions...)
+		schedulingOptions = append(schedulingOptions, scheduling.WithEventBus(s.eventBus))
 	}
 
 	// Initialize scheduling.
-	scheduling := scheduling.New(&cfg.Scheduler, dynconfig, d.PluginDir())
+	scheduling := scheduling.New(&cfg.Scheduler, dynconfig, d.PluginDir(), schedulingOptions...)
+
+	// Initialize job service.
+	if cfg.Job.Enable && pkgredis.IsEnabled(cfg.Database.Redis.Addrs) {
+		s.job, err = job.New(cfg, s.resource, scheduling, dfdaemonDialOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Initialize server options of scheduler grpc server.
 	schedulerServerOptions := []grpc.ServerOption{}
@@ -276,12 +460,39 @@ func New(ctx context.Context, cfg *config.Config, d dfpath.Dfpath) (*Server, err
 			return nil, err
 		}
 
+		schedulerServerOptions = append(schedulerServerOptions, grpc.Creds(serverTransportCredentials))
+	} else if s.certReloader != nil {
+		serverTransportCredentials, err := rpc.NewServerCredentialsByCertReloader(cfg.Security.TLSPolicy, cfg.Security.TLSVerify, s.certReloader)
+		if err != nil {
+			return nil, err
+		}
+
 		schedulerServerOptions = append(schedulerServerOptions, grpc.Creds(serverTransportCredentials))
 	} else {
 		schedulerServerOptions = append(schedulerServerOptions, grpc.Creds(insecure.NewCredentials()))
 	}
 
-	svr := rpcserver.New(cfg, resource, scheduling, dynconfig, s.storage, s.networkTopology, schedulerServerOptions...)
+	// Authenticate the calls of scheduler grpc server.
+	schedulerServerOptions = append(schedulerServerOptions, rpc.AuthServerOptions(rpc.NewAuthenticator(
+		rpc.WithAuthToken(cfg.Auth.Token),
+		rpc.WithAuthHMACSecret(cfg.Auth.HMACSecret),
+	))...)
+
+	// Throttle the calls of scheduler grpc server.
+	rateLimit := server.RateLimit{
+		QPS:   cfg.Server.RateLimit.QPS,
+		Burst: cfg.Server.RateLimit.Burst,
+	}
+	for _, method := range cfg.Server.RateLimit.Methods {
+		rateLimit.Methods = append(rateLimit.Methods, server.MethodRateLimit{
+			Method:      method.Method,
+			QPS:         method.QPS,
+			Burst:       method.Burst,
+			MaxInflight: method.MaxInflight,
+		})
+	}
+
+	svr := rpcserver.New(cfg, s.resource, scheduling, dynconfig, s.storage, s.networkTopology, s.eventBus, rateLimit, schedulerServerOptions...)
 	s.grpcServer = svr
 
 	// Initialize metrics.
@@ -289,6 +500,18 @@ func New(ctx context.Context, cfg *config.Config, d dfpath.Dfpath) (*Server, err
 		s.metricsServer = metrics.New(&cfg.Metrics, s.grpcServer)
 	}
 
+	// Initialize profiler.
+	if cfg.Profiling.Enable {
+		s.profiler = profiler.New(cfg.Profiling.Addr, fmt.Sprintf("dragonfly-%s", types.SchedulerName),
+			profiler.WithInterval(cfg.Profiling.Interval),
+			profiler.WithLabels(map[string]string{
+				"component": types.SchedulerName,
+				"cluster":   strconv.FormatUint(uint64(cfg.Manager.SchedulerClusterID), 10),
+				"version":   version.GitVersion,
+				"hostname":  cfg.Server.Host,
+			}))
+	}
+
 	return s, nil
 }
 
@@ -327,6 +550,18 @@ func (s *Server) Serve() error {
 		}()
 	}
 
+	// Serve profiler.
+	if s.profiler != nil {
+		go s.profiler.Serve()
+		logger.Info("profiler start successfully")
+	}
+
+	// Serve event bus.
+	if s.eventBus != nil {
+		go s.eventBus.Serve()
+		logger.Info("event bus start successfully")
+	}
+
 	// Serve announcer.
 	go func() {
 		s.announcer.Serve()
@@ -341,6 +576,70 @@ func (s *Server) Serve() error {
 		}()
 	}
 
+	// Serve snapshotter.
+	if s.snapshotter != nil {
+		go func() {
+			s.snapshotter.Serve()
+			logger.Info("snapshotter start successfully")
+		}()
+	}
+
+	// Serve prober.
+	if s.prober != nil {
+		go func() {
+			s.prober.Serve()
+			logger.Info("prober start successfully")
+		}()
+	}
+
+	// Serve seed peer elector.
+	if s.seedPeerElector != nil {
+		go func() {
+			s.seedPeerElector.Serve()
+			logger.Info("seed peer elector start successfully")
+		}()
+	}
+
+	// Serve replicator.
+	if s.replicator != nil {
+		go func() {
+			s.replicator.Serve()
+			logger.Info("replicator start successfully")
+		}()
+	}
+
+	// Serve flash crowd detector.
+	if s.flashCrowdDetector != nil {
+		go func() {
+			s.flashCrowdDetector.Serve()
+			logger.Info("flash crowd detector start successfully")
+		}()
+	}
+
+	// Serve federation.
+	if s.federation != nil {
+		go func() {
+			s.federation.Serve()
+			logger.Info("federation start successfully")
+		}()
+	}
+
+	// Serve inference.
+	if s.inference != nil {
+		go func() {
+			s.inference.Serve()
+			logger.Info("inference start successfully")
+		}()
+	}
+
+	// Serve cert reloader.
+	if s.certReloader != nil {
+		go func() {
+			s.certReloader.Serve()
+			logger.Info("cert reloader start successfully")
+		}()
+	}
+
 	// Generate GRPC limit listener.
 	ip, ok := ip.FormatIP(s.config.Server.ListenIP.String())
 	if !ok {
@@ -372,6 +671,12 @@ func (s *Server) Stop() {
 		logger.Info("stop dynconfig closed")
 	}
 
+	// Stop snapshotter and take the last snapshot.
+	if s.snapshotter != nil {
+		s.snapshotter.Stop()
+		logger.Info("snapshotter closed")
+	}
+
 	// Stop resource.
 	if err := s.resource.Stop(); err != nil {
 		logger.Errorf("stop resource failed %s", err.Error())
@@ -397,6 +702,12 @@ func (s *Server) Stop() {
 	s.gc.Stop()
 	logger.Info("gc closed")
 
+	// Stop cert reloader.
+	if s.certReloader != nil {
+		s.certReloader.Stop()
+		logger.Info("cert reloader closed")
+	}
+
 	// Stop metrics server.
 	if s.metricsServer != nil {
 		if err := s.metricsServer.Shutdown(context.Background()); err != nil {
@@ -406,6 +717,18 @@ func (s *Server) Stop() {
 		}
 	}
 
+	// Stop profiler.
+	if s.profiler != nil {
+		s.profiler.Stop()
+		logger.Info("profiler closed")
+	}
+
+	// Stop event bus.
+	if s.eventBus != nil {
+		s.eventBus.Stop()
+		logger.Info("event bus closed")
+	}
+
 	// Stop announcer.
 	s.announcer.Stop()
 	logger.Info("stop announcer closed")
@@ -428,6 +751,15 @@ func (s *Server) Stop() {
 		}
 	}
 
+	// Stop inference client.
+	if s.inferenceClient != nil {
+		if err := s.inferenceClient.Close(); err != nil {
+			logger.Errorf("inference client failed to stop: %s", err.Error())
+		} else {
+			logger.Info("inference client closed")
+		}
+	}
+
 	// Stop security client.
 	if s.securityClient != nil {
 		if err := s.securityClient.Close(); err != nil {
@@ -437,6 +769,42 @@ func (s *Server) Stop() {
 		}
 	}
 
+	// Stop prober.
+	if s.prober != nil {
+		s.prober.Stop()
+		logger.Info("prober closed")
+	}
+
+	// Stop seed peer elector.
+	if s.seedPeerElector != nil {
+		s.seedPeerElector.Stop()
+		logger.Info("seed peer elector closed")
+	}
+
+	// Stop replicator.
+	if s.replicator != nil {
+		s.replicator.Stop()
+		logger.Info("replicator closed")
+	}
+
+	// Stop flash crowd detector.
+	if s.flashCrowdDetector != nil {
+		s.flashCrowdDetector.Stop()
+		logger.Info("flash crowd detector closed")
+	}
+
+	// Stop federation.
+	if s.federation != nil {
+		s.federation.Stop()
+		logger.Info("federation closed")
+	}
+
+	// Stop inference.
+	if s.inference != nil {
+		s.inference.Stop()
+		logger.Info("inference closed")
+	}
+
 	// Stop network topology.
 	if s.networkTopology != nil {
 		s.networkTopology.Stop()
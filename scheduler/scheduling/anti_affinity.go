@@ -0,0 +1,65 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/anti_affinity_mock.go -source anti_affinity.go -package mocks
+
+package scheduling
+
+import (
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// AntiAffinity is the interface used for checking the anti-affinity between the peer and the parent,
+// the labels are configured by the application of the task in manager, e.g. the parent is never
+// scheduled on the same hypervisor as the peer in the bare-metal multi-tenant clusters.
+type AntiAffinity interface {
+	// Conflict returns whether the parent shares any anti-affinity label with the peer.
+	Conflict(peer *resource.Peer, parent *resource.Peer) bool
+}
+
+// antiAffinity implements AntiAffinity.
+type antiAffinity struct {
+	// dynconfig is the scheduler dynamic configuration.
+	dynconfig config.DynconfigInterface
+}
+
+// NewAntiAffinity returns a new AntiAffinity interface, the labels are loaded
+// from the application policies in the client config of the scheduler cluster.
+func NewAntiAffinity(dynconfig config.DynconfigInterface) AntiAffinity {
+	return &antiAffinity{dynconfig: dynconfig}
+}
+
+// Conflict returns whether the parent shares any anti-affinity label with the peer.
+func (a *antiAffinity) Conflict(peer *resource.Peer, parent *resource.Peer) bool {
+	if peer.Task.Application == "" {
+		return false
+	}
+
+	policy, ok := resource.LoadApplicationPolicy(a.dynconfig, peer.Task.Application)
+	if !ok {
+		return false
+	}
+
+	for _, label := range policy.AntiAffinity {
+		if value := peer.Host.Topology.Label(label); value != "" && value == parent.Host.Topology.Label(label) {
+			peer.Log.Debugf("parent %s conflicts with anti-affinity label %s %s", parent.ID, label, value)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/pkg/types"
+	configmocks "d7y.io/dragonfly/v2/scheduler/config/mocks"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+func TestAntiAffinity_Conflict(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(peer *resource.Peer, parent *resource.Peer, md *configmocks.MockDynconfigInterfaceMockRecorder)
+		expect func(t *testing.T, conflict bool)
+	}{
+		{
+			name: "get application policies failed",
+			mock: func(peer *resource.Peer, parent *resource.Peer, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, errors.New("foo")).Times(1)
+			},
+			expect: func(t *testing.T, conflict bool) {
+				assert.False(t, conflict)
+			},
+		},
+		{
+			name: "application of task is not found",
+			mock: func(peer *resource.Peer, parent *resource.Peer, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetApplicationPolicies().Return(map[string]rpc.ApplicationPolicy{"bar": {}}, nil).Times(1)
+			},
+			expect: func(t *testing.T, conflict bool) {
+				assert.False(t, conflict)
+			},
+		},
+		{
+			name: "parent is on the same hypervisor",
+			mock: func(peer *resource.Peer, parent *resource.Peer, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				peer.Host.Topology = types.Topology{Hypervisor: "foo"}
+				parent.Host.Topology = types.Topology{Hypervisor: "foo"}
+				policies := map[string]rpc.ApplicationPolicy{mockTaskApplication: {AntiAffinity: []string{types.TopologyLabelHypervisor}}}
+				md.GetApplicationPolicies().Return(policies, nil).Times(1)
+			},
+			expect: func(t *testing.T, conflict bool) {
+				assert.True(t, conflict)
+			},
+		},
+		{
+			name: "parent is on the different hypervisor",
+			mock: func(peer *resource.Peer, parent *resource.Peer, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				peer.Host.Topology = types.Topology{Rack: "foo", Hypervisor: "foo"}
+				parent.Host.Topology = types.Topology{Rack: "foo", Hypervisor: "bar"}
+				policies := map[string]rpc.ApplicationPolicy{mockTaskApplication: {AntiAffinity: []string{types.TopologyLabelHypervisor}}}
+				md.GetApplicationPolicies().Return(policies, nil).Times(1)
+			},
+			expect: func(t *testing.T, conflict bool) {
+				assert.False(t, conflict)
+			},
+		},
+		{
+			name: "hypervisor of peer is empty",
+			mock: func(peer *resource.Peer, parent *resource.Peer, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				policies := map[string]rpc.ApplicationPolicy{mockTaskApplication: {AntiAffinity: []string{types.TopologyLabelHypervisor}}}
+				md.GetApplicationPolicies().Return(policies, nil).Times(1)
+			},
+			expect: func(t *testing.T, conflict bool) {
+				assert.False(t, conflict)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockParentHost := resource.NewHost(
+				mockRawSeedHost.ID, mockRawSeedHost.IP, mockRawSeedHost.Hostname,
+				mockRawSeedHost.Port, mockRawSeedHost.DownloadPort, mockRawSeedHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+			parent := resource.NewPeer(mockSeedPeerID, mockResourceConfig, mockTask, mockParentHost)
+
+			tc.mock(peer, parent, dynconfig.EXPECT())
+			tc.expect(t, NewAntiAffinity(dynconfig).Conflict(peer, parent))
+		})
+	}
+}
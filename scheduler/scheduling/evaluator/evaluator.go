@@ -17,6 +17,13 @@
 package evaluator
 
 import (
+	"context"
+	"time"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
+	"d7y.io/dragonfly/v2/scheduler/inference"
+	"d7y.io/dragonfly/v2/scheduler/networktopology"
 	"d7y.io/dragonfly/v2/scheduler/resource"
 )
 
@@ -31,6 +38,81 @@ const (
 	PluginAlgorithm = "plugin"
 )
 
+const (
+	// DefaultPluginWeight is default weight of the score supplied by scorer plugin.
+	DefaultPluginWeight = 0.5
+
+	// DefaultLatencyBudget is default latency budget of inference.
+	DefaultLatencyBudget = 50 * time.Millisecond
+)
+
+// DefaultWeights is default weights of factors evaluated by the default algorithm if the
+// evaluator weights of scheduler cluster config are not configured, free bandwidth and
+// topology affinity are not weighted by default. The default scheduler cluster created
+// by manager weights free bandwidth, refer to types.DefaultSchedulerClusterEvaluatorWeights.
+var DefaultWeights = Weights{
+	FinishedPiece:           0.2,
+	ParentHostUploadSuccess: 0.2,
+	FreeUpload:              0.15,
+	FreeBandwidth:           0,
+	HostType:                0.15,
+	IDCAffinity:             0.15,
+	LocationAffinity:        0.15,
+	TopologyAffinity:        0,
+}
+
+// Weights is the weights of factors evaluated by the default algorithm.
+type Weights struct {
+	// FinishedPiece is the weight of finished pieces.
+	FinishedPiece float64
+
+	// ParentHostUploadSuccess is the weight of parent's host upload success rate.
+	ParentHostUploadSuccess float64
+
+	// FreeUpload is the weight of free upload slots.
+	FreeUpload float64
+
+	// FreeBandwidth is the weight of free bandwidth.
+	FreeBandwidth float64
+
+	// HostType is the weight of host type.
+	HostType float64
+
+	// IDCAffinity is the weight of IDC affinity.
+	IDCAffinity float64
+
+	// LocationAffinity is the weight of location affinity.
+	LocationAffinity float64
+
+	// TopologyAffinity is the weight of topology affinity.
+	TopologyAffinity float64
+}
+
+// DefaultTopologyWeights is default weights of topology levels.
+var DefaultTopologyWeights = TopologyWeights{
+	Region: 0.05,
+	Zone:   0.05,
+	Rack:   0.03,
+	Switch: 0.02,
+}
+
+// TopologyWeights is the relative weights of topology levels, the weight of level is accumulated
+// when parent and child are in the same failure domain of the level, and the topology affinity
+// score is the accumulated weight divided by the sum of weights.
+type TopologyWeights struct {
+	// Region is the weight of the same region.
+	Region float64
+
+	// Zone is the weight of the same zone.
+	Zone float64
+
+	// Rack is the weight of the same rack.
+	Rack float64
+
+	// Switch is the weight of the same switch.
+	Switch float64
+}
+
 type Evaluator interface {
 	// Evaluate todo Normalization.
 	Evaluate(parent *resource.Peer, child *resource.Peer, taskPieceCount int32) float64
@@ -39,16 +121,181 @@ type Evaluator interface {
 	IsBadNode(peer *resource.Peer) bool
 }
 
-func New(algorithm string, pluginDir string) Evaluator {
+// Scorer is the interface of evaluator plugin supplying custom scoring function,
+// e.g. rack-aware and cost-aware scoring. The score is blended with the default algorithm
+// by plugin weight, and the bad nodes are still determined by the default algorithm.
+type Scorer interface {
+	// Score returns the score of parent in [0, 1], the larger the score, the higher the priority.
+	Score(parent *resource.Peer, child *resource.Peer, totalPieceCount int32) float64
+}
+
+// BatchEvaluator is the optional interface of evaluator scoring the candidate parents in a batch.
+type BatchEvaluator interface {
+	// EvaluateParents returns the scores of parents, the order of scores is the same as parents.
+	EvaluateParents(parents []*resource.Peer, child *resource.Peer, totalPieceCount int32) []float64
+}
+
+// Inferencer is the interface of scoring the host graph of candidate parents by the trained model.
+type Inferencer interface {
+	// Infer returns the score of each edge of the graph.
+	Infer(context.Context, *inference.Graph) ([]float32, error)
+}
+
+// EvaluateParents returns the scores of parents, the parents are scored in a batch
+// if the evaluator implements BatchEvaluator.
+func EvaluateParents(e Evaluator, parents []*resource.Peer, child *resource.Peer, totalPieceCount int32) []float64 {
+	if be, ok := e.(BatchEvaluator); ok {
+		return be.EvaluateParents(parents, child, totalPieceCount)
+	}
+
+	scores := make([]float64, 0, len(parents))
+	for _, parent := range parents {
+		scores = append(scores, e.Evaluate(parent, child, totalPieceCount))
+	}
+
+	return scores
+}
+
+// Option is a functional option for configuring the evaluator.
+type Option func(o *options)
+
+// options is the options of evaluator.
+type options struct {
+	// pluginOptions is passed to the init function of plugin.
+	pluginOptions map[string]string
+
+	// pluginWeight is the weight of score supplied by scorer plugin.
+	pluginWeight float64
+
+	// weights loads the weights of factors, it is called for every evaluation,
+	// so the weights can be tuned at runtime.
+	weights func() Weights
+
+	// topologyWeights is the weights of topology levels.
+	topologyWeights TopologyWeights
+
+	// inferencer scores the candidate parents by the trained model.
+	inferencer Inferencer
+
+	// networkTopology supplies the probes between hosts to machine learning algorithm.
+	networkTopology networktopology.NetworkTopology
+
+	// latencyBudget is the latency budget of inference.
+	latencyBudget time.Duration
+
+	// featureFlags loads the feature flags rolled out to the scheduler.
+	featureFlags func() featureflag.Flags
+}
+
+// WithPluginOptions sets the options passed to the init function of plugin.
+func WithPluginOptions(pluginOptions map[string]string) Option {
+	return func(o *options) {
+		if pluginOptions != nil {
+			o.pluginOptions = pluginOptions
+		}
+	}
+}
+
+// WithPluginWeight sets the weight of score supplied by scorer plugin.
+func WithPluginWeight(weight float64) Option {
+	return func(o *options) {
+		o.pluginWeight = weight
+	}
+}
+
+// WithWeights sets the loader of weights of factors.
+func WithWeights(weights func() Weights) Option {
+	return func(o *options) {
+		if weights != nil {
+			o.weights = weights
+		}
+	}
+}
+
+// WithTopologyWeights sets the weights of topology levels.
+func WithTopologyWeights(weights TopologyWeights) Option {
+	return func(o *options) {
+		o.topologyWeights = weights
+	}
+}
+
+// WithInferencer sets the inferencer used by machine learning algorithm.
+func WithInferencer(inferencer Inferencer) Option {
+	return func(o *options) {
+		o.inferencer = inferencer
+	}
+}
+
+// WithNetworkTopology sets the network topology used by machine learning algorithm.
+func WithNetworkTopology(networkTopology networktopology.NetworkTopology) Option {
+	return func(o *options) {
+		o.networkTopology = networkTopology
+	}
+}
+
+// WithLatencyBudget sets the latency budget of inference.
+func WithLatencyBudget(budget time.Duration) Option {
+	return func(o *options) {
+		if budget > 0 {
+			o.latencyBudget = budget
+		}
+	}
+}
+
+// WithFeatureFlags sets the loader of feature flags, the machine learning algorithm
+// is rolled out to the scheduler of default algorithm by feature flag.
+func WithFeatureFlags(featureFlags func() featureflag.Flags) Option {
+	return func(o *options) {
+		o.featureFlags = featureFlags
+	}
+}
+
+// newOptions returns the options of evaluator.
+func newOptions(opts ...Option) *options {
+	o := &options{
+		pluginOptions:   map[string]string{},
+		pluginWeight:    DefaultPluginWeight,
+		weights:         func() Weights { return DefaultWeights },
+		topologyWeights: DefaultTopologyWeights,
+		latencyBudget:   DefaultLatencyBudget,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func New(algorithm string, pluginDir string, opts ...Option) Evaluator {
 	switch algorithm {
 	case PluginAlgorithm:
-		if plugin, err := LoadPlugin(pluginDir); err == nil {
+		plugin, err := LoadPlugin(pluginDir, opts...)
+		if err == nil {
 			return plugin
 		}
-	// TODO Implement MLAlgorithm.
-	case MLAlgorithm, DefaultAlgorithm:
-		return NewEvaluatorBase()
+
+		logger.Errorf("load evaluator plugin failed, fall back to default algorithm: %s", err.Error())
+	case MLAlgorithm:
+		o := newOptions(opts...)
+		if o.inferencer != nil {
+			return newEvaluatorML(o)
+		}
+
+		logger.Error("inferencer is not set, fall back to default algorithm")
+	case DefaultAlgorithm:
+		o := newOptions(opts...)
+		if o.inferencer != nil && o.featureFlags != nil {
+			evaluator := newEvaluatorML(o)
+			evaluator.enabled = func() bool {
+				return o.featureFlags().Enabled(featureflag.MLEvaluator)
+			}
+
+			return evaluator
+		}
+
+		return NewEvaluatorBase(opts...)
 	}
 
-	return NewEvaluatorBase()
+	return NewEvaluatorBase(opts...)
 }
@@ -28,26 +28,6 @@ import (
 	"d7y.io/dragonfly/v2/scheduler/resource"
 )
 
-const (
-	// Finished piece weight.
-	finishedPieceWeight float64 = 0.2
-
-	// Parent's host upload success weight.
-	parentHostUploadSuccessWeight = 0.2
-
-	// Free upload weight.
-	freeUploadWeight = 0.15
-
-	// Host type weight.
-	hostTypeWeight = 0.15
-
-	// IDC affinity weight.
-	idcAffinityWeight = 0.15
-
-	// Location affinity weight.
-	locationAffinityWeight = 0.15
-)
-
 const (
 	// Maximum score.
 	maxScore float64 = 1
@@ -69,10 +49,17 @@ const (
 	maxElementLen = 5
 )
 
-type evaluatorBase struct{}
+type evaluatorBase struct {
+	// weights loads the weights of factors.
+	weights func() Weights
+
+	// topologyWeights is the weights of topology levels.
+	topologyWeights TopologyWeights
+}
 
-func NewEvaluatorBase() Evaluator {
-	return &evaluatorBase{}
+func NewEvaluatorBase(opts ...Option) Evaluator {
+	o := newOptions(opts...)
+	return &evaluatorBase{weights: o.weights, topologyWeights: o.topologyWeights}
 }
 
 // The larger the value after evaluation, the higher the priority.
@@ -81,13 +68,16 @@ func (eb *evaluatorBase) Evaluate(parent *resource.Peer, child *resource.Peer, t
 	parentIDC := parent.Host.Network.IDC
 	childLocation := child.Host.Network.Location
 	childIDC := child.Host.Network.IDC
-
-	return finishedPieceWeight*calculatePieceScore(parent, child, totalPieceCount) +
-		parentHostUploadSuccessWeight*calculateParentHostUploadSuccessScore(parent) +
-		freeUploadWeight*calculateFreeUploadScore(parent.Host) +
-		hostTypeWeight*calculateHostTypeScore(parent) +
-		idcAffinityWeight*calculateIDCAffinityScore(parentIDC, childIDC) +
-		locationAffinityWeight*calculateMultiElementAffinityScore(parentLocation, childLocation)
+	weights := eb.weights()
+
+	return weights.FinishedPiece*calculatePieceScore(parent, child, totalPieceCount) +
+		weights.ParentHostUploadSuccess*calculateParentHostUploadSuccessScore(parent) +
+		weights.FreeUpload*calculateFreeUploadScore(parent.Host) +
+		weights.FreeBandwidth*calculateFreeBandwidthScore(parent.Host) +
+		weights.HostType*calculateHostTypeScore(parent) +
+		weights.IDCAffinity*calculateIDCAffinityScore(parentIDC, childIDC) +
+		weights.LocationAffinity*calculateMultiElementAffinityScore(parentLocation, childLocation) +
+		weights.TopologyAffinity*calculateTopologyAffinityScore(parent.Host.Topology, child.Host.Topology, eb.topologyWeights)
 }
 
 // calculatePieceScore 0.0~unlimited larger and better.
@@ -133,8 +123,18 @@ func calculateFreeUploadScore(host *resource.Host) float64 {
 	return minScore
 }
 
+// calculateFreeBandwidthScore 0.0~1.0 larger and better.
+func calculateFreeBandwidthScore(host *resource.Host) float64 {
+	return host.UploadThroughput.FreeRatio()
+}
+
 // calculateHostTypeScore 0.0~1.0 larger and better.
 func calculateHostTypeScore(peer *resource.Peer) float64 {
+	// Peer promoted as the temporary seed peer of the hot task is scheduled first.
+	if peer.PromotedSeed.Load() {
+		return maxScore
+	}
+
 	// When the task is downloaded for the first time,
 	// peer will be scheduled to seed peer first,
 	// otherwise it will be scheduled to dfdaemon first.
@@ -195,6 +195,33 @@ func calculateMultiElementAffinityScore(dst, src string) float64 {
 	return float64(score) / float64(maxElementLen)
 }
 
+// calculateTopologyAffinityScore 0.0~1.0 larger and better, the weights of levels are
+// accumulated from region to switch until the labels are different or empty,
+// and normalized by the sum of weights.
+func calculateTopologyAffinityScore(dst, src types.Topology, weights TopologyWeights) float64 {
+	levelWeights := []float64{weights.Region, weights.Zone, weights.Rack, weights.Switch}
+	var sum float64
+	for _, weight := range levelWeights {
+		sum += weight
+	}
+
+	if sum <= 0 {
+		return minScore
+	}
+
+	var score float64
+	dstLevels, srcLevels := dst.Levels(), src.Levels()
+	for i, weight := range levelWeights {
+		if dstLevels[i] == "" || dstLevels[i] != srcLevels[i] {
+			break
+		}
+
+		score += weight
+	}
+
+	return score / sum
+}
+
 func (eb *evaluatorBase) IsBadNode(peer *resource.Peer) bool {
 	if peer.FSM.Is(resource.PeerStateFailed) || peer.FSM.Is(resource.PeerStateLeave) || peer.FSM.Is(resource.PeerStatePending) ||
 		peer.FSM.Is(resource.PeerStateReceivedTiny) || peer.FSM.Is(resource.PeerStateReceivedSmall) ||
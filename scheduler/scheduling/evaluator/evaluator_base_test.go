@@ -450,6 +450,43 @@ func TestEvaluatorBase_calculateFreeUploadScore(t *testing.T) {
 	}
 }
 
+func TestEvaluatorBase_calculateFreeBandwidthScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(host *resource.Host)
+		expect func(t *testing.T, score float64)
+	}{
+		{
+			name: "host has not uploaded",
+			mock: func(host *resource.Host) {},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.Equal(score, float64(1))
+			},
+		},
+		{
+			name: "host is uploading",
+			mock: func(host *resource.Host) {
+				host.UploadThroughput.Observe(1024)
+			},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.True(score >= minScore && score <= maxScore)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			host := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			tc.mock(host)
+			tc.expect(t, calculateFreeBandwidthScore(host))
+		})
+	}
+}
+
 func TestEvaluatorBase_calculateHostTypeScore(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -486,6 +523,17 @@ func TestEvaluatorBase_calculateHostTypeScore(t *testing.T) {
 				assert.Equal(score, float64(1))
 			},
 		},
+		{
+			name: "peer is promoted as temporary seed peer",
+			mock: func(peer *resource.Peer) {
+				peer.FSM.SetState(resource.PeerStateSucceeded)
+				peer.PromotedSeed.Store(true)
+			},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.Equal(score, float64(1))
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -700,6 +748,135 @@ func TestEvaluatorBase_calculateMultiElementAffinityScore(t *testing.T) {
 	}
 }
 
+func TestEvaluatorBase_calculateTopologyAffinityScore(t *testing.T) {
+	mockTopologyWeightSum := DefaultTopologyWeights.Region + DefaultTopologyWeights.Zone +
+		DefaultTopologyWeights.Rack + DefaultTopologyWeights.Switch
+
+	tests := []struct {
+		name   string
+		dst    types.Topology
+		src    types.Topology
+		expect func(t *testing.T, score float64)
+	}{
+		{
+			name: "topology is empty",
+			dst:  types.Topology{},
+			src:  types.Topology{},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.Equal(score, float64(0))
+			},
+		},
+		{
+			name: "region does not match",
+			dst:  types.Topology{Region: "foo", Zone: "bar"},
+			src:  types.Topology{Region: "baz", Zone: "bar"},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.Equal(score, float64(0))
+			},
+		},
+		{
+			name: "region and zone match",
+			dst:  types.Topology{Region: "foo", Zone: "bar", Rack: "baz"},
+			src:  types.Topology{Region: "foo", Zone: "bar", Rack: "bas"},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(score, (DefaultTopologyWeights.Region+DefaultTopologyWeights.Zone)/mockTopologyWeightSum, 1e-9)
+			},
+		},
+		{
+			name: "rack label is empty",
+			dst:  types.Topology{Region: "foo", Zone: "bar", Switch: "qux"},
+			src:  types.Topology{Region: "foo", Zone: "bar", Switch: "qux"},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(score, (DefaultTopologyWeights.Region+DefaultTopologyWeights.Zone)/mockTopologyWeightSum, 1e-9)
+			},
+		},
+		{
+			name: "all levels match",
+			dst:  types.Topology{Region: "foo", Zone: "bar", Rack: "baz", Switch: "qux"},
+			src:  types.Topology{Region: "foo", Zone: "bar", Rack: "baz", Switch: "qux"},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(score, float64(1), 1e-9)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expect(t, calculateTopologyAffinityScore(tc.dst, tc.src, DefaultTopologyWeights))
+		})
+	}
+}
+
+func TestEvaluatorBase_calculateTopologyAffinityScoreWithoutWeights(t *testing.T) {
+	topology := types.Topology{Region: "foo", Zone: "bar", Rack: "baz", Switch: "qux"}
+	assert.Equal(t, float64(0), calculateTopologyAffinityScore(topology, topology, TopologyWeights{}))
+}
+
+func TestEvaluatorBase_EvaluateWithTopologyAffinity(t *testing.T) {
+	newPeer := func(rawHost *resource.Host, topology types.Topology) *resource.Peer {
+		return resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig,
+			resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength)),
+			resource.NewHost(
+				rawHost.ID, rawHost.IP, rawHost.Hostname,
+				rawHost.Port, rawHost.DownloadPort, rawHost.Type, resource.WithTopology(topology)))
+	}
+
+	topology := types.Topology{Region: "foo", Zone: "bar", Rack: "baz", Switch: "qux"}
+	tests := []struct {
+		name    string
+		weights Weights
+		expect  func(t *testing.T, score float64)
+	}{
+		{
+			name:    "topology affinity is not weighted",
+			weights: DefaultWeights,
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.Equal(score, float64(0.35))
+			},
+		},
+		{
+			name:    "topology affinity is weighted",
+			weights: Weights{ParentHostUploadSuccess: 0.5, TopologyAffinity: 0.5},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(score, float64(1), 1e-9)
+			},
+		},
+		{
+			name: "score does not exceed 1 with normalized weights",
+			weights: Weights{
+				FinishedPiece:           0.2,
+				ParentHostUploadSuccess: 0.2,
+				FreeUpload:              0.15,
+				HostType:                0.15,
+				IDCAffinity:             0.1,
+				LocationAffinity:        0.1,
+				TopologyAffinity:        0.1,
+			},
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.True(score >= minScore && score <= maxScore)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			weights := tc.weights
+			eb := NewEvaluatorBase(WithWeights(func() Weights { return weights }))
+			parent := newPeer(&mockRawSeedHost, topology)
+			child := newPeer(&mockRawHost, topology)
+			tc.expect(t, eb.Evaluate(parent, child, 1))
+		})
+	}
+}
+
 func TestEvaluatorBase_IsBadNode(t *testing.T) {
 	mockHost := resource.NewHost(
 		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
@@ -0,0 +1,162 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/inference"
+	"d7y.io/dragonfly/v2/scheduler/networktopology"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// evaluatorML scores the candidate parents by the trained GNN model, and falls back
+// to the default algorithm when the inference fails or exceeds the latency budget.
+type evaluatorML struct {
+	// base is the evaluator of default algorithm.
+	base Evaluator
+
+	// inferencer scores the graph by the model.
+	inferencer Inferencer
+
+	// networkTopology supplies the probes between hosts.
+	networkTopology networktopology.NetworkTopology
+
+	// latencyBudget is the latency budget of inference.
+	latencyBudget time.Duration
+
+	// enabled returns whether the model is used to score, the default algorithm is used
+	// if it returns false. The model is always used if enabled is nil.
+	enabled func() bool
+}
+
+// newEvaluatorML returns a new evaluator of machine learning algorithm.
+func newEvaluatorML(o *options) *evaluatorML {
+	return &evaluatorML{
+		base:            &evaluatorBase{weights: o.weights, topologyWeights: o.topologyWeights},
+		inferencer:      o.inferencer,
+		networkTopology: o.networkTopology,
+		latencyBudget:   o.latencyBudget,
+	}
+}
+
+// Evaluate returns the score of parent by the model.
+func (e *evaluatorML) Evaluate(parent *resource.Peer, child *resource.Peer, totalPieceCount int32) float64 {
+	return e.EvaluateParents([]*resource.Peer{parent}, child, totalPieceCount)[0]
+}
+
+// EvaluateParents returns the scores of parents by the model in a batch.
+func (e *evaluatorML) EvaluateParents(parents []*resource.Peer, child *resource.Peer, totalPieceCount int32) []float64 {
+	if e.enabled != nil && !e.enabled() {
+		return EvaluateParents(e.base, parents, child, totalPieceCount)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.latencyBudget)
+	defer cancel()
+
+	outputs, err := e.inferencer.Infer(ctx, e.graph(parents, child))
+	if err == nil && len(outputs) != len(parents) {
+		err = fmt.Errorf("invalid score count %d, expected %d", len(outputs), len(parents))
+	}
+
+	if err != nil {
+		child.Log.Warnf("inference failed, fall back to default algorithm: %s", err.Error())
+		return EvaluateParents(e.base, parents, child, totalPieceCount)
+	}
+
+	scores := make([]float64, 0, len(outputs))
+	for _, output := range outputs {
+		scores = append(scores, float64(output))
+	}
+
+	return scores
+}
+
+// IsBadNode determines if peer is a failed node by the default algorithm.
+func (e *evaluatorML) IsBadNode(peer *resource.Peer) bool {
+	return e.base.IsBadNode(peer)
+}
+
+// graph returns the host graph of the child and parents, the first node is the host of child,
+// and each parent has an edge from its host to the host of child.
+func (e *evaluatorML) graph(parents []*resource.Peer, child *resource.Peer) *inference.Graph {
+	graph := &inference.Graph{
+		NodeFeatures: [][]float32{nodeFeatures(child.Host)},
+		Edges:        make([][2]int64, 0, len(parents)),
+		EdgeFeatures: make([][]float32, 0, len(parents)),
+	}
+
+	nodes := map[string]int64{child.Host.ID: 0}
+	for _, parent := range parents {
+		node, ok := nodes[parent.Host.ID]
+		if !ok {
+			node = int64(len(graph.NodeFeatures))
+			nodes[parent.Host.ID] = node
+			graph.NodeFeatures = append(graph.NodeFeatures, nodeFeatures(parent.Host))
+		}
+
+		graph.Edges = append(graph.Edges, [2]int64{node, 0})
+		graph.EdgeFeatures = append(graph.EdgeFeatures, e.edgeFeatures(parent.Host, child.Host))
+	}
+
+	return graph
+}
+
+// nodeFeatures returns the features of host, the order is defined by types.GNNNodeFeatureCount.
+func nodeFeatures(host *resource.Host) []float32 {
+	var hostType, uploadSuccess float64
+	if host.Type != types.HostTypeNormal {
+		hostType = maxScore
+	}
+
+	uploadCount, uploadFailedCount := host.UploadCount.Load(), host.UploadFailedCount.Load()
+	if uploadCount == 0 {
+		uploadSuccess = maxScore
+	} else if uploadCount > uploadFailedCount {
+		uploadSuccess = float64(uploadCount-uploadFailedCount) / float64(uploadCount)
+	}
+
+	return []float32{
+		float32(hostType),
+		float32(calculateFreeUploadScore(host)),
+		float32(uploadSuccess),
+		float32(host.CPU.Percent / 100),
+		float32(host.Memory.UsedPercent / 100),
+		float32(host.Disk.UsedPercent / 100),
+	}
+}
+
+// edgeFeatures returns the features of edge from parent to child, the order is defined by types.GNNEdgeFeatureCount.
+func (e *evaluatorML) edgeFeatures(parent *resource.Host, child *resource.Host) []float32 {
+	var probed, averageRTT float64
+	if e.networkTopology != nil {
+		// The child probes the parent, so the child is the source of probes.
+		if rtt, err := e.networkTopology.Probes(child.ID, parent.ID).AverageRTT(); err == nil {
+			probed, averageRTT = maxScore, rtt.Seconds()
+		}
+	}
+
+	return []float32{
+		float32(probed),
+		float32(averageRTT),
+		float32(calculateIDCAffinityScore(parent.Network.IDC, child.Network.IDC)),
+		float32(calculateMultiElementAffinityScore(parent.Network.Location, child.Network.Location)),
+	}
+}
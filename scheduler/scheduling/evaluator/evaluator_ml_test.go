@@ -0,0 +1,201 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/pkg/featureflag"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/inference"
+	networktopologymocks "d7y.io/dragonfly/v2/scheduler/networktopology/mocks"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// inferFunc is an Inferencer returning the scores of function.
+type inferFunc func(ctx context.Context, graph *inference.Graph) ([]float32, error)
+
+func (f inferFunc) Infer(ctx context.Context, graph *inference.Graph) ([]float32, error) {
+	return f(ctx, graph)
+}
+
+func TestEvaluatorML_EvaluateParents(t *testing.T) {
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockSeedHost := resource.NewHost(
+		mockRawSeedHost.ID, mockRawSeedHost.IP, mockRawSeedHost.Hostname,
+		mockRawSeedHost.Port, mockRawSeedHost.DownloadPort, mockRawSeedHost.Type)
+	mockChildHost := resource.NewHost(
+		idgen.HostIDV2("127.0.0.3", "baz"), "127.0.0.3", "baz",
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	parents := []*resource.Peer{
+		resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockSeedHost),
+		resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockHost),
+		resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockHost),
+	}
+	child := resource.NewPeer(idgen.PeerIDV1("127.0.0.3"), mockResourceConfig, mockTask, mockChildHost)
+	baseScores := EvaluateParents(NewEvaluatorBase(), parents, child, 1)
+
+	tests := []struct {
+		name       string
+		inferencer Inferencer
+		mock       func(mn *networktopologymocks.MockNetworkTopologyMockRecorder, mp *networktopologymocks.MockProbesMockRecorder, probes *networktopologymocks.MockProbes)
+		expect     func(t *testing.T, scores []float64)
+	}{
+		{
+			name: "score parents by model",
+			inferencer: inferFunc(func(ctx context.Context, graph *inference.Graph) ([]float32, error) {
+				assert := assert.New(t)
+				assert.Len(graph.NodeFeatures, 3)
+				for _, features := range graph.NodeFeatures {
+					assert.Len(features, types.GNNNodeFeatureCount)
+				}
+				assert.Equal(float32(0), graph.NodeFeatures[0][0])
+				assert.Equal(float32(1), graph.NodeFeatures[1][0])
+				assert.Equal([][2]int64{{1, 0}, {2, 0}, {2, 0}}, graph.Edges)
+				assert.Len(graph.EdgeFeatures, 3)
+				assert.Equal([]float32{1, 0.01}, graph.EdgeFeatures[0][:2])
+				assert.Equal([]float32{0, 0}, graph.EdgeFeatures[1][:2])
+				assert.Len(graph.EdgeFeatures[2], types.GNNEdgeFeatureCount)
+				return []float32{0.9, 0.1, 0.2}, nil
+			}),
+			mock: func(mn *networktopologymocks.MockNetworkTopologyMockRecorder, mp *networktopologymocks.MockProbesMockRecorder, probes *networktopologymocks.MockProbes) {
+				gomock.InOrder(
+					mn.Probes(mockChildHost.ID, mockSeedHost.ID).Return(probes).Times(1),
+					mp.AverageRTT().Return(10*time.Millisecond, nil).Times(1),
+					mn.Probes(mockChildHost.ID, mockHost.ID).Return(probes).Times(2),
+				)
+				mp.AverageRTT().Return(time.Duration(0), errors.New("foo")).Times(2)
+			},
+			expect: func(t *testing.T, scores []float64) {
+				assert := assert.New(t)
+				assert.InDeltaSlice([]float64{0.9, 0.1, 0.2}, scores, 1e-6)
+			},
+		},
+		{
+			name: "fall back to default algorithm when inference failed",
+			inferencer: inferFunc(func(ctx context.Context, graph *inference.Graph) ([]float32, error) {
+				return nil, errors.New("foo")
+			}),
+			mock: func(mn *networktopologymocks.MockNetworkTopologyMockRecorder, mp *networktopologymocks.MockProbesMockRecorder, probes *networktopologymocks.MockProbes) {
+				mn.Probes(gomock.Any(), gomock.Any()).Return(probes).AnyTimes()
+				mp.AverageRTT().Return(time.Duration(0), errors.New("foo")).AnyTimes()
+			},
+			expect: func(t *testing.T, scores []float64) {
+				assert.Equal(t, baseScores, scores)
+			},
+		},
+		{
+			name: "fall back to default algorithm when inference exceeds the latency budget",
+			inferencer: inferFunc(func(ctx context.Context, graph *inference.Graph) ([]float32, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}),
+			mock: func(mn *networktopologymocks.MockNetworkTopologyMockRecorder, mp *networktopologymocks.MockProbesMockRecorder, probes *networktopologymocks.MockProbes) {
+				mn.Probes(gomock.Any(), gomock.Any()).Return(probes).AnyTimes()
+				mp.AverageRTT().Return(time.Duration(0), errors.New("foo")).AnyTimes()
+			},
+			expect: func(t *testing.T, scores []float64) {
+				assert.Equal(t, baseScores, scores)
+			},
+		},
+		{
+			name: "fall back to default algorithm when score count is invalid",
+			inferencer: inferFunc(func(ctx context.Context, graph *inference.Graph) ([]float32, error) {
+				return []float32{0.9}, nil
+			}),
+			mock: func(mn *networktopologymocks.MockNetworkTopologyMockRecorder, mp *networktopologymocks.MockProbesMockRecorder, probes *networktopologymocks.MockProbes) {
+				mn.Probes(gomock.Any(), gomock.Any()).Return(probes).AnyTimes()
+				mp.AverageRTT().Return(time.Duration(0), errors.New("foo")).AnyTimes()
+			},
+			expect: func(t *testing.T, scores []float64) {
+				assert.Equal(t, baseScores, scores)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			networkTopology := networktopologymocks.NewMockNetworkTopology(ctl)
+			probes := networktopologymocks.NewMockProbes(ctl)
+			tc.mock(networkTopology.EXPECT(), probes.EXPECT(), probes)
+
+			e := New(MLAlgorithm, "", WithInferencer(tc.inferencer), WithNetworkTopology(networkTopology), WithLatencyBudget(10*time.Millisecond))
+			tc.expect(t, EvaluateParents(e, parents, child, 1))
+		})
+	}
+}
+
+func TestEvaluatorML_EvaluateParentsWithoutNetworkTopology(t *testing.T) {
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	parent := resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockHost)
+	child := resource.NewPeer(idgen.PeerIDV1("127.0.0.2"), mockResourceConfig, mockTask, mockHost)
+
+	e := New(MLAlgorithm, "", WithInferencer(inferFunc(func(ctx context.Context, graph *inference.Graph) ([]float32, error) {
+		assert := assert.New(t)
+		assert.Len(graph.NodeFeatures, 1)
+		assert.Equal([][2]int64{{0, 0}}, graph.Edges)
+		assert.Equal([]float32{0, 0}, graph.EdgeFeatures[0][:2])
+		return []float32{0.5}, nil
+	})))
+	assert.InDelta(t, 0.5, e.Evaluate(parent, child, 1), 1e-6)
+}
+
+func TestEvaluatorML_EvaluateParentsWithFeatureFlags(t *testing.T) {
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	parent := resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockHost)
+	child := resource.NewPeer(idgen.PeerIDV1("127.0.0.2"), mockResourceConfig, mockTask, mockHost)
+	baseScore := NewEvaluatorBase().Evaluate(parent, child, 1)
+
+	flags := featureflag.Flags{}
+	e := New(DefaultAlgorithm, "", WithInferencer(inferFunc(func(ctx context.Context, graph *inference.Graph) ([]float32, error) {
+		return []float32{0.5}, nil
+	})), WithFeatureFlags(func() featureflag.Flags { return flags }))
+
+	assert := assert.New(t)
+	assert.Equal(baseScore, e.Evaluate(parent, child, 1))
+
+	// The model is used once the feature flag is rolled out to the scheduler.
+	flags = featureflag.Flags{featureflag.MLEvaluator}
+	assert.InDelta(0.5, e.Evaluate(parent, child, 1), 1e-6)
+
+	flags = featureflag.Flags{}
+	assert.Equal(baseScore, e.Evaluate(parent, child, 1))
+
+	// The default algorithm is used without inferencer.
+	e = New(DefaultAlgorithm, "", WithFeatureFlags(func() featureflag.Flags { return featureflag.Flags{featureflag.MLEvaluator} }))
+	assert.Equal(baseScore, e.Evaluate(parent, child, 1))
+}
@@ -0,0 +1,56 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package evaluator
+
+import (
+	"math"
+
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// evaluatorScorer blends the score of scorer with the default algorithm.
+type evaluatorScorer struct {
+	// Evaluator is the default algorithm.
+	Evaluator
+
+	// scorer supplies custom scoring function.
+	scorer Scorer
+
+	// weight is the weight of score supplied by scorer.
+	weight float64
+}
+
+// newEvaluatorScorer returns an evaluator blending the score of scorer with the default algorithm.
+func newEvaluatorScorer(scorer Scorer, weight float64, opts ...Option) Evaluator {
+	return &evaluatorScorer{
+		Evaluator: NewEvaluatorBase(opts...),
+		scorer:    scorer,
+		weight:    math.Max(minScore, math.Min(maxScore, weight)),
+	}
+}
+
+// Evaluate returns the weighted sum of default algorithm and scorer,
+// the score out of range supplied by scorer is clamped to [0, 1].
+func (es *evaluatorScorer) Evaluate(parent *resource.Peer, child *resource.Peer, totalPieceCount int32) float64 {
+	score := es.scorer.Score(parent, child, totalPieceCount)
+	if math.IsNaN(score) {
+		score = minScore
+	}
+
+	score = math.Max(minScore, math.Min(maxScore, score))
+	return (1-es.weight)*es.Evaluator.Evaluate(parent, child, totalPieceCount) + es.weight*score
+}
@@ -0,0 +1,109 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package evaluator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// scoreFunc is a Scorer returning the score of function.
+type scoreFunc func(parent *resource.Peer, child *resource.Peer, totalPieceCount int32) float64
+
+func (f scoreFunc) Score(parent *resource.Peer, child *resource.Peer, totalPieceCount int32) float64 {
+	return f(parent, child, totalPieceCount)
+}
+
+func TestEvaluatorScorer_Evaluate(t *testing.T) {
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	parent := resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockHost)
+	child := resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockHost)
+	baseScore := NewEvaluatorBase().Evaluate(parent, child, 1)
+
+	tests := []struct {
+		name   string
+		score  float64
+		weight float64
+		expect func(t *testing.T, score float64)
+	}{
+		{
+			name:   "blend score with default algorithm",
+			score:  1,
+			weight: 0.5,
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(0.5*baseScore+0.5, score, 1e-9)
+			},
+		},
+		{
+			name:   "weight is zero",
+			score:  1,
+			weight: 0,
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(baseScore, score, 1e-9)
+			},
+		},
+		{
+			name:   "weight is greater than one",
+			score:  0.3,
+			weight: 2,
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(0.3, score, 1e-9)
+			},
+		},
+		{
+			name:   "score is greater than one",
+			score:  10,
+			weight: 1,
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(maxScore, score, 1e-9)
+			},
+		},
+		{
+			name:   "score is NaN",
+			score:  math.NaN(),
+			weight: 1,
+			expect: func(t *testing.T, score float64) {
+				assert := assert.New(t)
+				assert.InDelta(minScore, score, 1e-9)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newEvaluatorScorer(scoreFunc(func(*resource.Peer, *resource.Peer, int32) float64 {
+				return tc.score
+			}), tc.weight)
+			tc.expect(t, e.Evaluate(parent, child, 1))
+			assert.Equal(t, NewEvaluatorBase().IsBadNode(parent), e.IsBadNode(parent))
+		})
+	}
+}
@@ -26,14 +26,21 @@ const (
 	pluginName = "evaluator"
 )
 
-func LoadPlugin(dir string) (Evaluator, error) {
-	client, _, err := dfplugin.Load(dir, dfplugin.PluginTypeScheduler, pluginName, map[string]string{})
+// LoadPlugin loads the evaluator plugin, the plugin implements either Evaluator
+// replacing the default algorithm, or Scorer blended with the default algorithm.
+func LoadPlugin(dir string, opts ...Option) (Evaluator, error) {
+	o := newOptions(opts...)
+	client, _, err := dfplugin.Load(dir, dfplugin.PluginTypeScheduler, pluginName, o.pluginOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	if rc, ok := client.(Evaluator); ok {
-		return rc, err
+	switch rc := client.(type) {
+	case Evaluator:
+		return rc, nil
+	case Scorer:
+		return newEvaluatorScorer(rc, o.pluginWeight, opts...), nil
 	}
+
 	return nil, errors.New("invalid evaluator plugin")
 }
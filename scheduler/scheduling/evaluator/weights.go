@@ -0,0 +1,132 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package evaluator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+// DynamicWeights is the weights of factors tuned by the scheduler cluster config,
+// the weights are updated when dynconfig is refreshed without restarting scheduler.
+type DynamicWeights struct {
+	mu      sync.RWMutex
+	weights Weights
+}
+
+// NewDynamicWeights returns a new DynamicWeights and registers it to dynconfig.
+func NewDynamicWeights(dynconfig config.DynconfigInterface) *DynamicWeights {
+	w := &DynamicWeights{weights: DefaultWeights}
+	if cfg, err := dynconfig.GetSchedulerClusterConfig(); err == nil {
+		w.update(cfg)
+	}
+
+	dynconfig.Register(w)
+	return w
+}
+
+// Load returns the current weights.
+func (w *DynamicWeights) Load() Weights {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.weights
+}
+
+// OnNotify updates the weights by the scheduler cluster config.
+func (w *DynamicWeights) OnNotify(data *config.DynconfigData) {
+	if data == nil || data.Scheduler == nil || data.Scheduler.SchedulerCluster == nil {
+		return
+	}
+
+	var cfg types.SchedulerClusterConfig
+	if err := json.Unmarshal(data.Scheduler.SchedulerCluster.Config, &cfg); err != nil {
+		logger.Errorf("unmarshal scheduler cluster config failed: %s", err.Error())
+		return
+	}
+
+	w.update(cfg)
+}
+
+// update updates the weights, the default weights are used if the weights are not configured,
+// and invalid weights are ignored.
+func (w *DynamicWeights) update(cfg types.SchedulerClusterConfig) {
+	weights := DefaultWeights
+	if cfg.EvaluatorWeights != nil {
+		var err error
+		weights, err = normalizeWeights(Weights{
+			FinishedPiece:           cfg.EvaluatorWeights.FinishedPiece,
+			ParentHostUploadSuccess: cfg.EvaluatorWeights.ParentHostUploadSuccess,
+			FreeUpload:              cfg.EvaluatorWeights.FreeUpload,
+			FreeBandwidth:           cfg.EvaluatorWeights.FreeBandwidth,
+			HostType:                cfg.EvaluatorWeights.HostType,
+			IDCAffinity:             cfg.EvaluatorWeights.IDCAffinity,
+			LocationAffinity:        cfg.EvaluatorWeights.LocationAffinity,
+			TopologyAffinity:        cfg.EvaluatorWeights.TopologyAffinity,
+		})
+		if err != nil {
+			logger.Errorf("invalid evaluator weights %#v: %s", cfg.EvaluatorWeights, err.Error())
+			return
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.weights != weights {
+		logger.Infof("update evaluator weights to %#v", weights)
+		w.weights = weights
+	}
+}
+
+// normalizeWeights validates the weights and scales them so that they sum to 1.
+func normalizeWeights(weights Weights) (Weights, error) {
+	values := []*float64{
+		&weights.FinishedPiece,
+		&weights.ParentHostUploadSuccess,
+		&weights.FreeUpload,
+		&weights.FreeBandwidth,
+		&weights.HostType,
+		&weights.IDCAffinity,
+		&weights.LocationAffinity,
+		&weights.TopologyAffinity,
+	}
+
+	var sum float64
+	for _, value := range values {
+		if math.IsNaN(*value) || math.IsInf(*value, 0) || *value < 0 {
+			return Weights{}, fmt.Errorf("weight %v must be a non-negative number", *value)
+		}
+
+		sum += *value
+	}
+
+	if sum == 0 {
+		return Weights{}, errors.New("weights must not be all zero")
+	}
+
+	for _, value := range values {
+		*value /= sum
+	}
+
+	return weights, nil
+}
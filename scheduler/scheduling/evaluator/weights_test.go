@@ -0,0 +1,177 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package evaluator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	managerv2 "d7y.io/api/v2/pkg/apis/manager/v2"
+
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	configmocks "d7y.io/dragonfly/v2/scheduler/config/mocks"
+)
+
+func TestDynamicWeights(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(md *configmocks.MockDynconfigInterfaceMockRecorder)
+		expect func(t *testing.T, w *DynamicWeights)
+	}{
+		{
+			name: "get scheduler cluster config failed",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				assert.Equal(t, DefaultWeights, w.Load())
+			},
+		},
+		{
+			name: "weights are not configured",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{FilterParentLimit: 10}, nil).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				assert.Equal(t, DefaultWeights, w.Load())
+			},
+		},
+		{
+			name: "weights are configured",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{
+					EvaluatorWeights: &types.SchedulerClusterEvaluatorWeights{FinishedPiece: 0.5, IDCAffinity: 0.5},
+				}, nil).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				assert.Equal(t, Weights{FinishedPiece: 0.5, IDCAffinity: 0.5}, w.Load())
+			},
+		},
+		{
+			name: "topology affinity is configured",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{
+					EvaluatorWeights: &types.SchedulerClusterEvaluatorWeights{FinishedPiece: 0.6, TopologyAffinity: 0.2},
+				}, nil).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				weights := w.Load()
+				assert.InDelta(t, 0.75, weights.FinishedPiece, 1e-9)
+				assert.InDelta(t, 0.25, weights.TopologyAffinity, 1e-9)
+			},
+		},
+		{
+			name: "weights are not normalized",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{
+					EvaluatorWeights: &types.SchedulerClusterEvaluatorWeights{FinishedPiece: 2, IDCAffinity: 2},
+				}, nil).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				assert.Equal(t, Weights{FinishedPiece: 0.5, IDCAffinity: 0.5}, w.Load())
+			},
+		},
+		{
+			name: "weights are negative",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{
+					EvaluatorWeights: &types.SchedulerClusterEvaluatorWeights{FinishedPiece: 1.5, IDCAffinity: -0.5},
+				}, nil).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				assert.Equal(t, DefaultWeights, w.Load())
+			},
+		},
+		{
+			name: "weights are all zero",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{
+					EvaluatorWeights: &types.SchedulerClusterEvaluatorWeights{},
+				}, nil).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				assert.Equal(t, DefaultWeights, w.Load())
+			},
+		},
+		{
+			name: "weights are updated by dynconfig",
+			mock: func(md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, nil).Times(1)
+				md.Register(gomock.Any()).Times(1)
+			},
+			expect: func(t *testing.T, w *DynamicWeights) {
+				assert := assert.New(t)
+				w.OnNotify(&config.DynconfigData{
+					Scheduler: &managerv2.Scheduler{
+						SchedulerCluster: &managerv2.SchedulerCluster{
+							Config: []byte(`{"evaluator_weights":{"free_upload":0.6,"host_type":0.4}}`),
+						},
+					},
+				})
+				assert.Equal(Weights{FreeUpload: 0.6, HostType: 0.4}, w.Load())
+
+				// Invalid config does not update the weights.
+				w.OnNotify(&config.DynconfigData{
+					Scheduler: &managerv2.Scheduler{
+						SchedulerCluster: &managerv2.SchedulerCluster{Config: []byte("foo")},
+					},
+				})
+				assert.Equal(Weights{FreeUpload: 0.6, HostType: 0.4}, w.Load())
+
+				// Invalid weights do not update the weights.
+				w.OnNotify(&config.DynconfigData{
+					Scheduler: &managerv2.Scheduler{
+						SchedulerCluster: &managerv2.SchedulerCluster{
+							Config: []byte(`{"evaluator_weights":{"free_upload":-1}}`),
+						},
+					},
+				})
+				assert.Equal(Weights{FreeUpload: 0.6, HostType: 0.4}, w.Load())
+
+				// Weights are reset to default when they are removed from config.
+				w.OnNotify(&config.DynconfigData{
+					Scheduler: &managerv2.Scheduler{
+						SchedulerCluster: &managerv2.SchedulerCluster{Config: []byte("{}")},
+					},
+				})
+				assert.Equal(DefaultWeights, w.Load())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+
+			tc.mock(dynconfig.EXPECT())
+			tc.expect(t, NewDynamicWeights(dynconfig))
+		})
+	}
+}
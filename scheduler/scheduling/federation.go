@@ -0,0 +1,242 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/federation_mock.go -source federation.go -package mocks
+
+package scheduling
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	managerv2 "d7y.io/api/v2/pkg/apis/manager/v2"
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/container/set"
+	managerclient "d7y.io/dragonfly/v2/pkg/rpc/manager/client"
+	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/version"
+)
+
+// Federation is the interface used for looking up the peers holding the task in the sibling scheduler clusters.
+type Federation interface {
+	// FindParents finds the peers holding the task in the sibling scheduler clusters,
+	// the peers in the blocklist are skipped.
+	FindParents(ctx context.Context, taskID string, blocklist set.SafeSet[string]) []*schedulerv1.PeerPacket_DestPeer
+
+	// Serve starts refreshing the sibling schedulers.
+	Serve()
+
+	// Stop stops refreshing the sibling schedulers.
+	Stop()
+}
+
+// federation implements Federation.
+type federation struct {
+	// config is the scheduler configuration.
+	config *config.Config
+
+	// managerClient is the client of manager.
+	managerClient managerclient.V2
+
+	// clients is the pooled scheduler clients of the sibling clusters, keyed by the address.
+	clients map[string]schedulerclient.V1
+
+	// mu protects clients.
+	mu *sync.RWMutex
+
+	// getClient returns the scheduler client of sibling.
+	getClient func(context.Context, string) (schedulerclient.V1, error)
+
+	// done is the channel of stopping federation.
+	done chan struct{}
+}
+
+// NewFederation returns a new Federation interface.
+func NewFederation(cfg *config.Config, managerClient managerclient.V2, dialOptions ...grpc.DialOption) Federation {
+	return &federation{
+		config:        cfg,
+		managerClient: managerClient,
+		clients:       make(map[string]schedulerclient.V1),
+		mu:            &sync.RWMutex{},
+		getClient: func(ctx context.Context, target string) (schedulerclient.V1, error) {
+			return schedulerclient.GetV1ByAddr(ctx, target, dialOptions...)
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// FindParents finds the peers holding the task in the sibling scheduler clusters,
+// the siblings are queried concurrently and the peers in the blocklist are skipped.
+func (f *federation) FindParents(ctx context.Context, taskID string, blocklist set.SafeSet[string]) []*schedulerv1.PeerPacket_DestPeer {
+	f.mu.RLock()
+	clients := make(map[string]schedulerclient.V1, len(f.clients))
+	for sibling, client := range f.clients {
+		clients[sibling] = client
+	}
+	f.mu.RUnlock()
+
+	if len(clients) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.config.Scheduler.Federation.Timeout)
+	defer cancel()
+
+	var (
+		wg               sync.WaitGroup
+		mu               sync.Mutex
+		candidateParents []*schedulerv1.PeerPacket_DestPeer
+	)
+	candidateParentIDs := set.New[string]()
+	for sibling, client := range clients {
+		wg.Add(1)
+		go func(sibling string, client schedulerclient.V1) {
+			defer wg.Done()
+
+			parents, err := client.FindTaskPeers(ctx, &schedulerv1.StatTaskRequest{TaskId: taskID})
+			if err != nil {
+				if dferrors.CheckError(err, commonv1.Code_PeerTaskNotFound) {
+					logger.WithTaskID(taskID).Debugf("task is not found in sibling scheduler %s", sibling)
+					return
+				}
+
+				logger.WithTaskID(taskID).Warnf("find task peers in sibling scheduler %s failed: %s", sibling, err.Error())
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, parent := range parents {
+				if blocklist.Contains(parent.PeerId) || candidateParentIDs.Contains(parent.PeerId) {
+					continue
+				}
+
+				candidateParents = append(candidateParents, parent)
+				candidateParentIDs.Add(parent.PeerId)
+			}
+		}(sibling, client)
+	}
+	wg.Wait()
+
+	if len(candidateParents) > config.DefaultSchedulerCandidateParentLimit {
+		candidateParents = candidateParents[:config.DefaultSchedulerCandidateParentLimit]
+	}
+
+	if len(candidateParents) > 0 {
+		logger.WithTaskID(taskID).Infof("find %d parents in sibling schedulers", len(candidateParents))
+	}
+
+	return candidateParents
+}
+
+// Serve starts refreshing the sibling schedulers.
+func (f *federation) Serve() {
+	f.refresh()
+
+	tick := time.NewTicker(f.config.Scheduler.Federation.Interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			f.refresh()
+		case <-f.done:
+			return
+		}
+	}
+}
+
+// Stop stops refreshing the sibling schedulers and closes the clients of siblings.
+func (f *federation) Stop() {
+	close(f.done)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sibling, client := range f.clients {
+		if err := client.Close(); err != nil {
+			logger.Errorf("close sibling scheduler %s client failed: %s", sibling, err.Error())
+		}
+	}
+	f.clients = make(map[string]schedulerclient.V1)
+}
+
+// refresh lists the schedulers registered in the manager, and keeps the schedulers
+// of the other scheduler clusters as the siblings.
+func (f *federation) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), f.config.Scheduler.Federation.Timeout)
+	defer cancel()
+
+	resp, err := f.managerClient.ListSchedulers(ctx, &managerv2.ListSchedulersRequest{
+		SourceType: managerv2.SourceType_SCHEDULER_SOURCE,
+		Hostname:   f.config.Server.Host,
+		Ip:         f.config.Server.AdvertiseIP.String(),
+		Version:    version.GitVersion,
+		Commit:     version.GitCommit,
+	})
+	if err != nil {
+		logger.Errorf("list sibling schedulers failed: %s", err.Error())
+		return
+	}
+
+	siblings := set.New[string]()
+	for _, scheduler := range resp.Schedulers {
+		if scheduler.SchedulerClusterId == uint64(f.config.Manager.SchedulerClusterID) {
+			continue
+		}
+
+		siblings.Add(net.JoinHostPort(scheduler.Ip, strconv.Itoa(int(scheduler.Port))))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Close the clients of the schedulers leaving the sibling clusters.
+	for sibling, client := range f.clients {
+		if siblings.Contains(sibling) {
+			continue
+		}
+
+		if err := client.Close(); err != nil {
+			logger.Errorf("close sibling scheduler %s client failed: %s", sibling, err.Error())
+		}
+		delete(f.clients, sibling)
+	}
+
+	// Dial the schedulers joining the sibling clusters, the clients are reused by the lookups.
+	for _, sibling := range siblings.Values() {
+		if _, ok := f.clients[sibling]; ok {
+			continue
+		}
+
+		client, err := f.getClient(ctx, sibling)
+		if err != nil {
+			logger.Errorf("dial sibling scheduler %s failed: %s", sibling, err.Error())
+			continue
+		}
+		f.clients[sibling] = client
+	}
+
+	logger.Debugf("refresh sibling schedulers %#v", siblings.Values())
+}
@@ -0,0 +1,187 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	managerv2 "d7y.io/api/v2/pkg/apis/manager/v2"
+	schedulerv1 "d7y.io/api/v2/pkg/apis/scheduler/v1"
+
+	"d7y.io/dragonfly/v2/internal/dferrors"
+	"d7y.io/dragonfly/v2/pkg/container/set"
+	managerclientmocks "d7y.io/dragonfly/v2/pkg/rpc/manager/client/mocks"
+	schedulerclient "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client"
+	schedulerclientmocks "d7y.io/dragonfly/v2/pkg/rpc/scheduler/client/mocks"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+var (
+	mockFederationConfig = &config.Config{
+		Server: config.ServerConfig{
+			Host:        "foo",
+			AdvertiseIP: net.ParseIP("127.0.0.1"),
+		},
+		Manager: config.ManagerConfig{
+			SchedulerClusterID: 1,
+		},
+		Scheduler: config.SchedulerConfig{
+			Federation: config.FederationConfig{
+				Enable:   true,
+				Interval: time.Minute,
+				Timeout:  time.Second,
+			},
+		},
+	}
+)
+
+func TestFederation_refresh(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	managerClient := managerclientmocks.NewMockV2(ctl)
+	gomock.InOrder(
+		managerClient.EXPECT().ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv2.ListSchedulersResponse{
+			Schedulers: []*managerv2.Scheduler{
+				{Ip: "127.0.0.1", Port: 8002, SchedulerClusterId: 1},
+				{Ip: "127.0.0.2", Port: 8002, SchedulerClusterId: 2},
+			},
+		}, nil).Times(1),
+		managerClient.EXPECT().ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv2.ListSchedulersResponse{
+			Schedulers: []*managerv2.Scheduler{
+				{Ip: "127.0.0.1", Port: 8002, SchedulerClusterId: 1},
+				{Ip: "127.0.0.2", Port: 8002, SchedulerClusterId: 2},
+				{Ip: "127.0.0.3", Port: 8002, SchedulerClusterId: 3},
+			},
+		}, nil).Times(1),
+		managerClient.EXPECT().ListSchedulers(gomock.Any(), gomock.Any()).Return(&managerv2.ListSchedulersResponse{
+			Schedulers: []*managerv2.Scheduler{
+				{Ip: "127.0.0.1", Port: 8002, SchedulerClusterId: 1},
+				{Ip: "127.0.0.3", Port: 8002, SchedulerClusterId: 3},
+			},
+		}, nil).Times(1),
+	)
+
+	clients := map[string]*schedulerclientmocks.MockV1{
+		"127.0.0.2:8002": schedulerclientmocks.NewMockV1(ctl),
+		"127.0.0.3:8002": schedulerclientmocks.NewMockV1(ctl),
+	}
+	clients["127.0.0.2:8002"].EXPECT().Close().Return(nil).Times(1)
+
+	var dials []string
+	f := NewFederation(mockFederationConfig, managerClient).(*federation)
+	f.getClient = func(_ context.Context, target string) (schedulerclient.V1, error) {
+		dials = append(dials, target)
+		return clients[target], nil
+	}
+
+	assert := assert.New(t)
+	f.refresh()
+	assert.Equal([]string{"127.0.0.2:8002"}, dials)
+	assert.Len(f.clients, 1)
+
+	// The client of the known sibling is reused.
+	f.refresh()
+	assert.Equal([]string{"127.0.0.2:8002", "127.0.0.3:8002"}, dials)
+	assert.Len(f.clients, 2)
+
+	// The client of the leaving sibling is closed.
+	f.refresh()
+	assert.Equal([]string{"127.0.0.2:8002", "127.0.0.3:8002"}, dials)
+	assert.Len(f.clients, 1)
+	assert.Contains(f.clients, "127.0.0.3:8002")
+}
+
+func TestFederation_FindParents(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(blocklist set.SafeSet[string], mc *schedulerclientmocks.MockV1MockRecorder)
+		expect func(t *testing.T, parents []*schedulerv1.PeerPacket_DestPeer)
+	}{
+		{
+			name: "find parents in sibling scheduler",
+			mock: func(blocklist set.SafeSet[string], mc *schedulerclientmocks.MockV1MockRecorder) {
+				blocklist.Add("bar")
+				mc.FindTaskPeers(gomock.Any(), gomock.Any()).Return([]*schedulerv1.PeerPacket_DestPeer{
+					{Ip: "127.0.0.3", RpcPort: 8003, PeerId: "foo"},
+					{Ip: "127.0.0.4", RpcPort: 8003, PeerId: "bar"},
+				}, nil).Times(2)
+			},
+			expect: func(t *testing.T, parents []*schedulerv1.PeerPacket_DestPeer) {
+				assert := assert.New(t)
+				assert.Len(parents, 1)
+				assert.Equal("foo", parents[0].PeerId)
+			},
+		},
+		{
+			name: "find duplicate parents in sibling schedulers",
+			mock: func(blocklist set.SafeSet[string], mc *schedulerclientmocks.MockV1MockRecorder) {
+				mc.FindTaskPeers(gomock.Any(), gomock.Any()).Return([]*schedulerv1.PeerPacket_DestPeer{
+					{Ip: "127.0.0.3", RpcPort: 8003, PeerId: "foo"},
+				}, nil).Times(2)
+			},
+			expect: func(t *testing.T, parents []*schedulerv1.PeerPacket_DestPeer) {
+				assert := assert.New(t)
+				assert.Len(parents, 1)
+				assert.Equal("foo", parents[0].PeerId)
+			},
+		},
+		{
+			name: "task is not found in sibling scheduler",
+			mock: func(blocklist set.SafeSet[string], mc *schedulerclientmocks.MockV1MockRecorder) {
+				mc.FindTaskPeers(gomock.Any(), gomock.Any()).Return(nil, dferrors.New(commonv1.Code_PeerTaskNotFound, "foo")).Times(2)
+			},
+			expect: func(t *testing.T, parents []*schedulerv1.PeerPacket_DestPeer) {
+				assert.Empty(t, parents)
+			},
+		},
+		{
+			name: "sibling scheduler is unavailable",
+			mock: func(blocklist set.SafeSet[string], mc *schedulerclientmocks.MockV1MockRecorder) {
+				mc.FindTaskPeers(gomock.Any(), gomock.Any()).Return(nil, errors.New("foo")).Times(2)
+			},
+			expect: func(t *testing.T, parents []*schedulerv1.PeerPacket_DestPeer) {
+				assert.Empty(t, parents)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			client := schedulerclientmocks.NewMockV1(ctl)
+			blocklist := set.NewSafeSet[string]()
+			tc.mock(blocklist, client.EXPECT())
+
+			f := NewFederation(mockFederationConfig, nil).(*federation)
+			f.clients = map[string]schedulerclient.V1{
+				"127.0.0.2:8002": client,
+				"127.0.0.3:8002": client,
+			}
+
+			tc.expect(t, f.FindParents(context.Background(), mockTaskID, blocklist))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/flash_crowd_detector_mock.go -source flash_crowd_detector.go -package mocks
+
+package scheduling
+
+import (
+	"sync"
+	"time"
+
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/metrics"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// FlashCrowdDetector is the interface used for detecting sudden spikes in registrations for the same task.
+type FlashCrowdDetector interface {
+	// Serve starts detecting flash crowd.
+	Serve()
+
+	// Stop stops detecting flash crowd.
+	Stop()
+}
+
+// flashCrowdDetector implements FlashCrowdDetector.
+type flashCrowdDetector struct {
+	// config is the flash crowd configuration.
+	config *config.FlashCrowdConfig
+
+	// taskManager is the task manager of scheduler.
+	taskManager resource.TaskManager
+
+	// windows is the registrations of the tasks in the last window.
+	windows *sync.Map
+
+	// done is the channel of stopping detector.
+	done chan struct{}
+}
+
+// registerWindow is the registrations of the task in a window.
+type registerWindow struct {
+	// registerCount is the total register count of the task at the end of the window.
+	registerCount int64
+
+	// delta is the registrations in the window.
+	delta int64
+}
+
+// NewFlashCrowdDetector returns a new FlashCrowdDetector interface.
+func NewFlashCrowdDetector(cfg *config.FlashCrowdConfig, taskManager resource.TaskManager) FlashCrowdDetector {
+	return &flashCrowdDetector{
+		config:      cfg,
+		taskManager: taskManager,
+		windows:     &sync.Map{},
+		done:        make(chan struct{}),
+	}
+}
+
+// Serve starts detecting flash crowd.
+func (d *flashCrowdDetector) Serve() {
+	tick := time.NewTicker(d.config.Interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			taskIDs := make(map[string]struct{})
+			d.taskManager.Range(func(_, value any) bool {
+				task, ok := value.(*resource.Task)
+				if !ok {
+					return true
+				}
+
+				taskIDs[task.ID] = struct{}{}
+				d.detect(task)
+				return true
+			})
+
+			// Clean up the windows of the tasks which have been reclaimed.
+			d.windows.Range(func(key, _ any) bool {
+				if _, ok := taskIDs[key.(string)]; !ok {
+					d.windows.Delete(key)
+				}
+
+				return true
+			})
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// Stop stops detecting flash crowd.
+func (d *flashCrowdDetector) Stop() {
+	close(d.done)
+}
+
+// detect compares the registrations of the task in the current window with the last window,
+// the task is switched into flash crowd when registrations spike, and switched back
+// when registrations drop below the minimum.
+func (d *flashCrowdDetector) detect(task *resource.Task) {
+	// The task registered in the current window has no registrations in the last window.
+	var last registerWindow
+	if value, loaded := d.windows.Load(task.ID); loaded {
+		last = value.(registerWindow)
+	}
+
+	registerCount := task.RegisterCount.Load()
+	current := registerWindow{registerCount: registerCount, delta: registerCount - last.registerCount}
+	d.windows.Store(task.ID, current)
+
+	if !task.FlashCrowd.Load() {
+		if current.delta >= d.config.MinRegisterCount && float64(current.delta) >= d.config.GrowthRate*float64(last.delta) {
+			task.FlashCrowd.Store(true)
+			task.Log.Infof("task is in flash crowd, registrations grow from %d to %d in %s", last.delta, current.delta, d.config.Interval)
+			metrics.FlashCrowdCount.WithLabelValues(task.Type.String(), task.Tag, task.Application).Inc()
+		}
+
+		return
+	}
+
+	if current.delta < d.config.MinRegisterCount {
+		task.FlashCrowd.Store(false)
+		task.Log.Infof("task is out of flash crowd, registrations drop from %d to %d in %s", last.delta, current.delta, d.config.Interval)
+	}
+}
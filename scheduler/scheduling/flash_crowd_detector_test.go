@@ -0,0 +1,104 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+var (
+	mockFlashCrowdConfig = &config.FlashCrowdConfig{
+		Enable:               true,
+		Interval:             time.Minute,
+		MinRegisterCount:     10,
+		GrowthRate:           4,
+		CandidateParentLimit: 8,
+		FilterParentLimit:    80,
+	}
+)
+
+func TestFlashCrowdDetector_detect(t *testing.T) {
+	tests := []struct {
+		name          string
+		registerCount []int64
+		expect        func(t *testing.T, task *resource.Task)
+	}{
+		{
+			name:          "registrations spike in the first window",
+			registerCount: []int64{10},
+			expect: func(t *testing.T, task *resource.Task) {
+				assert.True(t, task.FlashCrowd.Load())
+			},
+		},
+		{
+			name:          "registrations are less than the minimum",
+			registerCount: []int64{9},
+			expect: func(t *testing.T, task *resource.Task) {
+				assert.False(t, task.FlashCrowd.Load())
+			},
+		},
+		{
+			name:          "registrations grow slower than the growth rate",
+			registerCount: []int64{5, 20},
+			expect: func(t *testing.T, task *resource.Task) {
+				assert.False(t, task.FlashCrowd.Load())
+			},
+		},
+		{
+			name:          "registrations grow faster than the growth rate",
+			registerCount: []int64{3, 15},
+			expect: func(t *testing.T, task *resource.Task) {
+				assert.True(t, task.FlashCrowd.Load())
+			},
+		},
+		{
+			name:          "task in flash crowd keeps registrations above the minimum",
+			registerCount: []int64{10, 20},
+			expect: func(t *testing.T, task *resource.Task) {
+				assert.True(t, task.FlashCrowd.Load())
+			},
+		},
+		{
+			name:          "task is switched back when registrations drop below the minimum",
+			registerCount: []int64{10, 20, 25},
+			expect: func(t *testing.T, task *resource.Task) {
+				assert.False(t, task.FlashCrowd.Load())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			d := NewFlashCrowdDetector(mockFlashCrowdConfig, nil).(*flashCrowdDetector)
+			for _, registerCount := range tc.registerCount {
+				mockTask.RegisterCount.Store(registerCount)
+				d.detect(mockTask)
+			}
+
+			tc.expect(t, mockTask)
+		})
+	}
+}
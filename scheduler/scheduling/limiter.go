@@ -0,0 +1,112 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/limiter_mock.go -source limiter.go -package mocks
+
+package scheduling
+
+import (
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// BackToSourceLimiter is the interface used for limiting the peers downloading back-to-source
+// simultaneously, it prevents the origin servers from being overwhelmed when the seed peers are cold.
+type BackToSourceLimiter interface {
+	// Allow returns whether the peer is allowed to download back-to-source.
+	Allow(*resource.Peer) bool
+}
+
+// backToSourceLimiter implements BackToSourceLimiter.
+type backToSourceLimiter struct {
+	// dynconfig is the scheduler dynamic configuration.
+	dynconfig config.DynconfigInterface
+
+	// taskManager is the task manager of scheduler.
+	taskManager resource.TaskManager
+
+	// originQPSLimiter limits the back-to-source requests per second of the scheduler cluster,
+	// it is recreated when the qps of the scheduler cluster config changes.
+	originQPSLimiter *rate.Limiter
+
+	// originQPS is the qps of originQPSLimiter.
+	originQPS uint32
+
+	// mu protects originQPSLimiter and originQPS.
+	mu sync.Mutex
+}
+
+// NewBackToSourceLimiter returns a new BackToSourceLimiter interface, the limits per task
+// and per origin are loaded from the scheduler cluster config of manager, and the concurrency
+// per task is loaded from the application policy of manager.
+func NewBackToSourceLimiter(dynconfig config.DynconfigInterface, taskManager resource.TaskManager) BackToSourceLimiter {
+	return &backToSourceLimiter{
+		dynconfig:   dynconfig,
+		taskManager: taskManager,
+	}
+}
+
+// Allow returns whether the peer is allowed to download back-to-source.
+func (l *backToSourceLimiter) Allow(peer *resource.Peer) bool {
+	if policy, ok := resource.LoadApplicationPolicy(l.dynconfig, peer.Task.Application); ok &&
+		policy.BackToSourceConcurrency > 0 && peer.Task.BackToSourcePeers.Len() >= uint(policy.BackToSourceConcurrency) {
+		peer.Log.Infof("back-to-source peers of task exceed the concurrency %d of application %s",
+			policy.BackToSourceConcurrency, peer.Task.Application)
+		return false
+	}
+
+	config, err := l.dynconfig.GetSchedulerClusterConfig()
+	if err != nil {
+		return true
+	}
+
+	if config.BackToSourceTaskLimit > 0 && peer.Task.BackToSourcePeers.Len() >= uint(config.BackToSourceTaskLimit) {
+		peer.Log.Infof("back-to-source peers of task exceed the limit %d", config.BackToSourceTaskLimit)
+		return false
+	}
+
+	if config.BackToSourceOriginLimit > 0 {
+		origin := peer.Task.Origin()
+		if count := l.taskManager.OriginBackToSourcePeerCount(origin); count >= uint(config.BackToSourceOriginLimit) {
+			peer.Log.Infof("back-to-source peers of origin %s exceed the limit %d", origin, config.BackToSourceOriginLimit)
+			return false
+		}
+	}
+
+	if config.BackToSourceOriginQPS > 0 && !l.allowOriginQPS(config.BackToSourceOriginQPS) {
+		peer.Log.Infof("back-to-source requests exceed the qps %d", config.BackToSourceOriginQPS)
+		return false
+	}
+
+	return true
+}
+
+// allowOriginQPS returns whether the back-to-source request is allowed by the qps limit.
+func (l *backToSourceLimiter) allowOriginQPS(qps uint32) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.originQPSLimiter == nil || l.originQPS != qps {
+		l.originQPSLimiter = rate.NewLimiter(rate.Limit(qps), int(qps))
+		l.originQPS = qps
+	}
+
+	return l.originQPSLimiter.Allow()
+}
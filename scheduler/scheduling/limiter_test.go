@@ -0,0 +1,163 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	configmocks "d7y.io/dragonfly/v2/scheduler/config/mocks"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+func TestBackToSourceLimiter_Allow(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder)
+		expect func(t *testing.T, allowed bool)
+	}{
+		{
+			name: "get scheduler cluster config failed",
+			mock: func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, errors.New("foo")).Times(1)
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(1)
+			},
+			expect: func(t *testing.T, allowed bool) {
+				assert.True(t, allowed)
+			},
+		},
+		{
+			name: "limits are not configured",
+			mock: func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, errors.New("foo")).Times(1)
+				task.BackToSourcePeers.Add("foo")
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, nil).Times(1)
+			},
+			expect: func(t *testing.T, allowed bool) {
+				assert.True(t, allowed)
+			},
+		},
+		{
+			name: "back-to-source peers of task exceed the limit",
+			mock: func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, errors.New("foo")).Times(1)
+				task.BackToSourcePeers.Add("foo")
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{BackToSourceTaskLimit: 1}, nil).Times(1)
+			},
+			expect: func(t *testing.T, allowed bool) {
+				assert.False(t, allowed)
+			},
+		},
+		{
+			name: "back-to-source peers of task exceed the concurrency of application",
+			mock: func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder) {
+				task.BackToSourcePeers.Add("foo")
+				policies := map[string]rpc.ApplicationPolicy{mockTaskApplication: {BackToSourceConcurrency: 1}}
+				md.GetApplicationPolicies().Return(policies, nil).Times(1)
+			},
+			expect: func(t *testing.T, allowed bool) {
+				assert.False(t, allowed)
+			},
+		},
+		{
+			name: "back-to-source peers of task do not exceed the concurrency of application",
+			mock: func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder) {
+				task.BackToSourcePeers.Add("foo")
+				policies := map[string]rpc.ApplicationPolicy{mockTaskApplication: {BackToSourceConcurrency: 2}}
+				gomock.InOrder(
+					md.GetApplicationPolicies().Return(policies, nil).Times(1),
+					md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, nil).Times(1),
+				)
+			},
+			expect: func(t *testing.T, allowed bool) {
+				assert.True(t, allowed)
+			},
+		},
+		{
+			name: "back-to-source peers of origin exceed the limit",
+			mock: func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, errors.New("foo")).Times(1)
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{BackToSourceTaskLimit: 1, BackToSourceOriginLimit: 1}, nil).Times(1)
+				mt.OriginBackToSourcePeerCount(task.Origin()).Return(uint(1)).Times(1)
+			},
+			expect: func(t *testing.T, allowed bool) {
+				assert.False(t, allowed)
+			},
+		},
+		{
+			name: "back-to-source peers of origin do not exceed the limit",
+			mock: func(task *resource.Task, md *configmocks.MockDynconfigInterfaceMockRecorder, mt *resource.MockTaskManagerMockRecorder) {
+				md.GetApplicationPolicies().Return(nil, errors.New("foo")).Times(1)
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{BackToSourceOriginLimit: 1}, nil).Times(1)
+				mt.OriginBackToSourcePeerCount(task.Origin()).Return(uint(0)).Times(1)
+			},
+			expect: func(t *testing.T, allowed bool) {
+				assert.True(t, allowed)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			taskManager := resource.NewMockTaskManager(ctl)
+
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+			tc.mock(mockTask, dynconfig.EXPECT(), taskManager.EXPECT())
+			tc.expect(t, NewBackToSourceLimiter(dynconfig, taskManager).Allow(peer))
+		})
+	}
+}
+
+func TestBackToSourceLimiter_AllowOriginQPS(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+	taskManager := resource.NewMockTaskManager(ctl)
+
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+	peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+	dynconfig.EXPECT().GetApplicationPolicies().Return(nil, errors.New("foo")).Times(3)
+	gomock.InOrder(
+		dynconfig.EXPECT().GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{BackToSourceOriginQPS: 1}, nil).Times(2),
+		dynconfig.EXPECT().GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{BackToSourceOriginQPS: 2}, nil).Times(1),
+	)
+
+	limiter := NewBackToSourceLimiter(dynconfig, taskManager)
+	assert := assert.New(t)
+	assert.True(limiter.Allow(peer))
+	assert.False(limiter.Allow(peer))
+	assert.True(limiter.Allow(peer))
+}
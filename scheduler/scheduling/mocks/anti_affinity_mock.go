@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: anti_affinity.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	resource "d7y.io/dragonfly/v2/scheduler/resource"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAntiAffinity is a mock of AntiAffinity interface.
+type MockAntiAffinity struct {
+	ctrl     *gomock.Controller
+	recorder *MockAntiAffinityMockRecorder
+}
+
+// MockAntiAffinityMockRecorder is the mock recorder for MockAntiAffinity.
+type MockAntiAffinityMockRecorder struct {
+	mock *MockAntiAffinity
+}
+
+// NewMockAntiAffinity creates a new mock instance.
+func NewMockAntiAffinity(ctrl *gomock.Controller) *MockAntiAffinity {
+	mock := &MockAntiAffinity{ctrl: ctrl}
+	mock.recorder = &MockAntiAffinityMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAntiAffinity) EXPECT() *MockAntiAffinityMockRecorder {
+	return m.recorder
+}
+
+// Conflict mocks base method.
+func (m *MockAntiAffinity) Conflict(peer, parent *resource.Peer) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Conflict", peer, parent)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Conflict indicates an expected call of Conflict.
+func (mr *MockAntiAffinityMockRecorder) Conflict(peer, parent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conflict", reflect.TypeOf((*MockAntiAffinity)(nil).Conflict), peer, parent)
+}
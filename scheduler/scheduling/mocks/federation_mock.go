@@ -0,0 +1,75 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: federation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	scheduler "d7y.io/api/v2/pkg/apis/scheduler/v1"
+	set "d7y.io/dragonfly/v2/pkg/container/set"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFederation is a mock of Federation interface.
+type MockFederation struct {
+	ctrl     *gomock.Controller
+	recorder *MockFederationMockRecorder
+}
+
+// MockFederationMockRecorder is the mock recorder for MockFederation.
+type MockFederationMockRecorder struct {
+	mock *MockFederation
+}
+
+// NewMockFederation creates a new mock instance.
+func NewMockFederation(ctrl *gomock.Controller) *MockFederation {
+	mock := &MockFederation{ctrl: ctrl}
+	mock.recorder = &MockFederationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFederation) EXPECT() *MockFederationMockRecorder {
+	return m.recorder
+}
+
+// FindParents mocks base method.
+func (m *MockFederation) FindParents(ctx context.Context, taskID string, blocklist set.SafeSet[string]) []*scheduler.PeerPacket_DestPeer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindParents", ctx, taskID, blocklist)
+	ret0, _ := ret[0].([]*scheduler.PeerPacket_DestPeer)
+	return ret0
+}
+
+// FindParents indicates an expected call of FindParents.
+func (mr *MockFederationMockRecorder) FindParents(ctx, taskID, blocklist interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindParents", reflect.TypeOf((*MockFederation)(nil).FindParents), ctx, taskID, blocklist)
+}
+
+// Serve mocks base method.
+func (m *MockFederation) Serve() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Serve")
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockFederationMockRecorder) Serve() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockFederation)(nil).Serve))
+}
+
+// Stop mocks base method.
+func (m *MockFederation) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockFederationMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockFederation)(nil).Stop))
+}
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: flash_crowd_detector.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFlashCrowdDetector is a mock of FlashCrowdDetector interface.
+type MockFlashCrowdDetector struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlashCrowdDetectorMockRecorder
+}
+
+// MockFlashCrowdDetectorMockRecorder is the mock recorder for MockFlashCrowdDetector.
+type MockFlashCrowdDetectorMockRecorder struct {
+	mock *MockFlashCrowdDetector
+}
+
+// NewMockFlashCrowdDetector creates a new mock instance.
+func NewMockFlashCrowdDetector(ctrl *gomock.Controller) *MockFlashCrowdDetector {
+	mock := &MockFlashCrowdDetector{ctrl: ctrl}
+	mock.recorder = &MockFlashCrowdDetectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlashCrowdDetector) EXPECT() *MockFlashCrowdDetectorMockRecorder {
+	return m.recorder
+}
+
+// Serve mocks base method.
+func (m *MockFlashCrowdDetector) Serve() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Serve")
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockFlashCrowdDetectorMockRecorder) Serve() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockFlashCrowdDetector)(nil).Serve))
+}
+
+// Stop mocks base method.
+func (m *MockFlashCrowdDetector) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockFlashCrowdDetectorMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockFlashCrowdDetector)(nil).Stop))
+}
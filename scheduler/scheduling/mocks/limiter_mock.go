@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: limiter.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	resource "d7y.io/dragonfly/v2/scheduler/resource"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBackToSourceLimiter is a mock of BackToSourceLimiter interface.
+type MockBackToSourceLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackToSourceLimiterMockRecorder
+}
+
+// MockBackToSourceLimiterMockRecorder is the mock recorder for MockBackToSourceLimiter.
+type MockBackToSourceLimiterMockRecorder struct {
+	mock *MockBackToSourceLimiter
+}
+
+// NewMockBackToSourceLimiter creates a new mock instance.
+func NewMockBackToSourceLimiter(ctrl *gomock.Controller) *MockBackToSourceLimiter {
+	mock := &MockBackToSourceLimiter{ctrl: ctrl}
+	mock.recorder = &MockBackToSourceLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackToSourceLimiter) EXPECT() *MockBackToSourceLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockBackToSourceLimiter) Allow(arg0 *resource.Peer) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockBackToSourceLimiterMockRecorder) Allow(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockBackToSourceLimiter)(nil).Allow), arg0)
+}
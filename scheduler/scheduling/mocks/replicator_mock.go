@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: replicator.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReplicator is a mock of Replicator interface.
+type MockReplicator struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplicatorMockRecorder
+}
+
+// MockReplicatorMockRecorder is the mock recorder for MockReplicator.
+type MockReplicatorMockRecorder struct {
+	mock *MockReplicator
+}
+
+// NewMockReplicator creates a new mock instance.
+func NewMockReplicator(ctrl *gomock.Controller) *MockReplicator {
+	mock := &MockReplicator{ctrl: ctrl}
+	mock.recorder = &MockReplicatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplicator) EXPECT() *MockReplicatorMockRecorder {
+	return m.recorder
+}
+
+// Serve mocks base method.
+func (m *MockReplicator) Serve() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Serve")
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockReplicatorMockRecorder) Serve() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockReplicator)(nil).Serve))
+}
+
+// Stop mocks base method.
+func (m *MockReplicator) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockReplicatorMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockReplicator)(nil).Stop))
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reserver.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	resource "d7y.io/dragonfly/v2/scheduler/resource"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReserver is a mock of Reserver interface.
+type MockReserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockReserverMockRecorder
+}
+
+// MockReserverMockRecorder is the mock recorder for MockReserver.
+type MockReserverMockRecorder struct {
+	mock *MockReserver
+}
+
+// NewMockReserver creates a new mock instance.
+func NewMockReserver(ctrl *gomock.Controller) *MockReserver {
+	mock := &MockReserver{ctrl: ctrl}
+	mock.recorder = &MockReserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReserver) EXPECT() *MockReserverMockRecorder {
+	return m.recorder
+}
+
+// Reserve mocks base method.
+func (m *MockReserver) Reserve(arg0 context.Context, arg1 *resource.Peer, arg2 []*resource.Peer) []*resource.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*resource.Peer)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockReserverMockRecorder) Reserve(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockReserver)(nil).Reserve), arg0, arg1, arg2)
+}
@@ -51,6 +51,20 @@ func (mr *MockSchedulingMockRecorder) FindCandidateParents(arg0, arg1, arg2 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCandidateParents", reflect.TypeOf((*MockScheduling)(nil).FindCandidateParents), arg0, arg1, arg2)
 }
 
+// FindSiblingPeers mocks base method.
+func (m *MockScheduling) FindSiblingPeers(arg0 *resource.Peer) []*resource.Peer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSiblingPeers", arg0)
+	ret0, _ := ret[0].([]*resource.Peer)
+	return ret0
+}
+
+// FindSiblingPeers indicates an expected call of FindSiblingPeers.
+func (mr *MockSchedulingMockRecorder) FindSiblingPeers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSiblingPeers", reflect.TypeOf((*MockScheduling)(nil).FindSiblingPeers), arg0)
+}
+
 // FindSuccessParent mocks base method.
 func (m *MockScheduling) FindSuccessParent(arg0 context.Context, arg1 *resource.Peer, arg2 set.SafeSet[string]) (*resource.Peer, bool) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: seed_peer_elector.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSeedPeerElector is a mock of SeedPeerElector interface.
+type MockSeedPeerElector struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeedPeerElectorMockRecorder
+}
+
+// MockSeedPeerElectorMockRecorder is the mock recorder for MockSeedPeerElector.
+type MockSeedPeerElectorMockRecorder struct {
+	mock *MockSeedPeerElector
+}
+
+// NewMockSeedPeerElector creates a new mock instance.
+func NewMockSeedPeerElector(ctrl *gomock.Controller) *MockSeedPeerElector {
+	mock := &MockSeedPeerElector{ctrl: ctrl}
+	mock.recorder = &MockSeedPeerElectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeedPeerElector) EXPECT() *MockSeedPeerElectorMockRecorder {
+	return m.recorder
+}
+
+// Serve mocks base method.
+func (m *MockSeedPeerElector) Serve() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Serve")
+}
+
+// Serve indicates an expected call of Serve.
+func (mr *MockSeedPeerElectorMockRecorder) Serve() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Serve", reflect.TypeOf((*MockSeedPeerElector)(nil).Serve))
+}
+
+// Stop mocks base method.
+func (m *MockSeedPeerElector) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockSeedPeerElectorMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockSeedPeerElector)(nil).Stop))
+}
@@ -0,0 +1,219 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/prober_mock.go -source prober.go -package mocks
+
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// Prober is the interface used for probing the health of candidate parents.
+type Prober interface {
+	// Probe probes the parents asynchronously, the parents probed within the interval are skipped.
+	Probe(...*resource.Peer)
+
+	// IsStale returns whether the parent is unreachable or no longer holds the pieces.
+	IsStale(*resource.Peer) bool
+
+	// Serve starts the probe workers.
+	Serve()
+
+	// Stop stops the probe workers.
+	Stop()
+}
+
+// probeResult is the result of probing parent.
+type probeResult struct {
+	// stale is whether the parent is stale.
+	stale bool
+
+	// probedAt is the time of probing parent.
+	probedAt time.Time
+}
+
+// prober implements Prober.
+type prober struct {
+	// config is the parent probe configuration.
+	config *config.ParentProbeConfig
+
+	// queue is the queue of parents to be probed.
+	queue chan *resource.Peer
+
+	// results is the probe results keyed by peer id.
+	results *sync.Map
+
+	// getClient returns the dfdaemon client of parent.
+	getClient func(context.Context, string) (dfdaemonclient.V1, error)
+
+	// done is the channel of stopping prober.
+	done chan struct{}
+
+	// wg waits for the probe workers.
+	wg *sync.WaitGroup
+}
+
+// NewProber returns a new Prober interface.
+func NewProber(cfg *config.ParentProbeConfig, dialOptions ...grpc.DialOption) Prober {
+	return &prober{
+		config:  cfg,
+		queue:   make(chan *resource.Peer, cfg.QueueLength),
+		results: &sync.Map{},
+		getClient: func(ctx context.Context, target string) (dfdaemonclient.V1, error) {
+			return dfdaemonclient.GetV1(ctx, target, dialOptions...)
+		},
+		done: make(chan struct{}),
+		wg:   &sync.WaitGroup{},
+	}
+}
+
+// Probe probes the parents asynchronously, the parents probed within the interval are skipped.
+func (p *prober) Probe(parents ...*resource.Peer) {
+	for _, parent := range parents {
+		if rawResult, loaded := p.results.Load(parent.ID); loaded {
+			if time.Since(rawResult.(*probeResult).probedAt) < p.config.Interval {
+				continue
+			}
+		}
+
+		p.results.Store(parent.ID, &probeResult{probedAt: time.Now()})
+		select {
+		case p.queue <- parent:
+		default:
+			p.results.Delete(parent.ID)
+			parent.Log.Debug("parent probe queue is full, skip probing")
+		}
+	}
+}
+
+// IsStale returns whether the parent is unreachable or no longer holds the pieces.
+func (p *prober) IsStale(parent *resource.Peer) bool {
+	rawResult, loaded := p.results.Load(parent.ID)
+	if !loaded {
+		return false
+	}
+
+	return rawResult.(*probeResult).stale
+}
+
+// Serve starts the probe workers.
+func (p *prober) Serve() {
+	for i := 0; i < p.config.Concurrency; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for {
+				select {
+				case parent := <-p.queue:
+					p.probe(parent)
+				case <-p.done:
+					return
+				}
+			}
+		}()
+	}
+
+	tick := time.NewTicker(p.config.Interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			p.gc()
+		case <-p.done:
+			p.wg.Wait()
+			return
+		}
+	}
+}
+
+// Stop stops the probe workers.
+func (p *prober) Stop() {
+	close(p.done)
+}
+
+// probe probes the parent and evicts it when the parent is stale.
+func (p *prober) probe(parent *resource.Peer) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.Timeout)
+	defer cancel()
+
+	err := p.check(ctx, parent)
+	if err == nil {
+		return
+	}
+
+	p.results.Store(parent.ID, &probeResult{stale: true, probedAt: time.Now()})
+	parent.Log.Warnf("parent probe failed: %s, causing the peer to leave", err.Error())
+	if !parent.FSM.Can(resource.PeerEventLeave) {
+		return
+	}
+
+	if err := parent.FSM.Event(ctx, resource.PeerEventLeave); err != nil {
+		parent.Log.Errorf("peer fsm event failed: %s", err.Error())
+	}
+}
+
+// check verifies the parent is reachable and still holds the pieces.
+func (p *prober) check(ctx context.Context, parent *resource.Peer) error {
+	client, err := p.getClient(ctx, net.JoinHostPort(parent.Host.IP, strconv.Itoa(int(parent.Host.Port))))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	packet, err := client.GetPieceTasks(ctx, &commonv1.PieceTaskRequest{
+		TaskId:   parent.Task.ID,
+		DstPid:   parent.ID,
+		StartNum: 0,
+		Limit:    1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if parent.FSM.Is(resource.PeerStateSucceeded) && len(packet.GetPieceInfos()) == 0 {
+		return errors.New("succeeded parent holds no pieces")
+	}
+
+	return nil
+}
+
+// gc deletes the expired probe results.
+func (p *prober) gc() {
+	p.results.Range(func(key, value any) bool {
+		if time.Since(value.(*probeResult).probedAt) > p.config.Interval {
+			p.results.Delete(key)
+		}
+
+		return true
+	})
+
+	logger.Debug("parent probe results have been reclaimed")
+}
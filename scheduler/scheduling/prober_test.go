@@ -0,0 +1,212 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	dfdaemonclientmocks "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client/mocks"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+var (
+	mockParentProbeConfig = &config.ParentProbeConfig{
+		Enable:      true,
+		Interval:    time.Minute,
+		Timeout:     time.Second,
+		Concurrency: 1,
+		QueueLength: 1,
+	}
+)
+
+func TestProber_Probe(t *testing.T) {
+	tests := []struct {
+		name   string
+		run    func(t *testing.T, p *prober, parent *resource.Peer)
+		expect func(t *testing.T, p *prober, parent *resource.Peer)
+	}{
+		{
+			name: "parent is enqueued",
+			run: func(t *testing.T, p *prober, parent *resource.Peer) {
+				p.Probe(parent)
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert := assert.New(t)
+				assert.Len(p.queue, 1)
+				assert.False(p.IsStale(parent))
+			},
+		},
+		{
+			name: "parent probed within the interval is skipped",
+			run: func(t *testing.T, p *prober, parent *resource.Peer) {
+				p.Probe(parent)
+				<-p.queue
+				p.Probe(parent)
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert.Len(t, p.queue, 0)
+			},
+		},
+		{
+			name: "parent is skipped when queue is full",
+			run: func(t *testing.T, p *prober, parent *resource.Peer) {
+				p.queue <- parent
+				p.Probe(parent)
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert := assert.New(t)
+				assert.Len(p.queue, 1)
+				_, loaded := p.results.Load(parent.ID)
+				assert.False(loaded)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			parent := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+			p := NewProber(mockParentProbeConfig).(*prober)
+			tc.run(t, p, parent)
+			tc.expect(t, p, parent)
+		})
+	}
+}
+
+func TestProber_probe(t *testing.T) {
+	tests := []struct {
+		name   string
+		state  string
+		mock   func(m *dfdaemonclientmocks.MockV1MockRecorder) error
+		expect func(t *testing.T, p *prober, parent *resource.Peer)
+	}{
+		{
+			name:  "parent is healthy",
+			state: resource.PeerStateSucceeded,
+			mock: func(m *dfdaemonclientmocks.MockV1MockRecorder) error {
+				m.GetPieceTasks(gomock.Any(), gomock.Any()).Return(&commonv1.PiecePacket{
+					PieceInfos: []*commonv1.PieceInfo{{PieceNum: 0}},
+				}, nil).Times(1)
+				m.Close().Return(nil).Times(1)
+				return nil
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert := assert.New(t)
+				assert.False(p.IsStale(parent))
+				assert.True(parent.FSM.Is(resource.PeerStateSucceeded))
+			},
+		},
+		{
+			name:  "running parent holds no pieces",
+			state: resource.PeerStateRunning,
+			mock: func(m *dfdaemonclientmocks.MockV1MockRecorder) error {
+				m.GetPieceTasks(gomock.Any(), gomock.Any()).Return(&commonv1.PiecePacket{}, nil).Times(1)
+				m.Close().Return(nil).Times(1)
+				return nil
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert := assert.New(t)
+				assert.False(p.IsStale(parent))
+				assert.True(parent.FSM.Is(resource.PeerStateRunning))
+			},
+		},
+		{
+			name:  "succeeded parent holds no pieces",
+			state: resource.PeerStateSucceeded,
+			mock: func(m *dfdaemonclientmocks.MockV1MockRecorder) error {
+				m.GetPieceTasks(gomock.Any(), gomock.Any()).Return(&commonv1.PiecePacket{}, nil).Times(1)
+				m.Close().Return(nil).Times(1)
+				return nil
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert := assert.New(t)
+				assert.True(p.IsStale(parent))
+				assert.True(parent.FSM.Is(resource.PeerStateLeave))
+			},
+		},
+		{
+			name:  "parent is unreachable",
+			state: resource.PeerStateSucceeded,
+			mock: func(m *dfdaemonclientmocks.MockV1MockRecorder) error {
+				m.GetPieceTasks(gomock.Any(), gomock.Any()).Return(nil, errors.New("foo")).Times(1)
+				m.Close().Return(nil).Times(1)
+				return nil
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert := assert.New(t)
+				assert.True(p.IsStale(parent))
+				assert.True(parent.FSM.Is(resource.PeerStateLeave))
+			},
+		},
+		{
+			name:  "get client failed",
+			state: resource.PeerStateRunning,
+			mock: func(m *dfdaemonclientmocks.MockV1MockRecorder) error {
+				return errors.New("foo")
+			},
+			expect: func(t *testing.T, p *prober, parent *resource.Peer) {
+				assert := assert.New(t)
+				assert.True(p.IsStale(parent))
+				assert.True(parent.FSM.Is(resource.PeerStateLeave))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			client := dfdaemonclientmocks.NewMockV1(ctl)
+			clientErr := tc.mock(client.EXPECT())
+
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			parent := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+			parent.FSM.SetState(tc.state)
+
+			p := NewProber(mockParentProbeConfig).(*prober)
+			p.getClient = func(ctx context.Context, target string) (dfdaemonclient.V1, error) {
+				assert.Equal(t, "127.0.0.1:8003", target)
+				if clientErr != nil {
+					return nil, clientErr
+				}
+
+				return client, nil
+			}
+
+			p.probe(parent)
+			tc.expect(t, p, parent)
+		})
+	}
+}
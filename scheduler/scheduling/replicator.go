@@ -0,0 +1,250 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/replicator_mock.go -source replicator.go -package mocks
+
+package scheduling
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	commonv1 "d7y.io/api/v2/pkg/apis/common/v1"
+	dfdaemonv1 "d7y.io/api/v2/pkg/apis/dfdaemon/v1"
+
+	"d7y.io/dragonfly/v2/pkg/container/set"
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// Replicator is the interface used for maintaining the replica count of the persistent cache tasks
+// as the peers come and go.
+type Replicator interface {
+	// Serve starts maintaining replicas.
+	Serve()
+
+	// Stop stops maintaining replicas.
+	Stop()
+}
+
+// replicator implements Replicator.
+type replicator struct {
+	// config is the replication configuration.
+	config *config.ReplicationConfig
+
+	// taskManager is the task manager of scheduler.
+	taskManager resource.TaskManager
+
+	// hostManager is the host manager of scheduler.
+	hostManager resource.HostManager
+
+	// getClient returns the dfdaemon client of host.
+	getClient func(context.Context, string) (dfdaemonclient.V1, error)
+
+	// pending is the host ids being replicated keyed by task id.
+	pending map[string]set.Set[string]
+
+	// mu guards pending.
+	mu *sync.Mutex
+
+	// done is the channel of stopping replicator.
+	done chan struct{}
+}
+
+// NewReplicator returns a new Replicator interface.
+func NewReplicator(cfg *config.ReplicationConfig, taskManager resource.TaskManager, hostManager resource.HostManager, dialOptions ...grpc.DialOption) Replicator {
+	return &replicator{
+		config:      cfg,
+		taskManager: taskManager,
+		hostManager: hostManager,
+		getClient: func(ctx context.Context, target string) (dfdaemonclient.V1, error) {
+			return dfdaemonclient.GetV1(ctx, target, dialOptions...)
+		},
+		pending: make(map[string]set.Set[string]),
+		mu:      &sync.Mutex{},
+		done:    make(chan struct{}),
+	}
+}
+
+// Serve starts maintaining replicas.
+func (r *replicator) Serve() {
+	tick := time.NewTicker(r.config.Interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			r.taskManager.Range(func(_, value any) bool {
+				task, ok := value.(*resource.Task)
+				if !ok {
+					return true
+				}
+
+				r.replicate(task)
+				return true
+			})
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// Stop stops maintaining replicas.
+func (r *replicator) Stop() {
+	close(r.done)
+}
+
+// replicate replicates the missing replicas of the persistent cache task to the hosts
+// in the failure domains holding the fewest replicas.
+func (r *replicator) replicate(task *resource.Task) {
+	replication := task.Replication.Load()
+	if !replication.IsPersistent() || !task.FSM.Is(resource.TaskStateSucceeded) {
+		return
+	}
+
+	replicas := task.LoadReplicas()
+	if len(replicas) == 0 {
+		task.Log.Warn("persistent cache task has no replica to replicate from")
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending, ok := r.pending[task.ID]
+	if !ok {
+		pending = set.New[string]()
+	}
+
+	missing := replication.Replicas - len(replicas) - int(pending.Len())
+	if missing <= 0 {
+		return
+	}
+
+	// Hosts holding the task or being replicated are not the candidates,
+	// the failure domains of them are counted.
+	blocklist := set.New[string]()
+	for _, hostID := range pending.Values() {
+		blocklist.Add(hostID)
+	}
+
+	placement := make(map[string]int)
+	for _, peer := range task.LoadRandomPeers(uint(task.PeerCount())) {
+		blocklist.Add(peer.Host.ID)
+	}
+
+	for _, peer := range replicas {
+		placement[peer.Host.Topology.Label(replication.SpreadBy)]++
+	}
+
+	for _, hostID := range pending.Values() {
+		if host, loaded := r.hostManager.Load(hostID); loaded {
+			placement[host.Topology.Label(replication.SpreadBy)]++
+		}
+	}
+
+	var candidateHosts []*resource.Host
+	r.hostManager.Range(func(_, value any) bool {
+		host, ok := value.(*resource.Host)
+		if !ok {
+			return true
+		}
+
+		if blocklist.Contains(host.ID) || host.Draining.Load() {
+			return true
+		}
+
+		candidateHosts = append(candidateHosts, host)
+		return true
+	})
+
+	for i := 0; i < missing && len(candidateHosts) > 0; i++ {
+		// Prefer the host in the failure domain with the fewest replicas,
+		// and then the host with the most free upload.
+		sort.SliceStable(candidateHosts, func(i, j int) bool {
+			iCount := placement[candidateHosts[i].Topology.Label(replication.SpreadBy)]
+			jCount := placement[candidateHosts[j].Topology.Label(replication.SpreadBy)]
+			if iCount != jCount {
+				return iCount < jCount
+			}
+
+			return candidateHosts[i].FreeUploadCount() > candidateHosts[j].FreeUploadCount()
+		})
+
+		host := candidateHosts[0]
+		candidateHosts = candidateHosts[1:]
+		placement[host.Topology.Label(replication.SpreadBy)]++
+		pending.Add(host.ID)
+
+		go r.replicateToHost(task, host)
+	}
+
+	r.pending[task.ID] = pending
+}
+
+// replicateToHost asks the host to replicate the task into its local storage.
+func (r *replicator) replicateToHost(task *resource.Task, host *resource.Host) {
+	defer func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if pending, ok := r.pending[task.ID]; ok {
+			pending.Delete(host.ID)
+			if pending.Len() == 0 {
+				delete(r.pending, task.ID)
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+	defer cancel()
+
+	client, err := r.getClient(ctx, net.JoinHostPort(host.IP, strconv.Itoa(int(host.Port))))
+	if err != nil {
+		host.Log.Errorf("get client of replicating task %s failed: %s", task.ID, err.Error())
+		return
+	}
+	defer client.Close()
+
+	urlMeta := &commonv1.UrlMeta{
+		Tag:         task.Tag,
+		Application: task.Application,
+		Filter:      strings.Join(task.Filters, idgen.URLFilterSeparator),
+	}
+	if task.Digest != nil {
+		urlMeta.Digest = task.Digest.String()
+	}
+
+	task.Log.Infof("replicate task to host %s", host.ID)
+	if err := client.ExportTask(rpc.ContextWithReplicate(rpc.ContextWithTaskID(ctx, task.ID)), &dfdaemonv1.ExportTaskRequest{
+		Url:     task.URL,
+		UrlMeta: urlMeta,
+	}); err != nil {
+		host.Log.Errorf("replicate task %s failed: %s", task.ID, err.Error())
+		return
+	}
+
+	task.Log.Infof("task is replicated to host %s", host.ID)
+}
@@ -0,0 +1,184 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	dfdaemonclient "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client"
+	dfdaemonclientmocks "d7y.io/dragonfly/v2/pkg/rpc/dfdaemon/client/mocks"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+var (
+	mockReplicationConfig = &config.ReplicationConfig{
+		Enable:   true,
+		Interval: time.Minute,
+		Timeout:  time.Minute,
+	}
+)
+
+func TestReplicator_replicate(t *testing.T) {
+	tests := []struct {
+		name        string
+		replication types.Replication
+		run         func(t *testing.T, task *resource.Task, hosts map[string]*resource.Host)
+		expect      func(t *testing.T, replicatedHosts []string)
+	}{
+		{
+			name:        "replicate to the hosts in the zones without replicas",
+			replication: types.Replication{Replicas: 3, SpreadBy: types.TopologyLabelZone},
+			run: func(t *testing.T, task *resource.Task, hosts map[string]*resource.Host) {
+			},
+			expect: func(t *testing.T, replicatedHosts []string) {
+				assert.Equal(t, []string{"b-0", "c-0"}, replicatedHosts)
+			},
+		},
+		{
+			name:        "replicate to the host with the most free upload without spread label",
+			replication: types.Replication{Replicas: 2},
+			run: func(t *testing.T, task *resource.Task, hosts map[string]*resource.Host) {
+				hosts["a-1"].ConcurrentUploadLimit.Store(300)
+			},
+			expect: func(t *testing.T, replicatedHosts []string) {
+				assert.Equal(t, []string{"a-1"}, replicatedHosts)
+			},
+		},
+		{
+			name:        "draining host is not replicated",
+			replication: types.Replication{Replicas: 3, SpreadBy: types.TopologyLabelZone},
+			run: func(t *testing.T, task *resource.Task, hosts map[string]*resource.Host) {
+				hosts["b-0"].Draining.Store(true)
+			},
+			expect: func(t *testing.T, replicatedHosts []string) {
+				assert.Equal(t, []string{"a-1", "c-0"}, replicatedHosts)
+			},
+		},
+		{
+			name:        "replicas are enough",
+			replication: types.Replication{Replicas: 1, SpreadBy: types.TopologyLabelZone},
+			run: func(t *testing.T, task *resource.Task, hosts map[string]*resource.Host) {
+			},
+			expect: func(t *testing.T, replicatedHosts []string) {
+				assert.Empty(t, replicatedHosts)
+			},
+		},
+		{
+			name:        "task is not persistent",
+			replication: types.Replication{},
+			run: func(t *testing.T, task *resource.Task, hosts map[string]*resource.Host) {
+			},
+			expect: func(t *testing.T, replicatedHosts []string) {
+				assert.Empty(t, replicatedHosts)
+			},
+		},
+		{
+			name:        "task is not succeeded",
+			replication: types.Replication{Replicas: 3},
+			run: func(t *testing.T, task *resource.Task, hosts map[string]*resource.Host) {
+				task.FSM.SetState(resource.TaskStateRunning)
+			},
+			expect: func(t *testing.T, replicatedHosts []string) {
+				assert.Empty(t, replicatedHosts)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFCACHE, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			mockTask.FSM.SetState(resource.TaskStateSucceeded)
+			mockTask.Replication.Store(&tc.replication)
+
+			// Hosts are named by zone, the task is held by the first host in zone a.
+			hosts := make(map[string]*resource.Host)
+			for i, name := range []string{"a-0", "a-1", "b-0", "c-0"} {
+				hosts[name] = resource.NewHost(
+					name, fmt.Sprintf("127.0.0.%d", i), name,
+					mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type,
+					resource.WithTopology(types.Topology{Zone: name[:1]}), resource.WithConcurrentUploadLimit(int32(100+i)))
+			}
+
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, hosts["a-0"])
+			peer.FSM.SetState(resource.PeerStateSucceeded)
+			mockTask.StorePeer(peer)
+			tc.run(t, mockTask, hosts)
+
+			hostManager := resource.NewMockHostManager(ctl)
+			hostManager.EXPECT().Load(gomock.Any()).DoAndReturn(func(id string) (*resource.Host, bool) {
+				host, ok := hosts[id]
+				return host, ok
+			}).AnyTimes()
+			hostManager.EXPECT().Range(gomock.Any()).DoAndReturn(func(f func(any, any) bool) {
+				for id, host := range hosts {
+					if !f(id, host) {
+						return
+					}
+				}
+			}).AnyTimes()
+
+			var (
+				mu              sync.Mutex
+				replicatedHosts []string
+			)
+			r := NewReplicator(mockReplicationConfig, nil, hostManager).(*replicator)
+			r.getClient = func(_ context.Context, target string) (dfdaemonclient.V1, error) {
+				for name, host := range hosts {
+					if target == net.JoinHostPort(host.IP, strconv.Itoa(int(host.Port))) {
+						mu.Lock()
+						replicatedHosts = append(replicatedHosts, name)
+						mu.Unlock()
+					}
+				}
+
+				client := dfdaemonclientmocks.NewMockV1(ctl)
+				client.EXPECT().ExportTask(gomock.Any(), gomock.Any()).Return(nil)
+				client.EXPECT().Close().Return(nil)
+				return client, nil
+			}
+
+			r.replicate(mockTask)
+			assert.Eventually(t, func() bool {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+				return len(r.pending) == 0
+			}, time.Second, 10*time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			sort.Strings(replicatedHosts)
+			tc.expect(t, replicatedHosts)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/reserver_mock.go -source reserver.go -package mocks
+
+package scheduling
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/go-http-utils/headers"
+
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// Reserver is the interface used for reserving upload slots on candidate parents.
+type Reserver interface {
+	// Reserve reserves an upload slot on each parent for the child concurrently within the timeout,
+	// and returns the parents granting the reservations in the original order.
+	Reserve(context.Context, *resource.Peer, []*resource.Peer) []*resource.Peer
+}
+
+// reserver implements Reserver.
+type reserver struct {
+	// config is the upload reservation configuration.
+	config *config.UploadReservationConfig
+
+	// scheme is the scheme of the upload servers of parents.
+	scheme string
+
+	// client is the http client of the upload servers of parents.
+	client *http.Client
+
+	// signer signs the reservations, so the upload servers authenticate them.
+	signer *nethttp.HMACSigner
+}
+
+// NewReserver returns a new Reserver interface, the upload servers of parents
+// are requested with the scheme and tls configuration of downloading tiny tasks.
+func NewReserver(cfg *config.UploadReservationConfig, downloadTiny *config.DownloadTinyConfig) Reserver {
+	return &reserver{
+		config: cfg,
+		scheme: downloadTiny.Scheme,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: downloadTiny.TLS.InsecureSkipVerify},
+			},
+		},
+		signer: nethttp.NewHMACSigner(cfg.HMACSecret, nethttp.DefaultHMACMaxSkew),
+	}
+}
+
+// Reserve reserves an upload slot on each parent for the child concurrently within the timeout,
+// and returns the parents granting the reservations in the original order.
+func (r *reserver) Reserve(ctx context.Context, child *resource.Peer, parents []*resource.Peer) []*resource.Peer {
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	granted := make([]bool, len(parents))
+	var wg sync.WaitGroup
+	for i, parent := range parents {
+		wg.Add(1)
+		go func(i int, parent *resource.Peer) {
+			defer wg.Done()
+
+			ok, err := r.reserve(ctx, child, parent)
+			if err != nil {
+				// The parents failing the reservations are not reserved.
+				child.Log.Warnf("reserve upload slot on parent %s failed: %s", parent.ID, err.Error())
+				return
+			}
+
+			if !ok {
+				child.Log.Infof("parent %s denies the upload slot reservation", parent.ID)
+			}
+
+			granted[i] = ok
+		}(i, parent)
+	}
+
+	wg.Wait()
+
+	var reservedParents []*resource.Peer
+	for i, parent := range parents {
+		if granted[i] {
+			reservedParents = append(reservedParents, parent)
+		}
+	}
+
+	return reservedParents
+}
+
+// reserve requests the upload server of the parent to reserve an upload slot for the child.
+func (r *reserver) reserve(ctx context.Context, child *resource.Peer, parent *resource.Peer) (bool, error) {
+	body, err := json.Marshal(types.UploadReservationRequest{TaskID: parent.Task.ID, PeerID: child.ID})
+	if err != nil {
+		return false, err
+	}
+
+	targetURL := url.URL{
+		Scheme: r.scheme,
+		Host:   net.JoinHostPort(parent.Host.IP, strconv.Itoa(int(parent.Host.DownloadPort))),
+		Path:   types.UploadReservationPath,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(headers.ContentType, "application/json")
+	if err := r.signer.Sign(req, body); err != nil {
+		return false, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("bad response status %s", resp.Status)
+	}
+
+	var reservation types.UploadReservationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&reservation); err != nil {
+		return false, err
+	}
+
+	return reservation.Granted, nil
+}
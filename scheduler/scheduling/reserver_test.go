@@ -0,0 +1,143 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+var (
+	mockUploadReservationConfig = &config.UploadReservationConfig{
+		Enable:     true,
+		Timeout:    time.Second,
+		HMACSecret: "foo",
+	}
+
+	mockDownloadTinyConfig = &config.DownloadTinyConfig{
+		Scheme: "http",
+	}
+)
+
+func TestReserver_Reserve(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, req types.UploadReservationRequest)
+		expect  func(t *testing.T, parents []*resource.Peer, reservedParents []*resource.Peer)
+	}{
+		{
+			name: "parents grant the reservations",
+			handler: func(w http.ResponseWriter, req types.UploadReservationRequest) {
+				_ = json.NewEncoder(w).Encode(types.UploadReservationResponse{Granted: true, FreeSlots: 1})
+			},
+			expect: func(t *testing.T, parents []*resource.Peer, reservedParents []*resource.Peer) {
+				assert.Equal(t, parents, reservedParents)
+			},
+		},
+		{
+			name: "saturated parent denies the reservation",
+			handler: func(w http.ResponseWriter, req types.UploadReservationRequest) {
+				_ = json.NewEncoder(w).Encode(types.UploadReservationResponse{Granted: req.PeerID == "child-1"})
+			},
+			expect: func(t *testing.T, parents []*resource.Peer, reservedParents []*resource.Peer) {
+				assert.Equal(t, parents[1:], reservedParents)
+			},
+		},
+		{
+			name: "parents not serving the reservations are skipped",
+			handler: func(w http.ResponseWriter, req types.UploadReservationRequest) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expect: func(t *testing.T, parents []*resource.Peer, reservedParents []*resource.Peer) {
+				assert.Empty(t, reservedParents)
+			},
+		},
+		{
+			name: "parents timing out the reservations are skipped",
+			handler: func(w http.ResponseWriter, req types.UploadReservationRequest) {
+				if req.PeerID == "child-0" {
+					time.Sleep(2 * mockUploadReservationConfig.Timeout)
+				}
+
+				_ = json.NewEncoder(w).Encode(types.UploadReservationResponse{Granted: true})
+			},
+			expect: func(t *testing.T, parents []*resource.Peer, reservedParents []*resource.Peer) {
+				assert.Equal(t, parents[1:], reservedParents)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var parents []*resource.Peer
+			for i := 0; i < 2; i++ {
+				// The reservation of parent is identified by the child id in the mock upload server.
+				i := i
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if err := nethttp.NewHMACSigner(mockUploadReservationConfig.HMACSecret, time.Minute).Verify(r); err != nil {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+
+					var req types.UploadReservationRequest
+					if r.URL.Path != types.UploadReservationPath || json.NewDecoder(r.Body).Decode(&req) != nil {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+
+					req.PeerID = fmt.Sprintf("%s-%d", req.PeerID, i)
+					tc.handler(w, req)
+				}))
+				defer server.Close()
+
+				ip, rawPort, _ := net.SplitHostPort(server.Listener.Addr().String())
+				port, _ := strconv.Atoi(rawPort)
+				mockHost := resource.NewHost(
+					fmt.Sprintf("host-%d", i), ip, mockRawHost.Hostname,
+					mockRawHost.Port, int32(port), mockRawHost.Type)
+				mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+				parents = append(parents, resource.NewPeer(fmt.Sprintf("parent-%d", i), mockResourceConfig, mockTask, mockHost))
+			}
+
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			child := resource.NewPeer("child", mockResourceConfig, parents[0].Task, mockHost)
+
+			r := NewReserver(mockUploadReservationConfig, mockDownloadTinyConfig)
+			start := time.Now()
+			tc.expect(t, parents, r.Reserve(context.Background(), child, parents))
+			assert.Less(t, time.Since(start), 2*mockUploadReservationConfig.Timeout)
+		})
+	}
+}
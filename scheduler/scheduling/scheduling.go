@@ -21,6 +21,7 @@ package scheduling
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 
@@ -35,8 +36,10 @@ import (
 	schedulerv2 "d7y.io/api/v2/pkg/apis/scheduler/v2"
 
 	"d7y.io/dragonfly/v2/pkg/container/set"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/event"
 	"d7y.io/dragonfly/v2/scheduler/resource"
 	"d7y.io/dragonfly/v2/scheduler/scheduling/evaluator"
 )
@@ -55,6 +58,9 @@ type Scheduling interface {
 
 	// FindSuccessParent finds success parent for the peer.
 	FindSuccessParent(context.Context, *resource.Peer, set.SafeSet[string]) (*resource.Peer, bool)
+
+	// FindSiblingPeers finds the sibling peers downloading the same hot task as the peer.
+	FindSiblingPeers(*resource.Peer) []*resource.Peer
 }
 
 type scheduling struct {
@@ -66,20 +72,138 @@ type scheduling struct {
 
 	// Scheduler dynamic configuration.
 	dynconfig config.DynconfigInterface
+
+	// Prober probes the health of candidate parents.
+	prober Prober
+
+	// reserver reserves the upload slots on candidate parents.
+	reserver Reserver
+
+	// backToSourceLimiter limits the peers downloading back-to-source simultaneously.
+	backToSourceLimiter BackToSourceLimiter
+
+	// antiAffinity checks the anti-affinity between the peer and the parent.
+	antiAffinity AntiAffinity
+
+	// taskManager loads the full task of the range task.
+	taskManager resource.TaskManager
+
+	// federation looks up the peers holding the task in the sibling scheduler clusters.
+	federation Federation
+
+	// eventBus publishes the parent switched events.
+	eventBus event.Bus
+
+	// evaluatorOptions is the additional options of evaluator.
+	evaluatorOptions []evaluator.Option
+
+	// featureFlags loads the feature flags rolled out to the scheduler.
+	featureFlags func() featureflag.Flags
 }
 
-func New(cfg *config.SchedulerConfig, dynconfig config.DynconfigInterface, pluginDir string) Scheduling {
-	return &scheduling{
-		evaluator: evaluator.New(cfg.Algorithm, pluginDir),
+// Option is a functional option for configuring the scheduling.
+type Option func(s *scheduling)
+
+// WithEvaluatorOptions sets the additional options of evaluator.
+func WithEvaluatorOptions(opts ...evaluator.Option) Option {
+	return func(s *scheduling) {
+		s.evaluatorOptions = append(s.evaluatorOptions, opts...)
+	}
+}
+
+// WithFeatureFlags sets the loader of feature flags rolled out to the scheduler.
+func WithFeatureFlags(featureFlags func() featureflag.Flags) Option {
+	return func(s *scheduling) {
+		s.featureFlags = featureFlags
+	}
+}
+
+// WithProber sets the prober of candidate parents.
+func WithProber(prober Prober) Option {
+	return func(s *scheduling) {
+		s.prober = prober
+	}
+}
+
+// WithReserver sets the reserver of upload slots on candidate parents.
+func WithReserver(reserver Reserver) Option {
+	return func(s *scheduling) {
+		s.reserver = reserver
+	}
+}
+
+// WithBackToSourceLimiter sets the limiter of back-to-source peers.
+func WithBackToSourceLimiter(limiter BackToSourceLimiter) Option {
+	return func(s *scheduling) {
+		s.backToSourceLimiter = limiter
+	}
+}
+
+// WithAntiAffinity sets the anti-affinity of candidate parents.
+func WithAntiAffinity(antiAffinity AntiAffinity) Option {
+	return func(s *scheduling) {
+		s.antiAffinity = antiAffinity
+	}
+}
+
+// WithTaskManager sets the task manager, the range task is scheduled
+// to the succeeded peers of the full task.
+func WithTaskManager(taskManager resource.TaskManager) Option {
+	return func(s *scheduling) {
+		s.taskManager = taskManager
+	}
+}
+
+// WithFederation sets the federation, the peers holding the task in the sibling
+// scheduler clusters are scheduled before falling back to origin.
+func WithFederation(federation Federation) Option {
+	return func(s *scheduling) {
+		s.federation = federation
+	}
+}
+
+// WithEventBus sets the event bus, the parent switched events are published
+// when the peer is scheduled to the new parents.
+func WithEventBus(eventBus event.Bus) Option {
+	return func(s *scheduling) {
+		s.eventBus = eventBus
+	}
+}
+
+func New(cfg *config.SchedulerConfig, dynconfig config.DynconfigInterface, pluginDir string, options ...Option) Scheduling {
+	s := &scheduling{
 		config:    cfg,
 		dynconfig: dynconfig,
 	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	if s.featureFlags != nil {
+		s.evaluatorOptions = append(s.evaluatorOptions, evaluator.WithFeatureFlags(s.featureFlags))
+	}
+
+	s.evaluator = evaluator.New(cfg.Algorithm, pluginDir, append([]evaluator.Option{
+		evaluator.WithPluginOptions(cfg.Plugin.Options),
+		evaluator.WithPluginWeight(cfg.Plugin.Weight),
+		evaluator.WithTopologyWeights(evaluator.TopologyWeights{
+			Region: cfg.Topology.RegionWeight,
+			Zone:   cfg.Topology.ZoneWeight,
+			Rack:   cfg.Topology.RackWeight,
+			Switch: cfg.Topology.SwitchWeight,
+		}),
+		evaluator.WithLatencyBudget(cfg.Inference.LatencyBudget),
+	}, s.evaluatorOptions...)...)
+
+	return s
 }
 
 // ScheduleCandidateParents schedules candidate parents to the normal peer.
 // Used only in v2 version of the grpc.
 func (s *scheduling) ScheduleCandidateParents(ctx context.Context, peer *resource.Peer, blocklist set.SafeSet[string]) error {
 	var n int
+	previousParents := peer.Parents()
 	for {
 		select {
 		case <-ctx.Done():
@@ -92,10 +216,13 @@ func (s *scheduling) ScheduleCandidateParents(ctx context.Context, peer *resourc
 		//
 		// Condition 1: Peer's NeedBackToSource is true.
 		// Condition 2: Scheduling exceeds the RetryBackToSourceLimit.
+		//
+		// The back-to-source limits are checked only when the response is going to be sent,
+		// and the peers exceeding the limits keep being scheduled to parents.
 		if peer.Task.CanBackToSource() {
 			// Check condition 1:
 			// Peer's NeedBackToSource is true.
-			if peer.NeedBackToSource.Load() {
+			if peer.NeedBackToSource.Load() && s.allowBackToSource(peer) {
 				stream, loaded := peer.LoadAnnouncePeerStream()
 				if !loaded {
 					peer.Log.Error("load stream failed")
@@ -120,7 +247,7 @@ func (s *scheduling) ScheduleCandidateParents(ctx context.Context, peer *resourc
 
 			// Check condition 2:
 			// The number of retry scheduling is greater than RetryBackToSourceLimit
-			if n >= s.config.RetryBackToSourceLimit {
+			if !peer.NeedBackToSource.Load() && n >= s.config.RetryBackToSourceLimit && s.allowBackToSource(peer) {
 				stream, loaded := peer.LoadAnnouncePeerStream()
 				if !loaded {
 					peer.Log.Error("load stream failed")
@@ -201,6 +328,7 @@ func (s *scheduling) ScheduleCandidateParents(ctx context.Context, peer *resourc
 			}
 		}
 
+		s.publishParentSwitched(peer, previousParents, candidateParents)
 		peer.Log.Infof("scheduling success in %d times", n+1)
 		return nil
 	}
@@ -210,6 +338,7 @@ func (s *scheduling) ScheduleCandidateParents(ctx context.Context, peer *resourc
 // Used only in v1 version of the grpc.
 func (s *scheduling) ScheduleParentAndCandidateParents(ctx context.Context, peer *resource.Peer, blocklist set.SafeSet[string]) {
 	var n int
+	previousParents := peer.Parents()
 	for {
 		select {
 		case <-ctx.Done():
@@ -222,10 +351,18 @@ func (s *scheduling) ScheduleParentAndCandidateParents(ctx context.Context, peer
 		//
 		// Condition 1: Peer needs back-to-source.
 		// Condition 2: Scheduling exceeds the RetryBackToSourceLimit.
-		if peer.Task.CanBackToSource() {
+		//
+		// The back-to-source limits are checked only when Code_SchedNeedBackSource is going to be sent,
+		// and the peers exceeding the limits keep being scheduled to parents.
+		if peer.Task.CanBackToSource() && (peer.NeedBackToSource.Load() || n >= s.config.RetryBackToSourceLimit) {
+			// Peers holding the task in the sibling clusters are scheduled before falling back to origin.
+			if s.scheduleFederatedParents(ctx, peer, blocklist) {
+				return
+			}
+
 			// Check condition 1:
 			// Peer's NeedBackToSource is true.
-			if peer.NeedBackToSource.Load() {
+			if peer.NeedBackToSource.Load() && s.allowBackToSource(peer) {
 				stream, loaded := peer.LoadReportPieceResultStream()
 				if !loaded {
 					peer.Log.Error("load stream failed")
@@ -258,7 +395,7 @@ func (s *scheduling) ScheduleParentAndCandidateParents(ctx context.Context, peer
 
 			// Check condition 2:
 			// The number of retry scheduling is greater than RetryBackToSourceLimit
-			if n >= s.config.RetryBackToSourceLimit {
+			if !peer.NeedBackToSource.Load() && n >= s.config.RetryBackToSourceLimit && s.allowBackToSource(peer) {
 				stream, loaded := peer.LoadReportPieceResultStream()
 				if !loaded {
 					peer.Log.Error("load stream failed")
@@ -322,8 +459,13 @@ func (s *scheduling) ScheduleParentAndCandidateParents(ctx context.Context, peer
 			continue
 		}
 
-		// Find candidate parents.
+		// Find candidate parents, if the peer downloads a range of the full task,
+		// the succeeded peers of the full task can be the candidate parents.
 		candidateParents, found := s.FindCandidateParents(ctx, peer, blocklist)
+		if !found {
+			candidateParents, found = s.findFullTaskParents(peer, blocklist)
+		}
+
 		if !found {
 			n++
 			peer.Log.Infof("scheduling failed in %d times, because of candidate parents not found", n)
@@ -361,19 +503,99 @@ func (s *scheduling) ScheduleParentAndCandidateParents(ctx context.Context, peer
 			return
 		}
 
-		// Add edge from parent to peer.
+		// Add edge from parent to peer, the parents of the full task are not in the dag.
 		for _, candidateParent := range candidateParents {
+			if candidateParent.Task.ID != peer.Task.ID {
+				continue
+			}
+
 			if err := peer.Task.AddPeerEdge(candidateParent, peer); err != nil {
 				peer.Log.Debugf("peer adds edge failed: %s", err.Error())
 				continue
 			}
 		}
 
+		s.publishParentSwitched(peer, previousParents, candidateParents)
 		peer.Log.Infof("scheduling success in %d times", n+1)
 		return
 	}
 }
 
+// publishParentSwitched publishes the parent switched event when the peer
+// has been downloading from the previous parents and is scheduled to the different parents.
+func (s *scheduling) publishParentSwitched(peer *resource.Peer, previousParents, parents []*resource.Peer) {
+	if s.eventBus == nil || len(previousParents) == 0 {
+		return
+	}
+
+	previousParentIDs := make([]string, 0, len(previousParents))
+	for _, previousParent := range previousParents {
+		previousParentIDs = append(previousParentIDs, previousParent.ID)
+	}
+
+	parentIDs := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		parentIDs = append(parentIDs, parent.ID)
+	}
+
+	sort.Strings(previousParentIDs)
+	sort.Strings(parentIDs)
+	if slices.Equal(previousParentIDs, parentIDs) {
+		return
+	}
+
+	s.eventBus.Publish(event.Event{
+		Type:              event.TypeParentSwitched,
+		TaskID:            peer.Task.ID,
+		URL:               peer.Task.URL,
+		Application:       peer.Task.Application,
+		Tag:               peer.Task.Tag,
+		PeerID:            peer.ID,
+		HostID:            peer.Host.ID,
+		ParentIDs:         parentIDs,
+		PreviousParentIDs: previousParentIDs,
+	})
+}
+
+// scheduleFederatedParents schedules the peers holding the task in the sibling scheduler clusters
+// as the parents of the peer, returns true if the parents are sent to the peer.
+func (s *scheduling) scheduleFederatedParents(ctx context.Context, peer *resource.Peer, blocklist set.SafeSet[string]) bool {
+	if s.federation == nil {
+		return false
+	}
+
+	parents := s.federation.FindParents(ctx, peer.Task.ID, blocklist)
+	if len(parents) == 0 {
+		return false
+	}
+
+	stream, loaded := peer.LoadReportPieceResultStream()
+	if !loaded {
+		peer.Log.Error("load stream failed")
+		return false
+	}
+
+	concurrentPieceCount := config.DefaultPeerConcurrentPieceCount
+	if config, err := s.dynconfig.GetSchedulerClusterClientConfig(); err == nil && config.ConcurrentPieceCount > 0 {
+		concurrentPieceCount = int(config.ConcurrentPieceCount)
+	}
+
+	if err := stream.Send(&schedulerv1.PeerPacket{
+		TaskId:         peer.Task.ID,
+		SrcPid:         peer.ID,
+		ParallelCount:  int32(concurrentPieceCount),
+		MainPeer:       parents[0],
+		CandidatePeers: parents[1:],
+		Code:           commonv1.Code_Success,
+	}); err != nil {
+		peer.Log.Error(err)
+		return false
+	}
+
+	peer.Log.Infof("send PeerPacket to peer, parent %s is in the sibling cluster", parents[0].PeerId)
+	return true
+}
+
 // FindCandidateParents finds candidate parents for the peer.
 func (s *scheduling) FindCandidateParents(ctx context.Context, peer *resource.Peer, blocklist set.SafeSet[string]) ([]*resource.Peer, bool) {
 	// Only PeerStateRunning peers need to be rescheduled,
@@ -392,13 +614,7 @@ func (s *scheduling) FindCandidateParents(ctx context.Context, peer *resource.Pe
 	}
 
 	// Sort candidate parents by evaluation score.
-	taskTotalPieceCount := peer.Task.TotalPieceCount.Load()
-	sort.Slice(
-		candidateParents,
-		func(i, j int) bool {
-			return s.evaluator.Evaluate(candidateParents[i], peer, taskTotalPieceCount) > s.evaluator.Evaluate(candidateParents[j], peer, taskTotalPieceCount)
-		},
-	)
+	s.sortParents(candidateParents, peer)
 
 	// Get the parents with candidateParentLimit.
 	candidateParentLimit := config.DefaultSchedulerCandidateParentLimit
@@ -408,15 +624,36 @@ func (s *scheduling) FindCandidateParents(ctx context.Context, peer *resource.Pe
 		}
 	}
 
+	// The task in flash crowd is scheduled in tree-building mode with deeper fan-out limits.
+	if s.config.FlashCrowd.Enable && peer.Task.FlashCrowd.Load() {
+		candidateParentLimit = s.config.FlashCrowd.CandidateParentLimit
+	}
+
 	if len(candidateParents) > candidateParentLimit {
 		candidateParents = candidateParents[:candidateParentLimit]
 	}
 
+	// Reserve the upload slots before scheduling the candidate parents,
+	// and the saturated parents denying the reservations are skipped.
+	if s.reserver != nil {
+		candidateParents = s.reserver.Reserve(ctx, peer, candidateParents)
+		if len(candidateParents) == 0 {
+			peer.Log.Info("candidate parents deny the upload slot reservations")
+			return []*resource.Peer{}, false
+		}
+	}
+
 	var parentIDs []string
 	for _, candidateParent := range candidateParents {
 		parentIDs = append(parentIDs, candidateParent.ID)
 	}
 
+	// Probe the candidate parents asynchronously, and the stale parents
+	// will be filtered in the next scheduling.
+	if s.prober != nil {
+		s.prober.Probe(candidateParents...)
+	}
+
 	peer.Log.Infof("scheduling candidate parents is %#v", parentIDs)
 	return candidateParents, true
 }
@@ -446,18 +683,89 @@ func (s *scheduling) FindSuccessParent(ctx context.Context, peer *resource.Peer,
 	}
 
 	// Sort candidate parents by evaluation score.
-	taskTotalPieceCount := peer.Task.TotalPieceCount.Load()
-	sort.Slice(
-		successParents,
-		func(i, j int) bool {
-			return s.evaluator.Evaluate(successParents[i], peer, taskTotalPieceCount) > s.evaluator.Evaluate(successParents[j], peer, taskTotalPieceCount)
-		},
-	)
+	s.sortParents(successParents, peer)
+
+	if s.prober != nil {
+		s.prober.Probe(successParents[0])
+	}
 
 	peer.Log.Infof("scheduling success parent is %s", successParents[0].ID)
 	return successParents[0], true
 }
 
+// findFullTaskParents finds the succeeded peers of the full task as the candidate parents
+// when the peer downloads a range of it, the range is mapped onto the pieces of the full task.
+func (s *scheduling) findFullTaskParents(peer *resource.Peer, blocklist set.SafeSet[string]) ([]*resource.Peer, bool) {
+	if s.taskManager == nil || peer.Task.ParentID == "" || !peer.FSM.Is(resource.PeerStateRunning) {
+		return nil, false
+	}
+
+	fullTask, loaded := s.taskManager.Load(peer.Task.ParentID)
+	if !loaded {
+		return nil, false
+	}
+
+	var candidateParents []*resource.Peer
+	for _, candidateParent := range fullTask.LoadRandomPeers(uint(config.DefaultSchedulerFilterParentLimit)) {
+		if blocklist.Contains(candidateParent.ID) ||
+			!candidateParent.FSM.Is(resource.PeerStateSucceeded) ||
+			candidateParent.Host.ID == peer.Host.ID ||
+			candidateParent.Host.Draining.Load() ||
+			candidateParent.Host.Reputation.IsBlocked() ||
+			candidateParent.Host.FreeUploadCount() <= 0 {
+			continue
+		}
+
+		if s.prober != nil && s.prober.IsStale(candidateParent) {
+			continue
+		}
+
+		if s.antiAffinity != nil && s.antiAffinity.Conflict(peer, candidateParent) {
+			continue
+		}
+
+		if s.evaluator.IsBadNode(candidateParent) {
+			continue
+		}
+
+		candidateParents = append(candidateParents, candidateParent)
+	}
+
+	if len(candidateParents) == 0 {
+		return nil, false
+	}
+
+	s.sortParents(candidateParents, peer)
+	if len(candidateParents) > config.DefaultSchedulerCandidateParentLimit {
+		candidateParents = candidateParents[:config.DefaultSchedulerCandidateParentLimit]
+	}
+
+	peer.Log.Infof("scheduling %d candidate parents of the full task %s", len(candidateParents), fullTask.ID)
+	return candidateParents, true
+}
+
+// sortParents sorts the parents by evaluation score in descending order,
+// the parents are scored in a batch before sorting.
+func (s *scheduling) sortParents(parents []*resource.Peer, peer *resource.Peer) {
+	scores := evaluator.EvaluateParents(s.evaluator, parents, peer, peer.Task.TotalPieceCount.Load())
+	sort.Sort(&scoredParents{parents: parents, scores: scores})
+}
+
+// scoredParents implements sort.Interface, the parent with higher score is sorted first.
+type scoredParents struct {
+	parents []*resource.Peer
+	scores  []float64
+}
+
+func (sp *scoredParents) Len() int { return len(sp.parents) }
+
+func (sp *scoredParents) Less(i, j int) bool { return sp.scores[i] > sp.scores[j] }
+
+func (sp *scoredParents) Swap(i, j int) {
+	sp.parents[i], sp.parents[j] = sp.parents[j], sp.parents[i]
+	sp.scores[i], sp.scores[j] = sp.scores[j], sp.scores[i]
+}
+
 // filterCandidateParents filters the candidate parents that can be scheduled.
 func (s *scheduling) filterCandidateParents(peer *resource.Peer, blocklist set.SafeSet[string]) []*resource.Peer {
 	filterParentLimit := config.DefaultSchedulerFilterParentLimit
@@ -467,6 +775,11 @@ func (s *scheduling) filterCandidateParents(peer *resource.Peer, blocklist set.S
 		}
 	}
 
+	// The task in flash crowd is scheduled in tree-building mode with deeper fan-out limits.
+	if s.config.FlashCrowd.Enable && peer.Task.FlashCrowd.Load() {
+		filterParentLimit = s.config.FlashCrowd.FilterParentLimit
+	}
+
 	var (
 		candidateParents   []*resource.Peer
 		candidateParentIDs []string
@@ -492,6 +805,30 @@ func (s *scheduling) filterCandidateParents(peer *resource.Peer, blocklist set.S
 			continue
 		}
 
+		// Candidate parent host is draining for maintenance.
+		if candidateParent.Host.Draining.Load() {
+			peer.Log.Debugf("parent %s is not selected because its host %s is draining", candidateParent.ID, candidateParent.Host.ID)
+			continue
+		}
+
+		// Candidate parent host is blocklisted by reputation.
+		if candidateParent.Host.Reputation.IsBlocked() {
+			peer.Log.Debugf("parent %s is not selected because its host %s is blocklisted", candidateParent.ID, candidateParent.Host.ID)
+			continue
+		}
+
+		// Candidate parent is probed as stale.
+		if s.prober != nil && s.prober.IsStale(candidateParent) {
+			peer.Log.Debugf("parent %s is not selected because it is stale", candidateParent.ID)
+			continue
+		}
+
+		// Candidate parent conflicts with the anti-affinity labels of the peer.
+		if s.antiAffinity != nil && s.antiAffinity.Conflict(peer, candidateParent) {
+			peer.Log.Debugf("parent %s is not selected because of anti-affinity", candidateParent.ID)
+			continue
+		}
+
 		// Candidate parent is bad node.
 		if s.evaluator.IsBadNode(candidateParent) {
 			peer.Log.Debugf("parent %s is not selected because it is bad node", candidateParent.ID)
@@ -532,6 +869,71 @@ func (s *scheduling) filterCandidateParents(peer *resource.Peer, blocklist set.S
 	return candidateParents
 }
 
+// peerExchangeEnabled returns whether peer exchange is enabled by config or rolled out by feature flag.
+func (s *scheduling) peerExchangeEnabled() bool {
+	if s.config.PeerExchange.Enable {
+		return true
+	}
+
+	return s.featureFlags != nil && s.featureFlags().Enabled(featureflag.PeerExchange)
+}
+
+// FindSiblingPeers finds the sibling peers downloading the same hot task concurrently,
+// the peer gossips piece availability with the siblings directly instead of rescheduling.
+func (s *scheduling) FindSiblingPeers(peer *resource.Peer) []*resource.Peer {
+	if !s.peerExchangeEnabled() || peer.Task.PeerCount() < s.config.PeerExchange.HotTaskPeerCount {
+		return nil
+	}
+
+	parentIDs := set.New[string]()
+	for _, parent := range peer.Parents() {
+		parentIDs.Add(parent.ID)
+	}
+
+	var siblings []*resource.Peer
+	for _, sibling := range peer.Task.LoadRandomPeers(uint(config.DefaultSchedulerFilterParentLimit)) {
+		if len(siblings) >= s.config.PeerExchange.SiblingLimit {
+			break
+		}
+
+		// Sibling is the peer itself, the parent or on the same host.
+		if sibling.ID == peer.ID || parentIDs.Contains(sibling.ID) || sibling.Host.ID == peer.Host.ID {
+			continue
+		}
+
+		// Sibling is not downloading the task.
+		if !sibling.FSM.Is(resource.PeerStateRunning) && !sibling.FSM.Is(resource.PeerStateBackToSource) {
+			continue
+		}
+
+		// Sibling is probed as stale.
+		if s.prober != nil && s.prober.IsStale(sibling) {
+			continue
+		}
+
+		// Sibling conflicts with the anti-affinity labels of the peer.
+		if s.antiAffinity != nil && s.antiAffinity.Conflict(peer, sibling) {
+			continue
+		}
+
+		siblings = append(siblings, sibling)
+	}
+
+	peer.Log.Infof("find %d sibling peers", len(siblings))
+	return siblings
+}
+
+// allowBackToSource determines whether peer is allowed to download back-to-source by the limiter,
+// it is called only when the back-to-source response is going to be sent, because the limiter
+// takes a token of the origin qps.
+func (s *scheduling) allowBackToSource(peer *resource.Peer) bool {
+	if s.backToSourceLimiter == nil {
+		return true
+	}
+
+	return s.backToSourceLimiter.Allow(peer)
+}
+
 // ConstructSuccessSmallTaskResponse constructs scheduling successful response of the small task.
 // Used only in v2 version of the grpc.
 func ConstructSuccessSmallTaskResponse(candidateParent *resource.Peer) *schedulerv2.AnnouncePeerResponse_SmallTaskResponse {
@@ -887,15 +1289,6 @@ func ConstructSuccessPeerPacket(dynconfig config.DynconfigInterface, peer *resou
 		concurrentPieceCount = int(config.ConcurrentPieceCount)
 	}
 
-	var parents []*schedulerv1.PeerPacket_DestPeer
-	for _, candidateParent := range candidateParents {
-		parents = append(parents, &schedulerv1.PeerPacket_DestPeer{
-			Ip:      candidateParent.Host.IP,
-			RpcPort: candidateParent.Host.Port,
-			PeerId:  candidateParent.ID,
-		})
-	}
-
 	return &schedulerv1.PeerPacket{
 		TaskId:        peer.Task.ID,
 		SrcPid:        peer.ID,
@@ -905,7 +1298,22 @@ func ConstructSuccessPeerPacket(dynconfig config.DynconfigInterface, peer *resou
 			RpcPort: parent.Host.Port,
 			PeerId:  parent.ID,
 		},
-		CandidatePeers: parents,
+		CandidatePeers: ConstructDestPeers(candidateParents),
 		Code:           commonv1.Code_Success,
 	}
 }
+
+// ConstructDestPeers constructs the destination peers of peer packet.
+// Used only in v1 version of the grpc.
+func ConstructDestPeers(peers []*resource.Peer) []*schedulerv1.PeerPacket_DestPeer {
+	var destPeers []*schedulerv1.PeerPacket_DestPeer
+	for _, peer := range peers {
+		destPeers = append(destPeers, &schedulerv1.PeerPacket_DestPeer{
+			Ip:      peer.Host.IP,
+			RpcPort: peer.Host.Port,
+			PeerId:  peer.ID,
+		})
+	}
+
+	return destPeers
+}
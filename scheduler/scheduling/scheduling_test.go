@@ -43,13 +43,17 @@ import (
 	"d7y.io/dragonfly/v2/manager/types"
 	"d7y.io/dragonfly/v2/pkg/container/set"
 	"d7y.io/dragonfly/v2/pkg/digest"
+	"d7y.io/dragonfly/v2/pkg/featureflag"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	nethttp "d7y.io/dragonfly/v2/pkg/net/http"
 	pkgtypes "d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/config"
 	configmocks "d7y.io/dragonfly/v2/scheduler/config/mocks"
+	"d7y.io/dragonfly/v2/scheduler/event"
+	eventmocks "d7y.io/dragonfly/v2/scheduler/event/mocks"
 	"d7y.io/dragonfly/v2/scheduler/resource"
 	"d7y.io/dragonfly/v2/scheduler/scheduling/evaluator"
+	"d7y.io/dragonfly/v2/scheduler/scheduling/mocks"
 )
 
 var (
@@ -732,6 +736,144 @@ func TestScheduling_ScheduleParentAndCandidateParents(t *testing.T) {
 	}
 }
 
+func TestScheduling_ScheduleParentAndCandidateParentsWithFederation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(peer *resource.Peer, blocklist set.SafeSet[string], mf *mocks.MockFederationMockRecorder, ms *schedulerv1mocks.MockScheduler_ReportPieceResultServerMockRecorder, md *configmocks.MockDynconfigInterfaceMockRecorder)
+		expect func(t *testing.T, peer *resource.Peer)
+	}{
+		{
+			name: "peer needs back-to-source and parents are found in the sibling clusters",
+			mock: func(peer *resource.Peer, blocklist set.SafeSet[string], mf *mocks.MockFederationMockRecorder, ms *schedulerv1mocks.MockScheduler_ReportPieceResultServerMockRecorder, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				parents := []*schedulerv1.PeerPacket_DestPeer{
+					{Ip: "127.0.0.2", RpcPort: 8003, PeerId: "foo"},
+					{Ip: "127.0.0.3", RpcPort: 8003, PeerId: "bar"},
+				}
+
+				gomock.InOrder(
+					mf.FindParents(gomock.Any(), peer.Task.ID, blocklist).Return(parents).Times(1),
+					md.GetSchedulerClusterClientConfig().Return(types.SchedulerClusterClientConfig{}, errors.New("foo")).Times(1),
+					ms.Send(gomock.Eq(&schedulerv1.PeerPacket{
+						TaskId:         peer.Task.ID,
+						SrcPid:         peer.ID,
+						ParallelCount:  int32(config.DefaultPeerConcurrentPieceCount),
+						MainPeer:       parents[0],
+						CandidatePeers: parents[1:],
+						Code:           commonv1.Code_Success,
+					})).Return(nil).Times(1),
+				)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.True(peer.FSM.Is(resource.PeerStateRunning))
+			},
+		},
+		{
+			name: "peer needs back-to-source and parents are not found in the sibling clusters",
+			mock: func(peer *resource.Peer, blocklist set.SafeSet[string], mf *mocks.MockFederationMockRecorder, ms *schedulerv1mocks.MockScheduler_ReportPieceResultServerMockRecorder, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				gomock.InOrder(
+					mf.FindParents(gomock.Any(), peer.Task.ID, blocklist).Return(nil).Times(1),
+					ms.Send(gomock.Eq(&schedulerv1.PeerPacket{Code: commonv1.Code_SchedNeedBackSource})).Return(nil).Times(1),
+				)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.True(peer.FSM.Is(resource.PeerStateBackToSource))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			stream := schedulerv1mocks.NewMockScheduler_ReportPieceResultServer(ctl)
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			federation := mocks.NewMockFederation(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+			mockTask.StorePeer(peer)
+			peer.NeedBackToSource.Store(true)
+			peer.FSM.SetState(resource.PeerStateRunning)
+			peer.StoreReportPieceResultStream(stream)
+			blocklist := set.NewSafeSet[string]()
+
+			tc.mock(peer, blocklist, federation.EXPECT(), stream.EXPECT(), dynconfig.EXPECT())
+			scheduling := New(mockSchedulerConfig, dynconfig, mockPluginDir, WithFederation(federation))
+			scheduling.ScheduleParentAndCandidateParents(context.Background(), peer, blocklist)
+			tc.expect(t, peer)
+		})
+	}
+}
+
+func TestScheduling_ScheduleParentAndCandidateParentsWithBackToSourceLimiter(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(peer *resource.Peer, blocklist set.SafeSet[string], ml *mocks.MockBackToSourceLimiterMockRecorder, mf *mocks.MockFederationMockRecorder, ms *schedulerv1mocks.MockScheduler_ReportPieceResultServerMockRecorder, md *configmocks.MockDynconfigInterfaceMockRecorder)
+		expect func(t *testing.T, peer *resource.Peer)
+	}{
+		{
+			name: "peer is scheduled to parents in the sibling clusters without checking limiter",
+			mock: func(peer *resource.Peer, blocklist set.SafeSet[string], ml *mocks.MockBackToSourceLimiterMockRecorder, mf *mocks.MockFederationMockRecorder, ms *schedulerv1mocks.MockScheduler_ReportPieceResultServerMockRecorder, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				parents := []*schedulerv1.PeerPacket_DestPeer{{Ip: "127.0.0.2", RpcPort: 8003, PeerId: "foo"}}
+				gomock.InOrder(
+					mf.FindParents(gomock.Any(), peer.Task.ID, blocklist).Return(parents).Times(1),
+					md.GetSchedulerClusterClientConfig().Return(types.SchedulerClusterClientConfig{}, errors.New("foo")).Times(1),
+					ms.Send(gomock.Any()).Return(nil).Times(1),
+				)
+				ml.Allow(gomock.Any()).Times(0)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.True(peer.FSM.Is(resource.PeerStateRunning))
+			},
+		},
+		{
+			name: "limiter is checked when Code_SchedNeedBackSource is going to be sent",
+			mock: func(peer *resource.Peer, blocklist set.SafeSet[string], ml *mocks.MockBackToSourceLimiterMockRecorder, mf *mocks.MockFederationMockRecorder, ms *schedulerv1mocks.MockScheduler_ReportPieceResultServerMockRecorder, md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				gomock.InOrder(
+					mf.FindParents(gomock.Any(), peer.Task.ID, blocklist).Return(nil).Times(1),
+					ml.Allow(peer).Return(true).Times(1),
+					ms.Send(gomock.Eq(&schedulerv1.PeerPacket{Code: commonv1.Code_SchedNeedBackSource})).Return(nil).Times(1),
+				)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.True(peer.FSM.Is(resource.PeerStateBackToSource))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			stream := schedulerv1mocks.NewMockScheduler_ReportPieceResultServer(ctl)
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			federation := mocks.NewMockFederation(ctl)
+			limiter := mocks.NewMockBackToSourceLimiter(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+			mockTask.StorePeer(peer)
+			peer.NeedBackToSource.Store(true)
+			peer.FSM.SetState(resource.PeerStateRunning)
+			peer.StoreReportPieceResultStream(stream)
+			blocklist := set.NewSafeSet[string]()
+
+			tc.mock(peer, blocklist, limiter.EXPECT(), federation.EXPECT(), stream.EXPECT(), dynconfig.EXPECT())
+			scheduling := New(mockSchedulerConfig, dynconfig, mockPluginDir, WithFederation(federation), WithBackToSourceLimiter(limiter))
+			scheduling.ScheduleParentAndCandidateParents(context.Background(), peer, blocklist)
+			tc.expect(t, peer)
+		})
+	}
+}
+
 func TestScheduling_FindCandidateParents(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -811,6 +953,38 @@ func TestScheduling_FindCandidateParents(t *testing.T) {
 				assert.False(ok)
 			},
 		},
+		{
+			name: "parent host is draining",
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer, blocklist set.SafeSet[string], md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				peer.FSM.SetState(resource.PeerStateRunning)
+				mockPeers[0].FSM.SetState(resource.PeerStateSucceeded)
+				peer.Task.StorePeer(peer)
+				peer.Task.StorePeer(mockPeers[0])
+				mockPeers[0].Host.Draining.Store(true)
+
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(1)
+			},
+			expect: func(t *testing.T, peer *resource.Peer, mockPeers []*resource.Peer, parents []*resource.Peer, ok bool) {
+				assert := assert.New(t)
+				assert.False(ok)
+			},
+		},
+		{
+			name: "parent host is blocklisted",
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer, blocklist set.SafeSet[string], md *configmocks.MockDynconfigInterfaceMockRecorder) {
+				peer.FSM.SetState(resource.PeerStateRunning)
+				mockPeers[0].FSM.SetState(resource.PeerStateSucceeded)
+				peer.Task.StorePeer(peer)
+				peer.Task.StorePeer(mockPeers[0])
+				mockPeers[0].Host.Reputation.Block(time.Now().Add(time.Minute))
+
+				md.GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(1)
+			},
+			expect: func(t *testing.T, peer *resource.Peer, mockPeers []*resource.Peer, parents []*resource.Peer, ok bool) {
+				assert := assert.New(t)
+				assert.False(ok)
+			},
+		},
 		{
 			name: "parent free upload load is zero",
 			mock: func(peer *resource.Peer, mockPeers []*resource.Peer, blocklist set.SafeSet[string], md *configmocks.MockDynconfigInterfaceMockRecorder) {
@@ -1009,6 +1183,479 @@ func TestScheduling_FindCandidateParents(t *testing.T) {
 	}
 }
 
+func TestScheduling_FindCandidateParentsWithProber(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+	prober := mocks.NewMockProber(ctl)
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+	var mockPeers []*resource.Peer
+	for i := 0; i < 2; i++ {
+		mockHost := resource.NewHost(
+			idgen.HostIDV2("127.0.0.1", uuid.New().String()), mockRawHost.IP, mockRawHost.Hostname,
+			mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+		mockPeer := resource.NewPeer(idgen.PeerIDV1(fmt.Sprintf("127.0.0.%d", i)), mockResourceConfig, mockTask, mockHost)
+		mockPeer.FSM.SetState(resource.PeerStateSucceeded)
+		mockTask.StorePeer(mockPeer)
+		mockPeers = append(mockPeers, mockPeer)
+	}
+
+	peer.FSM.SetState(resource.PeerStateRunning)
+	mockTask.StorePeer(peer)
+
+	gomock.InOrder(
+		prober.EXPECT().IsStale(gomock.Any()).DoAndReturn(func(parent *resource.Peer) bool {
+			return parent.ID == mockPeers[0].ID
+		}).Times(2),
+		prober.EXPECT().Probe(mockPeers[1]).Times(1),
+	)
+	dynconfig.EXPECT().GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(2)
+
+	scheduling := New(mockSchedulerConfig, dynconfig, mockPluginDir, WithProber(prober))
+	parents, found := scheduling.FindCandidateParents(context.Background(), peer, set.NewSafeSet[string]())
+	assert := assert.New(t)
+	assert.True(found)
+	assert.Len(parents, 1)
+	assert.Equal(mockPeers[1].ID, parents[0].ID)
+}
+
+func TestScheduling_FindCandidateParentsWithReserver(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+	reserver := mocks.NewMockReserver(ctl)
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+	mockParentHost := resource.NewHost(
+		idgen.HostIDV2("127.0.0.1", uuid.New().String()), mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockParent := resource.NewPeer(idgen.PeerIDV1("127.0.0.1"), mockResourceConfig, mockTask, mockParentHost)
+	mockParent.FSM.SetState(resource.PeerStateSucceeded)
+	mockTask.StorePeer(mockParent)
+
+	peer.FSM.SetState(resource.PeerStateRunning)
+	mockTask.StorePeer(peer)
+
+	dynconfig.EXPECT().GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(4)
+	gomock.InOrder(
+		reserver.EXPECT().Reserve(gomock.Any(), peer, []*resource.Peer{mockParent}).Return([]*resource.Peer{mockParent}).Times(1),
+		reserver.EXPECT().Reserve(gomock.Any(), peer, []*resource.Peer{mockParent}).Return(nil).Times(1),
+	)
+
+	scheduling := New(mockSchedulerConfig, dynconfig, mockPluginDir, WithReserver(reserver))
+	assert := assert.New(t)
+	parents, found := scheduling.FindCandidateParents(context.Background(), peer, set.NewSafeSet[string]())
+	assert.True(found)
+	assert.Equal([]*resource.Peer{mockParent}, parents)
+
+	parents, found = scheduling.FindCandidateParents(context.Background(), peer, set.NewSafeSet[string]())
+	assert.False(found)
+	assert.Len(parents, 0)
+}
+
+func TestScheduling_FindCandidateParentsWithAntiAffinity(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+	antiAffinity := mocks.NewMockAntiAffinity(ctl)
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+	var mockPeers []*resource.Peer
+	for i := 0; i < 2; i++ {
+		mockHost := resource.NewHost(
+			idgen.HostIDV2("127.0.0.1", uuid.New().String()), mockRawHost.IP, mockRawHost.Hostname,
+			mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+		mockPeer := resource.NewPeer(idgen.PeerIDV1(fmt.Sprintf("127.0.0.%d", i)), mockResourceConfig, mockTask, mockHost)
+		mockPeer.FSM.SetState(resource.PeerStateSucceeded)
+		mockTask.StorePeer(mockPeer)
+		mockPeers = append(mockPeers, mockPeer)
+	}
+
+	peer.FSM.SetState(resource.PeerStateRunning)
+	mockTask.StorePeer(peer)
+
+	antiAffinity.EXPECT().Conflict(peer, gomock.Any()).DoAndReturn(func(peer *resource.Peer, parent *resource.Peer) bool {
+		return parent.ID == mockPeers[0].ID
+	}).Times(2)
+	dynconfig.EXPECT().GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(2)
+
+	scheduling := New(mockSchedulerConfig, dynconfig, mockPluginDir, WithAntiAffinity(antiAffinity))
+	parents, found := scheduling.FindCandidateParents(context.Background(), peer, set.NewSafeSet[string]())
+	assert := assert.New(t)
+	assert.True(found)
+	assert.Len(parents, 1)
+	assert.Equal(mockPeers[1].ID, parents[0].ID)
+}
+
+func TestScheduling_FindCandidateParentsWithFlashCrowd(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithDigest(mockTaskDigest), resource.WithPieceLength(mockTaskPieceLength))
+	peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+	for i := 0; i < 8; i++ {
+		mockHost := resource.NewHost(
+			idgen.HostIDV2("127.0.0.1", uuid.New().String()), mockRawHost.IP, mockRawHost.Hostname,
+			mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+		mockPeer := resource.NewPeer(idgen.PeerIDV1(fmt.Sprintf("127.0.0.%d", i)), mockResourceConfig, mockTask, mockHost)
+		mockPeer.FSM.SetState(resource.PeerStateSucceeded)
+		mockTask.StorePeer(mockPeer)
+	}
+
+	peer.FSM.SetState(resource.PeerStateRunning)
+	mockTask.StorePeer(peer)
+	mockTask.FlashCrowd.Store(true)
+
+	dynconfig.EXPECT().GetSchedulerClusterConfig().Return(types.SchedulerClusterConfig{}, errors.New("foo")).Times(2)
+
+	cfg := *mockSchedulerConfig
+	cfg.FlashCrowd = config.FlashCrowdConfig{
+		Enable:               true,
+		CandidateParentLimit: 6,
+		FilterParentLimit:    80,
+	}
+	scheduling := New(&cfg, dynconfig, mockPluginDir)
+	parents, found := scheduling.FindCandidateParents(context.Background(), peer, set.NewSafeSet[string]())
+	assert := assert.New(t)
+	assert.True(found)
+	assert.Len(parents, 6)
+}
+
+func TestScheduling_FindSiblingPeers(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       config.PeerExchangeConfig
+		featureFlags featureflag.Flags
+		mock         func(peer *resource.Peer, mockPeers []*resource.Peer)
+		expect       func(t *testing.T, mockPeers []*resource.Peer, siblings []*resource.Peer)
+	}{
+		{
+			name:   "peer exchange is disabled",
+			config: config.PeerExchangeConfig{},
+			mock:   func(peer *resource.Peer, mockPeers []*resource.Peer) {},
+			expect: func(t *testing.T, mockPeers []*resource.Peer, siblings []*resource.Peer) {
+				assert.Empty(t, siblings)
+			},
+		},
+		{
+			name:         "peer exchange is disabled without feature flag",
+			config:       config.PeerExchangeConfig{HotTaskPeerCount: 1, SiblingLimit: 4},
+			featureFlags: featureflag.Flags{featureflag.MLEvaluator},
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer) {
+				mockPeers[0].FSM.SetState(resource.PeerStateRunning)
+			},
+			expect: func(t *testing.T, mockPeers []*resource.Peer, siblings []*resource.Peer) {
+				assert.Empty(t, siblings)
+			},
+		},
+		{
+			name:         "peer exchange is rolled out by feature flag",
+			config:       config.PeerExchangeConfig{HotTaskPeerCount: 1, SiblingLimit: 4},
+			featureFlags: featureflag.Flags{featureflag.PeerExchange},
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer) {
+				mockPeers[0].FSM.SetState(resource.PeerStateRunning)
+				mockPeers[1].FSM.SetState(resource.PeerStateBackToSource)
+			},
+			expect: func(t *testing.T, mockPeers []*resource.Peer, siblings []*resource.Peer) {
+				assert := assert.New(t)
+				assert.ElementsMatch([]*resource.Peer{mockPeers[0], mockPeers[1]}, siblings)
+			},
+		},
+		{
+			name:   "task is not hot",
+			config: config.PeerExchangeConfig{Enable: true, HotTaskPeerCount: 100, SiblingLimit: 4},
+			mock:   func(peer *resource.Peer, mockPeers []*resource.Peer) {},
+			expect: func(t *testing.T, mockPeers []*resource.Peer, siblings []*resource.Peer) {
+				assert.Empty(t, siblings)
+			},
+		},
+		{
+			name:   "find downloading siblings except parents",
+			config: config.PeerExchangeConfig{Enable: true, HotTaskPeerCount: 1, SiblingLimit: 4},
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer) {
+				mockPeers[0].FSM.SetState(resource.PeerStateRunning)
+				mockPeers[1].FSM.SetState(resource.PeerStateBackToSource)
+				mockPeers[2].FSM.SetState(resource.PeerStateSucceeded)
+				mockPeers[3].FSM.SetState(resource.PeerStateRunning)
+			},
+			expect: func(t *testing.T, mockPeers []*resource.Peer, siblings []*resource.Peer) {
+				assert := assert.New(t)
+				assert.ElementsMatch([]*resource.Peer{mockPeers[0], mockPeers[1]}, siblings)
+			},
+		},
+		{
+			name:   "siblings exceed the limit",
+			config: config.PeerExchangeConfig{Enable: true, HotTaskPeerCount: 1, SiblingLimit: 1},
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer) {
+				mockPeers[0].FSM.SetState(resource.PeerStateRunning)
+				mockPeers[1].FSM.SetState(resource.PeerStateRunning)
+			},
+			expect: func(t *testing.T, mockPeers []*resource.Peer, siblings []*resource.Peer) {
+				assert.Len(t, siblings, 1)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+			mockTask.StorePeer(peer)
+
+			var mockPeers []*resource.Peer
+			for i := 0; i < 4; i++ {
+				mockHost := resource.NewHost(
+					idgen.HostIDV2("127.0.0.1", uuid.New().String()), mockRawHost.IP, mockRawHost.Hostname,
+					mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+				mockPeer := resource.NewPeer(idgen.PeerIDV1(fmt.Sprintf("127.0.0.%d", i)), mockResourceConfig, mockTask, mockHost)
+				mockTask.StorePeer(mockPeer)
+				mockPeers = append(mockPeers, mockPeer)
+			}
+
+			if err := mockTask.AddPeerEdge(mockPeers[3], peer); err != nil {
+				t.Fatal(err)
+			}
+
+			tc.mock(peer, mockPeers)
+			cfg := *mockSchedulerConfig
+			cfg.PeerExchange = tc.config
+			s := New(&cfg, dynconfig, mockPluginDir, WithFeatureFlags(func() featureflag.Flags { return tc.featureFlags }))
+			tc.expect(t, mockPeers, s.FindSiblingPeers(peer))
+		})
+	}
+}
+
+func TestScheduling_publishParentSwitched(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(s *scheduling, peer *resource.Peer, mockPeers []*resource.Peer)
+		mock func(peer *resource.Peer, mockPeers []*resource.Peer, mb *eventmocks.MockBusMockRecorder)
+	}{
+		{
+			name: "peer has no previous parents",
+			run: func(s *scheduling, peer *resource.Peer, mockPeers []*resource.Peer) {
+				s.publishParentSwitched(peer, nil, mockPeers[:1])
+			},
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer, mb *eventmocks.MockBusMockRecorder) {},
+		},
+		{
+			name: "parents are not changed",
+			run: func(s *scheduling, peer *resource.Peer, mockPeers []*resource.Peer) {
+				s.publishParentSwitched(peer, []*resource.Peer{mockPeers[0], mockPeers[1]}, []*resource.Peer{mockPeers[1], mockPeers[0]})
+			},
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer, mb *eventmocks.MockBusMockRecorder) {},
+		},
+		{
+			name: "parents are switched",
+			run: func(s *scheduling, peer *resource.Peer, mockPeers []*resource.Peer) {
+				s.publishParentSwitched(peer, mockPeers[:1], mockPeers[1:])
+			},
+			mock: func(peer *resource.Peer, mockPeers []*resource.Peer, mb *eventmocks.MockBusMockRecorder) {
+				mb.Publish(event.Event{
+					Type:              event.TypeParentSwitched,
+					TaskID:            peer.Task.ID,
+					URL:               peer.Task.URL,
+					Application:       peer.Task.Application,
+					Tag:               peer.Task.Tag,
+					PeerID:            peer.ID,
+					HostID:            peer.Host.ID,
+					ParentIDs:         []string{mockPeers[1].ID},
+					PreviousParentIDs: []string{mockPeers[0].ID},
+				}).Times(1)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			eventBus := eventmocks.NewMockBus(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+			var mockPeers []*resource.Peer
+			for i := 0; i < 2; i++ {
+				mockPeers = append(mockPeers, resource.NewPeer(idgen.PeerIDV1(fmt.Sprintf("127.0.0.%d", i)), mockResourceConfig, mockTask, mockHost))
+			}
+
+			tc.mock(peer, mockPeers, eventBus.EXPECT())
+			s := New(mockSchedulerConfig, dynconfig, mockPluginDir, WithEventBus(eventBus)).(*scheduling)
+			tc.run(s, peer, mockPeers)
+		})
+	}
+}
+
+func TestScheduling_allowBackToSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		limiter func(ctl *gomock.Controller) BackToSourceLimiter
+		expect  func(t *testing.T, ok bool)
+	}{
+		{
+			name: "limiter is not configured",
+			limiter: func(ctl *gomock.Controller) BackToSourceLimiter {
+				return nil
+			},
+			expect: func(t *testing.T, ok bool) {
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "limiter allows peer back-to-source",
+			limiter: func(ctl *gomock.Controller) BackToSourceLimiter {
+				limiter := mocks.NewMockBackToSourceLimiter(ctl)
+				limiter.EXPECT().Allow(gomock.Any()).Return(true).Times(1)
+				return limiter
+			},
+			expect: func(t *testing.T, ok bool) {
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "limiter rejects peer back-to-source",
+			limiter: func(ctl *gomock.Controller) BackToSourceLimiter {
+				limiter := mocks.NewMockBackToSourceLimiter(ctl)
+				limiter.EXPECT().Allow(gomock.Any()).Return(false).Times(1)
+				return limiter
+			},
+			expect: func(t *testing.T, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+			var options []Option
+			if limiter := tc.limiter(ctl); limiter != nil {
+				options = append(options, WithBackToSourceLimiter(limiter))
+			}
+
+			s := New(mockSchedulerConfig, dynconfig, mockPluginDir, options...).(*scheduling)
+			tc.expect(t, s.allowBackToSource(peer))
+		})
+	}
+}
+
+func TestScheduling_findFullTaskParents(t *testing.T) {
+	tests := []struct {
+		name   string
+		mock   func(peer *resource.Peer, fullPeers []*resource.Peer, mt *resource.MockTaskManagerMockRecorder)
+		expect func(t *testing.T, fullPeers []*resource.Peer, parents []*resource.Peer, ok bool)
+	}{
+		{
+			name: "task is not a range",
+			mock: func(peer *resource.Peer, fullPeers []*resource.Peer, mt *resource.MockTaskManagerMockRecorder) {
+				peer.Task.ParentID = ""
+			},
+			expect: func(t *testing.T, fullPeers []*resource.Peer, parents []*resource.Peer, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "full task does not exist",
+			mock: func(peer *resource.Peer, fullPeers []*resource.Peer, mt *resource.MockTaskManagerMockRecorder) {
+				mt.Load(gomock.Eq(peer.Task.ParentID)).Return(nil, false).Times(1)
+			},
+			expect: func(t *testing.T, fullPeers []*resource.Peer, parents []*resource.Peer, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "find succeeded peers of the full task",
+			mock: func(peer *resource.Peer, fullPeers []*resource.Peer, mt *resource.MockTaskManagerMockRecorder) {
+				fullPeers[0].FSM.SetState(resource.PeerStateSucceeded)
+				fullPeers[1].FSM.SetState(resource.PeerStateRunning)
+				fullPeers[2].FSM.SetState(resource.PeerStateSucceeded)
+				fullPeers[2].Host.Draining.Store(true)
+				mt.Load(gomock.Eq(peer.Task.ParentID)).Return(fullPeers[0].Task, true).Times(1)
+			},
+			expect: func(t *testing.T, fullPeers []*resource.Peer, parents []*resource.Peer, ok bool) {
+				assert := assert.New(t)
+				assert.True(ok)
+				assert.Equal([]*resource.Peer{fullPeers[0]}, parents)
+			},
+		},
+		{
+			name: "full task has no succeeded peers",
+			mock: func(peer *resource.Peer, fullPeers []*resource.Peer, mt *resource.MockTaskManagerMockRecorder) {
+				mt.Load(gomock.Eq(peer.Task.ParentID)).Return(fullPeers[0].Task, true).Times(1)
+			},
+			expect: func(t *testing.T, fullPeers []*resource.Peer, parents []*resource.Peer, ok bool) {
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			dynconfig := configmocks.NewMockDynconfigInterface(ctl)
+			taskManager := resource.NewMockTaskManager(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit, resource.WithParentID(idgen.TaskIDV2(mockTaskURL, "", mockTaskTag, mockTaskApplication, 0, nil)))
+			peer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+			peer.FSM.SetState(resource.PeerStateRunning)
+
+			fullTask := resource.NewTask(mockTask.ParentID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			var fullPeers []*resource.Peer
+			for i := 0; i < 3; i++ {
+				mockHost := resource.NewHost(
+					idgen.HostIDV2("127.0.0.1", uuid.New().String()), mockRawHost.IP, mockRawHost.Hostname,
+					mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+				fullPeer := resource.NewPeer(idgen.PeerIDV1(fmt.Sprintf("127.0.0.%d", i)), mockResourceConfig, fullTask, mockHost)
+				fullTask.StorePeer(fullPeer)
+				fullPeers = append(fullPeers, fullPeer)
+			}
+
+			tc.mock(peer, fullPeers, taskManager.EXPECT())
+			s := New(mockSchedulerConfig, dynconfig, mockPluginDir, WithTaskManager(taskManager)).(*scheduling)
+			parents, ok := s.findFullTaskParents(peer, set.NewSafeSet[string]())
+			tc.expect(t, fullPeers, parents, ok)
+		})
+	}
+}
+
 func TestScheduling_FindSuccessParent(t *testing.T) {
 	tests := []struct {
 		name   string
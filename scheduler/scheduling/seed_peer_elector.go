@@ -0,0 +1,149 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate mockgen -destination mocks/seed_peer_elector_mock.go -source seed_peer_elector.go -package mocks
+
+package scheduling
+
+import (
+	"sort"
+	"time"
+
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// SeedPeerElector is the interface used for electing the well-provisioned regular peers
+// as the temporary seed peers of the hot tasks when the dedicated seed peers are saturated.
+type SeedPeerElector interface {
+	// Serve starts electing temporary seed peers.
+	Serve()
+
+	// Stop stops electing temporary seed peers.
+	Stop()
+}
+
+// seedPeerElector implements SeedPeerElector.
+type seedPeerElector struct {
+	// config is the seed peer election configuration.
+	config *config.SeedPeerElectionConfig
+
+	// taskManager is the task manager of scheduler.
+	taskManager resource.TaskManager
+
+	// done is the channel of stopping elector.
+	done chan struct{}
+}
+
+// NewSeedPeerElector returns a new SeedPeerElector interface.
+func NewSeedPeerElector(cfg *config.SeedPeerElectionConfig, taskManager resource.TaskManager) SeedPeerElector {
+	return &seedPeerElector{
+		config:      cfg,
+		taskManager: taskManager,
+		done:        make(chan struct{}),
+	}
+}
+
+// Serve starts electing temporary seed peers.
+func (e *seedPeerElector) Serve() {
+	tick := time.NewTicker(e.config.Interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			e.taskManager.Range(func(_, value any) bool {
+				task, ok := value.(*resource.Task)
+				if !ok {
+					return true
+				}
+
+				e.elect(task)
+				return true
+			})
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// Stop stops electing temporary seed peers.
+func (e *seedPeerElector) Stop() {
+	close(e.done)
+}
+
+// elect promotes the well-provisioned regular peers of the hot task when the dedicated seed peers
+// are saturated, and demotes the temporary seed peers when demand subsides.
+func (e *seedPeerElector) elect(task *resource.Task) {
+	var (
+		promotedPeers  []*resource.Peer
+		candidatePeers []*resource.Peer
+		seedSaturated  = true
+	)
+	for _, vertex := range task.DAG.GetVertices() {
+		peer := vertex.Value
+		if peer == nil {
+			continue
+		}
+
+		switch {
+		case peer.PromotedSeed.Load():
+			// Temporary seed peer which is no longer available is demoted.
+			if !peer.FSM.Is(resource.PeerStateSucceeded) || peer.Host.Draining.Load() {
+				demote(peer)
+				continue
+			}
+
+			promotedPeers = append(promotedPeers, peer)
+		case peer.Host.Type != types.HostTypeNormal:
+			if peer.Host.FreeUploadCount() > 0 {
+				seedSaturated = false
+			}
+		case peer.FSM.Is(resource.PeerStateSucceeded) && !peer.Host.Draining.Load() && peer.Host.FreeUploadCount() > 0:
+			candidatePeers = append(candidatePeers, peer)
+		}
+	}
+
+	// Demand subsides, demote the temporary seed peers.
+	if task.PeerCount() < e.config.HotTaskPeerCount || !seedSaturated {
+		for _, peer := range promotedPeers {
+			demote(peer)
+		}
+
+		return
+	}
+
+	// Promote the regular peers with the most free upload.
+	sort.Slice(candidatePeers, func(i, j int) bool {
+		return candidatePeers[i].Host.FreeUploadCount() > candidatePeers[j].Host.FreeUploadCount()
+	})
+
+	for _, peer := range candidatePeers {
+		if len(promotedPeers) >= e.config.SeedPeerLimit {
+			return
+		}
+
+		peer.PromotedSeed.Store(true)
+		promotedPeers = append(promotedPeers, peer)
+		peer.Log.Infof("peer is promoted as temporary seed peer, free upload count is %d", peer.Host.FreeUploadCount())
+	}
+}
+
+// demote demotes the temporary seed peer.
+func demote(peer *resource.Peer) {
+	peer.PromotedSeed.Store(false)
+	peer.Log.Info("peer is demoted from temporary seed peer")
+}
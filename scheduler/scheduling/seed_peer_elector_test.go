@@ -0,0 +1,159 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scheduling
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/pkg/idgen"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+var (
+	mockSeedPeerElectionConfig = &config.SeedPeerElectionConfig{
+		Enable:           true,
+		Interval:         time.Minute,
+		HotTaskPeerCount: 3,
+		SeedPeerLimit:    1,
+	}
+)
+
+func TestSeedPeerElector_elect(t *testing.T) {
+	tests := []struct {
+		name   string
+		run    func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer)
+		expect func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer)
+	}{
+		{
+			name: "regular peer is promoted when task is hot and seed peer is saturated",
+			run: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				seedPeer.Host.ConcurrentUploadLimit.Store(0)
+				peers[1].Host.ConcurrentUploadLimit.Store(300)
+			},
+			expect: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				assert := assert.New(t)
+				assert.False(seedPeer.PromotedSeed.Load())
+				assert.False(peers[0].PromotedSeed.Load())
+				assert.True(peers[1].PromotedSeed.Load())
+				assert.False(peers[2].PromotedSeed.Load())
+			},
+		},
+		{
+			name: "regular peer is not promoted when seed peer is not saturated",
+			run: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+			},
+			expect: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				for _, peer := range peers {
+					assert.False(t, peer.PromotedSeed.Load())
+				}
+			},
+		},
+		{
+			name: "regular peer which is not succeeded is not promoted",
+			run: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				seedPeer.Host.ConcurrentUploadLimit.Store(0)
+				for _, peer := range peers {
+					peer.FSM.SetState(resource.PeerStateRunning)
+				}
+			},
+			expect: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				for _, peer := range peers {
+					assert.False(t, peer.PromotedSeed.Load())
+				}
+			},
+		},
+		{
+			name: "promoted peer is demoted when seed peer is not saturated",
+			run: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				peers[0].PromotedSeed.Store(true)
+			},
+			expect: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				assert.False(t, peers[0].PromotedSeed.Load())
+			},
+		},
+		{
+			name: "promoted peer is demoted when host is draining",
+			run: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				seedPeer.Host.ConcurrentUploadLimit.Store(0)
+				peers[0].PromotedSeed.Store(true)
+				peers[0].Host.Draining.Store(true)
+			},
+			expect: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				assert := assert.New(t)
+				assert.False(peers[0].PromotedSeed.Load())
+				assert.Equal(1, countPromotedSeedPeers(peers))
+			},
+		},
+		{
+			name: "promoted peers do not exceed the limit",
+			run: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				seedPeer.Host.ConcurrentUploadLimit.Store(0)
+				peers[2].PromotedSeed.Store(true)
+			},
+			expect: func(t *testing.T, seedPeer *resource.Peer, peers []*resource.Peer) {
+				assert := assert.New(t)
+				assert.True(peers[2].PromotedSeed.Load())
+				assert.Equal(1, countPromotedSeedPeers(peers))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			mockSeedHost := resource.NewHost(
+				mockRawSeedHost.ID, mockRawSeedHost.IP, mockRawSeedHost.Hostname,
+				mockRawSeedHost.Port, mockRawSeedHost.DownloadPort, mockRawSeedHost.Type)
+			seedPeer := resource.NewPeer(mockSeedPeerID, mockResourceConfig, mockTask, mockSeedHost)
+			mockTask.StorePeer(seedPeer)
+
+			var peers []*resource.Peer
+			for i := 0; i < 3; i++ {
+				mockHost := resource.NewHost(
+					idgen.HostIDV2("127.0.0.1", fmt.Sprintf("foo-%d", i)), mockRawHost.IP, mockRawHost.Hostname,
+					mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+				peer := resource.NewPeer(idgen.PeerIDV1(fmt.Sprintf("127.0.0.%d", i)), mockResourceConfig, mockTask, mockHost)
+				peer.FSM.SetState(resource.PeerStateSucceeded)
+				mockTask.StorePeer(peer)
+				peers = append(peers, peer)
+			}
+
+			tc.run(t, seedPeer, peers)
+			e := NewSeedPeerElector(mockSeedPeerElectionConfig, nil).(*seedPeerElector)
+			e.elect(mockTask)
+			tc.expect(t, seedPeer, peers)
+		})
+	}
+}
+
+func countPromotedSeedPeers(peers []*resource.Peer) int {
+	var count int
+	for _, peer := range peers {
+		if peer.PromotedSeed.Load() {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,55 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"d7y.io/dragonfly/v2/scheduler/event"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// publishTaskEvent publishes the task lifecycle event of the task if the event bus is enabled.
+func publishTaskEvent(eventBus event.Bus, typ event.Type, task *resource.Task, message string) {
+	if eventBus == nil {
+		return
+	}
+
+	eventBus.Publish(event.Event{
+		Type:        typ,
+		TaskID:      task.ID,
+		URL:         task.URL,
+		Application: task.Application,
+		Tag:         task.Tag,
+		Message:     message,
+	})
+}
+
+// publishPeerEvent publishes the task lifecycle event of the peer if the event bus is enabled.
+func publishPeerEvent(eventBus event.Bus, typ event.Type, peer *resource.Peer) {
+	if eventBus == nil {
+		return
+	}
+
+	eventBus.Publish(event.Event{
+		Type:        typ,
+		TaskID:      peer.Task.ID,
+		URL:         peer.Task.URL,
+		Application: peer.Task.Application,
+		Tag:         peer.Task.Tag,
+		PeerID:      peer.ID,
+		HostID:      peer.Host.ID,
+	})
+}
@@ -0,0 +1,103 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/scheduler/event"
+	eventmocks "d7y.io/dragonfly/v2/scheduler/event/mocks"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+func TestEvent_publishTaskEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     event.Type
+		message string
+		mock    func(task *resource.Task, mb *eventmocks.MockBusMockRecorder)
+	}{
+		{
+			name: "publish task created event",
+			typ:  event.TypeTaskCreated,
+			mock: func(task *resource.Task, mb *eventmocks.MockBusMockRecorder) {
+				mb.Publish(event.Event{
+					Type:        event.TypeTaskCreated,
+					TaskID:      task.ID,
+					URL:         task.URL,
+					Application: task.Application,
+					Tag:         task.Tag,
+				}).Times(1)
+			},
+		},
+		{
+			name:    "publish task failed event",
+			typ:     event.TypeTaskFailed,
+			message: "foo",
+			mock: func(task *resource.Task, mb *eventmocks.MockBusMockRecorder) {
+				mb.Publish(event.Event{
+					Type:        event.TypeTaskFailed,
+					TaskID:      task.ID,
+					URL:         task.URL,
+					Application: task.Application,
+					Tag:         task.Tag,
+					Message:     "foo",
+				}).Times(1)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			eventBus := eventmocks.NewMockBus(ctl)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+
+			tc.mock(mockTask, eventBus.EXPECT())
+			publishTaskEvent(eventBus, tc.typ, mockTask, tc.message)
+			publishTaskEvent(nil, tc.typ, mockTask, tc.message)
+		})
+	}
+}
+
+func TestEvent_publishPeerEvent(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	eventBus := eventmocks.NewMockBus(ctl)
+	mockHost := resource.NewHost(
+		mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+		mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+	mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+	mockPeer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+	eventBus.EXPECT().Publish(event.Event{
+		Type:        event.TypePeerJoined,
+		TaskID:      mockTask.ID,
+		URL:         mockTask.URL,
+		Application: mockTask.Application,
+		Tag:         mockTask.Tag,
+		PeerID:      mockPeer.ID,
+		HostID:      mockHost.ID,
+	}).Times(1)
+	publishPeerEvent(eventBus, event.TypePeerJoined, mockPeer)
+	publishPeerEvent(nil, event.TypePeerJoined, mockPeer)
+}
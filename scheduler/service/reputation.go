@@ -0,0 +1,83 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"time"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/metrics"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+const (
+	// blocklistReasonCorruption is the blocklist reason of the parent serving corrupted pieces.
+	blocklistReasonCorruption = "corruption"
+
+	// blocklistReasonFailure is the blocklist reason of the parent whose reputation score is too low.
+	blocklistReasonFailure = "failure"
+)
+
+// handleParentReports updates the reputation of the parents reported by the announcing host,
+// and blocklists the parents serving corrupted pieces or scored below the threshold.
+func handleParentReports(ctx context.Context, cfg config.ReputationConfig, peerManager resource.PeerManager) {
+	for _, report := range rpc.ParentReportsFromIncomingContext(ctx) {
+		if report.Pieces == 0 {
+			continue
+		}
+
+		parent, loaded := peerManager.Load(report.PeerID)
+		if !loaded {
+			continue
+		}
+
+		failures := report.Failures
+		if failures > report.Pieces {
+			failures = report.Pieces
+		}
+
+		metrics.ParentReportPieceCount.WithLabelValues("success").Add(float64(report.Pieces - failures))
+		metrics.ParentReportPieceCount.WithLabelValues("failure").Add(float64(failures))
+		metrics.ParentReportPieceCount.WithLabelValues("corruption").Add(float64(report.Corruptions))
+
+		host := parent.Host
+		host.Reputation.Report(report.Pieces, failures, report.Corruptions)
+		score := host.Reputation.Score()
+		metrics.HostReputation.WithLabelValues(host.ID, host.IP, host.Hostname).Set(score)
+
+		if host.Reputation.IsBlocked() {
+			continue
+		}
+
+		var reason string
+		switch {
+		case report.Corruptions > 0:
+			reason = blocklistReasonCorruption
+		case score < cfg.Threshold:
+			reason = blocklistReasonFailure
+		default:
+			continue
+		}
+
+		host.Reputation.Block(time.Now().Add(cfg.BlockDuration))
+		metrics.BlocklistHostCount.WithLabelValues(reason, host.Type.Name()).Inc()
+		logger.WithHostID(host.ID).Warnf("host is blocklisted for %s by %s, reputation score is %.2f", cfg.BlockDuration, reason, score)
+	}
+}
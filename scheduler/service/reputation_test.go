@@ -0,0 +1,141 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	"d7y.io/dragonfly/v2/pkg/rpc"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+func TestReputation_handleParentReports(t *testing.T) {
+	cfg := config.ReputationConfig{
+		Enable:        true,
+		Threshold:     0.5,
+		BlockDuration: time.Minute,
+	}
+
+	tests := []struct {
+		name    string
+		reports []rpc.ParentReport
+		mock    func(peer *resource.Peer, mp *resource.MockPeerManagerMockRecorder)
+		expect  func(t *testing.T, peer *resource.Peer)
+	}{
+		{
+			name: "parent succeeds",
+			reports: []rpc.ParentReport{
+				{PeerID: mockPeerID, Pieces: 10},
+			},
+			mock: func(peer *resource.Peer, mp *resource.MockPeerManagerMockRecorder) {
+				mp.Load(gomock.Eq(mockPeerID)).Return(peer, true).Times(1)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), peer.Host.Reputation.Score())
+				assert.False(peer.Host.Reputation.IsBlocked())
+			},
+		},
+		{
+			name: "parent serves corrupted pieces",
+			reports: []rpc.ParentReport{
+				{PeerID: mockPeerID, Pieces: 10, Failures: 1, Corruptions: 1},
+			},
+			mock: func(peer *resource.Peer, mp *resource.MockPeerManagerMockRecorder) {
+				mp.Load(gomock.Eq(mockPeerID)).Return(peer, true).Times(1)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				pieces, failures, corruptions := peer.Host.Reputation.Counts()
+				assert.Equal(uint64(10), pieces)
+				assert.Equal(uint64(1), failures)
+				assert.Equal(uint64(1), corruptions)
+				assert.True(peer.Host.Reputation.IsBlocked())
+			},
+		},
+		{
+			name: "parent score is below threshold",
+			reports: []rpc.ParentReport{
+				{PeerID: mockPeerID, Pieces: 10, Failures: 10},
+				{PeerID: mockPeerID, Pieces: 10, Failures: 10},
+			},
+			mock: func(peer *resource.Peer, mp *resource.MockPeerManagerMockRecorder) {
+				mp.Load(gomock.Eq(mockPeerID)).Return(peer, true).Times(2)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.Less(peer.Host.Reputation.Score(), cfg.Threshold)
+				assert.True(peer.Host.Reputation.IsBlocked())
+			},
+		},
+		{
+			name: "parent can not be found",
+			reports: []rpc.ParentReport{
+				{PeerID: mockPeerID, Pieces: 10, Corruptions: 1},
+			},
+			mock: func(peer *resource.Peer, mp *resource.MockPeerManagerMockRecorder) {
+				mp.Load(gomock.Eq(mockPeerID)).Return(nil, false).Times(1)
+			},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.Equal(float64(1), peer.Host.Reputation.Score())
+				assert.False(peer.Host.Reputation.IsBlocked())
+			},
+		},
+		{
+			name: "report has no pieces",
+			reports: []rpc.ParentReport{
+				{PeerID: mockPeerID},
+			},
+			mock: func(peer *resource.Peer, mp *resource.MockPeerManagerMockRecorder) {},
+			expect: func(t *testing.T, peer *resource.Peer) {
+				assert := assert.New(t)
+				assert.False(peer.Host.Reputation.IsBlocked())
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			peerManager := resource.NewMockPeerManager(ctl)
+			mockHost := resource.NewHost(
+				mockRawHost.ID, mockRawHost.IP, mockRawHost.Hostname,
+				mockRawHost.Port, mockRawHost.DownloadPort, mockRawHost.Type)
+			mockTask := resource.NewTask(mockTaskID, mockTaskURL, mockTaskTag, mockTaskApplication, commonv2.TaskType_DFDAEMON, mockTaskFilters, mockTaskHeader, mockTaskBackToSourceLimit)
+			mockPeer := resource.NewPeer(mockPeerID, mockResourceConfig, mockTask, mockHost)
+
+			// Convert the outgoing context of the daemon to the incoming context of the scheduler.
+			md, _ := metadata.FromOutgoingContext(rpc.ContextWithParentReports(context.Background(), tc.reports))
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			tc.mock(mockPeer, peerManager.EXPECT())
+			handleParentReports(ctx, cfg, peerManager)
+			tc.expect(t, mockPeer)
+		})
+	}
+}
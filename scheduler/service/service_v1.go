@@ -40,9 +40,11 @@ import (
 	"d7y.io/dragonfly/v2/pkg/digest"
 	"d7y.io/dragonfly/v2/pkg/idgen"
 	"d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	"d7y.io/dragonfly/v2/pkg/rpc/common"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/event"
 	"d7y.io/dragonfly/v2/scheduler/metrics"
 	"d7y.io/dragonfly/v2/scheduler/networktopology"
 	"d7y.io/dragonfly/v2/scheduler/resource"
@@ -69,6 +71,9 @@ type V1 struct {
 
 	// Network topology interface.
 	networkTopology networktopology.NetworkTopology
+
+	// Event bus interface.
+	eventBus event.Bus
 }
 
 // New v1 version of service instance.
@@ -79,6 +84,7 @@ func NewV1(
 	dynconfig config.DynconfigInterface,
 	storage storage.Storage,
 	networktopology networktopology.NetworkTopology,
+	eventBus event.Bus,
 ) *V1 {
 	return &V1{
 		resource:        resource,
@@ -87,12 +93,23 @@ func NewV1(
 		dynconfig:       dynconfig,
 		storage:         storage,
 		networkTopology: networktopology,
+		eventBus:        eventBus,
 	}
 }
 
 // RegisterPeerTask registers peer and triggers seed peer download task.
 func (v *V1) RegisterPeerTask(ctx context.Context, req *schedulerv1.PeerTaskRequest) (*schedulerv1.RegisterResult, error) {
-	logger.WithPeer(req.PeerHost.GetId(), req.GetTaskId(), req.GetPeerId()).Infof("register peer task request: %#v", req)
+	logger.WithPeer(req.PeerHost.GetId(), req.GetTaskId(), req.GetPeerId()).WithContext(ctx).Infof("register peer task request: %#v", req)
+
+	// Reject the task if the url is not allowed by the application policy.
+	if err := resource.CheckApplicationURL(v.dynconfig, req.UrlMeta.GetApplication(), req.GetUrl()); err != nil {
+		return nil, dferrors.New(commonv1.Code_SchedForbidden, err.Error())
+	}
+
+	// Reject the task if the url is denied by the url policy of the cluster.
+	if err := checkURLPolicy(v.dynconfig, req.PeerHost.GetId(), req.GetTaskId(), req.GetPeerId(), req.UrlMeta.GetApplication(), req.GetUrl()); err != nil {
+		return nil, dferrors.New(commonv1.Code_SchedForbidden, err.Error())
+	}
 
 	// Store resource.
 	task := v.storeTask(ctx, req, commonv2.TaskType_DFDAEMON)
@@ -355,6 +372,13 @@ func (v *V1) AnnounceTask(ctx context.Context, req *schedulerv1.AnnounceTaskRequ
 	host := v.storeHost(ctx, req.GetPeerHost())
 	peer := v.storePeer(ctx, peerID, req.UrlMeta.GetPriority(), req.UrlMeta.GetRange(), task, host)
 
+	// The task imported with replication policy is a persistent cache task,
+	// the replicas of it are maintained by scheduler.
+	if replication, ok := rpc.ReplicationFromIncomingContext(ctx); ok {
+		task.Replication.Store(&replication)
+		task.Log.Infof("task is persistent cache task with %d replicas spread by %s", replication.Replicas, replication.SpreadBy)
+	}
+
 	// If the task state is not TaskStateSucceeded,
 	// advance the task state to TaskStateSucceeded.
 	if !task.FSM.Is(resource.TaskStateSucceeded) {
@@ -379,7 +403,11 @@ func (v *V1) AnnounceTask(ctx context.Context, req *schedulerv1.AnnounceTaskRequ
 			}
 
 			if len(pieceInfo.PieceMd5) > 0 {
-				piece.Digest = digest.New(digest.AlgorithmMD5, pieceInfo.PieceMd5)
+				if d, err := digest.ParsePieceDigest(pieceInfo.PieceMd5); err != nil {
+					peer.Log.Errorf("parse piece digest failed: %s", err.Error())
+				} else {
+					piece.Digest = d
+				}
 			}
 
 			peer.StorePiece(piece)
@@ -432,7 +460,7 @@ func (v *V1) StatTask(ctx context.Context, req *schedulerv1.StatTaskRequest) (*s
 		return nil, dferrors.New(commonv1.Code_PeerTaskNotFound, msg)
 	}
 
-	return &schedulerv1.Task{
+	pbTask := &schedulerv1.Task{
 		Id:               task.ID,
 		Type:             types.TaskTypeV2ToV1(task.Type),
 		ContentLength:    task.ContentLength.Load(),
@@ -440,6 +468,64 @@ func (v *V1) StatTask(ctx context.Context, req *schedulerv1.StatTaskRequest) (*s
 		State:            task.FSM.Current(),
 		PeerCount:        int32(task.PeerCount()),
 		HasAvailablePeer: task.HasAvailablePeer(set.NewSafeSet[string]()),
+	}
+
+	return pbTask, nil
+}
+
+// FindTaskPeers returns the peers holding the task to the sibling scheduler,
+// and the sibling scheduler schedules them as the parents before falling back to origin.
+func (v *V1) FindTaskPeers(ctx context.Context, req *schedulerv1.StatTaskRequest) (*schedulerv1.PeerPacket, error) {
+	logger.WithTaskID(req.GetTaskId()).Debugf("find task peers request: %#v", req)
+
+	task, loaded := v.resource.TaskManager().Load(req.GetTaskId())
+	if !loaded {
+		msg := fmt.Sprintf("task %s not found", req.GetTaskId())
+		logger.Debug(msg)
+		return nil, dferrors.New(commonv1.Code_PeerTaskNotFound, msg)
+	}
+
+	return &schedulerv1.PeerPacket{
+		TaskId:         task.ID,
+		CandidatePeers: scheduling.ConstructDestPeers(v.federatedPeers(task)),
+		Code:           commonv1.Code_Success,
+	}, nil
+}
+
+// federatedPeers returns the succeeded peers of the task which can serve the peers of the sibling cluster.
+func (v *V1) federatedPeers(task *resource.Task) []*resource.Peer {
+	var peers []*resource.Peer
+	for _, peer := range task.LoadRandomPeers(uint(config.DefaultSchedulerFilterParentLimit)) {
+		if !peer.FSM.Is(resource.PeerStateSucceeded) || peer.Host.Draining.Load() || peer.Host.Reputation.IsBlocked() || peer.Host.FreeUploadCount() <= 0 {
+			continue
+		}
+
+		peers = append(peers, peer)
+		if len(peers) >= config.DefaultSchedulerCandidateParentLimit {
+			break
+		}
+	}
+
+	return peers
+}
+
+// ListSiblingPeers returns the sibling peers downloading the same task as the peer,
+// the peer gossips piece availability with the siblings directly.
+func (v *V1) ListSiblingPeers(ctx context.Context, req *schedulerv1.PeerTarget) (*schedulerv1.PeerPacket, error) {
+	logger.WithTaskAndPeerID(req.GetTaskId(), req.GetPeerId()).Debugf("list sibling peers request: %#v", req)
+
+	peer, loaded := v.resource.PeerManager().Load(req.GetPeerId())
+	if !loaded {
+		msg := fmt.Sprintf("peer %s not found", req.GetPeerId())
+		logger.Error(msg)
+		return nil, dferrors.New(commonv1.Code_SchedPeerNotFound, msg)
+	}
+
+	return &schedulerv1.PeerPacket{
+		TaskId:         peer.Task.ID,
+		SrcPid:         peer.ID,
+		CandidatePeers: scheduling.ConstructDestPeers(v.scheduling.FindSiblingPeers(peer)),
+		Code:           commonv1.Code_Success,
 	}, nil
 }
 
@@ -471,6 +557,11 @@ func (v *V1) AnnounceHost(ctx context.Context, req *schedulerv1.AnnounceHostRequ
 		concurrentUploadLimit = int32(clientConfig.LoadLimit)
 	}
 
+	// Update the reputation of the parents reported by host.
+	if v.config.Scheduler.Reputation.Enable {
+		handleParentReports(ctx, v.config.Scheduler.Reputation, v.resource.PeerManager())
+	}
+
 	host, loaded := v.resource.HostManager().Load(req.GetId())
 	if !loaded {
 		options := []resource.HostOption{
@@ -556,6 +647,14 @@ func (v *V1) AnnounceHost(ctx context.Context, req *schedulerv1.AnnounceHostRequ
 			options = append(options, resource.WithObjectStoragePort(req.GetObjectStoragePort()))
 		}
 
+		if topology := rpc.TopologyFromIncomingContext(ctx); topology != (types.Topology{}) {
+			options = append(options, resource.WithTopology(topology))
+		}
+
+		if limit, ok := rpc.UploadRateLimitFromIncomingContext(ctx); ok {
+			options = append(options, resource.WithUploadRateLimit(limit))
+		}
+
 		host = resource.NewHost(
 			req.GetId(), req.GetIp(), req.GetHostname(), req.GetPort(), req.GetDownloadPort(),
 			types.ParseHostType(req.GetType()), options...,
@@ -575,8 +674,16 @@ func (v *V1) AnnounceHost(ctx context.Context, req *schedulerv1.AnnounceHostRequ
 	host.PlatformFamily = req.GetPlatformFamily()
 	host.PlatformVersion = req.GetPlatformVersion()
 	host.KernelVersion = req.GetKernelVersion()
+	host.Topology = rpc.TopologyFromIncomingContext(ctx)
 	host.UpdatedAt.Store(time.Now())
 
+	// The upload rate limit of host is changed by the bandwidth policies and the host load.
+	if limit, ok := rpc.UploadRateLimitFromIncomingContext(ctx); ok {
+		host.UploadThroughput.SetCapacity(float64(limit))
+	} else {
+		host.UploadThroughput.SetCapacity(0)
+	}
+
 	if concurrentUploadLimit > 0 {
 		host.ConcurrentUploadLimit.Store(concurrentUploadLimit)
 	}
@@ -793,6 +900,15 @@ func (v *V1) triggerTask(ctx context.Context, req *schedulerv1.PeerTaskRequest,
 		return nil
 	}
 
+	// If the full task of the range has succeeded peer, the range is served by
+	// the peers of the full task instead of triggering the origin.
+	if task.ParentID != "" {
+		if parent, loaded := v.resource.TaskManager().Load(task.ParentID); loaded && parent.HasSucceededPeer(blocklist) {
+			peer.Log.Infof("peer does not need to trigger, because of the full task %s has succeeded peer", task.ParentID)
+			return nil
+		}
+	}
+
 	// The first download is triggered according to
 	// the different priorities of the peer and
 	// priority of the RegisterPeerTask parameter is
@@ -871,10 +987,16 @@ func (v *V1) storeTask(ctx context.Context, req *schedulerv1.PeerTaskRequest, ty
 			options = append(options, resource.WithDigest(d))
 		}
 
+		// The task downloads a range of the full task.
+		if req.UrlMeta.GetRange() != "" {
+			options = append(options, resource.WithParentID(idgen.ParentTaskIDV1(req.GetUrl(), req.UrlMeta)))
+		}
+
 		task := resource.NewTask(req.GetTaskId(), req.GetUrl(), req.UrlMeta.GetTag(), req.UrlMeta.GetApplication(),
 			typ, filters, req.UrlMeta.GetHeader(), int32(v.config.Scheduler.BackToSourceCount), options...)
 		v.resource.TaskManager().Store(task)
 		task.Log.Info("create new task")
+		publishTaskEvent(v.eventBus, event.TypeTaskCreated, task, "")
 		return task
 	}
 
@@ -922,8 +1044,17 @@ func (v *V1) storeHost(ctx context.Context, peerHost *schedulerv1.PeerHost) *res
 // storePeer stores a new peer or reuses a previous peer.
 func (v *V1) storePeer(ctx context.Context, id string, priority commonv1.Priority, rg string, task *resource.Task, host *resource.Host) *resource.Peer {
 	peer, loaded := v.resource.PeerManager().Load(id)
+
+	// The running peer registers again when dfdaemon restarts and resumes the unfinished task,
+	// reset the peer and the resumed pieces will be reported by dfdaemon again.
+	if loaded && peer.FSM.Is(resource.PeerStateRunning) && peer.Host.ID == host.ID {
+		peer.Log.Info("peer registers again, reset it to resume")
+		v.resource.PeerManager().Delete(id)
+		loaded = false
+	}
+
 	if !loaded {
-		options := []resource.PeerOption{}
+		options := []resource.PeerOption{resource.WithQueuePriority(rpc.QueuePriorityFromIncomingContext(ctx))}
 		if priority != commonv1.Priority_LEVEL0 {
 			options = append(options, resource.WithPriority(types.PriorityV1ToV2(priority)))
 		}
@@ -938,7 +1069,9 @@ func (v *V1) storePeer(ctx context.Context, id string, priority commonv1.Priorit
 
 		peer := resource.NewPeer(id, &v.config.Resource, task, host, options...)
 		v.resource.PeerManager().Store(peer)
-		peer.Log.Info("create new peer")
+		task.RaiseQueuePriority(peer.QueuePriority)
+		peer.Log.Infof("create new peer with queue priority %d", peer.QueuePriority)
+		publishPeerEvent(v.eventBus, event.TypePeerJoined, peer)
 		return peer
 	}
 
@@ -1023,7 +1156,7 @@ func (v *V1) registerSmallTask(ctx context.Context, peer *resource.Peer) (*sched
 	}
 
 	if piece.Digest != nil {
-		pieceInfo.PieceMd5 = piece.Digest.Encoded
+		pieceInfo.PieceMd5 = digest.FormatPieceDigest(piece.Digest.Algorithm, piece.Digest.Encoded)
 	}
 
 	return &schedulerv1.RegisterResult{
@@ -1121,7 +1254,11 @@ func (v *V1) handlePieceSuccess(ctx context.Context, peer *resource.Peer, pieceR
 	}
 
 	if len(pieceResult.PieceInfo.PieceMd5) > 0 {
-		piece.Digest = digest.New(digest.AlgorithmMD5, pieceResult.PieceInfo.PieceMd5)
+		if d, err := digest.ParsePieceDigest(pieceResult.PieceInfo.PieceMd5); err != nil {
+			peer.Log.Errorf("parse piece digest failed: %s", err.Error())
+		} else {
+			piece.Digest = d
+		}
 	}
 
 	peer.StorePiece(piece)
@@ -1141,6 +1278,7 @@ func (v *V1) handlePieceSuccess(ctx context.Context, peer *resource.Peer, pieceR
 		if destPeer, loaded := v.resource.PeerManager().Load(pieceResult.DstPid); loaded {
 			destPeer.UpdatedAt.Store(time.Now())
 			destPeer.Host.UpdatedAt.Store(time.Now())
+			destPeer.Host.UploadThroughput.Observe(piece.Length)
 		}
 	}
 
@@ -1357,6 +1495,15 @@ func (v *V1) handleTaskFailure(ctx context.Context, task *resource.Task, backToS
 		task.Log.Errorf("task fsm event failed: %s", err.Error())
 		return
 	}
+
+	var message string
+	if backToSourceErr != nil {
+		message = backToSourceErr.GetMetadata().GetStatus()
+	} else if seedPeerErr != nil {
+		message = seedPeerErr.Error()
+	}
+
+	publishTaskEvent(v.eventBus, event.TypeTaskFailed, task, message)
 }
 
 // createDownloadRecord stores peer download records.
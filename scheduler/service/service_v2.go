@@ -34,8 +34,10 @@ import (
 	"d7y.io/dragonfly/v2/pkg/container/set"
 	"d7y.io/dragonfly/v2/pkg/digest"
 	"d7y.io/dragonfly/v2/pkg/net/http"
+	"d7y.io/dragonfly/v2/pkg/rpc"
 	"d7y.io/dragonfly/v2/pkg/types"
 	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/event"
 	"d7y.io/dragonfly/v2/scheduler/metrics"
 	"d7y.io/dragonfly/v2/scheduler/networktopology"
 	"d7y.io/dragonfly/v2/scheduler/resource"
@@ -62,6 +64,9 @@ type V2 struct {
 
 	// Network topology interface.
 	networkTopology networktopology.NetworkTopology
+
+	// Event bus interface.
+	eventBus event.Bus
 }
 
 // New v2 version of service instance.
@@ -72,6 +77,7 @@ func NewV2(
 	dynconfig config.DynconfigInterface,
 	storage storage.Storage,
 	networkTopology networktopology.NetworkTopology,
+	eventBus event.Bus,
 ) *V2 {
 	return &V2{
 		resource:        resource,
@@ -80,6 +86,7 @@ func NewV2(
 		dynconfig:       dynconfig,
 		storage:         storage,
 		networkTopology: networkTopology,
+		eventBus:        eventBus,
 	}
 }
 
@@ -461,6 +468,11 @@ func (v *V2) AnnounceHost(ctx context.Context, req *schedulerv2.AnnounceHostRequ
 		concurrentUploadLimit = int32(clientConfig.LoadLimit)
 	}
 
+	// Update the reputation of the parents reported by host.
+	if v.config.Scheduler.Reputation.Enable {
+		handleParentReports(ctx, v.config.Scheduler.Reputation, v.resource.PeerManager())
+	}
+
 	host, loaded := v.resource.HostManager().Load(req.Host.GetId())
 	if !loaded {
 		options := []resource.HostOption{
@@ -546,6 +558,14 @@ func (v *V2) AnnounceHost(ctx context.Context, req *schedulerv2.AnnounceHostRequ
 			options = append(options, resource.WithObjectStoragePort(req.Host.GetObjectStoragePort()))
 		}
 
+		if topology := rpc.TopologyFromIncomingContext(ctx); topology != (types.Topology{}) {
+			options = append(options, resource.WithTopology(topology))
+		}
+
+		if limit, ok := rpc.UploadRateLimitFromIncomingContext(ctx); ok {
+			options = append(options, resource.WithUploadRateLimit(limit))
+		}
+
 		host = resource.NewHost(
 			req.Host.GetId(), req.Host.GetIp(), req.Host.GetHostname(),
 			req.Host.GetPort(), req.Host.GetDownloadPort(), types.HostType(req.Host.GetType()),
@@ -566,8 +586,16 @@ func (v *V2) AnnounceHost(ctx context.Context, req *schedulerv2.AnnounceHostRequ
 	host.PlatformFamily = req.Host.GetPlatformFamily()
 	host.PlatformVersion = req.Host.GetPlatformVersion()
 	host.KernelVersion = req.Host.GetKernelVersion()
+	host.Topology = rpc.TopologyFromIncomingContext(ctx)
 	host.UpdatedAt.Store(time.Now())
 
+	// The upload rate limit of host is changed by the bandwidth policies and the host load.
+	if limit, ok := rpc.UploadRateLimitFromIncomingContext(ctx); ok {
+		host.UploadThroughput.SetCapacity(float64(limit))
+	} else {
+		host.UploadThroughput.SetCapacity(0)
+	}
+
 	if concurrentUploadLimit > 0 {
 		host.ConcurrentUploadLimit.Store(concurrentUploadLimit)
 	}
@@ -1068,6 +1096,7 @@ func (v *V2) handleDownloadPeerBackToSourceFailedRequest(ctx context.Context, pe
 	if err := peer.Task.FSM.Event(ctx, resource.TaskEventDownloadFailed); err != nil {
 		return status.Error(codes.Internal, err.Error())
 	}
+	publishTaskEvent(v.eventBus, event.TypeTaskFailed, peer.Task, fmt.Sprintf("peer %s back-to-source failed", peer.ID))
 
 	// Collect DownloadPeerCount and DownloadPeerBackToSourceFailureCount metrics.
 	priority := peer.CalculatePriority(v.dynconfig)
@@ -1120,6 +1149,7 @@ func (v *V2) handleDownloadPieceFinishedRequest(ctx context.Context, peerID stri
 	if loadedParent {
 		parent.UpdatedAt.Store(time.Now())
 		parent.Host.UpdatedAt.Store(time.Now())
+		parent.Host.UploadThroughput.Observe(piece.Length)
 	}
 
 	// Handle task with piece finished request.
@@ -1263,6 +1293,16 @@ func (v *V2) handleResource(ctx context.Context, stream schedulerv2.Scheduler_An
 		return nil, nil, nil, status.Errorf(codes.NotFound, "host %s not found", hostID)
 	}
 
+	// Reject the task if the url is not allowed by the application policy.
+	if err := resource.CheckApplicationURL(v.dynconfig, download.GetApplication(), download.GetUrl()); err != nil {
+		return nil, nil, nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	// Reject the task if the url is denied by the url policy of the cluster.
+	if err := checkURLPolicy(v.dynconfig, hostID, taskID, peerID, download.GetApplication(), download.GetUrl()); err != nil {
+		return nil, nil, nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
 	// Store new task or update task.
 	task, loaded := v.resource.TaskManager().Load(taskID)
 	if !loaded {
@@ -1280,6 +1320,7 @@ func (v *V2) handleResource(ctx context.Context, stream schedulerv2.Scheduler_An
 		task = resource.NewTask(taskID, download.GetUrl(), download.GetTag(), download.GetApplication(), download.GetType(),
 			download.GetFilters(), download.GetHeader(), int32(v.config.Scheduler.BackToSourceCount), options...)
 		v.resource.TaskManager().Store(task)
+		publishTaskEvent(v.eventBus, event.TypeTaskCreated, task, "")
 	} else {
 		task.URL = download.GetUrl()
 		task.Filters = download.GetFilters()
@@ -1289,13 +1330,19 @@ func (v *V2) handleResource(ctx context.Context, stream schedulerv2.Scheduler_An
 	// Store new peer or load peer.
 	peer, loaded := v.resource.PeerManager().Load(peerID)
 	if !loaded {
-		options := []resource.PeerOption{resource.WithPriority(download.GetPriority()), resource.WithAnnouncePeerStream(stream)}
+		options := []resource.PeerOption{
+			resource.WithPriority(download.GetPriority()),
+			resource.WithQueuePriority(rpc.QueuePriorityFromIncomingContext(ctx)),
+			resource.WithAnnouncePeerStream(stream),
+		}
 		if download.Range != nil {
 			options = append(options, resource.WithRange(http.Range{Start: download.Range.GetStart(), Length: download.Range.GetLength()}))
 		}
 
 		peer = resource.NewPeer(peerID, &v.config.Resource, task, host, options...)
 		v.resource.PeerManager().Store(peer)
+		task.RaiseQueuePriority(peer.QueuePriority)
+		publishPeerEvent(v.eventBus, event.TypePeerJoined, peer)
 	}
 
 	return host, task, peer, nil
@@ -0,0 +1,37 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/metrics"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+)
+
+// checkURLPolicy returns an error if the url of the task is denied by the url policy,
+// and the denial is recorded in the audit log.
+func checkURLPolicy(dynconfig config.DynconfigInterface, hostID, taskID, peerID, application, url string) error {
+	if err := resource.CheckURLPolicy(dynconfig, url); err != nil {
+		metrics.URLPolicyDeniedCount.WithLabelValues(application).Inc()
+		logger.AuditLogger.Infow("task is denied by url policy", "url", url, "application", application,
+			"task", taskID, "peer", peerID, "host", hostID, "reason", err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/montanaflynn/stats"
+
+	commonv2 "d7y.io/api/v2/pkg/apis/common/v2"
+
+	logger "d7y.io/dragonfly/v2/internal/dflog"
+	managertypes "d7y.io/dragonfly/v2/manager/types"
+	"d7y.io/dragonfly/v2/pkg/container/set"
+	"d7y.io/dragonfly/v2/pkg/types"
+	"d7y.io/dragonfly/v2/scheduler/config"
+	"d7y.io/dragonfly/v2/scheduler/resource"
+	"d7y.io/dragonfly/v2/scheduler/scheduling"
+)
+
+// Simulator is the interface used for replaying the synthetic workload offline.
+type Simulator interface {
+	// Run replays the scheduling decisions of the workload and returns the report.
+	Run(context.Context) (*Report, error)
+}
+
+// Report is the metrics of the simulation, operators compare the reports
+// of different evaluator configurations before rollout.
+type Report struct {
+	// Algorithm is the scheduling algorithm.
+	Algorithm string `json:"algorithm"`
+
+	// Peers is the count of simulated peers, seed peers are excluded.
+	Peers int `json:"peers"`
+
+	// BackToSourcePeers is the count of peers downloading back-to-source.
+	BackToSourcePeers int `json:"backToSourcePeers"`
+
+	// BackToSourceRatio is the ratio of peers downloading back-to-source.
+	BackToSourceRatio float64 `json:"backToSourceRatio"`
+
+	// AverageCandidateParents is the average count of candidate parents per scheduling.
+	AverageCandidateParents float64 `json:"averageCandidateParents"`
+
+	// SameRegionRatio is the ratio of scheduled parents in the same region.
+	SameRegionRatio float64 `json:"sameRegionRatio"`
+
+	// SameZoneRatio is the ratio of scheduled parents in the same zone.
+	SameZoneRatio float64 `json:"sameZoneRatio"`
+
+	// SameRackRatio is the ratio of scheduled parents in the same rack.
+	SameRackRatio float64 `json:"sameRackRatio"`
+
+	// AverageDownloadSeconds is the average simulated download duration.
+	AverageDownloadSeconds float64 `json:"averageDownloadSeconds"`
+
+	// P95DownloadSeconds is the p95 of simulated download duration.
+	P95DownloadSeconds float64 `json:"p95DownloadSeconds"`
+
+	// SchedulingLatencyP50Milliseconds is the p50 of scheduling latency.
+	SchedulingLatencyP50Milliseconds float64 `json:"schedulingLatencyP50Milliseconds"`
+
+	// SchedulingLatencyP99Milliseconds is the p99 of scheduling latency.
+	SchedulingLatencyP99Milliseconds float64 `json:"schedulingLatencyP99Milliseconds"`
+
+	// MaxConcurrentUploadCount is the max concurrent upload count of the parent hosts.
+	MaxConcurrentUploadCount int32 `json:"maxConcurrentUploadCount"`
+
+	// SimulatedSeconds is the simulated duration of the workload.
+	SimulatedSeconds float64 `json:"simulatedSeconds"`
+}
+
+// simulator implements Simulator.
+type simulator struct {
+	// config is the scheduler configuration.
+	config *config.Config
+
+	// workload is the synthetic workload.
+	workload *Workload
+
+	// scheduling is the scheduling under simulation.
+	scheduling scheduling.Scheduling
+}
+
+// New returns a new Simulator interface, the options are used to build the scheduling under simulation.
+func New(cfg *config.Config, workload *Workload, pluginDir string, options ...scheduling.Option) Simulator {
+	return &simulator{
+		config:     cfg,
+		workload:   workload,
+		scheduling: scheduling.New(&cfg.Scheduler, &dynconfig{}, pluginDir, options...),
+	}
+}
+
+// dynconfig returns the default scheduler cluster config, because the simulation runs without manager.
+type dynconfig struct {
+	config.DynconfigInterface
+}
+
+// GetSchedulerClusterConfig returns the default scheduler cluster config.
+func (d *dynconfig) GetSchedulerClusterConfig() (managertypes.SchedulerClusterConfig, error) {
+	return managertypes.SchedulerClusterConfig{}, nil
+}
+
+// GetSchedulerClusterClientConfig returns the default client config.
+func (d *dynconfig) GetSchedulerClusterClientConfig() (managertypes.SchedulerClusterClientConfig, error) {
+	return managertypes.SchedulerClusterClientConfig{}, nil
+}
+
+// eventType is the type of simulation event.
+type eventType int
+
+const (
+	// eventArrive is the event of peer arriving and being scheduled.
+	eventArrive eventType = iota
+
+	// eventFinish is the event of peer finishing downloading.
+	eventFinish
+)
+
+// event is the simulation event at the virtual time.
+type event struct {
+	at   time.Duration
+	typ  eventType
+	peer *resource.Peer
+}
+
+// eventQueue is the min heap of events ordered by the virtual time.
+type eventQueue []*event
+
+func (q eventQueue) Len() int           { return len(q) }
+func (q eventQueue) Less(i, j int) bool { return q[i].at < q[j].at }
+func (q eventQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x any)        { *q = append(*q, x.(*event)) }
+func (q *eventQueue) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	*q = old[:n-1]
+	return e
+}
+
+// recorder records the metrics of simulation.
+type recorder struct {
+	peers             int
+	backToSourcePeers int
+	scheduled         int
+	candidateParents  int
+	sameRegion        int
+	sameZone          int
+	sameRack          int
+	downloadSeconds   []float64
+	latencies         []float64
+	maxUploadCount    int32
+	now               time.Duration
+}
+
+// Run replays the scheduling decisions of the workload and returns the report.
+func (s *simulator) Run(ctx context.Context) (*Report, error) {
+	r := rand.New(rand.NewSource(s.workload.Seed))
+	hosts, seedPeerHosts := s.hosts()
+
+	queue := &eventQueue{}
+	for i, taskWorkload := range s.workload.Tasks {
+		for j := 0; j < taskWorkload.Count; j++ {
+			task := resource.NewTask(fmt.Sprintf("simulation-task-%d-%d", i, j), fmt.Sprintf("http://simulation/%d/%d", i, j), "", "",
+				commonv2.TaskType_DFDAEMON, nil, nil, int32(len(hosts)), resource.WithPieceLength(int32(taskWorkload.PieceLength)))
+			task.ContentLength.Store(int64(taskWorkload.ContentLength))
+			task.TotalPieceCount.Store(int32(math.Ceil(float64(taskWorkload.ContentLength) / float64(taskWorkload.PieceLength))))
+
+			// Seed peers download the task back-to-source once the task is created.
+			for _, host := range seedPeerHosts {
+				heap.Push(queue, &event{typ: eventArrive, peer: s.newPeer(task, host)})
+			}
+
+			var at time.Duration
+			for _, k := range r.Perm(len(hosts))[:taskWorkload.Peers] {
+				at += time.Duration(r.ExpFloat64() / taskWorkload.ArrivalRate * float64(time.Second))
+				heap.Push(queue, &event{at: at, typ: eventArrive, peer: s.newPeer(task, hosts[k])})
+			}
+		}
+	}
+
+	rec := &recorder{}
+	for queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		e := heap.Pop(queue).(*event)
+		rec.now = e.at
+		switch e.typ {
+		case eventArrive:
+			heap.Push(queue, s.arrive(ctx, e, rec))
+		case eventFinish:
+			s.finish(e)
+		}
+	}
+
+	return s.report(rec), nil
+}
+
+// hosts returns the normal hosts and seed peer hosts spread over the topology.
+func (s *simulator) hosts() ([]*resource.Host, []*resource.Host) {
+	var hosts, seedPeerHosts []*resource.Host
+	for i := 0; i < s.workload.Hosts.Count; i++ {
+		hosts = append(hosts, resource.NewHost(
+			fmt.Sprintf("simulation-host-%d", i), fmt.Sprintf("10.0.%d.%d", i/256, i%256), fmt.Sprintf("simulation-host-%d", i),
+			8003, 8001, types.HostTypeNormal,
+			resource.WithConcurrentUploadLimit(s.workload.Hosts.ConcurrentUploadLimit), resource.WithTopology(s.topology(i))))
+	}
+
+	for i := 0; i < s.workload.Hosts.SeedPeerCount; i++ {
+		seedPeerHosts = append(seedPeerHosts, resource.NewHost(
+			fmt.Sprintf("simulation-seed-peer-%d", i), fmt.Sprintf("10.1.%d.%d", i/256, i%256), fmt.Sprintf("simulation-seed-peer-%d", i),
+			8003, 8001, types.HostTypeSuperSeed, resource.WithTopology(s.topology(i))))
+	}
+
+	return hosts, seedPeerHosts
+}
+
+// topology returns the topology labels of the nth host, the hosts are spread
+// over regions first, then zones and racks.
+func (s *simulator) topology(n int) types.Topology {
+	regions, zones, racks := s.workload.Hosts.Regions, s.workload.Hosts.Zones, s.workload.Hosts.Racks
+	region := n % regions
+	zone := (n / regions) % zones
+	rack := (n / (regions * zones)) % racks
+
+	return types.Topology{
+		Region: fmt.Sprintf("region-%d", region),
+		Zone:   fmt.Sprintf("region-%d-zone-%d", region, zone),
+		Rack:   fmt.Sprintf("region-%d-zone-%d-rack-%d", region, zone, rack),
+	}
+}
+
+// newPeer returns a new peer of the task on the host.
+func (s *simulator) newPeer(task *resource.Task, host *resource.Host) *resource.Peer {
+	return resource.NewPeer(fmt.Sprintf("%s-%s", task.ID, host.ID), &s.config.Resource, task, host)
+}
+
+// arrive schedules the arrived peer and returns the event of finishing downloading.
+func (s *simulator) arrive(ctx context.Context, e *event, rec *recorder) *event {
+	peer := e.peer
+	peer.Task.StorePeer(peer)
+	peer.Host.StorePeer(peer)
+	peer.FSM.SetState(resource.PeerStateRunning)
+
+	bandwidth := float64(s.workload.Hosts.Bandwidth)
+	finish := func(bandwidth float64) *event {
+		seconds := float64(peer.Task.ContentLength.Load()) / bandwidth
+		if peer.Host.Type == types.HostTypeNormal {
+			rec.downloadSeconds = append(rec.downloadSeconds, seconds)
+		}
+
+		return &event{at: e.at + time.Duration(seconds*float64(time.Second)), typ: eventFinish, peer: peer}
+	}
+
+	// Seed peers always download back-to-source and are excluded from the metrics.
+	if peer.Host.Type != types.HostTypeNormal {
+		return finish(bandwidth)
+	}
+
+	rec.peers++
+	start := time.Now()
+	parents, found := s.scheduling.FindCandidateParents(ctx, peer, set.NewSafeSet[string]())
+	rec.latencies = append(rec.latencies, float64(time.Since(start))/float64(time.Millisecond))
+	if !found {
+		peer.FSM.SetState(resource.PeerStateBackToSource)
+		peer.Task.BackToSourcePeers.Add(peer.ID)
+		rec.backToSourcePeers++
+		return finish(bandwidth)
+	}
+
+	parent := parents[0]
+	if err := peer.Task.AddPeerEdge(parent, peer); err != nil {
+		logger.Errorf("peer %s add edge with parent %s failed: %s", peer.ID, parent.ID, err.Error())
+		peer.FSM.SetState(resource.PeerStateBackToSource)
+		peer.Task.BackToSourcePeers.Add(peer.ID)
+		rec.backToSourcePeers++
+		return finish(bandwidth)
+	}
+
+	rec.scheduled++
+	rec.candidateParents += len(parents)
+	if parent.Host.Topology.Region == peer.Host.Topology.Region {
+		rec.sameRegion++
+	}
+
+	if parent.Host.Topology.Zone == peer.Host.Topology.Zone {
+		rec.sameZone++
+	}
+
+	if parent.Host.Topology.Rack == peer.Host.Topology.Rack {
+		rec.sameRack++
+	}
+
+	// The upload bandwidth of parent host is shared by the concurrent uploads.
+	uploadCount := parent.Host.ConcurrentUploadCount.Load()
+	if uploadCount > rec.maxUploadCount {
+		rec.maxUploadCount = uploadCount
+	}
+
+	return finish(bandwidth / float64(uploadCount))
+}
+
+// finish marks the peer succeeded and releases the upload of parents.
+func (s *simulator) finish(e *event) {
+	peer := e.peer
+	for i := int32(0); i < peer.Task.TotalPieceCount.Load(); i++ {
+		peer.FinishedPieces.Set(uint(i))
+	}
+
+	peer.FSM.SetState(resource.PeerStateSucceeded)
+	if err := peer.Task.DeletePeerInEdges(peer.ID); err != nil {
+		peer.Log.Error(err)
+	}
+}
+
+// report returns the report of recorded metrics.
+func (s *simulator) report(rec *recorder) *Report {
+	report := &Report{
+		Algorithm:                s.config.Scheduler.Algorithm,
+		Peers:                    rec.peers,
+		BackToSourcePeers:        rec.backToSourcePeers,
+		MaxConcurrentUploadCount: rec.maxUploadCount,
+		SimulatedSeconds:         rec.now.Seconds(),
+	}
+
+	if rec.peers > 0 {
+		report.BackToSourceRatio = float64(rec.backToSourcePeers) / float64(rec.peers)
+	}
+
+	if rec.scheduled > 0 {
+		report.AverageCandidateParents = float64(rec.candidateParents) / float64(rec.scheduled)
+		report.SameRegionRatio = float64(rec.sameRegion) / float64(rec.scheduled)
+		report.SameZoneRatio = float64(rec.sameZone) / float64(rec.scheduled)
+		report.SameRackRatio = float64(rec.sameRack) / float64(rec.scheduled)
+	}
+
+	report.AverageDownloadSeconds, _ = stats.Mean(rec.downloadSeconds)               // nolint: errcheck
+	report.P95DownloadSeconds, _ = stats.Percentile(rec.downloadSeconds, 95)         // nolint: errcheck
+	report.SchedulingLatencyP50Milliseconds, _ = stats.Percentile(rec.latencies, 50) // nolint: errcheck
+	report.SchedulingLatencyP99Milliseconds, _ = stats.Percentile(rec.latencies, 99) // nolint: errcheck
+	return report
+}
@@ -0,0 +1,196 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"d7y.io/dragonfly/v2/pkg/unit"
+	"d7y.io/dragonfly/v2/scheduler/config"
+)
+
+func TestLoadWorkload(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		expect func(t *testing.T, workload *Workload, err error)
+	}{
+		{
+			name: "load workload",
+			path: "testdata/workload.yaml",
+			expect: func(t *testing.T, workload *Workload, err error) {
+				assert := assert.New(t)
+				assert.NoError(err)
+				assert.Equal(int64(1), workload.Seed)
+				assert.Equal(20, workload.Hosts.Count)
+				assert.Equal(100*unit.MB, workload.Hosts.Bandwidth)
+				assert.Len(workload.Tasks, 1)
+				assert.Equal(1*unit.GB, workload.Tasks[0].ContentLength)
+				assert.Equal(4*unit.MB, workload.Tasks[0].PieceLength)
+			},
+		},
+		{
+			name: "workload file does not exist",
+			path: "testdata/foo.yaml",
+			expect: func(t *testing.T, workload *Workload, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			workload, err := LoadWorkload(tc.path)
+			tc.expect(t, workload, err)
+		})
+	}
+}
+
+func TestWorkload_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload func(w *Workload)
+		expect   func(t *testing.T, err error)
+	}{
+		{
+			name:     "valid workload",
+			workload: func(w *Workload) {},
+			expect: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:     "hosts requires parameter count",
+			workload: func(w *Workload) { w.Hosts.Count = 0 },
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "hosts requires parameter count")
+			},
+		},
+		{
+			name:     "hosts requires parameter bandwidth",
+			workload: func(w *Workload) { w.Hosts.Bandwidth = 0 },
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "hosts requires parameter bandwidth")
+			},
+		},
+		{
+			name:     "workload requires parameter tasks",
+			workload: func(w *Workload) { w.Tasks = nil },
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "workload requires parameter tasks")
+			},
+		},
+		{
+			name:     "peers exceed the count of hosts",
+			workload: func(w *Workload) { w.Tasks[0].Peers = w.Hosts.Count + 1 },
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "tasks requires parameter peers and peers can not exceed the count of hosts")
+			},
+		},
+		{
+			name:     "tasks requires parameter arrivalRate",
+			workload: func(w *Workload) { w.Tasks[0].ArrivalRate = 0 },
+			expect: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "tasks requires parameter arrivalRate")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			workload := newWorkload(0)
+			tc.workload(workload)
+			tc.expect(t, workload.Validate())
+		})
+	}
+}
+
+func TestSimulator_Run(t *testing.T) {
+	tests := []struct {
+		name     string
+		workload *Workload
+		expect   func(t *testing.T, report *Report)
+	}{
+		{
+			name:     "peers download from seed peers",
+			workload: newWorkload(1),
+			expect: func(t *testing.T, report *Report) {
+				assert := assert.New(t)
+				assert.Equal(config.DefaultSchedulerAlgorithm, report.Algorithm)
+				assert.Equal(20, report.Peers)
+				assert.Equal(0, report.BackToSourcePeers)
+				assert.Greater(report.AverageCandidateParents, float64(0))
+				assert.Greater(report.AverageDownloadSeconds, float64(0))
+				assert.GreaterOrEqual(report.P95DownloadSeconds, report.AverageDownloadSeconds)
+				assert.LessOrEqual(report.MaxConcurrentUploadCount, int32(config.DefaultSeedPeerConcurrentUploadLimit))
+			},
+		},
+		{
+			name:     "first peer of each task downloads back-to-source without seed peers",
+			workload: newWorkload(0),
+			expect: func(t *testing.T, report *Report) {
+				assert := assert.New(t)
+				assert.Equal(20, report.Peers)
+				assert.Equal(2, report.BackToSourcePeers)
+				assert.Equal(0.1, report.BackToSourceRatio)
+				assert.LessOrEqual(report.MaxConcurrentUploadCount, int32(4))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := New(config.New(), tc.workload, "").Run(context.Background())
+			assert.NoError(t, err)
+			tc.expect(t, report)
+		})
+	}
+}
+
+func TestSimulator_RunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := New(config.New(), newWorkload(1), "").Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// newWorkload returns a valid workload with the given count of seed peers.
+func newWorkload(seedPeerCount int) *Workload {
+	return &Workload{
+		Seed: 1,
+		Hosts: HostWorkload{
+			Count:                 20,
+			SeedPeerCount:         seedPeerCount,
+			Regions:               2,
+			Zones:                 2,
+			Racks:                 2,
+			ConcurrentUploadLimit: 4,
+			Bandwidth:             100 * unit.MB,
+		},
+		Tasks: []TaskWorkload{{
+			Count:         2,
+			Peers:         10,
+			ArrivalRate:   5,
+			ContentLength: 1 * unit.GB,
+			PieceLength:   4 * unit.MB,
+		}},
+	}
+}
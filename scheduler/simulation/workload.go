@@ -0,0 +1,159 @@
+/*
+ *     Copyright 2023 The Dragonfly Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"errors"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"d7y.io/dragonfly/v2/pkg/unit"
+)
+
+// Workload is the synthetic workload replayed by the simulator.
+type Workload struct {
+	// Seed is the seed of random generator, the same seed replays the same workload.
+	Seed int64 `yaml:"seed"`
+
+	// Hosts is the workload of hosts.
+	Hosts HostWorkload `yaml:"hosts"`
+
+	// Tasks is the workload of tasks.
+	Tasks []TaskWorkload `yaml:"tasks"`
+}
+
+// HostWorkload is the workload of hosts.
+type HostWorkload struct {
+	// Count is the count of normal hosts.
+	Count int `yaml:"count"`
+
+	// SeedPeerCount is the count of seed peer hosts.
+	SeedPeerCount int `yaml:"seedPeerCount"`
+
+	// Regions is the count of regions the hosts are spread over.
+	Regions int `yaml:"regions"`
+
+	// Zones is the count of zones in each region.
+	Zones int `yaml:"zones"`
+
+	// Racks is the count of racks in each zone.
+	Racks int `yaml:"racks"`
+
+	// ConcurrentUploadLimit is the concurrent upload limit of normal hosts.
+	ConcurrentUploadLimit int32 `yaml:"concurrentUploadLimit"`
+
+	// Bandwidth is the upload bandwidth per second of each host.
+	Bandwidth unit.Bytes `yaml:"bandwidth"`
+}
+
+// TaskWorkload is the workload of a group of tasks.
+type TaskWorkload struct {
+	// Count is the count of tasks.
+	Count int `yaml:"count"`
+
+	// Peers is the count of peers downloading each task.
+	Peers int `yaml:"peers"`
+
+	// ArrivalRate is the average count of peers arriving per second, the
+	// arrival of peers follows the poisson process.
+	ArrivalRate float64 `yaml:"arrivalRate"`
+
+	// ContentLength is the content length of task.
+	ContentLength unit.Bytes `yaml:"contentLength"`
+
+	// PieceLength is the piece length of task.
+	PieceLength unit.Bytes `yaml:"pieceLength"`
+}
+
+// LoadWorkload loads the workload from the yaml file.
+func LoadWorkload(path string) (*Workload, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var workload Workload
+	if err := yaml.Unmarshal(b, &workload); err != nil {
+		return nil, err
+	}
+
+	if err := workload.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &workload, nil
+}
+
+// Validate validates the workload.
+func (w *Workload) Validate() error {
+	if w.Hosts.Count <= 0 {
+		return errors.New("hosts requires parameter count")
+	}
+
+	if w.Hosts.SeedPeerCount < 0 {
+		return errors.New("hosts requires parameter seedPeerCount")
+	}
+
+	if w.Hosts.Regions <= 0 {
+		return errors.New("hosts requires parameter regions")
+	}
+
+	if w.Hosts.Zones <= 0 {
+		return errors.New("hosts requires parameter zones")
+	}
+
+	if w.Hosts.Racks <= 0 {
+		return errors.New("hosts requires parameter racks")
+	}
+
+	if w.Hosts.ConcurrentUploadLimit <= 0 {
+		return errors.New("hosts requires parameter concurrentUploadLimit")
+	}
+
+	if w.Hosts.Bandwidth <= 0 {
+		return errors.New("hosts requires parameter bandwidth")
+	}
+
+	if len(w.Tasks) == 0 {
+		return errors.New("workload requires parameter tasks")
+	}
+
+	for _, task := range w.Tasks {
+		if task.Count <= 0 {
+			return errors.New("tasks requires parameter count")
+		}
+
+		if task.Peers <= 0 || task.Peers > w.Hosts.Count {
+			return errors.New("tasks requires parameter peers and peers can not exceed the count of hosts")
+		}
+
+		if task.ArrivalRate <= 0 {
+			return errors.New("tasks requires parameter arrivalRate")
+		}
+
+		if task.ContentLength <= 0 {
+			return errors.New("tasks requires parameter contentLength")
+		}
+
+		if task.PieceLength <= 0 {
+			return errors.New("tasks requires parameter pieceLength")
+		}
+	}
+
+	return nil
+}
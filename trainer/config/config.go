@@ -116,6 +116,17 @@ type CertSpec struct {
 type ManagerConfig struct {
 	// Addr is manager address.
 	Addr string `yaml:"addr" mapstructure:"addr"`
+
+	// Auth is the credentials of manager.
+	Auth AuthConfig `yaml:"auth" mapstructure:"auth"`
+}
+
+type AuthConfig struct {
+	// Token is the bearer token, empty disables token authentication.
+	Token string `mapstructure:"token" yaml:"token"`
+
+	// HMACSecret is the secret of hmac signature, empty disables hmac authentication.
+	HMACSecret string `mapstructure:"hmacSecret" yaml:"hmacSecret"`
 }
 
 // New default configuration.
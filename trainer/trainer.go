@@ -80,6 +80,12 @@ func New(ctx context.Context, cfg *config.Config, d dfpath.Dfpath) (*Server, err
 		managerDialOptions = append(managerDialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
+	// Inject credentials into the calls of manager.
+	managerDialOptions = append(managerDialOptions, rpc.AuthDialOptions(rpc.NewAuthenticator(
+		rpc.WithAuthToken(cfg.Manager.Auth.Token),
+		rpc.WithAuthHMACSecret(cfg.Manager.Auth.HMACSecret),
+	))...)
+
 	// Initialize manager client.
 	managerClient, err := managerclient.GetV2ByAddr(ctx, cfg.Manager.Addr, managerDialOptions...)
 	if err != nil {
@@ -82,7 +82,7 @@ func (t *training) Train(ctx context.Context, ip, hostname string) error {
 func (t *training) trainGNN(ctx context.Context, ip, hostname string) error {
 	// 1. Get training data from storage.
 	// 2. Preprocess training data.
-	// 2. Train GNN model.
+	// 2. Train GNN model, the tensors of model are defined by the GNN constants in pkg/types.
 	// 3. Upload GNN model to manager service.
 	return nil
 }